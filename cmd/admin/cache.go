@@ -0,0 +1,108 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aasumitro/posbe/config"
+	storeRepository "github.com/aasumitro/posbe/internal/store/repository/sql"
+	"github.com/aasumitro/posbe/pkg/cache"
+	"github.com/aasumitro/posbe/pkg/model"
+)
+
+// cacheKeys of each target, status is the live status of tables and rooms
+var cacheKeys = map[string][]string{
+	cache.RolesKey:      {cache.RolesKey},
+	cache.StorePrefsKey: {cache.StorePrefsKey},
+	"status":            {"table_*_status", "room_*_status"},
+}
+
+func runCache(ctx context.Context, command string, args []string) {
+	requireSharedCache()
+	targets := []string{cache.RolesKey, cache.StorePrefsKey, "status"}
+	if len(args) > 0 && args[0] != "all" {
+		if _, ok := cacheKeys[args[0]]; !ok {
+			usage()
+		}
+		targets = args[:1]
+	}
+	switch command {
+	case "flush":
+		flushCache(ctx, targets)
+	case "rebuild":
+		for _, target := range targets {
+			rebuildCache(ctx, target)
+		}
+	default:
+		usage()
+	}
+}
+
+func flushCache(ctx context.Context, targets []string) {
+	for _, target := range targets {
+		var deleted int64
+		for _, pattern := range cacheKeys[target] {
+			keys, err := config.KV.Keys(ctx, pattern)
+			if err != nil {
+				fail(err.Error())
+			}
+			count, err := config.KV.Del(ctx, keys...)
+			if err != nil {
+				fail(err.Error())
+			}
+			deleted += count
+		}
+		fmt.Printf("%s: %d keys deleted\n", target, deleted)
+	}
+}
+
+func rebuildCache(ctx context.Context, target string) {
+	switch target {
+	case cache.RolesKey:
+		flushCache(ctx, []string{target})
+		// the role list load the cache on a miss
+		roles, errData := accountService().RoleList(ctx)
+		if errData != nil {
+			fail(errData.Message)
+		}
+		fmt.Printf("roles: %d roles cached\n", len(roles))
+	case cache.StorePrefsKey:
+		prefs, err := storeRepository.NewStorePrefSQLRepository().All(ctx)
+		if err != nil {
+			fail(err.Error())
+		}
+		if err := cache.New[*model.StoreSetting](cache.StorePrefsKey).Set(ctx, prefs); err != nil {
+			fail(err.Error())
+		}
+		fmt.Printf("store_prefs: %d prefs cached\n", len(*prefs))
+	case "status":
+		rebuildStatus(ctx)
+	}
+}
+
+// rebuildStatus add the missing status of the tables and rooms as free (0),
+// the status of a table or room in use is kept
+func rebuildStatus(ctx context.Context) {
+	var added int
+	tables, err := storeRepository.NewTableSQLRepository().All(ctx)
+	if err != nil {
+		fail(err.Error())
+	}
+	for _, table := range tables {
+		key := fmt.Sprintf("table_%d_status", table.ID)
+		if ok, _ := config.KV.SetNX(ctx, key, 0, 0); ok {
+			added++
+		}
+	}
+	rooms, err := storeRepository.NewRoomSQLRepository().All(ctx)
+	if err != nil {
+		fail(err.Error())
+	}
+	for _, room := range rooms {
+		key := fmt.Sprintf("room_%d_status", room.ID)
+		if ok, _ := config.KV.SetNX(ctx, key, 0, 0); ok {
+			added++
+		}
+	}
+	fmt.Printf("status: %d keys added\n", added)
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/aasumitro/posbe/config"
+	"github.com/spf13/viper"
+)
+
+const usageText = `usage: posbe-admin <command> [flags]
+
+commands:
+  user create-admin -username -name -email -phone [-password-stdin]
+                           create an admin user, a password is generated when not given
+  user reset-password -username [-password-stdin]
+                           set a new password and revoke the sessions of the user
+  session list -username   list the logged in devices of the user
+  session revoke -username [-id]
+                           revoke one session or every session of the user
+  cache flush [roles|store_prefs|status|all]
+                           delete the cached data (status reset the table and room status)
+  cache rebuild [roles|store_prefs|status|all]
+                           load the cached data again, the status of a table or room is kept
+
+the password is read from POSBE_ADMIN_PASSWORD or from stdin with
+-password-stdin (not echoed on a terminal), it is generated and printed
+once saved when neither is given
+
+the session and cache commands need CACHE_DRIVER=redis, the memory cache
+live in the server process
+`
+
+func usage() {
+	fmt.Fprint(os.Stderr, usageText)
+	os.Exit(2)
+}
+
+func main() {
+	// same config as the server
+	viper.SetConfigFile(".env")
+	if len(os.Args) < 3 {
+		usage()
+	}
+	ctx := context.Background()
+	config.LoadWith(ctx,
+		config.DatabaseConnection(),
+		config.CacheConnection())
+	group, command, args := os.Args[1], os.Args[2], os.Args[3:]
+	switch group {
+	case "user":
+		runUser(ctx, command, args)
+	case "session":
+		runSession(ctx, command, args)
+	case "cache":
+		runCache(ctx, command, args)
+	default:
+		usage()
+	}
+}
+
+// requireSharedCache stop the commands that change the sessions or the
+// cache, with the memory driver they only live in the server process
+func requireSharedCache() {
+	if config.RedisPool == nil {
+		fail("the memory cache live in the server process, restart the server instead")
+	}
+}
+
+// fail print the error and exit, the message of a service error is a string
+func fail(message any) {
+	fmt.Fprintf(os.Stderr, "error: %v\n", message)
+	os.Exit(1)
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"time"
+)
+
+func runSession(ctx context.Context, command string, args []string) {
+	requireSharedCache()
+	flags := flag.NewFlagSet("session "+command, flag.ExitOnError)
+	username := flags.String("username", "", "username of the user")
+	sessionID := flags.String("id", "", "session id, every session when empty (revoke)")
+	_ = flags.Parse(args)
+	if *username == "" {
+		flags.Usage()
+		usage()
+	}
+	user := findUser(ctx, *username)
+	svc := sessionService()
+	switch command {
+	case "list":
+		sessions, errData := svc.SessionList(ctx, user.ID, "")
+		if errData != nil {
+			fail(errData.Message)
+		}
+		for _, session := range sessions {
+			fmt.Printf("%s  %-15s  %s  last used %s\n", session.ID, session.IP,
+				session.Device, time.Unix(session.LastUsedAt, 0).Format(time.RFC3339))
+		}
+		fmt.Printf("%d sessions\n", len(sessions))
+	case "revoke":
+		if *sessionID == "" {
+			if errData := svc.RevokeAll(ctx, user.ID); errData != nil {
+				fail(errData.Message)
+			}
+			fmt.Printf("every session of %s is revoked\n", user.Username)
+			return
+		}
+		if errData := svc.Revoke(ctx, user.ID, *sessionID); errData != nil {
+			fail(errData.Message)
+		}
+		fmt.Printf("session %s is revoked\n", *sessionID)
+	default:
+		usage()
+	}
+}
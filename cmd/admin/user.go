@@ -0,0 +1,179 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/aasumitro/posbe/config"
+	"github.com/aasumitro/posbe/internal/account/handler/http"
+	repository "github.com/aasumitro/posbe/internal/account/repository/sql"
+	"github.com/aasumitro/posbe/internal/account/service"
+	"github.com/aasumitro/posbe/pkg/model"
+	"github.com/aasumitro/posbe/pkg/reference"
+	"github.com/aasumitro/posbe/pkg/utils"
+	"golang.org/x/term"
+)
+
+const (
+	// generatedPasswordBytes is 16 hex characters
+	generatedPasswordBytes = 8
+	// passwordEnv is read instead of stdin, e.g. from a secret of the deploy
+	passwordEnv = "POSBE_ADMIN_PASSWORD"
+)
+
+func accountService() model.IAccountService {
+	return service.NewAccountService(
+		repository.NewRoleSQLRepository(),
+		repository.NewUserSQLRepository(),
+		reference.NewReferenceSQLRepository())
+}
+
+func sessionService() model.ISessionService {
+	return service.NewSessionService(
+		repository.NewUserSQLRepository(), http.NewJSONWebToken(),
+		time.Duration(config.Instance.JWTLifetime)*time.Hour)
+}
+
+func runUser(ctx context.Context, command string, args []string) {
+	switch command {
+	case "create-admin":
+		createAdmin(ctx, args)
+	case "reset-password":
+		resetPassword(ctx, args)
+	default:
+		usage()
+	}
+}
+
+func createAdmin(ctx context.Context, args []string) {
+	flags := flag.NewFlagSet("create-admin", flag.ExitOnError)
+	user := &model.User{}
+	flags.StringVar(&user.Username, "username", "", "username to log in with")
+	flags.StringVar(&user.Name, "name", "", "full name")
+	flags.StringVar(&user.Email, "email", "", "email")
+	flags.StringVar(&user.Phone, "phone", "", "phone number (digits only)")
+	fromStdin := flags.Bool("password-stdin", false, "read the password from stdin")
+	_ = flags.Parse(args)
+	if user.Username == "" || user.Name == "" || user.Email == "" || user.Phone == "" {
+		flags.Usage()
+		usage()
+	}
+	svc := accountService()
+	roles, errData := svc.RoleList(ctx)
+	if errData != nil {
+		fail(errData.Message)
+	}
+	for _, role := range roles {
+		if role.Name == "admin" {
+			user.RoleID = role.ID
+		}
+	}
+	if user.RoleID == 0 {
+		fail("admin role not found, run the migrations first")
+	}
+	password, generated := passwordOrGenerated(readPassword(*fromStdin))
+	user.Password = password
+	created, errData := svc.AddUser(ctx, user)
+	if errData != nil {
+		fail(errData.Message)
+	}
+	fmt.Printf("admin %s created with id %d\n", created.Username, created.ID)
+	printGenerated(password, generated)
+}
+
+func resetPassword(ctx context.Context, args []string) {
+	flags := flag.NewFlagSet("reset-password", flag.ExitOnError)
+	username := flags.String("username", "", "username of the user")
+	fromStdin := flags.Bool("password-stdin", false, "read the new password from stdin")
+	_ = flags.Parse(args)
+	if *username == "" {
+		flags.Usage()
+		usage()
+	}
+	user := findUser(ctx, *username)
+	// the update write the role too, the repository only fill user.Role
+	user.RoleID = user.Role.ID
+	password, generated := passwordOrGenerated(readPassword(*fromStdin))
+	user.Password = password
+	if _, errData := accountService().EditUser(ctx, user); errData != nil {
+		fail(errData.Message)
+	}
+	// the password is in use from now on, even if the revoke below fail
+	printGenerated(password, generated)
+	// the old password may be known, so log out every device
+	if errData := sessionService().RevokeAll(ctx, user.ID); errData != nil {
+		fail(errData.Message)
+	}
+	fmt.Printf("password of %s is reset, the sessions are revoked\n", user.Username)
+	if config.RedisPool == nil {
+		fmt.Println("the memory cache keep the sessions in the server, restart it to end them")
+	}
+}
+
+func findUser(ctx context.Context, username string) *model.User {
+	user, err := repository.NewUserSQLRepository().
+		Find(ctx, model.FindWithUsername, username)
+	if err != nil {
+		fail(fmt.Sprintf("user %s: %s", username, err.Error()))
+	}
+	return user
+}
+
+// readPassword return the password of the passwordEnv env, or of the stdin
+// with -password-stdin (not echoed on a terminal), empty when none is given,
+// it is never a flag so it does not show in the process list or the history
+func readPassword(fromStdin bool) string {
+	if password := os.Getenv(passwordEnv); password != "" {
+		return password
+	}
+	if !fromStdin {
+		return ""
+	}
+	var password string
+	if fd := int(os.Stdin.Fd()); term.IsTerminal(fd) {
+		fmt.Fprint(os.Stderr, "password: ")
+		value, err := term.ReadPassword(fd)
+		fmt.Fprintln(os.Stderr)
+		if err != nil {
+			fail(err.Error())
+		}
+		password = string(value)
+	} else {
+		line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+		if err != nil && !errors.Is(err, io.EOF) {
+			fail(err.Error())
+		}
+		password = strings.TrimRight(line, "\r\n")
+	}
+	if password == "" {
+		fail("the password from stdin is empty")
+	}
+	return password
+}
+
+// passwordOrGenerated generate a password when none is given
+func passwordOrGenerated(password string) (value string, generated bool) {
+	if password != "" {
+		return password, false
+	}
+	value, err := utils.RandomHex(generatedPasswordBytes)
+	if err != nil {
+		fail(err.Error())
+	}
+	return value, true
+}
+
+// printGenerated print the generated password once it is saved, it is
+// the only time it is shown as only its hash is kept
+func printGenerated(password string, generated bool) {
+	if generated {
+		fmt.Printf("generated password: %s\n", password)
+	}
+}
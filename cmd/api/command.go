@@ -0,0 +1,118 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"os"
+	"strconv"
+
+	"github.com/aasumitro/posbe/config"
+	"github.com/aasumitro/posbe/db"
+	"github.com/aasumitro/posbe/pkg/migrate"
+)
+
+const usageText = `usage: posbe [command]
+
+commands:
+  serve                    run the api server (default)
+  migrate up               apply the pending migrations
+  migrate down [N|all]     roll back the last N migrations (default 1)
+  migrate status           print the version and the migrations
+  migrate force VERSION    set the version and clear the dirty flag (-1 for none)
+  seed                     insert the demo data
+`
+
+func usage() {
+	fmt.Fprint(os.Stderr, usageText)
+	os.Exit(2)
+}
+
+func runMigrate(ctx context.Context, args []string) {
+	if len(args) == 0 {
+		usage()
+	}
+	config.LoadWith(ctx, config.DatabaseConnection())
+	migrator, err := migrate.New(config.DbPool, config.Dialect, db.Migrations, db.MigrationsDir(config.Dialect))
+	if err != nil {
+		log.Fatalf("MIGRATION_ERROR: %s\n", err.Error())
+	}
+	switch args[0] {
+	case "up":
+		applied, err := migrator.Up(ctx)
+		printMigrations("applied", applied, err)
+	case "down":
+		reverted, err := migrator.Down(ctx, downSteps(args[1:]))
+		printMigrations("reverted", reverted, err)
+	case "status":
+		printStatus(ctx, migrator)
+	case "force":
+		if len(args) < 2 {
+			usage()
+		}
+		version, err := strconv.ParseInt(args[1], 10, 64)
+		if err != nil {
+			usage()
+		}
+		if err := migrator.Force(ctx, version); err != nil {
+			log.Fatalf("MIGRATION_ERROR: %s\n", err.Error())
+		}
+		fmt.Printf("version forced to %d\n", version)
+	default:
+		usage()
+	}
+}
+
+func downSteps(args []string) int {
+	if len(args) == 0 {
+		return 1
+	}
+	if args[0] == "all" {
+		return math.MaxInt
+	}
+	steps, err := strconv.Atoi(args[0])
+	if err != nil || steps < 1 {
+		usage()
+	}
+	return steps
+}
+
+func printMigrations(action string, migrations []*migrate.Migration, err error) {
+	for _, migration := range migrations {
+		fmt.Printf("%s %d_%s\n", action, migration.Version, migration.Name)
+	}
+	switch {
+	case errors.Is(err, migrate.ErrNoChange):
+		fmt.Println(err.Error())
+	case err != nil:
+		log.Fatalf("MIGRATION_ERROR: %s\n", err.Error())
+	}
+}
+
+func printStatus(ctx context.Context, migrator *migrate.Migrator) {
+	status, err := migrator.Status(ctx)
+	if err != nil {
+		log.Fatalf("MIGRATION_ERROR: %s\n", err.Error())
+	}
+	for _, migration := range status.Migrations {
+		state := "pending"
+		if migration.Applied {
+			state = "applied"
+		}
+		fmt.Printf("%-8s %d_%s\n", state, migration.Version, migration.Name)
+	}
+	fmt.Printf("version: %d, dirty: %t\n", status.Version, status.Dirty)
+}
+
+func runSeed(ctx context.Context) {
+	config.LoadWith(ctx, config.DatabaseConnection())
+	files, err := migrate.Seed(ctx, config.DbPool, db.Seeds, db.SeedsDir)
+	if err != nil {
+		log.Fatalf("SEED_ERROR: %s\n", err.Error())
+	}
+	for _, file := range files {
+		fmt.Printf("seeded %s\n", file)
+	}
+}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"os"
 
 	"github.com/aasumitro/posbe/config"
 	"github.com/aasumitro/posbe/internal"
@@ -23,6 +24,10 @@ import (
 // @in                          header
 // @name                        Authorization
 //
+// @securityDefinitions.apikey  APIKey
+// @in                          header
+// @name                        X-API-Key
+//
 // @license.name  MIT
 // @license.url   https://github.com/aasumitro/posbe/blob/main/LICENSE
 
@@ -30,12 +35,31 @@ func main() {
 	viper.SetConfigFile(".env")
 	// viper.AutomaticEnv()
 	mainCtx := context.Background()
+	command := "serve"
+	if len(os.Args) > 1 {
+		command = os.Args[1]
+	}
+	switch command {
+	case "serve":
+		serve(mainCtx)
+	case "migrate":
+		runMigrate(mainCtx, os.Args[2:])
+	case "seed":
+		runSeed(mainCtx)
+	default:
+		usage()
+	}
+}
+
+func serve(ctx context.Context) {
 	// load environment file
-	config.LoadWith(mainCtx,
+	config.LoadWith(ctx,
 		config.SentryConnection(),
-		config.PostgresConnection(),
-		config.RedisConnection(),
+		config.DatabaseConnection(),
+		config.DatabaseMigration(),
+		config.CacheConnection(),
+		config.StorageConnection(),
 		config.ServerEngine())
 	// run server app
-	internal.RunServer(mainCtx)
+	internal.RunServer(ctx)
 }
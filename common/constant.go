@@ -10,4 +10,55 @@ const (
 	HealthCheckPingTimeout = 1000
 
 	SwaggerDefaultModelsExpandDepth = 4
+
+	// EventStreamKeepAlive is seconds between ping on event stream,
+	// keep proxies from closing the idle connection
+	EventStreamKeepAlive = 25
+)
+
+const (
+	// OverrideTokenLifetime is seconds a manager override stay usable
+	OverrideTokenLifetime = 120
+)
+
+const (
+	// PINMaxAttempts is failed pin or badge attempts before the user is locked
+	PINMaxAttempts = 5
+	// PINLockoutTime is seconds the pin stay locked
+	PINLockoutTime = 300
+	// TerminalIdleTimeout is the default seconds before an idle terminal session end
+	TerminalIdleTimeout = 300
+)
+
+const (
+	// AccessTokenLifetime is seconds an access token stay valid, the refresh
+	// token live for JWT_LIFETIME hours and is rotated on every refresh
+	AccessTokenLifetime = 900
+	// SessionIDSize is random bytes of session id
+	SessionIDSize = 16
+)
+
+const (
+	// APIKeyRotationGrace is seconds a rotated api key keep working
+	APIKeyRotationGrace = 86400
+	// APIKeyTouchInterval is seconds between last_used_at updates of an api key
+	APIKeyTouchInterval = 60
+)
+
+const (
+	// AuditQueueSize is audit entries kept in memory before they are dropped
+	AuditQueueSize = 4096
+	// AuditBatchSize is audit entries written with one insert
+	AuditBatchSize = 100
+	// AuditFlushInterval is seconds between writes of a partial batch
+	AuditFlushInterval = 2
+	// AuditBodyLimit is bytes of the respond kept as the audit entry after state
+	AuditBodyLimit = 64 << 10
+	// AuditDefaultLimit is audit entries returned when no limit is given
+	AuditDefaultLimit = 50
+)
+
+const (
+	// SyncDefaultLimit is changes returned by a pull when no limit is given
+	SyncDefaultLimit = 500
 )
@@ -8,4 +8,5 @@ var (
 	ErrorPasswordNotProvideValidHash = errors.New("did not provide a valid hash")
 	ErrorPasswordUnableToVerify      = errors.New("unable to verify user password")
 	ErrorUnableToDelete              = errors.New("unable to delete this data")
+	ErrorReassignTarget              = errors.New("reassign_to must be another existing data")
 )
@@ -0,0 +1,47 @@
+package config
+
+import (
+	"log"
+
+	"github.com/aasumitro/posbe/pkg/broker"
+	"github.com/aasumitro/posbe/pkg/kv"
+	"github.com/redis/go-redis/v9"
+)
+
+// CacheConnection setup the key value store and the broker, redis is used
+// when REDIS_DSN_URL is set unless CACHE_DRIVER say otherwise, memory let
+// a single api instance run with postgres only
+func CacheConnection() Option {
+	return func(cfg *Config) {
+		cacheSingleton.Do(func() {
+			if cfg.CacheDriver == "" {
+				cfg.CacheDriver = kv.DriverMemory
+				if cfg.RedisDsnURL != "" {
+					cfg.CacheDriver = kv.DriverRedis
+				}
+			}
+			switch cfg.CacheDriver {
+			case kv.DriverRedis:
+				log.Println("Trying to open redis connection pool . . . .")
+				RedisPool = redis.NewClient(&redis.Options{
+					Addr:     cfg.RedisDsnURL,
+					Password: "",
+					DB:       0,
+				})
+				if err := RedisPool.Ping(cfg.ctx).Err(); err != nil {
+					log.Fatalf("REDIS_ERROR: %s\n",
+						err.Error())
+				}
+				KV = kv.NewRedisStore(RedisPool)
+				Broker = broker.NewRedisBroker(RedisPool)
+				log.Println("Redis connection pool created . . . .")
+			case kv.DriverMemory:
+				KV = kv.NewMemoryStore()
+				Broker = broker.NewMemoryBroker()
+				log.Println("In memory cache ready, it is not shared between instances . . . .")
+			default:
+				log.Fatalf("CACHE_ERROR: unknown driver %s\n", cfg.CacheDriver)
+			}
+		})
+	}
+}
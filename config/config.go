@@ -7,19 +7,30 @@ import (
 	"log"
 	"sync"
 
+	"github.com/aasumitro/posbe/pkg/broker"
+	"github.com/aasumitro/posbe/pkg/dialect"
+	"github.com/aasumitro/posbe/pkg/kv"
+	"github.com/aasumitro/posbe/pkg/storage"
 	"github.com/gin-gonic/gin"
 	"github.com/redis/go-redis/v9"
 	"github.com/spf13/viper"
 )
 
 var (
-	configSingleton, postgresSingleton,
-	redisSingleton, engineOnce sync.Once
+	configSingleton, databaseSingleton,
+	cacheSingleton, engineOnce,
+	storageSingleton sync.Once
 
-	Instance     *Config
-	PostgresPool *sql.DB
-	RedisPool    *redis.Client
-	GinEngine    *gin.Engine
+	Instance *Config
+	DbPool   *sql.DB
+	// Dialect is the one of DB_DRIVER
+	Dialect dialect.Dialect
+	// RedisPool is nil when the memory cache driver is used
+	RedisPool *redis.Client
+	KV        kv.Store
+	Broker    broker.Broker
+	GinEngine *gin.Engine
+	Storage   storage.Storage
 )
 
 type Config struct {
@@ -30,12 +41,26 @@ type Config struct {
 	AppDebug   bool   `mapstructure:"APP_DEBUG"`
 	AppVersion string `mapstructure:"APP_VERSION"`
 
+	DBDriver       string `mapstructure:"DB_DRIVER"`
 	PostgresDsnURL string `mapstructure:"POSTGRES_DSN_URL"`
+	SQLitePath     string `mapstructure:"SQLITE_PATH"`
+	DBAutoMigrate  bool   `mapstructure:"DB_AUTO_MIGRATE"`
+	CacheDriver    string `mapstructure:"CACHE_DRIVER"`
 	RedisDsnURL    string `mapstructure:"REDIS_DSN_URL"`
 	SentryDsnURL   string `mapstructure:"SENTRY_DSN_URL"`
 
 	JWTSecretKey string `mapstructure:"JWT_SECRET_KEY"`
 	JWTLifetime  int    `mapstructure:"JWT_LIFETIME"`
+
+	StorageDriver    string `mapstructure:"STORAGE_DRIVER"`
+	StorageLocalPath string `mapstructure:"STORAGE_LOCAL_PATH"`
+	StoragePublicURL string `mapstructure:"STORAGE_PUBLIC_URL"`
+	S3Endpoint       string `mapstructure:"S3_ENDPOINT"`
+	S3Region         string `mapstructure:"S3_REGION"`
+	S3Bucket         string `mapstructure:"S3_BUCKET"`
+	S3AccessKey      string `mapstructure:"S3_ACCESS_KEY"`
+	S3SecretKey      string `mapstructure:"S3_SECRET_KEY"`
+	S3PathStyle      bool   `mapstructure:"S3_PATH_STYLE"`
 }
 
 type Option func(cfg *Config)
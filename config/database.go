@@ -0,0 +1,94 @@
+package config
+
+import (
+	"database/sql"
+	"errors"
+	"log"
+	"net/url"
+	"os"
+	"path/filepath"
+
+	"github.com/aasumitro/posbe/db"
+	"github.com/aasumitro/posbe/pkg/dialect"
+	"github.com/aasumitro/posbe/pkg/migrate"
+
+	// postgresql
+	_ "github.com/lib/pq"
+	// sqlite
+	_ "modernc.org/sqlite"
+)
+
+const defaultSQLitePath = "./storage/posbe.db"
+
+// DatabaseConnection open the pool of DB_DRIVER, postgres (default) or
+// sqlite for a single terminal that run offline with the file at SQLITE_PATH
+func DatabaseConnection() Option {
+	return func(cfg *Config) {
+		databaseSingleton.Do(func() {
+			if cfg.DBDriver == "" {
+				cfg.DBDriver = dialect.Postgres
+			}
+			var err error
+			if Dialect, err = dialect.New(cfg.DBDriver); err != nil {
+				log.Fatalf("DATABASE_ERROR: %s %s\n",
+					err.Error(), cfg.DBDriver)
+			}
+			log.Println("Trying to open database connection pool . . . .")
+			dsn := cfg.PostgresDsnURL
+			if cfg.DBDriver == dialect.SQLite {
+				if cfg.SQLitePath == "" {
+					cfg.SQLitePath = defaultSQLitePath
+				}
+				if err := os.MkdirAll(filepath.Dir(cfg.SQLitePath), 0o755); err != nil {
+					log.Fatalf("DATABASE_ERROR: %s\n",
+						err.Error())
+				}
+				dsn = SQLiteDsn(cfg.SQLitePath)
+			}
+			conn, err := sql.Open(Dialect.Name(), dsn)
+			if err != nil {
+				log.Fatalf("DATABASE_ERROR: %s\n",
+					err.Error())
+			}
+			DbPool = conn
+			if err := DbPool.Ping(); err != nil {
+				log.Fatalf("DATABASE_ERROR: %s\n",
+					err.Error())
+			}
+			log.Printf("Database connected with %s driver . . . .\n",
+				Dialect.Name())
+		})
+	}
+}
+
+// SQLiteDsn enforce the foreign keys, wait for the lock instead of failing,
+// and begin every transaction as writer so the writes are serialized
+func SQLiteDsn(path string) string {
+	query := url.Values{}
+	query.Add("_pragma", "foreign_keys(1)")
+	query.Add("_pragma", "busy_timeout(5000)")
+	query.Add("_pragma", "journal_mode(WAL)")
+	query.Set("_txlock", "immediate")
+	return "file:" + path + "?" + query.Encode()
+}
+
+// DatabaseMigration apply the pending migrations on boot when DB_AUTO_MIGRATE
+// is set, the advisory lock (postgres) keep the other instances waiting until it is done,
+// must be used after DatabaseConnection
+func DatabaseMigration() Option {
+	return func(cfg *Config) {
+		if !cfg.DBAutoMigrate {
+			return
+		}
+		migrator, err := migrate.New(DbPool, Dialect, db.Migrations, db.MigrationsDir(Dialect))
+		if err != nil {
+			log.Fatalf("MIGRATION_ERROR: %s\n", err.Error())
+		}
+		applied, err := migrator.Up(cfg.ctx)
+		if err != nil && !errors.Is(err, migrate.ErrNoChange) {
+			log.Fatalf("MIGRATION_ERROR: %s\n", err.Error())
+		}
+		log.Printf("Database migrated, %d migrations applied . . . .\n",
+			len(applied))
+	}
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/ulule/limiter/v3"
 	lmgin "github.com/ulule/limiter/v3/drivers/middleware/gin"
+	lsmemory "github.com/ulule/limiter/v3/drivers/store/memory"
 	lsredis "github.com/ulule/limiter/v3/drivers/store/redis"
 	"go.uber.org/zap"
 )
@@ -73,11 +74,15 @@ func ServerEngine() Option {
 				if err != nil {
 					log.Fatalf("RATELIMITER_ERROR: %s", err.Error())
 				}
-				store, err := lsredis.NewStoreWithOptions(RedisPool,
-					limiter.StoreOptions{Prefix: fmt.Sprintf(
-						"%s{%s}", cfg.AppName, cfg.AppVersion)})
-				if err != nil {
-					log.Fatalf("RATELIMITER_ERROR: %s\n", err.Error())
+				storeOptions := limiter.StoreOptions{Prefix: fmt.Sprintf(
+					"%s{%s}", cfg.AppName, cfg.AppVersion)}
+				// count on the process when there is no redis to share the count
+				store := lsmemory.NewStoreWithOptions(storeOptions)
+				if RedisPool != nil {
+					store, err = lsredis.NewStoreWithOptions(RedisPool, storeOptions)
+					if err != nil {
+						log.Fatalf("RATELIMITER_ERROR: %s\n", err.Error())
+					}
 				}
 				GinEngine.ForwardedByClientIP = true
 				GinEngine.Use(lmgin.NewMiddleware(limiter.New(store, rate)))
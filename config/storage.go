@@ -0,0 +1,44 @@
+package config
+
+import (
+	"log"
+
+	"github.com/aasumitro/posbe/pkg/storage"
+)
+
+const (
+	defaultStorageLocalPath = "./storage/uploads"
+	defaultStoragePublicURL = "/uploads"
+)
+
+func StorageConnection() Option {
+	return func(cfg *Config) {
+		storageSingleton.Do(func() {
+			log.Println("Trying to setup file storage . . . .")
+			switch cfg.StorageDriver {
+			case storage.DriverS3:
+				if cfg.S3Endpoint == "" || cfg.S3Bucket == "" {
+					log.Fatalln("STORAGE_ERROR: S3_ENDPOINT and S3_BUCKET are required")
+				}
+				Storage = storage.NewS3Storage(
+					cfg.S3Endpoint, cfg.S3Region, cfg.S3Bucket,
+					cfg.S3AccessKey, cfg.S3SecretKey,
+					cfg.S3PathStyle, cfg.StoragePublicURL)
+			case storage.DriverLocal, "":
+				cfg.StorageDriver = storage.DriverLocal
+				if cfg.StorageLocalPath == "" {
+					cfg.StorageLocalPath = defaultStorageLocalPath
+				}
+				if cfg.StoragePublicURL == "" {
+					cfg.StoragePublicURL = defaultStoragePublicURL
+				}
+				Storage = storage.NewLocalStorage(
+					cfg.StorageLocalPath, cfg.StoragePublicURL)
+			default:
+				log.Fatalf("STORAGE_ERROR: unknown driver %s\n", cfg.StorageDriver)
+			}
+			log.Printf("File storage ready with %s driver . . . .\n",
+				cfg.StorageDriver)
+		})
+	}
+}
@@ -0,0 +1,27 @@
+package db
+
+import (
+	"embed"
+	"path"
+
+	"github.com/aasumitro/posbe/pkg/dialect"
+)
+
+// Migrations is the schema of the database, one dir per dialect, applied
+// with `posbe migrate up` (or on boot with DB_AUTO_MIGRATE)
+//
+//go:embed migrations/postgres/*.sql migrations/sqlite/*.sql
+var Migrations embed.FS
+
+// Seeds is the demo data, written in the sql of every dialect,
+// applied with `posbe seed`
+//
+//go:embed seeds/*.sql
+var Seeds embed.FS
+
+const SeedsDir = "seeds"
+
+// MigrationsDir is the dir of the migrations of d in Migrations
+func MigrationsDir(d dialect.Dialect) string {
+	return path.Join("migrations", d.Name())
+}
@@ -1,4 +0,0 @@
-INSERT INTO shifts (id, name, start_time, end_time)
-VALUES (1,'shift 1', 08_00, 15_00),
-       (2, 'shift 2', 15_01, 22_00),
-
@@ -0,0 +1,5 @@
+INSERT INTO shifts (id, name, start_time, end_time)
+VALUES (1, 'shift 1', 0800, 1500),
+       (2, 'shift 2', 1501, 2200);
+
+SELECT setval('shifts_id_seq', (SELECT MAX(id) FROM shifts));
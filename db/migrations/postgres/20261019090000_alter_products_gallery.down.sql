@@ -0,0 +1,5 @@
+ALTER TABLE products ALTER COLUMN gallery DROP NOT NULL;
+
+ALTER TABLE products ALTER COLUMN gallery DROP DEFAULT;
+
+ALTER TABLE products ALTER COLUMN gallery TYPE TEXT USING (gallery -> 0 ->> 'url');
@@ -0,0 +1,14 @@
+-- gallery used to be free text, keep the old value as the first image
+ALTER TABLE products ALTER COLUMN gallery TYPE JSONB USING (
+    CASE
+        WHEN gallery IS NULL OR gallery = '' THEN '[]'::JSONB
+        ELSE jsonb_build_array(jsonb_build_object(
+            'id', md5(gallery), 'url', gallery, 'thumbnail_url', gallery,
+            'key', '', 'thumbnail_key', '', 'width', 0, 'height', 0, 'cover', false
+        ))
+    END
+);
+
+ALTER TABLE products ALTER COLUMN gallery SET DEFAULT '[]'::JSONB;
+
+ALTER TABLE products ALTER COLUMN gallery SET NOT NULL;
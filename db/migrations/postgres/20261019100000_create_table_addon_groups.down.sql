@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS product_addon_prices;
+DROP TABLE IF EXISTS addon_group_assignments;
+DROP TABLE IF EXISTS addon_group_items;
+DROP TABLE IF EXISTS addon_groups;
@@ -0,0 +1,69 @@
+-- max_select 0 means unlimited
+CREATE TABLE IF NOT EXISTS addon_groups (
+    id BIGSERIAL PRIMARY KEY NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    description VARCHAR(255),
+    min_select INT NOT NULL DEFAULT 0,
+    max_select INT NOT NULL DEFAULT 0,
+    created_at BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    updated_at BIGINT,
+    CONSTRAINT chk_addon_groups_selection CHECK (
+        min_select >= 0 AND max_select >= 0 AND
+        (max_select = 0 OR min_select <= max_select)
+    )
+);
+
+CREATE TABLE IF NOT EXISTS addon_group_items (
+    addon_group_id BIGINT NOT NULL,
+    addon_id BIGINT NOT NULL,
+    position INT NOT NULL DEFAULT 0,
+    PRIMARY KEY (addon_group_id, addon_id)
+);
+
+ALTER TABLE addon_group_items ADD CONSTRAINT fk_addon_groups_addon_group_items
+    FOREIGN KEY (addon_group_id) REFERENCES addon_groups(id) ON DELETE CASCADE;
+
+ALTER TABLE addon_group_items ADD CONSTRAINT fk_addons_addon_group_items
+    FOREIGN KEY (addon_id) REFERENCES addons(id) ON DELETE CASCADE;
+
+-- a group is assigned either to a product or to a whole category
+CREATE TABLE IF NOT EXISTS addon_group_assignments (
+    id BIGSERIAL PRIMARY KEY NOT NULL,
+    addon_group_id BIGINT NOT NULL,
+    product_id BIGINT,
+    category_id BIGINT,
+    created_at BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    CONSTRAINT chk_addon_group_assignments_target CHECK (
+        (product_id IS NULL) <> (category_id IS NULL)
+    )
+);
+
+ALTER TABLE addon_group_assignments ADD CONSTRAINT fk_addon_groups_addon_group_assignments
+    FOREIGN KEY (addon_group_id) REFERENCES addon_groups(id) ON DELETE CASCADE;
+
+ALTER TABLE addon_group_assignments ADD CONSTRAINT fk_products_addon_group_assignments
+    FOREIGN KEY (product_id) REFERENCES products(id) ON DELETE CASCADE;
+
+ALTER TABLE addon_group_assignments ADD CONSTRAINT fk_categories_addon_group_assignments
+    FOREIGN KEY (category_id) REFERENCES categories(id) ON DELETE CASCADE;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_addon_group_assignments_product
+    ON addon_group_assignments (addon_group_id, product_id) WHERE product_id IS NOT NULL;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_addon_group_assignments_category
+    ON addon_group_assignments (addon_group_id, category_id) WHERE category_id IS NOT NULL;
+
+CREATE TABLE IF NOT EXISTS product_addon_prices (
+    product_id BIGINT NOT NULL,
+    addon_id BIGINT NOT NULL,
+    price FLOAT NOT NULL,
+    created_at BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    updated_at BIGINT,
+    PRIMARY KEY (product_id, addon_id)
+);
+
+ALTER TABLE product_addon_prices ADD CONSTRAINT fk_products_product_addon_prices
+    FOREIGN KEY (product_id) REFERENCES products(id) ON DELETE CASCADE;
+
+ALTER TABLE product_addon_prices ADD CONSTRAINT fk_addons_product_addon_prices
+    FOREIGN KEY (addon_id) REFERENCES addons(id) ON DELETE CASCADE;
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS bundle_slot_options;
+DROP TABLE IF EXISTS bundle_slots;
+DROP TABLE IF EXISTS bundle_items;
@@ -0,0 +1,55 @@
+-- bundle_id is the product sold as the set menu
+CREATE TABLE IF NOT EXISTS bundle_items (
+    id BIGSERIAL PRIMARY KEY NOT NULL,
+    bundle_id BIGINT NOT NULL,
+    product_id BIGINT NOT NULL,
+    variant_id BIGINT,
+    qty INT NOT NULL DEFAULT 1 CHECK (qty > 0),
+    position INT NOT NULL DEFAULT 0,
+    CONSTRAINT chk_bundle_items_self CHECK (bundle_id <> product_id)
+);
+
+ALTER TABLE bundle_items ADD CONSTRAINT fk_bundles_bundle_items
+    FOREIGN KEY (bundle_id) REFERENCES products(id) ON DELETE CASCADE;
+
+ALTER TABLE bundle_items ADD CONSTRAINT fk_products_bundle_items
+    FOREIGN KEY (product_id) REFERENCES products(id);
+
+ALTER TABLE bundle_items ADD CONSTRAINT fk_product_variants_bundle_items
+    FOREIGN KEY (variant_id) REFERENCES product_variants(id);
+
+CREATE TABLE IF NOT EXISTS bundle_slots (
+    id BIGSERIAL PRIMARY KEY NOT NULL,
+    bundle_id BIGINT NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    subcategory_id BIGINT,
+    qty INT NOT NULL DEFAULT 1 CHECK (qty > 0),
+    position INT NOT NULL DEFAULT 0
+);
+
+ALTER TABLE bundle_slots ADD CONSTRAINT fk_bundles_bundle_slots
+    FOREIGN KEY (bundle_id) REFERENCES products(id) ON DELETE CASCADE;
+
+ALTER TABLE bundle_slots ADD CONSTRAINT fk_subcategories_bundle_slots
+    FOREIGN KEY (subcategory_id) REFERENCES subcategories(id);
+
+CREATE TABLE IF NOT EXISTS bundle_slot_options (
+    id BIGSERIAL PRIMARY KEY NOT NULL,
+    slot_id BIGINT NOT NULL,
+    product_id BIGINT NOT NULL,
+    variant_id BIGINT,
+    upcharge FLOAT NOT NULL DEFAULT 0 CHECK (upcharge >= 0),
+    position INT NOT NULL DEFAULT 0
+);
+
+ALTER TABLE bundle_slot_options ADD CONSTRAINT fk_bundle_slots_bundle_slot_options
+    FOREIGN KEY (slot_id) REFERENCES bundle_slots(id) ON DELETE CASCADE;
+
+ALTER TABLE bundle_slot_options ADD CONSTRAINT fk_products_bundle_slot_options
+    FOREIGN KEY (product_id) REFERENCES products(id);
+
+ALTER TABLE bundle_slot_options ADD CONSTRAINT fk_product_variants_bundle_slot_options
+    FOREIGN KEY (variant_id) REFERENCES product_variants(id);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_bundle_slot_options_product
+    ON bundle_slot_options (slot_id, product_id, COALESCE(variant_id, 0));
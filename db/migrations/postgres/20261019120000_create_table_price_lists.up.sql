@@ -0,0 +1,42 @@
+-- channel: dine_in, takeaway, delivery (NULL = every channel)
+-- days: bitmask of weekday, bit 0 = sunday (0 = every day)
+-- start_time/end_time: HH:MM in store timezone, end before start pass midnight
+CREATE TABLE IF NOT EXISTS price_lists (
+    id BIGSERIAL PRIMARY KEY NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    channel VARCHAR(20),
+    customer_tier VARCHAR(50),
+    days INT NOT NULL DEFAULT 0,
+    start_time VARCHAR(5),
+    end_time VARCHAR(5),
+    effective_from BIGINT,
+    effective_to BIGINT,
+    priority INT NOT NULL DEFAULT 0,
+    disabled BOOLEAN NOT NULL DEFAULT false,
+    created_at BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    updated_at BIGINT,
+    CONSTRAINT chk_price_lists_channel CHECK (channel IN ('dine_in', 'takeaway', 'delivery')),
+    CONSTRAINT chk_price_lists_window CHECK ((start_time IS NULL) = (end_time IS NULL)),
+    CONSTRAINT chk_price_lists_effective CHECK (
+        effective_from IS NULL OR effective_to IS NULL OR effective_from < effective_to
+    )
+);
+
+-- item_type: product, variant, addon
+CREATE TABLE IF NOT EXISTS price_list_items (
+    id BIGSERIAL PRIMARY KEY NOT NULL,
+    price_list_id BIGINT NOT NULL,
+    item_type VARCHAR(10) NOT NULL,
+    item_id BIGINT NOT NULL,
+    price FLOAT NOT NULL CHECK (price >= 0),
+    CONSTRAINT chk_price_list_items_type CHECK (item_type IN ('product', 'variant', 'addon'))
+);
+
+ALTER TABLE price_list_items ADD CONSTRAINT fk_price_lists_price_list_items
+    FOREIGN KEY (price_list_id) REFERENCES price_lists(id) ON DELETE CASCADE;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_price_list_items_item
+    ON price_list_items (price_list_id, item_type, item_id);
+
+CREATE INDEX IF NOT EXISTS idx_price_list_items_lookup
+    ON price_list_items (item_type, item_id);
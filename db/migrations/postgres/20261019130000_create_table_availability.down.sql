@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS sold_out_items;
+DROP TABLE IF EXISTS availability_rules;
@@ -0,0 +1,51 @@
+-- a rule belong either to a product or to a whole category
+-- days: bitmask of weekday, bit 0 = sunday (0 = every day)
+-- start_time/end_time: HH:MM in store timezone, end before start pass midnight
+CREATE TABLE IF NOT EXISTS availability_rules (
+    id BIGSERIAL PRIMARY KEY NOT NULL,
+    product_id BIGINT,
+    category_id BIGINT,
+    days INT NOT NULL DEFAULT 0,
+    start_time VARCHAR(5),
+    end_time VARCHAR(5),
+    created_at BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    CONSTRAINT chk_availability_rules_target CHECK (
+        (product_id IS NULL) <> (category_id IS NULL)
+    ),
+    CONSTRAINT chk_availability_rules_window CHECK ((start_time IS NULL) = (end_time IS NULL))
+);
+
+ALTER TABLE availability_rules ADD CONSTRAINT fk_products_availability_rules
+    FOREIGN KEY (product_id) REFERENCES products(id) ON DELETE CASCADE;
+
+ALTER TABLE availability_rules ADD CONSTRAINT fk_categories_availability_rules
+    FOREIGN KEY (category_id) REFERENCES categories(id) ON DELETE CASCADE;
+
+CREATE INDEX IF NOT EXISTS idx_availability_rules_product
+    ON availability_rules (product_id) WHERE product_id IS NOT NULL;
+
+CREATE INDEX IF NOT EXISTS idx_availability_rules_category
+    ON availability_rules (category_id) WHERE category_id IS NOT NULL;
+
+-- 86'd product (variant_id NULL) or variant, in effect while the
+-- store shift is still open or until cleared when store_shift_id is NULL
+CREATE TABLE IF NOT EXISTS sold_out_items (
+    id BIGSERIAL PRIMARY KEY NOT NULL,
+    product_id BIGINT NOT NULL,
+    variant_id BIGINT,
+    store_shift_id BIGINT,
+    created_by BIGINT,
+    created_at BIGINT NOT NULL DEFAULT extract(epoch from now())
+);
+
+ALTER TABLE sold_out_items ADD CONSTRAINT fk_products_sold_out_items
+    FOREIGN KEY (product_id) REFERENCES products(id) ON DELETE CASCADE;
+
+ALTER TABLE sold_out_items ADD CONSTRAINT fk_product_variants_sold_out_items
+    FOREIGN KEY (variant_id) REFERENCES product_variants(id) ON DELETE CASCADE;
+
+ALTER TABLE sold_out_items ADD CONSTRAINT fk_store_shifts_sold_out_items
+    FOREIGN KEY (store_shift_id) REFERENCES store_shifts(id) ON DELETE CASCADE;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_sold_out_items_item
+    ON sold_out_items (product_id, (COALESCE(variant_id, 0)));
@@ -0,0 +1,25 @@
+CREATE OR REPLACE FUNCTION pg_temp.money_scale() RETURNS NUMERIC AS $$
+    SELECT CASE WHEN upper(value) IN ('JPY', 'KRW', 'VND') THEN 1 ELSE 100 END
+    FROM (SELECT COALESCE((SELECT value FROM store_prefs WHERE key = 'currency'), 'IDR') AS value) AS pref
+$$ LANGUAGE SQL STABLE;
+
+UPDATE store_shifts SET
+    open_cash = round(open_cash / pg_temp.money_scale()),
+    close_cash = round(close_cash / pg_temp.money_scale());
+
+ALTER TABLE price_list_items ALTER COLUMN price TYPE FLOAT
+    USING price / pg_temp.money_scale();
+ALTER TABLE bundle_slot_options ALTER COLUMN upcharge DROP DEFAULT;
+ALTER TABLE bundle_slot_options ALTER COLUMN upcharge TYPE FLOAT
+    USING upcharge / pg_temp.money_scale();
+ALTER TABLE bundle_slot_options ALTER COLUMN upcharge SET DEFAULT 0;
+ALTER TABLE product_addon_prices ALTER COLUMN price TYPE FLOAT
+    USING price / pg_temp.money_scale();
+ALTER TABLE rooms ALTER COLUMN price TYPE FLOAT
+    USING price / pg_temp.money_scale();
+ALTER TABLE addons ALTER COLUMN price TYPE FLOAT
+    USING price / pg_temp.money_scale();
+ALTER TABLE product_variants ALTER COLUMN price TYPE FLOAT
+    USING price / pg_temp.money_scale();
+ALTER TABLE products ALTER COLUMN price TYPE FLOAT
+    USING price / pg_temp.money_scale();
@@ -0,0 +1,57 @@
+-- money is stored as BIGINT minor unit of the store currency (e.g: cent),
+-- the scale follow ISO 4217 exponent of the currency store pref:
+-- JPY, KRW and VND have no minor unit, every other supported currency has 2
+CREATE OR REPLACE FUNCTION pg_temp.money_scale() RETURNS NUMERIC AS $$
+    SELECT CASE WHEN upper(value) IN ('JPY', 'KRW', 'VND') THEN 1 ELSE 100 END
+    FROM (SELECT COALESCE((SELECT value FROM store_prefs WHERE key = 'currency'), 'IDR') AS value) AS pref
+$$ LANGUAGE SQL STABLE;
+
+-- the decimal the price was entered as, the shortest text of the float8
+-- (postgres 12+) or of the float4 when it was widened from one (e.g:
+-- 1234.56 saved by a float32 client is 1234.56005859375 as float8)
+CREATE OR REPLACE FUNCTION pg_temp.money_value(v FLOAT8) RETURNS NUMERIC AS $$
+    SELECT CASE WHEN v = v::REAL::FLOAT8 THEN v::REAL::TEXT::NUMERIC ELSE v::TEXT::NUMERIC END
+$$ LANGUAGE SQL IMMUTABLE;
+
+-- refuse to convert a price that has more decimals than the currency allow,
+-- it has to be fixed by hand instead of being rounded silently
+DO $$
+DECLARE
+    source TEXT;
+    lossy BIGINT;
+BEGIN
+    FOREACH source IN ARRAY ARRAY[
+        'products.price', 'product_variants.price', 'addons.price', 'rooms.price',
+        'product_addon_prices.price', 'bundle_slot_options.upcharge', 'price_list_items.price'
+    ] LOOP
+        EXECUTE format(
+            'SELECT count(*) FROM %s WHERE pg_temp.money_value(%s) * pg_temp.money_scale() <> round(pg_temp.money_value(%s) * pg_temp.money_scale())',
+            split_part(source, '.', 1), split_part(source, '.', 2), split_part(source, '.', 2)
+        ) INTO lossy;
+        IF lossy > 0 THEN
+            RAISE EXCEPTION '% has % value(s) with more decimals than the store currency allow', source, lossy;
+        END IF;
+    END LOOP;
+END $$;
+
+ALTER TABLE products ALTER COLUMN price TYPE BIGINT
+    USING round(pg_temp.money_value(price) * pg_temp.money_scale());
+ALTER TABLE product_variants ALTER COLUMN price TYPE BIGINT
+    USING round(pg_temp.money_value(price) * pg_temp.money_scale());
+ALTER TABLE addons ALTER COLUMN price TYPE BIGINT
+    USING round(pg_temp.money_value(price) * pg_temp.money_scale());
+ALTER TABLE rooms ALTER COLUMN price TYPE BIGINT
+    USING round(pg_temp.money_value(price) * pg_temp.money_scale());
+ALTER TABLE product_addon_prices ALTER COLUMN price TYPE BIGINT
+    USING round(pg_temp.money_value(price) * pg_temp.money_scale());
+ALTER TABLE bundle_slot_options ALTER COLUMN upcharge DROP DEFAULT;
+ALTER TABLE bundle_slot_options ALTER COLUMN upcharge TYPE BIGINT
+    USING round(pg_temp.money_value(upcharge) * pg_temp.money_scale());
+ALTER TABLE bundle_slot_options ALTER COLUMN upcharge SET DEFAULT 0;
+ALTER TABLE price_list_items ALTER COLUMN price TYPE BIGINT
+    USING round(pg_temp.money_value(price) * pg_temp.money_scale());
+
+-- shift cash was kept in whole unit
+UPDATE store_shifts SET
+    open_cash = open_cash * pg_temp.money_scale(),
+    close_cash = close_cash * pg_temp.money_scale();
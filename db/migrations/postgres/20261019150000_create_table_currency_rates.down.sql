@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS cash_tenders;
+DROP TABLE IF EXISTS currency_rates;
@@ -0,0 +1,42 @@
+-- rate: how many store currency one unit of the currency buy (e.g: 16200 IDR = 1 USD),
+-- never updated, a new row is added so past conversions keep their rate
+CREATE TABLE IF NOT EXISTS currency_rates (
+    id BIGSERIAL PRIMARY KEY NOT NULL,
+    currency VARCHAR(3) NOT NULL,
+    rate NUMERIC(20, 8) NOT NULL CHECK (rate > 0),
+    created_by BIGINT,
+    created_at BIGINT NOT NULL DEFAULT extract(epoch from now())
+);
+
+CREATE INDEX IF NOT EXISTS idx_currency_rates_latest
+    ON currency_rates (currency, created_at DESC, id DESC);
+
+-- the currency_rate pref is "TO USD", keep it as the first USD rate
+INSERT INTO currency_rates (currency, rate)
+SELECT 'USD', rate.value::NUMERIC
+FROM store_prefs AS rate
+JOIN store_prefs AS base ON base.key = 'currency' AND upper(base.value) <> 'USD'
+WHERE rate.key = 'currency_rate' AND rate.value ~ '^[0-9]+(\.[0-9]+)?$' AND rate.value::NUMERIC > 0;
+
+-- amount, base_amount, due and change are minor unit of currency and base_currency,
+-- currency_rate_id is NULL when tendered in the store currency
+CREATE TABLE IF NOT EXISTS cash_tenders (
+    id BIGSERIAL PRIMARY KEY NOT NULL,
+    store_shift_id BIGINT,
+    currency_rate_id BIGINT,
+    currency VARCHAR(3) NOT NULL,
+    rate NUMERIC(20, 8) NOT NULL,
+    amount BIGINT NOT NULL CHECK (amount > 0),
+    base_currency VARCHAR(3) NOT NULL,
+    base_amount BIGINT NOT NULL,
+    due BIGINT NOT NULL CHECK (due >= 0),
+    change BIGINT NOT NULL CHECK (change >= 0),
+    created_by BIGINT,
+    created_at BIGINT NOT NULL DEFAULT extract(epoch from now())
+);
+
+ALTER TABLE cash_tenders ADD CONSTRAINT fk_store_shifts_cash_tenders
+    FOREIGN KEY (store_shift_id) REFERENCES store_shifts(id);
+
+ALTER TABLE cash_tenders ADD CONSTRAINT fk_currency_rates_cash_tenders
+    FOREIGN KEY (currency_rate_id) REFERENCES currency_rates(id);
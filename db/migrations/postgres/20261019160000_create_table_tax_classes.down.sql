@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS tax_class_assignments;
+DROP TABLE IF EXISTS tax_classes;
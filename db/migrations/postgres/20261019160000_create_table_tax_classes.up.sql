@@ -0,0 +1,48 @@
+-- rate in percentage, inclusive price already contain the tax,
+-- after_service also tax the service charge, one class is the default
+CREATE TABLE IF NOT EXISTS tax_classes (
+    id BIGSERIAL PRIMARY KEY NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    rate NUMERIC(7,4) NOT NULL DEFAULT 0,
+    inclusive BOOLEAN NOT NULL DEFAULT false,
+    after_service BOOLEAN NOT NULL DEFAULT false,
+    is_default BOOLEAN NOT NULL DEFAULT false,
+    created_at BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    updated_at BIGINT,
+    CONSTRAINT chk_tax_classes_rate CHECK (rate >= 0 AND rate <= 100)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_tax_classes_default
+    ON tax_classes (is_default) WHERE is_default = true;
+
+-- a product or a category belong to at most one class
+CREATE TABLE IF NOT EXISTS tax_class_assignments (
+    id BIGSERIAL PRIMARY KEY NOT NULL,
+    tax_class_id BIGINT NOT NULL,
+    product_id BIGINT,
+    category_id BIGINT,
+    CONSTRAINT chk_tax_class_assignments_target CHECK (
+        (product_id IS NULL) <> (category_id IS NULL)
+    )
+);
+
+ALTER TABLE tax_class_assignments ADD CONSTRAINT fk_tax_classes_tax_class_assignments
+    FOREIGN KEY (tax_class_id) REFERENCES tax_classes(id) ON DELETE CASCADE;
+
+ALTER TABLE tax_class_assignments ADD CONSTRAINT fk_products_tax_class_assignments
+    FOREIGN KEY (product_id) REFERENCES products(id) ON DELETE CASCADE;
+
+ALTER TABLE tax_class_assignments ADD CONSTRAINT fk_categories_tax_class_assignments
+    FOREIGN KEY (category_id) REFERENCES categories(id) ON DELETE CASCADE;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_tax_class_assignments_product
+    ON tax_class_assignments (product_id) WHERE product_id IS NOT NULL;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_tax_class_assignments_category
+    ON tax_class_assignments (category_id) WHERE category_id IS NOT NULL;
+
+-- keep the store wide tax_rate pref as the default class
+INSERT INTO tax_classes (name, rate, is_default)
+SELECT 'Standard', COALESCE((SELECT value FROM store_prefs WHERE key = 'tax_rate'), '0')::NUMERIC, true;
+
+INSERT INTO tax_classes (name, rate) VALUES ('Exempt', 0);
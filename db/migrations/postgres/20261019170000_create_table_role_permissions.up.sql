@@ -0,0 +1,28 @@
+-- permission names are declared by the api routes (see model.Permissions)
+CREATE TABLE IF NOT EXISTS role_permissions (
+    role_id BIGINT NOT NULL,
+    permission VARCHAR(100) NOT NULL,
+    PRIMARY KEY (role_id, permission)
+);
+
+ALTER TABLE role_permissions ADD CONSTRAINT fk_roles_role_permissions
+    FOREIGN KEY (role_id) REFERENCES roles(id) ON DELETE CASCADE;
+
+INSERT INTO role_permissions (role_id, permission)
+SELECT roles.id, p.permission FROM roles CROSS JOIN (VALUES
+    ('account.user.read'), ('account.user.write'), ('account.role.write'),
+    ('catalog.read'), ('catalog.product.write'), ('catalog.price.write'),
+    ('catalog.availability.write'), ('store.read'), ('store.write'),
+    ('order.read'), ('order.tender'), ('report.export')
+) AS p(permission) WHERE roles.name = 'admin';
+
+INSERT INTO role_permissions (role_id, permission)
+SELECT roles.id, p.permission FROM roles CROSS JOIN (VALUES
+    ('catalog.read'), ('catalog.availability.write'), ('store.read'),
+    ('order.read'), ('order.tender')
+) AS p(permission) WHERE roles.name = 'cashier';
+
+INSERT INTO role_permissions (role_id, permission)
+SELECT roles.id, p.permission FROM roles CROSS JOIN (VALUES
+    ('catalog.read'), ('store.read'), ('order.read')
+) AS p(permission) WHERE roles.name = 'waiter';
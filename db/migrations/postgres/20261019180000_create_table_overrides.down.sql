@@ -0,0 +1,5 @@
+DELETE FROM role_permissions WHERE permission IN
+    ('order.void', 'order.refund', 'order.price_override', 'order.discount', 'cash_drawer.open');
+DROP TABLE IF EXISTS cash_drawer_opens;
+DROP TABLE IF EXISTS overrides;
+ALTER TABLE users DROP COLUMN IF EXISTS pin;
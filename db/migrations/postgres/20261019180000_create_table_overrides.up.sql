@@ -0,0 +1,37 @@
+-- scrypt hash of the 4-6 digit pin, used to approve overrides
+ALTER TABLE users ADD COLUMN IF NOT EXISTS pin VARCHAR(255);
+
+-- single-use approval of a sensitive action, token is stored as sha256 hex
+CREATE TABLE IF NOT EXISTS overrides (
+    id BIGSERIAL PRIMARY KEY NOT NULL,
+    token_hash VARCHAR(64) NOT NULL UNIQUE,
+    action VARCHAR(100) NOT NULL,
+    reason VARCHAR(255),
+    approved_by BIGINT NOT NULL,
+    requested_by BIGINT,
+    expires_at BIGINT NOT NULL,
+    used_by BIGINT,
+    used_at BIGINT,
+    created_at BIGINT NOT NULL DEFAULT extract(epoch from now())
+);
+
+CREATE INDEX IF NOT EXISTS idx_overrides_approved_by ON overrides (approved_by);
+
+-- no-sale cash drawer open
+CREATE TABLE IF NOT EXISTS cash_drawer_opens (
+    id BIGSERIAL PRIMARY KEY NOT NULL,
+    store_shift_id BIGINT NOT NULL,
+    reason VARCHAR(255) NOT NULL,
+    opened_by BIGINT,
+    approved_by BIGINT,
+    created_at BIGINT NOT NULL DEFAULT extract(epoch from now())
+);
+
+ALTER TABLE cash_drawer_opens ADD CONSTRAINT fk_store_shifts_cash_drawer_opens
+    FOREIGN KEY (store_shift_id) REFERENCES store_shifts(id) ON DELETE CASCADE;
+
+INSERT INTO role_permissions (role_id, permission)
+SELECT roles.id, p.permission FROM roles CROSS JOIN (VALUES
+    ('order.void'), ('order.refund'), ('order.price_override'),
+    ('order.discount'), ('cash_drawer.open')
+) AS p(permission) WHERE roles.name = 'admin' ON CONFLICT DO NOTHING;
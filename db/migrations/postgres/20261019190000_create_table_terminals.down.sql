@@ -0,0 +1,6 @@
+DELETE FROM role_permissions WHERE permission = 'account.terminal.write';
+DROP INDEX IF EXISTS idx_users_badge_hash;
+ALTER TABLE users DROP COLUMN IF EXISTS pin_locked_until;
+ALTER TABLE users DROP COLUMN IF EXISTS pin_failures;
+ALTER TABLE users DROP COLUMN IF EXISTS badge_hash;
+DROP TABLE IF EXISTS terminals;
@@ -0,0 +1,23 @@
+-- shared device, key is stored as sha256 hex
+CREATE TABLE IF NOT EXISTS terminals (
+    id BIGSERIAL PRIMARY KEY NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    key_hash VARCHAR(64) NOT NULL UNIQUE,
+    idle_timeout INT NOT NULL DEFAULT 300, -- in seconds
+    disabled BOOLEAN NOT NULL DEFAULT false,
+    created_at BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    updated_at BIGINT
+);
+
+-- badge code is stored as sha256 hex so it can be looked up,
+-- pin is locked until pin_locked_until after repeated failures
+ALTER TABLE users ADD COLUMN IF NOT EXISTS badge_hash VARCHAR(64);
+ALTER TABLE users ADD COLUMN IF NOT EXISTS pin_failures INT NOT NULL DEFAULT 0;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS pin_locked_until BIGINT;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_badge_hash ON users (badge_hash)
+    WHERE badge_hash IS NOT NULL;
+
+INSERT INTO role_permissions (role_id, permission)
+SELECT roles.id, 'account.terminal.write' FROM roles
+WHERE roles.name = 'admin' ON CONFLICT DO NOTHING;
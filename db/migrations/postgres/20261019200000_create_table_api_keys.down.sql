@@ -0,0 +1,2 @@
+DELETE FROM role_permissions WHERE permission = 'account.api_key.write';
+DROP TABLE IF EXISTS api_keys;
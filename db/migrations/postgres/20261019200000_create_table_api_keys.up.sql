@@ -0,0 +1,20 @@
+-- key of machine integration, stored as sha256 hex, prefix is shown to tell keys apart
+CREATE TABLE IF NOT EXISTS api_keys (
+    id BIGSERIAL PRIMARY KEY NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    prefix VARCHAR(20) NOT NULL,
+    key_hash VARCHAR(64) NOT NULL UNIQUE,
+    previous_key_hash VARCHAR(64) UNIQUE,
+    previous_valid_until BIGINT,
+    scopes TEXT[] NOT NULL DEFAULT '{}', -- permission names
+    expires_at BIGINT,
+    last_used_at BIGINT,
+    revoked_at BIGINT,
+    created_by BIGINT,
+    created_at BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    updated_at BIGINT
+);
+
+INSERT INTO role_permissions (role_id, permission)
+SELECT roles.id, 'account.api_key.write' FROM roles
+WHERE roles.name = 'admin' ON CONFLICT DO NOTHING;
@@ -0,0 +1,26 @@
+-- append-only log of every mutating request, before_data is the row before
+-- the change and after_data the respond data (secrets are redacted)
+CREATE TABLE IF NOT EXISTS audit_logs (
+    id BIGSERIAL PRIMARY KEY NOT NULL,
+    actor_id BIGINT,
+    api_key_id BIGINT,
+    terminal_id BIGINT,
+    session_id VARCHAR(64),
+    action VARCHAR(20) NOT NULL,
+    method VARCHAR(10) NOT NULL,
+    route VARCHAR(255) NOT NULL,
+    entity_type VARCHAR(100) NOT NULL,
+    entity_id VARCHAR(100),
+    status INT NOT NULL,
+    before_data JSONB,
+    after_data JSONB,
+    ip VARCHAR(45),
+    created_at BIGINT NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_audit_logs_created_at ON audit_logs (created_at);
+CREATE INDEX IF NOT EXISTS idx_audit_logs_entity ON audit_logs (entity_type, entity_id);
+CREATE INDEX IF NOT EXISTS idx_audit_logs_actor_id ON audit_logs (actor_id);
+
+INSERT INTO role_permissions (role_id, permission)
+SELECT id, 'audit.read' FROM roles WHERE name = 'admin' ON CONFLICT DO NOTHING;
@@ -0,0 +1,8 @@
+DROP TRIGGER IF EXISTS trg_store_prefs_versions ON store_prefs;
+DROP TRIGGER IF EXISTS trg_addons_versions ON addons;
+DROP TRIGGER IF EXISTS trg_product_variants_versions ON product_variants;
+DROP TRIGGER IF EXISTS trg_products_versions ON products;
+
+DROP FUNCTION IF EXISTS record_entity_version();
+
+DROP TABLE IF EXISTS entity_versions;
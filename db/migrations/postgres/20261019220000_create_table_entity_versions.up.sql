@@ -0,0 +1,68 @@
+-- every change of versioned rows, data is the whole row (null when deleted),
+-- a version is in force from valid_from until valid_to (null while current)
+CREATE TABLE IF NOT EXISTS entity_versions (
+    id BIGSERIAL PRIMARY KEY NOT NULL,
+    entity_type VARCHAR(50) NOT NULL,
+    entity_id VARCHAR(100) NOT NULL,
+    version INT NOT NULL,
+    data JSONB,
+    changed_by BIGINT,
+    valid_from BIGINT NOT NULL,
+    valid_to BIGINT,
+    UNIQUE (entity_type, entity_id, version)
+);
+
+CREATE INDEX IF NOT EXISTS idx_entity_versions_valid
+    ON entity_versions (entity_type, valid_from, valid_to);
+
+-- TG_ARGV[0] is the key column of the table, the actor is set
+-- by the repository with set_config('posbe.actor_id', id, true)
+CREATE OR REPLACE FUNCTION record_entity_version() RETURNS TRIGGER AS $$
+DECLARE
+    row_data JSONB;
+    row_id TEXT;
+    changed_at BIGINT := extract(epoch from now())::BIGINT;
+    next_version INT;
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        row_id := to_jsonb(OLD) ->> TG_ARGV[0];
+    ELSE
+        row_data := to_jsonb(NEW);
+        row_id := row_data ->> TG_ARGV[0];
+        IF TG_OP = 'UPDATE' AND row_data = to_jsonb(OLD) THEN
+            RETURN NULL;
+        END IF;
+    END IF;
+
+    UPDATE entity_versions SET valid_to = changed_at
+    WHERE entity_type = TG_TABLE_NAME AND entity_id = row_id AND valid_to IS NULL;
+
+    SELECT COALESCE(MAX(version), 0) + 1 INTO next_version FROM entity_versions
+    WHERE entity_type = TG_TABLE_NAME AND entity_id = row_id;
+
+    INSERT INTO entity_versions (entity_type, entity_id, version, data, changed_by, valid_from)
+    VALUES (TG_TABLE_NAME, row_id, next_version, row_data,
+        NULLIF(current_setting('posbe.actor_id', true), '')::BIGINT, changed_at);
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+-- current rows are the first version, in force since ever
+INSERT INTO entity_versions (entity_type, entity_id, version, data, valid_from)
+SELECT 'products', id::TEXT, 1, to_jsonb(products), 0 FROM products
+UNION ALL
+SELECT 'product_variants', id::TEXT, 1, to_jsonb(product_variants), 0 FROM product_variants
+UNION ALL
+SELECT 'addons', id::TEXT, 1, to_jsonb(addons), 0 FROM addons
+UNION ALL
+SELECT 'store_prefs', key, 1, to_jsonb(store_prefs), 0 FROM store_prefs
+ON CONFLICT DO NOTHING;
+
+CREATE TRIGGER trg_products_versions AFTER INSERT OR UPDATE OR DELETE ON products
+    FOR EACH ROW EXECUTE FUNCTION record_entity_version('id');
+CREATE TRIGGER trg_product_variants_versions AFTER INSERT OR UPDATE OR DELETE ON product_variants
+    FOR EACH ROW EXECUTE FUNCTION record_entity_version('id');
+CREATE TRIGGER trg_addons_versions AFTER INSERT OR UPDATE OR DELETE ON addons
+    FOR EACH ROW EXECUTE FUNCTION record_entity_version('id');
+CREATE TRIGGER trg_store_prefs_versions AFTER INSERT OR UPDATE OR DELETE ON store_prefs
+    FOR EACH ROW EXECUTE FUNCTION record_entity_version('key');
@@ -0,0 +1,25 @@
+DELETE FROM role_permissions WHERE permission = 'trash.restore';
+
+DROP INDEX IF EXISTS users_username_key;
+DROP INDEX IF EXISTS users_email_key;
+DROP INDEX IF EXISTS users_phone_key;
+DROP INDEX IF EXISTS roles_name_key;
+DROP INDEX IF EXISTS products_sku_key;
+ALTER TABLE users ADD CONSTRAINT users_username_key UNIQUE (username);
+ALTER TABLE users ADD CONSTRAINT users_email_key UNIQUE (email);
+ALTER TABLE users ADD CONSTRAINT users_phone_key UNIQUE (phone);
+ALTER TABLE roles ADD CONSTRAINT roles_name_key UNIQUE (name);
+ALTER TABLE products ADD CONSTRAINT products_sku_key UNIQUE (sku);
+
+ALTER TABLE units DROP COLUMN IF EXISTS deleted_at, DROP COLUMN IF EXISTS deleted_by;
+ALTER TABLE categories DROP COLUMN IF EXISTS deleted_at, DROP COLUMN IF EXISTS deleted_by;
+ALTER TABLE subcategories DROP COLUMN IF EXISTS deleted_at, DROP COLUMN IF EXISTS deleted_by;
+ALTER TABLE addons DROP COLUMN IF EXISTS deleted_at, DROP COLUMN IF EXISTS deleted_by;
+ALTER TABLE products DROP COLUMN IF EXISTS deleted_at, DROP COLUMN IF EXISTS deleted_by;
+ALTER TABLE product_variants DROP COLUMN IF EXISTS deleted_at, DROP COLUMN IF EXISTS deleted_by;
+ALTER TABLE users DROP COLUMN IF EXISTS deleted_at, DROP COLUMN IF EXISTS deleted_by;
+ALTER TABLE roles DROP COLUMN IF EXISTS deleted_at, DROP COLUMN IF EXISTS deleted_by;
+ALTER TABLE terminals DROP COLUMN IF EXISTS deleted_at, DROP COLUMN IF EXISTS deleted_by;
+ALTER TABLE floors DROP COLUMN IF EXISTS deleted_at, DROP COLUMN IF EXISTS deleted_by;
+ALTER TABLE tables DROP COLUMN IF EXISTS deleted_at, DROP COLUMN IF EXISTS deleted_by;
+ALTER TABLE rooms DROP COLUMN IF EXISTS deleted_at, DROP COLUMN IF EXISTS deleted_by;
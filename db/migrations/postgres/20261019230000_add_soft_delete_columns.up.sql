@@ -0,0 +1,30 @@
+-- deleted rows are kept for the orders and reports that reference them,
+-- deleted_at (unix) and deleted_by (user) are set by Delete and cleared by Restore
+ALTER TABLE units ADD COLUMN IF NOT EXISTS deleted_at BIGINT, ADD COLUMN IF NOT EXISTS deleted_by BIGINT;
+ALTER TABLE categories ADD COLUMN IF NOT EXISTS deleted_at BIGINT, ADD COLUMN IF NOT EXISTS deleted_by BIGINT;
+ALTER TABLE subcategories ADD COLUMN IF NOT EXISTS deleted_at BIGINT, ADD COLUMN IF NOT EXISTS deleted_by BIGINT;
+ALTER TABLE addons ADD COLUMN IF NOT EXISTS deleted_at BIGINT, ADD COLUMN IF NOT EXISTS deleted_by BIGINT;
+ALTER TABLE products ADD COLUMN IF NOT EXISTS deleted_at BIGINT, ADD COLUMN IF NOT EXISTS deleted_by BIGINT;
+ALTER TABLE product_variants ADD COLUMN IF NOT EXISTS deleted_at BIGINT, ADD COLUMN IF NOT EXISTS deleted_by BIGINT;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS deleted_at BIGINT, ADD COLUMN IF NOT EXISTS deleted_by BIGINT;
+ALTER TABLE roles ADD COLUMN IF NOT EXISTS deleted_at BIGINT, ADD COLUMN IF NOT EXISTS deleted_by BIGINT;
+ALTER TABLE terminals ADD COLUMN IF NOT EXISTS deleted_at BIGINT, ADD COLUMN IF NOT EXISTS deleted_by BIGINT;
+ALTER TABLE floors ADD COLUMN IF NOT EXISTS deleted_at BIGINT, ADD COLUMN IF NOT EXISTS deleted_by BIGINT;
+ALTER TABLE tables ADD COLUMN IF NOT EXISTS deleted_at BIGINT, ADD COLUMN IF NOT EXISTS deleted_by BIGINT;
+ALTER TABLE rooms ADD COLUMN IF NOT EXISTS deleted_at BIGINT, ADD COLUMN IF NOT EXISTS deleted_by BIGINT;
+
+-- a deleted row no longer hold its unique name, sku or login
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_username_key;
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_email_key;
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_phone_key;
+ALTER TABLE roles DROP CONSTRAINT IF EXISTS roles_name_key;
+ALTER TABLE products DROP CONSTRAINT IF EXISTS products_sku_key;
+CREATE UNIQUE INDEX IF NOT EXISTS users_username_key ON users (username) WHERE deleted_at IS NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS users_email_key ON users (email) WHERE deleted_at IS NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS users_phone_key ON users (phone) WHERE deleted_at IS NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS roles_name_key ON roles (name) WHERE deleted_at IS NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS products_sku_key ON products (sku) WHERE deleted_at IS NULL;
+
+INSERT INTO role_permissions (role_id, permission)
+SELECT roles.id, 'trash.restore' FROM roles
+WHERE roles.name = 'admin' ON CONFLICT DO NOTHING;
@@ -0,0 +1,30 @@
+DROP TRIGGER IF EXISTS trg_store_shifts_sync ON store_shifts;
+DROP TRIGGER IF EXISTS trg_store_prefs_sync ON store_prefs;
+DROP TRIGGER IF EXISTS trg_rooms_sync ON rooms;
+DROP TRIGGER IF EXISTS trg_tables_sync ON tables;
+DROP TRIGGER IF EXISTS trg_floors_sync ON floors;
+DROP TRIGGER IF EXISTS trg_sold_out_items_sync ON sold_out_items;
+DROP TRIGGER IF EXISTS trg_availability_rules_sync ON availability_rules;
+DROP TRIGGER IF EXISTS trg_tax_class_assignments_sync ON tax_class_assignments;
+DROP TRIGGER IF EXISTS trg_tax_classes_sync ON tax_classes;
+DROP TRIGGER IF EXISTS trg_price_list_items_sync ON price_list_items;
+DROP TRIGGER IF EXISTS trg_price_lists_sync ON price_lists;
+DROP TRIGGER IF EXISTS trg_bundle_slot_options_sync ON bundle_slot_options;
+DROP TRIGGER IF EXISTS trg_bundle_slots_sync ON bundle_slots;
+DROP TRIGGER IF EXISTS trg_bundle_items_sync ON bundle_items;
+DROP TRIGGER IF EXISTS trg_product_addon_prices_sync ON product_addon_prices;
+DROP TRIGGER IF EXISTS trg_addon_group_assignments_sync ON addon_group_assignments;
+DROP TRIGGER IF EXISTS trg_addon_group_items_sync ON addon_group_items;
+DROP TRIGGER IF EXISTS trg_addon_groups_sync ON addon_groups;
+DROP TRIGGER IF EXISTS trg_addons_sync ON addons;
+DROP TRIGGER IF EXISTS trg_product_variants_sync ON product_variants;
+DROP TRIGGER IF EXISTS trg_products_sync ON products;
+DROP TRIGGER IF EXISTS trg_subcategories_sync ON subcategories;
+DROP TRIGGER IF EXISTS trg_categories_sync ON categories;
+DROP TRIGGER IF EXISTS trg_units_sync ON units;
+
+DROP FUNCTION IF EXISTS record_sync_change();
+
+DROP TABLE IF EXISTS sync_changes;
+
+DROP SEQUENCE IF EXISTS sync_version_seq;
@@ -0,0 +1,147 @@
+-- the latest change of every row the terminals keep offline, version is the
+-- cursor of the pull (a terminal ask for the changes after the last version
+-- it has), data is the whole row (null when deleted)
+CREATE SEQUENCE IF NOT EXISTS sync_version_seq;
+
+CREATE TABLE IF NOT EXISTS sync_changes (
+    entity_type VARCHAR(50) NOT NULL,
+    entity_id VARCHAR(100) NOT NULL,
+    version BIGINT NOT NULL UNIQUE,
+    data JSONB,
+    changed_at BIGINT NOT NULL,
+    PRIMARY KEY (entity_type, entity_id)
+);
+
+-- TG_ARGV are the key columns of the table (joined with ':'), the writers
+-- wait for each other until commit so the versions become visible in order
+-- and a pull never skip a change committed after it
+CREATE OR REPLACE FUNCTION record_sync_change() RETURNS TRIGGER AS $$
+DECLARE
+    row_data JSONB;
+    row_key JSONB;
+    row_id TEXT;
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        row_key := to_jsonb(OLD);
+    ELSE
+        row_data := to_jsonb(NEW);
+        row_key := row_data;
+        IF TG_OP = 'UPDATE' AND row_data = to_jsonb(OLD) THEN
+            RETURN NULL;
+        END IF;
+    END IF;
+    SELECT string_agg(row_key ->> key_column, ':' ORDER BY ordinal) INTO row_id
+    FROM unnest(TG_ARGV) WITH ORDINALITY AS keys(key_column, ordinal);
+
+    PERFORM pg_advisory_xact_lock(hashtext('sync_changes'));
+    INSERT INTO sync_changes (entity_type, entity_id, version, data, changed_at)
+    VALUES (TG_TABLE_NAME, row_id, nextval('sync_version_seq'), row_data,
+        extract(epoch from now())::BIGINT)
+    ON CONFLICT (entity_type, entity_id) DO UPDATE SET
+        version = EXCLUDED.version, data = EXCLUDED.data, changed_at = EXCLUDED.changed_at;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+-- current rows are the first changes, a new terminal pull them all from 0
+INSERT INTO sync_changes (entity_type, entity_id, version, data, changed_at)
+SELECT entity_type, entity_id, nextval('sync_version_seq'), data, extract(epoch from now())::BIGINT
+FROM (
+    SELECT 'units' AS entity_type, id::TEXT AS entity_id, to_jsonb(units) AS data FROM units
+    UNION ALL
+    SELECT 'categories', id::TEXT, to_jsonb(categories) FROM categories
+    UNION ALL
+    SELECT 'subcategories', id::TEXT, to_jsonb(subcategories) FROM subcategories
+    UNION ALL
+    SELECT 'products', id::TEXT, to_jsonb(products) FROM products
+    UNION ALL
+    SELECT 'product_variants', id::TEXT, to_jsonb(product_variants) FROM product_variants
+    UNION ALL
+    SELECT 'addons', id::TEXT, to_jsonb(addons) FROM addons
+    UNION ALL
+    SELECT 'addon_groups', id::TEXT, to_jsonb(addon_groups) FROM addon_groups
+    UNION ALL
+    SELECT 'addon_group_items', addon_group_id::TEXT || ':' || addon_id::TEXT, to_jsonb(addon_group_items) FROM addon_group_items
+    UNION ALL
+    SELECT 'addon_group_assignments', id::TEXT, to_jsonb(addon_group_assignments) FROM addon_group_assignments
+    UNION ALL
+    SELECT 'product_addon_prices', product_id::TEXT || ':' || addon_id::TEXT, to_jsonb(product_addon_prices) FROM product_addon_prices
+    UNION ALL
+    SELECT 'bundle_items', id::TEXT, to_jsonb(bundle_items) FROM bundle_items
+    UNION ALL
+    SELECT 'bundle_slots', id::TEXT, to_jsonb(bundle_slots) FROM bundle_slots
+    UNION ALL
+    SELECT 'bundle_slot_options', id::TEXT, to_jsonb(bundle_slot_options) FROM bundle_slot_options
+    UNION ALL
+    SELECT 'price_lists', id::TEXT, to_jsonb(price_lists) FROM price_lists
+    UNION ALL
+    SELECT 'price_list_items', id::TEXT, to_jsonb(price_list_items) FROM price_list_items
+    UNION ALL
+    SELECT 'tax_classes', id::TEXT, to_jsonb(tax_classes) FROM tax_classes
+    UNION ALL
+    SELECT 'tax_class_assignments', id::TEXT, to_jsonb(tax_class_assignments) FROM tax_class_assignments
+    UNION ALL
+    SELECT 'availability_rules', id::TEXT, to_jsonb(availability_rules) FROM availability_rules
+    UNION ALL
+    SELECT 'sold_out_items', id::TEXT, to_jsonb(sold_out_items) FROM sold_out_items
+    UNION ALL
+    SELECT 'floors', id::TEXT, to_jsonb(floors) FROM floors
+    UNION ALL
+    SELECT 'tables', id::TEXT, to_jsonb(tables) FROM tables
+    UNION ALL
+    SELECT 'rooms', id::TEXT, to_jsonb(rooms) FROM rooms
+    UNION ALL
+    SELECT 'store_prefs', key::TEXT, to_jsonb(store_prefs) FROM store_prefs
+    UNION ALL
+    SELECT 'store_shifts', id::TEXT, to_jsonb(store_shifts) FROM store_shifts
+) AS current_rows
+ON CONFLICT DO NOTHING;
+
+CREATE TRIGGER trg_units_sync AFTER INSERT OR UPDATE OR DELETE ON units
+    FOR EACH ROW EXECUTE FUNCTION record_sync_change('id');
+CREATE TRIGGER trg_categories_sync AFTER INSERT OR UPDATE OR DELETE ON categories
+    FOR EACH ROW EXECUTE FUNCTION record_sync_change('id');
+CREATE TRIGGER trg_subcategories_sync AFTER INSERT OR UPDATE OR DELETE ON subcategories
+    FOR EACH ROW EXECUTE FUNCTION record_sync_change('id');
+CREATE TRIGGER trg_products_sync AFTER INSERT OR UPDATE OR DELETE ON products
+    FOR EACH ROW EXECUTE FUNCTION record_sync_change('id');
+CREATE TRIGGER trg_product_variants_sync AFTER INSERT OR UPDATE OR DELETE ON product_variants
+    FOR EACH ROW EXECUTE FUNCTION record_sync_change('id');
+CREATE TRIGGER trg_addons_sync AFTER INSERT OR UPDATE OR DELETE ON addons
+    FOR EACH ROW EXECUTE FUNCTION record_sync_change('id');
+CREATE TRIGGER trg_addon_groups_sync AFTER INSERT OR UPDATE OR DELETE ON addon_groups
+    FOR EACH ROW EXECUTE FUNCTION record_sync_change('id');
+CREATE TRIGGER trg_addon_group_items_sync AFTER INSERT OR UPDATE OR DELETE ON addon_group_items
+    FOR EACH ROW EXECUTE FUNCTION record_sync_change('addon_group_id', 'addon_id');
+CREATE TRIGGER trg_addon_group_assignments_sync AFTER INSERT OR UPDATE OR DELETE ON addon_group_assignments
+    FOR EACH ROW EXECUTE FUNCTION record_sync_change('id');
+CREATE TRIGGER trg_product_addon_prices_sync AFTER INSERT OR UPDATE OR DELETE ON product_addon_prices
+    FOR EACH ROW EXECUTE FUNCTION record_sync_change('product_id', 'addon_id');
+CREATE TRIGGER trg_bundle_items_sync AFTER INSERT OR UPDATE OR DELETE ON bundle_items
+    FOR EACH ROW EXECUTE FUNCTION record_sync_change('id');
+CREATE TRIGGER trg_bundle_slots_sync AFTER INSERT OR UPDATE OR DELETE ON bundle_slots
+    FOR EACH ROW EXECUTE FUNCTION record_sync_change('id');
+CREATE TRIGGER trg_bundle_slot_options_sync AFTER INSERT OR UPDATE OR DELETE ON bundle_slot_options
+    FOR EACH ROW EXECUTE FUNCTION record_sync_change('id');
+CREATE TRIGGER trg_price_lists_sync AFTER INSERT OR UPDATE OR DELETE ON price_lists
+    FOR EACH ROW EXECUTE FUNCTION record_sync_change('id');
+CREATE TRIGGER trg_price_list_items_sync AFTER INSERT OR UPDATE OR DELETE ON price_list_items
+    FOR EACH ROW EXECUTE FUNCTION record_sync_change('id');
+CREATE TRIGGER trg_tax_classes_sync AFTER INSERT OR UPDATE OR DELETE ON tax_classes
+    FOR EACH ROW EXECUTE FUNCTION record_sync_change('id');
+CREATE TRIGGER trg_tax_class_assignments_sync AFTER INSERT OR UPDATE OR DELETE ON tax_class_assignments
+    FOR EACH ROW EXECUTE FUNCTION record_sync_change('id');
+CREATE TRIGGER trg_availability_rules_sync AFTER INSERT OR UPDATE OR DELETE ON availability_rules
+    FOR EACH ROW EXECUTE FUNCTION record_sync_change('id');
+CREATE TRIGGER trg_sold_out_items_sync AFTER INSERT OR UPDATE OR DELETE ON sold_out_items
+    FOR EACH ROW EXECUTE FUNCTION record_sync_change('id');
+CREATE TRIGGER trg_floors_sync AFTER INSERT OR UPDATE OR DELETE ON floors
+    FOR EACH ROW EXECUTE FUNCTION record_sync_change('id');
+CREATE TRIGGER trg_tables_sync AFTER INSERT OR UPDATE OR DELETE ON tables
+    FOR EACH ROW EXECUTE FUNCTION record_sync_change('id');
+CREATE TRIGGER trg_rooms_sync AFTER INSERT OR UPDATE OR DELETE ON rooms
+    FOR EACH ROW EXECUTE FUNCTION record_sync_change('id');
+CREATE TRIGGER trg_store_prefs_sync AFTER INSERT OR UPDATE OR DELETE ON store_prefs
+    FOR EACH ROW EXECUTE FUNCTION record_sync_change('key');
+CREATE TRIGGER trg_store_shifts_sync AFTER INSERT OR UPDATE OR DELETE ON store_shifts
+    FOR EACH ROW EXECUTE FUNCTION record_sync_change('id');
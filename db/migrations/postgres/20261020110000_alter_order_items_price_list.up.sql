@@ -0,0 +1,4 @@
+-- price is resolved by the server when the order is pushed, price_list_id
+-- is the list the price came from (null for the catalog price), kept
+-- without foreign key as the list may be deleted later
+ALTER TABLE order_items ADD COLUMN price_list_id BIGINT;
@@ -0,0 +1,3 @@
+ALTER TABLE orders DROP COLUMN IF EXISTS tax_summary;
+ALTER TABLE orders DROP COLUMN IF EXISTS tax;
+ALTER TABLE orders DROP COLUMN IF EXISTS service_charge;
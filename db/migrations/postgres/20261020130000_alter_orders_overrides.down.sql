@@ -0,0 +1,10 @@
+DROP TABLE IF EXISTS order_refunds;
+ALTER TABLE order_items DROP COLUMN IF EXISTS price_approved_by;
+ALTER TABLE orders DROP COLUMN IF EXISTS voided_at;
+ALTER TABLE orders DROP COLUMN IF EXISTS void_approved_by;
+ALTER TABLE orders DROP COLUMN IF EXISTS voided_by;
+ALTER TABLE orders DROP COLUMN IF EXISTS void_reason;
+ALTER TABLE orders DROP COLUMN IF EXISTS discount_approved_by;
+ALTER TABLE orders DROP COLUMN IF EXISTS no_service;
+ALTER TABLE orders DROP COLUMN IF EXISTS tax_exempt;
+ALTER TABLE orders DROP COLUMN IF EXISTS discount;
@@ -0,0 +1,42 @@
+DROP VIEW IF EXISTS store_prefs_versions;
+DROP VIEW IF EXISTS addons_versions;
+DROP VIEW IF EXISTS product_variants_versions;
+DROP VIEW IF EXISTS products_versions;
+
+DROP TABLE IF EXISTS audit_actor;
+DROP TABLE IF EXISTS entity_versions;
+DROP TABLE IF EXISTS audit_logs;
+DROP TABLE IF EXISTS tax_class_assignments;
+DROP TABLE IF EXISTS tax_classes;
+DROP TABLE IF EXISTS sold_out_items;
+DROP TABLE IF EXISTS availability_rules;
+DROP TABLE IF EXISTS price_list_items;
+DROP TABLE IF EXISTS price_lists;
+DROP TABLE IF EXISTS bundle_slot_options;
+DROP TABLE IF EXISTS bundle_slots;
+DROP TABLE IF EXISTS bundle_items;
+DROP TABLE IF EXISTS product_addon_prices;
+DROP TABLE IF EXISTS addon_group_assignments;
+DROP TABLE IF EXISTS addon_group_items;
+DROP TABLE IF EXISTS addon_groups;
+DROP TABLE IF EXISTS product_variants;
+DROP TABLE IF EXISTS addons;
+DROP TABLE IF EXISTS products;
+DROP TABLE IF EXISTS subcategories;
+DROP TABLE IF EXISTS categories;
+DROP TABLE IF EXISTS units;
+DROP TABLE IF EXISTS cash_tenders;
+DROP TABLE IF EXISTS currency_rates;
+DROP TABLE IF EXISTS cash_drawer_opens;
+DROP TABLE IF EXISTS store_shifts;
+DROP TABLE IF EXISTS shifts;
+DROP TABLE IF EXISTS store_prefs;
+DROP TABLE IF EXISTS rooms;
+DROP TABLE IF EXISTS tables;
+DROP TABLE IF EXISTS floors;
+DROP TABLE IF EXISTS api_keys;
+DROP TABLE IF EXISTS terminals;
+DROP TABLE IF EXISTS overrides;
+DROP TABLE IF EXISTS role_permissions;
+DROP TABLE IF EXISTS users;
+DROP TABLE IF EXISTS roles;
@@ -0,0 +1,869 @@
+-- sqlite start from the schema of postgres at this version, the later
+-- migrations are written for both of them with the same version.
+-- ids are never reused (AUTOINCREMENT) like the postgres sequences,
+-- money is INTEGER minor unit, rates are kept as TEXT so no decimal is lost,
+-- arrays and json are TEXT read with the json functions
+CREATE TABLE IF NOT EXISTS roles (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name VARCHAR(255) NOT NULL,
+    description VARCHAR(255),
+    deleted_at BIGINT,
+    deleted_by BIGINT
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS roles_name_key ON roles (name) WHERE deleted_at IS NULL;
+
+CREATE TABLE IF NOT EXISTS users (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name VARCHAR(255),
+    username VARCHAR(255) NOT NULL,
+    email VARCHAR(255),
+    phone BIGINT,
+    password VARCHAR(255) NOT NULL,
+    created_at BIGINT NOT NULL DEFAULT (unixepoch()),
+    updated_at BIGINT,
+    role_id BIGINT,
+    pin VARCHAR(255),
+    badge_hash VARCHAR(64),
+    pin_failures INT NOT NULL DEFAULT 0,
+    pin_locked_until BIGINT,
+    deleted_at BIGINT,
+    deleted_by BIGINT,
+    CONSTRAINT fk_users_roles FOREIGN KEY (role_id) REFERENCES roles(id)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS users_username_key ON users (username) WHERE deleted_at IS NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS users_email_key ON users (email) WHERE deleted_at IS NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS users_phone_key ON users (phone) WHERE deleted_at IS NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_badge_hash ON users (badge_hash)
+    WHERE badge_hash IS NOT NULL;
+
+-- permission names are declared by the api routes (see model.Permissions)
+CREATE TABLE IF NOT EXISTS role_permissions (
+    role_id BIGINT NOT NULL,
+    permission VARCHAR(100) NOT NULL,
+    PRIMARY KEY (role_id, permission),
+    CONSTRAINT fk_roles_role_permissions FOREIGN KEY (role_id) REFERENCES roles(id) ON DELETE CASCADE
+);
+
+-- single-use approval of a sensitive action, token is stored as sha256 hex
+CREATE TABLE IF NOT EXISTS overrides (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    token_hash VARCHAR(64) NOT NULL UNIQUE,
+    action VARCHAR(100) NOT NULL,
+    reason VARCHAR(255),
+    approved_by BIGINT NOT NULL,
+    requested_by BIGINT,
+    expires_at BIGINT NOT NULL,
+    used_by BIGINT,
+    used_at BIGINT,
+    created_at BIGINT NOT NULL DEFAULT (unixepoch())
+);
+
+CREATE INDEX IF NOT EXISTS idx_overrides_approved_by ON overrides (approved_by);
+
+-- shared device, key is stored as sha256 hex
+CREATE TABLE IF NOT EXISTS terminals (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name VARCHAR(255) NOT NULL,
+    key_hash VARCHAR(64) NOT NULL UNIQUE,
+    idle_timeout INT NOT NULL DEFAULT 300, -- in seconds
+    disabled BOOLEAN NOT NULL DEFAULT false,
+    created_at BIGINT NOT NULL DEFAULT (unixepoch()),
+    updated_at BIGINT,
+    deleted_at BIGINT,
+    deleted_by BIGINT
+);
+
+-- key of machine integration, stored as sha256 hex, prefix is shown to tell keys apart
+CREATE TABLE IF NOT EXISTS api_keys (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name VARCHAR(255) NOT NULL,
+    prefix VARCHAR(20) NOT NULL,
+    key_hash VARCHAR(64) NOT NULL UNIQUE,
+    previous_key_hash VARCHAR(64) UNIQUE,
+    previous_valid_until BIGINT,
+    scopes TEXT NOT NULL DEFAULT '[]', -- json array of permission names
+    expires_at BIGINT,
+    last_used_at BIGINT,
+    revoked_at BIGINT,
+    created_by BIGINT,
+    created_at BIGINT NOT NULL DEFAULT (unixepoch()),
+    updated_at BIGINT
+);
+
+CREATE TABLE IF NOT EXISTS floors (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name VARCHAR(255),
+    created_at BIGINT NOT NULL DEFAULT (unixepoch()),
+    updated_at BIGINT,
+    deleted_at BIGINT,
+    deleted_by BIGINT
+);
+
+CREATE TABLE IF NOT EXISTS tables (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    floor_id BIGINT,
+    name VARCHAR(255),
+    x_pos FLOAT,
+    y_pos FLOAT,
+    w_size FLOAT,
+    h_size FLOAT,
+    capacity INT,
+    type VARCHAR(10) DEFAULT 'square' CHECK (type IN ('round', 'square')),
+    created_at BIGINT NOT NULL DEFAULT (unixepoch()),
+    updated_at BIGINT,
+    deleted_at BIGINT,
+    deleted_by BIGINT,
+    CONSTRAINT fk_floors_tables FOREIGN KEY (floor_id) REFERENCES floors(id)
+);
+
+CREATE TABLE IF NOT EXISTS rooms (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    floor_id BIGINT,
+    name VARCHAR(255),
+    x_pos FLOAT,
+    y_pos FLOAT,
+    w_size FLOAT,
+    h_size FLOAT,
+    capacity INT,
+    price BIGINT,
+    created_at BIGINT NOT NULL DEFAULT (unixepoch()),
+    updated_at BIGINT,
+    deleted_at BIGINT,
+    deleted_by BIGINT,
+    CONSTRAINT fk_floors_rooms FOREIGN KEY (floor_id) REFERENCES floors(id)
+);
+
+CREATE TABLE IF NOT EXISTS store_prefs (
+    key VARCHAR(255) UNIQUE NOT NULL,
+    value VARCHAR(255),
+    created_at BIGINT NOT NULL DEFAULT (unixepoch()),
+    updated_at BIGINT
+);
+
+CREATE TABLE IF NOT EXISTS shifts (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name VARCHAR(255) NOT NULL,
+    start_time BIGINT NOT NULL,
+    end_time BIGINT NOT NULL,
+    created_at BIGINT NOT NULL DEFAULT (unixepoch()),
+    updated_at BIGINT
+);
+
+CREATE TABLE IF NOT EXISTS store_shifts (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    shift_id BIGINT NOT NULL,
+    open_at BIGINT NOT NULL DEFAULT (unixepoch()),
+    open_by BIGINT NOT NULL,
+    open_cash BIGINT NOT NULL,
+    close_at BIGINT,
+    close_by BIGINT,
+    close_cash BIGINT,
+    created_at BIGINT NOT NULL DEFAULT (unixepoch()),
+    updated_at BIGINT,
+    CONSTRAINT fk_shift_store_shifts FOREIGN KEY (shift_id) REFERENCES shifts(id)
+);
+
+-- no-sale cash drawer open
+CREATE TABLE IF NOT EXISTS cash_drawer_opens (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    store_shift_id BIGINT NOT NULL,
+    reason VARCHAR(255) NOT NULL,
+    opened_by BIGINT,
+    approved_by BIGINT,
+    created_at BIGINT NOT NULL DEFAULT (unixepoch()),
+    CONSTRAINT fk_store_shifts_cash_drawer_opens
+        FOREIGN KEY (store_shift_id) REFERENCES store_shifts(id) ON DELETE CASCADE
+);
+
+-- rate: how many store currency one unit of the currency buy (e.g: 16200 IDR = 1 USD),
+-- never updated, a new row is added so past conversions keep their rate
+CREATE TABLE IF NOT EXISTS currency_rates (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    currency VARCHAR(3) NOT NULL,
+    rate TEXT NOT NULL CHECK (CAST(rate AS REAL) > 0),
+    created_by BIGINT,
+    created_at BIGINT NOT NULL DEFAULT (unixepoch())
+);
+
+CREATE INDEX IF NOT EXISTS idx_currency_rates_latest
+    ON currency_rates (currency, created_at DESC, id DESC);
+
+-- amount, base_amount, due and change are minor unit of currency and base_currency,
+-- currency_rate_id is NULL when tendered in the store currency
+CREATE TABLE IF NOT EXISTS cash_tenders (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    store_shift_id BIGINT,
+    currency_rate_id BIGINT,
+    currency VARCHAR(3) NOT NULL,
+    rate TEXT NOT NULL,
+    amount BIGINT NOT NULL CHECK (amount > 0),
+    base_currency VARCHAR(3) NOT NULL,
+    base_amount BIGINT NOT NULL,
+    due BIGINT NOT NULL CHECK (due >= 0),
+    change BIGINT NOT NULL CHECK (change >= 0),
+    created_by BIGINT,
+    created_at BIGINT NOT NULL DEFAULT (unixepoch()),
+    CONSTRAINT fk_store_shifts_cash_tenders
+        FOREIGN KEY (store_shift_id) REFERENCES store_shifts(id),
+    CONSTRAINT fk_currency_rates_cash_tenders
+        FOREIGN KEY (currency_rate_id) REFERENCES currency_rates(id)
+);
+
+CREATE TABLE IF NOT EXISTS units (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    magnitude VARCHAR(50),
+    name VARCHAR(50),
+    symbol VARCHAR(50),
+    deleted_at BIGINT,
+    deleted_by BIGINT
+);
+
+CREATE TABLE IF NOT EXISTS categories (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name VARCHAR(50),
+    deleted_at BIGINT,
+    deleted_by BIGINT
+);
+
+CREATE TABLE IF NOT EXISTS subcategories (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    category_id BIGINT,
+    name VARCHAR(50),
+    deleted_at BIGINT,
+    deleted_by BIGINT,
+    CONSTRAINT fk_categories_subcategories FOREIGN KEY (category_id) REFERENCES categories(id)
+);
+
+CREATE TABLE IF NOT EXISTS products (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    category_id BIGINT NOT NULL,
+    subcategory_id BIGINT NOT NULL,
+    sku VARCHAR(255) NOT NULL,
+    image VARCHAR(255),
+    gallery TEXT NOT NULL DEFAULT '[]' CHECK (json_valid(gallery)),
+    name VARCHAR(255) NOT NULL,
+    description VARCHAR(255),
+    price BIGINT NOT NULL,
+    created_at BIGINT NOT NULL DEFAULT (unixepoch()),
+    updated_at BIGINT,
+    deleted_at BIGINT,
+    deleted_by BIGINT,
+    CONSTRAINT fk_products_categories FOREIGN KEY (category_id) REFERENCES categories(id),
+    CONSTRAINT fk_products_subcategories FOREIGN KEY (subcategory_id) REFERENCES subcategories(id)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS products_sku_key ON products (sku) WHERE deleted_at IS NULL;
+
+CREATE TABLE IF NOT EXISTS addons (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name VARCHAR(255),
+    description VARCHAR(255),
+    price BIGINT,
+    created_at BIGINT NOT NULL DEFAULT (unixepoch()),
+    updated_at BIGINT,
+    deleted_at BIGINT,
+    deleted_by BIGINT
+);
+
+CREATE TABLE IF NOT EXISTS product_variants (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    product_id BIGINT NOT NULL,
+    unit_id BIGINT NOT NULL,
+    unit_size FLOAT NOT NULL,
+    type VARCHAR(10) DEFAULT 'none' CHECK (type IN ('none', 'size')),
+    name VARCHAR(255) NOT NULL,
+    description VARCHAR(255),
+    price BIGINT,
+    created_at BIGINT NOT NULL DEFAULT (unixepoch()),
+    updated_at BIGINT,
+    deleted_at BIGINT,
+    deleted_by BIGINT,
+    CONSTRAINT fk_products_product_variants FOREIGN KEY (product_id) REFERENCES products(id),
+    CONSTRAINT fk_units_product_variants FOREIGN KEY (unit_id) REFERENCES units(id)
+);
+
+-- max_select 0 means unlimited
+CREATE TABLE IF NOT EXISTS addon_groups (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name VARCHAR(255) NOT NULL,
+    description VARCHAR(255),
+    min_select INT NOT NULL DEFAULT 0,
+    max_select INT NOT NULL DEFAULT 0,
+    created_at BIGINT NOT NULL DEFAULT (unixepoch()),
+    updated_at BIGINT,
+    CONSTRAINT chk_addon_groups_selection CHECK (
+        min_select >= 0 AND max_select >= 0 AND
+        (max_select = 0 OR min_select <= max_select)
+    )
+);
+
+CREATE TABLE IF NOT EXISTS addon_group_items (
+    addon_group_id BIGINT NOT NULL,
+    addon_id BIGINT NOT NULL,
+    position INT NOT NULL DEFAULT 0,
+    PRIMARY KEY (addon_group_id, addon_id),
+    CONSTRAINT fk_addon_groups_addon_group_items
+        FOREIGN KEY (addon_group_id) REFERENCES addon_groups(id) ON DELETE CASCADE,
+    CONSTRAINT fk_addons_addon_group_items
+        FOREIGN KEY (addon_id) REFERENCES addons(id) ON DELETE CASCADE
+);
+
+-- a group is assigned either to a product or to a whole category
+CREATE TABLE IF NOT EXISTS addon_group_assignments (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    addon_group_id BIGINT NOT NULL,
+    product_id BIGINT,
+    category_id BIGINT,
+    created_at BIGINT NOT NULL DEFAULT (unixepoch()),
+    CONSTRAINT chk_addon_group_assignments_target CHECK (
+        (product_id IS NULL) <> (category_id IS NULL)
+    ),
+    CONSTRAINT fk_addon_groups_addon_group_assignments
+        FOREIGN KEY (addon_group_id) REFERENCES addon_groups(id) ON DELETE CASCADE,
+    CONSTRAINT fk_products_addon_group_assignments
+        FOREIGN KEY (product_id) REFERENCES products(id) ON DELETE CASCADE,
+    CONSTRAINT fk_categories_addon_group_assignments
+        FOREIGN KEY (category_id) REFERENCES categories(id) ON DELETE CASCADE
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_addon_group_assignments_product
+    ON addon_group_assignments (addon_group_id, product_id) WHERE product_id IS NOT NULL;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_addon_group_assignments_category
+    ON addon_group_assignments (addon_group_id, category_id) WHERE category_id IS NOT NULL;
+
+CREATE TABLE IF NOT EXISTS product_addon_prices (
+    product_id BIGINT NOT NULL,
+    addon_id BIGINT NOT NULL,
+    price BIGINT NOT NULL,
+    created_at BIGINT NOT NULL DEFAULT (unixepoch()),
+    updated_at BIGINT,
+    PRIMARY KEY (product_id, addon_id),
+    CONSTRAINT fk_products_product_addon_prices
+        FOREIGN KEY (product_id) REFERENCES products(id) ON DELETE CASCADE,
+    CONSTRAINT fk_addons_product_addon_prices
+        FOREIGN KEY (addon_id) REFERENCES addons(id) ON DELETE CASCADE
+);
+
+-- bundle_id is the product sold as the set menu
+CREATE TABLE IF NOT EXISTS bundle_items (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    bundle_id BIGINT NOT NULL,
+    product_id BIGINT NOT NULL,
+    variant_id BIGINT,
+    qty INT NOT NULL DEFAULT 1 CHECK (qty > 0),
+    position INT NOT NULL DEFAULT 0,
+    CONSTRAINT chk_bundle_items_self CHECK (bundle_id <> product_id),
+    CONSTRAINT fk_bundles_bundle_items
+        FOREIGN KEY (bundle_id) REFERENCES products(id) ON DELETE CASCADE,
+    CONSTRAINT fk_products_bundle_items
+        FOREIGN KEY (product_id) REFERENCES products(id),
+    CONSTRAINT fk_product_variants_bundle_items
+        FOREIGN KEY (variant_id) REFERENCES product_variants(id)
+);
+
+CREATE TABLE IF NOT EXISTS bundle_slots (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    bundle_id BIGINT NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    subcategory_id BIGINT,
+    qty INT NOT NULL DEFAULT 1 CHECK (qty > 0),
+    position INT NOT NULL DEFAULT 0,
+    CONSTRAINT fk_bundles_bundle_slots
+        FOREIGN KEY (bundle_id) REFERENCES products(id) ON DELETE CASCADE,
+    CONSTRAINT fk_subcategories_bundle_slots
+        FOREIGN KEY (subcategory_id) REFERENCES subcategories(id)
+);
+
+CREATE TABLE IF NOT EXISTS bundle_slot_options (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    slot_id BIGINT NOT NULL,
+    product_id BIGINT NOT NULL,
+    variant_id BIGINT,
+    upcharge BIGINT NOT NULL DEFAULT 0 CHECK (upcharge >= 0),
+    position INT NOT NULL DEFAULT 0,
+    CONSTRAINT fk_bundle_slots_bundle_slot_options
+        FOREIGN KEY (slot_id) REFERENCES bundle_slots(id) ON DELETE CASCADE,
+    CONSTRAINT fk_products_bundle_slot_options
+        FOREIGN KEY (product_id) REFERENCES products(id),
+    CONSTRAINT fk_product_variants_bundle_slot_options
+        FOREIGN KEY (variant_id) REFERENCES product_variants(id)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_bundle_slot_options_product
+    ON bundle_slot_options (slot_id, product_id, COALESCE(variant_id, 0));
+
+-- channel: dine_in, takeaway, delivery (NULL = every channel)
+-- days: bitmask of weekday, bit 0 = sunday (0 = every day)
+-- start_time/end_time: HH:MM in store timezone, end before start pass midnight
+CREATE TABLE IF NOT EXISTS price_lists (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name VARCHAR(255) NOT NULL,
+    channel VARCHAR(20),
+    customer_tier VARCHAR(50),
+    days INT NOT NULL DEFAULT 0,
+    start_time VARCHAR(5),
+    end_time VARCHAR(5),
+    effective_from BIGINT,
+    effective_to BIGINT,
+    priority INT NOT NULL DEFAULT 0,
+    disabled BOOLEAN NOT NULL DEFAULT false,
+    created_at BIGINT NOT NULL DEFAULT (unixepoch()),
+    updated_at BIGINT,
+    CONSTRAINT chk_price_lists_channel CHECK (channel IN ('dine_in', 'takeaway', 'delivery')),
+    CONSTRAINT chk_price_lists_window CHECK ((start_time IS NULL) = (end_time IS NULL)),
+    CONSTRAINT chk_price_lists_effective CHECK (
+        effective_from IS NULL OR effective_to IS NULL OR effective_from < effective_to
+    )
+);
+
+-- item_type: product, variant, addon
+CREATE TABLE IF NOT EXISTS price_list_items (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    price_list_id BIGINT NOT NULL,
+    item_type VARCHAR(10) NOT NULL,
+    item_id BIGINT NOT NULL,
+    price BIGINT NOT NULL CHECK (price >= 0),
+    CONSTRAINT chk_price_list_items_type CHECK (item_type IN ('product', 'variant', 'addon')),
+    CONSTRAINT fk_price_lists_price_list_items
+        FOREIGN KEY (price_list_id) REFERENCES price_lists(id) ON DELETE CASCADE
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_price_list_items_item
+    ON price_list_items (price_list_id, item_type, item_id);
+
+CREATE INDEX IF NOT EXISTS idx_price_list_items_lookup
+    ON price_list_items (item_type, item_id);
+
+-- a rule belong either to a product or to a whole category
+-- days: bitmask of weekday, bit 0 = sunday (0 = every day)
+-- start_time/end_time: HH:MM in store timezone, end before start pass midnight
+CREATE TABLE IF NOT EXISTS availability_rules (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    product_id BIGINT,
+    category_id BIGINT,
+    days INT NOT NULL DEFAULT 0,
+    start_time VARCHAR(5),
+    end_time VARCHAR(5),
+    created_at BIGINT NOT NULL DEFAULT (unixepoch()),
+    CONSTRAINT chk_availability_rules_target CHECK (
+        (product_id IS NULL) <> (category_id IS NULL)
+    ),
+    CONSTRAINT chk_availability_rules_window CHECK ((start_time IS NULL) = (end_time IS NULL)),
+    CONSTRAINT fk_products_availability_rules
+        FOREIGN KEY (product_id) REFERENCES products(id) ON DELETE CASCADE,
+    CONSTRAINT fk_categories_availability_rules
+        FOREIGN KEY (category_id) REFERENCES categories(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_availability_rules_product
+    ON availability_rules (product_id) WHERE product_id IS NOT NULL;
+
+CREATE INDEX IF NOT EXISTS idx_availability_rules_category
+    ON availability_rules (category_id) WHERE category_id IS NOT NULL;
+
+-- 86'd product (variant_id NULL) or variant, in effect while the
+-- store shift is still open or until cleared when store_shift_id is NULL
+CREATE TABLE IF NOT EXISTS sold_out_items (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    product_id BIGINT NOT NULL,
+    variant_id BIGINT,
+    store_shift_id BIGINT,
+    created_by BIGINT,
+    created_at BIGINT NOT NULL DEFAULT (unixepoch()),
+    CONSTRAINT fk_products_sold_out_items
+        FOREIGN KEY (product_id) REFERENCES products(id) ON DELETE CASCADE,
+    CONSTRAINT fk_product_variants_sold_out_items
+        FOREIGN KEY (variant_id) REFERENCES product_variants(id) ON DELETE CASCADE,
+    CONSTRAINT fk_store_shifts_sold_out_items
+        FOREIGN KEY (store_shift_id) REFERENCES store_shifts(id) ON DELETE CASCADE
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_sold_out_items_item
+    ON sold_out_items (product_id, (COALESCE(variant_id, 0)));
+
+-- rate in percentage, inclusive price already contain the tax,
+-- after_service also tax the service charge, one class is the default
+CREATE TABLE IF NOT EXISTS tax_classes (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name VARCHAR(255) NOT NULL,
+    rate TEXT NOT NULL DEFAULT '0',
+    inclusive BOOLEAN NOT NULL DEFAULT false,
+    after_service BOOLEAN NOT NULL DEFAULT false,
+    is_default BOOLEAN NOT NULL DEFAULT false,
+    created_at BIGINT NOT NULL DEFAULT (unixepoch()),
+    updated_at BIGINT,
+    CONSTRAINT chk_tax_classes_rate CHECK (CAST(rate AS REAL) >= 0 AND CAST(rate AS REAL) <= 100)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_tax_classes_default
+    ON tax_classes (is_default) WHERE is_default = true;
+
+-- a product or a category belong to at most one class
+CREATE TABLE IF NOT EXISTS tax_class_assignments (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    tax_class_id BIGINT NOT NULL,
+    product_id BIGINT,
+    category_id BIGINT,
+    CONSTRAINT chk_tax_class_assignments_target CHECK (
+        (product_id IS NULL) <> (category_id IS NULL)
+    ),
+    CONSTRAINT fk_tax_classes_tax_class_assignments
+        FOREIGN KEY (tax_class_id) REFERENCES tax_classes(id) ON DELETE CASCADE,
+    CONSTRAINT fk_products_tax_class_assignments
+        FOREIGN KEY (product_id) REFERENCES products(id) ON DELETE CASCADE,
+    CONSTRAINT fk_categories_tax_class_assignments
+        FOREIGN KEY (category_id) REFERENCES categories(id) ON DELETE CASCADE
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_tax_class_assignments_product
+    ON tax_class_assignments (product_id) WHERE product_id IS NOT NULL;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_tax_class_assignments_category
+    ON tax_class_assignments (category_id) WHERE category_id IS NOT NULL;
+
+-- append-only log of every mutating request, before_data is the row before
+-- the change and after_data the respond data (secrets are redacted)
+CREATE TABLE IF NOT EXISTS audit_logs (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    actor_id BIGINT,
+    api_key_id BIGINT,
+    terminal_id BIGINT,
+    session_id VARCHAR(64),
+    action VARCHAR(20) NOT NULL,
+    method VARCHAR(10) NOT NULL,
+    route VARCHAR(255) NOT NULL,
+    entity_type VARCHAR(100) NOT NULL,
+    entity_id VARCHAR(100),
+    status INT NOT NULL,
+    before_data TEXT,
+    after_data TEXT,
+    ip VARCHAR(45),
+    created_at BIGINT NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_audit_logs_created_at ON audit_logs (created_at);
+CREATE INDEX IF NOT EXISTS idx_audit_logs_entity ON audit_logs (entity_type, entity_id);
+CREATE INDEX IF NOT EXISTS idx_audit_logs_actor_id ON audit_logs (actor_id);
+
+-- every change of versioned rows, data is the whole row (null when deleted),
+-- a version is in force from valid_from until valid_to (null while current)
+CREATE TABLE IF NOT EXISTS entity_versions (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    entity_type VARCHAR(50) NOT NULL,
+    entity_id VARCHAR(100) NOT NULL,
+    version INT NOT NULL,
+    data TEXT,
+    changed_by BIGINT,
+    valid_from BIGINT NOT NULL,
+    valid_to BIGINT,
+    UNIQUE (entity_type, entity_id, version)
+);
+
+CREATE INDEX IF NOT EXISTS idx_entity_versions_valid
+    ON entity_versions (entity_type, valid_from, valid_to);
+
+-- the actor of the running write, read by the triggers below, it is set and
+-- cleared by the repository (audit.Versioned) inside the write transaction
+CREATE TABLE IF NOT EXISTS audit_actor (
+    id INTEGER PRIMARY KEY CHECK (id = 1),
+    actor_id BIGINT
+);
+
+INSERT INTO audit_actor (id, actor_id) VALUES (1, NULL);
+
+-- default data, money in minor unit of IDR
+INSERT INTO roles (name, description)
+VALUES
+    ('admin', 'admin level can access all of the features/menus'),
+    ('cashier', 'cashier level can access room, order & payment menu'),
+    ('waiter', 'waiter level can access room & order menu');
+
+-- password is secret
+INSERT INTO users(role_id, name, username, email, phone, password)
+VALUES
+    (1, 'A. A. Sumitro', 'aasumitro', 'hello@aasumitro.id', 82271115593, '2ad1a22d5b3c9396d16243d2fe7f067976363715e322203a456278bb80b0b4a4.7ab4dcccfcd9d36efc68f1626d2fb80804a6508f9c3a7b44f430ba082b6870d2');
+
+WITH p(permission) AS (VALUES
+    ('account.user.read'), ('account.user.write'), ('account.role.write'),
+    ('account.terminal.write'), ('account.api_key.write'), ('audit.read'),
+    ('catalog.read'), ('catalog.product.write'), ('catalog.price.write'),
+    ('catalog.availability.write'), ('store.read'), ('store.write'),
+    ('order.read'), ('order.tender'), ('order.void'), ('order.refund'),
+    ('order.price_override'), ('order.discount'), ('cash_drawer.open'),
+    ('report.export'), ('trash.restore')
+)
+INSERT INTO role_permissions (role_id, permission)
+SELECT roles.id, p.permission FROM roles CROSS JOIN p WHERE roles.name = 'admin';
+
+WITH p(permission) AS (VALUES
+    ('catalog.read'), ('catalog.availability.write'), ('store.read'),
+    ('order.read'), ('order.tender')
+)
+INSERT INTO role_permissions (role_id, permission)
+SELECT roles.id, p.permission FROM roles CROSS JOIN p WHERE roles.name = 'cashier';
+
+WITH p(permission) AS (VALUES
+    ('catalog.read'), ('store.read'), ('order.read')
+)
+INSERT INTO role_permissions (role_id, permission)
+SELECT roles.id, p.permission FROM roles CROSS JOIN p WHERE roles.name = 'waiter';
+
+INSERT INTO floors (name)
+VALUES ('1st'), ('2nd');
+
+INSERT INTO tables (floor_id, name, x_pos, y_pos, w_size, h_size, capacity)
+VALUES (1, 'A1', 0, 0, 4 , 4, 4);
+
+INSERT INTO rooms (floor_id, name, x_pos, y_pos, w_size, h_size, capacity, price)
+VALUES (2, 'R1', 0, 0, 4 , 4, 4, 10000);
+
+INSERT INTO store_prefs (key, value)
+VALUES
+    ('name', 'Lorem Store'),
+    ('address', 'Jalan Suka Maju'),
+    ('email', 'lorem@store.id'),
+    ('phone', '+62872222'),
+    ('logo', '/lorem.png'),
+    ('tax_rate', '10'), -- in percentage
+    ('tax_category', 'standard'),
+    ('service_rate', '5'), -- in percentage
+    ('service_category', 'standard'),
+    ('pos_type', 'restaurant'), -- restaurant, bar, coffee, store, karaoke
+    ('feature_floor', '1'),  -- true or false
+    ('feature_room', '0'),  -- true or false
+    ('feature_table', '1'),  -- true or false
+    ('fe_theme', 'light'),  -- dark or light
+    ('fe_lang', 'en_US'),  -- en_US or id_ID
+    ('fe_locale', 'Asia/Makassar'), -- Asia/Jayapura, Asia/Makassar, Asia/Jakarta
+    ('currency', 'IDR'), -- IDR/USD
+    ('currency_rate', '16200'); -- TO USD
+
+INSERT INTO shifts (id, name, start_time, end_time)
+VALUES (1, 'shift 1', 0800, 1500),
+       (2, 'shift 2', 1501, 2200);
+
+INSERT INTO currency_rates (currency, rate) VALUES ('USD', '16200');
+
+INSERT INTO tax_classes (name, rate, is_default) VALUES ('Standard', '10', true);
+INSERT INTO tax_classes (name, rate) VALUES ('Exempt', '0');
+
+INSERT INTO categories (name)
+VALUES ('foods'), ('beverages');
+
+INSERT INTO subcategories (category_id, name)
+VALUES (1, 'meat'), (1, 'seafood'), (2, 'coffee'), (2, 'juice');
+
+INSERT INTO units (magnitude, name, symbol)
+VALUES ('mass', 'gram', 'g'),
+       ('mass', 'milligram', 'mg'),
+       ('mass', 'kilogram', 'kg'),
+       ('mass', 'milliliter', 'ml'),
+       ('mass', 'liter', 'l');
+
+INSERT INTO addons (name, description, price)
+VALUES ('oat milk', 'replace', 100),
+       ('raw milk', 'replace', 100),
+       ('cheese', 'extra cheese', 100),
+       ('chocolate', 'extra chocolate', 100);
+
+INSERT INTO products (category_id, subcategory_id, sku, name, description, price)
+VALUES (2, 4, 'JMGO100', 'mango juice', 'this sweet, tangy, and fruity tropical juice can be made using a blender, handheld blender, or a food processor in under 5 minutes.', 2500),
+       (1, 2, 'WA5S100', 'wagyu a5 steak', 'The highest yield grade and meat quality grade for Wagyu beef is A5, where A represents the yield grade, and 5 represents the meat quality grade. A5 Wagyu beef denotes meat with ideal firmness and texture, coloring, yield, and beef marbling score.', 10000);
+
+INSERT INTO product_variants (product_id, type, name, description, unit_id, unit_size, price)
+VALUES (1, 'size', 's', 'small', 4, 250, 0),
+       (1, 'size', 'm', 'medium', 4, 480, 200),
+       (1, 'size', 'l', 'large', 4, 650, 300),
+       (1, 'size', 'xl', 'extra large', 5, 1.5, 100),
+       (2, 'size', 'half', 'half portion', 1, 250, 0),
+       (2, 'size', 'normal', 'normal portion', 1, 500, 10000);
+
+-- the row of a versioned table as recorded in entity_versions (to_jsonb of postgres)
+CREATE VIEW products_versions AS
+SELECT id, json_object(
+    'id', id, 'category_id', category_id, 'subcategory_id', subcategory_id, 'sku', sku,
+    'image', image, 'gallery', json(gallery), 'name', name, 'description', description,
+    'price', price, 'created_at', created_at, 'updated_at', updated_at,
+    'deleted_at', deleted_at, 'deleted_by', deleted_by
+) AS data FROM products;
+
+CREATE VIEW product_variants_versions AS
+SELECT id, json_object(
+    'id', id, 'product_id', product_id, 'unit_id', unit_id, 'unit_size', unit_size,
+    'type', type, 'name', name, 'description', description, 'price', price,
+    'created_at', created_at, 'updated_at', updated_at, 'deleted_at', deleted_at,
+    'deleted_by', deleted_by
+) AS data FROM product_variants;
+
+CREATE VIEW addons_versions AS
+SELECT id, json_object(
+    'id', id, 'name', name, 'description', description, 'price', price,
+    'created_at', created_at, 'updated_at', updated_at, 'deleted_at', deleted_at,
+    'deleted_by', deleted_by
+) AS data FROM addons;
+
+CREATE VIEW store_prefs_versions AS
+SELECT key, json_object(
+    'key', key, 'value', value, 'created_at', created_at, 'updated_at', updated_at
+) AS data FROM store_prefs;
+
+-- current rows are the first version, in force since ever
+INSERT INTO entity_versions (entity_type, entity_id, version, data, valid_from)
+SELECT 'products', CAST(id AS TEXT), 1, data, 0 FROM products_versions
+UNION ALL
+SELECT 'product_variants', CAST(id AS TEXT), 1, data, 0 FROM product_variants_versions
+UNION ALL
+SELECT 'addons', CAST(id AS TEXT), 1, data, 0 FROM addons_versions
+UNION ALL
+SELECT 'store_prefs', CAST(key AS TEXT), 1, data, 0 FROM store_prefs_versions;
+
+CREATE TRIGGER trg_products_versions_insert AFTER INSERT ON products
+BEGIN
+    UPDATE entity_versions SET valid_to = unixepoch()
+    WHERE entity_type = 'products' AND entity_id = CAST(NEW.id AS TEXT) AND valid_to IS NULL;
+    INSERT INTO entity_versions (entity_type, entity_id, version, data, changed_by, valid_from)
+    SELECT 'products', CAST(NEW.id AS TEXT), COALESCE(MAX(version), 0) + 1,
+        (SELECT data FROM products_versions WHERE id = NEW.id),
+        (SELECT actor_id FROM audit_actor WHERE id = 1), unixepoch()
+    FROM entity_versions WHERE entity_type = 'products' AND entity_id = CAST(NEW.id AS TEXT);
+END;
+
+CREATE TRIGGER trg_products_versions_update AFTER UPDATE ON products
+WHEN (SELECT data FROM products_versions WHERE id = NEW.id) IS NOT (
+    SELECT data FROM entity_versions
+    WHERE entity_type = 'products' AND entity_id = CAST(NEW.id AS TEXT) AND valid_to IS NULL
+)
+BEGIN
+    UPDATE entity_versions SET valid_to = unixepoch()
+    WHERE entity_type = 'products' AND entity_id = CAST(NEW.id AS TEXT) AND valid_to IS NULL;
+    INSERT INTO entity_versions (entity_type, entity_id, version, data, changed_by, valid_from)
+    SELECT 'products', CAST(NEW.id AS TEXT), COALESCE(MAX(version), 0) + 1,
+        (SELECT data FROM products_versions WHERE id = NEW.id),
+        (SELECT actor_id FROM audit_actor WHERE id = 1), unixepoch()
+    FROM entity_versions WHERE entity_type = 'products' AND entity_id = CAST(NEW.id AS TEXT);
+END;
+
+CREATE TRIGGER trg_products_versions_delete AFTER DELETE ON products
+BEGIN
+    UPDATE entity_versions SET valid_to = unixepoch()
+    WHERE entity_type = 'products' AND entity_id = CAST(OLD.id AS TEXT) AND valid_to IS NULL;
+    INSERT INTO entity_versions (entity_type, entity_id, version, data, changed_by, valid_from)
+    SELECT 'products', CAST(OLD.id AS TEXT), COALESCE(MAX(version), 0) + 1,
+        NULL,
+        (SELECT actor_id FROM audit_actor WHERE id = 1), unixepoch()
+    FROM entity_versions WHERE entity_type = 'products' AND entity_id = CAST(OLD.id AS TEXT);
+END;
+
+CREATE TRIGGER trg_product_variants_versions_insert AFTER INSERT ON product_variants
+BEGIN
+    UPDATE entity_versions SET valid_to = unixepoch()
+    WHERE entity_type = 'product_variants' AND entity_id = CAST(NEW.id AS TEXT) AND valid_to IS NULL;
+    INSERT INTO entity_versions (entity_type, entity_id, version, data, changed_by, valid_from)
+    SELECT 'product_variants', CAST(NEW.id AS TEXT), COALESCE(MAX(version), 0) + 1,
+        (SELECT data FROM product_variants_versions WHERE id = NEW.id),
+        (SELECT actor_id FROM audit_actor WHERE id = 1), unixepoch()
+    FROM entity_versions WHERE entity_type = 'product_variants' AND entity_id = CAST(NEW.id AS TEXT);
+END;
+
+CREATE TRIGGER trg_product_variants_versions_update AFTER UPDATE ON product_variants
+WHEN (SELECT data FROM product_variants_versions WHERE id = NEW.id) IS NOT (
+    SELECT data FROM entity_versions
+    WHERE entity_type = 'product_variants' AND entity_id = CAST(NEW.id AS TEXT) AND valid_to IS NULL
+)
+BEGIN
+    UPDATE entity_versions SET valid_to = unixepoch()
+    WHERE entity_type = 'product_variants' AND entity_id = CAST(NEW.id AS TEXT) AND valid_to IS NULL;
+    INSERT INTO entity_versions (entity_type, entity_id, version, data, changed_by, valid_from)
+    SELECT 'product_variants', CAST(NEW.id AS TEXT), COALESCE(MAX(version), 0) + 1,
+        (SELECT data FROM product_variants_versions WHERE id = NEW.id),
+        (SELECT actor_id FROM audit_actor WHERE id = 1), unixepoch()
+    FROM entity_versions WHERE entity_type = 'product_variants' AND entity_id = CAST(NEW.id AS TEXT);
+END;
+
+CREATE TRIGGER trg_product_variants_versions_delete AFTER DELETE ON product_variants
+BEGIN
+    UPDATE entity_versions SET valid_to = unixepoch()
+    WHERE entity_type = 'product_variants' AND entity_id = CAST(OLD.id AS TEXT) AND valid_to IS NULL;
+    INSERT INTO entity_versions (entity_type, entity_id, version, data, changed_by, valid_from)
+    SELECT 'product_variants', CAST(OLD.id AS TEXT), COALESCE(MAX(version), 0) + 1,
+        NULL,
+        (SELECT actor_id FROM audit_actor WHERE id = 1), unixepoch()
+    FROM entity_versions WHERE entity_type = 'product_variants' AND entity_id = CAST(OLD.id AS TEXT);
+END;
+
+CREATE TRIGGER trg_addons_versions_insert AFTER INSERT ON addons
+BEGIN
+    UPDATE entity_versions SET valid_to = unixepoch()
+    WHERE entity_type = 'addons' AND entity_id = CAST(NEW.id AS TEXT) AND valid_to IS NULL;
+    INSERT INTO entity_versions (entity_type, entity_id, version, data, changed_by, valid_from)
+    SELECT 'addons', CAST(NEW.id AS TEXT), COALESCE(MAX(version), 0) + 1,
+        (SELECT data FROM addons_versions WHERE id = NEW.id),
+        (SELECT actor_id FROM audit_actor WHERE id = 1), unixepoch()
+    FROM entity_versions WHERE entity_type = 'addons' AND entity_id = CAST(NEW.id AS TEXT);
+END;
+
+CREATE TRIGGER trg_addons_versions_update AFTER UPDATE ON addons
+WHEN (SELECT data FROM addons_versions WHERE id = NEW.id) IS NOT (
+    SELECT data FROM entity_versions
+    WHERE entity_type = 'addons' AND entity_id = CAST(NEW.id AS TEXT) AND valid_to IS NULL
+)
+BEGIN
+    UPDATE entity_versions SET valid_to = unixepoch()
+    WHERE entity_type = 'addons' AND entity_id = CAST(NEW.id AS TEXT) AND valid_to IS NULL;
+    INSERT INTO entity_versions (entity_type, entity_id, version, data, changed_by, valid_from)
+    SELECT 'addons', CAST(NEW.id AS TEXT), COALESCE(MAX(version), 0) + 1,
+        (SELECT data FROM addons_versions WHERE id = NEW.id),
+        (SELECT actor_id FROM audit_actor WHERE id = 1), unixepoch()
+    FROM entity_versions WHERE entity_type = 'addons' AND entity_id = CAST(NEW.id AS TEXT);
+END;
+
+CREATE TRIGGER trg_addons_versions_delete AFTER DELETE ON addons
+BEGIN
+    UPDATE entity_versions SET valid_to = unixepoch()
+    WHERE entity_type = 'addons' AND entity_id = CAST(OLD.id AS TEXT) AND valid_to IS NULL;
+    INSERT INTO entity_versions (entity_type, entity_id, version, data, changed_by, valid_from)
+    SELECT 'addons', CAST(OLD.id AS TEXT), COALESCE(MAX(version), 0) + 1,
+        NULL,
+        (SELECT actor_id FROM audit_actor WHERE id = 1), unixepoch()
+    FROM entity_versions WHERE entity_type = 'addons' AND entity_id = CAST(OLD.id AS TEXT);
+END;
+
+CREATE TRIGGER trg_store_prefs_versions_insert AFTER INSERT ON store_prefs
+BEGIN
+    UPDATE entity_versions SET valid_to = unixepoch()
+    WHERE entity_type = 'store_prefs' AND entity_id = CAST(NEW.key AS TEXT) AND valid_to IS NULL;
+    INSERT INTO entity_versions (entity_type, entity_id, version, data, changed_by, valid_from)
+    SELECT 'store_prefs', CAST(NEW.key AS TEXT), COALESCE(MAX(version), 0) + 1,
+        (SELECT data FROM store_prefs_versions WHERE key = NEW.key),
+        (SELECT actor_id FROM audit_actor WHERE id = 1), unixepoch()
+    FROM entity_versions WHERE entity_type = 'store_prefs' AND entity_id = CAST(NEW.key AS TEXT);
+END;
+
+CREATE TRIGGER trg_store_prefs_versions_update AFTER UPDATE ON store_prefs
+WHEN (SELECT data FROM store_prefs_versions WHERE key = NEW.key) IS NOT (
+    SELECT data FROM entity_versions
+    WHERE entity_type = 'store_prefs' AND entity_id = CAST(NEW.key AS TEXT) AND valid_to IS NULL
+)
+BEGIN
+    UPDATE entity_versions SET valid_to = unixepoch()
+    WHERE entity_type = 'store_prefs' AND entity_id = CAST(NEW.key AS TEXT) AND valid_to IS NULL;
+    INSERT INTO entity_versions (entity_type, entity_id, version, data, changed_by, valid_from)
+    SELECT 'store_prefs', CAST(NEW.key AS TEXT), COALESCE(MAX(version), 0) + 1,
+        (SELECT data FROM store_prefs_versions WHERE key = NEW.key),
+        (SELECT actor_id FROM audit_actor WHERE id = 1), unixepoch()
+    FROM entity_versions WHERE entity_type = 'store_prefs' AND entity_id = CAST(NEW.key AS TEXT);
+END;
+
+CREATE TRIGGER trg_store_prefs_versions_delete AFTER DELETE ON store_prefs
+BEGIN
+    UPDATE entity_versions SET valid_to = unixepoch()
+    WHERE entity_type = 'store_prefs' AND entity_id = CAST(OLD.key AS TEXT) AND valid_to IS NULL;
+    INSERT INTO entity_versions (entity_type, entity_id, version, data, changed_by, valid_from)
+    SELECT 'store_prefs', CAST(OLD.key AS TEXT), COALESCE(MAX(version), 0) + 1,
+        NULL,
+        (SELECT actor_id FROM audit_actor WHERE id = 1), unixepoch()
+    FROM entity_versions WHERE entity_type = 'store_prefs' AND entity_id = CAST(OLD.key AS TEXT);
+END;
@@ -0,0 +1,99 @@
+DROP TRIGGER IF EXISTS trg_store_shifts_sync_delete;
+DROP TRIGGER IF EXISTS trg_store_shifts_sync_update;
+DROP TRIGGER IF EXISTS trg_store_shifts_sync_insert;
+DROP TRIGGER IF EXISTS trg_store_prefs_sync_delete;
+DROP TRIGGER IF EXISTS trg_store_prefs_sync_update;
+DROP TRIGGER IF EXISTS trg_store_prefs_sync_insert;
+DROP TRIGGER IF EXISTS trg_rooms_sync_delete;
+DROP TRIGGER IF EXISTS trg_rooms_sync_update;
+DROP TRIGGER IF EXISTS trg_rooms_sync_insert;
+DROP TRIGGER IF EXISTS trg_tables_sync_delete;
+DROP TRIGGER IF EXISTS trg_tables_sync_update;
+DROP TRIGGER IF EXISTS trg_tables_sync_insert;
+DROP TRIGGER IF EXISTS trg_floors_sync_delete;
+DROP TRIGGER IF EXISTS trg_floors_sync_update;
+DROP TRIGGER IF EXISTS trg_floors_sync_insert;
+DROP TRIGGER IF EXISTS trg_sold_out_items_sync_delete;
+DROP TRIGGER IF EXISTS trg_sold_out_items_sync_update;
+DROP TRIGGER IF EXISTS trg_sold_out_items_sync_insert;
+DROP TRIGGER IF EXISTS trg_availability_rules_sync_delete;
+DROP TRIGGER IF EXISTS trg_availability_rules_sync_update;
+DROP TRIGGER IF EXISTS trg_availability_rules_sync_insert;
+DROP TRIGGER IF EXISTS trg_tax_class_assignments_sync_delete;
+DROP TRIGGER IF EXISTS trg_tax_class_assignments_sync_update;
+DROP TRIGGER IF EXISTS trg_tax_class_assignments_sync_insert;
+DROP TRIGGER IF EXISTS trg_tax_classes_sync_delete;
+DROP TRIGGER IF EXISTS trg_tax_classes_sync_update;
+DROP TRIGGER IF EXISTS trg_tax_classes_sync_insert;
+DROP TRIGGER IF EXISTS trg_price_list_items_sync_delete;
+DROP TRIGGER IF EXISTS trg_price_list_items_sync_update;
+DROP TRIGGER IF EXISTS trg_price_list_items_sync_insert;
+DROP TRIGGER IF EXISTS trg_price_lists_sync_delete;
+DROP TRIGGER IF EXISTS trg_price_lists_sync_update;
+DROP TRIGGER IF EXISTS trg_price_lists_sync_insert;
+DROP TRIGGER IF EXISTS trg_bundle_slot_options_sync_delete;
+DROP TRIGGER IF EXISTS trg_bundle_slot_options_sync_update;
+DROP TRIGGER IF EXISTS trg_bundle_slot_options_sync_insert;
+DROP TRIGGER IF EXISTS trg_bundle_slots_sync_delete;
+DROP TRIGGER IF EXISTS trg_bundle_slots_sync_update;
+DROP TRIGGER IF EXISTS trg_bundle_slots_sync_insert;
+DROP TRIGGER IF EXISTS trg_bundle_items_sync_delete;
+DROP TRIGGER IF EXISTS trg_bundle_items_sync_update;
+DROP TRIGGER IF EXISTS trg_bundle_items_sync_insert;
+DROP TRIGGER IF EXISTS trg_product_addon_prices_sync_delete;
+DROP TRIGGER IF EXISTS trg_product_addon_prices_sync_update;
+DROP TRIGGER IF EXISTS trg_product_addon_prices_sync_insert;
+DROP TRIGGER IF EXISTS trg_addon_group_assignments_sync_delete;
+DROP TRIGGER IF EXISTS trg_addon_group_assignments_sync_update;
+DROP TRIGGER IF EXISTS trg_addon_group_assignments_sync_insert;
+DROP TRIGGER IF EXISTS trg_addon_group_items_sync_delete;
+DROP TRIGGER IF EXISTS trg_addon_group_items_sync_update;
+DROP TRIGGER IF EXISTS trg_addon_group_items_sync_insert;
+DROP TRIGGER IF EXISTS trg_addon_groups_sync_delete;
+DROP TRIGGER IF EXISTS trg_addon_groups_sync_update;
+DROP TRIGGER IF EXISTS trg_addon_groups_sync_insert;
+DROP TRIGGER IF EXISTS trg_addons_sync_delete;
+DROP TRIGGER IF EXISTS trg_addons_sync_update;
+DROP TRIGGER IF EXISTS trg_addons_sync_insert;
+DROP TRIGGER IF EXISTS trg_product_variants_sync_delete;
+DROP TRIGGER IF EXISTS trg_product_variants_sync_update;
+DROP TRIGGER IF EXISTS trg_product_variants_sync_insert;
+DROP TRIGGER IF EXISTS trg_products_sync_delete;
+DROP TRIGGER IF EXISTS trg_products_sync_update;
+DROP TRIGGER IF EXISTS trg_products_sync_insert;
+DROP TRIGGER IF EXISTS trg_subcategories_sync_delete;
+DROP TRIGGER IF EXISTS trg_subcategories_sync_update;
+DROP TRIGGER IF EXISTS trg_subcategories_sync_insert;
+DROP TRIGGER IF EXISTS trg_categories_sync_delete;
+DROP TRIGGER IF EXISTS trg_categories_sync_update;
+DROP TRIGGER IF EXISTS trg_categories_sync_insert;
+DROP TRIGGER IF EXISTS trg_units_sync_delete;
+DROP TRIGGER IF EXISTS trg_units_sync_update;
+DROP TRIGGER IF EXISTS trg_units_sync_insert;
+
+DROP VIEW IF EXISTS store_shifts_sync;
+DROP VIEW IF EXISTS store_prefs_sync;
+DROP VIEW IF EXISTS rooms_sync;
+DROP VIEW IF EXISTS tables_sync;
+DROP VIEW IF EXISTS floors_sync;
+DROP VIEW IF EXISTS sold_out_items_sync;
+DROP VIEW IF EXISTS availability_rules_sync;
+DROP VIEW IF EXISTS tax_class_assignments_sync;
+DROP VIEW IF EXISTS tax_classes_sync;
+DROP VIEW IF EXISTS price_list_items_sync;
+DROP VIEW IF EXISTS price_lists_sync;
+DROP VIEW IF EXISTS bundle_slot_options_sync;
+DROP VIEW IF EXISTS bundle_slots_sync;
+DROP VIEW IF EXISTS bundle_items_sync;
+DROP VIEW IF EXISTS product_addon_prices_sync;
+DROP VIEW IF EXISTS addon_group_assignments_sync;
+DROP VIEW IF EXISTS addon_group_items_sync;
+DROP VIEW IF EXISTS addon_groups_sync;
+DROP VIEW IF EXISTS addons_sync;
+DROP VIEW IF EXISTS product_variants_sync;
+DROP VIEW IF EXISTS products_sync;
+DROP VIEW IF EXISTS subcategories_sync;
+DROP VIEW IF EXISTS categories_sync;
+DROP VIEW IF EXISTS units_sync;
+
+DROP TABLE IF EXISTS sync_changes;
@@ -0,0 +1,893 @@
+-- the latest change of every row the terminals keep offline, version is the
+-- cursor of the pull (a terminal ask for the changes after the last version
+-- it has), data is the whole row (null when deleted), sqlite has a single
+-- writer so the next version is the last one + 1
+CREATE TABLE IF NOT EXISTS sync_changes (
+    entity_type VARCHAR(50) NOT NULL,
+    entity_id VARCHAR(100) NOT NULL,
+    version BIGINT NOT NULL UNIQUE,
+    data TEXT CHECK (data IS NULL OR json_valid(data)),
+    changed_at BIGINT NOT NULL,
+    PRIMARY KEY (entity_type, entity_id)
+);
+
+-- the row of a synced table as recorded in sync_changes (to_jsonb of postgres)
+CREATE VIEW units_sync AS
+SELECT id, CAST(id AS TEXT) AS entity_id, json_object(
+    'id', id, 'magnitude', magnitude, 'name', name, 'symbol', symbol, 'deleted_at', deleted_at,
+    'deleted_by', deleted_by
+) AS data FROM units;
+
+CREATE VIEW categories_sync AS
+SELECT id, CAST(id AS TEXT) AS entity_id, json_object(
+    'id', id, 'name', name, 'deleted_at', deleted_at, 'deleted_by', deleted_by
+) AS data FROM categories;
+
+CREATE VIEW subcategories_sync AS
+SELECT id, CAST(id AS TEXT) AS entity_id, json_object(
+    'id', id, 'category_id', category_id, 'name', name, 'deleted_at', deleted_at,
+    'deleted_by', deleted_by
+) AS data FROM subcategories;
+
+CREATE VIEW products_sync AS
+SELECT id, CAST(id AS TEXT) AS entity_id, json_object(
+    'id', id, 'category_id', category_id, 'subcategory_id', subcategory_id, 'sku', sku,
+    'image', image, 'gallery', json(gallery), 'name', name, 'description', description,
+    'price', price, 'created_at', created_at, 'updated_at', updated_at,
+    'deleted_at', deleted_at, 'deleted_by', deleted_by
+) AS data FROM products;
+
+CREATE VIEW product_variants_sync AS
+SELECT id, CAST(id AS TEXT) AS entity_id, json_object(
+    'id', id, 'product_id', product_id, 'unit_id', unit_id, 'unit_size', unit_size,
+    'type', type, 'name', name, 'description', description, 'price', price,
+    'created_at', created_at, 'updated_at', updated_at, 'deleted_at', deleted_at,
+    'deleted_by', deleted_by
+) AS data FROM product_variants;
+
+CREATE VIEW addons_sync AS
+SELECT id, CAST(id AS TEXT) AS entity_id, json_object(
+    'id', id, 'name', name, 'description', description, 'price', price,
+    'created_at', created_at, 'updated_at', updated_at, 'deleted_at', deleted_at,
+    'deleted_by', deleted_by
+) AS data FROM addons;
+
+CREATE VIEW addon_groups_sync AS
+SELECT id, CAST(id AS TEXT) AS entity_id, json_object(
+    'id', id, 'name', name, 'description', description, 'min_select', min_select,
+    'max_select', max_select, 'created_at', created_at, 'updated_at', updated_at
+) AS data FROM addon_groups;
+
+CREATE VIEW addon_group_items_sync AS
+SELECT addon_group_id, addon_id, CAST(addon_group_id AS TEXT) || ':' || CAST(addon_id AS TEXT) AS entity_id, json_object(
+    'addon_group_id', addon_group_id, 'addon_id', addon_id, 'position', position
+) AS data FROM addon_group_items;
+
+CREATE VIEW addon_group_assignments_sync AS
+SELECT id, CAST(id AS TEXT) AS entity_id, json_object(
+    'id', id, 'addon_group_id', addon_group_id, 'product_id', product_id,
+    'category_id', category_id, 'created_at', created_at
+) AS data FROM addon_group_assignments;
+
+CREATE VIEW product_addon_prices_sync AS
+SELECT product_id, addon_id, CAST(product_id AS TEXT) || ':' || CAST(addon_id AS TEXT) AS entity_id, json_object(
+    'product_id', product_id, 'addon_id', addon_id, 'price', price, 'created_at', created_at,
+    'updated_at', updated_at
+) AS data FROM product_addon_prices;
+
+CREATE VIEW bundle_items_sync AS
+SELECT id, CAST(id AS TEXT) AS entity_id, json_object(
+    'id', id, 'bundle_id', bundle_id, 'product_id', product_id, 'variant_id', variant_id,
+    'qty', qty, 'position', position
+) AS data FROM bundle_items;
+
+CREATE VIEW bundle_slots_sync AS
+SELECT id, CAST(id AS TEXT) AS entity_id, json_object(
+    'id', id, 'bundle_id', bundle_id, 'name', name, 'subcategory_id', subcategory_id,
+    'qty', qty, 'position', position
+) AS data FROM bundle_slots;
+
+CREATE VIEW bundle_slot_options_sync AS
+SELECT id, CAST(id AS TEXT) AS entity_id, json_object(
+    'id', id, 'slot_id', slot_id, 'product_id', product_id, 'variant_id', variant_id,
+    'upcharge', upcharge, 'position', position
+) AS data FROM bundle_slot_options;
+
+CREATE VIEW price_lists_sync AS
+SELECT id, CAST(id AS TEXT) AS entity_id, json_object(
+    'id', id, 'name', name, 'channel', channel, 'customer_tier', customer_tier, 'days', days,
+    'start_time', start_time, 'end_time', end_time, 'effective_from', effective_from,
+    'effective_to', effective_to, 'priority', priority,
+    'disabled', json(CASE WHEN disabled THEN 'true' ELSE 'false' END),
+    'created_at', created_at, 'updated_at', updated_at
+) AS data FROM price_lists;
+
+CREATE VIEW price_list_items_sync AS
+SELECT id, CAST(id AS TEXT) AS entity_id, json_object(
+    'id', id, 'price_list_id', price_list_id, 'item_type', item_type, 'item_id', item_id,
+    'price', price
+) AS data FROM price_list_items;
+
+CREATE VIEW tax_classes_sync AS
+SELECT id, CAST(id AS TEXT) AS entity_id, json_object(
+    'id', id, 'name', name, 'rate', json(rate),
+    'inclusive', json(CASE WHEN inclusive THEN 'true' ELSE 'false' END),
+    'after_service', json(CASE WHEN after_service THEN 'true' ELSE 'false' END),
+    'is_default', json(CASE WHEN is_default THEN 'true' ELSE 'false' END),
+    'created_at', created_at, 'updated_at', updated_at
+) AS data FROM tax_classes;
+
+CREATE VIEW tax_class_assignments_sync AS
+SELECT id, CAST(id AS TEXT) AS entity_id, json_object(
+    'id', id, 'tax_class_id', tax_class_id, 'product_id', product_id, 'category_id', category_id
+) AS data FROM tax_class_assignments;
+
+CREATE VIEW availability_rules_sync AS
+SELECT id, CAST(id AS TEXT) AS entity_id, json_object(
+    'id', id, 'product_id', product_id, 'category_id', category_id, 'days', days,
+    'start_time', start_time, 'end_time', end_time, 'created_at', created_at
+) AS data FROM availability_rules;
+
+CREATE VIEW sold_out_items_sync AS
+SELECT id, CAST(id AS TEXT) AS entity_id, json_object(
+    'id', id, 'product_id', product_id, 'variant_id', variant_id,
+    'store_shift_id', store_shift_id, 'created_by', created_by, 'created_at', created_at
+) AS data FROM sold_out_items;
+
+CREATE VIEW floors_sync AS
+SELECT id, CAST(id AS TEXT) AS entity_id, json_object(
+    'id', id, 'name', name, 'created_at', created_at, 'updated_at', updated_at,
+    'deleted_at', deleted_at, 'deleted_by', deleted_by
+) AS data FROM floors;
+
+CREATE VIEW tables_sync AS
+SELECT id, CAST(id AS TEXT) AS entity_id, json_object(
+    'id', id, 'floor_id', floor_id, 'name', name, 'x_pos', x_pos, 'y_pos', y_pos,
+    'w_size', w_size, 'h_size', h_size, 'capacity', capacity, 'type', type,
+    'created_at', created_at, 'updated_at', updated_at, 'deleted_at', deleted_at,
+    'deleted_by', deleted_by
+) AS data FROM tables;
+
+CREATE VIEW rooms_sync AS
+SELECT id, CAST(id AS TEXT) AS entity_id, json_object(
+    'id', id, 'floor_id', floor_id, 'name', name, 'x_pos', x_pos, 'y_pos', y_pos,
+    'w_size', w_size, 'h_size', h_size, 'capacity', capacity, 'price', price,
+    'created_at', created_at, 'updated_at', updated_at, 'deleted_at', deleted_at,
+    'deleted_by', deleted_by
+) AS data FROM rooms;
+
+CREATE VIEW store_prefs_sync AS
+SELECT key, CAST(key AS TEXT) AS entity_id, json_object(
+    'key', key, 'value', value, 'created_at', created_at, 'updated_at', updated_at
+) AS data FROM store_prefs;
+
+CREATE VIEW store_shifts_sync AS
+SELECT id, CAST(id AS TEXT) AS entity_id, json_object(
+    'id', id, 'shift_id', shift_id, 'open_at', open_at, 'open_by', open_by,
+    'open_cash', open_cash, 'close_at', close_at, 'close_by', close_by,
+    'close_cash', close_cash, 'created_at', created_at, 'updated_at', updated_at
+) AS data FROM store_shifts;
+
+-- current rows are the first changes, a new terminal pull them all from 0
+INSERT INTO sync_changes (entity_type, entity_id, version, data, changed_at)
+SELECT entity_type, entity_id, ROW_NUMBER() OVER (), data, unixepoch() FROM (
+    SELECT 'units' AS entity_type, entity_id, data FROM units_sync
+    UNION ALL
+    SELECT 'categories', entity_id, data FROM categories_sync
+    UNION ALL
+    SELECT 'subcategories', entity_id, data FROM subcategories_sync
+    UNION ALL
+    SELECT 'products', entity_id, data FROM products_sync
+    UNION ALL
+    SELECT 'product_variants', entity_id, data FROM product_variants_sync
+    UNION ALL
+    SELECT 'addons', entity_id, data FROM addons_sync
+    UNION ALL
+    SELECT 'addon_groups', entity_id, data FROM addon_groups_sync
+    UNION ALL
+    SELECT 'addon_group_items', entity_id, data FROM addon_group_items_sync
+    UNION ALL
+    SELECT 'addon_group_assignments', entity_id, data FROM addon_group_assignments_sync
+    UNION ALL
+    SELECT 'product_addon_prices', entity_id, data FROM product_addon_prices_sync
+    UNION ALL
+    SELECT 'bundle_items', entity_id, data FROM bundle_items_sync
+    UNION ALL
+    SELECT 'bundle_slots', entity_id, data FROM bundle_slots_sync
+    UNION ALL
+    SELECT 'bundle_slot_options', entity_id, data FROM bundle_slot_options_sync
+    UNION ALL
+    SELECT 'price_lists', entity_id, data FROM price_lists_sync
+    UNION ALL
+    SELECT 'price_list_items', entity_id, data FROM price_list_items_sync
+    UNION ALL
+    SELECT 'tax_classes', entity_id, data FROM tax_classes_sync
+    UNION ALL
+    SELECT 'tax_class_assignments', entity_id, data FROM tax_class_assignments_sync
+    UNION ALL
+    SELECT 'availability_rules', entity_id, data FROM availability_rules_sync
+    UNION ALL
+    SELECT 'sold_out_items', entity_id, data FROM sold_out_items_sync
+    UNION ALL
+    SELECT 'floors', entity_id, data FROM floors_sync
+    UNION ALL
+    SELECT 'tables', entity_id, data FROM tables_sync
+    UNION ALL
+    SELECT 'rooms', entity_id, data FROM rooms_sync
+    UNION ALL
+    SELECT 'store_prefs', entity_id, data FROM store_prefs_sync
+    UNION ALL
+    SELECT 'store_shifts', entity_id, data FROM store_shifts_sync
+);
+
+CREATE TRIGGER trg_units_sync_insert AFTER INSERT ON units
+BEGIN
+    REPLACE INTO sync_changes (entity_type, entity_id, version, data, changed_at)
+    SELECT 'units', CAST(NEW.id AS TEXT), COALESCE(MAX(version), 0) + 1,
+        (SELECT data FROM units_sync WHERE id = NEW.id), unixepoch()
+    FROM sync_changes;
+END;
+
+CREATE TRIGGER trg_units_sync_update AFTER UPDATE ON units
+WHEN (SELECT data FROM units_sync WHERE id = NEW.id) IS NOT (
+    SELECT data FROM sync_changes
+    WHERE entity_type = 'units' AND entity_id = CAST(NEW.id AS TEXT)
+)
+BEGIN
+    REPLACE INTO sync_changes (entity_type, entity_id, version, data, changed_at)
+    SELECT 'units', CAST(NEW.id AS TEXT), COALESCE(MAX(version), 0) + 1,
+        (SELECT data FROM units_sync WHERE id = NEW.id), unixepoch()
+    FROM sync_changes;
+END;
+
+CREATE TRIGGER trg_units_sync_delete AFTER DELETE ON units
+BEGIN
+    REPLACE INTO sync_changes (entity_type, entity_id, version, data, changed_at)
+    SELECT 'units', CAST(OLD.id AS TEXT), COALESCE(MAX(version), 0) + 1,
+        NULL, unixepoch()
+    FROM sync_changes;
+END;
+
+CREATE TRIGGER trg_categories_sync_insert AFTER INSERT ON categories
+BEGIN
+    REPLACE INTO sync_changes (entity_type, entity_id, version, data, changed_at)
+    SELECT 'categories', CAST(NEW.id AS TEXT), COALESCE(MAX(version), 0) + 1,
+        (SELECT data FROM categories_sync WHERE id = NEW.id), unixepoch()
+    FROM sync_changes;
+END;
+
+CREATE TRIGGER trg_categories_sync_update AFTER UPDATE ON categories
+WHEN (SELECT data FROM categories_sync WHERE id = NEW.id) IS NOT (
+    SELECT data FROM sync_changes
+    WHERE entity_type = 'categories' AND entity_id = CAST(NEW.id AS TEXT)
+)
+BEGIN
+    REPLACE INTO sync_changes (entity_type, entity_id, version, data, changed_at)
+    SELECT 'categories', CAST(NEW.id AS TEXT), COALESCE(MAX(version), 0) + 1,
+        (SELECT data FROM categories_sync WHERE id = NEW.id), unixepoch()
+    FROM sync_changes;
+END;
+
+CREATE TRIGGER trg_categories_sync_delete AFTER DELETE ON categories
+BEGIN
+    REPLACE INTO sync_changes (entity_type, entity_id, version, data, changed_at)
+    SELECT 'categories', CAST(OLD.id AS TEXT), COALESCE(MAX(version), 0) + 1,
+        NULL, unixepoch()
+    FROM sync_changes;
+END;
+
+CREATE TRIGGER trg_subcategories_sync_insert AFTER INSERT ON subcategories
+BEGIN
+    REPLACE INTO sync_changes (entity_type, entity_id, version, data, changed_at)
+    SELECT 'subcategories', CAST(NEW.id AS TEXT), COALESCE(MAX(version), 0) + 1,
+        (SELECT data FROM subcategories_sync WHERE id = NEW.id), unixepoch()
+    FROM sync_changes;
+END;
+
+CREATE TRIGGER trg_subcategories_sync_update AFTER UPDATE ON subcategories
+WHEN (SELECT data FROM subcategories_sync WHERE id = NEW.id) IS NOT (
+    SELECT data FROM sync_changes
+    WHERE entity_type = 'subcategories' AND entity_id = CAST(NEW.id AS TEXT)
+)
+BEGIN
+    REPLACE INTO sync_changes (entity_type, entity_id, version, data, changed_at)
+    SELECT 'subcategories', CAST(NEW.id AS TEXT), COALESCE(MAX(version), 0) + 1,
+        (SELECT data FROM subcategories_sync WHERE id = NEW.id), unixepoch()
+    FROM sync_changes;
+END;
+
+CREATE TRIGGER trg_subcategories_sync_delete AFTER DELETE ON subcategories
+BEGIN
+    REPLACE INTO sync_changes (entity_type, entity_id, version, data, changed_at)
+    SELECT 'subcategories', CAST(OLD.id AS TEXT), COALESCE(MAX(version), 0) + 1,
+        NULL, unixepoch()
+    FROM sync_changes;
+END;
+
+CREATE TRIGGER trg_products_sync_insert AFTER INSERT ON products
+BEGIN
+    REPLACE INTO sync_changes (entity_type, entity_id, version, data, changed_at)
+    SELECT 'products', CAST(NEW.id AS TEXT), COALESCE(MAX(version), 0) + 1,
+        (SELECT data FROM products_sync WHERE id = NEW.id), unixepoch()
+    FROM sync_changes;
+END;
+
+CREATE TRIGGER trg_products_sync_update AFTER UPDATE ON products
+WHEN (SELECT data FROM products_sync WHERE id = NEW.id) IS NOT (
+    SELECT data FROM sync_changes
+    WHERE entity_type = 'products' AND entity_id = CAST(NEW.id AS TEXT)
+)
+BEGIN
+    REPLACE INTO sync_changes (entity_type, entity_id, version, data, changed_at)
+    SELECT 'products', CAST(NEW.id AS TEXT), COALESCE(MAX(version), 0) + 1,
+        (SELECT data FROM products_sync WHERE id = NEW.id), unixepoch()
+    FROM sync_changes;
+END;
+
+CREATE TRIGGER trg_products_sync_delete AFTER DELETE ON products
+BEGIN
+    REPLACE INTO sync_changes (entity_type, entity_id, version, data, changed_at)
+    SELECT 'products', CAST(OLD.id AS TEXT), COALESCE(MAX(version), 0) + 1,
+        NULL, unixepoch()
+    FROM sync_changes;
+END;
+
+CREATE TRIGGER trg_product_variants_sync_insert AFTER INSERT ON product_variants
+BEGIN
+    REPLACE INTO sync_changes (entity_type, entity_id, version, data, changed_at)
+    SELECT 'product_variants', CAST(NEW.id AS TEXT), COALESCE(MAX(version), 0) + 1,
+        (SELECT data FROM product_variants_sync WHERE id = NEW.id), unixepoch()
+    FROM sync_changes;
+END;
+
+CREATE TRIGGER trg_product_variants_sync_update AFTER UPDATE ON product_variants
+WHEN (SELECT data FROM product_variants_sync WHERE id = NEW.id) IS NOT (
+    SELECT data FROM sync_changes
+    WHERE entity_type = 'product_variants' AND entity_id = CAST(NEW.id AS TEXT)
+)
+BEGIN
+    REPLACE INTO sync_changes (entity_type, entity_id, version, data, changed_at)
+    SELECT 'product_variants', CAST(NEW.id AS TEXT), COALESCE(MAX(version), 0) + 1,
+        (SELECT data FROM product_variants_sync WHERE id = NEW.id), unixepoch()
+    FROM sync_changes;
+END;
+
+CREATE TRIGGER trg_product_variants_sync_delete AFTER DELETE ON product_variants
+BEGIN
+    REPLACE INTO sync_changes (entity_type, entity_id, version, data, changed_at)
+    SELECT 'product_variants', CAST(OLD.id AS TEXT), COALESCE(MAX(version), 0) + 1,
+        NULL, unixepoch()
+    FROM sync_changes;
+END;
+
+CREATE TRIGGER trg_addons_sync_insert AFTER INSERT ON addons
+BEGIN
+    REPLACE INTO sync_changes (entity_type, entity_id, version, data, changed_at)
+    SELECT 'addons', CAST(NEW.id AS TEXT), COALESCE(MAX(version), 0) + 1,
+        (SELECT data FROM addons_sync WHERE id = NEW.id), unixepoch()
+    FROM sync_changes;
+END;
+
+CREATE TRIGGER trg_addons_sync_update AFTER UPDATE ON addons
+WHEN (SELECT data FROM addons_sync WHERE id = NEW.id) IS NOT (
+    SELECT data FROM sync_changes
+    WHERE entity_type = 'addons' AND entity_id = CAST(NEW.id AS TEXT)
+)
+BEGIN
+    REPLACE INTO sync_changes (entity_type, entity_id, version, data, changed_at)
+    SELECT 'addons', CAST(NEW.id AS TEXT), COALESCE(MAX(version), 0) + 1,
+        (SELECT data FROM addons_sync WHERE id = NEW.id), unixepoch()
+    FROM sync_changes;
+END;
+
+CREATE TRIGGER trg_addons_sync_delete AFTER DELETE ON addons
+BEGIN
+    REPLACE INTO sync_changes (entity_type, entity_id, version, data, changed_at)
+    SELECT 'addons', CAST(OLD.id AS TEXT), COALESCE(MAX(version), 0) + 1,
+        NULL, unixepoch()
+    FROM sync_changes;
+END;
+
+CREATE TRIGGER trg_addon_groups_sync_insert AFTER INSERT ON addon_groups
+BEGIN
+    REPLACE INTO sync_changes (entity_type, entity_id, version, data, changed_at)
+    SELECT 'addon_groups', CAST(NEW.id AS TEXT), COALESCE(MAX(version), 0) + 1,
+        (SELECT data FROM addon_groups_sync WHERE id = NEW.id), unixepoch()
+    FROM sync_changes;
+END;
+
+CREATE TRIGGER trg_addon_groups_sync_update AFTER UPDATE ON addon_groups
+WHEN (SELECT data FROM addon_groups_sync WHERE id = NEW.id) IS NOT (
+    SELECT data FROM sync_changes
+    WHERE entity_type = 'addon_groups' AND entity_id = CAST(NEW.id AS TEXT)
+)
+BEGIN
+    REPLACE INTO sync_changes (entity_type, entity_id, version, data, changed_at)
+    SELECT 'addon_groups', CAST(NEW.id AS TEXT), COALESCE(MAX(version), 0) + 1,
+        (SELECT data FROM addon_groups_sync WHERE id = NEW.id), unixepoch()
+    FROM sync_changes;
+END;
+
+CREATE TRIGGER trg_addon_groups_sync_delete AFTER DELETE ON addon_groups
+BEGIN
+    REPLACE INTO sync_changes (entity_type, entity_id, version, data, changed_at)
+    SELECT 'addon_groups', CAST(OLD.id AS TEXT), COALESCE(MAX(version), 0) + 1,
+        NULL, unixepoch()
+    FROM sync_changes;
+END;
+
+CREATE TRIGGER trg_addon_group_items_sync_insert AFTER INSERT ON addon_group_items
+BEGIN
+    REPLACE INTO sync_changes (entity_type, entity_id, version, data, changed_at)
+    SELECT 'addon_group_items', CAST(NEW.addon_group_id AS TEXT) || ':' || CAST(NEW.addon_id AS TEXT), COALESCE(MAX(version), 0) + 1,
+        (SELECT data FROM addon_group_items_sync WHERE addon_group_id = NEW.addon_group_id AND addon_id = NEW.addon_id), unixepoch()
+    FROM sync_changes;
+END;
+
+CREATE TRIGGER trg_addon_group_items_sync_update AFTER UPDATE ON addon_group_items
+WHEN (SELECT data FROM addon_group_items_sync WHERE addon_group_id = NEW.addon_group_id AND addon_id = NEW.addon_id) IS NOT (
+    SELECT data FROM sync_changes
+    WHERE entity_type = 'addon_group_items' AND entity_id = CAST(NEW.addon_group_id AS TEXT) || ':' || CAST(NEW.addon_id AS TEXT)
+)
+BEGIN
+    REPLACE INTO sync_changes (entity_type, entity_id, version, data, changed_at)
+    SELECT 'addon_group_items', CAST(NEW.addon_group_id AS TEXT) || ':' || CAST(NEW.addon_id AS TEXT), COALESCE(MAX(version), 0) + 1,
+        (SELECT data FROM addon_group_items_sync WHERE addon_group_id = NEW.addon_group_id AND addon_id = NEW.addon_id), unixepoch()
+    FROM sync_changes;
+END;
+
+CREATE TRIGGER trg_addon_group_items_sync_delete AFTER DELETE ON addon_group_items
+BEGIN
+    REPLACE INTO sync_changes (entity_type, entity_id, version, data, changed_at)
+    SELECT 'addon_group_items', CAST(OLD.addon_group_id AS TEXT) || ':' || CAST(OLD.addon_id AS TEXT), COALESCE(MAX(version), 0) + 1,
+        NULL, unixepoch()
+    FROM sync_changes;
+END;
+
+CREATE TRIGGER trg_addon_group_assignments_sync_insert AFTER INSERT ON addon_group_assignments
+BEGIN
+    REPLACE INTO sync_changes (entity_type, entity_id, version, data, changed_at)
+    SELECT 'addon_group_assignments', CAST(NEW.id AS TEXT), COALESCE(MAX(version), 0) + 1,
+        (SELECT data FROM addon_group_assignments_sync WHERE id = NEW.id), unixepoch()
+    FROM sync_changes;
+END;
+
+CREATE TRIGGER trg_addon_group_assignments_sync_update AFTER UPDATE ON addon_group_assignments
+WHEN (SELECT data FROM addon_group_assignments_sync WHERE id = NEW.id) IS NOT (
+    SELECT data FROM sync_changes
+    WHERE entity_type = 'addon_group_assignments' AND entity_id = CAST(NEW.id AS TEXT)
+)
+BEGIN
+    REPLACE INTO sync_changes (entity_type, entity_id, version, data, changed_at)
+    SELECT 'addon_group_assignments', CAST(NEW.id AS TEXT), COALESCE(MAX(version), 0) + 1,
+        (SELECT data FROM addon_group_assignments_sync WHERE id = NEW.id), unixepoch()
+    FROM sync_changes;
+END;
+
+CREATE TRIGGER trg_addon_group_assignments_sync_delete AFTER DELETE ON addon_group_assignments
+BEGIN
+    REPLACE INTO sync_changes (entity_type, entity_id, version, data, changed_at)
+    SELECT 'addon_group_assignments', CAST(OLD.id AS TEXT), COALESCE(MAX(version), 0) + 1,
+        NULL, unixepoch()
+    FROM sync_changes;
+END;
+
+CREATE TRIGGER trg_product_addon_prices_sync_insert AFTER INSERT ON product_addon_prices
+BEGIN
+    REPLACE INTO sync_changes (entity_type, entity_id, version, data, changed_at)
+    SELECT 'product_addon_prices', CAST(NEW.product_id AS TEXT) || ':' || CAST(NEW.addon_id AS TEXT), COALESCE(MAX(version), 0) + 1,
+        (SELECT data FROM product_addon_prices_sync WHERE product_id = NEW.product_id AND addon_id = NEW.addon_id), unixepoch()
+    FROM sync_changes;
+END;
+
+CREATE TRIGGER trg_product_addon_prices_sync_update AFTER UPDATE ON product_addon_prices
+WHEN (SELECT data FROM product_addon_prices_sync WHERE product_id = NEW.product_id AND addon_id = NEW.addon_id) IS NOT (
+    SELECT data FROM sync_changes
+    WHERE entity_type = 'product_addon_prices' AND entity_id = CAST(NEW.product_id AS TEXT) || ':' || CAST(NEW.addon_id AS TEXT)
+)
+BEGIN
+    REPLACE INTO sync_changes (entity_type, entity_id, version, data, changed_at)
+    SELECT 'product_addon_prices', CAST(NEW.product_id AS TEXT) || ':' || CAST(NEW.addon_id AS TEXT), COALESCE(MAX(version), 0) + 1,
+        (SELECT data FROM product_addon_prices_sync WHERE product_id = NEW.product_id AND addon_id = NEW.addon_id), unixepoch()
+    FROM sync_changes;
+END;
+
+CREATE TRIGGER trg_product_addon_prices_sync_delete AFTER DELETE ON product_addon_prices
+BEGIN
+    REPLACE INTO sync_changes (entity_type, entity_id, version, data, changed_at)
+    SELECT 'product_addon_prices', CAST(OLD.product_id AS TEXT) || ':' || CAST(OLD.addon_id AS TEXT), COALESCE(MAX(version), 0) + 1,
+        NULL, unixepoch()
+    FROM sync_changes;
+END;
+
+CREATE TRIGGER trg_bundle_items_sync_insert AFTER INSERT ON bundle_items
+BEGIN
+    REPLACE INTO sync_changes (entity_type, entity_id, version, data, changed_at)
+    SELECT 'bundle_items', CAST(NEW.id AS TEXT), COALESCE(MAX(version), 0) + 1,
+        (SELECT data FROM bundle_items_sync WHERE id = NEW.id), unixepoch()
+    FROM sync_changes;
+END;
+
+CREATE TRIGGER trg_bundle_items_sync_update AFTER UPDATE ON bundle_items
+WHEN (SELECT data FROM bundle_items_sync WHERE id = NEW.id) IS NOT (
+    SELECT data FROM sync_changes
+    WHERE entity_type = 'bundle_items' AND entity_id = CAST(NEW.id AS TEXT)
+)
+BEGIN
+    REPLACE INTO sync_changes (entity_type, entity_id, version, data, changed_at)
+    SELECT 'bundle_items', CAST(NEW.id AS TEXT), COALESCE(MAX(version), 0) + 1,
+        (SELECT data FROM bundle_items_sync WHERE id = NEW.id), unixepoch()
+    FROM sync_changes;
+END;
+
+CREATE TRIGGER trg_bundle_items_sync_delete AFTER DELETE ON bundle_items
+BEGIN
+    REPLACE INTO sync_changes (entity_type, entity_id, version, data, changed_at)
+    SELECT 'bundle_items', CAST(OLD.id AS TEXT), COALESCE(MAX(version), 0) + 1,
+        NULL, unixepoch()
+    FROM sync_changes;
+END;
+
+CREATE TRIGGER trg_bundle_slots_sync_insert AFTER INSERT ON bundle_slots
+BEGIN
+    REPLACE INTO sync_changes (entity_type, entity_id, version, data, changed_at)
+    SELECT 'bundle_slots', CAST(NEW.id AS TEXT), COALESCE(MAX(version), 0) + 1,
+        (SELECT data FROM bundle_slots_sync WHERE id = NEW.id), unixepoch()
+    FROM sync_changes;
+END;
+
+CREATE TRIGGER trg_bundle_slots_sync_update AFTER UPDATE ON bundle_slots
+WHEN (SELECT data FROM bundle_slots_sync WHERE id = NEW.id) IS NOT (
+    SELECT data FROM sync_changes
+    WHERE entity_type = 'bundle_slots' AND entity_id = CAST(NEW.id AS TEXT)
+)
+BEGIN
+    REPLACE INTO sync_changes (entity_type, entity_id, version, data, changed_at)
+    SELECT 'bundle_slots', CAST(NEW.id AS TEXT), COALESCE(MAX(version), 0) + 1,
+        (SELECT data FROM bundle_slots_sync WHERE id = NEW.id), unixepoch()
+    FROM sync_changes;
+END;
+
+CREATE TRIGGER trg_bundle_slots_sync_delete AFTER DELETE ON bundle_slots
+BEGIN
+    REPLACE INTO sync_changes (entity_type, entity_id, version, data, changed_at)
+    SELECT 'bundle_slots', CAST(OLD.id AS TEXT), COALESCE(MAX(version), 0) + 1,
+        NULL, unixepoch()
+    FROM sync_changes;
+END;
+
+CREATE TRIGGER trg_bundle_slot_options_sync_insert AFTER INSERT ON bundle_slot_options
+BEGIN
+    REPLACE INTO sync_changes (entity_type, entity_id, version, data, changed_at)
+    SELECT 'bundle_slot_options', CAST(NEW.id AS TEXT), COALESCE(MAX(version), 0) + 1,
+        (SELECT data FROM bundle_slot_options_sync WHERE id = NEW.id), unixepoch()
+    FROM sync_changes;
+END;
+
+CREATE TRIGGER trg_bundle_slot_options_sync_update AFTER UPDATE ON bundle_slot_options
+WHEN (SELECT data FROM bundle_slot_options_sync WHERE id = NEW.id) IS NOT (
+    SELECT data FROM sync_changes
+    WHERE entity_type = 'bundle_slot_options' AND entity_id = CAST(NEW.id AS TEXT)
+)
+BEGIN
+    REPLACE INTO sync_changes (entity_type, entity_id, version, data, changed_at)
+    SELECT 'bundle_slot_options', CAST(NEW.id AS TEXT), COALESCE(MAX(version), 0) + 1,
+        (SELECT data FROM bundle_slot_options_sync WHERE id = NEW.id), unixepoch()
+    FROM sync_changes;
+END;
+
+CREATE TRIGGER trg_bundle_slot_options_sync_delete AFTER DELETE ON bundle_slot_options
+BEGIN
+    REPLACE INTO sync_changes (entity_type, entity_id, version, data, changed_at)
+    SELECT 'bundle_slot_options', CAST(OLD.id AS TEXT), COALESCE(MAX(version), 0) + 1,
+        NULL, unixepoch()
+    FROM sync_changes;
+END;
+
+CREATE TRIGGER trg_price_lists_sync_insert AFTER INSERT ON price_lists
+BEGIN
+    REPLACE INTO sync_changes (entity_type, entity_id, version, data, changed_at)
+    SELECT 'price_lists', CAST(NEW.id AS TEXT), COALESCE(MAX(version), 0) + 1,
+        (SELECT data FROM price_lists_sync WHERE id = NEW.id), unixepoch()
+    FROM sync_changes;
+END;
+
+CREATE TRIGGER trg_price_lists_sync_update AFTER UPDATE ON price_lists
+WHEN (SELECT data FROM price_lists_sync WHERE id = NEW.id) IS NOT (
+    SELECT data FROM sync_changes
+    WHERE entity_type = 'price_lists' AND entity_id = CAST(NEW.id AS TEXT)
+)
+BEGIN
+    REPLACE INTO sync_changes (entity_type, entity_id, version, data, changed_at)
+    SELECT 'price_lists', CAST(NEW.id AS TEXT), COALESCE(MAX(version), 0) + 1,
+        (SELECT data FROM price_lists_sync WHERE id = NEW.id), unixepoch()
+    FROM sync_changes;
+END;
+
+CREATE TRIGGER trg_price_lists_sync_delete AFTER DELETE ON price_lists
+BEGIN
+    REPLACE INTO sync_changes (entity_type, entity_id, version, data, changed_at)
+    SELECT 'price_lists', CAST(OLD.id AS TEXT), COALESCE(MAX(version), 0) + 1,
+        NULL, unixepoch()
+    FROM sync_changes;
+END;
+
+CREATE TRIGGER trg_price_list_items_sync_insert AFTER INSERT ON price_list_items
+BEGIN
+    REPLACE INTO sync_changes (entity_type, entity_id, version, data, changed_at)
+    SELECT 'price_list_items', CAST(NEW.id AS TEXT), COALESCE(MAX(version), 0) + 1,
+        (SELECT data FROM price_list_items_sync WHERE id = NEW.id), unixepoch()
+    FROM sync_changes;
+END;
+
+CREATE TRIGGER trg_price_list_items_sync_update AFTER UPDATE ON price_list_items
+WHEN (SELECT data FROM price_list_items_sync WHERE id = NEW.id) IS NOT (
+    SELECT data FROM sync_changes
+    WHERE entity_type = 'price_list_items' AND entity_id = CAST(NEW.id AS TEXT)
+)
+BEGIN
+    REPLACE INTO sync_changes (entity_type, entity_id, version, data, changed_at)
+    SELECT 'price_list_items', CAST(NEW.id AS TEXT), COALESCE(MAX(version), 0) + 1,
+        (SELECT data FROM price_list_items_sync WHERE id = NEW.id), unixepoch()
+    FROM sync_changes;
+END;
+
+CREATE TRIGGER trg_price_list_items_sync_delete AFTER DELETE ON price_list_items
+BEGIN
+    REPLACE INTO sync_changes (entity_type, entity_id, version, data, changed_at)
+    SELECT 'price_list_items', CAST(OLD.id AS TEXT), COALESCE(MAX(version), 0) + 1,
+        NULL, unixepoch()
+    FROM sync_changes;
+END;
+
+CREATE TRIGGER trg_tax_classes_sync_insert AFTER INSERT ON tax_classes
+BEGIN
+    REPLACE INTO sync_changes (entity_type, entity_id, version, data, changed_at)
+    SELECT 'tax_classes', CAST(NEW.id AS TEXT), COALESCE(MAX(version), 0) + 1,
+        (SELECT data FROM tax_classes_sync WHERE id = NEW.id), unixepoch()
+    FROM sync_changes;
+END;
+
+CREATE TRIGGER trg_tax_classes_sync_update AFTER UPDATE ON tax_classes
+WHEN (SELECT data FROM tax_classes_sync WHERE id = NEW.id) IS NOT (
+    SELECT data FROM sync_changes
+    WHERE entity_type = 'tax_classes' AND entity_id = CAST(NEW.id AS TEXT)
+)
+BEGIN
+    REPLACE INTO sync_changes (entity_type, entity_id, version, data, changed_at)
+    SELECT 'tax_classes', CAST(NEW.id AS TEXT), COALESCE(MAX(version), 0) + 1,
+        (SELECT data FROM tax_classes_sync WHERE id = NEW.id), unixepoch()
+    FROM sync_changes;
+END;
+
+CREATE TRIGGER trg_tax_classes_sync_delete AFTER DELETE ON tax_classes
+BEGIN
+    REPLACE INTO sync_changes (entity_type, entity_id, version, data, changed_at)
+    SELECT 'tax_classes', CAST(OLD.id AS TEXT), COALESCE(MAX(version), 0) + 1,
+        NULL, unixepoch()
+    FROM sync_changes;
+END;
+
+CREATE TRIGGER trg_tax_class_assignments_sync_insert AFTER INSERT ON tax_class_assignments
+BEGIN
+    REPLACE INTO sync_changes (entity_type, entity_id, version, data, changed_at)
+    SELECT 'tax_class_assignments', CAST(NEW.id AS TEXT), COALESCE(MAX(version), 0) + 1,
+        (SELECT data FROM tax_class_assignments_sync WHERE id = NEW.id), unixepoch()
+    FROM sync_changes;
+END;
+
+CREATE TRIGGER trg_tax_class_assignments_sync_update AFTER UPDATE ON tax_class_assignments
+WHEN (SELECT data FROM tax_class_assignments_sync WHERE id = NEW.id) IS NOT (
+    SELECT data FROM sync_changes
+    WHERE entity_type = 'tax_class_assignments' AND entity_id = CAST(NEW.id AS TEXT)
+)
+BEGIN
+    REPLACE INTO sync_changes (entity_type, entity_id, version, data, changed_at)
+    SELECT 'tax_class_assignments', CAST(NEW.id AS TEXT), COALESCE(MAX(version), 0) + 1,
+        (SELECT data FROM tax_class_assignments_sync WHERE id = NEW.id), unixepoch()
+    FROM sync_changes;
+END;
+
+CREATE TRIGGER trg_tax_class_assignments_sync_delete AFTER DELETE ON tax_class_assignments
+BEGIN
+    REPLACE INTO sync_changes (entity_type, entity_id, version, data, changed_at)
+    SELECT 'tax_class_assignments', CAST(OLD.id AS TEXT), COALESCE(MAX(version), 0) + 1,
+        NULL, unixepoch()
+    FROM sync_changes;
+END;
+
+CREATE TRIGGER trg_availability_rules_sync_insert AFTER INSERT ON availability_rules
+BEGIN
+    REPLACE INTO sync_changes (entity_type, entity_id, version, data, changed_at)
+    SELECT 'availability_rules', CAST(NEW.id AS TEXT), COALESCE(MAX(version), 0) + 1,
+        (SELECT data FROM availability_rules_sync WHERE id = NEW.id), unixepoch()
+    FROM sync_changes;
+END;
+
+CREATE TRIGGER trg_availability_rules_sync_update AFTER UPDATE ON availability_rules
+WHEN (SELECT data FROM availability_rules_sync WHERE id = NEW.id) IS NOT (
+    SELECT data FROM sync_changes
+    WHERE entity_type = 'availability_rules' AND entity_id = CAST(NEW.id AS TEXT)
+)
+BEGIN
+    REPLACE INTO sync_changes (entity_type, entity_id, version, data, changed_at)
+    SELECT 'availability_rules', CAST(NEW.id AS TEXT), COALESCE(MAX(version), 0) + 1,
+        (SELECT data FROM availability_rules_sync WHERE id = NEW.id), unixepoch()
+    FROM sync_changes;
+END;
+
+CREATE TRIGGER trg_availability_rules_sync_delete AFTER DELETE ON availability_rules
+BEGIN
+    REPLACE INTO sync_changes (entity_type, entity_id, version, data, changed_at)
+    SELECT 'availability_rules', CAST(OLD.id AS TEXT), COALESCE(MAX(version), 0) + 1,
+        NULL, unixepoch()
+    FROM sync_changes;
+END;
+
+CREATE TRIGGER trg_sold_out_items_sync_insert AFTER INSERT ON sold_out_items
+BEGIN
+    REPLACE INTO sync_changes (entity_type, entity_id, version, data, changed_at)
+    SELECT 'sold_out_items', CAST(NEW.id AS TEXT), COALESCE(MAX(version), 0) + 1,
+        (SELECT data FROM sold_out_items_sync WHERE id = NEW.id), unixepoch()
+    FROM sync_changes;
+END;
+
+CREATE TRIGGER trg_sold_out_items_sync_update AFTER UPDATE ON sold_out_items
+WHEN (SELECT data FROM sold_out_items_sync WHERE id = NEW.id) IS NOT (
+    SELECT data FROM sync_changes
+    WHERE entity_type = 'sold_out_items' AND entity_id = CAST(NEW.id AS TEXT)
+)
+BEGIN
+    REPLACE INTO sync_changes (entity_type, entity_id, version, data, changed_at)
+    SELECT 'sold_out_items', CAST(NEW.id AS TEXT), COALESCE(MAX(version), 0) + 1,
+        (SELECT data FROM sold_out_items_sync WHERE id = NEW.id), unixepoch()
+    FROM sync_changes;
+END;
+
+CREATE TRIGGER trg_sold_out_items_sync_delete AFTER DELETE ON sold_out_items
+BEGIN
+    REPLACE INTO sync_changes (entity_type, entity_id, version, data, changed_at)
+    SELECT 'sold_out_items', CAST(OLD.id AS TEXT), COALESCE(MAX(version), 0) + 1,
+        NULL, unixepoch()
+    FROM sync_changes;
+END;
+
+CREATE TRIGGER trg_floors_sync_insert AFTER INSERT ON floors
+BEGIN
+    REPLACE INTO sync_changes (entity_type, entity_id, version, data, changed_at)
+    SELECT 'floors', CAST(NEW.id AS TEXT), COALESCE(MAX(version), 0) + 1,
+        (SELECT data FROM floors_sync WHERE id = NEW.id), unixepoch()
+    FROM sync_changes;
+END;
+
+CREATE TRIGGER trg_floors_sync_update AFTER UPDATE ON floors
+WHEN (SELECT data FROM floors_sync WHERE id = NEW.id) IS NOT (
+    SELECT data FROM sync_changes
+    WHERE entity_type = 'floors' AND entity_id = CAST(NEW.id AS TEXT)
+)
+BEGIN
+    REPLACE INTO sync_changes (entity_type, entity_id, version, data, changed_at)
+    SELECT 'floors', CAST(NEW.id AS TEXT), COALESCE(MAX(version), 0) + 1,
+        (SELECT data FROM floors_sync WHERE id = NEW.id), unixepoch()
+    FROM sync_changes;
+END;
+
+CREATE TRIGGER trg_floors_sync_delete AFTER DELETE ON floors
+BEGIN
+    REPLACE INTO sync_changes (entity_type, entity_id, version, data, changed_at)
+    SELECT 'floors', CAST(OLD.id AS TEXT), COALESCE(MAX(version), 0) + 1,
+        NULL, unixepoch()
+    FROM sync_changes;
+END;
+
+CREATE TRIGGER trg_tables_sync_insert AFTER INSERT ON tables
+BEGIN
+    REPLACE INTO sync_changes (entity_type, entity_id, version, data, changed_at)
+    SELECT 'tables', CAST(NEW.id AS TEXT), COALESCE(MAX(version), 0) + 1,
+        (SELECT data FROM tables_sync WHERE id = NEW.id), unixepoch()
+    FROM sync_changes;
+END;
+
+CREATE TRIGGER trg_tables_sync_update AFTER UPDATE ON tables
+WHEN (SELECT data FROM tables_sync WHERE id = NEW.id) IS NOT (
+    SELECT data FROM sync_changes
+    WHERE entity_type = 'tables' AND entity_id = CAST(NEW.id AS TEXT)
+)
+BEGIN
+    REPLACE INTO sync_changes (entity_type, entity_id, version, data, changed_at)
+    SELECT 'tables', CAST(NEW.id AS TEXT), COALESCE(MAX(version), 0) + 1,
+        (SELECT data FROM tables_sync WHERE id = NEW.id), unixepoch()
+    FROM sync_changes;
+END;
+
+CREATE TRIGGER trg_tables_sync_delete AFTER DELETE ON tables
+BEGIN
+    REPLACE INTO sync_changes (entity_type, entity_id, version, data, changed_at)
+    SELECT 'tables', CAST(OLD.id AS TEXT), COALESCE(MAX(version), 0) + 1,
+        NULL, unixepoch()
+    FROM sync_changes;
+END;
+
+CREATE TRIGGER trg_rooms_sync_insert AFTER INSERT ON rooms
+BEGIN
+    REPLACE INTO sync_changes (entity_type, entity_id, version, data, changed_at)
+    SELECT 'rooms', CAST(NEW.id AS TEXT), COALESCE(MAX(version), 0) + 1,
+        (SELECT data FROM rooms_sync WHERE id = NEW.id), unixepoch()
+    FROM sync_changes;
+END;
+
+CREATE TRIGGER trg_rooms_sync_update AFTER UPDATE ON rooms
+WHEN (SELECT data FROM rooms_sync WHERE id = NEW.id) IS NOT (
+    SELECT data FROM sync_changes
+    WHERE entity_type = 'rooms' AND entity_id = CAST(NEW.id AS TEXT)
+)
+BEGIN
+    REPLACE INTO sync_changes (entity_type, entity_id, version, data, changed_at)
+    SELECT 'rooms', CAST(NEW.id AS TEXT), COALESCE(MAX(version), 0) + 1,
+        (SELECT data FROM rooms_sync WHERE id = NEW.id), unixepoch()
+    FROM sync_changes;
+END;
+
+CREATE TRIGGER trg_rooms_sync_delete AFTER DELETE ON rooms
+BEGIN
+    REPLACE INTO sync_changes (entity_type, entity_id, version, data, changed_at)
+    SELECT 'rooms', CAST(OLD.id AS TEXT), COALESCE(MAX(version), 0) + 1,
+        NULL, unixepoch()
+    FROM sync_changes;
+END;
+
+CREATE TRIGGER trg_store_prefs_sync_insert AFTER INSERT ON store_prefs
+BEGIN
+    REPLACE INTO sync_changes (entity_type, entity_id, version, data, changed_at)
+    SELECT 'store_prefs', CAST(NEW.key AS TEXT), COALESCE(MAX(version), 0) + 1,
+        (SELECT data FROM store_prefs_sync WHERE key = NEW.key), unixepoch()
+    FROM sync_changes;
+END;
+
+CREATE TRIGGER trg_store_prefs_sync_update AFTER UPDATE ON store_prefs
+WHEN (SELECT data FROM store_prefs_sync WHERE key = NEW.key) IS NOT (
+    SELECT data FROM sync_changes
+    WHERE entity_type = 'store_prefs' AND entity_id = CAST(NEW.key AS TEXT)
+)
+BEGIN
+    REPLACE INTO sync_changes (entity_type, entity_id, version, data, changed_at)
+    SELECT 'store_prefs', CAST(NEW.key AS TEXT), COALESCE(MAX(version), 0) + 1,
+        (SELECT data FROM store_prefs_sync WHERE key = NEW.key), unixepoch()
+    FROM sync_changes;
+END;
+
+CREATE TRIGGER trg_store_prefs_sync_delete AFTER DELETE ON store_prefs
+BEGIN
+    REPLACE INTO sync_changes (entity_type, entity_id, version, data, changed_at)
+    SELECT 'store_prefs', CAST(OLD.key AS TEXT), COALESCE(MAX(version), 0) + 1,
+        NULL, unixepoch()
+    FROM sync_changes;
+END;
+
+CREATE TRIGGER trg_store_shifts_sync_insert AFTER INSERT ON store_shifts
+BEGIN
+    REPLACE INTO sync_changes (entity_type, entity_id, version, data, changed_at)
+    SELECT 'store_shifts', CAST(NEW.id AS TEXT), COALESCE(MAX(version), 0) + 1,
+        (SELECT data FROM store_shifts_sync WHERE id = NEW.id), unixepoch()
+    FROM sync_changes;
+END;
+
+CREATE TRIGGER trg_store_shifts_sync_update AFTER UPDATE ON store_shifts
+WHEN (SELECT data FROM store_shifts_sync WHERE id = NEW.id) IS NOT (
+    SELECT data FROM sync_changes
+    WHERE entity_type = 'store_shifts' AND entity_id = CAST(NEW.id AS TEXT)
+)
+BEGIN
+    REPLACE INTO sync_changes (entity_type, entity_id, version, data, changed_at)
+    SELECT 'store_shifts', CAST(NEW.id AS TEXT), COALESCE(MAX(version), 0) + 1,
+        (SELECT data FROM store_shifts_sync WHERE id = NEW.id), unixepoch()
+    FROM sync_changes;
+END;
+
+CREATE TRIGGER trg_store_shifts_sync_delete AFTER DELETE ON store_shifts
+BEGIN
+    REPLACE INTO sync_changes (entity_type, entity_id, version, data, changed_at)
+    SELECT 'store_shifts', CAST(OLD.id AS TEXT), COALESCE(MAX(version), 0) + 1,
+        NULL, unixepoch()
+    FROM sync_changes;
+END;
@@ -0,0 +1,4 @@
+DELETE FROM role_permissions WHERE permission = 'order.write';
+DROP TABLE IF EXISTS order_payments;
+DROP TABLE IF EXISTS order_items;
+DROP TABLE IF EXISTS orders;
@@ -0,0 +1,63 @@
+-- orders are taken on the terminals, online or offline, and pushed with
+-- the uuid made by the terminal so a push can be sent again safely,
+-- version is raised on every accepted change and checksum is the
+-- last pushed order (a push with the same checksum change nothing),
+-- created_at and updated_at are the terminal clock, synced_at is the server
+CREATE TABLE IF NOT EXISTS orders (
+    id VARCHAR(36) PRIMARY KEY NOT NULL,
+    terminal_id BIGINT,
+    table_id BIGINT,
+    room_id BIGINT,
+    status VARCHAR(10) NOT NULL DEFAULT 'open' CHECK (status IN ('open', 'paid', 'void')),
+    currency VARCHAR(3) NOT NULL,
+    total BIGINT NOT NULL DEFAULT 0,
+    version INT NOT NULL DEFAULT 1,
+    checksum VARCHAR(64) NOT NULL,
+    created_by BIGINT,
+    created_at BIGINT NOT NULL,
+    updated_at BIGINT,
+    synced_at BIGINT NOT NULL DEFAULT (unixepoch()),
+    CONSTRAINT fk_terminals_orders FOREIGN KEY (terminal_id) REFERENCES terminals(id),
+    CONSTRAINT fk_tables_orders FOREIGN KEY (table_id) REFERENCES tables(id),
+    CONSTRAINT fk_rooms_orders FOREIGN KEY (room_id) REFERENCES rooms(id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_orders_created_at ON orders (created_at);
+
+-- product_id and name are kept as sold (the product may be deleted later),
+-- void_reason is the conflict the item was voided for when it was pushed
+CREATE TABLE IF NOT EXISTS order_items (
+    id VARCHAR(36) PRIMARY KEY NOT NULL,
+    order_id VARCHAR(36) NOT NULL,
+    position INT NOT NULL,
+    product_id BIGINT NOT NULL,
+    variant_id BIGINT,
+    name VARCHAR(255) NOT NULL,
+    quantity INT NOT NULL CHECK (quantity > 0),
+    price BIGINT NOT NULL CHECK (price >= 0),
+    note VARCHAR(255),
+    void_reason VARCHAR(20),
+    CONSTRAINT fk_orders_order_items
+        FOREIGN KEY (order_id) REFERENCES orders(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_order_items_order ON order_items (order_id, position);
+
+-- payments are never changed, a payment pushed again is ignored
+CREATE TABLE IF NOT EXISTS order_payments (
+    id VARCHAR(36) PRIMARY KEY NOT NULL,
+    order_id VARCHAR(36) NOT NULL,
+    method VARCHAR(10) NOT NULL CHECK (method IN ('cash', 'card', 'transfer', 'other')),
+    amount BIGINT NOT NULL CHECK (amount > 0),
+    reference VARCHAR(255),
+    created_by BIGINT,
+    created_at BIGINT NOT NULL,
+    synced_at BIGINT NOT NULL DEFAULT (unixepoch()),
+    CONSTRAINT fk_orders_order_payments FOREIGN KEY (order_id) REFERENCES orders(id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_order_payments_order ON order_payments (order_id);
+
+INSERT INTO role_permissions (role_id, permission)
+SELECT roles.id, 'order.write' FROM roles
+WHERE roles.name IN ('admin', 'cashier', 'waiter') ON CONFLICT DO NOTHING;
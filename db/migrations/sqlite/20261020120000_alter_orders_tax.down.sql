@@ -0,0 +1,3 @@
+ALTER TABLE orders DROP COLUMN tax_summary;
+ALTER TABLE orders DROP COLUMN tax;
+ALTER TABLE orders DROP COLUMN service_charge;
@@ -0,0 +1,6 @@
+-- total of an order is calculated by the server with the tax classes when
+-- the order is pushed, service_charge and tax are kept for the reports and
+-- tax_summary is the per-rate breakdown for the invoice
+ALTER TABLE orders ADD COLUMN service_charge BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE orders ADD COLUMN tax BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE orders ADD COLUMN tax_summary TEXT CHECK (tax_summary IS NULL OR json_valid(tax_summary));
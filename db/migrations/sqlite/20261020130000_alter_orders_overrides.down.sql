@@ -0,0 +1,10 @@
+DROP TABLE IF EXISTS order_refunds;
+ALTER TABLE order_items DROP COLUMN price_approved_by;
+ALTER TABLE orders DROP COLUMN voided_at;
+ALTER TABLE orders DROP COLUMN void_approved_by;
+ALTER TABLE orders DROP COLUMN voided_by;
+ALTER TABLE orders DROP COLUMN void_reason;
+ALTER TABLE orders DROP COLUMN discount_approved_by;
+ALTER TABLE orders DROP COLUMN no_service;
+ALTER TABLE orders DROP COLUMN tax_exempt;
+ALTER TABLE orders DROP COLUMN discount;
@@ -0,0 +1,29 @@
+-- the overrides taken on an order on the server keep the user who approved
+-- them (the user itself when its role is granted the action), discount is
+-- taken off the items before tax, tax_exempt and no_service are passed on
+-- to the tax calculation, a void keep its reason, who voided it and when
+ALTER TABLE orders ADD COLUMN discount BIGINT NOT NULL DEFAULT 0 CHECK (discount >= 0);
+ALTER TABLE orders ADD COLUMN tax_exempt BOOLEAN NOT NULL DEFAULT false;
+ALTER TABLE orders ADD COLUMN no_service BOOLEAN NOT NULL DEFAULT false;
+ALTER TABLE orders ADD COLUMN discount_approved_by BIGINT;
+ALTER TABLE orders ADD COLUMN void_reason VARCHAR(255);
+ALTER TABLE orders ADD COLUMN voided_by BIGINT;
+ALTER TABLE orders ADD COLUMN void_approved_by BIGINT;
+ALTER TABLE orders ADD COLUMN voided_at BIGINT;
+
+-- a price set by hand (price override) is kept by the next pushes
+ALTER TABLE order_items ADD COLUMN price_approved_by BIGINT;
+
+-- money given back on a paid order, never more than what was paid
+CREATE TABLE IF NOT EXISTS order_refunds (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    order_id VARCHAR(36) NOT NULL,
+    amount BIGINT NOT NULL CHECK (amount > 0),
+    reason VARCHAR(255) NOT NULL,
+    refunded_by BIGINT,
+    approved_by BIGINT,
+    created_at BIGINT NOT NULL DEFAULT (unixepoch()),
+    CONSTRAINT fk_orders_order_refunds FOREIGN KEY (order_id) REFERENCES orders(id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_order_refunds_order ON order_refunds (order_id);
@@ -0,0 +1,13 @@
+-- demo staff to try the desktop client, password is secret
+WITH demo (name, username, email, phone, role) AS (
+    VALUES
+        ('Demo Cashier', 'cashier', 'cashier@store.id', 81200000001, 'cashier'),
+        ('Demo Waiter', 'waiter', 'waiter@store.id', 81200000002, 'waiter')
+)
+INSERT INTO users (role_id, name, username, email, phone, password)
+SELECT roles.id, demo.name, demo.username, demo.email, demo.phone,
+       '2ad1a22d5b3c9396d16243d2fe7f067976363715e322203a456278bb80b0b4a4.7ab4dcccfcd9d36efc68f1626d2fb80804a6508f9c3a7b44f430ba082b6870d2'
+FROM demo
+JOIN roles ON roles.name = demo.role AND roles.deleted_at IS NULL
+WHERE true -- sqlite need a WHERE before ON CONFLICT of INSERT ... SELECT
+ON CONFLICT DO NOTHING;
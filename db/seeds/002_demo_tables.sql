@@ -0,0 +1,11 @@
+-- more tables on the 1st floor
+WITH demo (name, x_pos, capacity) AS (
+    VALUES ('A2', 5, 2), ('A3', 10, 4), ('A4', 15, 6)
+)
+INSERT INTO tables (floor_id, name, x_pos, y_pos, w_size, h_size, capacity)
+SELECT floors.id, demo.name, demo.x_pos, 0, 4, 4, demo.capacity
+FROM demo
+JOIN floors ON floors.name = '1st' AND floors.deleted_at IS NULL
+WHERE NOT EXISTS (
+    SELECT 1 FROM tables WHERE tables.name = demo.name AND tables.deleted_at IS NULL
+);
@@ -0,0 +1,142 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/aasumitro/posbe/pkg/http/middleware"
+	"github.com/aasumitro/posbe/pkg/model"
+	"github.com/aasumitro/posbe/pkg/utils"
+	"github.com/gin-gonic/gin"
+)
+
+type apiKeyHandler struct {
+	svc model.IAPIKeyService
+}
+
+// api keys godoc
+// @Schemes
+// @Summary API Key List
+// @Description Get api keys of machine integrations, the keys are never returned here.
+// @Tags API Keys
+// @Accept json
+// @Produce json
+// @Success 200 {object} utils.SuccessRespond{data=[]model.APIKey} "OK RESPOND"
+// @Failure 401 {object} utils.ErrorRespond "UNAUTHORIZED RESPOND"
+// @Failure 500 {object} utils.ErrorRespond "INTERNAL SERVER ERROR RESPOND"
+// @Router /api/v1/api-keys [GET]
+func (handler apiKeyHandler) fetch(ctx *gin.Context) {
+	keys, err := handler.svc.APIKeyList(ctx)
+	if err != nil {
+		utils.NewHTTPRespond(ctx, err.Code, err.Message)
+		return
+	}
+	utils.NewHTTPRespond(ctx, http.StatusOK, keys)
+}
+
+// api keys godoc
+// @Schemes
+// @Summary Store API Key Data
+// @Description Issue api key granted to the scopes, the key is only returned here and sent as X-API-Key header.
+// @Description The scopes must be granted to the creator.
+// @Tags API Keys
+// @Accept json
+// @Produce json
+// @Param body body model.APIKey true "name, scopes and optional expires_at"
+// @Success 201 {object} utils.SuccessRespond{data=model.APIKey} "CREATED RESPOND"
+// @Failure 401 {object} utils.ErrorRespond "UNAUTHORIZED RESPOND"
+// @Failure 403 {object} utils.ErrorRespond "FORBIDDEN RESPOND"
+// @Failure 422 {object} utils.ValidationErrorRespond "UNPROCESSABLE ENTITY RESPOND"
+// @Failure 500 {object} utils.ErrorRespond "INTERNAL SERVER ERROR RESPOND"
+// @Router /api/v1/api-keys [POST]
+func (handler apiKeyHandler) store(ctx *gin.Context) {
+	var form model.APIKey
+	if err := ctx.ShouldBindJSON(&form); err != nil {
+		utils.NewHTTPRespond(ctx, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+	form.CreatedBy = middleware.PayloadUserID(ctx)
+	permissions, errData := middleware.GrantedPermissions(ctx)
+	if errData != nil {
+		utils.NewHTTPRespond(ctx, errData.Code, errData.Message)
+		return
+	}
+	form.CreatorPermissions = permissions
+	key, err := handler.svc.AddAPIKey(ctx, &form)
+	if err != nil {
+		utils.NewHTTPRespond(ctx, err.Code, err.Message)
+		return
+	}
+	utils.NewHTTPRespond(ctx, http.StatusCreated, key)
+}
+
+// api keys godoc
+// @Schemes
+// @Summary Rotate API Key
+// @Description Issue new key for the api key, the current key keep working for a day.
+// @Tags API Keys
+// @Accept json
+// @Produce json
+// @Param id path int true "api key id"
+// @Success 200 {object} utils.SuccessRespond{data=model.APIKey} "OK RESPOND"
+// @Failure 400 {object} utils.ErrorRespond "BAD REQUEST RESPOND"
+// @Failure 401 {object} utils.ErrorRespond "UNAUTHORIZED RESPOND"
+// @Failure 404 {object} utils.ErrorRespond "NOT FOUND RESPOND"
+// @Failure 500 {object} utils.ErrorRespond "INTERNAL SERVER ERROR RESPOND"
+// @Router /api/v1/api-keys/{id}/rotate [POST]
+func (handler apiKeyHandler) rotate(ctx *gin.Context) {
+	idParams := ctx.Param("id")
+	id, errParse := strconv.Atoi(idParams)
+	if errParse != nil {
+		utils.NewHTTPRespond(ctx,
+			http.StatusBadRequest,
+			errParse.Error())
+		return
+	}
+	key, err := handler.svc.RotateAPIKey(ctx, id)
+	if err != nil {
+		utils.NewHTTPRespond(ctx, err.Code, err.Message)
+		return
+	}
+	utils.NewHTTPRespond(ctx, http.StatusOK, key)
+}
+
+// api keys godoc
+// @Schemes
+// @Summary Revoke API Key
+// @Description Revoke api key by ID, the current and rotated key stop working.
+// @Tags API Keys
+// @Accept json
+// @Produce json
+// @Param id path int true "api key id"
+// @Success 204 "NO CONTENT RESPOND"
+// @Failure 400 {object} utils.ErrorRespond "BAD REQUEST RESPOND"
+// @Failure 401 {object} utils.ErrorRespond "UNAUTHORIZED RESPOND"
+// @Failure 404 {object} utils.ErrorRespond "NOT FOUND RESPOND"
+// @Failure 500 {object} utils.ErrorRespond "INTERNAL SERVER ERROR RESPOND"
+// @Router /api/v1/api-keys/{id} [DELETE]
+func (handler apiKeyHandler) destroy(ctx *gin.Context) {
+	idParams := ctx.Param("id")
+	id, errParse := strconv.Atoi(idParams)
+	if errParse != nil {
+		utils.NewHTTPRespond(ctx,
+			http.StatusBadRequest,
+			errParse.Error())
+		return
+	}
+	if err := handler.svc.RevokeAPIKey(ctx, id); err != nil {
+		utils.NewHTTPRespond(ctx, err.Code, err.Message)
+		return
+	}
+	utils.NewHTTPRespond(ctx, http.StatusNoContent, nil)
+}
+
+func NewAPIKeyHandler(apiKeyService model.IAPIKeyService, router gin.IRoutes) {
+	handler := apiKeyHandler{svc: apiKeyService}
+	read := middleware.Permitted(model.PermissionAccountUserRead)
+	write := middleware.Permitted(model.PermissionAccountAPIKeyWrite)
+	router.GET("/api-keys", read, handler.fetch)
+	router.POST("/api-keys", write, handler.store)
+	router.POST("/api-keys/:id/rotate", write, handler.rotate)
+	router.DELETE("/api-keys/:id", write, handler.destroy)
+}
@@ -4,6 +4,7 @@ import (
 	"net/http"
 	"time"
 
+	"github.com/aasumitro/posbe/common"
 	"github.com/aasumitro/posbe/config"
 
 	"github.com/aasumitro/posbe/pkg/http/middleware"
@@ -12,21 +13,27 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
+const (
+	accessTokenCookie  = "jwt"
+	refreshTokenCookie = "refresh_token"
+)
+
 type authHandler struct {
-	svc model.IAccountService
-	jwt utils.IJSONWebToken
+	svc        model.IAccountService
+	sessionSvc model.ISessionService
 }
 
 // login godoc
 // @Schemes
 // @Summary Logged User In
-// @Description Generate Access Token (JWT).
+// @Description Generate short-lived Access Token (JWT) and rotating Refresh Token,
+// @Description both are set as cookie and returned along with the user.
 // @Tags Account Auth
 // @Accept mpfd
 // @Produce json
 // @Param username formData string true "username"
 // @Param password formData string true "password"
-// @Success 201 {object} utils.SuccessRespond{Data=domain.User} "CREATED_RESPOND"
+// @Success 201 {object} utils.SuccessRespond{Data=model.AuthSession} "CREATED_RESPOND"
 // @Failure 400 {object} utils.ErrorRespond "BAD_REQUEST_RESPOND"
 // @Failure 422 {object} utils.ValidationErrorRespond "UNPROCESSABLE_ENTITY_RESPOND"
 // @Failure 500 {object} utils.ErrorRespond "INTERNAL_SERVER_ERROR_RESPOND"
@@ -43,50 +50,120 @@ func (handler authHandler) login(ctx *gin.Context) {
 		utils.NewHTTPRespond(ctx, err.Code, err.Message)
 		return
 	}
-	token, claimErr := handler.jwt.ClaimJWTToken(data)
-	if claimErr != nil {
-		utils.NewHTTPRespond(ctx, http.StatusInternalServerError, claimErr.Error())
+	user, _ := data.(*model.User)
+	session, err := handler.sessionSvc.Start(ctx,
+		&model.AuthSession{User: user},
+		deviceName(ctx), ctx.ClientIP())
+	if err != nil {
+		utils.NewHTTPRespond(ctx, err.Code, err.Message)
 		return
 	}
-	http.SetCookie(ctx.Writer, &http.Cookie{
-		Name:   "jwt",
-		Value:  token,
-		MaxAge: 0,
-		Path:   "/",
-		// Secure:   true,
-		HttpOnly: true,
-	})
-	utils.NewHTTPRespond(ctx, http.StatusCreated, data)
+	setTokenCookie(ctx, session)
+	utils.NewHTTPRespond(ctx, http.StatusCreated, session)
+}
+
+// refresh godoc
+// @Schemes
+// @Summary Refresh Access Token
+// @Description Exchange the refresh token (body or cookie) for new tokens, the refresh token is rotated
+// @Description and using a rotated one again revoke the session.
+// @Tags Account Auth
+// @Accept json
+// @Produce json
+// @Param body body model.RefreshForm false "refresh token, read from cookie when empty"
+// @Success 201 {object} utils.SuccessRespond{Data=model.AuthSession} "CREATED_RESPOND"
+// @Failure 401 {object} utils.ErrorRespond "UNAUTHORIZED_RESPOND"
+// @Failure 500 {object} utils.ErrorRespond "INTERNAL_SERVER_ERROR_RESPOND"
+// @Router /api/v1/refresh [POST]
+func (handler authHandler) refresh(ctx *gin.Context) {
+	var form model.RefreshForm
+	_ = ctx.ShouldBind(&form)
+	if form.RefreshToken == "" {
+		form.RefreshToken, _ = ctx.Cookie(refreshTokenCookie)
+	}
+	session, err := handler.sessionSvc.Refresh(ctx,
+		form.RefreshToken, deviceName(ctx), ctx.ClientIP())
+	if err != nil {
+		utils.NewHTTPRespond(ctx, err.Code, err.Message)
+		return
+	}
+	setTokenCookie(ctx, session)
+	utils.NewHTTPRespond(ctx, http.StatusCreated, session)
 }
 
 // logout godoc
 // @Schemes
 // @Summary Logged User Out
-// @Description Remove JWT Cookie
+// @Description Revoke the current session and remove the token cookies.
 // @Tags Account Auth
 // @Accept mpfd
 // @Produce json
 // @Success 200 {object} utils.SuccessRespond "CREATED_RESPOND"
+// @Failure 401 {object} utils.ErrorRespond "UNAUTHORIZED_RESPOND"
 // @Router /api/v1/logout [POST]
 func (handler authHandler) logout(ctx *gin.Context) {
+	err := handler.sessionSvc.Revoke(ctx,
+		middleware.PayloadUserID(ctx),
+		middleware.PayloadSessionID(ctx))
+	if err != nil && err.Code != http.StatusNotFound {
+		utils.NewHTTPRespond(ctx, err.Code, err.Message)
+		return
+	}
+	for _, name := range []string{accessTokenCookie, refreshTokenCookie} {
+		http.SetCookie(ctx.Writer, &http.Cookie{
+			Name:    name,
+			Value:   "",
+			MaxAge:  0,
+			Path:    "/",
+			Expires: time.Now().Add(-time.Hour),
+		})
+	}
+	utils.NewHTTPRespond(ctx, http.StatusOK, "LOGGED_OUT")
+}
+
+func setTokenCookie(ctx *gin.Context, session *model.AuthSession) {
 	http.SetCookie(ctx.Writer, &http.Cookie{
-		Name:    "jwt",
-		Value:   "",
-		MaxAge:  0,
-		Path:    "/",
-		Expires: time.Now().Add(-time.Hour),
+		Name:   accessTokenCookie,
+		Value:  session.AccessToken,
+		MaxAge: 0,
+		Path:   "/",
+		// Secure:   true,
+		HttpOnly: true,
+	})
+	http.SetCookie(ctx.Writer, &http.Cookie{
+		Name:   refreshTokenCookie,
+		Value:  session.RefreshToken,
+		MaxAge: config.Instance.JWTLifetime * int(time.Hour/time.Second),
+		Path:   "/",
+		// Secure:   true,
+		HttpOnly: true,
 	})
-	utils.NewHTTPRespond(ctx, http.StatusOK, "LOGGED_OUT")
 }
 
-func NewAuthHandler(accountService model.IAccountService, router *gin.RouterGroup) {
-	handler := authHandler{svc: accountService, jwt: &utils.JSONWebToken{
+// deviceName is shown in the session list, the client may name itself
+func deviceName(ctx *gin.Context) string {
+	if name := ctx.GetHeader("X-Device-Name"); name != "" {
+		return name
+	}
+	return ctx.Request.UserAgent()
+}
+
+// NewJSONWebToken claim short-lived access tokens
+func NewJSONWebToken() utils.IJSONWebToken {
+	return &utils.JSONWebToken{
 		Issuer:    config.Instance.AppName,
 		SecretKey: []byte(config.Instance.JWTSecretKey),
-		IssuedAt:  time.Now(),
-		ExpiredAt: time.Now().Add(time.Duration(config.Instance.JWTLifetime) * time.Hour),
-	}}
+		Lifetime:  common.AccessTokenLifetime * time.Second,
+	}
+}
+
+func NewAuthHandler(
+	accountService model.IAccountService,
+	sessionService model.ISessionService,
+	router *gin.RouterGroup,
+) {
+	handler := authHandler{svc: accountService, sessionSvc: sessionService}
 	router.POST("/login", handler.login)
-	protectedRouter := router.Use(middleware.Auth())
-	protectedRouter.POST("/logout", handler.logout)
+	router.POST("/refresh", handler.refresh)
+	router.POST("/logout", middleware.Auth(), handler.logout)
 }
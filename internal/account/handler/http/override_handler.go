@@ -0,0 +1,89 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/aasumitro/posbe/pkg/http/middleware"
+	"github.com/aasumitro/posbe/pkg/model"
+	"github.com/aasumitro/posbe/pkg/utils"
+	"github.com/gin-gonic/gin"
+)
+
+type overrideHandler struct {
+	svc model.IOverrideService
+}
+
+// overrides godoc
+// @Schemes
+// @Summary Approve Override
+// @Description Approve one action with the supervisor pin, the returned token is single-use,
+// @Description short-lived and sent as X-Override-Token header along with the action.
+// @Tags Overrides
+// @Accept json
+// @Produce json
+// @Param body body model.OverrideForm true "approver, pin and action"
+// @Success 201 {object} utils.SuccessRespond{data=model.Override} "CREATED RESPOND"
+// @Failure 401 {object} utils.ErrorRespond "UNAUTHORIZED RESPOND"
+// @Failure 403 {object} utils.ErrorRespond "FORBIDDEN RESPOND"
+// @Failure 422 {object} utils.ValidationErrorRespond "UNPROCESSABLE ENTITY RESPOND"
+// @Failure 500 {object} utils.ErrorRespond "INTERNAL SERVER ERROR RESPOND"
+// @Router /api/v1/overrides [POST]
+func (handler overrideHandler) approve(ctx *gin.Context) {
+	var form model.OverrideForm
+	if err := ctx.ShouldBind(&form); err != nil {
+		utils.NewHTTPRespond(ctx, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+	form.RequestedBy = middleware.PayloadUserID(ctx)
+	override, err := handler.svc.Approve(ctx, &form)
+	if err != nil {
+		utils.NewHTTPRespond(ctx, err.Code, err.Message)
+		return
+	}
+	utils.NewHTTPRespond(ctx, http.StatusCreated, override)
+}
+
+// overrides godoc
+// @Schemes
+// @Summary Set User PIN
+// @Description Set the pin the user approve overrides with.
+// @Tags Overrides
+// @Accept json
+// @Produce json
+// @Param id   path int 		  true "user id"
+// @Param body body model.PINForm true "4 to 6 digits pin"
+// @Success 204 "NO CONTENT RESPOND"
+// @Failure 400 {object} utils.ErrorRespond "BAD REQUEST RESPOND"
+// @Failure 401 {object} utils.ErrorRespond "UNAUTHORIZED RESPOND"
+// @Failure 404 {object} utils.ErrorRespond "NOT FOUND RESPOND"
+// @Failure 422 {object} utils.ValidationErrorRespond "UNPROCESSABLE ENTITY RESPOND"
+// @Failure 500 {object} utils.ErrorRespond "INTERNAL SERVER ERROR RESPOND"
+// @Router /api/v1/users/{id}/pin [PUT]
+func (handler overrideHandler) pin(ctx *gin.Context) {
+	idParams := ctx.Param("id")
+	id, errParse := strconv.Atoi(idParams)
+	if errParse != nil {
+		utils.NewHTTPRespond(ctx,
+			http.StatusBadRequest,
+			errParse.Error())
+		return
+	}
+	var form model.PINForm
+	if err := ctx.ShouldBind(&form); err != nil {
+		utils.NewHTTPRespond(ctx, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+	if err := handler.svc.SetPIN(ctx, id, form.PIN); err != nil {
+		utils.NewHTTPRespond(ctx, err.Code, err.Message)
+		return
+	}
+	utils.NewHTTPRespond(ctx, http.StatusNoContent, nil)
+}
+
+func NewOverrideHandler(overrideService model.IOverrideService, router gin.IRoutes) {
+	handler := overrideHandler{svc: overrideService}
+	write := middleware.Permitted(model.PermissionAccountUserWrite)
+	router.POST("/overrides", handler.approve)
+	router.PUT("/users/:id/pin", write, handler.pin)
+}
@@ -43,6 +43,7 @@ func (handler roleHandler) fetch(ctx *gin.Context) {
 // @Produce json
 // @Param name formData string true "name"
 // @Param description formData string true "description"
+// @Param permissions formData []string false "granted permissions" collectionFormat(multi)
 // @Success 201 {object} utils.SuccessRespond{data=model.Role} "CREATED RESPOND"
 // @Failure 401 {object} utils.ErrorRespond "UNAUTHORIZED RESPOND"
 // @Failure 422 {object} utils.ValidationErrorRespond "UNPROCESSABLE ENTITY RESPOND"
@@ -72,6 +73,7 @@ func (handler roleHandler) store(ctx *gin.Context) {
 // @Param id path int true "role id"
 // @Param name formData string true "name"
 // @Param description formData string true "description"
+// @Param permissions formData []string false "granted permissions, replace the current permissions" collectionFormat(multi)
 // @Success 200 {object} utils.SuccessRespond{data=model.Role} "CREATED RESPOND"
 // @Failure 400 {object} utils.ErrorRespond "BAD REQUEST RESPOND"
 // @Failure 401 {object} utils.ErrorRespond "UNAUTHORIZED RESPOND"
@@ -109,9 +111,13 @@ func (handler roleHandler) update(ctx *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param id path int true "role id"
+// @Param reassign_to query int false "role id that take over the users"
 // @Success 204 "NO CONTENT RESPOND"
 // @Failure 400 {object} utils.ErrorRespond "BAD REQUEST RESPOND"
 // @Failure 401 {object} utils.ErrorRespond "UNAUTHORIZED RESPOND"
+// @Failure 404 {object} utils.ErrorRespond "NOT FOUND RESPOND"
+// @Failure 409 {object} utils.ValidationErrorRespond{data=model.DeleteConflict} "CONFLICT RESPOND"
+// @Failure 422 {object} utils.ValidationErrorRespond "UNPROCESSABLE ENTITY RESPOND"
 // @Failure 500 {object} utils.ErrorRespond "INTERNAL SERVER ERROR RESPOND"
 // @Router /api/v1/roles/{id} [DELETE]
 func (handler roleHandler) destroy(ctx *gin.Context) {
@@ -132,12 +138,67 @@ func (handler roleHandler) destroy(ctx *gin.Context) {
 	utils.NewHTTPRespond(ctx, http.StatusNoContent, nil)
 }
 
+// roles godoc
+// @Schemes
+// @Summary Restore Role Data
+// @Description Restore deleted role by ID.
+// @Tags Users Roles
+// @Accept json
+// @Produce json
+// @Param id path int true "role id"
+// @Success 200 {object} utils.SuccessRespond{data=model.Role} "OK RESPOND"
+// @Failure 400 {object} utils.ErrorRespond "BAD REQUEST RESPOND"
+// @Failure 401 {object} utils.ErrorRespond "UNAUTHORIZED RESPOND"
+// @Failure 403 {object} utils.ErrorRespond "FORBIDDEN RESPOND"
+// @Failure 404 {object} utils.ErrorRespond "NOT FOUND RESPOND"
+// @Failure 500 {object} utils.ErrorRespond "INTERNAL SERVER ERROR RESPOND"
+// @Router /api/v1/roles/{id}/restore [PATCH]
+func (handler roleHandler) restore(ctx *gin.Context) {
+	idParams := ctx.Param("id")
+	id, errParse := strconv.Atoi(idParams)
+	if errParse != nil {
+		utils.NewHTTPRespond(ctx,
+			http.StatusBadRequest,
+			errParse.Error())
+		return
+	}
+	data, err := handler.svc.RestoreRole(ctx, &model.Role{ID: id})
+	if err != nil {
+		utils.NewHTTPRespond(ctx, err.Code, err.Message)
+		return
+	}
+	utils.NewHTTPRespond(ctx, http.StatusOK, data)
+}
+
+// permissions godoc
+// @Schemes
+// @Summary Permission List
+// @Description Get every permission that can be granted to a role.
+// @Tags Users Roles
+// @Accept json
+// @Produce json
+// @Success 200 {object} utils.SuccessRespond{data=[]model.Permission} "OK RESPOND"
+// @Failure 401 {object} utils.ErrorRespond "UNAUTHORIZED RESPOND"
+// @Failure 403 {object} utils.ErrorRespond "FORBIDDEN RESPOND"
+// @Router /api/v1/permissions [GET]
+func (handler roleHandler) permissions(ctx *gin.Context) {
+	permissions, err := handler.svc.PermissionList(ctx)
+	if err != nil {
+		utils.NewHTTPRespond(ctx, err.Code, err.Message)
+		return
+	}
+	utils.NewHTTPRespond(ctx, http.StatusOK, permissions)
+}
+
 func NewRoleHandler(accountService model.IAccountService, router gin.IRoutes) {
 	handler := roleHandler{svc: accountService}
-	router.GET("/roles", handler.fetch)
-	protectedRoute := router.Use(middleware.
-		AcceptedRoles([]string{"admin"}))
-	protectedRoute.POST("/roles", handler.store)
-	protectedRoute.PUT("/roles/:id", handler.update)
-	protectedRoute.DELETE("/roles/:id", handler.destroy)
+	read := middleware.Permitted(model.PermissionAccountUserRead)
+	write := middleware.Permitted(model.PermissionAccountRoleWrite)
+	trash := middleware.Permitted(model.PermissionTrashRestore)
+	router.GET("/roles", read, handler.fetch)
+	router.POST("/roles", write, handler.store)
+	router.PUT("/roles/:id", write, handler.update)
+	router.DELETE("/roles/:id", write, handler.destroy)
+	router.PATCH("/roles/:id/restore", trash, handler.restore)
+	router.GET("/permissions", read, handler.permissions)
 }
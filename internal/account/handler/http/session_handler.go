@@ -0,0 +1,118 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/aasumitro/posbe/pkg/http/middleware"
+	"github.com/aasumitro/posbe/pkg/model"
+	"github.com/aasumitro/posbe/pkg/utils"
+	"github.com/gin-gonic/gin"
+)
+
+type sessionHandler struct {
+	svc model.ISessionService
+}
+
+// sessions godoc
+// @Schemes
+// @Summary Session List
+// @Description Get logged in devices of the current user.
+// @Tags Sessions
+// @Accept json
+// @Produce json
+// @Success 200 {object} utils.SuccessRespond{data=[]model.Session} "OK RESPOND"
+// @Failure 401 {object} utils.ErrorRespond "UNAUTHORIZED RESPOND"
+// @Failure 500 {object} utils.ErrorRespond "INTERNAL SERVER ERROR RESPOND"
+// @Router /api/v1/sessions [GET]
+func (handler sessionHandler) fetch(ctx *gin.Context) {
+	sessions, err := handler.svc.SessionList(ctx,
+		middleware.PayloadUserID(ctx),
+		middleware.PayloadSessionID(ctx))
+	if err != nil {
+		utils.NewHTTPRespond(ctx, err.Code, err.Message)
+		return
+	}
+	utils.NewHTTPRespond(ctx, http.StatusOK, sessions)
+}
+
+// sessions godoc
+// @Schemes
+// @Summary Revoke Session
+// @Description Log the current user out of one device.
+// @Tags Sessions
+// @Accept json
+// @Produce json
+// @Param id path string true "session id"
+// @Success 204 "NO CONTENT RESPOND"
+// @Failure 401 {object} utils.ErrorRespond "UNAUTHORIZED RESPOND"
+// @Failure 404 {object} utils.ErrorRespond "NOT FOUND RESPOND"
+// @Failure 500 {object} utils.ErrorRespond "INTERNAL SERVER ERROR RESPOND"
+// @Router /api/v1/sessions/{id} [DELETE]
+func (handler sessionHandler) destroy(ctx *gin.Context) {
+	if err := handler.svc.Revoke(ctx,
+		middleware.PayloadUserID(ctx),
+		ctx.Param("id"),
+	); err != nil {
+		utils.NewHTTPRespond(ctx, err.Code, err.Message)
+		return
+	}
+	utils.NewHTTPRespond(ctx, http.StatusNoContent, nil)
+}
+
+// sessions godoc
+// @Schemes
+// @Summary Revoke All Sessions
+// @Description Log the current user out of every device, include this one.
+// @Tags Sessions
+// @Accept json
+// @Produce json
+// @Success 204 "NO CONTENT RESPOND"
+// @Failure 401 {object} utils.ErrorRespond "UNAUTHORIZED RESPOND"
+// @Failure 500 {object} utils.ErrorRespond "INTERNAL SERVER ERROR RESPOND"
+// @Router /api/v1/sessions [DELETE]
+func (handler sessionHandler) destroyAll(ctx *gin.Context) {
+	if err := handler.svc.RevokeAll(ctx, middleware.PayloadUserID(ctx)); err != nil {
+		utils.NewHTTPRespond(ctx, err.Code, err.Message)
+		return
+	}
+	utils.NewHTTPRespond(ctx, http.StatusNoContent, nil)
+}
+
+// sessions godoc
+// @Schemes
+// @Summary Revoke User Sessions
+// @Description Log the user out of every device (e.g. lost device or leaving staff).
+// @Tags Sessions
+// @Accept json
+// @Produce json
+// @Param id path int true "user id"
+// @Success 204 "NO CONTENT RESPOND"
+// @Failure 400 {object} utils.ErrorRespond "BAD REQUEST RESPOND"
+// @Failure 401 {object} utils.ErrorRespond "UNAUTHORIZED RESPOND"
+// @Failure 500 {object} utils.ErrorRespond "INTERNAL SERVER ERROR RESPOND"
+// @Router /api/v1/users/{id}/sessions [DELETE]
+func (handler sessionHandler) destroyUser(ctx *gin.Context) {
+	idParams := ctx.Param("id")
+	id, errParse := strconv.Atoi(idParams)
+	if errParse != nil {
+		utils.NewHTTPRespond(ctx,
+			http.StatusBadRequest,
+			errParse.Error())
+		return
+	}
+	if err := handler.svc.RevokeAll(ctx, id); err != nil {
+		utils.NewHTTPRespond(ctx, err.Code, err.Message)
+		return
+	}
+	utils.NewHTTPRespond(ctx, http.StatusNoContent, nil)
+}
+
+func NewSessionHandler(sessionService model.ISessionService, router gin.IRoutes) {
+	handler := sessionHandler{svc: sessionService}
+	userWrite := middleware.Permitted(model.PermissionAccountUserWrite)
+	router.GET("/sessions", handler.fetch)
+	router.DELETE("/sessions", handler.destroyAll)
+	router.DELETE("/sessions/:id", handler.destroy)
+	router.DELETE("/users/:id/sessions", userWrite, handler.destroyUser)
+}
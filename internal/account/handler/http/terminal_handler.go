@@ -0,0 +1,279 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/aasumitro/posbe/pkg/http/middleware"
+	"github.com/aasumitro/posbe/pkg/model"
+	"github.com/aasumitro/posbe/pkg/utils"
+	"github.com/gin-gonic/gin"
+)
+
+const terminalKeyHeader = "X-Terminal-Key"
+
+type terminalHandler struct {
+	svc model.ITerminalService
+}
+
+// terminals godoc
+// @Schemes
+// @Summary Terminal List
+// @Description Get shared terminals.
+// @Tags Terminals
+// @Accept json
+// @Produce json
+// @Success 200 {object} utils.SuccessRespond{data=[]model.Terminal} "OK RESPOND"
+// @Failure 401 {object} utils.ErrorRespond "UNAUTHORIZED RESPOND"
+// @Failure 500 {object} utils.ErrorRespond "INTERNAL SERVER ERROR RESPOND"
+// @Router /api/v1/terminals [GET]
+func (handler terminalHandler) fetch(ctx *gin.Context) {
+	terminals, err := handler.svc.TerminalList(ctx)
+	if err != nil {
+		utils.NewHTTPRespond(ctx, err.Code, err.Message)
+		return
+	}
+	utils.NewHTTPRespond(ctx, http.StatusOK, terminals)
+}
+
+// terminals godoc
+// @Schemes
+// @Summary Store Terminal Data
+// @Description Register shared terminal, the key is only returned here and sent as X-Terminal-Key header.
+// @Tags Terminals
+// @Accept mpfd
+// @Produce json
+// @Param name 			formData string true "terminal name"
+// @Param idle_timeout 	formData int 	false "seconds before idle session end"
+// @Success 201 {object} utils.SuccessRespond{data=model.Terminal} "CREATED RESPOND"
+// @Failure 401 {object} utils.ErrorRespond "UNAUTHORIZED RESPOND"
+// @Failure 422 {object} utils.ValidationErrorRespond "UNPROCESSABLE ENTITY RESPOND"
+// @Failure 500 {object} utils.ErrorRespond "INTERNAL SERVER ERROR RESPOND"
+// @Router /api/v1/terminals [POST]
+func (handler terminalHandler) store(ctx *gin.Context) {
+	var form model.Terminal
+	if err := ctx.ShouldBind(&form); err != nil {
+		utils.NewHTTPRespond(ctx, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+	terminal, err := handler.svc.AddTerminal(ctx, &form)
+	if err != nil {
+		utils.NewHTTPRespond(ctx, err.Code, err.Message)
+		return
+	}
+	utils.NewHTTPRespond(ctx, http.StatusCreated, terminal)
+}
+
+// terminals godoc
+// @Schemes
+// @Summary Update Terminal Data
+// @Description Update terminal by ID, the active session of the terminal end.
+// @Tags Terminals
+// @Accept mpfd
+// @Produce json
+// @Param id 			path 	 int 	true  "terminal id"
+// @Param name 			formData string true  "terminal name"
+// @Param idle_timeout 	formData int 	false "seconds before idle session end"
+// @Param disabled 		formData bool 	false "disable login on the terminal"
+// @Success 200 {object} utils.SuccessRespond{data=model.Terminal} "OK RESPOND"
+// @Failure 400 {object} utils.ErrorRespond "BAD REQUEST RESPOND"
+// @Failure 401 {object} utils.ErrorRespond "UNAUTHORIZED RESPOND"
+// @Failure 404 {object} utils.ErrorRespond "NOT FOUND RESPOND"
+// @Failure 422 {object} utils.ValidationErrorRespond "UNPROCESSABLE ENTITY RESPOND"
+// @Failure 500 {object} utils.ErrorRespond "INTERNAL SERVER ERROR RESPOND"
+// @Router /api/v1/terminals/{id} [PUT]
+func (handler terminalHandler) update(ctx *gin.Context) {
+	idParams := ctx.Param("id")
+	id, errParse := strconv.Atoi(idParams)
+	if errParse != nil {
+		utils.NewHTTPRespond(ctx,
+			http.StatusBadRequest,
+			errParse.Error())
+		return
+	}
+	var form model.Terminal
+	if err := ctx.ShouldBind(&form); err != nil {
+		utils.NewHTTPRespond(ctx, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+	form.ID = id
+	terminal, err := handler.svc.EditTerminal(ctx, &form)
+	if err != nil {
+		utils.NewHTTPRespond(ctx, err.Code, err.Message)
+		return
+	}
+	utils.NewHTTPRespond(ctx, http.StatusOK, terminal)
+}
+
+// terminals godoc
+// @Schemes
+// @Summary Delete Terminal Data
+// @Description Delete terminal by ID.
+// @Tags Terminals
+// @Accept json
+// @Produce json
+// @Param id path int true "terminal id"
+// @Success 204 "NO CONTENT RESPOND"
+// @Failure 400 {object} utils.ErrorRespond "BAD REQUEST RESPOND"
+// @Failure 401 {object} utils.ErrorRespond "UNAUTHORIZED RESPOND"
+// @Failure 404 {object} utils.ErrorRespond "NOT FOUND RESPOND"
+// @Failure 500 {object} utils.ErrorRespond "INTERNAL SERVER ERROR RESPOND"
+// @Router /api/v1/terminals/{id} [DELETE]
+func (handler terminalHandler) destroy(ctx *gin.Context) {
+	idParams := ctx.Param("id")
+	id, errParse := strconv.Atoi(idParams)
+	if errParse != nil {
+		utils.NewHTTPRespond(ctx,
+			http.StatusBadRequest,
+			errParse.Error())
+		return
+	}
+	data := model.Terminal{ID: id}
+	if err := handler.svc.DeleteTerminal(ctx, &data); err != nil {
+		utils.NewHTTPRespond(ctx, err.Code, err.Message)
+		return
+	}
+	utils.NewHTTPRespond(ctx, http.StatusNoContent, nil)
+}
+
+// terminals godoc
+// @Schemes
+// @Summary Restore Terminal Data
+// @Description Restore deleted terminal by ID.
+// @Tags Terminals
+// @Accept json
+// @Produce json
+// @Param id path int true "terminal id"
+// @Success 200 {object} utils.SuccessRespond{data=model.Terminal} "OK RESPOND"
+// @Failure 400 {object} utils.ErrorRespond "BAD REQUEST RESPOND"
+// @Failure 401 {object} utils.ErrorRespond "UNAUTHORIZED RESPOND"
+// @Failure 403 {object} utils.ErrorRespond "FORBIDDEN RESPOND"
+// @Failure 404 {object} utils.ErrorRespond "NOT FOUND RESPOND"
+// @Failure 500 {object} utils.ErrorRespond "INTERNAL SERVER ERROR RESPOND"
+// @Router /api/v1/terminals/{id}/restore [PATCH]
+func (handler terminalHandler) restore(ctx *gin.Context) {
+	idParams := ctx.Param("id")
+	id, errParse := strconv.Atoi(idParams)
+	if errParse != nil {
+		utils.NewHTTPRespond(ctx,
+			http.StatusBadRequest,
+			errParse.Error())
+		return
+	}
+	data, err := handler.svc.RestoreTerminal(ctx, &model.Terminal{ID: id})
+	if err != nil {
+		utils.NewHTTPRespond(ctx, err.Code, err.Message)
+		return
+	}
+	utils.NewHTTPRespond(ctx, http.StatusOK, data)
+}
+
+// terminals godoc
+// @Schemes
+// @Summary Set User Badge
+// @Description Set the badge code the user log in on terminals with.
+// @Tags Terminals
+// @Accept json
+// @Produce json
+// @Param id   path int 			true "user id"
+// @Param body body model.BadgeForm true "badge code"
+// @Success 204 "NO CONTENT RESPOND"
+// @Failure 400 {object} utils.ErrorRespond "BAD REQUEST RESPOND"
+// @Failure 401 {object} utils.ErrorRespond "UNAUTHORIZED RESPOND"
+// @Failure 404 {object} utils.ErrorRespond "NOT FOUND RESPOND"
+// @Failure 422 {object} utils.ValidationErrorRespond "UNPROCESSABLE ENTITY RESPOND"
+// @Failure 500 {object} utils.ErrorRespond "INTERNAL SERVER ERROR RESPOND"
+// @Router /api/v1/users/{id}/badge [PUT]
+func (handler terminalHandler) badge(ctx *gin.Context) {
+	idParams := ctx.Param("id")
+	id, errParse := strconv.Atoi(idParams)
+	if errParse != nil {
+		utils.NewHTTPRespond(ctx,
+			http.StatusBadRequest,
+			errParse.Error())
+		return
+	}
+	var form model.BadgeForm
+	if err := ctx.ShouldBind(&form); err != nil {
+		utils.NewHTTPRespond(ctx, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+	if err := handler.svc.SetBadge(ctx, id, form.Badge); err != nil {
+		utils.NewHTTPRespond(ctx, err.Code, err.Message)
+		return
+	}
+	utils.NewHTTPRespond(ctx, http.StatusNoContent, nil)
+}
+
+// terminals godoc
+// @Schemes
+// @Summary Terminal Users
+// @Description Get the id, name and pin state of the users to pick from before entering the pin.
+// @Tags Terminals
+// @Accept json
+// @Produce json
+// @Param X-Terminal-Key header string true "terminal key"
+// @Success 200 {object} utils.SuccessRespond{data=[]model.TerminalUser} "OK RESPOND"
+// @Failure 401 {object} utils.ErrorRespond "UNAUTHORIZED RESPOND"
+// @Failure 500 {object} utils.ErrorRespond "INTERNAL SERVER ERROR RESPOND"
+// @Router /api/v1/terminals/users [GET]
+func (handler terminalHandler) users(ctx *gin.Context) {
+	users, err := handler.svc.TerminalUsers(ctx, ctx.GetHeader(terminalKeyHeader))
+	if err != nil {
+		utils.NewHTTPRespond(ctx, err.Code, err.Message)
+		return
+	}
+	utils.NewHTTPRespond(ctx, http.StatusOK, users)
+}
+
+// terminals godoc
+// @Schemes
+// @Summary Terminal Quick Login
+// @Description Log in on the terminal with user and pin or badge only, it switch
+// @Description the user of the terminal and the session end when idle.
+// @Tags Terminals
+// @Accept json
+// @Produce json
+// @Param X-Terminal-Key header string 				true "terminal key"
+// @Param body 			 body 	model.QuickLoginForm true "user and pin, or badge"
+// @Success 201 {object} utils.SuccessRespond{data=model.AuthSession} "CREATED RESPOND"
+// @Failure 401 {object} utils.ErrorRespond "UNAUTHORIZED RESPOND"
+// @Failure 422 {object} utils.ValidationErrorRespond "UNPROCESSABLE ENTITY RESPOND"
+// @Failure 423 {object} utils.ErrorRespond "LOCKED RESPOND"
+// @Failure 500 {object} utils.ErrorRespond "INTERNAL SERVER ERROR RESPOND"
+// @Router /api/v1/terminals/login [POST]
+func (handler terminalHandler) login(ctx *gin.Context) {
+	var form model.QuickLoginForm
+	if err := ctx.ShouldBind(&form); err != nil {
+		utils.NewHTTPRespond(ctx, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+	form.Device, form.IP = deviceName(ctx), ctx.ClientIP()
+	session, err := handler.svc.QuickLogin(ctx, ctx.GetHeader(terminalKeyHeader), &form)
+	if err != nil {
+		utils.NewHTTPRespond(ctx, err.Code, err.Message)
+		return
+	}
+	setTokenCookie(ctx, session)
+	utils.NewHTTPRespond(ctx, http.StatusCreated, session)
+}
+
+func NewTerminalHandler(
+	terminalService model.ITerminalService,
+	router *gin.RouterGroup,
+	protectedRouter gin.IRoutes,
+) {
+	handler := terminalHandler{svc: terminalService}
+	router.GET("/terminals/users", handler.users)
+	router.POST("/terminals/login", handler.login)
+	read := middleware.Permitted(model.PermissionAccountUserRead)
+	write := middleware.Permitted(model.PermissionAccountTerminalWrite)
+	userWrite := middleware.Permitted(model.PermissionAccountUserWrite)
+	trash := middleware.Permitted(model.PermissionTrashRestore)
+	protectedRouter.GET("/terminals", read, handler.fetch)
+	protectedRouter.POST("/terminals", write, handler.store)
+	protectedRouter.PUT("/terminals/:id", write, handler.update)
+	protectedRouter.DELETE("/terminals/:id", write, handler.destroy)
+	protectedRouter.PATCH("/terminals/:id/restore", trash, handler.restore)
+	protectedRouter.PUT("/users/:id/badge", userWrite, handler.badge)
+}
@@ -173,13 +173,47 @@ func (handler userHandler) destroy(ctx *gin.Context) {
 	utils.NewHTTPRespond(ctx, http.StatusNoContent, nil)
 }
 
+// users godoc
+// @Schemes
+// @Summary Restore User Data
+// @Description Restore deleted user by ID.
+// @Tags Users
+// @Accept json
+// @Produce json
+// @Param id path int true "user id"
+// @Success 200 {object} utils.SuccessRespond{data=model.User} "OK RESPOND"
+// @Failure 400 {object} utils.ErrorRespond "BAD REQUEST RESPOND"
+// @Failure 401 {object} utils.ErrorRespond "UNAUTHORIZED RESPOND"
+// @Failure 403 {object} utils.ErrorRespond "FORBIDDEN RESPOND"
+// @Failure 404 {object} utils.ErrorRespond "NOT FOUND RESPOND"
+// @Failure 500 {object} utils.ErrorRespond "INTERNAL SERVER ERROR RESPOND"
+// @Router /api/v1/users/{id}/restore [PATCH]
+func (handler userHandler) restore(ctx *gin.Context) {
+	idParams := ctx.Param("id")
+	id, errParse := strconv.Atoi(idParams)
+	if errParse != nil {
+		utils.NewHTTPRespond(ctx,
+			http.StatusBadRequest,
+			errParse.Error())
+		return
+	}
+	data, err := handler.svc.RestoreUser(ctx, &model.User{ID: id})
+	if err != nil {
+		utils.NewHTTPRespond(ctx, err.Code, err.Message)
+		return
+	}
+	utils.NewHTTPRespond(ctx, http.StatusOK, data)
+}
+
 func NewUserHandler(accountService model.IAccountService, router gin.IRoutes) {
 	handler := userHandler{svc: accountService}
-	router.GET("/users", handler.fetch)
-	router.GET("/users/:id", handler.show)
-	protectedRoute := router.Use(middleware.
-		AcceptedRoles([]string{"admin"}))
-	protectedRoute.POST("/users", handler.store)
-	protectedRoute.PUT("/users/:id", handler.update)
-	protectedRoute.DELETE("/users/:id", handler.destroy)
+	read := middleware.Permitted(model.PermissionAccountUserRead)
+	write := middleware.Permitted(model.PermissionAccountUserWrite)
+	trash := middleware.Permitted(model.PermissionTrashRestore)
+	router.GET("/users", read, handler.fetch)
+	router.GET("/users/:id", read, handler.show)
+	router.POST("/users", write, handler.store)
+	router.PUT("/users/:id", write, handler.update)
+	router.DELETE("/users/:id", write, handler.destroy)
+	router.PATCH("/users/:id/restore", trash, handler.restore)
 }
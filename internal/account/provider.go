@@ -2,49 +2,70 @@ package account
 
 import (
 	"context"
-	"encoding/json"
-	"errors"
+	"time"
 
+	"github.com/aasumitro/posbe/common"
 	"github.com/aasumitro/posbe/config"
 	"github.com/aasumitro/posbe/internal/account/handler/http"
 	repository "github.com/aasumitro/posbe/internal/account/repository/sql"
 	"github.com/aasumitro/posbe/internal/account/service"
+	"github.com/aasumitro/posbe/pkg/audit"
 	"github.com/aasumitro/posbe/pkg/http/middleware"
 	"github.com/aasumitro/posbe/pkg/model"
+	"github.com/aasumitro/posbe/pkg/reference"
+	"github.com/aasumitro/posbe/pkg/utils"
 	"github.com/gin-gonic/gin"
-	"github.com/redis/go-redis/v9"
 )
 
 var (
-	userRepository model.ICRUDRepository[model.User]
-	roleRepository model.ICRUDRepository[model.Role]
+	userRepository model.ISoftDeleteRepository[model.User]
+	roleRepository model.ISoftDeleteRepository[model.Role]
 )
 
 func NewAccountModuleProvider(router *gin.RouterGroup) {
-	userRepository = repository.NewUserSQLRepository()
-	roleRepository = repository.NewRoleSQLRepository()
+	userRepository = audit.Track(repository.NewUserSQLRepository())
+	roleRepository = audit.Track(repository.NewRoleSQLRepository())
 	accountService := service.NewAccountService(
-		roleRepository, userRepository)
-	shouldCacheData(context.Background())
-	http.NewAuthHandler(accountService, router)
-	protectedRouter := router.
+		roleRepository, userRepository, reference.NewReferenceSQLRepository())
+	// warm the role cache at first booting
+	_, _ = accountService.RoleList(context.Background())
+	overrideRepository := repository.NewOverrideSQLRepository()
+	overrideService := service.NewOverrideService(
+		overrideRepository, roleRepository)
+	sessionService := service.NewSessionService(
+		userRepository, http.NewJSONWebToken(),
+		time.Duration(config.Instance.JWTLifetime)*time.Hour)
+	terminalService := service.NewTerminalService(
+		audit.Track(repository.NewTerminalSQLRepository()),
+		overrideRepository, userRepository, sessionService)
+	apiKeyService := service.NewAPIKeyService(
+		repository.NewAPIKeySQLRepository())
+	middleware.SetPermissionResolver(accountService.RolePermissions)
+	middleware.SetOverrideConsumer(overrideService.Consume)
+	middleware.SetAPIKeyResolver(apiKeyService.Authenticate)
+	middleware.SetSessionValidator(func(
+		ctx context.Context,
+		terminalID int,
+		sessionID string,
+	) *utils.ServiceError {
+		if errData := sessionService.Validate(ctx, sessionID); errData != nil {
+			return errData
+		}
+		if terminalID > 0 {
+			return terminalService.TouchSession(ctx, terminalID, sessionID)
+		}
+		return nil
+	})
+	http.NewAuthHandler(accountService, sessionService, router)
+	protectedRouter := router.Group(common.EmptyPath).
 		Use(middleware.Auth()).
+		Use(middleware.IncludeDeleted()).
+		Use(middleware.ReassignTo()).
 		Use(middleware.ActivityObserver())
 	http.NewRoleHandler(accountService, protectedRouter)
 	http.NewUserHandler(accountService, protectedRouter)
-}
-
-func shouldCacheData(ctx context.Context) {
-	// run this at first booting
-	if err := config.RedisPool.
-		Get(ctx, "roles").
-		Err(); errors.Is(err, redis.Nil) && err != nil {
-		if roles, err := roleRepository.All(ctx); err == nil {
-			// encode given data
-			jsonData, _ := json.Marshal(roles)
-			// store data to redis
-			config.RedisPool.Set(ctx,
-				"roles", jsonData, 0)
-		}
-	}
+	http.NewOverrideHandler(overrideService, protectedRouter)
+	http.NewTerminalHandler(terminalService, router, protectedRouter)
+	http.NewSessionHandler(sessionService, protectedRouter)
+	http.NewAPIKeyHandler(apiKeyService, protectedRouter)
 }
@@ -0,0 +1,120 @@
+package sql
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/aasumitro/posbe/config"
+	"github.com/aasumitro/posbe/pkg/dialect"
+	"github.com/aasumitro/posbe/pkg/model"
+)
+
+const apiKeyColumns = "id, name, prefix, scopes, COALESCE(expires_at, 0), " +
+	"COALESCE(previous_valid_until, 0), COALESCE(last_used_at, 0), " +
+	"COALESCE(revoked_at, 0), COALESCE(created_by, 0), created_at"
+
+type (
+	APIKeySQLRepository struct {
+		Db      *sql.DB
+		Dialect dialect.Dialect
+	}
+
+	// scanner is implemented by both *sql.Row and *sql.Rows
+	scanner interface {
+		Scan(dest ...any) error
+	}
+)
+
+func (repo APIKeySQLRepository) All(ctx context.Context) (keys []*model.APIKey, err error) {
+	q := "SELECT " + apiKeyColumns + " FROM api_keys ORDER BY id ASC"
+	rows, err := repo.Db.QueryContext(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+	defer func(rows *sql.Rows) { _ = rows.Close() }(rows)
+	for rows.Next() {
+		key, err := repo.scan(rows)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+	return keys, rows.Err()
+}
+
+func (repo APIKeySQLRepository) Find(ctx context.Context, id int) (key *model.APIKey, err error) {
+	q := "SELECT " + apiKeyColumns + " FROM api_keys WHERE id = $1 LIMIT 1"
+	return repo.scan(repo.Db.QueryRowContext(ctx, q, id))
+}
+
+func (repo APIKeySQLRepository) FindByHash(
+	ctx context.Context,
+	keyHash string,
+	now int64,
+) (key *model.APIKey, err error) {
+	q := "SELECT " + apiKeyColumns + " FROM api_keys "
+	q += "WHERE (key_hash = $1 OR (previous_key_hash = $1 AND previous_valid_until > $2)) "
+	q += "AND revoked_at IS NULL AND (expires_at IS NULL OR expires_at > $2) LIMIT 1"
+	return repo.scan(repo.Db.QueryRowContext(ctx, q, keyHash, now))
+}
+
+func (repo APIKeySQLRepository) Create(ctx context.Context, key *model.APIKey) (data *model.APIKey, err error) {
+	q := "INSERT INTO api_keys (name, prefix, key_hash, scopes, expires_at, created_by, created_at) "
+	q += "VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING " + apiKeyColumns
+	return repo.scan(repo.Db.QueryRowContext(ctx, q,
+		key.Name, key.Prefix, key.KeyHash, repo.Dialect.Array(key.Scopes),
+		sql.NullInt64{Int64: key.ExpiresAt, Valid: key.ExpiresAt > 0},
+		nullID(key.CreatedBy), time.Now().Unix()))
+}
+
+func (repo APIKeySQLRepository) Rotate(
+	ctx context.Context,
+	id int,
+	keyHash, prefix string,
+	previousValidUntil int64,
+) (data *model.APIKey, err error) {
+	q := "UPDATE api_keys SET previous_key_hash = key_hash, previous_valid_until = $1, "
+	q += "key_hash = $2, prefix = $3, updated_at = $4 "
+	q += "WHERE id = $5 AND revoked_at IS NULL RETURNING " + apiKeyColumns
+	return repo.scan(repo.Db.QueryRowContext(ctx, q,
+		previousValidUntil, keyHash, prefix, time.Now().Unix(), id))
+}
+
+func (repo APIKeySQLRepository) Revoke(ctx context.Context, id int, revokedAt int64) error {
+	q := "UPDATE api_keys SET revoked_at = $1, updated_at = $1 WHERE id = $2 AND revoked_at IS NULL"
+	result, err := repo.Db.ExecContext(ctx, q, revokedAt, id)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func (repo APIKeySQLRepository) Touch(ctx context.Context, id int, usedAt int64) error {
+	q := "UPDATE api_keys SET last_used_at = $1 WHERE id = $2"
+	_, err := repo.Db.ExecContext(ctx, q, usedAt, id)
+	return err
+}
+
+func (repo APIKeySQLRepository) scan(row scanner) (*model.APIKey, error) {
+	var key model.APIKey
+	if err := row.Scan(
+		&key.ID, &key.Name, &key.Prefix, repo.Dialect.ScanArray(&key.Scopes),
+		&key.ExpiresAt, &key.PreviousValidUntil, &key.LastUsedAt,
+		&key.RevokedAt, &key.CreatedBy, &key.CreatedAt,
+	); err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
+
+func NewAPIKeySQLRepository() model.IAPIKeyRepository {
+	return &APIKeySQLRepository{Db: config.DbPool, Dialect: config.Dialect}
+}
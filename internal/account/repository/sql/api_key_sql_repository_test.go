@@ -0,0 +1,164 @@
+package sql_test
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/aasumitro/posbe/config"
+	repoSql "github.com/aasumitro/posbe/internal/account/repository/sql"
+	"github.com/aasumitro/posbe/pkg/dbtest"
+	"github.com/aasumitro/posbe/pkg/dialect"
+	"github.com/aasumitro/posbe/pkg/model"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type apiKeyRepositoryTestSuite struct {
+	suite.Suite
+	mock    sqlmock.Sqlmock
+	repo    model.IAPIKeyRepository
+	columns []string
+}
+
+func (suite *apiKeyRepositoryTestSuite) SetupSuite() {
+	var err error
+	config.DbPool, suite.mock, err = sqlmock.New(
+		sqlmock.QueryMatcherOption(
+			sqlmock.QueryMatcherRegexp))
+	require.NoError(suite.T(), err)
+	suite.repo = repoSql.NewAPIKeySQLRepository()
+	suite.columns = []string{"id", "name", "prefix", "scopes", "expires_at",
+		"previous_valid_until", "last_used_at", "revoked_at", "created_by", "created_at"}
+}
+
+func (suite *apiKeyRepositoryTestSuite) AfterTest(_, _ string) {
+	require.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+func (suite *apiKeyRepositoryTestSuite) TestRepository_All_ExpectReturnRows() {
+	suite.mock.ExpectQuery("SELECT (.+) FROM api_keys ORDER BY id ASC").
+		WillReturnRows(suite.mock.NewRows(suite.columns).
+			AddRow(1, "accounting", "pk_0123abcd", arrayValue([]string{"report.export"}), 0, 0, 0, 0, 1, 123).
+			AddRow(2, "delivery", "pk_4567abcd", arrayValue([]string{"catalog.read", "store.read"}), 0, 0, 0, 456, 1, 123))
+	res, err := suite.repo.All(context.TODO())
+	require.NoError(suite.T(), err)
+	require.Len(suite.T(), res, 2)
+	require.Equal(suite.T(), []string{"catalog.read", "store.read"}, res[1].Scopes)
+	require.Equal(suite.T(), int64(456), res[1].RevokedAt)
+}
+
+func (suite *apiKeyRepositoryTestSuite) TestRepository_All_ExpectReturnError() {
+	suite.mock.ExpectQuery("SELECT (.+) FROM api_keys").
+		WillReturnError(errors.New("UNEXPECTED"))
+	res, err := suite.repo.All(context.TODO())
+	require.Nil(suite.T(), res)
+	require.Error(suite.T(), err)
+}
+
+func (suite *apiKeyRepositoryTestSuite) TestRepository_Find_ExpectReturnErrorNoRows() {
+	suite.mock.ExpectQuery("SELECT (.+) FROM api_keys WHERE id = \\$1 LIMIT 1").
+		WithArgs(9).WillReturnError(sql.ErrNoRows)
+	res, err := suite.repo.Find(context.TODO(), 9)
+	require.Nil(suite.T(), res)
+	require.ErrorIs(suite.T(), err, sql.ErrNoRows)
+}
+
+func (suite *apiKeyRepositoryTestSuite) TestRepository_FindByHash_ExpectReturnRow() {
+	suite.mock.ExpectQuery("SELECT (.+) FROM api_keys WHERE \\(key_hash = \\$1 OR "+
+		"\\(previous_key_hash = \\$1 AND previous_valid_until > \\$2\\)\\) AND revoked_at IS NULL").
+		WithArgs("hash", int64(100)).
+		WillReturnRows(suite.mock.NewRows(suite.columns).
+			AddRow(1, "accounting", "pk_0123abcd", arrayValue([]string{"report.export"}), 0, 0, 0, 0, 1, 123))
+	res, err := suite.repo.FindByHash(context.TODO(), "hash", 100)
+	require.NoError(suite.T(), err)
+	require.Equal(suite.T(), []string{"report.export"}, res.Scopes)
+}
+
+func (suite *apiKeyRepositoryTestSuite) TestRepository_Create_ExpectReturnRow() {
+	suite.mock.ExpectQuery("INSERT INTO api_keys (.+) RETURNING").
+		WithArgs("accounting", "pk_0123abcd", "hash", arrayValue([]string{"report.export"}),
+			sql.NullInt64{}, sql.NullInt64{Int64: 1, Valid: true}, sqlmock.AnyArg()).
+		WillReturnRows(suite.mock.NewRows(suite.columns).
+			AddRow(1, "accounting", "pk_0123abcd", arrayValue([]string{"report.export"}), 0, 0, 0, 0, 1, 123))
+	res, err := suite.repo.Create(context.TODO(), &model.APIKey{Name: "accounting",
+		Prefix: "pk_0123abcd", KeyHash: "hash", Scopes: []string{"report.export"}, CreatedBy: 1})
+	require.NoError(suite.T(), err)
+	require.Equal(suite.T(), 1, res.ID)
+}
+
+func (suite *apiKeyRepositoryTestSuite) TestRepository_Rotate_ExpectReturnRow() {
+	suite.mock.ExpectQuery("UPDATE api_keys SET previous_key_hash = key_hash, (.+) "+
+		"WHERE id = \\$5 AND revoked_at IS NULL RETURNING").
+		WithArgs(int64(200), "hash", "pk_4567abcd", sqlmock.AnyArg(), 1).
+		WillReturnRows(suite.mock.NewRows(suite.columns).
+			AddRow(1, "accounting", "pk_4567abcd", arrayValue([]string{"report.export"}), 0, 200, 0, 0, 1, 123))
+	res, err := suite.repo.Rotate(context.TODO(), 1, "hash", "pk_4567abcd", 200)
+	require.NoError(suite.T(), err)
+	require.Equal(suite.T(), int64(200), res.PreviousValidUntil)
+}
+
+func (suite *apiKeyRepositoryTestSuite) TestRepository_Revoke() {
+	suite.mock.ExpectExec("UPDATE api_keys SET revoked_at = \\$1, (.+) WHERE id = \\$2 AND revoked_at IS NULL").
+		WithArgs(int64(100), 1).WillReturnResult(sqlmock.NewResult(0, 1))
+	suite.mock.ExpectExec("UPDATE api_keys SET revoked_at = \\$1, (.+) WHERE id = \\$2 AND revoked_at IS NULL").
+		WithArgs(int64(100), 9).WillReturnResult(sqlmock.NewResult(0, 0))
+	require.NoError(suite.T(), suite.repo.Revoke(context.TODO(), 1, 100))
+	require.ErrorIs(suite.T(), suite.repo.Revoke(context.TODO(), 9, 100), sql.ErrNoRows)
+}
+
+func (suite *apiKeyRepositoryTestSuite) TestRepository_Touch_ExpectSuccess() {
+	suite.mock.ExpectExec("UPDATE api_keys SET last_used_at = \\$1 WHERE id = \\$2").
+		WithArgs(int64(100), 1).WillReturnResult(sqlmock.NewResult(0, 1))
+	require.NoError(suite.T(), suite.repo.Touch(context.TODO(), 1, 100))
+}
+
+func (suite *apiKeyRepositoryTestSuite) TestAPIKeyRepository_Rotate() {
+	dbtest.Migrated(suite.T(), config.Dialect.Name())
+	ctx := context.TODO()
+	repo := repoSql.NewAPIKeySQLRepository()
+	now := time.Now().Unix()
+	key, err := repo.Create(ctx, &model.APIKey{Name: "ecommerce", Prefix: "pk_1", KeyHash: "first",
+		Scopes: []string{model.PermissionCatalogRead}, CreatedBy: 1, CreatedAt: now})
+	require.NoError(suite.T(), err)
+	key, err = repo.Find(ctx, key.ID)
+	require.NoError(suite.T(), err)
+	require.Equal(suite.T(), []string{model.PermissionCatalogRead}, key.Scopes)
+
+	_, err = repo.Rotate(ctx, key.ID, "second", "pk_2", now+60)
+	require.NoError(suite.T(), err)
+	for _, hash := range []string{"first", "second"} {
+		found, err := repo.FindByHash(ctx, hash, now)
+		require.NoError(suite.T(), err)
+		require.Equal(suite.T(), key.ID, found.ID)
+	}
+	_, err = repo.FindByHash(ctx, "first", now+120)
+	require.ErrorIs(suite.T(), err, sql.ErrNoRows)
+
+	require.NoError(suite.T(), repo.Touch(ctx, key.ID, now))
+	require.NoError(suite.T(), repo.Revoke(ctx, key.ID, now))
+	_, err = repo.FindByHash(ctx, "second", now)
+	require.ErrorIs(suite.T(), err, sql.ErrNoRows)
+	keys, err := repo.All(ctx)
+	require.NoError(suite.T(), err)
+	require.Len(suite.T(), keys, 1)
+}
+
+func TestAPIKeyRepository(t *testing.T) {
+	for _, d := range dialect.All() {
+		t.Run(d.Name(), func(t *testing.T) {
+			config.Dialect = d
+			suite.Run(t, new(apiKeyRepositoryTestSuite))
+		})
+	}
+}
+
+// arrayValue is values bound as array by the dialect under test
+func arrayValue(values any) driver.Value {
+	value, _ := config.Dialect.Array(values).Value()
+	return value
+}
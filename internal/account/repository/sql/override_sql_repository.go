@@ -0,0 +1,149 @@
+package sql
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/aasumitro/posbe/config"
+	"github.com/aasumitro/posbe/pkg/model"
+)
+
+type OverrideSQLRepository struct {
+	Db *sql.DB
+}
+
+const userPINColumns = "id, COALESCE(role_id, 0), COALESCE(pin, ''), COALESCE(pin_locked_until, 0)"
+
+func (repo OverrideSQLRepository) UserPIN(ctx context.Context, userID int) (pin *model.UserPIN, err error) {
+	q := "SELECT " + userPINColumns + " FROM users WHERE id = $1 AND deleted_at IS NULL LIMIT 1"
+	return scanUserPIN(repo.Db.QueryRowContext(ctx, q, userID))
+}
+
+func (repo OverrideSQLRepository) UpdateUserPIN(ctx context.Context, userID int, pin string) error {
+	q := "UPDATE users SET pin = $1, pin_failures = 0, pin_locked_until = NULL WHERE id = $2"
+	return repo.updateUser(ctx, q, pin, userID)
+}
+
+func (repo OverrideSQLRepository) TerminalUsers(ctx context.Context) (users []*model.TerminalUser, err error) {
+	q := "SELECT id, name, COALESCE(pin, '') <> '' FROM users WHERE deleted_at IS NULL ORDER BY name, id"
+	rows, err := repo.Db.QueryContext(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+	defer func(rows *sql.Rows) { _ = rows.Close() }(rows)
+	for rows.Next() {
+		var user model.TerminalUser
+		if err := rows.Scan(&user.ID, &user.Name, &user.HasPIN); err != nil {
+			return nil, err
+		}
+		users = append(users, &user)
+	}
+	return users, rows.Err()
+}
+
+func (repo OverrideSQLRepository) UserBadge(ctx context.Context, badgeHash string) (pin *model.UserPIN, err error) {
+	q := "SELECT " + userPINColumns + " FROM users WHERE badge_hash = $1 AND deleted_at IS NULL LIMIT 1"
+	return scanUserPIN(repo.Db.QueryRowContext(ctx, q, badgeHash))
+}
+
+func (repo OverrideSQLRepository) UpdateUserBadge(ctx context.Context, userID int, badgeHash string) error {
+	q := "UPDATE users SET badge_hash = $1 WHERE id = $2"
+	return repo.updateUser(ctx, q, badgeHash, userID)
+}
+
+func (repo OverrideSQLRepository) PINFailed(
+	ctx context.Context,
+	userID, maxAttempts int,
+	lockedUntil int64,
+) error {
+	q := "UPDATE users SET "
+	q += "pin_locked_until = CASE WHEN pin_failures + 1 >= $1 THEN $2 ELSE pin_locked_until END, "
+	q += "pin_failures = CASE WHEN pin_failures + 1 >= $1 THEN 0 ELSE pin_failures + 1 END "
+	q += "WHERE id = $3"
+	_, err := repo.Db.ExecContext(ctx, q, maxAttempts, lockedUntil, userID)
+	return err
+}
+
+func (repo OverrideSQLRepository) PINSucceeded(ctx context.Context, userID int) error {
+	q := "UPDATE users SET pin_failures = 0, pin_locked_until = NULL WHERE id = $1"
+	_, err := repo.Db.ExecContext(ctx, q, userID)
+	return err
+}
+
+// updateUser return sql.ErrNoRows when no user is updated
+func (repo OverrideSQLRepository) updateUser(ctx context.Context, q string, args ...any) error {
+	result, err := repo.Db.ExecContext(ctx, q, args...)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func (repo OverrideSQLRepository) Create(
+	ctx context.Context,
+	override *model.Override,
+	tokenHash string,
+) (data *model.Override, err error) {
+	q := "INSERT INTO overrides (token_hash, action, reason, approved_by, requested_by, "
+	q += "expires_at, created_at) VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING id"
+	created := *override
+	data = &created
+	data.CreatedAt = time.Now().Unix()
+	if err := repo.Db.QueryRowContext(ctx, q,
+		tokenHash, data.Action, nullString(data.Reason), data.ApprovedBy,
+		nullID(data.RequestedBy), data.ExpiresAt, data.CreatedAt,
+	).Scan(&data.ID); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+func (repo OverrideSQLRepository) Consume(
+	ctx context.Context,
+	tokenHash, action string,
+	usedBy int,
+) (data *model.Override, err error) {
+	now := time.Now().Unix()
+	q := "UPDATE overrides SET used_by = $1, used_at = $2 "
+	q += "WHERE token_hash = $3 AND action = $4 AND used_at IS NULL AND expires_at > $2 "
+	q += "RETURNING id, action, COALESCE(reason, ''), approved_by, COALESCE(requested_by, 0), "
+	q += "expires_at, created_at"
+	data = &model.Override{UsedBy: usedBy, UsedAt: now}
+	if err := repo.Db.QueryRowContext(ctx, q, nullID(usedBy), now, tokenHash, action).Scan(
+		&data.ID, &data.Action, &data.Reason, &data.ApprovedBy,
+		&data.RequestedBy, &data.ExpiresAt, &data.CreatedAt,
+	); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+func scanUserPIN(row *sql.Row) (*model.UserPIN, error) {
+	var pin model.UserPIN
+	if err := row.Scan(&pin.UserID, &pin.RoleID, &pin.PIN, &pin.LockedUntil); err != nil {
+		return nil, err
+	}
+	return &pin, nil
+}
+
+// nullID store zero id as NULL for optional reference
+func nullID(id int) sql.NullInt64 {
+	return sql.NullInt64{Int64: int64(id), Valid: id > 0}
+}
+
+// nullString store empty string as NULL for optional column
+func nullString(value string) sql.NullString {
+	return sql.NullString{String: value, Valid: value != ""}
+}
+
+func NewOverrideSQLRepository() model.IOverrideRepository {
+	return &OverrideSQLRepository{Db: config.DbPool}
+}
@@ -0,0 +1,190 @@
+package sql_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/aasumitro/posbe/config"
+	repoSql "github.com/aasumitro/posbe/internal/account/repository/sql"
+	"github.com/aasumitro/posbe/pkg/dbtest"
+	"github.com/aasumitro/posbe/pkg/dialect"
+	"github.com/aasumitro/posbe/pkg/model"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type overrideRepositoryTestSuite struct {
+	suite.Suite
+	mock sqlmock.Sqlmock
+	repo model.IOverrideRepository
+}
+
+func (suite *overrideRepositoryTestSuite) SetupSuite() {
+	var err error
+	config.DbPool, suite.mock, err = sqlmock.New(
+		sqlmock.QueryMatcherOption(
+			sqlmock.QueryMatcherRegexp))
+	require.NoError(suite.T(), err)
+	suite.repo = repoSql.NewOverrideSQLRepository()
+}
+
+func (suite *overrideRepositoryTestSuite) AfterTest(_, _ string) {
+	require.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+func (suite *overrideRepositoryTestSuite) TestRepository_UserPIN_ExpectReturnRow() {
+	suite.mock.ExpectQuery("SELECT id, COALESCE\\(role_id, 0\\), COALESCE\\(pin, ''\\), (.+) FROM users WHERE id").
+		WithArgs(2).
+		WillReturnRows(suite.mock.NewRows([]string{"id", "role_id", "pin", "pin_locked_until"}).AddRow(2, 1, "hash.salt", 0))
+	res, err := suite.repo.UserPIN(context.TODO(), 2)
+	require.NoError(suite.T(), err)
+	require.Equal(suite.T(), &model.UserPIN{UserID: 2, RoleID: 1, PIN: "hash.salt"}, res)
+}
+
+func (suite *overrideRepositoryTestSuite) TestRepository_UserPIN_ExpectReturnError() {
+	suite.mock.ExpectQuery("FROM users").WithArgs(9).WillReturnError(sql.ErrNoRows)
+	res, err := suite.repo.UserPIN(context.TODO(), 9)
+	require.Nil(suite.T(), res)
+	require.ErrorIs(suite.T(), err, sql.ErrNoRows)
+}
+
+func (suite *overrideRepositoryTestSuite) TestRepository_UpdateUserPIN_ExpectErrorNoRows() {
+	suite.mock.ExpectExec("UPDATE users SET pin = \\$1, (.+) WHERE id = \\$2").
+		WithArgs("hash.salt", 9).WillReturnResult(sqlmock.NewResult(0, 0))
+	err := suite.repo.UpdateUserPIN(context.TODO(), 9, "hash.salt")
+	require.ErrorIs(suite.T(), err, sql.ErrNoRows)
+}
+
+func (suite *overrideRepositoryTestSuite) TestRepository_UpdateUserPIN_ExpectSuccess() {
+	suite.mock.ExpectExec("UPDATE users SET pin").
+		WithArgs("hash.salt", 2).WillReturnResult(sqlmock.NewResult(0, 1))
+	err := suite.repo.UpdateUserPIN(context.TODO(), 2, "hash.salt")
+	require.NoError(suite.T(), err)
+}
+
+func (suite *overrideRepositoryTestSuite) TestRepository_Create_ExpectReturnRow() {
+	suite.mock.ExpectQuery("INSERT INTO overrides").
+		WithArgs("token-hash", model.PermissionOrderVoid, nil, 1, 3, int64(1700000120), sqlmock.AnyArg()).
+		WillReturnRows(suite.mock.NewRows([]string{"id"}).AddRow(7))
+	res, err := suite.repo.Create(context.TODO(), &model.Override{
+		Action: model.PermissionOrderVoid, ApprovedBy: 1, RequestedBy: 3, ExpiresAt: 1700000120,
+	}, "token-hash")
+	require.NoError(suite.T(), err)
+	require.Equal(suite.T(), 7, res.ID)
+	require.NotZero(suite.T(), res.CreatedAt)
+}
+
+func (suite *overrideRepositoryTestSuite) TestRepository_Consume_ExpectReturnRow() {
+	suite.mock.ExpectQuery("UPDATE overrides SET used_by = \\$1, used_at = \\$2 (.+) used_at IS NULL AND expires_at > \\$2").
+		WithArgs(3, sqlmock.AnyArg(), "token-hash", model.PermissionOrderVoid).
+		WillReturnRows(suite.mock.NewRows([]string{
+			"id", "action", "reason", "approved_by", "requested_by", "expires_at", "created_at"}).
+			AddRow(7, model.PermissionOrderVoid, "wrong item", 1, 3, 1700000120, 1700000000))
+	res, err := suite.repo.Consume(context.TODO(), "token-hash", model.PermissionOrderVoid, 3)
+	require.NoError(suite.T(), err)
+	require.Equal(suite.T(), 1, res.ApprovedBy)
+	require.Equal(suite.T(), 3, res.UsedBy)
+	require.NotZero(suite.T(), res.UsedAt)
+}
+
+func (suite *overrideRepositoryTestSuite) TestRepository_Consume_ExpectReturnError() {
+	suite.mock.ExpectQuery("UPDATE overrides").
+		WillReturnError(errors.New("UNEXPECTED"))
+	res, err := suite.repo.Consume(context.TODO(), "token-hash", model.PermissionOrderVoid, 3)
+	require.Nil(suite.T(), res)
+	require.NotNil(suite.T(), err)
+}
+
+func (suite *overrideRepositoryTestSuite) TestRepository_UserBadge_ExpectReturnRow() {
+	suite.mock.ExpectQuery("SELECT (.+) FROM users WHERE badge_hash = \\$1").
+		WithArgs("badge").
+		WillReturnRows(suite.mock.NewRows([]string{"id", "role_id", "pin", "pin_locked_until"}).AddRow(2, 1, "", 123))
+	res, err := suite.repo.UserBadge(context.TODO(), "badge")
+	require.NoError(suite.T(), err)
+	require.Equal(suite.T(), &model.UserPIN{UserID: 2, RoleID: 1, LockedUntil: 123}, res)
+}
+
+func (suite *overrideRepositoryTestSuite) TestRepository_TerminalUsers_ExpectReturnRows() {
+	suite.mock.ExpectQuery("SELECT id, name, COALESCE\\(pin, ''\\) <> '' FROM users WHERE deleted_at IS NULL").
+		WillReturnRows(suite.mock.NewRows([]string{"id", "name", "has_pin"}).
+			AddRow(2, "ipsum", false).AddRow(1, "lorem", true))
+	res, err := suite.repo.TerminalUsers(context.TODO())
+	require.NoError(suite.T(), err)
+	require.Equal(suite.T(), []*model.TerminalUser{
+		{ID: 2, Name: "ipsum"}, {ID: 1, Name: "lorem", HasPIN: true},
+	}, res)
+}
+
+func (suite *overrideRepositoryTestSuite) TestRepository_TerminalUsers_ExpectReturnError() {
+	suite.mock.ExpectQuery("FROM users").WillReturnError(errors.New("UNEXPECTED"))
+	res, err := suite.repo.TerminalUsers(context.TODO())
+	require.Nil(suite.T(), res)
+	require.Error(suite.T(), err)
+}
+
+func (suite *overrideRepositoryTestSuite) TestRepository_UpdateUserBadge_ExpectErrorNoRows() {
+	suite.mock.ExpectExec("UPDATE users SET badge_hash = \\$1 WHERE id = \\$2").
+		WithArgs("badge", 9).WillReturnResult(sqlmock.NewResult(0, 0))
+	err := suite.repo.UpdateUserBadge(context.TODO(), 9, "badge")
+	require.ErrorIs(suite.T(), err, sql.ErrNoRows)
+}
+
+func (suite *overrideRepositoryTestSuite) TestRepository_PINFailed_ExpectSuccess() {
+	suite.mock.ExpectExec("UPDATE users SET pin_locked_until = CASE (.+) pin_failures = CASE (.+) WHERE id = \\$3").
+		WithArgs(5, int64(123), 2).WillReturnResult(sqlmock.NewResult(0, 1))
+	err := suite.repo.PINFailed(context.TODO(), 2, 5, 123)
+	require.NoError(suite.T(), err)
+}
+
+func (suite *overrideRepositoryTestSuite) TestRepository_PINSucceeded_ExpectSuccess() {
+	suite.mock.ExpectExec("UPDATE users SET pin_failures = 0, pin_locked_until = NULL WHERE id = \\$1").
+		WithArgs(2).WillReturnResult(sqlmock.NewResult(0, 1))
+	err := suite.repo.PINSucceeded(context.TODO(), 2)
+	require.NoError(suite.T(), err)
+}
+
+func (suite *overrideRepositoryTestSuite) TestOverrideRepository_Consume() {
+	dbtest.Migrated(suite.T(), config.Dialect.Name())
+	ctx := context.TODO()
+	repo := repoSql.NewOverrideSQLRepository()
+	require.NoError(suite.T(), repo.UpdateUserPIN(ctx, 1, "pin-hash"))
+	require.NoError(suite.T(), repo.UpdateUserBadge(ctx, 1, "badge-hash"))
+	require.NoError(suite.T(), repo.PINFailed(ctx, 1, 1, time.Now().Add(time.Minute).Unix()))
+	pin, err := repo.UserBadge(ctx, "badge-hash")
+	require.NoError(suite.T(), err)
+	require.Equal(suite.T(), "pin-hash", pin.PIN)
+	require.NotZero(suite.T(), pin.LockedUntil)
+	require.NoError(suite.T(), repo.PINSucceeded(ctx, 1))
+	pin, err = repo.UserPIN(ctx, 1)
+	require.NoError(suite.T(), err)
+	require.Zero(suite.T(), pin.LockedUntil)
+	require.ErrorIs(suite.T(), repo.UpdateUserPIN(ctx, 99, "pin-hash"), sql.ErrNoRows)
+	users, err := repo.TerminalUsers(ctx)
+	require.NoError(suite.T(), err)
+	require.NotEmpty(suite.T(), users)
+	for _, user := range users {
+		require.Equal(suite.T(), user.ID == 1, user.HasPIN, user.Name)
+	}
+
+	override, err := repo.Create(ctx, &model.Override{Action: model.PermissionOrderVoid, Reason: "lorem",
+		ApprovedBy: 1, RequestedBy: 2, ExpiresAt: time.Now().Add(time.Minute).Unix()}, "token-hash")
+	require.NoError(suite.T(), err)
+	used, err := repo.Consume(ctx, "token-hash", model.PermissionOrderVoid, 2)
+	require.NoError(suite.T(), err)
+	require.Equal(suite.T(), override.ID, used.ID)
+	_, err = repo.Consume(ctx, "token-hash", model.PermissionOrderVoid, 2)
+	require.ErrorIs(suite.T(), err, sql.ErrNoRows)
+}
+
+func TestOverrideRepository(t *testing.T) {
+	for _, d := range dialect.All() {
+		t.Run(d.Name(), func(t *testing.T) {
+			config.Dialect = d
+			suite.Run(t, new(overrideRepositoryTestSuite))
+		})
+	}
+}
@@ -3,20 +3,30 @@ package sql
 import (
 	"context"
 	"database/sql"
+	"time"
 
 	"github.com/aasumitro/posbe/config"
+	"github.com/aasumitro/posbe/pkg/audit"
+	"github.com/aasumitro/posbe/pkg/dialect"
 	"github.com/aasumitro/posbe/pkg/model"
 )
 
 type RoleSQLRepository struct {
-	Db *sql.DB
+	Db      *sql.DB
+	Dialect dialect.Dialect
+}
+
+func (repo RoleSQLRepository) columns() string {
+	return "roles.id, roles.name, roles.description, COUNT(users.role_id) as usage, " +
+		repo.Dialect.ArrayAgg("permission", "FROM role_permissions WHERE role_id = roles.id") + ", " +
+		"roles.deleted_at, roles.deleted_by"
 }
 
 func (repo RoleSQLRepository) All(ctx context.Context) (roles []*model.Role, err error) {
-	q := "SELECT roles.id, roles.name, roles.description, COUNT(users.role_id) as usage "
-	q += "FROM roles LEFT OUTER JOIN users ON users.role_id = roles.id "
-	q += "GROUP BY roles.id ORDER BY roles.id ASC"
-	rows, err := repo.Db.QueryContext(ctx, q)
+	q := "SELECT " + repo.columns() + " FROM roles "
+	q += "LEFT OUTER JOIN users ON users.role_id = roles.id AND users.deleted_at IS NULL "
+	q += "WHERE ($1 OR roles.deleted_at IS NULL) GROUP BY roles.id ORDER BY roles.id ASC"
+	rows, err := repo.Db.QueryContext(ctx, q, model.IncludeDeleted(ctx))
 	if err != nil {
 		return nil, err
 	}
@@ -26,6 +36,8 @@ func (repo RoleSQLRepository) All(ctx context.Context) (roles []*model.Role, err
 		if err := rows.Scan(
 			&role.ID, &role.Name,
 			&role.Description, &role.Usage,
+			repo.Dialect.ScanArray(&role.Permissions),
+			&role.DeletedAt, &role.DeletedBy,
 		); err != nil {
 			return nil, err
 		}
@@ -35,14 +47,16 @@ func (repo RoleSQLRepository) All(ctx context.Context) (roles []*model.Role, err
 }
 
 func (repo RoleSQLRepository) Find(ctx context.Context, _ model.FindWith, val any) (role *model.Role, err error) {
-	q := "SELECT roles.id, roles.name, roles.description, COUNT(users.role_id) as usage "
-	q += "FROM roles LEFT OUTER JOIN users ON users.role_id = roles.id "
-	q += "WHERE roles.id = $1 GROUP BY roles.id LIMIT 1"
-	row := repo.Db.QueryRowContext(ctx, q, val)
+	q := "SELECT " + repo.columns() + " FROM roles "
+	q += "LEFT OUTER JOIN users ON users.role_id = roles.id AND users.deleted_at IS NULL "
+	q += "WHERE roles.id = $1 AND ($2 OR roles.deleted_at IS NULL) GROUP BY roles.id LIMIT 1"
+	row := repo.Db.QueryRowContext(ctx, q, val, model.IncludeDeleted(ctx))
 	role = &model.Role{}
 	if err := row.Scan(
 		&role.ID, &role.Name,
 		&role.Description, &role.Usage,
+		repo.Dialect.ScanArray(&role.Permissions),
+		&role.DeletedAt, &role.DeletedBy,
 	); err != nil {
 		return nil, err
 	}
@@ -50,31 +64,74 @@ func (repo RoleSQLRepository) Find(ctx context.Context, _ model.FindWith, val an
 }
 
 func (repo RoleSQLRepository) Create(ctx context.Context, params *model.Role) (role *model.Role, err error) {
-	q := "INSERT INTO roles (name, description) values ($1, $2) RETURNING *"
-	row := repo.Db.QueryRowContext(ctx, q, params.Name, params.Description)
+	tx, err := repo.Db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback() }()
+	q := "INSERT INTO roles (name, description) values ($1, $2) RETURNING id, name, description"
+	row := tx.QueryRowContext(ctx, q, params.Name, params.Description)
 	role = &model.Role{}
 	if err := row.Scan(&role.ID, &role.Name, &role.Description); err != nil {
 		return nil, err
 	}
-	return role, nil
+	if err := replaceRolePermissions(ctx, tx, role, params.Permissions); err != nil {
+		return nil, err
+	}
+	return role, tx.Commit()
 }
 
 func (repo RoleSQLRepository) Update(ctx context.Context, params *model.Role) (role *model.Role, err error) {
-	q := "UPDATE roles SET name = $1, description = $2 WHERE id = $3 RETURNING *"
-	row := repo.Db.QueryRowContext(ctx, q, params.Name, params.Description, params.ID)
+	tx, err := repo.Db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback() }()
+	q := "UPDATE roles SET name = $1, description = $2 "
+	q += "WHERE id = $3 AND deleted_at IS NULL RETURNING id, name, description"
+	row := tx.QueryRowContext(ctx, q, params.Name, params.Description, params.ID)
 	role = &model.Role{}
 	if err := row.Scan(&role.ID, &role.Name, &role.Description); err != nil {
 		return nil, err
 	}
-	return role, nil
+	if err := replaceRolePermissions(ctx, tx, role, params.Permissions); err != nil {
+		return nil, err
+	}
+	return role, tx.Commit()
 }
 
 func (repo RoleSQLRepository) Delete(ctx context.Context, params *model.Role) error {
-	q := "DELETE FROM roles WHERE id = $1"
-	_, err := repo.Db.ExecContext(ctx, q, params.ID)
+	q := "UPDATE roles SET deleted_at = $1, deleted_by = $2 WHERE id = $3 AND deleted_at IS NULL"
+	_, err := repo.Db.ExecContext(ctx, q, time.Now().Unix(), audit.NullActor(ctx), params.ID)
 	return err
 }
 
-func NewRoleSQLRepository() model.ICRUDRepository[model.Role] {
-	return &RoleSQLRepository{Db: config.PostgresPool}
+func (repo RoleSQLRepository) Restore(ctx context.Context, params *model.Role) (role *model.Role, err error) {
+	q := "UPDATE roles SET deleted_at = NULL, deleted_by = NULL "
+	q += "WHERE id = $1 AND deleted_at IS NOT NULL RETURNING id"
+	var id int
+	if err := repo.Db.QueryRowContext(ctx, q, params.ID).Scan(&id); err != nil {
+		return nil, err
+	}
+	return repo.Find(ctx, model.FindWithID, id)
+}
+
+func replaceRolePermissions(ctx context.Context, tx *sql.Tx, role *model.Role, permissions []string) error {
+	q := "DELETE FROM role_permissions WHERE role_id = $1"
+	if _, err := tx.ExecContext(ctx, q, role.ID); err != nil {
+		return err
+	}
+	role.Permissions = []string{}
+	q = "INSERT INTO role_permissions (role_id, permission) VALUES ($1, $2) ON CONFLICT DO NOTHING"
+	for _, permission := range permissions {
+		if _, err := tx.ExecContext(ctx, q, role.ID, permission); err != nil {
+			return err
+		}
+		role.Permissions = append(role.Permissions, permission)
+	}
+	return nil
+}
+
+func NewRoleSQLRepository() model.ISoftDeleteRepository[model.Role] {
+	return &RoleSQLRepository{Db: config.DbPool, Dialect: config.Dialect}
 }
@@ -2,6 +2,7 @@ package sql_test
 
 import (
 	"context"
+	"database/sql"
 	"errors"
 	"regexp"
 	"testing"
@@ -9,6 +10,8 @@ import (
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/aasumitro/posbe/config"
 	repoSql "github.com/aasumitro/posbe/internal/account/repository/sql"
+	"github.com/aasumitro/posbe/pkg/dbtest"
+	"github.com/aasumitro/posbe/pkg/dialect"
 	"github.com/aasumitro/posbe/pkg/model"
 	_ "github.com/lib/pq"
 	"github.com/stretchr/testify/require"
@@ -18,7 +21,7 @@ import (
 type roleRepositoryTestSuite struct {
 	suite.Suite
 	mock     sqlmock.Sqlmock
-	roleRepo model.ICRUDRepository[model.Role]
+	roleRepo model.ISoftDeleteRepository[model.Role]
 }
 
 // SetupSuite is useful in cases where the setup code is time-consuming and isn't modified in any of the tests.
@@ -27,7 +30,7 @@ type roleRepositoryTestSuite struct {
 // SetupSuite could be used once to load the database with data.
 func (suite *roleRepositoryTestSuite) SetupSuite() {
 	var err error
-	config.PostgresPool, suite.mock, err = sqlmock.New(
+	config.DbPool, suite.mock, err = sqlmock.New(
 		sqlmock.QueryMatcherOption(
 			sqlmock.QueryMatcherRegexp))
 	require.NoError(suite.T(), err)
@@ -40,24 +43,30 @@ func (suite *roleRepositoryTestSuite) AfterTest(_, _ string) {
 
 func (suite *roleRepositoryTestSuite) TestRoleRepository_All_ExpectedReturnDataRows() {
 	roles := suite.mock.
-		NewRows([]string{"id", "name", "description", "usage"}).
-		AddRow(1, "test", "test 1", 1).
-		AddRow(2, "test 2", "test 2", 0)
-	q := "SELECT roles.id, roles.name, roles.description, COUNT(users.role_id) as usage "
-	q += "FROM roles LEFT OUTER JOIN users ON users.role_id = roles.id "
-	q += "GROUP BY roles.id ORDER BY roles.id ASC"
+		NewRows([]string{"id", "name", "description", "usage", "permissions", "deleted_at", "deleted_by"}).
+		AddRow(1, "test", "test 1", 1, arrayValue([]string{"catalog.read", "store.read"}), nil, nil).
+		AddRow(2, "test 2", "test 2", 0, arrayValue([]string{}), nil, nil)
+	q := "SELECT roles.id, roles.name, roles.description, COUNT(users.role_id) as usage, "
+	q += config.Dialect.ArrayAgg("permission", "FROM role_permissions WHERE role_id = roles.id") + ", "
+	q += "roles.deleted_at, roles.deleted_by FROM roles "
+	q += "LEFT OUTER JOIN users ON users.role_id = roles.id AND users.deleted_at IS NULL "
+	q += "WHERE ($1 OR roles.deleted_at IS NULL) GROUP BY roles.id ORDER BY roles.id ASC"
 	expectedQuery := regexp.QuoteMeta(q)
 	suite.mock.ExpectQuery(expectedQuery).WillReturnRows(roles)
 	res, err := suite.roleRepo.All(context.TODO())
 	require.Nil(suite.T(), err)
 	require.NoError(suite.T(), err)
 	require.NotNil(suite.T(), res)
+	require.Equal(suite.T(), []string{"catalog.read", "store.read"}, res[0].Permissions)
+	require.Empty(suite.T(), res[1].Permissions)
 }
 
 func (suite *roleRepositoryTestSuite) TestRoleRepository_All_ExpectedReturnErrorFromQuery() {
-	q := "SELECT roles.id, roles.name, roles.description, COUNT(users.role_id) as usage "
-	q += "FROM roles LEFT OUTER JOIN users ON users.role_id = roles.id "
-	q += "GROUP BY roles.id ORDER BY roles.id ASC"
+	q := "SELECT roles.id, roles.name, roles.description, COUNT(users.role_id) as usage, "
+	q += config.Dialect.ArrayAgg("permission", "FROM role_permissions WHERE role_id = roles.id") + ", "
+	q += "roles.deleted_at, roles.deleted_by FROM roles "
+	q += "LEFT OUTER JOIN users ON users.role_id = roles.id AND users.deleted_at IS NULL "
+	q += "WHERE ($1 OR roles.deleted_at IS NULL) GROUP BY roles.id ORDER BY roles.id ASC"
 	expectedQuery := regexp.QuoteMeta(q)
 	suite.mock.ExpectQuery(expectedQuery).WillReturnError(errors.New(""))
 	res, err := suite.roleRepo.All(context.TODO())
@@ -67,12 +76,14 @@ func (suite *roleRepositoryTestSuite) TestRoleRepository_All_ExpectedReturnError
 
 func (suite *roleRepositoryTestSuite) TestRoleRepository_All_ExpectedReturnErrorFromScan() {
 	roles := suite.mock.
-		NewRows([]string{"id", "name", "description", "usage"}).
-		AddRow(1, "test", "test 1", 1).
-		AddRow(nil, nil, nil, nil)
-	q := "SELECT roles.id, roles.name, roles.description, COUNT(users.role_id) as usage "
-	q += "FROM roles LEFT OUTER JOIN users ON users.role_id = roles.id "
-	q += "GROUP BY roles.id ORDER BY roles.id ASC"
+		NewRows([]string{"id", "name", "description", "usage", "permissions", "deleted_at", "deleted_by"}).
+		AddRow(1, "test", "test 1", 1, arrayValue([]string{"catalog.read", "store.read"}), nil, nil).
+		AddRow(nil, nil, nil, nil, nil, nil, nil)
+	q := "SELECT roles.id, roles.name, roles.description, COUNT(users.role_id) as usage, "
+	q += config.Dialect.ArrayAgg("permission", "FROM role_permissions WHERE role_id = roles.id") + ", "
+	q += "roles.deleted_at, roles.deleted_by FROM roles "
+	q += "LEFT OUTER JOIN users ON users.role_id = roles.id AND users.deleted_at IS NULL "
+	q += "WHERE ($1 OR roles.deleted_at IS NULL) GROUP BY roles.id ORDER BY roles.id ASC"
 	expectedQuery := regexp.QuoteMeta(q)
 	suite.mock.ExpectQuery(expectedQuery).WillReturnRows(roles)
 	res, err := suite.roleRepo.All(context.TODO())
@@ -82,11 +93,13 @@ func (suite *roleRepositoryTestSuite) TestRoleRepository_All_ExpectedReturnError
 
 func (suite *roleRepositoryTestSuite) TestRoleRepository_Find_ExpectedSuccess() {
 	role := suite.mock.
-		NewRows([]string{"id", "name", "description", "usage"}).
-		AddRow(1, "test", "test 1", 1)
-	q := "SELECT roles.id, roles.name, roles.description, COUNT(users.role_id) as usage "
-	q += "FROM roles LEFT OUTER JOIN users ON users.role_id = roles.id "
-	q += "WHERE roles.id = $1 GROUP BY roles.id LIMIT 1"
+		NewRows([]string{"id", "name", "description", "usage", "permissions", "deleted_at", "deleted_by"}).
+		AddRow(1, "test", "test 1", 1, arrayValue([]string{"catalog.read", "store.read"}), nil, nil)
+	q := "SELECT roles.id, roles.name, roles.description, COUNT(users.role_id) as usage, "
+	q += config.Dialect.ArrayAgg("permission", "FROM role_permissions WHERE role_id = roles.id") + ", "
+	q += "roles.deleted_at, roles.deleted_by FROM roles "
+	q += "LEFT OUTER JOIN users ON users.role_id = roles.id AND users.deleted_at IS NULL "
+	q += "WHERE roles.id = $1 AND ($2 OR roles.deleted_at IS NULL) GROUP BY roles.id LIMIT 1"
 	expectedQuery := regexp.QuoteMeta(q)
 	suite.mock.ExpectQuery(expectedQuery).WillReturnRows(role)
 	res, err := suite.roleRepo.Find(context.TODO(), model.FindWithID, 1)
@@ -97,11 +110,13 @@ func (suite *roleRepositoryTestSuite) TestRoleRepository_Find_ExpectedSuccess()
 
 func (suite *roleRepositoryTestSuite) TestRoleRepository_Find_ExpectedError() {
 	role := suite.mock.
-		NewRows([]string{"id", "name", "description", "usage"}).
-		AddRow(nil, nil, nil, nil)
-	q := "SELECT roles.id, roles.name, roles.description, COUNT(users.role_id) as usage "
-	q += "FROM roles LEFT OUTER JOIN users ON users.role_id = roles.id "
-	q += "WHERE roles.id = $1 GROUP BY roles.id LIMIT 1"
+		NewRows([]string{"id", "name", "description", "usage", "permissions", "deleted_at", "deleted_by"}).
+		AddRow(nil, nil, nil, nil, nil, nil, nil)
+	q := "SELECT roles.id, roles.name, roles.description, COUNT(users.role_id) as usage, "
+	q += config.Dialect.ArrayAgg("permission", "FROM role_permissions WHERE role_id = roles.id") + ", "
+	q += "roles.deleted_at, roles.deleted_by FROM roles "
+	q += "LEFT OUTER JOIN users ON users.role_id = roles.id AND users.deleted_at IS NULL "
+	q += "WHERE roles.id = $1 AND ($2 OR roles.deleted_at IS NULL) GROUP BY roles.id LIMIT 1"
 	expectedQuery := regexp.QuoteMeta(q)
 	suite.mock.ExpectQuery(expectedQuery).WillReturnRows(role)
 	res, err := suite.roleRepo.Find(context.TODO(), model.FindWithID, 1)
@@ -110,18 +125,26 @@ func (suite *roleRepositoryTestSuite) TestRoleRepository_Find_ExpectedError() {
 }
 
 func (suite *roleRepositoryTestSuite) TestRoleRepository_Create_ExpectedSuccess() {
-	role := &model.Role{ID: 1, Name: "test", Description: "test"}
+	role := &model.Role{ID: 1, Name: "test", Description: "test",
+		Permissions: []string{model.PermissionCatalogRead}}
 	rows := suite.mock.
 		NewRows([]string{"id", "name", "description"}).
 		AddRow(1, "test", "test 1")
-	expectedQuery := regexp.QuoteMeta("INSERT INTO roles (name, description) values ($1, $2) RETURNING *")
+	suite.mock.ExpectBegin()
+	expectedQuery := regexp.QuoteMeta("INSERT INTO roles (name, description) values ($1, $2) RETURNING id, name, description")
 	suite.mock.ExpectQuery(expectedQuery).
 		WithArgs(role.Name, role.Description).
 		WillReturnRows(rows).
 		WillReturnError(nil)
+	suite.mock.ExpectExec("DELETE FROM role_permissions WHERE role_id = \\$1").
+		WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 0))
+	suite.mock.ExpectExec("INSERT INTO role_permissions").
+		WithArgs(1, model.PermissionCatalogRead).WillReturnResult(sqlmock.NewResult(0, 1))
+	suite.mock.ExpectCommit()
 	res, err := suite.roleRepo.Create(context.TODO(), role)
 	require.Nil(suite.T(), err)
 	require.NotNil(suite.T(), res)
+	require.Equal(suite.T(), []string{model.PermissionCatalogRead}, res.Permissions)
 }
 
 func (suite *roleRepositoryTestSuite) TestRoleRepository_Create_ExpectedError() {
@@ -129,11 +152,13 @@ func (suite *roleRepositoryTestSuite) TestRoleRepository_Create_ExpectedError()
 	rows := suite.mock.
 		NewRows([]string{"id", "name", "description"}).
 		AddRow(1, nil, nil)
-	expectedQuery := regexp.QuoteMeta("INSERT INTO roles (name, description) values ($1, $2) RETURNING *")
+	suite.mock.ExpectBegin()
+	expectedQuery := regexp.QuoteMeta("INSERT INTO roles (name, description) values ($1, $2) RETURNING id, name, description")
 	suite.mock.ExpectQuery(expectedQuery).
 		WithArgs(role.Name, role.Description).
 		WillReturnRows(rows).
 		WillReturnError(nil)
+	suite.mock.ExpectRollback()
 	res, err := suite.roleRepo.Create(context.TODO(), role)
 	require.Nil(suite.T(), res)
 	require.NotNil(suite.T(), err)
@@ -144,13 +169,18 @@ func (suite *roleRepositoryTestSuite) TestRoleRepository_Update_ExpectedSuccess(
 	rows := suite.mock.
 		NewRows([]string{"id", "name", "description"}).
 		AddRow(1, "test", "test")
-	expectedQuery := regexp.QuoteMeta("UPDATE roles SET name = $1, description = $2 WHERE id = $3 RETURNING *")
+	suite.mock.ExpectBegin()
+	expectedQuery := regexp.QuoteMeta("UPDATE roles SET name = $1, description = $2 WHERE id = $3 AND deleted_at IS NULL RETURNING id, name, description")
 	suite.mock.ExpectQuery(expectedQuery).
 		WithArgs(role.Name, role.Description, role.ID).
 		WillReturnRows(rows).
 		WillReturnError(nil)
+	suite.mock.ExpectExec("DELETE FROM role_permissions WHERE role_id = \\$1").
+		WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 2))
+	suite.mock.ExpectCommit()
 	res, err := suite.roleRepo.Update(context.TODO(), role)
 	require.Nil(suite.T(), err)
+	require.Empty(suite.T(), res.Permissions)
 	require.NotNil(suite.T(), res)
 }
 
@@ -159,26 +189,84 @@ func (suite *roleRepositoryTestSuite) TestRoleRepository_Update_ExpectedError()
 	rows := suite.mock.
 		NewRows([]string{"id", "name", "description"}).
 		AddRow(1, nil, nil)
-	expectedQuery := regexp.QuoteMeta("UPDATE roles SET name = $1, description = $2 WHERE id = $3 RETURNING *")
+	suite.mock.ExpectBegin()
+	expectedQuery := regexp.QuoteMeta("UPDATE roles SET name = $1, description = $2 WHERE id = $3 AND deleted_at IS NULL RETURNING id, name, description")
 	suite.mock.ExpectQuery(expectedQuery).
 		WithArgs(role.Name, role.Description, role.ID).
 		WillReturnRows(rows).
 		WillReturnError(nil)
+	suite.mock.ExpectRollback()
 	res, err := suite.roleRepo.Update(context.TODO(), role)
 	require.Nil(suite.T(), res)
 	require.NotNil(suite.T(), err)
 }
 
 func (suite *roleRepositoryTestSuite) TestRoleRepository_Delete_ExpectedSuccess() {
-	expectedQuery := regexp.QuoteMeta("DELETE FROM roles")
+	expectedQuery := regexp.QuoteMeta("UPDATE roles SET deleted_at = $1, deleted_by = $2 WHERE id = $3 AND deleted_at IS NULL")
 	suite.mock.ExpectExec(expectedQuery).
-		WithArgs(1).
+		WithArgs(sqlmock.AnyArg(), nil, 1).
 		WillReturnResult(sqlmock.NewResult(0, 1))
 	role := &model.Role{ID: 1, Name: "test", Description: "test"}
 	err := suite.roleRepo.Delete(context.TODO(), role)
 	require.Nil(suite.T(), err)
 }
 
+func (suite *roleRepositoryTestSuite) TestRoleRepository_Restore_ExpectedSuccess() {
+	suite.mock.ExpectQuery("UPDATE roles SET deleted_at = NULL, deleted_by = NULL WHERE id = \\$1 AND deleted_at IS NOT NULL RETURNING id").
+		WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	role := suite.mock.
+		NewRows([]string{"id", "name", "description", "usage", "permissions", "deleted_at", "deleted_by"}).
+		AddRow(1, "test", "test 1", 0, arrayValue([]string{}), nil, nil)
+	suite.mock.ExpectQuery("FROM roles (.+) WHERE roles.id = \\$1").
+		WithArgs(1, false).WillReturnRows(role)
+	res, err := suite.roleRepo.Restore(context.TODO(), &model.Role{ID: 1})
+	require.Nil(suite.T(), err)
+	require.Equal(suite.T(), 1, res.ID)
+}
+
+func (suite *roleRepositoryTestSuite) TestRoleRepository_Restore_ExpectedError() {
+	suite.mock.ExpectQuery("UPDATE roles SET deleted_at = NULL").
+		WithArgs(1).WillReturnError(errors.New("sql: no rows in result set"))
+	res, err := suite.roleRepo.Restore(context.TODO(), &model.Role{ID: 1})
+	require.Nil(suite.T(), res)
+	require.NotNil(suite.T(), err)
+}
+
+func (suite *roleRepositoryTestSuite) TestRoleRepository_SoftDelete() {
+	dbtest.Migrated(suite.T(), config.Dialect.Name())
+	ctx := context.TODO()
+	repo := repoSql.NewRoleSQLRepository()
+	role, err := repo.Create(ctx, &model.Role{Name: "supervisor", Description: "lorem",
+		Permissions: []string{model.PermissionOrderWrite, model.PermissionOrderTender}})
+	require.NoError(suite.T(), err)
+	role, err = repo.Find(ctx, model.FindWithID, role.ID)
+	require.NoError(suite.T(), err)
+	require.ElementsMatch(suite.T(), []string{model.PermissionOrderWrite, model.PermissionOrderTender}, role.Permissions)
+
+	role.Permissions = []string{model.PermissionOrderWrite}
+	_, err = repo.Update(ctx, role)
+	require.NoError(suite.T(), err)
+	role, err = repo.Find(ctx, model.FindWithID, role.ID)
+	require.NoError(suite.T(), err)
+	require.Equal(suite.T(), []string{model.PermissionOrderWrite}, role.Permissions)
+
+	require.NoError(suite.T(), repo.Delete(ctx, role))
+	_, err = repo.Find(ctx, model.FindWithID, role.ID)
+	require.ErrorIs(suite.T(), err, sql.ErrNoRows)
+	_, err = repo.Restore(ctx, role)
+	require.NoError(suite.T(), err)
+	roles, err := repo.All(ctx)
+	require.NoError(suite.T(), err)
+	// the seeded admin is counted, the deleted users are not
+	require.Equal(suite.T(), "admin", roles[0].Name)
+	require.Equal(suite.T(), 1, roles[0].Usage)
+}
+
 func TestRoleRepository(t *testing.T) {
-	suite.Run(t, new(roleRepositoryTestSuite))
+	for _, d := range dialect.All() {
+		t.Run(d.Name(), func(t *testing.T) {
+			config.Dialect = d
+			suite.Run(t, new(roleRepositoryTestSuite))
+		})
+	}
 }
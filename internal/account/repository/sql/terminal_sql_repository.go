@@ -0,0 +1,118 @@
+package sql
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/aasumitro/posbe/config"
+	"github.com/aasumitro/posbe/pkg/audit"
+	"github.com/aasumitro/posbe/pkg/model"
+)
+
+const terminalColumns = "id, name, idle_timeout, disabled, created_at, COALESCE(updated_at, 0), " +
+	"deleted_at, deleted_by"
+
+type TerminalSQLRepository struct {
+	Db *sql.DB
+}
+
+func (repo TerminalSQLRepository) All(ctx context.Context) (terminals []*model.Terminal, err error) {
+	q := "SELECT " + terminalColumns + " FROM terminals WHERE ($1 OR deleted_at IS NULL) ORDER BY id ASC"
+	rows, err := repo.Db.QueryContext(ctx, q, model.IncludeDeleted(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer func(rows *sql.Rows) { _ = rows.Close() }(rows)
+	for rows.Next() {
+		var terminal model.Terminal
+		if err := rows.Scan(
+			&terminal.ID, &terminal.Name, &terminal.IdleTimeout,
+			&terminal.Disabled, &terminal.CreatedAt, &terminal.UpdatedAt,
+			&terminal.DeletedAt, &terminal.DeletedBy,
+		); err != nil {
+			return nil, err
+		}
+		terminals = append(terminals, &terminal)
+	}
+	return terminals, rows.Err()
+}
+
+func (repo TerminalSQLRepository) Find(ctx context.Context, key model.FindWith, val any) (terminal *model.Terminal, err error) {
+	q := "SELECT " + terminalColumns + " FROM terminals WHERE ($2 OR deleted_at IS NULL) AND "
+	//goland:noinspection GoSwitchMissingCasesForIotaConsts
+	switch key {
+	case model.FindWithKey:
+		q += "key_hash = $1"
+	default:
+		q += "id = $1"
+	}
+	q += " LIMIT 1"
+	terminal = &model.Terminal{}
+	if err := repo.Db.QueryRowContext(ctx, q, val, model.IncludeDeleted(ctx)).Scan(
+		&terminal.ID, &terminal.Name, &terminal.IdleTimeout,
+		&terminal.Disabled, &terminal.CreatedAt, &terminal.UpdatedAt,
+		&terminal.DeletedAt, &terminal.DeletedBy,
+	); err != nil {
+		return nil, err
+	}
+	return terminal, nil
+}
+
+func (repo TerminalSQLRepository) Create(ctx context.Context, params *model.Terminal) (terminal *model.Terminal, err error) {
+	q := "INSERT INTO terminals (name, key_hash, idle_timeout, disabled, created_at) "
+	q += "VALUES ($1, $2, $3, $4, $5) RETURNING " + terminalColumns
+	terminal = &model.Terminal{}
+	if err := repo.Db.QueryRowContext(ctx, q,
+		params.Name, params.KeyHash, params.IdleTimeout,
+		params.Disabled, time.Now().Unix(),
+	).Scan(
+		&terminal.ID, &terminal.Name, &terminal.IdleTimeout,
+		&terminal.Disabled, &terminal.CreatedAt, &terminal.UpdatedAt,
+		&terminal.DeletedAt, &terminal.DeletedBy,
+	); err != nil {
+		return nil, err
+	}
+	return terminal, nil
+}
+
+func (repo TerminalSQLRepository) Update(ctx context.Context, params *model.Terminal) (terminal *model.Terminal, err error) {
+	q := "UPDATE terminals SET name = $1, idle_timeout = $2, disabled = $3, updated_at = $4 "
+	q += "WHERE id = $5 AND deleted_at IS NULL RETURNING " + terminalColumns
+	terminal = &model.Terminal{}
+	if err := repo.Db.QueryRowContext(ctx, q,
+		params.Name, params.IdleTimeout, params.Disabled,
+		time.Now().Unix(), params.ID,
+	).Scan(
+		&terminal.ID, &terminal.Name, &terminal.IdleTimeout,
+		&terminal.Disabled, &terminal.CreatedAt, &terminal.UpdatedAt,
+		&terminal.DeletedAt, &terminal.DeletedBy,
+	); err != nil {
+		return nil, err
+	}
+	return terminal, nil
+}
+
+func (repo TerminalSQLRepository) Delete(ctx context.Context, params *model.Terminal) error {
+	q := "UPDATE terminals SET deleted_at = $1, deleted_by = $2 WHERE id = $3 AND deleted_at IS NULL"
+	_, err := repo.Db.ExecContext(ctx, q, time.Now().Unix(), audit.NullActor(ctx), params.ID)
+	return err
+}
+
+func (repo TerminalSQLRepository) Restore(ctx context.Context, params *model.Terminal) (terminal *model.Terminal, err error) {
+	q := "UPDATE terminals SET deleted_at = NULL, deleted_by = NULL "
+	q += "WHERE id = $1 AND deleted_at IS NOT NULL RETURNING " + terminalColumns
+	terminal = &model.Terminal{}
+	if err := repo.Db.QueryRowContext(ctx, q, params.ID).Scan(
+		&terminal.ID, &terminal.Name, &terminal.IdleTimeout,
+		&terminal.Disabled, &terminal.CreatedAt, &terminal.UpdatedAt,
+		&terminal.DeletedAt, &terminal.DeletedBy,
+	); err != nil {
+		return nil, err
+	}
+	return terminal, nil
+}
+
+func NewTerminalSQLRepository() model.ISoftDeleteRepository[model.Terminal] {
+	return &TerminalSQLRepository{Db: config.DbPool}
+}
@@ -0,0 +1,139 @@
+package sql_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/aasumitro/posbe/config"
+	repoSql "github.com/aasumitro/posbe/internal/account/repository/sql"
+	"github.com/aasumitro/posbe/pkg/dbtest"
+	"github.com/aasumitro/posbe/pkg/dialect"
+	"github.com/aasumitro/posbe/pkg/model"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type terminalRepositoryTestSuite struct {
+	suite.Suite
+	mock    sqlmock.Sqlmock
+	repo    model.ISoftDeleteRepository[model.Terminal]
+	columns []string
+}
+
+func (suite *terminalRepositoryTestSuite) SetupSuite() {
+	var err error
+	config.DbPool, suite.mock, err = sqlmock.New(
+		sqlmock.QueryMatcherOption(
+			sqlmock.QueryMatcherRegexp))
+	require.NoError(suite.T(), err)
+	suite.repo = repoSql.NewTerminalSQLRepository()
+	suite.columns = []string{"id", "name", "idle_timeout", "disabled", "created_at", "updated_at", "deleted_at", "deleted_by"}
+}
+
+func (suite *terminalRepositoryTestSuite) AfterTest(_, _ string) {
+	require.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+func (suite *terminalRepositoryTestSuite) TestRepository_All_ExpectReturnRows() {
+	suite.mock.ExpectQuery("SELECT (.+) FROM terminals WHERE \\(\\$1 OR deleted_at IS NULL\\) ORDER BY id ASC").
+		WillReturnRows(suite.mock.NewRows(suite.columns).
+			AddRow(1, "tablet 1", 300, false, 123, 0, nil, nil).
+			AddRow(2, "tablet 2", 60, true, 123, 456, nil, nil))
+	res, err := suite.repo.All(context.TODO())
+	require.NoError(suite.T(), err)
+	require.Len(suite.T(), res, 2)
+	require.True(suite.T(), res[1].Disabled)
+}
+
+func (suite *terminalRepositoryTestSuite) TestRepository_All_ExpectReturnError() {
+	suite.mock.ExpectQuery("SELECT (.+) FROM terminals").
+		WillReturnError(errors.New("UNEXPECTED"))
+	res, err := suite.repo.All(context.TODO())
+	require.Nil(suite.T(), res)
+	require.Error(suite.T(), err)
+}
+
+func (suite *terminalRepositoryTestSuite) TestRepository_Find_ExpectReturnRow() {
+	suite.mock.ExpectQuery("SELECT (.+) FROM terminals WHERE (.+) AND key_hash = \\$1 LIMIT 1").
+		WithArgs("hash", false).
+		WillReturnRows(suite.mock.NewRows(suite.columns).AddRow(1, "tablet 1", 300, false, 123, 0, nil, nil))
+	res, err := suite.repo.Find(context.TODO(), model.FindWithKey, "hash")
+	require.NoError(suite.T(), err)
+	require.Equal(suite.T(), "tablet 1", res.Name)
+}
+
+func (suite *terminalRepositoryTestSuite) TestRepository_Find_ExpectReturnErrorNoRows() {
+	suite.mock.ExpectQuery("SELECT (.+) FROM terminals WHERE (.+) AND id = \\$1 LIMIT 1").
+		WithArgs(9, false).WillReturnError(sql.ErrNoRows)
+	res, err := suite.repo.Find(context.TODO(), model.FindWithID, 9)
+	require.Nil(suite.T(), res)
+	require.ErrorIs(suite.T(), err, sql.ErrNoRows)
+}
+
+func (suite *terminalRepositoryTestSuite) TestRepository_Create_ExpectReturnRow() {
+	suite.mock.ExpectQuery("INSERT INTO terminals (.+) RETURNING").
+		WithArgs("tablet 1", "hash", 300, false, sqlmock.AnyArg()).
+		WillReturnRows(suite.mock.NewRows(suite.columns).AddRow(1, "tablet 1", 300, false, 123, 0, nil, nil))
+	res, err := suite.repo.Create(context.TODO(), &model.Terminal{
+		Name: "tablet 1", KeyHash: "hash", IdleTimeout: 300})
+	require.NoError(suite.T(), err)
+	require.Equal(suite.T(), 1, res.ID)
+}
+
+func (suite *terminalRepositoryTestSuite) TestRepository_Update_ExpectReturnRow() {
+	suite.mock.ExpectQuery("UPDATE terminals SET (.+) WHERE id = \\$5 AND deleted_at IS NULL RETURNING").
+		WithArgs("tablet 1", 60, true, sqlmock.AnyArg(), 1).
+		WillReturnRows(suite.mock.NewRows(suite.columns).AddRow(1, "tablet 1", 60, true, 123, 456, nil, nil))
+	res, err := suite.repo.Update(context.TODO(), &model.Terminal{
+		ID: 1, Name: "tablet 1", IdleTimeout: 60, Disabled: true})
+	require.NoError(suite.T(), err)
+	require.Equal(suite.T(), int64(456), res.UpdatedAt)
+}
+
+func (suite *terminalRepositoryTestSuite) TestRepository_Delete_ExpectSuccess() {
+	suite.mock.ExpectExec("UPDATE terminals SET deleted_at = \\$1, deleted_by = \\$2 WHERE id = \\$3 AND deleted_at IS NULL").
+		WithArgs(sqlmock.AnyArg(), nil, 1).WillReturnResult(sqlmock.NewResult(0, 1))
+	err := suite.repo.Delete(context.TODO(), &model.Terminal{ID: 1})
+	require.NoError(suite.T(), err)
+}
+
+func (suite *terminalRepositoryTestSuite) TestRepository_Restore_ExpectReturnRow() {
+	suite.mock.ExpectQuery("UPDATE terminals SET deleted_at = NULL, deleted_by = NULL WHERE id = \\$1 AND deleted_at IS NOT NULL RETURNING").
+		WithArgs(1).
+		WillReturnRows(suite.mock.NewRows(suite.columns).AddRow(1, "tablet 1", 300, false, 123, 0, nil, nil))
+	res, err := suite.repo.Restore(context.TODO(), &model.Terminal{ID: 1})
+	require.NoError(suite.T(), err)
+	require.Nil(suite.T(), res.DeletedAt)
+}
+
+func (suite *terminalRepositoryTestSuite) TestTerminalRepository_SoftDelete() {
+	dbtest.Migrated(suite.T(), config.Dialect.Name())
+	ctx := context.TODO()
+	repo := repoSql.NewTerminalSQLRepository()
+	terminal, err := repo.Create(ctx, &model.Terminal{Name: "bar", KeyHash: "hash", IdleTimeout: 60})
+	require.NoError(suite.T(), err)
+	found, err := repo.Find(ctx, model.FindWithKey, "hash")
+	require.NoError(suite.T(), err)
+	require.Equal(suite.T(), terminal.ID, found.ID)
+	found.Disabled = true
+	terminal, err = repo.Update(ctx, found)
+	require.NoError(suite.T(), err)
+	require.True(suite.T(), terminal.Disabled)
+	require.NoError(suite.T(), repo.Delete(ctx, terminal))
+	_, err = repo.Find(ctx, model.FindWithID, terminal.ID)
+	require.ErrorIs(suite.T(), err, sql.ErrNoRows)
+	_, err = repo.Restore(ctx, terminal)
+	require.NoError(suite.T(), err)
+}
+
+func TestTerminalRepository(t *testing.T) {
+	for _, d := range dialect.All() {
+		t.Run(d.Name(), func(t *testing.T) {
+			config.Dialect = d
+			suite.Run(t, new(terminalRepositoryTestSuite))
+		})
+	}
+}
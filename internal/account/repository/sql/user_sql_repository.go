@@ -3,20 +3,29 @@ package sql
 import (
 	"context"
 	"database/sql"
+	"time"
 
 	"github.com/aasumitro/posbe/config"
+	"github.com/aasumitro/posbe/pkg/audit"
 	"github.com/aasumitro/posbe/pkg/model"
 )
 
+// userReturning read the written user with its role, the role is selected
+// by RETURNING as sqlite has no data-modifying WITH
+const userReturning = "RETURNING id, role_id, name, username, email, phone, password, role_id, " +
+	"(SELECT name FROM roles WHERE roles.id = users.role_id), " +
+	"(SELECT description FROM roles WHERE roles.id = users.role_id), deleted_at, deleted_by"
+
 type UserSQLRepository struct {
 	Db *sql.DB
 }
 
 func (repo UserSQLRepository) All(ctx context.Context) (users []*model.User, err error) {
 	q := `SELECT u.id, u.role_id, u.name, u.username, u.email, u.phone, 
-		r.id as role_id, r.name as role_name, r.description FROM users as u 
-		JOIN roles as r ON r.id = u.role_id`
-	rows, err := repo.Db.QueryContext(ctx, q)
+		r.id as role_id, r.name as role_name, r.description, u.deleted_at, u.deleted_by 
+		FROM users as u JOIN roles as r ON r.id = u.role_id 
+		WHERE ($1 OR u.deleted_at IS NULL)`
+	rows, err := repo.Db.QueryContext(ctx, q, model.IncludeDeleted(ctx))
 	if err != nil {
 		return nil, err
 	}
@@ -29,6 +38,7 @@ func (repo UserSQLRepository) All(ctx context.Context) (users []*model.User, err
 			&user.Email, &user.Phone,
 			&user.Role.ID, &user.Role.Name,
 			&user.Role.Description,
+			&user.DeletedAt, &user.DeletedBy,
 		); err != nil {
 			return nil, err
 		}
@@ -37,7 +47,7 @@ func (repo UserSQLRepository) All(ctx context.Context) (users []*model.User, err
 			Email: user.Email, Phone: user.Phone, Role: model.Role{
 				ID: user.Role.ID, Name: user.Role.Name,
 				Description: user.Role.Description,
-			},
+			}, SoftDelete: user.SoftDelete,
 		})
 	}
 	return users, nil
@@ -46,8 +56,8 @@ func (repo UserSQLRepository) All(ctx context.Context) (users []*model.User, err
 func (repo UserSQLRepository) Find(ctx context.Context, key model.FindWith, val any) (user *model.User, err error) {
 	q := `
 		SELECT u.id, u.role_id, u.name, u.username, u.email, u.phone, u.password, 
-		r.id as role_id, r.name as role_name, r.description FROM users as u 
-		JOIN roles as r ON r.id = u.role_id WHERE
+		r.id as role_id, r.name as role_name, r.description, u.deleted_at, u.deleted_by 
+		FROM users as u JOIN roles as r ON r.id = u.role_id WHERE ($2 OR u.deleted_at IS NULL) AND 
 	`
 	//goland:noinspection GoSwitchMissingCasesForIotaConsts
 	switch key {
@@ -63,16 +73,13 @@ func (repo UserSQLRepository) Find(ctx context.Context, key model.FindWith, val
 		q += "u.phone = $1"
 	}
 	q += " LIMIT 1"
-	row := repo.Db.QueryRowContext(ctx, q, val)
+	row := repo.Db.QueryRowContext(ctx, q, val, model.IncludeDeleted(ctx))
 	return scanData(row)
 }
 
 func (repo UserSQLRepository) Create(ctx context.Context, params *model.User) (user *model.User, err error) {
-	q := "WITH u AS (INSERT INTO users(role_id, name, username, email, phone, password) "
-	q += "values ($1, $2, $3, $4, $5, $6) RETURNING *) "
-	q += "SELECT u.id, u.role_id, u.name, u.username, u.email, u.phone, u.password, "
-	q += "r.id as role_id, r.name as role_name, r.description FROM u "
-	q += "JOIN roles as r ON r.id = u.role_id"
+	q := "INSERT INTO users(role_id, name, username, email, phone, password) "
+	q += "values ($1, $2, $3, $4, $5, $6) " + userReturning
 	row := repo.Db.QueryRowContext(
 		ctx, q, params.RoleID, params.Name,
 		params.Username, params.Email, params.Phone,
@@ -81,11 +88,8 @@ func (repo UserSQLRepository) Create(ctx context.Context, params *model.User) (u
 }
 
 func (repo UserSQLRepository) Update(ctx context.Context, params *model.User) (user *model.User, err error) {
-	q := "WITH u AS (UPDATE users SET role_id = $1, name = $2, username = $3, email = $4, "
-	q += "phone = $5, password = $6 WHERE id = $7 RETURNING *) "
-	q += "SELECT u.id, u.role_id, u.name, u.username, u.email, u.phone, u.password, "
-	q += "r.id as role_id, r.name as role_name, r.description FROM u "
-	q += "JOIN roles as r ON r.id = u.role_id"
+	q := "UPDATE users SET role_id = $1, name = $2, username = $3, email = $4, "
+	q += "phone = $5, password = $6 WHERE id = $7 AND deleted_at IS NULL " + userReturning
 	row := repo.Db.QueryRowContext(
 		ctx, q, params.RoleID, params.Name, params.Username,
 		params.Email, params.Phone, params.Password, params.ID)
@@ -93,17 +97,25 @@ func (repo UserSQLRepository) Update(ctx context.Context, params *model.User) (u
 }
 
 func (repo UserSQLRepository) Delete(ctx context.Context, params *model.User) error {
-	q := "DELETE FROM users WHERE id = $1"
-	_, err := repo.Db.ExecContext(ctx, q, params.ID)
+	q := "UPDATE users SET deleted_at = $1, deleted_by = $2 WHERE id = $3 AND deleted_at IS NULL"
+	_, err := repo.Db.ExecContext(ctx, q, time.Now().Unix(), audit.NullActor(ctx), params.ID)
 	return err
 }
 
+func (repo UserSQLRepository) Restore(ctx context.Context, params *model.User) (user *model.User, err error) {
+	q := "UPDATE users SET deleted_at = NULL, deleted_by = NULL "
+	q += "WHERE id = $1 AND deleted_at IS NOT NULL " + userReturning
+	row := repo.Db.QueryRowContext(ctx, q, params.ID)
+	return scanData(row)
+}
+
 func scanData(row *sql.Row) (data *model.User, err error) {
 	var user model.User
 	if err := row.Scan(
 		&user.ID, &user.RoleID, &user.Name,
 		&user.Username, &user.Email, &user.Phone, &user.Password,
 		&user.Role.ID, &user.Role.Name, &user.Role.Description,
+		&user.DeletedAt, &user.DeletedBy,
 	); err != nil {
 		return nil, err
 	}
@@ -112,10 +124,10 @@ func scanData(row *sql.Row) (data *model.User, err error) {
 		Email: user.Email, Phone: user.Phone, Role: model.Role{
 			ID: user.Role.ID, Name: user.Role.Name,
 			Description: user.Role.Description,
-		},
+		}, SoftDelete: user.SoftDelete,
 	}, nil
 }
 
-func NewUserSQLRepository() model.ICRUDRepository[model.User] {
-	return &UserSQLRepository{Db: config.PostgresPool}
+func NewUserSQLRepository() model.ISoftDeleteRepository[model.User] {
+	return &UserSQLRepository{Db: config.DbPool}
 }
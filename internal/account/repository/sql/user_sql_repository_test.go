@@ -2,6 +2,7 @@ package sql_test
 
 import (
 	"context"
+	"database/sql"
 	"errors"
 	"regexp"
 	"testing"
@@ -9,6 +10,8 @@ import (
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/aasumitro/posbe/config"
 	repoSql "github.com/aasumitro/posbe/internal/account/repository/sql"
+	"github.com/aasumitro/posbe/pkg/dbtest"
+	"github.com/aasumitro/posbe/pkg/dialect"
 	"github.com/aasumitro/posbe/pkg/model"
 	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
@@ -17,12 +20,12 @@ import (
 type userRepositoryTestSuite struct {
 	suite.Suite
 	mock     sqlmock.Sqlmock
-	userRepo model.ICRUDRepository[model.User]
+	userRepo model.ISoftDeleteRepository[model.User]
 }
 
 func (suite *userRepositoryTestSuite) SetupSuite() {
 	var err error
-	config.PostgresPool, suite.mock, err = sqlmock.New(
+	config.DbPool, suite.mock, err = sqlmock.New(
 		sqlmock.QueryMatcherOption(
 			sqlmock.QueryMatcherRegexp))
 	require.NoError(suite.T(), err)
@@ -35,12 +38,12 @@ func (suite *userRepositoryTestSuite) AfterTest(_, _ string) {
 
 func (suite *userRepositoryTestSuite) TestUserRepository_All_ExpectedReturnDataRows() {
 	users := suite.mock.
-		NewRows([]string{"id", "users.role_id", "name", "username", "email", "phone", "role_id", "role_name", "role_description"}).
-		AddRow(1, 1, "lorem ipsum", "lorem", "lorem@ipsum.id", "+6275555", 1, "test", "test 12345").
-		AddRow(2, 2, "ipsum lorem", "ipsum", "ipsum@lorem.id", "+6278888", 1, "test", "test 12345")
+		NewRows([]string{"id", "users.role_id", "name", "username", "email", "phone", "role_id", "role_name", "role_description", "deleted_at", "deleted_by"}).
+		AddRow(1, 1, "lorem ipsum", "lorem", "lorem@ipsum.id", "+6275555", 1, "test", "test 12345", nil, nil).
+		AddRow(2, 2, "ipsum lorem", "ipsum", "ipsum@lorem.id", "+6278888", 1, "test", "test 12345", nil, nil)
 	q := "SELECT u.id, u.role_id, u.name, u.username, u.email, u.phone, "
-	q += "r.id as role_id, r.name as role_name, r.description FROM users as u "
-	q += "JOIN roles as r ON r.id = u.role_id"
+	q += "r.id as role_id, r.name as role_name, r.description, u.deleted_at, u.deleted_by "
+	q += "FROM users as u JOIN roles as r ON r.id = u.role_id WHERE ($1 OR u.deleted_at IS NULL)"
 	expectedQuery := regexp.QuoteMeta(q)
 	suite.mock.ExpectQuery(expectedQuery).WillReturnRows(users)
 	res, err := suite.userRepo.All(context.TODO())
@@ -51,8 +54,8 @@ func (suite *userRepositoryTestSuite) TestUserRepository_All_ExpectedReturnDataR
 
 func (suite *userRepositoryTestSuite) TestUserRepository_All_ExpectedReturnErrorFromQuery() {
 	q := "SELECT u.id, u.role_id, u.name, u.username, u.email, u.phone, "
-	q += "r.id as role_id, r.name as role_name, r.description FROM users as u "
-	q += "JOIN roles as r ON r.id = u.role_id"
+	q += "r.id as role_id, r.name as role_name, r.description, u.deleted_at, u.deleted_by "
+	q += "FROM users as u JOIN roles as r ON r.id = u.role_id WHERE ($1 OR u.deleted_at IS NULL)"
 	expectedQuery := regexp.QuoteMeta(q)
 	suite.mock.ExpectQuery(expectedQuery).WillReturnError(errors.New(""))
 	res, err := suite.userRepo.All(context.TODO())
@@ -62,12 +65,12 @@ func (suite *userRepositoryTestSuite) TestUserRepository_All_ExpectedReturnError
 
 func (suite *userRepositoryTestSuite) TestUserRepository_All_ExpectedReturnErrorFromScan() {
 	users := suite.mock.
-		NewRows([]string{"id", "users.role_id", "name", "username", "email", "phone", "role_id", "role_name", "role_description"}).
-		AddRow(1, 1, "lorem ipsum", "lorem", "lorem@ipsum.id", "+6275555", 1, "test", "test 12345").
-		AddRow(nil, nil, nil, nil, nil, nil, nil, nil, nil)
+		NewRows([]string{"id", "users.role_id", "name", "username", "email", "phone", "role_id", "role_name", "role_description", "deleted_at", "deleted_by"}).
+		AddRow(1, 1, "lorem ipsum", "lorem", "lorem@ipsum.id", "+6275555", 1, "test", "test 12345", nil, nil).
+		AddRow(nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
 	q := "SELECT u.id, u.role_id, u.name, u.username, u.email, u.phone, "
-	q += "r.id as role_id, r.name as role_name, r.description FROM users as u "
-	q += "JOIN roles as r ON r.id = u.role_id"
+	q += "r.id as role_id, r.name as role_name, r.description, u.deleted_at, u.deleted_by "
+	q += "FROM users as u JOIN roles as r ON r.id = u.role_id WHERE ($1 OR u.deleted_at IS NULL)"
 	expectedQuery := regexp.QuoteMeta(q)
 	suite.mock.ExpectQuery(expectedQuery).WillReturnRows(users)
 	res, err := suite.userRepo.All(context.TODO())
@@ -77,11 +80,11 @@ func (suite *userRepositoryTestSuite) TestUserRepository_All_ExpectedReturnError
 
 func (suite *userRepositoryTestSuite) TestUserRepository_Find_ExpectedSuccess() {
 	user := suite.mock.
-		NewRows([]string{"id", "users.role_id", "name", "username", "email", "phone", "password", "role_id", "role_name", "role_description"}).
-		AddRow(1, 1, "lorem ipsum", "lorem", "lorem@ipsum.id", "+6275555", "qwe123", 1, "test", "test 12345")
+		NewRows([]string{"id", "users.role_id", "name", "username", "email", "phone", "password", "role_id", "role_name", "role_description", "deleted_at", "deleted_by"}).
+		AddRow(1, 1, "lorem ipsum", "lorem", "lorem@ipsum.id", "+6275555", "qwe123", 1, "test", "test 12345", nil, nil)
 	q := "SELECT u.id, u.role_id, u.name, u.username, u.email, u.phone, u.password, "
-	q += "r.id as role_id, r.name as role_name, r.description FROM users as u "
-	q += "JOIN roles as r ON r.id = u.role_id WHERE u.id = $1"
+	q += "r.id as role_id, r.name as role_name, r.description, u.deleted_at, u.deleted_by "
+	q += "FROM users as u JOIN roles as r ON r.id = u.role_id WHERE ($2 OR u.deleted_at IS NULL) AND u.id = $1"
 	expectedQuery := regexp.QuoteMeta(q)
 	suite.mock.ExpectQuery(expectedQuery).WillReturnRows(user)
 	res, err := suite.userRepo.Find(context.TODO(), model.FindWithID, 1)
@@ -124,11 +127,11 @@ func (suite *userRepositoryTestSuite) TestUserRepository_FindBY_ExpectedSuccess(
 	}
 	for _, tt := range tests {
 		user := suite.mock.
-			NewRows([]string{"id", "users.role_id", "name", "username", "email", "phone", "password", "role_id", "role_name", "role_description"}).
-			AddRow(1, 1, "lorem ipsum", "lorem", "lorem@ipsum.id", "+6275555", "qwe123", 1, "test", "test 12345")
+			NewRows([]string{"id", "users.role_id", "name", "username", "email", "phone", "password", "role_id", "role_name", "role_description", "deleted_at", "deleted_by"}).
+			AddRow(1, 1, "lorem ipsum", "lorem", "lorem@ipsum.id", "+6275555", "qwe123", 1, "test", "test 12345", nil, nil)
 		q := "SELECT u.id, u.role_id, u.name, u.username, u.email, u.phone, u.password, "
-		q += "r.id as role_id, r.name as role_name, r.description FROM users as u "
-		q += "JOIN roles as r ON r.id = u.role_id WHERE "
+		q += "r.id as role_id, r.name as role_name, r.description, u.deleted_at, u.deleted_by "
+		q += "FROM users as u JOIN roles as r ON r.id = u.role_id WHERE ($2 OR u.deleted_at IS NULL) AND "
 		q += tt.args
 		q += " LIMIT 1"
 		expectedQuery := regexp.QuoteMeta(q)
@@ -142,11 +145,11 @@ func (suite *userRepositoryTestSuite) TestUserRepository_FindBY_ExpectedSuccess(
 
 func (suite *userRepositoryTestSuite) TestUserRepository_Find_ExpectedError() {
 	user := suite.mock.
-		NewRows([]string{"id", "users.role_id", "name", "username", "email", "phone", "password", "role_id", "role_name", "role_description"}).
-		AddRow(nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+		NewRows([]string{"id", "users.role_id", "name", "username", "email", "phone", "password", "role_id", "role_name", "role_description", "deleted_at", "deleted_by"}).
+		AddRow(nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
 	q := "SELECT u.id, u.role_id, u.name, u.username, u.email, u.phone, u.password, "
-	q += "r.id as role_id, r.name as role_name, r.description FROM users as u "
-	q += "JOIN roles as r ON r.id = u.role_id WHERE u.id = $1"
+	q += "r.id as role_id, r.name as role_name, r.description, u.deleted_at, u.deleted_by "
+	q += "FROM users as u JOIN roles as r ON r.id = u.role_id WHERE ($2 OR u.deleted_at IS NULL) AND u.id = $1"
 	expectedQuery := regexp.QuoteMeta(q)
 	suite.mock.ExpectQuery(expectedQuery).WillReturnRows(user)
 	res, err := suite.userRepo.Find(context.TODO(), model.FindWithID, 1)
@@ -157,13 +160,13 @@ func (suite *userRepositoryTestSuite) TestUserRepository_Find_ExpectedError() {
 func (suite *userRepositoryTestSuite) TestUserRepository_Create_ExpectedSuccess() {
 	user := &model.User{ID: 1, RoleID: 1, Name: "test 123", Username: "test", Email: "test@test.id", Phone: "+627888", Password: "12345"}
 	rows := suite.mock.
-		NewRows([]string{"id", "users.role_id", "name", "username", "email", "phone", "password", "role_id", "role_name", "role_description"}).
-		AddRow(1, 1, "lorem ipsum", "lorem", "lorem@ipsum.id", "+6275555", "qwe123", 1, "test", "test 12345")
-	q := "WITH u AS (INSERT INTO users(role_id, name, username, email, phone, password) "
-	q += "values ($1, $2, $3, $4, $5, $6) RETURNING *) "
-	q += "SELECT u.id, u.role_id, u.name, u.username, u.email, u.phone, u.password, "
-	q += "r.id as role_id, r.name as role_name, r.description FROM u "
-	q += "JOIN roles as r ON r.id = u.role_id"
+		NewRows([]string{"id", "users.role_id", "name", "username", "email", "phone", "password", "role_id", "role_name", "role_description", "deleted_at", "deleted_by"}).
+		AddRow(1, 1, "lorem ipsum", "lorem", "lorem@ipsum.id", "+6275555", "qwe123", 1, "test", "test 12345", nil, nil)
+	q := "INSERT INTO users(role_id, name, username, email, phone, password) "
+	q += "values ($1, $2, $3, $4, $5, $6) "
+	q += "RETURNING id, role_id, name, username, email, phone, password, role_id, "
+	q += "(SELECT name FROM roles WHERE roles.id = users.role_id), "
+	q += "(SELECT description FROM roles WHERE roles.id = users.role_id), deleted_at, deleted_by"
 	expectedQuery := regexp.QuoteMeta(q)
 	suite.mock.ExpectQuery(expectedQuery).
 		WithArgs(user.RoleID, user.Name, user.Username, user.Email, user.Phone, user.Password).
@@ -176,13 +179,13 @@ func (suite *userRepositoryTestSuite) TestUserRepository_Create_ExpectedSuccess(
 func (suite *userRepositoryTestSuite) TestUserRepository_Create_ExpectedError() {
 	user := &model.User{ID: 1, RoleID: 1, Name: "test 123", Username: "test", Email: "test@test.id", Phone: "+627888", Password: "12345"}
 	rows := suite.mock.
-		NewRows([]string{"id", "users.role_id", "name", "username", "email", "phone", "password", "role_id", "role_name", "role_description"}).
-		AddRow(nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
-	q := "WITH u AS (INSERT INTO users(role_id, name, username, email, phone, password) "
-	q += "values ($1, $2, $3, $4, $5, $6) RETURNING *) "
-	q += "SELECT u.id, u.role_id, u.name, u.username, u.email, u.phone, u.password, "
-	q += "r.id as role_id, r.name as role_name, r.description FROM u "
-	q += "JOIN roles as r ON r.id = u.role_id"
+		NewRows([]string{"id", "users.role_id", "name", "username", "email", "phone", "password", "role_id", "role_name", "role_description", "deleted_at", "deleted_by"}).
+		AddRow(nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+	q := "INSERT INTO users(role_id, name, username, email, phone, password) "
+	q += "values ($1, $2, $3, $4, $5, $6) "
+	q += "RETURNING id, role_id, name, username, email, phone, password, role_id, "
+	q += "(SELECT name FROM roles WHERE roles.id = users.role_id), "
+	q += "(SELECT description FROM roles WHERE roles.id = users.role_id), deleted_at, deleted_by"
 	expectedQuery := regexp.QuoteMeta(q)
 	suite.mock.ExpectQuery(expectedQuery).
 		WithArgs(user.RoleID, user.Name, user.Username, user.Email, user.Phone, user.Password).
@@ -196,13 +199,13 @@ func (suite *userRepositoryTestSuite) TestUserRepository_Create_ExpectedError()
 func (suite *userRepositoryTestSuite) TestUserRepository_Update_ExpectedSuccess() {
 	user := &model.User{ID: 1, RoleID: 1, Name: "test 123", Username: "test", Email: "test@test.id", Phone: "+627888", Password: "12345"}
 	rows := suite.mock.
-		NewRows([]string{"id", "users.role_id", "name", "username", "email", "phone", "password", "role_id", "role_name", "role_description"}).
-		AddRow(1, 1, "lorem ipsum", "lorem", "lorem@ipsum.id", "+6275555", "qwe123", 1, "test", "test 12345")
-	q := "WITH u AS (UPDATE users SET role_id = $1, name = $2, username = $3, email = $4, "
-	q += "phone = $5, password = $6 WHERE id = $7 RETURNING *) "
-	q += "SELECT u.id, u.role_id, u.name, u.username, u.email, u.phone, u.password, "
-	q += "r.id as role_id, r.name as role_name, r.description FROM u "
-	q += "JOIN roles as r ON r.id = u.role_id"
+		NewRows([]string{"id", "users.role_id", "name", "username", "email", "phone", "password", "role_id", "role_name", "role_description", "deleted_at", "deleted_by"}).
+		AddRow(1, 1, "lorem ipsum", "lorem", "lorem@ipsum.id", "+6275555", "qwe123", 1, "test", "test 12345", nil, nil)
+	q := "UPDATE users SET role_id = $1, name = $2, username = $3, email = $4, "
+	q += "phone = $5, password = $6 WHERE id = $7 AND deleted_at IS NULL "
+	q += "RETURNING id, role_id, name, username, email, phone, password, role_id, "
+	q += "(SELECT name FROM roles WHERE roles.id = users.role_id), "
+	q += "(SELECT description FROM roles WHERE roles.id = users.role_id), deleted_at, deleted_by"
 	expectedQuery := regexp.QuoteMeta(q)
 	suite.mock.ExpectQuery(expectedQuery).
 		WithArgs(user.RoleID, user.Name, user.Username, user.Email, user.Phone, user.Password, user.ID).
@@ -215,13 +218,13 @@ func (suite *userRepositoryTestSuite) TestUserRepository_Update_ExpectedSuccess(
 func (suite *userRepositoryTestSuite) TestUserRepository_Update_ExpectedError() {
 	user := &model.User{ID: 1, RoleID: 1, Name: "test 123", Username: "test", Email: "test@test.id", Phone: "+627888", Password: "12345"}
 	rows := suite.mock.
-		NewRows([]string{"id", "users.role_id", "name", "username", "email", "phone", "password", "role_id", "role_name", "role_description"}).
-		AddRow(nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
-	q := "WITH u AS (UPDATE users SET role_id = $1, name = $2, username = $3, email = $4, "
-	q += "phone = $5, password = $6 WHERE id = $7 RETURNING *) "
-	q += "SELECT u.id, u.role_id, u.name, u.username, u.email, u.phone, u.password, "
-	q += "r.id as role_id, r.name as role_name, r.description FROM u "
-	q += "JOIN roles as r ON r.id = u.role_id"
+		NewRows([]string{"id", "users.role_id", "name", "username", "email", "phone", "password", "role_id", "role_name", "role_description", "deleted_at", "deleted_by"}).
+		AddRow(nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+	q := "UPDATE users SET role_id = $1, name = $2, username = $3, email = $4, "
+	q += "phone = $5, password = $6 WHERE id = $7 AND deleted_at IS NULL "
+	q += "RETURNING id, role_id, name, username, email, phone, password, role_id, "
+	q += "(SELECT name FROM roles WHERE roles.id = users.role_id), "
+	q += "(SELECT description FROM roles WHERE roles.id = users.role_id), deleted_at, deleted_by"
 	expectedQuery := regexp.QuoteMeta(q)
 	suite.mock.ExpectQuery(expectedQuery).
 		WithArgs(user.RoleID, user.Name, user.Username, user.Email, user.Phone, user.Password, user.ID).
@@ -232,15 +235,65 @@ func (suite *userRepositoryTestSuite) TestUserRepository_Update_ExpectedError()
 }
 
 func (suite *userRepositoryTestSuite) TestUserRepository_Delete_ExpectedSuccess() {
-	expectedQuery := regexp.QuoteMeta("DELETE FROM users")
+	expectedQuery := regexp.QuoteMeta("UPDATE users SET deleted_at = $1, deleted_by = $2 WHERE id = $3 AND deleted_at IS NULL")
 	suite.mock.ExpectExec(expectedQuery).
-		WithArgs(1).
+		WithArgs(sqlmock.AnyArg(), nil, 1).
 		WillReturnResult(sqlmock.NewResult(0, 1))
 	user := &model.User{ID: 1, RoleID: 1, Username: "test", Password: "12345"}
 	err := suite.userRepo.Delete(context.TODO(), user)
 	require.Nil(suite.T(), err)
 }
 
+func (suite *userRepositoryTestSuite) TestUserRepository_Restore_ExpectedSuccess() {
+	rows := suite.mock.
+		NewRows([]string{"id", "users.role_id", "name", "username", "email", "phone", "password", "role_id", "role_name", "role_description", "deleted_at", "deleted_by"}).
+		AddRow(1, 1, "lorem ipsum", "lorem", "lorem@ipsum.id", "+6275555", "qwe123", 1, "test", "test 12345", nil, nil)
+	q := "UPDATE users SET deleted_at = NULL, deleted_by = NULL "
+	q += "WHERE id = $1 AND deleted_at IS NOT NULL "
+	q += "RETURNING id, role_id, name, username, email, phone, password, role_id, "
+	q += "(SELECT name FROM roles WHERE roles.id = users.role_id), "
+	q += "(SELECT description FROM roles WHERE roles.id = users.role_id), deleted_at, deleted_by"
+	expectedQuery := regexp.QuoteMeta(q)
+	suite.mock.ExpectQuery(expectedQuery).WithArgs(1).WillReturnRows(rows)
+	res, err := suite.userRepo.Restore(context.TODO(), &model.User{ID: 1})
+	require.Nil(suite.T(), err)
+	require.NotNil(suite.T(), res)
+}
+
+func (suite *userRepositoryTestSuite) TestUserRepository_SoftDelete() {
+	dbtest.Migrated(suite.T(), config.Dialect.Name())
+	ctx := context.TODO()
+	repo := repoSql.NewUserSQLRepository()
+	user, err := repo.Create(ctx, &model.User{RoleID: 2, Name: "lorem", Username: "lorem",
+		Email: "lorem@store.id", Phone: "81200000009", Password: "secret"})
+	require.NoError(suite.T(), err)
+	require.Equal(suite.T(), "cashier", user.Role.Name)
+	found, err := repo.Find(ctx, model.FindWithUsername, "lorem")
+	require.NoError(suite.T(), err)
+	require.Equal(suite.T(), user.ID, found.ID)
+
+	found.RoleID, found.Name = 3, "ipsum"
+	user, err = repo.Update(ctx, found)
+	require.NoError(suite.T(), err)
+	require.Equal(suite.T(), "waiter", user.Role.Name)
+
+	require.NoError(suite.T(), repo.Delete(ctx, user))
+	_, err = repo.Find(ctx, model.FindWithUsername, "lorem")
+	require.ErrorIs(suite.T(), err, sql.ErrNoRows)
+	// the username of a deleted user is free again
+	_, err = repo.Create(ctx, &model.User{RoleID: 2, Name: "lorem", Username: "lorem",
+		Email: "lorem2@store.id", Phone: "81200000010", Password: "secret"})
+	require.NoError(suite.T(), err)
+	users, err := repo.All(context.WithValue(ctx, model.IncludeDeletedKey, true))
+	require.NoError(suite.T(), err)
+	require.Len(suite.T(), users, 5)
+}
+
 func TestUserRepository(t *testing.T) {
-	suite.Run(t, new(userRepositoryTestSuite))
+	for _, d := range dialect.All() {
+		t.Run(d.Name(), func(t *testing.T) {
+			config.Dialect = d
+			suite.Run(t, new(userRepositoryTestSuite))
+		})
+	}
 }
@@ -3,24 +3,26 @@ package service
 import (
 	"context"
 	"database/sql"
-	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
-	"time"
+	"slices"
 
-	"github.com/aasumitro/posbe/common"
-	"github.com/aasumitro/posbe/config"
+	"github.com/aasumitro/posbe/pkg/cache"
 	"github.com/aasumitro/posbe/pkg/model"
+	"github.com/aasumitro/posbe/pkg/reference"
 	"github.com/aasumitro/posbe/pkg/utils"
 )
 
 type accountService struct {
-	roleRepo model.ICRUDRepository[model.Role]
-	userRepo model.ICRUDRepository[model.User]
-	pwd      utils.IPassword
+	roleRepo      model.ISoftDeleteRepository[model.Role]
+	userRepo      model.ISoftDeleteRepository[model.User]
+	referenceRepo model.IReferenceRepository
+	pwd           utils.IPassword
 }
 
-var roleCacheKey = "roles"
+// roleCache is shared by every account service, so a miss is loaded once
+var roleCache = cache.New[[]*model.Role](cache.RolesKey)
 
 func (service accountService) RoleList(
 	ctx context.Context,
@@ -28,22 +30,12 @@ func (service accountService) RoleList(
 	roles []*model.Role,
 	errorData *utils.ServiceError,
 ) {
-	helper := utils.RedisCache{Ctx: ctx, RdpConn: config.RedisPool}
-	data, err := helper.CacheFirstData(&utils.CacheDataSupplied{
-		Key: roleCacheKey,
-		TTL: time.Hour * 1,
-		CbF: func() (data any, err error) {
-			return service.roleRepo.All(ctx)
-		},
-	})
-	if data, ok := data.([]*model.Role); ok {
-		roles = data
-	}
-	if data, ok := data.(string); ok {
-		var r []*model.Role
-		_ = json.Unmarshal([]byte(data), &r)
-		roles = r
+	if model.IncludeDeleted(ctx) {
+		// the cache hold the roles that are not deleted only
+		data, err := service.roleRepo.All(ctx)
+		return utils.ValidateDataRows[model.Role](data, err)
 	}
+	roles, err := roleCache.Get(ctx, service.roleRepo.All)
 	return utils.ValidateDataRows[model.Role](roles, err)
 }
 
@@ -54,8 +46,11 @@ func (service accountService) AddRole(
 	role *model.Role,
 	errorData *utils.ServiceError,
 ) {
+	if errData := validatePermissions(item.Permissions); errData != nil {
+		return nil, errData
+	}
 	data, err := service.roleRepo.Create(ctx, item)
-	config.RedisPool.Del(ctx, roleCacheKey)
+	_ = roleCache.Invalidate(ctx)
 	return utils.ValidateDataRow[model.Role](data, err)
 }
 
@@ -66,8 +61,11 @@ func (service accountService) EditRole(
 	role *model.Role,
 	errorData *utils.ServiceError,
 ) {
+	if errData := validatePermissions(item.Permissions); errData != nil {
+		return nil, errData
+	}
 	data, err := service.roleRepo.Update(ctx, item)
-	config.RedisPool.Del(ctx, roleCacheKey)
+	_ = roleCache.Invalidate(ctx)
 	return utils.ValidateDataRow[model.Role](data, err)
 }
 
@@ -88,20 +86,68 @@ func (service accountService) DeleteRole(
 			Message: err.Error(),
 		}
 	}
-	if role.Usage >= 1 {
-		return &utils.ServiceError{
-			Code:    http.StatusForbidden,
-			Message: common.ErrorUnableToDelete,
+	// the row is deleted by the reference repository with the reassign
+	model.AuditBefore(ctx, role)
+	errData := reference.Delete(ctx, service.referenceRepo, "roles", role.ID)
+	_ = roleCache.Invalidate(ctx)
+	return errData
+}
+
+func (service accountService) RestoreRole(
+	ctx context.Context,
+	data *model.Role,
+) (
+	role *model.Role,
+	errorData *utils.ServiceError,
+) {
+	role, err := service.roleRepo.Restore(ctx, data)
+	if err == nil {
+		_ = roleCache.Invalidate(ctx)
+	}
+	return utils.ValidateDataRow[model.Role](role, err)
+}
+
+func (service accountService) PermissionList(
+	_ context.Context,
+) (
+	permissions []*model.Permission,
+	errorData *utils.ServiceError,
+) {
+	return model.Permissions, nil
+}
+
+// RolePermissions read the role from the cached role list,
+// unknown role is granted nothing
+func (service accountService) RolePermissions(
+	ctx context.Context,
+	roleID int,
+) (
+	permissions []string,
+	errorData *utils.ServiceError,
+) {
+	roles, errData := service.RoleList(ctx)
+	if errData != nil {
+		return nil, errData
+	}
+	for _, role := range roles {
+		if role.ID == roleID {
+			return role.Permissions, nil
 		}
 	}
-	err = service.roleRepo.Delete(ctx, role)
-	if err != nil {
-		return &utils.ServiceError{
-			Code:    http.StatusInternalServerError,
-			Message: err.Error(),
+	return []string{}, nil
+}
+
+func validatePermissions(permissions []string) *utils.ServiceError {
+	for _, permission := range permissions {
+		if !slices.ContainsFunc(model.Permissions, func(p *model.Permission) bool {
+			return p.Name == permission
+		}) {
+			return &utils.ServiceError{
+				Code:    http.StatusUnprocessableEntity,
+				Message: fmt.Sprintf("unknown permission %s", permission),
+			}
 		}
 	}
-	config.RedisPool.Del(ctx, roleCacheKey)
 	return nil
 }
 
@@ -205,6 +251,17 @@ func (service accountService) DeleteUser(
 	return nil
 }
 
+func (service accountService) RestoreUser(
+	ctx context.Context,
+	data *model.User,
+) (
+	user *model.User,
+	errorData *utils.ServiceError,
+) {
+	user, err := service.userRepo.Restore(ctx, data)
+	return utils.ValidateDataRow[model.User](user, err)
+}
+
 func (service accountService) VerifyUserCredentials(
 	ctx context.Context,
 	username, password string,
@@ -247,24 +304,28 @@ func (service accountService) VerifyUserCredentials(
 }
 
 func NewAccountService(
-	roleRepo model.ICRUDRepository[model.Role],
-	userRepo model.ICRUDRepository[model.User],
+	roleRepo model.ISoftDeleteRepository[model.Role],
+	userRepo model.ISoftDeleteRepository[model.User],
+	referenceRepo model.IReferenceRepository,
 ) model.IAccountService {
 	return &accountService{
-		roleRepo: roleRepo,
-		userRepo: userRepo,
+		roleRepo:      roleRepo,
+		userRepo:      userRepo,
+		referenceRepo: referenceRepo,
 	}
 }
 
 // NewAccountServiceTest for testing purpose
 func NewAccountServiceTest(
-	roleRepo model.ICRUDRepository[model.Role],
-	userRepo model.ICRUDRepository[model.User],
+	roleRepo model.ISoftDeleteRepository[model.Role],
+	userRepo model.ISoftDeleteRepository[model.User],
+	referenceRepo model.IReferenceRepository,
 	pwd utils.IPassword,
 ) model.IAccountService {
 	return &accountService{
-		roleRepo: roleRepo,
-		userRepo: userRepo,
-		pwd:      pwd,
+		roleRepo:      roleRepo,
+		userRepo:      userRepo,
+		referenceRepo: referenceRepo,
+		pwd:           pwd,
 	}
 }
@@ -13,6 +13,7 @@ import (
 	"github.com/aasumitro/posbe/config"
 	"github.com/aasumitro/posbe/internal/account/service"
 	mocks2 "github.com/aasumitro/posbe/mocks"
+	"github.com/aasumitro/posbe/pkg/kv"
 	"github.com/aasumitro/posbe/pkg/model"
 	"github.com/aasumitro/posbe/pkg/utils"
 	"github.com/alicebob/miniredis/v2"
@@ -78,25 +79,22 @@ func (suite *accountTestSuite) SetupSuite() {
 		Message: "UNEXPECTED",
 	}
 
-	config.RedisPool = redis.NewClient(&redis.Options{
+	config.KV = kv.NewRedisStore(redis.NewClient(&redis.Options{
 		Addr: miniredis.RunT(suite.T()).Addr(),
-	})
+	}))
 }
 
 func (suite *accountTestSuite) TestAccountService_RoleList_ShouldSuccess_ReturnModel() {
-	config.RedisPool = redis.NewClient(&redis.Options{
+	config.KV = kv.NewRedisStore(redis.NewClient(&redis.Options{
 		Addr: miniredis.RunT(suite.T()).Addr(),
-	})
-	cacheMock := new(mocks2.Cache)
-	roleRepoMock := new(mocks2.ICRUDRepository[model.Role])
-	userRepoMock := new(mocks2.ICRUDRepository[model.User])
+	}))
+	roleRepoMock := new(mocks2.ISoftDeleteRepository[model.Role])
+	userRepoMock := new(mocks2.ISoftDeleteRepository[model.User])
 	accSvc := service.NewAccountService(
-		roleRepoMock, userRepoMock)
+		roleRepoMock, userRepoMock, new(mocks2.IReferenceRepository))
 	roleRepoMock.
 		On("All", mock.Anything).
 		Return(suite.roles, nil).Once()
-	cacheMock.On("CacheFirstData", mock.Anything).
-		Return(suite.roles, nil).Once()
 	data, err := accSvc.RoleList(context.TODO())
 	require.Nil(suite.T(), err)
 	require.NotNil(suite.T(), data)
@@ -105,24 +103,18 @@ func (suite *accountTestSuite) TestAccountService_RoleList_ShouldSuccess_ReturnM
 }
 
 func (suite *accountTestSuite) TestAccountService_RoleList_ShouldSuccess_ReturnString() {
-	config.RedisPool = redis.NewClient(&redis.Options{
+	config.KV = kv.NewRedisStore(redis.NewClient(&redis.Options{
 		Addr: miniredis.RunT(suite.T()).Addr(),
-	})
-	cacheMock := new(mocks2.Cache)
-	roleRepoMock := new(mocks2.ICRUDRepository[model.Role])
-	userRepoMock := new(mocks2.ICRUDRepository[model.User])
+	}))
+	roleRepoMock := new(mocks2.ISoftDeleteRepository[model.Role])
+	userRepoMock := new(mocks2.ISoftDeleteRepository[model.User])
 	accSvc := service.NewAccountService(
-		roleRepoMock, userRepoMock)
+		roleRepoMock, userRepoMock, new(mocks2.IReferenceRepository))
 	roleRepoMock.
 		On("All", mock.Anything).
 		Return(nil, nil).Once()
 	jsonData, _ := json.Marshal(suite.roles)
-	config.RedisPool.Set(context.TODO(), "roles", jsonData, 1)
-	cacheMock.On("CacheFirstData", &utils.CacheDataSupplied{
-		Key: "roles",
-		TTL: time.Hour * 1,
-		CbF: nil,
-	}).Return(jsonData, nil).Once()
+	_ = config.KV.Set(context.TODO(), "roles", jsonData, time.Hour*1)
 	data, err := accSvc.RoleList(context.TODO())
 	suite.T().Log(data)
 	suite.T().Log(err)
@@ -132,10 +124,10 @@ func (suite *accountTestSuite) TestAccountService_RoleList_ShouldSuccess_ReturnS
 }
 
 func (suite *accountTestSuite) TestAccountService_RoleList_ShouldError() {
-	roleRepoMock := new(mocks2.ICRUDRepository[model.Role])
-	userRepoMock := new(mocks2.ICRUDRepository[model.User])
+	roleRepoMock := new(mocks2.ISoftDeleteRepository[model.Role])
+	userRepoMock := new(mocks2.ISoftDeleteRepository[model.User])
 	accSvc := service.NewAccountService(
-		roleRepoMock, userRepoMock)
+		roleRepoMock, userRepoMock, new(mocks2.IReferenceRepository))
 	roleRepoMock.
 		On("All", mock.Anything).
 		Once().
@@ -148,10 +140,10 @@ func (suite *accountTestSuite) TestAccountService_RoleList_ShouldError() {
 }
 
 func (suite *accountTestSuite) TestAccountService_AddRole_ShouldSuccess() {
-	roleRepoMock := new(mocks2.ICRUDRepository[model.Role])
-	userRepoMock := new(mocks2.ICRUDRepository[model.User])
+	roleRepoMock := new(mocks2.ISoftDeleteRepository[model.Role])
+	userRepoMock := new(mocks2.ISoftDeleteRepository[model.User])
 	accSvc := service.NewAccountService(
-		roleRepoMock, userRepoMock)
+		roleRepoMock, userRepoMock, new(mocks2.IReferenceRepository))
 	roleRepoMock.
 		On("Create", mock.Anything, mock.Anything).
 		Once().
@@ -164,10 +156,10 @@ func (suite *accountTestSuite) TestAccountService_AddRole_ShouldSuccess() {
 }
 
 func (suite *accountTestSuite) TestAccountService_AddRole_ShouldError() {
-	roleRepoMock := new(mocks2.ICRUDRepository[model.Role])
-	userRepoMock := new(mocks2.ICRUDRepository[model.User])
+	roleRepoMock := new(mocks2.ISoftDeleteRepository[model.Role])
+	userRepoMock := new(mocks2.ISoftDeleteRepository[model.User])
 	accSvc := service.NewAccountService(
-		roleRepoMock, userRepoMock)
+		roleRepoMock, userRepoMock, new(mocks2.IReferenceRepository))
 	roleRepoMock.
 		On("Create", mock.Anything, mock.Anything).
 		Once().
@@ -179,11 +171,56 @@ func (suite *accountTestSuite) TestAccountService_AddRole_ShouldError() {
 	roleRepoMock.AssertExpectations(suite.T())
 }
 
+func (suite *accountTestSuite) TestAccountService_AddRole_ShouldErrorUnknownPermission() {
+	roleRepoMock := new(mocks2.ISoftDeleteRepository[model.Role])
+	userRepoMock := new(mocks2.ISoftDeleteRepository[model.User])
+	accSvc := service.NewAccountService(
+		roleRepoMock, userRepoMock, new(mocks2.IReferenceRepository))
+	data, err := accSvc.AddRole(context.TODO(), &model.Role{
+		Name: "kitchen", Permissions: []string{model.PermissionCatalogRead, "order.eat"}})
+	require.Nil(suite.T(), data)
+	require.Equal(suite.T(), http.StatusUnprocessableEntity, err.Code)
+	require.Equal(suite.T(), "unknown permission order.eat", err.Message)
+	roleRepoMock.AssertNotCalled(suite.T(), "Create", mock.Anything, mock.Anything)
+}
+
+func (suite *accountTestSuite) TestAccountService_PermissionList_ShouldSuccess() {
+	accSvc := service.NewAccountService(
+		new(mocks2.ISoftDeleteRepository[model.Role]), new(mocks2.ISoftDeleteRepository[model.User]), new(mocks2.IReferenceRepository))
+	data, err := accSvc.PermissionList(context.TODO())
+	require.Nil(suite.T(), err)
+	require.Equal(suite.T(), model.Permissions, data)
+}
+
+func (suite *accountTestSuite) TestAccountService_RolePermissions_ShouldSuccess() {
+	config.KV = kv.NewRedisStore(redis.NewClient(&redis.Options{
+		Addr: miniredis.RunT(suite.T()).Addr(),
+	}))
+	roleRepoMock := new(mocks2.ISoftDeleteRepository[model.Role])
+	userRepoMock := new(mocks2.ISoftDeleteRepository[model.User])
+	accSvc := service.NewAccountService(
+		roleRepoMock, userRepoMock, new(mocks2.IReferenceRepository))
+	roleRepoMock.
+		On("All", mock.Anything).
+		Return([]*model.Role{
+			{ID: 1, Name: "admin", Permissions: []string{model.PermissionCatalogRead}},
+			{ID: 3, Name: "waiter", Permissions: []string{}},
+		}, nil).Once()
+	data, err := accSvc.RolePermissions(context.TODO(), 1)
+	require.Nil(suite.T(), err)
+	require.Equal(suite.T(), []string{model.PermissionCatalogRead}, data)
+	// second call is served from the cache
+	data, err = accSvc.RolePermissions(context.TODO(), 9)
+	require.Nil(suite.T(), err)
+	require.Empty(suite.T(), data)
+	roleRepoMock.AssertExpectations(suite.T())
+}
+
 func (suite *accountTestSuite) TestAccountService_EditRole_ShouldSuccess() {
-	roleRepoMock := new(mocks2.ICRUDRepository[model.Role])
-	userRepoMock := new(mocks2.ICRUDRepository[model.User])
+	roleRepoMock := new(mocks2.ISoftDeleteRepository[model.Role])
+	userRepoMock := new(mocks2.ISoftDeleteRepository[model.User])
 	accSvc := service.NewAccountService(
-		roleRepoMock, userRepoMock)
+		roleRepoMock, userRepoMock, new(mocks2.IReferenceRepository))
 	roleRepoMock.
 		On("Update", mock.Anything, mock.Anything).
 		Once().
@@ -196,10 +233,10 @@ func (suite *accountTestSuite) TestAccountService_EditRole_ShouldSuccess() {
 }
 
 func (suite *accountTestSuite) TestAccountService_EditRole_ShouldError() {
-	roleRepoMock := new(mocks2.ICRUDRepository[model.Role])
-	userRepoMock := new(mocks2.ICRUDRepository[model.User])
+	roleRepoMock := new(mocks2.ISoftDeleteRepository[model.Role])
+	userRepoMock := new(mocks2.ISoftDeleteRepository[model.User])
 	accSvc := service.NewAccountService(
-		roleRepoMock, userRepoMock)
+		roleRepoMock, userRepoMock, new(mocks2.IReferenceRepository))
 	roleRepoMock.
 		On("Update", mock.Anything, mock.Anything).
 		Once().
@@ -212,27 +249,29 @@ func (suite *accountTestSuite) TestAccountService_EditRole_ShouldError() {
 }
 
 func (suite *accountTestSuite) TestAccountService_DeleteRole_ShouldSuccess() {
-	roleRepoMock := new(mocks2.ICRUDRepository[model.Role])
-	userRepoMock := new(mocks2.ICRUDRepository[model.User])
+	roleRepoMock := new(mocks2.ISoftDeleteRepository[model.Role])
+	userRepoMock := new(mocks2.ISoftDeleteRepository[model.User])
+	referenceRepoMock := new(mocks2.IReferenceRepository)
+	referenceRepoMock.
+		On("Delete", mock.Anything, "roles", mock.Anything, 0).
+		Once().
+		Return(nil, nil)
 	accSvc := service.NewAccountService(
-		roleRepoMock, userRepoMock)
+		roleRepoMock, userRepoMock, referenceRepoMock)
 	roleRepoMock.
 		On("Find", mock.Anything, mock.Anything, mock.Anything).
 		Once().
 		Return(suite.roles[1], nil)
-	roleRepoMock.
-		On("Delete", mock.Anything, mock.Anything).
-		Once().
-		Return(nil)
 	err := accSvc.DeleteRole(context.TODO(), suite.roles[1])
 	require.Nil(suite.T(), err)
 	roleRepoMock.AssertExpectations(suite.T())
+	referenceRepoMock.AssertExpectations(suite.T())
 }
 func (suite *accountTestSuite) TestService_DeleteRole_ShouldErrorWhenFindNotFound() {
-	roleRepoMock := new(mocks2.ICRUDRepository[model.Role])
-	userRepoMock := new(mocks2.ICRUDRepository[model.User])
+	roleRepoMock := new(mocks2.ISoftDeleteRepository[model.Role])
+	userRepoMock := new(mocks2.ISoftDeleteRepository[model.User])
 	svc := service.NewAccountService(
-		roleRepoMock, userRepoMock)
+		roleRepoMock, userRepoMock, new(mocks2.IReferenceRepository))
 	roleRepoMock.
 		On("Find", mock.Anything, mock.Anything, mock.Anything).
 		Once().
@@ -243,10 +282,10 @@ func (suite *accountTestSuite) TestService_DeleteRole_ShouldErrorWhenFindNotFoun
 	roleRepoMock.AssertExpectations(suite.T())
 }
 func (suite *accountTestSuite) TestAccountService_DeleteRole_ShouldErrorInternal() {
-	roleRepoMock := new(mocks2.ICRUDRepository[model.Role])
-	userRepoMock := new(mocks2.ICRUDRepository[model.User])
+	roleRepoMock := new(mocks2.ISoftDeleteRepository[model.Role])
+	userRepoMock := new(mocks2.ISoftDeleteRepository[model.User])
 	accSvc := service.NewAccountService(
-		roleRepoMock, userRepoMock)
+		roleRepoMock, userRepoMock, new(mocks2.IReferenceRepository))
 	roleRepoMock.
 		On("Find", mock.Anything, mock.Anything, mock.Anything).
 		Once().
@@ -258,10 +297,15 @@ func (suite *accountTestSuite) TestAccountService_DeleteRole_ShouldErrorInternal
 }
 
 func (suite *accountTestSuite) TestAccountService_DeleteRole_ShouldErrorUsage() {
-	roleRepoMock := new(mocks2.ICRUDRepository[model.Role])
-	userRepoMock := new(mocks2.ICRUDRepository[model.User])
+	roleRepoMock := new(mocks2.ISoftDeleteRepository[model.Role])
+	userRepoMock := new(mocks2.ISoftDeleteRepository[model.User])
+	referenceRepoMock := new(mocks2.IReferenceRepository)
+	referenceRepoMock.
+		On("Delete", mock.Anything, "roles", mock.Anything, 0).
+		Once().
+		Return([]*model.Reference{{Table: "users", Column: "role_id", Total: 1, IDs: []int{1}}}, nil)
 	accSvc := service.NewAccountService(
-		roleRepoMock, userRepoMock)
+		roleRepoMock, userRepoMock, referenceRepoMock)
 	roleRepoMock.
 		On("Find", mock.Anything, mock.Anything, mock.Anything).
 		Once().
@@ -269,36 +313,73 @@ func (suite *accountTestSuite) TestAccountService_DeleteRole_ShouldErrorUsage()
 	err := accSvc.DeleteRole(context.TODO(), suite.role)
 	require.NotNil(suite.T(), err)
 	require.Equal(suite.T(), err, &utils.ServiceError{
-		Code:    http.StatusForbidden,
-		Message: svcErr.ErrorUnableToDelete,
+		Code: http.StatusConflict,
+		Message: &model.DeleteConflict{
+			Message:    svcErr.ErrorUnableToDelete.Error(),
+			References: []*model.Reference{{Table: "users", Column: "role_id", Total: 1, IDs: []int{1}}},
+		},
 	})
 	roleRepoMock.AssertExpectations(suite.T())
+	referenceRepoMock.AssertExpectations(suite.T())
 }
 
-func (suite *accountTestSuite) TestAccountService_DeleteRole_ShouldErrorWhenDelete() {
-	roleRepoMock := new(mocks2.ICRUDRepository[model.Role])
-	userRepoMock := new(mocks2.ICRUDRepository[model.User])
+func (suite *accountTestSuite) TestAccountService_RestoreRole_ShouldSuccess() {
+	roleRepoMock := new(mocks2.ISoftDeleteRepository[model.Role])
+	userRepoMock := new(mocks2.ISoftDeleteRepository[model.User])
 	accSvc := service.NewAccountService(
-		roleRepoMock, userRepoMock)
+		roleRepoMock, userRepoMock, new(mocks2.IReferenceRepository))
 	roleRepoMock.
-		On("Find", mock.Anything, mock.Anything, mock.Anything).
+		On("Restore", mock.Anything, mock.Anything).
 		Once().
 		Return(suite.roles[1], nil)
+	data, err := accSvc.RestoreRole(context.TODO(), suite.roles[1])
+	require.Nil(suite.T(), err)
+	require.Equal(suite.T(), data, suite.roles[1])
+	roleRepoMock.AssertExpectations(suite.T())
+}
+
+func (suite *accountTestSuite) TestAccountService_RoleList_ShouldSkipCacheWhenIncludeDeleted() {
+	roleRepoMock := new(mocks2.ISoftDeleteRepository[model.Role])
+	userRepoMock := new(mocks2.ISoftDeleteRepository[model.User])
+	accSvc := service.NewAccountService(
+		roleRepoMock, userRepoMock, new(mocks2.IReferenceRepository))
 	roleRepoMock.
-		On("Delete", mock.Anything, mock.Anything).
+		On("All", mock.Anything).
 		Once().
-		Return(errors.New("UNEXPECTED"))
+		Return(suite.roles, nil)
+	ctx := context.WithValue(context.TODO(), model.IncludeDeletedKey, true) //nolint:staticcheck // gin context keys are strings
+	data, err := accSvc.RoleList(ctx)
+	require.Nil(suite.T(), err)
+	require.Equal(suite.T(), data, suite.roles)
+	roleRepoMock.AssertExpectations(suite.T())
+}
+
+func (suite *accountTestSuite) TestAccountService_DeleteRole_ShouldErrorWhenDelete() {
+	roleRepoMock := new(mocks2.ISoftDeleteRepository[model.Role])
+	userRepoMock := new(mocks2.ISoftDeleteRepository[model.User])
+	referenceRepoMock := new(mocks2.IReferenceRepository)
+	referenceRepoMock.
+		On("Delete", mock.Anything, "roles", mock.Anything, 0).
+		Once().
+		Return(nil, errors.New("UNEXPECTED"))
+	accSvc := service.NewAccountService(
+		roleRepoMock, userRepoMock, referenceRepoMock)
+	roleRepoMock.
+		On("Find", mock.Anything, mock.Anything, mock.Anything).
+		Once().
+		Return(suite.roles[1], nil)
 	err := accSvc.DeleteRole(context.TODO(), suite.roles[1])
 	require.NotNil(suite.T(), err)
 	require.Equal(suite.T(), err, suite.svcErr)
 	roleRepoMock.AssertExpectations(suite.T())
+	referenceRepoMock.AssertExpectations(suite.T())
 }
 
 func (suite *accountTestSuite) TestAccountService_UserList_ShouldSuccess() {
-	roleRepoMock := new(mocks2.ICRUDRepository[model.Role])
-	userRepoMock := new(mocks2.ICRUDRepository[model.User])
+	roleRepoMock := new(mocks2.ISoftDeleteRepository[model.Role])
+	userRepoMock := new(mocks2.ISoftDeleteRepository[model.User])
 	accSvc := service.NewAccountService(
-		roleRepoMock, userRepoMock)
+		roleRepoMock, userRepoMock, new(mocks2.IReferenceRepository))
 	userRepoMock.
 		On("All", mock.Anything).
 		Once().
@@ -311,10 +392,10 @@ func (suite *accountTestSuite) TestAccountService_UserList_ShouldSuccess() {
 }
 
 func (suite *accountTestSuite) TestAccountService_UserList_ShouldError() {
-	roleRepoMock := new(mocks2.ICRUDRepository[model.Role])
-	userRepoMock := new(mocks2.ICRUDRepository[model.User])
+	roleRepoMock := new(mocks2.ISoftDeleteRepository[model.Role])
+	userRepoMock := new(mocks2.ISoftDeleteRepository[model.User])
 	accSvc := service.NewAccountService(
-		roleRepoMock, userRepoMock)
+		roleRepoMock, userRepoMock, new(mocks2.IReferenceRepository))
 	userRepoMock.
 		On("All", mock.Anything).
 		Once().
@@ -327,10 +408,10 @@ func (suite *accountTestSuite) TestAccountService_UserList_ShouldError() {
 }
 
 func (suite *accountTestSuite) TestAccountService_ShowUser_ShouldSuccess() {
-	roleRepoMock := new(mocks2.ICRUDRepository[model.Role])
-	userRepoMock := new(mocks2.ICRUDRepository[model.User])
+	roleRepoMock := new(mocks2.ISoftDeleteRepository[model.Role])
+	userRepoMock := new(mocks2.ISoftDeleteRepository[model.User])
 	accSvc := service.NewAccountService(
-		roleRepoMock, userRepoMock)
+		roleRepoMock, userRepoMock, new(mocks2.IReferenceRepository))
 	userRepoMock.
 		On("Find", mock.Anything, mock.Anything, mock.Anything).
 		Once().
@@ -343,10 +424,10 @@ func (suite *accountTestSuite) TestAccountService_ShowUser_ShouldSuccess() {
 }
 
 func (suite *accountTestSuite) TestAccountService_ShowUser_ShouldError() {
-	roleRepoMock := new(mocks2.ICRUDRepository[model.Role])
-	userRepoMock := new(mocks2.ICRUDRepository[model.User])
+	roleRepoMock := new(mocks2.ISoftDeleteRepository[model.Role])
+	userRepoMock := new(mocks2.ISoftDeleteRepository[model.User])
 	accSvc := service.NewAccountService(
-		roleRepoMock, userRepoMock)
+		roleRepoMock, userRepoMock, new(mocks2.IReferenceRepository))
 	userRepoMock.
 		On("Find", mock.Anything, mock.Anything, mock.Anything).
 		Once().
@@ -359,10 +440,10 @@ func (suite *accountTestSuite) TestAccountService_ShowUser_ShouldError() {
 }
 
 func (suite *accountTestSuite) TestAccountService_AddUser_ShouldSuccess() {
-	roleRepoMock := new(mocks2.ICRUDRepository[model.Role])
-	userRepoMock := new(mocks2.ICRUDRepository[model.User])
+	roleRepoMock := new(mocks2.ISoftDeleteRepository[model.Role])
+	userRepoMock := new(mocks2.ISoftDeleteRepository[model.User])
 	accSvc := service.NewAccountService(
-		roleRepoMock, userRepoMock)
+		roleRepoMock, userRepoMock, new(mocks2.IReferenceRepository))
 	userRepoMock.
 		On("Create", mock.Anything, mock.Anything).
 		Once().
@@ -375,15 +456,15 @@ func (suite *accountTestSuite) TestAccountService_AddUser_ShouldSuccess() {
 }
 
 func (suite *accountTestSuite) TestAccountService_AddUser_ShouldError_Password() {
-	roleRepoMock := new(mocks2.ICRUDRepository[model.Role])
-	userRepoMock := new(mocks2.ICRUDRepository[model.User])
+	roleRepoMock := new(mocks2.ISoftDeleteRepository[model.Role])
+	userRepoMock := new(mocks2.ISoftDeleteRepository[model.User])
 	pwdMock := new(mocks2.IPassword)
 	pwdMock.
 		On("HashPassword").
 		Return("", errors.New("UNEXPECTED")).
 		Once()
 	accSvc := service.NewAccountServiceTest(
-		roleRepoMock, userRepoMock, pwdMock)
+		roleRepoMock, userRepoMock, new(mocks2.IReferenceRepository), pwdMock)
 	data, err := accSvc.AddUser(context.TODO(), suite.users[1])
 	require.Nil(suite.T(), data)
 	require.NotNil(suite.T(), err)
@@ -392,10 +473,10 @@ func (suite *accountTestSuite) TestAccountService_AddUser_ShouldError_Password()
 }
 
 func (suite *accountTestSuite) TestAccountService_AddUser_ShouldError() {
-	roleRepoMock := new(mocks2.ICRUDRepository[model.Role])
-	userRepoMock := new(mocks2.ICRUDRepository[model.User])
+	roleRepoMock := new(mocks2.ISoftDeleteRepository[model.Role])
+	userRepoMock := new(mocks2.ISoftDeleteRepository[model.User])
 	accSvc := service.NewAccountService(
-		roleRepoMock, userRepoMock)
+		roleRepoMock, userRepoMock, new(mocks2.IReferenceRepository))
 	userRepoMock.
 		On("Create", mock.Anything, mock.Anything).
 		Once().
@@ -408,10 +489,10 @@ func (suite *accountTestSuite) TestAccountService_AddUser_ShouldError() {
 }
 
 func (suite *accountTestSuite) TestAccountService_EditUser_ShouldSuccess() {
-	roleRepoMock := new(mocks2.ICRUDRepository[model.Role])
-	userRepoMock := new(mocks2.ICRUDRepository[model.User])
+	roleRepoMock := new(mocks2.ISoftDeleteRepository[model.Role])
+	userRepoMock := new(mocks2.ISoftDeleteRepository[model.User])
 	accSvc := service.NewAccountService(
-		roleRepoMock, userRepoMock)
+		roleRepoMock, userRepoMock, new(mocks2.IReferenceRepository))
 	userRepoMock.
 		On("Update", mock.Anything, mock.Anything).
 		Once().
@@ -424,15 +505,15 @@ func (suite *accountTestSuite) TestAccountService_EditUser_ShouldSuccess() {
 }
 
 func (suite *accountTestSuite) TestAccountService_EditUser_ShouldError_Password() {
-	roleRepoMock := new(mocks2.ICRUDRepository[model.Role])
-	userRepoMock := new(mocks2.ICRUDRepository[model.User])
+	roleRepoMock := new(mocks2.ISoftDeleteRepository[model.Role])
+	userRepoMock := new(mocks2.ISoftDeleteRepository[model.User])
 	pwdMock := new(mocks2.IPassword)
 	pwdMock.
 		On("HashPassword").
 		Return("", errors.New("UNEXPECTED")).
 		Once()
 	accSvc := service.NewAccountServiceTest(
-		roleRepoMock, userRepoMock, pwdMock)
+		roleRepoMock, userRepoMock, new(mocks2.IReferenceRepository), pwdMock)
 	data, err := accSvc.EditUser(context.TODO(), suite.users[1])
 	require.Nil(suite.T(), data)
 	require.NotNil(suite.T(), err)
@@ -441,10 +522,10 @@ func (suite *accountTestSuite) TestAccountService_EditUser_ShouldError_Password(
 }
 
 func (suite *accountTestSuite) TestAccountService_EditUser_ShouldError() {
-	roleRepoMock := new(mocks2.ICRUDRepository[model.Role])
-	userRepoMock := new(mocks2.ICRUDRepository[model.User])
+	roleRepoMock := new(mocks2.ISoftDeleteRepository[model.Role])
+	userRepoMock := new(mocks2.ISoftDeleteRepository[model.User])
 	accSvc := service.NewAccountService(
-		roleRepoMock, userRepoMock)
+		roleRepoMock, userRepoMock, new(mocks2.IReferenceRepository))
 	userRepoMock.
 		On("Update", mock.Anything, mock.Anything).
 		Once().
@@ -457,10 +538,10 @@ func (suite *accountTestSuite) TestAccountService_EditUser_ShouldError() {
 }
 
 func (suite *accountTestSuite) TestAccountService_DeleteUser_ShouldSuccess() {
-	roleRepoMock := new(mocks2.ICRUDRepository[model.Role])
-	userRepoMock := new(mocks2.ICRUDRepository[model.User])
+	roleRepoMock := new(mocks2.ISoftDeleteRepository[model.Role])
+	userRepoMock := new(mocks2.ISoftDeleteRepository[model.User])
 	accSvc := service.NewAccountService(
-		roleRepoMock, userRepoMock)
+		roleRepoMock, userRepoMock, new(mocks2.IReferenceRepository))
 	userRepoMock.
 		On("Find", mock.Anything, mock.Anything, mock.Anything).
 		Once().
@@ -474,10 +555,10 @@ func (suite *accountTestSuite) TestAccountService_DeleteUser_ShouldSuccess() {
 	roleRepoMock.AssertExpectations(suite.T())
 }
 func (suite *accountTestSuite) TestService_DeleteUser_ShouldErrorWhenFindNotFound() {
-	roleRepoMock := new(mocks2.ICRUDRepository[model.Role])
-	userRepoMock := new(mocks2.ICRUDRepository[model.User])
+	roleRepoMock := new(mocks2.ISoftDeleteRepository[model.Role])
+	userRepoMock := new(mocks2.ISoftDeleteRepository[model.User])
 	svc := service.NewAccountService(
-		roleRepoMock, userRepoMock)
+		roleRepoMock, userRepoMock, new(mocks2.IReferenceRepository))
 	userRepoMock.
 		On("Find", mock.Anything, mock.Anything, mock.Anything).
 		Once().
@@ -488,10 +569,10 @@ func (suite *accountTestSuite) TestService_DeleteUser_ShouldErrorWhenFindNotFoun
 	userRepoMock.AssertExpectations(suite.T())
 }
 func (suite *accountTestSuite) TestAccountService_DeleteUser_ShouldErrorWhenFind() {
-	roleRepoMock := new(mocks2.ICRUDRepository[model.Role])
-	userRepoMock := new(mocks2.ICRUDRepository[model.User])
+	roleRepoMock := new(mocks2.ISoftDeleteRepository[model.Role])
+	userRepoMock := new(mocks2.ISoftDeleteRepository[model.User])
 	accSvc := service.NewAccountService(
-		roleRepoMock, userRepoMock)
+		roleRepoMock, userRepoMock, new(mocks2.IReferenceRepository))
 	userRepoMock.
 		On("Find", mock.Anything, mock.Anything, mock.Anything).
 		Once().
@@ -503,10 +584,10 @@ func (suite *accountTestSuite) TestAccountService_DeleteUser_ShouldErrorWhenFind
 }
 
 func (suite *accountTestSuite) TestAccountService_DeleteUser_ShouldErrorWhenDelete() {
-	roleRepoMock := new(mocks2.ICRUDRepository[model.Role])
-	userRepoMock := new(mocks2.ICRUDRepository[model.User])
+	roleRepoMock := new(mocks2.ISoftDeleteRepository[model.Role])
+	userRepoMock := new(mocks2.ISoftDeleteRepository[model.User])
 	accSvc := service.NewAccountService(
-		roleRepoMock, userRepoMock)
+		roleRepoMock, userRepoMock, new(mocks2.IReferenceRepository))
 	userRepoMock.
 		On("Find", mock.Anything, mock.Anything, mock.Anything).
 		Once().
@@ -522,10 +603,10 @@ func (suite *accountTestSuite) TestAccountService_DeleteUser_ShouldErrorWhenDele
 }
 
 func (suite *accountTestSuite) TestAccountService_VerifyUserCredentials_ShouldSuccess() {
-	roleRepoMock := new(mocks2.ICRUDRepository[model.Role])
-	userRepoMock := new(mocks2.ICRUDRepository[model.User])
+	roleRepoMock := new(mocks2.ISoftDeleteRepository[model.Role])
+	userRepoMock := new(mocks2.ISoftDeleteRepository[model.User])
 	accSvc := service.NewAccountService(
-		roleRepoMock, userRepoMock)
+		roleRepoMock, userRepoMock, new(mocks2.IReferenceRepository))
 	userRepoMock.
 		On("Find", mock.Anything, mock.Anything, mock.Anything).
 		Once().
@@ -538,10 +619,10 @@ func (suite *accountTestSuite) TestAccountService_VerifyUserCredentials_ShouldSu
 }
 
 func (suite *accountTestSuite) TestAccountService_VerifyUserCredentials_ShouldErrorFind() {
-	roleRepoMock := new(mocks2.ICRUDRepository[model.Role])
-	userRepoMock := new(mocks2.ICRUDRepository[model.User])
+	roleRepoMock := new(mocks2.ISoftDeleteRepository[model.Role])
+	userRepoMock := new(mocks2.ISoftDeleteRepository[model.User])
 	accSvc := service.NewAccountService(
-		roleRepoMock, userRepoMock)
+		roleRepoMock, userRepoMock, new(mocks2.IReferenceRepository))
 	userRepoMock.
 		On("Find", mock.Anything, mock.Anything, mock.Anything).
 		Once().
@@ -553,10 +634,10 @@ func (suite *accountTestSuite) TestAccountService_VerifyUserCredentials_ShouldEr
 	roleRepoMock.AssertExpectations(suite.T())
 }
 func (suite *accountTestSuite) TestAccountService_VerifyUserCredentials_ShouldErrorWhenFindNotFound() {
-	roleRepoMock := new(mocks2.ICRUDRepository[model.Role])
-	userRepoMock := new(mocks2.ICRUDRepository[model.User])
+	roleRepoMock := new(mocks2.ISoftDeleteRepository[model.Role])
+	userRepoMock := new(mocks2.ISoftDeleteRepository[model.User])
 	svc := service.NewAccountService(
-		roleRepoMock, userRepoMock)
+		roleRepoMock, userRepoMock, new(mocks2.IReferenceRepository))
 	userRepoMock.
 		On("Find", mock.Anything, mock.Anything, mock.Anything).
 		Once().
@@ -571,11 +652,11 @@ func (suite *accountTestSuite) TestAccountService_VerifyUserCredentials_ShouldEr
 	userRepoMock.AssertExpectations(suite.T())
 }
 func (suite *accountTestSuite) TestAccountService_VerifyUserCredentials_ShouldErrorComparePassword() {
-	roleRepoMock := new(mocks2.ICRUDRepository[model.Role])
-	userRepoMock := new(mocks2.ICRUDRepository[model.User])
+	roleRepoMock := new(mocks2.ISoftDeleteRepository[model.Role])
+	userRepoMock := new(mocks2.ISoftDeleteRepository[model.User])
 	pwdUtil := new(mocks2.IPassword)
 	accSvc := service.NewAccountServiceTest(
-		roleRepoMock, userRepoMock, pwdUtil)
+		roleRepoMock, userRepoMock, new(mocks2.IReferenceRepository), pwdUtil)
 	userRepoMock.
 		On("Find", mock.Anything, mock.Anything, mock.Anything).
 		Once().
@@ -592,10 +673,10 @@ func (suite *accountTestSuite) TestAccountService_VerifyUserCredentials_ShouldEr
 }
 
 func (suite *accountTestSuite) TestAccountService_VerifyUserCredentials_ShouldErrorPassword() {
-	roleRepoMock := new(mocks2.ICRUDRepository[model.Role])
-	userRepoMock := new(mocks2.ICRUDRepository[model.User])
+	roleRepoMock := new(mocks2.ISoftDeleteRepository[model.Role])
+	userRepoMock := new(mocks2.ISoftDeleteRepository[model.User])
 	accSvc := service.NewAccountService(
-		roleRepoMock, userRepoMock)
+		roleRepoMock, userRepoMock, new(mocks2.IReferenceRepository))
 	userRepoMock.
 		On("Find", mock.Anything, mock.Anything, mock.Anything).
 		Once().
@@ -0,0 +1,149 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/aasumitro/posbe/common"
+	"github.com/aasumitro/posbe/pkg/model"
+	"github.com/aasumitro/posbe/pkg/utils"
+)
+
+const (
+	apiKeySize      = 24
+	apiKeyPrefix    = "pk_"
+	apiKeyPrefixLen = len(apiKeyPrefix) + 8
+)
+
+type apiKeyService struct {
+	apiKeyRepo model.IAPIKeyRepository
+}
+
+func (service apiKeyService) APIKeyList(
+	ctx context.Context,
+) (
+	keys []*model.APIKey,
+	errorData *utils.ServiceError,
+) {
+	data, err := service.apiKeyRepo.All(ctx)
+	return utils.ValidateDataRows[model.APIKey](data, err)
+}
+
+// AddAPIKey issue the key, it is returned once and should
+// be kept by the integration
+func (service apiKeyService) AddAPIKey(
+	ctx context.Context,
+	data *model.APIKey,
+) (
+	key *model.APIKey,
+	errorData *utils.ServiceError,
+) {
+	if errData := validatePermissions(data.Scopes); errData != nil {
+		return nil, errData
+	}
+	for _, scope := range data.Scopes {
+		if !slices.Contains(data.CreatorPermissions, scope) {
+			return nil, &utils.ServiceError{
+				Code:    http.StatusForbidden,
+				Message: fmt.Sprintf("scope %s is not granted to the creator", scope),
+			}
+		}
+	}
+	if data.ExpiresAt > 0 && data.ExpiresAt <= time.Now().Unix() {
+		return nil, &utils.ServiceError{
+			Code:    http.StatusUnprocessableEntity,
+			Message: "expires_at must be in the future",
+		}
+	}
+	plain, err := newAPIKey()
+	if err != nil {
+		return nil, internalError(err)
+	}
+	data.Prefix, data.KeyHash = plain[:apiKeyPrefixLen], hashToken(plain)
+	key, err = service.apiKeyRepo.Create(ctx, data)
+	if err != nil {
+		return utils.ValidateDataRow[model.APIKey](key, err)
+	}
+	key.Key = plain
+	return key, nil
+}
+
+// RotateAPIKey issue a new key, the current one keep working
+// for common.APIKeyRotationGrace seconds
+func (service apiKeyService) RotateAPIKey(
+	ctx context.Context,
+	id int,
+) (
+	key *model.APIKey,
+	errorData *utils.ServiceError,
+) {
+	plain, err := newAPIKey()
+	if err != nil {
+		return nil, internalError(err)
+	}
+	key, err = service.apiKeyRepo.Rotate(ctx, id, hashToken(plain), plain[:apiKeyPrefixLen],
+		time.Now().Unix()+common.APIKeyRotationGrace)
+	if err != nil {
+		return utils.ValidateDataRow[model.APIKey](key, err)
+	}
+	key.Key = plain
+	return key, nil
+}
+
+func (service apiKeyService) RevokeAPIKey(
+	ctx context.Context,
+	id int,
+) *utils.ServiceError {
+	_, errData := utils.ValidateDataRow[model.APIKey](
+		nil, service.apiKeyRepo.Revoke(ctx, id, time.Now().Unix()))
+	return errData
+}
+
+func (service apiKeyService) Authenticate(
+	ctx context.Context,
+	key string,
+) (
+	data *model.APIKey,
+	errorData *utils.ServiceError,
+) {
+	invalid := &utils.ServiceError{
+		Code:    http.StatusUnauthorized,
+		Message: "INVALID_API_KEY",
+	}
+	if !strings.HasPrefix(key, apiKeyPrefix) {
+		return nil, invalid
+	}
+	now := time.Now().Unix()
+	data, err := service.apiKeyRepo.FindByHash(ctx, hashToken(key), now)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, invalid
+	}
+	if err != nil {
+		return nil, internalError(err)
+	}
+	if now-data.LastUsedAt >= common.APIKeyTouchInterval {
+		if err := service.apiKeyRepo.Touch(ctx, data.ID, now); err != nil {
+			return nil, internalError(err)
+		}
+		data.LastUsedAt = now
+	}
+	return data, nil
+}
+
+func newAPIKey() (string, error) {
+	secret, err := randomHex(apiKeySize)
+	if err != nil {
+		return "", err
+	}
+	return apiKeyPrefix + secret, nil
+}
+
+func NewAPIKeyService(apiKeyRepo model.IAPIKeyRepository) model.IAPIKeyService {
+	return &apiKeyService{apiKeyRepo: apiKeyRepo}
+}
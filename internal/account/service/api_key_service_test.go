@@ -0,0 +1,127 @@
+package service_test
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aasumitro/posbe/internal/account/service"
+	"github.com/aasumitro/posbe/mocks"
+	"github.com/aasumitro/posbe/pkg/model"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type apiKeyTestSuite struct {
+	suite.Suite
+	apiKeyRepo *mocks.IAPIKeyRepository
+	svc        model.IAPIKeyService
+}
+
+func (suite *apiKeyTestSuite) SetupTest() {
+	suite.apiKeyRepo = new(mocks.IAPIKeyRepository)
+	suite.svc = service.NewAPIKeyService(suite.apiKeyRepo)
+}
+
+func (suite *apiKeyTestSuite) TestAPIKeyService_AddAPIKey_ShouldReturnKeyOnce() {
+	var stored *model.APIKey
+	suite.apiKeyRepo.On("Create", mock.Anything, mock.Anything).Once().
+		Return(func(_ context.Context, key *model.APIKey) *model.APIKey {
+			stored = key
+			return &model.APIKey{ID: 1, Name: key.Name, Prefix: key.Prefix, Scopes: key.Scopes}
+		}, nil)
+	data, err := suite.svc.AddAPIKey(context.TODO(), &model.APIKey{
+		Name: "accounting", Scopes: []string{model.PermissionReportExport},
+		CreatorPermissions: []string{model.PermissionReportExport, model.PermissionAccountAPIKeyWrite}})
+	require.Nil(suite.T(), err)
+	require.True(suite.T(), strings.HasPrefix(data.Key, "pk_"))
+	require.Len(suite.T(), data.Key, 51)
+	require.Equal(suite.T(), data.Key[:11], data.Prefix)
+	require.Equal(suite.T(), sha256Hex(data.Key), stored.KeyHash)
+}
+
+func (suite *apiKeyTestSuite) TestAPIKeyService_AddAPIKey_ShouldReject() {
+	for _, key := range []*model.APIKey{
+		{Name: "lorem", Scopes: []string{"unknown.scope"}},
+		{Name: "lorem", Scopes: []string{model.PermissionReportExport},
+			CreatorPermissions: []string{model.PermissionReportExport},
+			ExpiresAt:          time.Now().Unix() - 1},
+	} {
+		data, err := suite.svc.AddAPIKey(context.TODO(), key)
+		require.Nil(suite.T(), data)
+		require.Equal(suite.T(), http.StatusUnprocessableEntity, err.Code)
+	}
+	suite.apiKeyRepo.AssertNotCalled(suite.T(), "Create", mock.Anything, mock.Anything)
+}
+
+func (suite *apiKeyTestSuite) TestAPIKeyService_AddAPIKey_ShouldRejectScopeNotGrantedToCreator() {
+	// the creator may issue keys but is not granted the export
+	data, err := suite.svc.AddAPIKey(context.TODO(), &model.APIKey{
+		Name: "accounting", Scopes: []string{model.PermissionOrderRead, model.PermissionReportExport},
+		CreatorPermissions: []string{model.PermissionAccountAPIKeyWrite, model.PermissionOrderRead}})
+	require.Nil(suite.T(), data)
+	require.Equal(suite.T(), http.StatusForbidden, err.Code)
+	require.Contains(suite.T(), err.Message, model.PermissionReportExport)
+	suite.apiKeyRepo.AssertNotCalled(suite.T(), "Create", mock.Anything, mock.Anything)
+}
+
+func (suite *apiKeyTestSuite) TestAPIKeyService_RotateAPIKey_ShouldKeepPreviousKeyInGrace() {
+	suite.apiKeyRepo.On("Rotate", mock.Anything, 1, mock.Anything, mock.Anything,
+		mock.MatchedBy(func(until int64) bool {
+			return until > time.Now().Unix()+86000
+		})).Once().
+		Return(&model.APIKey{ID: 1, Name: "accounting"}, nil)
+	data, err := suite.svc.RotateAPIKey(context.TODO(), 1)
+	require.Nil(suite.T(), err)
+	require.True(suite.T(), strings.HasPrefix(data.Key, "pk_"))
+	suite.apiKeyRepo.AssertExpectations(suite.T())
+}
+
+func (suite *apiKeyTestSuite) TestAPIKeyService_RotateAPIKey_ShouldReturnNotFound() {
+	suite.apiKeyRepo.On("Rotate", mock.Anything, 9, mock.Anything, mock.Anything, mock.Anything).
+		Once().Return(nil, sql.ErrNoRows)
+	data, err := suite.svc.RotateAPIKey(context.TODO(), 9)
+	require.Nil(suite.T(), data)
+	require.Equal(suite.T(), http.StatusNotFound, err.Code)
+}
+
+func (suite *apiKeyTestSuite) TestAPIKeyService_RevokeAPIKey() {
+	suite.apiKeyRepo.On("Revoke", mock.Anything, 1, mock.Anything).Once().Return(nil)
+	suite.apiKeyRepo.On("Revoke", mock.Anything, 9, mock.Anything).Once().Return(sql.ErrNoRows)
+	require.Nil(suite.T(), suite.svc.RevokeAPIKey(context.TODO(), 1))
+	require.Equal(suite.T(), http.StatusNotFound, suite.svc.RevokeAPIKey(context.TODO(), 9).Code)
+}
+
+func (suite *apiKeyTestSuite) TestAPIKeyService_Authenticate_ShouldTouchOncePerInterval() {
+	suite.apiKeyRepo.On("FindByHash", mock.Anything, sha256Hex("pk_fresh"), mock.Anything).
+		Once().Return(&model.APIKey{ID: 1, Scopes: []string{model.PermissionReportExport}}, nil)
+	suite.apiKeyRepo.On("FindByHash", mock.Anything, sha256Hex("pk_recent"), mock.Anything).
+		Once().Return(&model.APIKey{ID: 2, LastUsedAt: time.Now().Unix()}, nil)
+	suite.apiKeyRepo.On("Touch", mock.Anything, 1, mock.Anything).Once().Return(nil)
+	data, err := suite.svc.Authenticate(context.TODO(), "pk_fresh")
+	require.Nil(suite.T(), err)
+	require.Equal(suite.T(), []string{model.PermissionReportExport}, data.Scopes)
+	require.NotZero(suite.T(), data.LastUsedAt)
+	_, err = suite.svc.Authenticate(context.TODO(), "pk_recent")
+	require.Nil(suite.T(), err)
+	suite.apiKeyRepo.AssertNumberOfCalls(suite.T(), "Touch", 1)
+}
+
+func (suite *apiKeyTestSuite) TestAPIKeyService_Authenticate_ShouldReject() {
+	suite.apiKeyRepo.On("FindByHash", mock.Anything, sha256Hex("pk_revoked"), mock.Anything).
+		Once().Return(nil, sql.ErrNoRows)
+	for _, key := range []string{"", "lorem", "pk_revoked"} {
+		data, err := suite.svc.Authenticate(context.TODO(), key)
+		require.Nil(suite.T(), data)
+		require.Equal(suite.T(), http.StatusUnauthorized, err.Code)
+		require.Equal(suite.T(), "INVALID_API_KEY", err.Message)
+	}
+}
+
+func TestAPIKeyService(t *testing.T) {
+	suite.Run(t, new(apiKeyTestSuite))
+}
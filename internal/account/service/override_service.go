@@ -0,0 +1,171 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/aasumitro/posbe/common"
+	"github.com/aasumitro/posbe/pkg/model"
+	"github.com/aasumitro/posbe/pkg/utils"
+)
+
+const overrideTokenSize = 32
+
+type overrideService struct {
+	overrideRepo model.IOverrideRepository
+	roleRepo     model.ICRUDRepository[model.Role]
+}
+
+func (service overrideService) SetPIN(
+	ctx context.Context,
+	userID int,
+	pin string,
+) *utils.ServiceError {
+	u := utils.Password{Stored: "", Supplied: pin}
+	hashed, err := u.HashPassword()
+	if err != nil {
+		return &utils.ServiceError{
+			Code:    http.StatusInternalServerError,
+			Message: err.Error(),
+		}
+	}
+	_, errData := utils.ValidateDataRow[model.UserPIN](
+		nil, service.overrideRepo.UpdateUserPIN(ctx, userID, hashed))
+	return errData
+}
+
+// Approve check the approver pin and permission, then issue a token
+// the requester send along with the action it approve
+func (service overrideService) Approve(
+	ctx context.Context,
+	form *model.OverrideForm,
+) (override *model.Override, errData *utils.ServiceError) {
+	if !slices.Contains(model.OverrideActions, form.Action) {
+		return nil, &utils.ServiceError{
+			Code:    http.StatusUnprocessableEntity,
+			Message: fmt.Sprintf("action %s can not be overridden", form.Action),
+		}
+	}
+	approver, err := service.overrideRepo.UserPIN(ctx, form.ApproverID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, &utils.ServiceError{
+			Code:    http.StatusInternalServerError,
+			Message: err.Error(),
+		}
+	}
+	if approver == nil || approver.PIN == "" {
+		return nil, &utils.ServiceError{
+			Code:    http.StatusUnprocessableEntity,
+			Message: "invalid user or pin",
+		}
+	}
+	if errData := verifyPIN(ctx, service.overrideRepo, approver, form.PIN); errData != nil {
+		return nil, errData
+	}
+	role, err := service.roleRepo.Find(ctx, model.FindWithID, approver.RoleID)
+	if err != nil {
+		_, errData := utils.ValidateDataRow[model.Role](role, err)
+		return nil, errData
+	}
+	if !slices.Contains(role.Permissions, form.Action) {
+		return nil, &utils.ServiceError{
+			Code:    http.StatusForbidden,
+			Message: fmt.Sprintf("approver is not allowed to %s", form.Action),
+		}
+	}
+
+	plain, err := randomHex(overrideTokenSize)
+	if err != nil {
+		return nil, &utils.ServiceError{
+			Code:    http.StatusInternalServerError,
+			Message: err.Error(),
+		}
+	}
+	data, err := service.overrideRepo.Create(ctx, &model.Override{
+		Action: form.Action, Reason: form.Reason,
+		ApprovedBy: approver.UserID, RequestedBy: form.RequestedBy,
+		ExpiresAt: time.Now().Add(common.OverrideTokenLifetime * time.Second).Unix(),
+	}, hashToken(plain))
+	if err != nil {
+		return utils.ValidateDataRow[model.Override](data, err)
+	}
+	data.Token = plain
+
+	return data, nil
+}
+
+func (service overrideService) Consume(
+	ctx context.Context,
+	token, action string,
+	usedBy int,
+) (override *model.Override, errData *utils.ServiceError) {
+	data, err := service.overrideRepo.Consume(ctx, hashToken(token), action, usedBy)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, &utils.ServiceError{
+			Code:    http.StatusForbidden,
+			Message: "override is invalid, expired or already used",
+		}
+	}
+	return utils.ValidateDataRow[model.Override](data, err)
+}
+
+// verifyPIN compare the supplied pin with the user pin, the pin is
+// locked for a while after common.PINMaxAttempts failures in a row
+func verifyPIN(
+	ctx context.Context,
+	repo model.IOverrideRepository,
+	pin *model.UserPIN,
+	supplied string,
+) *utils.ServiceError {
+	now := time.Now().Unix()
+	if pin.LockedUntil > now {
+		return &utils.ServiceError{
+			Code:    http.StatusLocked,
+			Message: "pin is locked after too many failed attempts",
+		}
+	}
+	u := utils.Password{Stored: pin.PIN, Supplied: supplied}
+	if ok, err := u.ComparePasswords(); err != nil || !ok {
+		if err := repo.PINFailed(ctx, pin.UserID, common.PINMaxAttempts,
+			now+common.PINLockoutTime); err != nil {
+			return &utils.ServiceError{
+				Code:    http.StatusInternalServerError,
+				Message: err.Error(),
+			}
+		}
+		return &utils.ServiceError{
+			Code:    http.StatusUnprocessableEntity,
+			Message: "invalid user or pin",
+		}
+	}
+	if err := repo.PINSucceeded(ctx, pin.UserID); err != nil {
+		return &utils.ServiceError{
+			Code:    http.StatusInternalServerError,
+			Message: err.Error(),
+		}
+	}
+	return nil
+}
+
+// hashToken keep the plain token, key or badge out of the database
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func NewOverrideService(
+	overrideRepo model.IOverrideRepository,
+	roleRepo model.ICRUDRepository[model.Role],
+) model.IOverrideService {
+	return &overrideService{
+		overrideRepo: overrideRepo,
+		roleRepo:     roleRepo,
+	}
+}
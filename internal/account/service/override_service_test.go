@@ -0,0 +1,116 @@
+package service_test
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/aasumitro/posbe/internal/account/service"
+	"github.com/aasumitro/posbe/mocks"
+	"github.com/aasumitro/posbe/pkg/model"
+	"github.com/aasumitro/posbe/pkg/utils"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type overrideTestSuite struct {
+	suite.Suite
+	pin          string
+	overrideRepo *mocks.IOverrideRepository
+	roleRepo     *mocks.ISoftDeleteRepository[model.Role]
+	svc          model.IOverrideService
+}
+
+func (suite *overrideTestSuite) SetupSuite() {
+	u := utils.Password{Supplied: "1234"}
+	pin, err := u.HashPassword()
+	require.NoError(suite.T(), err)
+	suite.pin = pin
+}
+
+func (suite *overrideTestSuite) SetupTest() {
+	suite.overrideRepo = new(mocks.IOverrideRepository)
+	suite.roleRepo = new(mocks.ISoftDeleteRepository[model.Role])
+	suite.svc = service.NewOverrideService(suite.overrideRepo, suite.roleRepo)
+	suite.overrideRepo.On("UserPIN", mock.Anything, 1).
+		Return(&model.UserPIN{UserID: 1, RoleID: 1, PIN: suite.pin}, nil)
+	suite.overrideRepo.On("UserPIN", mock.Anything, 2).
+		Return(&model.UserPIN{UserID: 2, RoleID: 2, PIN: suite.pin}, nil)
+	suite.overrideRepo.On("UserPIN", mock.Anything, 3).
+		Return(&model.UserPIN{UserID: 3, RoleID: 2}, nil)
+	suite.overrideRepo.On("UserPIN", mock.Anything, 4).
+		Return(&model.UserPIN{UserID: 4, RoleID: 1, PIN: suite.pin, LockedUntil: time.Now().Unix() + 60}, nil)
+	suite.overrideRepo.On("PINFailed", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	suite.overrideRepo.On("PINSucceeded", mock.Anything, mock.Anything).Return(nil)
+	suite.roleRepo.On("Find", mock.Anything, model.FindWithID, 1).
+		Return(&model.Role{ID: 1, Permissions: []string{model.PermissionOrderVoid}}, nil)
+	suite.roleRepo.On("Find", mock.Anything, model.FindWithID, 2).
+		Return(&model.Role{ID: 2, Permissions: []string{model.PermissionOrderRead}}, nil)
+}
+
+func (suite *overrideTestSuite) TestOverrideService_Approve_ShouldIssueToken() {
+	var tokenHash string
+	suite.overrideRepo.On("Create", mock.Anything, mock.Anything, mock.Anything).Once().
+		Return(func(_ context.Context, override *model.Override, hash string) *model.Override {
+			tokenHash = hash
+			override.ID = 1
+			return override
+		}, nil)
+	data, err := suite.svc.Approve(context.TODO(), &model.OverrideForm{
+		ApproverID: 1, PIN: "1234", Action: model.PermissionOrderVoid, RequestedBy: 4})
+	require.Nil(suite.T(), err)
+	require.Len(suite.T(), data.Token, 64)
+	require.NotEqual(suite.T(), data.Token, tokenHash)
+	require.Equal(suite.T(), 1, data.ApprovedBy)
+	require.Equal(suite.T(), 4, data.RequestedBy)
+	require.Greater(suite.T(), data.ExpiresAt, time.Now().Unix())
+}
+
+func (suite *overrideTestSuite) TestOverrideService_Approve_ShouldReject() {
+	for _, test := range []struct {
+		form *model.OverrideForm
+		code int
+	}{
+		{&model.OverrideForm{ApproverID: 1, PIN: "1234", Action: model.PermissionOrderRead}, http.StatusUnprocessableEntity},
+		{&model.OverrideForm{ApproverID: 1, PIN: "4321", Action: model.PermissionOrderVoid}, http.StatusUnprocessableEntity},
+		{&model.OverrideForm{ApproverID: 3, PIN: "1234", Action: model.PermissionOrderVoid}, http.StatusUnprocessableEntity},
+		{&model.OverrideForm{ApproverID: 2, PIN: "1234", Action: model.PermissionOrderVoid}, http.StatusForbidden},
+		{&model.OverrideForm{ApproverID: 4, PIN: "1234", Action: model.PermissionOrderVoid}, http.StatusLocked},
+	} {
+		data, err := suite.svc.Approve(context.TODO(), test.form)
+		require.Nil(suite.T(), data)
+		require.Equal(suite.T(), test.code, err.Code, test.form)
+	}
+	suite.overrideRepo.AssertNotCalled(suite.T(), "Create", mock.Anything, mock.Anything, mock.Anything)
+	suite.overrideRepo.AssertCalled(suite.T(), "PINFailed", mock.Anything, 1, 5, mock.Anything)
+}
+
+func (suite *overrideTestSuite) TestOverrideService_Consume_ShouldReturnApprover() {
+	suite.overrideRepo.On("Consume", mock.Anything, mock.Anything, model.PermissionOrderVoid, 4).Once().
+		Return(&model.Override{ID: 1, ApprovedBy: 1, UsedBy: 4}, nil)
+	data, err := suite.svc.Consume(context.TODO(), "lorem", model.PermissionOrderVoid, 4)
+	require.Nil(suite.T(), err)
+	require.Equal(suite.T(), 1, data.ApprovedBy)
+}
+
+func (suite *overrideTestSuite) TestOverrideService_Consume_ShouldErrorUsed() {
+	suite.overrideRepo.On("Consume", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Once().
+		Return(nil, sql.ErrNoRows)
+	data, err := suite.svc.Consume(context.TODO(), "lorem", model.PermissionOrderVoid, 4)
+	require.Nil(suite.T(), data)
+	require.Equal(suite.T(), http.StatusForbidden, err.Code)
+}
+
+func (suite *overrideTestSuite) TestOverrideService_SetPIN_ShouldErrorNotFound() {
+	suite.overrideRepo.On("UpdateUserPIN", mock.Anything, 9, mock.Anything).Once().
+		Return(sql.ErrNoRows)
+	err := suite.svc.SetPIN(context.TODO(), 9, "1234")
+	require.Equal(suite.T(), http.StatusNotFound, err.Code)
+}
+
+func TestOverrideService(t *testing.T) {
+	suite.Run(t, new(overrideTestSuite))
+}
@@ -0,0 +1,299 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aasumitro/posbe/common"
+	"github.com/aasumitro/posbe/config"
+	"github.com/aasumitro/posbe/pkg/model"
+	"github.com/aasumitro/posbe/pkg/utils"
+)
+
+const refreshSecretSize = 32
+
+type sessionService struct {
+	userRepo        model.ICRUDRepository[model.User]
+	jwt             utils.IJSONWebToken
+	refreshLifetime time.Duration
+}
+
+func (service sessionService) Start(
+	ctx context.Context,
+	session *model.AuthSession,
+	device, ip string,
+) (
+	data *model.AuthSession,
+	errorData *utils.ServiceError,
+) {
+	sessionID, err := randomHex(common.SessionIDSize)
+	if err != nil {
+		return nil, internalError(err)
+	}
+	refreshToken, err := newRefreshToken(sessionID)
+	if err != nil {
+		return nil, internalError(err)
+	}
+	now := time.Now().Unix()
+	key, userKey := sessionKey(sessionID), userSessionsKey(session.ID)
+	if err := config.KV.HSet(ctx, key, service.refreshLifetime,
+		"user_id", session.ID, "terminal_id", session.TerminalID,
+		"idle_timeout", session.IdleTimeout, "device", device, "ip", ip,
+		"created_at", now, "last_used_at", now, "refresh", hashToken(refreshToken),
+	); err != nil {
+		return nil, internalError(err)
+	}
+	if err := config.KV.SAdd(ctx, userKey, service.refreshLifetime, sessionID); err != nil {
+		return nil, internalError(err)
+	}
+	data = &model.AuthSession{
+		User: session.User, SessionID: sessionID,
+		TerminalID: session.TerminalID, IdleTimeout: session.IdleTimeout,
+	}
+	return service.issue(data, refreshToken)
+}
+
+func (service sessionService) Refresh(
+	ctx context.Context,
+	refreshToken, device, ip string,
+) (
+	data *model.AuthSession,
+	errorData *utils.ServiceError,
+) {
+	invalid := &utils.ServiceError{
+		Code:    http.StatusUnauthorized,
+		Message: "INVALID_REFRESH_TOKEN",
+	}
+	sessionID, _, found := strings.Cut(refreshToken, ".")
+	if !found || sessionID == "" {
+		return nil, invalid
+	}
+	key := sessionKey(sessionID)
+	values, err := config.KV.HGetAll(ctx, key)
+	if err != nil {
+		return nil, internalError(err)
+	}
+	if len(values) == 0 {
+		return nil, invalid
+	}
+	userID, _ := strconv.Atoi(values["user_id"])
+	if values["refresh"] != hashToken(refreshToken) {
+		// a rotated token is used again, it may be stolen
+		_ = service.revoke(ctx, userID, sessionID)
+		return nil, invalid
+	}
+	user, err := service.userRepo.Find(ctx, model.FindWithID, userID)
+	if err != nil {
+		_, errData := utils.ValidateDataRow[model.User](user, err)
+		if errData.Code == http.StatusNotFound {
+			_ = service.revoke(ctx, userID, sessionID)
+			return nil, invalid
+		}
+		return nil, errData
+	}
+	user.Password = ""
+
+	rotated, err := newRefreshToken(sessionID)
+	if err != nil {
+		return nil, internalError(err)
+	}
+	// rotate only when the token was not rotated since it was read,
+	// so two requests with the same token can not both get a new one
+	rotatedNow, err := config.KV.HCompareAndSet(ctx, key, service.refreshLifetime,
+		"refresh", hashToken(refreshToken),
+		"refresh", hashToken(rotated), "device", device,
+		"ip", ip, "last_used_at", time.Now().Unix(),
+	)
+	if err != nil {
+		return nil, internalError(err)
+	}
+	if !rotatedNow {
+		// used again while it was rotated, as a reuse
+		_ = service.revoke(ctx, userID, sessionID)
+		return nil, invalid
+	}
+	if err := config.KV.Expire(ctx, userSessionsKey(userID), service.refreshLifetime); err != nil {
+		return nil, internalError(err)
+	}
+	terminalID, _ := strconv.Atoi(values["terminal_id"])
+	idleTimeout, _ := strconv.Atoi(values["idle_timeout"])
+	return service.issue(&model.AuthSession{
+		User: user, SessionID: sessionID,
+		TerminalID: terminalID, IdleTimeout: idleTimeout,
+	}, rotated)
+}
+
+func (service sessionService) SessionList(
+	ctx context.Context,
+	userID int,
+	currentID string,
+) (
+	sessions []*model.Session,
+	errorData *utils.ServiceError,
+) {
+	userKey := userSessionsKey(userID)
+	sessionIDs, err := config.KV.SMembers(ctx, userKey)
+	if err != nil {
+		return nil, internalError(err)
+	}
+	sessions = []*model.Session{}
+	for _, sessionID := range sessionIDs {
+		values, err := config.KV.HGetAll(ctx, sessionKey(sessionID))
+		if err != nil {
+			return nil, internalError(err)
+		}
+		if len(values) == 0 {
+			// the session expired
+			_ = config.KV.SRem(ctx, userKey, sessionID)
+			continue
+		}
+		session := &model.Session{
+			ID: sessionID, UserID: userID, Device: values["device"],
+			IP: values["ip"], Current: sessionID == currentID,
+		}
+		session.TerminalID, _ = strconv.Atoi(values["terminal_id"])
+		session.CreatedAt, _ = strconv.ParseInt(values["created_at"], 10, 64)
+		session.LastUsedAt, _ = strconv.ParseInt(values["last_used_at"], 10, 64)
+		sessions = append(sessions, session)
+	}
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].LastUsedAt > sessions[j].LastUsedAt
+	})
+	return sessions, nil
+}
+
+func (service sessionService) Revoke(
+	ctx context.Context,
+	userID int,
+	sessionID string,
+) *utils.ServiceError {
+	values, err := config.KV.HGetAll(ctx, sessionKey(sessionID))
+	if err != nil {
+		return internalError(err)
+	}
+	if owner := values["user_id"]; owner == "" || owner != strconv.Itoa(userID) {
+		return &utils.ServiceError{
+			Code:    http.StatusNotFound,
+			Message: "session not found",
+		}
+	}
+	if err := service.revoke(ctx, userID, sessionID); err != nil {
+		return internalError(err)
+	}
+	return nil
+}
+
+func (service sessionService) RevokeAll(
+	ctx context.Context,
+	userID int,
+) *utils.ServiceError {
+	sessionIDs, err := config.KV.SMembers(ctx, userSessionsKey(userID))
+	if err != nil {
+		return internalError(err)
+	}
+	for _, sessionID := range sessionIDs {
+		if err := service.revoke(ctx, userID, sessionID); err != nil {
+			return internalError(err)
+		}
+	}
+	return nil
+}
+
+func (service sessionService) Validate(
+	ctx context.Context,
+	sessionID string,
+) *utils.ServiceError {
+	if sessionID == "" {
+		return &utils.ServiceError{
+			Code:    http.StatusUnauthorized,
+			Message: "SESSION_REQUIRED",
+		}
+	}
+	revoked, err := config.KV.Exists(ctx, sessionDenylistKey(sessionID))
+	if err != nil {
+		return internalError(err)
+	}
+	if revoked {
+		return &utils.ServiceError{
+			Code:    http.StatusUnauthorized,
+			Message: "SESSION_REVOKED",
+		}
+	}
+	return nil
+}
+
+// revoke remove the session so it can not be refreshed, and deny its
+// access tokens until the last one issued expire, the deny is written
+// first so a failure never leave a removed session with usable tokens
+func (service sessionService) revoke(ctx context.Context, userID int, sessionID string) error {
+	if err := config.KV.Set(ctx, sessionDenylistKey(sessionID), userID,
+		common.AccessTokenLifetime*time.Second); err != nil {
+		return err
+	}
+	if _, err := config.KV.Del(ctx, sessionKey(sessionID)); err != nil {
+		return err
+	}
+	return config.KV.SRem(ctx, userSessionsKey(userID), sessionID)
+}
+
+// issue claim the access token, the payload is the session without tokens
+func (service sessionService) issue(
+	session *model.AuthSession,
+	refreshToken string,
+) (*model.AuthSession, *utils.ServiceError) {
+	payload := *session
+	token, err := service.jwt.ClaimJWTToken(&payload)
+	if err != nil {
+		return nil, internalError(err)
+	}
+	session.AccessToken = token
+	session.RefreshToken = refreshToken
+	session.ExpiresIn = common.AccessTokenLifetime
+	return session, nil
+}
+
+// newRefreshToken is prefixed with the session id, so the session can be
+// found while only the hash of the token is stored
+func newRefreshToken(sessionID string) (string, error) {
+	secret, err := randomHex(refreshSecretSize)
+	if err != nil {
+		return "", err
+	}
+	return sessionID + "." + secret, nil
+}
+
+func sessionKey(sessionID string) string {
+	return fmt.Sprintf("session:%s", sessionID)
+}
+
+func userSessionsKey(userID int) string {
+	return fmt.Sprintf("user_sessions:%d", userID)
+}
+
+func sessionDenylistKey(sessionID string) string {
+	return fmt.Sprintf("session_denylist:%s", sessionID)
+}
+
+func internalError(err error) *utils.ServiceError {
+	return &utils.ServiceError{
+		Code:    http.StatusInternalServerError,
+		Message: err.Error(),
+	}
+}
+
+func NewSessionService(
+	userRepo model.ICRUDRepository[model.User],
+	jwt utils.IJSONWebToken,
+	refreshLifetime time.Duration,
+) model.ISessionService {
+	return &sessionService{
+		userRepo:        userRepo,
+		jwt:             jwt,
+		refreshLifetime: refreshLifetime,
+	}
+}
@@ -0,0 +1,183 @@
+package service_test
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/aasumitro/posbe/config"
+	"github.com/aasumitro/posbe/internal/account/service"
+	"github.com/aasumitro/posbe/mocks"
+	"github.com/aasumitro/posbe/pkg/kv"
+	"github.com/aasumitro/posbe/pkg/model"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type sessionTestSuite struct {
+	suite.Suite
+	redis    *miniredis.Miniredis
+	jwt      *mocks.IJSONWebToken
+	userRepo *mocks.ISoftDeleteRepository[model.User]
+	user     *model.User
+	svc      model.ISessionService
+}
+
+func (suite *sessionTestSuite) SetupTest() {
+	suite.redis = miniredis.RunT(suite.T())
+	config.KV = kv.NewRedisStore(redis.NewClient(&redis.Options{Addr: suite.redis.Addr()}))
+	suite.jwt = new(mocks.IJSONWebToken)
+	suite.userRepo = new(mocks.ISoftDeleteRepository[model.User])
+	suite.user = &model.User{ID: 1, Name: "lorem", Role: model.Role{ID: 2}}
+	suite.svc = service.NewSessionService(suite.userRepo, suite.jwt, time.Hour)
+	suite.jwt.On("ClaimJWTToken", mock.Anything).Return("access", nil)
+	suite.userRepo.On("Find", mock.Anything, model.FindWithID, 1).
+		Return(&model.User{ID: 1, Name: "lorem", Password: "secret", Role: model.Role{ID: 3}}, nil)
+	suite.userRepo.On("Find", mock.Anything, model.FindWithID, 2).Return(nil, sql.ErrNoRows)
+}
+
+func (suite *sessionTestSuite) TestSessionService_Start_ShouldIssueTokens() {
+	data, err := suite.svc.Start(context.TODO(), &model.AuthSession{User: suite.user}, "tablet", "10.0.0.1")
+	require.Nil(suite.T(), err)
+	require.Equal(suite.T(), "access", data.AccessToken)
+	require.Contains(suite.T(), data.RefreshToken, data.SessionID+".")
+	require.Equal(suite.T(), 900, data.ExpiresIn)
+	// the payload is claimed without tokens
+	payload := suite.jwt.Calls[0].Arguments.Get(0).(*model.AuthSession)
+	require.Empty(suite.T(), payload.RefreshToken)
+	require.Equal(suite.T(), data.SessionID, payload.SessionID)
+	require.Nil(suite.T(), suite.svc.Validate(context.TODO(), data.SessionID))
+}
+
+func (suite *sessionTestSuite) TestSessionService_Refresh_ShouldRotate() {
+	started, err := suite.svc.Start(context.TODO(), &model.AuthSession{User: suite.user}, "tablet", "10.0.0.1")
+	require.Nil(suite.T(), err)
+	refreshed, err := suite.svc.Refresh(context.TODO(), started.RefreshToken, "tablet", "10.0.0.2")
+	require.Nil(suite.T(), err)
+	require.Equal(suite.T(), started.SessionID, refreshed.SessionID)
+	require.NotEqual(suite.T(), started.RefreshToken, refreshed.RefreshToken)
+	require.Empty(suite.T(), refreshed.Password)
+	require.Equal(suite.T(), 3, refreshed.Role.ID)
+}
+
+func (suite *sessionTestSuite) TestSessionService_Refresh_ShouldRevokeOnReuse() {
+	started, err := suite.svc.Start(context.TODO(), &model.AuthSession{User: suite.user}, "tablet", "10.0.0.1")
+	require.Nil(suite.T(), err)
+	refreshed, err := suite.svc.Refresh(context.TODO(), started.RefreshToken, "tablet", "10.0.0.1")
+	require.Nil(suite.T(), err)
+	data, err := suite.svc.Refresh(context.TODO(), started.RefreshToken, "tablet", "10.0.0.1")
+	require.Nil(suite.T(), data)
+	require.Equal(suite.T(), http.StatusUnauthorized, err.Code)
+	data, err = suite.svc.Refresh(context.TODO(), refreshed.RefreshToken, "tablet", "10.0.0.1")
+	require.Nil(suite.T(), data)
+	require.Equal(suite.T(), http.StatusUnauthorized, err.Code)
+	err = suite.svc.Validate(context.TODO(), started.SessionID)
+	require.Equal(suite.T(), "SESSION_REVOKED", err.Message)
+}
+
+func (suite *sessionTestSuite) TestSessionService_Refresh_ShouldRotateOnceWhenConcurrent() {
+	// every request has read the session before any of them rotate it
+	var read sync.WaitGroup
+	read.Add(10)
+	userRepo := new(mocks.ISoftDeleteRepository[model.User])
+	userRepo.On("Find", mock.Anything, model.FindWithID, 1).
+		Run(func(mock.Arguments) { read.Done(); read.Wait() }).
+		Return(func(context.Context, model.FindWith, any) *model.User {
+			return &model.User{ID: 1, Name: "lorem", Role: model.Role{ID: 3}}
+		}, nil)
+	svc := service.NewSessionService(userRepo, suite.jwt, time.Hour)
+	started, err := svc.Start(context.TODO(), &model.AuthSession{User: suite.user}, "tablet", "10.0.0.1")
+	require.Nil(suite.T(), err)
+	var wg sync.WaitGroup
+	var refreshed, rejected atomic.Int32
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			data, err := svc.Refresh(context.TODO(), started.RefreshToken, "tablet", "10.0.0.1")
+			switch {
+			case err == nil && data != nil:
+				refreshed.Add(1)
+			case err != nil && err.Code == http.StatusUnauthorized:
+				rejected.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+	// only one request get a new token, the others are a reuse
+	require.Equal(suite.T(), int32(1), refreshed.Load())
+	require.Equal(suite.T(), int32(9), rejected.Load())
+	err = svc.Validate(context.TODO(), started.SessionID)
+	require.Equal(suite.T(), "SESSION_REVOKED", err.Message)
+}
+
+func (suite *sessionTestSuite) TestSessionService_Refresh_ShouldReject() {
+	started, err := suite.svc.Start(context.TODO(),
+		&model.AuthSession{User: &model.User{ID: 2}}, "tablet", "10.0.0.1")
+	require.Nil(suite.T(), err)
+	for _, token := range []string{"", "lorem", "lorem.ipsum", started.RefreshToken} {
+		data, err := suite.svc.Refresh(context.TODO(), token, "tablet", "10.0.0.1")
+		require.Nil(suite.T(), data)
+		require.Equal(suite.T(), http.StatusUnauthorized, err.Code, token)
+	}
+	suite.redis.FastForward(2 * time.Hour)
+	started, _ = suite.svc.Start(context.TODO(), &model.AuthSession{User: suite.user}, "tablet", "10.0.0.1")
+	suite.redis.FastForward(2 * time.Hour)
+	data, err := suite.svc.Refresh(context.TODO(), started.RefreshToken, "tablet", "10.0.0.1")
+	require.Nil(suite.T(), data)
+	require.Equal(suite.T(), http.StatusUnauthorized, err.Code)
+}
+
+func (suite *sessionTestSuite) TestSessionService_SessionList_ShouldMarkCurrent() {
+	first, _ := suite.svc.Start(context.TODO(), &model.AuthSession{User: suite.user}, "tablet", "10.0.0.1")
+	second, _ := suite.svc.Start(context.TODO(), &model.AuthSession{User: suite.user}, "desktop", "10.0.0.2")
+	_, _ = suite.svc.Start(context.TODO(), &model.AuthSession{User: &model.User{ID: 2}}, "phone", "10.0.0.3")
+	sessions, err := suite.svc.SessionList(context.TODO(), 1, second.SessionID)
+	require.Nil(suite.T(), err)
+	require.Len(suite.T(), sessions, 2)
+	for _, session := range sessions {
+		require.Equal(suite.T(), session.ID == second.SessionID, session.Current)
+		require.Contains(suite.T(), []string{first.SessionID, second.SessionID}, session.ID)
+	}
+}
+
+func (suite *sessionTestSuite) TestSessionService_Revoke_ShouldDenyAccessToken() {
+	first, _ := suite.svc.Start(context.TODO(), &model.AuthSession{User: suite.user}, "tablet", "10.0.0.1")
+	second, _ := suite.svc.Start(context.TODO(), &model.AuthSession{User: suite.user}, "desktop", "10.0.0.2")
+	err := suite.svc.Revoke(context.TODO(), 2, first.SessionID)
+	require.Equal(suite.T(), http.StatusNotFound, err.Code)
+	require.Nil(suite.T(), suite.svc.Revoke(context.TODO(), 1, first.SessionID))
+	require.Equal(suite.T(), http.StatusUnauthorized, suite.svc.Validate(context.TODO(), first.SessionID).Code)
+	require.Nil(suite.T(), suite.svc.Validate(context.TODO(), second.SessionID))
+	// denied until the last access token expire
+	suite.redis.FastForward(901 * time.Second)
+	require.Nil(suite.T(), suite.svc.Validate(context.TODO(), first.SessionID))
+}
+
+func (suite *sessionTestSuite) TestSessionService_RevokeAll_ShouldRevokeEverySession() {
+	first, _ := suite.svc.Start(context.TODO(), &model.AuthSession{User: suite.user}, "tablet", "10.0.0.1")
+	second, _ := suite.svc.Start(context.TODO(), &model.AuthSession{User: suite.user}, "desktop", "10.0.0.2")
+	require.Nil(suite.T(), suite.svc.RevokeAll(context.TODO(), 1))
+	for _, session := range []*model.AuthSession{first, second} {
+		require.Equal(suite.T(), http.StatusUnauthorized, suite.svc.Validate(context.TODO(), session.SessionID).Code)
+	}
+	sessions, err := suite.svc.SessionList(context.TODO(), 1, "")
+	require.Nil(suite.T(), err)
+	require.Empty(suite.T(), sessions)
+}
+
+func (suite *sessionTestSuite) TestSessionService_Validate_ShouldRequireSession() {
+	err := suite.svc.Validate(context.TODO(), "")
+	require.Equal(suite.T(), http.StatusUnauthorized, err.Code)
+}
+
+func TestSessionService(t *testing.T) {
+	suite.Run(t, new(sessionTestSuite))
+}
@@ -0,0 +1,355 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/aasumitro/posbe/common"
+	"github.com/aasumitro/posbe/config"
+	"github.com/aasumitro/posbe/pkg/kv"
+	"github.com/aasumitro/posbe/pkg/model"
+	"github.com/aasumitro/posbe/pkg/utils"
+)
+
+const terminalKeySize = 32
+
+type terminalService struct {
+	terminalRepo   model.ISoftDeleteRepository[model.Terminal]
+	overrideRepo   model.IOverrideRepository
+	userRepo       model.ICRUDRepository[model.User]
+	sessionService model.ISessionService
+}
+
+func (service terminalService) TerminalList(
+	ctx context.Context,
+) (
+	terminals []*model.Terminal,
+	errorData *utils.ServiceError,
+) {
+	data, err := service.terminalRepo.All(ctx)
+	return utils.ValidateDataRows[model.Terminal](data, err)
+}
+
+// AddTerminal register the terminal, the key is returned once and
+// should be kept on the device to log in with
+func (service terminalService) AddTerminal(
+	ctx context.Context,
+	data *model.Terminal,
+) (
+	terminal *model.Terminal,
+	errorData *utils.ServiceError,
+) {
+	key, err := randomHex(terminalKeySize)
+	if err != nil {
+		return nil, &utils.ServiceError{
+			Code:    http.StatusInternalServerError,
+			Message: err.Error(),
+		}
+	}
+	if data.IdleTimeout == 0 {
+		data.IdleTimeout = common.TerminalIdleTimeout
+	}
+	data.KeyHash = hashToken(key)
+	terminal, err = service.terminalRepo.Create(ctx, data)
+	if err != nil {
+		return utils.ValidateDataRow[model.Terminal](terminal, err)
+	}
+	terminal.Key = key
+	return terminal, nil
+}
+
+func (service terminalService) EditTerminal(
+	ctx context.Context,
+	data *model.Terminal,
+) (
+	terminal *model.Terminal,
+	errorData *utils.ServiceError,
+) {
+	if data.IdleTimeout == 0 {
+		data.IdleTimeout = common.TerminalIdleTimeout
+	}
+	terminal, err := service.terminalRepo.Update(ctx, data)
+	if err == nil {
+		// the idle timeout and disabled state apply on the next login
+		_, _ = config.KV.Del(ctx, terminalSessionKey(terminal.ID))
+	}
+	return utils.ValidateDataRow[model.Terminal](terminal, err)
+}
+
+func (service terminalService) DeleteTerminal(
+	ctx context.Context,
+	data *model.Terminal,
+) *utils.ServiceError {
+	terminal, err := service.terminalRepo.Find(ctx, model.FindWithID, data.ID)
+	if err != nil {
+		_, errData := utils.ValidateDataRow[model.Terminal](terminal, err)
+		return errData
+	}
+	if err := service.terminalRepo.Delete(ctx, terminal); err != nil {
+		return &utils.ServiceError{
+			Code:    http.StatusInternalServerError,
+			Message: err.Error(),
+		}
+	}
+	_, _ = config.KV.Del(ctx, terminalSessionKey(terminal.ID))
+	return nil
+}
+
+func (service terminalService) RestoreTerminal(
+	ctx context.Context,
+	data *model.Terminal,
+) (
+	terminal *model.Terminal,
+	errorData *utils.ServiceError,
+) {
+	terminal, err := service.terminalRepo.Restore(ctx, data)
+	return utils.ValidateDataRow[model.Terminal](terminal, err)
+}
+
+func (service terminalService) SetBadge(
+	ctx context.Context,
+	userID int,
+	badge string,
+) *utils.ServiceError {
+	badgeHash := hashToken(badge)
+	owner, err := service.overrideRepo.UserBadge(ctx, badgeHash)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return &utils.ServiceError{
+			Code:    http.StatusInternalServerError,
+			Message: err.Error(),
+		}
+	}
+	if owner != nil && owner.UserID != userID {
+		return &utils.ServiceError{
+			Code:    http.StatusUnprocessableEntity,
+			Message: "badge is used by another user",
+		}
+	}
+	_, errData := utils.ValidateDataRow[model.UserPIN](
+		nil, service.overrideRepo.UpdateUserBadge(ctx, userID, badgeHash))
+	return errData
+}
+
+func (service terminalService) TerminalUsers(
+	ctx context.Context,
+	key string,
+) (
+	users []*model.TerminalUser,
+	errorData *utils.ServiceError,
+) {
+	if _, errData := service.terminal(ctx, key); errData != nil {
+		return nil, errData
+	}
+	data, err := service.overrideRepo.TerminalUsers(ctx)
+	return utils.ValidateDataRows[model.TerminalUser](data, err)
+}
+
+func (service terminalService) QuickLogin(
+	ctx context.Context,
+	key string,
+	form *model.QuickLoginForm,
+) (
+	session *model.AuthSession,
+	errorData *utils.ServiceError,
+) {
+	terminal, errData := service.terminal(ctx, key)
+	if errData != nil {
+		return nil, errData
+	}
+
+	var pin *model.UserPIN
+	var err error
+	if form.Badge != "" {
+		if errData := badgeLocked(ctx, terminal.ID, form.IP); errData != nil {
+			return nil, errData
+		}
+		pin, err = service.overrideRepo.UserBadge(ctx, hashToken(form.Badge))
+	} else {
+		pin, err = service.overrideRepo.UserPIN(ctx, form.UserID)
+	}
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, &utils.ServiceError{
+			Code:    http.StatusInternalServerError,
+			Message: err.Error(),
+		}
+	}
+	if pin == nil && form.Badge != "" {
+		return nil, badgeFailed(ctx, terminal.ID, form.IP)
+	}
+	if pin == nil || (form.Badge == "" && pin.PIN == "") {
+		return nil, &utils.ServiceError{
+			Code:    http.StatusUnprocessableEntity,
+			Message: "invalid user, pin or badge",
+		}
+	}
+	if form.Badge == "" {
+		if errData := verifyPIN(ctx, service.overrideRepo, pin, form.PIN); errData != nil {
+			return nil, errData
+		}
+	}
+
+	user, err := service.userRepo.Find(ctx, model.FindWithID, pin.UserID)
+	if err != nil {
+		_, errData := utils.ValidateDataRow[model.User](user, err)
+		return nil, errData
+	}
+	user.Password = ""
+	session, errData = service.sessionService.Start(ctx, &model.AuthSession{
+		User: user, TerminalID: terminal.ID, IdleTimeout: terminal.IdleTimeout,
+	}, form.Device, form.IP)
+	if errData != nil {
+		return nil, errData
+	}
+
+	// one session per terminal, log in replace the session of the previous user
+	terminalKey := terminalSessionKey(terminal.ID)
+	previous, err := config.KV.HGetAll(ctx, terminalKey)
+	if err != nil {
+		return nil, &utils.ServiceError{
+			Code:    http.StatusInternalServerError,
+			Message: err.Error(),
+		}
+	}
+	if err := config.KV.HSet(ctx, terminalKey,
+		time.Duration(terminal.IdleTimeout)*time.Second,
+		"session", session.SessionID, "user", user.ID, "idle", terminal.IdleTimeout,
+	); err != nil {
+		return nil, &utils.ServiceError{
+			Code:    http.StatusInternalServerError,
+			Message: err.Error(),
+		}
+	}
+	if previousID := previous["session"]; previousID != "" {
+		userID, _ := strconv.Atoi(previous["user"])
+		// the previous session may already be revoked by its user
+		_ = service.sessionService.Revoke(ctx, userID, previousID)
+	}
+
+	return session, nil
+}
+
+func (service terminalService) TouchSession(
+	ctx context.Context,
+	terminalID int,
+	sessionID string,
+) *utils.ServiceError {
+	sessionKey := terminalSessionKey(terminalID)
+	values, err := config.KV.HGetAll(ctx, sessionKey)
+	if err != nil {
+		return &utils.ServiceError{
+			Code:    http.StatusInternalServerError,
+			Message: err.Error(),
+		}
+	}
+	active := values["session"]
+	if active == "" || active != sessionID {
+		return &utils.ServiceError{
+			Code:    http.StatusUnauthorized,
+			Message: "SESSION_EXPIRED",
+		}
+	}
+	idle, _ := strconv.Atoi(values["idle"])
+	if idle <= 0 {
+		idle = common.TerminalIdleTimeout
+	}
+	_ = config.KV.Expire(ctx, sessionKey, time.Duration(idle)*time.Second)
+	return nil
+}
+
+// terminal find the enabled terminal of the key
+func (service terminalService) terminal(
+	ctx context.Context,
+	key string,
+) (*model.Terminal, *utils.ServiceError) {
+	invalid := &utils.ServiceError{
+		Code:    http.StatusUnauthorized,
+		Message: "INVALID_TERMINAL",
+	}
+	if key == "" {
+		return nil, invalid
+	}
+	terminal, err := service.terminalRepo.Find(ctx, model.FindWithKey, hashToken(key))
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, &utils.ServiceError{
+			Code:    http.StatusInternalServerError,
+			Message: err.Error(),
+		}
+	}
+	if terminal == nil || terminal.Disabled {
+		return nil, invalid
+	}
+	return terminal, nil
+}
+
+// badgeLocked reject the badge login while the terminal or the ip reached
+// common.PINMaxAttempts failures, a badge has no pin to lock on the user
+// so the scans are locked where they come from
+func badgeLocked(ctx context.Context, terminalID int, ip string) *utils.ServiceError {
+	for _, key := range badgeFailureKeys(terminalID, ip) {
+		value, err := config.KV.Get(ctx, key)
+		if err != nil && !errors.Is(err, kv.ErrNil) {
+			return internalError(err)
+		}
+		if failures, _ := strconv.Atoi(value); failures >= common.PINMaxAttempts {
+			return &utils.ServiceError{
+				Code:    http.StatusLocked,
+				Message: "badge is locked after too many failed attempts",
+			}
+		}
+	}
+	return nil
+}
+
+// badgeFailed count the failure, the counters are not reset by a valid
+// badge so the failures only expire after common.PINLockoutTime
+func badgeFailed(ctx context.Context, terminalID int, ip string) *utils.ServiceError {
+	for _, key := range badgeFailureKeys(terminalID, ip) {
+		if _, err := config.KV.Incr(ctx, key, common.PINLockoutTime*time.Second); err != nil {
+			return internalError(err)
+		}
+	}
+	return &utils.ServiceError{
+		Code:    http.StatusUnprocessableEntity,
+		Message: "invalid user, pin or badge",
+	}
+}
+
+func badgeFailureKeys(terminalID int, ip string) []string {
+	return []string{
+		fmt.Sprintf("badge_failures:terminal:%d", terminalID),
+		fmt.Sprintf("badge_failures:ip:%s", ip),
+	}
+}
+
+func terminalSessionKey(terminalID int) string {
+	return fmt.Sprintf("terminal_session:%d", terminalID)
+}
+
+func randomHex(size int) (string, error) {
+	value := make([]byte, size)
+	if _, err := rand.Read(value); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(value), nil
+}
+
+func NewTerminalService(
+	terminalRepo model.ISoftDeleteRepository[model.Terminal],
+	overrideRepo model.IOverrideRepository,
+	userRepo model.ICRUDRepository[model.User],
+	sessionService model.ISessionService,
+) model.ITerminalService {
+	return &terminalService{
+		terminalRepo:   terminalRepo,
+		overrideRepo:   overrideRepo,
+		userRepo:       userRepo,
+		sessionService: sessionService,
+	}
+}
@@ -0,0 +1,223 @@
+package service_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/aasumitro/posbe/config"
+	"github.com/aasumitro/posbe/internal/account/service"
+	"github.com/aasumitro/posbe/mocks"
+	"github.com/aasumitro/posbe/pkg/kv"
+	"github.com/aasumitro/posbe/pkg/model"
+	"github.com/aasumitro/posbe/pkg/utils"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type terminalTestSuite struct {
+	suite.Suite
+	pin          string
+	redis        *miniredis.Miniredis
+	terminalRepo *mocks.ISoftDeleteRepository[model.Terminal]
+	overrideRepo *mocks.IOverrideRepository
+	userRepo     *mocks.ISoftDeleteRepository[model.User]
+	sessionSvc   model.ISessionService
+	svc          model.ITerminalService
+}
+
+func (suite *terminalTestSuite) SetupSuite() {
+	u := utils.Password{Supplied: "1234"}
+	pin, err := u.HashPassword()
+	require.NoError(suite.T(), err)
+	suite.pin = pin
+}
+
+func (suite *terminalTestSuite) SetupTest() {
+	suite.redis = miniredis.RunT(suite.T())
+	config.KV = kv.NewRedisStore(redis.NewClient(&redis.Options{Addr: suite.redis.Addr()}))
+	suite.terminalRepo = new(mocks.ISoftDeleteRepository[model.Terminal])
+	suite.overrideRepo = new(mocks.IOverrideRepository)
+	suite.userRepo = new(mocks.ISoftDeleteRepository[model.User])
+	suite.sessionSvc = service.NewSessionService(suite.userRepo,
+		&utils.JSONWebToken{SecretKey: []byte("secret"), Lifetime: time.Minute}, time.Hour)
+	suite.svc = service.NewTerminalService(
+		suite.terminalRepo, suite.overrideRepo, suite.userRepo, suite.sessionSvc)
+	suite.terminalRepo.On("Find", mock.Anything, model.FindWithKey, mock.Anything).
+		Return(func(_ context.Context, _ model.FindWith, val any) *model.Terminal {
+			switch val {
+			case sha256Hex("tablet-key"):
+				return &model.Terminal{ID: 1, Name: "tablet 1", IdleTimeout: 60}
+			case sha256Hex("disabled-key"):
+				return &model.Terminal{ID: 2, Name: "tablet 2", IdleTimeout: 60, Disabled: true}
+			case sha256Hex("kiosk-key"):
+				return &model.Terminal{ID: 3, Name: "kiosk", IdleTimeout: 60}
+			}
+			return nil
+		}, func(_ context.Context, _ model.FindWith, val any) error {
+			switch val {
+			case sha256Hex("tablet-key"), sha256Hex("disabled-key"), sha256Hex("kiosk-key"):
+				return nil
+			}
+			return sql.ErrNoRows
+		})
+	suite.overrideRepo.On("UserPIN", mock.Anything, 1).
+		Return(&model.UserPIN{UserID: 1, RoleID: 2, PIN: suite.pin}, nil)
+	suite.overrideRepo.On("UserPIN", mock.Anything, 9).Return(nil, sql.ErrNoRows)
+	suite.overrideRepo.On("UserBadge", mock.Anything, sha256Hex("B-001")).
+		Return(&model.UserPIN{UserID: 1, RoleID: 2}, nil)
+	suite.overrideRepo.On("UserBadge", mock.Anything, mock.Anything).Return(nil, sql.ErrNoRows)
+	suite.overrideRepo.On("PINFailed", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	suite.overrideRepo.On("PINSucceeded", mock.Anything, mock.Anything).Return(nil)
+	suite.userRepo.On("Find", mock.Anything, model.FindWithID, 1).
+		Return(&model.User{ID: 1, Name: "lorem", Password: "secret", Role: model.Role{ID: 2}}, nil)
+}
+
+func (suite *terminalTestSuite) TestTerminalService_AddTerminal_ShouldReturnKeyOnce() {
+	var keyHash string
+	suite.terminalRepo.On("Create", mock.Anything, mock.Anything).Once().
+		Return(func(_ context.Context, terminal *model.Terminal) *model.Terminal {
+			keyHash = terminal.KeyHash
+			return &model.Terminal{ID: 1, Name: terminal.Name, IdleTimeout: terminal.IdleTimeout}
+		}, nil)
+	data, err := suite.svc.AddTerminal(context.TODO(), &model.Terminal{Name: "tablet 1"})
+	require.Nil(suite.T(), err)
+	require.Len(suite.T(), data.Key, 64)
+	require.Equal(suite.T(), sha256Hex(data.Key), keyHash)
+	require.Equal(suite.T(), 300, data.IdleTimeout)
+}
+
+func (suite *terminalTestSuite) TestTerminalService_QuickLogin_ShouldSwitchUser() {
+	first, err := suite.svc.QuickLogin(context.TODO(), "tablet-key",
+		&model.QuickLoginForm{UserID: 1, PIN: "1234"})
+	require.Nil(suite.T(), err)
+	require.Empty(suite.T(), first.Password)
+	require.Equal(suite.T(), 1, first.TerminalID)
+	require.NotEmpty(suite.T(), first.AccessToken)
+	payload, _ := json.Marshal(first)
+	require.Contains(suite.T(), string(payload), `"terminal_id":1`)
+	require.Contains(suite.T(), string(payload), `"name":"lorem"`)
+
+	second, err := suite.svc.QuickLogin(context.TODO(), "tablet-key",
+		&model.QuickLoginForm{Badge: "B-001"})
+	require.Nil(suite.T(), err)
+	require.NotEqual(suite.T(), first.SessionID, second.SessionID)
+	require.Nil(suite.T(), suite.svc.TouchSession(context.TODO(), 1, second.SessionID))
+	errData := suite.svc.TouchSession(context.TODO(), 1, first.SessionID)
+	require.Equal(suite.T(), http.StatusUnauthorized, errData.Code)
+	errData = suite.sessionSvc.Validate(context.TODO(), first.SessionID)
+	require.Equal(suite.T(), http.StatusUnauthorized, errData.Code)
+}
+
+func (suite *terminalTestSuite) TestTerminalService_TouchSession_ShouldExpireWhenIdle() {
+	session, err := suite.svc.QuickLogin(context.TODO(), "tablet-key",
+		&model.QuickLoginForm{UserID: 1, PIN: "1234"})
+	require.Nil(suite.T(), err)
+	suite.redis.FastForward(40 * time.Second)
+	require.Nil(suite.T(), suite.svc.TouchSession(context.TODO(), 1, session.SessionID))
+	suite.redis.FastForward(40 * time.Second)
+	require.Nil(suite.T(), suite.svc.TouchSession(context.TODO(), 1, session.SessionID))
+	suite.redis.FastForward(61 * time.Second)
+	errData := suite.svc.TouchSession(context.TODO(), 1, session.SessionID)
+	require.Equal(suite.T(), http.StatusUnauthorized, errData.Code)
+}
+
+func (suite *terminalTestSuite) TestTerminalService_QuickLogin_ShouldReject() {
+	for _, test := range []struct {
+		key  string
+		form *model.QuickLoginForm
+		code int
+	}{
+		{"", &model.QuickLoginForm{UserID: 1, PIN: "1234"}, http.StatusUnauthorized},
+		{"unknown-key", &model.QuickLoginForm{UserID: 1, PIN: "1234"}, http.StatusUnauthorized},
+		{"disabled-key", &model.QuickLoginForm{UserID: 1, PIN: "1234"}, http.StatusUnauthorized},
+		{"tablet-key", &model.QuickLoginForm{UserID: 1, PIN: "4321"}, http.StatusUnprocessableEntity},
+		{"tablet-key", &model.QuickLoginForm{UserID: 9, PIN: "1234"}, http.StatusUnprocessableEntity},
+		{"tablet-key", &model.QuickLoginForm{Badge: "B-404"}, http.StatusUnprocessableEntity},
+	} {
+		data, err := suite.svc.QuickLogin(context.TODO(), test.key, test.form)
+		require.Nil(suite.T(), data)
+		require.Equal(suite.T(), test.code, err.Code, test)
+	}
+	suite.overrideRepo.AssertNumberOfCalls(suite.T(), "PINFailed", 1)
+}
+
+func (suite *terminalTestSuite) TestTerminalService_QuickLogin_ShouldLockBadgePerTerminal() {
+	for i := 0; i < 5; i++ {
+		_, err := suite.svc.QuickLogin(context.TODO(), "tablet-key",
+			&model.QuickLoginForm{Badge: "B-404", IP: "10.0.0.1"})
+		require.Equal(suite.T(), http.StatusUnprocessableEntity, err.Code)
+	}
+	// the terminal is locked even for a valid badge from another ip
+	_, err := suite.svc.QuickLogin(context.TODO(), "tablet-key",
+		&model.QuickLoginForm{Badge: "B-001", IP: "10.0.0.2"})
+	require.Equal(suite.T(), http.StatusLocked, err.Code)
+	// the pin login of the terminal is not locked by the badges
+	_, err = suite.svc.QuickLogin(context.TODO(), "tablet-key",
+		&model.QuickLoginForm{UserID: 1, PIN: "1234", IP: "10.0.0.2"})
+	require.Nil(suite.T(), err)
+	suite.redis.FastForward(301 * time.Second)
+	_, err = suite.svc.QuickLogin(context.TODO(), "tablet-key",
+		&model.QuickLoginForm{Badge: "B-001", IP: "10.0.0.2"})
+	require.Nil(suite.T(), err)
+}
+
+func (suite *terminalTestSuite) TestTerminalService_QuickLogin_ShouldLockBadgePerIP() {
+	for i := 0; i < 5; i++ {
+		// spread over the terminals to stay under the limit of each one
+		key := "tablet-key"
+		if i%2 == 1 {
+			key = "kiosk-key"
+		}
+		_, err := suite.svc.QuickLogin(context.TODO(), key,
+			&model.QuickLoginForm{Badge: "B-404", IP: "10.0.0.1"})
+		require.Equal(suite.T(), http.StatusUnprocessableEntity, err.Code)
+	}
+	_, err := suite.svc.QuickLogin(context.TODO(), "kiosk-key",
+		&model.QuickLoginForm{Badge: "B-001", IP: "10.0.0.1"})
+	require.Equal(suite.T(), http.StatusLocked, err.Code)
+	suite.overrideRepo.AssertNumberOfCalls(suite.T(), "UserBadge", 5)
+}
+
+func (suite *terminalTestSuite) TestTerminalService_TerminalUsers_ShouldReturnProjection() {
+	suite.overrideRepo.On("TerminalUsers", mock.Anything).Once().Return([]*model.TerminalUser{
+		{ID: 1, Name: "lorem", HasPIN: true}, {ID: 2, Name: "ipsum"},
+	}, nil)
+	users, err := suite.svc.TerminalUsers(context.TODO(), "tablet-key")
+	require.Nil(suite.T(), err)
+	payload, _ := json.Marshal(users)
+	require.JSONEq(suite.T(), `[{"id":1,"name":"lorem","has_pin":true},`+
+		`{"id":2,"name":"ipsum","has_pin":false}]`, string(payload))
+	suite.userRepo.AssertNotCalled(suite.T(), "All", mock.Anything)
+	_, err = suite.svc.TerminalUsers(context.TODO(), "unknown-key")
+	require.Equal(suite.T(), http.StatusUnauthorized, err.Code)
+}
+
+func (suite *terminalTestSuite) TestTerminalService_SetBadge_ShouldErrorUsedByOther() {
+	err := suite.svc.SetBadge(context.TODO(), 2, "B-001")
+	require.Equal(suite.T(), http.StatusUnprocessableEntity, err.Code)
+	suite.overrideRepo.AssertNotCalled(suite.T(), "UpdateUserBadge", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *terminalTestSuite) TestTerminalService_SetBadge_ShouldSuccess() {
+	suite.overrideRepo.On("UpdateUserBadge", mock.Anything, 1, sha256Hex("B-001")).Once().Return(nil)
+	err := suite.svc.SetBadge(context.TODO(), 1, "B-001")
+	require.Nil(suite.T(), err)
+}
+
+func TestTerminalService(t *testing.T) {
+	suite.Run(t, new(terminalTestSuite))
+}
+
+func sha256Hex(value string) string {
+	sum := sha256.Sum256([]byte(value))
+	return hex.EncodeToString(sum[:])
+}
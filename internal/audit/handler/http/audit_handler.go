@@ -0,0 +1,57 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/aasumitro/posbe/pkg/http/middleware"
+	"github.com/aasumitro/posbe/pkg/model"
+	"github.com/aasumitro/posbe/pkg/utils"
+	"github.com/gin-gonic/gin"
+)
+
+type auditHandler struct {
+	svc model.IAuditService
+}
+
+// audit godoc
+// @Schemes
+// @Summary Audit Log
+// @Description Get mutating requests of users and api keys, newest first.
+// @Description Changes compare the before and after state of updated entity.
+// @Tags Audit
+// @Accept json
+// @Produce json
+// @Param actor_id 		query int 	 false "user id"
+// @Param api_key_id 	query int 	 false "api key id"
+// @Param terminal_id 	query int 	 false "terminal id"
+// @Param action 		query string false "action" Enums(create, update, delete)
+// @Param entity_type 	query string false "route before the first param, e.g. products or products/variants"
+// @Param entity_id 	query string false "entity id"
+// @Param from 			query int 	 false "range start (unix time)"
+// @Param to 			query int 	 false "range end (unix time)"
+// @Param limit 		query int 	 false "max entries, default 50 (max 500)"
+// @Param offset 		query int 	 false "entries to skip"
+// @Success 200 {object} utils.SuccessRespond{data=[]model.AuditEntry} "OK RESPOND"
+// @Failure 401 {object} utils.ErrorRespond "UNAUTHORIZED RESPOND"
+// @Failure 422 {object} utils.ValidationErrorRespond "UNPROCESSABLE ENTITY RESPOND"
+// @Failure 500 {object} utils.ErrorRespond "INTERNAL SERVER ERROR RESPOND"
+// @Router /api/v1/audit [GET]
+func (handler auditHandler) fetch(ctx *gin.Context) {
+	var filter model.AuditFilter
+	if err := ctx.ShouldBindQuery(&filter); err != nil {
+		utils.NewHTTPRespond(ctx, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+	entries, err := handler.svc.AuditList(ctx, &filter)
+	if err != nil {
+		utils.NewHTTPRespond(ctx, err.Code, err.Message)
+		return
+	}
+	utils.NewHTTPRespond(ctx, http.StatusOK, entries)
+}
+
+func NewAuditHandler(svc model.IAuditService, router gin.IRoutes) {
+	handler := auditHandler{svc: svc}
+	read := middleware.Permitted(model.PermissionAuditRead)
+	router.GET("/audit", read, handler.fetch)
+}
@@ -0,0 +1,32 @@
+package audit
+
+import (
+	"context"
+
+	"github.com/aasumitro/posbe/common"
+	"github.com/aasumitro/posbe/internal/audit/handler/http"
+	repository "github.com/aasumitro/posbe/internal/audit/repository/sql"
+	"github.com/aasumitro/posbe/internal/audit/service"
+	"github.com/aasumitro/posbe/pkg/http/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+// NewAuditModuleProvider start the audit writer, it keep writing until ctx
+// is done, done is closed after the last entries are written
+func NewAuditModuleProvider(
+	ctx context.Context,
+	router *gin.RouterGroup,
+) (done <-chan struct{}) {
+	auditService := service.NewAuditService(
+		repository.NewAuditSQLRepository())
+	middleware.SetAuditRecorder(auditService.Record)
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		auditService.Run(ctx)
+	}()
+	protectedRouter := router.Group(common.EmptyPath).
+		Use(middleware.Auth())
+	http.NewAuditHandler(auditService, protectedRouter)
+	return closed
+}
@@ -0,0 +1,131 @@
+package sql
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/aasumitro/posbe/common"
+	"github.com/aasumitro/posbe/config"
+	"github.com/aasumitro/posbe/pkg/model"
+)
+
+const auditColumns = "actor_id, api_key_id, terminal_id, session_id, action, method, route, " +
+	"entity_type, entity_id, status, before_data, after_data, ip, created_at"
+
+type AuditSQLRepository struct {
+	Db *sql.DB
+}
+
+// Insert write the entries with one statement
+func (repo AuditSQLRepository) Insert(ctx context.Context, entries []*model.AuditEntry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+	columnSize := len(strings.Split(auditColumns, ","))
+	values := make([]string, 0, len(entries))
+	args := make([]any, 0, len(entries)*columnSize)
+	for i, entry := range entries {
+		placeholders := make([]string, columnSize)
+		for j := range placeholders {
+			placeholders[j] = fmt.Sprintf("$%d", i*columnSize+j+1)
+		}
+		values = append(values, "("+strings.Join(placeholders, ", ")+")")
+		args = append(args,
+			nullInt(entry.ActorID), nullInt(entry.APIKeyID), nullInt(entry.TerminalID),
+			nullString(entry.SessionID), entry.Action, entry.Method, entry.Route,
+			entry.EntityType, nullString(entry.EntityID), entry.Status,
+			nullString(string(entry.Before)), nullString(string(entry.After)),
+			nullString(entry.IP), entry.CreatedAt)
+	}
+	q := "INSERT INTO audit_logs (" + auditColumns + ") VALUES " + strings.Join(values, ", ")
+	_, err := repo.Db.ExecContext(ctx, q, args...)
+	return err
+}
+
+func (repo AuditSQLRepository) Search(
+	ctx context.Context,
+	filter *model.AuditFilter,
+) (entries []*model.AuditEntry, err error) {
+	var where []string
+	var args []any
+	condition := func(expr string, value any) {
+		args = append(args, value)
+		where = append(where, fmt.Sprintf(expr, len(args)))
+	}
+	if filter.ActorID > 0 {
+		condition("actor_id = $%d", filter.ActorID)
+	}
+	if filter.APIKeyID > 0 {
+		condition("api_key_id = $%d", filter.APIKeyID)
+	}
+	if filter.TerminalID > 0 {
+		condition("terminal_id = $%d", filter.TerminalID)
+	}
+	if filter.Action != "" {
+		condition("action = $%d", filter.Action)
+	}
+	if filter.EntityType != "" {
+		condition("entity_type = $%d", filter.EntityType)
+	}
+	if filter.EntityID != "" {
+		condition("entity_id = $%d", filter.EntityID)
+	}
+	if filter.From > 0 {
+		condition("created_at >= $%d", filter.From)
+	}
+	if filter.To > 0 {
+		condition("created_at <= $%d", filter.To)
+	}
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = common.AuditDefaultLimit
+	}
+	q := "SELECT id, " + auditColumns + " FROM audit_logs"
+	if len(where) > 0 {
+		q += " WHERE " + strings.Join(where, " AND ")
+	}
+	q += fmt.Sprintf(" ORDER BY id DESC LIMIT $%d OFFSET $%d", len(args)+1, len(args)+2)
+	rows, err := repo.Db.QueryContext(ctx, q, append(args, limit, filter.Offset)...)
+	if err != nil {
+		return nil, err
+	}
+	defer func(rows *sql.Rows) { _ = rows.Close() }(rows)
+	for rows.Next() {
+		var entry model.AuditEntry
+		var actorID, apiKeyID, terminalID sql.NullInt64
+		var sessionID, entityID, before, after, ip sql.NullString
+		if err := rows.Scan(
+			&entry.ID, &actorID, &apiKeyID, &terminalID, &sessionID,
+			&entry.Action, &entry.Method, &entry.Route, &entry.EntityType,
+			&entityID, &entry.Status, &before, &after, &ip, &entry.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		entry.ActorID, entry.APIKeyID = int(actorID.Int64), int(apiKeyID.Int64)
+		entry.TerminalID = int(terminalID.Int64)
+		entry.SessionID, entry.EntityID, entry.IP = sessionID.String, entityID.String, ip.String
+		if before.Valid {
+			entry.Before = json.RawMessage(before.String)
+		}
+		if after.Valid {
+			entry.After = json.RawMessage(after.String)
+		}
+		entries = append(entries, &entry)
+	}
+	return entries, rows.Err()
+}
+
+func nullInt(value int) sql.NullInt64 {
+	return sql.NullInt64{Int64: int64(value), Valid: value > 0}
+}
+
+func nullString(value string) sql.NullString {
+	return sql.NullString{String: value, Valid: value != ""}
+}
+
+func NewAuditSQLRepository() model.IAuditRepository {
+	return &AuditSQLRepository{Db: config.DbPool}
+}
@@ -0,0 +1,121 @@
+package sql_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/aasumitro/posbe/config"
+	repoSql "github.com/aasumitro/posbe/internal/audit/repository/sql"
+	"github.com/aasumitro/posbe/pkg/dbtest"
+	"github.com/aasumitro/posbe/pkg/dialect"
+	"github.com/aasumitro/posbe/pkg/model"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type auditRepositoryTestSuite struct {
+	suite.Suite
+	mock    sqlmock.Sqlmock
+	repo    model.IAuditRepository
+	columns []string
+}
+
+func (suite *auditRepositoryTestSuite) SetupSuite() {
+	var err error
+	config.DbPool, suite.mock, err = sqlmock.New(
+		sqlmock.QueryMatcherOption(
+			sqlmock.QueryMatcherRegexp))
+	require.NoError(suite.T(), err)
+	suite.repo = repoSql.NewAuditSQLRepository()
+	suite.columns = []string{"id", "actor_id", "api_key_id", "terminal_id", "session_id",
+		"action", "method", "route", "entity_type", "entity_id", "status",
+		"before_data", "after_data", "ip", "created_at"}
+}
+
+func (suite *auditRepositoryTestSuite) AfterTest(_, _ string) {
+	require.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+func (suite *auditRepositoryTestSuite) TestRepository_Insert_ExpectOneStatement() {
+	suite.mock.ExpectExec("INSERT INTO audit_logs (.+) VALUES \\(\\$1, (.+), \\$14\\), \\(\\$15, (.+), \\$28\\)").
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	err := suite.repo.Insert(context.TODO(), []*model.AuditEntry{
+		{ActorID: 1, Action: "update", Method: "PUT", Route: "/api/v1/units/:id",
+			EntityType: "units", EntityID: "1", Status: 200, After: []byte(`{"id":1}`)},
+		{APIKeyID: 2, Action: "delete", Method: "DELETE", Route: "/api/v1/units/:id",
+			EntityType: "units", EntityID: "2", Status: 204},
+	})
+	require.NoError(suite.T(), err)
+}
+
+func (suite *auditRepositoryTestSuite) TestRepository_Insert_ExpectNothingWhenEmpty() {
+	require.NoError(suite.T(), suite.repo.Insert(context.TODO(), nil))
+}
+
+func (suite *auditRepositoryTestSuite) TestRepository_Search_ExpectReturnRows() {
+	suite.mock.ExpectQuery("SELECT (.+) FROM audit_logs WHERE actor_id = \\$1 AND entity_type = \\$2 "+
+		"AND created_at >= \\$3 ORDER BY id DESC LIMIT \\$4 OFFSET \\$5").
+		WithArgs(1, "units", int64(100), 50, 0).
+		WillReturnRows(suite.mock.NewRows(suite.columns).
+			AddRow(2, 1, nil, nil, "sid", "update", "PUT", "/api/v1/units/:id", "units", "1",
+				200, `{"name":"gram"}`, `{"name":"kilogram"}`, "127.0.0.1", 123).
+			AddRow(1, 1, nil, 3, nil, "create", "POST", "/api/v1/units", "units", "1",
+				201, nil, `{"name":"gram"}`, nil, 120))
+	res, err := suite.repo.Search(context.TODO(), &model.AuditFilter{
+		ActorID: 1, EntityType: "units", From: 100})
+	require.NoError(suite.T(), err)
+	require.Len(suite.T(), res, 2)
+	require.JSONEq(suite.T(), `{"name":"gram"}`, string(res[0].Before))
+	require.Nil(suite.T(), res[1].Before)
+	require.Equal(suite.T(), 3, res[1].TerminalID)
+}
+
+func (suite *auditRepositoryTestSuite) TestRepository_Search_ExpectReturnError() {
+	suite.mock.ExpectQuery("SELECT (.+) FROM audit_logs ORDER BY id DESC").
+		WithArgs(10, 20).
+		WillReturnError(errors.New("UNEXPECTED"))
+	res, err := suite.repo.Search(context.TODO(), &model.AuditFilter{Limit: 10, Offset: 20})
+	require.Nil(suite.T(), res)
+	require.Error(suite.T(), err)
+}
+
+func (suite *auditRepositoryTestSuite) TestAuditRepository_Search() {
+	dbtest.Migrated(suite.T(), config.Dialect.Name())
+	ctx := context.TODO()
+	repo := repoSql.NewAuditSQLRepository()
+	require.NoError(suite.T(), repo.Insert(ctx, []*model.AuditEntry{
+		{ActorID: 1, Action: "update", Method: "PUT", Route: "/v1/units/:id", EntityType: "units",
+			EntityID: "1", Status: 200, Before: json.RawMessage(`{"name":"lorem"}`),
+			After: json.RawMessage(`{"name":"ipsum"}`), IP: "127.0.0.1", CreatedAt: 100},
+		{TerminalID: 2, Action: "delete", Method: "DELETE", Route: "/v1/units/:id",
+			EntityType: "units", EntityID: "2", Status: 204, CreatedAt: 200},
+	}))
+
+	entries, err := repo.Search(ctx, &model.AuditFilter{EntityType: "units"})
+	require.NoError(suite.T(), err)
+	require.Len(suite.T(), entries, 2)
+	require.Equal(suite.T(), "delete", entries[0].Action)
+	require.Equal(suite.T(), 2, entries[0].TerminalID)
+	require.Nil(suite.T(), entries[0].Before)
+	require.JSONEq(suite.T(), `{"name":"ipsum"}`, string(entries[1].After))
+
+	entries, err = repo.Search(ctx, &model.AuditFilter{ActorID: 1, From: 50, To: 150})
+	require.NoError(suite.T(), err)
+	require.Len(suite.T(), entries, 1)
+	entries, err = repo.Search(ctx, &model.AuditFilter{Limit: 1, Offset: 1})
+	require.NoError(suite.T(), err)
+	require.Len(suite.T(), entries, 1)
+	require.Equal(suite.T(), "update", entries[0].Action)
+}
+
+func TestAuditRepository(t *testing.T) {
+	for _, d := range dialect.All() {
+		t.Run(d.Name(), func(t *testing.T) {
+			config.Dialect = d
+			suite.Run(t, new(auditRepositoryTestSuite))
+		})
+	}
+}
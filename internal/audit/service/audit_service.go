@@ -0,0 +1,147 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"slices"
+	"time"
+
+	"github.com/aasumitro/posbe/common"
+	"github.com/aasumitro/posbe/pkg/audit"
+	"github.com/aasumitro/posbe/pkg/model"
+	"github.com/aasumitro/posbe/pkg/utils"
+)
+
+var (
+	// redactedFields never reach the audit log
+	redactedFields = []string{
+		"password", "pin", "badge", "token", "access_token", "refresh_token",
+	}
+	// secretKeyEntities return the plain key once (e.g. issued api key),
+	// the key of other entities is kept (e.g. store pref key)
+	secretKeyEntities = []string{"terminals", "api-keys"}
+)
+
+type auditService struct {
+	auditRepo model.IAuditRepository
+	queue     chan *model.AuditEntry
+}
+
+func (service *auditService) Record(entry *model.AuditEntry) {
+	select {
+	case service.queue <- entry:
+	default:
+		log.Printf("AUDIT_DROPPED: %s %s\n", entry.Method, entry.Route)
+	}
+}
+
+func (service *auditService) Run(ctx context.Context) {
+	ticker := time.NewTicker(common.AuditFlushInterval * time.Second)
+	defer ticker.Stop()
+	batch := make([]*model.AuditEntry, 0, common.AuditBatchSize)
+	for {
+		select {
+		case entry := <-service.queue:
+			if batch = append(batch, entry); len(batch) >= common.AuditBatchSize {
+				batch = service.write(batch)
+			}
+		case <-ticker.C:
+			batch = service.write(batch)
+		case <-ctx.Done():
+			for {
+				select {
+				case entry := <-service.queue:
+					if batch = append(batch, entry); len(batch) >= common.AuditBatchSize {
+						batch = service.write(batch)
+					}
+				default:
+					service.write(batch)
+					return
+				}
+			}
+		}
+	}
+}
+
+// write the batch with its own context, so the last batch
+// is still written when the server shut down
+func (service *auditService) write(batch []*model.AuditEntry) []*model.AuditEntry {
+	if len(batch) == 0 {
+		return batch
+	}
+	for _, entry := range batch {
+		fields := redactedFields
+		if slices.Contains(secretKeyEntities, entry.EntityType) {
+			fields = append(slices.Clip(fields), "key")
+		}
+		entry.Before, entry.After = redact(entry.Before, fields), redact(entry.After, fields)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(),
+		common.ServerReadTimeout*time.Second)
+	defer cancel()
+	if err := service.auditRepo.Insert(ctx, batch); err != nil {
+		log.Printf("AUDIT_ERROR: %d entries lost: %s\n", len(batch), err.Error())
+	}
+	return batch[:0]
+}
+
+func (service *auditService) AuditList(
+	ctx context.Context,
+	filter *model.AuditFilter,
+) (
+	entries []*model.AuditEntry,
+	errorData *utils.ServiceError,
+) {
+	data, err := service.auditRepo.Search(ctx, filter)
+	for _, entry := range data {
+		entry.Changes = audit.Diff(entry.Before, entry.After)
+	}
+	return utils.ValidateDataRows[model.AuditEntry](data, err)
+}
+
+func redact(data json.RawMessage, fields []string) json.RawMessage {
+	if len(data) == 0 {
+		return data
+	}
+	var value any
+	if err := json.Unmarshal(data, &value); err != nil {
+		return nil
+	}
+	if !redactValue(value, fields) {
+		return data
+	}
+	redacted, err := json.Marshal(value)
+	if err != nil {
+		return nil
+	}
+	return redacted
+}
+
+// redactValue mask the redacted fields in place, true when one is masked
+func redactValue(value any, fields []string) (masked bool) {
+	switch v := value.(type) {
+	case map[string]any:
+		for field, item := range v {
+			if slices.Contains(fields, field) {
+				if item != nil && item != "" {
+					v[field], masked = "[REDACTED]", true
+				}
+				continue
+			}
+			masked = redactValue(item, fields) || masked
+		}
+	case []any:
+		for _, item := range v {
+			masked = redactValue(item, fields) || masked
+		}
+	}
+	return masked
+}
+
+func NewAuditService(auditRepo model.IAuditRepository) model.IAuditService {
+	return &auditService{
+		auditRepo: auditRepo,
+		queue:     make(chan *model.AuditEntry, common.AuditQueueSize),
+	}
+}
@@ -0,0 +1,111 @@
+package service_test
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/aasumitro/posbe/internal/audit/service"
+	"github.com/aasumitro/posbe/mocks"
+	"github.com/aasumitro/posbe/pkg/model"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type auditTestSuite struct {
+	suite.Suite
+	auditRepo *mocks.IAuditRepository
+	svc       model.IAuditService
+}
+
+func (suite *auditTestSuite) SetupTest() {
+	suite.auditRepo = new(mocks.IAuditRepository)
+	suite.svc = service.NewAuditService(suite.auditRepo)
+}
+
+// run the writer and return the written batches when it stop
+func (suite *auditTestSuite) run(record func()) [][]*model.AuditEntry {
+	var batches [][]*model.AuditEntry
+	suite.auditRepo.On("Insert", mock.Anything, mock.Anything).
+		Return(func(_ context.Context, entries []*model.AuditEntry) error {
+			batches = append(batches, append([]*model.AuditEntry(nil), entries...))
+			return nil
+		})
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		suite.svc.Run(ctx)
+	}()
+	record()
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		suite.T().Fatal("writer not stopped")
+	}
+	return batches
+}
+
+func (suite *auditTestSuite) TestAuditService_Run_ShouldWriteInBatches() {
+	batches := suite.run(func() {
+		for i := 0; i < 150; i++ {
+			suite.svc.Record(&model.AuditEntry{EntityType: "units", EntityID: fmt.Sprint(i)})
+		}
+	})
+	var total int
+	for _, batch := range batches {
+		require.LessOrEqual(suite.T(), len(batch), 100)
+		total += len(batch)
+	}
+	require.Equal(suite.T(), 150, total)
+}
+
+func (suite *auditTestSuite) TestAuditService_Run_ShouldRedactSecrets() {
+	batches := suite.run(func() {
+		suite.svc.Record(&model.AuditEntry{EntityType: "users",
+			Before: []byte(`{"id":1,"password":"hash","role":{"id":1}}`)})
+		suite.svc.Record(&model.AuditEntry{EntityType: "api-keys",
+			After: []byte(`{"id":1,"key":"pk_secret","prefix":"pk_secret"}`)})
+		suite.svc.Record(&model.AuditEntry{EntityType: "store/prefs",
+			After: []byte(`[{"key":"currency","value":"IDR"}]`)})
+	})
+	require.Len(suite.T(), batches, 1)
+	require.JSONEq(suite.T(), `{"id":1,"password":"[REDACTED]","role":{"id":1}}`,
+		string(batches[0][0].Before))
+	require.JSONEq(suite.T(), `{"id":1,"key":"[REDACTED]","prefix":"pk_secret"}`,
+		string(batches[0][1].After))
+	require.JSONEq(suite.T(), `[{"key":"currency","value":"IDR"}]`,
+		string(batches[0][2].After))
+}
+
+func (suite *auditTestSuite) TestAuditService_AuditList_ShouldReturnChanges() {
+	suite.auditRepo.On("Search", mock.Anything, mock.Anything).Once().
+		Return([]*model.AuditEntry{
+			{ID: 2, Action: "update", Before: []byte(`{"id":1,"name":"gram","symbol":"g"}`),
+				After: []byte(`{"id":1,"name":"kilogram","symbol":"kg"}`)},
+			{ID: 1, Action: "create", After: []byte(`{"id":1,"name":"gram"}`)},
+		}, nil)
+	data, err := suite.svc.AuditList(context.TODO(), &model.AuditFilter{EntityType: "units"})
+	require.Nil(suite.T(), err)
+	require.Len(suite.T(), data[0].Changes, 2)
+	require.Equal(suite.T(), "gram", data[0].Changes["name"].Before)
+	require.Equal(suite.T(), "kilogram", data[0].Changes["name"].After)
+	require.Nil(suite.T(), data[1].Changes)
+}
+
+func (suite *auditTestSuite) TestAuditService_AuditList_ShouldReturnError() {
+	suite.auditRepo.On("Search", mock.Anything, mock.Anything).Once().
+		Return(nil, sql.ErrConnDone)
+	data, err := suite.svc.AuditList(context.TODO(), &model.AuditFilter{})
+	require.Nil(suite.T(), data)
+	require.Equal(suite.T(), http.StatusInternalServerError, err.Code)
+}
+
+func TestAuditService(t *testing.T) {
+	suite.Run(t, new(auditTestSuite))
+}
@@ -15,9 +15,12 @@ import (
 	"github.com/aasumitro/posbe/common"
 	"github.com/aasumitro/posbe/config"
 	"github.com/aasumitro/posbe/internal/account"
+	"github.com/aasumitro/posbe/internal/audit"
 	"github.com/aasumitro/posbe/internal/catalog"
+	"github.com/aasumitro/posbe/internal/report"
 	"github.com/aasumitro/posbe/internal/store"
 	"github.com/aasumitro/posbe/internal/transaction"
+	"github.com/aasumitro/posbe/pkg/storage"
 	"github.com/aasumitro/posbe/web"
 	"github.com/gin-gonic/gin"
 	swaggerFiles "github.com/swaggo/files"
@@ -48,8 +51,12 @@ func RunServer(ctx context.Context) {
 	routerEngine := config.GinEngine
 	// register public routes
 	registerPublicRoutes(ctx, routerEngine)
+	// background workers are stopped after the server,
+	// so the requests in flight are still audited
+	workerCtx, stopWorkers := context.WithCancel(context.Background())
+	defer stopWorkers()
 	// register providers
-	registerAPIModuleV1(routerEngine)
+	auditDone := registerAPIModuleV1(workerCtx, routerEngine)
 	// server defines parameters for running an HTTP server.
 	server := &http.Server{
 		Addr:              config.Instance.AppPort,
@@ -80,13 +87,16 @@ func RunServer(ctx context.Context) {
 	if err := server.Shutdown(ctx); err != nil {
 		log.Printf("Server forced to shutdown: %s\n", err)
 	}
+	// write the pending audit entries before closing the database
+	stopWorkers()
+	<-auditDone
 	// Close database connections
-	if err := config.PostgresPool.Close(); err != nil {
+	if err := config.DbPool.Close(); err != nil {
 		log.Printf("Error disconnect mongodb connection: %v\n", err)
 	}
-	// Close redis connections
-	if err := config.RedisPool.Close(); err != nil {
-		log.Printf("Error shutting down redis connection: %v\n", err)
+	// Close the cache connections (redis)
+	if err := config.KV.Close(); err != nil {
+		log.Printf("Error shutting down cache connection: %v\n", err)
 	}
 	// notify user of shutdown
 	log.Println("Server exiting")
@@ -132,27 +142,44 @@ func registerPublicRoutes(
 	})
 	// client (web ui) route handler
 	router.StaticFS("/fe", http.FS(web.SPAAssets()))
+	// uploaded files, only when kept on local storage
+	if config.Instance.StorageDriver == storage.DriverLocal &&
+		strings.HasPrefix(config.Instance.StoragePublicURL, "/") {
+		router.Static(config.Instance.StoragePublicURL,
+			config.Instance.StorageLocalPath)
+	}
 	// swagger docs routes
 	router.GET("/api-specs/*any",
 		ginSwagger.WrapHandler(swaggerFiles.Handler,
 			ginSwagger.DefaultModelsExpandDepth(
 				common.SwaggerDefaultModelsExpandDepth)))
 	// health check routes
-	redisCheck := checks.NewRedisCheck(config.RedisPool)
 	healthConfig := healthcheckconfig.DefaultConfig()
 	healthConfig.HealthPath = "/health"
-	_ = healthcheck.New(router, healthConfig, []checks.Check{
-		&redisCheck, checks.NewContextCheck(sgCtx, "signals"),
+	healthChecks := []checks.Check{
+		checks.NewContextCheck(sgCtx, "signals"),
 		checks.NewPingCheck("https://www.google.com",
 			"GET", common.HealthCheckPingTimeout, nil, nil),
-		checks.SqlCheck{Sql: config.PostgresPool},
-	})
+		checks.SqlCheck{Sql: config.DbPool},
+	}
+	// the memory cache has nothing to check
+	if config.RedisPool != nil {
+		redisCheck := checks.NewRedisCheck(config.RedisPool)
+		healthChecks = append(healthChecks, &redisCheck)
+	}
+	_ = healthcheck.New(router, healthConfig, healthChecks)
 }
 
-func registerAPIModuleV1(engine *gin.Engine) {
+func registerAPIModuleV1(
+	workerCtx context.Context,
+	engine *gin.Engine,
+) (auditDone <-chan struct{}) {
 	routerGroup := engine.Group("api/v1")
+	auditDone = audit.NewAuditModuleProvider(workerCtx, routerGroup)
 	account.NewAccountModuleProvider(routerGroup)
 	store.NewStoreModuleProvider(routerGroup)
 	catalog.NewCatalogModuleProvider(routerGroup)
 	transaction.NewTransactionModuleProvider(routerGroup)
+	report.NewReportModuleProvider(routerGroup)
+	return auditDone
 }
@@ -0,0 +1,391 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/aasumitro/posbe/pkg/http/middleware"
+	"github.com/aasumitro/posbe/pkg/model"
+	"github.com/aasumitro/posbe/pkg/utils"
+	"github.com/gin-gonic/gin"
+)
+
+type addonGroupHandler struct {
+	svc model.ICatalogModifierService
+}
+
+// addon groups godoc
+// @Schemes
+// @Summary Addon Groups List
+// @Description Get Addon (modifier) Groups List with their addons.
+// @Tags Product Addon Groups
+// @Accept json
+// @Produce json
+// @Success 200 {object} utils.SuccessRespond{data=[]model.AddonGroup} "OK RESPOND"
+// @Failure 401 {object} utils.ErrorRespond "UNAUTHORIZED RESPOND"
+// @Failure 500 {object} utils.ErrorRespond "INTERNAL SERVER ERROR RESPOND"
+// @Router /api/v1/addon-groups [GET]
+func (handler addonGroupHandler) fetch(ctx *gin.Context) {
+	data, err := handler.svc.AddonGroupList(ctx)
+	if err != nil {
+		utils.NewHTTPRespond(ctx, err.Code, err.Message)
+		return
+	}
+	utils.NewHTTPRespond(ctx, http.StatusOK, data)
+}
+
+// addon groups godoc
+// @Schemes
+// @Summary Store Addon Group Data
+// @Description Create new addon group, max_select 0 mean unlimited.
+// @Tags Product Addon Groups
+// @Accept mpfd
+// @Produce json
+// @Param name 			formData string 	true  "name"
+// @Param description 	formData string 	false "description"
+// @Param min_select 	formData int 		false "minimum selection"
+// @Param max_select 	formData int 		false "maximum selection"
+// @Param addon_ids 	formData []int 		true  "addon ids in display order"
+// @Success 201 {object} utils.SuccessRespond{data=model.AddonGroup} "CREATED RESPOND"
+// @Failure 401 {object} utils.ErrorRespond "UNAUTHORIZED RESPOND"
+// @Failure 422 {object} utils.ValidationErrorRespond "UNPROCESSABLE ENTITY RESPOND"
+// @Failure 500 {object} utils.ErrorRespond "INTERNAL SERVER ERROR RESPOND"
+// @Router /api/v1/addon-groups [POST]
+func (handler addonGroupHandler) store(ctx *gin.Context) {
+	var form model.AddonGroup
+	if err := ctx.ShouldBind(&form); err != nil {
+		utils.NewHTTPRespond(ctx, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+
+	data, err := handler.svc.AddAddonGroup(ctx, &form)
+	if err != nil {
+		utils.NewHTTPRespond(ctx, err.Code, err.Message)
+		return
+	}
+
+	utils.NewHTTPRespond(ctx, http.StatusCreated, data)
+}
+
+// addon groups godoc
+// @Schemes
+// @Summary Update Addon Group Data
+// @Description Update addon group by ID, addon_ids replace the current items.
+// @Tags Product Addon Groups
+// @Accept mpfd
+// @Produce json
+// @Param id   			path     int  		true  "addon group id"
+// @Param name 			formData string 	true  "name"
+// @Param description 	formData string 	false "description"
+// @Param min_select 	formData int 		false "minimum selection"
+// @Param max_select 	formData int 		false "maximum selection"
+// @Param addon_ids 	formData []int 		true  "addon ids in display order"
+// @Success 200 {object} utils.SuccessRespond{data=model.AddonGroup} "OK RESPOND"
+// @Failure 400 {object} utils.ErrorRespond "BAD REQUEST RESPOND"
+// @Failure 401 {object} utils.ErrorRespond "UNAUTHORIZED RESPOND"
+// @Failure 404 {object} utils.ErrorRespond "NOT FOUND RESPOND"
+// @Failure 422 {object} utils.ValidationErrorRespond "UNPROCESSABLE ENTITY RESPOND"
+// @Failure 500 {object} utils.ErrorRespond "INTERNAL SERVER ERROR RESPOND"
+// @Router /api/v1/addon-groups/{id} [PUT]
+func (handler addonGroupHandler) update(ctx *gin.Context) {
+	idParams := ctx.Param("id")
+	id, errParse := strconv.Atoi(idParams)
+	if errParse != nil {
+		utils.NewHTTPRespond(ctx,
+			http.StatusBadRequest,
+			errParse.Error())
+		return
+	}
+
+	var form model.AddonGroup
+	if err := ctx.ShouldBind(&form); err != nil {
+		utils.NewHTTPRespond(ctx, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+
+	form.ID = id
+	data, err := handler.svc.EditAddonGroup(ctx, &form)
+	if err != nil {
+		utils.NewHTTPRespond(ctx, err.Code, err.Message)
+		return
+	}
+
+	utils.NewHTTPRespond(ctx, http.StatusOK, data)
+}
+
+// addon groups godoc
+// @Schemes
+// @Summary Delete Addon Group Data
+// @Description Delete addon group by ID, assignments are removed as well.
+// @Tags Product Addon Groups
+// @Accept json
+// @Produce json
+// @Param id path int true "addon group id"
+// @Success 204 "NO CONTENT RESPOND"
+// @Failure 400 {object} utils.ErrorRespond "BAD REQUEST RESPOND"
+// @Failure 401 {object} utils.ErrorRespond "UNAUTHORIZED RESPOND"
+// @Failure 404 {object} utils.ErrorRespond "NOT FOUND RESPOND"
+// @Failure 500 {object} utils.ErrorRespond "INTERNAL SERVER ERROR RESPOND"
+// @Router /api/v1/addon-groups/{id} [DELETE]
+func (handler addonGroupHandler) destroy(ctx *gin.Context) {
+	idParams := ctx.Param("id")
+	id, errParse := strconv.Atoi(idParams)
+	if errParse != nil {
+		utils.NewHTTPRespond(ctx,
+			http.StatusBadRequest,
+			errParse.Error())
+		return
+	}
+	data := model.AddonGroup{ID: id}
+
+	err := handler.svc.DeleteAddonGroup(ctx, &data)
+	if err != nil {
+		utils.NewHTTPRespond(ctx, err.Code, err.Message)
+		return
+	}
+
+	utils.NewHTTPRespond(ctx, http.StatusNoContent, nil)
+}
+
+// addon groups godoc
+// @Schemes
+// @Summary Assign Addon Group
+// @Description Assign addon group to a product or to every product of a category.
+// @Tags Product Addon Groups
+// @Accept mpfd
+// @Produce json
+// @Param id   			path     int  true  "addon group id"
+// @Param product_id 	formData int  false "product id"
+// @Param category_id 	formData int  false "category id"
+// @Success 204 "NO CONTENT RESPOND"
+// @Failure 400 {object} utils.ErrorRespond "BAD REQUEST RESPOND"
+// @Failure 401 {object} utils.ErrorRespond "UNAUTHORIZED RESPOND"
+// @Failure 404 {object} utils.ErrorRespond "NOT FOUND RESPOND"
+// @Failure 422 {object} utils.ValidationErrorRespond "UNPROCESSABLE ENTITY RESPOND"
+// @Failure 500 {object} utils.ErrorRespond "INTERNAL SERVER ERROR RESPOND"
+// @Router /api/v1/addon-groups/{id}/assignments [POST]
+func (handler addonGroupHandler) assign(ctx *gin.Context) {
+	handler.assignment(ctx, handler.svc.AssignAddonGroup)
+}
+
+// addon groups godoc
+// @Schemes
+// @Summary Unassign Addon Group
+// @Description Remove addon group from a product or a category.
+// @Tags Product Addon Groups
+// @Accept mpfd
+// @Produce json
+// @Param id   			path     int  true  "addon group id"
+// @Param product_id 	formData int  false "product id"
+// @Param category_id 	formData int  false "category id"
+// @Success 204 "NO CONTENT RESPOND"
+// @Failure 400 {object} utils.ErrorRespond "BAD REQUEST RESPOND"
+// @Failure 401 {object} utils.ErrorRespond "UNAUTHORIZED RESPOND"
+// @Failure 404 {object} utils.ErrorRespond "NOT FOUND RESPOND"
+// @Failure 422 {object} utils.ValidationErrorRespond "UNPROCESSABLE ENTITY RESPOND"
+// @Failure 500 {object} utils.ErrorRespond "INTERNAL SERVER ERROR RESPOND"
+// @Router /api/v1/addon-groups/{id}/assignments [DELETE]
+func (handler addonGroupHandler) unassign(ctx *gin.Context) {
+	handler.assignment(ctx, handler.svc.UnassignAddonGroup)
+}
+
+func (handler addonGroupHandler) assignment(
+	ctx *gin.Context,
+	action func(context.Context, *model.AddonGroupAssignment) *utils.ServiceError,
+) {
+	idParams := ctx.Param("id")
+	id, errParse := strconv.Atoi(idParams)
+	if errParse != nil {
+		utils.NewHTTPRespond(ctx,
+			http.StatusBadRequest,
+			errParse.Error())
+		return
+	}
+
+	var form model.AddonGroupAssignment
+	if err := ctx.ShouldBind(&form); err != nil {
+		utils.NewHTTPRespond(ctx, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+
+	form.AddonGroupID = id
+	if err := action(ctx, &form); err != nil {
+		utils.NewHTTPRespond(ctx, err.Code, err.Message)
+		return
+	}
+
+	utils.NewHTTPRespond(ctx, http.StatusNoContent, nil)
+}
+
+// products godoc
+// @Schemes
+// @Summary Product Addon Groups
+// @Description Get addon groups applied to the product (directly or by category),
+// @Description addon price already use the product override.
+// @Tags Product Addon Groups
+// @Accept json
+// @Produce json
+// @Param id path int true "product id"
+// @Success 200 {object} utils.SuccessRespond{data=[]model.AddonGroup} "OK RESPOND"
+// @Failure 400 {object} utils.ErrorRespond "BAD REQUEST RESPOND"
+// @Failure 401 {object} utils.ErrorRespond "UNAUTHORIZED RESPOND"
+// @Failure 500 {object} utils.ErrorRespond "INTERNAL SERVER ERROR RESPOND"
+// @Router /api/v1/products/{id}/addon-groups [GET]
+func (handler addonGroupHandler) productGroups(ctx *gin.Context) {
+	idParams := ctx.Param("id")
+	id, errParse := strconv.Atoi(idParams)
+	if errParse != nil {
+		utils.NewHTTPRespond(ctx,
+			http.StatusBadRequest,
+			errParse.Error())
+		return
+	}
+
+	data, err := handler.svc.ProductAddonGroups(ctx, id)
+	if err != nil {
+		utils.NewHTTPRespond(ctx, err.Code, err.Message)
+		return
+	}
+
+	utils.NewHTTPRespond(ctx, http.StatusOK, data)
+}
+
+// products godoc
+// @Schemes
+// @Summary Set Product Addon Price
+// @Description Override addon price for the product.
+// @Tags Product Addon Groups
+// @Accept mpfd
+// @Produce json
+// @Param id   		path     int  	true "product id"
+// @Param addon_id 	formData int  	true "addon id"
+// @Param price 	formData number true "price"
+// @Success 204 "NO CONTENT RESPOND"
+// @Failure 400 {object} utils.ErrorRespond "BAD REQUEST RESPOND"
+// @Failure 401 {object} utils.ErrorRespond "UNAUTHORIZED RESPOND"
+// @Failure 404 {object} utils.ErrorRespond "NOT FOUND RESPOND"
+// @Failure 422 {object} utils.ValidationErrorRespond "UNPROCESSABLE ENTITY RESPOND"
+// @Failure 500 {object} utils.ErrorRespond "INTERNAL SERVER ERROR RESPOND"
+// @Router /api/v1/products/{id}/addon-prices [PUT]
+func (handler addonGroupHandler) setPrice(ctx *gin.Context) {
+	idParams := ctx.Param("id")
+	id, errParse := strconv.Atoi(idParams)
+	if errParse != nil {
+		utils.NewHTTPRespond(ctx,
+			http.StatusBadRequest,
+			errParse.Error())
+		return
+	}
+
+	var form model.ProductAddonPrice
+	if err := ctx.ShouldBind(&form); err != nil {
+		utils.NewHTTPRespond(ctx, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+
+	form.ProductID = id
+	if err := handler.svc.SetProductAddonPrice(ctx, &form); err != nil {
+		utils.NewHTTPRespond(ctx, err.Code, err.Message)
+		return
+	}
+
+	utils.NewHTTPRespond(ctx, http.StatusNoContent, nil)
+}
+
+// products godoc
+// @Schemes
+// @Summary Delete Product Addon Price
+// @Description Remove addon price override, addon use the default price again.
+// @Tags Product Addon Groups
+// @Accept json
+// @Produce json
+// @Param id   		path int true "product id"
+// @Param addon_id 	path int true "addon id"
+// @Success 204 "NO CONTENT RESPOND"
+// @Failure 400 {object} utils.ErrorRespond "BAD REQUEST RESPOND"
+// @Failure 401 {object} utils.ErrorRespond "UNAUTHORIZED RESPOND"
+// @Failure 500 {object} utils.ErrorRespond "INTERNAL SERVER ERROR RESPOND"
+// @Router /api/v1/products/{id}/addon-prices/{addon_id} [DELETE]
+func (handler addonGroupHandler) destroyPrice(ctx *gin.Context) {
+	id, errParse := strconv.Atoi(ctx.Param("id"))
+	if errParse != nil {
+		utils.NewHTTPRespond(ctx,
+			http.StatusBadRequest,
+			errParse.Error())
+		return
+	}
+	addonID, errParse := strconv.Atoi(ctx.Param("addon_id"))
+	if errParse != nil {
+		utils.NewHTTPRespond(ctx,
+			http.StatusBadRequest,
+			errParse.Error())
+		return
+	}
+	data := model.ProductAddonPrice{ProductID: id, AddonID: addonID}
+
+	if err := handler.svc.DeleteProductAddonPrice(ctx, &data); err != nil {
+		utils.NewHTTPRespond(ctx, err.Code, err.Message)
+		return
+	}
+
+	utils.NewHTTPRespond(ctx, http.StatusNoContent, nil)
+}
+
+// products godoc
+// @Schemes
+// @Summary Validate Addon Selection
+// @Description Check selected addons against the product group rules,
+// @Description return priced lines or the list of violated rules.
+// @Tags Product Addon Groups
+// @Accept json
+// @Produce json
+// @Param id   path int 					 true "product id"
+// @Param body body model.AddonSelectionForm true "selected addons"
+// @Success 200 {object} utils.SuccessRespond{data=model.AddonSelectionResult} "OK RESPOND"
+// @Failure 400 {object} utils.ErrorRespond "BAD REQUEST RESPOND"
+// @Failure 401 {object} utils.ErrorRespond "UNAUTHORIZED RESPOND"
+// @Failure 422 {object} utils.ValidationErrorRespond{data=[]model.AddonSelectionError} "UNPROCESSABLE ENTITY RESPOND"
+// @Failure 500 {object} utils.ErrorRespond "INTERNAL SERVER ERROR RESPOND"
+// @Router /api/v1/products/{id}/addon-groups/validate [POST]
+func (handler addonGroupHandler) validate(ctx *gin.Context) {
+	idParams := ctx.Param("id")
+	id, errParse := strconv.Atoi(idParams)
+	if errParse != nil {
+		utils.NewHTTPRespond(ctx,
+			http.StatusBadRequest,
+			errParse.Error())
+		return
+	}
+
+	var form model.AddonSelectionForm
+	if err := ctx.ShouldBindJSON(&form); err != nil {
+		utils.NewHTTPRespond(ctx, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+
+	data, err := handler.svc.ValidateAddonSelection(ctx, id, form.Addons)
+	if err != nil {
+		utils.NewHTTPRespond(ctx, err.Code, err.Message)
+		return
+	}
+
+	utils.NewHTTPRespond(ctx, http.StatusOK, data)
+}
+
+func NewAddonGroupHandler(svc model.ICatalogModifierService, router gin.IRoutes) {
+	handler := addonGroupHandler{svc: svc}
+	read := middleware.Permitted(model.PermissionCatalogRead)
+	write := middleware.Permitted(model.PermissionCatalogProductWrite)
+	priceWrite := middleware.Permitted(model.PermissionCatalogPriceWrite)
+	router.GET("/addon-groups", read, handler.fetch)
+	router.POST("/addon-groups", write, handler.store)
+	router.PUT("/addon-groups/:id", write, handler.update)
+	router.DELETE("/addon-groups/:id", write, handler.destroy)
+	router.POST("/addon-groups/:id/assignments", write, handler.assign)
+	router.DELETE("/addon-groups/:id/assignments", write, handler.unassign)
+	router.GET("/products/:id/addon-groups", read, handler.productGroups)
+	router.POST("/products/:id/addon-groups/validate", read, middleware.SkipAudit(), handler.validate)
+	router.PUT("/products/:id/addon-prices", priceWrite, handler.setPrice)
+	router.DELETE("/products/:id/addon-prices/:addon_id", priceWrite, handler.destroyPrice)
+}
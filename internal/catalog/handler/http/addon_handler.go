@@ -4,6 +4,7 @@ import (
 	"net/http"
 	"strconv"
 
+	"github.com/aasumitro/posbe/pkg/http/middleware"
 	"github.com/aasumitro/posbe/pkg/model"
 	"github.com/aasumitro/posbe/pkg/utils"
 	"github.com/gin-gonic/gin"
@@ -115,9 +116,13 @@ func (handler addonHandler) update(ctx *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param id path int true "category id"
+// @Param reassign_to query int false "addon id that take over the addon groups and prices"
 // @Success 204 "NO CONTENT RESPOND"
 // @Failure 400 {object} utils.ErrorRespond "BAD REQUEST RESPOND"
 // @Failure 401 {object} utils.ErrorRespond "UNAUTHORIZED RESPOND"
+// @Failure 404 {object} utils.ErrorRespond "NOT FOUND RESPOND"
+// @Failure 409 {object} utils.ValidationErrorRespond{data=model.DeleteConflict} "CONFLICT RESPOND"
+// @Failure 422 {object} utils.ValidationErrorRespond "UNPROCESSABLE ENTITY RESPOND"
 // @Failure 500 {object} utils.ErrorRespond "INTERNAL SERVER ERROR RESPOND"
 // @Router /api/v1/addons/{id} [DELETE]
 func (handler addonHandler) destroy(ctx *gin.Context) {
@@ -140,10 +145,46 @@ func (handler addonHandler) destroy(ctx *gin.Context) {
 	utils.NewHTTPRespond(ctx, http.StatusNoContent, nil)
 }
 
+// addons godoc
+// @Schemes
+// @Summary Restore Addon Data
+// @Description Restore deleted addon by ID.
+// @Tags Product Addons
+// @Accept json
+// @Produce json
+// @Param id path int true "addon id"
+// @Success 200 {object} utils.SuccessRespond{data=model.Addon} "OK RESPOND"
+// @Failure 400 {object} utils.ErrorRespond "BAD REQUEST RESPOND"
+// @Failure 401 {object} utils.ErrorRespond "UNAUTHORIZED RESPOND"
+// @Failure 403 {object} utils.ErrorRespond "FORBIDDEN RESPOND"
+// @Failure 404 {object} utils.ErrorRespond "NOT FOUND RESPOND"
+// @Failure 500 {object} utils.ErrorRespond "INTERNAL SERVER ERROR RESPOND"
+// @Router /api/v1/addons/{id}/restore [PATCH]
+func (handler addonHandler) restore(ctx *gin.Context) {
+	idParams := ctx.Param("id")
+	id, errParse := strconv.Atoi(idParams)
+	if errParse != nil {
+		utils.NewHTTPRespond(ctx,
+			http.StatusBadRequest,
+			errParse.Error())
+		return
+	}
+	data, err := handler.svc.RestoreAddon(ctx, &model.Addon{ID: id})
+	if err != nil {
+		utils.NewHTTPRespond(ctx, err.Code, err.Message)
+		return
+	}
+	utils.NewHTTPRespond(ctx, http.StatusOK, data)
+}
+
 func NewAddonHandler(svc model.ICatalogCommonService, router gin.IRoutes) {
 	handler := addonHandler{svc: svc}
-	router.GET("/addons", handler.fetch)
-	router.POST("/addons", handler.store)
-	router.PUT("/addons/:id", handler.update)
-	router.DELETE("/addons/:id", handler.destroy)
+	read := middleware.Permitted(model.PermissionCatalogRead)
+	write := middleware.Permitted(model.PermissionCatalogProductWrite)
+	trash := middleware.Permitted(model.PermissionTrashRestore)
+	router.GET("/addons", read, handler.fetch)
+	router.POST("/addons", write, handler.store)
+	router.PUT("/addons/:id", write, handler.update)
+	router.DELETE("/addons/:id", write, handler.destroy)
+	router.PATCH("/addons/:id/restore", trash, handler.restore)
 }
@@ -0,0 +1,268 @@
+package http
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/aasumitro/posbe/common"
+	"github.com/aasumitro/posbe/pkg/http/middleware"
+	"github.com/aasumitro/posbe/pkg/model"
+	"github.com/aasumitro/posbe/pkg/utils"
+	"github.com/gin-gonic/gin"
+)
+
+type availabilityHandler struct {
+	svc model.ICatalogAvailabilityService
+}
+
+// availability godoc
+// @Schemes
+// @Summary Availability Rules
+// @Description Get selling windows of products and categories.
+// @Tags Product Availability
+// @Accept json
+// @Produce json
+// @Success 200 {object} utils.SuccessRespond{data=[]model.AvailabilityRule} "OK RESPOND"
+// @Failure 401 {object} utils.ErrorRespond "UNAUTHORIZED RESPOND"
+// @Failure 500 {object} utils.ErrorRespond "INTERNAL SERVER ERROR RESPOND"
+// @Router /api/v1/availability-rules [GET]
+func (handler availabilityHandler) rules(ctx *gin.Context) {
+	data, err := handler.svc.AvailabilityRuleList(ctx)
+	if err != nil {
+		utils.NewHTTPRespond(ctx, err.Code, err.Message)
+		return
+	}
+	utils.NewHTTPRespond(ctx, http.StatusOK, data)
+}
+
+// availability godoc
+// @Schemes
+// @Summary Save Availability Rules
+// @Description Replace selling windows of a product or a category,
+// @Description product rules replace the category rules, empty rules mean always available.
+// @Tags Product Availability
+// @Accept json
+// @Produce json
+// @Param body body model.AvailabilityRuleForm true "target and rules"
+// @Success 204 "NO CONTENT RESPOND"
+// @Failure 401 {object} utils.ErrorRespond "UNAUTHORIZED RESPOND"
+// @Failure 422 {object} utils.ValidationErrorRespond "UNPROCESSABLE ENTITY RESPOND"
+// @Failure 500 {object} utils.ErrorRespond "INTERNAL SERVER ERROR RESPOND"
+// @Router /api/v1/availability-rules [PUT]
+func (handler availabilityHandler) saveRules(ctx *gin.Context) {
+	var form model.AvailabilityRuleForm
+	if err := ctx.ShouldBindJSON(&form); err != nil {
+		utils.NewHTTPRespond(ctx, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+
+	if err := handler.svc.SaveAvailabilityRules(ctx, &form); err != nil {
+		utils.NewHTTPRespond(ctx, err.Code, err.Message)
+		return
+	}
+
+	utils.NewHTTPRespond(ctx, http.StatusNoContent, nil)
+}
+
+// availability godoc
+// @Schemes
+// @Summary Availability Board
+// @Description Get current availability of every product for the terminals.
+// @Tags Product Availability
+// @Accept json
+// @Produce json
+// @Success 200 {object} utils.SuccessRespond{data=[]model.ProductAvailability} "OK RESPOND"
+// @Failure 401 {object} utils.ErrorRespond "UNAUTHORIZED RESPOND"
+// @Failure 500 {object} utils.ErrorRespond "INTERNAL SERVER ERROR RESPOND"
+// @Router /api/v1/availability [GET]
+func (handler availabilityHandler) board(ctx *gin.Context) {
+	data, err := handler.svc.AvailabilityBoard(ctx)
+	if err != nil {
+		utils.NewHTTPRespond(ctx, err.Code, err.Message)
+		return
+	}
+	utils.NewHTTPRespond(ctx, http.StatusOK, data)
+}
+
+// availability godoc
+// @Schemes
+// @Summary Check Availability
+// @Description Validate order entry items against the schedule and 86 list.
+// @Tags Product Availability
+// @Accept json
+// @Produce json
+// @Param body body model.AvailabilityCheckForm true "order items"
+// @Success 204 "NO CONTENT RESPOND"
+// @Failure 401 {object} utils.ErrorRespond "UNAUTHORIZED RESPOND"
+// @Failure 422 {object} utils.ValidationErrorRespond{data=[]model.AvailabilityError} "UNPROCESSABLE ENTITY RESPOND"
+// @Failure 500 {object} utils.ErrorRespond "INTERNAL SERVER ERROR RESPOND"
+// @Router /api/v1/availability/check [POST]
+func (handler availabilityHandler) check(ctx *gin.Context) {
+	var form model.AvailabilityCheckForm
+	if err := ctx.ShouldBindJSON(&form); err != nil {
+		utils.NewHTTPRespond(ctx, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+
+	if err := handler.svc.CheckAvailability(ctx, &form); err != nil {
+		utils.NewHTTPRespond(ctx, err.Code, err.Message)
+		return
+	}
+
+	utils.NewHTTPRespond(ctx, http.StatusNoContent, nil)
+}
+
+// availability godoc
+// @Schemes
+// @Summary Availability Events
+// @Description Stream availability changes as server-sent events,
+// @Description "availability" event carry model.AvailabilityEvent, "ping" keep the connection alive.
+// @Tags Product Availability
+// @Produce text/event-stream
+// @Success 200 {object} model.AvailabilityEvent "EVENT STREAM"
+// @Failure 401 {object} utils.ErrorRespond "UNAUTHORIZED RESPOND"
+// @Router /api/v1/availability/events [GET]
+func (handler availabilityHandler) events(ctx *gin.Context) {
+	events, closeFn := handler.svc.Subscribe(ctx.Request.Context())
+	defer func() { _ = closeFn() }()
+	ticker := time.NewTicker(common.EventStreamKeepAlive * time.Second)
+	defer ticker.Stop()
+
+	ctx.Header("Cache-Control", "no-cache")
+	ctx.Header("X-Accel-Buffering", "no")
+	ctx.Stream(func(_ io.Writer) bool {
+		select {
+		case event, ok := <-events:
+			if !ok {
+				return false
+			}
+			ctx.SSEvent("availability", json.RawMessage(event))
+			return true
+		case <-ticker.C:
+			ctx.SSEvent("ping", time.Now().Unix())
+			return true
+		case <-ctx.Request.Context().Done():
+			return false
+		}
+	})
+}
+
+// availability godoc
+// @Schemes
+// @Summary Sold Out Items
+// @Description Get 86'd products and variants that are still in effect.
+// @Tags Product Availability
+// @Accept json
+// @Produce json
+// @Success 200 {object} utils.SuccessRespond{data=[]model.SoldOutItem} "OK RESPOND"
+// @Failure 401 {object} utils.ErrorRespond "UNAUTHORIZED RESPOND"
+// @Failure 500 {object} utils.ErrorRespond "INTERNAL SERVER ERROR RESPOND"
+// @Router /api/v1/sold-out [GET]
+func (handler availabilityHandler) soldOut(ctx *gin.Context) {
+	data, err := handler.svc.SoldOutList(ctx)
+	if err != nil {
+		utils.NewHTTPRespond(ctx, err.Code, err.Message)
+		return
+	}
+	utils.NewHTTPRespond(ctx, http.StatusOK, data)
+}
+
+// products godoc
+// @Schemes
+// @Summary 86 Product
+// @Description Mark product or one of its variants sold out for the rest of the open shift.
+// @Tags Product Availability
+// @Accept json
+// @Produce json
+// @Param id   		 path  int true  "product id"
+// @Param variant_id query int false "variant id"
+// @Success 201 {object} utils.SuccessRespond{data=model.SoldOutItem} "CREATED RESPOND"
+// @Failure 400 {object} utils.ErrorRespond "BAD REQUEST RESPOND"
+// @Failure 401 {object} utils.ErrorRespond "UNAUTHORIZED RESPOND"
+// @Failure 404 {object} utils.ErrorRespond "NOT FOUND RESPOND"
+// @Failure 500 {object} utils.ErrorRespond "INTERNAL SERVER ERROR RESPOND"
+// @Router /api/v1/products/{id}/sold-out [POST]
+func (handler availabilityHandler) markSoldOut(ctx *gin.Context) {
+	item, ok := soldOutItem(ctx)
+	if !ok {
+		return
+	}
+	item.CreatedBy = middleware.PayloadUserID(ctx)
+
+	data, err := handler.svc.MarkSoldOut(ctx, item)
+	if err != nil {
+		utils.NewHTTPRespond(ctx, err.Code, err.Message)
+		return
+	}
+
+	utils.NewHTTPRespond(ctx, http.StatusCreated, data)
+}
+
+// products godoc
+// @Schemes
+// @Summary Restock Product
+// @Description Remove product or variant from the 86 list.
+// @Tags Product Availability
+// @Accept json
+// @Produce json
+// @Param id   		 path  int true  "product id"
+// @Param variant_id query int false "variant id"
+// @Success 204 "NO CONTENT RESPOND"
+// @Failure 400 {object} utils.ErrorRespond "BAD REQUEST RESPOND"
+// @Failure 401 {object} utils.ErrorRespond "UNAUTHORIZED RESPOND"
+// @Failure 404 {object} utils.ErrorRespond "NOT FOUND RESPOND"
+// @Failure 500 {object} utils.ErrorRespond "INTERNAL SERVER ERROR RESPOND"
+// @Router /api/v1/products/{id}/sold-out [DELETE]
+func (handler availabilityHandler) clearSoldOut(ctx *gin.Context) {
+	item, ok := soldOutItem(ctx)
+	if !ok {
+		return
+	}
+
+	if err := handler.svc.ClearSoldOut(ctx, item); err != nil {
+		utils.NewHTTPRespond(ctx, err.Code, err.Message)
+		return
+	}
+
+	utils.NewHTTPRespond(ctx, http.StatusNoContent, nil)
+}
+
+func soldOutItem(ctx *gin.Context) (*model.SoldOutItem, bool) {
+	id, errParse := strconv.Atoi(ctx.Param("id"))
+	if errParse != nil {
+		utils.NewHTTPRespond(ctx,
+			http.StatusBadRequest,
+			errParse.Error())
+		return nil, false
+	}
+	item := &model.SoldOutItem{ProductID: id}
+	if variantParams := ctx.Query("variant_id"); variantParams != "" {
+		variantID, errParse := strconv.Atoi(variantParams)
+		if errParse != nil {
+			utils.NewHTTPRespond(ctx,
+				http.StatusBadRequest,
+				errParse.Error())
+			return nil, false
+		}
+		item.VariantID = variantID
+	}
+	return item, true
+}
+
+func NewAvailabilityHandler(svc model.ICatalogAvailabilityService, router gin.IRoutes) {
+	handler := availabilityHandler{svc: svc}
+	read := middleware.Permitted(model.PermissionCatalogRead)
+	write := middleware.Permitted(model.PermissionCatalogProductWrite)
+	availabilityWrite := middleware.Permitted(model.PermissionCatalogAvailabilityWrite)
+	router.GET("/availability-rules", read, handler.rules)
+	router.PUT("/availability-rules", write, handler.saveRules)
+	router.GET("/availability", read, handler.board)
+	router.POST("/availability/check", read, middleware.SkipAudit(), handler.check)
+	router.GET("/availability/events", read, handler.events)
+	router.GET("/sold-out", read, handler.soldOut)
+	router.POST("/products/:id/sold-out", availabilityWrite, handler.markSoldOut)
+	router.DELETE("/products/:id/sold-out", availabilityWrite, handler.clearSoldOut)
+}
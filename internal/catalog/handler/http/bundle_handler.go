@@ -0,0 +1,175 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/aasumitro/posbe/pkg/http/middleware"
+	"github.com/aasumitro/posbe/pkg/model"
+	"github.com/aasumitro/posbe/pkg/utils"
+	"github.com/gin-gonic/gin"
+)
+
+type bundleHandler struct {
+	svc model.ICatalogBundleService
+}
+
+// bundles godoc
+// @Schemes
+// @Summary Bundle Detail
+// @Description Get fixed items and choice slots of bundle product.
+// @Tags Product Bundles
+// @Accept json
+// @Produce json
+// @Param id path int true "bundle product id"
+// @Success 200 {object} utils.SuccessRespond{data=model.Bundle} "OK RESPOND"
+// @Failure 400 {object} utils.ErrorRespond "BAD REQUEST RESPOND"
+// @Failure 401 {object} utils.ErrorRespond "UNAUTHORIZED RESPOND"
+// @Failure 404 {object} utils.ErrorRespond "NOT FOUND RESPOND"
+// @Failure 500 {object} utils.ErrorRespond "INTERNAL SERVER ERROR RESPOND"
+// @Router /api/v1/products/{id}/bundle [GET]
+func (handler bundleHandler) detail(ctx *gin.Context) {
+	idParams := ctx.Param("id")
+	id, errParse := strconv.Atoi(idParams)
+	if errParse != nil {
+		utils.NewHTTPRespond(ctx,
+			http.StatusBadRequest,
+			errParse.Error())
+		return
+	}
+
+	data, err := handler.svc.BundleDetail(ctx, id)
+	if err != nil {
+		utils.NewHTTPRespond(ctx, err.Code, err.Message)
+		return
+	}
+
+	utils.NewHTTPRespond(ctx, http.StatusOK, data)
+}
+
+// bundles godoc
+// @Schemes
+// @Summary Save Bundle
+// @Description Turn the product into bundle or replace its items and slots,
+// @Description the bundle is sold at the product price.
+// @Tags Product Bundles
+// @Accept json
+// @Produce json
+// @Param id   path int 		 true "bundle product id"
+// @Param body body model.Bundle true "bundle items and slots"
+// @Success 200 {object} utils.SuccessRespond{data=model.Bundle} "OK RESPOND"
+// @Failure 400 {object} utils.ErrorRespond "BAD REQUEST RESPOND"
+// @Failure 401 {object} utils.ErrorRespond "UNAUTHORIZED RESPOND"
+// @Failure 404 {object} utils.ErrorRespond "NOT FOUND RESPOND"
+// @Failure 422 {object} utils.ValidationErrorRespond "UNPROCESSABLE ENTITY RESPOND"
+// @Failure 500 {object} utils.ErrorRespond "INTERNAL SERVER ERROR RESPOND"
+// @Router /api/v1/products/{id}/bundle [PUT]
+func (handler bundleHandler) save(ctx *gin.Context) {
+	idParams := ctx.Param("id")
+	id, errParse := strconv.Atoi(idParams)
+	if errParse != nil {
+		utils.NewHTTPRespond(ctx,
+			http.StatusBadRequest,
+			errParse.Error())
+		return
+	}
+
+	var form model.Bundle
+	if err := ctx.ShouldBindJSON(&form); err != nil {
+		utils.NewHTTPRespond(ctx, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+
+	form.ProductID = id
+	data, err := handler.svc.SaveBundle(ctx, &form)
+	if err != nil {
+		utils.NewHTTPRespond(ctx, err.Code, err.Message)
+		return
+	}
+
+	utils.NewHTTPRespond(ctx, http.StatusOK, data)
+}
+
+// bundles godoc
+// @Schemes
+// @Summary Delete Bundle
+// @Description Remove items and slots, the product is sold as a single item again.
+// @Tags Product Bundles
+// @Accept json
+// @Produce json
+// @Param id path int true "bundle product id"
+// @Success 204 "NO CONTENT RESPOND"
+// @Failure 400 {object} utils.ErrorRespond "BAD REQUEST RESPOND"
+// @Failure 401 {object} utils.ErrorRespond "UNAUTHORIZED RESPOND"
+// @Failure 404 {object} utils.ErrorRespond "NOT FOUND RESPOND"
+// @Failure 500 {object} utils.ErrorRespond "INTERNAL SERVER ERROR RESPOND"
+// @Router /api/v1/products/{id}/bundle [DELETE]
+func (handler bundleHandler) destroy(ctx *gin.Context) {
+	idParams := ctx.Param("id")
+	id, errParse := strconv.Atoi(idParams)
+	if errParse != nil {
+		utils.NewHTTPRespond(ctx,
+			http.StatusBadRequest,
+			errParse.Error())
+		return
+	}
+
+	if err := handler.svc.DeleteBundle(ctx, id); err != nil {
+		utils.NewHTTPRespond(ctx, err.Code, err.Message)
+		return
+	}
+
+	utils.NewHTTPRespond(ctx, http.StatusNoContent, nil)
+}
+
+// bundles godoc
+// @Schemes
+// @Summary Expand Bundle
+// @Description Validate slot choices and break the bundle down into components
+// @Description (kitchen routing, stock depletion) with the allocated revenue.
+// @Tags Product Bundles
+// @Accept json
+// @Produce json
+// @Param id   path int 					true "bundle product id"
+// @Param body body model.BundleExpandForm 	true "bundle qty and slot choices"
+// @Success 200 {object} utils.SuccessRespond{data=model.BundleExpansion} "OK RESPOND"
+// @Failure 400 {object} utils.ErrorRespond "BAD REQUEST RESPOND"
+// @Failure 401 {object} utils.ErrorRespond "UNAUTHORIZED RESPOND"
+// @Failure 404 {object} utils.ErrorRespond "NOT FOUND RESPOND"
+// @Failure 422 {object} utils.ValidationErrorRespond{data=[]model.BundleChoiceError} "UNPROCESSABLE ENTITY RESPOND"
+// @Failure 500 {object} utils.ErrorRespond "INTERNAL SERVER ERROR RESPOND"
+// @Router /api/v1/products/{id}/bundle/expand [POST]
+func (handler bundleHandler) expand(ctx *gin.Context) {
+	idParams := ctx.Param("id")
+	id, errParse := strconv.Atoi(idParams)
+	if errParse != nil {
+		utils.NewHTTPRespond(ctx,
+			http.StatusBadRequest,
+			errParse.Error())
+		return
+	}
+
+	var form model.BundleExpandForm
+	if err := ctx.ShouldBindJSON(&form); err != nil {
+		utils.NewHTTPRespond(ctx, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+
+	data, err := handler.svc.ExpandBundle(ctx, id, &form)
+	if err != nil {
+		utils.NewHTTPRespond(ctx, err.Code, err.Message)
+		return
+	}
+
+	utils.NewHTTPRespond(ctx, http.StatusOK, data)
+}
+
+func NewBundleHandler(svc model.ICatalogBundleService, router gin.IRoutes) {
+	handler := bundleHandler{svc: svc}
+	read := middleware.Permitted(model.PermissionCatalogRead)
+	write := middleware.Permitted(model.PermissionCatalogProductWrite)
+	router.GET("/products/:id/bundle", read, handler.detail)
+	router.PUT("/products/:id/bundle", write, handler.save)
+	router.DELETE("/products/:id/bundle", write, handler.destroy)
+	router.POST("/products/:id/bundle/expand", read, middleware.SkipAudit(), handler.expand)
+}
@@ -0,0 +1,75 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/aasumitro/posbe/pkg/http/middleware"
+	"github.com/aasumitro/posbe/pkg/model"
+	"github.com/aasumitro/posbe/pkg/utils"
+	"github.com/gin-gonic/gin"
+)
+
+const maxImportFileSize = 10 << 20
+
+type catalogImportHandler struct {
+	svc model.ICatalogImportService
+}
+
+// imports godoc
+// @Schemes
+// @Summary Import Catalog Data
+// @Description Bulk import categories, subcategories, units, addons, products and variants.
+// @Description XLSX file read every sheet named after the entity, CSV file need the entity param.
+// @Description Products are matched by sku, variants by product sku and name, and nothing is stored when a row fails.
+// @Tags Catalog Imports
+// @Accept mpfd
+// @Produce json
+// @Param file 		formData file 	true 	"csv or xlsx file"
+// @Param entity 	formData string false 	"entity of csv file" Enums(categories, subcategories, units, addons, products, variants)
+// @Param dry_run 	formData bool 	false 	"validate only, nothing stored"
+// @Success 200 {object} utils.SuccessRespond{data=model.CatalogImportReport} "OK RESPOND"
+// @Failure 400 {object} utils.ErrorRespond "BAD REQUEST RESPOND"
+// @Failure 401 {object} utils.ErrorRespond "UNAUTHORIZED RESPOND"
+// @Failure 422 {object} utils.ValidationErrorRespond{data=model.CatalogImportReport} "UNPROCESSABLE ENTITY RESPOND"
+// @Failure 500 {object} utils.ErrorRespond "INTERNAL SERVER ERROR RESPOND"
+// @Router /api/v1/catalog/imports [POST]
+func (handler catalogImportHandler) store(ctx *gin.Context) {
+	ctx.Request.Body = http.MaxBytesReader(ctx.Writer, ctx.Request.Body, maxImportFileSize)
+	var form model.CatalogImportForm
+	if err := ctx.ShouldBind(&form); err != nil {
+		utils.NewHTTPRespond(ctx, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+
+	header, errFile := ctx.FormFile("file")
+	if errFile != nil {
+		utils.NewHTTPRespond(ctx, http.StatusBadRequest, errFile.Error())
+		return
+	}
+	file, errFile := header.Open()
+	if errFile != nil {
+		utils.NewHTTPRespond(ctx, http.StatusBadRequest, errFile.Error())
+		return
+	}
+	defer func() { _ = file.Close() }()
+
+	sheets, errFile := utils.ReadImportSheets(file, header.Filename, form.Entity)
+	if errFile != nil {
+		utils.NewHTTPRespond(ctx, http.StatusBadRequest, errFile.Error())
+		return
+	}
+
+	data, err := handler.svc.Import(ctx, sheets, form.DryRun)
+	if err != nil {
+		utils.NewHTTPRespond(ctx, err.Code, err.Message)
+		return
+	}
+
+	utils.NewHTTPRespond(ctx, http.StatusOK, data)
+}
+
+func NewCatalogImportHandler(svc model.ICatalogImportService, router gin.IRoutes) {
+	handler := catalogImportHandler{svc: svc}
+	write := middleware.Permitted(model.PermissionCatalogProductWrite)
+	router.POST("/catalog/imports", write, handler.store)
+}
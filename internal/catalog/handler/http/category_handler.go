@@ -4,6 +4,7 @@ import (
 	"net/http"
 	"strconv"
 
+	"github.com/aasumitro/posbe/pkg/http/middleware"
 	"github.com/aasumitro/posbe/pkg/model"
 	"github.com/aasumitro/posbe/pkg/utils"
 	"github.com/gin-gonic/gin"
@@ -112,9 +113,13 @@ func (handler categoryHandler) update(ctx *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param id path int true "category id"
+// @Param reassign_to query int false "category id that take over the subcategories and products"
 // @Success 204 "NO CONTENT RESPOND"
 // @Failure 400 {object} utils.ErrorRespond "BAD REQUEST RESPOND"
 // @Failure 401 {object} utils.ErrorRespond "UNAUTHORIZED RESPOND"
+// @Failure 404 {object} utils.ErrorRespond "NOT FOUND RESPOND"
+// @Failure 409 {object} utils.ValidationErrorRespond{data=model.DeleteConflict} "CONFLICT RESPOND"
+// @Failure 422 {object} utils.ValidationErrorRespond "UNPROCESSABLE ENTITY RESPOND"
 // @Failure 500 {object} utils.ErrorRespond "INTERNAL SERVER ERROR RESPOND"
 // @Router /api/v1/categories/{id} [DELETE]
 func (handler categoryHandler) destroy(ctx *gin.Context) {
@@ -135,10 +140,46 @@ func (handler categoryHandler) destroy(ctx *gin.Context) {
 	utils.NewHTTPRespond(ctx, http.StatusNoContent, nil)
 }
 
+// categories godoc
+// @Schemes
+// @Summary Restore Category Data
+// @Description Restore deleted category by ID.
+// @Tags Product Categories
+// @Accept json
+// @Produce json
+// @Param id path int true "category id"
+// @Success 200 {object} utils.SuccessRespond{data=model.Category} "OK RESPOND"
+// @Failure 400 {object} utils.ErrorRespond "BAD REQUEST RESPOND"
+// @Failure 401 {object} utils.ErrorRespond "UNAUTHORIZED RESPOND"
+// @Failure 403 {object} utils.ErrorRespond "FORBIDDEN RESPOND"
+// @Failure 404 {object} utils.ErrorRespond "NOT FOUND RESPOND"
+// @Failure 500 {object} utils.ErrorRespond "INTERNAL SERVER ERROR RESPOND"
+// @Router /api/v1/categories/{id}/restore [PATCH]
+func (handler categoryHandler) restore(ctx *gin.Context) {
+	idParams := ctx.Param("id")
+	id, errParse := strconv.Atoi(idParams)
+	if errParse != nil {
+		utils.NewHTTPRespond(ctx,
+			http.StatusBadRequest,
+			errParse.Error())
+		return
+	}
+	data, err := handler.svc.RestoreCategory(ctx, &model.Category{ID: id})
+	if err != nil {
+		utils.NewHTTPRespond(ctx, err.Code, err.Message)
+		return
+	}
+	utils.NewHTTPRespond(ctx, http.StatusOK, data)
+}
+
 func NewCategoryHandler(svc model.ICatalogCommonService, router gin.IRoutes) {
 	handler := categoryHandler{svc: svc}
-	router.GET("/categories", handler.fetch)
-	router.POST("/categories", handler.store)
-	router.PUT("/categories/:id", handler.update)
-	router.DELETE("/categories/:id", handler.destroy)
+	read := middleware.Permitted(model.PermissionCatalogRead)
+	write := middleware.Permitted(model.PermissionCatalogProductWrite)
+	trash := middleware.Permitted(model.PermissionTrashRestore)
+	router.GET("/categories", read, handler.fetch)
+	router.POST("/categories", write, handler.store)
+	router.PUT("/categories/:id", write, handler.update)
+	router.DELETE("/categories/:id", write, handler.destroy)
+	router.PATCH("/categories/:id/restore", trash, handler.restore)
 }
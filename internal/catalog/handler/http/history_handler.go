@@ -0,0 +1,76 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/aasumitro/posbe/pkg/http/middleware"
+	"github.com/aasumitro/posbe/pkg/model"
+	"github.com/aasumitro/posbe/pkg/utils"
+	"github.com/gin-gonic/gin"
+)
+
+type historyHandler struct {
+	svc model.ICatalogHistoryService
+}
+
+// history godoc
+// @Schemes
+// @Summary Entity History
+// @Description Get every version of the entity, oldest first, with who changed it and when.
+// @Description Changes compare the version with the previous one.
+// @Tags History
+// @Accept json
+// @Produce json
+// @Param entity_type path string true "entity type" Enums(products, product_variants, addons, store_prefs)
+// @Param id 		  path string true "entity id (key for store_prefs)"
+// @Success 200 {object} utils.SuccessRespond{data=[]model.EntityVersion} "OK RESPOND"
+// @Failure 401 {object} utils.ErrorRespond "UNAUTHORIZED RESPOND"
+// @Failure 404 {object} utils.ErrorRespond "NOT FOUND RESPOND"
+// @Failure 500 {object} utils.ErrorRespond "INTERNAL SERVER ERROR RESPOND"
+// @Router /api/v1/history/{entity_type}/{id} [GET]
+func (handler historyHandler) fetch(ctx *gin.Context) {
+	data, err := handler.svc.History(ctx, ctx.Param("entity_type"), ctx.Param("id"))
+	if err != nil {
+		utils.NewHTTPRespond(ctx, err.Code, err.Message)
+		return
+	}
+	utils.NewHTTPRespond(ctx, http.StatusOK, data)
+}
+
+// history godoc
+// @Schemes
+// @Summary Entity Snapshot
+// @Description Get the entities as they were at the given time, deleted entities are left out.
+// @Description Used to reconcile past receipts and reports with the prices in force.
+// @Tags History
+// @Accept json
+// @Produce json
+// @Param entity_type path 	string true  "entity type" Enums(products, product_variants, addons, store_prefs)
+// @Param at 		  query int    true  "point in time (unix time)"
+// @Param ids 		  query string false "comma separated entity ids, every entity when empty"
+// @Success 200 {object} utils.SuccessRespond{data=[]model.EntityVersion} "OK RESPOND"
+// @Failure 401 {object} utils.ErrorRespond "UNAUTHORIZED RESPOND"
+// @Failure 404 {object} utils.ErrorRespond "NOT FOUND RESPOND"
+// @Failure 422 {object} utils.ValidationErrorRespond "UNPROCESSABLE ENTITY RESPOND"
+// @Failure 500 {object} utils.ErrorRespond "INTERNAL SERVER ERROR RESPOND"
+// @Router /api/v1/history/{entity_type} [GET]
+func (handler historyHandler) snapshot(ctx *gin.Context) {
+	var query model.EntityVersionQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		utils.NewHTTPRespond(ctx, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+	data, err := handler.svc.Snapshot(ctx, ctx.Param("entity_type"), &query)
+	if err != nil {
+		utils.NewHTTPRespond(ctx, err.Code, err.Message)
+		return
+	}
+	utils.NewHTTPRespond(ctx, http.StatusOK, data)
+}
+
+func NewHistoryHandler(svc model.ICatalogHistoryService, router gin.IRoutes) {
+	handler := historyHandler{svc: svc}
+	read := middleware.Permitted(model.PermissionCatalogRead)
+	router.GET("/history/:entity_type", read, handler.snapshot)
+	router.GET("/history/:entity_type/:id", read, handler.fetch)
+}
@@ -0,0 +1,181 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/aasumitro/posbe/pkg/http/middleware"
+	"github.com/aasumitro/posbe/pkg/model"
+	"github.com/aasumitro/posbe/pkg/utils"
+	"github.com/gin-gonic/gin"
+)
+
+type priceListHandler struct {
+	svc model.ICatalogPriceService
+}
+
+// price lists godoc
+// @Schemes
+// @Summary Price Lists
+// @Description Get Price Lists with their items.
+// @Tags Price Lists
+// @Accept json
+// @Produce json
+// @Success 200 {object} utils.SuccessRespond{data=[]model.PriceList} "OK RESPOND"
+// @Failure 401 {object} utils.ErrorRespond "UNAUTHORIZED RESPOND"
+// @Failure 500 {object} utils.ErrorRespond "INTERNAL SERVER ERROR RESPOND"
+// @Router /api/v1/price-lists [GET]
+func (handler priceListHandler) fetch(ctx *gin.Context) {
+	data, err := handler.svc.PriceListList(ctx)
+	if err != nil {
+		utils.NewHTTPRespond(ctx, err.Code, err.Message)
+		return
+	}
+	utils.NewHTTPRespond(ctx, http.StatusOK, data)
+}
+
+// price lists godoc
+// @Schemes
+// @Summary Store Price List Data
+// @Description Create new price list, empty condition match every order.
+// @Tags Price Lists
+// @Accept json
+// @Produce json
+// @Param body body model.PriceList true "price list and its items"
+// @Success 201 {object} utils.SuccessRespond{data=model.PriceList} "CREATED RESPOND"
+// @Failure 401 {object} utils.ErrorRespond "UNAUTHORIZED RESPOND"
+// @Failure 422 {object} utils.ValidationErrorRespond "UNPROCESSABLE ENTITY RESPOND"
+// @Failure 500 {object} utils.ErrorRespond "INTERNAL SERVER ERROR RESPOND"
+// @Router /api/v1/price-lists [POST]
+func (handler priceListHandler) store(ctx *gin.Context) {
+	var form model.PriceList
+	if err := ctx.ShouldBindJSON(&form); err != nil {
+		utils.NewHTTPRespond(ctx, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+
+	data, err := handler.svc.AddPriceList(ctx, &form)
+	if err != nil {
+		utils.NewHTTPRespond(ctx, err.Code, err.Message)
+		return
+	}
+
+	utils.NewHTTPRespond(ctx, http.StatusCreated, data)
+}
+
+// price lists godoc
+// @Schemes
+// @Summary Update Price List Data
+// @Description Update price list by ID, items replace the current items.
+// @Tags Price Lists
+// @Accept json
+// @Produce json
+// @Param id   path int 			true "price list id"
+// @Param body body model.PriceList true "price list and its items"
+// @Success 200 {object} utils.SuccessRespond{data=model.PriceList} "OK RESPOND"
+// @Failure 400 {object} utils.ErrorRespond "BAD REQUEST RESPOND"
+// @Failure 401 {object} utils.ErrorRespond "UNAUTHORIZED RESPOND"
+// @Failure 404 {object} utils.ErrorRespond "NOT FOUND RESPOND"
+// @Failure 422 {object} utils.ValidationErrorRespond "UNPROCESSABLE ENTITY RESPOND"
+// @Failure 500 {object} utils.ErrorRespond "INTERNAL SERVER ERROR RESPOND"
+// @Router /api/v1/price-lists/{id} [PUT]
+func (handler priceListHandler) update(ctx *gin.Context) {
+	idParams := ctx.Param("id")
+	id, errParse := strconv.Atoi(idParams)
+	if errParse != nil {
+		utils.NewHTTPRespond(ctx,
+			http.StatusBadRequest,
+			errParse.Error())
+		return
+	}
+
+	var form model.PriceList
+	if err := ctx.ShouldBindJSON(&form); err != nil {
+		utils.NewHTTPRespond(ctx, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+
+	form.ID = id
+	data, err := handler.svc.EditPriceList(ctx, &form)
+	if err != nil {
+		utils.NewHTTPRespond(ctx, err.Code, err.Message)
+		return
+	}
+
+	utils.NewHTTPRespond(ctx, http.StatusOK, data)
+}
+
+// price lists godoc
+// @Schemes
+// @Summary Delete Price List Data
+// @Description Delete price list by ID, past orders keep their price snapshot.
+// @Tags Price Lists
+// @Accept json
+// @Produce json
+// @Param id path int true "price list id"
+// @Success 204 "NO CONTENT RESPOND"
+// @Failure 400 {object} utils.ErrorRespond "BAD REQUEST RESPOND"
+// @Failure 401 {object} utils.ErrorRespond "UNAUTHORIZED RESPOND"
+// @Failure 404 {object} utils.ErrorRespond "NOT FOUND RESPOND"
+// @Failure 500 {object} utils.ErrorRespond "INTERNAL SERVER ERROR RESPOND"
+// @Router /api/v1/price-lists/{id} [DELETE]
+func (handler priceListHandler) destroy(ctx *gin.Context) {
+	idParams := ctx.Param("id")
+	id, errParse := strconv.Atoi(idParams)
+	if errParse != nil {
+		utils.NewHTTPRespond(ctx,
+			http.StatusBadRequest,
+			errParse.Error())
+		return
+	}
+	data := model.PriceList{ID: id}
+
+	err := handler.svc.DeletePriceList(ctx, &data)
+	if err != nil {
+		utils.NewHTTPRespond(ctx, err.Code, err.Message)
+		return
+	}
+
+	utils.NewHTTPRespond(ctx, http.StatusNoContent, nil)
+}
+
+// price lists godoc
+// @Schemes
+// @Summary Resolve Prices
+// @Description Resolve effective price of each item for the order context,
+// @Description the snapshot should be stored on the order line as is.
+// @Tags Price Lists
+// @Accept json
+// @Produce json
+// @Param body body model.PriceQuery true "order context and items"
+// @Success 200 {object} utils.SuccessRespond{data=[]model.PriceSnapshot} "OK RESPOND"
+// @Failure 401 {object} utils.ErrorRespond "UNAUTHORIZED RESPOND"
+// @Failure 422 {object} utils.ValidationErrorRespond "UNPROCESSABLE ENTITY RESPOND"
+// @Failure 500 {object} utils.ErrorRespond "INTERNAL SERVER ERROR RESPOND"
+// @Router /api/v1/prices/resolve [POST]
+func (handler priceListHandler) resolve(ctx *gin.Context) {
+	var form model.PriceQuery
+	if err := ctx.ShouldBindJSON(&form); err != nil {
+		utils.NewHTTPRespond(ctx, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+
+	data, err := handler.svc.ResolvePrices(ctx, &form)
+	if err != nil {
+		utils.NewHTTPRespond(ctx, err.Code, err.Message)
+		return
+	}
+
+	utils.NewHTTPRespond(ctx, http.StatusOK, data)
+}
+
+func NewPriceListHandler(svc model.ICatalogPriceService, router gin.IRoutes) {
+	handler := priceListHandler{svc: svc}
+	read := middleware.Permitted(model.PermissionCatalogRead)
+	priceWrite := middleware.Permitted(model.PermissionCatalogPriceWrite)
+	router.GET("/price-lists", read, handler.fetch)
+	router.POST("/price-lists", priceWrite, handler.store)
+	router.PUT("/price-lists/:id", priceWrite, handler.update)
+	router.DELETE("/price-lists/:id", priceWrite, handler.destroy)
+	router.POST("/prices/resolve", read, middleware.SkipAudit(), handler.resolve)
+}
@@ -0,0 +1,102 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/aasumitro/posbe/pkg/http/middleware"
+	"github.com/aasumitro/posbe/pkg/model"
+	"github.com/aasumitro/posbe/pkg/utils"
+	"github.com/gin-gonic/gin"
+)
+
+type productMediaHandler struct {
+	svc model.ICatalogMediaService
+}
+
+// products godoc
+// @Schemes
+// @Summary Upload Product Image
+// @Description Upload image to product gallery, thumbnail is generated.
+// @Description First image (or image uploaded with cover=true) become the product image.
+// @Tags Product Images
+// @Accept mpfd
+// @Produce json
+// @Param id 	path 	 int 	true  "product id"
+// @Param file 	formData file 	true  "jpeg, png, gif or webp image (max 5MB)"
+// @Param cover formData bool 	false "set as product cover image"
+// @Success 201 {object} utils.SuccessRespond{data=model.Product} "CREATED RESPOND"
+// @Failure 400 {object} utils.ErrorRespond "BAD REQUEST RESPOND"
+// @Failure 401 {object} utils.ErrorRespond "UNAUTHORIZED RESPOND"
+// @Failure 404 {object} utils.ErrorRespond "NOT FOUND RESPOND"
+// @Failure 413 {object} utils.ErrorRespond "REQUEST ENTITY TOO LARGE RESPOND"
+// @Failure 415 {object} utils.ErrorRespond "UNSUPPORTED MEDIA TYPE RESPOND"
+// @Failure 422 {object} utils.ValidationErrorRespond "UNPROCESSABLE ENTITY RESPOND"
+// @Failure 500 {object} utils.ErrorRespond "INTERNAL SERVER ERROR RESPOND"
+// @Router /api/v1/products/{id}/images [POST]
+func (handler productMediaHandler) store(ctx *gin.Context) {
+	idParams := ctx.Param("id")
+	id, errParse := strconv.Atoi(idParams)
+	if errParse != nil {
+		utils.NewHTTPRespond(ctx,
+			http.StatusBadRequest,
+			errParse.Error())
+		return
+	}
+
+	upload, err := utils.ReadUploadImage(ctx, "file")
+	if err != nil {
+		utils.NewHTTPRespond(ctx, err.Code, err.Message)
+		return
+	}
+	cover, _ := strconv.ParseBool(ctx.PostForm("cover"))
+
+	data, err := handler.svc.AddProductImage(ctx, id, upload, cover)
+	if err != nil {
+		utils.NewHTTPRespond(ctx, err.Code, err.Message)
+		return
+	}
+
+	utils.NewHTTPRespond(ctx, http.StatusCreated, data)
+}
+
+// products godoc
+// @Schemes
+// @Summary Delete Product Image
+// @Description Remove image from product gallery and storage.
+// @Tags Product Images
+// @Accept json
+// @Produce json
+// @Param id 		path int 	true "product id"
+// @Param image_id 	path string true "gallery image id"
+// @Success 200 {object} utils.SuccessRespond{data=model.Product} "OK RESPOND"
+// @Failure 400 {object} utils.ErrorRespond "BAD REQUEST RESPOND"
+// @Failure 401 {object} utils.ErrorRespond "UNAUTHORIZED RESPOND"
+// @Failure 404 {object} utils.ErrorRespond "NOT FOUND RESPOND"
+// @Failure 500 {object} utils.ErrorRespond "INTERNAL SERVER ERROR RESPOND"
+// @Router /api/v1/products/{id}/images/{image_id} [DELETE]
+func (handler productMediaHandler) destroy(ctx *gin.Context) {
+	idParams := ctx.Param("id")
+	id, errParse := strconv.Atoi(idParams)
+	if errParse != nil {
+		utils.NewHTTPRespond(ctx,
+			http.StatusBadRequest,
+			errParse.Error())
+		return
+	}
+
+	data, err := handler.svc.DeleteProductImage(ctx, id, ctx.Param("image_id"))
+	if err != nil {
+		utils.NewHTTPRespond(ctx, err.Code, err.Message)
+		return
+	}
+
+	utils.NewHTTPRespond(ctx, http.StatusOK, data)
+}
+
+func NewProductMediaHandler(svc model.ICatalogMediaService, router gin.IRoutes) {
+	handler := productMediaHandler{svc: svc}
+	write := middleware.Permitted(model.PermissionCatalogProductWrite)
+	router.POST("/products/:id/images", write, handler.store)
+	router.DELETE("/products/:id/images/:image_id", write, handler.destroy)
+}
@@ -4,6 +4,7 @@ import (
 	"net/http"
 	"strconv"
 
+	"github.com/aasumitro/posbe/pkg/http/middleware"
 	"github.com/aasumitro/posbe/pkg/model"
 	"github.com/aasumitro/posbe/pkg/utils"
 	"github.com/gin-gonic/gin"
@@ -129,9 +130,44 @@ func (handler variantHandler) destroy(ctx *gin.Context) {
 	utils.NewHTTPRespond(ctx, http.StatusNoContent, nil)
 }
 
+// products/variants godoc
+// @Schemes
+// @Summary Restore Variant Data
+// @Description Restore deleted variant by ID.
+// @Tags Product Variants
+// @Accept json
+// @Produce json
+// @Param id path int true "variant id"
+// @Success 200 {object} utils.SuccessRespond{data=model.ProductVariant} "OK RESPOND"
+// @Failure 400 {object} utils.ErrorRespond "BAD REQUEST RESPOND"
+// @Failure 401 {object} utils.ErrorRespond "UNAUTHORIZED RESPOND"
+// @Failure 403 {object} utils.ErrorRespond "FORBIDDEN RESPOND"
+// @Failure 404 {object} utils.ErrorRespond "NOT FOUND RESPOND"
+// @Failure 500 {object} utils.ErrorRespond "INTERNAL SERVER ERROR RESPOND"
+// @Router /api/v1/products/variants/{id}/restore [PATCH]
+func (handler variantHandler) restore(ctx *gin.Context) {
+	idParams := ctx.Param("id")
+	id, errParse := strconv.Atoi(idParams)
+	if errParse != nil {
+		utils.NewHTTPRespond(ctx,
+			http.StatusBadRequest,
+			errParse.Error())
+		return
+	}
+	data, err := handler.svc.RestoreProductVariant(ctx, &model.ProductVariant{ID: id})
+	if err != nil {
+		utils.NewHTTPRespond(ctx, err.Code, err.Message)
+		return
+	}
+	utils.NewHTTPRespond(ctx, http.StatusOK, data)
+}
+
 func NewProductVariantHandler(svc model.ICatalogProductService, router gin.IRoutes) {
 	handler := variantHandler{svc: svc}
-	router.POST("/products/variants", handler.store)
-	router.PUT("/products/variants/:id", handler.update)
-	router.DELETE("/products/variants/:id", handler.destroy)
+	write := middleware.Permitted(model.PermissionCatalogProductWrite)
+	trash := middleware.Permitted(model.PermissionTrashRestore)
+	router.POST("/products/variants", write, handler.store)
+	router.PUT("/products/variants/:id", write, handler.update)
+	router.DELETE("/products/variants/:id", write, handler.destroy)
+	router.PATCH("/products/variants/:id/restore", trash, handler.restore)
 }
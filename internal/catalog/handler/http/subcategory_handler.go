@@ -4,6 +4,7 @@ import (
 	"net/http"
 	"strconv"
 
+	"github.com/aasumitro/posbe/pkg/http/middleware"
 	"github.com/aasumitro/posbe/pkg/model"
 	"github.com/aasumitro/posbe/pkg/utils"
 	"github.com/gin-gonic/gin"
@@ -114,9 +115,13 @@ func (handler subcategoryHandler) update(ctx *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param id path int true "subcategory id"
+// @Param reassign_to query int false "subcategory id that take over the products"
 // @Success 204 "NO CONTENT RESPOND"
 // @Failure 400 {object} utils.ErrorRespond "BAD REQUEST RESPOND"
 // @Failure 401 {object} utils.ErrorRespond "UNAUTHORIZED RESPOND"
+// @Failure 404 {object} utils.ErrorRespond "NOT FOUND RESPOND"
+// @Failure 409 {object} utils.ValidationErrorRespond{data=model.DeleteConflict} "CONFLICT RESPOND"
+// @Failure 422 {object} utils.ValidationErrorRespond "UNPROCESSABLE ENTITY RESPOND"
 // @Failure 500 {object} utils.ErrorRespond "INTERNAL SERVER ERROR RESPOND"
 // @Router /api/v1/subcategories/{id} [DELETE]
 func (handler subcategoryHandler) destroy(ctx *gin.Context) {
@@ -139,10 +144,46 @@ func (handler subcategoryHandler) destroy(ctx *gin.Context) {
 	utils.NewHTTPRespond(ctx, http.StatusNoContent, nil)
 }
 
+// subcategories godoc
+// @Schemes
+// @Summary Restore Subcategory Data
+// @Description Restore deleted subcategory by ID.
+// @Tags Product Subcategories
+// @Accept json
+// @Produce json
+// @Param id path int true "subcategory id"
+// @Success 200 {object} utils.SuccessRespond{data=model.Subcategory} "OK RESPOND"
+// @Failure 400 {object} utils.ErrorRespond "BAD REQUEST RESPOND"
+// @Failure 401 {object} utils.ErrorRespond "UNAUTHORIZED RESPOND"
+// @Failure 403 {object} utils.ErrorRespond "FORBIDDEN RESPOND"
+// @Failure 404 {object} utils.ErrorRespond "NOT FOUND RESPOND"
+// @Failure 500 {object} utils.ErrorRespond "INTERNAL SERVER ERROR RESPOND"
+// @Router /api/v1/subcategories/{id}/restore [PATCH]
+func (handler subcategoryHandler) restore(ctx *gin.Context) {
+	idParams := ctx.Param("id")
+	id, errParse := strconv.Atoi(idParams)
+	if errParse != nil {
+		utils.NewHTTPRespond(ctx,
+			http.StatusBadRequest,
+			errParse.Error())
+		return
+	}
+	data, err := handler.svc.RestoreSubcategory(ctx, &model.Subcategory{ID: id})
+	if err != nil {
+		utils.NewHTTPRespond(ctx, err.Code, err.Message)
+		return
+	}
+	utils.NewHTTPRespond(ctx, http.StatusOK, data)
+}
+
 func NewSubcategoryHandler(svc model.ICatalogCommonService, router gin.IRoutes) {
 	handler := subcategoryHandler{svc: svc}
-	router.GET("/subcategories", handler.fetch)
-	router.POST("/subcategories", handler.store)
-	router.PUT("/subcategories/:id", handler.update)
-	router.DELETE("/subcategories/:id", handler.destroy)
+	read := middleware.Permitted(model.PermissionCatalogRead)
+	write := middleware.Permitted(model.PermissionCatalogProductWrite)
+	trash := middleware.Permitted(model.PermissionTrashRestore)
+	router.GET("/subcategories", read, handler.fetch)
+	router.POST("/subcategories", write, handler.store)
+	router.PUT("/subcategories/:id", write, handler.update)
+	router.DELETE("/subcategories/:id", write, handler.destroy)
+	router.PATCH("/subcategories/:id/restore", trash, handler.restore)
 }
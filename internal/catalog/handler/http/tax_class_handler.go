@@ -0,0 +1,182 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/aasumitro/posbe/pkg/http/middleware"
+	"github.com/aasumitro/posbe/pkg/model"
+	"github.com/aasumitro/posbe/pkg/utils"
+	"github.com/gin-gonic/gin"
+)
+
+type taxClassHandler struct {
+	svc model.ICatalogTaxService
+}
+
+// tax classes godoc
+// @Schemes
+// @Summary Tax Classes
+// @Description Get Tax Classes with their products and categories.
+// @Tags Tax Classes
+// @Accept json
+// @Produce json
+// @Success 200 {object} utils.SuccessRespond{data=[]model.TaxClass} "OK RESPOND"
+// @Failure 401 {object} utils.ErrorRespond "UNAUTHORIZED RESPOND"
+// @Failure 500 {object} utils.ErrorRespond "INTERNAL SERVER ERROR RESPOND"
+// @Router /api/v1/tax-classes [GET]
+func (handler taxClassHandler) fetch(ctx *gin.Context) {
+	data, err := handler.svc.TaxClassList(ctx)
+	if err != nil {
+		utils.NewHTTPRespond(ctx, err.Code, err.Message)
+		return
+	}
+	utils.NewHTTPRespond(ctx, http.StatusOK, data)
+}
+
+// tax classes godoc
+// @Schemes
+// @Summary Store Tax Class Data
+// @Description Create new tax class, listed products and categories move to the class.
+// @Tags Tax Classes
+// @Accept json
+// @Produce json
+// @Param body body model.TaxClass true "tax class and its assignments"
+// @Success 201 {object} utils.SuccessRespond{data=model.TaxClass} "CREATED RESPOND"
+// @Failure 401 {object} utils.ErrorRespond "UNAUTHORIZED RESPOND"
+// @Failure 422 {object} utils.ValidationErrorRespond "UNPROCESSABLE ENTITY RESPOND"
+// @Failure 500 {object} utils.ErrorRespond "INTERNAL SERVER ERROR RESPOND"
+// @Router /api/v1/tax-classes [POST]
+func (handler taxClassHandler) store(ctx *gin.Context) {
+	var form model.TaxClass
+	if err := ctx.ShouldBindJSON(&form); err != nil {
+		utils.NewHTTPRespond(ctx, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+
+	data, err := handler.svc.AddTaxClass(ctx, &form)
+	if err != nil {
+		utils.NewHTTPRespond(ctx, err.Code, err.Message)
+		return
+	}
+
+	utils.NewHTTPRespond(ctx, http.StatusCreated, data)
+}
+
+// tax classes godoc
+// @Schemes
+// @Summary Update Tax Class Data
+// @Description Update tax class by ID, assignments replace the current assignments.
+// @Tags Tax Classes
+// @Accept json
+// @Produce json
+// @Param id   path int 		   true "tax class id"
+// @Param body body model.TaxClass true "tax class and its assignments"
+// @Success 200 {object} utils.SuccessRespond{data=model.TaxClass} "OK RESPOND"
+// @Failure 400 {object} utils.ErrorRespond "BAD REQUEST RESPOND"
+// @Failure 401 {object} utils.ErrorRespond "UNAUTHORIZED RESPOND"
+// @Failure 404 {object} utils.ErrorRespond "NOT FOUND RESPOND"
+// @Failure 422 {object} utils.ValidationErrorRespond "UNPROCESSABLE ENTITY RESPOND"
+// @Failure 500 {object} utils.ErrorRespond "INTERNAL SERVER ERROR RESPOND"
+// @Router /api/v1/tax-classes/{id} [PUT]
+func (handler taxClassHandler) update(ctx *gin.Context) {
+	idParams := ctx.Param("id")
+	id, errParse := strconv.Atoi(idParams)
+	if errParse != nil {
+		utils.NewHTTPRespond(ctx,
+			http.StatusBadRequest,
+			errParse.Error())
+		return
+	}
+
+	var form model.TaxClass
+	if err := ctx.ShouldBindJSON(&form); err != nil {
+		utils.NewHTTPRespond(ctx, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+
+	form.ID = id
+	data, err := handler.svc.EditTaxClass(ctx, &form)
+	if err != nil {
+		utils.NewHTTPRespond(ctx, err.Code, err.Message)
+		return
+	}
+
+	utils.NewHTTPRespond(ctx, http.StatusOK, data)
+}
+
+// tax classes godoc
+// @Schemes
+// @Summary Delete Tax Class Data
+// @Description Delete tax class by ID, its products fall back to the default class.
+// @Tags Tax Classes
+// @Accept json
+// @Produce json
+// @Param id path int true "tax class id"
+// @Success 204 "NO CONTENT RESPOND"
+// @Failure 400 {object} utils.ErrorRespond "BAD REQUEST RESPOND"
+// @Failure 401 {object} utils.ErrorRespond "UNAUTHORIZED RESPOND"
+// @Failure 404 {object} utils.ErrorRespond "NOT FOUND RESPOND"
+// @Failure 422 {object} utils.ErrorRespond "UNPROCESSABLE ENTITY RESPOND"
+// @Failure 500 {object} utils.ErrorRespond "INTERNAL SERVER ERROR RESPOND"
+// @Router /api/v1/tax-classes/{id} [DELETE]
+func (handler taxClassHandler) destroy(ctx *gin.Context) {
+	idParams := ctx.Param("id")
+	id, errParse := strconv.Atoi(idParams)
+	if errParse != nil {
+		utils.NewHTTPRespond(ctx,
+			http.StatusBadRequest,
+			errParse.Error())
+		return
+	}
+	data := model.TaxClass{ID: id}
+
+	err := handler.svc.DeleteTaxClass(ctx, &data)
+	if err != nil {
+		utils.NewHTTPRespond(ctx, err.Code, err.Message)
+		return
+	}
+
+	utils.NewHTTPRespond(ctx, http.StatusNoContent, nil)
+}
+
+// tax classes godoc
+// @Schemes
+// @Summary Calculate Tax
+// @Description Calculate service charge and per-rate tax of the order lines,
+// @Description the summary should be stored on the order as is.
+// @Tags Tax Classes
+// @Accept json
+// @Produce json
+// @Param body body model.TaxQuery true "order lines"
+// @Success 200 {object} utils.SuccessRespond{data=model.TaxSummary} "OK RESPOND"
+// @Failure 401 {object} utils.ErrorRespond "UNAUTHORIZED RESPOND"
+// @Failure 422 {object} utils.ValidationErrorRespond "UNPROCESSABLE ENTITY RESPOND"
+// @Failure 500 {object} utils.ErrorRespond "INTERNAL SERVER ERROR RESPOND"
+// @Router /api/v1/taxes/calculate [POST]
+func (handler taxClassHandler) calculate(ctx *gin.Context) {
+	var form model.TaxQuery
+	if err := ctx.ShouldBindJSON(&form); err != nil {
+		utils.NewHTTPRespond(ctx, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+
+	data, err := handler.svc.CalculateTax(ctx, &form)
+	if err != nil {
+		utils.NewHTTPRespond(ctx, err.Code, err.Message)
+		return
+	}
+
+	utils.NewHTTPRespond(ctx, http.StatusOK, data)
+}
+
+func NewTaxClassHandler(svc model.ICatalogTaxService, router gin.IRoutes) {
+	handler := taxClassHandler{svc: svc}
+	read := middleware.Permitted(model.PermissionCatalogRead)
+	priceWrite := middleware.Permitted(model.PermissionCatalogPriceWrite)
+	router.GET("/tax-classes", read, handler.fetch)
+	router.POST("/tax-classes", priceWrite, handler.store)
+	router.PUT("/tax-classes/:id", priceWrite, handler.update)
+	router.DELETE("/tax-classes/:id", priceWrite, handler.destroy)
+	router.POST("/taxes/calculate", read, middleware.SkipAudit(), handler.calculate)
+}
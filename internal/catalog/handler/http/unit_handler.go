@@ -4,6 +4,7 @@ import (
 	"net/http"
 	"strconv"
 
+	"github.com/aasumitro/posbe/pkg/http/middleware"
 	"github.com/aasumitro/posbe/pkg/model"
 	"github.com/aasumitro/posbe/pkg/utils"
 	"github.com/gin-gonic/gin"
@@ -114,9 +115,13 @@ func (handler unitHandler) update(ctx *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param id path int true "unit id"
+// @Param reassign_to query int false "unit id that take over the variants"
 // @Success 204 "NO CONTENT RESPOND"
 // @Failure 400 {object} utils.ErrorRespond "BAD REQUEST RESPOND"
 // @Failure 401 {object} utils.ErrorRespond "UNAUTHORIZED RESPOND"
+// @Failure 404 {object} utils.ErrorRespond "NOT FOUND RESPOND"
+// @Failure 409 {object} utils.ValidationErrorRespond{data=model.DeleteConflict} "CONFLICT RESPOND"
+// @Failure 422 {object} utils.ValidationErrorRespond "UNPROCESSABLE ENTITY RESPOND"
 // @Failure 500 {object} utils.ErrorRespond "INTERNAL SERVER ERROR RESPOND"
 // @Router /api/v1/units/{id} [DELETE]
 func (handler unitHandler) destroy(ctx *gin.Context) {
@@ -139,10 +144,46 @@ func (handler unitHandler) destroy(ctx *gin.Context) {
 	utils.NewHTTPRespond(ctx, http.StatusNoContent, nil)
 }
 
+// units godoc
+// @Schemes
+// @Summary Restore Unit Data
+// @Description Restore deleted unit by ID.
+// @Tags Product Units
+// @Accept json
+// @Produce json
+// @Param id path int true "unit id"
+// @Success 200 {object} utils.SuccessRespond{data=model.Unit} "OK RESPOND"
+// @Failure 400 {object} utils.ErrorRespond "BAD REQUEST RESPOND"
+// @Failure 401 {object} utils.ErrorRespond "UNAUTHORIZED RESPOND"
+// @Failure 403 {object} utils.ErrorRespond "FORBIDDEN RESPOND"
+// @Failure 404 {object} utils.ErrorRespond "NOT FOUND RESPOND"
+// @Failure 500 {object} utils.ErrorRespond "INTERNAL SERVER ERROR RESPOND"
+// @Router /api/v1/units/{id}/restore [PATCH]
+func (handler unitHandler) restore(ctx *gin.Context) {
+	idParams := ctx.Param("id")
+	id, errParse := strconv.Atoi(idParams)
+	if errParse != nil {
+		utils.NewHTTPRespond(ctx,
+			http.StatusBadRequest,
+			errParse.Error())
+		return
+	}
+	data, err := handler.svc.RestoreUnit(ctx, &model.Unit{ID: id})
+	if err != nil {
+		utils.NewHTTPRespond(ctx, err.Code, err.Message)
+		return
+	}
+	utils.NewHTTPRespond(ctx, http.StatusOK, data)
+}
+
 func NewUnitHandler(svc model.ICatalogCommonService, router gin.IRoutes) {
 	handler := unitHandler{svc: svc}
-	router.GET("/units", handler.fetch)
-	router.POST("/units", handler.store)
-	router.PUT("/units/:id", handler.update)
-	router.DELETE("/units/:id", handler.destroy)
+	read := middleware.Permitted(model.PermissionCatalogRead)
+	write := middleware.Permitted(model.PermissionCatalogProductWrite)
+	trash := middleware.Permitted(model.PermissionTrashRestore)
+	router.GET("/units", read, handler.fetch)
+	router.POST("/units", write, handler.store)
+	router.PUT("/units/:id", write, handler.update)
+	router.DELETE("/units/:id", write, handler.destroy)
+	router.PATCH("/units/:id/restore", trash, handler.restore)
 }
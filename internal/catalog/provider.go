@@ -1,30 +1,67 @@
 package catalog
 
 import (
+	"github.com/aasumitro/posbe/common"
+	"github.com/aasumitro/posbe/config"
 	"github.com/aasumitro/posbe/internal/catalog/handler/http"
 	repository "github.com/aasumitro/posbe/internal/catalog/repository/sql"
 	"github.com/aasumitro/posbe/internal/catalog/service"
+	storeRepository "github.com/aasumitro/posbe/internal/store/repository/sql"
+	"github.com/aasumitro/posbe/pkg/audit"
 	"github.com/aasumitro/posbe/pkg/http/middleware"
+	"github.com/aasumitro/posbe/pkg/reference"
 	"github.com/gin-gonic/gin"
 )
 
 func NewCatalogModuleProvider(router *gin.RouterGroup) {
-	unitRepository := repository.NewUnitSQLRepository()
-	categoryRepository := repository.NewCategorySQLRepository()
-	subcategoryRepository := repository.NewSubcategorySQLRepository()
-	addonRepository := repository.NewAddonSQLRepository()
-	productRepository := repository.NewProductSQLRepository()
-	productVariantRepository := repository.NewProductVariantSQLRepository()
+	unitRepository := audit.Track(repository.NewUnitSQLRepository())
+	categoryRepository := audit.Track(repository.NewCategorySQLRepository())
+	subcategoryRepository := audit.Track(repository.NewSubcategorySQLRepository())
+	addonRepository := audit.Track(repository.NewAddonSQLRepository())
+	productRepository := audit.TrackWithSearch(repository.NewProductSQLRepository())
+	productVariantRepository := audit.Track(repository.NewProductVariantSQLRepository())
+	catalogImportRepository := repository.NewCatalogImportSQLRepository()
+	addonGroupRepository := repository.NewAddonGroupSQLRepository()
+	bundleRepository := repository.NewBundleSQLRepository()
+	priceListRepository := repository.NewPriceListSQLRepository()
+	availabilityRepository := repository.NewAvailabilitySQLRepository()
+	taxClassRepository := repository.NewTaxClassSQLRepository()
+	storePrefRepository := storeRepository.NewStorePrefSQLRepository()
+	entityVersionRepository := repository.NewEntityVersionSQLRepository()
 	catalogCommonService := service.NewCatalogCommonService(unitRepository,
-		categoryRepository, subcategoryRepository, addonRepository)
-	productCommonService := service.NewCatalogProductService(
-		productRepository, productVariantRepository)
-	protectedRouter := router.
+		categoryRepository, subcategoryRepository, addonRepository,
+		reference.NewReferenceSQLRepository())
+	productCommonService := service.NewCatalogProductService(productRepository,
+		productVariantRepository, availabilityRepository, storePrefRepository)
+	catalogImportService := service.NewCatalogImportService(catalogImportRepository)
+	catalogMediaService := service.NewCatalogMediaService(productRepository, config.Storage)
+	catalogModifierService := service.NewCatalogModifierService(
+		addonGroupRepository, addonRepository)
+	catalogBundleService := service.NewCatalogBundleService(
+		bundleRepository, productRepository, productVariantRepository)
+	catalogPriceService := service.NewCatalogPriceService(
+		priceListRepository, storePrefRepository)
+	catalogTaxService := service.NewCatalogTaxService(
+		taxClassRepository, storePrefRepository)
+	catalogAvailabilityService := service.NewCatalogAvailabilityService(availabilityRepository,
+		storePrefRepository, config.Broker)
+	catalogHistoryService := service.NewCatalogHistoryService(entityVersionRepository)
+	protectedRouter := router.Group(common.EmptyPath).
 		Use(middleware.Auth()).
-		Use(middleware.AcceptedRoles([]string{"*"}))
+		Use(middleware.IncludeDeleted()).
+		Use(middleware.ReassignTo()).
+		Use(middleware.ActivityObserver())
 	http.NewUnitHandler(catalogCommonService, protectedRouter)
 	http.NewCategoryHandler(catalogCommonService, protectedRouter)
 	http.NewSubcategoryHandler(catalogCommonService, protectedRouter)
 	http.NewAddonHandler(catalogCommonService, protectedRouter)
 	http.NewProductVariantHandler(productCommonService, protectedRouter)
+	http.NewCatalogImportHandler(catalogImportService, protectedRouter)
+	http.NewProductMediaHandler(catalogMediaService, protectedRouter)
+	http.NewAddonGroupHandler(catalogModifierService, protectedRouter)
+	http.NewBundleHandler(catalogBundleService, protectedRouter)
+	http.NewPriceListHandler(catalogPriceService, protectedRouter)
+	http.NewAvailabilityHandler(catalogAvailabilityService, protectedRouter)
+	http.NewTaxClassHandler(catalogTaxService, protectedRouter)
+	http.NewHistoryHandler(catalogHistoryService, protectedRouter)
 }
@@ -0,0 +1,227 @@
+package sql
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/aasumitro/posbe/config"
+	"github.com/aasumitro/posbe/pkg/model"
+	"github.com/aasumitro/posbe/pkg/money"
+)
+
+const addonGroupColumns = "g.id, g.name, g.description, g.min_select, g.max_select, " +
+	"a.id, a.name, a.description, "
+
+type AddonGroupSQLRepository struct {
+	Db *sql.DB
+}
+
+func (repo AddonGroupSQLRepository) All(ctx context.Context) (data []*model.AddonGroup, err error) {
+	q := "SELECT " + addonGroupColumns + "a.price FROM addon_groups AS g "
+	q += "LEFT JOIN addon_group_items AS i ON i.addon_group_id = g.id "
+	q += "LEFT JOIN addons AS a ON a.id = i.addon_id "
+	q += "ORDER BY g.id, i.position"
+	rows, err := repo.Db.QueryContext(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+	defer func(rows *sql.Rows) { _ = rows.Close() }(rows)
+
+	return scanAddonGroups(rows)
+}
+
+func (repo AddonGroupSQLRepository) Find(ctx context.Context, _ model.FindWith, val any) (data *model.AddonGroup, err error) {
+	q := "SELECT " + addonGroupColumns + "a.price FROM addon_groups AS g "
+	q += "LEFT JOIN addon_group_items AS i ON i.addon_group_id = g.id "
+	q += "LEFT JOIN addons AS a ON a.id = i.addon_id "
+	q += "WHERE g.id = $1 ORDER BY i.position"
+	rows, err := repo.Db.QueryContext(ctx, q, val)
+	if err != nil {
+		return nil, err
+	}
+	defer func(rows *sql.Rows) { _ = rows.Close() }(rows)
+
+	groups, err := scanAddonGroups(rows)
+	if err != nil {
+		return nil, err
+	}
+	if len(groups) == 0 {
+		return nil, sql.ErrNoRows
+	}
+
+	return groups[0], nil
+}
+
+func (repo AddonGroupSQLRepository) Create(ctx context.Context, params *model.AddonGroup) (data *model.AddonGroup, err error) {
+	tx, err := repo.Db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	q := "INSERT INTO addon_groups (name, description, min_select, max_select) "
+	q += "VALUES ($1, $2, $3, $4) RETURNING id"
+	var id int
+	if err := tx.QueryRowContext(ctx, q, params.Name, params.Description,
+		params.MinSelect, params.MaxSelect,
+	).Scan(&id); err != nil {
+		return nil, err
+	}
+	if err := replaceAddonGroupItems(ctx, tx, id, params.AddonIDs); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return repo.Find(ctx, model.FindWithID, id)
+}
+
+func (repo AddonGroupSQLRepository) Update(ctx context.Context, params *model.AddonGroup) (data *model.AddonGroup, err error) {
+	tx, err := repo.Db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	q := "UPDATE addon_groups SET name = $1, description = $2, min_select = $3, "
+	q += "max_select = $4, updated_at = $5 WHERE id = $6"
+	result, err := tx.ExecContext(ctx, q, params.Name, params.Description,
+		params.MinSelect, params.MaxSelect, time.Now().Unix(), params.ID)
+	if err != nil {
+		return nil, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return nil, err
+	}
+	if affected == 0 {
+		return nil, sql.ErrNoRows
+	}
+	if err := replaceAddonGroupItems(ctx, tx, params.ID, params.AddonIDs); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return repo.Find(ctx, model.FindWithID, params.ID)
+}
+
+func (repo AddonGroupSQLRepository) Delete(ctx context.Context, params *model.AddonGroup) error {
+	q := "DELETE FROM addon_groups WHERE id = $1"
+	_, err := repo.Db.ExecContext(ctx, q, params.ID)
+	return err
+}
+
+func (repo AddonGroupSQLRepository) Assign(ctx context.Context, assignment *model.AddonGroupAssignment) error {
+	q := "INSERT INTO addon_group_assignments (addon_group_id, product_id, category_id) "
+	q += "VALUES ($1, $2, $3) ON CONFLICT DO NOTHING"
+	_, err := repo.Db.ExecContext(ctx, q, assignment.AddonGroupID,
+		nullID(assignment.ProductID), nullID(assignment.CategoryID))
+	return err
+}
+
+func (repo AddonGroupSQLRepository) Unassign(ctx context.Context, assignment *model.AddonGroupAssignment) error {
+	q := "DELETE FROM addon_group_assignments WHERE addon_group_id = $1 "
+	q += "AND (product_id = $2 OR category_id = $3)"
+	_, err := repo.Db.ExecContext(ctx, q, assignment.AddonGroupID,
+		nullID(assignment.ProductID), nullID(assignment.CategoryID))
+	return err
+}
+
+func (repo AddonGroupSQLRepository) ProductGroups(ctx context.Context, productID int) (data []*model.AddonGroup, err error) {
+	q := "SELECT " + addonGroupColumns + "COALESCE(pap.price, a.price) FROM addon_groups AS g "
+	q += "JOIN products AS p ON p.id = $1 "
+	q += "LEFT JOIN addon_group_items AS i ON i.addon_group_id = g.id "
+	q += "LEFT JOIN addons AS a ON a.id = i.addon_id "
+	q += "LEFT JOIN product_addon_prices AS pap ON pap.product_id = p.id AND pap.addon_id = a.id "
+	q += "WHERE EXISTS (SELECT 1 FROM addon_group_assignments AS ga WHERE ga.addon_group_id = g.id "
+	q += "AND (ga.product_id = p.id OR ga.category_id = p.category_id)) "
+	q += "ORDER BY g.id, i.position"
+	rows, err := repo.Db.QueryContext(ctx, q, productID)
+	if err != nil {
+		return nil, err
+	}
+	defer func(rows *sql.Rows) { _ = rows.Close() }(rows)
+
+	return scanAddonGroups(rows)
+}
+
+func (repo AddonGroupSQLRepository) SetProductPrice(ctx context.Context, price *model.ProductAddonPrice) error {
+	q := "INSERT INTO product_addon_prices (product_id, addon_id, price) VALUES ($1, $2, $3) "
+	q += "ON CONFLICT (product_id, addon_id) DO UPDATE SET price = EXCLUDED.price, updated_at = $4"
+	_, err := repo.Db.ExecContext(ctx, q, price.ProductID, price.AddonID,
+		price.Price, time.Now().Unix())
+	return err
+}
+
+func (repo AddonGroupSQLRepository) DeleteProductPrice(ctx context.Context, price *model.ProductAddonPrice) error {
+	q := "DELETE FROM product_addon_prices WHERE product_id = $1 AND addon_id = $2"
+	_, err := repo.Db.ExecContext(ctx, q, price.ProductID, price.AddonID)
+	return err
+}
+
+func replaceAddonGroupItems(ctx context.Context, tx *sql.Tx, groupID int, addonIDs []int) error {
+	q := "DELETE FROM addon_group_items WHERE addon_group_id = $1"
+	if _, err := tx.ExecContext(ctx, q, groupID); err != nil {
+		return err
+	}
+	q = "INSERT INTO addon_group_items (addon_group_id, addon_id, position) VALUES ($1, $2, $3)"
+	for position, addonID := range addonIDs {
+		if _, err := tx.ExecContext(ctx, q, groupID, addonID, position); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// scanAddonGroups fold the joined group-addon rows, addon columns
+// are null for a group without items
+func scanAddonGroups(rows *sql.Rows) (data []*model.AddonGroup, err error) {
+	groups := make(map[int]*model.AddonGroup)
+	for rows.Next() {
+		var group model.AddonGroup
+		var groupDescription, addonName, addonDescription sql.NullString
+		var addonID sql.NullInt64
+		var addonPrice money.Amount
+		if err := rows.Scan(
+			&group.ID, &group.Name, &groupDescription,
+			&group.MinSelect, &group.MaxSelect,
+			&addonID, &addonName, &addonDescription, &addonPrice,
+		); err != nil {
+			return nil, err
+		}
+
+		current, ok := groups[group.ID]
+		if !ok {
+			group.Description = groupDescription.String
+			group.AddonIDs = []int{}
+			group.Addons = []*model.Addon{}
+			current = &group
+			groups[group.ID] = current
+			data = append(data, current)
+		}
+		if addonID.Valid {
+			current.AddonIDs = append(current.AddonIDs, int(addonID.Int64))
+			current.Addons = append(current.Addons, &model.Addon{
+				ID:          int(addonID.Int64),
+				Name:        addonName.String,
+				Description: addonDescription.String,
+				Price:       addonPrice,
+			})
+		}
+	}
+
+	return data, rows.Err()
+}
+
+// nullID store zero id as NULL for optional foreign key
+func nullID(id int) sql.NullInt64 {
+	return sql.NullInt64{Int64: int64(id), Valid: id > 0}
+}
+
+func NewAddonGroupSQLRepository() model.IAddonGroupRepository {
+	return &AddonGroupSQLRepository{Db: config.DbPool}
+}
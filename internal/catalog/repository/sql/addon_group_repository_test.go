@@ -0,0 +1,251 @@
+package sql_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/aasumitro/posbe/config"
+	repoSql "github.com/aasumitro/posbe/internal/catalog/repository/sql"
+	"github.com/aasumitro/posbe/pkg/dbtest"
+	"github.com/aasumitro/posbe/pkg/dialect"
+	"github.com/aasumitro/posbe/pkg/model"
+	"github.com/aasumitro/posbe/pkg/money"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+var addonGroupRowColumns = []string{
+	"id", "name", "description", "min_select", "max_select",
+	"addon_id", "addon_name", "addon_description", "addon_price",
+}
+
+type addonGroupRepositoryTestSuite struct {
+	suite.Suite
+	mock sqlmock.Sqlmock
+	repo model.IAddonGroupRepository
+}
+
+func (suite *addonGroupRepositoryTestSuite) SetupSuite() {
+	var err error
+	config.DbPool, suite.mock, err = sqlmock.New(
+		sqlmock.QueryMatcherOption(
+			sqlmock.QueryMatcherRegexp))
+	require.NoError(suite.T(), err)
+	suite.repo = repoSql.NewAddonGroupSQLRepository()
+}
+
+func (suite *addonGroupRepositoryTestSuite) AfterTest(_, _ string) {
+	require.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+func (suite *addonGroupRepositoryTestSuite) TestRepository_All_ExpectReturnRows() {
+	data := suite.mock.NewRows(addonGroupRowColumns).
+		AddRow(1, "milk", nil, 1, 1, 1, "oat", "oat milk", 5000).
+		AddRow(1, "milk", nil, 1, 1, 2, "soy", "soy milk", 4000).
+		AddRow(2, "toppings", "extra", 0, 3, nil, nil, nil, nil)
+	suite.mock.ExpectQuery("SELECT (.+) FROM addon_groups AS g").WillReturnRows(data)
+	res, err := suite.repo.All(context.TODO())
+	require.NoError(suite.T(), err)
+	require.Len(suite.T(), res, 2)
+	require.Equal(suite.T(), []int{1, 2}, res[0].AddonIDs)
+	require.Len(suite.T(), res[0].Addons, 2)
+	require.Empty(suite.T(), res[1].Addons)
+	require.Equal(suite.T(), "extra", res[1].Description)
+}
+
+func (suite *addonGroupRepositoryTestSuite) TestRepository_All_ExpectReturnErrorFromQuery() {
+	suite.mock.ExpectQuery("SELECT (.+) FROM addon_groups AS g").
+		WillReturnError(errors.New("UNEXPECTED"))
+	res, err := suite.repo.All(context.TODO())
+	require.NotNil(suite.T(), err)
+	require.Nil(suite.T(), res)
+}
+
+func (suite *addonGroupRepositoryTestSuite) TestRepository_All_ExpectReturnErrorFromScan() {
+	data := suite.mock.NewRows(addonGroupRowColumns).
+		AddRow(nil, nil, nil, nil, nil, nil, nil, nil, nil)
+	suite.mock.ExpectQuery("SELECT (.+) FROM addon_groups AS g").WillReturnRows(data)
+	res, err := suite.repo.All(context.TODO())
+	require.NotNil(suite.T(), err)
+	require.Nil(suite.T(), res)
+}
+
+func (suite *addonGroupRepositoryTestSuite) TestRepository_Find_ExpectReturnRow() {
+	data := suite.mock.NewRows(addonGroupRowColumns).
+		AddRow(1, "milk", nil, 1, 1, 1, "oat", "oat milk", 5000)
+	suite.mock.ExpectQuery("SELECT (.+) WHERE g.id = \\$1").
+		WithArgs(1).WillReturnRows(data)
+	res, err := suite.repo.Find(context.TODO(), model.FindWithID, 1)
+	require.NoError(suite.T(), err)
+	require.Equal(suite.T(), "milk", res.Name)
+}
+
+func (suite *addonGroupRepositoryTestSuite) TestRepository_Find_ExpectReturnErrorNoRows() {
+	suite.mock.ExpectQuery("SELECT (.+) WHERE g.id = \\$1").
+		WithArgs(1).WillReturnRows(suite.mock.NewRows(addonGroupRowColumns))
+	res, err := suite.repo.Find(context.TODO(), model.FindWithID, 1)
+	require.Nil(suite.T(), res)
+	require.ErrorContains(suite.T(), err, "no rows")
+}
+
+func (suite *addonGroupRepositoryTestSuite) TestRepository_Create_ExpectReturnRow() {
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectQuery("INSERT INTO addon_groups").
+		WithArgs("milk", "", 1, 1).
+		WillReturnRows(suite.mock.NewRows([]string{"id"}).AddRow(1))
+	suite.mock.ExpectExec("DELETE FROM addon_group_items").
+		WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 0))
+	suite.mock.ExpectExec("INSERT INTO addon_group_items").
+		WithArgs(1, 1, 0).WillReturnResult(sqlmock.NewResult(0, 1))
+	suite.mock.ExpectExec("INSERT INTO addon_group_items").
+		WithArgs(1, 2, 1).WillReturnResult(sqlmock.NewResult(0, 1))
+	suite.mock.ExpectCommit()
+	data := suite.mock.NewRows(addonGroupRowColumns).
+		AddRow(1, "milk", nil, 1, 1, 1, "oat", "oat milk", 5000).
+		AddRow(1, "milk", nil, 1, 1, 2, "soy", "soy milk", 4000)
+	suite.mock.ExpectQuery("SELECT (.+) WHERE g.id = \\$1").
+		WithArgs(1).WillReturnRows(data)
+	res, err := suite.repo.Create(context.TODO(), &model.AddonGroup{
+		Name: "milk", MinSelect: 1, MaxSelect: 1, AddonIDs: []int{1, 2}})
+	require.NoError(suite.T(), err)
+	require.Equal(suite.T(), 1, res.ID)
+}
+
+func (suite *addonGroupRepositoryTestSuite) TestRepository_Create_ExpectRollbackOnItemError() {
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectQuery("INSERT INTO addon_groups").
+		WillReturnRows(suite.mock.NewRows([]string{"id"}).AddRow(1))
+	suite.mock.ExpectExec("DELETE FROM addon_group_items").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	suite.mock.ExpectExec("INSERT INTO addon_group_items").
+		WillReturnError(errors.New("fk_addons_addon_group_items"))
+	suite.mock.ExpectRollback()
+	res, err := suite.repo.Create(context.TODO(), &model.AddonGroup{
+		Name: "milk", AddonIDs: []int{99}})
+	require.Nil(suite.T(), res)
+	require.NotNil(suite.T(), err)
+}
+
+func (suite *addonGroupRepositoryTestSuite) TestRepository_Update_ExpectReturnRow() {
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectExec("UPDATE addon_groups SET").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	suite.mock.ExpectExec("DELETE FROM addon_group_items").
+		WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 1))
+	suite.mock.ExpectExec("INSERT INTO addon_group_items").
+		WithArgs(1, 2, 0).WillReturnResult(sqlmock.NewResult(0, 1))
+	suite.mock.ExpectCommit()
+	data := suite.mock.NewRows(addonGroupRowColumns).
+		AddRow(1, "milk", nil, 0, 1, 2, "soy", "soy milk", 4000)
+	suite.mock.ExpectQuery("SELECT (.+) WHERE g.id = \\$1").
+		WithArgs(1).WillReturnRows(data)
+	res, err := suite.repo.Update(context.TODO(), &model.AddonGroup{
+		ID: 1, Name: "milk", MaxSelect: 1, AddonIDs: []int{2}})
+	require.NoError(suite.T(), err)
+	require.Equal(suite.T(), []int{2}, res.AddonIDs)
+}
+
+func (suite *addonGroupRepositoryTestSuite) TestRepository_Update_ExpectReturnErrorNoRows() {
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectExec("UPDATE addon_groups SET").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	suite.mock.ExpectRollback()
+	res, err := suite.repo.Update(context.TODO(), &model.AddonGroup{ID: 1, AddonIDs: []int{2}})
+	require.Nil(suite.T(), res)
+	require.ErrorContains(suite.T(), err, "no rows")
+}
+
+func (suite *addonGroupRepositoryTestSuite) TestRepository_Delete_ExpectSuccess() {
+	suite.mock.ExpectExec("DELETE FROM addon_groups WHERE id = \\$1").
+		WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 1))
+	err := suite.repo.Delete(context.TODO(), &model.AddonGroup{ID: 1})
+	require.NoError(suite.T(), err)
+}
+
+func (suite *addonGroupRepositoryTestSuite) TestRepository_Assign_ExpectNullForUnusedTarget() {
+	suite.mock.ExpectExec("INSERT INTO addon_group_assignments").
+		WithArgs(1, 3, nil).WillReturnResult(sqlmock.NewResult(0, 1))
+	err := suite.repo.Assign(context.TODO(), &model.AddonGroupAssignment{
+		AddonGroupID: 1, ProductID: 3})
+	require.NoError(suite.T(), err)
+}
+
+func (suite *addonGroupRepositoryTestSuite) TestRepository_Unassign_ExpectSuccess() {
+	suite.mock.ExpectExec("DELETE FROM addon_group_assignments").
+		WithArgs(1, nil, 2).WillReturnResult(sqlmock.NewResult(0, 1))
+	err := suite.repo.Unassign(context.TODO(), &model.AddonGroupAssignment{
+		AddonGroupID: 1, CategoryID: 2})
+	require.NoError(suite.T(), err)
+}
+
+func (suite *addonGroupRepositoryTestSuite) TestRepository_ProductGroups_ExpectReturnRows() {
+	data := suite.mock.NewRows(addonGroupRowColumns).
+		AddRow(1, "milk", nil, 1, 1, 1, "oat", "oat milk", 3000)
+	suite.mock.ExpectQuery("COALESCE\\(pap.price, a.price\\)(.+)JOIN products AS p ON p.id = \\$1").
+		WithArgs(3).WillReturnRows(data)
+	res, err := suite.repo.ProductGroups(context.TODO(), 3)
+	require.NoError(suite.T(), err)
+	require.Equal(suite.T(), money.Amount(3000), res[0].Addons[0].Price)
+}
+
+func (suite *addonGroupRepositoryTestSuite) TestRepository_ProductGroups_ExpectReturnErrorFromQuery() {
+	suite.mock.ExpectQuery("JOIN products AS p ON p.id = \\$1").
+		WillReturnError(errors.New("UNEXPECTED"))
+	res, err := suite.repo.ProductGroups(context.TODO(), 3)
+	require.Nil(suite.T(), res)
+	require.NotNil(suite.T(), err)
+}
+
+func (suite *addonGroupRepositoryTestSuite) TestRepository_SetProductPrice_ExpectSuccess() {
+	suite.mock.ExpectExec("INSERT INTO product_addon_prices (.+) ON CONFLICT").
+		WithArgs(3, 1, int64(3000), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	err := suite.repo.SetProductPrice(context.TODO(), &model.ProductAddonPrice{
+		ProductID: 3, AddonID: 1, Price: 3000})
+	require.NoError(suite.T(), err)
+}
+
+func (suite *addonGroupRepositoryTestSuite) TestRepository_DeleteProductPrice_ExpectSuccess() {
+	suite.mock.ExpectExec("DELETE FROM product_addon_prices").
+		WithArgs(3, 1).WillReturnResult(sqlmock.NewResult(0, 1))
+	err := suite.repo.DeleteProductPrice(context.TODO(), &model.ProductAddonPrice{
+		ProductID: 3, AddonID: 1})
+	require.NoError(suite.T(), err)
+}
+
+func (suite *addonGroupRepositoryTestSuite) TestAddonGroupRepository_ProductGroups() {
+	dbtest.Migrated(suite.T(), config.Dialect.Name())
+	ctx := context.TODO()
+	repo := repoSql.NewAddonGroupSQLRepository()
+	group, err := repo.Create(ctx, &model.AddonGroup{Name: "milk", MinSelect: 0, MaxSelect: 1, AddonIDs: []int{1, 2}})
+	require.NoError(suite.T(), err)
+	group.AddonIDs = []int{1}
+	_, err = repo.Update(ctx, group)
+	require.NoError(suite.T(), err)
+	require.NoError(suite.T(), repo.Assign(ctx, &model.AddonGroupAssignment{AddonGroupID: group.ID, CategoryID: 2}))
+	require.NoError(suite.T(), repo.SetProductPrice(ctx, &model.ProductAddonPrice{ProductID: 1, AddonID: 1, Price: 80}))
+
+	groups, err := repo.ProductGroups(ctx, 1)
+	require.NoError(suite.T(), err)
+	require.Len(suite.T(), groups, 1)
+	require.Len(suite.T(), groups[0].Addons, 1)
+	require.Equal(suite.T(), money.Amount(80), groups[0].Addons[0].Price)
+
+	require.NoError(suite.T(), repo.DeleteProductPrice(ctx, &model.ProductAddonPrice{ProductID: 1, AddonID: 1}))
+	require.NoError(suite.T(), repo.Unassign(ctx, &model.AddonGroupAssignment{AddonGroupID: group.ID, CategoryID: 2}))
+	groups, err = repo.ProductGroups(ctx, 1)
+	require.NoError(suite.T(), err)
+	require.Empty(suite.T(), groups)
+	require.NoError(suite.T(), repo.Delete(ctx, group))
+}
+
+func TestAddonGroupRepository(t *testing.T) {
+	for _, d := range dialect.All() {
+		t.Run(d.Name(), func(t *testing.T) {
+			config.Dialect = d
+			suite.Run(t, new(addonGroupRepositoryTestSuite))
+		})
+	}
+}
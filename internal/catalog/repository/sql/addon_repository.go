@@ -3,77 +3,100 @@ package sql
 import (
 	"context"
 	"database/sql"
+	"time"
 
 	"github.com/aasumitro/posbe/config"
+	"github.com/aasumitro/posbe/pkg/audit"
 	"github.com/aasumitro/posbe/pkg/model"
 )
 
+const addonColumns = "id, name, description, price, deleted_at, deleted_by"
+
 type AddonSQLRepository struct {
 	Db *sql.DB
 }
 
 func (repo AddonSQLRepository) All(ctx context.Context) (data []*model.Addon, err error) {
-	q := "SELECT * FROM addons"
-	rows, err := repo.Db.QueryContext(ctx, q)
+	q := "SELECT " + addonColumns + " FROM addons WHERE ($1 OR deleted_at IS NULL)"
+	rows, err := repo.Db.QueryContext(ctx, q, model.IncludeDeleted(ctx))
 	if err != nil {
 		return nil, err
 	}
 	defer func(rows *sql.Rows) { _ = rows.Close() }(rows)
 
 	for rows.Next() {
-		var addon model.Addon
-
-		if err := rows.Scan(
-			&addon.ID, &addon.Name,
-			&addon.Description, &addon.Price,
-		); err != nil {
+		addon, err := scanAddon(rows)
+		if err != nil {
 			return nil, err
 		}
 
-		data = append(data, &addon)
+		data = append(data, addon)
 	}
 
 	return data, nil
 }
 
 func (repo AddonSQLRepository) Find(ctx context.Context, _ model.FindWith, val any) (data *model.Addon, err error) {
-	q := "SELECT * FROM addons WHERE id = $1 LIMIT 1"
-	row := repo.Db.QueryRowContext(ctx, q, val)
+	q := "SELECT " + addonColumns + " FROM addons WHERE id = $1 AND ($2 OR deleted_at IS NULL) LIMIT 1"
+	row := repo.Db.QueryRowContext(ctx, q, val, model.IncludeDeleted(ctx))
 
-	data = &model.Addon{}
-	if err := row.Scan(
-		&data.ID, &data.Name,
-		&data.Description, &data.Price,
-	); err != nil {
+	return scanAddon(row)
+}
+
+func (repo AddonSQLRepository) Create(ctx context.Context, params *model.Addon) (data *model.Addon, err error) {
+	q := "INSERT INTO addons (name, description, price) VALUES ($1, $2, $3) RETURNING " + addonColumns
+	if err := audit.Versioned(ctx, repo.Db, func(tx *sql.Tx) error {
+		row := tx.QueryRowContext(ctx, q, params.Name, params.Description, params.Price)
+		data, err = scanAddon(row)
+		return err
+	}); err != nil {
 		return nil, err
 	}
 
 	return data, nil
 }
 
-func (repo AddonSQLRepository) Create(ctx context.Context, params *model.Addon) (data *model.Addon, err error) {
-	q := "INSERT INTO addons (name, description, price) VALUES ($1, $2, $3) RETURNING *"
-	row := repo.Db.QueryRowContext(ctx, q, params.Name, params.Description, params.Price)
-
-	data = &model.Addon{}
-	if err := row.Scan(
-		&data.ID, &data.Name,
-		&data.Description, &data.Price,
-	); err != nil {
+func (repo AddonSQLRepository) Update(ctx context.Context, params *model.Addon) (data *model.Addon, err error) {
+	q := "UPDATE addons SET name = $1, description = $2, price = $3 "
+	q += "WHERE id = $4 AND deleted_at IS NULL RETURNING " + addonColumns
+	if err := audit.Versioned(ctx, repo.Db, func(tx *sql.Tx) error {
+		row := tx.QueryRowContext(ctx, q, params.Name, params.Description, params.Price, params.ID)
+		data, err = scanAddon(row)
+		return err
+	}); err != nil {
 		return nil, err
 	}
 
 	return data, nil
 }
 
-func (repo AddonSQLRepository) Update(ctx context.Context, params *model.Addon) (data *model.Addon, err error) {
-	q := "UPDATE addons SET name = $1, description = $2, price = $3 WHERE id = $4 RETURNING *"
-	row := repo.Db.QueryRowContext(ctx, q, params.Name, params.Description, params.Price, params.ID)
+func (repo AddonSQLRepository) Delete(ctx context.Context, params *model.Addon) error {
+	q := "UPDATE addons SET deleted_at = $1, deleted_by = $2 WHERE id = $3 AND deleted_at IS NULL"
+	return audit.Versioned(ctx, repo.Db, func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, q, time.Now().Unix(), audit.NullActor(ctx), params.ID)
+		return err
+	})
+}
 
+func (repo AddonSQLRepository) Restore(ctx context.Context, params *model.Addon) (data *model.Addon, err error) {
+	q := "UPDATE addons SET deleted_at = NULL, deleted_by = NULL "
+	q += "WHERE id = $1 AND deleted_at IS NOT NULL RETURNING " + addonColumns
+	if err := audit.Versioned(ctx, repo.Db, func(tx *sql.Tx) error {
+		data, err = scanAddon(tx.QueryRowContext(ctx, q, params.ID))
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+func scanAddon(row scanner) (data *model.Addon, err error) {
 	data = &model.Addon{}
 	if err := row.Scan(
 		&data.ID, &data.Name,
 		&data.Description, &data.Price,
+		&data.DeletedAt, &data.DeletedBy,
 	); err != nil {
 		return nil, err
 	}
@@ -81,12 +104,6 @@ func (repo AddonSQLRepository) Update(ctx context.Context, params *model.Addon)
 	return data, nil
 }
 
-func (repo AddonSQLRepository) Delete(ctx context.Context, params *model.Addon) error {
-	q := "DELETE FROM addons WHERE id = $1"
-	_, err := repo.Db.ExecContext(ctx, q, params.ID)
-	return err
-}
-
-func NewAddonSQLRepository() model.ICRUDRepository[model.Addon] {
-	return &AddonSQLRepository{Db: config.PostgresPool}
+func NewAddonSQLRepository() model.ISoftDeleteRepository[model.Addon] {
+	return &AddonSQLRepository{Db: config.DbPool}
 }
@@ -2,6 +2,7 @@ package sql_test
 
 import (
 	"context"
+	"database/sql"
 	"errors"
 	"regexp"
 	"testing"
@@ -9,7 +10,10 @@ import (
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/aasumitro/posbe/config"
 	repoSql "github.com/aasumitro/posbe/internal/catalog/repository/sql"
+	"github.com/aasumitro/posbe/pkg/dbtest"
+	"github.com/aasumitro/posbe/pkg/dialect"
 	"github.com/aasumitro/posbe/pkg/model"
+	"github.com/aasumitro/posbe/pkg/money"
 	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
 )
@@ -17,12 +21,12 @@ import (
 type addonRepositoryTestSuite struct {
 	suite.Suite
 	mock sqlmock.Sqlmock
-	repo model.ICRUDRepository[model.Addon]
+	repo model.ISoftDeleteRepository[model.Addon]
 }
 
 func (suite *addonRepositoryTestSuite) SetupSuite() {
 	var err error
-	config.PostgresPool, suite.mock, err = sqlmock.New(
+	config.DbPool, suite.mock, err = sqlmock.New(
 		sqlmock.QueryMatcherOption(
 			sqlmock.QueryMatcherRegexp))
 	require.NoError(suite.T(), err)
@@ -35,10 +39,10 @@ func (suite *addonRepositoryTestSuite) AfterTest(_, _ string) {
 
 func (suite *addonRepositoryTestSuite) TestRepository_All_ExpectReturnRows() {
 	data := suite.mock.
-		NewRows([]string{"id", "name", "description", "price"}).
-		AddRow(1, "test", "test", 1).
-		AddRow(2, "test 2", "test 2", 1)
-	query := "SELECT * FROM addons"
+		NewRows([]string{"id", "name", "description", "price", "deleted_at", "deleted_by"}).
+		AddRow(1, "test", "test", 1, nil, nil).
+		AddRow(2, "test 2", "test 2", 1, nil, nil)
+	query := "SELECT id, name, description, price, deleted_at, deleted_by FROM addons WHERE ($1 OR deleted_at IS NULL)"
 	meta := regexp.QuoteMeta(query)
 	suite.mock.ExpectQuery(meta).WillReturnRows(data)
 	res, err := suite.repo.All(context.TODO())
@@ -48,7 +52,7 @@ func (suite *addonRepositoryTestSuite) TestRepository_All_ExpectReturnRows() {
 }
 
 func (suite *addonRepositoryTestSuite) TestRepository_All_ExpectReturnErrorFromQuery() {
-	query := "SELECT * FROM addons"
+	query := "SELECT id, name, description, price, deleted_at, deleted_by FROM addons WHERE ($1 OR deleted_at IS NULL)"
 	meta := regexp.QuoteMeta(query)
 	suite.mock.ExpectQuery(meta).WillReturnError(errors.New(""))
 	res, err := suite.repo.All(context.TODO())
@@ -58,10 +62,10 @@ func (suite *addonRepositoryTestSuite) TestRepository_All_ExpectReturnErrorFromQ
 
 func (suite *addonRepositoryTestSuite) TestRepository_All_ExpectReturnErrorFromScan() {
 	data := suite.mock.
-		NewRows([]string{"id", "name", "description", "price"}).
-		AddRow(1, "test", "test", 1).
-		AddRow(nil, nil, nil, nil)
-	query := "SELECT * FROM addons"
+		NewRows([]string{"id", "name", "description", "price", "deleted_at", "deleted_by"}).
+		AddRow(1, "test", "test", 1, nil, nil).
+		AddRow(nil, nil, nil, nil, nil, nil)
+	query := "SELECT id, name, description, price, deleted_at, deleted_by FROM addons WHERE ($1 OR deleted_at IS NULL)"
 	meta := regexp.QuoteMeta(query)
 	suite.mock.ExpectQuery(meta).WillReturnRows(data)
 	res, err := suite.repo.All(context.TODO())
@@ -71,9 +75,9 @@ func (suite *addonRepositoryTestSuite) TestRepository_All_ExpectReturnErrorFromS
 
 func (suite *addonRepositoryTestSuite) TestRepository_Find_ExpectReturnRow() {
 	data := suite.mock.
-		NewRows([]string{"id", "name", "description", "price"}).
-		AddRow(1, "test", "test", 1)
-	query := "SELECT * FROM addons WHERE id = $1 LIMIT 1"
+		NewRows([]string{"id", "name", "description", "price", "deleted_at", "deleted_by"}).
+		AddRow(1, "test", "test", 1, nil, nil)
+	query := "SELECT id, name, description, price, deleted_at, deleted_by FROM addons WHERE id = $1 AND ($2 OR deleted_at IS NULL) LIMIT 1"
 	meta := regexp.QuoteMeta(query)
 	suite.mock.ExpectQuery(meta).WillReturnRows(data)
 	res, err := suite.repo.Find(context.TODO(), model.FindWithID, 1)
@@ -84,9 +88,9 @@ func (suite *addonRepositoryTestSuite) TestRepository_Find_ExpectReturnRow() {
 
 func (suite *addonRepositoryTestSuite) TestRepository_Find_ExpectReturnError() {
 	data := suite.mock.
-		NewRows([]string{"id", "name", "description", "price"}).
-		AddRow(nil, nil, nil, nil)
-	query := "SELECT * FROM addons WHERE id = $1 LIMIT 1"
+		NewRows([]string{"id", "name", "description", "price", "deleted_at", "deleted_by"}).
+		AddRow(nil, nil, nil, nil, nil, nil)
+	query := "SELECT id, name, description, price, deleted_at, deleted_by FROM addons WHERE id = $1 AND ($2 OR deleted_at IS NULL) LIMIT 1"
 	meta := regexp.QuoteMeta(query)
 	suite.mock.ExpectQuery(meta).WillReturnRows(data)
 	res, err := suite.repo.Find(context.TODO(), model.FindWithID, 1)
@@ -97,14 +101,16 @@ func (suite *addonRepositoryTestSuite) TestRepository_Find_ExpectReturnError() {
 func (suite *addonRepositoryTestSuite) TestRepository_Created_ExpectSuccess() {
 	addon := &model.Addon{ID: 1, Name: "test", Description: "test", Price: 1}
 	data := suite.mock.
-		NewRows([]string{"id", "name", "description", "price"}).
-		AddRow(1, "test", "test", 1)
-	query := "INSERT INTO addons (name, description, price) VALUES ($1, $2, $3) RETURNING *"
+		NewRows([]string{"id", "name", "description", "price", "deleted_at", "deleted_by"}).
+		AddRow(1, "test", "test", 1, nil, nil)
+	query := "INSERT INTO addons (name, description, price) VALUES ($1, $2, $3) RETURNING id, name, description, price, deleted_at, deleted_by"
 	meta := regexp.QuoteMeta(query)
+	expectVersioned(suite.mock)
 	suite.mock.ExpectQuery(meta).
 		WithArgs(addon.Name, addon.Description, addon.Price).
 		WillReturnRows(data).
 		WillReturnError(nil)
+	expectVersionedCommit(suite.mock)
 	res, err := suite.repo.Create(context.TODO(), addon)
 	require.Nil(suite.T(), err)
 	require.NotNil(suite.T(), res)
@@ -113,14 +119,16 @@ func (suite *addonRepositoryTestSuite) TestRepository_Created_ExpectSuccess() {
 func (suite *addonRepositoryTestSuite) TestRepository_Created_ExpectError() {
 	addon := &model.Addon{ID: 1, Name: "test", Description: "test", Price: 1}
 	data := suite.mock.
-		NewRows([]string{"id", "name", "description", "price"}).
-		AddRow(1, nil, nil, nil)
-	query := "INSERT INTO addons (name, description, price) VALUES ($1, $2, $3) RETURNING *"
+		NewRows([]string{"id", "name", "description", "price", "deleted_at", "deleted_by"}).
+		AddRow(1, nil, nil, nil, nil, nil)
+	query := "INSERT INTO addons (name, description, price) VALUES ($1, $2, $3) RETURNING id, name, description, price, deleted_at, deleted_by"
 	meta := regexp.QuoteMeta(query)
+	expectVersioned(suite.mock)
 	suite.mock.ExpectQuery(meta).
 		WithArgs(addon.Name, addon.Description, addon.Price).
 		WillReturnRows(data).
 		WillReturnError(nil)
+	suite.mock.ExpectRollback()
 	res, err := suite.repo.Create(context.TODO(), addon)
 	require.Nil(suite.T(), res)
 	require.NotNil(suite.T(), err)
@@ -129,14 +137,16 @@ func (suite *addonRepositoryTestSuite) TestRepository_Created_ExpectError() {
 func (suite *addonRepositoryTestSuite) TestRepository_Updated_ExpectSuccess() {
 	addon := &model.Addon{ID: 1, Name: "test", Description: "test", Price: 1}
 	data := suite.mock.
-		NewRows([]string{"id", "name", "description", "price"}).
-		AddRow(1, "test", "test", 1)
-	query := "UPDATE addons SET name = $1, description = $2, price = $3 WHERE id = $4 RETURNING *"
+		NewRows([]string{"id", "name", "description", "price", "deleted_at", "deleted_by"}).
+		AddRow(1, "test", "test", 1, nil, nil)
+	query := "UPDATE addons SET name = $1, description = $2, price = $3 WHERE id = $4 AND deleted_at IS NULL RETURNING id, name, description, price, deleted_at, deleted_by"
 	meta := regexp.QuoteMeta(query)
+	expectVersioned(suite.mock)
 	suite.mock.ExpectQuery(meta).
 		WithArgs(addon.Name, addon.Description, addon.Price, addon.ID).
 		WillReturnRows(data).
 		WillReturnError(nil)
+	expectVersionedCommit(suite.mock)
 	res, err := suite.repo.Update(context.TODO(), addon)
 	require.Nil(suite.T(), err)
 	require.NotNil(suite.T(), res)
@@ -145,29 +155,69 @@ func (suite *addonRepositoryTestSuite) TestRepository_Updated_ExpectSuccess() {
 func (suite *addonRepositoryTestSuite) TestRepository_Updated_ExpectError() {
 	addon := &model.Addon{ID: 1, Name: "test", Description: "test", Price: 1}
 	data := suite.mock.
-		NewRows([]string{"id", "name", "description", "price"}).
-		AddRow(1, nil, nil, nil)
-	query := "UPDATE addons SET name = $1, description = $2, price = $3 WHERE id = $4 RETURNING *"
+		NewRows([]string{"id", "name", "description", "price", "deleted_at", "deleted_by"}).
+		AddRow(1, nil, nil, nil, nil, nil)
+	query := "UPDATE addons SET name = $1, description = $2, price = $3 WHERE id = $4 AND deleted_at IS NULL RETURNING id, name, description, price, deleted_at, deleted_by"
 	meta := regexp.QuoteMeta(query)
+	expectVersioned(suite.mock)
 	suite.mock.ExpectQuery(meta).
 		WithArgs(addon.Name, addon.Description, addon.Price, addon.ID).
 		WillReturnRows(data).
 		WillReturnError(nil)
+	suite.mock.ExpectRollback()
 	res, err := suite.repo.Update(context.TODO(), addon)
 	require.Nil(suite.T(), res)
 	require.NotNil(suite.T(), err)
 }
 
 func (suite *addonRepositoryTestSuite) TestRepository_Delete_ExpectSuccess() {
-	expectedQuery := regexp.QuoteMeta("DELETE FROM addons WHERE id = $1")
+	expectedQuery := regexp.QuoteMeta("UPDATE addons SET deleted_at = $1, deleted_by = $2 WHERE id = $3 AND deleted_at IS NULL")
+	expectVersioned(suite.mock)
 	suite.mock.ExpectExec(expectedQuery).
-		WithArgs(1).
+		WithArgs(sqlmock.AnyArg(), nil, 1).
 		WillReturnResult(sqlmock.NewResult(0, 1))
 	data := &model.Addon{ID: 1}
+	expectVersionedCommit(suite.mock)
 	err := suite.repo.Delete(context.TODO(), data)
 	require.Nil(suite.T(), err)
 }
 
+func (suite *addonRepositoryTestSuite) TestRepository_Restore_ExpectSuccess() {
+	data := suite.mock.
+		NewRows([]string{"id", "name", "description", "price", "deleted_at", "deleted_by"}).
+		AddRow(1, "test", "test", 1, nil, nil)
+	query := "UPDATE addons SET deleted_at = NULL, deleted_by = NULL WHERE id = $1 AND deleted_at IS NOT NULL RETURNING id, name, description, price, deleted_at, deleted_by"
+	meta := regexp.QuoteMeta(query)
+	expectVersioned(suite.mock)
+	suite.mock.ExpectQuery(meta).WithArgs(1).WillReturnRows(data)
+	expectVersionedCommit(suite.mock)
+	res, err := suite.repo.Restore(context.TODO(), &model.Addon{ID: 1})
+	require.Nil(suite.T(), err)
+	require.NotNil(suite.T(), res)
+}
+
+func (suite *addonRepositoryTestSuite) TestAddonRepository_SoftDelete() {
+	dbtest.Migrated(suite.T(), config.Dialect.Name())
+	ctx := context.TODO()
+	repo := repoSql.NewAddonSQLRepository()
+	addon, err := repo.Create(ctx, &model.Addon{Name: "sugar", Description: "extra", Price: 50})
+	require.NoError(suite.T(), err)
+	addon.Price = 75
+	addon, err = repo.Update(ctx, addon)
+	require.NoError(suite.T(), err)
+	require.Equal(suite.T(), money.Amount(75), addon.Price)
+	require.NoError(suite.T(), repo.Delete(ctx, addon))
+	_, err = repo.Find(ctx, model.FindWithID, addon.ID)
+	require.ErrorIs(suite.T(), err, sql.ErrNoRows)
+	_, err = repo.Restore(ctx, addon)
+	require.NoError(suite.T(), err)
+}
+
 func TestAddonRepository(t *testing.T) {
-	suite.Run(t, new(addonRepositoryTestSuite))
+	for _, d := range dialect.All() {
+		t.Run(d.Name(), func(t *testing.T) {
+			config.Dialect = d
+			suite.Run(t, new(addonRepositoryTestSuite))
+		})
+	}
 }
@@ -0,0 +1,163 @@
+package sql
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/aasumitro/posbe/config"
+	"github.com/aasumitro/posbe/pkg/model"
+)
+
+type AvailabilitySQLRepository struct {
+	Db *sql.DB
+}
+
+func (repo AvailabilitySQLRepository) Rules(ctx context.Context) (data []*model.AvailabilityRule, err error) {
+	q := "SELECT id, product_id, category_id, days, start_time, end_time "
+	q += "FROM availability_rules ORDER BY id"
+	rows, err := repo.Db.QueryContext(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+	defer func(rows *sql.Rows) { _ = rows.Close() }(rows)
+
+	for rows.Next() {
+		var rule model.AvailabilityRule
+		var productID, categoryID sql.NullInt64
+		var startTime, endTime sql.NullString
+		var days int
+		if err := rows.Scan(&rule.ID, &productID, &categoryID,
+			&days, &startTime, &endTime); err != nil {
+			return nil, err
+		}
+		rule.ProductID, rule.CategoryID = int(productID.Int64), int(categoryID.Int64)
+		rule.StartTime, rule.EndTime = startTime.String, endTime.String
+		rule.Days = maskDays(days)
+		data = append(data, &rule)
+	}
+
+	return data, rows.Err()
+}
+
+func (repo AvailabilitySQLRepository) ReplaceRules(ctx context.Context, form *model.AvailabilityRuleForm) error {
+	tx, err := repo.Db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	q := "DELETE FROM availability_rules WHERE product_id = $1"
+	target := form.ProductID
+	if form.ProductID == 0 {
+		q = "DELETE FROM availability_rules WHERE category_id = $1"
+		target = form.CategoryID
+	}
+	if _, err := tx.ExecContext(ctx, q, target); err != nil {
+		return err
+	}
+	q = "INSERT INTO availability_rules (product_id, category_id, days, start_time, end_time) "
+	q += "VALUES ($1, $2, $3, $4, $5)"
+	for _, rule := range form.Rules {
+		if _, err := tx.ExecContext(ctx, q,
+			nullID(form.ProductID), nullID(form.CategoryID), daysMask(rule.Days),
+			nullString(rule.StartTime), nullString(rule.EndTime),
+		); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (repo AvailabilitySQLRepository) SoldOut(ctx context.Context) (data []*model.SoldOutItem, err error) {
+	q := "SELECT s.id, s.product_id, s.variant_id, s.store_shift_id, s.created_by, s.created_at "
+	q += "FROM sold_out_items AS s LEFT JOIN store_shifts AS ss ON ss.id = s.store_shift_id "
+	q += "WHERE s.store_shift_id IS NULL OR ss.close_at IS NULL ORDER BY s.id"
+	rows, err := repo.Db.QueryContext(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+	defer func(rows *sql.Rows) { _ = rows.Close() }(rows)
+
+	for rows.Next() {
+		var item model.SoldOutItem
+		var variantID, storeShiftID, createdBy sql.NullInt64
+		if err := rows.Scan(&item.ID, &item.ProductID, &variantID,
+			&storeShiftID, &createdBy, &item.CreatedAt); err != nil {
+			return nil, err
+		}
+		item.VariantID = int(variantID.Int64)
+		item.StoreShiftID = int(storeShiftID.Int64)
+		item.CreatedBy = int(createdBy.Int64)
+		data = append(data, &item)
+	}
+
+	return data, rows.Err()
+}
+
+// MarkSoldOut attach the item to the open store shift, marking an
+// expired item again start it over for the current shift
+func (repo AvailabilitySQLRepository) MarkSoldOut(
+	ctx context.Context,
+	item *model.SoldOutItem,
+) (data *model.SoldOutItem, err error) {
+	q := "INSERT INTO sold_out_items (product_id, variant_id, store_shift_id, created_by, created_at) "
+	q += "VALUES ($1, $2, (SELECT id FROM store_shifts WHERE close_at IS NULL "
+	q += "ORDER BY open_at DESC LIMIT 1), $3, $4) "
+	q += "ON CONFLICT (product_id, (COALESCE(variant_id, 0))) DO UPDATE SET "
+	q += "store_shift_id = EXCLUDED.store_shift_id, created_by = EXCLUDED.created_by, "
+	q += "created_at = EXCLUDED.created_at RETURNING id, store_shift_id"
+	data = &model.SoldOutItem{
+		ProductID: item.ProductID, VariantID: item.VariantID,
+		CreatedBy: item.CreatedBy, CreatedAt: time.Now().Unix(),
+	}
+	var storeShiftID sql.NullInt64
+	if err := repo.Db.QueryRowContext(ctx, q,
+		data.ProductID, nullID(data.VariantID), nullID(data.CreatedBy), data.CreatedAt,
+	).Scan(&data.ID, &storeShiftID); err != nil {
+		return nil, err
+	}
+	data.StoreShiftID = int(storeShiftID.Int64)
+
+	return data, nil
+}
+
+func (repo AvailabilitySQLRepository) ClearSoldOut(ctx context.Context, item *model.SoldOutItem) error {
+	q := "DELETE FROM sold_out_items WHERE product_id = $1 AND COALESCE(variant_id, 0) = $2"
+	result, err := repo.Db.ExecContext(ctx, q, item.ProductID, item.VariantID)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func (repo AvailabilitySQLRepository) ProductCategories(ctx context.Context) (categories map[int]int, err error) {
+	rows, err := repo.Db.QueryContext(ctx, "SELECT id, category_id FROM products")
+	if err != nil {
+		return nil, err
+	}
+	defer func(rows *sql.Rows) { _ = rows.Close() }(rows)
+
+	categories = make(map[int]int)
+	for rows.Next() {
+		var id, categoryID int
+		if err := rows.Scan(&id, &categoryID); err != nil {
+			return nil, err
+		}
+		categories[id] = categoryID
+	}
+
+	return categories, rows.Err()
+}
+
+func NewAvailabilitySQLRepository() model.IAvailabilityRepository {
+	return &AvailabilitySQLRepository{Db: config.DbPool}
+}
@@ -0,0 +1,167 @@
+package sql_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/aasumitro/posbe/config"
+	repoSql "github.com/aasumitro/posbe/internal/catalog/repository/sql"
+	"github.com/aasumitro/posbe/pkg/dbtest"
+	"github.com/aasumitro/posbe/pkg/dialect"
+	"github.com/aasumitro/posbe/pkg/model"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type availabilityRepositoryTestSuite struct {
+	suite.Suite
+	mock sqlmock.Sqlmock
+	repo model.IAvailabilityRepository
+}
+
+func (suite *availabilityRepositoryTestSuite) SetupSuite() {
+	var err error
+	config.DbPool, suite.mock, err = sqlmock.New(
+		sqlmock.QueryMatcherOption(
+			sqlmock.QueryMatcherRegexp))
+	require.NoError(suite.T(), err)
+	suite.repo = repoSql.NewAvailabilitySQLRepository()
+}
+
+func (suite *availabilityRepositoryTestSuite) AfterTest(_, _ string) {
+	require.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+func (suite *availabilityRepositoryTestSuite) TestRepository_Rules_ExpectReturnRows() {
+	suite.mock.ExpectQuery("FROM availability_rules ORDER BY id").
+		WillReturnRows(suite.mock.NewRows([]string{
+			"id", "product_id", "category_id", "days", "start_time", "end_time"}).
+			AddRow(1, 1, nil, 0, "07:00", "11:00").
+			AddRow(2, nil, 2, 65, nil, nil))
+	res, err := suite.repo.Rules(context.TODO())
+	require.NoError(suite.T(), err)
+	require.Len(suite.T(), res, 2)
+	require.Equal(suite.T(), "07:00", res[0].StartTime)
+	require.Empty(suite.T(), res[0].Days)
+	require.Equal(suite.T(), 2, res[1].CategoryID)
+	require.Equal(suite.T(), []int{0, 6}, res[1].Days)
+}
+
+func (suite *availabilityRepositoryTestSuite) TestRepository_Rules_ExpectReturnError() {
+	suite.mock.ExpectQuery("FROM availability_rules").
+		WillReturnError(errors.New("UNEXPECTED"))
+	res, err := suite.repo.Rules(context.TODO())
+	require.Nil(suite.T(), res)
+	require.NotNil(suite.T(), err)
+}
+
+func (suite *availabilityRepositoryTestSuite) TestRepository_ReplaceRules_ExpectSuccess() {
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectExec("DELETE FROM availability_rules WHERE category_id = \\$1").
+		WithArgs(2).WillReturnResult(sqlmock.NewResult(0, 1))
+	suite.mock.ExpectExec("INSERT INTO availability_rules").
+		WithArgs(nil, 2, 65, nil, nil).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	suite.mock.ExpectCommit()
+	err := suite.repo.ReplaceRules(context.TODO(), &model.AvailabilityRuleForm{
+		CategoryID: 2, Rules: []*model.AvailabilityRule{{Days: []int{0, 6}}}})
+	require.NoError(suite.T(), err)
+}
+
+func (suite *availabilityRepositoryTestSuite) TestRepository_ReplaceRules_ExpectRollback() {
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectExec("DELETE FROM availability_rules WHERE product_id = \\$1").
+		WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 0))
+	suite.mock.ExpectExec("INSERT INTO availability_rules").
+		WithArgs(1, nil, 0, "07:00", "11:00").
+		WillReturnError(errors.New("UNEXPECTED"))
+	suite.mock.ExpectRollback()
+	err := suite.repo.ReplaceRules(context.TODO(), &model.AvailabilityRuleForm{
+		ProductID: 1, Rules: []*model.AvailabilityRule{{StartTime: "07:00", EndTime: "11:00"}}})
+	require.NotNil(suite.T(), err)
+}
+
+func (suite *availabilityRepositoryTestSuite) TestRepository_SoldOut_ExpectReturnRows() {
+	suite.mock.ExpectQuery("FROM sold_out_items AS s LEFT JOIN store_shifts (.+) ss.close_at IS NULL").
+		WillReturnRows(suite.mock.NewRows([]string{
+			"id", "product_id", "variant_id", "store_shift_id", "created_by", "created_at"}).
+			AddRow(1, 1, nil, 3, 1, 1700000000).
+			AddRow(2, 2, 20, nil, nil, 1700000000))
+	res, err := suite.repo.SoldOut(context.TODO())
+	require.NoError(suite.T(), err)
+	require.Len(suite.T(), res, 2)
+	require.Equal(suite.T(), 3, res[0].StoreShiftID)
+	require.Equal(suite.T(), 20, res[1].VariantID)
+}
+
+func (suite *availabilityRepositoryTestSuite) TestRepository_MarkSoldOut_ExpectReturnRow() {
+	suite.mock.ExpectQuery("INSERT INTO sold_out_items (.+) ON CONFLICT").
+		WithArgs(1, nil, 5, sqlmock.AnyArg()).
+		WillReturnRows(suite.mock.NewRows([]string{"id", "store_shift_id"}).AddRow(1, 3))
+	res, err := suite.repo.MarkSoldOut(context.TODO(), &model.SoldOutItem{ProductID: 1, CreatedBy: 5})
+	require.NoError(suite.T(), err)
+	require.Equal(suite.T(), 1, res.ID)
+	require.Equal(suite.T(), 3, res.StoreShiftID)
+}
+
+func (suite *availabilityRepositoryTestSuite) TestRepository_ClearSoldOut_ExpectErrorNoRows() {
+	suite.mock.ExpectExec("DELETE FROM sold_out_items").WithArgs(1, 20).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	err := suite.repo.ClearSoldOut(context.TODO(), &model.SoldOutItem{ProductID: 1, VariantID: 20})
+	require.ErrorContains(suite.T(), err, "no rows")
+}
+
+func (suite *availabilityRepositoryTestSuite) TestRepository_ClearSoldOut_ExpectSuccess() {
+	suite.mock.ExpectExec("DELETE FROM sold_out_items").WithArgs(1, 0).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	err := suite.repo.ClearSoldOut(context.TODO(), &model.SoldOutItem{ProductID: 1})
+	require.NoError(suite.T(), err)
+}
+
+func (suite *availabilityRepositoryTestSuite) TestRepository_ProductCategories_ExpectReturnMap() {
+	suite.mock.ExpectQuery("SELECT id, category_id FROM products").
+		WillReturnRows(suite.mock.NewRows([]string{"id", "category_id"}).
+			AddRow(1, 1).AddRow(2, 2))
+	res, err := suite.repo.ProductCategories(context.TODO())
+	require.NoError(suite.T(), err)
+	require.Equal(suite.T(), map[int]int{1: 1, 2: 2}, res)
+}
+
+func (suite *availabilityRepositoryTestSuite) TestAvailabilityRepository_RulesAndSoldOut() {
+	dbtest.Migrated(suite.T(), config.Dialect.Name())
+	ctx := context.TODO()
+	repo := repoSql.NewAvailabilitySQLRepository()
+	require.NoError(suite.T(), repo.ReplaceRules(ctx, &model.AvailabilityRuleForm{
+		CategoryID: 1,
+		Rules:      []*model.AvailabilityRule{{Days: []int{0, 6}, StartTime: "07:00", EndTime: "11:00"}},
+	}))
+	rules, err := repo.Rules(ctx)
+	require.NoError(suite.T(), err)
+	require.Len(suite.T(), rules, 1)
+	require.Equal(suite.T(), []int{0, 6}, rules[0].Days)
+
+	item, err := repo.MarkSoldOut(ctx, &model.SoldOutItem{ProductID: 1, VariantID: 2})
+	require.NoError(suite.T(), err)
+	require.NotZero(suite.T(), item.ID)
+	soldOut, err := repo.SoldOut(ctx)
+	require.NoError(suite.T(), err)
+	require.Len(suite.T(), soldOut, 1)
+	require.NoError(suite.T(), repo.ClearSoldOut(ctx, item))
+	require.ErrorIs(suite.T(), repo.ClearSoldOut(ctx, item), sql.ErrNoRows)
+
+	categories, err := repo.ProductCategories(ctx)
+	require.NoError(suite.T(), err)
+	require.Equal(suite.T(), 2, categories[1])
+}
+
+func TestAvailabilityRepository(t *testing.T) {
+	for _, d := range dialect.All() {
+		t.Run(d.Name(), func(t *testing.T) {
+			config.Dialect = d
+			suite.Run(t, new(availabilityRepositoryTestSuite))
+		})
+	}
+}
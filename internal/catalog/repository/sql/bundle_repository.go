@@ -0,0 +1,193 @@
+package sql
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/aasumitro/posbe/config"
+	"github.com/aasumitro/posbe/pkg/model"
+	"github.com/aasumitro/posbe/pkg/money"
+)
+
+type BundleSQLRepository struct {
+	Db *sql.DB
+}
+
+func (repo BundleSQLRepository) Find(ctx context.Context, productID int) (data *model.Bundle, err error) {
+	data = &model.Bundle{
+		ProductID: productID,
+		Items:     []*model.BundleItem{},
+		Slots:     []*model.BundleSlot{},
+	}
+	if data.Items, err = repo.items(ctx, productID); err != nil {
+		return nil, err
+	}
+	if data.Slots, err = repo.slots(ctx, productID); err != nil {
+		return nil, err
+	}
+	if len(data.Items) == 0 && len(data.Slots) == 0 {
+		return nil, sql.ErrNoRows
+	}
+
+	return data, nil
+}
+
+func (repo BundleSQLRepository) Save(ctx context.Context, bundle *model.Bundle) (data *model.Bundle, err error) {
+	tx, err := repo.Db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if err := clearBundle(ctx, tx, bundle.ProductID); err != nil {
+		return nil, err
+	}
+	q := "INSERT INTO bundle_items (bundle_id, product_id, variant_id, qty, position) "
+	q += "VALUES ($1, $2, $3, $4, $5)"
+	for position, item := range bundle.Items {
+		if _, err := tx.ExecContext(ctx, q, bundle.ProductID, item.ProductID,
+			nullID(item.VariantID), item.Qty, position); err != nil {
+			return nil, err
+		}
+	}
+	for position, slot := range bundle.Slots {
+		q := "INSERT INTO bundle_slots (bundle_id, name, subcategory_id, qty, position) "
+		q += "VALUES ($1, $2, $3, $4, $5) RETURNING id"
+		var slotID int
+		if err := tx.QueryRowContext(ctx, q, bundle.ProductID, slot.Name,
+			nullID(slot.SubcategoryID), slot.Qty, position,
+		).Scan(&slotID); err != nil {
+			return nil, err
+		}
+		q = "INSERT INTO bundle_slot_options (slot_id, product_id, variant_id, upcharge, position) "
+		q += "VALUES ($1, $2, $3, $4, $5)"
+		for optionPosition, option := range slot.Options {
+			if _, err := tx.ExecContext(ctx, q, slotID, option.ProductID,
+				nullID(option.VariantID), option.Upcharge, optionPosition); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return repo.Find(ctx, bundle.ProductID)
+}
+
+func (repo BundleSQLRepository) Delete(ctx context.Context, productID int) error {
+	tx, err := repo.Db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if err := clearBundle(ctx, tx, productID); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (repo BundleSQLRepository) items(ctx context.Context, productID int) (data []*model.BundleItem, err error) {
+	q := "SELECT i.id, i.product_id, i.variant_id, i.qty, p.name, v.name FROM bundle_items AS i "
+	q += "JOIN products AS p ON p.id = i.product_id "
+	q += "LEFT JOIN product_variants AS v ON v.id = i.variant_id "
+	q += "WHERE i.bundle_id = $1 ORDER BY i.position"
+	rows, err := repo.Db.QueryContext(ctx, q, productID)
+	if err != nil {
+		return nil, err
+	}
+	defer func(rows *sql.Rows) { _ = rows.Close() }(rows)
+
+	data = []*model.BundleItem{}
+	for rows.Next() {
+		var item model.BundleItem
+		var variantID sql.NullInt64
+		var variantName sql.NullString
+		if err := rows.Scan(
+			&item.ID, &item.ProductID, &variantID,
+			&item.Qty, &item.Name, &variantName,
+		); err != nil {
+			return nil, err
+		}
+		item.VariantID = int(variantID.Int64)
+		item.Name = bundleComponentName(item.Name, variantName)
+		data = append(data, &item)
+	}
+
+	return data, rows.Err()
+}
+
+func (repo BundleSQLRepository) slots(ctx context.Context, productID int) (data []*model.BundleSlot, err error) {
+	q := "SELECT s.id, s.name, s.subcategory_id, s.qty, o.product_id, o.variant_id, "
+	q += "o.upcharge, p.name, v.name FROM bundle_slots AS s "
+	q += "LEFT JOIN bundle_slot_options AS o ON o.slot_id = s.id "
+	q += "LEFT JOIN products AS p ON p.id = o.product_id "
+	q += "LEFT JOIN product_variants AS v ON v.id = o.variant_id "
+	q += "WHERE s.bundle_id = $1 ORDER BY s.position, o.position"
+	rows, err := repo.Db.QueryContext(ctx, q, productID)
+	if err != nil {
+		return nil, err
+	}
+	defer func(rows *sql.Rows) { _ = rows.Close() }(rows)
+
+	data = []*model.BundleSlot{}
+	slots := make(map[int]*model.BundleSlot)
+	for rows.Next() {
+		var slot model.BundleSlot
+		var subcategoryID, optionProductID, optionVariantID sql.NullInt64
+		var upcharge money.Amount
+		var productName, variantName sql.NullString
+		if err := rows.Scan(
+			&slot.ID, &slot.Name, &subcategoryID, &slot.Qty,
+			&optionProductID, &optionVariantID, &upcharge,
+			&productName, &variantName,
+		); err != nil {
+			return nil, err
+		}
+
+		current, ok := slots[slot.ID]
+		if !ok {
+			slot.SubcategoryID = int(subcategoryID.Int64)
+			slot.Options = []*model.BundleSlotOption{}
+			current = &slot
+			slots[slot.ID] = current
+			data = append(data, current)
+		}
+		if optionProductID.Valid {
+			current.Options = append(current.Options, &model.BundleSlotOption{
+				ProductID: int(optionProductID.Int64),
+				VariantID: int(optionVariantID.Int64),
+				Upcharge:  upcharge,
+				Name:      bundleComponentName(productName.String, variantName),
+			})
+		}
+	}
+
+	return data, rows.Err()
+}
+
+// clearBundle remove items and slots, options follow the slot by cascade
+func clearBundle(ctx context.Context, tx *sql.Tx, productID int) error {
+	for _, q := range []string{
+		"DELETE FROM bundle_items WHERE bundle_id = $1",
+		"DELETE FROM bundle_slots WHERE bundle_id = $1",
+	} {
+		if _, err := tx.ExecContext(ctx, q, productID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func bundleComponentName(product string, variant sql.NullString) string {
+	if variant.Valid && variant.String != "" {
+		return product + " (" + variant.String + ")"
+	}
+	return product
+}
+
+func NewBundleSQLRepository() model.IBundleRepository {
+	return &BundleSQLRepository{Db: config.DbPool}
+}
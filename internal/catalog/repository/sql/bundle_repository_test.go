@@ -0,0 +1,165 @@
+package sql_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/aasumitro/posbe/config"
+	repoSql "github.com/aasumitro/posbe/internal/catalog/repository/sql"
+	"github.com/aasumitro/posbe/pkg/dbtest"
+	"github.com/aasumitro/posbe/pkg/dialect"
+	"github.com/aasumitro/posbe/pkg/model"
+	"github.com/aasumitro/posbe/pkg/money"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+var (
+	bundleItemColumns = []string{"id", "product_id", "variant_id", "qty", "name", "variant_name"}
+	bundleSlotColumns = []string{
+		"id", "name", "subcategory_id", "qty", "product_id",
+		"variant_id", "upcharge", "product_name", "variant_name",
+	}
+)
+
+type bundleRepositoryTestSuite struct {
+	suite.Suite
+	mock sqlmock.Sqlmock
+	repo model.IBundleRepository
+}
+
+func (suite *bundleRepositoryTestSuite) SetupSuite() {
+	var err error
+	config.DbPool, suite.mock, err = sqlmock.New(
+		sqlmock.QueryMatcherOption(
+			sqlmock.QueryMatcherRegexp))
+	require.NoError(suite.T(), err)
+	suite.repo = repoSql.NewBundleSQLRepository()
+}
+
+func (suite *bundleRepositoryTestSuite) AfterTest(_, _ string) {
+	require.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+func (suite *bundleRepositoryTestSuite) expectFind() {
+	suite.mock.ExpectQuery("FROM bundle_items AS i").WithArgs(10).
+		WillReturnRows(suite.mock.NewRows(bundleItemColumns).
+			AddRow(1, 2, 3, 1, "burger", "large"))
+	suite.mock.ExpectQuery("FROM bundle_slots AS s").WithArgs(10).
+		WillReturnRows(suite.mock.NewRows(bundleSlotColumns).
+			AddRow(1, "drink", 4, 1, 5, nil, 0, "tea", nil).
+			AddRow(1, "drink", 4, 1, 6, nil, 5000, "juice", nil).
+			AddRow(2, "side", nil, 1, nil, nil, nil, nil, nil))
+}
+
+func (suite *bundleRepositoryTestSuite) TestRepository_Find_ExpectReturnBundle() {
+	suite.expectFind()
+	res, err := suite.repo.Find(context.TODO(), 10)
+	require.NoError(suite.T(), err)
+	require.Equal(suite.T(), "burger (large)", res.Items[0].Name)
+	require.Len(suite.T(), res.Slots, 2)
+	require.Len(suite.T(), res.Slots[0].Options, 2)
+	require.Equal(suite.T(), money.Amount(5000), res.Slots[0].Options[1].Upcharge)
+	require.Empty(suite.T(), res.Slots[1].Options)
+}
+
+func (suite *bundleRepositoryTestSuite) TestRepository_Find_ExpectReturnErrorNotBundle() {
+	suite.mock.ExpectQuery("FROM bundle_items AS i").
+		WillReturnRows(suite.mock.NewRows(bundleItemColumns))
+	suite.mock.ExpectQuery("FROM bundle_slots AS s").
+		WillReturnRows(suite.mock.NewRows(bundleSlotColumns))
+	res, err := suite.repo.Find(context.TODO(), 10)
+	require.Nil(suite.T(), res)
+	require.ErrorContains(suite.T(), err, "no rows")
+}
+
+func (suite *bundleRepositoryTestSuite) TestRepository_Find_ExpectReturnErrorFromQuery() {
+	suite.mock.ExpectQuery("FROM bundle_items AS i").
+		WillReturnError(errors.New("UNEXPECTED"))
+	res, err := suite.repo.Find(context.TODO(), 10)
+	require.Nil(suite.T(), res)
+	require.NotNil(suite.T(), err)
+}
+
+func (suite *bundleRepositoryTestSuite) TestRepository_Save_ExpectReplaceAll() {
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectExec("DELETE FROM bundle_items").WithArgs(10).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	suite.mock.ExpectExec("DELETE FROM bundle_slots").WithArgs(10).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	suite.mock.ExpectExec("INSERT INTO bundle_items").
+		WithArgs(10, 2, 3, 1, 0).WillReturnResult(sqlmock.NewResult(1, 1))
+	suite.mock.ExpectQuery("INSERT INTO bundle_slots").
+		WithArgs(10, "drink", 4, 1, 0).
+		WillReturnRows(suite.mock.NewRows([]string{"id"}).AddRow(1))
+	suite.mock.ExpectExec("INSERT INTO bundle_slot_options").
+		WithArgs(1, 6, nil, int64(5000), 0).WillReturnResult(sqlmock.NewResult(1, 1))
+	suite.mock.ExpectCommit()
+	suite.expectFind()
+	res, err := suite.repo.Save(context.TODO(), &model.Bundle{
+		ProductID: 10,
+		Items:     []*model.BundleItem{{ProductID: 2, VariantID: 3, Qty: 1}},
+		Slots: []*model.BundleSlot{{Name: "drink", SubcategoryID: 4, Qty: 1,
+			Options: []*model.BundleSlotOption{{ProductID: 6, Upcharge: 5000}}}},
+	})
+	require.NoError(suite.T(), err)
+	require.NotNil(suite.T(), res)
+}
+
+func (suite *bundleRepositoryTestSuite) TestRepository_Save_ExpectRollbackOnError() {
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectExec("DELETE FROM bundle_items").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	suite.mock.ExpectExec("DELETE FROM bundle_slots").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	suite.mock.ExpectExec("INSERT INTO bundle_items").
+		WillReturnError(errors.New("fk_products_bundle_items"))
+	suite.mock.ExpectRollback()
+	res, err := suite.repo.Save(context.TODO(), &model.Bundle{
+		ProductID: 10, Items: []*model.BundleItem{{ProductID: 99, Qty: 1}}})
+	require.Nil(suite.T(), res)
+	require.NotNil(suite.T(), err)
+}
+
+func (suite *bundleRepositoryTestSuite) TestRepository_Delete_ExpectSuccess() {
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectExec("DELETE FROM bundle_items").WithArgs(10).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	suite.mock.ExpectExec("DELETE FROM bundle_slots").WithArgs(10).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	suite.mock.ExpectCommit()
+	err := suite.repo.Delete(context.TODO(), 10)
+	require.NoError(suite.T(), err)
+}
+
+func (suite *bundleRepositoryTestSuite) TestBundleRepository_Save() {
+	dbtest.Migrated(suite.T(), config.Dialect.Name())
+	ctx := context.TODO()
+	repo := repoSql.NewBundleSQLRepository()
+	bundle, err := repo.Save(ctx, &model.Bundle{
+		ProductID: 2,
+		Items:     []*model.BundleItem{{ProductID: 1, VariantID: 1, Qty: 1}},
+		Slots: []*model.BundleSlot{{Name: "drink", Qty: 1,
+			Options: []*model.BundleSlotOption{{ProductID: 1, Upcharge: 500}}}},
+	})
+	require.NoError(suite.T(), err)
+	bundle, err = repo.Find(ctx, bundle.ProductID)
+	require.NoError(suite.T(), err)
+	require.Len(suite.T(), bundle.Items, 1)
+	require.Len(suite.T(), bundle.Slots[0].Options, 1)
+	require.NoError(suite.T(), repo.Delete(ctx, 2))
+	_, err = repo.Find(ctx, 2)
+	require.ErrorIs(suite.T(), err, sql.ErrNoRows)
+}
+
+func TestBundleRepository(t *testing.T) {
+	for _, d := range dialect.All() {
+		t.Run(d.Name(), func(t *testing.T) {
+			config.Dialect = d
+			suite.Run(t, new(bundleRepositoryTestSuite))
+		})
+	}
+}
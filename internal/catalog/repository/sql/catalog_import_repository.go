@@ -0,0 +1,325 @@
+package sql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aasumitro/posbe/config"
+	"github.com/aasumitro/posbe/pkg/audit"
+	"github.com/aasumitro/posbe/pkg/model"
+)
+
+type (
+	CatalogImportSQLRepository struct {
+		Db *sql.DB
+	}
+
+	// queryer is implemented by both *sql.DB and *sql.Tx
+	queryer interface {
+		QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	}
+)
+
+func (repo CatalogImportSQLRepository) Snapshot(
+	ctx context.Context,
+) (snapshot *model.CatalogImportSnapshot, err error) {
+	return snapshotCatalog(ctx, repo.Db)
+}
+
+func (repo CatalogImportSQLRepository) Apply(
+	ctx context.Context,
+	plan *model.CatalogImportPlan,
+) error {
+	tx, err := repo.Db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	// rollback is no-op after commit
+	defer func() { _ = tx.Rollback() }()
+	// imported products, variants and addons are versioned
+	if err := audit.SetActor(ctx, tx); err != nil {
+		return err
+	}
+	// lookup keys loaded inside the transaction,
+	// so the applied ids are never stale
+	snapshot, err := snapshotCatalog(ctx, tx)
+	if err != nil {
+		return err
+	}
+	now := time.Now().Unix()
+	for _, step := range []func() error{
+		func() error { return applyCategories(ctx, tx, snapshot, plan.Categories) },
+		func() error { return applySubcategories(ctx, tx, snapshot, plan.Subcategories) },
+		func() error { return applyUnits(ctx, tx, snapshot, plan.Units) },
+		func() error { return applyAddons(ctx, tx, snapshot, plan.Addons, now) },
+		func() error { return applyProducts(ctx, tx, snapshot, plan.Products, now) },
+		func() error { return applyVariants(ctx, tx, snapshot, plan.Variants, now) },
+	} {
+		if err := step(); err != nil {
+			return err
+		}
+	}
+	if err := audit.ResetActor(ctx, tx); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func snapshotCatalog(
+	ctx context.Context,
+	db queryer,
+) (snapshot *model.CatalogImportSnapshot, err error) {
+	snapshot = &model.CatalogImportSnapshot{
+		Categories:    make(map[string]int),
+		Subcategories: make(map[string]int),
+		Units:         make(map[string]int),
+		Addons:        make(map[string]int),
+		Products:      make(map[string]int),
+		Variants:      make(map[string]int),
+	}
+	for _, lookup := range []struct {
+		q      string
+		target map[string]int
+		key    func(values []string) string
+	}{
+		{"SELECT id, name FROM categories WHERE deleted_at IS NULL", snapshot.Categories,
+			func(v []string) string { return model.CatalogImportKey(v[0]) }},
+		{"SELECT s.id, c.name, s.name FROM subcategories AS s " +
+			"JOIN categories AS c ON c.id = s.category_id WHERE s.deleted_at IS NULL", snapshot.Subcategories,
+			func(v []string) string { return model.CatalogImportKey(v[0], v[1]) }},
+		{"SELECT id, symbol FROM units WHERE deleted_at IS NULL", snapshot.Units,
+			func(v []string) string { return model.CatalogImportKey(v[0]) }},
+		{"SELECT id, name FROM addons WHERE deleted_at IS NULL", snapshot.Addons,
+			func(v []string) string { return model.CatalogImportKey(v[0]) }},
+		{"SELECT id, sku FROM products WHERE deleted_at IS NULL", snapshot.Products,
+			func(v []string) string { return strings.TrimSpace(v[0]) }},
+		{"SELECT v.id, p.sku, v.name FROM product_variants AS v " +
+			"JOIN products AS p ON p.id = v.product_id WHERE v.deleted_at IS NULL AND p.deleted_at IS NULL", snapshot.Variants,
+			func(v []string) string { return strings.TrimSpace(v[0]) + "/" + model.CatalogImportKey(v[1]) }},
+	} {
+		if err := lookupKeys(ctx, db, lookup.q, lookup.target, lookup.key); err != nil {
+			return nil, err
+		}
+	}
+	return snapshot, nil
+}
+
+// lookupKeys scan id and the following text columns of each row into target
+func lookupKeys(
+	ctx context.Context,
+	db queryer,
+	q string,
+	target map[string]int,
+	key func(values []string) string,
+) error {
+	rows, err := db.QueryContext(ctx, q)
+	if err != nil {
+		return err
+	}
+	defer func(rows *sql.Rows) { _ = rows.Close() }(rows)
+	columns, err := rows.Columns()
+	if err != nil {
+		return err
+	}
+	for rows.Next() {
+		var id int
+		values := make([]sql.NullString, len(columns)-1)
+		dest := []any{&id}
+		for i := range values {
+			dest = append(dest, &values[i])
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return err
+		}
+		texts := make([]string, len(values))
+		for i, value := range values {
+			texts[i] = value.String
+		}
+		target[key(texts)] = id
+	}
+	return rows.Err()
+}
+
+func applyCategories(
+	ctx context.Context,
+	tx *sql.Tx,
+	snapshot *model.CatalogImportSnapshot,
+	items []*model.Category,
+) error {
+	for _, item := range items {
+		key := model.CatalogImportKey(item.Name)
+		if _, ok := snapshot.Categories[key]; ok {
+			continue
+		}
+		q := "INSERT INTO categories (name) VALUES ($1) RETURNING id"
+		var id int
+		if err := tx.QueryRowContext(ctx, q, item.Name).Scan(&id); err != nil {
+			return err
+		}
+		snapshot.Categories[key] = id
+	}
+	return nil
+}
+
+func applySubcategories(
+	ctx context.Context,
+	tx *sql.Tx,
+	snapshot *model.CatalogImportSnapshot,
+	items []*model.CatalogImportSubcategory,
+) error {
+	for _, item := range items {
+		key := model.CatalogImportKey(item.Category, item.Name)
+		if _, ok := snapshot.Subcategories[key]; ok {
+			continue
+		}
+		categoryID, ok := snapshot.Categories[model.CatalogImportKey(item.Category)]
+		if !ok {
+			return unresolved("category", item.Category)
+		}
+		q := "INSERT INTO subcategories (category_id, name) VALUES ($1, $2) RETURNING id"
+		var id int
+		if err := tx.QueryRowContext(ctx, q, categoryID, item.Name).Scan(&id); err != nil {
+			return err
+		}
+		snapshot.Subcategories[key] = id
+	}
+	return nil
+}
+
+func applyUnits(
+	ctx context.Context,
+	tx *sql.Tx,
+	snapshot *model.CatalogImportSnapshot,
+	items []*model.Unit,
+) error {
+	for _, item := range items {
+		key := model.CatalogImportKey(item.Symbol)
+		if id, ok := snapshot.Units[key]; ok {
+			q := "UPDATE units SET magnitude = $1, name = $2 WHERE id = $3"
+			if _, err := tx.ExecContext(ctx, q, item.Magnitude, item.Name, id); err != nil {
+				return err
+			}
+			continue
+		}
+		q := "INSERT INTO units (magnitude, name, symbol) VALUES ($1, $2, $3) RETURNING id"
+		var id int
+		if err := tx.QueryRowContext(ctx, q,
+			item.Magnitude, item.Name, item.Symbol,
+		).Scan(&id); err != nil {
+			return err
+		}
+		snapshot.Units[key] = id
+	}
+	return nil
+}
+
+func applyAddons(
+	ctx context.Context,
+	tx *sql.Tx,
+	snapshot *model.CatalogImportSnapshot,
+	items []*model.Addon,
+	now int64,
+) error {
+	for _, item := range items {
+		key := model.CatalogImportKey(item.Name)
+		if id, ok := snapshot.Addons[key]; ok {
+			q := "UPDATE addons SET description = $1, price = $2, updated_at = $3 WHERE id = $4"
+			if _, err := tx.ExecContext(ctx, q, item.Description, item.Price, now, id); err != nil {
+				return err
+			}
+			continue
+		}
+		q := "INSERT INTO addons (name, description, price) VALUES ($1, $2, $3) RETURNING id"
+		var id int
+		if err := tx.QueryRowContext(ctx, q,
+			item.Name, item.Description, item.Price,
+		).Scan(&id); err != nil {
+			return err
+		}
+		snapshot.Addons[key] = id
+	}
+	return nil
+}
+
+func applyProducts(
+	ctx context.Context,
+	tx *sql.Tx,
+	snapshot *model.CatalogImportSnapshot,
+	items []*model.CatalogImportProduct,
+	now int64,
+) error {
+	for _, item := range items {
+		categoryID, ok := snapshot.Categories[model.CatalogImportKey(item.Category)]
+		if !ok {
+			return unresolved("category", item.Category)
+		}
+		subcategoryID, ok := snapshot.Subcategories[model.CatalogImportKey(item.Category, item.Subcategory)]
+		if !ok {
+			return unresolved("subcategory", item.Subcategory)
+		}
+		// empty image keep the uploaded one
+		q := "INSERT INTO products (category_id, subcategory_id, sku, image, name, description, price) "
+		q += "VALUES ($1, $2, $3, $4, $5, $6, $7) ON CONFLICT (sku) WHERE deleted_at IS NULL DO UPDATE SET "
+		q += "category_id = EXCLUDED.category_id, subcategory_id = EXCLUDED.subcategory_id, "
+		q += "image = COALESCE(EXCLUDED.image, products.image), name = EXCLUDED.name, "
+		q += "description = EXCLUDED.description, price = EXCLUDED.price, updated_at = $8 RETURNING id"
+		var id int
+		if err := tx.QueryRowContext(ctx, q, categoryID, subcategoryID,
+			item.Sku, item.Image, item.Name, item.Description, item.Price, now,
+		).Scan(&id); err != nil {
+			return err
+		}
+		snapshot.Products[strings.TrimSpace(item.Sku)] = id
+	}
+	return nil
+}
+
+func applyVariants(
+	ctx context.Context,
+	tx *sql.Tx,
+	snapshot *model.CatalogImportSnapshot,
+	items []*model.CatalogImportVariant,
+	now int64,
+) error {
+	for _, item := range items {
+		productID, ok := snapshot.Products[strings.TrimSpace(item.ProductSku)]
+		if !ok {
+			return unresolved("product", item.ProductSku)
+		}
+		unitID, ok := snapshot.Units[model.CatalogImportKey(item.UnitSymbol)]
+		if !ok {
+			return unresolved("unit", item.UnitSymbol)
+		}
+		key := strings.TrimSpace(item.ProductSku) + "/" + model.CatalogImportKey(item.Name)
+		if id, ok := snapshot.Variants[key]; ok {
+			q := "UPDATE product_variants SET unit_id = $1, unit_size = $2, type = $3, "
+			q += "description = $4, price = $5, updated_at = $6 WHERE id = $7"
+			if _, err := tx.ExecContext(ctx, q, unitID, item.UnitSize, item.Type,
+				item.Description, item.Price, now, id); err != nil {
+				return err
+			}
+			continue
+		}
+		q := "INSERT INTO product_variants (product_id, unit_id, unit_size, type, name, description, price) "
+		q += "VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING id"
+		var id int
+		if err := tx.QueryRowContext(ctx, q, productID, unitID, item.UnitSize,
+			item.Type, item.Name, item.Description, item.Price,
+		).Scan(&id); err != nil {
+			return err
+		}
+		snapshot.Variants[key] = id
+	}
+	return nil
+}
+
+func unresolved(entity, name string) error {
+	return fmt.Errorf("unable to resolve %s %s", entity, name)
+}
+
+func NewCatalogImportSQLRepository() model.ICatalogImportRepository {
+	return &CatalogImportSQLRepository{Db: config.DbPool}
+}
@@ -0,0 +1,161 @@
+package sql_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/aasumitro/posbe/config"
+	repoSql "github.com/aasumitro/posbe/internal/catalog/repository/sql"
+	"github.com/aasumitro/posbe/pkg/dbtest"
+	"github.com/aasumitro/posbe/pkg/dialect"
+	"github.com/aasumitro/posbe/pkg/model"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type catalogImportRepositoryTestSuite struct {
+	suite.Suite
+	mock sqlmock.Sqlmock
+	repo model.ICatalogImportRepository
+}
+
+func (suite *catalogImportRepositoryTestSuite) SetupSuite() {
+	var err error
+	config.DbPool, suite.mock, err = sqlmock.New(
+		sqlmock.QueryMatcherOption(
+			sqlmock.QueryMatcherRegexp))
+	require.NoError(suite.T(), err)
+	suite.repo = repoSql.NewCatalogImportSQLRepository()
+}
+
+func (suite *catalogImportRepositoryTestSuite) AfterTest(_, _ string) {
+	require.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+func (suite *catalogImportRepositoryTestSuite) expectSnapshot() {
+	suite.mock.ExpectQuery(regexp.QuoteMeta("SELECT id, name FROM categories")).
+		WillReturnRows(suite.mock.NewRows([]string{"id", "name"}).AddRow(1, "Drinks"))
+	suite.mock.ExpectQuery(regexp.QuoteMeta("SELECT s.id, c.name, s.name FROM subcategories")).
+		WillReturnRows(suite.mock.NewRows([]string{"id", "name", "name"}).AddRow(1, "Drinks", "Coffee"))
+	suite.mock.ExpectQuery(regexp.QuoteMeta("SELECT id, symbol FROM units")).
+		WillReturnRows(suite.mock.NewRows([]string{"id", "symbol"}).AddRow(1, "ml"))
+	suite.mock.ExpectQuery(regexp.QuoteMeta("SELECT id, name FROM addons")).
+		WillReturnRows(suite.mock.NewRows([]string{"id", "name"}).AddRow(1, "Sugar"))
+	suite.mock.ExpectQuery(regexp.QuoteMeta("SELECT id, sku FROM products")).
+		WillReturnRows(suite.mock.NewRows([]string{"id", "sku"}).AddRow(1, "CF-01"))
+	suite.mock.ExpectQuery(regexp.QuoteMeta("SELECT v.id, p.sku, v.name FROM product_variants")).
+		WillReturnRows(suite.mock.NewRows([]string{"id", "sku", "name"}).AddRow(1, "CF-01", "Regular"))
+}
+
+func (suite *catalogImportRepositoryTestSuite) TestRepository_Snapshot_ExpectReturnKeys() {
+	suite.expectSnapshot()
+	res, err := suite.repo.Snapshot(context.TODO())
+	require.NoError(suite.T(), err)
+	require.Equal(suite.T(), map[string]int{"drinks": 1}, res.Categories)
+	require.Equal(suite.T(), map[string]int{"drinks/coffee": 1}, res.Subcategories)
+	require.Equal(suite.T(), map[string]int{"CF-01": 1}, res.Products)
+	require.Equal(suite.T(), map[string]int{"CF-01/regular": 1}, res.Variants)
+}
+
+func (suite *catalogImportRepositoryTestSuite) TestRepository_Snapshot_ExpectReturnError() {
+	suite.mock.ExpectQuery(regexp.QuoteMeta("SELECT id, name FROM categories")).
+		WillReturnError(errors.New("UNEXPECTED"))
+	res, err := suite.repo.Snapshot(context.TODO())
+	require.Nil(suite.T(), res)
+	require.EqualError(suite.T(), err, "UNEXPECTED")
+}
+
+func (suite *catalogImportRepositoryTestSuite) TestRepository_Apply_ExpectCommit() {
+	expectVersioned(suite.mock)
+	suite.expectSnapshot()
+	suite.mock.ExpectQuery(regexp.QuoteMeta("INSERT INTO categories (name) VALUES ($1) RETURNING id")).
+		WithArgs("Foods").
+		WillReturnRows(suite.mock.NewRows([]string{"id"}).AddRow(2))
+	suite.mock.ExpectQuery(regexp.QuoteMeta("INSERT INTO subcategories (category_id, name) VALUES ($1, $2) RETURNING id")).
+		WithArgs(2, "Rice").
+		WillReturnRows(suite.mock.NewRows([]string{"id"}).AddRow(2))
+	suite.mock.ExpectExec(regexp.QuoteMeta("UPDATE units SET magnitude = $1, name = $2 WHERE id = $3")).
+		WithArgs("volume", "millilitre", 1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	suite.mock.ExpectQuery(regexp.QuoteMeta("INSERT INTO addons (name, description, price) VALUES ($1, $2, $3) RETURNING id")).
+		WithArgs("Egg", "fried egg", int64(5000)).
+		WillReturnRows(suite.mock.NewRows([]string{"id"}).AddRow(2))
+	suite.mock.ExpectQuery(regexp.QuoteMeta("INSERT INTO products (category_id, subcategory_id, sku, image, name, description, price) " +
+		"VALUES ($1, $2, $3, $4, $5, $6, $7) ON CONFLICT (sku) WHERE deleted_at IS NULL DO UPDATE SET")).
+		WillReturnRows(suite.mock.NewRows([]string{"id"}).AddRow(2))
+	suite.mock.ExpectQuery(regexp.QuoteMeta("INSERT INTO product_variants (product_id, unit_id, unit_size, type, name, description, price) ")).
+		WithArgs(2, 1, float32(250), "size", "Large", sql.NullString{}, int64(30000)).
+		WillReturnRows(suite.mock.NewRows([]string{"id"}).AddRow(2))
+	expectVersionedCommit(suite.mock)
+	err := suite.repo.Apply(context.TODO(), &model.CatalogImportPlan{
+		Categories: []*model.Category{{Name: "Foods"}},
+		Subcategories: []*model.CatalogImportSubcategory{
+			{Subcategory: model.Subcategory{Name: "Rice"}, Category: "foods"},
+		},
+		Units:  []*model.Unit{{Magnitude: "volume", Name: "millilitre", Symbol: "ml"}},
+		Addons: []*model.Addon{{Name: "Egg", Description: "fried egg", Price: 5000}},
+		Products: []*model.CatalogImportProduct{
+			{Product: model.Product{Sku: "FR-01", Name: "Fried Rice", Price: 25000},
+				Category: "Foods", Subcategory: "Rice"},
+		},
+		Variants: []*model.CatalogImportVariant{
+			{ProductVariant: model.ProductVariant{UnitSize: 250, Type: "size", Name: "Large", Price: 30000},
+				ProductSku: "FR-01", UnitSymbol: "ML"},
+		},
+	})
+	require.NoError(suite.T(), err)
+}
+
+func (suite *catalogImportRepositoryTestSuite) TestRepository_Apply_ExpectRollback() {
+	expectVersioned(suite.mock)
+	suite.expectSnapshot()
+	suite.mock.ExpectRollback()
+	err := suite.repo.Apply(context.TODO(), &model.CatalogImportPlan{
+		Subcategories: []*model.CatalogImportSubcategory{
+			{Subcategory: model.Subcategory{Name: "Rice"}, Category: "Foods"},
+		},
+	})
+	require.EqualError(suite.T(), err, "unable to resolve category Foods")
+}
+
+func (suite *catalogImportRepositoryTestSuite) TestRepository_Apply_ExpectBeginError() {
+	suite.mock.ExpectBegin().WillReturnError(errors.New("UNEXPECTED"))
+	err := suite.repo.Apply(context.TODO(), &model.CatalogImportPlan{})
+	require.EqualError(suite.T(), err, "UNEXPECTED")
+}
+
+func (suite *catalogImportRepositoryTestSuite) TestCatalogImportRepository_Apply() {
+	dbtest.Migrated(suite.T(), config.Dialect.Name())
+	ctx := context.TODO()
+	repo := repoSql.NewCatalogImportSQLRepository()
+	require.NoError(suite.T(), repo.Apply(ctx, &model.CatalogImportPlan{
+		Categories:    []*model.Category{{Name: "snacks"}},
+		Subcategories: []*model.CatalogImportSubcategory{{Subcategory: model.Subcategory{Name: "chips"}, Category: "snacks"}},
+		Products: []*model.CatalogImportProduct{{
+			Product:  model.Product{Sku: "CHP100", Name: "potato chips", Price: 1500},
+			Category: "snacks", Subcategory: "chips",
+		}},
+		Variants: []*model.CatalogImportVariant{{
+			ProductVariant: model.ProductVariant{UnitSize: 100, Type: "size", Name: "small", Price: 1500},
+			ProductSku:     "CHP100", UnitSymbol: "g",
+		}},
+	}))
+	snapshot, err := repo.Snapshot(ctx)
+	require.NoError(suite.T(), err)
+	require.Contains(suite.T(), snapshot.Products, "CHP100")
+	require.Contains(suite.T(), snapshot.Subcategories, model.CatalogImportKey("snacks", "chips"))
+	require.Contains(suite.T(), snapshot.Variants, "CHP100/"+model.CatalogImportKey("small"))
+}
+
+func TestCatalogImportRepository(t *testing.T) {
+	for _, d := range dialect.All() {
+		t.Run(d.Name(), func(t *testing.T) {
+			config.Dialect = d
+			suite.Run(t, new(catalogImportRepositoryTestSuite))
+		})
+	}
+}
@@ -3,77 +3,81 @@ package sql
 import (
 	"context"
 	"database/sql"
+	"time"
 
 	"github.com/aasumitro/posbe/config"
+	"github.com/aasumitro/posbe/pkg/audit"
 	"github.com/aasumitro/posbe/pkg/model"
 )
 
+const categoryColumns = "id, name, deleted_at, deleted_by"
+
 type CategorySQLRepository struct {
 	Db *sql.DB
 }
 
 func (repo CategorySQLRepository) All(ctx context.Context) (data []*model.Category, err error) {
-	q := "SELECT * FROM categories"
-	rows, err := repo.Db.QueryContext(ctx, q)
+	q := "SELECT " + categoryColumns + " FROM categories WHERE ($1 OR deleted_at IS NULL)"
+	rows, err := repo.Db.QueryContext(ctx, q, model.IncludeDeleted(ctx))
 	if err != nil {
 		return nil, err
 	}
 	defer func(rows *sql.Rows) { _ = rows.Close() }(rows)
 
 	for rows.Next() {
-		var category model.Category
-
-		if err := rows.Scan(
-			&category.ID,
-			&category.Name,
-		); err != nil {
+		category, err := scanCategory(rows)
+		if err != nil {
 			return nil, err
 		}
 
-		data = append(data, &category)
+		data = append(data, category)
 	}
 
 	return data, nil
 }
 
 func (repo CategorySQLRepository) Find(ctx context.Context, _ model.FindWith, val any) (data *model.Category, err error) {
-	q := "SELECT * FROM categories WHERE id = $1 LIMIT 1"
-	row := repo.Db.QueryRowContext(ctx, q, val)
+	q := "SELECT " + categoryColumns + " FROM categories WHERE id = $1 AND ($2 OR deleted_at IS NULL) LIMIT 1"
+	row := repo.Db.QueryRowContext(ctx, q, val, model.IncludeDeleted(ctx))
 
-	data = &model.Category{}
-	if err := row.Scan(
-		&data.ID,
-		&data.Name,
-	); err != nil {
-		return nil, err
-	}
-
-	return data, nil
+	return scanCategory(row)
 }
 
 func (repo CategorySQLRepository) Create(ctx context.Context, params *model.Category) (data *model.Category, err error) {
-	q := "INSERT INTO categories (name) VALUES ($1) RETURNING *"
+	q := "INSERT INTO categories (name) VALUES ($1) RETURNING " + categoryColumns
 	row := repo.Db.QueryRowContext(ctx, q, params.Name)
 
-	data = &model.Category{}
-	if err := row.Scan(
-		&data.ID,
-		&data.Name,
-	); err != nil {
-		return nil, err
-	}
-
-	return data, nil
+	return scanCategory(row)
 }
 
 func (repo CategorySQLRepository) Update(ctx context.Context, params *model.Category) (data *model.Category, err error) {
-	q := "UPDATE categories SET name = $1 WHERE id = $2 RETURNING *"
+	q := "UPDATE categories SET name = $1 WHERE id = $2 AND deleted_at IS NULL RETURNING " + categoryColumns
 	row := repo.Db.QueryRowContext(ctx, q, params.Name, params.ID)
 
+	return scanCategory(row)
+}
+
+func (repo CategorySQLRepository) Delete(ctx context.Context, params *model.Category) error {
+	q := "UPDATE categories SET deleted_at = $1, deleted_by = $2 WHERE id = $3 AND deleted_at IS NULL"
+	_, err := repo.Db.ExecContext(ctx, q, time.Now().Unix(), audit.NullActor(ctx), params.ID)
+	return err
+}
+
+func (repo CategorySQLRepository) Restore(ctx context.Context, params *model.Category) (data *model.Category, err error) {
+	q := "UPDATE categories SET deleted_at = NULL, deleted_by = NULL "
+	q += "WHERE id = $1 AND deleted_at IS NOT NULL RETURNING " + categoryColumns
+	row := repo.Db.QueryRowContext(ctx, q, params.ID)
+
+	return scanCategory(row)
+}
+
+func scanCategory(row scanner) (data *model.Category, err error) {
 	data = &model.Category{}
 	if err := row.Scan(
 		&data.ID,
 		&data.Name,
+		&data.DeletedAt,
+		&data.DeletedBy,
 	); err != nil {
 		return nil, err
 	}
@@ -81,12 +85,6 @@ func (repo CategorySQLRepository) Update(ctx context.Context, params *model.Cate
 	return data, nil
 }
 
-func (repo CategorySQLRepository) Delete(ctx context.Context, params *model.Category) error {
-	q := "DELETE FROM categories WHERE id = $1"
-	_, err := repo.Db.ExecContext(ctx, q, params.ID)
-	return err
-}
-
-func NewCategorySQLRepository() model.ICRUDRepository[model.Category] {
-	return &CategorySQLRepository{Db: config.PostgresPool}
+func NewCategorySQLRepository() model.ISoftDeleteRepository[model.Category] {
+	return &CategorySQLRepository{Db: config.DbPool}
 }
@@ -2,6 +2,7 @@ package sql_test
 
 import (
 	"context"
+	"database/sql"
 	"errors"
 	"regexp"
 	"testing"
@@ -9,6 +10,8 @@ import (
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/aasumitro/posbe/config"
 	repoSql "github.com/aasumitro/posbe/internal/catalog/repository/sql"
+	"github.com/aasumitro/posbe/pkg/dbtest"
+	"github.com/aasumitro/posbe/pkg/dialect"
 	"github.com/aasumitro/posbe/pkg/model"
 	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
@@ -17,13 +20,13 @@ import (
 type categoryRepositoryTestSuite struct {
 	suite.Suite
 	mock sqlmock.Sqlmock
-	repo model.ICRUDRepository[model.Category]
+	repo model.ISoftDeleteRepository[model.Category]
 }
 
 func (suite *categoryRepositoryTestSuite) SetupSuite() {
 	var err error
 
-	config.PostgresPool, suite.mock, err = sqlmock.New(
+	config.DbPool, suite.mock, err = sqlmock.New(
 		sqlmock.QueryMatcherOption(
 			sqlmock.QueryMatcherRegexp))
 	require.NoError(suite.T(), err)
@@ -37,10 +40,10 @@ func (suite *categoryRepositoryTestSuite) AfterTest(_, _ string) {
 
 func (suite *categoryRepositoryTestSuite) TestRepository_All_ExpectReturnRows() {
 	data := suite.mock.
-		NewRows([]string{"id", "name"}).
-		AddRow(1, "test").
-		AddRow(2, "test 2")
-	query := "SELECT * FROM categories"
+		NewRows([]string{"id", "name", "deleted_at", "deleted_by"}).
+		AddRow(1, "test", nil, nil).
+		AddRow(2, "test 2", nil, nil)
+	query := "SELECT id, name, deleted_at, deleted_by FROM categories WHERE ($1 OR deleted_at IS NULL)"
 	meta := regexp.QuoteMeta(query)
 	suite.mock.ExpectQuery(meta).WillReturnRows(data)
 	res, err := suite.repo.All(context.TODO())
@@ -50,7 +53,7 @@ func (suite *categoryRepositoryTestSuite) TestRepository_All_ExpectReturnRows()
 }
 
 func (suite *categoryRepositoryTestSuite) TestRepository_All_ExpectReturnErrorFromQuery() {
-	query := "SELECT * FROM categories"
+	query := "SELECT id, name, deleted_at, deleted_by FROM categories WHERE ($1 OR deleted_at IS NULL)"
 	meta := regexp.QuoteMeta(query)
 	suite.mock.ExpectQuery(meta).WillReturnError(errors.New(""))
 	res, err := suite.repo.All(context.TODO())
@@ -60,10 +63,10 @@ func (suite *categoryRepositoryTestSuite) TestRepository_All_ExpectReturnErrorFr
 
 func (suite *categoryRepositoryTestSuite) TestRepository_All_ExpectReturnErrorFromScan() {
 	data := suite.mock.
-		NewRows([]string{"id", "name"}).
-		AddRow(1, "test").
-		AddRow(nil, nil)
-	query := "SELECT * FROM categories"
+		NewRows([]string{"id", "name", "deleted_at", "deleted_by"}).
+		AddRow(1, "test", nil, nil).
+		AddRow(nil, nil, nil, nil)
+	query := "SELECT id, name, deleted_at, deleted_by FROM categories WHERE ($1 OR deleted_at IS NULL)"
 	meta := regexp.QuoteMeta(query)
 	suite.mock.ExpectQuery(meta).WillReturnRows(data)
 	res, err := suite.repo.All(context.TODO())
@@ -73,9 +76,9 @@ func (suite *categoryRepositoryTestSuite) TestRepository_All_ExpectReturnErrorFr
 
 func (suite *categoryRepositoryTestSuite) TestRepository_Find_ExpectReturnRow() {
 	data := suite.mock.
-		NewRows([]string{"id", "name"}).
-		AddRow(1, "test")
-	query := "SELECT * FROM categories WHERE id = $1 LIMIT 1"
+		NewRows([]string{"id", "name", "deleted_at", "deleted_by"}).
+		AddRow(1, "test", nil, nil)
+	query := "SELECT id, name, deleted_at, deleted_by FROM categories WHERE id = $1 AND ($2 OR deleted_at IS NULL) LIMIT 1"
 	meta := regexp.QuoteMeta(query)
 	suite.mock.ExpectQuery(meta).WillReturnRows(data)
 	res, err := suite.repo.Find(context.TODO(), model.FindWithID, 1)
@@ -86,9 +89,9 @@ func (suite *categoryRepositoryTestSuite) TestRepository_Find_ExpectReturnRow()
 
 func (suite *categoryRepositoryTestSuite) TestRepository_Find_ExpectReturnError() {
 	data := suite.mock.
-		NewRows([]string{"id", "name"}).
-		AddRow(nil, nil)
-	query := "SELECT * FROM categories WHERE id = $1 LIMIT 1"
+		NewRows([]string{"id", "name", "deleted_at", "deleted_by"}).
+		AddRow(nil, nil, nil, nil)
+	query := "SELECT id, name, deleted_at, deleted_by FROM categories WHERE id = $1 AND ($2 OR deleted_at IS NULL) LIMIT 1"
 	meta := regexp.QuoteMeta(query)
 	suite.mock.ExpectQuery(meta).WillReturnRows(data)
 	res, err := suite.repo.Find(context.TODO(), model.FindWithID, 1)
@@ -99,9 +102,9 @@ func (suite *categoryRepositoryTestSuite) TestRepository_Find_ExpectReturnError(
 func (suite *categoryRepositoryTestSuite) TestRepository_Created_ExpectSuccess() {
 	category := &model.Category{ID: 1, Name: "test"}
 	data := suite.mock.
-		NewRows([]string{"id", "name"}).
-		AddRow(1, "test")
-	query := "INSERT INTO categories (name) VALUES ($1) RETURNING *"
+		NewRows([]string{"id", "name", "deleted_at", "deleted_by"}).
+		AddRow(1, "test", nil, nil)
+	query := "INSERT INTO categories (name) VALUES ($1) RETURNING id, name, deleted_at, deleted_by"
 	meta := regexp.QuoteMeta(query)
 	suite.mock.ExpectQuery(meta).
 		WithArgs(category.Name).
@@ -115,9 +118,9 @@ func (suite *categoryRepositoryTestSuite) TestRepository_Created_ExpectSuccess()
 func (suite *categoryRepositoryTestSuite) TestRepository_Created_ExpectError() {
 	category := &model.Category{ID: 1, Name: "test"}
 	data := suite.mock.
-		NewRows([]string{"id", "name"}).
-		AddRow(1, nil)
-	query := "INSERT INTO categories (name) VALUES ($1) RETURNING *"
+		NewRows([]string{"id", "name", "deleted_at", "deleted_by"}).
+		AddRow(1, nil, nil, nil)
+	query := "INSERT INTO categories (name) VALUES ($1) RETURNING id, name, deleted_at, deleted_by"
 	meta := regexp.QuoteMeta(query)
 	suite.mock.ExpectQuery(meta).
 		WithArgs(category.Name).
@@ -131,9 +134,9 @@ func (suite *categoryRepositoryTestSuite) TestRepository_Created_ExpectError() {
 func (suite *categoryRepositoryTestSuite) TestRepository_Updated_ExpectSuccess() {
 	category := &model.Category{ID: 1, Name: "test"}
 	data := suite.mock.
-		NewRows([]string{"id", "name"}).
-		AddRow(1, "test")
-	query := "UPDATE categories SET name = $1 WHERE id = $2 RETURNING *"
+		NewRows([]string{"id", "name", "deleted_at", "deleted_by"}).
+		AddRow(1, "test", nil, nil)
+	query := "UPDATE categories SET name = $1 WHERE id = $2 AND deleted_at IS NULL RETURNING id, name, deleted_at, deleted_by"
 	meta := regexp.QuoteMeta(query)
 	suite.mock.ExpectQuery(meta).
 		WithArgs(category.Name, category.ID).
@@ -147,9 +150,9 @@ func (suite *categoryRepositoryTestSuite) TestRepository_Updated_ExpectSuccess()
 func (suite *categoryRepositoryTestSuite) TestRepository_Updated_ExpectError() {
 	category := &model.Category{ID: 1, Name: "test"}
 	data := suite.mock.
-		NewRows([]string{"id", "name"}).
-		AddRow(1, nil)
-	query := "UPDATE categories SET name = $1 WHERE id = $2 RETURNING *"
+		NewRows([]string{"id", "name", "deleted_at", "deleted_by"}).
+		AddRow(1, nil, nil, nil)
+	query := "UPDATE categories SET name = $1 WHERE id = $2 AND deleted_at IS NULL RETURNING id, name, deleted_at, deleted_by"
 	meta := regexp.QuoteMeta(query)
 	suite.mock.ExpectQuery(meta).
 		WithArgs(category.Name, category.ID).
@@ -161,15 +164,47 @@ func (suite *categoryRepositoryTestSuite) TestRepository_Updated_ExpectError() {
 }
 
 func (suite *categoryRepositoryTestSuite) TestRepository_Delete_ExpectSuccess() {
-	expectedQuery := regexp.QuoteMeta("DELETE FROM categories WHERE id = $1")
+	expectedQuery := regexp.QuoteMeta("UPDATE categories SET deleted_at = $1, deleted_by = $2 WHERE id = $3 AND deleted_at IS NULL")
 	suite.mock.ExpectExec(expectedQuery).
-		WithArgs(1).
+		WithArgs(sqlmock.AnyArg(), nil, 1).
 		WillReturnResult(sqlmock.NewResult(0, 1))
 	data := &model.Category{ID: 1}
 	err := suite.repo.Delete(context.TODO(), data)
 	require.Nil(suite.T(), err)
 }
 
+func (suite *categoryRepositoryTestSuite) TestCategoryRepository_SoftDelete() {
+	dbtest.Migrated(suite.T(), config.Dialect.Name())
+	ctx := context.TODO()
+	categories := repoSql.NewCategorySQLRepository()
+	subcategories := repoSql.NewSubcategorySQLRepository()
+	category, err := categories.Create(ctx, &model.Category{Name: "desserts"})
+	require.NoError(suite.T(), err)
+	subcategory, err := subcategories.Create(ctx, &model.Subcategory{CategoryID: category.ID, Name: "cakes"})
+	require.NoError(suite.T(), err)
+	subcategory.Name = "pies"
+	subcategory, err = subcategories.Update(ctx, subcategory)
+	require.NoError(suite.T(), err)
+	require.Equal(suite.T(), "pies", subcategory.Name)
+
+	require.NoError(suite.T(), subcategories.Delete(ctx, subcategory))
+	_, err = subcategories.Find(ctx, model.FindWithID, subcategory.ID)
+	require.ErrorIs(suite.T(), err, sql.ErrNoRows)
+	before, err := categories.All(ctx)
+	require.NoError(suite.T(), err)
+	require.NoError(suite.T(), categories.Delete(ctx, category))
+	after, err := categories.All(ctx)
+	require.NoError(suite.T(), err)
+	require.Len(suite.T(), after, len(before)-1)
+	_, err = categories.Restore(ctx, category)
+	require.NoError(suite.T(), err)
+}
+
 func TestCategoryRepository(t *testing.T) {
-	suite.Run(t, new(categoryRepositoryTestSuite))
+	for _, d := range dialect.All() {
+		t.Run(d.Name(), func(t *testing.T) {
+			config.Dialect = d
+			suite.Run(t, new(categoryRepositoryTestSuite))
+		})
+	}
 }
@@ -0,0 +1,96 @@
+package sql
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+
+	"github.com/aasumitro/posbe/config"
+	"github.com/aasumitro/posbe/pkg/dialect"
+	"github.com/aasumitro/posbe/pkg/model"
+)
+
+const entityVersionColumns = "id, entity_type, entity_id, version, data, changed_by, valid_from, valid_to"
+
+type EntityVersionSQLRepository struct {
+	Db      *sql.DB
+	Dialect dialect.Dialect
+}
+
+func (repo EntityVersionSQLRepository) Versions(
+	ctx context.Context,
+	entityType, entityID string,
+) (data []*model.EntityVersion, err error) {
+	q := "SELECT " + entityVersionColumns + " FROM entity_versions "
+	q += "WHERE entity_type = $1 AND entity_id = $2 ORDER BY version ASC"
+	rows, err := repo.Db.QueryContext(ctx, q, entityType, entityID)
+	if err != nil {
+		return nil, err
+	}
+	defer func(rows *sql.Rows) { _ = rows.Close() }(rows)
+
+	return scanEntityVersions(rows)
+}
+
+func (repo EntityVersionSQLRepository) AsOf(
+	ctx context.Context,
+	entityType string,
+	ids []string,
+	at int64,
+) (data []*model.EntityVersion, err error) {
+	q := "SELECT " + entityVersionColumns + " FROM entity_versions "
+	q += "WHERE entity_type = $1 AND valid_from <= $2 AND (valid_to IS NULL OR valid_to > $2) "
+	q += "AND data IS NOT NULL AND data->>'deleted_at' IS NULL "
+	args := []any{entityType, at}
+	if len(ids) > 0 {
+		q += "AND " + repo.Dialect.Any("entity_id", "$3") + " "
+		args = append(args, repo.Dialect.Array(ids))
+	}
+	q += "ORDER BY entity_id ASC"
+	rows, err := repo.Db.QueryContext(ctx, q, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func(rows *sql.Rows) { _ = rows.Close() }(rows)
+
+	return scanEntityVersions(rows)
+}
+
+func scanEntityVersions(rows *sql.Rows) (data []*model.EntityVersion, err error) {
+	for rows.Next() {
+		var version model.EntityVersion
+		var payload []byte
+		var changedBy, validTo sql.NullInt64
+		if err := rows.Scan(&version.ID, &version.EntityType, &version.EntityID,
+			&version.Version, &payload, &changedBy, &version.ValidFrom, &validTo,
+		); err != nil {
+			return nil, err
+		}
+		version.Data = payload
+		version.Deleted = deletedVersion(payload)
+		version.ChangedBy = int(changedBy.Int64)
+		if validTo.Valid {
+			version.ValidTo = &validTo.Int64
+		}
+		data = append(data, &version)
+	}
+
+	return data, rows.Err()
+}
+
+// deletedVersion tell the row was removed, hard deleted rows have no data
+// and soft deleted rows have their deleted_at set
+func deletedVersion(payload []byte) bool {
+	if payload == nil {
+		return true
+	}
+	var row struct {
+		DeletedAt *int64 `json:"deleted_at"`
+	}
+	_ = json.Unmarshal(payload, &row)
+	return row.DeletedAt != nil
+}
+
+func NewEntityVersionSQLRepository() model.IEntityVersionRepository {
+	return &EntityVersionSQLRepository{Db: config.DbPool, Dialect: config.Dialect}
+}
@@ -0,0 +1,103 @@
+package sql_test
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/aasumitro/posbe/config"
+	repoSql "github.com/aasumitro/posbe/internal/catalog/repository/sql"
+	"github.com/aasumitro/posbe/pkg/dialect"
+	"github.com/aasumitro/posbe/pkg/model"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type entityVersionRepositoryTestSuite struct {
+	suite.Suite
+	mock    sqlmock.Sqlmock
+	repo    model.IEntityVersionRepository
+	columns []string
+}
+
+func (suite *entityVersionRepositoryTestSuite) SetupSuite() {
+	var err error
+	config.DbPool, suite.mock, err = sqlmock.New(
+		sqlmock.QueryMatcherOption(
+			sqlmock.QueryMatcherRegexp))
+	require.NoError(suite.T(), err)
+	suite.repo = repoSql.NewEntityVersionSQLRepository()
+	suite.columns = []string{"id", "entity_type", "entity_id", "version",
+		"data", "changed_by", "valid_from", "valid_to"}
+}
+
+func (suite *entityVersionRepositoryTestSuite) AfterTest(_, _ string) {
+	require.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+func (suite *entityVersionRepositoryTestSuite) TestRepository_Versions_ExpectReturnRows() {
+	suite.mock.ExpectQuery("SELECT (.+) FROM entity_versions WHERE entity_type = \\$1 AND entity_id = \\$2 ORDER BY version ASC").
+		WithArgs("products", "1").
+		WillReturnRows(suite.mock.NewRows(suite.columns).
+			AddRow(1, "products", "1", 1, []byte(`{"price":1000}`), nil, 0, 100).
+			AddRow(2, "products", "1", 2, []byte(`{"price":2000}`), 3, 100, 200).
+			AddRow(3, "products", "1", 3, nil, 3, 200, 300).
+			AddRow(4, "products", "1", 4, []byte(`{"price":2000,"deleted_at":300}`), 3, 300, nil))
+	res, err := suite.repo.Versions(context.TODO(), "products", "1")
+	require.NoError(suite.T(), err)
+	require.Len(suite.T(), res, 4)
+	require.Equal(suite.T(), 3, res[1].ChangedBy)
+	require.Equal(suite.T(), int64(200), *res[1].ValidTo)
+	require.True(suite.T(), res[2].Deleted)
+	require.True(suite.T(), res[3].Deleted)
+	require.Nil(suite.T(), res[3].ValidTo)
+}
+
+func (suite *entityVersionRepositoryTestSuite) TestRepository_Versions_ExpectReturnError() {
+	suite.mock.ExpectQuery("SELECT (.+) FROM entity_versions").
+		WillReturnError(errors.New("UNEXPECTED"))
+	res, err := suite.repo.Versions(context.TODO(), "products", "1")
+	require.Nil(suite.T(), res)
+	require.EqualError(suite.T(), err, "UNEXPECTED")
+}
+
+func (suite *entityVersionRepositoryTestSuite) TestRepository_AsOf_ExpectReturnRows() {
+	suite.mock.ExpectQuery("SELECT (.+) FROM entity_versions WHERE entity_type = \\$1 AND valid_from <= \\$2 "+
+		"AND \\(valid_to IS NULL OR valid_to > \\$2\\) AND data IS NOT NULL AND data->>'deleted_at' IS NULL AND "+
+		regexp.QuoteMeta(config.Dialect.Any("entity_id", "$3"))).
+		WithArgs("products", int64(150), sqlmock.AnyArg()).
+		WillReturnRows(suite.mock.NewRows(suite.columns).
+			AddRow(2, "products", "1", 2, []byte(`{"price":2000}`), 3, 100, 200))
+	res, err := suite.repo.AsOf(context.TODO(), "products", []string{"1"}, 150)
+	require.NoError(suite.T(), err)
+	require.Len(suite.T(), res, 1)
+	require.JSONEq(suite.T(), `{"price":2000}`, string(res[0].Data))
+}
+
+func (suite *entityVersionRepositoryTestSuite) TestRepository_AsOf_ExpectReturnAll() {
+	suite.mock.ExpectQuery("SELECT (.+) FROM entity_versions WHERE (.+) AND data IS NOT NULL AND data->>'deleted_at' IS NULL ORDER BY entity_id ASC").
+		WithArgs("addons", int64(150)).
+		WillReturnRows(suite.mock.NewRows(suite.columns))
+	res, err := suite.repo.AsOf(context.TODO(), "addons", nil, 150)
+	require.NoError(suite.T(), err)
+	require.Empty(suite.T(), res)
+}
+
+func (suite *entityVersionRepositoryTestSuite) TestRepository_AsOf_ExpectReturnError() {
+	suite.mock.ExpectQuery("SELECT (.+) FROM entity_versions").
+		WillReturnError(errors.New("UNEXPECTED"))
+	res, err := suite.repo.AsOf(context.TODO(), "addons", nil, 150)
+	require.Nil(suite.T(), res)
+	require.EqualError(suite.T(), err, "UNEXPECTED")
+}
+
+func TestEntityVersionRepository(t *testing.T) {
+	for _, d := range dialect.All() {
+		t.Run(d.Name(), func(t *testing.T) {
+			config.Dialect = d
+			suite.Run(t, new(entityVersionRepositoryTestSuite))
+		})
+	}
+}
@@ -0,0 +1,298 @@
+package sql
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/aasumitro/posbe/config"
+	"github.com/aasumitro/posbe/pkg/dialect"
+	"github.com/aasumitro/posbe/pkg/model"
+	"github.com/aasumitro/posbe/pkg/money"
+)
+
+const priceListColumns = "l.id, l.name, l.channel, l.customer_tier, l.days, l.start_time, " +
+	"l.end_time, l.effective_from, l.effective_to, l.priority, l.disabled"
+
+type (
+	PriceListSQLRepository struct {
+		Db      *sql.DB
+		Dialect dialect.Dialect
+	}
+
+	// scanner is implemented by both *sql.Row and *sql.Rows
+	scanner interface {
+		Scan(dest ...any) error
+	}
+)
+
+func (repo PriceListSQLRepository) All(ctx context.Context) (data []*model.PriceList, err error) {
+	q := "SELECT " + priceListColumns + " FROM price_lists AS l ORDER BY l.priority DESC, l.id"
+	rows, err := repo.Db.QueryContext(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+	defer func(rows *sql.Rows) { _ = rows.Close() }(rows)
+
+	lists := make(map[int]*model.PriceList)
+	for rows.Next() {
+		list, err := scanPriceList(rows)
+		if err != nil {
+			return nil, err
+		}
+		lists[list.ID] = list
+		data = append(data, list)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	q = "SELECT price_list_id, item_type, item_id, price FROM price_list_items ORDER BY id"
+	items, err := repo.Db.QueryContext(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+	defer func(rows *sql.Rows) { _ = rows.Close() }(items)
+
+	for items.Next() {
+		var listID int
+		var item model.PriceListItem
+		if err := items.Scan(&listID, &item.ItemType, &item.ItemID, &item.Price); err != nil {
+			return nil, err
+		}
+		if list, ok := lists[listID]; ok {
+			list.Items = append(list.Items, &item)
+		}
+	}
+
+	return data, items.Err()
+}
+
+func (repo PriceListSQLRepository) Find(ctx context.Context, _ model.FindWith, val any) (data *model.PriceList, err error) {
+	q := "SELECT " + priceListColumns + " FROM price_lists AS l WHERE l.id = $1 LIMIT 1"
+	if data, err = scanPriceList(repo.Db.QueryRowContext(ctx, q, val)); err != nil {
+		return nil, err
+	}
+
+	q = "SELECT item_type, item_id, price FROM price_list_items WHERE price_list_id = $1 ORDER BY id"
+	rows, err := repo.Db.QueryContext(ctx, q, data.ID)
+	if err != nil {
+		return nil, err
+	}
+	defer func(rows *sql.Rows) { _ = rows.Close() }(rows)
+
+	for rows.Next() {
+		var item model.PriceListItem
+		if err := rows.Scan(&item.ItemType, &item.ItemID, &item.Price); err != nil {
+			return nil, err
+		}
+		data.Items = append(data.Items, &item)
+	}
+
+	return data, rows.Err()
+}
+
+func (repo PriceListSQLRepository) Create(ctx context.Context, params *model.PriceList) (data *model.PriceList, err error) {
+	tx, err := repo.Db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	q := "INSERT INTO price_lists (name, channel, customer_tier, days, start_time, end_time, "
+	q += "effective_from, effective_to, priority, disabled) "
+	q += "VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10) RETURNING id"
+	var id int
+	if err := tx.QueryRowContext(ctx, q, priceListArgs(params)...).Scan(&id); err != nil {
+		return nil, err
+	}
+	if err := replacePriceListItems(ctx, tx, id, params.Items); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return repo.Find(ctx, model.FindWithID, id)
+}
+
+func (repo PriceListSQLRepository) Update(ctx context.Context, params *model.PriceList) (data *model.PriceList, err error) {
+	tx, err := repo.Db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	q := "UPDATE price_lists SET name = $1, channel = $2, customer_tier = $3, days = $4, "
+	q += "start_time = $5, end_time = $6, effective_from = $7, effective_to = $8, "
+	q += "priority = $9, disabled = $10, updated_at = $11 WHERE id = $12"
+	args := append(priceListArgs(params), time.Now().Unix(), params.ID)
+	result, err := tx.ExecContext(ctx, q, args...)
+	if err != nil {
+		return nil, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return nil, err
+	}
+	if affected == 0 {
+		return nil, sql.ErrNoRows
+	}
+	if err := replacePriceListItems(ctx, tx, params.ID, params.Items); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return repo.Find(ctx, model.FindWithID, params.ID)
+}
+
+func (repo PriceListSQLRepository) Delete(ctx context.Context, params *model.PriceList) error {
+	q := "DELETE FROM price_lists WHERE id = $1"
+	_, err := repo.Db.ExecContext(ctx, q, params.ID)
+	return err
+}
+
+func (repo PriceListSQLRepository) Candidates(
+	ctx context.Context,
+	items []*model.PriceQueryItem,
+) (data []*model.PriceCandidate, err error) {
+	q := "SELECT " + priceListColumns + ", i.item_type, i.item_id, i.price "
+	q += "FROM price_list_items AS i JOIN price_lists AS l ON l.id = i.price_list_id "
+	q += "WHERE l.disabled = false AND " + repo.Dialect.Any("(i.item_type || ':' || i.item_id)", "$1")
+	rows, err := repo.Db.QueryContext(ctx, q, repo.Dialect.Array(priceItemKeys(items)))
+	if err != nil {
+		return nil, err
+	}
+	defer func(rows *sql.Rows) { _ = rows.Close() }(rows)
+
+	for rows.Next() {
+		var item model.PriceListItem
+		list, err := scanPriceList(rows, &item.ItemType, &item.ItemID, &item.Price)
+		if err != nil {
+			return nil, err
+		}
+		data = append(data, &model.PriceCandidate{List: list, Item: &item})
+	}
+
+	return data, rows.Err()
+}
+
+func (repo PriceListSQLRepository) BasePrices(
+	ctx context.Context,
+	items []*model.PriceQueryItem,
+) (prices map[string]money.Amount, err error) {
+	ids := map[string][]int64{
+		model.PriceItemProduct: {},
+		model.PriceItemVariant: {},
+		model.PriceItemAddon:   {},
+	}
+	for _, item := range items {
+		ids[item.ItemType] = append(ids[item.ItemType], int64(item.ItemID))
+	}
+	q := "SELECT 'product', id, price FROM products WHERE " + repo.Dialect.Any("id", "$1") + " "
+	q += "UNION ALL SELECT 'variant', id, price FROM product_variants WHERE " + repo.Dialect.Any("id", "$2") + " "
+	q += "UNION ALL SELECT 'addon', id, price FROM addons WHERE " + repo.Dialect.Any("id", "$3")
+	rows, err := repo.Db.QueryContext(ctx, q,
+		repo.Dialect.Array(ids[model.PriceItemProduct]),
+		repo.Dialect.Array(ids[model.PriceItemVariant]),
+		repo.Dialect.Array(ids[model.PriceItemAddon]))
+	if err != nil {
+		return nil, err
+	}
+	defer func(rows *sql.Rows) { _ = rows.Close() }(rows)
+
+	prices = make(map[string]money.Amount)
+	for rows.Next() {
+		var itemType string
+		var id int
+		var price money.Amount
+		if err := rows.Scan(&itemType, &id, &price); err != nil {
+			return nil, err
+		}
+		prices[model.PriceItemKey(itemType, id)] = price
+	}
+
+	return prices, rows.Err()
+}
+
+func replacePriceListItems(ctx context.Context, tx *sql.Tx, listID int, items []*model.PriceListItem) error {
+	q := "DELETE FROM price_list_items WHERE price_list_id = $1"
+	if _, err := tx.ExecContext(ctx, q, listID); err != nil {
+		return err
+	}
+	q = "INSERT INTO price_list_items (price_list_id, item_type, item_id, price) VALUES ($1, $2, $3, $4)"
+	for _, item := range items {
+		if _, err := tx.ExecContext(ctx, q, listID, item.ItemType, item.ItemID, item.Price); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// scanPriceList read priceListColumns, extra destinations are scanned after them
+func scanPriceList(row scanner, extra ...any) (*model.PriceList, error) {
+	var list model.PriceList
+	var channel, tier, startTime, endTime sql.NullString
+	var from, to sql.NullInt64
+	var days int
+	dest := []any{
+		&list.ID, &list.Name, &channel, &tier, &days, &startTime,
+		&endTime, &from, &to, &list.Priority, &list.Disabled,
+	}
+	if err := row.Scan(append(dest, extra...)...); err != nil {
+		return nil, err
+	}
+	list.Channel, list.CustomerTier = channel.String, tier.String
+	list.StartTime, list.EndTime = startTime.String, endTime.String
+	list.EffectiveFrom, list.EffectiveTo = from.Int64, to.Int64
+	list.Days = maskDays(days)
+	list.Items = []*model.PriceListItem{}
+	return &list, nil
+}
+
+func priceListArgs(list *model.PriceList) []any {
+	return []any{
+		list.Name, nullString(list.Channel), nullString(list.CustomerTier), daysMask(list.Days),
+		nullString(list.StartTime), nullString(list.EndTime),
+		sql.NullInt64{Int64: list.EffectiveFrom, Valid: list.EffectiveFrom > 0},
+		sql.NullInt64{Int64: list.EffectiveTo, Valid: list.EffectiveTo > 0},
+		list.Priority, list.Disabled,
+	}
+}
+
+func priceItemKeys(items []*model.PriceQueryItem) []string {
+	keys := make([]string, len(items))
+	for i, item := range items {
+		keys[i] = model.PriceItemKey(item.ItemType, item.ItemID)
+	}
+	return keys
+}
+
+// daysMask store weekdays as bitmask, bit 0 is sunday
+func daysMask(days []int) (mask int) {
+	for _, day := range days {
+		mask |= 1 << day
+	}
+	return mask
+}
+
+func maskDays(mask int) []int {
+	days := []int{}
+	for day := 0; day < 7; day++ {
+		if mask&(1<<day) != 0 {
+			days = append(days, day)
+		}
+	}
+	return days
+}
+
+// nullString store empty string as NULL for optional column
+func nullString(value string) sql.NullString {
+	return sql.NullString{String: value, Valid: value != ""}
+}
+
+func NewPriceListSQLRepository() model.IPriceListRepository {
+	return &PriceListSQLRepository{Db: config.DbPool, Dialect: config.Dialect}
+}
@@ -0,0 +1,203 @@
+package sql_test
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/aasumitro/posbe/config"
+	repoSql "github.com/aasumitro/posbe/internal/catalog/repository/sql"
+	"github.com/aasumitro/posbe/pkg/dbtest"
+	"github.com/aasumitro/posbe/pkg/dialect"
+	"github.com/aasumitro/posbe/pkg/model"
+	"github.com/aasumitro/posbe/pkg/money"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+var priceListRowColumns = []string{
+	"id", "name", "channel", "customer_tier", "days", "start_time",
+	"end_time", "effective_from", "effective_to", "priority", "disabled",
+}
+
+type priceListRepositoryTestSuite struct {
+	suite.Suite
+	mock sqlmock.Sqlmock
+	repo model.IPriceListRepository
+}
+
+func (suite *priceListRepositoryTestSuite) SetupSuite() {
+	var err error
+	config.DbPool, suite.mock, err = sqlmock.New(
+		sqlmock.QueryMatcherOption(
+			sqlmock.QueryMatcherRegexp))
+	require.NoError(suite.T(), err)
+	suite.repo = repoSql.NewPriceListSQLRepository()
+}
+
+func (suite *priceListRepositoryTestSuite) AfterTest(_, _ string) {
+	require.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+func (suite *priceListRepositoryTestSuite) expectFind() {
+	suite.mock.ExpectQuery("FROM price_lists AS l WHERE l.id = \\$1").WithArgs(1).
+		WillReturnRows(suite.mock.NewRows(priceListRowColumns).
+			AddRow(1, "happy hour", "dine_in", nil, 62, "15:00", "17:00", nil, nil, 1, false))
+	suite.mock.ExpectQuery("FROM price_list_items WHERE price_list_id = \\$1").WithArgs(1).
+		WillReturnRows(suite.mock.NewRows([]string{"item_type", "item_id", "price"}).
+			AddRow("product", 1, 15000))
+}
+
+func (suite *priceListRepositoryTestSuite) TestRepository_All_ExpectReturnRows() {
+	suite.mock.ExpectQuery("FROM price_lists AS l ORDER BY").
+		WillReturnRows(suite.mock.NewRows(priceListRowColumns).
+			AddRow(1, "happy hour", "dine_in", nil, 62, "15:00", "17:00", nil, nil, 1, false).
+			AddRow(2, "member", nil, "gold", 0, nil, nil, 1700000000, nil, 0, false))
+	suite.mock.ExpectQuery("FROM price_list_items ORDER BY id").
+		WillReturnRows(suite.mock.NewRows([]string{"price_list_id", "item_type", "item_id", "price"}).
+			AddRow(1, "product", 1, 15000).
+			AddRow(2, "addon", 1, 0))
+	res, err := suite.repo.All(context.TODO())
+	require.NoError(suite.T(), err)
+	require.Len(suite.T(), res, 2)
+	require.Equal(suite.T(), []int{1, 2, 3, 4, 5}, res[0].Days)
+	require.Empty(suite.T(), res[1].Days)
+	require.Equal(suite.T(), int64(1700000000), res[1].EffectiveFrom)
+	require.Len(suite.T(), res[1].Items, 1)
+}
+
+func (suite *priceListRepositoryTestSuite) TestRepository_All_ExpectReturnErrorFromQuery() {
+	suite.mock.ExpectQuery("FROM price_lists AS l ORDER BY").
+		WillReturnError(errors.New("UNEXPECTED"))
+	res, err := suite.repo.All(context.TODO())
+	require.Nil(suite.T(), res)
+	require.NotNil(suite.T(), err)
+}
+
+func (suite *priceListRepositoryTestSuite) TestRepository_Find_ExpectReturnRow() {
+	suite.expectFind()
+	res, err := suite.repo.Find(context.TODO(), model.FindWithID, 1)
+	require.NoError(suite.T(), err)
+	require.Equal(suite.T(), "dine_in", res.Channel)
+	require.Len(suite.T(), res.Items, 1)
+}
+
+func (suite *priceListRepositoryTestSuite) TestRepository_Create_ExpectReturnRow() {
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectQuery("INSERT INTO price_lists").
+		WithArgs("happy hour", "dine_in", nil, 62, "15:00", "17:00", nil, nil, 1, false).
+		WillReturnRows(suite.mock.NewRows([]string{"id"}).AddRow(1))
+	suite.mock.ExpectExec("DELETE FROM price_list_items").WithArgs(1).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	suite.mock.ExpectExec("INSERT INTO price_list_items").
+		WithArgs(1, "product", 1, int64(15000)).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	suite.mock.ExpectCommit()
+	suite.expectFind()
+	res, err := suite.repo.Create(context.TODO(), &model.PriceList{
+		Name: "happy hour", Channel: "dine_in", Days: []int{1, 2, 3, 4, 5},
+		StartTime: "15:00", EndTime: "17:00", Priority: 1,
+		Items: []*model.PriceListItem{{ItemType: "product", ItemID: 1, Price: 15000}},
+	})
+	require.NoError(suite.T(), err)
+	require.Equal(suite.T(), 1, res.ID)
+}
+
+func (suite *priceListRepositoryTestSuite) TestRepository_Update_ExpectReturnErrorNoRows() {
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectExec("UPDATE price_lists SET").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	suite.mock.ExpectRollback()
+	res, err := suite.repo.Update(context.TODO(), &model.PriceList{ID: 1, Name: "x"})
+	require.Nil(suite.T(), res)
+	require.ErrorContains(suite.T(), err, "no rows")
+}
+
+func (suite *priceListRepositoryTestSuite) TestRepository_Delete_ExpectSuccess() {
+	suite.mock.ExpectExec("DELETE FROM price_lists WHERE id = \\$1").WithArgs(1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	err := suite.repo.Delete(context.TODO(), &model.PriceList{ID: 1})
+	require.NoError(suite.T(), err)
+}
+
+func (suite *priceListRepositoryTestSuite) TestRepository_Candidates_ExpectReturnRows() {
+	columns := append(append([]string{}, priceListRowColumns...), "item_type", "item_id", "price")
+	suite.mock.ExpectQuery("JOIN price_lists AS l (.+)" +
+		regexp.QuoteMeta(config.Dialect.Any("(i.item_type || ':' || i.item_id)", "$1"))).
+		WithArgs(arrayValue([]string{"product:1", "addon:2"})).
+		WillReturnRows(suite.mock.NewRows(columns).
+			AddRow(1, "happy hour", "dine_in", nil, 0, nil, nil, nil, nil, 1, false, "product", 1, 15000))
+	res, err := suite.repo.Candidates(context.TODO(), []*model.PriceQueryItem{
+		{ItemType: "product", ItemID: 1}, {ItemType: "addon", ItemID: 2}})
+	require.NoError(suite.T(), err)
+	require.Len(suite.T(), res, 1)
+	require.Equal(suite.T(), money.Amount(15000), res[0].Item.Price)
+	require.Equal(suite.T(), "happy hour", res[0].List.Name)
+}
+
+func (suite *priceListRepositoryTestSuite) TestRepository_BasePrices_ExpectReturnPrices() {
+	suite.mock.ExpectQuery("FROM products (.+) UNION ALL (.+) FROM addons").
+		WithArgs(arrayValue([]int64{1}), arrayValue([]int64{}), arrayValue([]int64{2})).
+		WillReturnRows(suite.mock.NewRows([]string{"type", "id", "price"}).
+			AddRow("product", 1, 20000).
+			AddRow("addon", 2, 5000))
+	res, err := suite.repo.BasePrices(context.TODO(), []*model.PriceQueryItem{
+		{ItemType: "product", ItemID: 1}, {ItemType: "addon", ItemID: 2}})
+	require.NoError(suite.T(), err)
+	require.Equal(suite.T(), money.Amount(20000), res["product:1"])
+	require.Equal(suite.T(), money.Amount(5000), res["addon:2"])
+}
+
+func (suite *priceListRepositoryTestSuite) TestPriceListRepository_Candidates() {
+	dbtest.Migrated(suite.T(), config.Dialect.Name())
+	ctx := context.TODO()
+	repo := repoSql.NewPriceListSQLRepository()
+	list, err := repo.Create(ctx, &model.PriceList{
+		Name: "happy hour", Channel: model.PriceChannelDineIn, Days: []int{1, 2},
+		StartTime: "15:00", EndTime: "17:00", Priority: 1,
+		Items: []*model.PriceListItem{{ItemType: model.PriceItemProduct, ItemID: 1, Price: 1000}},
+	})
+	require.NoError(suite.T(), err)
+	found, err := repo.Find(ctx, model.FindWithID, list.ID)
+	require.NoError(suite.T(), err)
+	require.Equal(suite.T(), []int{1, 2}, found.Days)
+	require.Len(suite.T(), found.Items, 1)
+
+	items := []*model.PriceQueryItem{
+		{ItemType: model.PriceItemProduct, ItemID: 1},
+		{ItemType: model.PriceItemVariant, ItemID: 1},
+		{ItemType: model.PriceItemAddon, ItemID: 1},
+	}
+	candidates, err := repo.Candidates(ctx, items)
+	require.NoError(suite.T(), err)
+	require.Len(suite.T(), candidates, 1)
+	prices, err := repo.BasePrices(ctx, items)
+	require.NoError(suite.T(), err)
+	require.Len(suite.T(), prices, 3)
+
+	list.Disabled = true
+	_, err = repo.Update(ctx, list)
+	require.NoError(suite.T(), err)
+	candidates, err = repo.Candidates(ctx, items)
+	require.NoError(suite.T(), err)
+	require.Empty(suite.T(), candidates)
+	require.NoError(suite.T(), repo.Delete(ctx, list))
+}
+
+func TestPriceListRepository(t *testing.T) {
+	for _, d := range dialect.All() {
+		t.Run(d.Name(), func(t *testing.T) {
+			config.Dialect = d
+			suite.Run(t, new(priceListRepositoryTestSuite))
+		})
+	}
+}
+
+// arrayValue is values bound as array by the dialect under test
+func arrayValue(values any) driver.Value {
+	value, _ := config.Dialect.Array(values).Value()
+	return value
+}
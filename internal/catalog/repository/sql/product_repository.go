@@ -4,125 +4,102 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"time"
 
 	"github.com/aasumitro/posbe/config"
+	"github.com/aasumitro/posbe/pkg/audit"
 	"github.com/aasumitro/posbe/pkg/model"
+	"github.com/aasumitro/posbe/pkg/money"
 )
 
+const productColumns = "id, category_id, subcategory_id, sku, image, gallery, " +
+	"name, description, price, deleted_at, deleted_by"
+
 type ProductSQLRepository struct {
 	Db *sql.DB
 }
 
 func (repo ProductSQLRepository) Search(ctx context.Context, keys []model.FindWith, values []any) (data []*model.Product, err error) {
-	q := "SELECT * FROM products "
+	q := "SELECT " + productColumns + " FROM products WHERE ($1 OR deleted_at IS NULL) "
 	var whereClause string
-	if len(keys) > 0 {
-		whereClause += "WHERE "
-
-		for i, key := range keys {
-			switch key {
-			case model.FindWithSKU:
-				data := values[i].(string)
-				whereClause += fmt.Sprintf("sku = '%s' ", data)
-			case model.FindWithCategoryID:
-				data := values[i].(int)
-				whereClause += fmt.Sprintf("category_id = %d ", data)
-			case model.FindWithSubcategoryID:
-				data := values[i].(int)
-				whereClause += fmt.Sprintf("subcategory_id = %d ", data)
-			case model.FindWithPriceInRange:
-				data := values[i].([]float32)
-				whereClause += fmt.Sprintf("price BETWEEN %f AND %f ", data[0], data[1])
-			default:
-				panic("unhandled default case")
-			}
-
-			if i != (len(keys) - 1) {
-				whereClause += "AND "
-			}
+	for i, key := range keys {
+		whereClause += "AND "
+
+		switch key {
+		case model.FindWithSKU:
+			data := values[i].(string)
+			whereClause += fmt.Sprintf("sku = '%s' ", data)
+		case model.FindWithCategoryID:
+			data := values[i].(int)
+			whereClause += fmt.Sprintf("category_id = %d ", data)
+		case model.FindWithSubcategoryID:
+			data := values[i].(int)
+			whereClause += fmt.Sprintf("subcategory_id = %d ", data)
+		case model.FindWithPriceInRange:
+			data := values[i].([]money.Amount)
+			whereClause += fmt.Sprintf("price BETWEEN %d AND %d ", data[0], data[1])
+		default:
+			panic("unhandled default case")
 		}
-
-		q += whereClause
 	}
+	q += whereClause
 
-	rows, err := repo.Db.QueryContext(ctx, q)
+	rows, err := repo.Db.QueryContext(ctx, q, model.IncludeDeleted(ctx))
 	if err != nil {
 		return nil, err
 	}
 	defer func(rows *sql.Rows) { _ = rows.Close() }(rows)
 
 	for rows.Next() {
-		var product model.Product
-
-		if err := rows.Scan(
-			&product.ID, &product.CategoryID, &product.SubcategoryID,
-			&product.Sku, &product.Image, &product.Gallery, &product.Name,
-			&product.Description, &product.Price,
-		); err != nil {
+		product, err := scanProduct(rows)
+		if err != nil {
 			return nil, err
 		}
 
-		data = append(data, &product)
+		data = append(data, product)
 	}
 
 	return data, nil
 }
 
 func (repo ProductSQLRepository) All(ctx context.Context) (data []*model.Product, err error) {
-	q := "SELECT * FROM products"
-	rows, err := repo.Db.QueryContext(ctx, q)
+	q := "SELECT " + productColumns + " FROM products WHERE ($1 OR deleted_at IS NULL)"
+	rows, err := repo.Db.QueryContext(ctx, q, model.IncludeDeleted(ctx))
 	if err != nil {
 		return nil, err
 	}
 	defer func(rows *sql.Rows) { _ = rows.Close() }(rows)
 
 	for rows.Next() {
-		var product model.Product
-
-		if err := rows.Scan(
-			&product.ID, &product.CategoryID, &product.SubcategoryID,
-			&product.Sku, &product.Image, &product.Gallery, &product.Name,
-			&product.Description, &product.Price,
-		); err != nil {
+		product, err := scanProduct(rows)
+		if err != nil {
 			return nil, err
 		}
 
-		data = append(data, &product)
+		data = append(data, product)
 	}
 
 	return data, nil
 }
 
 func (repo ProductSQLRepository) Find(ctx context.Context, _ model.FindWith, val any) (data *model.Product, err error) {
-	q := "SELECT * FROM products WHERE id = $1 LIMIT 1"
-	row := repo.Db.QueryRowContext(ctx, q, val)
+	q := "SELECT " + productColumns + " FROM products WHERE id = $1 AND ($2 OR deleted_at IS NULL) LIMIT 1"
+	row := repo.Db.QueryRowContext(ctx, q, val, model.IncludeDeleted(ctx))
 
-	data = &model.Product{}
-	if err := row.Scan(
-		&data.ID, &data.CategoryID, &data.SubcategoryID,
-		&data.Sku, &data.Image, &data.Gallery, &data.Name,
-		&data.Description, &data.Price,
-	); err != nil {
-		return nil, err
-	}
-
-	return data, nil
+	return scanProduct(row)
 }
 
 func (repo ProductSQLRepository) Create(ctx context.Context, params *model.Product) (data *model.Product, err error) {
 	q := "INSERT INTO products "
 	q += "(category_id, subcategory_id, sku, image, gallery, name, description, price) "
-	q += "VALUES ($1, $2, $3, $4, $5, $6, $7, $8) RETURNING *"
-	row := repo.Db.QueryRowContext(ctx, q, params.CategoryID, params.SubcategoryID,
-		params.Sku, params.Image, params.Gallery, params.Name,
-		params.Description, params.Price)
-
-	data = &model.Product{}
-	if err := row.Scan(
-		&data.ID, &data.CategoryID, &data.SubcategoryID,
-		&data.Sku, &data.Image, &data.Gallery, &data.Name,
-		&data.Description, &data.Price,
-	); err != nil {
+	q += "VALUES ($1, $2, $3, $4, $5, $6, $7, $8) RETURNING " + productColumns
+	if err := audit.Versioned(ctx, repo.Db, func(tx *sql.Tx) error {
+		row := tx.QueryRowContext(ctx, q, params.CategoryID, params.SubcategoryID,
+			params.Sku, params.Image, params.Gallery, params.Name,
+			params.Description, params.Price)
+		data, err = scanProduct(row)
+		return err
+	}); err != nil {
 		return nil, err
 	}
 
@@ -131,16 +108,49 @@ func (repo ProductSQLRepository) Create(ctx context.Context, params *model.Produ
 
 func (repo ProductSQLRepository) Update(ctx context.Context, params *model.Product) (data *model.Product, err error) {
 	q := "UPDATE products SET category_id = $1, subcategory_id = $2, sku = $3, image = $4, "
-	q += "gallery = $5, name = $6, description = $7, price = $8 WHERE id = $9 RETURNING *"
-	row := repo.Db.QueryRowContext(ctx, q, params.CategoryID, params.SubcategoryID,
-		params.Sku, params.Image, params.Gallery, params.Name,
-		params.Description, params.Price, params.ID)
+	q += "gallery = $5, name = $6, description = $7, price = $8 "
+	q += "WHERE id = $9 AND deleted_at IS NULL RETURNING " + productColumns
+	if err := audit.Versioned(ctx, repo.Db, func(tx *sql.Tx) error {
+		row := tx.QueryRowContext(ctx, q, params.CategoryID, params.SubcategoryID,
+			params.Sku, params.Image, params.Gallery, params.Name,
+			params.Description, params.Price, params.ID)
+		data, err = scanProduct(row)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+func (repo ProductSQLRepository) Delete(ctx context.Context, params *model.Product) error {
+	q := "UPDATE products SET deleted_at = $1, deleted_by = $2 WHERE id = $3 AND deleted_at IS NULL"
+	return audit.Versioned(ctx, repo.Db, func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, q, time.Now().Unix(), audit.NullActor(ctx), params.ID)
+		return err
+	})
+}
 
+func (repo ProductSQLRepository) Restore(ctx context.Context, params *model.Product) (data *model.Product, err error) {
+	q := "UPDATE products SET deleted_at = NULL, deleted_by = NULL "
+	q += "WHERE id = $1 AND deleted_at IS NOT NULL RETURNING " + productColumns
+	if err := audit.Versioned(ctx, repo.Db, func(tx *sql.Tx) error {
+		data, err = scanProduct(tx.QueryRowContext(ctx, q, params.ID))
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+func scanProduct(row scanner) (data *model.Product, err error) {
 	data = &model.Product{}
 	if err := row.Scan(
 		&data.ID, &data.CategoryID, &data.SubcategoryID,
 		&data.Sku, &data.Image, &data.Gallery, &data.Name,
 		&data.Description, &data.Price,
+		&data.DeletedAt, &data.DeletedBy,
 	); err != nil {
 		return nil, err
 	}
@@ -148,12 +158,6 @@ func (repo ProductSQLRepository) Update(ctx context.Context, params *model.Produ
 	return data, nil
 }
 
-func (repo ProductSQLRepository) Delete(ctx context.Context, params *model.Product) error {
-	q := "DELETE FROM products WHERE id = $1"
-	_, err := repo.Db.ExecContext(ctx, q, params.ID)
-	return err
-}
-
 func NewProductSQLRepository() model.ICRUDWithSearchRepository[model.Product] {
-	return &ProductSQLRepository{Db: config.PostgresPool}
+	return &ProductSQLRepository{Db: config.DbPool}
 }
@@ -5,12 +5,16 @@ import (
 	"database/sql"
 	"errors"
 	"regexp"
+	"strconv"
 	"testing"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/aasumitro/posbe/config"
 	repoSql "github.com/aasumitro/posbe/internal/catalog/repository/sql"
+	"github.com/aasumitro/posbe/pkg/dbtest"
+	"github.com/aasumitro/posbe/pkg/dialect"
 	"github.com/aasumitro/posbe/pkg/model"
+	"github.com/aasumitro/posbe/pkg/money"
 	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
 )
@@ -24,7 +28,7 @@ type productRepositoryTestSuite struct {
 func (suite *productRepositoryTestSuite) SetupSuite() {
 	var err error
 
-	config.PostgresPool, suite.mock, err = sqlmock.New(
+	config.DbPool, suite.mock, err = sqlmock.New(
 		sqlmock.QueryMatcherOption(
 			sqlmock.QueryMatcherRegexp))
 	require.NoError(suite.T(), err)
@@ -34,11 +38,11 @@ func (suite *productRepositoryTestSuite) SetupSuite() {
 
 func (suite *productRepositoryTestSuite) TestRepository_Search_ExpectReturnRows() {
 	data := suite.mock.
-		NewRows([]string{"id", "category_id", "subcategory_id", "sku", "image", "gallery", "name", "price", "description"}).
-		AddRow(1, 1, 1, "12", "test", "test", "test", "test", 12)
+		NewRows([]string{"id", "category_id", "subcategory_id", "sku", "image", "gallery", "name", "price", "description", "deleted_at", "deleted_by"}).
+		AddRow(1, 1, 1, "12", "test", "[]", "test", "test", 12, nil, nil)
 	keys := []model.FindWith{model.FindWithCategoryID, model.FindWithSubcategoryID, model.FindWithSKU, model.FindWithPriceInRange}
-	values := []any{1, 1, "12", []float32{10, 12}}
-	query := "SELECT * FROM products WHERE category_id = 1 AND subcategory_id = 1 AND sku = '12' AND price BETWEEN 10.000000 AND 12.000000"
+	values := []any{1, 1, "12", []money.Amount{1000, 1200}}
+	query := "SELECT id, category_id, subcategory_id, sku, image, gallery, name, description, price, deleted_at, deleted_by FROM products WHERE ($1 OR deleted_at IS NULL) AND category_id = 1 AND subcategory_id = 1 AND sku = '12' AND price BETWEEN 1000 AND 1200"
 	meta := regexp.QuoteMeta(query)
 	suite.mock.ExpectQuery(meta).WillReturnRows(data)
 	res, err := suite.repo.Search(context.TODO(), keys, values)
@@ -48,8 +52,8 @@ func (suite *productRepositoryTestSuite) TestRepository_Search_ExpectReturnRows(
 }
 func (suite *productRepositoryTestSuite) TestRepository_Search_ExpectReturnErrorFromQuery() {
 	keys := []model.FindWith{model.FindWithCategoryID, model.FindWithSubcategoryID, model.FindWithSKU, model.FindWithPriceInRange}
-	values := []any{1, 1, "12", []float32{10, 12}}
-	query := "SELECT * FROM products WHERE category_id = 1 AND subcategory_id = 1 AND sku = '12' AND price BETWEEN 10.000000 AND 12.000000"
+	values := []any{1, 1, "12", []money.Amount{1000, 1200}}
+	query := "SELECT id, category_id, subcategory_id, sku, image, gallery, name, description, price, deleted_at, deleted_by FROM products WHERE ($1 OR deleted_at IS NULL) AND category_id = 1 AND subcategory_id = 1 AND sku = '12' AND price BETWEEN 1000 AND 1200"
 	meta := regexp.QuoteMeta(query)
 	suite.mock.ExpectQuery(meta).WillReturnError(errors.New(""))
 	res, err := suite.repo.Search(context.TODO(), keys, values)
@@ -58,11 +62,11 @@ func (suite *productRepositoryTestSuite) TestRepository_Search_ExpectReturnError
 }
 func (suite *productRepositoryTestSuite) TestRepository_Search_ExpectReturnErrorFromScan() {
 	data := suite.mock.
-		NewRows([]string{"id", "category_id", "subcategory_id", "sku", "image", "gallery", "name", "price", "description"}).
-		AddRow(1, nil, nil, nil, nil, nil, nil, nil, nil)
+		NewRows([]string{"id", "category_id", "subcategory_id", "sku", "image", "gallery", "name", "price", "description", "deleted_at", "deleted_by"}).
+		AddRow(1, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
 	keys := []model.FindWith{model.FindWithCategoryID, model.FindWithSubcategoryID, model.FindWithSKU, model.FindWithPriceInRange}
-	values := []any{1, 1, "12", []float32{10, 12}}
-	query := "SELECT * FROM products WHERE category_id = 1 AND subcategory_id = 1 AND sku = '12' AND price BETWEEN 10.000000 AND 12.000000"
+	values := []any{1, 1, "12", []money.Amount{1000, 1200}}
+	query := "SELECT id, category_id, subcategory_id, sku, image, gallery, name, description, price, deleted_at, deleted_by FROM products WHERE ($1 OR deleted_at IS NULL) AND category_id = 1 AND subcategory_id = 1 AND sku = '12' AND price BETWEEN 1000 AND 1200"
 	meta := regexp.QuoteMeta(query)
 	suite.mock.ExpectQuery(meta).WillReturnRows(data)
 	res, err := suite.repo.Search(context.TODO(), keys, values)
@@ -72,9 +76,9 @@ func (suite *productRepositoryTestSuite) TestRepository_Search_ExpectReturnError
 
 func (suite *productRepositoryTestSuite) TestRepository_All_ExpectReturnRows() {
 	data := suite.mock.
-		NewRows([]string{"id", "category_id", "subcategory_id", "sku", "image", "gallery", "name", "price", "description"}).
-		AddRow(1, 1, 1, "12", "test", "test", "test", "test", 12)
-	query := "SELECT * FROM products"
+		NewRows([]string{"id", "category_id", "subcategory_id", "sku", "image", "gallery", "name", "price", "description", "deleted_at", "deleted_by"}).
+		AddRow(1, 1, 1, "12", "test", "[]", "test", "test", 12, nil, nil)
+	query := "SELECT id, category_id, subcategory_id, sku, image, gallery, name, description, price, deleted_at, deleted_by FROM products WHERE ($1 OR deleted_at IS NULL)"
 	meta := regexp.QuoteMeta(query)
 	suite.mock.ExpectQuery(meta).WillReturnRows(data)
 	res, err := suite.repo.All(context.TODO())
@@ -83,7 +87,7 @@ func (suite *productRepositoryTestSuite) TestRepository_All_ExpectReturnRows() {
 	require.NotNil(suite.T(), res)
 }
 func (suite *productRepositoryTestSuite) TestRepository_All_ExpectReturnErrorFromQuery() {
-	query := "SELECT * FROM products"
+	query := "SELECT id, category_id, subcategory_id, sku, image, gallery, name, description, price, deleted_at, deleted_by FROM products WHERE ($1 OR deleted_at IS NULL)"
 	meta := regexp.QuoteMeta(query)
 	suite.mock.ExpectQuery(meta).WillReturnError(errors.New(""))
 	res, err := suite.repo.All(context.TODO())
@@ -92,9 +96,9 @@ func (suite *productRepositoryTestSuite) TestRepository_All_ExpectReturnErrorFro
 }
 func (suite *productRepositoryTestSuite) TestRepository_All_ExpectReturnErrorFromScan() {
 	data := suite.mock.
-		NewRows([]string{"id", "category_id", "subcategory_id", "sku", "image", "gallery", "name", "price", "description"}).
-		AddRow(1, nil, nil, nil, nil, nil, nil, nil, nil)
-	query := "SELECT * FROM products"
+		NewRows([]string{"id", "category_id", "subcategory_id", "sku", "image", "gallery", "name", "price", "description", "deleted_at", "deleted_by"}).
+		AddRow(1, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+	query := "SELECT id, category_id, subcategory_id, sku, image, gallery, name, description, price, deleted_at, deleted_by FROM products WHERE ($1 OR deleted_at IS NULL)"
 	meta := regexp.QuoteMeta(query)
 	suite.mock.ExpectQuery(meta).WillReturnRows(data)
 	res, err := suite.repo.All(context.TODO())
@@ -104,9 +108,9 @@ func (suite *productRepositoryTestSuite) TestRepository_All_ExpectReturnErrorFro
 
 func (suite *productRepositoryTestSuite) TestRepository_Find_ExpectReturnRow() {
 	data := suite.mock.
-		NewRows([]string{"id", "category_id", "subcategory_id", "sku", "image", "gallery", "name", "price", "description"}).
-		AddRow(1, 1, 1, "12", "test", "test", "test", "test", 12)
-	query := "SELECT * FROM products WHERE id = $1 LIMIT 1"
+		NewRows([]string{"id", "category_id", "subcategory_id", "sku", "image", "gallery", "name", "price", "description", "deleted_at", "deleted_by"}).
+		AddRow(1, 1, 1, "12", "test", "[]", "test", "test", 12, nil, nil)
+	query := "SELECT id, category_id, subcategory_id, sku, image, gallery, name, description, price, deleted_at, deleted_by FROM products WHERE id = $1 AND ($2 OR deleted_at IS NULL) LIMIT 1"
 	meta := regexp.QuoteMeta(query)
 	suite.mock.ExpectQuery(meta).WillReturnRows(data)
 	res, err := suite.repo.Find(context.TODO(), model.FindWithID, 1)
@@ -116,9 +120,9 @@ func (suite *productRepositoryTestSuite) TestRepository_Find_ExpectReturnRow() {
 }
 func (suite *productRepositoryTestSuite) TestRepository_Find_ExpectReturnError() {
 	data := suite.mock.
-		NewRows([]string{"id", "category_id", "subcategory_id", "sku", "image", "gallery", "name", "price", "description"}).
-		AddRow(1, nil, nil, nil, nil, nil, nil, nil, nil)
-	query := "SELECT * FROM products WHERE id = $1 LIMIT 1"
+		NewRows([]string{"id", "category_id", "subcategory_id", "sku", "image", "gallery", "name", "price", "description", "deleted_at", "deleted_by"}).
+		AddRow(1, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+	query := "SELECT id, category_id, subcategory_id, sku, image, gallery, name, description, price, deleted_at, deleted_by FROM products WHERE id = $1 AND ($2 OR deleted_at IS NULL) LIMIT 1"
 	meta := regexp.QuoteMeta(query)
 	suite.mock.ExpectQuery(meta).WillReturnRows(data)
 	res, err := suite.repo.Find(context.TODO(), model.FindWithID, 1)
@@ -127,89 +131,166 @@ func (suite *productRepositoryTestSuite) TestRepository_Find_ExpectReturnError()
 }
 
 func (suite *productRepositoryTestSuite) TestRepository_Created_ExpectSuccess() {
-	product := &model.Product{ID: 1, CategoryID: 1, SubcategoryID: 1, Sku: "12", Image: sql.NullString{String: "test"}, Gallery: sql.NullString{String: "test"}, Name: "test", Price: 12, Description: sql.NullString{String: "test"}}
+	product := &model.Product{ID: 1, CategoryID: 1, SubcategoryID: 1, Sku: "12", Image: sql.NullString{String: "test"}, Gallery: model.ProductGallery{}, Name: "test", Price: 12, Description: sql.NullString{String: "test"}}
 	data := suite.mock.
-		NewRows([]string{"id", "category_id", "subcategory_id", "sku", "image", "gallery", "name", "price", "description"}).
-		AddRow(1, 1, 1, "12", "test", "test", "test", "test", 12)
+		NewRows([]string{"id", "category_id", "subcategory_id", "sku", "image", "gallery", "name", "price", "description", "deleted_at", "deleted_by"}).
+		AddRow(1, 1, 1, "12", "test", "[]", "test", "test", 12, nil, nil)
 	q := "INSERT INTO products "
 	q += "(category_id, subcategory_id, sku, image, gallery, name, description, price) "
-	q += "VALUES ($1, $2, $3, $4, $5, $6, $7, $8) RETURNING *"
+	q += "VALUES ($1, $2, $3, $4, $5, $6, $7, $8) RETURNING id, category_id, subcategory_id, sku, image, gallery, name, description, price, deleted_at, deleted_by"
 	meta := regexp.QuoteMeta(q)
+	expectVersioned(suite.mock)
 	suite.mock.ExpectQuery(meta).
 		WithArgs(product.CategoryID, product.SubcategoryID,
 			product.Sku, product.Image, product.Gallery, product.Name,
 			product.Description, product.Price).
 		WillReturnRows(data).
 		WillReturnError(nil)
+	expectVersionedCommit(suite.mock)
 	res, err := suite.repo.Create(context.TODO(), product)
 	require.Nil(suite.T(), err)
 	require.NotNil(suite.T(), res)
 }
 func (suite *productRepositoryTestSuite) TestRepository_Created_ExpectError() {
-	product := &model.Product{ID: 1, CategoryID: 1, SubcategoryID: 1, Sku: "12", Image: sql.NullString{String: "test"}, Gallery: sql.NullString{String: "test"}, Name: "test", Price: 12, Description: sql.NullString{String: "test"}}
+	product := &model.Product{ID: 1, CategoryID: 1, SubcategoryID: 1, Sku: "12", Image: sql.NullString{String: "test"}, Gallery: model.ProductGallery{}, Name: "test", Price: 12, Description: sql.NullString{String: "test"}}
 	data := suite.mock.
-		NewRows([]string{"id", "category_id", "subcategory_id", "sku", "image", "gallery", "name", "price", "description"}).
-		AddRow(1, nil, nil, nil, nil, nil, nil, nil, nil)
+		NewRows([]string{"id", "category_id", "subcategory_id", "sku", "image", "gallery", "name", "price", "description", "deleted_at", "deleted_by"}).
+		AddRow(1, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
 	q := "INSERT INTO products "
 	q += "(category_id, subcategory_id, sku, image, gallery, name, description, price) "
-	q += "VALUES ($1, $2, $3, $4, $5, $6, $7, $8) RETURNING *"
+	q += "VALUES ($1, $2, $3, $4, $5, $6, $7, $8) RETURNING id, category_id, subcategory_id, sku, image, gallery, name, description, price, deleted_at, deleted_by"
 	meta := regexp.QuoteMeta(q)
+	expectVersioned(suite.mock)
 	suite.mock.ExpectQuery(meta).
 		WithArgs(product.CategoryID, product.SubcategoryID,
 			product.Sku, product.Image, product.Gallery, product.Name,
 			product.Description, product.Price).
 		WillReturnRows(data).
 		WillReturnError(nil)
+	suite.mock.ExpectRollback()
 	res, err := suite.repo.Create(context.TODO(), product)
 	require.Nil(suite.T(), res)
 	require.NotNil(suite.T(), err)
 }
 
 func (suite *productRepositoryTestSuite) TestRepository_Updated_ExpectSuccess() {
-	product := &model.Product{ID: 1, CategoryID: 1, SubcategoryID: 1, Sku: "12", Image: sql.NullString{String: "test"}, Gallery: sql.NullString{String: "test"}, Name: "test", Price: 12, Description: sql.NullString{String: "test"}}
+	product := &model.Product{ID: 1, CategoryID: 1, SubcategoryID: 1, Sku: "12", Image: sql.NullString{String: "test"}, Gallery: model.ProductGallery{}, Name: "test", Price: 12, Description: sql.NullString{String: "test"}}
 	data := suite.mock.
-		NewRows([]string{"id", "category_id", "subcategory_id", "sku", "image", "gallery", "name", "price", "description"}).
-		AddRow(1, 1, 1, "12", "test", "test", "test", "test", 12)
-	query := "UPDATE products SET category_id = $1, subcategory_id = $2, sku = $3, image = $4, gallery = $5, name = $6, description = $7, price = $8 WHERE id = $9 RETURNING *"
+		NewRows([]string{"id", "category_id", "subcategory_id", "sku", "image", "gallery", "name", "price", "description", "deleted_at", "deleted_by"}).
+		AddRow(1, 1, 1, "12", "test", "[]", "test", "test", 12, nil, nil)
+	query := "UPDATE products SET category_id = $1, subcategory_id = $2, sku = $3, image = $4, gallery = $5, name = $6, description = $7, price = $8 WHERE id = $9 AND deleted_at IS NULL RETURNING id, category_id, subcategory_id, sku, image, gallery, name, description, price, deleted_at, deleted_by"
 	meta := regexp.QuoteMeta(query)
+	expectVersioned(suite.mock)
 	suite.mock.ExpectQuery(meta).
 		WithArgs(product.CategoryID, product.SubcategoryID,
 			product.Sku, product.Image, product.Gallery, product.Name,
 			product.Description, product.Price, product.ID).
 		WillReturnRows(data).
 		WillReturnError(nil)
+	expectVersionedCommit(suite.mock)
 	res, err := suite.repo.Update(context.TODO(), product)
 	require.Nil(suite.T(), err)
 	require.NotNil(suite.T(), res)
 }
 func (suite *productRepositoryTestSuite) TestRepository_Updated_ExpectError() {
-	product := &model.Product{ID: 1, CategoryID: 1, SubcategoryID: 1, Sku: "12", Image: sql.NullString{String: "test"}, Gallery: sql.NullString{String: "test"}, Name: "test", Price: 12, Description: sql.NullString{String: "test"}}
+	product := &model.Product{ID: 1, CategoryID: 1, SubcategoryID: 1, Sku: "12", Image: sql.NullString{String: "test"}, Gallery: model.ProductGallery{}, Name: "test", Price: 12, Description: sql.NullString{String: "test"}}
 	data := suite.mock.
-		NewRows([]string{"id", "category_id", "subcategory_id", "sku", "image", "gallery", "name", "price", "description"}).
-		AddRow(1, nil, nil, nil, nil, nil, nil, nil, nil)
-	query := "UPDATE products SET category_id = $1, subcategory_id = $2, sku = $3, image = $4, gallery = $5, name = $6, description = $7, price = $8 WHERE id = $9 RETURNING *"
+		NewRows([]string{"id", "category_id", "subcategory_id", "sku", "image", "gallery", "name", "price", "description", "deleted_at", "deleted_by"}).
+		AddRow(1, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+	query := "UPDATE products SET category_id = $1, subcategory_id = $2, sku = $3, image = $4, gallery = $5, name = $6, description = $7, price = $8 WHERE id = $9 AND deleted_at IS NULL RETURNING id, category_id, subcategory_id, sku, image, gallery, name, description, price, deleted_at, deleted_by"
 	meta := regexp.QuoteMeta(query)
+	expectVersioned(suite.mock)
 	suite.mock.ExpectQuery(meta).
 		WithArgs(product.CategoryID, product.SubcategoryID,
 			product.Sku, product.Image, product.Gallery, product.Name,
 			product.Description, product.Price, product.ID).
 		WillReturnRows(data).
 		WillReturnError(nil)
+	suite.mock.ExpectRollback()
 	res, err := suite.repo.Update(context.TODO(), product)
 	require.Nil(suite.T(), res)
 	require.NotNil(suite.T(), err)
 }
 
 func (suite *productRepositoryTestSuite) TestRepository_Delete_ExpectSuccess() {
-	expectedQuery := regexp.QuoteMeta("DELETE FROM products WHERE id = $1")
+	expectedQuery := regexp.QuoteMeta("UPDATE products SET deleted_at = $1, deleted_by = $2 WHERE id = $3 AND deleted_at IS NULL")
+	expectVersioned(suite.mock)
 	suite.mock.ExpectExec(expectedQuery).
-		WithArgs(1).
+		WithArgs(sqlmock.AnyArg(), nil, 1).
 		WillReturnResult(sqlmock.NewResult(0, 1))
 	data := &model.Product{ID: 1}
+	expectVersionedCommit(suite.mock)
 	err := suite.repo.Delete(context.TODO(), data)
 	require.Nil(suite.T(), err)
 }
 
+func (suite *productRepositoryTestSuite) TestProductRepository_ShouldRecordVersions() {
+	dbtest.Migrated(suite.T(), config.Dialect.Name())
+	ctx := context.TODO()
+	products := repoSql.NewProductSQLRepository()
+	variants := repoSql.NewProductVariantSQLRepository()
+	versions := repoSql.NewEntityVersionSQLRepository()
+	product, err := products.Create(ctx, &model.Product{
+		CategoryID: 2, SubcategoryID: 3, Sku: "CFLT100", Name: "flat white", Price: 3500,
+		Gallery: model.ProductGallery{{ID: "a", URL: "/a.png", Cover: true}},
+	})
+	require.NoError(suite.T(), err)
+	require.Len(suite.T(), product.Gallery, 1)
+	variant, err := variants.Create(ctx, &model.ProductVariant{
+		ProductID: product.ID, UnitID: 4, UnitSize: 250, Type: "size", Name: "regular", Price: 3500})
+	require.NoError(suite.T(), err)
+	variant.Price = 3800
+	_, err = variants.Update(ctx, variant)
+	require.NoError(suite.T(), err)
+
+	product.Price = 4000
+	_, err = products.Update(ctx, product)
+	require.NoError(suite.T(), err)
+	found, err := products.Search(ctx,
+		[]model.FindWith{model.FindWithSKU, model.FindWithPriceInRange},
+		[]any{"CFLT100", []money.Amount{3000, 5000}})
+	require.NoError(suite.T(), err)
+	require.Len(suite.T(), found, 1)
+
+	require.NoError(suite.T(), products.Delete(ctx, product))
+	_, err = products.Find(ctx, model.FindWithID, product.ID)
+	require.ErrorIs(suite.T(), err, sql.ErrNoRows)
+	_, err = products.Restore(ctx, product)
+	require.NoError(suite.T(), err)
+
+	// create, update, delete and restore
+	history, err := versions.Versions(ctx, "products", strconv.Itoa(product.ID))
+	require.NoError(suite.T(), err)
+	require.Len(suite.T(), history, 4)
+	history, err = versions.Versions(ctx, "product_variants", strconv.Itoa(variant.ID))
+	require.NoError(suite.T(), err)
+	require.Len(suite.T(), history, 2)
+	asOf, err := versions.AsOf(ctx, "products", []string{strconv.Itoa(product.ID)}, history[1].ValidFrom)
+	require.NoError(suite.T(), err)
+	require.Len(suite.T(), asOf, 1)
+}
+
 func TestProductRepository(t *testing.T) {
-	suite.Run(t, new(productRepositoryTestSuite))
+	for _, d := range dialect.All() {
+		t.Run(d.Name(), func(t *testing.T) {
+			config.Dialect = d
+			suite.Run(t, new(productRepositoryTestSuite))
+		})
+	}
+}
+
+// expectVersioned expect the transaction of a versioned write with
+// the actor of the dialect, the rollback is expected by the test
+func expectVersioned(mock sqlmock.Sqlmock) {
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(config.Dialect.SetActor())).
+		WithArgs("").WillReturnResult(sqlmock.NewResult(0, 0))
+}
+
+// expectVersionedCommit expect the actor to be reset before commit
+func expectVersionedCommit(mock sqlmock.Sqlmock) {
+	if q := config.Dialect.ResetActor(); q != "" {
+		mock.ExpectExec(regexp.QuoteMeta(q)).WillReturnResult(sqlmock.NewResult(0, 1))
+	}
+	mock.ExpectCommit()
 }
@@ -3,11 +3,16 @@ package sql
 import (
 	"context"
 	"database/sql"
+	"time"
 
 	"github.com/aasumitro/posbe/config"
+	"github.com/aasumitro/posbe/pkg/audit"
 	"github.com/aasumitro/posbe/pkg/model"
 )
 
+const productVariantColumns = "id, product_id, unit_id, unit_size, type, name, " +
+	"description, price, deleted_at, deleted_by"
+
 type ProductVariantSQLRepository struct {
 	Db *sql.DB
 }
@@ -17,46 +22,69 @@ func (repo ProductVariantSQLRepository) All(_ context.Context) (data []*model.Pr
 }
 
 func (repo ProductVariantSQLRepository) Find(ctx context.Context, _ model.FindWith, val any) (data *model.ProductVariant, err error) {
-	q := "SELECT * FROM product_variants WHERE id = $1 LIMIT 1"
-	row := repo.Db.QueryRowContext(ctx, q, val)
+	q := "SELECT " + productVariantColumns + " FROM product_variants "
+	q += "WHERE id = $1 AND ($2 OR deleted_at IS NULL) LIMIT 1"
+	row := repo.Db.QueryRowContext(ctx, q, val, model.IncludeDeleted(ctx))
 
-	data = &model.ProductVariant{}
-	if err := row.Scan(
-		&data.ID, &data.ProductID, &data.UnitID,
-		&data.UnitSize, &data.Type, &data.Name,
-		&data.Description, &data.Price,
-	); err != nil {
+	return scanProductVariant(row)
+}
+
+func (repo ProductVariantSQLRepository) Create(ctx context.Context, params *model.ProductVariant) (data *model.ProductVariant, err error) {
+	q := "INSERT INTO product_variants (product_id, unit_id, unit_size, type, name, description, price) "
+	q += "VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING " + productVariantColumns
+	if err := audit.Versioned(ctx, repo.Db, func(tx *sql.Tx) error {
+		row := tx.QueryRowContext(ctx, q, params.ProductID, params.UnitID, params.UnitSize, params.Type, params.Name, params.Description, params.Price)
+		data, err = scanProductVariant(row)
+		return err
+	}); err != nil {
 		return nil, err
 	}
 
 	return data, nil
 }
 
-func (repo ProductVariantSQLRepository) Create(ctx context.Context, params *model.ProductVariant) (data *model.ProductVariant, err error) {
-	q := "INSERT INTO product_variants (product_id, unit_id, unit_size, type, name, description, price) VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING *"
-	row := repo.Db.QueryRowContext(ctx, q, params.ProductID, params.UnitID, params.UnitSize, params.Type, params.Name, params.Description, params.Price)
-
-	data = &model.ProductVariant{}
-	if err := row.Scan(
-		&data.ID, &data.ProductID, &data.UnitID,
-		&data.UnitSize, &data.Type, &data.Name,
-		&data.Description, &data.Price,
-	); err != nil {
+func (repo ProductVariantSQLRepository) Update(ctx context.Context, params *model.ProductVariant) (data *model.ProductVariant, err error) {
+	q := "UPDATE product_variants SET product_id = $1, unit_id = $2, unit_size = $3, type = $4, name = $5, description = $6, price = $7 "
+	q += "WHERE id = $8 AND deleted_at IS NULL RETURNING " + productVariantColumns
+	if err := audit.Versioned(ctx, repo.Db, func(tx *sql.Tx) error {
+		row := tx.QueryRowContext(ctx, q, params.ProductID, params.UnitID, params.UnitSize, params.Type, params.Name, params.Description, params.Price, params.ID)
+		data, err = scanProductVariant(row)
+		return err
+	}); err != nil {
 		return nil, err
 	}
 
 	return data, nil
 }
 
-func (repo ProductVariantSQLRepository) Update(ctx context.Context, params *model.ProductVariant) (data *model.ProductVariant, err error) {
-	q := "UPDATE product_variants SET product_id = $1, unit_id = $2, unit_size = $3, type = $4, name = $5, description = $6, price = $7 WHERE id = $8 RETURNING *"
-	row := repo.Db.QueryRowContext(ctx, q, params.ProductID, params.UnitID, params.UnitSize, params.Type, params.Name, params.Description, params.Price, params.ID)
+func (repo ProductVariantSQLRepository) Delete(ctx context.Context, params *model.ProductVariant) error {
+	q := "UPDATE product_variants SET deleted_at = $1, deleted_by = $2 WHERE id = $3 AND deleted_at IS NULL"
+	return audit.Versioned(ctx, repo.Db, func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, q, time.Now().Unix(), audit.NullActor(ctx), params.ID)
+		return err
+	})
+}
 
+func (repo ProductVariantSQLRepository) Restore(ctx context.Context, params *model.ProductVariant) (data *model.ProductVariant, err error) {
+	q := "UPDATE product_variants SET deleted_at = NULL, deleted_by = NULL "
+	q += "WHERE id = $1 AND deleted_at IS NOT NULL RETURNING " + productVariantColumns
+	if err := audit.Versioned(ctx, repo.Db, func(tx *sql.Tx) error {
+		data, err = scanProductVariant(tx.QueryRowContext(ctx, q, params.ID))
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+func scanProductVariant(row scanner) (data *model.ProductVariant, err error) {
 	data = &model.ProductVariant{}
 	if err := row.Scan(
 		&data.ID, &data.ProductID, &data.UnitID,
 		&data.UnitSize, &data.Type, &data.Name,
 		&data.Description, &data.Price,
+		&data.DeletedAt, &data.DeletedBy,
 	); err != nil {
 		return nil, err
 	}
@@ -64,12 +92,6 @@ func (repo ProductVariantSQLRepository) Update(ctx context.Context, params *mode
 	return data, nil
 }
 
-func (repo ProductVariantSQLRepository) Delete(ctx context.Context, params *model.ProductVariant) error {
-	q := "DELETE FROM product_variants WHERE id = $1"
-	_, err := repo.Db.ExecContext(ctx, q, params.ID)
-	return err
-}
-
-func NewProductVariantSQLRepository() model.ICRUDRepository[model.ProductVariant] {
-	return &ProductVariantSQLRepository{Db: config.PostgresPool}
+func NewProductVariantSQLRepository() model.ISoftDeleteRepository[model.ProductVariant] {
+	return &ProductVariantSQLRepository{Db: config.DbPool}
 }
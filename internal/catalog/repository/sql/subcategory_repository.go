@@ -3,81 +3,83 @@ package sql
 import (
 	"context"
 	"database/sql"
+	"time"
 
 	"github.com/aasumitro/posbe/config"
+	"github.com/aasumitro/posbe/pkg/audit"
 	"github.com/aasumitro/posbe/pkg/model"
 )
 
+const subcategoryColumns = "id, category_id, name, deleted_at, deleted_by"
+
 type SubcategorySQLRepository struct {
 	Db *sql.DB
 }
 
 func (repo SubcategorySQLRepository) All(ctx context.Context) (data []*model.Subcategory, err error) {
-	q := "SELECT * FROM subcategories"
-	rows, err := repo.Db.QueryContext(ctx, q)
+	q := "SELECT " + subcategoryColumns + " FROM subcategories WHERE ($1 OR deleted_at IS NULL)"
+	rows, err := repo.Db.QueryContext(ctx, q, model.IncludeDeleted(ctx))
 	if err != nil {
 		return nil, err
 	}
 	defer func(rows *sql.Rows) { _ = rows.Close() }(rows)
 
 	for rows.Next() {
-		var subcategories model.Subcategory
-
-		if err := rows.Scan(
-			&subcategories.ID,
-			&subcategories.CategoryID,
-			&subcategories.Name,
-		); err != nil {
+		subcategory, err := scanSubcategory(rows)
+		if err != nil {
 			return nil, err
 		}
 
-		data = append(data, &subcategories)
+		data = append(data, subcategory)
 	}
 
 	return data, nil
 }
 
 func (repo SubcategorySQLRepository) Find(ctx context.Context, _ model.FindWith, val any) (data *model.Subcategory, err error) {
-	q := "SELECT * FROM subcategories WHERE id = $1 LIMIT 1"
-	row := repo.Db.QueryRowContext(ctx, q, val)
-
-	data = &model.Subcategory{}
-	if err := row.Scan(
-		&data.ID,
-		&data.CategoryID,
-		&data.Name,
-	); err != nil {
-		return nil, err
-	}
+	q := "SELECT " + subcategoryColumns + " FROM subcategories WHERE id = $1 AND ($2 OR deleted_at IS NULL) LIMIT 1"
+	row := repo.Db.QueryRowContext(ctx, q, val, model.IncludeDeleted(ctx))
 
-	return data, nil
+	return scanSubcategory(row)
 }
 
 func (repo SubcategorySQLRepository) Create(ctx context.Context, params *model.Subcategory) (data *model.Subcategory, err error) {
-	q := "INSERT INTO subcategories (category_id, name) VALUES ($1, $2) RETURNING *"
+	q := "INSERT INTO subcategories (category_id, name) VALUES ($1, $2) RETURNING " + subcategoryColumns
 	row := repo.Db.QueryRowContext(ctx, q, params.CategoryID, params.Name)
 
-	data = &model.Subcategory{}
-	if err := row.Scan(
-		&data.ID,
-		&data.CategoryID,
-		&data.Name,
-	); err != nil {
-		return nil, err
-	}
-
-	return data, nil
+	return scanSubcategory(row)
 }
 
 func (repo SubcategorySQLRepository) Update(ctx context.Context, params *model.Subcategory) (data *model.Subcategory, err error) {
-	q := "UPDATE subcategories SET category_id = $1, name = $2 WHERE id = $3 RETURNING *"
+	q := "UPDATE subcategories SET category_id = $1, name = $2 "
+	q += "WHERE id = $3 AND deleted_at IS NULL RETURNING " + subcategoryColumns
 	row := repo.Db.QueryRowContext(ctx, q, params.CategoryID, params.Name, params.ID)
 
+	return scanSubcategory(row)
+}
+
+func (repo SubcategorySQLRepository) Delete(ctx context.Context, params *model.Subcategory) error {
+	q := "UPDATE subcategories SET deleted_at = $1, deleted_by = $2 WHERE id = $3 AND deleted_at IS NULL"
+	_, err := repo.Db.ExecContext(ctx, q, time.Now().Unix(), audit.NullActor(ctx), params.ID)
+	return err
+}
+
+func (repo SubcategorySQLRepository) Restore(ctx context.Context, params *model.Subcategory) (data *model.Subcategory, err error) {
+	q := "UPDATE subcategories SET deleted_at = NULL, deleted_by = NULL "
+	q += "WHERE id = $1 AND deleted_at IS NOT NULL RETURNING " + subcategoryColumns
+	row := repo.Db.QueryRowContext(ctx, q, params.ID)
+
+	return scanSubcategory(row)
+}
+
+func scanSubcategory(row scanner) (data *model.Subcategory, err error) {
 	data = &model.Subcategory{}
 	if err := row.Scan(
 		&data.ID,
 		&data.CategoryID,
 		&data.Name,
+		&data.DeletedAt,
+		&data.DeletedBy,
 	); err != nil {
 		return nil, err
 	}
@@ -85,12 +87,6 @@ func (repo SubcategorySQLRepository) Update(ctx context.Context, params *model.S
 	return data, nil
 }
 
-func (repo SubcategorySQLRepository) Delete(ctx context.Context, params *model.Subcategory) error {
-	q := "DELETE FROM subcategories WHERE id = $1"
-	_, err := repo.Db.ExecContext(ctx, q, params.ID)
-	return err
-}
-
-func NewSubcategorySQLRepository() model.ICRUDRepository[model.Subcategory] {
-	return &SubcategorySQLRepository{Db: config.PostgresPool}
+func NewSubcategorySQLRepository() model.ISoftDeleteRepository[model.Subcategory] {
+	return &SubcategorySQLRepository{Db: config.DbPool}
 }
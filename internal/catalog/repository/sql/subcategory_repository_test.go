@@ -9,6 +9,7 @@ import (
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/aasumitro/posbe/config"
 	repoSql "github.com/aasumitro/posbe/internal/catalog/repository/sql"
+	"github.com/aasumitro/posbe/pkg/dialect"
 	"github.com/aasumitro/posbe/pkg/model"
 	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
@@ -17,13 +18,13 @@ import (
 type subcategoryRepositoryTestSuite struct {
 	suite.Suite
 	mock sqlmock.Sqlmock
-	repo model.ICRUDRepository[model.Subcategory]
+	repo model.ISoftDeleteRepository[model.Subcategory]
 }
 
 func (suite *subcategoryRepositoryTestSuite) SetupSuite() {
 	var err error
 
-	config.PostgresPool, suite.mock, err = sqlmock.New(
+	config.DbPool, suite.mock, err = sqlmock.New(
 		sqlmock.QueryMatcherOption(
 			sqlmock.QueryMatcherRegexp))
 	require.NoError(suite.T(), err)
@@ -37,10 +38,10 @@ func (suite *subcategoryRepositoryTestSuite) AfterTest(_, _ string) {
 
 func (suite *subcategoryRepositoryTestSuite) TestRepository_All_ExpectReturnRows() {
 	data := suite.mock.
-		NewRows([]string{"id", "category_id", "name"}).
-		AddRow(1, 1, "test").
-		AddRow(2, 1, "test 2")
-	query := "SELECT * FROM subcategories"
+		NewRows([]string{"id", "category_id", "name", "deleted_at", "deleted_by"}).
+		AddRow(1, 1, "test", nil, nil).
+		AddRow(2, 1, "test 2", nil, nil)
+	query := "SELECT id, category_id, name, deleted_at, deleted_by FROM subcategories WHERE ($1 OR deleted_at IS NULL)"
 	meta := regexp.QuoteMeta(query)
 	suite.mock.ExpectQuery(meta).WillReturnRows(data)
 	res, err := suite.repo.All(context.TODO())
@@ -50,7 +51,7 @@ func (suite *subcategoryRepositoryTestSuite) TestRepository_All_ExpectReturnRows
 }
 
 func (suite *subcategoryRepositoryTestSuite) TestRepository_All_ExpectReturnErrorFromQuery() {
-	query := "SELECT * FROM subcategories"
+	query := "SELECT id, category_id, name, deleted_at, deleted_by FROM subcategories WHERE ($1 OR deleted_at IS NULL)"
 	meta := regexp.QuoteMeta(query)
 	suite.mock.ExpectQuery(meta).WillReturnError(errors.New(""))
 	res, err := suite.repo.All(context.TODO())
@@ -60,10 +61,10 @@ func (suite *subcategoryRepositoryTestSuite) TestRepository_All_ExpectReturnErro
 
 func (suite *subcategoryRepositoryTestSuite) TestRepository_All_ExpectReturnErrorFromScan() {
 	data := suite.mock.
-		NewRows([]string{"id", "category_id", "name"}).
-		AddRow(1, 1, "test").
-		AddRow(nil, nil, nil)
-	query := "SELECT * FROM subcategories"
+		NewRows([]string{"id", "category_id", "name", "deleted_at", "deleted_by"}).
+		AddRow(1, 1, "test", nil, nil).
+		AddRow(nil, nil, nil, nil, nil)
+	query := "SELECT id, category_id, name, deleted_at, deleted_by FROM subcategories WHERE ($1 OR deleted_at IS NULL)"
 	meta := regexp.QuoteMeta(query)
 	suite.mock.ExpectQuery(meta).WillReturnRows(data)
 	res, err := suite.repo.All(context.TODO())
@@ -73,9 +74,9 @@ func (suite *subcategoryRepositoryTestSuite) TestRepository_All_ExpectReturnErro
 
 func (suite *subcategoryRepositoryTestSuite) TestRepository_Find_ExpectReturnRow() {
 	data := suite.mock.
-		NewRows([]string{"id", "category_id", "name"}).
-		AddRow(1, 1, "test")
-	query := "SELECT * FROM subcategories WHERE id = $1 LIMIT 1"
+		NewRows([]string{"id", "category_id", "name", "deleted_at", "deleted_by"}).
+		AddRow(1, 1, "test", nil, nil)
+	query := "SELECT id, category_id, name, deleted_at, deleted_by FROM subcategories WHERE id = $1 AND ($2 OR deleted_at IS NULL) LIMIT 1"
 	meta := regexp.QuoteMeta(query)
 	suite.mock.ExpectQuery(meta).WillReturnRows(data)
 	res, err := suite.repo.Find(context.TODO(), model.FindWithID, 1)
@@ -86,9 +87,9 @@ func (suite *subcategoryRepositoryTestSuite) TestRepository_Find_ExpectReturnRow
 
 func (suite *subcategoryRepositoryTestSuite) TestRepository_Find_ExpectReturnError() {
 	data := suite.mock.
-		NewRows([]string{"id", "category_id", "name"}).
-		AddRow(nil, nil, nil)
-	query := "SELECT * FROM subcategories WHERE id = $1 LIMIT 1"
+		NewRows([]string{"id", "category_id", "name", "deleted_at", "deleted_by"}).
+		AddRow(nil, nil, nil, nil, nil)
+	query := "SELECT id, category_id, name, deleted_at, deleted_by FROM subcategories WHERE id = $1 AND ($2 OR deleted_at IS NULL) LIMIT 1"
 	meta := regexp.QuoteMeta(query)
 	suite.mock.ExpectQuery(meta).WillReturnRows(data)
 	res, err := suite.repo.Find(context.TODO(), model.FindWithID, 1)
@@ -99,9 +100,9 @@ func (suite *subcategoryRepositoryTestSuite) TestRepository_Find_ExpectReturnErr
 func (suite *subcategoryRepositoryTestSuite) TestRepository_Created_ExpectSuccess() {
 	subcategory := &model.Subcategory{ID: 1, CategoryID: 1, Name: "test"}
 	data := suite.mock.
-		NewRows([]string{"id", "category_id", "name"}).
-		AddRow(1, 1, "test")
-	query := "INSERT INTO subcategories (category_id, name) VALUES ($1, $2) RETURNING *"
+		NewRows([]string{"id", "category_id", "name", "deleted_at", "deleted_by"}).
+		AddRow(1, 1, "test", nil, nil)
+	query := "INSERT INTO subcategories (category_id, name) VALUES ($1, $2) RETURNING id, category_id, name, deleted_at, deleted_by"
 	meta := regexp.QuoteMeta(query)
 	suite.mock.ExpectQuery(meta).
 		WithArgs(subcategory.CategoryID, subcategory.Name).
@@ -115,9 +116,9 @@ func (suite *subcategoryRepositoryTestSuite) TestRepository_Created_ExpectSucces
 func (suite *subcategoryRepositoryTestSuite) TestRepository_Created_ExpectError() {
 	subcategory := &model.Subcategory{ID: 1, CategoryID: 1, Name: "test"}
 	data := suite.mock.
-		NewRows([]string{"id", "category_id", "name"}).
-		AddRow(1, nil, nil)
-	query := "INSERT INTO subcategories (category_id, name) VALUES ($1, $2) RETURNING *"
+		NewRows([]string{"id", "category_id", "name", "deleted_at", "deleted_by"}).
+		AddRow(1, nil, nil, nil, nil)
+	query := "INSERT INTO subcategories (category_id, name) VALUES ($1, $2) RETURNING id, category_id, name, deleted_at, deleted_by"
 	meta := regexp.QuoteMeta(query)
 	suite.mock.ExpectQuery(meta).
 		WithArgs(subcategory.CategoryID, subcategory.Name).
@@ -131,9 +132,9 @@ func (suite *subcategoryRepositoryTestSuite) TestRepository_Created_ExpectError(
 func (suite *subcategoryRepositoryTestSuite) TestRepository_Updated_ExpectSuccess() {
 	subcategory := &model.Subcategory{ID: 1, CategoryID: 1, Name: "test"}
 	data := suite.mock.
-		NewRows([]string{"id", "category_id", "name"}).
-		AddRow(1, 1, "test")
-	query := "UPDATE subcategories SET category_id = $1, name = $2 WHERE id = $3 RETURNING *"
+		NewRows([]string{"id", "category_id", "name", "deleted_at", "deleted_by"}).
+		AddRow(1, 1, "test", nil, nil)
+	query := "UPDATE subcategories SET category_id = $1, name = $2 WHERE id = $3 AND deleted_at IS NULL RETURNING id, category_id, name, deleted_at, deleted_by"
 	meta := regexp.QuoteMeta(query)
 	suite.mock.ExpectQuery(meta).
 		WithArgs(subcategory.CategoryID, subcategory.Name, subcategory.ID).
@@ -147,9 +148,9 @@ func (suite *subcategoryRepositoryTestSuite) TestRepository_Updated_ExpectSucces
 func (suite *subcategoryRepositoryTestSuite) TestRepository_Updated_ExpectError() {
 	subcategory := &model.Subcategory{ID: 1, CategoryID: 1, Name: "test"}
 	data := suite.mock.
-		NewRows([]string{"id", "category_id", "name"}).
-		AddRow(1, nil, nil)
-	query := "UPDATE subcategories SET category_id = $1, name = $2 WHERE id = $3 RETURNING *"
+		NewRows([]string{"id", "category_id", "name", "deleted_at", "deleted_by"}).
+		AddRow(1, nil, nil, nil, nil)
+	query := "UPDATE subcategories SET category_id = $1, name = $2 WHERE id = $3 AND deleted_at IS NULL RETURNING id, category_id, name, deleted_at, deleted_by"
 	meta := regexp.QuoteMeta(query)
 	suite.mock.ExpectQuery(meta).
 		WithArgs(subcategory.CategoryID, subcategory.Name, subcategory.ID).
@@ -161,9 +162,9 @@ func (suite *subcategoryRepositoryTestSuite) TestRepository_Updated_ExpectError(
 }
 
 func (suite *subcategoryRepositoryTestSuite) TestRepository_Delete_ExpectSuccess() {
-	expectedQuery := regexp.QuoteMeta("DELETE FROM subcategories WHERE id = $1")
+	expectedQuery := regexp.QuoteMeta("UPDATE subcategories SET deleted_at = $1, deleted_by = $2 WHERE id = $3 AND deleted_at IS NULL")
 	suite.mock.ExpectExec(expectedQuery).
-		WithArgs(1).
+		WithArgs(sqlmock.AnyArg(), nil, 1).
 		WillReturnResult(sqlmock.NewResult(0, 1))
 	data := &model.Subcategory{ID: 1}
 	err := suite.repo.Delete(context.TODO(), data)
@@ -171,5 +172,10 @@ func (suite *subcategoryRepositoryTestSuite) TestRepository_Delete_ExpectSuccess
 }
 
 func TestSubcategoryRepository(t *testing.T) {
-	suite.Run(t, new(subcategoryRepositoryTestSuite))
+	for _, d := range dialect.All() {
+		t.Run(d.Name(), func(t *testing.T) {
+			config.Dialect = d
+			suite.Run(t, new(subcategoryRepositoryTestSuite))
+		})
+	}
 }
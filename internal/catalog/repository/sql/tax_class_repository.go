@@ -0,0 +1,239 @@
+package sql
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/aasumitro/posbe/config"
+	"github.com/aasumitro/posbe/pkg/dialect"
+	"github.com/aasumitro/posbe/pkg/model"
+)
+
+const taxClassColumns = "id, name, rate, inclusive, after_service, is_default"
+
+type TaxClassSQLRepository struct {
+	Db      *sql.DB
+	Dialect dialect.Dialect
+}
+
+func (repo TaxClassSQLRepository) All(ctx context.Context) (data []*model.TaxClass, err error) {
+	q := "SELECT " + taxClassColumns + " FROM tax_classes ORDER BY id"
+	rows, err := repo.Db.QueryContext(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+	defer func(rows *sql.Rows) { _ = rows.Close() }(rows)
+
+	classes := make(map[int]*model.TaxClass)
+	for rows.Next() {
+		class, err := scanTaxClass(rows)
+		if err != nil {
+			return nil, err
+		}
+		classes[class.ID] = class
+		data = append(data, class)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	q = "SELECT tax_class_id, product_id, category_id FROM tax_class_assignments ORDER BY id"
+	assignments, err := repo.Db.QueryContext(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+	defer func(rows *sql.Rows) { _ = rows.Close() }(assignments)
+
+	for assignments.Next() {
+		var classID int
+		var productID, categoryID sql.NullInt64
+		if err := assignments.Scan(&classID, &productID, &categoryID); err != nil {
+			return nil, err
+		}
+		if class, ok := classes[classID]; ok {
+			appendTaxAssignment(class, productID, categoryID)
+		}
+	}
+
+	return data, assignments.Err()
+}
+
+func (repo TaxClassSQLRepository) Find(ctx context.Context, _ model.FindWith, val any) (data *model.TaxClass, err error) {
+	q := "SELECT " + taxClassColumns + " FROM tax_classes WHERE id = $1 LIMIT 1"
+	if data, err = scanTaxClass(repo.Db.QueryRowContext(ctx, q, val)); err != nil {
+		return nil, err
+	}
+
+	q = "SELECT product_id, category_id FROM tax_class_assignments WHERE tax_class_id = $1 ORDER BY id"
+	rows, err := repo.Db.QueryContext(ctx, q, data.ID)
+	if err != nil {
+		return nil, err
+	}
+	defer func(rows *sql.Rows) { _ = rows.Close() }(rows)
+
+	for rows.Next() {
+		var productID, categoryID sql.NullInt64
+		if err := rows.Scan(&productID, &categoryID); err != nil {
+			return nil, err
+		}
+		appendTaxAssignment(data, productID, categoryID)
+	}
+
+	return data, rows.Err()
+}
+
+func (repo TaxClassSQLRepository) Create(ctx context.Context, params *model.TaxClass) (data *model.TaxClass, err error) {
+	tx, err := repo.Db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if err := unsetDefaultTaxClass(ctx, tx, params); err != nil {
+		return nil, err
+	}
+	q := "INSERT INTO tax_classes (name, rate, inclusive, after_service, is_default) "
+	q += "VALUES ($1, $2, $3, $4, $5) RETURNING id"
+	var id int
+	if err := tx.QueryRowContext(ctx, q, params.Name, params.Rate,
+		params.Inclusive, params.AfterService, params.IsDefault,
+	).Scan(&id); err != nil {
+		return nil, err
+	}
+	if err := replaceTaxAssignments(ctx, tx, id, params); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return repo.Find(ctx, model.FindWithID, id)
+}
+
+func (repo TaxClassSQLRepository) Update(ctx context.Context, params *model.TaxClass) (data *model.TaxClass, err error) {
+	tx, err := repo.Db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if err := unsetDefaultTaxClass(ctx, tx, params); err != nil {
+		return nil, err
+	}
+	q := "UPDATE tax_classes SET name = $1, rate = $2, inclusive = $3, after_service = $4, "
+	q += "is_default = $5, updated_at = $6 WHERE id = $7"
+	result, err := tx.ExecContext(ctx, q, params.Name, params.Rate, params.Inclusive,
+		params.AfterService, params.IsDefault, time.Now().Unix(), params.ID)
+	if err != nil {
+		return nil, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return nil, err
+	}
+	if affected == 0 {
+		return nil, sql.ErrNoRows
+	}
+	if err := replaceTaxAssignments(ctx, tx, params.ID, params); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return repo.Find(ctx, model.FindWithID, params.ID)
+}
+
+func (repo TaxClassSQLRepository) Delete(ctx context.Context, params *model.TaxClass) error {
+	q := "DELETE FROM tax_classes WHERE id = $1"
+	_, err := repo.Db.ExecContext(ctx, q, params.ID)
+	return err
+}
+
+func (repo TaxClassSQLRepository) ProductClasses(ctx context.Context, productIDs []int) (classes map[int]int, err error) {
+	ids := make([]int64, len(productIDs))
+	for i, id := range productIDs {
+		ids[i] = int64(id)
+	}
+	q := "SELECT p.id, COALESCE(pa.tax_class_id, ca.tax_class_id, "
+	q += "(SELECT id FROM tax_classes WHERE is_default = true LIMIT 1)) FROM products AS p "
+	q += "LEFT JOIN tax_class_assignments AS pa ON pa.product_id = p.id "
+	q += "LEFT JOIN tax_class_assignments AS ca ON ca.category_id = p.category_id "
+	q += "WHERE " + repo.Dialect.Any("p.id", "$1")
+	rows, err := repo.Db.QueryContext(ctx, q, repo.Dialect.Array(ids))
+	if err != nil {
+		return nil, err
+	}
+	defer func(rows *sql.Rows) { _ = rows.Close() }(rows)
+
+	classes = make(map[int]int)
+	for rows.Next() {
+		var id int
+		var classID sql.NullInt64
+		if err := rows.Scan(&id, &classID); err != nil {
+			return nil, err
+		}
+		classes[id] = int(classID.Int64)
+	}
+
+	return classes, rows.Err()
+}
+
+// unsetDefaultTaxClass keep a single default class
+func unsetDefaultTaxClass(ctx context.Context, tx *sql.Tx, class *model.TaxClass) error {
+	if !class.IsDefault {
+		return nil
+	}
+	q := "UPDATE tax_classes SET is_default = false WHERE is_default = true AND id <> $1"
+	_, err := tx.ExecContext(ctx, q, class.ID)
+	return err
+}
+
+// replaceTaxAssignments move the listed products and categories
+// to the class, they leave their previous class
+func replaceTaxAssignments(ctx context.Context, tx *sql.Tx, classID int, class *model.TaxClass) error {
+	q := "DELETE FROM tax_class_assignments WHERE tax_class_id = $1"
+	if _, err := tx.ExecContext(ctx, q, classID); err != nil {
+		return err
+	}
+	q = "INSERT INTO tax_class_assignments (tax_class_id, product_id) VALUES ($1, $2) "
+	q += "ON CONFLICT (product_id) WHERE product_id IS NOT NULL "
+	q += "DO UPDATE SET tax_class_id = EXCLUDED.tax_class_id"
+	for _, productID := range class.ProductIDs {
+		if _, err := tx.ExecContext(ctx, q, classID, productID); err != nil {
+			return err
+		}
+	}
+	q = "INSERT INTO tax_class_assignments (tax_class_id, category_id) VALUES ($1, $2) "
+	q += "ON CONFLICT (category_id) WHERE category_id IS NOT NULL "
+	q += "DO UPDATE SET tax_class_id = EXCLUDED.tax_class_id"
+	for _, categoryID := range class.CategoryIDs {
+		if _, err := tx.ExecContext(ctx, q, classID, categoryID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func scanTaxClass(row scanner) (*model.TaxClass, error) {
+	class := model.TaxClass{ProductIDs: []int{}, CategoryIDs: []int{}}
+	if err := row.Scan(&class.ID, &class.Name, &class.Rate,
+		&class.Inclusive, &class.AfterService, &class.IsDefault); err != nil {
+		return nil, err
+	}
+	return &class, nil
+}
+
+func appendTaxAssignment(class *model.TaxClass, productID, categoryID sql.NullInt64) {
+	if productID.Valid {
+		class.ProductIDs = append(class.ProductIDs, int(productID.Int64))
+	}
+	if categoryID.Valid {
+		class.CategoryIDs = append(class.CategoryIDs, int(categoryID.Int64))
+	}
+}
+
+func NewTaxClassSQLRepository() model.ITaxClassRepository {
+	return &TaxClassSQLRepository{Db: config.DbPool, Dialect: config.Dialect}
+}
@@ -0,0 +1,191 @@
+package sql_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/aasumitro/posbe/config"
+	repoSql "github.com/aasumitro/posbe/internal/catalog/repository/sql"
+	"github.com/aasumitro/posbe/pkg/dbtest"
+	"github.com/aasumitro/posbe/pkg/dialect"
+	"github.com/aasumitro/posbe/pkg/model"
+	"github.com/aasumitro/posbe/pkg/money"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+var taxClassRowColumns = []string{
+	"id", "name", "rate", "inclusive", "after_service", "is_default",
+}
+
+type taxClassRepositoryTestSuite struct {
+	suite.Suite
+	mock sqlmock.Sqlmock
+	repo model.ITaxClassRepository
+}
+
+func (suite *taxClassRepositoryTestSuite) SetupSuite() {
+	var err error
+	config.DbPool, suite.mock, err = sqlmock.New(
+		sqlmock.QueryMatcherOption(
+			sqlmock.QueryMatcherRegexp))
+	require.NoError(suite.T(), err)
+	suite.repo = repoSql.NewTaxClassSQLRepository()
+}
+
+func (suite *taxClassRepositoryTestSuite) AfterTest(_, _ string) {
+	require.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+func (suite *taxClassRepositoryTestSuite) expectFind() {
+	suite.mock.ExpectQuery("FROM tax_classes WHERE id = \\$1").WithArgs(1).
+		WillReturnRows(suite.mock.NewRows(taxClassRowColumns).
+			AddRow(1, "VAT", "11.0000", true, false, true))
+	suite.mock.ExpectQuery("FROM tax_class_assignments WHERE tax_class_id = \\$1").WithArgs(1).
+		WillReturnRows(suite.mock.NewRows([]string{"product_id", "category_id"}).
+			AddRow(nil, 2))
+}
+
+func (suite *taxClassRepositoryTestSuite) TestRepository_All_ExpectReturnRows() {
+	suite.mock.ExpectQuery("FROM tax_classes ORDER BY id").
+		WillReturnRows(suite.mock.NewRows(taxClassRowColumns).
+			AddRow(1, "VAT", "11.0000", true, false, true).
+			AddRow(2, "Exempt", "0.0000", false, false, false))
+	suite.mock.ExpectQuery("FROM tax_class_assignments ORDER BY id").
+		WillReturnRows(suite.mock.NewRows([]string{"tax_class_id", "product_id", "category_id"}).
+			AddRow(1, nil, 2).
+			AddRow(2, 5, nil))
+	res, err := suite.repo.All(context.TODO())
+	require.NoError(suite.T(), err)
+	require.Len(suite.T(), res, 2)
+	require.Equal(suite.T(), money.Percent(110000), res[0].Rate)
+	require.Equal(suite.T(), []int{2}, res[0].CategoryIDs)
+	require.Equal(suite.T(), []int{5}, res[1].ProductIDs)
+	require.Empty(suite.T(), res[1].CategoryIDs)
+}
+
+func (suite *taxClassRepositoryTestSuite) TestRepository_All_ExpectReturnError() {
+	suite.mock.ExpectQuery("FROM tax_classes").
+		WillReturnError(errors.New("UNEXPECTED"))
+	res, err := suite.repo.All(context.TODO())
+	require.Nil(suite.T(), res)
+	require.NotNil(suite.T(), err)
+}
+
+func (suite *taxClassRepositoryTestSuite) TestRepository_Find_ExpectReturnRow() {
+	suite.expectFind()
+	res, err := suite.repo.Find(context.TODO(), model.FindWithID, 1)
+	require.NoError(suite.T(), err)
+	require.True(suite.T(), res.Inclusive)
+	require.Equal(suite.T(), []int{2}, res.CategoryIDs)
+}
+
+func (suite *taxClassRepositoryTestSuite) TestRepository_Create_ExpectReturnRow() {
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectExec("UPDATE tax_classes SET is_default = false").WithArgs(0).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	suite.mock.ExpectQuery("INSERT INTO tax_classes").
+		WithArgs("VAT", "11", true, false, true).
+		WillReturnRows(suite.mock.NewRows([]string{"id"}).AddRow(1))
+	suite.mock.ExpectExec("DELETE FROM tax_class_assignments WHERE tax_class_id = \\$1").
+		WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 0))
+	suite.mock.ExpectExec("INSERT INTO tax_class_assignments (.+) ON CONFLICT \\(category_id\\)").
+		WithArgs(1, 2).WillReturnResult(sqlmock.NewResult(1, 1))
+	suite.mock.ExpectCommit()
+	suite.expectFind()
+	res, err := suite.repo.Create(context.TODO(), &model.TaxClass{
+		Name: "VAT", Rate: 110000, Inclusive: true, IsDefault: true, CategoryIDs: []int{2}})
+	require.NoError(suite.T(), err)
+	require.Equal(suite.T(), 1, res.ID)
+}
+
+func (suite *taxClassRepositoryTestSuite) TestRepository_Create_ExpectRollback() {
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectQuery("INSERT INTO tax_classes").
+		WillReturnRows(suite.mock.NewRows([]string{"id"}).AddRow(3))
+	suite.mock.ExpectExec("DELETE FROM tax_class_assignments").
+		WithArgs(3).WillReturnResult(sqlmock.NewResult(0, 0))
+	suite.mock.ExpectExec("INSERT INTO tax_class_assignments (.+) ON CONFLICT \\(product_id\\)").
+		WithArgs(3, 5).WillReturnError(errors.New("UNEXPECTED"))
+	suite.mock.ExpectRollback()
+	res, err := suite.repo.Create(context.TODO(), &model.TaxClass{
+		Name: "Exempt", ProductIDs: []int{5}})
+	require.Nil(suite.T(), res)
+	require.NotNil(suite.T(), err)
+}
+
+func (suite *taxClassRepositoryTestSuite) TestRepository_Update_ExpectErrorNoRows() {
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectExec("UPDATE tax_classes SET name").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	suite.mock.ExpectRollback()
+	res, err := suite.repo.Update(context.TODO(), &model.TaxClass{ID: 9, Name: "VAT"})
+	require.Nil(suite.T(), res)
+	require.ErrorContains(suite.T(), err, "no rows")
+}
+
+func (suite *taxClassRepositoryTestSuite) TestRepository_Update_ExpectReturnRow() {
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectExec("UPDATE tax_classes SET name").
+		WithArgs("VAT", "11", true, false, false, sqlmock.AnyArg(), 1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	suite.mock.ExpectExec("DELETE FROM tax_class_assignments").
+		WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 1))
+	suite.mock.ExpectCommit()
+	suite.expectFind()
+	res, err := suite.repo.Update(context.TODO(), &model.TaxClass{
+		ID: 1, Name: "VAT", Rate: 110000, Inclusive: true})
+	require.NoError(suite.T(), err)
+	require.Equal(suite.T(), "VAT", res.Name)
+}
+
+func (suite *taxClassRepositoryTestSuite) TestRepository_Delete_ExpectSuccess() {
+	suite.mock.ExpectExec("DELETE FROM tax_classes WHERE id = \\$1").WithArgs(2).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	err := suite.repo.Delete(context.TODO(), &model.TaxClass{ID: 2})
+	require.NoError(suite.T(), err)
+}
+
+func (suite *taxClassRepositoryTestSuite) TestRepository_ProductClasses_ExpectReturnRows() {
+	suite.mock.ExpectQuery("COALESCE\\(pa.tax_class_id, ca.tax_class_id").
+		WillReturnRows(suite.mock.NewRows([]string{"id", "tax_class_id"}).
+			AddRow(1, 1).
+			AddRow(2, nil))
+	res, err := suite.repo.ProductClasses(context.TODO(), []int{1, 2})
+	require.NoError(suite.T(), err)
+	require.Equal(suite.T(), map[int]int{1: 1, 2: 0}, res)
+}
+
+func (suite *taxClassRepositoryTestSuite) TestTaxClassRepository_ProductClasses() {
+	dbtest.Migrated(suite.T(), config.Dialect.Name())
+	ctx := context.TODO()
+	repo := repoSql.NewTaxClassSQLRepository()
+	rate, err := money.ParsePercent("11")
+	require.NoError(suite.T(), err)
+	class, err := repo.Create(ctx, &model.TaxClass{Name: "VAT", Rate: rate, CategoryIDs: []int{2}, ProductIDs: []int{2}})
+	require.NoError(suite.T(), err)
+	found, err := repo.Find(ctx, model.FindWithID, class.ID)
+	require.NoError(suite.T(), err)
+	require.Equal(suite.T(), []int{2}, found.ProductIDs)
+	require.Equal(suite.T(), "11", found.Rate.String())
+
+	classes, err := repo.ProductClasses(ctx, []int{1, 2, 99})
+	require.NoError(suite.T(), err)
+	require.Equal(suite.T(), map[int]int{1: class.ID, 2: class.ID}, classes)
+
+	class.ProductIDs = nil
+	_, err = repo.Update(ctx, class)
+	require.NoError(suite.T(), err)
+	require.NoError(suite.T(), repo.Delete(ctx, class))
+}
+
+func TestTaxClassRepository(t *testing.T) {
+	for _, d := range dialect.All() {
+		t.Run(d.Name(), func(t *testing.T) {
+			config.Dialect = d
+			suite.Run(t, new(taxClassRepositoryTestSuite))
+		})
+	}
+}
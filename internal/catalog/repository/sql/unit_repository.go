@@ -3,77 +3,81 @@ package sql
 import (
 	"context"
 	"database/sql"
+	"time"
 
 	"github.com/aasumitro/posbe/config"
+	"github.com/aasumitro/posbe/pkg/audit"
 	"github.com/aasumitro/posbe/pkg/model"
 )
 
+const unitColumns = "id, magnitude, name, symbol, deleted_at, deleted_by"
+
 type UnitSQLRepository struct {
 	Db *sql.DB
 }
 
 func (repo UnitSQLRepository) All(ctx context.Context) (data []*model.Unit, err error) {
-	q := "SELECT * FROM units"
-	rows, err := repo.Db.QueryContext(ctx, q)
+	q := "SELECT " + unitColumns + " FROM units WHERE ($1 OR deleted_at IS NULL)"
+	rows, err := repo.Db.QueryContext(ctx, q, model.IncludeDeleted(ctx))
 	if err != nil {
 		return nil, err
 	}
 	defer func(rows *sql.Rows) { _ = rows.Close() }(rows)
 
 	for rows.Next() {
-		var unit model.Unit
-
-		if err := rows.Scan(
-			&unit.ID, &unit.Magnitude,
-			&unit.Name, &unit.Symbol,
-		); err != nil {
+		unit, err := scanUnit(rows)
+		if err != nil {
 			return nil, err
 		}
 
-		data = append(data, &unit)
+		data = append(data, unit)
 	}
 
 	return data, nil
 }
 
 func (repo UnitSQLRepository) Find(ctx context.Context, _ model.FindWith, val any) (data *model.Unit, err error) {
-	q := "SELECT * FROM units WHERE id = $1 LIMIT 1"
-	row := repo.Db.QueryRowContext(ctx, q, val)
+	q := "SELECT " + unitColumns + " FROM units WHERE id = $1 AND ($2 OR deleted_at IS NULL) LIMIT 1"
+	row := repo.Db.QueryRowContext(ctx, q, val, model.IncludeDeleted(ctx))
 
-	data = &model.Unit{}
-	if err := row.Scan(
-		&data.ID, &data.Magnitude,
-		&data.Name, &data.Symbol,
-	); err != nil {
-		return nil, err
-	}
-
-	return data, nil
+	return scanUnit(row)
 }
 
 func (repo UnitSQLRepository) Create(ctx context.Context, params *model.Unit) (data *model.Unit, err error) {
-	q := "INSERT INTO units (magnitude, name, symbol) VALUES ($1, $2, $3) RETURNING *"
+	q := "INSERT INTO units (magnitude, name, symbol) VALUES ($1, $2, $3) RETURNING " + unitColumns
 	row := repo.Db.QueryRowContext(ctx, q, params.Magnitude, params.Name, params.Symbol)
 
-	data = &model.Unit{}
-	if err := row.Scan(
-		&data.ID, &data.Magnitude,
-		&data.Name, &data.Symbol,
-	); err != nil {
-		return nil, err
-	}
-
-	return data, nil
+	return scanUnit(row)
 }
 
 func (repo UnitSQLRepository) Update(ctx context.Context, params *model.Unit) (data *model.Unit, err error) {
-	q := "UPDATE units SET magnitude = $1, name = $2, symbol = $3 WHERE id = $4 RETURNING *"
+	q := "UPDATE units SET magnitude = $1, name = $2, symbol = $3 "
+	q += "WHERE id = $4 AND deleted_at IS NULL RETURNING " + unitColumns
 	row := repo.Db.QueryRowContext(ctx, q, params.Magnitude, params.Name, params.Symbol, params.ID)
 
+	return scanUnit(row)
+}
+
+func (repo UnitSQLRepository) Delete(ctx context.Context, params *model.Unit) error {
+	q := "UPDATE units SET deleted_at = $1, deleted_by = $2 WHERE id = $3 AND deleted_at IS NULL"
+	_, err := repo.Db.ExecContext(ctx, q, time.Now().Unix(), audit.NullActor(ctx), params.ID)
+	return err
+}
+
+func (repo UnitSQLRepository) Restore(ctx context.Context, params *model.Unit) (data *model.Unit, err error) {
+	q := "UPDATE units SET deleted_at = NULL, deleted_by = NULL "
+	q += "WHERE id = $1 AND deleted_at IS NOT NULL RETURNING " + unitColumns
+	row := repo.Db.QueryRowContext(ctx, q, params.ID)
+
+	return scanUnit(row)
+}
+
+func scanUnit(row scanner) (data *model.Unit, err error) {
 	data = &model.Unit{}
 	if err := row.Scan(
 		&data.ID, &data.Magnitude,
 		&data.Name, &data.Symbol,
+		&data.DeletedAt, &data.DeletedBy,
 	); err != nil {
 		return nil, err
 	}
@@ -81,12 +85,6 @@ func (repo UnitSQLRepository) Update(ctx context.Context, params *model.Unit) (d
 	return data, nil
 }
 
-func (repo UnitSQLRepository) Delete(ctx context.Context, params *model.Unit) error {
-	q := "DELETE FROM units WHERE id = $1"
-	_, err := repo.Db.ExecContext(ctx, q, params.ID)
-	return err
-}
-
-func NewUnitSQLRepository() model.ICRUDRepository[model.Unit] {
-	return &UnitSQLRepository{Db: config.PostgresPool}
+func NewUnitSQLRepository() model.ISoftDeleteRepository[model.Unit] {
+	return &UnitSQLRepository{Db: config.DbPool}
 }
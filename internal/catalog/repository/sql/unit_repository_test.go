@@ -2,6 +2,7 @@ package sql_test
 
 import (
 	"context"
+	"database/sql"
 	"errors"
 	"regexp"
 	"testing"
@@ -9,6 +10,8 @@ import (
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/aasumitro/posbe/config"
 	repoSql "github.com/aasumitro/posbe/internal/catalog/repository/sql"
+	"github.com/aasumitro/posbe/pkg/dbtest"
+	"github.com/aasumitro/posbe/pkg/dialect"
 	"github.com/aasumitro/posbe/pkg/model"
 	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
@@ -17,13 +20,13 @@ import (
 type unitRepositoryTestSuite struct {
 	suite.Suite
 	mock sqlmock.Sqlmock
-	repo model.ICRUDRepository[model.Unit]
+	repo model.ISoftDeleteRepository[model.Unit]
 }
 
 func (suite *unitRepositoryTestSuite) SetupSuite() {
 	var err error
 
-	config.PostgresPool, suite.mock, err = sqlmock.New(
+	config.DbPool, suite.mock, err = sqlmock.New(
 		sqlmock.QueryMatcherOption(
 			sqlmock.QueryMatcherRegexp))
 	require.NoError(suite.T(), err)
@@ -37,10 +40,10 @@ func (suite *unitRepositoryTestSuite) AfterTest(_, _ string) {
 
 func (suite *unitRepositoryTestSuite) TestRepository_All_ExpectReturnRows() {
 	data := suite.mock.
-		NewRows([]string{"id", "magnitude", "name", "symbol"}).
-		AddRow(1, "test", "test", "test").
-		AddRow(2, "test 2", "test 2", "test 2")
-	query := "SELECT * FROM units"
+		NewRows([]string{"id", "magnitude", "name", "symbol", "deleted_at", "deleted_by"}).
+		AddRow(1, "test", "test", "test", nil, nil).
+		AddRow(2, "test 2", "test 2", "test 2", nil, nil)
+	query := "SELECT id, magnitude, name, symbol, deleted_at, deleted_by FROM units WHERE ($1 OR deleted_at IS NULL)"
 	meta := regexp.QuoteMeta(query)
 	suite.mock.ExpectQuery(meta).WillReturnRows(data)
 	res, err := suite.repo.All(context.TODO())
@@ -50,7 +53,7 @@ func (suite *unitRepositoryTestSuite) TestRepository_All_ExpectReturnRows() {
 }
 
 func (suite *unitRepositoryTestSuite) TestRepository_All_ExpectReturnErrorFromQuery() {
-	query := "SELECT * FROM units"
+	query := "SELECT id, magnitude, name, symbol, deleted_at, deleted_by FROM units WHERE ($1 OR deleted_at IS NULL)"
 	meta := regexp.QuoteMeta(query)
 	suite.mock.ExpectQuery(meta).WillReturnError(errors.New(""))
 	res, err := suite.repo.All(context.TODO())
@@ -60,10 +63,10 @@ func (suite *unitRepositoryTestSuite) TestRepository_All_ExpectReturnErrorFromQu
 
 func (suite *unitRepositoryTestSuite) TestRepository_All_ExpectReturnErrorFromScan() {
 	data := suite.mock.
-		NewRows([]string{"id", "magnitude", "name", "symbol"}).
-		AddRow(1, "test", "test", "test").
-		AddRow(nil, nil, nil, nil)
-	query := "SELECT * FROM units"
+		NewRows([]string{"id", "magnitude", "name", "symbol", "deleted_at", "deleted_by"}).
+		AddRow(1, "test", "test", "test", nil, nil).
+		AddRow(nil, nil, nil, nil, nil, nil)
+	query := "SELECT id, magnitude, name, symbol, deleted_at, deleted_by FROM units WHERE ($1 OR deleted_at IS NULL)"
 	meta := regexp.QuoteMeta(query)
 	suite.mock.ExpectQuery(meta).WillReturnRows(data)
 	res, err := suite.repo.All(context.TODO())
@@ -73,9 +76,9 @@ func (suite *unitRepositoryTestSuite) TestRepository_All_ExpectReturnErrorFromSc
 
 func (suite *unitRepositoryTestSuite) TestRepository_Find_ExpectReturnRow() {
 	data := suite.mock.
-		NewRows([]string{"id", "magnitude", "name", "symbol"}).
-		AddRow(1, "test", "test", "test")
-	query := "SELECT * FROM units WHERE id = $1 LIMIT 1"
+		NewRows([]string{"id", "magnitude", "name", "symbol", "deleted_at", "deleted_by"}).
+		AddRow(1, "test", "test", "test", nil, nil)
+	query := "SELECT id, magnitude, name, symbol, deleted_at, deleted_by FROM units WHERE id = $1 AND ($2 OR deleted_at IS NULL) LIMIT 1"
 	meta := regexp.QuoteMeta(query)
 	suite.mock.ExpectQuery(meta).WillReturnRows(data)
 	res, err := suite.repo.Find(context.TODO(), model.FindWithID, 1)
@@ -86,9 +89,9 @@ func (suite *unitRepositoryTestSuite) TestRepository_Find_ExpectReturnRow() {
 
 func (suite *unitRepositoryTestSuite) TestRepository_Find_ExpectReturnError() {
 	data := suite.mock.
-		NewRows([]string{"id", "magnitude", "name", "symbol"}).
-		AddRow(nil, nil, nil, nil)
-	query := "SELECT * FROM units WHERE id = $1 LIMIT 1"
+		NewRows([]string{"id", "magnitude", "name", "symbol", "deleted_at", "deleted_by"}).
+		AddRow(nil, nil, nil, nil, nil, nil)
+	query := "SELECT id, magnitude, name, symbol, deleted_at, deleted_by FROM units WHERE id = $1 AND ($2 OR deleted_at IS NULL) LIMIT 1"
 	meta := regexp.QuoteMeta(query)
 	suite.mock.ExpectQuery(meta).WillReturnRows(data)
 	res, err := suite.repo.Find(context.TODO(), model.FindWithID, 1)
@@ -99,9 +102,9 @@ func (suite *unitRepositoryTestSuite) TestRepository_Find_ExpectReturnError() {
 func (suite *unitRepositoryTestSuite) TestRepository_Created_ExpectSuccess() {
 	unit := &model.Unit{ID: 1, Magnitude: "test", Name: "test", Symbol: "test"}
 	data := suite.mock.
-		NewRows([]string{"id", "magnitude", "name", "symbol"}).
-		AddRow(1, "test", "test", "test")
-	query := "INSERT INTO units (magnitude, name, symbol) VALUES ($1, $2, $3) RETURNING *"
+		NewRows([]string{"id", "magnitude", "name", "symbol", "deleted_at", "deleted_by"}).
+		AddRow(1, "test", "test", "test", nil, nil)
+	query := "INSERT INTO units (magnitude, name, symbol) VALUES ($1, $2, $3) RETURNING id, magnitude, name, symbol, deleted_at, deleted_by"
 	meta := regexp.QuoteMeta(query)
 	suite.mock.ExpectQuery(meta).
 		WithArgs(unit.Magnitude, unit.Name, unit.Symbol).
@@ -115,9 +118,9 @@ func (suite *unitRepositoryTestSuite) TestRepository_Created_ExpectSuccess() {
 func (suite *unitRepositoryTestSuite) TestRepository_Created_ExpectError() {
 	unit := &model.Unit{ID: 1, Magnitude: "test", Name: "test", Symbol: "test"}
 	data := suite.mock.
-		NewRows([]string{"id", "magnitude", "name", "symbol"}).
-		AddRow(1, nil, nil, nil)
-	query := "INSERT INTO units (magnitude, name, symbol) VALUES ($1, $2, $3) RETURNING *"
+		NewRows([]string{"id", "magnitude", "name", "symbol", "deleted_at", "deleted_by"}).
+		AddRow(1, nil, nil, nil, nil, nil)
+	query := "INSERT INTO units (magnitude, name, symbol) VALUES ($1, $2, $3) RETURNING id, magnitude, name, symbol, deleted_at, deleted_by"
 	meta := regexp.QuoteMeta(query)
 	suite.mock.ExpectQuery(meta).
 		WithArgs(unit.Magnitude, unit.Name, unit.Symbol).
@@ -131,9 +134,9 @@ func (suite *unitRepositoryTestSuite) TestRepository_Created_ExpectError() {
 func (suite *unitRepositoryTestSuite) TestRepository_Updated_ExpectSuccess() {
 	unit := &model.Unit{ID: 1, Magnitude: "test", Name: "test", Symbol: "test"}
 	data := suite.mock.
-		NewRows([]string{"id", "magnitude", "name", "symbol"}).
-		AddRow(1, "test", "test", "test")
-	query := "UPDATE units SET magnitude = $1, name = $2, symbol = $3 WHERE id = $4 RETURNING *"
+		NewRows([]string{"id", "magnitude", "name", "symbol", "deleted_at", "deleted_by"}).
+		AddRow(1, "test", "test", "test", nil, nil)
+	query := "UPDATE units SET magnitude = $1, name = $2, symbol = $3 WHERE id = $4 AND deleted_at IS NULL RETURNING id, magnitude, name, symbol, deleted_at, deleted_by"
 	meta := regexp.QuoteMeta(query)
 	suite.mock.ExpectQuery(meta).
 		WithArgs(unit.Magnitude, unit.Name, unit.Symbol, unit.ID).
@@ -147,9 +150,9 @@ func (suite *unitRepositoryTestSuite) TestRepository_Updated_ExpectSuccess() {
 func (suite *unitRepositoryTestSuite) TestRepository_Updated_ExpectError() {
 	unit := &model.Unit{ID: 1, Magnitude: "test", Name: "test", Symbol: "test"}
 	data := suite.mock.
-		NewRows([]string{"id", "magnitude", "name", "symbol"}).
-		AddRow(1, nil, nil, nil)
-	query := "UPDATE units SET magnitude = $1, name = $2, symbol = $3 WHERE id = $4 RETURNING *"
+		NewRows([]string{"id", "magnitude", "name", "symbol", "deleted_at", "deleted_by"}).
+		AddRow(1, nil, nil, nil, nil, nil)
+	query := "UPDATE units SET magnitude = $1, name = $2, symbol = $3 WHERE id = $4 AND deleted_at IS NULL RETURNING id, magnitude, name, symbol, deleted_at, deleted_by"
 	meta := regexp.QuoteMeta(query)
 	suite.mock.ExpectQuery(meta).
 		WithArgs(unit.Magnitude, unit.Name, unit.Symbol, unit.ID).
@@ -161,15 +164,69 @@ func (suite *unitRepositoryTestSuite) TestRepository_Updated_ExpectError() {
 }
 
 func (suite *unitRepositoryTestSuite) TestRepository_Delete_ExpectSuccess() {
-	expectedQuery := regexp.QuoteMeta("DELETE FROM units WHERE id = $1")
+	expectedQuery := regexp.QuoteMeta("UPDATE units SET deleted_at = $1, deleted_by = $2 WHERE id = $3 AND deleted_at IS NULL")
 	suite.mock.ExpectExec(expectedQuery).
-		WithArgs(1).
+		WithArgs(sqlmock.AnyArg(), nil, 1).
 		WillReturnResult(sqlmock.NewResult(0, 1))
 	data := &model.Unit{ID: 1}
 	err := suite.repo.Delete(context.TODO(), data)
 	require.Nil(suite.T(), err)
 }
 
+func (suite *unitRepositoryTestSuite) TestRepository_Restore_ExpectSuccess() {
+	data := suite.mock.
+		NewRows([]string{"id", "magnitude", "name", "symbol", "deleted_at", "deleted_by"}).
+		AddRow(1, "test", "test", "test", nil, nil)
+	query := "UPDATE units SET deleted_at = NULL, deleted_by = NULL WHERE id = $1 AND deleted_at IS NOT NULL RETURNING id, magnitude, name, symbol, deleted_at, deleted_by"
+	meta := regexp.QuoteMeta(query)
+	suite.mock.ExpectQuery(meta).WithArgs(1).WillReturnRows(data)
+	res, err := suite.repo.Restore(context.TODO(), &model.Unit{ID: 1})
+	require.Nil(suite.T(), err)
+	require.NotNil(suite.T(), res)
+}
+
+func (suite *unitRepositoryTestSuite) TestRepository_Restore_ExpectErrorNotDeleted() {
+	query := "UPDATE units SET deleted_at = NULL, deleted_by = NULL WHERE id = $1 AND deleted_at IS NOT NULL"
+	meta := regexp.QuoteMeta(query)
+	suite.mock.ExpectQuery(meta).WithArgs(1).WillReturnError(sql.ErrNoRows)
+	res, err := suite.repo.Restore(context.TODO(), &model.Unit{ID: 1})
+	require.Nil(suite.T(), res)
+	require.ErrorIs(suite.T(), err, sql.ErrNoRows)
+}
+
+func (suite *unitRepositoryTestSuite) TestUnitRepository_SoftDelete() {
+	dbtest.Migrated(suite.T(), config.Dialect.Name())
+	ctx := context.TODO()
+	repo := repoSql.NewUnitSQLRepository()
+	unit, err := repo.Create(ctx, &model.Unit{Magnitude: "length", Name: "metre", Symbol: "m"})
+	require.NoError(suite.T(), err)
+	unit.Symbol = "M"
+	unit, err = repo.Update(ctx, unit)
+	require.NoError(suite.T(), err)
+	require.Equal(suite.T(), "M", unit.Symbol)
+
+	require.NoError(suite.T(), repo.Delete(ctx, unit))
+	_, err = repo.Find(ctx, model.FindWithID, unit.ID)
+	require.ErrorIs(suite.T(), err, sql.ErrNoRows)
+	deleted, err := repo.Find(context.WithValue(ctx, model.IncludeDeletedKey, true), model.FindWithID, unit.ID)
+	require.NoError(suite.T(), err)
+	require.NotNil(suite.T(), deleted.DeletedAt)
+	units, err := repo.All(ctx)
+	require.NoError(suite.T(), err)
+	for _, item := range units {
+		require.NotEqual(suite.T(), unit.ID, item.ID)
+	}
+
+	restored, err := repo.Restore(ctx, unit)
+	require.NoError(suite.T(), err)
+	require.Nil(suite.T(), restored.DeletedAt)
+}
+
 func TestUnitRepository(t *testing.T) {
-	suite.Run(t, new(unitRepositoryTestSuite))
+	for _, d := range dialect.All() {
+		t.Run(d.Name(), func(t *testing.T) {
+			config.Dialect = d
+			suite.Run(t, new(unitRepositoryTestSuite))
+		})
+	}
 }
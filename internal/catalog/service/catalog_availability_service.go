@@ -0,0 +1,298 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/aasumitro/posbe/pkg/broker"
+	"github.com/aasumitro/posbe/pkg/model"
+	"github.com/aasumitro/posbe/pkg/utils"
+)
+
+type (
+	catalogAvailabilityService struct {
+		availabilityRepo model.IAvailabilityRepository
+		prefRepo         model.IStorePrefRepository
+		broker           broker.Broker
+	}
+
+	// availabilityIndex is the rules and sold out items keyed for lookup
+	availabilityIndex struct {
+		productRules    map[int][]*model.AvailabilityRule
+		categoryRules   map[int][]*model.AvailabilityRule
+		soldOut         map[int]bool
+		soldOutVariants map[int][]int
+	}
+)
+
+func (service catalogAvailabilityService) AvailabilityRuleList(
+	ctx context.Context,
+) (rules []*model.AvailabilityRule, errData *utils.ServiceError) {
+	data, err := service.availabilityRepo.Rules(ctx)
+	return utils.ValidateDataRows[model.AvailabilityRule](data, err)
+}
+
+func (service catalogAvailabilityService) SaveAvailabilityRules(
+	ctx context.Context,
+	form *model.AvailabilityRuleForm,
+) *utils.ServiceError {
+	if errData := validateAvailabilityRules(form); errData != nil {
+		return errData
+	}
+	if err := service.availabilityRepo.ReplaceRules(ctx, form); err != nil {
+		_, errData := utils.ValidateDataRow[model.AvailabilityRule](nil, err)
+		return errData
+	}
+	service.publish(ctx, &model.AvailabilityEvent{
+		Type:       model.AvailabilityEventSchedule,
+		ProductID:  form.ProductID,
+		CategoryID: form.CategoryID,
+	})
+	return nil
+}
+
+func (service catalogAvailabilityService) SoldOutList(
+	ctx context.Context,
+) (items []*model.SoldOutItem, errData *utils.ServiceError) {
+	data, err := service.availabilityRepo.SoldOut(ctx)
+	return utils.ValidateDataRows[model.SoldOutItem](data, err)
+}
+
+func (service catalogAvailabilityService) MarkSoldOut(
+	ctx context.Context,
+	item *model.SoldOutItem,
+) (soldOut *model.SoldOutItem, errData *utils.ServiceError) {
+	categories, err := service.availabilityRepo.ProductCategories(ctx)
+	if err != nil {
+		return utils.ValidateDataRow[model.SoldOutItem](nil, err)
+	}
+	if _, ok := categories[item.ProductID]; !ok {
+		return nil, &utils.ServiceError{
+			Code:    http.StatusNotFound,
+			Message: fmt.Sprintf("product %d not found", item.ProductID),
+		}
+	}
+	data, err := service.availabilityRepo.MarkSoldOut(ctx, item)
+	if err != nil {
+		return utils.ValidateDataRow[model.SoldOutItem](nil, err)
+	}
+	service.publish(ctx, &model.AvailabilityEvent{
+		Type:      model.AvailabilityEventSoldOut,
+		ProductID: data.ProductID,
+		VariantID: data.VariantID,
+	})
+	return data, nil
+}
+
+func (service catalogAvailabilityService) ClearSoldOut(
+	ctx context.Context,
+	item *model.SoldOutItem,
+) *utils.ServiceError {
+	if err := service.availabilityRepo.ClearSoldOut(ctx, item); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return &utils.ServiceError{
+				Code:    http.StatusNotFound,
+				Message: "item is not sold out",
+			}
+		}
+		_, errData := utils.ValidateDataRow[model.SoldOutItem](nil, err)
+		return errData
+	}
+	service.publish(ctx, &model.AvailabilityEvent{
+		Type:      model.AvailabilityEventRestocked,
+		ProductID: item.ProductID,
+		VariantID: item.VariantID,
+	})
+	return nil
+}
+
+func (service catalogAvailabilityService) AvailabilityBoard(
+	ctx context.Context,
+) (board []*model.ProductAvailability, errData *utils.ServiceError) {
+	categories, err := service.availabilityRepo.ProductCategories(ctx)
+	if err != nil {
+		return utils.ValidateDataRows[model.ProductAvailability](nil, err)
+	}
+	index, err := loadAvailability(ctx, service.availabilityRepo)
+	if err != nil {
+		return utils.ValidateDataRows[model.ProductAvailability](nil, err)
+	}
+	at := time.Now().In(storeLocation(ctx, service.prefRepo))
+	for productID, categoryID := range categories {
+		board = append(board, index.product(productID, categoryID, at))
+	}
+	sort.Slice(board, func(i, j int) bool {
+		return board[i].ProductID < board[j].ProductID
+	})
+	return board, nil
+}
+
+func (service catalogAvailabilityService) CheckAvailability(
+	ctx context.Context,
+	form *model.AvailabilityCheckForm,
+) *utils.ServiceError {
+	categories, err := service.availabilityRepo.ProductCategories(ctx)
+	if err != nil {
+		_, errData := utils.ValidateDataRow[model.ProductAvailability](nil, err)
+		return errData
+	}
+	index, err := loadAvailability(ctx, service.availabilityRepo)
+	if err != nil {
+		_, errData := utils.ValidateDataRow[model.ProductAvailability](nil, err)
+		return errData
+	}
+	at := time.Now()
+	if form.At > 0 {
+		at = time.Unix(form.At, 0)
+	}
+	at = at.In(storeLocation(ctx, service.prefRepo))
+
+	var errs []*model.AvailabilityError
+	for _, item := range form.Items {
+		categoryID, ok := categories[item.ProductID]
+		if !ok {
+			errs = append(errs, &model.AvailabilityError{
+				ProductID: item.ProductID, VariantID: item.VariantID,
+				Reason:  model.UnavailableNotFound,
+				Message: fmt.Sprintf("product %d not found", item.ProductID),
+			})
+			continue
+		}
+		availability := index.product(item.ProductID, categoryID, at)
+		switch {
+		case !availability.Available:
+			errs = append(errs, &model.AvailabilityError{
+				ProductID: item.ProductID, VariantID: item.VariantID,
+				Reason:  availability.Reason,
+				Message: fmt.Sprintf("product %d is not available (%s)", item.ProductID, availability.Reason),
+			})
+		case item.VariantID > 0 && slices.Contains(availability.SoldOutVariantIDs, item.VariantID):
+			errs = append(errs, &model.AvailabilityError{
+				ProductID: item.ProductID, VariantID: item.VariantID,
+				Reason:  model.UnavailableSoldOut,
+				Message: fmt.Sprintf("variant %d is sold out", item.VariantID),
+			})
+		}
+	}
+	if len(errs) > 0 {
+		return &utils.ServiceError{
+			Code:    http.StatusUnprocessableEntity,
+			Message: errs,
+		}
+	}
+	return nil
+}
+
+func (service catalogAvailabilityService) Subscribe(
+	ctx context.Context,
+) (events <-chan []byte, closeFn func() error) {
+	return service.broker.Subscribe(ctx, model.AvailabilityTopic)
+}
+
+// publish is best effort, the change is already saved and
+// terminals catch up on the next board refresh
+func (service catalogAvailabilityService) publish(ctx context.Context, event *model.AvailabilityEvent) {
+	event.At = time.Now().Unix()
+	if err := service.broker.Publish(ctx, model.AvailabilityTopic, event); err != nil {
+		log.Printf("BROKER_ERROR: %s\n", err.Error())
+	}
+}
+
+func validateAvailabilityRules(form *model.AvailabilityRuleForm) *utils.ServiceError {
+	var message string
+	if (form.ProductID > 0) == (form.CategoryID > 0) {
+		message = "either product_id or category_id is required"
+	}
+	for _, rule := range form.Rules {
+		if (rule.StartTime == "") != (rule.EndTime == "") {
+			message = "start_time and end_time must be set together"
+			break
+		}
+	}
+	if message == "" {
+		return nil
+	}
+	return &utils.ServiceError{
+		Code:    http.StatusUnprocessableEntity,
+		Message: message,
+	}
+}
+
+func loadAvailability(ctx context.Context, repo model.IAvailabilityRepository) (*availabilityIndex, error) {
+	rules, err := repo.Rules(ctx)
+	if err != nil {
+		return nil, err
+	}
+	soldOut, err := repo.SoldOut(ctx)
+	if err != nil {
+		return nil, err
+	}
+	index := &availabilityIndex{
+		productRules:    make(map[int][]*model.AvailabilityRule),
+		categoryRules:   make(map[int][]*model.AvailabilityRule),
+		soldOut:         make(map[int]bool),
+		soldOutVariants: make(map[int][]int),
+	}
+	for _, rule := range rules {
+		if rule.ProductID > 0 {
+			index.productRules[rule.ProductID] = append(index.productRules[rule.ProductID], rule)
+			continue
+		}
+		index.categoryRules[rule.CategoryID] = append(index.categoryRules[rule.CategoryID], rule)
+	}
+	for _, item := range soldOut {
+		if item.VariantID > 0 {
+			index.soldOutVariants[item.ProductID] = append(index.soldOutVariants[item.ProductID], item.VariantID)
+			continue
+		}
+		index.soldOut[item.ProductID] = true
+	}
+	return index, nil
+}
+
+// product is available when not 86'd and one of its rules match,
+// product rules replace the category rules, no rule mean always available
+func (index *availabilityIndex) product(productID, categoryID int, at time.Time) *model.ProductAvailability {
+	availability := &model.ProductAvailability{
+		ProductID:         productID,
+		Available:         true,
+		SoldOutVariantIDs: append([]int{}, index.soldOutVariants[productID]...),
+	}
+	if index.soldOut[productID] {
+		availability.Available, availability.Reason = false, model.UnavailableSoldOut
+		return availability
+	}
+	rules := index.productRules[productID]
+	if len(rules) == 0 {
+		rules = index.categoryRules[categoryID]
+	}
+	if len(rules) == 0 {
+		return availability
+	}
+	for _, rule := range rules {
+		if withinSchedule(rule.Days, rule.StartTime, rule.EndTime, at) {
+			return availability
+		}
+	}
+	availability.Available, availability.Reason = false, model.UnavailableSchedule
+	return availability
+}
+
+func NewCatalogAvailabilityService(
+	availabilityRepo model.IAvailabilityRepository,
+	prefRepo model.IStorePrefRepository,
+	eventBroker broker.Broker,
+) model.ICatalogAvailabilityService {
+	return &catalogAvailabilityService{
+		availabilityRepo: availabilityRepo,
+		prefRepo:         prefRepo,
+		broker:           eventBroker,
+	}
+}
@@ -0,0 +1,176 @@
+package service_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/aasumitro/posbe/internal/catalog/service"
+	"github.com/aasumitro/posbe/mocks"
+	"github.com/aasumitro/posbe/pkg/model"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type catalogAvailabilityTestSuite struct {
+	suite.Suite
+	repo   *mocks.IAvailabilityRepository
+	broker *mocks.Broker
+	svc    model.ICatalogAvailabilityService
+	loc    *time.Location
+}
+
+func (suite *catalogAvailabilityTestSuite) SetupTest() {
+	suite.repo = new(mocks.IAvailabilityRepository)
+	suite.broker = new(mocks.Broker)
+	prefRepo := new(mocks.IStorePrefRepository)
+	prefRepo.On("Find", mock.Anything, "fe_locale").
+		Return(&model.StoreSetting{"fe_locale": "Asia/Makassar"}, nil)
+	suite.svc = service.NewCatalogAvailabilityService(suite.repo, prefRepo, suite.broker)
+	var err error
+	suite.loc, err = time.LoadLocation("Asia/Makassar")
+	require.NoError(suite.T(), err)
+}
+
+// expectState: product 1 breakfast 07:00-11:00, category 2 weekend only
+// with product 3 overriding it, product 4 86'd, variant 50 of product 5 86'd
+func (suite *catalogAvailabilityTestSuite) expectState() {
+	suite.repo.On("ProductCategories", mock.Anything).
+		Return(map[int]int{1: 1, 2: 2, 3: 2, 4: 1, 5: 1}, nil)
+	suite.repo.On("Rules", mock.Anything).Return([]*model.AvailabilityRule{
+		{ID: 1, ProductID: 1, StartTime: "07:00", EndTime: "11:00"},
+		{ID: 2, CategoryID: 2, Days: []int{0, 6}},
+		{ID: 3, ProductID: 3, Days: []int{1, 2, 3, 4, 5}},
+	}, nil)
+	suite.repo.On("SoldOut", mock.Anything).Return([]*model.SoldOutItem{
+		{ID: 1, ProductID: 4}, {ID: 2, ProductID: 5, VariantID: 50},
+	}, nil)
+}
+
+func (suite *catalogAvailabilityTestSuite) TestCatalogAvailabilityService_CheckAvailability_ShouldPass() {
+	suite.expectState()
+	// wednesday 08:00 outlet time
+	at := time.Date(2026, 10, 21, 8, 0, 0, 0, suite.loc).Unix()
+	err := suite.svc.CheckAvailability(context.TODO(), &model.AvailabilityCheckForm{
+		At: at, Items: []*model.AvailabilityCheckItem{
+			{ProductID: 1}, {ProductID: 3}, {ProductID: 5, VariantID: 51}}})
+	require.Nil(suite.T(), err)
+}
+
+func (suite *catalogAvailabilityTestSuite) TestCatalogAvailabilityService_CheckAvailability_ShouldReject() {
+	suite.expectState()
+	// wednesday 12:00 outlet time
+	at := time.Date(2026, 10, 21, 12, 0, 0, 0, suite.loc).Unix()
+	err := suite.svc.CheckAvailability(context.TODO(), &model.AvailabilityCheckForm{
+		At: at, Items: []*model.AvailabilityCheckItem{
+			{ProductID: 1}, {ProductID: 2}, {ProductID: 4},
+			{ProductID: 5, VariantID: 50}, {ProductID: 9}}})
+	require.NotNil(suite.T(), err)
+	require.Equal(suite.T(), http.StatusUnprocessableEntity, err.Code)
+	errs := err.Message.([]*model.AvailabilityError)
+	require.Len(suite.T(), errs, 5)
+	require.Equal(suite.T(), model.UnavailableSchedule, errs[0].Reason)
+	require.Equal(suite.T(), model.UnavailableSchedule, errs[1].Reason)
+	require.Equal(suite.T(), model.UnavailableSoldOut, errs[2].Reason)
+	require.Equal(suite.T(), model.UnavailableSoldOut, errs[3].Reason)
+	require.Equal(suite.T(), model.UnavailableNotFound, errs[4].Reason)
+}
+
+func (suite *catalogAvailabilityTestSuite) TestCatalogAvailabilityService_CheckAvailability_ShouldErrorRepo() {
+	suite.repo.On("ProductCategories", mock.Anything).Return(nil, errors.New("UNEXPECTED"))
+	err := suite.svc.CheckAvailability(context.TODO(), &model.AvailabilityCheckForm{
+		Items: []*model.AvailabilityCheckItem{{ProductID: 1}}})
+	require.Equal(suite.T(), http.StatusInternalServerError, err.Code)
+}
+
+func (suite *catalogAvailabilityTestSuite) TestCatalogAvailabilityService_AvailabilityBoard_ShouldSuccess() {
+	suite.expectState()
+	data, err := suite.svc.AvailabilityBoard(context.TODO())
+	require.Nil(suite.T(), err)
+	require.Len(suite.T(), data, 5)
+	require.Equal(suite.T(), 1, data[0].ProductID)
+	require.False(suite.T(), data[3].Available)
+	require.Equal(suite.T(), []int{50}, data[4].SoldOutVariantIDs)
+}
+
+func (suite *catalogAvailabilityTestSuite) TestCatalogAvailabilityService_SaveAvailabilityRules_ShouldValidate() {
+	for _, form := range []*model.AvailabilityRuleForm{
+		{},
+		{ProductID: 1, CategoryID: 1},
+		{ProductID: 1, Rules: []*model.AvailabilityRule{{StartTime: "07:00"}}},
+	} {
+		err := suite.svc.SaveAvailabilityRules(context.TODO(), form)
+		require.Equal(suite.T(), http.StatusUnprocessableEntity, err.Code)
+	}
+}
+
+func (suite *catalogAvailabilityTestSuite) TestCatalogAvailabilityService_SaveAvailabilityRules_ShouldBroadcast() {
+	form := &model.AvailabilityRuleForm{CategoryID: 2,
+		Rules: []*model.AvailabilityRule{{Days: []int{0, 6}}}}
+	suite.repo.On("ReplaceRules", mock.Anything, form).Once().Return(nil)
+	suite.broker.On("Publish", mock.Anything, model.AvailabilityTopic,
+		mock.MatchedBy(func(event *model.AvailabilityEvent) bool {
+			return event.Type == model.AvailabilityEventSchedule && event.CategoryID == 2
+		})).Once().Return(nil)
+	err := suite.svc.SaveAvailabilityRules(context.TODO(), form)
+	require.Nil(suite.T(), err)
+	suite.broker.AssertExpectations(suite.T())
+}
+
+func (suite *catalogAvailabilityTestSuite) TestCatalogAvailabilityService_MarkSoldOut_ShouldBroadcast() {
+	item := &model.SoldOutItem{ProductID: 5, VariantID: 50}
+	suite.repo.On("ProductCategories", mock.Anything).Return(map[int]int{5: 1}, nil)
+	suite.repo.On("MarkSoldOut", mock.Anything, item).Once().
+		Return(&model.SoldOutItem{ID: 1, ProductID: 5, VariantID: 50, StoreShiftID: 3}, nil)
+	suite.broker.On("Publish", mock.Anything, model.AvailabilityTopic,
+		mock.MatchedBy(func(event *model.AvailabilityEvent) bool {
+			return event.Type == model.AvailabilityEventSoldOut && event.VariantID == 50
+		})).Once().Return(errors.New("UNEXPECTED"))
+	data, err := suite.svc.MarkSoldOut(context.TODO(), item)
+	require.Nil(suite.T(), err)
+	require.Equal(suite.T(), 3, data.StoreShiftID)
+	suite.broker.AssertExpectations(suite.T())
+}
+
+func (suite *catalogAvailabilityTestSuite) TestCatalogAvailabilityService_MarkSoldOut_ShouldErrorNotFound() {
+	suite.repo.On("ProductCategories", mock.Anything).Return(map[int]int{5: 1}, nil)
+	data, err := suite.svc.MarkSoldOut(context.TODO(), &model.SoldOutItem{ProductID: 9})
+	require.Nil(suite.T(), data)
+	require.Equal(suite.T(), http.StatusNotFound, err.Code)
+}
+
+func (suite *catalogAvailabilityTestSuite) TestCatalogAvailabilityService_ClearSoldOut_ShouldErrorNotFound() {
+	item := &model.SoldOutItem{ProductID: 5}
+	suite.repo.On("ClearSoldOut", mock.Anything, item).Once().Return(sql.ErrNoRows)
+	err := suite.svc.ClearSoldOut(context.TODO(), item)
+	require.Equal(suite.T(), http.StatusNotFound, err.Code)
+}
+
+func (suite *catalogAvailabilityTestSuite) TestCatalogAvailabilityService_ClearSoldOut_ShouldBroadcast() {
+	item := &model.SoldOutItem{ProductID: 5}
+	suite.repo.On("ClearSoldOut", mock.Anything, item).Once().Return(nil)
+	suite.broker.On("Publish", mock.Anything, model.AvailabilityTopic,
+		mock.MatchedBy(func(event *model.AvailabilityEvent) bool {
+			return event.Type == model.AvailabilityEventRestocked
+		})).Once().Return(nil)
+	err := suite.svc.ClearSoldOut(context.TODO(), item)
+	require.Nil(suite.T(), err)
+	suite.broker.AssertExpectations(suite.T())
+}
+
+func (suite *catalogAvailabilityTestSuite) TestCatalogAvailabilityService_Subscribe_ShouldUseTopic() {
+	events := make(chan []byte)
+	suite.broker.On("Subscribe", mock.Anything, model.AvailabilityTopic).
+		Once().Return((<-chan []byte)(events), func() error { return nil })
+	data, closeFn := suite.svc.Subscribe(context.TODO())
+	require.NotNil(suite.T(), data)
+	require.NoError(suite.T(), closeFn())
+}
+
+func TestCatalogAvailabilityService(t *testing.T) {
+	suite.Run(t, new(catalogAvailabilityTestSuite))
+}
@@ -0,0 +1,294 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/aasumitro/posbe/pkg/model"
+	"github.com/aasumitro/posbe/pkg/money"
+	"github.com/aasumitro/posbe/pkg/utils"
+)
+
+type catalogBundleService struct {
+	bundleRepo  model.IBundleRepository
+	productRepo model.ICRUDWithSearchRepository[model.Product]
+	variantRepo model.ICRUDRepository[model.ProductVariant]
+}
+
+func (service catalogBundleService) BundleDetail(
+	ctx context.Context,
+	productID int,
+) (bundle *model.Bundle, errData *utils.ServiceError) {
+	data, err := service.bundleRepo.Find(ctx, productID)
+	return utils.ValidateDataRow[model.Bundle](data, err)
+}
+
+func (service catalogBundleService) SaveBundle(
+	ctx context.Context,
+	item *model.Bundle,
+) (bundle *model.Bundle, errData *utils.ServiceError) {
+	product, err := service.productRepo.Find(ctx, model.FindWithID, item.ProductID)
+	if err != nil {
+		_, errData := utils.ValidateDataRow[model.Product](product, err)
+		return nil, errData
+	}
+	if len(item.Items) == 0 && len(item.Slots) == 0 {
+		return nil, &utils.ServiceError{
+			Code:    http.StatusUnprocessableEntity,
+			Message: "bundle require at least one item or slot",
+		}
+	}
+	for _, bundleItem := range item.Items {
+		if errData := service.checkComponent(ctx, item.ProductID,
+			bundleItem.ProductID, bundleItem.VariantID); errData != nil {
+			return nil, errData
+		}
+	}
+	for _, slot := range item.Slots {
+		if slot.SubcategoryID == 0 && len(slot.Options) == 0 {
+			return nil, &utils.ServiceError{
+				Code:    http.StatusUnprocessableEntity,
+				Message: fmt.Sprintf("slot %s require subcategory_id or options", slot.Name),
+			}
+		}
+		for _, option := range slot.Options {
+			if errData := service.checkComponent(ctx, item.ProductID,
+				option.ProductID, option.VariantID); errData != nil {
+				return nil, errData
+			}
+		}
+	}
+
+	data, err := service.bundleRepo.Save(ctx, item)
+	return utils.ValidateDataRow[model.Bundle](data, err)
+}
+
+func (service catalogBundleService) DeleteBundle(
+	ctx context.Context,
+	productID int,
+) *utils.ServiceError {
+	data, err := service.bundleRepo.Find(ctx, productID)
+	if err != nil {
+		_, errData := utils.ValidateDataRow[model.Bundle](data, err)
+		return errData
+	}
+	_, errData := utils.ValidateDataRow[model.Bundle](
+		nil, service.bundleRepo.Delete(ctx, productID))
+	return errData
+}
+
+func (service catalogBundleService) ExpandBundle(
+	ctx context.Context,
+	productID int,
+	form *model.BundleExpandForm,
+) (expansion *model.BundleExpansion, errData *utils.ServiceError) {
+	product, err := service.productRepo.Find(ctx, model.FindWithID, productID)
+	if err != nil {
+		_, errData := utils.ValidateDataRow[model.Product](product, err)
+		return nil, errData
+	}
+	bundle, err := service.bundleRepo.Find(ctx, productID)
+	if err != nil {
+		_, errData := utils.ValidateDataRow[model.Bundle](bundle, err)
+		return nil, errData
+	}
+
+	var components []*model.BundleComponent
+	for _, item := range bundle.Items {
+		component, err := service.component(ctx, item.ProductID, item.VariantID)
+		if err != nil {
+			_, errData := utils.ValidateDataRow[model.BundleComponent](nil, err)
+			return nil, errData
+		}
+		component.Qty = item.Qty
+		components = append(components, component)
+	}
+
+	slots := make(map[int]*model.BundleSlot, len(bundle.Slots))
+	for _, slot := range bundle.Slots {
+		slots[slot.ID] = slot
+	}
+	var errs []*model.BundleChoiceError
+	chosen := make(map[int]int, len(bundle.Slots))
+	for _, choice := range form.Choices {
+		slot, ok := slots[choice.SlotID]
+		if !ok {
+			errs = append(errs, &model.BundleChoiceError{
+				SlotID: choice.SlotID, ProductID: choice.ProductID,
+				Message: "slot is not part of this bundle",
+			})
+			continue
+		}
+		component, err := service.component(ctx, choice.ProductID, choice.VariantID)
+		if err != nil {
+			if !errors.Is(err, sql.ErrNoRows) {
+				_, errData := utils.ValidateDataRow[model.BundleComponent](nil, err)
+				return nil, errData
+			}
+			errs = append(errs, &model.BundleChoiceError{
+				SlotID: slot.ID, ProductID: choice.ProductID,
+				Message: "product or variant not found",
+			})
+			continue
+		}
+		option := findSlotOption(slot, choice)
+		if option == nil && (slot.SubcategoryID == 0 ||
+			component.SubcategoryID != slot.SubcategoryID) {
+			errs = append(errs, &model.BundleChoiceError{
+				SlotID: slot.ID, ProductID: choice.ProductID,
+				Message: fmt.Sprintf("%s is not available for %s", component.Name, slot.Name),
+			})
+			continue
+		}
+		if option != nil {
+			component.Upcharge = option.Upcharge
+		}
+		component.SlotID, component.Qty = slot.ID, 1
+		chosen[slot.ID]++
+		components = append(components, component)
+	}
+	for _, slot := range bundle.Slots {
+		if chosen[slot.ID] != slot.Qty {
+			errs = append(errs, &model.BundleChoiceError{
+				SlotID:  slot.ID,
+				Message: fmt.Sprintf("%s require %d choice", slot.Name, slot.Qty),
+			})
+		}
+	}
+	if len(errs) > 0 {
+		return nil, &utils.ServiceError{
+			Code:    http.StatusUnprocessableEntity,
+			Message: errs,
+		}
+	}
+
+	qty := form.Qty
+	if qty == 0 {
+		qty = 1
+	}
+	expansion = &model.BundleExpansion{
+		ProductID: product.ID, Name: product.Name,
+		Qty: qty, UnitPrice: product.Price,
+		Components: components,
+	}
+	for _, component := range components {
+		expansion.UnitPrice += component.Upcharge.Mul(component.Qty)
+	}
+	allocateRevenue(product.Price, components)
+	for _, component := range components {
+		component.Qty *= qty
+		component.Revenue = component.Revenue.Mul(qty)
+	}
+	expansion.Total = expansion.UnitPrice.Mul(qty)
+
+	return expansion, nil
+}
+
+// checkComponent make sure the product (and variant) exists,
+// bundle inside bundle is not supported
+func (service catalogBundleService) checkComponent(
+	ctx context.Context,
+	bundleID, productID, variantID int,
+) *utils.ServiceError {
+	if productID == bundleID {
+		return &utils.ServiceError{
+			Code:    http.StatusUnprocessableEntity,
+			Message: "bundle cannot contain itself",
+		}
+	}
+	if _, err := service.component(ctx, productID, variantID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return &utils.ServiceError{
+				Code:    http.StatusUnprocessableEntity,
+				Message: fmt.Sprintf("product %d (variant %d) not found", productID, variantID),
+			}
+		}
+		_, errData := utils.ValidateDataRow[model.BundleComponent](nil, err)
+		return errData
+	}
+	return nil
+}
+
+// component load the standalone product data, variant price win over product price
+func (service catalogBundleService) component(
+	ctx context.Context,
+	productID, variantID int,
+) (*model.BundleComponent, error) {
+	product, err := service.productRepo.Find(ctx, model.FindWithID, productID)
+	if err != nil {
+		return nil, err
+	}
+	component := &model.BundleComponent{
+		ProductID: product.ID, CategoryID: product.CategoryID,
+		SubcategoryID: product.SubcategoryID,
+		Name:          product.Name, ListPrice: product.Price,
+	}
+	if variantID == 0 {
+		return component, nil
+	}
+	variant, err := service.variantRepo.Find(ctx, model.FindWithID, variantID)
+	if err != nil {
+		return nil, err
+	}
+	if variant.ProductID != product.ID {
+		return nil, sql.ErrNoRows
+	}
+	component.VariantID = variant.ID
+	component.Name = fmt.Sprintf("%s (%s)", product.Name, variant.Name)
+	component.ListPrice = variant.Price
+	return component, nil
+}
+
+func findSlotOption(slot *model.BundleSlot, choice *model.BundleChoice) *model.BundleSlotOption {
+	var found *model.BundleSlotOption
+	for _, option := range slot.Options {
+		if option.ProductID != choice.ProductID {
+			continue
+		}
+		// exact variant wins over the any variant option
+		if option.VariantID == choice.VariantID {
+			return option
+		}
+		if option.VariantID == 0 {
+			found = option
+		}
+	}
+	return found
+}
+
+// allocateRevenue split the bundle price to the components weighted by their
+// list price (or quantity when nothing has a list price), upcharge stay with
+// the component that cause it, the shares always add up to the price
+func allocateRevenue(price money.Amount, components []*model.BundleComponent) {
+	weights := make([]int64, len(components))
+	var listed bool
+	for i, component := range components {
+		weights[i] = int64(component.ListPrice.Mul(component.Qty))
+		listed = listed || weights[i] > 0
+	}
+	if !listed {
+		for i, component := range components {
+			weights[i] = int64(component.Qty)
+		}
+	}
+	// weights are never negative, prices are validated on write
+	shares, _ := price.Allocate(weights...)
+	for i, component := range components {
+		component.Revenue = shares[i] + component.Upcharge.Mul(component.Qty)
+	}
+}
+
+func NewCatalogBundleService(
+	bundleRepo model.IBundleRepository,
+	productRepo model.ICRUDWithSearchRepository[model.Product],
+	variantRepo model.ICRUDRepository[model.ProductVariant],
+) model.ICatalogBundleService {
+	return &catalogBundleService{
+		bundleRepo:  bundleRepo,
+		productRepo: productRepo,
+		variantRepo: variantRepo,
+	}
+}
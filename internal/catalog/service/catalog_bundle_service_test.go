@@ -0,0 +1,162 @@
+package service_test
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"testing"
+
+	"github.com/aasumitro/posbe/internal/catalog/service"
+	"github.com/aasumitro/posbe/mocks"
+	"github.com/aasumitro/posbe/pkg/model"
+	"github.com/aasumitro/posbe/pkg/money"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type catalogBundleTestSuite struct {
+	suite.Suite
+	bundleRepo  *mocks.IBundleRepository
+	productRepo *mocks.ICRUDWithSearchRepository[model.Product]
+	variantRepo *mocks.ISoftDeleteRepository[model.ProductVariant]
+	svc         model.ICatalogBundleService
+	bundle      *model.Bundle
+}
+
+func (suite *catalogBundleTestSuite) SetupTest() {
+	suite.bundleRepo = new(mocks.IBundleRepository)
+	suite.productRepo = new(mocks.ICRUDWithSearchRepository[model.Product])
+	suite.variantRepo = new(mocks.ISoftDeleteRepository[model.ProductVariant])
+	suite.svc = service.NewCatalogBundleService(
+		suite.bundleRepo, suite.productRepo, suite.variantRepo)
+	// burger set: burger + fries, pick 1 drink from juice (subcategory 4)
+	// or the premium shake with upcharge
+	suite.bundle = &model.Bundle{
+		ProductID: 10,
+		Items: []*model.BundleItem{
+			{ProductID: 1, Qty: 1},
+			{ProductID: 2, VariantID: 20, Qty: 1},
+		},
+		Slots: []*model.BundleSlot{
+			{ID: 1, Name: "drink", SubcategoryID: 4, Qty: 1,
+				Options: []*model.BundleSlotOption{{ProductID: 5, Upcharge: 5000}}},
+		},
+	}
+	products := map[int]*model.Product{
+		10: {ID: 10, Name: "burger set", Price: 50000, CategoryID: 1, SubcategoryID: 9},
+		1:  {ID: 1, Name: "burger", Price: 40000, CategoryID: 2, SubcategoryID: 7},
+		2:  {ID: 2, Name: "fries", Price: 0, CategoryID: 2, SubcategoryID: 8},
+		3:  {ID: 3, Name: "orange juice", Price: 20000, CategoryID: 1, SubcategoryID: 4},
+		4:  {ID: 4, Name: "cola", Price: 15000, CategoryID: 1, SubcategoryID: 5},
+		5:  {ID: 5, Name: "shake", Price: 30000, CategoryID: 1, SubcategoryID: 6},
+	}
+	suite.productRepo.On("Find", mock.Anything, model.FindWithID, mock.Anything).
+		Return(func(_ context.Context, _ model.FindWith, val any) *model.Product {
+			return products[val.(int)]
+		}, func(_ context.Context, _ model.FindWith, val any) error {
+			if _, ok := products[val.(int)]; !ok {
+				return sql.ErrNoRows
+			}
+			return nil
+		})
+	suite.variantRepo.On("Find", mock.Anything, model.FindWithID, 20).
+		Return(&model.ProductVariant{ID: 20, ProductID: 2, Name: "large", Price: 20000}, nil)
+}
+
+func (suite *catalogBundleTestSuite) TestCatalogBundleService_ExpandBundle_ShouldAllocateRevenue() {
+	suite.bundleRepo.On("Find", mock.Anything, 10).Once().Return(suite.bundle, nil)
+	data, err := suite.svc.ExpandBundle(context.TODO(), 10, &model.BundleExpandForm{
+		Qty:     2,
+		Choices: []*model.BundleChoice{{SlotID: 1, ProductID: 5}},
+	})
+	require.Nil(suite.T(), err)
+	require.Equal(suite.T(), money.Amount(55000), data.UnitPrice)
+	require.Equal(suite.T(), money.Amount(110000), data.Total)
+	require.Len(suite.T(), data.Components, 3)
+	var revenue money.Amount
+	for _, component := range data.Components {
+		require.Equal(suite.T(), 2, component.Qty)
+		revenue += component.Revenue
+	}
+	require.Equal(suite.T(), data.Total, revenue)
+	// 40000 : 20000 : 30000 list price split of 50000, shake keep its upcharge
+	require.Equal(suite.T(), money.Amount(44444), data.Components[0].Revenue)
+	require.Equal(suite.T(), "fries (large)", data.Components[1].Name)
+	require.Equal(suite.T(), 1, data.Components[2].SlotID)
+}
+
+func (suite *catalogBundleTestSuite) TestCatalogBundleService_ExpandBundle_ShouldAcceptSubcategoryChoice() {
+	suite.bundleRepo.On("Find", mock.Anything, 10).Once().Return(suite.bundle, nil)
+	data, err := suite.svc.ExpandBundle(context.TODO(), 10, &model.BundleExpandForm{
+		Choices: []*model.BundleChoice{{SlotID: 1, ProductID: 3}},
+	})
+	require.Nil(suite.T(), err)
+	require.Equal(suite.T(), money.Amount(50000), data.Total)
+	require.Equal(suite.T(), money.Amount(0), data.Components[2].Upcharge)
+}
+
+func (suite *catalogBundleTestSuite) TestCatalogBundleService_ExpandBundle_ShouldReportChoiceErrors() {
+	suite.bundleRepo.On("Find", mock.Anything, 10).Once().Return(suite.bundle, nil)
+	data, err := suite.svc.ExpandBundle(context.TODO(), 10, &model.BundleExpandForm{
+		Choices: []*model.BundleChoice{
+			{SlotID: 1, ProductID: 4},
+			{SlotID: 9, ProductID: 3},
+			{SlotID: 1, ProductID: 99},
+		},
+	})
+	require.Nil(suite.T(), data)
+	require.Equal(suite.T(), http.StatusUnprocessableEntity, err.Code)
+	// cola not allowed, unknown slot, unknown product, drink slot not filled
+	require.Len(suite.T(), err.Message.([]*model.BundleChoiceError), 4)
+}
+
+func (suite *catalogBundleTestSuite) TestCatalogBundleService_ExpandBundle_ShouldErrorNotBundle() {
+	suite.bundleRepo.On("Find", mock.Anything, 10).Once().Return(nil, sql.ErrNoRows)
+	data, err := suite.svc.ExpandBundle(context.TODO(), 10, &model.BundleExpandForm{})
+	require.Nil(suite.T(), data)
+	require.Equal(suite.T(), http.StatusNotFound, err.Code)
+}
+
+func (suite *catalogBundleTestSuite) TestCatalogBundleService_SaveBundle_ShouldSuccess() {
+	suite.bundleRepo.On("Save", mock.Anything, suite.bundle).Once().Return(suite.bundle, nil)
+	data, err := suite.svc.SaveBundle(context.TODO(), suite.bundle)
+	require.Nil(suite.T(), err)
+	require.Equal(suite.T(), suite.bundle, data)
+}
+
+func (suite *catalogBundleTestSuite) TestCatalogBundleService_SaveBundle_ShouldErrorInvalidComponent() {
+	for _, bundle := range []*model.Bundle{
+		{ProductID: 10},
+		{ProductID: 10, Items: []*model.BundleItem{{ProductID: 10, Qty: 1}}},
+		{ProductID: 10, Items: []*model.BundleItem{{ProductID: 99, Qty: 1}}},
+		{ProductID: 10, Slots: []*model.BundleSlot{{Name: "drink", Qty: 1}}},
+	} {
+		data, err := suite.svc.SaveBundle(context.TODO(), bundle)
+		require.Nil(suite.T(), data)
+		require.Equal(suite.T(), http.StatusUnprocessableEntity, err.Code)
+	}
+}
+
+func (suite *catalogBundleTestSuite) TestCatalogBundleService_SaveBundle_ShouldErrorProductNotFound() {
+	data, err := suite.svc.SaveBundle(context.TODO(), &model.Bundle{ProductID: 99})
+	require.Nil(suite.T(), data)
+	require.Equal(suite.T(), http.StatusNotFound, err.Code)
+}
+
+func (suite *catalogBundleTestSuite) TestCatalogBundleService_DeleteBundle_ShouldSuccess() {
+	suite.bundleRepo.On("Find", mock.Anything, 10).Once().Return(suite.bundle, nil)
+	suite.bundleRepo.On("Delete", mock.Anything, 10).Once().Return(nil)
+	err := suite.svc.DeleteBundle(context.TODO(), 10)
+	require.Nil(suite.T(), err)
+}
+
+func (suite *catalogBundleTestSuite) TestCatalogBundleService_DeleteBundle_ShouldErrorNotFound() {
+	suite.bundleRepo.On("Find", mock.Anything, 10).Once().Return(nil, sql.ErrNoRows)
+	err := suite.svc.DeleteBundle(context.TODO(), 10)
+	require.Equal(suite.T(), http.StatusNotFound, err.Code)
+}
+
+func TestCatalogBundleService(t *testing.T) {
+	suite.Run(t, new(catalogBundleTestSuite))
+}
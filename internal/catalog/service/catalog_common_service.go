@@ -7,14 +7,16 @@ import (
 	"net/http"
 
 	"github.com/aasumitro/posbe/pkg/model"
+	"github.com/aasumitro/posbe/pkg/reference"
 	"github.com/aasumitro/posbe/pkg/utils"
 )
 
 type catalogCommonService struct {
-	unitRepo        model.ICRUDRepository[model.Unit]
-	categoryRepo    model.ICRUDRepository[model.Category]
-	subcategoryRepo model.ICRUDRepository[model.Subcategory]
-	addonRepo       model.ICRUDRepository[model.Addon]
+	unitRepo        model.ISoftDeleteRepository[model.Unit]
+	categoryRepo    model.ISoftDeleteRepository[model.Category]
+	subcategoryRepo model.ISoftDeleteRepository[model.Subcategory]
+	addonRepo       model.ISoftDeleteRepository[model.Addon]
+	referenceRepo   model.IReferenceRepository
 }
 
 func (service catalogCommonService) UnitList(
@@ -66,13 +68,20 @@ func (service catalogCommonService) DeleteUnit(
 			Message: err.Error(),
 		}
 	}
-	if err := service.unitRepo.Delete(ctx, data); err != nil {
-		return &utils.ServiceError{
-			Code:    http.StatusInternalServerError,
-			Message: err.Error(),
-		}
-	}
-	return nil
+	// the row is deleted by the reference repository with the reassign
+	model.AuditBefore(ctx, data)
+	return reference.Delete(ctx, service.referenceRepo, "units", data.ID)
+}
+
+func (service catalogCommonService) RestoreUnit(
+	ctx context.Context,
+	item *model.Unit,
+) (
+	units *model.Unit,
+	errData *utils.ServiceError,
+) {
+	data, err := service.unitRepo.Restore(ctx, item)
+	return utils.ValidateDataRow[model.Unit](data, err)
 }
 
 func (service catalogCommonService) CategoryList(
@@ -124,13 +133,20 @@ func (service catalogCommonService) DeleteCategory(
 			Message: err.Error(),
 		}
 	}
-	if err := service.categoryRepo.Delete(ctx, data); err != nil {
-		return &utils.ServiceError{
-			Code:    http.StatusInternalServerError,
-			Message: err.Error(),
-		}
-	}
-	return nil
+	// the row is deleted by the reference repository with the reassign
+	model.AuditBefore(ctx, data)
+	return reference.Delete(ctx, service.referenceRepo, "categories", data.ID)
+}
+
+func (service catalogCommonService) RestoreCategory(
+	ctx context.Context,
+	item *model.Category,
+) (
+	units *model.Category,
+	errData *utils.ServiceError,
+) {
+	data, err := service.categoryRepo.Restore(ctx, item)
+	return utils.ValidateDataRow[model.Category](data, err)
 }
 
 func (service catalogCommonService) SubcategoryList(
@@ -174,14 +190,20 @@ func (service catalogCommonService) DeleteSubcategory(
 			Message: err.Error(),
 		}
 	}
-	if err := service.subcategoryRepo.Delete(ctx, data); err != nil {
-		return &utils.ServiceError{
-			Code:    http.StatusInternalServerError,
-			Message: err.Error(),
-		}
-	}
+	// the row is deleted by the reference repository with the reassign
+	model.AuditBefore(ctx, data)
+	return reference.Delete(ctx, service.referenceRepo, "subcategories", data.ID)
+}
 
-	return nil
+func (service catalogCommonService) RestoreSubcategory(
+	ctx context.Context,
+	item *model.Subcategory,
+) (
+	units *model.Subcategory,
+	errData *utils.ServiceError,
+) {
+	data, err := service.subcategoryRepo.Restore(ctx, item)
+	return utils.ValidateDataRow[model.Subcategory](data, err)
 }
 
 func (service catalogCommonService) AddonList(
@@ -225,26 +247,34 @@ func (service catalogCommonService) DeleteAddon(
 		}
 	}
 
-	if err := service.addonRepo.Delete(ctx, data); err != nil {
-		return &utils.ServiceError{
-			Code:    http.StatusInternalServerError,
-			Message: err.Error(),
-		}
-	}
+	// the row is deleted by the reference repository with the reassign
+	model.AuditBefore(ctx, data)
+	return reference.Delete(ctx, service.referenceRepo, "addons", data.ID)
+}
 
-	return nil
+func (service catalogCommonService) RestoreAddon(
+	ctx context.Context,
+	item *model.Addon,
+) (
+	units *model.Addon,
+	errData *utils.ServiceError,
+) {
+	data, err := service.addonRepo.Restore(ctx, item)
+	return utils.ValidateDataRow[model.Addon](data, err)
 }
 
 func NewCatalogCommonService(
-	unitRepo model.ICRUDRepository[model.Unit],
-	categoryRepo model.ICRUDRepository[model.Category],
-	subcategoryRepo model.ICRUDRepository[model.Subcategory],
-	addonRepo model.ICRUDRepository[model.Addon],
+	unitRepo model.ISoftDeleteRepository[model.Unit],
+	categoryRepo model.ISoftDeleteRepository[model.Category],
+	subcategoryRepo model.ISoftDeleteRepository[model.Subcategory],
+	addonRepo model.ISoftDeleteRepository[model.Addon],
+	referenceRepo model.IReferenceRepository,
 ) model.ICatalogCommonService {
 	return &catalogCommonService{
 		unitRepo:        unitRepo,
 		categoryRepo:    categoryRepo,
 		subcategoryRepo: subcategoryRepo,
 		addonRepo:       addonRepo,
+		referenceRepo:   referenceRepo,
 	}
 }
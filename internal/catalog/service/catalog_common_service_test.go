@@ -44,10 +44,10 @@ func (suite *catalogCommonService) SetupSuite() {
 
 // === UNITS
 func (suite *catalogCommonService) TestService_UnitList_ShouldSuccess() {
-	repoMock := new(mocks.ICRUDRepository[model.Unit])
+	repoMock := new(mocks.ISoftDeleteRepository[model.Unit])
 	svc := service.NewCatalogCommonService(repoMock,
-		new(mocks.ICRUDRepository[model.Category]), new(mocks.ICRUDRepository[model.Subcategory]),
-		new(mocks.ICRUDRepository[model.Addon]))
+		new(mocks.ISoftDeleteRepository[model.Category]), new(mocks.ISoftDeleteRepository[model.Subcategory]),
+		new(mocks.ISoftDeleteRepository[model.Addon]), new(mocks.IReferenceRepository))
 	repoMock.On("All", mock.Anything).
 		Return(suite.units, nil).Once()
 	data, err := svc.UnitList(context.TODO())
@@ -57,10 +57,10 @@ func (suite *catalogCommonService) TestService_UnitList_ShouldSuccess() {
 	repoMock.AssertExpectations(suite.T())
 }
 func (suite *catalogCommonService) TestService_UnitList_ShouldError() {
-	repoMock := new(mocks.ICRUDRepository[model.Unit])
+	repoMock := new(mocks.ISoftDeleteRepository[model.Unit])
 	svc := service.NewCatalogCommonService(repoMock,
-		new(mocks.ICRUDRepository[model.Category]), new(mocks.ICRUDRepository[model.Subcategory]),
-		new(mocks.ICRUDRepository[model.Addon]))
+		new(mocks.ISoftDeleteRepository[model.Category]), new(mocks.ISoftDeleteRepository[model.Subcategory]),
+		new(mocks.ISoftDeleteRepository[model.Addon]), new(mocks.IReferenceRepository))
 	repoMock.On("All", mock.Anything).
 		Return(nil, errors.New("UNEXPECTED")).Once()
 	data, err := svc.UnitList(context.TODO())
@@ -71,10 +71,10 @@ func (suite *catalogCommonService) TestService_UnitList_ShouldError() {
 }
 
 func (suite *catalogCommonService) TestService_AddUnit_ShouldSuccess() {
-	repoMock := new(mocks.ICRUDRepository[model.Unit])
+	repoMock := new(mocks.ISoftDeleteRepository[model.Unit])
 	svc := service.NewCatalogCommonService(repoMock,
-		new(mocks.ICRUDRepository[model.Category]), new(mocks.ICRUDRepository[model.Subcategory]),
-		new(mocks.ICRUDRepository[model.Addon]))
+		new(mocks.ISoftDeleteRepository[model.Category]), new(mocks.ISoftDeleteRepository[model.Subcategory]),
+		new(mocks.ISoftDeleteRepository[model.Addon]), new(mocks.IReferenceRepository))
 	repoMock.On("Create", mock.Anything, mock.Anything).
 		Return(suite.unit, nil).Once()
 	data, err := svc.AddUnit(context.TODO(), suite.unit)
@@ -84,10 +84,10 @@ func (suite *catalogCommonService) TestService_AddUnit_ShouldSuccess() {
 	repoMock.AssertExpectations(suite.T())
 }
 func (suite *catalogCommonService) TestService_AddUnit_ShouldError() {
-	repoMock := new(mocks.ICRUDRepository[model.Unit])
+	repoMock := new(mocks.ISoftDeleteRepository[model.Unit])
 	svc := service.NewCatalogCommonService(repoMock,
-		new(mocks.ICRUDRepository[model.Category]), new(mocks.ICRUDRepository[model.Subcategory]),
-		new(mocks.ICRUDRepository[model.Addon]))
+		new(mocks.ISoftDeleteRepository[model.Category]), new(mocks.ISoftDeleteRepository[model.Subcategory]),
+		new(mocks.ISoftDeleteRepository[model.Addon]), new(mocks.IReferenceRepository))
 	repoMock.On("Create", mock.Anything, mock.Anything).
 		Return(nil, errors.New("UNEXPECTED")).Once()
 	data, err := svc.AddUnit(context.TODO(), suite.unit)
@@ -98,10 +98,10 @@ func (suite *catalogCommonService) TestService_AddUnit_ShouldError() {
 }
 
 func (suite *catalogCommonService) TestService_EditUnit_ShouldSuccess() {
-	repoMock := new(mocks.ICRUDRepository[model.Unit])
+	repoMock := new(mocks.ISoftDeleteRepository[model.Unit])
 	svc := service.NewCatalogCommonService(repoMock,
-		new(mocks.ICRUDRepository[model.Category]), new(mocks.ICRUDRepository[model.Subcategory]),
-		new(mocks.ICRUDRepository[model.Addon]))
+		new(mocks.ISoftDeleteRepository[model.Category]), new(mocks.ISoftDeleteRepository[model.Subcategory]),
+		new(mocks.ISoftDeleteRepository[model.Addon]), new(mocks.IReferenceRepository))
 	repoMock.On("Update", mock.Anything, mock.Anything).
 		Return(suite.unit, nil).Once()
 	data, err := svc.EditUnit(context.TODO(), suite.unit)
@@ -111,10 +111,10 @@ func (suite *catalogCommonService) TestService_EditUnit_ShouldSuccess() {
 	repoMock.AssertExpectations(suite.T())
 }
 func (suite *catalogCommonService) TestService_EditUnit_ShouldError() {
-	repoMock := new(mocks.ICRUDRepository[model.Unit])
+	repoMock := new(mocks.ISoftDeleteRepository[model.Unit])
 	svc := service.NewCatalogCommonService(repoMock,
-		new(mocks.ICRUDRepository[model.Category]), new(mocks.ICRUDRepository[model.Subcategory]),
-		new(mocks.ICRUDRepository[model.Addon]))
+		new(mocks.ISoftDeleteRepository[model.Category]), new(mocks.ISoftDeleteRepository[model.Subcategory]),
+		new(mocks.ISoftDeleteRepository[model.Addon]), new(mocks.IReferenceRepository))
 	repoMock.On("Update", mock.Anything, mock.Anything).
 		Return(nil, errors.New("UNEXPECTED")).Once()
 	data, err := svc.EditUnit(context.TODO(), suite.unit)
@@ -125,25 +125,28 @@ func (suite *catalogCommonService) TestService_EditUnit_ShouldError() {
 }
 
 func (suite *catalogCommonService) TestService_DeleteUnit_ShouldSuccess() {
-	repoMock := new(mocks.ICRUDRepository[model.Unit])
+	repoMock := new(mocks.ISoftDeleteRepository[model.Unit])
+	referenceRepoMock := new(mocks.IReferenceRepository)
+	referenceRepoMock.
+		On("Delete", mock.Anything, "units", mock.Anything, 0).
+		Once().
+		Return(nil, nil)
 	svc := service.NewCatalogCommonService(repoMock,
-		new(mocks.ICRUDRepository[model.Category]), new(mocks.ICRUDRepository[model.Subcategory]),
-		new(mocks.ICRUDRepository[model.Addon]))
+		new(mocks.ISoftDeleteRepository[model.Category]), new(mocks.ISoftDeleteRepository[model.Subcategory]),
+		new(mocks.ISoftDeleteRepository[model.Addon]), referenceRepoMock)
 	repoMock.
 		On("Find", mock.Anything, mock.Anything, mock.Anything).
 		Return(suite.units[1], nil).Once()
-	repoMock.
-		On("Delete", mock.Anything, mock.Anything).
-		Return(nil).Once()
 	err := svc.DeleteUnit(context.TODO(), suite.units[1])
 	require.Nil(suite.T(), err)
 	repoMock.AssertExpectations(suite.T())
+	referenceRepoMock.AssertExpectations(suite.T())
 }
 func (suite *catalogCommonService) TestService_DeleteUnit_ShouldErrorWhenFind() {
-	repoMock := new(mocks.ICRUDRepository[model.Unit])
+	repoMock := new(mocks.ISoftDeleteRepository[model.Unit])
 	svc := service.NewCatalogCommonService(repoMock,
-		new(mocks.ICRUDRepository[model.Category]), new(mocks.ICRUDRepository[model.Subcategory]),
-		new(mocks.ICRUDRepository[model.Addon]))
+		new(mocks.ISoftDeleteRepository[model.Category]), new(mocks.ISoftDeleteRepository[model.Subcategory]),
+		new(mocks.ISoftDeleteRepository[model.Addon]), new(mocks.IReferenceRepository))
 	repoMock.
 		On("Find", mock.Anything, mock.Anything, mock.Anything).
 		Once().
@@ -154,10 +157,10 @@ func (suite *catalogCommonService) TestService_DeleteUnit_ShouldErrorWhenFind()
 	repoMock.AssertExpectations(suite.T())
 }
 func (suite *catalogCommonService) TestService_DeleteUnit_ShouldErrorWhenFindNotFound() {
-	repoMock := new(mocks.ICRUDRepository[model.Unit])
+	repoMock := new(mocks.ISoftDeleteRepository[model.Unit])
 	svc := service.NewCatalogCommonService(repoMock,
-		new(mocks.ICRUDRepository[model.Category]), new(mocks.ICRUDRepository[model.Subcategory]),
-		new(mocks.ICRUDRepository[model.Addon]))
+		new(mocks.ISoftDeleteRepository[model.Category]), new(mocks.ISoftDeleteRepository[model.Subcategory]),
+		new(mocks.ISoftDeleteRepository[model.Addon]), new(mocks.IReferenceRepository))
 	repoMock.
 		On("Find", mock.Anything, mock.Anything, mock.Anything).
 		Once().
@@ -168,28 +171,56 @@ func (suite *catalogCommonService) TestService_DeleteUnit_ShouldErrorWhenFindNot
 	repoMock.AssertExpectations(suite.T())
 }
 func (suite *catalogCommonService) TestService_DeleteUnit_ShouldErrorWhenDelete() {
-	repoMock := new(mocks.ICRUDRepository[model.Unit])
+	repoMock := new(mocks.ISoftDeleteRepository[model.Unit])
+	referenceRepoMock := new(mocks.IReferenceRepository)
+	referenceRepoMock.
+		On("Delete", mock.Anything, "units", mock.Anything, 0).
+		Once().
+		Return(nil, errors.New("UNEXPECTED"))
 	svc := service.NewCatalogCommonService(repoMock,
-		new(mocks.ICRUDRepository[model.Category]), new(mocks.ICRUDRepository[model.Subcategory]),
-		new(mocks.ICRUDRepository[model.Addon]))
+		new(mocks.ISoftDeleteRepository[model.Category]), new(mocks.ISoftDeleteRepository[model.Subcategory]),
+		new(mocks.ISoftDeleteRepository[model.Addon]), referenceRepoMock)
 	repoMock.
 		On("Find", mock.Anything, mock.Anything, mock.Anything).
 		Return(suite.units[1], nil).Once()
-	repoMock.
-		On("Delete", mock.Anything, mock.Anything).
-		Return(errors.New("UNEXPECTED")).Once()
 	err := svc.DeleteUnit(context.TODO(), suite.unit)
 	require.NotNil(suite.T(), err)
 	require.Equal(suite.T(), err, suite.svcErr)
 	repoMock.AssertExpectations(suite.T())
+	referenceRepoMock.AssertExpectations(suite.T())
+}
+
+func (suite *catalogCommonService) TestService_RestoreUnit_ShouldSuccess() {
+	repoMock := new(mocks.ISoftDeleteRepository[model.Unit])
+	svc := service.NewCatalogCommonService(repoMock,
+		new(mocks.ISoftDeleteRepository[model.Category]), new(mocks.ISoftDeleteRepository[model.Subcategory]),
+		new(mocks.ISoftDeleteRepository[model.Addon]), new(mocks.IReferenceRepository))
+	repoMock.On("Restore", mock.Anything, mock.Anything).
+		Return(suite.unit, nil).Once()
+	data, err := svc.RestoreUnit(context.TODO(), &model.Unit{ID: 1})
+	require.Nil(suite.T(), err)
+	require.Equal(suite.T(), data, suite.unit)
+	repoMock.AssertExpectations(suite.T())
+}
+func (suite *catalogCommonService) TestService_RestoreUnit_ShouldErrorNotDeleted() {
+	repoMock := new(mocks.ISoftDeleteRepository[model.Unit])
+	svc := service.NewCatalogCommonService(repoMock,
+		new(mocks.ISoftDeleteRepository[model.Category]), new(mocks.ISoftDeleteRepository[model.Subcategory]),
+		new(mocks.ISoftDeleteRepository[model.Addon]), new(mocks.IReferenceRepository))
+	repoMock.On("Restore", mock.Anything, mock.Anything).
+		Return(nil, sql.ErrNoRows).Once()
+	data, err := svc.RestoreUnit(context.TODO(), &model.Unit{ID: 1})
+	require.Nil(suite.T(), data)
+	require.Equal(suite.T(), err, &utils.ServiceError{Code: 404, Message: "sql: no rows in result set"})
+	repoMock.AssertExpectations(suite.T())
 }
 
 // === Category
 func (suite *catalogCommonService) TestService_CategoryList_ShouldSuccess() {
-	repoMock := new(mocks.ICRUDRepository[model.Category])
+	repoMock := new(mocks.ISoftDeleteRepository[model.Category])
 	svc := service.NewCatalogCommonService(
-		new(mocks.ICRUDRepository[model.Unit]), repoMock, new(mocks.ICRUDRepository[model.Subcategory]),
-		new(mocks.ICRUDRepository[model.Addon]))
+		new(mocks.ISoftDeleteRepository[model.Unit]), repoMock, new(mocks.ISoftDeleteRepository[model.Subcategory]),
+		new(mocks.ISoftDeleteRepository[model.Addon]), new(mocks.IReferenceRepository))
 	repoMock.On("All", mock.Anything).
 		Return(suite.categories, nil).Once()
 	data, err := svc.CategoryList(context.TODO())
@@ -199,11 +230,11 @@ func (suite *catalogCommonService) TestService_CategoryList_ShouldSuccess() {
 	repoMock.AssertExpectations(suite.T())
 }
 func (suite *catalogCommonService) TestService_CategoryList_ShouldError() {
-	repoMock := new(mocks.ICRUDRepository[model.Category])
+	repoMock := new(mocks.ISoftDeleteRepository[model.Category])
 	svc := service.NewCatalogCommonService(
-		new(mocks.ICRUDRepository[model.Unit]), repoMock,
-		new(mocks.ICRUDRepository[model.Subcategory]),
-		new(mocks.ICRUDRepository[model.Addon]))
+		new(mocks.ISoftDeleteRepository[model.Unit]), repoMock,
+		new(mocks.ISoftDeleteRepository[model.Subcategory]),
+		new(mocks.ISoftDeleteRepository[model.Addon]), new(mocks.IReferenceRepository))
 	repoMock.On("All", mock.Anything).
 		Return(nil, errors.New("UNEXPECTED")).Once()
 	data, err := svc.CategoryList(context.TODO())
@@ -214,11 +245,11 @@ func (suite *catalogCommonService) TestService_CategoryList_ShouldError() {
 }
 
 func (suite *catalogCommonService) TestService_AddCategory_ShouldSuccess() {
-	repoMock := new(mocks.ICRUDRepository[model.Category])
+	repoMock := new(mocks.ISoftDeleteRepository[model.Category])
 	svc := service.NewCatalogCommonService(
-		new(mocks.ICRUDRepository[model.Unit]), repoMock,
-		new(mocks.ICRUDRepository[model.Subcategory]),
-		new(mocks.ICRUDRepository[model.Addon]))
+		new(mocks.ISoftDeleteRepository[model.Unit]), repoMock,
+		new(mocks.ISoftDeleteRepository[model.Subcategory]),
+		new(mocks.ISoftDeleteRepository[model.Addon]), new(mocks.IReferenceRepository))
 	repoMock.On("Create", mock.Anything, mock.Anything).
 		Return(suite.category, nil).Once()
 	data, err := svc.AddCategory(context.TODO(), suite.category)
@@ -228,11 +259,11 @@ func (suite *catalogCommonService) TestService_AddCategory_ShouldSuccess() {
 	repoMock.AssertExpectations(suite.T())
 }
 func (suite *catalogCommonService) TestService_AddCategory_ShouldError() {
-	repoMock := new(mocks.ICRUDRepository[model.Category])
+	repoMock := new(mocks.ISoftDeleteRepository[model.Category])
 	svc := service.NewCatalogCommonService(
-		new(mocks.ICRUDRepository[model.Unit]), repoMock,
-		new(mocks.ICRUDRepository[model.Subcategory]),
-		new(mocks.ICRUDRepository[model.Addon]))
+		new(mocks.ISoftDeleteRepository[model.Unit]), repoMock,
+		new(mocks.ISoftDeleteRepository[model.Subcategory]),
+		new(mocks.ISoftDeleteRepository[model.Addon]), new(mocks.IReferenceRepository))
 	repoMock.On("Create", mock.Anything, mock.Anything).
 		Return(nil, errors.New("UNEXPECTED")).Once()
 	data, err := svc.AddCategory(context.TODO(), suite.category)
@@ -243,11 +274,11 @@ func (suite *catalogCommonService) TestService_AddCategory_ShouldError() {
 }
 
 func (suite *catalogCommonService) TestService_EditCategory_ShouldSuccess() {
-	repoMock := new(mocks.ICRUDRepository[model.Category])
+	repoMock := new(mocks.ISoftDeleteRepository[model.Category])
 	svc := service.NewCatalogCommonService(
-		new(mocks.ICRUDRepository[model.Unit]), repoMock,
-		new(mocks.ICRUDRepository[model.Subcategory]),
-		new(mocks.ICRUDRepository[model.Addon]))
+		new(mocks.ISoftDeleteRepository[model.Unit]), repoMock,
+		new(mocks.ISoftDeleteRepository[model.Subcategory]),
+		new(mocks.ISoftDeleteRepository[model.Addon]), new(mocks.IReferenceRepository))
 	repoMock.On("Update", mock.Anything, mock.Anything).
 		Return(suite.category, nil).Once()
 	data, err := svc.EditCategory(context.TODO(), suite.category)
@@ -257,11 +288,11 @@ func (suite *catalogCommonService) TestService_EditCategory_ShouldSuccess() {
 	repoMock.AssertExpectations(suite.T())
 }
 func (suite *catalogCommonService) TestService_EditCategory_ShouldError() {
-	repoMock := new(mocks.ICRUDRepository[model.Category])
+	repoMock := new(mocks.ISoftDeleteRepository[model.Category])
 	svc := service.NewCatalogCommonService(
-		new(mocks.ICRUDRepository[model.Unit]), repoMock,
-		new(mocks.ICRUDRepository[model.Subcategory]),
-		new(mocks.ICRUDRepository[model.Addon]))
+		new(mocks.ISoftDeleteRepository[model.Unit]), repoMock,
+		new(mocks.ISoftDeleteRepository[model.Subcategory]),
+		new(mocks.ISoftDeleteRepository[model.Addon]), new(mocks.IReferenceRepository))
 	repoMock.On("Update", mock.Anything, mock.Anything).
 		Return(nil, errors.New("UNEXPECTED")).Once()
 	data, err := svc.EditCategory(context.TODO(), suite.category)
@@ -272,27 +303,30 @@ func (suite *catalogCommonService) TestService_EditCategory_ShouldError() {
 }
 
 func (suite *catalogCommonService) TestService_DeleteCategory_ShouldSuccess() {
-	repoMock := new(mocks.ICRUDRepository[model.Category])
+	repoMock := new(mocks.ISoftDeleteRepository[model.Category])
+	referenceRepoMock := new(mocks.IReferenceRepository)
+	referenceRepoMock.
+		On("Delete", mock.Anything, "categories", mock.Anything, 0).
+		Once().
+		Return(nil, nil)
 	svc := service.NewCatalogCommonService(
-		new(mocks.ICRUDRepository[model.Unit]), repoMock,
-		new(mocks.ICRUDRepository[model.Subcategory]),
-		new(mocks.ICRUDRepository[model.Addon]))
+		new(mocks.ISoftDeleteRepository[model.Unit]), repoMock,
+		new(mocks.ISoftDeleteRepository[model.Subcategory]),
+		new(mocks.ISoftDeleteRepository[model.Addon]), referenceRepoMock)
 	repoMock.
 		On("Find", mock.Anything, mock.Anything, mock.Anything).
 		Return(suite.categories[1], nil).Once()
-	repoMock.
-		On("Delete", mock.Anything, mock.Anything).
-		Return(nil).Once()
 	err := svc.DeleteCategory(context.TODO(), suite.categories[1])
 	require.Nil(suite.T(), err)
 	repoMock.AssertExpectations(suite.T())
+	referenceRepoMock.AssertExpectations(suite.T())
 }
 func (suite *catalogCommonService) TestService_DeleteCategory_ShouldErrorWhenFind() {
-	repoMock := new(mocks.ICRUDRepository[model.Category])
+	repoMock := new(mocks.ISoftDeleteRepository[model.Category])
 	svc := service.NewCatalogCommonService(
-		new(mocks.ICRUDRepository[model.Unit]), repoMock,
-		new(mocks.ICRUDRepository[model.Subcategory]),
-		new(mocks.ICRUDRepository[model.Addon]))
+		new(mocks.ISoftDeleteRepository[model.Unit]), repoMock,
+		new(mocks.ISoftDeleteRepository[model.Subcategory]),
+		new(mocks.ISoftDeleteRepository[model.Addon]), new(mocks.IReferenceRepository))
 	repoMock.
 		On("Find", mock.Anything, mock.Anything, mock.Anything).
 		Once().
@@ -303,11 +337,11 @@ func (suite *catalogCommonService) TestService_DeleteCategory_ShouldErrorWhenFin
 	repoMock.AssertExpectations(suite.T())
 }
 func (suite *catalogCommonService) TestService_DeleteCategory_ShouldErrorWhenFindNotFound() {
-	repoMock := new(mocks.ICRUDRepository[model.Category])
+	repoMock := new(mocks.ISoftDeleteRepository[model.Category])
 	svc := service.NewCatalogCommonService(
-		new(mocks.ICRUDRepository[model.Unit]), repoMock,
-		new(mocks.ICRUDRepository[model.Subcategory]),
-		new(mocks.ICRUDRepository[model.Addon]))
+		new(mocks.ISoftDeleteRepository[model.Unit]), repoMock,
+		new(mocks.ISoftDeleteRepository[model.Subcategory]),
+		new(mocks.ISoftDeleteRepository[model.Addon]), new(mocks.IReferenceRepository))
 	repoMock.
 		On("Find", mock.Anything, mock.Anything, mock.Anything).
 		Once().
@@ -318,30 +352,33 @@ func (suite *catalogCommonService) TestService_DeleteCategory_ShouldErrorWhenFin
 	repoMock.AssertExpectations(suite.T())
 }
 func (suite *catalogCommonService) TestService_DeleteCategory_ShouldErrorWhenDelete() {
-	repoMock := new(mocks.ICRUDRepository[model.Category])
+	repoMock := new(mocks.ISoftDeleteRepository[model.Category])
+	referenceRepoMock := new(mocks.IReferenceRepository)
+	referenceRepoMock.
+		On("Delete", mock.Anything, "categories", mock.Anything, 0).
+		Once().
+		Return(nil, errors.New("UNEXPECTED"))
 	svc := service.NewCatalogCommonService(
-		new(mocks.ICRUDRepository[model.Unit]), repoMock,
-		new(mocks.ICRUDRepository[model.Subcategory]),
-		new(mocks.ICRUDRepository[model.Addon]))
+		new(mocks.ISoftDeleteRepository[model.Unit]), repoMock,
+		new(mocks.ISoftDeleteRepository[model.Subcategory]),
+		new(mocks.ISoftDeleteRepository[model.Addon]), referenceRepoMock)
 	repoMock.
 		On("Find", mock.Anything, mock.Anything, mock.Anything).
 		Return(suite.categories[1], nil).Once()
-	repoMock.
-		On("Delete", mock.Anything, mock.Anything).
-		Return(errors.New("UNEXPECTED")).Once()
 	err := svc.DeleteCategory(context.TODO(), suite.category)
 	require.NotNil(suite.T(), err)
 	require.Equal(suite.T(), err, suite.svcErr)
 	repoMock.AssertExpectations(suite.T())
+	referenceRepoMock.AssertExpectations(suite.T())
 }
 
 // === Subcategory
 func (suite *catalogCommonService) TestService_SubcategoryList_ShouldSuccess() {
-	repoMock := new(mocks.ICRUDRepository[model.Subcategory])
+	repoMock := new(mocks.ISoftDeleteRepository[model.Subcategory])
 	svc := service.NewCatalogCommonService(
-		new(mocks.ICRUDRepository[model.Unit]),
-		new(mocks.ICRUDRepository[model.Category]), repoMock,
-		new(mocks.ICRUDRepository[model.Addon]))
+		new(mocks.ISoftDeleteRepository[model.Unit]),
+		new(mocks.ISoftDeleteRepository[model.Category]), repoMock,
+		new(mocks.ISoftDeleteRepository[model.Addon]), new(mocks.IReferenceRepository))
 	repoMock.On("All", mock.Anything).
 		Return(suite.subcategories, nil).Once()
 	data, err := svc.SubcategoryList(context.TODO())
@@ -351,11 +388,11 @@ func (suite *catalogCommonService) TestService_SubcategoryList_ShouldSuccess() {
 	repoMock.AssertExpectations(suite.T())
 }
 func (suite *catalogCommonService) TestService_SubcategoryList_ShouldError() {
-	repoMock := new(mocks.ICRUDRepository[model.Subcategory])
+	repoMock := new(mocks.ISoftDeleteRepository[model.Subcategory])
 	svc := service.NewCatalogCommonService(
-		new(mocks.ICRUDRepository[model.Unit]),
-		new(mocks.ICRUDRepository[model.Category]), repoMock,
-		new(mocks.ICRUDRepository[model.Addon]))
+		new(mocks.ISoftDeleteRepository[model.Unit]),
+		new(mocks.ISoftDeleteRepository[model.Category]), repoMock,
+		new(mocks.ISoftDeleteRepository[model.Addon]), new(mocks.IReferenceRepository))
 	repoMock.On("All", mock.Anything).
 		Return(nil, errors.New("UNEXPECTED")).Once()
 	data, err := svc.SubcategoryList(context.TODO())
@@ -366,10 +403,10 @@ func (suite *catalogCommonService) TestService_SubcategoryList_ShouldError() {
 }
 
 func (suite *catalogCommonService) TestService_AddSubcategory_ShouldSuccess() {
-	repoMock := new(mocks.ICRUDRepository[model.Subcategory])
+	repoMock := new(mocks.ISoftDeleteRepository[model.Subcategory])
 	svc := service.NewCatalogCommonService(
-		new(mocks.ICRUDRepository[model.Unit]), new(mocks.ICRUDRepository[model.Category]), repoMock,
-		new(mocks.ICRUDRepository[model.Addon]))
+		new(mocks.ISoftDeleteRepository[model.Unit]), new(mocks.ISoftDeleteRepository[model.Category]), repoMock,
+		new(mocks.ISoftDeleteRepository[model.Addon]), new(mocks.IReferenceRepository))
 	repoMock.On("Create", mock.Anything, mock.Anything).
 		Return(suite.subcategory, nil).Once()
 	data, err := svc.AddSubcategory(context.TODO(), suite.subcategory)
@@ -379,11 +416,11 @@ func (suite *catalogCommonService) TestService_AddSubcategory_ShouldSuccess() {
 	repoMock.AssertExpectations(suite.T())
 }
 func (suite *catalogCommonService) TestService_AddSubcategory_ShouldError() {
-	repoMock := new(mocks.ICRUDRepository[model.Subcategory])
+	repoMock := new(mocks.ISoftDeleteRepository[model.Subcategory])
 	svc := service.NewCatalogCommonService(
-		new(mocks.ICRUDRepository[model.Unit]),
-		new(mocks.ICRUDRepository[model.Category]), repoMock,
-		new(mocks.ICRUDRepository[model.Addon]))
+		new(mocks.ISoftDeleteRepository[model.Unit]),
+		new(mocks.ISoftDeleteRepository[model.Category]), repoMock,
+		new(mocks.ISoftDeleteRepository[model.Addon]), new(mocks.IReferenceRepository))
 	repoMock.On("Create", mock.Anything, mock.Anything).
 		Return(nil, errors.New("UNEXPECTED")).Once()
 	data, err := svc.AddSubcategory(context.TODO(), suite.subcategory)
@@ -394,11 +431,11 @@ func (suite *catalogCommonService) TestService_AddSubcategory_ShouldError() {
 }
 
 func (suite *catalogCommonService) TestService_EditSubcategory_ShouldSuccess() {
-	repoMock := new(mocks.ICRUDRepository[model.Subcategory])
+	repoMock := new(mocks.ISoftDeleteRepository[model.Subcategory])
 	svc := service.NewCatalogCommonService(
-		new(mocks.ICRUDRepository[model.Unit]),
-		new(mocks.ICRUDRepository[model.Category]), repoMock,
-		new(mocks.ICRUDRepository[model.Addon]))
+		new(mocks.ISoftDeleteRepository[model.Unit]),
+		new(mocks.ISoftDeleteRepository[model.Category]), repoMock,
+		new(mocks.ISoftDeleteRepository[model.Addon]), new(mocks.IReferenceRepository))
 	repoMock.On("Update", mock.Anything, mock.Anything).
 		Return(suite.subcategory, nil).Once()
 	data, err := svc.EditSubcategory(context.TODO(), suite.subcategory)
@@ -408,11 +445,11 @@ func (suite *catalogCommonService) TestService_EditSubcategory_ShouldSuccess() {
 	repoMock.AssertExpectations(suite.T())
 }
 func (suite *catalogCommonService) TestService_EditSubcategory_ShouldError() {
-	repoMock := new(mocks.ICRUDRepository[model.Subcategory])
+	repoMock := new(mocks.ISoftDeleteRepository[model.Subcategory])
 	svc := service.NewCatalogCommonService(
-		new(mocks.ICRUDRepository[model.Unit]),
-		new(mocks.ICRUDRepository[model.Category]), repoMock,
-		new(mocks.ICRUDRepository[model.Addon]))
+		new(mocks.ISoftDeleteRepository[model.Unit]),
+		new(mocks.ISoftDeleteRepository[model.Category]), repoMock,
+		new(mocks.ISoftDeleteRepository[model.Addon]), new(mocks.IReferenceRepository))
 	repoMock.On("Update", mock.Anything, mock.Anything).
 		Return(nil, errors.New("UNEXPECTED")).Once()
 	data, err := svc.EditSubcategory(context.TODO(), suite.subcategory)
@@ -423,27 +460,50 @@ func (suite *catalogCommonService) TestService_EditSubcategory_ShouldError() {
 }
 
 func (suite *catalogCommonService) TestService_DeleteSubcategory_ShouldSuccess() {
-	repoMock := new(mocks.ICRUDRepository[model.Subcategory])
+	repoMock := new(mocks.ISoftDeleteRepository[model.Subcategory])
+	referenceRepoMock := new(mocks.IReferenceRepository)
+	referenceRepoMock.
+		On("Delete", mock.Anything, "subcategories", mock.Anything, 0).
+		Once().
+		Return(nil, nil)
 	svc := service.NewCatalogCommonService(
-		new(mocks.ICRUDRepository[model.Unit]),
-		new(mocks.ICRUDRepository[model.Category]), repoMock,
-		new(mocks.ICRUDRepository[model.Addon]))
+		new(mocks.ISoftDeleteRepository[model.Unit]),
+		new(mocks.ISoftDeleteRepository[model.Category]), repoMock,
+		new(mocks.ISoftDeleteRepository[model.Addon]), referenceRepoMock)
 	repoMock.
 		On("Find", mock.Anything, mock.Anything, mock.Anything).
 		Return(suite.subcategories[1], nil).Once()
-	repoMock.
-		On("Delete", mock.Anything, mock.Anything).
-		Return(nil).Once()
 	err := svc.DeleteSubcategory(context.TODO(), suite.subcategories[1])
 	require.Nil(suite.T(), err)
 	repoMock.AssertExpectations(suite.T())
+	referenceRepoMock.AssertExpectations(suite.T())
+}
+func (suite *catalogCommonService) TestService_DeleteSubcategory_ShouldReassignProducts() {
+	repoMock := new(mocks.ISoftDeleteRepository[model.Subcategory])
+	referenceRepoMock := new(mocks.IReferenceRepository)
+	referenceRepoMock.
+		On("Delete", mock.Anything, "subcategories", 1, 2).
+		Once().
+		Return(nil, nil)
+	svc := service.NewCatalogCommonService(
+		new(mocks.ISoftDeleteRepository[model.Unit]),
+		new(mocks.ISoftDeleteRepository[model.Category]), repoMock,
+		new(mocks.ISoftDeleteRepository[model.Addon]), referenceRepoMock)
+	repoMock.
+		On("Find", mock.Anything, mock.Anything, mock.Anything).
+		Return(&model.Subcategory{ID: 1, CategoryID: 1, Name: "lorem"}, nil).Once()
+	ctx := context.WithValue(context.TODO(), model.ReassignToKey, 2) //nolint:staticcheck // gin context keys are strings
+	err := svc.DeleteSubcategory(ctx, &model.Subcategory{ID: 1})
+	require.Nil(suite.T(), err)
+	repoMock.AssertExpectations(suite.T())
+	referenceRepoMock.AssertExpectations(suite.T())
 }
 func (suite *catalogCommonService) TestService_DeleteSubcategory_ShouldErrorWhenFind() {
-	repoMock := new(mocks.ICRUDRepository[model.Subcategory])
+	repoMock := new(mocks.ISoftDeleteRepository[model.Subcategory])
 	svc := service.NewCatalogCommonService(
-		new(mocks.ICRUDRepository[model.Unit]),
-		new(mocks.ICRUDRepository[model.Category]), repoMock,
-		new(mocks.ICRUDRepository[model.Addon]))
+		new(mocks.ISoftDeleteRepository[model.Unit]),
+		new(mocks.ISoftDeleteRepository[model.Category]), repoMock,
+		new(mocks.ISoftDeleteRepository[model.Addon]), new(mocks.IReferenceRepository))
 	repoMock.
 		On("Find", mock.Anything, mock.Anything, mock.Anything).
 		Once().
@@ -454,11 +514,11 @@ func (suite *catalogCommonService) TestService_DeleteSubcategory_ShouldErrorWhen
 	repoMock.AssertExpectations(suite.T())
 }
 func (suite *catalogCommonService) TestService_DeleteSubcategory_ShouldErrorWhenFindNotFound() {
-	repoMock := new(mocks.ICRUDRepository[model.Subcategory])
+	repoMock := new(mocks.ISoftDeleteRepository[model.Subcategory])
 	svc := service.NewCatalogCommonService(
-		new(mocks.ICRUDRepository[model.Unit]),
-		new(mocks.ICRUDRepository[model.Category]), repoMock,
-		new(mocks.ICRUDRepository[model.Addon]))
+		new(mocks.ISoftDeleteRepository[model.Unit]),
+		new(mocks.ISoftDeleteRepository[model.Category]), repoMock,
+		new(mocks.ISoftDeleteRepository[model.Addon]), new(mocks.IReferenceRepository))
 	repoMock.
 		On("Find", mock.Anything, mock.Anything, mock.Anything).
 		Once().
@@ -469,30 +529,33 @@ func (suite *catalogCommonService) TestService_DeleteSubcategory_ShouldErrorWhen
 	repoMock.AssertExpectations(suite.T())
 }
 func (suite *catalogCommonService) TestService_DeleteSubcategory_ShouldErrorWhenDelete() {
-	repoMock := new(mocks.ICRUDRepository[model.Subcategory])
+	repoMock := new(mocks.ISoftDeleteRepository[model.Subcategory])
+	referenceRepoMock := new(mocks.IReferenceRepository)
+	referenceRepoMock.
+		On("Delete", mock.Anything, "subcategories", mock.Anything, 0).
+		Once().
+		Return(nil, errors.New("UNEXPECTED"))
 	svc := service.NewCatalogCommonService(
-		new(mocks.ICRUDRepository[model.Unit]),
-		new(mocks.ICRUDRepository[model.Category]), repoMock,
-		new(mocks.ICRUDRepository[model.Addon]))
+		new(mocks.ISoftDeleteRepository[model.Unit]),
+		new(mocks.ISoftDeleteRepository[model.Category]), repoMock,
+		new(mocks.ISoftDeleteRepository[model.Addon]), referenceRepoMock)
 	repoMock.
 		On("Find", mock.Anything, mock.Anything, mock.Anything).
 		Return(suite.subcategories[1], nil).Once()
-	repoMock.
-		On("Delete", mock.Anything, mock.Anything).
-		Return(errors.New("UNEXPECTED")).Once()
 	err := svc.DeleteSubcategory(context.TODO(), suite.subcategory)
 	require.NotNil(suite.T(), err)
 	require.Equal(suite.T(), err, suite.svcErr)
 	repoMock.AssertExpectations(suite.T())
+	referenceRepoMock.AssertExpectations(suite.T())
 }
 
 // === Addon
 func (suite *catalogCommonService) TestService_AddonList_ShouldSuccess() {
-	repoMock := new(mocks.ICRUDRepository[model.Addon])
+	repoMock := new(mocks.ISoftDeleteRepository[model.Addon])
 	svc := service.NewCatalogCommonService(
-		new(mocks.ICRUDRepository[model.Unit]),
-		new(mocks.ICRUDRepository[model.Category]),
-		new(mocks.ICRUDRepository[model.Subcategory]), repoMock)
+		new(mocks.ISoftDeleteRepository[model.Unit]),
+		new(mocks.ISoftDeleteRepository[model.Category]),
+		new(mocks.ISoftDeleteRepository[model.Subcategory]), repoMock, new(mocks.IReferenceRepository))
 	repoMock.On("All", mock.Anything).
 		Return(suite.addons, nil).Once()
 	data, err := svc.AddonList(context.TODO())
@@ -502,11 +565,11 @@ func (suite *catalogCommonService) TestService_AddonList_ShouldSuccess() {
 	repoMock.AssertExpectations(suite.T())
 }
 func (suite *catalogCommonService) TestService_AddonList_ShouldError() {
-	repoMock := new(mocks.ICRUDRepository[model.Addon])
+	repoMock := new(mocks.ISoftDeleteRepository[model.Addon])
 	svc := service.NewCatalogCommonService(
-		new(mocks.ICRUDRepository[model.Unit]),
-		new(mocks.ICRUDRepository[model.Category]),
-		new(mocks.ICRUDRepository[model.Subcategory]), repoMock)
+		new(mocks.ISoftDeleteRepository[model.Unit]),
+		new(mocks.ISoftDeleteRepository[model.Category]),
+		new(mocks.ISoftDeleteRepository[model.Subcategory]), repoMock, new(mocks.IReferenceRepository))
 	repoMock.On("All", mock.Anything).
 		Return(nil, errors.New("UNEXPECTED")).Once()
 	data, err := svc.AddonList(context.TODO())
@@ -517,11 +580,11 @@ func (suite *catalogCommonService) TestService_AddonList_ShouldError() {
 }
 
 func (suite *catalogCommonService) TestService_AddAddon_ShouldSuccess() {
-	repoMock := new(mocks.ICRUDRepository[model.Addon])
+	repoMock := new(mocks.ISoftDeleteRepository[model.Addon])
 	svc := service.NewCatalogCommonService(
-		new(mocks.ICRUDRepository[model.Unit]),
-		new(mocks.ICRUDRepository[model.Category]),
-		new(mocks.ICRUDRepository[model.Subcategory]), repoMock)
+		new(mocks.ISoftDeleteRepository[model.Unit]),
+		new(mocks.ISoftDeleteRepository[model.Category]),
+		new(mocks.ISoftDeleteRepository[model.Subcategory]), repoMock, new(mocks.IReferenceRepository))
 	repoMock.On("Create", mock.Anything, mock.Anything).
 		Return(suite.addon, nil).Once()
 	data, err := svc.AddAddon(context.TODO(), suite.addon)
@@ -531,11 +594,11 @@ func (suite *catalogCommonService) TestService_AddAddon_ShouldSuccess() {
 	repoMock.AssertExpectations(suite.T())
 }
 func (suite *catalogCommonService) TestService_AddAddon_ShouldError() {
-	repoMock := new(mocks.ICRUDRepository[model.Addon])
+	repoMock := new(mocks.ISoftDeleteRepository[model.Addon])
 	svc := service.NewCatalogCommonService(
-		new(mocks.ICRUDRepository[model.Unit]),
-		new(mocks.ICRUDRepository[model.Category]),
-		new(mocks.ICRUDRepository[model.Subcategory]), repoMock)
+		new(mocks.ISoftDeleteRepository[model.Unit]),
+		new(mocks.ISoftDeleteRepository[model.Category]),
+		new(mocks.ISoftDeleteRepository[model.Subcategory]), repoMock, new(mocks.IReferenceRepository))
 	repoMock.On("Create", mock.Anything, mock.Anything).
 		Return(nil, errors.New("UNEXPECTED")).Once()
 	data, err := svc.AddAddon(context.TODO(), suite.addon)
@@ -546,11 +609,11 @@ func (suite *catalogCommonService) TestService_AddAddon_ShouldError() {
 }
 
 func (suite *catalogCommonService) TestService_EditAddon_ShouldSuccess() {
-	repoMock := new(mocks.ICRUDRepository[model.Addon])
+	repoMock := new(mocks.ISoftDeleteRepository[model.Addon])
 	svc := service.NewCatalogCommonService(
-		new(mocks.ICRUDRepository[model.Unit]),
-		new(mocks.ICRUDRepository[model.Category]),
-		new(mocks.ICRUDRepository[model.Subcategory]), repoMock)
+		new(mocks.ISoftDeleteRepository[model.Unit]),
+		new(mocks.ISoftDeleteRepository[model.Category]),
+		new(mocks.ISoftDeleteRepository[model.Subcategory]), repoMock, new(mocks.IReferenceRepository))
 	repoMock.On("Update", mock.Anything, mock.Anything).
 		Return(suite.addon, nil).Once()
 	data, err := svc.EditAddon(context.TODO(), suite.addon)
@@ -560,11 +623,11 @@ func (suite *catalogCommonService) TestService_EditAddon_ShouldSuccess() {
 	repoMock.AssertExpectations(suite.T())
 }
 func (suite *catalogCommonService) TestService_EditAddon_ShouldError() {
-	repoMock := new(mocks.ICRUDRepository[model.Addon])
+	repoMock := new(mocks.ISoftDeleteRepository[model.Addon])
 	svc := service.NewCatalogCommonService(
-		new(mocks.ICRUDRepository[model.Unit]),
-		new(mocks.ICRUDRepository[model.Category]),
-		new(mocks.ICRUDRepository[model.Subcategory]), repoMock)
+		new(mocks.ISoftDeleteRepository[model.Unit]),
+		new(mocks.ISoftDeleteRepository[model.Category]),
+		new(mocks.ISoftDeleteRepository[model.Subcategory]), repoMock, new(mocks.IReferenceRepository))
 	repoMock.On("Update", mock.Anything, mock.Anything).
 		Return(nil, errors.New("UNEXPECTED")).Once()
 	data, err := svc.EditAddon(context.TODO(), suite.addon)
@@ -575,27 +638,30 @@ func (suite *catalogCommonService) TestService_EditAddon_ShouldError() {
 }
 
 func (suite *catalogCommonService) TestService_DeleteAddon_ShouldSuccess() {
-	repoMock := new(mocks.ICRUDRepository[model.Addon])
+	repoMock := new(mocks.ISoftDeleteRepository[model.Addon])
+	referenceRepoMock := new(mocks.IReferenceRepository)
+	referenceRepoMock.
+		On("Delete", mock.Anything, "addons", mock.Anything, 0).
+		Once().
+		Return(nil, nil)
 	svc := service.NewCatalogCommonService(
-		new(mocks.ICRUDRepository[model.Unit]),
-		new(mocks.ICRUDRepository[model.Category]),
-		new(mocks.ICRUDRepository[model.Subcategory]), repoMock)
+		new(mocks.ISoftDeleteRepository[model.Unit]),
+		new(mocks.ISoftDeleteRepository[model.Category]),
+		new(mocks.ISoftDeleteRepository[model.Subcategory]), repoMock, referenceRepoMock)
 	repoMock.
 		On("Find", mock.Anything, mock.Anything, mock.Anything).
 		Return(suite.addons[1], nil).Once()
-	repoMock.
-		On("Delete", mock.Anything, mock.Anything).
-		Return(nil).Once()
 	err := svc.DeleteAddon(context.TODO(), suite.addon)
 	require.Nil(suite.T(), err)
 	repoMock.AssertExpectations(suite.T())
+	referenceRepoMock.AssertExpectations(suite.T())
 }
 func (suite *catalogCommonService) TestService_DeleteAddon_ShouldErrorWhenFind() {
-	repoMock := new(mocks.ICRUDRepository[model.Addon])
+	repoMock := new(mocks.ISoftDeleteRepository[model.Addon])
 	svc := service.NewCatalogCommonService(
-		new(mocks.ICRUDRepository[model.Unit]),
-		new(mocks.ICRUDRepository[model.Category]),
-		new(mocks.ICRUDRepository[model.Subcategory]), repoMock)
+		new(mocks.ISoftDeleteRepository[model.Unit]),
+		new(mocks.ISoftDeleteRepository[model.Category]),
+		new(mocks.ISoftDeleteRepository[model.Subcategory]), repoMock, new(mocks.IReferenceRepository))
 	repoMock.
 		On("Find", mock.Anything, mock.Anything, mock.Anything).
 		Once().
@@ -606,11 +672,11 @@ func (suite *catalogCommonService) TestService_DeleteAddon_ShouldErrorWhenFind()
 	repoMock.AssertExpectations(suite.T())
 }
 func (suite *catalogCommonService) TestService_DeleteAddon_ShouldErrorWhenFindNotFound() {
-	repoMock := new(mocks.ICRUDRepository[model.Addon])
+	repoMock := new(mocks.ISoftDeleteRepository[model.Addon])
 	svc := service.NewCatalogCommonService(
-		new(mocks.ICRUDRepository[model.Unit]),
-		new(mocks.ICRUDRepository[model.Category]),
-		new(mocks.ICRUDRepository[model.Subcategory]), repoMock)
+		new(mocks.ISoftDeleteRepository[model.Unit]),
+		new(mocks.ISoftDeleteRepository[model.Category]),
+		new(mocks.ISoftDeleteRepository[model.Subcategory]), repoMock, new(mocks.IReferenceRepository))
 	repoMock.
 		On("Find", mock.Anything, mock.Anything, mock.Anything).
 		Once().
@@ -621,21 +687,24 @@ func (suite *catalogCommonService) TestService_DeleteAddon_ShouldErrorWhenFindNo
 	repoMock.AssertExpectations(suite.T())
 }
 func (suite *catalogCommonService) TestService_DeleteAddon_ShouldErrorWhenDelete() {
-	repoMock := new(mocks.ICRUDRepository[model.Addon])
+	repoMock := new(mocks.ISoftDeleteRepository[model.Addon])
+	referenceRepoMock := new(mocks.IReferenceRepository)
+	referenceRepoMock.
+		On("Delete", mock.Anything, "addons", mock.Anything, 0).
+		Once().
+		Return(nil, errors.New("UNEXPECTED"))
 	svc := service.NewCatalogCommonService(
-		new(mocks.ICRUDRepository[model.Unit]),
-		new(mocks.ICRUDRepository[model.Category]),
-		new(mocks.ICRUDRepository[model.Subcategory]), repoMock)
+		new(mocks.ISoftDeleteRepository[model.Unit]),
+		new(mocks.ISoftDeleteRepository[model.Category]),
+		new(mocks.ISoftDeleteRepository[model.Subcategory]), repoMock, referenceRepoMock)
 	repoMock.
 		On("Find", mock.Anything, mock.Anything, mock.Anything).
 		Return(suite.addon, nil).Once()
-	repoMock.
-		On("Delete", mock.Anything, mock.Anything).
-		Return(errors.New("UNEXPECTED")).Once()
 	err := svc.DeleteAddon(context.TODO(), suite.addon)
 	require.NotNil(suite.T(), err)
 	require.Equal(suite.T(), err, suite.svcErr)
 	repoMock.AssertExpectations(suite.T())
+	referenceRepoMock.AssertExpectations(suite.T())
 }
 
 func TestCatalogCommonService(t *testing.T) {
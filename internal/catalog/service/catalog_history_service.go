@@ -0,0 +1,73 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/aasumitro/posbe/pkg/audit"
+	"github.com/aasumitro/posbe/pkg/model"
+	"github.com/aasumitro/posbe/pkg/utils"
+)
+
+type catalogHistoryService struct {
+	versionRepo model.IEntityVersionRepository
+}
+
+func (service catalogHistoryService) History(
+	ctx context.Context,
+	entityType, entityID string,
+) (versions []*model.EntityVersion, errData *utils.ServiceError) {
+	if errData := validateVersionedEntity(entityType); errData != nil {
+		return nil, errData
+	}
+	data, err := service.versionRepo.Versions(ctx, entityType, entityID)
+	if err == nil && len(data) == 0 {
+		err = sql.ErrNoRows
+	}
+	if err != nil {
+		return utils.ValidateDataRows[model.EntityVersion](data, err)
+	}
+	// changes of a version are made against the previous one,
+	// the first version and deletion have no changes
+	for i := 1; i < len(data); i++ {
+		data[i].Changes = audit.Diff(data[i-1].Data, data[i].Data)
+	}
+	return data, nil
+}
+
+func (service catalogHistoryService) Snapshot(
+	ctx context.Context,
+	entityType string,
+	query *model.EntityVersionQuery,
+) (versions []*model.EntityVersion, errData *utils.ServiceError) {
+	if errData := validateVersionedEntity(entityType); errData != nil {
+		return nil, errData
+	}
+	var ids []string
+	for _, id := range strings.Split(query.IDs, ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			ids = append(ids, id)
+		}
+	}
+	data, err := service.versionRepo.AsOf(ctx, entityType, ids, query.At)
+	return utils.ValidateDataRows[model.EntityVersion](data, err)
+}
+
+func validateVersionedEntity(entityType string) *utils.ServiceError {
+	if slices.Contains(model.VersionedEntities, entityType) {
+		return nil
+	}
+	return &utils.ServiceError{
+		Code: http.StatusNotFound,
+		Message: fmt.Sprintf("%s has no history, expected one of %s",
+			entityType, strings.Join(model.VersionedEntities, ", ")),
+	}
+}
+
+func NewCatalogHistoryService(versionRepo model.IEntityVersionRepository) model.ICatalogHistoryService {
+	return &catalogHistoryService{versionRepo: versionRepo}
+}
@@ -0,0 +1,108 @@
+package service_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/aasumitro/posbe/internal/catalog/service"
+	"github.com/aasumitro/posbe/mocks"
+	"github.com/aasumitro/posbe/pkg/model"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type catalogHistoryTestSuite struct {
+	suite.Suite
+	versionRepo *mocks.IEntityVersionRepository
+	svc         model.ICatalogHistoryService
+	versions    []*model.EntityVersion
+}
+
+func (suite *catalogHistoryTestSuite) SetupTest() {
+	suite.versionRepo = new(mocks.IEntityVersionRepository)
+	suite.svc = service.NewCatalogHistoryService(suite.versionRepo)
+	suite.versions = []*model.EntityVersion{
+		{ID: 1, EntityType: "products", EntityID: "1", Version: 1,
+			Data: json.RawMessage(`{"id":1,"name":"Coffee","price":1000}`)},
+		{ID: 2, EntityType: "products", EntityID: "1", Version: 2, ChangedBy: 3,
+			Data: json.RawMessage(`{"id":1,"name":"Coffee","price":2000}`)},
+		{ID: 3, EntityType: "products", EntityID: "1", Version: 3, ChangedBy: 3, Deleted: true},
+	}
+}
+
+func (suite *catalogHistoryTestSuite) TestService_History_ExpectReturnChanges() {
+	suite.versionRepo.On("Versions", mock.Anything, "products", "1").
+		Return(suite.versions, nil).Once()
+	data, err := suite.svc.History(context.TODO(), "products", "1")
+	require.Nil(suite.T(), err)
+	require.Len(suite.T(), data, 3)
+	require.Nil(suite.T(), data[0].Changes)
+	require.Equal(suite.T(), map[string]*model.AuditChange{
+		"price": {Before: float64(1000), After: float64(2000)}}, data[1].Changes)
+	require.Nil(suite.T(), data[2].Changes)
+}
+
+func (suite *catalogHistoryTestSuite) TestService_History_ExpectReturnNotFound() {
+	suite.versionRepo.On("Versions", mock.Anything, "addons", "9").
+		Return(nil, nil).Once()
+	data, err := suite.svc.History(context.TODO(), "addons", "9")
+	require.Nil(suite.T(), data)
+	require.Equal(suite.T(), http.StatusNotFound, err.Code)
+}
+
+func (suite *catalogHistoryTestSuite) TestService_History_ExpectReturnError() {
+	suite.versionRepo.On("Versions", mock.Anything, "addons", "1").
+		Return(nil, errors.New("UNEXPECTED")).Once()
+	data, err := suite.svc.History(context.TODO(), "addons", "1")
+	require.Nil(suite.T(), data)
+	require.Equal(suite.T(), http.StatusInternalServerError, err.Code)
+}
+
+func (suite *catalogHistoryTestSuite) TestService_History_ExpectReturnUnknownEntity() {
+	data, err := suite.svc.History(context.TODO(), "users", "1")
+	require.Nil(suite.T(), data)
+	require.Equal(suite.T(), http.StatusNotFound, err.Code)
+	suite.versionRepo.AssertNotCalled(suite.T(), "Versions")
+}
+
+func (suite *catalogHistoryTestSuite) TestService_Snapshot_ExpectReturnRows() {
+	suite.versionRepo.On("AsOf", mock.Anything, "products", []string{"1", "2"}, int64(150)).
+		Return(suite.versions[1:2], nil).Once()
+	data, err := suite.svc.Snapshot(context.TODO(), "products",
+		&model.EntityVersionQuery{At: 150, IDs: "1, 2,"})
+	require.Nil(suite.T(), err)
+	require.Len(suite.T(), data, 1)
+}
+
+func (suite *catalogHistoryTestSuite) TestService_Snapshot_ExpectReturnAll() {
+	suite.versionRepo.On("AsOf", mock.Anything, "store_prefs", []string(nil), int64(150)).
+		Return(suite.versions[:1], nil).Once()
+	data, err := suite.svc.Snapshot(context.TODO(), "store_prefs",
+		&model.EntityVersionQuery{At: 150})
+	require.Nil(suite.T(), err)
+	require.Len(suite.T(), data, 1)
+}
+
+func (suite *catalogHistoryTestSuite) TestService_Snapshot_ExpectReturnError() {
+	suite.versionRepo.On("AsOf", mock.Anything, "addons", []string(nil), int64(150)).
+		Return(nil, errors.New("UNEXPECTED")).Once()
+	data, err := suite.svc.Snapshot(context.TODO(), "addons",
+		&model.EntityVersionQuery{At: 150})
+	require.Nil(suite.T(), data)
+	require.Equal(suite.T(), http.StatusInternalServerError, err.Code)
+}
+
+func (suite *catalogHistoryTestSuite) TestService_Snapshot_ExpectReturnUnknownEntity() {
+	data, err := suite.svc.Snapshot(context.TODO(), "users",
+		&model.EntityVersionQuery{At: 150})
+	require.Nil(suite.T(), data)
+	require.Equal(suite.T(), http.StatusNotFound, err.Code)
+}
+
+func TestCatalogHistoryService(t *testing.T) {
+	suite.Run(t, new(catalogHistoryTestSuite))
+}
@@ -0,0 +1,470 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/aasumitro/posbe/pkg/model"
+	"github.com/aasumitro/posbe/pkg/money"
+	"github.com/aasumitro/posbe/pkg/utils"
+)
+
+type (
+	importColumn struct {
+		Key       string
+		Required  bool
+		MaxLength int
+		Number    bool
+		Money     bool
+		Positive  bool
+	}
+
+	catalogImportService struct {
+		importRepo model.ICatalogImportRepository
+	}
+
+	// catalogImport collect the plan & errors of a single import request
+	catalogImport struct {
+		snapshot *model.CatalogImportSnapshot
+		plan     *model.CatalogImportPlan
+		report   *model.CatalogImportReport
+		// keys introduced by the file itself, per entity
+		seen map[string]map[string]int
+	}
+)
+
+const (
+	importCategories    = "categories"
+	importSubcategories = "subcategories"
+	importUnits         = "units"
+	importAddons        = "addons"
+	importProducts      = "products"
+	importVariants      = "variants"
+)
+
+// catalogImportOrder is the dependency order,
+// a sheet can only refer to the sheet before it
+var catalogImportOrder = []string{
+	importCategories, importSubcategories, importUnits,
+	importAddons, importProducts, importVariants,
+}
+
+var catalogImportColumns = map[string][]importColumn{
+	importCategories: {
+		{Key: "name", Required: true, MaxLength: 50},
+	},
+	importSubcategories: {
+		{Key: "category", Required: true, MaxLength: 50},
+		{Key: "name", Required: true, MaxLength: 50},
+	},
+	importUnits: {
+		{Key: "symbol", Required: true, MaxLength: 50},
+		{Key: "name", Required: true, MaxLength: 50},
+		{Key: "magnitude", Required: true, MaxLength: 50},
+	},
+	importAddons: {
+		{Key: "name", Required: true, MaxLength: 255},
+		{Key: "description", Required: true, MaxLength: 255},
+		{Key: "price", Required: true, Money: true},
+	},
+	importProducts: {
+		{Key: "sku", Required: true, MaxLength: 255},
+		{Key: "name", Required: true, MaxLength: 255},
+		{Key: "category", Required: true, MaxLength: 50},
+		{Key: "subcategory", Required: true, MaxLength: 50},
+		{Key: "price", Required: true, Money: true},
+		{Key: "description", MaxLength: 255},
+		{Key: "image", MaxLength: 255},
+	},
+	importVariants: {
+		{Key: "product_sku", Required: true, MaxLength: 255},
+		{Key: "name", Required: true, MaxLength: 255},
+		{Key: "unit", Required: true, MaxLength: 50},
+		{Key: "unit_size", Required: true, Number: true, Positive: true},
+		{Key: "price", Required: true, Money: true},
+		{Key: "type"},
+		{Key: "description", MaxLength: 255},
+	},
+}
+
+var variantTypes = []string{"none", "size"}
+
+func (service catalogImportService) Import(
+	ctx context.Context,
+	sheets []*utils.ImportSheet,
+	dryRun bool,
+) (report *model.CatalogImportReport, errData *utils.ServiceError) {
+	if len(sheets) == 0 {
+		return nil, &utils.ServiceError{
+			Code:    http.StatusBadRequest,
+			Message: "import file has no data",
+		}
+	}
+	snapshot, err := service.importRepo.Snapshot(ctx)
+	if err != nil {
+		return nil, &utils.ServiceError{
+			Code:    http.StatusInternalServerError,
+			Message: err.Error(),
+		}
+	}
+	imp := newCatalogImport(snapshot, dryRun)
+	imp.validate(sheets)
+	if len(imp.report.Errors) > 0 {
+		if dryRun {
+			return imp.report, nil
+		}
+		return nil, &utils.ServiceError{
+			Code:    http.StatusUnprocessableEntity,
+			Message: imp.report,
+		}
+	}
+	if dryRun {
+		return imp.report, nil
+	}
+	if err := service.importRepo.Apply(ctx, imp.plan); err != nil {
+		return nil, &utils.ServiceError{
+			Code:    http.StatusInternalServerError,
+			Message: err.Error(),
+		}
+	}
+	imp.report.Applied = true
+	return imp.report, nil
+}
+
+func newCatalogImport(
+	snapshot *model.CatalogImportSnapshot,
+	dryRun bool,
+) *catalogImport {
+	imp := &catalogImport{
+		snapshot: snapshot,
+		plan:     &model.CatalogImportPlan{},
+		report: &model.CatalogImportReport{
+			DryRun:  dryRun,
+			Summary: make(map[string]*model.CatalogImportCount),
+			Errors:  []*model.CatalogImportError{},
+		},
+		seen: make(map[string]map[string]int),
+	}
+	for _, entity := range catalogImportOrder {
+		imp.seen[entity] = make(map[string]int)
+	}
+	return imp
+}
+
+func (imp *catalogImport) validate(sheets []*utils.ImportSheet) {
+	byName := make(map[string]*utils.ImportSheet)
+	for _, sheet := range sheets {
+		if !slices.Contains(catalogImportOrder, sheet.Name) {
+			imp.fail(sheet.Name, 0, "", fmt.Sprintf(
+				"unknown entity, use one of %s", strings.Join(catalogImportOrder, ", ")))
+			continue
+		}
+		byName[sheet.Name] = sheet
+	}
+	for _, entity := range catalogImportOrder {
+		sheet, ok := byName[entity]
+		if !ok || !imp.checkHeaders(sheet) {
+			continue
+		}
+		imp.report.Summary[entity] = &model.CatalogImportCount{}
+		for _, row := range sheet.Rows {
+			if !imp.checkRow(entity, row) {
+				continue
+			}
+			switch entity {
+			case importCategories:
+				imp.category(row)
+			case importSubcategories:
+				imp.subcategory(row)
+			case importUnits:
+				imp.unit(row)
+			case importAddons:
+				imp.addon(row)
+			case importProducts:
+				imp.product(row)
+			case importVariants:
+				imp.variant(row)
+			}
+		}
+	}
+}
+
+func (imp *catalogImport) checkHeaders(sheet *utils.ImportSheet) bool {
+	valid := true
+	for _, column := range catalogImportColumns[sheet.Name] {
+		if column.Required && !sheet.HasHeader(column.Key) {
+			imp.fail(sheet.Name, 1, column.Key, "missing column")
+			valid = false
+		}
+	}
+	return valid
+}
+
+func (imp *catalogImport) checkRow(entity string, row *utils.ImportRow) bool {
+	valid := true
+	for _, column := range catalogImportColumns[entity] {
+		value := row.Get(column.Key)
+		if column.Required && value == "" {
+			imp.fail(entity, row.Line, column.Key, "value is required")
+			valid = false
+		}
+		if column.MaxLength > 0 && len(value) > column.MaxLength {
+			imp.fail(entity, row.Line, column.Key,
+				fmt.Sprintf("value is longer than %d characters", column.MaxLength))
+			valid = false
+		}
+		if column.Number && value != "" {
+			if message := checkNumber(value, column.Positive); message != "" {
+				imp.fail(entity, row.Line, column.Key, message)
+				valid = false
+			}
+		}
+		if column.Money && value != "" {
+			if message := checkMoney(value); message != "" {
+				imp.fail(entity, row.Line, column.Key, message)
+				valid = false
+			}
+		}
+	}
+	return valid
+}
+
+func checkNumber(value string, positive bool) string {
+	number, err := strconv.ParseFloat(value, 32)
+	switch {
+	case err != nil:
+		return "value must be a number"
+	case positive && number <= 0:
+		return "value must be greater than 0"
+	case number < 0:
+		return "value must not be negative"
+	}
+	return ""
+}
+
+func checkMoney(value string) string {
+	amount, err := money.Parse(value)
+	switch {
+	case errors.Is(err, money.ErrorTooManyDecimals):
+		return fmt.Sprintf("value must not have more than %d decimals",
+			money.StoreCurrency().Exponent)
+	case err != nil:
+		return "value must be a number"
+	case amount < 0:
+		return "value must not be negative"
+	}
+	return ""
+}
+
+func (imp *catalogImport) category(row *utils.ImportRow) {
+	name := row.Get("name")
+	key := model.CatalogImportKey(name)
+	if !imp.unique(importCategories, key, row) {
+		return
+	}
+	if _, ok := imp.snapshot.Categories[key]; ok {
+		imp.report.Summary[importCategories].Skipped++
+		return
+	}
+	imp.report.Summary[importCategories].Created++
+	imp.plan.Categories = append(imp.plan.Categories, &model.Category{Name: name})
+}
+
+func (imp *catalogImport) subcategory(row *utils.ImportRow) {
+	category, name := row.Get("category"), row.Get("name")
+	if !imp.exists(importCategories, model.CatalogImportKey(category)) {
+		imp.fail(importSubcategories, row.Line, "category",
+			fmt.Sprintf("category %s not found", category))
+		return
+	}
+	key := model.CatalogImportKey(category, name)
+	if !imp.unique(importSubcategories, key, row) {
+		return
+	}
+	if _, ok := imp.snapshot.Subcategories[key]; ok {
+		imp.report.Summary[importSubcategories].Skipped++
+		return
+	}
+	imp.report.Summary[importSubcategories].Created++
+	imp.plan.Subcategories = append(imp.plan.Subcategories, &model.CatalogImportSubcategory{
+		Subcategory: model.Subcategory{Name: name},
+		Category:    category,
+	})
+}
+
+func (imp *catalogImport) unit(row *utils.ImportRow) {
+	item := &model.Unit{
+		Magnitude: row.Get("magnitude"),
+		Name:      row.Get("name"),
+		Symbol:    row.Get("symbol"),
+	}
+	key := model.CatalogImportKey(item.Symbol)
+	if !imp.unique(importUnits, key, row) {
+		return
+	}
+	imp.count(importUnits, imp.snapshot.Units, key)
+	imp.plan.Units = append(imp.plan.Units, item)
+}
+
+func (imp *catalogImport) addon(row *utils.ImportRow) {
+	item := &model.Addon{
+		Name:        row.Get("name"),
+		Description: row.Get("description"),
+		Price:       amount(row, "price"),
+	}
+	key := model.CatalogImportKey(item.Name)
+	if !imp.unique(importAddons, key, row) {
+		return
+	}
+	imp.count(importAddons, imp.snapshot.Addons, key)
+	imp.plan.Addons = append(imp.plan.Addons, item)
+}
+
+func (imp *catalogImport) product(row *utils.ImportRow) {
+	category, subcategory := row.Get("category"), row.Get("subcategory")
+	valid := true
+	if !imp.exists(importCategories, model.CatalogImportKey(category)) {
+		imp.fail(importProducts, row.Line, "category",
+			fmt.Sprintf("category %s not found", category))
+		valid = false
+	} else if !imp.exists(importSubcategories, model.CatalogImportKey(category, subcategory)) {
+		imp.fail(importProducts, row.Line, "subcategory",
+			fmt.Sprintf("subcategory %s not found in %s", subcategory, category))
+		valid = false
+	}
+	if !valid {
+		return
+	}
+	item := &model.CatalogImportProduct{
+		Product: model.Product{
+			Sku:         row.Get("sku"),
+			Name:        row.Get("name"),
+			Price:       amount(row, "price"),
+			Image:       nullString(row.Get("image")),
+			Description: nullString(row.Get("description")),
+		},
+		Category:    category,
+		Subcategory: subcategory,
+	}
+	if !imp.unique(importProducts, item.Sku, row) {
+		return
+	}
+	imp.count(importProducts, imp.snapshot.Products, item.Sku)
+	imp.plan.Products = append(imp.plan.Products, item)
+}
+
+func (imp *catalogImport) variant(row *utils.ImportRow) {
+	sku, unit := row.Get("product_sku"), row.Get("unit")
+	valid := true
+	if !imp.exists(importProducts, sku) {
+		imp.fail(importVariants, row.Line, "product_sku",
+			fmt.Sprintf("product %s not found", sku))
+		valid = false
+	}
+	if !imp.exists(importUnits, model.CatalogImportKey(unit)) {
+		imp.fail(importVariants, row.Line, "unit",
+			fmt.Sprintf("unit %s not found", unit))
+		valid = false
+	}
+	variantType := strings.ToLower(row.Get("type"))
+	if variantType == "" {
+		variantType = variantTypes[0]
+	}
+	if !slices.Contains(variantTypes, variantType) {
+		imp.fail(importVariants, row.Line, "type", fmt.Sprintf(
+			"type must be one of %s", strings.Join(variantTypes, ", ")))
+		valid = false
+	}
+	if !valid {
+		return
+	}
+	item := &model.CatalogImportVariant{
+		ProductVariant: model.ProductVariant{
+			UnitSize:    number(row, "unit_size"),
+			Type:        variantType,
+			Name:        row.Get("name"),
+			Description: nullString(row.Get("description")),
+			Price:       amount(row, "price"),
+		},
+		ProductSku: sku,
+		UnitSymbol: unit,
+	}
+	key := sku + "/" + model.CatalogImportKey(item.Name)
+	if !imp.unique(importVariants, key, row) {
+		return
+	}
+	imp.count(importVariants, imp.snapshot.Variants, key)
+	imp.plan.Variants = append(imp.plan.Variants, item)
+}
+
+// exists check the reference is already stored or part of the import
+func (imp *catalogImport) exists(entity, key string) bool {
+	if _, ok := imp.seen[entity][key]; ok {
+		return true
+	}
+	var stored map[string]int
+	switch entity {
+	case importCategories:
+		stored = imp.snapshot.Categories
+	case importSubcategories:
+		stored = imp.snapshot.Subcategories
+	case importUnits:
+		stored = imp.snapshot.Units
+	case importProducts:
+		stored = imp.snapshot.Products
+	}
+	_, ok := stored[key]
+	return ok
+}
+
+// unique make sure the same record is not written twice by one file
+func (imp *catalogImport) unique(entity, key string, row *utils.ImportRow) bool {
+	if line, ok := imp.seen[entity][key]; ok {
+		imp.fail(entity, row.Line, "", fmt.Sprintf("duplicate of line %d", line))
+		return false
+	}
+	imp.seen[entity][key] = row.Line
+	return true
+}
+
+func (imp *catalogImport) count(entity string, stored map[string]int, key string) {
+	if _, ok := stored[key]; ok {
+		imp.report.Summary[entity].Updated++
+		return
+	}
+	imp.report.Summary[entity].Created++
+}
+
+// amount parse money value already validated by checkRow
+func amount(row *utils.ImportRow, field string) money.Amount {
+	value, _ := money.Parse(row.Get(field))
+	return value
+}
+
+// number parse value already validated by checkRow
+func number(row *utils.ImportRow, field string) float32 {
+	value, _ := strconv.ParseFloat(row.Get(field), 32)
+	return float32(value)
+}
+
+func (imp *catalogImport) fail(entity string, line int, field, message string) {
+	imp.report.Errors = append(imp.report.Errors, &model.CatalogImportError{
+		Entity: entity, Line: line, Field: field, Message: message,
+	})
+}
+
+func nullString(value string) sql.NullString {
+	return sql.NullString{String: value, Valid: value != ""}
+}
+
+func NewCatalogImportService(
+	importRepo model.ICatalogImportRepository,
+) model.ICatalogImportService {
+	return &catalogImportService{importRepo: importRepo}
+}
@@ -0,0 +1,182 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/aasumitro/posbe/internal/catalog/service"
+	"github.com/aasumitro/posbe/mocks"
+	"github.com/aasumitro/posbe/pkg/model"
+	"github.com/aasumitro/posbe/pkg/utils"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type catalogImportTestSuite struct {
+	suite.Suite
+}
+
+func importSnapshot() *model.CatalogImportSnapshot {
+	return &model.CatalogImportSnapshot{
+		Categories:    map[string]int{"drinks": 1},
+		Subcategories: map[string]int{"drinks/coffee": 1},
+		Units:         map[string]int{"ml": 1},
+		Addons:        map[string]int{"sugar": 1},
+		Products:      map[string]int{"CF-01": 1},
+		Variants:      map[string]int{"CF-01/regular": 1},
+	}
+}
+
+func importSheet(name string, headers []string, rows ...[]string) *utils.ImportSheet {
+	sheet := &utils.ImportSheet{Name: name, Headers: headers}
+	for i, row := range rows {
+		values := make(map[string]string)
+		for j, value := range row {
+			values[headers[j]] = value
+		}
+		sheet.Rows = append(sheet.Rows, &utils.ImportRow{Line: i + 2, Values: values})
+	}
+	return sheet
+}
+
+func validImportSheets() []*utils.ImportSheet {
+	// workbook order does not matter
+	return []*utils.ImportSheet{
+		importSheet("variants", []string{"product_sku", "name", "unit", "unit_size", "price", "type"},
+			[]string{"CF-01", "Regular", "ml", "250", "20000", ""},
+			[]string{"FR-01", "Large", "gr", "400", "30000", "size"}),
+		importSheet("products", []string{"sku", "name", "category", "subcategory", "price"},
+			[]string{"CF-01", "Latte", "drinks", "coffee", "20000"},
+			[]string{"FR-01", "Fried Rice", "Foods", "Rice", "25000"}),
+		importSheet("categories", []string{"name"},
+			[]string{"Drinks"}, []string{"Foods"}),
+		importSheet("subcategories", []string{"category", "name"},
+			[]string{"Foods", "Rice"}),
+		importSheet("units", []string{"symbol", "name", "magnitude"},
+			[]string{"gr", "gram", "mass"}),
+		importSheet("addons", []string{"name", "description", "price"},
+			[]string{"Sugar", "brown sugar", "1000"}),
+	}
+}
+
+func (suite *catalogImportTestSuite) TestCatalogImportService_Import_ShouldDryRun() {
+	repo := new(mocks.ICatalogImportRepository)
+	svc := service.NewCatalogImportService(repo)
+	repo.On("Snapshot", mock.Anything).Once().Return(importSnapshot(), nil)
+	data, err := svc.Import(context.TODO(), validImportSheets(), true)
+	require.Nil(suite.T(), err)
+	require.Empty(suite.T(), data.Errors)
+	require.True(suite.T(), data.DryRun)
+	require.False(suite.T(), data.Applied)
+	require.Equal(suite.T(), &model.CatalogImportCount{Created: 1, Skipped: 1}, data.Summary["categories"])
+	require.Equal(suite.T(), &model.CatalogImportCount{Created: 1, Updated: 1}, data.Summary["products"])
+	require.Equal(suite.T(), &model.CatalogImportCount{Created: 1, Updated: 1}, data.Summary["variants"])
+	require.Equal(suite.T(), &model.CatalogImportCount{Updated: 1}, data.Summary["addons"])
+	repo.AssertExpectations(suite.T())
+}
+
+func (suite *catalogImportTestSuite) TestCatalogImportService_Import_ShouldApply() {
+	repo := new(mocks.ICatalogImportRepository)
+	svc := service.NewCatalogImportService(repo)
+	repo.On("Snapshot", mock.Anything).Once().Return(importSnapshot(), nil)
+	repo.
+		On("Apply", mock.Anything, mock.MatchedBy(func(plan *model.CatalogImportPlan) bool {
+			return len(plan.Categories) == 1 && len(plan.Products) == 2 &&
+				plan.Variants[0].Type == "none" && plan.Variants[1].UnitSymbol == "gr"
+		})).
+		Once().
+		Return(nil)
+	data, err := svc.Import(context.TODO(), validImportSheets(), false)
+	require.Nil(suite.T(), err)
+	require.True(suite.T(), data.Applied)
+	repo.AssertExpectations(suite.T())
+}
+
+func (suite *catalogImportTestSuite) TestCatalogImportService_Import_ShouldReportRowErrors() {
+	repo := new(mocks.ICatalogImportRepository)
+	svc := service.NewCatalogImportService(repo)
+	repo.On("Snapshot", mock.Anything).Once().Return(importSnapshot(), nil)
+	sheets := []*utils.ImportSheet{
+		importSheet("products", []string{"sku", "name", "category", "subcategory", "price"},
+			[]string{"TS-01", "Tea", "Drinks", "Tea", "5000"},
+			[]string{"TS-02", "", "Snacks", "Chips", "abc"},
+			[]string{"CF-02", "Mocha", "Drinks", "Coffee", "22000"},
+			[]string{"CF-02", "Mocha", "Drinks", "Coffee", "22000"}),
+		importSheet("variants", []string{"product_sku", "name", "unit", "unit_size", "price", "type"},
+			[]string{"TS-01", "Hot", "cup", "1", "1000", "color"},
+			[]string{"CF-01", "Big", "ml", "0", "1000", ""}),
+		importSheet("units", []string{"symbol", "name"}),
+		importSheet("orders", []string{"id"}),
+	}
+	data, err := svc.Import(context.TODO(), sheets, true)
+	require.Nil(suite.T(), err)
+	lines := make(map[string][]int)
+	for _, e := range data.Errors {
+		lines[e.Entity+"."+e.Field] = append(lines[e.Entity+"."+e.Field], e.Line)
+	}
+	require.Equal(suite.T(), []int{0}, lines["orders."])
+	require.Equal(suite.T(), []int{1}, lines["units.magnitude"])
+	require.Equal(suite.T(), []int{2}, lines["products.subcategory"])
+	require.Equal(suite.T(), []int{3}, lines["products.price"])
+	require.Equal(suite.T(), []int{3}, lines["products.name"])
+	require.Equal(suite.T(), []int{5}, lines["products."])
+	require.Equal(suite.T(), []int{2}, lines["variants.product_sku"])
+	require.Equal(suite.T(), []int{2}, lines["variants.unit"])
+	require.Equal(suite.T(), []int{2}, lines["variants.type"])
+	require.Equal(suite.T(), []int{3}, lines["variants.unit_size"])
+	repo.AssertExpectations(suite.T())
+}
+
+func (suite *catalogImportTestSuite) TestCatalogImportService_Import_ShouldRejectInvalidFile() {
+	repo := new(mocks.ICatalogImportRepository)
+	svc := service.NewCatalogImportService(repo)
+	repo.On("Snapshot", mock.Anything).Once().Return(importSnapshot(), nil)
+	data, err := svc.Import(context.TODO(), []*utils.ImportSheet{
+		importSheet("categories", []string{"name"}, []string{"Drinks"}, []string{"drinks "}),
+	}, false)
+	require.Nil(suite.T(), data)
+	require.Equal(suite.T(), http.StatusUnprocessableEntity, err.Code)
+	report := err.Message.(*model.CatalogImportReport)
+	require.Len(suite.T(), report.Errors, 1)
+	require.Equal(suite.T(), "duplicate of line 2", report.Errors[0].Message)
+	repo.AssertExpectations(suite.T())
+}
+
+func (suite *catalogImportTestSuite) TestCatalogImportService_Import_ShouldErrorEmptyFile() {
+	repo := new(mocks.ICatalogImportRepository)
+	svc := service.NewCatalogImportService(repo)
+	data, err := svc.Import(context.TODO(), nil, true)
+	require.Nil(suite.T(), data)
+	require.Equal(suite.T(), http.StatusBadRequest, err.Code)
+}
+
+func (suite *catalogImportTestSuite) TestCatalogImportService_Import_ShouldErrorWhenSnapshot() {
+	repo := new(mocks.ICatalogImportRepository)
+	svc := service.NewCatalogImportService(repo)
+	repo.On("Snapshot", mock.Anything).Once().Return(nil, errors.New("UNEXPECTED"))
+	data, err := svc.Import(context.TODO(), validImportSheets(), true)
+	require.Nil(suite.T(), data)
+	require.Equal(suite.T(), &utils.ServiceError{
+		Code:    http.StatusInternalServerError,
+		Message: "UNEXPECTED",
+	}, err)
+	repo.AssertExpectations(suite.T())
+}
+
+func (suite *catalogImportTestSuite) TestCatalogImportService_Import_ShouldErrorWhenApply() {
+	repo := new(mocks.ICatalogImportRepository)
+	svc := service.NewCatalogImportService(repo)
+	repo.On("Snapshot", mock.Anything).Once().Return(importSnapshot(), nil)
+	repo.On("Apply", mock.Anything, mock.Anything).Once().Return(errors.New("UNEXPECTED"))
+	data, err := svc.Import(context.TODO(), validImportSheets(), false)
+	require.Nil(suite.T(), data)
+	require.Equal(suite.T(), http.StatusInternalServerError, err.Code)
+	repo.AssertExpectations(suite.T())
+}
+
+func TestCatalogImportService(t *testing.T) {
+	suite.Run(t, new(catalogImportTestSuite))
+}
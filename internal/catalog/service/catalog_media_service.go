@@ -0,0 +1,153 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/aasumitro/posbe/pkg/model"
+	"github.com/aasumitro/posbe/pkg/storage"
+	"github.com/aasumitro/posbe/pkg/utils"
+)
+
+const maxGalleryImages = 10
+
+type catalogMediaService struct {
+	productRepo model.ICRUDWithSearchRepository[model.Product]
+	storage     storage.Storage
+}
+
+func (service catalogMediaService) AddProductImage(
+	ctx context.Context,
+	productID int,
+	upload *utils.UploadImage,
+	cover bool,
+) (product *model.Product, errData *utils.ServiceError) {
+	data, err := service.productRepo.Find(ctx, model.FindWithID, productID)
+	if err != nil {
+		return utils.ValidateDataRow[model.Product](data, err)
+	}
+	if len(data.Gallery) >= maxGalleryImages {
+		return nil, &utils.ServiceError{
+			Code:    http.StatusBadRequest,
+			Message: fmt.Sprintf("product can only have %d images", maxGalleryImages),
+		}
+	}
+	image, err := service.store(ctx, productID, upload)
+	if err != nil {
+		return nil, &utils.ServiceError{
+			Code:    http.StatusInternalServerError,
+			Message: err.Error(),
+		}
+	}
+	data.Gallery = append(data.Gallery, image)
+	// first image is always the cover
+	if cover || len(data.Gallery) == 1 {
+		setCover(data, image)
+	}
+	updated, err := service.productRepo.Update(ctx, data)
+	if err != nil {
+		// stored files is useless without the product row
+		service.remove(ctx, image)
+	}
+	return utils.ValidateDataRow[model.Product](updated, err)
+}
+
+func (service catalogMediaService) DeleteProductImage(
+	ctx context.Context,
+	productID int,
+	imageID string,
+) (product *model.Product, errData *utils.ServiceError) {
+	data, err := service.productRepo.Find(ctx, model.FindWithID, productID)
+	if err != nil {
+		return utils.ValidateDataRow[model.Product](data, err)
+	}
+	var removed *model.ProductImage
+	gallery := model.ProductGallery{}
+	for _, image := range data.Gallery {
+		if image.ID == imageID {
+			removed = image
+			continue
+		}
+		gallery = append(gallery, image)
+	}
+	if removed == nil {
+		return nil, &utils.ServiceError{
+			Code:    http.StatusNotFound,
+			Message: fmt.Sprintf("image %s not found", imageID),
+		}
+	}
+	data.Gallery = gallery
+	if removed.Cover {
+		data.Image = sql.NullString{}
+		if len(gallery) > 0 {
+			setCover(data, gallery[0])
+		}
+	}
+	updated, err := service.productRepo.Update(ctx, data)
+	if err == nil {
+		service.remove(ctx, removed)
+	}
+	return utils.ValidateDataRow[model.Product](updated, err)
+}
+
+func (service catalogMediaService) store(
+	ctx context.Context,
+	productID int,
+	upload *utils.UploadImage,
+) (*model.ProductImage, error) {
+	id, err := utils.RandomHex(8)
+	if err != nil {
+		return nil, err
+	}
+	key := fmt.Sprintf("products/%d/%s.%s", productID, id, upload.Ext)
+	original, err := service.storage.Put(ctx, key, bytes.NewReader(upload.Data),
+		int64(len(upload.Data)), upload.ContentType)
+	if err != nil {
+		return nil, err
+	}
+	key = fmt.Sprintf("products/%d/%s_thumb.%s", productID, id, upload.ThumbnailExt)
+	thumbnail, err := service.storage.Put(ctx, key, bytes.NewReader(upload.Thumbnail),
+		int64(len(upload.Thumbnail)), upload.ThumbnailType)
+	if err != nil {
+		_ = service.storage.Delete(ctx, original.Key)
+		return nil, err
+	}
+	return &model.ProductImage{
+		ID: id, URL: original.URL, ThumbnailURL: thumbnail.URL,
+		Key: original.Key, ThumbnailKey: thumbnail.Key,
+		Width: upload.Width, Height: upload.Height,
+	}, nil
+}
+
+// remove is best effort, orphan file is better than failed request
+func (service catalogMediaService) remove(ctx context.Context, image *model.ProductImage) {
+	for _, key := range []string{image.Key, image.ThumbnailKey} {
+		if key == "" {
+			continue
+		}
+		if err := service.storage.Delete(ctx, key); err != nil {
+			log.Printf("STORAGE_ERROR: %s\n", err.Error())
+		}
+	}
+}
+
+func setCover(product *model.Product, cover *model.ProductImage) {
+	for _, image := range product.Gallery {
+		image.Cover = image == cover
+	}
+	product.Image = sql.NullString{String: cover.URL, Valid: true}
+}
+
+func NewCatalogMediaService(
+	productRepo model.ICRUDWithSearchRepository[model.Product],
+	fileStorage storage.Storage,
+) model.ICatalogMediaService {
+	return &catalogMediaService{
+		productRepo: productRepo,
+		storage:     fileStorage,
+	}
+}
@@ -0,0 +1,205 @@
+package service_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/aasumitro/posbe/internal/catalog/service"
+	"github.com/aasumitro/posbe/mocks"
+	"github.com/aasumitro/posbe/pkg/model"
+	"github.com/aasumitro/posbe/pkg/storage"
+	"github.com/aasumitro/posbe/pkg/utils"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type catalogMediaTestSuite struct {
+	suite.Suite
+	upload *utils.UploadImage
+}
+
+func (suite *catalogMediaTestSuite) SetupSuite() {
+	suite.upload = &utils.UploadImage{
+		Data: []byte("original"), ContentType: "image/png", Ext: "png",
+		Width: 640, Height: 480, Thumbnail: []byte("thumb"),
+		ThumbnailType: "image/png", ThumbnailExt: "png",
+	}
+}
+
+func storagePut(store *mocks.Storage) {
+	store.
+		On("Put", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Return(func(_ context.Context, key string, _ io.Reader, size int64, contentType string) *storage.Object {
+			return &storage.Object{Key: key, URL: "/uploads/" + key, Size: size, ContentType: contentType}
+		}, nil)
+}
+
+func (suite *catalogMediaTestSuite) TestCatalogMediaService_AddProductImage_ShouldSetCover() {
+	repo := new(mocks.ICRUDWithSearchRepository[model.Product])
+	store := new(mocks.Storage)
+	svc := service.NewCatalogMediaService(repo, store)
+	repo.On("Find", mock.Anything, model.FindWithID, 1).
+		Once().
+		Return(&model.Product{ID: 1, Gallery: model.ProductGallery{}}, nil)
+	storagePut(store)
+	repo.On("Update", mock.Anything, mock.Anything).
+		Once().
+		Return(func(_ context.Context, p *model.Product) *model.Product { return p }, nil)
+	data, err := svc.AddProductImage(context.TODO(), 1, suite.upload, false)
+	require.Nil(suite.T(), err)
+	require.Len(suite.T(), data.Gallery, 1)
+	image := data.Gallery[0]
+	require.True(suite.T(), image.Cover)
+	require.True(suite.T(), strings.HasPrefix(image.Key, "products/1/"))
+	require.True(suite.T(), strings.HasSuffix(image.ThumbnailKey, "_thumb.png"))
+	require.Equal(suite.T(), sql.NullString{String: image.URL, Valid: true}, data.Image)
+	require.Equal(suite.T(), 640, image.Width)
+	repo.AssertExpectations(suite.T())
+	store.AssertNumberOfCalls(suite.T(), "Put", 2)
+}
+
+func (suite *catalogMediaTestSuite) TestCatalogMediaService_AddProductImage_ShouldKeepCover() {
+	repo := new(mocks.ICRUDWithSearchRepository[model.Product])
+	store := new(mocks.Storage)
+	svc := service.NewCatalogMediaService(repo, store)
+	cover := &model.ProductImage{ID: "a", URL: "/uploads/a.png", Cover: true}
+	repo.On("Find", mock.Anything, model.FindWithID, 1).
+		Once().
+		Return(&model.Product{ID: 1, Image: sql.NullString{String: cover.URL, Valid: true},
+			Gallery: model.ProductGallery{cover}}, nil)
+	storagePut(store)
+	repo.On("Update", mock.Anything, mock.Anything).
+		Once().
+		Return(func(_ context.Context, p *model.Product) *model.Product { return p }, nil)
+	data, err := svc.AddProductImage(context.TODO(), 1, suite.upload, false)
+	require.Nil(suite.T(), err)
+	require.Len(suite.T(), data.Gallery, 2)
+	require.True(suite.T(), data.Gallery[0].Cover)
+	require.False(suite.T(), data.Gallery[1].Cover)
+	require.Equal(suite.T(), cover.URL, data.Image.String)
+}
+
+func (suite *catalogMediaTestSuite) TestCatalogMediaService_AddProductImage_ShouldErrorWhenFull() {
+	repo := new(mocks.ICRUDWithSearchRepository[model.Product])
+	svc := service.NewCatalogMediaService(repo, new(mocks.Storage))
+	gallery := model.ProductGallery{}
+	for i := 0; i < 10; i++ {
+		gallery = append(gallery, &model.ProductImage{})
+	}
+	repo.On("Find", mock.Anything, model.FindWithID, 1).
+		Once().
+		Return(&model.Product{ID: 1, Gallery: gallery}, nil)
+	data, err := svc.AddProductImage(context.TODO(), 1, suite.upload, false)
+	require.Nil(suite.T(), data)
+	require.Equal(suite.T(), http.StatusBadRequest, err.Code)
+}
+
+func (suite *catalogMediaTestSuite) TestCatalogMediaService_AddProductImage_ShouldErrorNotFound() {
+	repo := new(mocks.ICRUDWithSearchRepository[model.Product])
+	svc := service.NewCatalogMediaService(repo, new(mocks.Storage))
+	repo.On("Find", mock.Anything, model.FindWithID, 1).
+		Once().
+		Return(nil, sql.ErrNoRows)
+	data, err := svc.AddProductImage(context.TODO(), 1, suite.upload, false)
+	require.Nil(suite.T(), data)
+	require.Equal(suite.T(), http.StatusNotFound, err.Code)
+}
+
+func (suite *catalogMediaTestSuite) TestCatalogMediaService_AddProductImage_ShouldCleanupWhenUpdateFail() {
+	repo := new(mocks.ICRUDWithSearchRepository[model.Product])
+	store := new(mocks.Storage)
+	svc := service.NewCatalogMediaService(repo, store)
+	repo.On("Find", mock.Anything, model.FindWithID, 1).
+		Once().
+		Return(&model.Product{ID: 1}, nil)
+	storagePut(store)
+	store.On("Delete", mock.Anything, mock.Anything).Twice().Return(nil)
+	repo.On("Update", mock.Anything, mock.Anything).
+		Once().
+		Return(nil, errors.New("UNEXPECTED"))
+	data, err := svc.AddProductImage(context.TODO(), 1, suite.upload, true)
+	require.Nil(suite.T(), data)
+	require.Equal(suite.T(), http.StatusInternalServerError, err.Code)
+	store.AssertExpectations(suite.T())
+}
+
+func (suite *catalogMediaTestSuite) TestCatalogMediaService_AddProductImage_ShouldErrorWhenStorageFail() {
+	repo := new(mocks.ICRUDWithSearchRepository[model.Product])
+	store := new(mocks.Storage)
+	svc := service.NewCatalogMediaService(repo, store)
+	repo.On("Find", mock.Anything, model.FindWithID, 1).
+		Once().
+		Return(&model.Product{ID: 1}, nil)
+	store.On("Put", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Once().
+		Return(nil, errors.New("UNEXPECTED"))
+	data, err := svc.AddProductImage(context.TODO(), 1, suite.upload, true)
+	require.Nil(suite.T(), data)
+	require.Equal(suite.T(), &utils.ServiceError{
+		Code:    http.StatusInternalServerError,
+		Message: "UNEXPECTED",
+	}, err)
+}
+
+func (suite *catalogMediaTestSuite) TestCatalogMediaService_DeleteProductImage_ShouldMoveCover() {
+	repo := new(mocks.ICRUDWithSearchRepository[model.Product])
+	store := new(mocks.Storage)
+	svc := service.NewCatalogMediaService(repo, store)
+	repo.On("Find", mock.Anything, model.FindWithID, 1).
+		Once().
+		Return(&model.Product{ID: 1, Image: sql.NullString{String: "/a.png", Valid: true},
+			Gallery: model.ProductGallery{
+				{ID: "a", URL: "/a.png", Key: "a.png", ThumbnailKey: "a_thumb.png", Cover: true},
+				{ID: "b", URL: "/b.png", Key: "b.png", ThumbnailKey: "b_thumb.png"},
+			}}, nil)
+	repo.On("Update", mock.Anything, mock.Anything).
+		Once().
+		Return(func(_ context.Context, p *model.Product) *model.Product { return p }, nil)
+	store.On("Delete", mock.Anything, "a.png").Once().Return(nil)
+	store.On("Delete", mock.Anything, "a_thumb.png").Once().Return(errors.New("UNEXPECTED"))
+	data, err := svc.DeleteProductImage(context.TODO(), 1, "a")
+	require.Nil(suite.T(), err)
+	require.Len(suite.T(), data.Gallery, 1)
+	require.True(suite.T(), data.Gallery[0].Cover)
+	require.Equal(suite.T(), "/b.png", data.Image.String)
+	store.AssertExpectations(suite.T())
+}
+
+func (suite *catalogMediaTestSuite) TestCatalogMediaService_DeleteProductImage_ShouldClearCover() {
+	repo := new(mocks.ICRUDWithSearchRepository[model.Product])
+	store := new(mocks.Storage)
+	svc := service.NewCatalogMediaService(repo, store)
+	repo.On("Find", mock.Anything, model.FindWithID, 1).
+		Once().
+		Return(&model.Product{ID: 1, Image: sql.NullString{String: "/a.png", Valid: true},
+			Gallery: model.ProductGallery{{ID: "a", URL: "/a.png", Key: "a.png", Cover: true}}}, nil)
+	repo.On("Update", mock.Anything, mock.Anything).
+		Once().
+		Return(func(_ context.Context, p *model.Product) *model.Product { return p }, nil)
+	store.On("Delete", mock.Anything, "a.png").Once().Return(nil)
+	data, err := svc.DeleteProductImage(context.TODO(), 1, "a")
+	require.Nil(suite.T(), err)
+	require.Empty(suite.T(), data.Gallery)
+	require.False(suite.T(), data.Image.Valid)
+}
+
+func (suite *catalogMediaTestSuite) TestCatalogMediaService_DeleteProductImage_ShouldErrorNotFound() {
+	repo := new(mocks.ICRUDWithSearchRepository[model.Product])
+	svc := service.NewCatalogMediaService(repo, new(mocks.Storage))
+	repo.On("Find", mock.Anything, model.FindWithID, 1).
+		Once().
+		Return(&model.Product{ID: 1}, nil)
+	data, err := svc.DeleteProductImage(context.TODO(), 1, "lorem")
+	require.Nil(suite.T(), data)
+	require.Equal(suite.T(), http.StatusNotFound, err.Code)
+}
+
+func TestCatalogMediaService(t *testing.T) {
+	suite.Run(t, new(catalogMediaTestSuite))
+}
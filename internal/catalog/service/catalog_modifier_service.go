@@ -0,0 +1,262 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/aasumitro/posbe/pkg/model"
+	"github.com/aasumitro/posbe/pkg/utils"
+)
+
+type catalogModifierService struct {
+	addonGroupRepo model.IAddonGroupRepository
+	addonRepo      model.ICRUDRepository[model.Addon]
+}
+
+func (service catalogModifierService) AddonGroupList(
+	ctx context.Context,
+) (groups []*model.AddonGroup, errData *utils.ServiceError) {
+	data, err := service.addonGroupRepo.All(ctx)
+	return utils.ValidateDataRows[model.AddonGroup](data, err)
+}
+
+func (service catalogModifierService) AddAddonGroup(
+	ctx context.Context,
+	item *model.AddonGroup,
+) (group *model.AddonGroup, errData *utils.ServiceError) {
+	if errData := service.validateGroup(ctx, item); errData != nil {
+		return nil, errData
+	}
+	data, err := service.addonGroupRepo.Create(ctx, item)
+	return utils.ValidateDataRow[model.AddonGroup](data, err)
+}
+
+func (service catalogModifierService) EditAddonGroup(
+	ctx context.Context,
+	item *model.AddonGroup,
+) (group *model.AddonGroup, errData *utils.ServiceError) {
+	if errData := service.validateGroup(ctx, item); errData != nil {
+		return nil, errData
+	}
+	data, err := service.addonGroupRepo.Update(ctx, item)
+	return utils.ValidateDataRow[model.AddonGroup](data, err)
+}
+
+func (service catalogModifierService) DeleteAddonGroup(
+	ctx context.Context,
+	item *model.AddonGroup,
+) *utils.ServiceError {
+	data, err := service.addonGroupRepo.Find(ctx, model.FindWithID, item.ID)
+	if err != nil {
+		_, errData := utils.ValidateDataRow[model.AddonGroup](data, err)
+		return errData
+	}
+	_, errData := utils.ValidateDataRow[model.AddonGroup](
+		nil, service.addonGroupRepo.Delete(ctx, data))
+	return errData
+}
+
+func (service catalogModifierService) AssignAddonGroup(
+	ctx context.Context,
+	item *model.AddonGroupAssignment,
+) *utils.ServiceError {
+	if errData := service.validateAssignment(ctx, item); errData != nil {
+		return errData
+	}
+	_, errData := utils.ValidateDataRow[model.AddonGroupAssignment](
+		nil, service.addonGroupRepo.Assign(ctx, item))
+	return errData
+}
+
+func (service catalogModifierService) UnassignAddonGroup(
+	ctx context.Context,
+	item *model.AddonGroupAssignment,
+) *utils.ServiceError {
+	if errData := service.validateAssignment(ctx, item); errData != nil {
+		return errData
+	}
+	_, errData := utils.ValidateDataRow[model.AddonGroupAssignment](
+		nil, service.addonGroupRepo.Unassign(ctx, item))
+	return errData
+}
+
+func (service catalogModifierService) ProductAddonGroups(
+	ctx context.Context,
+	productID int,
+) (groups []*model.AddonGroup, errData *utils.ServiceError) {
+	data, err := service.addonGroupRepo.ProductGroups(ctx, productID)
+	return utils.ValidateDataRows[model.AddonGroup](data, err)
+}
+
+func (service catalogModifierService) SetProductAddonPrice(
+	ctx context.Context,
+	item *model.ProductAddonPrice,
+) *utils.ServiceError {
+	addon, err := service.addonRepo.Find(ctx, model.FindWithID, item.AddonID)
+	if err != nil {
+		_, errData := utils.ValidateDataRow[model.Addon](addon, err)
+		return errData
+	}
+	_, errData := utils.ValidateDataRow[model.ProductAddonPrice](
+		nil, service.addonGroupRepo.SetProductPrice(ctx, item))
+	return errData
+}
+
+func (service catalogModifierService) DeleteProductAddonPrice(
+	ctx context.Context,
+	item *model.ProductAddonPrice,
+) *utils.ServiceError {
+	_, errData := utils.ValidateDataRow[model.ProductAddonPrice](
+		nil, service.addonGroupRepo.DeleteProductPrice(ctx, item))
+	return errData
+}
+
+func (service catalogModifierService) ValidateAddonSelection(
+	ctx context.Context,
+	productID int,
+	selections []*model.AddonSelection,
+) (result *model.AddonSelectionResult, errData *utils.ServiceError) {
+	groups, err := service.addonGroupRepo.ProductGroups(ctx, productID)
+	if err != nil {
+		_, errData := utils.ValidateDataRows[model.AddonGroup](groups, err)
+		return nil, errData
+	}
+
+	applicable := make(map[int]*model.AddonGroup, len(groups))
+	for _, group := range groups {
+		applicable[group.ID] = group
+	}
+	var errs []*model.AddonSelectionError
+	selected := make(map[int]int, len(groups))
+	result = &model.AddonSelectionResult{Lines: []*model.AddonSelectionLine{}}
+	for _, selection := range selections {
+		group, ok := applicable[selection.AddonGroupID]
+		if !ok {
+			errs = append(errs, &model.AddonSelectionError{
+				AddonGroupID: selection.AddonGroupID,
+				AddonID:      selection.AddonID,
+				Message:      "addon group is not available for this product",
+			})
+			continue
+		}
+		addon := findGroupAddon(group, selection.AddonID)
+		if addon == nil {
+			errs = append(errs, &model.AddonSelectionError{
+				AddonGroupID: group.ID,
+				AddonID:      selection.AddonID,
+				Message:      fmt.Sprintf("addon is not part of %s", group.Name),
+			})
+			continue
+		}
+		qty := selection.Qty
+		if qty == 0 {
+			qty = 1
+		}
+		selected[group.ID] += qty
+		line := &model.AddonSelectionLine{
+			AddonGroupID: group.ID, AddonID: addon.ID, Name: addon.Name,
+			Qty: qty, Price: addon.Price, Subtotal: addon.Price.Mul(qty),
+		}
+		result.Lines = append(result.Lines, line)
+		result.Total += line.Subtotal
+	}
+
+	// rules are checked for every group, so a required group
+	// that was not selected at all is reported as well
+	for _, group := range groups {
+		count := selected[group.ID]
+		switch {
+		case count < group.MinSelect:
+			errs = append(errs, &model.AddonSelectionError{
+				AddonGroupID: group.ID,
+				Message:      fmt.Sprintf("%s require at least %d selection", group.Name, group.MinSelect),
+			})
+		case group.MaxSelect > 0 && count > group.MaxSelect:
+			errs = append(errs, &model.AddonSelectionError{
+				AddonGroupID: group.ID,
+				Message:      fmt.Sprintf("%s allow at most %d selection", group.Name, group.MaxSelect),
+			})
+		}
+	}
+	if len(errs) > 0 {
+		return nil, &utils.ServiceError{
+			Code:    http.StatusUnprocessableEntity,
+			Message: errs,
+		}
+	}
+
+	return result, nil
+}
+
+func (service catalogModifierService) validateGroup(
+	ctx context.Context,
+	item *model.AddonGroup,
+) *utils.ServiceError {
+	if item.MaxSelect > 0 && item.MinSelect > item.MaxSelect {
+		return &utils.ServiceError{
+			Code:    http.StatusUnprocessableEntity,
+			Message: "min_select cannot be greater than max_select",
+		}
+	}
+	addons, err := service.addonRepo.All(ctx)
+	if err != nil {
+		_, errData := utils.ValidateDataRows[model.Addon](addons, err)
+		return errData
+	}
+	exists := make(map[int]bool, len(addons))
+	for _, addon := range addons {
+		exists[addon.ID] = true
+	}
+	seen := make(map[int]bool, len(item.AddonIDs))
+	for _, id := range item.AddonIDs {
+		if !exists[id] {
+			return &utils.ServiceError{
+				Code:    http.StatusUnprocessableEntity,
+				Message: fmt.Sprintf("addon %d not found", id),
+			}
+		}
+		if seen[id] {
+			return &utils.ServiceError{
+				Code:    http.StatusUnprocessableEntity,
+				Message: fmt.Sprintf("addon %d is listed more than once", id),
+			}
+		}
+		seen[id] = true
+	}
+	return nil
+}
+
+func (service catalogModifierService) validateAssignment(
+	ctx context.Context,
+	item *model.AddonGroupAssignment,
+) *utils.ServiceError {
+	if (item.ProductID > 0) == (item.CategoryID > 0) {
+		return &utils.ServiceError{
+			Code:    http.StatusUnprocessableEntity,
+			Message: "either product_id or category_id is required",
+		}
+	}
+	data, err := service.addonGroupRepo.Find(ctx, model.FindWithID, item.AddonGroupID)
+	_, errData := utils.ValidateDataRow[model.AddonGroup](data, err)
+	return errData
+}
+
+func findGroupAddon(group *model.AddonGroup, addonID int) *model.Addon {
+	for _, addon := range group.Addons {
+		if addon.ID == addonID {
+			return addon
+		}
+	}
+	return nil
+}
+
+func NewCatalogModifierService(
+	addonGroupRepo model.IAddonGroupRepository,
+	addonRepo model.ICRUDRepository[model.Addon],
+) model.ICatalogModifierService {
+	return &catalogModifierService{
+		addonGroupRepo: addonGroupRepo,
+		addonRepo:      addonRepo,
+	}
+}
@@ -0,0 +1,183 @@
+package service_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/aasumitro/posbe/internal/catalog/service"
+	"github.com/aasumitro/posbe/mocks"
+	"github.com/aasumitro/posbe/pkg/model"
+	"github.com/aasumitro/posbe/pkg/money"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type catalogModifierTestSuite struct {
+	suite.Suite
+	addons []*model.Addon
+	groups []*model.AddonGroup
+}
+
+func (suite *catalogModifierTestSuite) SetupSuite() {
+	suite.addons = []*model.Addon{
+		{ID: 1, Name: "oat milk", Price: 5000},
+		{ID: 2, Name: "soy milk", Price: 4000},
+		{ID: 3, Name: "boba", Price: 3000},
+	}
+	suite.groups = []*model.AddonGroup{
+		{ID: 1, Name: "milk choice", MinSelect: 1, MaxSelect: 1,
+			AddonIDs: []int{1, 2}, Addons: suite.addons[:2]},
+		{ID: 2, Name: "extra toppings", MinSelect: 0, MaxSelect: 3,
+			AddonIDs: []int{3}, Addons: suite.addons[2:]},
+	}
+}
+
+func (suite *catalogModifierTestSuite) TestCatalogModifierService_AddAddonGroup_ShouldSuccess() {
+	groupRepo := new(mocks.IAddonGroupRepository)
+	addonRepo := new(mocks.ISoftDeleteRepository[model.Addon])
+	svc := service.NewCatalogModifierService(groupRepo, addonRepo)
+	addonRepo.On("All", mock.Anything).Once().Return(suite.addons, nil)
+	groupRepo.On("Create", mock.Anything, suite.groups[0]).
+		Once().Return(suite.groups[0], nil)
+	data, err := svc.AddAddonGroup(context.TODO(), suite.groups[0])
+	require.Nil(suite.T(), err)
+	require.Equal(suite.T(), suite.groups[0], data)
+}
+
+func (suite *catalogModifierTestSuite) TestCatalogModifierService_AddAddonGroup_ShouldErrorInvalidRange() {
+	svc := service.NewCatalogModifierService(
+		new(mocks.IAddonGroupRepository), new(mocks.ISoftDeleteRepository[model.Addon]))
+	data, err := svc.AddAddonGroup(context.TODO(), &model.AddonGroup{
+		Name: "milk", MinSelect: 2, MaxSelect: 1, AddonIDs: []int{1}})
+	require.Nil(suite.T(), data)
+	require.Equal(suite.T(), http.StatusUnprocessableEntity, err.Code)
+}
+
+func (suite *catalogModifierTestSuite) TestCatalogModifierService_EditAddonGroup_ShouldErrorUnknownAddon() {
+	addonRepo := new(mocks.ISoftDeleteRepository[model.Addon])
+	svc := service.NewCatalogModifierService(new(mocks.IAddonGroupRepository), addonRepo)
+	addonRepo.On("All", mock.Anything).Once().Return(suite.addons, nil)
+	data, err := svc.EditAddonGroup(context.TODO(), &model.AddonGroup{
+		ID: 1, Name: "milk", AddonIDs: []int{1, 9}})
+	require.Nil(suite.T(), data)
+	require.Equal(suite.T(), http.StatusUnprocessableEntity, err.Code)
+	require.Equal(suite.T(), "addon 9 not found", err.Message)
+}
+
+func (suite *catalogModifierTestSuite) TestCatalogModifierService_EditAddonGroup_ShouldErrorDuplicateAddon() {
+	addonRepo := new(mocks.ISoftDeleteRepository[model.Addon])
+	svc := service.NewCatalogModifierService(new(mocks.IAddonGroupRepository), addonRepo)
+	addonRepo.On("All", mock.Anything).Once().Return(suite.addons, nil)
+	data, err := svc.EditAddonGroup(context.TODO(), &model.AddonGroup{
+		ID: 1, Name: "milk", AddonIDs: []int{1, 1}})
+	require.Nil(suite.T(), data)
+	require.Equal(suite.T(), http.StatusUnprocessableEntity, err.Code)
+}
+
+func (suite *catalogModifierTestSuite) TestCatalogModifierService_DeleteAddonGroup_ShouldErrorNotFound() {
+	groupRepo := new(mocks.IAddonGroupRepository)
+	svc := service.NewCatalogModifierService(groupRepo, new(mocks.ISoftDeleteRepository[model.Addon]))
+	groupRepo.On("Find", mock.Anything, model.FindWithID, 1).
+		Once().Return(nil, sql.ErrNoRows)
+	err := svc.DeleteAddonGroup(context.TODO(), &model.AddonGroup{ID: 1})
+	require.Equal(suite.T(), http.StatusNotFound, err.Code)
+}
+
+func (suite *catalogModifierTestSuite) TestCatalogModifierService_DeleteAddonGroup_ShouldSuccess() {
+	groupRepo := new(mocks.IAddonGroupRepository)
+	svc := service.NewCatalogModifierService(groupRepo, new(mocks.ISoftDeleteRepository[model.Addon]))
+	groupRepo.On("Find", mock.Anything, model.FindWithID, 1).
+		Once().Return(suite.groups[0], nil)
+	groupRepo.On("Delete", mock.Anything, suite.groups[0]).Once().Return(nil)
+	err := svc.DeleteAddonGroup(context.TODO(), &model.AddonGroup{ID: 1})
+	require.Nil(suite.T(), err)
+}
+
+func (suite *catalogModifierTestSuite) TestCatalogModifierService_AssignAddonGroup_ShouldRequireOneTarget() {
+	svc := service.NewCatalogModifierService(
+		new(mocks.IAddonGroupRepository), new(mocks.ISoftDeleteRepository[model.Addon]))
+	err := svc.AssignAddonGroup(context.TODO(), &model.AddonGroupAssignment{
+		AddonGroupID: 1, ProductID: 1, CategoryID: 1})
+	require.Equal(suite.T(), http.StatusUnprocessableEntity, err.Code)
+	err = svc.UnassignAddonGroup(context.TODO(), &model.AddonGroupAssignment{AddonGroupID: 1})
+	require.Equal(suite.T(), http.StatusUnprocessableEntity, err.Code)
+}
+
+func (suite *catalogModifierTestSuite) TestCatalogModifierService_AssignAddonGroup_ShouldSuccess() {
+	groupRepo := new(mocks.IAddonGroupRepository)
+	svc := service.NewCatalogModifierService(groupRepo, new(mocks.ISoftDeleteRepository[model.Addon]))
+	assignment := &model.AddonGroupAssignment{AddonGroupID: 1, CategoryID: 2}
+	groupRepo.On("Find", mock.Anything, model.FindWithID, 1).
+		Once().Return(suite.groups[0], nil)
+	groupRepo.On("Assign", mock.Anything, assignment).Once().Return(nil)
+	err := svc.AssignAddonGroup(context.TODO(), assignment)
+	require.Nil(suite.T(), err)
+}
+
+func (suite *catalogModifierTestSuite) TestCatalogModifierService_SetProductAddonPrice_ShouldErrorUnknownAddon() {
+	addonRepo := new(mocks.ISoftDeleteRepository[model.Addon])
+	svc := service.NewCatalogModifierService(new(mocks.IAddonGroupRepository), addonRepo)
+	addonRepo.On("Find", mock.Anything, model.FindWithID, 9).
+		Once().Return(nil, sql.ErrNoRows)
+	err := svc.SetProductAddonPrice(context.TODO(), &model.ProductAddonPrice{
+		ProductID: 1, AddonID: 9, Price: 1000})
+	require.Equal(suite.T(), http.StatusNotFound, err.Code)
+}
+
+func (suite *catalogModifierTestSuite) TestCatalogModifierService_SetProductAddonPrice_ShouldSuccess() {
+	groupRepo := new(mocks.IAddonGroupRepository)
+	addonRepo := new(mocks.ISoftDeleteRepository[model.Addon])
+	svc := service.NewCatalogModifierService(groupRepo, addonRepo)
+	price := &model.ProductAddonPrice{ProductID: 1, AddonID: 1, Price: 1000}
+	addonRepo.On("Find", mock.Anything, model.FindWithID, 1).
+		Once().Return(suite.addons[0], nil)
+	groupRepo.On("SetProductPrice", mock.Anything, price).Once().Return(nil)
+	err := svc.SetProductAddonPrice(context.TODO(), price)
+	require.Nil(suite.T(), err)
+}
+
+func (suite *catalogModifierTestSuite) TestCatalogModifierService_ValidateAddonSelection_ShouldReturnTotal() {
+	groupRepo := new(mocks.IAddonGroupRepository)
+	svc := service.NewCatalogModifierService(groupRepo, new(mocks.ISoftDeleteRepository[model.Addon]))
+	groupRepo.On("ProductGroups", mock.Anything, 1).Once().Return(suite.groups, nil)
+	data, err := svc.ValidateAddonSelection(context.TODO(), 1, []*model.AddonSelection{
+		{AddonGroupID: 1, AddonID: 2},
+		{AddonGroupID: 2, AddonID: 3, Qty: 2},
+	})
+	require.Nil(suite.T(), err)
+	require.Len(suite.T(), data.Lines, 2)
+	require.Equal(suite.T(), money.Amount(10000), data.Total)
+}
+
+func (suite *catalogModifierTestSuite) TestCatalogModifierService_ValidateAddonSelection_ShouldReportRules() {
+	groupRepo := new(mocks.IAddonGroupRepository)
+	svc := service.NewCatalogModifierService(groupRepo, new(mocks.ISoftDeleteRepository[model.Addon]))
+	groupRepo.On("ProductGroups", mock.Anything, 1).Once().Return(suite.groups, nil)
+	data, err := svc.ValidateAddonSelection(context.TODO(), 1, []*model.AddonSelection{
+		{AddonGroupID: 1, AddonID: 3},
+		{AddonGroupID: 2, AddonID: 3, Qty: 4},
+		{AddonGroupID: 5, AddonID: 1},
+	})
+	require.Nil(suite.T(), data)
+	require.Equal(suite.T(), http.StatusUnprocessableEntity, err.Code)
+	errs := err.Message.([]*model.AddonSelectionError)
+	// unknown addon, unknown group, milk missing, toppings over max
+	require.Len(suite.T(), errs, 4)
+}
+
+func (suite *catalogModifierTestSuite) TestCatalogModifierService_ValidateAddonSelection_ShouldErrorRepository() {
+	groupRepo := new(mocks.IAddonGroupRepository)
+	svc := service.NewCatalogModifierService(groupRepo, new(mocks.ISoftDeleteRepository[model.Addon]))
+	groupRepo.On("ProductGroups", mock.Anything, 1).Once().Return(nil, errors.New("UNEXPECTED"))
+	data, err := svc.ValidateAddonSelection(context.TODO(), 1, nil)
+	require.Nil(suite.T(), data)
+	require.Equal(suite.T(), http.StatusInternalServerError, err.Code)
+}
+
+func TestCatalogModifierService(t *testing.T) {
+	suite.Run(t, new(catalogModifierTestSuite))
+}
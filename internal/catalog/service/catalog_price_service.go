@@ -0,0 +1,213 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/aasumitro/posbe/pkg/model"
+	"github.com/aasumitro/posbe/pkg/utils"
+)
+
+type catalogPriceService struct {
+	priceListRepo model.IPriceListRepository
+	prefRepo      model.IStorePrefRepository
+}
+
+func (service catalogPriceService) PriceListList(
+	ctx context.Context,
+) (lists []*model.PriceList, errData *utils.ServiceError) {
+	data, err := service.priceListRepo.All(ctx)
+	return utils.ValidateDataRows[model.PriceList](data, err)
+}
+
+func (service catalogPriceService) AddPriceList(
+	ctx context.Context,
+	item *model.PriceList,
+) (list *model.PriceList, errData *utils.ServiceError) {
+	if errData := validatePriceList(item); errData != nil {
+		return nil, errData
+	}
+	data, err := service.priceListRepo.Create(ctx, item)
+	return utils.ValidateDataRow[model.PriceList](data, err)
+}
+
+func (service catalogPriceService) EditPriceList(
+	ctx context.Context,
+	item *model.PriceList,
+) (list *model.PriceList, errData *utils.ServiceError) {
+	if errData := validatePriceList(item); errData != nil {
+		return nil, errData
+	}
+	data, err := service.priceListRepo.Update(ctx, item)
+	return utils.ValidateDataRow[model.PriceList](data, err)
+}
+
+func (service catalogPriceService) DeletePriceList(
+	ctx context.Context,
+	item *model.PriceList,
+) *utils.ServiceError {
+	data, err := service.priceListRepo.Find(ctx, model.FindWithID, item.ID)
+	if err != nil {
+		_, errData := utils.ValidateDataRow[model.PriceList](data, err)
+		return errData
+	}
+	_, errData := utils.ValidateDataRow[model.PriceList](
+		nil, service.priceListRepo.Delete(ctx, data))
+	return errData
+}
+
+func (service catalogPriceService) ResolvePrices(
+	ctx context.Context,
+	query *model.PriceQuery,
+) (snapshots []*model.PriceSnapshot, errData *utils.ServiceError) {
+	basePrices, err := service.priceListRepo.BasePrices(ctx, query.Items)
+	if err != nil {
+		_, errData := utils.ValidateDataRow[model.PriceSnapshot](nil, err)
+		return nil, errData
+	}
+	for _, item := range query.Items {
+		if _, ok := basePrices[model.PriceItemKey(item.ItemType, item.ItemID)]; !ok {
+			return nil, &utils.ServiceError{
+				Code:    http.StatusUnprocessableEntity,
+				Message: fmt.Sprintf("%s %d not found", item.ItemType, item.ItemID),
+			}
+		}
+	}
+	candidates, err := service.priceListRepo.Candidates(ctx, query.Items)
+	if err != nil {
+		_, errData := utils.ValidateDataRow[model.PriceSnapshot](nil, err)
+		return nil, errData
+	}
+
+	at := time.Now()
+	if query.At > 0 {
+		at = time.Unix(query.At, 0)
+	}
+	at = at.In(storeLocation(ctx, service.prefRepo))
+	for _, item := range query.Items {
+		key := model.PriceItemKey(item.ItemType, item.ItemID)
+		snapshot := &model.PriceSnapshot{
+			ItemType: item.ItemType, ItemID: item.ItemID,
+			BasePrice: basePrices[key], Price: basePrices[key],
+			Channel: query.Channel, CustomerTier: query.CustomerTier,
+			ResolvedAt: at.Unix(),
+		}
+		var best *model.PriceCandidate
+		for _, candidate := range candidates {
+			if model.PriceItemKey(candidate.Item.ItemType, candidate.Item.ItemID) != key ||
+				!priceListApplies(candidate.List, query, at) {
+				continue
+			}
+			// higher priority win, newer list win the tie
+			if best == nil || candidate.List.Priority > best.List.Priority ||
+				(candidate.List.Priority == best.List.Priority && candidate.List.ID > best.List.ID) {
+				best = candidate
+			}
+		}
+		if best != nil {
+			snapshot.Price = best.Item.Price
+			snapshot.PriceListID = best.List.ID
+			snapshot.PriceListName = best.List.Name
+		}
+		snapshots = append(snapshots, snapshot)
+	}
+
+	return snapshots, nil
+}
+
+// storeLocation use the store fe_locale pref, time window and days are local to the outlet
+func storeLocation(ctx context.Context, prefRepo model.IStorePrefRepository) *time.Location {
+	if pref, err := prefRepo.Find(ctx, "fe_locale"); err == nil && pref != nil {
+		if name, ok := (*pref)["fe_locale"].(string); ok {
+			if loc, err := time.LoadLocation(name); err == nil {
+				return loc
+			}
+		}
+	}
+	return time.Local
+}
+
+func validatePriceList(item *model.PriceList) *utils.ServiceError {
+	var message string
+	switch {
+	case (item.StartTime == "") != (item.EndTime == ""):
+		message = "start_time and end_time must be set together"
+	case item.EffectiveFrom > 0 && item.EffectiveTo > 0 &&
+		item.EffectiveFrom >= item.EffectiveTo:
+		message = "effective_to must be after effective_from"
+	}
+	seen := make(map[string]bool, len(item.Items))
+	for _, listItem := range item.Items {
+		key := model.PriceItemKey(listItem.ItemType, listItem.ItemID)
+		if seen[key] {
+			message = fmt.Sprintf("%s %d is listed more than once", listItem.ItemType, listItem.ItemID)
+			break
+		}
+		seen[key] = true
+	}
+	if message == "" {
+		return nil
+	}
+	return &utils.ServiceError{
+		Code:    http.StatusUnprocessableEntity,
+		Message: message,
+	}
+}
+
+// priceListApplies check every condition of the list against the order context
+func priceListApplies(list *model.PriceList, query *model.PriceQuery, at time.Time) bool {
+	if list.Channel != "" && list.Channel != query.Channel {
+		return false
+	}
+	if list.CustomerTier != "" && list.CustomerTier != query.CustomerTier {
+		return false
+	}
+	if list.EffectiveFrom > 0 && at.Unix() < list.EffectiveFrom {
+		return false
+	}
+	if list.EffectiveTo > 0 && at.Unix() >= list.EffectiveTo {
+		return false
+	}
+	return withinSchedule(list.Days, list.StartTime, list.EndTime, at)
+}
+
+// withinSchedule match weekday and HH:MM window, empty days or window match
+// anything, window that end before it start pass midnight (e.g: 22:00 - 02:00)
+func withinSchedule(days []int, startTime, endTime string, at time.Time) bool {
+	if len(days) > 0 {
+		matched := false
+		for _, day := range days {
+			matched = matched || time.Weekday(day) == at.Weekday()
+		}
+		if !matched {
+			return false
+		}
+	}
+	if startTime == "" || endTime == "" {
+		return true
+	}
+	start, errStart := time.Parse("15:04", startTime)
+	end, errEnd := time.Parse("15:04", endTime)
+	if errStart != nil || errEnd != nil {
+		return false
+	}
+	now := at.Hour()*60 + at.Minute()
+	from := start.Hour()*60 + start.Minute()
+	to := end.Hour()*60 + end.Minute()
+	if from <= to {
+		return now >= from && now < to
+	}
+	return now >= from || now < to
+}
+
+func NewCatalogPriceService(
+	priceListRepo model.IPriceListRepository,
+	prefRepo model.IStorePrefRepository,
+) model.ICatalogPriceService {
+	return &catalogPriceService{
+		priceListRepo: priceListRepo,
+		prefRepo:      prefRepo,
+	}
+}
@@ -0,0 +1,144 @@
+package service_test
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/aasumitro/posbe/internal/catalog/service"
+	"github.com/aasumitro/posbe/mocks"
+	"github.com/aasumitro/posbe/pkg/model"
+	"github.com/aasumitro/posbe/pkg/money"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type catalogPriceTestSuite struct {
+	suite.Suite
+	priceRepo  *mocks.IPriceListRepository
+	prefRepo   *mocks.IStorePrefRepository
+	svc        model.ICatalogPriceService
+	loc        *time.Location
+	candidates []*model.PriceCandidate
+}
+
+func (suite *catalogPriceTestSuite) SetupTest() {
+	suite.priceRepo = new(mocks.IPriceListRepository)
+	suite.prefRepo = new(mocks.IStorePrefRepository)
+	suite.svc = service.NewCatalogPriceService(suite.priceRepo, suite.prefRepo)
+	var err error
+	suite.loc, err = time.LoadLocation("Asia/Makassar")
+	require.NoError(suite.T(), err)
+	suite.prefRepo.On("Find", mock.Anything, "fe_locale").
+		Return(&model.StoreSetting{"fe_locale": "Asia/Makassar"}, nil)
+	suite.priceRepo.On("BasePrices", mock.Anything, mock.Anything).
+		Return(map[string]money.Amount{"product:1": 20000, "addon:1": 5000}, nil)
+	product := &model.PriceListItem{ItemType: "product", ItemID: 1}
+	suite.candidates = []*model.PriceCandidate{
+		{List: &model.PriceList{ID: 1, Name: "happy hour", Days: []int{1, 2, 3, 4, 5},
+			StartTime: "15:00", EndTime: "17:00"},
+			Item: &model.PriceListItem{ItemType: product.ItemType, ItemID: 1, Price: 15000}},
+		{List: &model.PriceList{ID: 2, Name: "delivery", Channel: "delivery", Priority: 1},
+			Item: &model.PriceListItem{ItemType: product.ItemType, ItemID: 1, Price: 22000}},
+		{List: &model.PriceList{ID: 3, Name: "late night", StartTime: "22:00", EndTime: "02:00"},
+			Item: &model.PriceListItem{ItemType: product.ItemType, ItemID: 1, Price: 18000}},
+		{List: &model.PriceList{ID: 4, Name: "gold member", CustomerTier: "gold",
+			EffectiveTo: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC).Unix()},
+			Item: &model.PriceListItem{ItemType: "addon", ItemID: 1, Price: 0}},
+	}
+	suite.priceRepo.On("Candidates", mock.Anything, mock.Anything).Return(suite.candidates, nil)
+}
+
+func (suite *catalogPriceTestSuite) resolve(query *model.PriceQuery) []*model.PriceSnapshot {
+	query.Items = []*model.PriceQueryItem{
+		{ItemType: "product", ItemID: 1}, {ItemType: "addon", ItemID: 1}}
+	data, err := suite.svc.ResolvePrices(context.TODO(), query)
+	require.Nil(suite.T(), err)
+	require.Len(suite.T(), data, 2)
+	return data
+}
+
+func (suite *catalogPriceTestSuite) TestCatalogPriceService_ResolvePrices_ShouldUseTimeWindow() {
+	// wednesday 16:00 outlet time
+	at := time.Date(2026, 10, 21, 16, 0, 0, 0, suite.loc).Unix()
+	data := suite.resolve(&model.PriceQuery{Channel: "dine_in", At: at})
+	require.Equal(suite.T(), money.Amount(15000), data[0].Price)
+	require.Equal(suite.T(), money.Amount(20000), data[0].BasePrice)
+	require.Equal(suite.T(), 1, data[0].PriceListID)
+	require.Equal(suite.T(), money.Amount(5000), data[1].Price)
+	require.Zero(suite.T(), data[1].PriceListID)
+}
+
+func (suite *catalogPriceTestSuite) TestCatalogPriceService_ResolvePrices_ShouldPreferPriority() {
+	at := time.Date(2026, 10, 21, 16, 0, 0, 0, suite.loc).Unix()
+	data := suite.resolve(&model.PriceQuery{Channel: "delivery", At: at})
+	require.Equal(suite.T(), money.Amount(22000), data[0].Price)
+	require.Equal(suite.T(), "delivery", data[0].PriceListName)
+}
+
+func (suite *catalogPriceTestSuite) TestCatalogPriceService_ResolvePrices_ShouldPassMidnight() {
+	// saturday 01:00, happy hour only on weekdays
+	at := time.Date(2026, 10, 24, 1, 0, 0, 0, suite.loc).Unix()
+	data := suite.resolve(&model.PriceQuery{At: at})
+	require.Equal(suite.T(), money.Amount(18000), data[0].Price)
+}
+
+func (suite *catalogPriceTestSuite) TestCatalogPriceService_ResolvePrices_ShouldHonorEffectiveDate() {
+	at := time.Date(2025, 12, 31, 12, 0, 0, 0, suite.loc).Unix()
+	data := suite.resolve(&model.PriceQuery{CustomerTier: "gold", At: at})
+	require.Equal(suite.T(), money.Amount(0), data[1].Price)
+	at = time.Date(2026, 1, 2, 12, 0, 0, 0, suite.loc).Unix()
+	data = suite.resolve(&model.PriceQuery{CustomerTier: "gold", At: at})
+	require.Equal(suite.T(), money.Amount(5000), data[1].Price)
+}
+
+func (suite *catalogPriceTestSuite) TestCatalogPriceService_ResolvePrices_ShouldErrorUnknownItem() {
+	data, err := suite.svc.ResolvePrices(context.TODO(), &model.PriceQuery{
+		Items: []*model.PriceQueryItem{{ItemType: "variant", ItemID: 9}}})
+	require.Nil(suite.T(), data)
+	require.Equal(suite.T(), http.StatusUnprocessableEntity, err.Code)
+}
+
+func (suite *catalogPriceTestSuite) TestCatalogPriceService_AddPriceList_ShouldValidate() {
+	for _, list := range []*model.PriceList{
+		{Name: "a", StartTime: "10:00"},
+		{Name: "b", EffectiveFrom: 20, EffectiveTo: 10},
+		{Name: "c", Items: []*model.PriceListItem{
+			{ItemType: "product", ItemID: 1}, {ItemType: "product", ItemID: 1}}},
+	} {
+		data, err := suite.svc.AddPriceList(context.TODO(), list)
+		require.Nil(suite.T(), data)
+		require.Equal(suite.T(), http.StatusUnprocessableEntity, err.Code)
+	}
+}
+
+func (suite *catalogPriceTestSuite) TestCatalogPriceService_AddPriceList_ShouldSuccess() {
+	list := &model.PriceList{Name: "weekend", Days: []int{0, 6}}
+	suite.priceRepo.On("Create", mock.Anything, list).Once().Return(list, nil)
+	data, err := suite.svc.AddPriceList(context.TODO(), list)
+	require.Nil(suite.T(), err)
+	require.Equal(suite.T(), list, data)
+}
+
+func (suite *catalogPriceTestSuite) TestCatalogPriceService_EditPriceList_ShouldErrorNotFound() {
+	list := &model.PriceList{ID: 9, Name: "weekend"}
+	suite.priceRepo.On("Update", mock.Anything, list).Once().Return(nil, sql.ErrNoRows)
+	data, err := suite.svc.EditPriceList(context.TODO(), list)
+	require.Nil(suite.T(), data)
+	require.Equal(suite.T(), http.StatusNotFound, err.Code)
+}
+
+func (suite *catalogPriceTestSuite) TestCatalogPriceService_DeletePriceList_ShouldSuccess() {
+	list := &model.PriceList{ID: 1}
+	suite.priceRepo.On("Find", mock.Anything, model.FindWithID, 1).Once().Return(list, nil)
+	suite.priceRepo.On("Delete", mock.Anything, list).Once().Return(nil)
+	err := suite.svc.DeletePriceList(context.TODO(), list)
+	require.Nil(suite.T(), err)
+}
+
+func TestCatalogPriceService(t *testing.T) {
+	suite.Run(t, new(catalogPriceTestSuite))
+}
@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"errors"
 	"net/http"
+	"time"
 
 	"github.com/aasumitro/posbe/pkg/model"
 	"github.com/aasumitro/posbe/pkg/utils"
@@ -12,7 +13,9 @@ import (
 
 type catalogProductService struct {
 	productRepo        model.ICRUDWithSearchRepository[model.Product]
-	productVariantRepo model.ICRUDRepository[model.ProductVariant]
+	productVariantRepo model.ISoftDeleteRepository[model.ProductVariant]
+	availabilityRepo   model.IAvailabilityRepository
+	prefRepo           model.IStorePrefRepository
 }
 
 func (service catalogProductService) ProductSearch(
@@ -21,14 +24,20 @@ func (service catalogProductService) ProductSearch(
 	values []any,
 ) (products []*model.Product, errData *utils.ServiceError) {
 	data, err := service.productRepo.Search(ctx, keys, values)
-	return utils.ValidateDataRows[model.Product](data, err)
+	if err != nil {
+		return utils.ValidateDataRows[model.Product](data, err)
+	}
+	return utils.ValidateDataRows[model.Product](data, service.applyAvailability(ctx, data...))
 }
 
 func (service catalogProductService) ProductList(
 	ctx context.Context,
 ) (products []*model.Product, errData *utils.ServiceError) {
 	data, err := service.productRepo.All(ctx)
-	return utils.ValidateDataRows[model.Product](data, err)
+	if err != nil {
+		return utils.ValidateDataRows[model.Product](data, err)
+	}
+	return utils.ValidateDataRows[model.Product](data, service.applyAvailability(ctx, data...))
 }
 
 func (service catalogProductService) ProductDetail(
@@ -36,7 +45,10 @@ func (service catalogProductService) ProductDetail(
 	id int,
 ) (product *model.Product, errData *utils.ServiceError) {
 	data, err := service.productRepo.Find(ctx, model.FindWithID, id)
-	return utils.ValidateDataRow[model.Product](data, err)
+	if err != nil {
+		return utils.ValidateDataRow[model.Product](data, err)
+	}
+	return utils.ValidateDataRow[model.Product](data, service.applyAvailability(ctx, data))
 }
 
 func (service catalogProductService) AddProduct(
@@ -94,6 +106,14 @@ func (service catalogProductService) DeleteProduct(
 	return nil
 }
 
+func (service catalogProductService) RestoreProduct(
+	ctx context.Context,
+	item *model.Product,
+) (product *model.Product, errData *utils.ServiceError) {
+	data, err := service.productRepo.Restore(ctx, item)
+	return utils.ValidateDataRow[model.Product](data, err)
+}
+
 func (service catalogProductService) AddProductVariant(
 	ctx context.Context,
 	item *model.ProductVariant,
@@ -137,12 +157,40 @@ func (service catalogProductService) DeleteProductVariant(
 	return nil
 }
 
+func (service catalogProductService) RestoreProductVariant(
+	ctx context.Context,
+	item *model.ProductVariant,
+) (variant *model.ProductVariant, errData *utils.ServiceError) {
+	data, err := service.productVariantRepo.Restore(ctx, item)
+	return utils.ValidateDataRow[model.ProductVariant](data, err)
+}
+
+// applyAvailability set the schedule and 86 state on the listed products
+func (service catalogProductService) applyAvailability(
+	ctx context.Context,
+	products ...*model.Product,
+) error {
+	index, err := loadAvailability(ctx, service.availabilityRepo)
+	if err != nil {
+		return err
+	}
+	at := time.Now().In(storeLocation(ctx, service.prefRepo))
+	for _, product := range products {
+		product.Availability = index.product(product.ID, product.CategoryID, at)
+	}
+	return nil
+}
+
 func NewCatalogProductService(
 	productRepo model.ICRUDWithSearchRepository[model.Product],
-	productVariantRepo model.ICRUDRepository[model.ProductVariant],
+	productVariantRepo model.ISoftDeleteRepository[model.ProductVariant],
+	availabilityRepo model.IAvailabilityRepository,
+	prefRepo model.IStorePrefRepository,
 ) model.ICatalogProductService {
 	return &catalogProductService{
 		productRepo:        productRepo,
 		productVariantRepo: productVariantRepo,
+		availabilityRepo:   availabilityRepo,
+		prefRepo:           prefRepo,
 	}
 }
@@ -33,9 +33,10 @@ func (suite *catalogProductService) SetupSuite() {
 }
 
 func (suite *catalogProductService) TestService_AddVariant_ShouldSuccess() {
-	repoMock := new(mocks2.ICRUDRepository[model.ProductVariant])
+	repoMock := new(mocks2.ISoftDeleteRepository[model.ProductVariant])
 	svc := service.NewCatalogProductService(
-		new(mocks2.ICRUDWithSearchRepository[model.Product]), repoMock)
+		new(mocks2.ICRUDWithSearchRepository[model.Product]), repoMock,
+		new(mocks2.IAvailabilityRepository), new(mocks2.IStorePrefRepository))
 	repoMock.On("Create", mock.Anything, mock.Anything).
 		Return(suite.variant, nil).Once()
 	data, err := svc.AddProductVariant(context.TODO(), suite.variant)
@@ -45,9 +46,10 @@ func (suite *catalogProductService) TestService_AddVariant_ShouldSuccess() {
 	repoMock.AssertExpectations(suite.T())
 }
 func (suite *catalogProductService) TestService_AddVariant_ShouldError() {
-	repoMock := new(mocks2.ICRUDRepository[model.ProductVariant])
+	repoMock := new(mocks2.ISoftDeleteRepository[model.ProductVariant])
 	svc := service.NewCatalogProductService(
-		new(mocks2.ICRUDWithSearchRepository[model.Product]), repoMock)
+		new(mocks2.ICRUDWithSearchRepository[model.Product]), repoMock,
+		new(mocks2.IAvailabilityRepository), new(mocks2.IStorePrefRepository))
 	repoMock.On("Create", mock.Anything, mock.Anything).
 		Return(nil, errors.New("UNEXPECTED")).Once()
 	data, err := svc.AddProductVariant(context.TODO(), suite.variant)
@@ -58,9 +60,10 @@ func (suite *catalogProductService) TestService_AddVariant_ShouldError() {
 }
 
 func (suite *catalogProductService) TestService_EditVariant_ShouldSuccess() {
-	repoMock := new(mocks2.ICRUDRepository[model.ProductVariant])
+	repoMock := new(mocks2.ISoftDeleteRepository[model.ProductVariant])
 	svc := service.NewCatalogProductService(
-		new(mocks2.ICRUDWithSearchRepository[model.Product]), repoMock)
+		new(mocks2.ICRUDWithSearchRepository[model.Product]), repoMock,
+		new(mocks2.IAvailabilityRepository), new(mocks2.IStorePrefRepository))
 	repoMock.On("Update", mock.Anything, mock.Anything).
 		Return(suite.variant, nil).Once()
 	data, err := svc.EditProductVariant(context.TODO(), suite.variant)
@@ -70,9 +73,10 @@ func (suite *catalogProductService) TestService_EditVariant_ShouldSuccess() {
 	repoMock.AssertExpectations(suite.T())
 }
 func (suite *catalogProductService) TestService_EditVariant_ShouldError() {
-	repoMock := new(mocks2.ICRUDRepository[model.ProductVariant])
+	repoMock := new(mocks2.ISoftDeleteRepository[model.ProductVariant])
 	svc := service.NewCatalogProductService(
-		new(mocks2.ICRUDWithSearchRepository[model.Product]), repoMock)
+		new(mocks2.ICRUDWithSearchRepository[model.Product]), repoMock,
+		new(mocks2.IAvailabilityRepository), new(mocks2.IStorePrefRepository))
 	repoMock.On("Update", mock.Anything, mock.Anything).
 		Return(nil, errors.New("UNEXPECTED")).Once()
 	data, err := svc.EditProductVariant(context.TODO(), suite.variant)
@@ -83,9 +87,10 @@ func (suite *catalogProductService) TestService_EditVariant_ShouldError() {
 }
 
 func (suite *catalogProductService) TestService_DeleteVariant_ShouldSuccess() {
-	repoMock := new(mocks2.ICRUDRepository[model.ProductVariant])
+	repoMock := new(mocks2.ISoftDeleteRepository[model.ProductVariant])
 	svc := service.NewCatalogProductService(
-		new(mocks2.ICRUDWithSearchRepository[model.Product]), repoMock)
+		new(mocks2.ICRUDWithSearchRepository[model.Product]), repoMock,
+		new(mocks2.IAvailabilityRepository), new(mocks2.IStorePrefRepository))
 	repoMock.
 		On("Find", mock.Anything, mock.Anything, mock.Anything).
 		Return(suite.variant, nil).Once()
@@ -97,9 +102,10 @@ func (suite *catalogProductService) TestService_DeleteVariant_ShouldSuccess() {
 	repoMock.AssertExpectations(suite.T())
 }
 func (suite *catalogProductService) TestService_DeleteVariant_ShouldErrorWhenFind() {
-	repoMock := new(mocks2.ICRUDRepository[model.ProductVariant])
+	repoMock := new(mocks2.ISoftDeleteRepository[model.ProductVariant])
 	svc := service.NewCatalogProductService(
-		new(mocks2.ICRUDWithSearchRepository[model.Product]), repoMock)
+		new(mocks2.ICRUDWithSearchRepository[model.Product]), repoMock,
+		new(mocks2.IAvailabilityRepository), new(mocks2.IStorePrefRepository))
 	repoMock.
 		On("Find", mock.Anything, mock.Anything, mock.Anything).
 		Once().
@@ -110,9 +116,10 @@ func (suite *catalogProductService) TestService_DeleteVariant_ShouldErrorWhenFin
 	repoMock.AssertExpectations(suite.T())
 }
 func (suite *catalogProductService) TestService_DeleteVariant_ShouldErrorWhenFindNotFound() {
-	repoMock := new(mocks2.ICRUDRepository[model.ProductVariant])
+	repoMock := new(mocks2.ISoftDeleteRepository[model.ProductVariant])
 	svc := service.NewCatalogProductService(
-		new(mocks2.ICRUDWithSearchRepository[model.Product]), repoMock)
+		new(mocks2.ICRUDWithSearchRepository[model.Product]), repoMock,
+		new(mocks2.IAvailabilityRepository), new(mocks2.IStorePrefRepository))
 	repoMock.
 		On("Find", mock.Anything, mock.Anything, mock.Anything).
 		Once().
@@ -123,9 +130,10 @@ func (suite *catalogProductService) TestService_DeleteVariant_ShouldErrorWhenFin
 	repoMock.AssertExpectations(suite.T())
 }
 func (suite *catalogProductService) TestService_DeleteVariant_ShouldErrorWhenDelete() {
-	repoMock := new(mocks2.ICRUDRepository[model.ProductVariant])
+	repoMock := new(mocks2.ISoftDeleteRepository[model.ProductVariant])
 	svc := service.NewCatalogProductService(
-		new(mocks2.ICRUDWithSearchRepository[model.Product]), repoMock)
+		new(mocks2.ICRUDWithSearchRepository[model.Product]), repoMock,
+		new(mocks2.IAvailabilityRepository), new(mocks2.IStorePrefRepository))
 	repoMock.
 		On("Find", mock.Anything, mock.Anything, mock.Anything).
 		Return(suite.variant, nil).Once()
@@ -138,6 +146,26 @@ func (suite *catalogProductService) TestService_DeleteVariant_ShouldErrorWhenDel
 	repoMock.AssertExpectations(suite.T())
 }
 
+func (suite *catalogProductService) TestService_ProductList_ShouldApplyAvailability() {
+	productRepo := new(mocks2.ICRUDWithSearchRepository[model.Product])
+	availabilityRepo := new(mocks2.IAvailabilityRepository)
+	prefRepo := new(mocks2.IStorePrefRepository)
+	svc := service.NewCatalogProductService(productRepo,
+		new(mocks2.ISoftDeleteRepository[model.ProductVariant]), availabilityRepo, prefRepo)
+	productRepo.On("All", mock.Anything).Return([]*model.Product{
+		{ID: 1, CategoryID: 1}, {ID: 2, CategoryID: 1}}, nil).Once()
+	availabilityRepo.On("Rules", mock.Anything).Return([]*model.AvailabilityRule{}, nil).Once()
+	availabilityRepo.On("SoldOut", mock.Anything).Return([]*model.SoldOutItem{
+		{ProductID: 2}, {ProductID: 1, VariantID: 10}}, nil).Once()
+	prefRepo.On("Find", mock.Anything, "fe_locale").Return(nil, errors.New("UNEXPECTED")).Once()
+	data, err := svc.ProductList(context.TODO())
+	require.Nil(suite.T(), err)
+	require.True(suite.T(), data[0].Availability.Available)
+	require.Equal(suite.T(), []int{10}, data[0].Availability.SoldOutVariantIDs)
+	require.False(suite.T(), data[1].Availability.Available)
+	require.Equal(suite.T(), model.UnavailableSoldOut, data[1].Availability.Reason)
+}
+
 func TestCatalogProductService(t *testing.T) {
 	suite.Run(t, new(catalogProductService))
 }
@@ -0,0 +1,185 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+
+	"github.com/aasumitro/posbe/pkg/model"
+	"github.com/aasumitro/posbe/pkg/money"
+	"github.com/aasumitro/posbe/pkg/utils"
+)
+
+type catalogTaxService struct {
+	taxClassRepo model.ITaxClassRepository
+	prefRepo     model.IStorePrefRepository
+}
+
+func (service catalogTaxService) TaxClassList(
+	ctx context.Context,
+) (classes []*model.TaxClass, errData *utils.ServiceError) {
+	data, err := service.taxClassRepo.All(ctx)
+	return utils.ValidateDataRows[model.TaxClass](data, err)
+}
+
+func (service catalogTaxService) AddTaxClass(
+	ctx context.Context,
+	item *model.TaxClass,
+) (class *model.TaxClass, errData *utils.ServiceError) {
+	data, err := service.taxClassRepo.Create(ctx, item)
+	return utils.ValidateDataRow[model.TaxClass](data, err)
+}
+
+func (service catalogTaxService) EditTaxClass(
+	ctx context.Context,
+	item *model.TaxClass,
+) (class *model.TaxClass, errData *utils.ServiceError) {
+	current, err := service.taxClassRepo.Find(ctx, model.FindWithID, item.ID)
+	if err != nil {
+		return utils.ValidateDataRow[model.TaxClass](current, err)
+	}
+	if current.IsDefault && !item.IsDefault {
+		return nil, &utils.ServiceError{
+			Code:    http.StatusUnprocessableEntity,
+			Message: "default tax class can not be unset, set another class as default",
+		}
+	}
+	data, err := service.taxClassRepo.Update(ctx, item)
+	return utils.ValidateDataRow[model.TaxClass](data, err)
+}
+
+func (service catalogTaxService) DeleteTaxClass(
+	ctx context.Context,
+	item *model.TaxClass,
+) *utils.ServiceError {
+	data, err := service.taxClassRepo.Find(ctx, model.FindWithID, item.ID)
+	if err != nil {
+		_, errData := utils.ValidateDataRow[model.TaxClass](data, err)
+		return errData
+	}
+	if data.IsDefault {
+		return &utils.ServiceError{
+			Code:    http.StatusUnprocessableEntity,
+			Message: "default tax class can not be deleted, set another class as default",
+		}
+	}
+	_, errData := utils.ValidateDataRow[model.TaxClass](
+		nil, service.taxClassRepo.Delete(ctx, data))
+	return errData
+}
+
+// CalculateTax group the lines by tax class, take the included tax out of
+// inclusive prices, charge the service on the net and allocate it to the
+// classes, then add the exclusive tax (and the tax of the service share for
+// after_service classes). Rounding is done once per class.
+func (service catalogTaxService) CalculateTax(
+	ctx context.Context,
+	query *model.TaxQuery,
+) (summary *model.TaxSummary, errData *utils.ServiceError) {
+	productIDs := make([]int, 0, len(query.Items))
+	for _, item := range query.Items {
+		productIDs = append(productIDs, item.ProductID)
+	}
+	productClasses, err := service.taxClassRepo.ProductClasses(ctx, productIDs)
+	if err != nil {
+		_, errData := utils.ValidateDataRow[model.TaxSummary](nil, err)
+		return nil, errData
+	}
+	classes, err := service.taxClassRepo.All(ctx)
+	if err != nil {
+		_, errData := utils.ValidateDataRow[model.TaxSummary](nil, err)
+		return nil, errData
+	}
+	classByID := map[int]*model.TaxClass{0: {Name: "Untaxed"}}
+	for _, class := range classes {
+		classByID[class.ID] = class
+	}
+
+	gross := make(map[int]money.Amount)
+	for _, item := range query.Items {
+		classID, ok := productClasses[item.ProductID]
+		if !ok {
+			return nil, &utils.ServiceError{
+				Code:    http.StatusUnprocessableEntity,
+				Message: fmt.Sprintf("product %d not found", item.ProductID),
+			}
+		}
+		gross[classID] += item.Amount
+	}
+	classIDs := make([]int, 0, len(gross))
+	for classID := range gross {
+		classIDs = append(classIDs, classID)
+	}
+	sort.Ints(classIDs)
+
+	summary = &model.TaxSummary{Exempt: query.Exempt, Lines: []*model.TaxLine{}}
+	if !query.NoService {
+		summary.ServiceRate = serviceRate(ctx, service.prefRepo)
+	}
+	nets := make([]int64, len(classIDs))
+	for i, classID := range classIDs {
+		class := classByID[classID]
+		line := &model.TaxLine{
+			TaxClassID: classID, Name: class.Name,
+			Rate: class.Rate, Inclusive: class.Inclusive,
+		}
+		if class.Inclusive {
+			line.Amount = class.Rate.Included(gross[classID])
+		}
+		line.Base = gross[classID] - line.Amount
+		summary.Subtotal += gross[classID]
+		summary.Net += line.Base
+		nets[i] = int64(line.Base)
+		summary.Lines = append(summary.Lines, line)
+	}
+
+	summary.ServiceCharge = summary.ServiceRate.Of(summary.Net)
+	shares, err := summary.ServiceCharge.Allocate(nets...)
+	if err != nil {
+		return nil, &utils.ServiceError{
+			Code:    http.StatusUnprocessableEntity,
+			Message: err.Error(),
+		}
+	}
+	for i, line := range summary.Lines {
+		class := classByID[line.TaxClassID]
+		if class.AfterService {
+			line.Base += shares[i]
+		}
+		switch {
+		case query.Exempt:
+			line.Amount = 0
+		case class.Inclusive && class.AfterService:
+			line.Amount += class.Rate.Of(shares[i])
+		case !class.Inclusive:
+			line.Amount = class.Rate.Of(line.Base)
+		}
+		summary.Tax += line.Amount
+	}
+	summary.Total = summary.Net + summary.ServiceCharge + summary.Tax
+
+	return summary, nil
+}
+
+// serviceRate read the service_rate store pref, invalid value mean no service charge
+func serviceRate(ctx context.Context, prefRepo model.IStorePrefRepository) money.Percent {
+	if pref, err := prefRepo.Find(ctx, "service_rate"); err == nil && pref != nil {
+		if value, ok := (*pref)["service_rate"].(string); ok {
+			if rate, err := money.ParsePercent(value); err == nil {
+				return rate
+			}
+		}
+	}
+	return 0
+}
+
+func NewCatalogTaxService(
+	taxClassRepo model.ITaxClassRepository,
+	prefRepo model.IStorePrefRepository,
+) model.ICatalogTaxService {
+	return &catalogTaxService{
+		taxClassRepo: taxClassRepo,
+		prefRepo:     prefRepo,
+	}
+}
@@ -0,0 +1,184 @@
+package service_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/aasumitro/posbe/internal/catalog/service"
+	"github.com/aasumitro/posbe/mocks"
+	"github.com/aasumitro/posbe/pkg/model"
+	"github.com/aasumitro/posbe/pkg/money"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type catalogTaxTestSuite struct {
+	suite.Suite
+	taxRepo  *mocks.ITaxClassRepository
+	prefRepo *mocks.IStorePrefRepository
+	svc      model.ICatalogTaxService
+	classes  []*model.TaxClass
+	query    *model.TaxQuery
+}
+
+func (suite *catalogTaxTestSuite) SetupTest() {
+	suite.taxRepo = new(mocks.ITaxClassRepository)
+	suite.prefRepo = new(mocks.IStorePrefRepository)
+	suite.svc = service.NewCatalogTaxService(suite.taxRepo, suite.prefRepo)
+	suite.classes = []*model.TaxClass{
+		{ID: 1, Name: "VAT", Rate: 110000, Inclusive: true, IsDefault: true},
+		{ID: 2, Name: "Alcohol", Rate: 100000, AfterService: true},
+		{ID: 3, Name: "Exempt"},
+	}
+	suite.prefRepo.On("Find", mock.Anything, "service_rate").
+		Return(&model.StoreSetting{"service_rate": "5"}, nil)
+	// Rp111.000 inclusive, Rp100.000 exclusive and Rp50.000 exempt
+	suite.query = &model.TaxQuery{Items: []*model.TaxQueryItem{
+		{ProductID: 1, Amount: 11100000},
+		{ProductID: 2, Amount: 10000000},
+		{ProductID: 3, Amount: 5000000},
+	}}
+}
+
+func (suite *catalogTaxTestSuite) expectClasses(productClasses map[int]int) {
+	suite.taxRepo.On("ProductClasses", mock.Anything, []int{1, 2, 3}).
+		Return(productClasses, nil).Once()
+	suite.taxRepo.On("All", mock.Anything).Return(suite.classes, nil).Once()
+}
+
+func (suite *catalogTaxTestSuite) TestCatalogTaxService_CalculateTax_ShouldSummarizePerRate() {
+	suite.expectClasses(map[int]int{1: 1, 2: 2, 3: 3})
+	data, err := suite.svc.CalculateTax(context.TODO(), suite.query)
+	require.Nil(suite.T(), err)
+	require.Equal(suite.T(), money.Amount(26100000), data.Subtotal)
+	require.Equal(suite.T(), money.Amount(25000000), data.Net)
+	require.Equal(suite.T(), money.Amount(1250000), data.ServiceCharge)
+	require.Len(suite.T(), data.Lines, 3)
+	// inclusive tax is taken out of the price, service is not taxed
+	require.Equal(suite.T(), money.Amount(10000000), data.Lines[0].Base)
+	require.Equal(suite.T(), money.Amount(1100000), data.Lines[0].Amount)
+	// exclusive tax compounded on its service share
+	require.Equal(suite.T(), money.Amount(10500000), data.Lines[1].Base)
+	require.Equal(suite.T(), money.Amount(1050000), data.Lines[1].Amount)
+	require.Equal(suite.T(), money.Amount(5000000), data.Lines[2].Base)
+	require.Zero(suite.T(), data.Lines[2].Amount)
+	require.Equal(suite.T(), money.Amount(2150000), data.Tax)
+	require.Equal(suite.T(), money.Amount(28400000), data.Total)
+	suite.taxRepo.AssertExpectations(suite.T())
+}
+
+func (suite *catalogTaxTestSuite) TestCatalogTaxService_CalculateTax_ShouldCompoundInclusive() {
+	suite.classes[0].AfterService = true
+	suite.expectClasses(map[int]int{1: 1, 2: 2, 3: 3})
+	data, err := suite.svc.CalculateTax(context.TODO(), suite.query)
+	require.Nil(suite.T(), err)
+	require.Equal(suite.T(), money.Amount(10500000), data.Lines[0].Base)
+	require.Equal(suite.T(), money.Amount(1155000), data.Lines[0].Amount)
+	require.Equal(suite.T(), money.Amount(28455000), data.Total)
+}
+
+func (suite *catalogTaxTestSuite) TestCatalogTaxService_CalculateTax_ShouldExempt() {
+	suite.expectClasses(map[int]int{1: 1, 2: 2, 3: 3})
+	suite.query.Exempt = true
+	suite.query.NoService = true
+	data, err := suite.svc.CalculateTax(context.TODO(), suite.query)
+	require.Nil(suite.T(), err)
+	require.True(suite.T(), data.Exempt)
+	require.Zero(suite.T(), data.Tax)
+	require.Zero(suite.T(), data.ServiceCharge)
+	require.Equal(suite.T(), money.Amount(25000000), data.Total)
+	suite.prefRepo.AssertNotCalled(suite.T(), "Find", mock.Anything, "service_rate")
+}
+
+func (suite *catalogTaxTestSuite) TestCatalogTaxService_CalculateTax_ShouldGroupUntaxed() {
+	suite.expectClasses(map[int]int{1: 1, 2: 0, 3: 1})
+	data, err := suite.svc.CalculateTax(context.TODO(), suite.query)
+	require.Nil(suite.T(), err)
+	require.Len(suite.T(), data.Lines, 2)
+	require.Equal(suite.T(), "Untaxed", data.Lines[0].Name)
+	require.Zero(suite.T(), data.Lines[0].Amount)
+	require.Equal(suite.T(), money.Amount(10000000), data.Lines[0].Base)
+	require.Equal(suite.T(), money.Amount(1595495), data.Lines[1].Amount)
+}
+
+func (suite *catalogTaxTestSuite) TestCatalogTaxService_CalculateTax_ShouldRejectUnknownProduct() {
+	suite.expectClasses(map[int]int{1: 1, 2: 2})
+	data, err := suite.svc.CalculateTax(context.TODO(), suite.query)
+	require.Nil(suite.T(), data)
+	require.Equal(suite.T(), http.StatusUnprocessableEntity, err.Code)
+	require.Equal(suite.T(), "product 3 not found", err.Message)
+}
+
+func (suite *catalogTaxTestSuite) TestCatalogTaxService_CalculateTax_ShouldReturnError() {
+	suite.taxRepo.On("ProductClasses", mock.Anything, mock.Anything).
+		Return(nil, errors.New("UNEXPECTED")).Once()
+	data, err := suite.svc.CalculateTax(context.TODO(), suite.query)
+	require.Nil(suite.T(), data)
+	require.Equal(suite.T(), http.StatusInternalServerError, err.Code)
+}
+
+func (suite *catalogTaxTestSuite) TestCatalogTaxService_EditTaxClass_ShouldKeepDefault() {
+	suite.taxRepo.On("Find", mock.Anything, model.FindWithID, 1).
+		Return(suite.classes[0], nil).Once()
+	data, err := suite.svc.EditTaxClass(context.TODO(), &model.TaxClass{ID: 1, Name: "VAT"})
+	require.Nil(suite.T(), data)
+	require.Equal(suite.T(), http.StatusUnprocessableEntity, err.Code)
+	suite.taxRepo.AssertNotCalled(suite.T(), "Update", mock.Anything, mock.Anything)
+}
+
+func (suite *catalogTaxTestSuite) TestCatalogTaxService_EditTaxClass_ShouldSuccess() {
+	form := &model.TaxClass{ID: 2, Name: "Alcohol", Rate: 120000, ProductIDs: []int{2}}
+	suite.taxRepo.On("Find", mock.Anything, model.FindWithID, 2).
+		Return(suite.classes[1], nil).Once()
+	suite.taxRepo.On("Update", mock.Anything, form).Return(form, nil).Once()
+	data, err := suite.svc.EditTaxClass(context.TODO(), form)
+	require.Nil(suite.T(), err)
+	require.Equal(suite.T(), form, data)
+}
+
+func (suite *catalogTaxTestSuite) TestCatalogTaxService_EditTaxClass_ShouldReturnNotFound() {
+	suite.taxRepo.On("Find", mock.Anything, model.FindWithID, 9).
+		Return(nil, sql.ErrNoRows).Once()
+	data, err := suite.svc.EditTaxClass(context.TODO(), &model.TaxClass{ID: 9})
+	require.Nil(suite.T(), data)
+	require.Equal(suite.T(), http.StatusNotFound, err.Code)
+}
+
+func (suite *catalogTaxTestSuite) TestCatalogTaxService_DeleteTaxClass_ShouldRejectDefault() {
+	suite.taxRepo.On("Find", mock.Anything, model.FindWithID, 1).
+		Return(suite.classes[0], nil).Once()
+	err := suite.svc.DeleteTaxClass(context.TODO(), &model.TaxClass{ID: 1})
+	require.Equal(suite.T(), http.StatusUnprocessableEntity, err.Code)
+	suite.taxRepo.AssertNotCalled(suite.T(), "Delete", mock.Anything, mock.Anything)
+}
+
+func (suite *catalogTaxTestSuite) TestCatalogTaxService_DeleteTaxClass_ShouldSuccess() {
+	suite.taxRepo.On("Find", mock.Anything, model.FindWithID, 3).
+		Return(suite.classes[2], nil).Once()
+	suite.taxRepo.On("Delete", mock.Anything, suite.classes[2]).Return(nil).Once()
+	err := suite.svc.DeleteTaxClass(context.TODO(), &model.TaxClass{ID: 3})
+	require.Nil(suite.T(), err)
+}
+
+func (suite *catalogTaxTestSuite) TestCatalogTaxService_TaxClassList_ShouldSuccess() {
+	suite.taxRepo.On("All", mock.Anything).Return(suite.classes, nil).Once()
+	data, err := suite.svc.TaxClassList(context.TODO())
+	require.Nil(suite.T(), err)
+	require.Len(suite.T(), data, 3)
+}
+
+func (suite *catalogTaxTestSuite) TestCatalogTaxService_AddTaxClass_ShouldSuccess() {
+	form := &model.TaxClass{Name: "Luxury", Rate: 200000}
+	suite.taxRepo.On("Create", mock.Anything, form).Return(&model.TaxClass{ID: 4, Name: "Luxury"}, nil).Once()
+	data, err := suite.svc.AddTaxClass(context.TODO(), form)
+	require.Nil(suite.T(), err)
+	require.Equal(suite.T(), 4, data.ID)
+}
+
+func TestCatalogTaxService(t *testing.T) {
+	suite.Run(t, new(catalogTaxTestSuite))
+}
@@ -0,0 +1,84 @@
+package http
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/aasumitro/posbe/pkg/http/middleware"
+	"github.com/aasumitro/posbe/pkg/model"
+	"github.com/aasumitro/posbe/pkg/utils"
+	"github.com/gin-gonic/gin"
+)
+
+type exportHandler struct {
+	svc model.IExportService
+}
+
+// exports godoc
+// @Schemes
+// @Summary Export Dataset List
+// @Description Get available export datasets and columns.
+// @Tags Reports
+// @Accept json
+// @Produce json
+// @Success 200 {object} utils.SuccessRespond{data=[]model.ExportDataset} "OK RESPOND"
+// @Failure 401 {object} utils.ErrorRespond "UNAUTHORIZED RESPOND"
+// @Failure 500 {object} utils.ErrorRespond "INTERNAL SERVER ERROR RESPOND"
+// @Router /api/v1/exports [GET]
+func (handler exportHandler) fetch(ctx *gin.Context) {
+	datasets, err := handler.svc.DatasetList(ctx)
+	if err != nil {
+		utils.NewHTTPRespond(ctx, err.Code, err.Message)
+		return
+	}
+	utils.NewHTTPRespond(ctx, http.StatusOK, datasets)
+}
+
+// exports godoc
+// @Schemes
+// @Summary Export Dataset
+// @Description Stream dataset as CSV or XLSX file.
+// @Tags Reports
+// @Accept json
+// @Produce octet-stream
+// @Param dataset path string true "dataset name" Enums(products, product_variants, addons, units, users, roles, sales, shift_reports)
+// @Param format query string false "file format" Enums(csv, xlsx)
+// @Param columns query string false "comma separated column keys"
+// @Param lang query string false "number & date locale, default store fe_lang" Enums(en_US, id_ID)
+// @Param tz query string false "timezone, default store fe_locale"
+// @Param from query int false "range start (unix time)"
+// @Param to query int false "range end (unix time)"
+// @Success 200 {file} file "FILE RESPOND"
+// @Failure 400 {object} utils.ErrorRespond "BAD REQUEST RESPOND"
+// @Failure 401 {object} utils.ErrorRespond "UNAUTHORIZED RESPOND"
+// @Failure 404 {object} utils.ErrorRespond "NOT FOUND RESPOND"
+// @Router /api/v1/exports/{dataset} [GET]
+func (handler exportHandler) export(ctx *gin.Context) {
+	var form model.ExportForm
+	if err := ctx.ShouldBindQuery(&form); err != nil {
+		utils.NewHTTPRespond(ctx, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+	export, err := handler.svc.PrepareExport(ctx, ctx.Param("dataset"), &form)
+	if err != nil {
+		utils.NewHTTPRespond(ctx, err.Code, err.Message)
+		return
+	}
+	ctx.Header("Content-Type", export.ContentType)
+	ctx.Header("Content-Disposition",
+		fmt.Sprintf("attachment; filename=%q", export.Filename))
+	ctx.Status(http.StatusOK)
+	// headers already sent, error can only be logged from here
+	if err := export.WriteTo(ctx, ctx.Writer); err != nil {
+		log.Printf("EXPORT_ERROR: %s\n", err.Error())
+		_ = ctx.Error(err)
+	}
+}
+
+func NewExportHandler(svc model.IExportService, router gin.IRoutes) {
+	handler := exportHandler{svc: svc}
+	export := middleware.Permitted(model.PermissionReportExport)
+	router.GET("/exports", export, handler.fetch)
+	router.GET("/exports/:dataset", export, handler.export)
+}
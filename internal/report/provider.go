@@ -0,0 +1,22 @@
+package report
+
+import (
+	"github.com/aasumitro/posbe/common"
+	"github.com/aasumitro/posbe/internal/report/handler/http"
+	repository "github.com/aasumitro/posbe/internal/report/repository/sql"
+	"github.com/aasumitro/posbe/internal/report/service"
+	storeRepository "github.com/aasumitro/posbe/internal/store/repository/sql"
+	"github.com/aasumitro/posbe/pkg/http/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+func NewReportModuleProvider(router *gin.RouterGroup) {
+	exportRepository := repository.NewExportSQLRepository()
+	storePrefRepository := storeRepository.NewStorePrefSQLRepository()
+	exportService := service.NewExportService(
+		exportRepository, storePrefRepository)
+	// use sub group, so the middlewares not leaking to other modules
+	protectedRouter := router.Group(common.EmptyPath).
+		Use(middleware.Auth())
+	http.NewExportHandler(exportService, protectedRouter)
+}
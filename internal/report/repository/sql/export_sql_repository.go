@@ -0,0 +1,276 @@
+package sql
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+
+	"github.com/aasumitro/posbe/config"
+	"github.com/aasumitro/posbe/pkg/model"
+	"github.com/aasumitro/posbe/pkg/utils"
+)
+
+type (
+	exportColumn struct {
+		utils.ExportColumn
+		Expr string
+	}
+
+	exportSource struct {
+		Name string
+		From string
+		// Where filter the rows always, e.g: leave the soft deleted rows out
+		Where   string
+		GroupBy string
+		OrderBy string
+		// DateColumn used as range filter when from & to provided
+		DateColumn string
+		Columns    []exportColumn
+	}
+
+	ExportSQLRepository struct {
+		Db      *sql.DB
+		sources []*exportSource
+	}
+)
+
+var exportSources = []*exportSource{
+	{
+		Name: "products",
+		From: "products AS p JOIN categories AS c ON c.id = p.category_id " +
+			"JOIN subcategories AS s ON s.id = p.subcategory_id",
+		Where:      "p.deleted_at IS NULL",
+		OrderBy:    "p.id",
+		DateColumn: "p.created_at",
+		Columns: []exportColumn{
+			{utils.ExportColumn{Key: "id", Title: "ID", Kind: utils.ExportKindText}, "p.id"},
+			{utils.ExportColumn{Key: "sku", Title: "SKU", Kind: utils.ExportKindText}, "p.sku"},
+			{utils.ExportColumn{Key: "name", Title: "Name", Kind: utils.ExportKindText}, "p.name"},
+			{utils.ExportColumn{Key: "category", Title: "Category", Kind: utils.ExportKindText}, "c.name"},
+			{utils.ExportColumn{Key: "subcategory", Title: "Subcategory", Kind: utils.ExportKindText}, "s.name"},
+			{utils.ExportColumn{Key: "description", Title: "Description", Kind: utils.ExportKindText}, "p.description"},
+			{utils.ExportColumn{Key: "price", Title: "Price", Kind: utils.ExportKindMoney}, "p.price"},
+			{utils.ExportColumn{Key: "created_at", Title: "Created At", Kind: utils.ExportKindDate}, "p.created_at"},
+		},
+	},
+	{
+		Name: "product_variants",
+		From: "product_variants AS v JOIN products AS p ON p.id = v.product_id " +
+			"JOIN units AS u ON u.id = v.unit_id",
+		Where:      "v.deleted_at IS NULL AND p.deleted_at IS NULL",
+		OrderBy:    "v.id",
+		DateColumn: "v.created_at",
+		Columns: []exportColumn{
+			{utils.ExportColumn{Key: "id", Title: "ID", Kind: utils.ExportKindText}, "v.id"},
+			{utils.ExportColumn{Key: "product_sku", Title: "Product SKU", Kind: utils.ExportKindText}, "p.sku"},
+			{utils.ExportColumn{Key: "product_name", Title: "Product Name", Kind: utils.ExportKindText}, "p.name"},
+			{utils.ExportColumn{Key: "type", Title: "Type", Kind: utils.ExportKindText}, "v.type"},
+			{utils.ExportColumn{Key: "name", Title: "Name", Kind: utils.ExportKindText}, "v.name"},
+			{utils.ExportColumn{Key: "description", Title: "Description", Kind: utils.ExportKindText}, "v.description"},
+			{utils.ExportColumn{Key: "unit_size", Title: "Unit Size", Kind: utils.ExportKindDecimal}, "v.unit_size"},
+			{utils.ExportColumn{Key: "unit", Title: "Unit", Kind: utils.ExportKindText}, "u.symbol"},
+			{utils.ExportColumn{Key: "price", Title: "Price", Kind: utils.ExportKindMoney}, "v.price"},
+		},
+	},
+	{
+		Name:       "addons",
+		From:       "addons",
+		Where:      "addons.deleted_at IS NULL",
+		OrderBy:    "addons.id",
+		DateColumn: "addons.created_at",
+		Columns: []exportColumn{
+			{utils.ExportColumn{Key: "id", Title: "ID", Kind: utils.ExportKindText}, "addons.id"},
+			{utils.ExportColumn{Key: "name", Title: "Name", Kind: utils.ExportKindText}, "addons.name"},
+			{utils.ExportColumn{Key: "description", Title: "Description", Kind: utils.ExportKindText}, "addons.description"},
+			{utils.ExportColumn{Key: "price", Title: "Price", Kind: utils.ExportKindMoney}, "addons.price"},
+		},
+	},
+	{
+		Name:    "units",
+		From:    "units",
+		Where:   "units.deleted_at IS NULL",
+		OrderBy: "units.id",
+		Columns: []exportColumn{
+			{utils.ExportColumn{Key: "id", Title: "ID", Kind: utils.ExportKindText}, "units.id"},
+			{utils.ExportColumn{Key: "magnitude", Title: "Magnitude", Kind: utils.ExportKindText}, "units.magnitude"},
+			{utils.ExportColumn{Key: "name", Title: "Name", Kind: utils.ExportKindText}, "units.name"},
+			{utils.ExportColumn{Key: "symbol", Title: "Symbol", Kind: utils.ExportKindText}, "units.symbol"},
+		},
+	},
+	{
+		Name:       "users",
+		From:       "users AS u JOIN roles AS r ON r.id = u.role_id",
+		Where:      "u.deleted_at IS NULL",
+		OrderBy:    "u.id",
+		DateColumn: "u.created_at",
+		Columns: []exportColumn{
+			{utils.ExportColumn{Key: "id", Title: "ID", Kind: utils.ExportKindText}, "u.id"},
+			{utils.ExportColumn{Key: "name", Title: "Name", Kind: utils.ExportKindText}, "u.name"},
+			{utils.ExportColumn{Key: "username", Title: "Username", Kind: utils.ExportKindText}, "u.username"},
+			{utils.ExportColumn{Key: "email", Title: "Email", Kind: utils.ExportKindText}, "u.email"},
+			{utils.ExportColumn{Key: "phone", Title: "Phone", Kind: utils.ExportKindText}, "u.phone"},
+			{utils.ExportColumn{Key: "role", Title: "Role", Kind: utils.ExportKindText}, "r.name"},
+			{utils.ExportColumn{Key: "created_at", Title: "Created At", Kind: utils.ExportKindDate}, "u.created_at"},
+		},
+	},
+	{
+		Name:    "roles",
+		From:    "roles AS r LEFT OUTER JOIN users AS u ON u.role_id = r.id AND u.deleted_at IS NULL",
+		Where:   "r.deleted_at IS NULL",
+		GroupBy: "r.id",
+		OrderBy: "r.id",
+		Columns: []exportColumn{
+			{utils.ExportColumn{Key: "id", Title: "ID", Kind: utils.ExportKindText}, "r.id"},
+			{utils.ExportColumn{Key: "name", Title: "Name", Kind: utils.ExportKindText}, "r.name"},
+			{utils.ExportColumn{Key: "description", Title: "Description", Kind: utils.ExportKindText}, "r.description"},
+			{utils.ExportColumn{Key: "usage", Title: "Total Users", Kind: utils.ExportKindInteger}, "COUNT(u.id)"},
+		},
+	},
+	{
+		// one row per order, void orders are not sales and voided items
+		// are already left out of the total
+		Name: "sales",
+		From: "orders AS o LEFT OUTER JOIN tables AS t ON t.id = o.table_id " +
+			"LEFT OUTER JOIN rooms AS r ON r.id = o.room_id " +
+			"LEFT OUTER JOIN users AS u ON u.id = o.created_by",
+		Where:      "o.status <> 'void'",
+		OrderBy:    "o.created_at",
+		DateColumn: "o.created_at",
+		Columns: []exportColumn{
+			{utils.ExportColumn{Key: "id", Title: "ID", Kind: utils.ExportKindText}, "o.id"},
+			{utils.ExportColumn{Key: "created_at", Title: "Created At", Kind: utils.ExportKindDate}, "o.created_at"},
+			{utils.ExportColumn{Key: "status", Title: "Status", Kind: utils.ExportKindText}, "o.status"},
+			{utils.ExportColumn{Key: "table", Title: "Table", Kind: utils.ExportKindText}, "COALESCE(t.name, r.name)"},
+			{utils.ExportColumn{Key: "created_by", Title: "Created By", Kind: utils.ExportKindText}, "u.name"},
+			{utils.ExportColumn{Key: "quantity", Title: "Quantity", Kind: utils.ExportKindInteger},
+				"(SELECT COALESCE(SUM(oi.quantity), 0) FROM order_items AS oi " +
+					"WHERE oi.order_id = o.id AND oi.void_reason IS NULL)"},
+			{utils.ExportColumn{Key: "net", Title: "Net", Kind: utils.ExportKindMoney},
+				"(o.total - o.service_charge - o.tax)"},
+			{utils.ExportColumn{Key: "service_charge", Title: "Service Charge", Kind: utils.ExportKindMoney},
+				"o.service_charge"},
+			{utils.ExportColumn{Key: "tax", Title: "Tax", Kind: utils.ExportKindMoney}, "o.tax"},
+			{utils.ExportColumn{Key: "total", Title: "Total", Kind: utils.ExportKindMoney}, "o.total"},
+			{utils.ExportColumn{Key: "paid", Title: "Paid", Kind: utils.ExportKindMoney},
+				"(SELECT COALESCE(SUM(op.amount), 0) FROM order_payments AS op WHERE op.order_id = o.id)"},
+		},
+	},
+	{
+		Name: "shift_reports",
+		From: "store_shifts AS ss JOIN shifts AS s ON s.id = ss.shift_id " +
+			"LEFT OUTER JOIN users AS uo ON uo.id = ss.open_by " +
+			"LEFT OUTER JOIN users AS uc ON uc.id = ss.close_by",
+		OrderBy:    "ss.open_at",
+		DateColumn: "ss.open_at",
+		Columns: []exportColumn{
+			{utils.ExportColumn{Key: "id", Title: "ID", Kind: utils.ExportKindText}, "ss.id"},
+			{utils.ExportColumn{Key: "shift", Title: "Shift", Kind: utils.ExportKindText}, "s.name"},
+			{utils.ExportColumn{Key: "open_at", Title: "Open At", Kind: utils.ExportKindDate}, "ss.open_at"},
+			{utils.ExportColumn{Key: "open_by", Title: "Open By", Kind: utils.ExportKindText}, "uo.name"},
+			{utils.ExportColumn{Key: "open_cash", Title: "Open Cash", Kind: utils.ExportKindMoney}, "ss.open_cash"},
+			{utils.ExportColumn{Key: "close_at", Title: "Close At", Kind: utils.ExportKindDate}, "ss.close_at"},
+			{utils.ExportColumn{Key: "close_by", Title: "Close By", Kind: utils.ExportKindText}, "uc.name"},
+			{utils.ExportColumn{Key: "close_cash", Title: "Close Cash", Kind: utils.ExportKindMoney}, "ss.close_cash"},
+			{utils.ExportColumn{Key: "cash_difference", Title: "Cash Difference", Kind: utils.ExportKindMoney},
+				"(ss.close_cash - ss.open_cash)"},
+		},
+	},
+}
+
+func (repo ExportSQLRepository) Datasets() []*model.ExportDataset {
+	datasets := make([]*model.ExportDataset, len(repo.sources))
+	for i, source := range repo.sources {
+		columns := make([]utils.ExportColumn, len(source.Columns))
+		for j, column := range source.Columns {
+			columns[j] = column.ExportColumn
+		}
+		datasets[i] = &model.ExportDataset{
+			Name: source.Name, Columns: columns,
+			Ranged: source.DateColumn != "",
+		}
+	}
+	return datasets
+}
+
+// Stream run the export query and pass every row to the given callback,
+// rows is never collected so the memory usage stay flat for big table
+func (repo ExportSQLRepository) Stream(
+	ctx context.Context,
+	query *model.ExportQuery,
+	fn func(values []any) error,
+) error {
+	q, args, total := repo.buildQuery(query)
+	if total == 0 {
+		return sql.ErrNoRows
+	}
+	rows, err := repo.Db.QueryContext(ctx, q, args...)
+	if err != nil {
+		return err
+	}
+	defer func(rows *sql.Rows) { _ = rows.Close() }(rows)
+	values := make([]any, total)
+	dest := make([]any, total)
+	for i := range values {
+		dest[i] = &values[i]
+	}
+	for rows.Next() {
+		if err := rows.Scan(dest...); err != nil {
+			return err
+		}
+		if err := fn(values); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// buildQuery only use expression from the source definition,
+// unknown column key is ignored so user input never reach the query
+func (repo ExportSQLRepository) buildQuery(
+	query *model.ExportQuery,
+) (q string, args []any, total int) {
+	var source *exportSource
+	for _, s := range repo.sources {
+		if s.Name == query.Dataset {
+			source = s
+		}
+	}
+	if source == nil {
+		return "", nil, 0
+	}
+	exprs := make([]string, 0, len(query.Columns))
+	for _, key := range query.Columns {
+		for _, column := range source.Columns {
+			if column.Key == key {
+				exprs = append(exprs, column.Expr)
+			}
+		}
+	}
+	if len(exprs) == 0 {
+		return "", nil, 0
+	}
+	q = "SELECT " + strings.Join(exprs, ", ") + " FROM " + source.From
+	var conditions []string
+	if source.Where != "" {
+		conditions = append(conditions, source.Where)
+	}
+	if source.DateColumn != "" && query.From > 0 && query.To > 0 {
+		conditions = append(conditions, source.DateColumn+" BETWEEN $1 AND $2")
+		args = append(args, query.From, query.To)
+	}
+	if len(conditions) > 0 {
+		q += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	if source.GroupBy != "" {
+		q += " GROUP BY " + source.GroupBy
+	}
+	q += " ORDER BY " + source.OrderBy + " ASC"
+	return q, args, len(exprs)
+}
+
+func NewExportSQLRepository() model.IExportRepository {
+	return &ExportSQLRepository{
+		Db:      config.DbPool,
+		sources: exportSources,
+	}
+}
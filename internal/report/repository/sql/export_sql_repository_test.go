@@ -0,0 +1,225 @@
+package sql_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/aasumitro/posbe/config"
+	repoSql "github.com/aasumitro/posbe/internal/report/repository/sql"
+	transactionSql "github.com/aasumitro/posbe/internal/transaction/repository/sql"
+	"github.com/aasumitro/posbe/pkg/dbtest"
+	"github.com/aasumitro/posbe/pkg/dialect"
+	"github.com/aasumitro/posbe/pkg/model"
+	"github.com/aasumitro/posbe/pkg/money"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type exportRepositoryTestSuite struct {
+	suite.Suite
+	mock       sqlmock.Sqlmock
+	exportRepo model.IExportRepository
+}
+
+func (suite *exportRepositoryTestSuite) SetupSuite() {
+	var err error
+
+	config.DbPool, suite.mock, err = sqlmock.New(
+		sqlmock.QueryMatcherOption(
+			sqlmock.QueryMatcherRegexp))
+	require.NoError(suite.T(), err)
+
+	suite.exportRepo = repoSql.NewExportSQLRepository()
+}
+
+func (suite *exportRepositoryTestSuite) AfterTest(_, _ string) {
+	require.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+func (suite *exportRepositoryTestSuite) TestExportRepository_Datasets() {
+	datasets := suite.exportRepo.Datasets()
+	require.NotEmpty(suite.T(), datasets)
+	for _, dataset := range datasets {
+		require.NotEmpty(suite.T(), dataset.Columns)
+	}
+}
+
+func (suite *exportRepositoryTestSuite) TestExportRepository_Stream_ExpectedSuccess() {
+	rows := suite.mock.
+		NewRows([]string{"sku", "price"}).
+		AddRow("SKU-1", 1000.5).
+		AddRow("SKU-2", 2000)
+	q := "SELECT p.sku, p.price FROM products AS p JOIN categories AS c ON c.id = p.category_id " +
+		"JOIN subcategories AS s ON s.id = p.subcategory_id " +
+		"WHERE p.deleted_at IS NULL AND p.created_at BETWEEN $1 AND $2 ORDER BY p.id ASC"
+	suite.mock.ExpectQuery(regexp.QuoteMeta(q)).
+		WithArgs(int64(1), int64(2)).
+		WillReturnRows(rows)
+	var result [][]any
+	err := suite.exportRepo.Stream(context.TODO(), &model.ExportQuery{
+		Dataset: "products",
+		Columns: []string{"sku", "price", "unknown"},
+		From:    1, To: 2,
+	}, func(values []any) error {
+		result = append(result, append([]any{}, values...))
+		return nil
+	})
+	require.NoError(suite.T(), err)
+	require.Len(suite.T(), result, 2)
+	require.Equal(suite.T(), "SKU-1", result[0][0])
+}
+
+func (suite *exportRepositoryTestSuite) TestExportRepository_Stream_ExpectedGroupedQuery() {
+	rows := suite.mock.
+		NewRows([]string{"name", "usage"}).
+		AddRow("admin", 1)
+	q := "SELECT r.name, COUNT(u.id) FROM roles AS r " +
+		"LEFT OUTER JOIN users AS u ON u.role_id = r.id AND u.deleted_at IS NULL " +
+		"WHERE r.deleted_at IS NULL GROUP BY r.id ORDER BY r.id ASC"
+	suite.mock.ExpectQuery(regexp.QuoteMeta(q)).WillReturnRows(rows)
+	err := suite.exportRepo.Stream(context.TODO(), &model.ExportQuery{
+		Dataset: "roles",
+		Columns: []string{"name", "usage"},
+	}, func(values []any) error { return nil })
+	require.NoError(suite.T(), err)
+}
+
+func (suite *exportRepositoryTestSuite) TestExportRepository_Stream_ExpectedErrorUnknownDataset() {
+	err := suite.exportRepo.Stream(context.TODO(), &model.ExportQuery{
+		Dataset: "lorem",
+		Columns: []string{"id"},
+	}, func(values []any) error { return nil })
+	require.ErrorIs(suite.T(), err, sql.ErrNoRows)
+}
+
+func (suite *exportRepositoryTestSuite) TestExportRepository_Stream_ExpectedErrorQuery() {
+	q := "SELECT units.id FROM units WHERE units.deleted_at IS NULL ORDER BY units.id ASC"
+	suite.mock.ExpectQuery(regexp.QuoteMeta(q)).
+		WillReturnError(errors.New("UNEXPECTED"))
+	err := suite.exportRepo.Stream(context.TODO(), &model.ExportQuery{
+		Dataset: "units",
+		Columns: []string{"id"},
+	}, func(values []any) error { return nil })
+	require.EqualError(suite.T(), err, "UNEXPECTED")
+}
+
+func (suite *exportRepositoryTestSuite) TestExportRepository_Stream_ExpectedErrorCallback() {
+	rows := suite.mock.
+		NewRows([]string{"id"}).
+		AddRow(1)
+	q := "SELECT units.id FROM units WHERE units.deleted_at IS NULL ORDER BY units.id ASC"
+	suite.mock.ExpectQuery(regexp.QuoteMeta(q)).WillReturnRows(rows)
+	err := suite.exportRepo.Stream(context.TODO(), &model.ExportQuery{
+		Dataset: "units",
+		Columns: []string{"id"},
+	}, func(values []any) error { return errors.New("UNEXPECTED") })
+	require.EqualError(suite.T(), err, "UNEXPECTED")
+}
+
+// stream export every column of the dataset with repo
+func (suite *exportRepositoryTestSuite) stream(repo model.IExportRepository, dataset string, from, to int64) (rows [][]any) {
+	for _, d := range repo.Datasets() {
+		if d.Name != dataset {
+			continue
+		}
+		query := &model.ExportQuery{Dataset: dataset, From: from, To: to}
+		for _, column := range d.Columns {
+			query.Columns = append(query.Columns, column.Key)
+		}
+		require.NoError(suite.T(), repo.Stream(context.TODO(), query, func(values []any) error {
+			rows = append(rows, append([]any{}, values...))
+			return nil
+		}))
+	}
+	return rows
+}
+
+func (suite *exportRepositoryTestSuite) TestExportRepository_Stream() {
+	dbtest.Migrated(suite.T(), config.Dialect.Name())
+	repo := repoSql.NewExportSQLRepository()
+	for _, dataset := range repo.Datasets() {
+		suite.stream(repo, dataset.Name, 0, 0)
+		if dataset.Ranged {
+			suite.stream(repo, dataset.Name, 1, 1<<40)
+		}
+	}
+	require.Len(suite.T(), suite.stream(repo, "products", 0, 0), 2)
+	require.Len(suite.T(), suite.stream(repo, "roles", 0, 0), 3)
+}
+
+func (suite *exportRepositoryTestSuite) TestExportRepository_Stream_ShouldLeaveDeletedRowsOut() {
+	db := dbtest.Migrated(suite.T(), config.Dialect.Name())
+	repo := repoSql.NewExportSQLRepository()
+	products, variants := len(suite.stream(repo, "products", 0, 0)), len(suite.stream(repo, "product_variants", 0, 0))
+	addons, units := len(suite.stream(repo, "addons", 0, 0)), len(suite.stream(repo, "units", 0, 0))
+	users := len(suite.stream(repo, "users", 0, 0))
+	for _, q := range []string{
+		// product 2 has 2 variants
+		"UPDATE products SET deleted_at = 1 WHERE id = 2",
+		"UPDATE addons SET deleted_at = 1 WHERE id = 4",
+		"UPDATE units SET deleted_at = 1 WHERE id = 5",
+		// the only waiter
+		"UPDATE users SET deleted_at = 1 WHERE id = 3",
+		"UPDATE roles SET deleted_at = 1 WHERE id = 2",
+	} {
+		_, err := db.ExecContext(context.TODO(), q)
+		require.NoError(suite.T(), err)
+	}
+	require.Len(suite.T(), suite.stream(repo, "products", 0, 0), products-1)
+	require.Len(suite.T(), suite.stream(repo, "product_variants", 0, 0), variants-2)
+	require.Len(suite.T(), suite.stream(repo, "addons", 0, 0), addons-1)
+	require.Len(suite.T(), suite.stream(repo, "units", 0, 0), units-1)
+	require.Len(suite.T(), suite.stream(repo, "users", 0, 0), users-1)
+	roles := suite.stream(repo, "roles", 0, 0)
+	require.Len(suite.T(), roles, 2)
+	// the deleted waiter is not counted
+	require.Equal(suite.T(), "waiter", roles[1][1])
+	require.EqualValues(suite.T(), 0, roles[1][3])
+}
+
+func (suite *exportRepositoryTestSuite) TestExportRepository_Stream_Sales() {
+	dbtest.Migrated(suite.T(), config.Dialect.Name())
+	repo := repoSql.NewExportSQLRepository()
+	ctx := context.TODO()
+	orders := transactionSql.NewOrderSQLRepository()
+	for _, order := range []*model.Order{
+		{ID: "6f1c2d3e-4b5a-4c6d-8e7f-901234567890", TableID: 1, Status: model.OrderStatusPaid,
+			Total: 3450, ServiceCharge: 150, Tax: 300, CreatedBy: 1, CreatedAt: 100,
+			Items: []*model.OrderItem{
+				{ID: "0a0a0a0a-0000-4000-8000-00000000000a", ProductID: 1, Name: "lorem", Quantity: 2, Price: 1500},
+				{ID: "0b0b0b0b-0000-4000-8000-00000000000b", ProductID: 2, Name: "ipsum", Quantity: 1, Price: 2000,
+					VoidReason: model.SyncConflictSoldOut},
+			}},
+		{ID: "7f1c2d3e-4b5a-4c6d-8e7f-901234567890", TableID: 1, Status: model.OrderStatusVoid,
+			Total: 1500, CreatedBy: 1, CreatedAt: 200},
+	} {
+		order.Currency, order.Version, order.Checksum = money.DefaultCurrency, 1, "lorem"
+		_, err := orders.Save(ctx, order)
+		require.NoError(suite.T(), err)
+	}
+	_, err := orders.AddPayments(ctx, "6f1c2d3e-4b5a-4c6d-8e7f-901234567890", []*model.OrderPayment{
+		{ID: "0c0c0c0c-0000-4000-8000-00000000000c", Method: "cash", Amount: 3450, CreatedAt: 110}})
+	require.NoError(suite.T(), err)
+
+	// the void order is left out
+	sales := suite.stream(repo, "sales", 0, 0)
+	require.Len(suite.T(), sales, 1)
+	require.Equal(suite.T(), "A1", sales[0][3])
+	// id, created at, status, table, created by, quantity, net, service charge, tax, total, paid
+	require.EqualValues(suite.T(), []any{int64(2), int64(3000), int64(150), int64(300), int64(3450),
+		int64(3450)}, sales[0][5:])
+	require.Empty(suite.T(), suite.stream(repo, "sales", 101, 1<<40))
+}
+
+func TestExportRepository(t *testing.T) {
+	for _, d := range dialect.All() {
+		t.Run(d.Name(), func(t *testing.T) {
+			config.Dialect = d
+			suite.Run(t, new(exportRepositoryTestSuite))
+		})
+	}
+}
@@ -0,0 +1,160 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/aasumitro/posbe/pkg/model"
+	"github.com/aasumitro/posbe/pkg/utils"
+)
+
+type exportService struct {
+	exportRepo model.IExportRepository
+	prefRepo   model.IStorePrefRepository
+}
+
+func (service exportService) DatasetList(
+	_ context.Context,
+) (datasets []*model.ExportDataset, errData *utils.ServiceError) {
+	return service.exportRepo.Datasets(), nil
+}
+
+func (service exportService) PrepareExport(
+	ctx context.Context,
+	dataset string,
+	form *model.ExportForm,
+) (export *model.Export, errData *utils.ServiceError) {
+	source := service.findDataset(dataset)
+	if source == nil {
+		return nil, &utils.ServiceError{
+			Code:    http.StatusNotFound,
+			Message: fmt.Sprintf("export dataset %s not found", dataset),
+		}
+	}
+	format := utils.ExportFormat(strings.ToLower(form.Format))
+	if format == "" {
+		format = utils.ExportFormatCSV
+	}
+	if !slices.Contains([]utils.ExportFormat{
+		utils.ExportFormatCSV, utils.ExportFormatXLSX,
+	}, format) {
+		return nil, &utils.ServiceError{
+			Code:    http.StatusBadRequest,
+			Message: utils.ErrorExportUnsupportedFormat.Error(),
+		}
+	}
+	columns, err := selectColumns(source, form.Columns)
+	if err != nil {
+		return nil, &utils.ServiceError{
+			Code:    http.StatusBadRequest,
+			Message: err.Error(),
+		}
+	}
+	if form.From > 0 && form.To > 0 && form.From > form.To {
+		return nil, &utils.ServiceError{
+			Code:    http.StatusBadRequest,
+			Message: "export range from must be before to",
+		}
+	}
+	locale := service.locale(ctx, form)
+	query := &model.ExportQuery{
+		Dataset: source.Name,
+		From:    form.From,
+		To:      form.To,
+	}
+	for _, column := range columns {
+		query.Columns = append(query.Columns, column.Key)
+	}
+	return &model.Export{
+		Filename: fmt.Sprintf("%s_%s.%s", source.Name,
+			time.Now().In(locale.Location).Format("20060102150405"), format),
+		ContentType: format.ContentType(),
+		WriteTo: func(ctx context.Context, out io.Writer) error {
+			writer, err := utils.NewExportWriter(format, out, locale)
+			if err != nil {
+				return err
+			}
+			if err := writer.WriteHeader(columns); err != nil {
+				return err
+			}
+			if err := service.exportRepo.Stream(ctx, query, writer.WriteRow); err != nil {
+				return err
+			}
+			return writer.Close()
+		},
+	}, nil
+}
+
+func (service exportService) findDataset(name string) *model.ExportDataset {
+	for _, dataset := range service.exportRepo.Datasets() {
+		if dataset.Name == name {
+			return dataset
+		}
+	}
+	return nil
+}
+
+// locale prefer the requested lang & tz,
+// otherwise fall back to the store preferences
+func (service exportService) locale(
+	ctx context.Context,
+	form *model.ExportForm,
+) *utils.ExportLocale {
+	lang, timezone := form.Lang, form.Timezone
+	if lang == "" || timezone == "" {
+		if prefs, err := service.prefRepo.All(ctx); err == nil && prefs != nil {
+			setting := *prefs
+			if v, ok := setting["fe_lang"].(string); ok && lang == "" {
+				lang = v
+			}
+			if v, ok := setting["fe_locale"].(string); ok && timezone == "" {
+				timezone = v
+			}
+		}
+	}
+	return utils.NewExportLocale(lang, timezone)
+}
+
+// selectColumns keep the dataset order,
+// empty selection will export all available columns
+func selectColumns(
+	dataset *model.ExportDataset,
+	selection string,
+) ([]utils.ExportColumn, error) {
+	if strings.TrimSpace(selection) == "" {
+		return dataset.Columns, nil
+	}
+	keys := strings.Split(selection, ",")
+	for i := range keys {
+		keys[i] = strings.TrimSpace(keys[i])
+	}
+	for _, key := range keys {
+		if !slices.ContainsFunc(dataset.Columns, func(c utils.ExportColumn) bool {
+			return c.Key == key
+		}) {
+			return nil, fmt.Errorf("unknown column %s for %s", key, dataset.Name)
+		}
+	}
+	var columns []utils.ExportColumn
+	for _, column := range dataset.Columns {
+		if slices.Contains(keys, column.Key) {
+			columns = append(columns, column)
+		}
+	}
+	return columns, nil
+}
+
+func NewExportService(
+	exportRepo model.IExportRepository,
+	prefRepo model.IStorePrefRepository,
+) model.IExportService {
+	return &exportService{
+		exportRepo: exportRepo,
+		prefRepo:   prefRepo,
+	}
+}
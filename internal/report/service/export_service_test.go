@@ -0,0 +1,128 @@
+package service_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/aasumitro/posbe/internal/report/service"
+	"github.com/aasumitro/posbe/mocks"
+	"github.com/aasumitro/posbe/pkg/model"
+	"github.com/aasumitro/posbe/pkg/utils"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type exportTestSuite struct {
+	suite.Suite
+	datasets []*model.ExportDataset
+}
+
+func (suite *exportTestSuite) SetupSuite() {
+	suite.datasets = []*model.ExportDataset{
+		{
+			Name: "products",
+			Columns: []utils.ExportColumn{
+				{Key: "sku", Title: "SKU", Kind: utils.ExportKindText},
+				{Key: "name", Title: "Name", Kind: utils.ExportKindText},
+				{Key: "price", Title: "Price", Kind: utils.ExportKindDecimal},
+			},
+			Ranged: true,
+		},
+	}
+}
+
+func (suite *exportTestSuite) TestExportService_DatasetList_ShouldSuccess() {
+	repo := new(mocks.IExportRepository)
+	svc := service.NewExportService(repo, new(mocks.IStorePrefRepository))
+	repo.On("Datasets").Once().Return(suite.datasets)
+	data, err := svc.DatasetList(context.TODO())
+	require.Nil(suite.T(), err)
+	require.Equal(suite.T(), suite.datasets, data)
+	repo.AssertExpectations(suite.T())
+}
+
+func (suite *exportTestSuite) TestExportService_PrepareExport_ShouldSuccess() {
+	repo := new(mocks.IExportRepository)
+	prefRepo := new(mocks.IStorePrefRepository)
+	svc := service.NewExportService(repo, prefRepo)
+	repo.On("Datasets").Once().Return(suite.datasets)
+	prefRepo.
+		On("All", mock.Anything).
+		Once().
+		Return(&model.StoreSetting{"fe_lang": "id_ID", "fe_locale": "Asia/Makassar"}, nil)
+	repo.
+		On("Stream", mock.Anything, mock.MatchedBy(func(q *model.ExportQuery) bool {
+			return q.Dataset == "products" && strings.Join(q.Columns, ",") == "sku,price"
+		}), mock.Anything).
+		Once().
+		Run(func(args mock.Arguments) {
+			fn := args.Get(2).(func([]any) error)
+			_ = fn([]any{"SKU-1", 12500.5})
+		}).
+		Return(nil)
+	export, err := svc.PrepareExport(context.TODO(), "products", &model.ExportForm{
+		Columns: "price, sku",
+	})
+	require.Nil(suite.T(), err)
+	require.Equal(suite.T(), "text/csv", export.ContentType)
+	require.True(suite.T(), strings.HasPrefix(export.Filename, "products_"))
+	require.True(suite.T(), strings.HasSuffix(export.Filename, ".csv"))
+	var out bytes.Buffer
+	require.NoError(suite.T(), export.WriteTo(context.TODO(), &out))
+	require.Equal(suite.T(), "SKU,Price\nSKU-1,\"12.500,50\"\n", out.String())
+	repo.AssertExpectations(suite.T())
+	prefRepo.AssertExpectations(suite.T())
+}
+
+func (suite *exportTestSuite) TestExportService_PrepareExport_ShouldErrorWhenStream() {
+	repo := new(mocks.IExportRepository)
+	prefRepo := new(mocks.IStorePrefRepository)
+	svc := service.NewExportService(repo, prefRepo)
+	repo.On("Datasets").Once().Return(suite.datasets)
+	repo.
+		On("Stream", mock.Anything, mock.Anything, mock.Anything).
+		Once().
+		Return(errors.New("UNEXPECTED"))
+	export, err := svc.PrepareExport(context.TODO(), "products", &model.ExportForm{
+		Format: "xlsx", Lang: "en_US", Timezone: "UTC",
+	})
+	require.Nil(suite.T(), err)
+	require.Equal(suite.T(), utils.ExportFormatXLSX.ContentType(), export.ContentType)
+	require.EqualError(suite.T(), export.WriteTo(context.TODO(), &bytes.Buffer{}), "UNEXPECTED")
+	repo.AssertExpectations(suite.T())
+}
+
+func (suite *exportTestSuite) TestExportService_PrepareExport_ShouldErrorNotFound() {
+	repo := new(mocks.IExportRepository)
+	svc := service.NewExportService(repo, new(mocks.IStorePrefRepository))
+	repo.On("Datasets").Once().Return(suite.datasets)
+	export, err := svc.PrepareExport(context.TODO(), "lorem", &model.ExportForm{})
+	require.Nil(suite.T(), export)
+	require.Equal(suite.T(), http.StatusNotFound, err.Code)
+	repo.AssertExpectations(suite.T())
+}
+
+func (suite *exportTestSuite) TestExportService_PrepareExport_ShouldErrorBadRequest() {
+	for _, form := range []*model.ExportForm{
+		{Format: "pdf"},
+		{Columns: "sku,lorem"},
+		{From: 2, To: 1},
+	} {
+		repo := new(mocks.IExportRepository)
+		svc := service.NewExportService(repo, new(mocks.IStorePrefRepository))
+		repo.On("Datasets").Once().Return(suite.datasets)
+		export, err := svc.PrepareExport(context.TODO(), "products", form)
+		require.Nil(suite.T(), export)
+		require.Equal(suite.T(), http.StatusBadRequest, err.Code)
+		repo.AssertExpectations(suite.T())
+	}
+}
+
+func TestExportService(t *testing.T) {
+	suite.Run(t, new(exportTestSuite))
+}
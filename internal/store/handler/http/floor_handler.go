@@ -6,6 +6,7 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/aasumitro/posbe/pkg/http/middleware"
 	"github.com/aasumitro/posbe/pkg/model"
 	"github.com/aasumitro/posbe/pkg/utils"
 	"github.com/gin-gonic/gin"
@@ -145,9 +146,13 @@ func (handler floorHandler) update(ctx *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param id path int true "floor id"
+// @Param reassign_to query int false "floor id that take over the tables and rooms"
 // @Success 204 "NO CONTENT RESPOND"
 // @Failure 400 {object} utils.ErrorRespond "BAD REQUEST RESPOND"
 // @Failure 401 {object} utils.ErrorRespond "UNAUTHORIZED RESPOND"
+// @Failure 404 {object} utils.ErrorRespond "NOT FOUND RESPOND"
+// @Failure 409 {object} utils.ValidationErrorRespond{data=model.DeleteConflict} "CONFLICT RESPOND"
+// @Failure 422 {object} utils.ValidationErrorRespond "UNPROCESSABLE ENTITY RESPOND"
 // @Failure 500 {object} utils.ErrorRespond "INTERNAL SERVER ERROR RESPOND"
 // @Router /api/v1/floors/{id} [DELETE]
 func (handler floorHandler) destroy(ctx *gin.Context) {
@@ -168,11 +173,47 @@ func (handler floorHandler) destroy(ctx *gin.Context) {
 	utils.NewHTTPRespond(ctx, http.StatusNoContent, nil)
 }
 
+// floors godoc
+// @Schemes
+// @Summary Restore Floor Data
+// @Description Restore deleted floor by ID.
+// @Tags Floors
+// @Accept json
+// @Produce json
+// @Param id path int true "floor id"
+// @Success 200 {object} utils.SuccessRespond{data=model.Floor} "OK RESPOND"
+// @Failure 400 {object} utils.ErrorRespond "BAD REQUEST RESPOND"
+// @Failure 401 {object} utils.ErrorRespond "UNAUTHORIZED RESPOND"
+// @Failure 403 {object} utils.ErrorRespond "FORBIDDEN RESPOND"
+// @Failure 404 {object} utils.ErrorRespond "NOT FOUND RESPOND"
+// @Failure 500 {object} utils.ErrorRespond "INTERNAL SERVER ERROR RESPOND"
+// @Router /api/v1/floors/{id}/restore [PATCH]
+func (handler floorHandler) restore(ctx *gin.Context) {
+	idParams := ctx.Param("id")
+	id, errParse := strconv.Atoi(idParams)
+	if errParse != nil {
+		utils.NewHTTPRespond(ctx,
+			http.StatusBadRequest,
+			errParse.Error())
+		return
+	}
+	data, err := handler.svc.RestoreFloor(ctx, &model.Floor{ID: id})
+	if err != nil {
+		utils.NewHTTPRespond(ctx, err.Code, err.Message)
+		return
+	}
+	utils.NewHTTPRespond(ctx, http.StatusOK, data)
+}
+
 func NewFloorHandler(svc model.IStoreService, router gin.IRoutes) {
 	handler := floorHandler{svc: svc}
-	router.GET("/floors/:join", handler.floorsWith)
-	router.GET("/floors", handler.fetch)
-	router.POST("/floors", handler.store)
-	router.PUT("/floors/:id", handler.update)
-	router.DELETE("/floors/:id", handler.destroy)
+	read := middleware.Permitted(model.PermissionStoreRead)
+	write := middleware.Permitted(model.PermissionStoreWrite)
+	trash := middleware.Permitted(model.PermissionTrashRestore)
+	router.GET("/floors/:join", read, handler.floorsWith)
+	router.GET("/floors", read, handler.fetch)
+	router.POST("/floors", write, handler.store)
+	router.PUT("/floors/:id", write, handler.update)
+	router.DELETE("/floors/:id", write, handler.destroy)
+	router.PATCH("/floors/:id/restore", trash, handler.restore)
 }
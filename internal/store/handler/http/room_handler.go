@@ -4,6 +4,7 @@ import (
 	"net/http"
 	"strconv"
 
+	"github.com/aasumitro/posbe/pkg/http/middleware"
 	"github.com/aasumitro/posbe/pkg/model"
 	"github.com/aasumitro/posbe/pkg/utils"
 	"github.com/gin-gonic/gin"
@@ -148,10 +149,46 @@ func (handler roomHandler) destroy(ctx *gin.Context) {
 	utils.NewHTTPRespond(ctx, http.StatusNoContent, nil)
 }
 
+// rooms godoc
+// @Schemes
+// @Summary Restore Room Data
+// @Description Restore deleted room by ID.
+// @Tags Rooms
+// @Accept json
+// @Produce json
+// @Param id path int true "room id"
+// @Success 200 {object} utils.SuccessRespond{data=model.Room} "OK RESPOND"
+// @Failure 400 {object} utils.ErrorRespond "BAD REQUEST RESPOND"
+// @Failure 401 {object} utils.ErrorRespond "UNAUTHORIZED RESPOND"
+// @Failure 403 {object} utils.ErrorRespond "FORBIDDEN RESPOND"
+// @Failure 404 {object} utils.ErrorRespond "NOT FOUND RESPOND"
+// @Failure 500 {object} utils.ErrorRespond "INTERNAL SERVER ERROR RESPOND"
+// @Router /api/v1/rooms/{id}/restore [PATCH]
+func (handler roomHandler) restore(ctx *gin.Context) {
+	idParams := ctx.Param("id")
+	id, errParse := strconv.Atoi(idParams)
+	if errParse != nil {
+		utils.NewHTTPRespond(ctx,
+			http.StatusBadRequest,
+			errParse.Error())
+		return
+	}
+	data, err := handler.svc.RestoreRoom(ctx, &model.Room{ID: id})
+	if err != nil {
+		utils.NewHTTPRespond(ctx, err.Code, err.Message)
+		return
+	}
+	utils.NewHTTPRespond(ctx, http.StatusOK, data)
+}
+
 func NewRoomHandler(svc model.IStoreService, router gin.IRoutes) {
 	handler := roomHandler{svc: svc}
-	router.GET("/rooms", handler.fetch)
-	router.POST("/rooms", handler.store)
-	router.PUT("/rooms/:id", handler.update)
-	router.DELETE("/rooms/:id", handler.destroy)
+	read := middleware.Permitted(model.PermissionStoreRead)
+	write := middleware.Permitted(model.PermissionStoreWrite)
+	trash := middleware.Permitted(model.PermissionTrashRestore)
+	router.GET("/rooms", read, handler.fetch)
+	router.POST("/rooms", write, handler.store)
+	router.PUT("/rooms/:id", write, handler.update)
+	router.DELETE("/rooms/:id", write, handler.destroy)
+	router.PATCH("/rooms/:id/restore", trash, handler.restore)
 }
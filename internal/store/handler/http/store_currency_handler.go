@@ -0,0 +1,96 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/aasumitro/posbe/pkg/http/middleware"
+	"github.com/aasumitro/posbe/pkg/model"
+	"github.com/aasumitro/posbe/pkg/utils"
+	"github.com/gin-gonic/gin"
+)
+
+type storeCurrencyHandler struct {
+	svc model.IStoreCurrencyService
+}
+
+// store godoc
+// @Schemes
+// @Summary Store Currency
+// @Description Get store currency with its display format and the rate in effect of every foreign currency.
+// @Tags Store
+// @Accept json
+// @Produce json
+// @Success 200 {object} utils.SuccessRespond{data=model.StoreCurrency} "OK RESPOND"
+// @Failure 401 {object} utils.ErrorRespond "UNAUTHORIZED RESPOND"
+// @Failure 500 {object} utils.ErrorRespond "INTERNAL SERVER ERROR RESPOND"
+// @Router /api/v1/store/currency [GET]
+func (handler storeCurrencyHandler) currency(ctx *gin.Context) {
+	data, err := handler.svc.Currency(ctx)
+	if err != nil {
+		utils.NewHTTPRespond(ctx, err.Code, err.Message)
+		return
+	}
+	utils.NewHTTPRespond(ctx, http.StatusOK, data)
+}
+
+// store godoc
+// @Schemes
+// @Summary Currency Rate History
+// @Description Get every rate of a currency, latest first.
+// @Tags Store
+// @Accept json
+// @Produce json
+// @Param currency query string true "ISO 4217 code"
+// @Success 200 {object} utils.SuccessRespond{data=[]model.CurrencyRate} "OK RESPOND"
+// @Failure 401 {object} utils.ErrorRespond "UNAUTHORIZED RESPOND"
+// @Failure 500 {object} utils.ErrorRespond "INTERNAL SERVER ERROR RESPOND"
+// @Router /api/v1/store/currency-rates [GET]
+func (handler storeCurrencyHandler) rates(ctx *gin.Context) {
+	data, err := handler.svc.RateHistory(ctx, ctx.Query("currency"))
+	if err != nil {
+		utils.NewHTTPRespond(ctx, err.Code, err.Message)
+		return
+	}
+	utils.NewHTTPRespond(ctx, http.StatusOK, data)
+}
+
+// store godoc
+// @Schemes
+// @Summary Add Currency Rate
+// @Description Add new rate of a foreign currency, the previous rate is kept for past conversions.
+// @Tags Store
+// @Accept json
+// @Produce json
+// @Param body body model.CurrencyRate true "currency and rate to the store currency"
+// @Success 201 {object} utils.SuccessRespond{data=model.CurrencyRate} "CREATED RESPOND"
+// @Failure 401 {object} utils.ErrorRespond "UNAUTHORIZED RESPOND"
+// @Failure 422 {object} utils.ValidationErrorRespond "UNPROCESSABLE ENTITY RESPOND"
+// @Failure 500 {object} utils.ErrorRespond "INTERNAL SERVER ERROR RESPOND"
+// @Router /api/v1/store/currency-rates [POST]
+func (handler storeCurrencyHandler) addRate(ctx *gin.Context) {
+	var form model.CurrencyRate
+	if err := ctx.ShouldBindJSON(&form); err != nil {
+		utils.NewHTTPRespond(ctx,
+			http.StatusUnprocessableEntity,
+			err.Error())
+		return
+	}
+	form.CreatedBy = middleware.PayloadUserID(ctx)
+
+	data, err := handler.svc.AddRate(ctx, &form)
+	if err != nil {
+		utils.NewHTTPRespond(ctx, err.Code, err.Message)
+		return
+	}
+
+	utils.NewHTTPRespond(ctx, http.StatusCreated, data)
+}
+
+func NewStoreCurrencyHandler(svc model.IStoreCurrencyService, router gin.IRoutes) {
+	handler := storeCurrencyHandler{svc: svc}
+	read := middleware.Permitted(model.PermissionStoreRead)
+	write := middleware.Permitted(model.PermissionStoreWrite)
+	router.GET("/store/currency", read, handler.currency)
+	router.GET("/store/currency-rates", read, handler.rates)
+	router.POST("/store/currency-rates", write, handler.addRate)
+}
@@ -3,6 +3,7 @@ package http
 import (
 	"net/http"
 
+	"github.com/aasumitro/posbe/pkg/http/middleware"
 	"github.com/aasumitro/posbe/pkg/model"
 	"github.com/aasumitro/posbe/pkg/utils"
 	"github.com/gin-gonic/gin"
@@ -62,8 +63,40 @@ func (handler storePrefHandler) update(ctx *gin.Context) {
 	utils.NewHTTPRespond(ctx, http.StatusOK, pref)
 }
 
+// store godoc
+// @Schemes
+// @Summary Upload Store Logo
+// @Description Upload store logo and update the logo setting.
+// @Tags Store
+// @Accept mpfd
+// @Produce json
+// @Param file formData file true "jpeg, png, gif or webp image (max 5MB)"
+// @Success 200 {object} utils.SuccessRespond{data=model.StoreSetting} "OK RESPOND"
+// @Failure 400 {object} utils.ErrorRespond "BAD REQUEST RESPOND"
+// @Failure 401 {object} utils.ErrorRespond "UNAUTHORIZED RESPOND"
+// @Failure 413 {object} utils.ErrorRespond "REQUEST ENTITY TOO LARGE RESPOND"
+// @Failure 415 {object} utils.ErrorRespond "UNSUPPORTED MEDIA TYPE RESPOND"
+// @Failure 500 {object} utils.ErrorRespond "INTERNAL SERVER ERROR RESPOND"
+// @Router  /api/v1/store/logo [POST]
+func (handler storePrefHandler) logo(ctx *gin.Context) {
+	upload, err := utils.ReadUploadImage(ctx, "file")
+	if err != nil {
+		utils.NewHTTPRespond(ctx, err.Code, err.Message)
+		return
+	}
+	pref, err := handler.svc.UploadLogo(ctx, upload)
+	if err != nil {
+		utils.NewHTTPRespond(ctx, err.Code, err.Message)
+		return
+	}
+	utils.NewHTTPRespond(ctx, http.StatusOK, pref)
+}
+
 func NewStorePrefHandler(svc model.IStorePrefService, router gin.IRoutes) {
 	handler := storePrefHandler{svc: svc}
-	router.GET("/store/prefs", handler.fetch)
-	router.PUT("/store/prefs", handler.update)
+	read := middleware.Permitted(model.PermissionStoreRead)
+	write := middleware.Permitted(model.PermissionStoreWrite)
+	router.GET("/store/prefs", read, handler.fetch)
+	router.PUT("/store/prefs", write, handler.update)
+	router.POST("/store/logo", write, handler.logo)
 }
@@ -4,6 +4,7 @@ import (
 	"net/http"
 	"strconv"
 
+	"github.com/aasumitro/posbe/pkg/http/middleware"
 	"github.com/aasumitro/posbe/pkg/model"
 	"github.com/aasumitro/posbe/pkg/utils"
 	"github.com/gin-gonic/gin"
@@ -147,10 +148,46 @@ func (handler tableHandler) destroy(ctx *gin.Context) {
 	utils.NewHTTPRespond(ctx, http.StatusNoContent, nil)
 }
 
+// tables godoc
+// @Schemes
+// @Summary Restore Table Data
+// @Description Restore deleted table by ID.
+// @Tags Tables
+// @Accept json
+// @Produce json
+// @Param id path int true "table id"
+// @Success 200 {object} utils.SuccessRespond{data=model.Table} "OK RESPOND"
+// @Failure 400 {object} utils.ErrorRespond "BAD REQUEST RESPOND"
+// @Failure 401 {object} utils.ErrorRespond "UNAUTHORIZED RESPOND"
+// @Failure 403 {object} utils.ErrorRespond "FORBIDDEN RESPOND"
+// @Failure 404 {object} utils.ErrorRespond "NOT FOUND RESPOND"
+// @Failure 500 {object} utils.ErrorRespond "INTERNAL SERVER ERROR RESPOND"
+// @Router /api/v1/tables/{id}/restore [PATCH]
+func (handler tableHandler) restore(ctx *gin.Context) {
+	idParams := ctx.Param("id")
+	id, errParse := strconv.Atoi(idParams)
+	if errParse != nil {
+		utils.NewHTTPRespond(ctx,
+			http.StatusBadRequest,
+			errParse.Error())
+		return
+	}
+	data, err := handler.svc.RestoreTable(ctx, &model.Table{ID: id})
+	if err != nil {
+		utils.NewHTTPRespond(ctx, err.Code, err.Message)
+		return
+	}
+	utils.NewHTTPRespond(ctx, http.StatusOK, data)
+}
+
 func NewTableHandler(svc model.IStoreService, router gin.IRoutes) {
 	handler := tableHandler{svc: svc}
-	router.GET("/tables", handler.fetch)
-	router.POST("/tables", handler.store)
-	router.PUT("/tables/:id", handler.update)
-	router.DELETE("/tables/:id", handler.destroy)
+	read := middleware.Permitted(model.PermissionStoreRead)
+	write := middleware.Permitted(model.PermissionStoreWrite)
+	trash := middleware.Permitted(model.PermissionTrashRestore)
+	router.GET("/tables", read, handler.fetch)
+	router.POST("/tables", write, handler.store)
+	router.PUT("/tables/:id", write, handler.update)
+	router.DELETE("/tables/:id", write, handler.destroy)
+	router.PATCH("/tables/:id/restore", trash, handler.restore)
 }
@@ -2,73 +2,91 @@ package store
 
 import (
 	"context"
-	"encoding/json"
-	"errors"
 	"fmt"
+	"log"
 	"strconv"
 
+	"github.com/aasumitro/posbe/common"
 	"github.com/aasumitro/posbe/config"
 	"github.com/aasumitro/posbe/internal/store/handler/http"
 	repository "github.com/aasumitro/posbe/internal/store/repository/sql"
 	"github.com/aasumitro/posbe/internal/store/service"
+	"github.com/aasumitro/posbe/pkg/audit"
 	"github.com/aasumitro/posbe/pkg/http/middleware"
 	"github.com/aasumitro/posbe/pkg/model"
+	"github.com/aasumitro/posbe/pkg/money"
+	"github.com/aasumitro/posbe/pkg/reference"
 	"github.com/gin-gonic/gin"
-	"github.com/redis/go-redis/v9"
 )
 
 var (
-	floorRepo     model.ICRUDRepository[model.Floor]
+	floorRepo     model.ISoftDeleteRepository[model.Floor]
 	tableRepo     model.ICRUDAddOnRepository[model.Table]
 	roomRepo      model.ICRUDAddOnRepository[model.Room]
 	storePrefRepo model.IStorePrefRepository
 )
 
 func NewStoreModuleProvider(router *gin.RouterGroup) {
-	floorRepo = repository.NewFloorSQLRepository()
-	tableRepo = repository.NewTableSQLRepository()
-	roomRepo = repository.NewRoomSQLRepository()
+	floorRepo = audit.Track(repository.NewFloorSQLRepository())
+	tableRepo = audit.TrackAddOn(repository.NewTableSQLRepository())
+	roomRepo = audit.TrackAddOn(repository.NewRoomSQLRepository())
 	storePrefRepo = repository.NewStorePrefSQLRepository()
-	storeService := service.NewStoreService(floorRepo, tableRepo, roomRepo)
-	storePrefService := service.NewStorePrefService(storePrefRepo)
-	shouldCacheData(context.Background())
-	protectedRouter := router.
+	storeService := service.NewStoreService(floorRepo, tableRepo, roomRepo,
+		reference.NewReferenceSQLRepository())
+	storePrefService := service.NewStorePrefService(storePrefRepo, config.Storage)
+	storeCurrencyService := service.NewStoreCurrencyService(
+		repository.NewCurrencyRateSQLRepository(), storePrefRepo)
+	shouldCacheData(context.Background(), storePrefService)
+	loadStoreCurrency(context.Background())
+	protectedRouter := router.Group(common.EmptyPath).
 		Use(middleware.Auth()).
-		Use(middleware.AcceptedRoles([]string{"*"}))
+		Use(middleware.IncludeDeleted()).
+		Use(middleware.ReassignTo()).
+		Use(middleware.ActivityObserver())
 	http.NewFloorHandler(storeService, protectedRouter)
 	http.NewTableHandler(storeService, protectedRouter)
 	http.NewRoomHandler(storeService, protectedRouter)
 	http.NewStorePrefHandler(storePrefService, protectedRouter)
+	http.NewStoreCurrencyHandler(storeCurrencyService, protectedRouter)
 }
 
-func shouldCacheData(ctx context.Context) {
-	// run this at first booting
-	if err := config.RedisPool.
-		Get(ctx, "store_prefs").
-		Err(); err != nil && errors.Is(err, redis.Nil) {
-		if prefs, err := storePrefRepo.All(ctx); err == nil {
-			setting := *prefs
-			// store room status
-			if room, _ := strconv.ParseBool(setting["feature_room"].(string)); room {
-				if rooms, err := roomRepo.All(ctx); err == nil {
-					for _, d := range rooms {
-						key := fmt.Sprintf("room_%d_status", d.ID)
-						config.RedisPool.Set(ctx, key, 0, 0)
-					}
-				}
+// loadStoreCurrency set the currency used to encode money amounts,
+// the default currency is kept when the pref is missing or unknown
+func loadStoreCurrency(ctx context.Context) {
+	pref, err := storePrefRepo.Find(ctx, "currency")
+	if err != nil {
+		return
+	}
+	if code, ok := (*pref)["currency"].(string); ok {
+		if err := money.SetCurrency(code); err != nil {
+			log.Printf("CURRENCY_ERROR: %s\n", err.Error())
+		}
+	}
+}
+
+func shouldCacheData(ctx context.Context, prefService model.IStorePrefService) {
+	// run this at every boot, the prefs are cached on a miss
+	prefs, errData := prefService.AllPrefs(ctx)
+	if errData != nil {
+		return
+	}
+	setting := *prefs
+	// store room status, the status of a room in use is kept
+	if room, _ := strconv.ParseBool(setting["feature_room"].(string)); room {
+		if rooms, err := roomRepo.All(ctx); err == nil {
+			for _, d := range rooms {
+				key := fmt.Sprintf("room_%d_status", d.ID)
+				_, _ = config.KV.SetNX(ctx, key, 0, 0)
 			}
-			// store table status
-			if table, _ := strconv.ParseBool(setting["feature_table"].(string)); table {
-				if tables, err := tableRepo.All(ctx); err == nil {
-					for _, d := range tables {
-						key := fmt.Sprintf("table_%d_status", d.ID)
-						config.RedisPool.Set(ctx, key, 0, 0)
-					}
-				}
+		}
+	}
+	// store table status, the status of a table in use is kept
+	if table, _ := strconv.ParseBool(setting["feature_table"].(string)); table {
+		if tables, err := tableRepo.All(ctx); err == nil {
+			for _, d := range tables {
+				key := fmt.Sprintf("table_%d_status", d.ID)
+				_, _ = config.KV.SetNX(ctx, key, 0, 0)
 			}
-			jsonData, _ := json.Marshal(prefs)
-			// store data to redis
-			config.RedisPool.Set(ctx, "store_prefs", jsonData, 0)
 		}
 	}
 }
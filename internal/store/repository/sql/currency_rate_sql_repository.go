@@ -0,0 +1,81 @@
+package sql
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/aasumitro/posbe/config"
+	"github.com/aasumitro/posbe/pkg/model"
+)
+
+const currencyRateColumns = "id, currency, rate, COALESCE(created_by, 0), created_at"
+
+type CurrencyRateSQLRepository struct {
+	Db *sql.DB
+}
+
+func (repo CurrencyRateSQLRepository) Latest(ctx context.Context) (data []*model.CurrencyRate, err error) {
+	q := "SELECT " + currencyRateColumns + " FROM currency_rates AS r WHERE id = ("
+	q += "SELECT id FROM currency_rates WHERE currency = r.currency "
+	q += "ORDER BY created_at DESC, id DESC LIMIT 1) ORDER BY currency"
+	return repo.query(ctx, q)
+}
+
+func (repo CurrencyRateSQLRepository) History(ctx context.Context, currency string) (data []*model.CurrencyRate, err error) {
+	q := "SELECT " + currencyRateColumns + " FROM currency_rates "
+	q += "WHERE currency = $1 ORDER BY created_at DESC, id DESC"
+	return repo.query(ctx, q, currency)
+}
+
+func (repo CurrencyRateSQLRepository) Find(ctx context.Context, currency string) (data *model.CurrencyRate, err error) {
+	q := "SELECT " + currencyRateColumns + " FROM currency_rates "
+	q += "WHERE currency = $1 ORDER BY created_at DESC, id DESC LIMIT 1"
+	data = &model.CurrencyRate{}
+	if err := repo.Db.QueryRowContext(ctx, q, currency).Scan(
+		&data.ID, &data.Currency, &data.Rate, &data.CreatedBy, &data.CreatedAt,
+	); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+func (repo CurrencyRateSQLRepository) Create(ctx context.Context, rate *model.CurrencyRate) (data *model.CurrencyRate, err error) {
+	q := "INSERT INTO currency_rates (currency, rate, created_by, created_at) "
+	q += "VALUES ($1, $2, $3, $4) RETURNING id"
+	data = &model.CurrencyRate{
+		Currency: rate.Currency, Rate: rate.Rate,
+		CreatedBy: rate.CreatedBy, CreatedAt: time.Now().Unix(),
+	}
+	createdBy := sql.NullInt64{Int64: int64(data.CreatedBy), Valid: data.CreatedBy > 0}
+	if err := repo.Db.QueryRowContext(ctx, q, data.Currency, data.Rate,
+		createdBy, data.CreatedAt,
+	).Scan(&data.ID); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+func (repo CurrencyRateSQLRepository) query(ctx context.Context, q string, args ...any) (data []*model.CurrencyRate, err error) {
+	rows, err := repo.Db.QueryContext(ctx, q, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func(rows *sql.Rows) { _ = rows.Close() }(rows)
+
+	data = []*model.CurrencyRate{}
+	for rows.Next() {
+		var rate model.CurrencyRate
+		if err := rows.Scan(
+			&rate.ID, &rate.Currency, &rate.Rate, &rate.CreatedBy, &rate.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		data = append(data, &rate)
+	}
+	return data, rows.Err()
+}
+
+func NewCurrencyRateSQLRepository() model.ICurrencyRateRepository {
+	return &CurrencyRateSQLRepository{Db: config.DbPool}
+}
@@ -0,0 +1,124 @@
+package sql_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/aasumitro/posbe/config"
+	repoSql "github.com/aasumitro/posbe/internal/store/repository/sql"
+	"github.com/aasumitro/posbe/pkg/dbtest"
+	"github.com/aasumitro/posbe/pkg/dialect"
+	"github.com/aasumitro/posbe/pkg/model"
+	"github.com/aasumitro/posbe/pkg/money"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type currencyRateRepositoryTestSuite struct {
+	suite.Suite
+	mock sqlmock.Sqlmock
+	repo model.ICurrencyRateRepository
+}
+
+var currencyRateRows = []string{"id", "currency", "rate", "created_by", "created_at"}
+
+func (suite *currencyRateRepositoryTestSuite) SetupSuite() {
+	var err error
+	config.DbPool, suite.mock, err = sqlmock.New(
+		sqlmock.QueryMatcherOption(sqlmock.QueryMatcherRegexp))
+	require.NoError(suite.T(), err)
+	suite.repo = repoSql.NewCurrencyRateSQLRepository()
+}
+
+func (suite *currencyRateRepositoryTestSuite) AfterTest(_, _ string) {
+	require.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+func (suite *currencyRateRepositoryTestSuite) TestRepository_Latest_ExpectReturnRows() {
+	rows := suite.mock.NewRows(currencyRateRows).
+		AddRow(2, "SGD", []byte("12100.00000000"), 1, 123).
+		AddRow(3, "USD", []byte("16250.50000000"), 0, 124)
+	suite.mock.ExpectQuery("SELECT (.+) FROM currency_rates AS r WHERE id = \\(SELECT id FROM currency_rates WHERE currency = r.currency").
+		WillReturnRows(rows)
+	res, err := suite.repo.Latest(context.TODO())
+	require.NoError(suite.T(), err)
+	require.Len(suite.T(), res, 2)
+	require.Equal(suite.T(), "16250.5", res[1].Rate.String())
+}
+
+func (suite *currencyRateRepositoryTestSuite) TestRepository_History_ExpectReturnError() {
+	suite.mock.ExpectQuery("SELECT (.+) FROM currency_rates WHERE currency = (.+)").
+		WithArgs("USD").WillReturnError(errors.New("UNEXPECTED"))
+	res, err := suite.repo.History(context.TODO(), "USD")
+	require.Nil(suite.T(), res)
+	require.Error(suite.T(), err)
+}
+
+func (suite *currencyRateRepositoryTestSuite) TestRepository_Find_ExpectReturnRow() {
+	rows := suite.mock.NewRows(currencyRateRows).
+		AddRow(3, "USD", []byte("16250.50000000"), 1, 124)
+	suite.mock.ExpectQuery("SELECT (.+) FROM currency_rates WHERE currency = (.+) LIMIT 1").
+		WithArgs("USD").WillReturnRows(rows)
+	res, err := suite.repo.Find(context.TODO(), "USD")
+	require.NoError(suite.T(), err)
+	require.Equal(suite.T(), 3, res.ID)
+}
+
+func (suite *currencyRateRepositoryTestSuite) TestRepository_Find_ExpectReturnErrorNoRows() {
+	suite.mock.ExpectQuery("SELECT (.+) FROM currency_rates WHERE currency = (.+) LIMIT 1").
+		WithArgs("SGD").WillReturnRows(suite.mock.NewRows(currencyRateRows))
+	res, err := suite.repo.Find(context.TODO(), "SGD")
+	require.Nil(suite.T(), res)
+	require.ErrorIs(suite.T(), err, sql.ErrNoRows)
+}
+
+func (suite *currencyRateRepositoryTestSuite) TestRepository_Create_ExpectReturnRow() {
+	rate, err := money.ParseRate("16300")
+	require.NoError(suite.T(), err)
+	suite.mock.ExpectQuery("INSERT INTO currency_rates (.+) RETURNING id").
+		WithArgs("USD", "16300", sql.NullInt64{Int64: 1, Valid: true}, sqlmock.AnyArg()).
+		WillReturnRows(suite.mock.NewRows([]string{"id"}).AddRow(4))
+	res, err := suite.repo.Create(context.TODO(), &model.CurrencyRate{
+		Currency: "USD", Rate: rate, CreatedBy: 1})
+	require.NoError(suite.T(), err)
+	require.Equal(suite.T(), 4, res.ID)
+}
+
+func (suite *currencyRateRepositoryTestSuite) TestCurrencyRateRepository_Latest() {
+	dbtest.Migrated(suite.T(), config.Dialect.Name())
+	ctx := context.TODO()
+	repo := repoSql.NewCurrencyRateSQLRepository()
+	usd, err := money.ParseRate("16500.25")
+	require.NoError(suite.T(), err)
+	sgd, err := money.ParseRate("12100")
+	require.NoError(suite.T(), err)
+	rate, err := repo.Create(ctx, &model.CurrencyRate{Currency: "USD", Rate: usd, CreatedBy: 1})
+	require.NoError(suite.T(), err)
+	_, err = repo.Create(ctx, &model.CurrencyRate{Currency: "SGD", Rate: sgd})
+	require.NoError(suite.T(), err)
+	found, err := repo.Find(ctx, "USD")
+	require.NoError(suite.T(), err)
+	require.Equal(suite.T(), rate.ID, found.ID)
+	history, err := repo.History(ctx, "USD")
+	require.NoError(suite.T(), err)
+	require.Len(suite.T(), history, 2)
+	latest, err := repo.Latest(ctx)
+	require.NoError(suite.T(), err)
+	require.Len(suite.T(), latest, 2)
+	require.Equal(suite.T(), "SGD", latest[0].Currency)
+	require.Equal(suite.T(), usd.String(), latest[1].Rate.String())
+	_, err = repo.Find(ctx, "EUR")
+	require.ErrorIs(suite.T(), err, sql.ErrNoRows)
+}
+
+func TestCurrencyRateRepository(t *testing.T) {
+	for _, d := range dialect.All() {
+		t.Run(d.Name(), func(t *testing.T) {
+			config.Dialect = d
+			suite.Run(t, new(currencyRateRepositoryTestSuite))
+		})
+	}
+}
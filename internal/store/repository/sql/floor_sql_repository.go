@@ -6,90 +6,115 @@ import (
 	"time"
 
 	"github.com/aasumitro/posbe/config"
+	"github.com/aasumitro/posbe/pkg/audit"
 	"github.com/aasumitro/posbe/pkg/model"
 )
 
+// floorSelect count the tables and rooms that are not deleted
+const floorSelect = "SELECT floors.id, floors.name, " +
+	"COUNT(tables.floor_id) as total_tables, COUNT(rooms.floor_id) as total_rooms, " +
+	"floors.created_at, floors.updated_at, floors.deleted_at, floors.deleted_by " +
+	"FROM floors LEFT OUTER JOIN tables ON tables.floor_id = floors.id AND tables.deleted_at IS NULL " +
+	"LEFT OUTER JOIN rooms ON rooms.floor_id = floors.id AND rooms.deleted_at IS NULL "
+
+// scanner is implemented by both *sql.Row and *sql.Rows
+type scanner interface {
+	Scan(dest ...any) error
+}
+
 type FloorSQLRepository struct {
 	Db *sql.DB
 }
 
 func (repo FloorSQLRepository) All(ctx context.Context) (floors []*model.Floor, err error) {
-	q := "SELECT floors.id, floors.name, COUNT(tables.floor_id) "
-	q += "as total_tables, COUNT(rooms.floor_id) as total_rooms, "
-	q += "floors.created_at, floors.updated_at "
-	q += "FROM floors LEFT OUTER JOIN tables ON tables.floor_id = floors.id "
-	q += "LEFT OUTER JOIN rooms ON rooms.floor_id = floors.id "
+	q := floorSelect
+	q += "WHERE ($1 OR floors.deleted_at IS NULL) "
 	q += "GROUP BY floors.id ORDER BY floors.id ASC"
-	rows, err := repo.Db.QueryContext(ctx, q)
+	rows, err := repo.Db.QueryContext(ctx, q, model.IncludeDeleted(ctx))
 	if err != nil {
 		return nil, err
 	}
 	defer func(rows *sql.Rows) { _ = rows.Close() }(rows)
+
 	for rows.Next() {
 		var floor model.Floor
+
 		if err := rows.Scan(
 			&floor.ID, &floor.Name,
 			&floor.TotalTables, &floor.TotalRooms,
 			&floor.CreatedAt, &floor.UpdatedAt,
+			&floor.DeletedAt, &floor.DeletedBy,
 		); err != nil {
 			return nil, err
 		}
+
 		floors = append(floors, &floor)
 	}
+
 	return floors, nil
 }
 
 func (repo FloorSQLRepository) Find(ctx context.Context, _ model.FindWith, val any) (floor *model.Floor, err error) {
-	q := "SELECT floors.id, floors.name, COUNT(tables.floor_id) "
-	q += "as total_tables, COUNT(rooms.floor_id) as total_rooms, "
-	q += "floors.created_at, floors.updated_at "
-	q += "FROM floors LEFT OUTER JOIN tables ON tables.floor_id = floors.id "
-	q += "LEFT OUTER JOIN rooms ON rooms.floor_id = floors.id "
-	q += "WHERE floors.id = $1 GROUP BY floors.id LIMIT 1"
-	row := repo.Db.QueryRowContext(ctx, q, val)
+	q := floorSelect
+	q += "WHERE floors.id = $1 AND ($2 OR floors.deleted_at IS NULL) GROUP BY floors.id LIMIT 1"
+	row := repo.Db.QueryRowContext(ctx, q, val, model.IncludeDeleted(ctx))
+
 	floor = &model.Floor{}
 	if err := row.Scan(
 		&floor.ID, &floor.Name,
 		&floor.TotalTables, &floor.TotalRooms,
 		&floor.CreatedAt, &floor.UpdatedAt,
+		&floor.DeletedAt, &floor.DeletedBy,
 	); err != nil {
 		return nil, err
 	}
+
 	return floor, nil
 }
 
 func (repo FloorSQLRepository) Create(ctx context.Context, params *model.Floor) (floor *model.Floor, err error) {
-	q := "INSERT INTO floors (name, created_at) values ($1, $2) RETURNING *"
+	q := "INSERT INTO floors (name, created_at) values ($1, $2) "
+	q += "RETURNING id, name, created_at, updated_at, deleted_at, deleted_by"
 	row := repo.Db.QueryRowContext(ctx, q, params.Name, time.Now().Unix())
-	floor = &model.Floor{}
-	if err := row.Scan(
-		&floor.ID, &floor.Name,
-		&floor.CreatedAt, &floor.UpdatedAt,
-	); err != nil {
-		return nil, err
-	}
-	return floor, nil
+
+	return scanFloor(row)
 }
 
 func (repo FloorSQLRepository) Update(ctx context.Context, params *model.Floor) (floor *model.Floor, err error) {
-	q := "UPDATE floors SET name = $1, updated_at = $2 WHERE id = $3 RETURNING *"
+	q := "UPDATE floors SET name = $1, updated_at = $2 WHERE id = $3 AND deleted_at IS NULL "
+	q += "RETURNING id, name, created_at, updated_at, deleted_at, deleted_by"
 	row := repo.Db.QueryRowContext(ctx, q, params.Name, time.Now().Unix(), params.ID)
+
+	return scanFloor(row)
+}
+
+func (repo FloorSQLRepository) Delete(ctx context.Context, params *model.Floor) error {
+	q := "UPDATE floors SET deleted_at = $1, deleted_by = $2 WHERE id = $3 AND deleted_at IS NULL"
+	_, err := repo.Db.ExecContext(ctx, q, time.Now().Unix(), audit.NullActor(ctx), params.ID)
+	return err
+}
+
+func (repo FloorSQLRepository) Restore(ctx context.Context, params *model.Floor) (floor *model.Floor, err error) {
+	q := "UPDATE floors SET deleted_at = NULL, deleted_by = NULL WHERE id = $1 AND deleted_at IS NOT NULL "
+	q += "RETURNING id, name, created_at, updated_at, deleted_at, deleted_by"
+	row := repo.Db.QueryRowContext(ctx, q, params.ID)
+
+	return scanFloor(row)
+}
+
+func scanFloor(row scanner) (floor *model.Floor, err error) {
 	floor = &model.Floor{}
 	if err := row.Scan(
 		&floor.ID, &floor.Name,
 		&floor.CreatedAt, &floor.UpdatedAt,
+		&floor.DeletedAt, &floor.DeletedBy,
 	); err != nil {
 		return nil, err
 	}
-	return floor, nil
-}
 
-func (repo FloorSQLRepository) Delete(ctx context.Context, params *model.Floor) error {
-	q := "DELETE FROM floors WHERE id = $1"
-	_, err := repo.Db.ExecContext(ctx, q, params.ID)
-	return err
+	return floor, nil
 }
 
-func NewFloorSQLRepository() model.ICRUDRepository[model.Floor] {
-	return &FloorSQLRepository{Db: config.PostgresPool}
+func NewFloorSQLRepository() model.ISoftDeleteRepository[model.Floor] {
+	return &FloorSQLRepository{Db: config.DbPool}
 }
@@ -2,6 +2,7 @@ package sql_test
 
 import (
 	"context"
+	"database/sql"
 	"errors"
 	"regexp"
 	"testing"
@@ -10,6 +11,8 @@ import (
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/aasumitro/posbe/config"
 	repoSql "github.com/aasumitro/posbe/internal/store/repository/sql"
+	"github.com/aasumitro/posbe/pkg/dbtest"
+	"github.com/aasumitro/posbe/pkg/dialect"
 	"github.com/aasumitro/posbe/pkg/model"
 	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
@@ -18,13 +21,13 @@ import (
 type floorRepositoryTestSuite struct {
 	suite.Suite
 	mock      sqlmock.Sqlmock
-	floorRepo model.ICRUDRepository[model.Floor]
+	floorRepo model.ISoftDeleteRepository[model.Floor]
 }
 
 func (suite *floorRepositoryTestSuite) SetupSuite() {
 	var err error
 
-	config.PostgresPool, suite.mock, err = sqlmock.New(
+	config.DbPool, suite.mock, err = sqlmock.New(
 		sqlmock.QueryMatcherOption(
 			sqlmock.QueryMatcherRegexp))
 	require.NoError(suite.T(), err)
@@ -38,15 +41,15 @@ func (suite *floorRepositoryTestSuite) AfterTest(_, _ string) {
 
 func (suite *floorRepositoryTestSuite) TestFloorRepository_All_ExpectedReturnDataRows() {
 	floors := suite.mock.
-		NewRows([]string{"id", "name", "total_tables", "total_rooms", "created_at", "updated_at"}).
-		AddRow(1, "test", 1, 1, "13123", "123123").
-		AddRow(2, "test 2", 1, 2, "13123", "123123")
+		NewRows([]string{"id", "name", "total_tables", "total_rooms", "created_at", "updated_at", "deleted_at", "deleted_by"}).
+		AddRow(1, "test", 1, 1, "13123", "123123", nil, nil).
+		AddRow(2, "test 2", 1, 2, "13123", "123123", nil, nil)
 	q := "SELECT floors.id, floors.name, COUNT(tables.floor_id) "
 	q += "as total_tables, COUNT(rooms.floor_id) as total_rooms, "
-	q += "floors.created_at, floors.updated_at "
-	q += "FROM floors LEFT OUTER JOIN tables ON tables.floor_id = floors.id "
-	q += "LEFT OUTER JOIN rooms ON rooms.floor_id = floors.id "
-	q += "GROUP BY floors.id ORDER BY floors.id ASC"
+	q += "floors.created_at, floors.updated_at, floors.deleted_at, floors.deleted_by "
+	q += "FROM floors LEFT OUTER JOIN tables ON tables.floor_id = floors.id AND tables.deleted_at IS NULL "
+	q += "LEFT OUTER JOIN rooms ON rooms.floor_id = floors.id AND rooms.deleted_at IS NULL "
+	q += "WHERE ($1 OR floors.deleted_at IS NULL) GROUP BY floors.id ORDER BY floors.id ASC"
 	expectedQuery := regexp.QuoteMeta(q)
 	suite.mock.ExpectQuery(expectedQuery).WillReturnRows(floors)
 	res, err := suite.floorRepo.All(context.TODO())
@@ -58,10 +61,10 @@ func (suite *floorRepositoryTestSuite) TestFloorRepository_All_ExpectedReturnDat
 func (suite *floorRepositoryTestSuite) TestFloorRepository_All_ExpectedReturnErrorFromQuery() {
 	q := "SELECT floors.id, floors.name, COUNT(tables.floor_id) "
 	q += "as total_tables, COUNT(rooms.floor_id) as total_rooms, "
-	q += "floors.created_at, floors.updated_at "
-	q += "FROM floors LEFT OUTER JOIN tables ON tables.floor_id = floors.id "
-	q += "LEFT OUTER JOIN rooms ON rooms.floor_id = floors.id "
-	q += "GROUP BY floors.id ORDER BY floors.id ASC"
+	q += "floors.created_at, floors.updated_at, floors.deleted_at, floors.deleted_by "
+	q += "FROM floors LEFT OUTER JOIN tables ON tables.floor_id = floors.id AND tables.deleted_at IS NULL "
+	q += "LEFT OUTER JOIN rooms ON rooms.floor_id = floors.id AND rooms.deleted_at IS NULL "
+	q += "WHERE ($1 OR floors.deleted_at IS NULL) GROUP BY floors.id ORDER BY floors.id ASC"
 	expectedQuery := regexp.QuoteMeta(q)
 	suite.mock.ExpectQuery(expectedQuery).WillReturnError(errors.New(""))
 	res, err := suite.floorRepo.All(context.TODO())
@@ -76,10 +79,10 @@ func (suite *floorRepositoryTestSuite) TestFloorRepository_All_ExpectedReturnErr
 		AddRow(nil, nil)
 	q := "SELECT floors.id, floors.name, COUNT(tables.floor_id) "
 	q += "as total_tables, COUNT(rooms.floor_id) as total_rooms, "
-	q += "floors.created_at, floors.updated_at "
-	q += "FROM floors LEFT OUTER JOIN tables ON tables.floor_id = floors.id "
-	q += "LEFT OUTER JOIN rooms ON rooms.floor_id = floors.id "
-	q += "GROUP BY floors.id ORDER BY floors.id ASC"
+	q += "floors.created_at, floors.updated_at, floors.deleted_at, floors.deleted_by "
+	q += "FROM floors LEFT OUTER JOIN tables ON tables.floor_id = floors.id AND tables.deleted_at IS NULL "
+	q += "LEFT OUTER JOIN rooms ON rooms.floor_id = floors.id AND rooms.deleted_at IS NULL "
+	q += "WHERE ($1 OR floors.deleted_at IS NULL) GROUP BY floors.id ORDER BY floors.id ASC"
 	expectedQuery := regexp.QuoteMeta(q)
 	suite.mock.ExpectQuery(expectedQuery).WillReturnRows(floors)
 	res, err := suite.floorRepo.All(context.TODO())
@@ -89,14 +92,14 @@ func (suite *floorRepositoryTestSuite) TestFloorRepository_All_ExpectedReturnErr
 
 func (suite *floorRepositoryTestSuite) TestFloorRepository_Find_ExpectedSuccess() {
 	floor := suite.mock.
-		NewRows([]string{"id", "name", "total_tables", "total_rooms", "created_at", "updated_at"}).
-		AddRow(1, "test", 1, 1, "13123", "123123")
+		NewRows([]string{"id", "name", "total_tables", "total_rooms", "created_at", "updated_at", "deleted_at", "deleted_by"}).
+		AddRow(1, "test", 1, 1, "13123", "123123", nil, nil)
 	q := "SELECT floors.id, floors.name, COUNT(tables.floor_id) "
 	q += "as total_tables, COUNT(rooms.floor_id) as total_rooms, "
-	q += "floors.created_at, floors.updated_at "
-	q += "FROM floors LEFT OUTER JOIN tables ON tables.floor_id = floors.id "
-	q += "LEFT OUTER JOIN rooms ON rooms.floor_id = floors.id "
-	q += "WHERE floors.id = $1 GROUP BY floors.id LIMIT 1"
+	q += "floors.created_at, floors.updated_at, floors.deleted_at, floors.deleted_by "
+	q += "FROM floors LEFT OUTER JOIN tables ON tables.floor_id = floors.id AND tables.deleted_at IS NULL "
+	q += "LEFT OUTER JOIN rooms ON rooms.floor_id = floors.id AND rooms.deleted_at IS NULL "
+	q += "WHERE floors.id = $1 AND ($2 OR floors.deleted_at IS NULL) GROUP BY floors.id LIMIT 1"
 	expectedQuery := regexp.QuoteMeta(q)
 	suite.mock.ExpectQuery(expectedQuery).WillReturnRows(floor)
 	res, err := suite.floorRepo.Find(context.TODO(), model.FindWithID, 1)
@@ -111,10 +114,10 @@ func (suite *floorRepositoryTestSuite) TestFloorRepository_Find_ExpectedError()
 		AddRow(nil, nil)
 	q := "SELECT floors.id, floors.name, COUNT(tables.floor_id) "
 	q += "as total_tables, COUNT(rooms.floor_id) as total_rooms, "
-	q += "floors.created_at, floors.updated_at "
-	q += "FROM floors LEFT OUTER JOIN tables ON tables.floor_id = floors.id "
-	q += "LEFT OUTER JOIN rooms ON rooms.floor_id = floors.id "
-	q += "WHERE floors.id = $1 GROUP BY floors.id LIMIT 1"
+	q += "floors.created_at, floors.updated_at, floors.deleted_at, floors.deleted_by "
+	q += "FROM floors LEFT OUTER JOIN tables ON tables.floor_id = floors.id AND tables.deleted_at IS NULL "
+	q += "LEFT OUTER JOIN rooms ON rooms.floor_id = floors.id AND rooms.deleted_at IS NULL "
+	q += "WHERE floors.id = $1 AND ($2 OR floors.deleted_at IS NULL) GROUP BY floors.id LIMIT 1"
 	expectedQuery := regexp.QuoteMeta(q)
 	suite.mock.ExpectQuery(expectedQuery).WillReturnRows(floor)
 	res, err := suite.floorRepo.Find(context.TODO(), model.FindWithID, 1)
@@ -125,9 +128,9 @@ func (suite *floorRepositoryTestSuite) TestFloorRepository_Find_ExpectedError()
 func (suite *floorRepositoryTestSuite) TestFloorRepository_Create_ExpectedSuccess() {
 	floor := &model.Floor{ID: 1, Name: "test"}
 	rows := suite.mock.
-		NewRows([]string{"id", "name", "created_at", "updated_at"}).
-		AddRow(1, "test", "123123", "12312312")
-	expectedQuery := regexp.QuoteMeta("INSERT INTO floors (name, created_at) values ($1, $2) RETURNING *")
+		NewRows([]string{"id", "name", "created_at", "updated_at", "deleted_at", "deleted_by"}).
+		AddRow(1, "test", "123123", "12312312", nil, nil)
+	expectedQuery := regexp.QuoteMeta("INSERT INTO floors (name, created_at) values ($1, $2) RETURNING id, name, created_at, updated_at, deleted_at, deleted_by")
 	suite.mock.ExpectQuery(expectedQuery).
 		WithArgs(floor.Name, time.Now().Unix()).
 		WillReturnRows(rows).
@@ -140,9 +143,9 @@ func (suite *floorRepositoryTestSuite) TestFloorRepository_Create_ExpectedSucces
 func (suite *floorRepositoryTestSuite) TestFloorRepository_Create_ExpectedError() {
 	floor := &model.Floor{ID: 1, Name: "test"}
 	rows := suite.mock.
-		NewRows([]string{"id", "name", "created_at", "updated_at"}).
-		AddRow(1, nil, nil, nil)
-	expectedQuery := regexp.QuoteMeta("INSERT INTO floors (name, created_at) values ($1, $2) RETURNING *")
+		NewRows([]string{"id", "name", "created_at", "updated_at", "deleted_at", "deleted_by"}).
+		AddRow(1, nil, nil, nil, nil, nil)
+	expectedQuery := regexp.QuoteMeta("INSERT INTO floors (name, created_at) values ($1, $2) RETURNING id, name, created_at, updated_at, deleted_at, deleted_by")
 	suite.mock.ExpectQuery(expectedQuery).
 		WithArgs(floor.Name, time.Now().Unix()).
 		WillReturnRows(rows).
@@ -155,9 +158,9 @@ func (suite *floorRepositoryTestSuite) TestFloorRepository_Create_ExpectedError(
 func (suite *floorRepositoryTestSuite) TestFloorRepository_Update_ExpectedSuccess() {
 	floor := &model.Floor{ID: 1, Name: "test"}
 	rows := suite.mock.
-		NewRows([]string{"id", "name", "created_at", "updated_at"}).
-		AddRow(1, "test", "123123", "12312312")
-	expectedQuery := regexp.QuoteMeta("UPDATE floors SET name = $1, updated_at = $2 WHERE id = $3 RETURNING *")
+		NewRows([]string{"id", "name", "created_at", "updated_at", "deleted_at", "deleted_by"}).
+		AddRow(1, "test", "123123", "12312312", nil, nil)
+	expectedQuery := regexp.QuoteMeta("UPDATE floors SET name = $1, updated_at = $2 WHERE id = $3 AND deleted_at IS NULL RETURNING id, name, created_at, updated_at, deleted_at, deleted_by")
 	suite.mock.ExpectQuery(expectedQuery).
 		WithArgs(floor.Name, time.Now().Unix(), floor.ID).
 		WillReturnRows(rows).
@@ -170,9 +173,9 @@ func (suite *floorRepositoryTestSuite) TestFloorRepository_Update_ExpectedSucces
 func (suite *floorRepositoryTestSuite) TestFloorRepository_Update_ExpectedError() {
 	floor := &model.Floor{ID: 1, Name: "test"}
 	rows := suite.mock.
-		NewRows([]string{"id", "name", "created_at", "updated_at"}).
-		AddRow(1, nil, nil, nil)
-	expectedQuery := regexp.QuoteMeta("UPDATE floors SET name = $1, updated_at = $2 WHERE id = $3 RETURNING *")
+		NewRows([]string{"id", "name", "created_at", "updated_at", "deleted_at", "deleted_by"}).
+		AddRow(1, nil, nil, nil, nil, nil)
+	expectedQuery := regexp.QuoteMeta("UPDATE floors SET name = $1, updated_at = $2 WHERE id = $3 AND deleted_at IS NULL RETURNING id, name, created_at, updated_at, deleted_at, deleted_by")
 	suite.mock.ExpectQuery(expectedQuery).
 		WithArgs(floor.Name, time.Now().Unix(), floor.ID).
 		WillReturnRows(rows).
@@ -183,15 +186,57 @@ func (suite *floorRepositoryTestSuite) TestFloorRepository_Update_ExpectedError(
 }
 
 func (suite *floorRepositoryTestSuite) TestFloorRepository_Delete_ExpectedSuccess() {
-	expectedQuery := regexp.QuoteMeta("DELETE FROM floors")
+	expectedQuery := regexp.QuoteMeta("UPDATE floors SET deleted_at = $1, deleted_by = $2 WHERE id = $3 AND deleted_at IS NULL")
 	suite.mock.ExpectExec(expectedQuery).
-		WithArgs(1).
+		WithArgs(sqlmock.AnyArg(), nil, 1).
 		WillReturnResult(sqlmock.NewResult(0, 1))
 	floor := &model.Floor{ID: 1, Name: "test"}
 	err := suite.floorRepo.Delete(context.TODO(), floor)
 	require.Nil(suite.T(), err)
 }
 
+func (suite *floorRepositoryTestSuite) TestFloorRepository_Restore_ExpectedSuccess() {
+	rows := suite.mock.
+		NewRows([]string{"id", "name", "created_at", "updated_at", "deleted_at", "deleted_by"}).
+		AddRow(1, "test", "123123", "12312312", nil, nil)
+	q := "UPDATE floors SET deleted_at = NULL, deleted_by = NULL WHERE id = $1 AND deleted_at IS NOT NULL "
+	q += "RETURNING id, name, created_at, updated_at, deleted_at, deleted_by"
+	expectedQuery := regexp.QuoteMeta(q)
+	suite.mock.ExpectQuery(expectedQuery).
+		WithArgs(1).
+		WillReturnRows(rows)
+	res, err := suite.floorRepo.Restore(context.TODO(), &model.Floor{ID: 1})
+	require.Nil(suite.T(), err)
+	require.NotNil(suite.T(), res)
+}
+
+func (suite *floorRepositoryTestSuite) TestFloorRepository_SoftDelete() {
+	dbtest.Migrated(suite.T(), config.Dialect.Name())
+	ctx := context.TODO()
+	repo := repoSql.NewFloorSQLRepository()
+	floor, err := repo.Create(ctx, &model.Floor{Name: "rooftop"})
+	require.NoError(suite.T(), err)
+	floor.Name = "terrace"
+	floor, err = repo.Update(ctx, floor)
+	require.NoError(suite.T(), err)
+	require.Equal(suite.T(), "terrace", floor.Name)
+
+	require.NoError(suite.T(), repo.Delete(ctx, floor))
+	_, err = repo.Find(ctx, model.FindWithID, floor.ID)
+	require.ErrorIs(suite.T(), err, sql.ErrNoRows)
+	_, err = repo.Restore(ctx, floor)
+	require.NoError(suite.T(), err)
+	// the seeded floor 1 has 4 tables, the deleted one is not counted
+	found, err := repo.Find(ctx, model.FindWithID, 1)
+	require.NoError(suite.T(), err)
+	require.Equal(suite.T(), 4, found.TotalTables)
+}
+
 func TestFloorRepository(t *testing.T) {
-	suite.Run(t, new(floorRepositoryTestSuite))
+	for _, d := range dialect.All() {
+		t.Run(d.Name(), func(t *testing.T) {
+			config.Dialect = d
+			suite.Run(t, new(floorRepositoryTestSuite))
+		})
+	}
 }
@@ -6,61 +6,64 @@ import (
 	"time"
 
 	"github.com/aasumitro/posbe/config"
+	"github.com/aasumitro/posbe/pkg/audit"
 	"github.com/aasumitro/posbe/pkg/model"
 )
 
+const roomColumns = "id, floor_id, name, x_pos, y_pos, w_size, h_size, capacity, price, " +
+	"created_at, updated_at, deleted_at, deleted_by"
+
 type RoomSQLRepository struct {
 	Db *sql.DB
 }
 
 func (repo RoomSQLRepository) AllWhere(ctx context.Context, key model.FindWith, val any) (rooms []*model.Room, err error) {
-	q := "SELECT * FROM rooms "
+	q := "SELECT " + roomColumns + " FROM rooms WHERE ($1 OR deleted_at IS NULL) "
+	args := []any{model.IncludeDeleted(ctx)}
 	if key == model.FindWithRelationID {
-		q += "WHERE floor_id = $1"
+		q += "AND floor_id = $2"
+		args = append(args, val)
 	}
-	rows, err := repo.Db.QueryContext(ctx, q, val)
+	rows, err := repo.Db.QueryContext(ctx, q, args...)
 	if err != nil {
 		return nil, err
 	}
+	defer func(rows *sql.Rows) { _ = rows.Close() }(rows)
+
 	for rows.Next() {
-		var room model.Room
-		if err := rows.Scan(
-			&room.ID, &room.FloorID, &room.Name,
-			&room.XPos, &room.YPos, &room.WSize,
-			&room.HSize, &room.Capacity, &room.Price,
-			&room.CreatedAt, &room.UpdatedAt,
-		); err != nil {
+		room, err := scanRoom(rows)
+		if err != nil {
 			return nil, err
 		}
-		rooms = append(rooms, &room)
+
+		rooms = append(rooms, room)
 	}
+
 	return rooms, nil
 }
 
 func (repo RoomSQLRepository) All(ctx context.Context) (rooms []*model.Room, err error) {
-	q := "SELECT * FROM rooms"
-	rows, err := repo.Db.QueryContext(ctx, q)
+	q := "SELECT " + roomColumns + " FROM rooms WHERE ($1 OR deleted_at IS NULL)"
+	rows, err := repo.Db.QueryContext(ctx, q, model.IncludeDeleted(ctx))
 	if err != nil {
 		return nil, err
 	}
+	defer func(rows *sql.Rows) { _ = rows.Close() }(rows)
+
 	for rows.Next() {
-		var room model.Room
-		if err := rows.Scan(
-			&room.ID, &room.FloorID, &room.Name,
-			&room.XPos, &room.YPos, &room.WSize,
-			&room.HSize, &room.Capacity, &room.Price,
-			&room.CreatedAt, &room.UpdatedAt,
-		); err != nil {
+		room, err := scanRoom(rows)
+		if err != nil {
 			return nil, err
 		}
-		rooms = append(rooms, &room)
+
+		rooms = append(rooms, room)
 	}
 
 	return rooms, nil
 }
 
 func (repo RoomSQLRepository) Find(ctx context.Context, key model.FindWith, val any) (room *model.Room, err error) {
-	q := "SELECT * FROM rooms WHERE "
+	q := "SELECT " + roomColumns + " FROM rooms WHERE ($2 OR deleted_at IS NULL) AND "
 	//goland:noinspection ALL
 	switch key {
 	case model.FindWithID:
@@ -69,36 +72,21 @@ func (repo RoomSQLRepository) Find(ctx context.Context, key model.FindWith, val
 		q += "floor_id = $1 "
 	}
 	q += "LIMIT 1"
-	row := repo.Db.QueryRowContext(ctx, q, val)
-	room = &model.Room{}
-	if err := row.Scan(
-		&room.ID, &room.FloorID, &room.Name,
-		&room.XPos, &room.YPos, &room.WSize,
-		&room.HSize, &room.Capacity, &room.Price,
-		&room.CreatedAt, &room.UpdatedAt,
-	); err != nil {
-		return nil, err
-	}
-	return room, nil
+	row := repo.Db.QueryRowContext(ctx, q, val, model.IncludeDeleted(ctx))
+
+	return scanRoom(row)
 }
 
 func (repo RoomSQLRepository) Create(ctx context.Context, params *model.Room) (room *model.Room, err error) {
 	q := "INSERT INTO rooms (floor_id, name, x_pos,  "
 	q += "y_pos, w_size, h_size, capacity, price, created_at) "
-	q += "values ($1, $2, $3, $4, $5, $6, $7, $8, $9) RETURNING *"
+	q += "values ($1, $2, $3, $4, $5, $6, $7, $8, $9) RETURNING " + roomColumns
 	row := repo.Db.QueryRowContext(ctx, q,
 		params.FloorID, params.Name, params.XPos,
 		params.YPos, params.WSize, params.HSize,
 		params.Capacity, params.Price, time.Now().Unix())
-	room = &model.Room{}
-	if err := row.Scan(&room.ID, &room.FloorID, &room.Name,
-		&room.XPos, &room.YPos, &room.WSize,
-		&room.HSize, &room.Capacity, &room.Price,
-		&room.CreatedAt, &room.UpdatedAt,
-	); err != nil {
-		return nil, err
-	}
-	return room, nil
+
+	return scanRoom(row)
 }
 
 func (repo RoomSQLRepository) Update(ctx context.Context, params *model.Room) (room *model.Room, err error) {
@@ -106,28 +94,46 @@ func (repo RoomSQLRepository) Update(ctx context.Context, params *model.Room) (r
 	q += "floor_id = $1, name = $2, x_pos = $3, "
 	q += "y_pos = $4, w_size = $5, h_size = $6, "
 	q += "capacity= $7, price = $8, updated_at = $9 "
-	q += "WHERE id = $10 RETURNING *"
+	q += "WHERE id = $10 AND deleted_at IS NULL RETURNING " + roomColumns
 	row := repo.Db.QueryRowContext(ctx, q,
 		params.FloorID, params.Name, params.XPos,
 		params.YPos, params.WSize, params.HSize,
-		params.Capacity, params.Price, time.Now().Unix(), params.ID)
+		params.Capacity, params.Price,
+		time.Now().Unix(), params.ID)
+
+	return scanRoom(row)
+}
+
+func (repo RoomSQLRepository) Delete(ctx context.Context, params *model.Room) error {
+	q := "UPDATE rooms SET deleted_at = $1, deleted_by = $2 WHERE id = $3 AND deleted_at IS NULL"
+	_, err := repo.Db.ExecContext(ctx, q, time.Now().Unix(), audit.NullActor(ctx), params.ID)
+	return err
+}
+
+func (repo RoomSQLRepository) Restore(ctx context.Context, params *model.Room) (room *model.Room, err error) {
+	q := "UPDATE rooms SET deleted_at = NULL, deleted_by = NULL "
+	q += "WHERE id = $1 AND deleted_at IS NOT NULL RETURNING " + roomColumns
+	row := repo.Db.QueryRowContext(ctx, q, params.ID)
+
+	return scanRoom(row)
+}
+
+// scanRoom read a row of roomColumns
+func scanRoom(row scanner) (room *model.Room, err error) {
 	room = &model.Room{}
-	if err := row.Scan(&room.ID, &room.FloorID, &room.Name,
+	if err := row.Scan(
+		&room.ID, &room.FloorID, &room.Name,
 		&room.XPos, &room.YPos, &room.WSize,
 		&room.HSize, &room.Capacity, &room.Price,
 		&room.CreatedAt, &room.UpdatedAt,
+		&room.DeletedAt, &room.DeletedBy,
 	); err != nil {
 		return nil, err
 	}
-	return room, nil
-}
 
-func (repo RoomSQLRepository) Delete(ctx context.Context, params *model.Room) error {
-	q := "DELETE FROM rooms WHERE id = $1"
-	_, err := repo.Db.ExecContext(ctx, q, params.ID)
-	return err
+	return room, nil
 }
 
 func NewRoomSQLRepository() model.ICRUDAddOnRepository[model.Room] {
-	return &RoomSQLRepository{Db: config.PostgresPool}
+	return &RoomSQLRepository{Db: config.DbPool}
 }
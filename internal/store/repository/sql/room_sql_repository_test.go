@@ -2,6 +2,7 @@ package sql_test
 
 import (
 	"context"
+	"database/sql"
 	"errors"
 	"regexp"
 	"testing"
@@ -10,7 +11,10 @@ import (
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/aasumitro/posbe/config"
 	repoSql "github.com/aasumitro/posbe/internal/store/repository/sql"
+	"github.com/aasumitro/posbe/pkg/dbtest"
+	"github.com/aasumitro/posbe/pkg/dialect"
 	"github.com/aasumitro/posbe/pkg/model"
+	"github.com/aasumitro/posbe/pkg/money"
 	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
 )
@@ -24,7 +28,7 @@ type roomRepositoryTestSuite struct {
 func (suite *roomRepositoryTestSuite) SetupSuite() {
 	var err error
 
-	config.PostgresPool, suite.mock, err = sqlmock.New(
+	config.DbPool, suite.mock, err = sqlmock.New(
 		sqlmock.QueryMatcherOption(
 			sqlmock.QueryMatcherRegexp))
 	require.NoError(suite.T(), err)
@@ -38,10 +42,10 @@ func (suite *roomRepositoryTestSuite) AfterTest(_, _ string) {
 
 func (suite *roomRepositoryTestSuite) TestRoomRepository_AllWhere_ExpectedReturnDataRows() {
 	rooms := suite.mock.
-		NewRows([]string{"id", "floor_id", "name", "x_pos", "y_pos", "w_size", "h_size", "capacity", "price", "created_at", "updated_at"}).
-		AddRow(1, 1, "test", 1, 2, 3, 4, 5, 1, "13123", "123123").
-		AddRow(2, 1, "test 2", 1, 2, 3, 4, 5, 1, "13123", "123123")
-	q := "SELECT * FROM rooms WHERE floor_id = $1"
+		NewRows([]string{"id", "floor_id", "name", "x_pos", "y_pos", "w_size", "h_size", "capacity", "price", "created_at", "updated_at", "deleted_at", "deleted_by"}).
+		AddRow(1, 1, "test", 1, 2, 3, 4, 5, 1, "13123", "123123", nil, nil).
+		AddRow(2, 1, "test 2", 1, 2, 3, 4, 5, 1, "13123", "123123", nil, nil)
+	q := "SELECT id, floor_id, name, x_pos, y_pos, w_size, h_size, capacity, price, created_at, updated_at, deleted_at, deleted_by FROM rooms WHERE ($1 OR deleted_at IS NULL) AND floor_id = $2"
 	expectedQuery := regexp.QuoteMeta(q)
 	suite.mock.ExpectQuery(expectedQuery).WillReturnRows(rooms)
 	res, err := suite.roomRepo.AllWhere(context.TODO(), model.FindWithRelationID, 1)
@@ -51,7 +55,7 @@ func (suite *roomRepositoryTestSuite) TestRoomRepository_AllWhere_ExpectedReturn
 }
 
 func (suite *roomRepositoryTestSuite) TestRoomRepository_AllWhere_ExpectedReturnErrorFromQuery() {
-	q := "SELECT * FROM rooms WHERE floor_id = $1"
+	q := "SELECT id, floor_id, name, x_pos, y_pos, w_size, h_size, capacity, price, created_at, updated_at, deleted_at, deleted_by FROM rooms WHERE ($1 OR deleted_at IS NULL) AND floor_id = $2"
 	expectedQuery := regexp.QuoteMeta(q)
 	suite.mock.ExpectQuery(expectedQuery).WillReturnError(errors.New(""))
 	res, err := suite.roomRepo.AllWhere(context.TODO(), model.FindWithRelationID, 1)
@@ -61,10 +65,10 @@ func (suite *roomRepositoryTestSuite) TestRoomRepository_AllWhere_ExpectedReturn
 
 func (suite *roomRepositoryTestSuite) TestRoomRepository_AllWhere_ExpectedReturnErrorFromScan() {
 	rooms := suite.mock.
-		NewRows([]string{"id", "floor_id", "name", "x_pos", "y_pos", "w_size", "h_size", "capacity", "price", "created_at", "updated_at"}).
-		AddRow(1, 1, "test", 1, 2, 3, 4, 5, 1, "13123", "123123").
-		AddRow(nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
-	q := "SELECT * FROM rooms WHERE floor_id = $1"
+		NewRows([]string{"id", "floor_id", "name", "x_pos", "y_pos", "w_size", "h_size", "capacity", "price", "created_at", "updated_at", "deleted_at", "deleted_by"}).
+		AddRow(1, 1, "test", 1, 2, 3, 4, 5, 1, "13123", "123123", nil, nil).
+		AddRow(nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+	q := "SELECT id, floor_id, name, x_pos, y_pos, w_size, h_size, capacity, price, created_at, updated_at, deleted_at, deleted_by FROM rooms WHERE ($1 OR deleted_at IS NULL) AND floor_id = $2"
 	expectedQuery := regexp.QuoteMeta(q)
 	suite.mock.ExpectQuery(expectedQuery).WillReturnRows(rooms)
 	res, err := suite.roomRepo.AllWhere(context.TODO(), model.FindWithRelationID, 1)
@@ -74,10 +78,10 @@ func (suite *roomRepositoryTestSuite) TestRoomRepository_AllWhere_ExpectedReturn
 
 func (suite *roomRepositoryTestSuite) TestRoomRepository_All_ExpectedReturnDataRows() {
 	rooms := suite.mock.
-		NewRows([]string{"id", "floor_id", "name", "x_pos", "y_pos", "w_size", "h_size", "capacity", "price", "created_at", "updated_at"}).
-		AddRow(1, 1, "test", 1, 2, 3, 4, 5, 1, "13123", "123123").
-		AddRow(2, 1, "test 2", 1, 2, 3, 4, 5, 1, "13123", "123123")
-	q := "SELECT * FROM rooms"
+		NewRows([]string{"id", "floor_id", "name", "x_pos", "y_pos", "w_size", "h_size", "capacity", "price", "created_at", "updated_at", "deleted_at", "deleted_by"}).
+		AddRow(1, 1, "test", 1, 2, 3, 4, 5, 1, "13123", "123123", nil, nil).
+		AddRow(2, 1, "test 2", 1, 2, 3, 4, 5, 1, "13123", "123123", nil, nil)
+	q := "SELECT id, floor_id, name, x_pos, y_pos, w_size, h_size, capacity, price, created_at, updated_at, deleted_at, deleted_by FROM rooms WHERE ($1 OR deleted_at IS NULL)"
 	expectedQuery := regexp.QuoteMeta(q)
 	suite.mock.ExpectQuery(expectedQuery).WillReturnRows(rooms)
 	res, err := suite.roomRepo.All(context.TODO())
@@ -87,7 +91,7 @@ func (suite *roomRepositoryTestSuite) TestRoomRepository_All_ExpectedReturnDataR
 }
 
 func (suite *roomRepositoryTestSuite) TestRoomRepository_All_ExpectedReturnErrorFromQuery() {
-	q := "SELECT * FROM rooms"
+	q := "SELECT id, floor_id, name, x_pos, y_pos, w_size, h_size, capacity, price, created_at, updated_at, deleted_at, deleted_by FROM rooms WHERE ($1 OR deleted_at IS NULL)"
 	expectedQuery := regexp.QuoteMeta(q)
 	suite.mock.ExpectQuery(expectedQuery).WillReturnError(errors.New(""))
 	res, err := suite.roomRepo.All(context.TODO())
@@ -97,10 +101,10 @@ func (suite *roomRepositoryTestSuite) TestRoomRepository_All_ExpectedReturnError
 
 func (suite *roomRepositoryTestSuite) TestRoomRepository_All_ExpectedReturnErrorFromScan() {
 	rooms := suite.mock.
-		NewRows([]string{"id", "floor_id", "name", "x_pos", "y_pos", "w_size", "h_size", "capacity", "price", "created_at", "updated_at"}).
-		AddRow(1, 1, "test", 1, 2, 3, 4, 5, 1, "13123", "123123").
-		AddRow(nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
-	q := "SELECT * FROM rooms"
+		NewRows([]string{"id", "floor_id", "name", "x_pos", "y_pos", "w_size", "h_size", "capacity", "price", "created_at", "updated_at", "deleted_at", "deleted_by"}).
+		AddRow(1, 1, "test", 1, 2, 3, 4, 5, 1, "13123", "123123", nil, nil).
+		AddRow(nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+	q := "SELECT id, floor_id, name, x_pos, y_pos, w_size, h_size, capacity, price, created_at, updated_at, deleted_at, deleted_by FROM rooms WHERE ($1 OR deleted_at IS NULL)"
 	expectedQuery := regexp.QuoteMeta(q)
 	suite.mock.ExpectQuery(expectedQuery).WillReturnRows(rooms)
 	res, err := suite.roomRepo.All(context.TODO())
@@ -110,9 +114,9 @@ func (suite *roomRepositoryTestSuite) TestRoomRepository_All_ExpectedReturnError
 
 func (suite *roomRepositoryTestSuite) TestRoomRepository_Find_ExpectedSuccess() {
 	rooms := suite.mock.
-		NewRows([]string{"id", "floor_id", "name", "x_pos", "y_pos", "w_size", "h_size", "capacity", "price", "created_at", "updated_at"}).
-		AddRow(1, 1, "test", 1, 2, 3, 4, 5, 1, "13123", "123123")
-	q := "SELECT * FROM rooms WHERE id = $1 LIMIT 1"
+		NewRows([]string{"id", "floor_id", "name", "x_pos", "y_pos", "w_size", "h_size", "capacity", "price", "created_at", "updated_at", "deleted_at", "deleted_by"}).
+		AddRow(1, 1, "test", 1, 2, 3, 4, 5, 1, "13123", "123123", nil, nil)
+	q := "SELECT id, floor_id, name, x_pos, y_pos, w_size, h_size, capacity, price, created_at, updated_at, deleted_at, deleted_by FROM rooms WHERE ($2 OR deleted_at IS NULL) AND id = $1 LIMIT 1"
 	expectedQuery := regexp.QuoteMeta(q)
 	suite.mock.ExpectQuery(expectedQuery).WillReturnRows(rooms)
 	res, err := suite.roomRepo.Find(context.TODO(), model.FindWithID, 1)
@@ -123,9 +127,9 @@ func (suite *roomRepositoryTestSuite) TestRoomRepository_Find_ExpectedSuccess()
 
 func (suite *roomRepositoryTestSuite) TestRoomRepository_FindRelation_ExpectedSuccess() {
 	room := suite.mock.
-		NewRows([]string{"id", "floor_id", "name", "x_pos", "y_pos", "w_size", "h_size", "capacity", "price", "created_at", "updated_at"}).
-		AddRow(1, 1, "test", 1, 2, 3, 4, 5, 1, "13123", "123123")
-	q := "SELECT * FROM rooms WHERE floor_id = $1 LIMIT 1"
+		NewRows([]string{"id", "floor_id", "name", "x_pos", "y_pos", "w_size", "h_size", "capacity", "price", "created_at", "updated_at", "deleted_at", "deleted_by"}).
+		AddRow(1, 1, "test", 1, 2, 3, 4, 5, 1, "13123", "123123", nil, nil)
+	q := "SELECT id, floor_id, name, x_pos, y_pos, w_size, h_size, capacity, price, created_at, updated_at, deleted_at, deleted_by FROM rooms WHERE ($2 OR deleted_at IS NULL) AND floor_id = $1 LIMIT 1"
 	expectedQuery := regexp.QuoteMeta(q)
 	suite.mock.ExpectQuery(expectedQuery).WillReturnRows(room)
 	res, err := suite.roomRepo.Find(context.TODO(), model.FindWithRelationID, 1)
@@ -136,9 +140,9 @@ func (suite *roomRepositoryTestSuite) TestRoomRepository_FindRelation_ExpectedSu
 
 func (suite *roomRepositoryTestSuite) TestRoomRepository_Find_ExpectedError() {
 	table := suite.mock.
-		NewRows([]string{"id", "floor_id", "name", "x_pos", "y_pos", "w_size", "h_size", "capacity", "price", "created_at", "updated_at"}).
-		AddRow(nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
-	q := "SELECT * FROM rooms WHERE id = $1"
+		NewRows([]string{"id", "floor_id", "name", "x_pos", "y_pos", "w_size", "h_size", "capacity", "price", "created_at", "updated_at", "deleted_at", "deleted_by"}).
+		AddRow(nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+	q := "SELECT id, floor_id, name, x_pos, y_pos, w_size, h_size, capacity, price, created_at, updated_at, deleted_at, deleted_by FROM rooms WHERE ($2 OR deleted_at IS NULL) AND id = $1"
 	expectedQuery := regexp.QuoteMeta(q)
 	suite.mock.ExpectQuery(expectedQuery).WillReturnRows(table)
 	res, err := suite.roomRepo.Find(context.TODO(), model.FindWithID, 1)
@@ -149,11 +153,11 @@ func (suite *roomRepositoryTestSuite) TestRoomRepository_Find_ExpectedError() {
 func (suite *roomRepositoryTestSuite) TestRoomRepository_Create_ExpectedSuccess() {
 	room := &model.Room{FloorID: 1, Name: "test", XPos: 1, YPos: 1, WSize: 1, HSize: 1, Capacity: 1, Price: 1}
 	rows := suite.mock.
-		NewRows([]string{"id", "floor_id", "name", "x_pos", "y_pos", "w_size", "h_size", "capacity", "price", "created_at", "updated_at"}).
-		AddRow(1, 1, "test", 1, 2, 3, 4, 5, 1, "13123", "123123")
+		NewRows([]string{"id", "floor_id", "name", "x_pos", "y_pos", "w_size", "h_size", "capacity", "price", "created_at", "updated_at", "deleted_at", "deleted_by"}).
+		AddRow(1, 1, "test", 1, 2, 3, 4, 5, 1, "13123", "123123", nil, nil)
 	q := "INSERT INTO rooms (floor_id, name, x_pos,  "
 	q += "y_pos, w_size, h_size, capacity, price, created_at) "
-	q += "values ($1, $2, $3, $4, $5, $6, $7, $8, $9) RETURNING *"
+	q += "values ($1, $2, $3, $4, $5, $6, $7, $8, $9) RETURNING id, floor_id, name, x_pos, y_pos, w_size, h_size, capacity, price, created_at, updated_at, deleted_at, deleted_by"
 	expectedQuery := regexp.QuoteMeta(q)
 	suite.mock.ExpectQuery(expectedQuery).
 		WithArgs(room.FloorID, room.Name, room.XPos, room.YPos, room.WSize, room.HSize, room.Capacity, room.Price, time.Now().Unix()).
@@ -167,11 +171,11 @@ func (suite *roomRepositoryTestSuite) TestRoomRepository_Create_ExpectedSuccess(
 func (suite *roomRepositoryTestSuite) TestRoomRepository_Create_ExpectedError() {
 	room := &model.Room{FloorID: 1, Name: "test", XPos: 1, YPos: 1, WSize: 1, HSize: 1, Capacity: 1, Price: 1}
 	rows := suite.mock.
-		NewRows([]string{"id", "floor_id", "name", "x_pos", "y_pos", "w_size", "h_size", "capacity", "price", "created_at", "updated_at"}).
-		AddRow(nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+		NewRows([]string{"id", "floor_id", "name", "x_pos", "y_pos", "w_size", "h_size", "capacity", "price", "created_at", "updated_at", "deleted_at", "deleted_by"}).
+		AddRow(nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
 	q := "INSERT INTO rooms (floor_id, name, x_pos,  "
 	q += "y_pos, w_size, h_size, capacity, price, created_at) "
-	q += "values ($1, $2, $3, $4, $5, $6, $7, $8, $9) RETURNING *"
+	q += "values ($1, $2, $3, $4, $5, $6, $7, $8, $9) RETURNING id, floor_id, name, x_pos, y_pos, w_size, h_size, capacity, price, created_at, updated_at, deleted_at, deleted_by"
 	expectedQuery := regexp.QuoteMeta(q)
 	suite.mock.ExpectQuery(expectedQuery).
 		WithArgs(room.FloorID, room.Name, room.XPos, room.YPos, room.WSize, room.HSize, room.Capacity, room.Price, time.Now().Unix()).
@@ -185,13 +189,13 @@ func (suite *roomRepositoryTestSuite) TestRoomRepository_Create_ExpectedError()
 func (suite *roomRepositoryTestSuite) TestRoomRepository_Update_ExpectedSuccess() {
 	room := &model.Room{ID: 1, FloorID: 1, Name: "test", XPos: 1, YPos: 1, WSize: 1, HSize: 1, Capacity: 1, Price: 1}
 	rows := suite.mock.
-		NewRows([]string{"id", "floor_id", "name", "x_pos", "y_pos", "w_size", "h_size", "capacity", "price", "created_at", "updated_at"}).
-		AddRow(1, 1, "test", 1, 2, 3, 4, 5, 1, "13123", "123123")
+		NewRows([]string{"id", "floor_id", "name", "x_pos", "y_pos", "w_size", "h_size", "capacity", "price", "created_at", "updated_at", "deleted_at", "deleted_by"}).
+		AddRow(1, 1, "test", 1, 2, 3, 4, 5, 1, "13123", "123123", nil, nil)
 	q := "UPDATE rooms SET "
 	q += "floor_id = $1, name = $2, x_pos = $3, "
 	q += "y_pos = $4, w_size = $5, h_size = $6, "
 	q += "capacity= $7, price = $8, updated_at = $9 "
-	q += "WHERE id = $10 RETURNING *"
+	q += "WHERE id = $10 AND deleted_at IS NULL RETURNING id, floor_id, name, x_pos, y_pos, w_size, h_size, capacity, price, created_at, updated_at, deleted_at, deleted_by"
 	expectedQuery := regexp.QuoteMeta(q)
 	suite.mock.ExpectQuery(expectedQuery).
 		WithArgs(room.FloorID, room.Name, room.XPos, room.YPos, room.WSize, room.HSize, room.Capacity, room.Price, time.Now().Unix(), room.ID).
@@ -205,13 +209,13 @@ func (suite *roomRepositoryTestSuite) TestRoomRepository_Update_ExpectedSuccess(
 func (suite *roomRepositoryTestSuite) TestRoomRepository_Update_ExpectedError() {
 	room := &model.Room{ID: 1, FloorID: 1, Name: "test", XPos: 1, YPos: 1, WSize: 1, HSize: 1, Capacity: 1, Price: 1}
 	rows := suite.mock.
-		NewRows([]string{"id", "floor_id", "name", "x_pos", "y_pos", "w_size", "h_size", "capacity", "price", "created_at", "updated_at"}).
-		AddRow(nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+		NewRows([]string{"id", "floor_id", "name", "x_pos", "y_pos", "w_size", "h_size", "capacity", "price", "created_at", "updated_at", "deleted_at", "deleted_by"}).
+		AddRow(nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
 	q := "UPDATE rooms SET "
 	q += "floor_id = $1, name = $2, x_pos = $3, "
 	q += "y_pos = $4, w_size = $5, h_size = $6, "
 	q += "capacity= $7, price = $8, updated_at = $9 "
-	q += "WHERE id = $10 RETURNING *"
+	q += "WHERE id = $10 AND deleted_at IS NULL RETURNING id, floor_id, name, x_pos, y_pos, w_size, h_size, capacity, price, created_at, updated_at, deleted_at, deleted_by"
 	expectedQuery := regexp.QuoteMeta(q)
 	suite.mock.ExpectQuery(expectedQuery).
 		WithArgs(room.FloorID, room.Name, room.XPos, room.YPos, room.WSize, room.HSize, room.Capacity, room.Price, time.Now().Unix(), room.ID).
@@ -223,15 +227,44 @@ func (suite *roomRepositoryTestSuite) TestRoomRepository_Update_ExpectedError()
 }
 
 func (suite *roomRepositoryTestSuite) TestRoomRepository_Delete_ExpectedSuccess() {
-	expectedQuery := regexp.QuoteMeta("DELETE FROM rooms WHERE id = $1")
+	expectedQuery := regexp.QuoteMeta("UPDATE rooms SET deleted_at = $1, deleted_by = $2 WHERE id = $3 AND deleted_at IS NULL")
 	suite.mock.ExpectExec(expectedQuery).
-		WithArgs(1).
+		WithArgs(sqlmock.AnyArg(), nil, 1).
 		WillReturnResult(sqlmock.NewResult(0, 1))
 	room := &model.Room{ID: 1}
 	err := suite.roomRepo.Delete(context.TODO(), room)
 	require.Nil(suite.T(), err)
 }
 
+func (suite *roomRepositoryTestSuite) TestRoomRepository_SoftDelete() {
+	dbtest.Migrated(suite.T(), config.Dialect.Name())
+	ctx := context.TODO()
+	repo := repoSql.NewRoomSQLRepository()
+	room, err := repo.Create(ctx, &model.Room{FloorID: 1, Name: "VIP 2",
+		XPos: 1, YPos: 1, WSize: 2, HSize: 2, Capacity: 8, Price: 150000})
+	require.NoError(suite.T(), err)
+	room.Price = 175000
+	room, err = repo.Update(ctx, room)
+	require.NoError(suite.T(), err)
+	require.Equal(suite.T(), money.Amount(175000), room.Price)
+
+	require.NoError(suite.T(), repo.Delete(ctx, room))
+	_, err = repo.Find(ctx, model.FindWithID, room.ID)
+	require.ErrorIs(suite.T(), err, sql.ErrNoRows)
+	rooms, err := repo.AllWhere(context.WithValue(ctx, model.IncludeDeletedKey, true),
+		model.FindWithRelationID, 1)
+	require.NoError(suite.T(), err)
+	require.Len(suite.T(), rooms, 1)
+	_, err = repo.Restore(ctx, room)
+	require.NoError(suite.T(), err)
+	require.NoError(suite.T(), repo.Delete(ctx, room))
+}
+
 func TestRoomRepository(t *testing.T) {
-	suite.Run(t, new(roomRepositoryTestSuite))
+	for _, d := range dialect.All() {
+		t.Run(d.Name(), func(t *testing.T) {
+			config.Dialect = d
+			suite.Run(t, new(roomRepositoryTestSuite))
+		})
+	}
 }
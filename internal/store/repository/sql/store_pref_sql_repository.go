@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/aasumitro/posbe/config"
+	"github.com/aasumitro/posbe/pkg/audit"
 	"github.com/aasumitro/posbe/pkg/model"
 )
 
@@ -51,12 +52,14 @@ func (repo StorePrefSQLRepository) All(ctx context.Context) (prefs *model.StoreS
 
 func (repo StorePrefSQLRepository) Update(ctx context.Context, key, value string) (prefs *model.StoreSetting, err error) {
 	q := "UPDATE store_prefs SET value = $1, updated_at = $2 WHERE key = $3 RETURNING *"
-	row := repo.Db.QueryRowContext(ctx, q, value, time.Now().Unix(), key)
 	var storePref model.StorePref
-	if err := row.Scan(
-		&storePref.Key, &storePref.Value,
-		&storePref.CreatedAt, &storePref.UpdatedAt,
-	); err != nil {
+	if err := audit.Versioned(ctx, repo.Db, func(tx *sql.Tx) error {
+		row := tx.QueryRowContext(ctx, q, value, time.Now().Unix(), key)
+		return row.Scan(
+			&storePref.Key, &storePref.Value,
+			&storePref.CreatedAt, &storePref.UpdatedAt,
+		)
+	}); err != nil {
 		return nil, err
 	}
 	return &model.StoreSetting{
@@ -65,5 +68,5 @@ func (repo StorePrefSQLRepository) Update(ctx context.Context, key, value string
 }
 
 func NewStorePrefSQLRepository() model.IStorePrefRepository {
-	return &StorePrefSQLRepository{Db: config.PostgresPool}
+	return &StorePrefSQLRepository{Db: config.DbPool}
 }
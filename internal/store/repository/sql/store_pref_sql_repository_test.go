@@ -2,6 +2,7 @@ package sql_test
 
 import (
 	"context"
+	"database/sql"
 	"errors"
 	"regexp"
 	"testing"
@@ -10,6 +11,8 @@ import (
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/aasumitro/posbe/config"
 	repoSql "github.com/aasumitro/posbe/internal/store/repository/sql"
+	"github.com/aasumitro/posbe/pkg/dbtest"
+	"github.com/aasumitro/posbe/pkg/dialect"
 	"github.com/aasumitro/posbe/pkg/model"
 	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
@@ -24,7 +27,7 @@ type storePrefRepositoryTestSuite struct {
 func (suite *storePrefRepositoryTestSuite) SetupSuite() {
 	var err error
 
-	config.PostgresPool, suite.mock, err = sqlmock.New(
+	config.DbPool, suite.mock, err = sqlmock.New(
 		sqlmock.QueryMatcherOption(
 			sqlmock.QueryMatcherRegexp))
 	require.NoError(suite.T(), err)
@@ -106,10 +109,12 @@ func (suite *storePrefRepositoryTestSuite) TestStorePrefsRepository_Update_Expec
 		AddRow("test", "test", 123, 123)
 	q := "UPDATE store_prefs SET value = $1, updated_at = $2 WHERE key = $3 RETURNING *"
 	expectedQuery := regexp.QuoteMeta(q)
+	expectVersioned(suite.mock)
 	suite.mock.ExpectQuery(expectedQuery).
 		WithArgs("test", time.Now().Unix(), "test").
 		WillReturnRows(pref).
 		WillReturnError(nil)
+	expectVersionedCommit(suite.mock)
 	res, err := suite.storePref.Update(context.TODO(), "test", "test")
 	require.Nil(suite.T(), err)
 	require.NoError(suite.T(), err)
@@ -122,14 +127,54 @@ func (suite *storePrefRepositoryTestSuite) TestStorePrefsRepository_Update_Expec
 		AddRow(nil, nil, nil, nil)
 	q := "UPDATE store_prefs SET value = $1, updated_at = $2 WHERE key = $3 RETURNING *"
 	expectedQuery := regexp.QuoteMeta(q)
+	expectVersioned(suite.mock)
 	suite.mock.ExpectQuery(expectedQuery).
 		WithArgs("test", time.Now().Unix(), "test").
 		WillReturnRows(pref)
+	suite.mock.ExpectRollback()
 	res, err := suite.storePref.Update(context.TODO(), "test", "test")
 	require.Nil(suite.T(), res)
 	require.NotNil(suite.T(), err)
 }
 
+func (suite *storePrefRepositoryTestSuite) TestStorePrefRepository_Update() {
+	dbtest.Migrated(suite.T(), config.Dialect.Name())
+	ctx := context.TODO()
+	repo := repoSql.NewStorePrefSQLRepository()
+	pref, err := repo.Update(ctx, "name", "Ipsum Store")
+	require.NoError(suite.T(), err)
+	require.Equal(suite.T(), "Ipsum Store", (*pref)["name"])
+	pref, err = repo.Find(ctx, "name")
+	require.NoError(suite.T(), err)
+	require.Equal(suite.T(), "Ipsum Store", (*pref)["name"])
+	prefs, err := repo.All(ctx)
+	require.NoError(suite.T(), err)
+	require.Equal(suite.T(), "Ipsum Store", (*prefs)["name"])
+	_, err = repo.Update(ctx, "lorem", "ipsum")
+	require.ErrorIs(suite.T(), err, sql.ErrNoRows)
+}
+
 func TestStorePrefRepository(t *testing.T) {
-	suite.Run(t, new(storePrefRepositoryTestSuite))
+	for _, d := range dialect.All() {
+		t.Run(d.Name(), func(t *testing.T) {
+			config.Dialect = d
+			suite.Run(t, new(storePrefRepositoryTestSuite))
+		})
+	}
+}
+
+// expectVersioned expect the transaction of a versioned write with
+// the actor of the dialect, the rollback is expected by the test
+func expectVersioned(mock sqlmock.Sqlmock) {
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(config.Dialect.SetActor())).
+		WithArgs("").WillReturnResult(sqlmock.NewResult(0, 0))
+}
+
+// expectVersionedCommit expect the actor to be reset before commit
+func expectVersionedCommit(mock sqlmock.Sqlmock) {
+	if q := config.Dialect.ResetActor(); q != "" {
+		mock.ExpectExec(regexp.QuoteMeta(q)).WillReturnResult(sqlmock.NewResult(0, 1))
+	}
+	mock.ExpectCommit()
 }
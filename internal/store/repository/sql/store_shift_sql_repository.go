@@ -24,6 +24,7 @@ func (repo StoreShiftSQLRepository) All(
 	if err != nil {
 		return nil, err
 	}
+	defer func(rows *sql.Rows) { _ = rows.Close() }(rows)
 	for rows.Next() {
 		var s model.Shift
 		if err := rows.Scan(
@@ -136,9 +137,11 @@ func (repo StoreShiftSQLRepository) OpenShift(
 	qssi := "INSERT INTO store_shifts "
 	qssi += "(shift_id, open_at, open_by, open_cash, created_at) "
 	qssi += " VALUES ($1, $2, $3, $4, $5) RETURNING id"
+	// scan the returned id so the statement is released
+	var id int
 	return repo.Db.QueryRowContext(ctx, qssi,
 		form.ShiftID, time.Now().Unix(), form.UserID,
-		form.Cash, time.Now().Unix()).Err()
+		form.Cash, time.Now().Unix()).Scan(&id)
 }
 
 func (repo StoreShiftSQLRepository) CloseShift(
@@ -152,12 +155,13 @@ func (repo StoreShiftSQLRepository) CloseShift(
 	q += "close_at = $1, close_by = $2, "
 	q += "close_cash = $3, updated_at = $4 "
 	q += " WHERE id = $5 AND shift_id = $6 RETURNING id"
+	var id int
 	return repo.Db.QueryRowContext(ctx, q,
 		time.Now().Unix(), form.UserID,
 		form.Cash, time.Now().Unix(),
-		form.ID, form.ShiftID).Err()
+		form.ID, form.ShiftID).Scan(&id)
 }
 
 func NewStoreShiftSQLRepository() model.IStoreShiftRepository {
-	return &StoreShiftSQLRepository{Db: config.PostgresPool}
+	return &StoreShiftSQLRepository{Db: config.DbPool}
 }
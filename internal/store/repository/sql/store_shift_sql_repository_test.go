@@ -2,6 +2,7 @@ package sql_test
 
 import (
 	"context"
+	"database/sql"
 	"errors"
 	"regexp"
 	"testing"
@@ -10,6 +11,8 @@ import (
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/aasumitro/posbe/config"
 	repoSql "github.com/aasumitro/posbe/internal/store/repository/sql"
+	"github.com/aasumitro/posbe/pkg/dbtest"
+	"github.com/aasumitro/posbe/pkg/dialect"
 	"github.com/aasumitro/posbe/pkg/model"
 	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
@@ -24,7 +27,7 @@ type shiftRepositoryTestSuite struct {
 func (suite *shiftRepositoryTestSuite) SetupSuite() {
 	var err error
 
-	config.PostgresPool, suite.mock, err = sqlmock.New(
+	config.DbPool, suite.mock, err = sqlmock.New(
 		sqlmock.QueryMatcherOption(
 			sqlmock.QueryMatcherRegexp))
 	require.NoError(suite.T(), err)
@@ -36,8 +39,35 @@ func (suite *shiftRepositoryTestSuite) AfterTest(_, _ string) {
 	require.NoError(suite.T(), suite.mock.ExpectationsWereMet())
 }
 
+func (suite *shiftRepositoryTestSuite) TestStoreShiftRepository_OpenClose() {
+	dbtest.Migrated(suite.T(), config.Dialect.Name())
+	ctx := context.TODO()
+	repo := repoSql.NewStoreShiftSQLRepository()
+	shift, err := repo.Create(ctx, &model.Shift{Name: "night", StartTime: 1200, EndTime: 1500})
+	require.NoError(suite.T(), err)
+	shift.EndTime = 1600
+	shift, err = repo.Update(ctx, shift)
+	require.NoError(suite.T(), err)
+	require.Equal(suite.T(), int64(1600), shift.EndTime)
+	shifts, err := repo.All(ctx)
+	require.NoError(suite.T(), err)
+	require.Len(suite.T(), shifts, 3)
+
+	require.NoError(suite.T(), repo.OpenShift(ctx, &model.StoreShiftForm{
+		UserID: 1, ShiftID: shift.ID, Cash: 100000}))
+	require.NoError(suite.T(), repo.CloseShift(ctx, &model.StoreShiftForm{
+		ID: 1, UserID: 1, ShiftID: shift.ID, Cash: 250000}))
+	require.ErrorIs(suite.T(), repo.CloseShift(ctx, &model.StoreShiftForm{
+		ID: 1, UserID: 1, ShiftID: 1, Cash: 250000}), sql.ErrNoRows)
+}
+
 func TestShiftRepository(t *testing.T) {
-	suite.Run(t, new(shiftRepositoryTestSuite))
+	for _, d := range dialect.All() {
+		t.Run(d.Name(), func(t *testing.T) {
+			config.Dialect = d
+			suite.Run(t, new(shiftRepositoryTestSuite))
+		})
+	}
 }
 
 func (suite *shiftRepositoryTestSuite) TestShiftRepository_All_ExpectReturnData() {
@@ -6,61 +6,64 @@ import (
 	"time"
 
 	"github.com/aasumitro/posbe/config"
+	"github.com/aasumitro/posbe/pkg/audit"
 	"github.com/aasumitro/posbe/pkg/model"
 )
 
+const tableColumns = "id, floor_id, name, x_pos, y_pos, w_size, h_size, capacity, type, " +
+	"created_at, updated_at, deleted_at, deleted_by"
+
 type TableSQLRepository struct {
 	Db *sql.DB
 }
 
 func (repo TableSQLRepository) AllWhere(ctx context.Context, key model.FindWith, val any) (tables []*model.Table, err error) {
-	q := "SELECT * FROM tables "
+	q := "SELECT " + tableColumns + " FROM tables WHERE ($1 OR deleted_at IS NULL) "
+	args := []any{model.IncludeDeleted(ctx)}
 	if key == model.FindWithRelationID {
-		q += "WHERE floor_id = $1"
+		q += "AND floor_id = $2"
+		args = append(args, val)
 	}
-	rows, err := repo.Db.QueryContext(ctx, q, val)
+	rows, err := repo.Db.QueryContext(ctx, q, args...)
 	if err != nil {
 		return nil, err
 	}
+	defer func(rows *sql.Rows) { _ = rows.Close() }(rows)
+
 	for rows.Next() {
-		var table model.Table
-		if err := rows.Scan(
-			&table.ID, &table.FloorID, &table.Name,
-			&table.XPos, &table.YPos, &table.WSize,
-			&table.HSize, &table.Capacity, &table.Type,
-			&table.CreatedAt, &table.UpdatedAt,
-		); err != nil {
+		table, err := scanTable(rows)
+		if err != nil {
 			return nil, err
 		}
-		tables = append(tables, &table)
+
+		tables = append(tables, table)
 	}
+
 	return tables, nil
 }
 
 func (repo TableSQLRepository) All(ctx context.Context) (tables []*model.Table, err error) {
-	q := "SELECT * FROM tables"
-	rows, err := repo.Db.QueryContext(ctx, q)
+	q := "SELECT " + tableColumns + " FROM tables WHERE ($1 OR deleted_at IS NULL)"
+	rows, err := repo.Db.QueryContext(ctx, q, model.IncludeDeleted(ctx))
 	if err != nil {
 		return nil, err
 	}
+	defer func(rows *sql.Rows) { _ = rows.Close() }(rows)
+
 	for rows.Next() {
-		var table model.Table
-		if err := rows.Scan(
-			&table.ID, &table.FloorID, &table.Name,
-			&table.XPos, &table.YPos, &table.WSize,
-			&table.HSize, &table.Capacity, &table.Type,
-			&table.CreatedAt, &table.UpdatedAt,
-		); err != nil {
+		table, err := scanTable(rows)
+		if err != nil {
 			return nil, err
 		}
-		tables = append(tables, &table)
+
+		tables = append(tables, table)
 	}
 
 	return tables, nil
 }
 
 func (repo TableSQLRepository) Find(ctx context.Context, key model.FindWith, val any) (table *model.Table, err error) {
-	q := "SELECT * FROM tables WHERE "
+	q := "SELECT " + tableColumns + " FROM tables WHERE ($2 OR deleted_at IS NULL) AND "
 	//goland:noinspection ALL
 	switch key {
 	case model.FindWithID:
@@ -69,36 +72,21 @@ func (repo TableSQLRepository) Find(ctx context.Context, key model.FindWith, val
 		q += "floor_id = $1 "
 	}
 	q += "LIMIT 1"
-	row := repo.Db.QueryRowContext(ctx, q, val)
-	table = &model.Table{}
-	if err := row.Scan(
-		&table.ID, &table.FloorID, &table.Name,
-		&table.XPos, &table.YPos, &table.WSize,
-		&table.HSize, &table.Capacity, &table.Type,
-		&table.CreatedAt, &table.UpdatedAt,
-	); err != nil {
-		return nil, err
-	}
-	return table, nil
+	row := repo.Db.QueryRowContext(ctx, q, val, model.IncludeDeleted(ctx))
+
+	return scanTable(row)
 }
 
 func (repo TableSQLRepository) Create(ctx context.Context, params *model.Table) (table *model.Table, err error) {
 	q := "INSERT INTO tables (floor_id, name, x_pos,  "
 	q += "y_pos, w_size, h_size, capacity, type, created_at) "
-	q += "values ($1, $2, $3, $4, $5, $6, $7, $8, $9) RETURNING *"
+	q += "values ($1, $2, $3, $4, $5, $6, $7, $8, $9) RETURNING " + tableColumns
 	row := repo.Db.QueryRowContext(ctx, q,
 		params.FloorID, params.Name, params.XPos,
 		params.YPos, params.WSize, params.HSize,
 		params.Capacity, params.Type, time.Now().Unix())
-	table = &model.Table{}
-	if err := row.Scan(&table.ID, &table.FloorID, &table.Name,
-		&table.XPos, &table.YPos, &table.WSize,
-		&table.HSize, &table.Capacity, &table.Type,
-		&table.CreatedAt, &table.UpdatedAt,
-	); err != nil {
-		return nil, err
-	}
-	return table, nil
+
+	return scanTable(row)
 }
 
 func (repo TableSQLRepository) Update(ctx context.Context, params *model.Table) (table *model.Table, err error) {
@@ -106,29 +94,46 @@ func (repo TableSQLRepository) Update(ctx context.Context, params *model.Table)
 	q += "floor_id = $1, name = $2, x_pos = $3, "
 	q += "y_pos = $4, w_size = $5, h_size = $6, "
 	q += "capacity= $7, type = $8, updated_at = $9 "
-	q += "WHERE id = $10 RETURNING *"
+	q += "WHERE id = $10 AND deleted_at IS NULL RETURNING " + tableColumns
 	row := repo.Db.QueryRowContext(ctx, q,
 		params.FloorID, params.Name, params.XPos,
 		params.YPos, params.WSize, params.HSize,
 		params.Capacity, params.Type,
 		time.Now().Unix(), params.ID)
+
+	return scanTable(row)
+}
+
+func (repo TableSQLRepository) Delete(ctx context.Context, params *model.Table) error {
+	q := "UPDATE tables SET deleted_at = $1, deleted_by = $2 WHERE id = $3 AND deleted_at IS NULL"
+	_, err := repo.Db.ExecContext(ctx, q, time.Now().Unix(), audit.NullActor(ctx), params.ID)
+	return err
+}
+
+func (repo TableSQLRepository) Restore(ctx context.Context, params *model.Table) (table *model.Table, err error) {
+	q := "UPDATE tables SET deleted_at = NULL, deleted_by = NULL "
+	q += "WHERE id = $1 AND deleted_at IS NOT NULL RETURNING " + tableColumns
+	row := repo.Db.QueryRowContext(ctx, q, params.ID)
+
+	return scanTable(row)
+}
+
+// scanTable read a row of tableColumns
+func scanTable(row scanner) (table *model.Table, err error) {
 	table = &model.Table{}
-	if err := row.Scan(&table.ID, &table.FloorID, &table.Name,
+	if err := row.Scan(
+		&table.ID, &table.FloorID, &table.Name,
 		&table.XPos, &table.YPos, &table.WSize,
 		&table.HSize, &table.Capacity, &table.Type,
 		&table.CreatedAt, &table.UpdatedAt,
+		&table.DeletedAt, &table.DeletedBy,
 	); err != nil {
 		return nil, err
 	}
-	return table, nil
-}
 
-func (repo TableSQLRepository) Delete(ctx context.Context, params *model.Table) error {
-	q := "DELETE FROM tables WHERE id = $1"
-	_, err := repo.Db.ExecContext(ctx, q, params.ID)
-	return err
+	return table, nil
 }
 
 func NewTableSQLRepository() model.ICRUDAddOnRepository[model.Table] {
-	return &TableSQLRepository{Db: config.PostgresPool}
+	return &TableSQLRepository{Db: config.DbPool}
 }
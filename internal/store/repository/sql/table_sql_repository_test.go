@@ -10,6 +10,8 @@ import (
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/aasumitro/posbe/config"
 	repoSql "github.com/aasumitro/posbe/internal/store/repository/sql"
+	"github.com/aasumitro/posbe/pkg/dbtest"
+	"github.com/aasumitro/posbe/pkg/dialect"
 	"github.com/aasumitro/posbe/pkg/model"
 	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
@@ -24,7 +26,7 @@ type tableRepositoryTestSuite struct {
 func (suite *tableRepositoryTestSuite) SetupSuite() {
 	var err error
 
-	config.PostgresPool, suite.mock, err = sqlmock.New(
+	config.DbPool, suite.mock, err = sqlmock.New(
 		sqlmock.QueryMatcherOption(
 			sqlmock.QueryMatcherRegexp))
 	require.NoError(suite.T(), err)
@@ -38,10 +40,10 @@ func (suite *tableRepositoryTestSuite) AfterTest(_, _ string) {
 
 func (suite *tableRepositoryTestSuite) TestTableRepository_AllWhere_ExpectedReturnDataRows() {
 	tables := suite.mock.
-		NewRows([]string{"id", "floor_id", "name", "x_pos", "y_pos", "w_size", "h_size", "capacity", "type", "created_at", "updated_at"}).
-		AddRow(1, 1, "test", 1, 2, 3, 4, 5, "sq", "13123", "123123").
-		AddRow(2, 1, "test 2", 1, 2, 3, 4, 5, "sq", "13123", "123123")
-	q := "SELECT * FROM tables WHERE floor_id = $1"
+		NewRows([]string{"id", "floor_id", "name", "x_pos", "y_pos", "w_size", "h_size", "capacity", "type", "created_at", "updated_at", "deleted_at", "deleted_by"}).
+		AddRow(1, 1, "test", 1, 2, 3, 4, 5, "sq", "13123", "123123", nil, nil).
+		AddRow(2, 1, "test 2", 1, 2, 3, 4, 5, "sq", "13123", "123123", nil, nil)
+	q := "SELECT id, floor_id, name, x_pos, y_pos, w_size, h_size, capacity, type, created_at, updated_at, deleted_at, deleted_by FROM tables WHERE ($1 OR deleted_at IS NULL) AND floor_id = $2"
 	expectedQuery := regexp.QuoteMeta(q)
 	suite.mock.ExpectQuery(expectedQuery).WillReturnRows(tables)
 	res, err := suite.tableRepo.AllWhere(context.TODO(), model.FindWithRelationID, 1)
@@ -51,7 +53,7 @@ func (suite *tableRepositoryTestSuite) TestTableRepository_AllWhere_ExpectedRetu
 }
 
 func (suite *tableRepositoryTestSuite) TestTableRepository_AllWhere_ExpectedReturnErrorFromQuery() {
-	q := "SELECT * FROM tables WHERE floor_id = $1"
+	q := "SELECT id, floor_id, name, x_pos, y_pos, w_size, h_size, capacity, type, created_at, updated_at, deleted_at, deleted_by FROM tables WHERE ($1 OR deleted_at IS NULL) AND floor_id = $2"
 	expectedQuery := regexp.QuoteMeta(q)
 	suite.mock.ExpectQuery(expectedQuery).WillReturnError(errors.New(""))
 	res, err := suite.tableRepo.AllWhere(context.TODO(), model.FindWithRelationID, 1)
@@ -61,10 +63,10 @@ func (suite *tableRepositoryTestSuite) TestTableRepository_AllWhere_ExpectedRetu
 
 func (suite *tableRepositoryTestSuite) TestTableRepository_AllWhere_ExpectedReturnErrorFromScan() {
 	tables := suite.mock.
-		NewRows([]string{"id", "floor_id", "name", "x_pos", "y_pos", "w_size", "h_size", "capacity", "type", "created_at", "updated_at"}).
-		AddRow(1, 1, "test", 1, 2, 3, 4, 5, "sq", "13123", "123123").
-		AddRow(nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
-	q := "SELECT * FROM tables WHERE floor_id = $1"
+		NewRows([]string{"id", "floor_id", "name", "x_pos", "y_pos", "w_size", "h_size", "capacity", "type", "created_at", "updated_at", "deleted_at", "deleted_by"}).
+		AddRow(1, 1, "test", 1, 2, 3, 4, 5, "sq", "13123", "123123", nil, nil).
+		AddRow(nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+	q := "SELECT id, floor_id, name, x_pos, y_pos, w_size, h_size, capacity, type, created_at, updated_at, deleted_at, deleted_by FROM tables WHERE ($1 OR deleted_at IS NULL) AND floor_id = $2"
 	expectedQuery := regexp.QuoteMeta(q)
 	suite.mock.ExpectQuery(expectedQuery).WillReturnRows(tables)
 	res, err := suite.tableRepo.AllWhere(context.TODO(), model.FindWithRelationID, 1)
@@ -74,10 +76,10 @@ func (suite *tableRepositoryTestSuite) TestTableRepository_AllWhere_ExpectedRetu
 
 func (suite *tableRepositoryTestSuite) TestTableRepository_All_ExpectedReturnDataRows() {
 	tables := suite.mock.
-		NewRows([]string{"id", "floor_id", "name", "x_pos", "y_pos", "w_size", "h_size", "capacity", "type", "created_at", "updated_at"}).
-		AddRow(1, 1, "test", 1, 2, 3, 4, 5, "sq", "13123", "123123").
-		AddRow(2, 1, "test 2", 1, 2, 3, 4, 5, "sq", "13123", "123123")
-	q := "SELECT * FROM tables"
+		NewRows([]string{"id", "floor_id", "name", "x_pos", "y_pos", "w_size", "h_size", "capacity", "type", "created_at", "updated_at", "deleted_at", "deleted_by"}).
+		AddRow(1, 1, "test", 1, 2, 3, 4, 5, "sq", "13123", "123123", nil, nil).
+		AddRow(2, 1, "test 2", 1, 2, 3, 4, 5, "sq", "13123", "123123", nil, nil)
+	q := "SELECT id, floor_id, name, x_pos, y_pos, w_size, h_size, capacity, type, created_at, updated_at, deleted_at, deleted_by FROM tables WHERE ($1 OR deleted_at IS NULL)"
 	expectedQuery := regexp.QuoteMeta(q)
 	suite.mock.ExpectQuery(expectedQuery).WillReturnRows(tables)
 	res, err := suite.tableRepo.All(context.TODO())
@@ -87,7 +89,7 @@ func (suite *tableRepositoryTestSuite) TestTableRepository_All_ExpectedReturnDat
 }
 
 func (suite *tableRepositoryTestSuite) TestTableRepository_All_ExpectedReturnErrorFromQuery() {
-	q := "SELECT * FROM tables"
+	q := "SELECT id, floor_id, name, x_pos, y_pos, w_size, h_size, capacity, type, created_at, updated_at, deleted_at, deleted_by FROM tables WHERE ($1 OR deleted_at IS NULL)"
 	expectedQuery := regexp.QuoteMeta(q)
 	suite.mock.ExpectQuery(expectedQuery).WillReturnError(errors.New(""))
 	res, err := suite.tableRepo.All(context.TODO())
@@ -97,10 +99,10 @@ func (suite *tableRepositoryTestSuite) TestTableRepository_All_ExpectedReturnErr
 
 func (suite *tableRepositoryTestSuite) TestTableRepository_All_ExpectedReturnErrorFromScan() {
 	tables := suite.mock.
-		NewRows([]string{"id", "floor_id", "name", "x_pos", "y_pos", "w_size", "h_size", "capacity", "type", "created_at", "updated_at"}).
-		AddRow(1, 1, "test", 1, 2, 3, 4, 5, "sq", "13123", "123123").
-		AddRow(nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
-	q := "SELECT * FROM tables"
+		NewRows([]string{"id", "floor_id", "name", "x_pos", "y_pos", "w_size", "h_size", "capacity", "type", "created_at", "updated_at", "deleted_at", "deleted_by"}).
+		AddRow(1, 1, "test", 1, 2, 3, 4, 5, "sq", "13123", "123123", nil, nil).
+		AddRow(nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+	q := "SELECT id, floor_id, name, x_pos, y_pos, w_size, h_size, capacity, type, created_at, updated_at, deleted_at, deleted_by FROM tables WHERE ($1 OR deleted_at IS NULL)"
 	expectedQuery := regexp.QuoteMeta(q)
 	suite.mock.ExpectQuery(expectedQuery).WillReturnRows(tables)
 	res, err := suite.tableRepo.All(context.TODO())
@@ -110,9 +112,9 @@ func (suite *tableRepositoryTestSuite) TestTableRepository_All_ExpectedReturnErr
 
 func (suite *tableRepositoryTestSuite) TestTableRepository_Find_ExpectedSuccess() {
 	table := suite.mock.
-		NewRows([]string{"id", "floor_id", "name", "x_pos", "y_pos", "w_size", "h_size", "capacity", "type", "created_at", "updated_at"}).
-		AddRow(1, 1, "test", 1, 2, 3, 4, 5, "sq", "13123", "123123")
-	q := "SELECT * FROM tables WHERE id = $1 LIMIT 1"
+		NewRows([]string{"id", "floor_id", "name", "x_pos", "y_pos", "w_size", "h_size", "capacity", "type", "created_at", "updated_at", "deleted_at", "deleted_by"}).
+		AddRow(1, 1, "test", 1, 2, 3, 4, 5, "sq", "13123", "123123", nil, nil)
+	q := "SELECT id, floor_id, name, x_pos, y_pos, w_size, h_size, capacity, type, created_at, updated_at, deleted_at, deleted_by FROM tables WHERE ($2 OR deleted_at IS NULL) AND id = $1 LIMIT 1"
 	expectedQuery := regexp.QuoteMeta(q)
 	suite.mock.ExpectQuery(expectedQuery).WillReturnRows(table)
 	res, err := suite.tableRepo.Find(context.TODO(), model.FindWithID, 1)
@@ -123,9 +125,9 @@ func (suite *tableRepositoryTestSuite) TestTableRepository_Find_ExpectedSuccess(
 
 func (suite *tableRepositoryTestSuite) TestTableRepository_FindRelation_ExpectedSuccess() {
 	table := suite.mock.
-		NewRows([]string{"id", "floor_id", "name", "x_pos", "y_pos", "w_size", "h_size", "capacity", "type", "created_at", "updated_at"}).
-		AddRow(1, 1, "test", 1, 2, 3, 4, 5, "sq", "13123", "123123")
-	q := "SELECT * FROM tables WHERE floor_id = $1 LIMIT 1"
+		NewRows([]string{"id", "floor_id", "name", "x_pos", "y_pos", "w_size", "h_size", "capacity", "type", "created_at", "updated_at", "deleted_at", "deleted_by"}).
+		AddRow(1, 1, "test", 1, 2, 3, 4, 5, "sq", "13123", "123123", nil, nil)
+	q := "SELECT id, floor_id, name, x_pos, y_pos, w_size, h_size, capacity, type, created_at, updated_at, deleted_at, deleted_by FROM tables WHERE ($2 OR deleted_at IS NULL) AND floor_id = $1 LIMIT 1"
 	expectedQuery := regexp.QuoteMeta(q)
 	suite.mock.ExpectQuery(expectedQuery).WillReturnRows(table)
 	res, err := suite.tableRepo.Find(context.TODO(), model.FindWithRelationID, 1)
@@ -136,9 +138,9 @@ func (suite *tableRepositoryTestSuite) TestTableRepository_FindRelation_Expected
 
 func (suite *tableRepositoryTestSuite) TestTableRepository_Find_ExpectedError() {
 	table := suite.mock.
-		NewRows([]string{"id", "floor_id", "name", "x_pos", "y_pos", "w_size", "h_size", "capacity", "type", "created_at", "updated_at"}).
-		AddRow(nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
-	q := "SELECT * FROM tables WHERE id = $1"
+		NewRows([]string{"id", "floor_id", "name", "x_pos", "y_pos", "w_size", "h_size", "capacity", "type", "created_at", "updated_at", "deleted_at", "deleted_by"}).
+		AddRow(nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+	q := "SELECT id, floor_id, name, x_pos, y_pos, w_size, h_size, capacity, type, created_at, updated_at, deleted_at, deleted_by FROM tables WHERE ($2 OR deleted_at IS NULL) AND id = $1"
 	expectedQuery := regexp.QuoteMeta(q)
 	suite.mock.ExpectQuery(expectedQuery).WillReturnRows(table)
 	res, err := suite.tableRepo.Find(context.TODO(), model.FindWithID, 1)
@@ -149,11 +151,11 @@ func (suite *tableRepositoryTestSuite) TestTableRepository_Find_ExpectedError()
 func (suite *tableRepositoryTestSuite) TestTableRepository_Create_ExpectedSuccess() {
 	table := &model.Table{FloorID: 1, Name: "test", XPos: 1, YPos: 1, WSize: 1, HSize: 1, Capacity: 1, Type: "sq"}
 	rows := suite.mock.
-		NewRows([]string{"id", "floor_id", "name", "x_pos", "y_pos", "w_size", "h_size", "capacity", "type", "created_at", "updated_at"}).
-		AddRow(1, 1, "test", 1, 2, 3, 4, 5, "sq", "13123", "123123")
+		NewRows([]string{"id", "floor_id", "name", "x_pos", "y_pos", "w_size", "h_size", "capacity", "type", "created_at", "updated_at", "deleted_at", "deleted_by"}).
+		AddRow(1, 1, "test", 1, 2, 3, 4, 5, "sq", "13123", "123123", nil, nil)
 	q := "INSERT INTO tables (floor_id, name, x_pos,  "
 	q += "y_pos, w_size, h_size, capacity, type, created_at) "
-	q += "values ($1, $2, $3, $4, $5, $6, $7, $8, $9) RETURNING *"
+	q += "values ($1, $2, $3, $4, $5, $6, $7, $8, $9) RETURNING id, floor_id, name, x_pos, y_pos, w_size, h_size, capacity, type, created_at, updated_at, deleted_at, deleted_by"
 	expectedQuery := regexp.QuoteMeta(q)
 	suite.mock.ExpectQuery(expectedQuery).
 		WithArgs(table.FloorID, table.Name, table.XPos, table.YPos, table.WSize, table.HSize, table.Capacity, table.Type, time.Now().Unix()).
@@ -167,11 +169,11 @@ func (suite *tableRepositoryTestSuite) TestTableRepository_Create_ExpectedSucces
 func (suite *tableRepositoryTestSuite) TestTableRepository_Create_ExpectedError() {
 	table := &model.Table{FloorID: 1, Name: "test", XPos: 1, YPos: 1, WSize: 1, HSize: 1, Capacity: 1, Type: "sq"}
 	rows := suite.mock.
-		NewRows([]string{"id", "floor_id", "name", "x_pos", "y_pos", "w_size", "h_size", "capacity", "type", "created_at", "updated_at"}).
-		AddRow(nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+		NewRows([]string{"id", "floor_id", "name", "x_pos", "y_pos", "w_size", "h_size", "capacity", "type", "created_at", "updated_at", "deleted_at", "deleted_by"}).
+		AddRow(nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
 	q := "INSERT INTO tables (floor_id, name, x_pos,  "
 	q += "y_pos, w_size, h_size, capacity, type, created_at) "
-	q += "values ($1, $2, $3, $4, $5, $6, $7, $8, $9) RETURNING *"
+	q += "values ($1, $2, $3, $4, $5, $6, $7, $8, $9) RETURNING id, floor_id, name, x_pos, y_pos, w_size, h_size, capacity, type, created_at, updated_at, deleted_at, deleted_by"
 	expectedQuery := regexp.QuoteMeta(q)
 	suite.mock.ExpectQuery(expectedQuery).
 		WithArgs(table.FloorID, table.Name, table.XPos, table.YPos, table.WSize, table.HSize, table.Capacity, table.Type, time.Now().Unix()).
@@ -185,13 +187,13 @@ func (suite *tableRepositoryTestSuite) TestTableRepository_Create_ExpectedError(
 func (suite *tableRepositoryTestSuite) TestTableRepository_Update_ExpectedSuccess() {
 	table := &model.Table{ID: 1, FloorID: 1, Name: "test", XPos: 1, YPos: 1, WSize: 1, HSize: 1, Capacity: 1, Type: "sq"}
 	rows := suite.mock.
-		NewRows([]string{"id", "floor_id", "name", "x_pos", "y_pos", "w_size", "h_size", "capacity", "type", "created_at", "updated_at"}).
-		AddRow(1, 1, "test", 1, 2, 3, 4, 5, "sq", "13123", "123123")
+		NewRows([]string{"id", "floor_id", "name", "x_pos", "y_pos", "w_size", "h_size", "capacity", "type", "created_at", "updated_at", "deleted_at", "deleted_by"}).
+		AddRow(1, 1, "test", 1, 2, 3, 4, 5, "sq", "13123", "123123", nil, nil)
 	q := "UPDATE tables SET "
 	q += "floor_id = $1, name = $2, x_pos = $3, "
 	q += "y_pos = $4, w_size = $5, h_size = $6, "
 	q += "capacity= $7, type = $8, updated_at = $9 "
-	q += "WHERE id = $10 RETURNING *"
+	q += "WHERE id = $10 AND deleted_at IS NULL RETURNING id, floor_id, name, x_pos, y_pos, w_size, h_size, capacity, type, created_at, updated_at, deleted_at, deleted_by"
 	expectedQuery := regexp.QuoteMeta(q)
 	suite.mock.ExpectQuery(expectedQuery).
 		WithArgs(table.FloorID, table.Name, table.XPos, table.YPos, table.WSize, table.HSize, table.Capacity, table.Type, time.Now().Unix(), table.ID).
@@ -205,13 +207,13 @@ func (suite *tableRepositoryTestSuite) TestTableRepository_Update_ExpectedSucces
 func (suite *tableRepositoryTestSuite) TestTableRepository_Update_ExpectedError() {
 	table := &model.Table{ID: 1, FloorID: 1, Name: "test", XPos: 1, YPos: 1, WSize: 1, HSize: 1, Capacity: 1, Type: "sq"}
 	rows := suite.mock.
-		NewRows([]string{"id", "floor_id", "name", "x_pos", "y_pos", "w_size", "h_size", "capacity", "type", "created_at", "updated_at"}).
-		AddRow(nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+		NewRows([]string{"id", "floor_id", "name", "x_pos", "y_pos", "w_size", "h_size", "capacity", "type", "created_at", "updated_at", "deleted_at", "deleted_by"}).
+		AddRow(nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
 	q := "UPDATE tables SET "
 	q += "floor_id = $1, name = $2, x_pos = $3, "
 	q += "y_pos = $4, w_size = $5, h_size = $6, "
 	q += "capacity= $7, type = $8, updated_at = $9 "
-	q += "WHERE id = $10 RETURNING *"
+	q += "WHERE id = $10 AND deleted_at IS NULL RETURNING id, floor_id, name, x_pos, y_pos, w_size, h_size, capacity, type, created_at, updated_at, deleted_at, deleted_by"
 	expectedQuery := regexp.QuoteMeta(q)
 	suite.mock.ExpectQuery(expectedQuery).
 		WithArgs(table.FloorID, table.Name, table.XPos, table.YPos, table.WSize, table.HSize, table.Capacity, table.Type, time.Now().Unix(), table.ID).
@@ -223,15 +225,59 @@ func (suite *tableRepositoryTestSuite) TestTableRepository_Update_ExpectedError(
 }
 
 func (suite *tableRepositoryTestSuite) TestTableRepository_Delete_ExpectedSuccess() {
-	expectedQuery := regexp.QuoteMeta("DELETE FROM table")
+	expectedQuery := regexp.QuoteMeta("UPDATE tables SET deleted_at = $1, deleted_by = $2 WHERE id = $3 AND deleted_at IS NULL")
 	suite.mock.ExpectExec(expectedQuery).
-		WithArgs(1).
+		WithArgs(sqlmock.AnyArg(), nil, 1).
 		WillReturnResult(sqlmock.NewResult(0, 1))
 	table := &model.Table{ID: 1}
 	err := suite.tableRepo.Delete(context.TODO(), table)
 	require.Nil(suite.T(), err)
 }
 
+func (suite *tableRepositoryTestSuite) TestTableRepository_Restore_ExpectedSuccess() {
+	rows := suite.mock.
+		NewRows([]string{"id", "floor_id", "name", "x_pos", "y_pos", "w_size", "h_size", "capacity", "type", "created_at", "updated_at", "deleted_at", "deleted_by"}).
+		AddRow(1, 1, "test", 1, 2, 3, 4, 5, "sq", "13123", "123123", nil, nil)
+	q := "UPDATE tables SET deleted_at = NULL, deleted_by = NULL "
+	q += "WHERE id = $1 AND deleted_at IS NOT NULL RETURNING id, floor_id, name, x_pos, y_pos, w_size, h_size, capacity, type, created_at, updated_at, deleted_at, deleted_by"
+	expectedQuery := regexp.QuoteMeta(q)
+	suite.mock.ExpectQuery(expectedQuery).
+		WithArgs(1).
+		WillReturnRows(rows)
+	res, err := suite.tableRepo.Restore(context.TODO(), &model.Table{ID: 1})
+	require.Nil(suite.T(), err)
+	require.NotNil(suite.T(), res)
+}
+
+func (suite *tableRepositoryTestSuite) TestTableRepository_SoftDelete() {
+	dbtest.Migrated(suite.T(), config.Dialect.Name())
+	ctx := context.TODO()
+	repo := repoSql.NewTableSQLRepository()
+	table, err := repo.Create(ctx, &model.Table{FloorID: 2, Name: "T9",
+		XPos: 1, YPos: 1, WSize: 2, HSize: 2, Capacity: 4, Type: "square"})
+	require.NoError(suite.T(), err)
+	table.Capacity = 6
+	table, err = repo.Update(ctx, table)
+	require.NoError(suite.T(), err)
+	require.Equal(suite.T(), 6, table.Capacity)
+	tables, err := repo.AllWhere(ctx, model.FindWithRelationID, 2)
+	require.NoError(suite.T(), err)
+	require.Len(suite.T(), tables, 1)
+
+	require.NoError(suite.T(), repo.Delete(ctx, table))
+	tables, err = repo.AllWhere(ctx, model.FindWithRelationID, 2)
+	require.NoError(suite.T(), err)
+	require.Empty(suite.T(), tables)
+	_, err = repo.Restore(ctx, table)
+	require.NoError(suite.T(), err)
+	require.NoError(suite.T(), repo.Delete(ctx, table))
+}
+
 func TestTableRepository(t *testing.T) {
-	suite.Run(t, new(tableRepositoryTestSuite))
+	for _, d := range dialect.All() {
+		t.Run(d.Name(), func(t *testing.T) {
+			config.Dialect = d
+			suite.Run(t, new(tableRepositoryTestSuite))
+		})
+	}
 }
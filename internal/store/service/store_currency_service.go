@@ -0,0 +1,95 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/aasumitro/posbe/pkg/model"
+	"github.com/aasumitro/posbe/pkg/money"
+	"github.com/aasumitro/posbe/pkg/utils"
+)
+
+// legacyRatePrefKey is the pref read by older clients, it hold the USD rate
+const legacyRatePrefKey = "currency_rate"
+
+type storeCurrencyService struct {
+	rateRepo model.ICurrencyRateRepository
+	prefRepo model.IStorePrefRepository
+}
+
+func (service storeCurrencyService) Currency(
+	ctx context.Context,
+) (data *model.StoreCurrency, errData *utils.ServiceError) {
+	rates, err := service.rateRepo.Latest(ctx)
+	if err != nil {
+		return nil, &utils.ServiceError{
+			Code:    http.StatusInternalServerError,
+			Message: err.Error(),
+		}
+	}
+	return &model.StoreCurrency{Base: money.StoreCurrency(), Rates: rates}, nil
+}
+
+func (service storeCurrencyService) RateHistory(
+	ctx context.Context,
+	currency string,
+) (data []*model.CurrencyRate, errData *utils.ServiceError) {
+	data, err := service.rateRepo.History(ctx, strings.ToUpper(currency))
+	return utils.ValidateDataRows[model.CurrencyRate](data, err)
+}
+
+func (service storeCurrencyService) AddRate(
+	ctx context.Context,
+	rate *model.CurrencyRate,
+) (data *model.CurrencyRate, errData *utils.ServiceError) {
+	currency, err := money.Lookup(rate.Currency)
+	switch {
+	case err != nil:
+		return nil, &utils.ServiceError{
+			Code:    http.StatusUnprocessableEntity,
+			Message: err.Error(),
+		}
+	case currency.Code == money.StoreCurrency().Code:
+		return nil, &utils.ServiceError{
+			Code:    http.StatusUnprocessableEntity,
+			Message: "rate of the store currency is always 1",
+		}
+	case rate.Rate.IsZero():
+		return nil, &utils.ServiceError{
+			Code:    http.StatusUnprocessableEntity,
+			Message: money.ErrorInvalidRate.Error(),
+		}
+	}
+	rate.Currency = currency.Code
+
+	data, err = service.rateRepo.Create(ctx, rate)
+	if err != nil {
+		return nil, &utils.ServiceError{
+			Code:    http.StatusInternalServerError,
+			Message: err.Error(),
+		}
+	}
+
+	if currency.Code == "USD" {
+		if _, err := service.prefRepo.Update(ctx, legacyRatePrefKey, data.Rate.String()); err != nil {
+			return nil, &utils.ServiceError{
+				Code:    http.StatusInternalServerError,
+				Message: err.Error(),
+			}
+		}
+		_ = prefCache.Invalidate(ctx)
+	}
+
+	return data, nil
+}
+
+func NewStoreCurrencyService(
+	rateRepo model.ICurrencyRateRepository,
+	prefRepo model.IStorePrefRepository,
+) model.IStoreCurrencyService {
+	return &storeCurrencyService{
+		rateRepo: rateRepo,
+		prefRepo: prefRepo,
+	}
+}
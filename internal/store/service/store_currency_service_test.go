@@ -0,0 +1,86 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/aasumitro/posbe/internal/store/service"
+	"github.com/aasumitro/posbe/mocks"
+	"github.com/aasumitro/posbe/pkg/model"
+	"github.com/aasumitro/posbe/pkg/money"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type storeCurrencyTestSuite struct {
+	suite.Suite
+	rateRepo *mocks.ICurrencyRateRepository
+	prefRepo *mocks.IStorePrefRepository
+	svc      model.IStoreCurrencyService
+	rate     money.Rate
+}
+
+func (suite *storeCurrencyTestSuite) SetupTest() {
+	suite.rateRepo = new(mocks.ICurrencyRateRepository)
+	suite.prefRepo = new(mocks.IStorePrefRepository)
+	suite.svc = service.NewStoreCurrencyService(suite.rateRepo, suite.prefRepo)
+	var err error
+	suite.rate, err = money.ParseRate("16300")
+	require.NoError(suite.T(), err)
+}
+
+func (suite *storeCurrencyTestSuite) TestStoreCurrencyService_Currency_ShouldSuccess() {
+	suite.rateRepo.On("Latest", mock.Anything).Once().
+		Return([]*model.CurrencyRate{{ID: 1, Currency: "USD", Rate: suite.rate}}, nil)
+	data, err := suite.svc.Currency(context.TODO())
+	require.Nil(suite.T(), err)
+	require.Equal(suite.T(), money.DefaultCurrency, data.Base.Code)
+	require.Len(suite.T(), data.Rates, 1)
+}
+
+func (suite *storeCurrencyTestSuite) TestStoreCurrencyService_Currency_ShouldError() {
+	suite.rateRepo.On("Latest", mock.Anything).Once().Return(nil, errors.New("UNEXPECTED"))
+	data, err := suite.svc.Currency(context.TODO())
+	require.Nil(suite.T(), data)
+	require.Equal(suite.T(), http.StatusInternalServerError, err.Code)
+}
+
+func (suite *storeCurrencyTestSuite) TestStoreCurrencyService_AddRate_ShouldSyncLegacyPref() {
+	suite.rateRepo.On("Create", mock.Anything, mock.MatchedBy(func(rate *model.CurrencyRate) bool {
+		return rate.Currency == "USD"
+	})).Once().Return(&model.CurrencyRate{ID: 2, Currency: "USD", Rate: suite.rate}, nil)
+	suite.prefRepo.On("Update", mock.Anything, "currency_rate", "16300").Once().
+		Return(&model.StoreSetting{"currency_rate": "16300"}, nil)
+	data, err := suite.svc.AddRate(context.TODO(), &model.CurrencyRate{Currency: "usd", Rate: suite.rate})
+	require.Nil(suite.T(), err)
+	require.Equal(suite.T(), 2, data.ID)
+	suite.prefRepo.AssertExpectations(suite.T())
+}
+
+func (suite *storeCurrencyTestSuite) TestStoreCurrencyService_AddRate_ShouldValidate() {
+	for _, rate := range []*model.CurrencyRate{
+		{Currency: "XXX", Rate: suite.rate},
+		{Currency: money.DefaultCurrency, Rate: suite.rate},
+		{Currency: "SGD"},
+	} {
+		data, err := suite.svc.AddRate(context.TODO(), rate)
+		require.Nil(suite.T(), data)
+		require.Equal(suite.T(), http.StatusUnprocessableEntity, err.Code)
+	}
+	suite.rateRepo.AssertNotCalled(suite.T(), "Create", mock.Anything, mock.Anything)
+}
+
+func (suite *storeCurrencyTestSuite) TestStoreCurrencyService_RateHistory_ShouldSuccess() {
+	suite.rateRepo.On("History", mock.Anything, "SGD").Once().
+		Return([]*model.CurrencyRate{}, nil)
+	data, err := suite.svc.RateHistory(context.TODO(), "sgd")
+	require.Nil(suite.T(), err)
+	require.NotNil(suite.T(), data)
+}
+
+func TestStoreCurrencyService(t *testing.T) {
+	suite.Run(t, new(storeCurrencyTestSuite))
+}
@@ -1,23 +1,37 @@
 package service
 
 import (
+	"bytes"
 	"context"
 	"database/sql"
 	"errors"
+	"fmt"
 	"net/http"
 
+	"github.com/aasumitro/posbe/pkg/cache"
 	"github.com/aasumitro/posbe/pkg/model"
+	"github.com/aasumitro/posbe/pkg/money"
+	"github.com/aasumitro/posbe/pkg/storage"
 	"github.com/aasumitro/posbe/pkg/utils"
 )
 
+const (
+	logoPrefKey     = "logo"
+	currencyPrefKey = "currency"
+)
+
+// prefCache is shared with the currency service, it write the legacy rate pref
+var prefCache = cache.New[*model.StoreSetting](cache.StorePrefsKey)
+
 type storePrefService struct {
 	prefRepo model.IStorePrefRepository
+	storage  storage.Storage
 }
 
 func (service storePrefService) AllPrefs(
 	ctx context.Context,
 ) (prefs *model.StoreSetting, errData *utils.ServiceError) {
-	data, err := service.prefRepo.All(ctx)
+	data, err := prefCache.Get(ctx, service.prefRepo.All)
 	return utils.ValidateDataRow[model.StoreSetting](data, err)
 }
 
@@ -40,6 +54,12 @@ func (service storePrefService) UpdatePrefs(
 		}
 	}
 
+	if key == currencyPrefKey {
+		if errData := checkCurrency(value); errData != nil {
+			return nil, errData
+		}
+	}
+
 	data, err := service.prefRepo.Update(ctx, key, value)
 	if err != nil {
 		return nil, &utils.ServiceError{
@@ -47,14 +67,69 @@ func (service storePrefService) UpdatePrefs(
 			Message: err.Error(),
 		}
 	}
+	_ = prefCache.Invalidate(ctx)
+
+	if key == currencyPrefKey {
+		_ = money.SetCurrency(value)
+	}
 
 	return data, nil
 }
 
+// checkCurrency only allow a currency with the same minor unit,
+// stored amounts are not rescaled when the currency change
+func checkCurrency(code string) *utils.ServiceError {
+	currency, err := money.Lookup(code)
+	if err != nil {
+		return &utils.ServiceError{
+			Code:    http.StatusUnprocessableEntity,
+			Message: err.Error(),
+		}
+	}
+	if current := money.StoreCurrency(); currency.Exponent != current.Exponent {
+		return &utils.ServiceError{
+			Code: http.StatusUnprocessableEntity,
+			Message: fmt.Sprintf("%s has %d decimals while %s has %d, stored amounts would change value",
+				currency.Code, currency.Exponent, current.Code, current.Exponent),
+		}
+	}
+	return nil
+}
+
+func (service storePrefService) UploadLogo(
+	ctx context.Context,
+	upload *utils.UploadImage,
+) (prefs *model.StoreSetting, errData *utils.ServiceError) {
+	id, err := utils.RandomHex(8)
+	if err != nil {
+		return nil, &utils.ServiceError{
+			Code:    http.StatusInternalServerError,
+			Message: err.Error(),
+		}
+	}
+	// unique key, so the cached old logo is never served
+	key := fmt.Sprintf("store/logo_%s.%s", id, upload.Ext)
+	object, err := service.storage.Put(ctx, key, bytes.NewReader(upload.Data),
+		int64(len(upload.Data)), upload.ContentType)
+	if err != nil {
+		return nil, &utils.ServiceError{
+			Code:    http.StatusInternalServerError,
+			Message: err.Error(),
+		}
+	}
+	prefs, errData = service.UpdatePrefs(ctx, logoPrefKey, object.URL)
+	if errData != nil {
+		_ = service.storage.Delete(ctx, object.Key)
+	}
+	return prefs, errData
+}
+
 func NewStorePrefService(
 	prefRepo model.IStorePrefRepository,
+	fileStorage storage.Storage,
 ) model.IStorePrefService {
 	return &storePrefService{
 		prefRepo: prefRepo,
+		storage:  fileStorage,
 	}
 }
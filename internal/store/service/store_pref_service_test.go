@@ -4,12 +4,20 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"net/http"
+	"strings"
 	"testing"
 
+	"github.com/aasumitro/posbe/config"
 	"github.com/aasumitro/posbe/internal/store/service"
 	"github.com/aasumitro/posbe/mocks"
+	"github.com/aasumitro/posbe/pkg/kv"
 	"github.com/aasumitro/posbe/pkg/model"
+	"github.com/aasumitro/posbe/pkg/money"
+	"github.com/aasumitro/posbe/pkg/storage"
 	"github.com/aasumitro/posbe/pkg/utils"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
@@ -22,7 +30,7 @@ type storePrefTestSuite struct {
 
 func (suite *storePrefTestSuite) TestStorePrefService_AllPrefs_ShouldSuccess() {
 	repo := new(mocks.IStorePrefRepository)
-	svc := service.NewStorePrefService(repo)
+	svc := service.NewStorePrefService(repo, nil)
 	repo.
 		On("All", mock.Anything).
 		Once().
@@ -42,7 +50,7 @@ func (suite *storePrefTestSuite) TestStorePrefService_AllPrefs_ShouldSuccess() {
 
 func (suite *storePrefTestSuite) TestStorePrefService_AllPrefs_ShouldError() {
 	repo := new(mocks.IStorePrefRepository)
-	svc := service.NewStorePrefService(repo)
+	svc := service.NewStorePrefService(repo, nil)
 	repo.
 		On("All", mock.Anything).
 		Once().
@@ -59,7 +67,7 @@ func (suite *storePrefTestSuite) TestStorePrefService_AllPrefs_ShouldError() {
 
 func (suite *storePrefTestSuite) TestStorePrefService_UpdatePrefs_ShouldSuccess() {
 	repo := new(mocks.IStorePrefRepository)
-	svc := service.NewStorePrefService(repo)
+	svc := service.NewStorePrefService(repo, nil)
 	repo.
 		On("Find", mock.Anything, mock.Anything).
 		Once().
@@ -79,7 +87,7 @@ func (suite *storePrefTestSuite) TestStorePrefService_UpdatePrefs_ShouldSuccess(
 
 func (suite *storePrefTestSuite) TestStorePrefService_UpdatePrefs_ShouldErrorWhenFind() {
 	repo := new(mocks.IStorePrefRepository)
-	svc := service.NewStorePrefService(repo)
+	svc := service.NewStorePrefService(repo, nil)
 	repo.
 		On("Find", mock.Anything, mock.Anything).
 		Once().
@@ -95,7 +103,7 @@ func (suite *storePrefTestSuite) TestStorePrefService_UpdatePrefs_ShouldErrorWhe
 }
 func (suite *storePrefTestSuite) TestStorePrefService_UpdatePrefs_ShouldErrorWhenFindNotFound() {
 	repo := new(mocks.IStorePrefRepository)
-	svc := service.NewStorePrefService(repo)
+	svc := service.NewStorePrefService(repo, nil)
 	repo.
 		On("Find", mock.Anything, mock.Anything, mock.Anything).
 		Once().
@@ -108,7 +116,7 @@ func (suite *storePrefTestSuite) TestStorePrefService_UpdatePrefs_ShouldErrorWhe
 }
 func (suite *storePrefTestSuite) TestStorePrefService_UpdatePrefs_ShouldErrorWhenDelete() {
 	repo := new(mocks.IStorePrefRepository)
-	svc := service.NewStorePrefService(repo)
+	svc := service.NewStorePrefService(repo, nil)
 	repo.
 		On("Find", mock.Anything, mock.Anything).
 		Once().
@@ -127,6 +135,124 @@ func (suite *storePrefTestSuite) TestStorePrefService_UpdatePrefs_ShouldErrorWhe
 	repo.AssertExpectations(suite.T())
 }
 
+func (suite *storePrefTestSuite) TestStorePrefService_UpdatePrefs_ShouldSwitchCurrency() {
+	suite.T().Cleanup(func() { _ = money.SetCurrency(money.DefaultCurrency) })
+	repo := new(mocks.IStorePrefRepository)
+	svc := service.NewStorePrefService(repo, nil)
+	repo.
+		On("Find", mock.Anything, "currency").
+		Return(&model.StoreSetting{"currency": "IDR"}, nil)
+	repo.
+		On("Update", mock.Anything, "currency", "USD").
+		Once().
+		Return(&model.StoreSetting{"currency": "USD"}, nil)
+	data, err := svc.UpdatePrefs(context.TODO(), "currency", "USD")
+	require.Nil(suite.T(), err)
+	require.NotNil(suite.T(), data)
+	require.Equal(suite.T(), "USD", money.StoreCurrency().Code)
+	// unknown and different minor unit never reach the repository
+	for _, code := range []string{"XXX", "JPY"} {
+		data, err = svc.UpdatePrefs(context.TODO(), "currency", code)
+		require.Nil(suite.T(), data)
+		require.Equal(suite.T(), http.StatusUnprocessableEntity, err.Code)
+	}
+	repo.AssertExpectations(suite.T())
+}
+
+func (suite *storePrefTestSuite) TestStorePrefService_UploadLogo_ShouldSuccess() {
+	repo := new(mocks.IStorePrefRepository)
+	store := new(mocks.Storage)
+	svc := service.NewStorePrefService(repo, store)
+	store.
+		On("Put", mock.Anything, mock.MatchedBy(func(key string) bool {
+			return strings.HasPrefix(key, "store/logo_") && strings.HasSuffix(key, ".png")
+		}), mock.Anything, int64(4), "image/png").
+		Once().
+		Return(&storage.Object{Key: "store/logo_a.png", URL: "/uploads/store/logo_a.png"}, nil)
+	repo.
+		On("Find", mock.Anything, "logo").
+		Once().
+		Return(&model.StoreSetting{"logo": "/lorem.png"}, nil)
+	repo.
+		On("Update", mock.Anything, "logo", "/uploads/store/logo_a.png").
+		Once().
+		Return(&model.StoreSetting{"logo": "/uploads/store/logo_a.png"}, nil)
+	data, err := svc.UploadLogo(context.TODO(), &utils.UploadImage{
+		Data: []byte("logo"), ContentType: "image/png", Ext: "png",
+	})
+	require.Nil(suite.T(), err)
+	require.Equal(suite.T(), &model.StoreSetting{"logo": "/uploads/store/logo_a.png"}, data)
+	repo.AssertExpectations(suite.T())
+	store.AssertExpectations(suite.T())
+}
+
+func (suite *storePrefTestSuite) TestStorePrefService_UploadLogo_ShouldErrorWhenStore() {
+	repo := new(mocks.IStorePrefRepository)
+	store := new(mocks.Storage)
+	svc := service.NewStorePrefService(repo, store)
+	store.
+		On("Put", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Once().
+		Return(nil, errors.New("UNEXPECTED"))
+	data, err := svc.UploadLogo(context.TODO(), &utils.UploadImage{Ext: "png"})
+	require.Nil(suite.T(), data)
+	require.Equal(suite.T(), &utils.ServiceError{
+		Code:    500,
+		Message: "UNEXPECTED",
+	}, err)
+	store.AssertExpectations(suite.T())
+}
+
+func (suite *storePrefTestSuite) TestStorePrefService_UploadLogo_ShouldCleanupWhenUpdate() {
+	repo := new(mocks.IStorePrefRepository)
+	store := new(mocks.Storage)
+	svc := service.NewStorePrefService(repo, store)
+	store.
+		On("Put", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Once().
+		Return(&storage.Object{Key: "store/logo_a.png", URL: "/uploads/store/logo_a.png"}, nil)
+	store.On("Delete", mock.Anything, "store/logo_a.png").Once().Return(nil)
+	repo.
+		On("Find", mock.Anything, "logo").
+		Once().
+		Return(nil, sql.ErrNoRows)
+	data, err := svc.UploadLogo(context.TODO(), &utils.UploadImage{Ext: "png"})
+	require.Nil(suite.T(), data)
+	require.Equal(suite.T(), 404, err.Code)
+	repo.AssertExpectations(suite.T())
+	store.AssertExpectations(suite.T())
+}
+
+func (suite *storePrefTestSuite) TestStorePrefService_UpdatePrefs_ShouldRefreshCache() {
+	config.KV = kv.NewRedisStore(redis.NewClient(&redis.Options{
+		Addr: miniredis.RunT(suite.T()).Addr(),
+	}))
+	defer func() { config.KV = nil }()
+	repo := new(mocks.IStorePrefRepository)
+	svc := service.NewStorePrefService(repo, nil)
+	repo.On("All", mock.Anything).Once().
+		Return(&model.StoreSetting{"lorem": "lorem"}, nil)
+	repo.On("Find", mock.Anything, mock.Anything).Once().
+		Return(&model.StoreSetting{"lorem": "lorem"}, nil)
+	repo.On("Update", mock.Anything, mock.Anything, mock.Anything).Once().
+		Return(&model.StoreSetting{"lorem": "ipsum"}, nil)
+	repo.On("All", mock.Anything).Once().
+		Return(&model.StoreSetting{"lorem": "ipsum"}, nil)
+	data, err := svc.AllPrefs(context.TODO())
+	require.Nil(suite.T(), err)
+	require.Equal(suite.T(), &model.StoreSetting{"lorem": "lorem"}, data)
+	// served from the cache
+	data, err = svc.AllPrefs(context.TODO())
+	require.Nil(suite.T(), err)
+	require.Equal(suite.T(), &model.StoreSetting{"lorem": "lorem"}, data)
+	_, err = svc.UpdatePrefs(context.TODO(), "lorem", "ipsum")
+	require.Nil(suite.T(), err)
+	data, err = svc.AllPrefs(context.TODO())
+	require.Nil(suite.T(), err)
+	require.Equal(suite.T(), &model.StoreSetting{"lorem": "ipsum"}, data)
+	repo.AssertExpectations(suite.T())
+}
+
 func TestStorePrefService(t *testing.T) {
 	suite.Run(t, new(storePrefTestSuite))
 }
@@ -7,15 +7,16 @@ import (
 	"net/http"
 	"reflect"
 
-	"github.com/aasumitro/posbe/common"
 	"github.com/aasumitro/posbe/pkg/model"
+	"github.com/aasumitro/posbe/pkg/reference"
 	"github.com/aasumitro/posbe/pkg/utils"
 )
 
 type storeService struct {
-	floorRepo model.ICRUDRepository[model.Floor]
-	tableRepo model.ICRUDAddOnRepository[model.Table]
-	roomRepo  model.ICRUDAddOnRepository[model.Room]
+	floorRepo     model.ISoftDeleteRepository[model.Floor]
+	tableRepo     model.ICRUDAddOnRepository[model.Table]
+	roomRepo      model.ICRUDAddOnRepository[model.Room]
+	referenceRepo model.IReferenceRepository
 }
 
 func (service storeService) FloorList(
@@ -59,19 +60,17 @@ func (service storeService) DeleteFloor(
 			Message: err.Error(),
 		}
 	}
-	if floor.TotalTables >= 1 {
-		return &utils.ServiceError{
-			Code:    http.StatusForbidden,
-			Message: common.ErrorUnableToDelete,
-		}
-	}
-	if err := service.floorRepo.Delete(ctx, floor); err != nil {
-		return &utils.ServiceError{
-			Code:    http.StatusInternalServerError,
-			Message: err.Error(),
-		}
-	}
-	return nil
+	// the row is deleted by the reference repository with the reassign
+	model.AuditBefore(ctx, floor)
+	return reference.Delete(ctx, service.referenceRepo, "floors", floor.ID)
+}
+
+func (service storeService) RestoreFloor(
+	ctx context.Context,
+	item *model.Floor,
+) (floor *model.Floor, errData *utils.ServiceError) {
+	data, err := service.floorRepo.Restore(ctx, item)
+	return utils.ValidateDataRow[model.Floor](data, err)
 }
 
 func (service storeService) TableList(
@@ -126,6 +125,14 @@ func (service storeService) DeleteTable(
 	return nil
 }
 
+func (service storeService) RestoreTable(
+	ctx context.Context,
+	item *model.Table,
+) (table *model.Table, errData *utils.ServiceError) {
+	data, err := service.tableRepo.Restore(ctx, item)
+	return utils.ValidateDataRow[model.Table](data, err)
+}
+
 func (service storeService) RoomList(
 	ctx context.Context,
 ) (rooms []*model.Room, errData *utils.ServiceError) {
@@ -179,6 +186,14 @@ func (service storeService) DeleteRoom(
 	return nil
 }
 
+func (service storeService) RestoreRoom(
+	ctx context.Context,
+	item *model.Room,
+) (room *model.Room, errData *utils.ServiceError) {
+	data, err := service.roomRepo.Restore(ctx, item)
+	return utils.ValidateDataRow[model.Room](data, err)
+}
+
 func (service storeService) FloorsWith(
 	ctx context.Context,
 	s any) (floors []*model.Floor, errData *utils.ServiceError) {
@@ -230,13 +245,15 @@ func (service storeService) FloorsWith(
 }
 
 func NewStoreService(
-	floorRepo model.ICRUDRepository[model.Floor],
+	floorRepo model.ISoftDeleteRepository[model.Floor],
 	tableRepo model.ICRUDAddOnRepository[model.Table],
 	roomRepo model.ICRUDAddOnRepository[model.Room],
+	referenceRepo model.IReferenceRepository,
 ) model.IStoreService {
 	return &storeService{
-		tableRepo: tableRepo,
-		floorRepo: floorRepo,
-		roomRepo:  roomRepo,
+		tableRepo:     tableRepo,
+		floorRepo:     floorRepo,
+		roomRepo:      roomRepo,
+		referenceRepo: referenceRepo,
 	}
 }
@@ -79,9 +79,9 @@ func (suite *storeTestSuite) SetupSuite() {
 
 // ================= FLOOR TEST CASE
 func (suite *storeTestSuite) TestStoreService_FloorList_ShouldSuccess() {
-	floorRepoMock := new(mocks.ICRUDRepository[model.Floor])
+	floorRepoMock := new(mocks.ISoftDeleteRepository[model.Floor])
 	svc := service.NewStoreService(floorRepoMock, new(mocks.ICRUDAddOnRepository[model.Table]),
-		new(mocks.ICRUDAddOnRepository[model.Room]))
+		new(mocks.ICRUDAddOnRepository[model.Room]), new(mocks.IReferenceRepository))
 	floorRepoMock.
 		On("All", mock.Anything).
 		Once().
@@ -94,9 +94,9 @@ func (suite *storeTestSuite) TestStoreService_FloorList_ShouldSuccess() {
 }
 
 func (suite *storeTestSuite) TestStoreService_FloorList_ShouldError() {
-	floorRepoMock := new(mocks.ICRUDRepository[model.Floor])
+	floorRepoMock := new(mocks.ISoftDeleteRepository[model.Floor])
 	svc := service.NewStoreService(floorRepoMock, new(mocks.ICRUDAddOnRepository[model.Table]),
-		new(mocks.ICRUDAddOnRepository[model.Room]))
+		new(mocks.ICRUDAddOnRepository[model.Room]), new(mocks.IReferenceRepository))
 	floorRepoMock.
 		On("All", mock.Anything).
 		Once().
@@ -109,9 +109,9 @@ func (suite *storeTestSuite) TestStoreService_FloorList_ShouldError() {
 }
 
 func (suite *storeTestSuite) TestStoreService_AddFloor_ShouldSuccess() {
-	floorRepoMock := new(mocks.ICRUDRepository[model.Floor])
+	floorRepoMock := new(mocks.ISoftDeleteRepository[model.Floor])
 	svc := service.NewStoreService(floorRepoMock, new(mocks.ICRUDAddOnRepository[model.Table]),
-		new(mocks.ICRUDAddOnRepository[model.Room]))
+		new(mocks.ICRUDAddOnRepository[model.Room]), new(mocks.IReferenceRepository))
 	floorRepoMock.
 		On("Create", mock.Anything, mock.Anything).
 		Once().
@@ -124,9 +124,9 @@ func (suite *storeTestSuite) TestStoreService_AddFloor_ShouldSuccess() {
 }
 
 func (suite *storeTestSuite) TestStoreService_AddFloor_ShouldError() {
-	floorRepoMock := new(mocks.ICRUDRepository[model.Floor])
+	floorRepoMock := new(mocks.ISoftDeleteRepository[model.Floor])
 	svc := service.NewStoreService(floorRepoMock, new(mocks.ICRUDAddOnRepository[model.Table]),
-		new(mocks.ICRUDAddOnRepository[model.Room]))
+		new(mocks.ICRUDAddOnRepository[model.Room]), new(mocks.IReferenceRepository))
 	floorRepoMock.
 		On("Create", mock.Anything, mock.Anything).
 		Once().
@@ -139,9 +139,9 @@ func (suite *storeTestSuite) TestStoreService_AddFloor_ShouldError() {
 }
 
 func (suite *storeTestSuite) TestStoreService_EditFloor_ShouldSuccess() {
-	floorRepoMock := new(mocks.ICRUDRepository[model.Floor])
+	floorRepoMock := new(mocks.ISoftDeleteRepository[model.Floor])
 	svc := service.NewStoreService(floorRepoMock, new(mocks.ICRUDAddOnRepository[model.Table]),
-		new(mocks.ICRUDAddOnRepository[model.Room]))
+		new(mocks.ICRUDAddOnRepository[model.Room]), new(mocks.IReferenceRepository))
 	floorRepoMock.
 		On("Update", mock.Anything, mock.Anything).
 		Once().
@@ -154,9 +154,9 @@ func (suite *storeTestSuite) TestStoreService_EditFloor_ShouldSuccess() {
 }
 
 func (suite *storeTestSuite) TestStoreService_EditFloor_ShouldError() {
-	floorRepoMock := new(mocks.ICRUDRepository[model.Floor])
+	floorRepoMock := new(mocks.ISoftDeleteRepository[model.Floor])
 	svc := service.NewStoreService(floorRepoMock, new(mocks.ICRUDAddOnRepository[model.Table]),
-		new(mocks.ICRUDAddOnRepository[model.Room]))
+		new(mocks.ICRUDAddOnRepository[model.Room]), new(mocks.IReferenceRepository))
 	floorRepoMock.
 		On("Update", mock.Anything, mock.Anything).
 		Once().
@@ -169,26 +169,28 @@ func (suite *storeTestSuite) TestStoreService_EditFloor_ShouldError() {
 }
 
 func (suite *storeTestSuite) TestStoreService_DeleteFloor_ShouldSuccess() {
-	floorRepoMock := new(mocks.ICRUDRepository[model.Floor])
+	floorRepoMock := new(mocks.ISoftDeleteRepository[model.Floor])
+	referenceRepoMock := new(mocks.IReferenceRepository)
+	referenceRepoMock.
+		On("Delete", mock.Anything, "floors", mock.Anything, 0).
+		Once().
+		Return(nil, nil)
 	svc := service.NewStoreService(floorRepoMock, new(mocks.ICRUDAddOnRepository[model.Table]),
-		new(mocks.ICRUDAddOnRepository[model.Room]))
+		new(mocks.ICRUDAddOnRepository[model.Room]), referenceRepoMock)
 	floorRepoMock.
 		On("Find", mock.Anything, mock.Anything, mock.Anything).
 		Once().
 		Return(suite.floors[1], nil)
-	floorRepoMock.
-		On("Delete", mock.Anything, mock.Anything).
-		Once().
-		Return(nil)
 	err := svc.DeleteFloor(context.TODO(), suite.floors[1])
 	require.Nil(suite.T(), err)
 	floorRepoMock.AssertExpectations(suite.T())
+	referenceRepoMock.AssertExpectations(suite.T())
 }
 
 func (suite *storeTestSuite) TestStoreService_DeleteFloor_ShouldErrorWhenFind() {
-	floorRepoMock := new(mocks.ICRUDRepository[model.Floor])
+	floorRepoMock := new(mocks.ISoftDeleteRepository[model.Floor])
 	svc := service.NewStoreService(floorRepoMock, new(mocks.ICRUDAddOnRepository[model.Table]),
-		new(mocks.ICRUDAddOnRepository[model.Room]))
+		new(mocks.ICRUDAddOnRepository[model.Room]), new(mocks.IReferenceRepository))
 	floorRepoMock.
 		On("Find", mock.Anything, mock.Anything, mock.Anything).
 		Once().
@@ -199,9 +201,9 @@ func (suite *storeTestSuite) TestStoreService_DeleteFloor_ShouldErrorWhenFind()
 	floorRepoMock.AssertExpectations(suite.T())
 }
 func (suite *storeTestSuite) TestStoreService_DeleteFloor_ShouldErrorWhenFindNotFound() {
-	floorRepoMock := new(mocks.ICRUDRepository[model.Floor])
+	floorRepoMock := new(mocks.ISoftDeleteRepository[model.Floor])
 	svc := service.NewStoreService(floorRepoMock, new(mocks.ICRUDAddOnRepository[model.Table]),
-		new(mocks.ICRUDAddOnRepository[model.Room]))
+		new(mocks.ICRUDAddOnRepository[model.Room]), new(mocks.IReferenceRepository))
 	floorRepoMock.
 		On("Find", mock.Anything, mock.Anything, mock.Anything).
 		Once().
@@ -212,9 +214,14 @@ func (suite *storeTestSuite) TestStoreService_DeleteFloor_ShouldErrorWhenFindNot
 	floorRepoMock.AssertExpectations(suite.T())
 }
 func (suite *storeTestSuite) TestStoreService_DeleteFloor_ShouldErrorHasTables() {
-	floorRepoMock := new(mocks.ICRUDRepository[model.Floor])
+	floorRepoMock := new(mocks.ISoftDeleteRepository[model.Floor])
+	referenceRepoMock := new(mocks.IReferenceRepository)
+	referenceRepoMock.
+		On("Delete", mock.Anything, "floors", mock.Anything, 0).
+		Once().
+		Return([]*model.Reference{{Table: "tables", Column: "floor_id", Total: 1, IDs: []int{1}}}, nil)
 	svc := service.NewStoreService(floorRepoMock, new(mocks.ICRUDAddOnRepository[model.Table]),
-		new(mocks.ICRUDAddOnRepository[model.Room]))
+		new(mocks.ICRUDAddOnRepository[model.Room]), referenceRepoMock)
 	floorRepoMock.
 		On("Find", mock.Anything, mock.Anything, mock.Anything).
 		Once().
@@ -222,37 +229,71 @@ func (suite *storeTestSuite) TestStoreService_DeleteFloor_ShouldErrorHasTables()
 	err := svc.DeleteFloor(context.TODO(), suite.floor)
 	require.NotNil(suite.T(), err)
 	require.Equal(suite.T(), err, &utils.ServiceError{
-		Code:    http.StatusForbidden,
-		Message: svcErr.ErrorUnableToDelete,
+		Code: http.StatusConflict,
+		Message: &model.DeleteConflict{
+			Message:    svcErr.ErrorUnableToDelete.Error(),
+			References: []*model.Reference{{Table: "tables", Column: "floor_id", Total: 1, IDs: []int{1}}},
+		},
 	})
 	floorRepoMock.AssertExpectations(suite.T())
+	referenceRepoMock.AssertExpectations(suite.T())
 }
 
 func (suite *storeTestSuite) TestStoreService_DeleteFloor_ShouldErrorWhenDelete() {
-	floorRepoMock := new(mocks.ICRUDRepository[model.Floor])
+	floorRepoMock := new(mocks.ISoftDeleteRepository[model.Floor])
+	referenceRepoMock := new(mocks.IReferenceRepository)
+	referenceRepoMock.
+		On("Delete", mock.Anything, "floors", mock.Anything, 0).
+		Once().
+		Return(nil, errors.New("UNEXPECTED"))
 	svc := service.NewStoreService(floorRepoMock, new(mocks.ICRUDAddOnRepository[model.Table]),
-		new(mocks.ICRUDAddOnRepository[model.Room]))
+		new(mocks.ICRUDAddOnRepository[model.Room]), referenceRepoMock)
 	floorRepoMock.
 		On("Find", mock.Anything, mock.Anything, mock.Anything).
 		Once().
 		Return(suite.floors[1], nil)
-	floorRepoMock.
-		On("Delete", mock.Anything, mock.Anything).
-		Once().
-		Return(errors.New("UNEXPECTED"))
 	err := svc.DeleteFloor(context.TODO(), suite.floors[1])
 	require.NotNil(suite.T(), err)
 	require.Equal(suite.T(), err, suite.svcErr)
 	floorRepoMock.AssertExpectations(suite.T())
+	referenceRepoMock.AssertExpectations(suite.T())
+}
+
+func (suite *storeTestSuite) TestStoreService_RestoreFloor_ShouldSuccess() {
+	floorRepoMock := new(mocks.ISoftDeleteRepository[model.Floor])
+	svc := service.NewStoreService(floorRepoMock, new(mocks.ICRUDAddOnRepository[model.Table]),
+		new(mocks.ICRUDAddOnRepository[model.Room]), new(mocks.IReferenceRepository))
+	floorRepoMock.
+		On("Restore", mock.Anything, mock.Anything).
+		Once().
+		Return(suite.floors[1], nil)
+	data, err := svc.RestoreFloor(context.TODO(), suite.floors[1])
+	require.Nil(suite.T(), err)
+	require.Equal(suite.T(), data, suite.floors[1])
+	floorRepoMock.AssertExpectations(suite.T())
+}
+
+func (suite *storeTestSuite) TestStoreService_RestoreFloor_ShouldError() {
+	floorRepoMock := new(mocks.ISoftDeleteRepository[model.Floor])
+	svc := service.NewStoreService(floorRepoMock, new(mocks.ICRUDAddOnRepository[model.Table]),
+		new(mocks.ICRUDAddOnRepository[model.Room]), new(mocks.IReferenceRepository))
+	floorRepoMock.
+		On("Restore", mock.Anything, mock.Anything).
+		Once().
+		Return(nil, errors.New("UNEXPECTED"))
+	data, err := svc.RestoreFloor(context.TODO(), suite.floors[1])
+	require.Nil(suite.T(), data)
+	require.Equal(suite.T(), err, suite.svcErr)
+	floorRepoMock.AssertExpectations(suite.T())
 }
 
 // ================== TABLE TEST CASE
 func (suite *storeTestSuite) TestStoreService_TableList_ShouldSuccess() {
 	tableRepoMock := new(mocks.ICRUDAddOnRepository[model.Table])
 	svc := service.NewStoreService(
-		new(mocks.ICRUDRepository[model.Floor]),
+		new(mocks.ISoftDeleteRepository[model.Floor]),
 		tableRepoMock,
-		new(mocks.ICRUDAddOnRepository[model.Room]))
+		new(mocks.ICRUDAddOnRepository[model.Room]), new(mocks.IReferenceRepository))
 	tableRepoMock.
 		On("All", mock.Anything).
 		Once().
@@ -267,9 +308,9 @@ func (suite *storeTestSuite) TestStoreService_TableList_ShouldSuccess() {
 func (suite *storeTestSuite) TestStoreService_TableList_ShouldError() {
 	tableRepoMock := new(mocks.ICRUDAddOnRepository[model.Table])
 	svc := service.NewStoreService(
-		new(mocks.ICRUDRepository[model.Floor]),
+		new(mocks.ISoftDeleteRepository[model.Floor]),
 		tableRepoMock,
-		new(mocks.ICRUDAddOnRepository[model.Room]))
+		new(mocks.ICRUDAddOnRepository[model.Room]), new(mocks.IReferenceRepository))
 	tableRepoMock.
 		On("All", mock.Anything).
 		Once().
@@ -284,9 +325,9 @@ func (suite *storeTestSuite) TestStoreService_TableList_ShouldError() {
 func (suite *storeTestSuite) TestStoreService_AddTable_ShouldSuccess() {
 	tableRepoMock := new(mocks.ICRUDAddOnRepository[model.Table])
 	svc := service.NewStoreService(
-		new(mocks.ICRUDRepository[model.Floor]),
+		new(mocks.ISoftDeleteRepository[model.Floor]),
 		tableRepoMock,
-		new(mocks.ICRUDAddOnRepository[model.Room]))
+		new(mocks.ICRUDAddOnRepository[model.Room]), new(mocks.IReferenceRepository))
 	tableRepoMock.
 		On("Create", mock.Anything, mock.Anything).
 		Once().
@@ -301,9 +342,9 @@ func (suite *storeTestSuite) TestStoreService_AddTable_ShouldSuccess() {
 func (suite *storeTestSuite) TestStoreService_AddTable_ShouldError() {
 	tableRepoMock := new(mocks.ICRUDAddOnRepository[model.Table])
 	svc := service.NewStoreService(
-		new(mocks.ICRUDRepository[model.Floor]),
+		new(mocks.ISoftDeleteRepository[model.Floor]),
 		tableRepoMock,
-		new(mocks.ICRUDAddOnRepository[model.Room]))
+		new(mocks.ICRUDAddOnRepository[model.Room]), new(mocks.IReferenceRepository))
 	tableRepoMock.
 		On("Create", mock.Anything, mock.Anything).
 		Once().
@@ -318,9 +359,9 @@ func (suite *storeTestSuite) TestStoreService_AddTable_ShouldError() {
 func (suite *storeTestSuite) TestStoreService_EditTable_ShouldSuccess() {
 	tableRepoMock := new(mocks.ICRUDAddOnRepository[model.Table])
 	svc := service.NewStoreService(
-		new(mocks.ICRUDRepository[model.Floor]),
+		new(mocks.ISoftDeleteRepository[model.Floor]),
 		tableRepoMock,
-		new(mocks.ICRUDAddOnRepository[model.Room]))
+		new(mocks.ICRUDAddOnRepository[model.Room]), new(mocks.IReferenceRepository))
 	tableRepoMock.
 		On("Update", mock.Anything, mock.Anything).
 		Once().
@@ -335,9 +376,9 @@ func (suite *storeTestSuite) TestStoreService_EditTable_ShouldSuccess() {
 func (suite *storeTestSuite) TestStoreService_EditTable_ShouldError() {
 	tableRepoMock := new(mocks.ICRUDAddOnRepository[model.Table])
 	svc := service.NewStoreService(
-		new(mocks.ICRUDRepository[model.Floor]),
+		new(mocks.ISoftDeleteRepository[model.Floor]),
 		tableRepoMock,
-		new(mocks.ICRUDAddOnRepository[model.Room]))
+		new(mocks.ICRUDAddOnRepository[model.Room]), new(mocks.IReferenceRepository))
 	tableRepoMock.
 		On("Update", mock.Anything, mock.Anything).
 		Once().
@@ -352,9 +393,9 @@ func (suite *storeTestSuite) TestStoreService_EditTable_ShouldError() {
 func (suite *storeTestSuite) TestStoreService_DeleteTable_ShouldSuccess() {
 	tableRepoMock := new(mocks.ICRUDAddOnRepository[model.Table])
 	svc := service.NewStoreService(
-		new(mocks.ICRUDRepository[model.Floor]),
+		new(mocks.ISoftDeleteRepository[model.Floor]),
 		tableRepoMock,
-		new(mocks.ICRUDAddOnRepository[model.Room]))
+		new(mocks.ICRUDAddOnRepository[model.Room]), new(mocks.IReferenceRepository))
 	tableRepoMock.
 		On("Find", mock.Anything, mock.Anything, mock.Anything).
 		Once().
@@ -371,9 +412,9 @@ func (suite *storeTestSuite) TestStoreService_DeleteTable_ShouldSuccess() {
 func (suite *storeTestSuite) TestStoreService_DeleteTable_ShouldErrorWhenFind() {
 	tableRepoMock := new(mocks.ICRUDAddOnRepository[model.Table])
 	svc := service.NewStoreService(
-		new(mocks.ICRUDRepository[model.Floor]),
+		new(mocks.ISoftDeleteRepository[model.Floor]),
 		tableRepoMock,
-		new(mocks.ICRUDAddOnRepository[model.Room]))
+		new(mocks.ICRUDAddOnRepository[model.Room]), new(mocks.IReferenceRepository))
 	tableRepoMock.
 		On("Find", mock.Anything, mock.Anything, mock.Anything).
 		Once().
@@ -386,9 +427,9 @@ func (suite *storeTestSuite) TestStoreService_DeleteTable_ShouldErrorWhenFind()
 func (suite *storeTestSuite) TestStoreService_DeleteTable_ShouldErrorWhenFindNotFound() {
 	tableRepoMock := new(mocks.ICRUDAddOnRepository[model.Table])
 	svc := service.NewStoreService(
-		new(mocks.ICRUDRepository[model.Floor]),
+		new(mocks.ISoftDeleteRepository[model.Floor]),
 		tableRepoMock,
-		new(mocks.ICRUDAddOnRepository[model.Room]))
+		new(mocks.ICRUDAddOnRepository[model.Room]), new(mocks.IReferenceRepository))
 	tableRepoMock.
 		On("Find", mock.Anything, mock.Anything, mock.Anything).
 		Once().
@@ -401,9 +442,9 @@ func (suite *storeTestSuite) TestStoreService_DeleteTable_ShouldErrorWhenFindNot
 func (suite *storeTestSuite) TestStoreService_DeleteTable_ShouldErrorWhenDelete() {
 	tableRepoMock := new(mocks.ICRUDAddOnRepository[model.Table])
 	svc := service.NewStoreService(
-		new(mocks.ICRUDRepository[model.Floor]),
+		new(mocks.ISoftDeleteRepository[model.Floor]),
 		tableRepoMock,
-		new(mocks.ICRUDAddOnRepository[model.Room]))
+		new(mocks.ICRUDAddOnRepository[model.Room]), new(mocks.IReferenceRepository))
 	tableRepoMock.
 		On("Find", mock.Anything, mock.Anything, mock.Anything).
 		Once().
@@ -422,9 +463,9 @@ func (suite *storeTestSuite) TestStoreService_DeleteTable_ShouldErrorWhenDelete(
 func (suite *storeTestSuite) TestStoreService_RoomList_ShouldSuccess() {
 	roomRepoMock := new(mocks.ICRUDAddOnRepository[model.Room])
 	svc := service.NewStoreService(
-		new(mocks.ICRUDRepository[model.Floor]),
+		new(mocks.ISoftDeleteRepository[model.Floor]),
 		new(mocks.ICRUDAddOnRepository[model.Table]),
-		roomRepoMock)
+		roomRepoMock, new(mocks.IReferenceRepository))
 	roomRepoMock.
 		On("All", mock.Anything).
 		Once().
@@ -439,9 +480,9 @@ func (suite *storeTestSuite) TestStoreService_RoomList_ShouldSuccess() {
 func (suite *storeTestSuite) TestStoreService_RoomList_ShouldError() {
 	roomRepoMock := new(mocks.ICRUDAddOnRepository[model.Room])
 	svc := service.NewStoreService(
-		new(mocks.ICRUDRepository[model.Floor]),
+		new(mocks.ISoftDeleteRepository[model.Floor]),
 		new(mocks.ICRUDAddOnRepository[model.Table]),
-		roomRepoMock)
+		roomRepoMock, new(mocks.IReferenceRepository))
 	roomRepoMock.
 		On("All", mock.Anything).
 		Once().
@@ -456,9 +497,9 @@ func (suite *storeTestSuite) TestStoreService_RoomList_ShouldError() {
 func (suite *storeTestSuite) TestStoreService_AddRoom_ShouldSuccess() {
 	roomRepoMock := new(mocks.ICRUDAddOnRepository[model.Room])
 	svc := service.NewStoreService(
-		new(mocks.ICRUDRepository[model.Floor]),
+		new(mocks.ISoftDeleteRepository[model.Floor]),
 		new(mocks.ICRUDAddOnRepository[model.Table]),
-		roomRepoMock)
+		roomRepoMock, new(mocks.IReferenceRepository))
 	roomRepoMock.
 		On("Create", mock.Anything, mock.Anything).
 		Once().
@@ -473,9 +514,9 @@ func (suite *storeTestSuite) TestStoreService_AddRoom_ShouldSuccess() {
 func (suite *storeTestSuite) TestStoreService_AddRoom_ShouldError() {
 	roomRepoMock := new(mocks.ICRUDAddOnRepository[model.Room])
 	svc := service.NewStoreService(
-		new(mocks.ICRUDRepository[model.Floor]),
+		new(mocks.ISoftDeleteRepository[model.Floor]),
 		new(mocks.ICRUDAddOnRepository[model.Table]),
-		roomRepoMock)
+		roomRepoMock, new(mocks.IReferenceRepository))
 	roomRepoMock.
 		On("Create", mock.Anything, mock.Anything).
 		Once().
@@ -490,9 +531,9 @@ func (suite *storeTestSuite) TestStoreService_AddRoom_ShouldError() {
 func (suite *storeTestSuite) TestStoreService_EditRoom_ShouldSuccess() {
 	roomRepoMock := new(mocks.ICRUDAddOnRepository[model.Room])
 	svc := service.NewStoreService(
-		new(mocks.ICRUDRepository[model.Floor]),
+		new(mocks.ISoftDeleteRepository[model.Floor]),
 		new(mocks.ICRUDAddOnRepository[model.Table]),
-		roomRepoMock)
+		roomRepoMock, new(mocks.IReferenceRepository))
 	roomRepoMock.
 		On("Update", mock.Anything, mock.Anything).
 		Once().
@@ -507,9 +548,9 @@ func (suite *storeTestSuite) TestStoreService_EditRoom_ShouldSuccess() {
 func (suite *storeTestSuite) TestStoreService_EditRoom_ShouldError() {
 	roomRepoMock := new(mocks.ICRUDAddOnRepository[model.Room])
 	svc := service.NewStoreService(
-		new(mocks.ICRUDRepository[model.Floor]),
+		new(mocks.ISoftDeleteRepository[model.Floor]),
 		new(mocks.ICRUDAddOnRepository[model.Table]),
-		roomRepoMock)
+		roomRepoMock, new(mocks.IReferenceRepository))
 	roomRepoMock.
 		On("Update", mock.Anything, mock.Anything).
 		Once().
@@ -524,9 +565,9 @@ func (suite *storeTestSuite) TestStoreService_EditRoom_ShouldError() {
 func (suite *storeTestSuite) TestStoreService_DeleteRoom_ShouldSuccess() {
 	roomRepoMock := new(mocks.ICRUDAddOnRepository[model.Room])
 	svc := service.NewStoreService(
-		new(mocks.ICRUDRepository[model.Floor]),
+		new(mocks.ISoftDeleteRepository[model.Floor]),
 		new(mocks.ICRUDAddOnRepository[model.Table]),
-		roomRepoMock)
+		roomRepoMock, new(mocks.IReferenceRepository))
 	roomRepoMock.
 		On("Find", mock.Anything, mock.Anything, mock.Anything).
 		Once().
@@ -543,9 +584,9 @@ func (suite *storeTestSuite) TestStoreService_DeleteRoom_ShouldSuccess() {
 func (suite *storeTestSuite) TestStoreService_DeleteRoom_ShouldErrorWhenFind() {
 	roomRepoMock := new(mocks.ICRUDAddOnRepository[model.Room])
 	svc := service.NewStoreService(
-		new(mocks.ICRUDRepository[model.Floor]),
+		new(mocks.ISoftDeleteRepository[model.Floor]),
 		new(mocks.ICRUDAddOnRepository[model.Table]),
-		roomRepoMock)
+		roomRepoMock, new(mocks.IReferenceRepository))
 	roomRepoMock.
 		On("Find", mock.Anything, mock.Anything, mock.Anything).
 		Once().
@@ -558,9 +599,9 @@ func (suite *storeTestSuite) TestStoreService_DeleteRoom_ShouldErrorWhenFind() {
 func (suite *storeTestSuite) TestStoreService_DeleteRoom_ShouldErrorWhenFindNotFound() {
 	roomRepoMock := new(mocks.ICRUDAddOnRepository[model.Room])
 	svc := service.NewStoreService(
-		new(mocks.ICRUDRepository[model.Floor]),
+		new(mocks.ISoftDeleteRepository[model.Floor]),
 		new(mocks.ICRUDAddOnRepository[model.Table]),
-		roomRepoMock)
+		roomRepoMock, new(mocks.IReferenceRepository))
 	roomRepoMock.
 		On("Find", mock.Anything, mock.Anything, mock.Anything).
 		Once().
@@ -573,9 +614,9 @@ func (suite *storeTestSuite) TestStoreService_DeleteRoom_ShouldErrorWhenFindNotF
 func (suite *storeTestSuite) TestStoreService_DeleteRoom_ShouldErrorWhenDelete() {
 	roomRepoMock := new(mocks.ICRUDAddOnRepository[model.Room])
 	svc := service.NewStoreService(
-		new(mocks.ICRUDRepository[model.Floor]),
+		new(mocks.ISoftDeleteRepository[model.Floor]),
 		new(mocks.ICRUDAddOnRepository[model.Table]),
-		roomRepoMock)
+		roomRepoMock, new(mocks.IReferenceRepository))
 	roomRepoMock.
 		On("Find", mock.Anything, mock.Anything, mock.Anything).
 		Once().
@@ -592,11 +633,11 @@ func (suite *storeTestSuite) TestStoreService_DeleteRoom_ShouldErrorWhenDelete()
 
 // =================== ADD ON TEST CASE
 func (suite *storeTestSuite) TestStoreService_FloorsWithTable_ShouldSuccess() {
-	floorRepoMock := new(mocks.ICRUDRepository[model.Floor])
+	floorRepoMock := new(mocks.ISoftDeleteRepository[model.Floor])
 	tableRepoMock := new(mocks.ICRUDAddOnRepository[model.Table])
 	svc := service.NewStoreService(
 		floorRepoMock, tableRepoMock,
-		new(mocks.ICRUDAddOnRepository[model.Room]))
+		new(mocks.ICRUDAddOnRepository[model.Room]), new(mocks.IReferenceRepository))
 	floorRepoMock.
 		On("All", mock.Anything).
 		Once().
@@ -620,11 +661,11 @@ func (suite *storeTestSuite) TestStoreService_FloorsWithTable_ShouldSuccess() {
 }
 
 func (suite *storeTestSuite) TestStoreService_FloorsWithTable_ShouldErrorAllWhere() {
-	floorRepoMock := new(mocks.ICRUDRepository[model.Floor])
+	floorRepoMock := new(mocks.ISoftDeleteRepository[model.Floor])
 	tableRepoMock := new(mocks.ICRUDAddOnRepository[model.Table])
 	svc := service.NewStoreService(
 		floorRepoMock, tableRepoMock,
-		new(mocks.ICRUDAddOnRepository[model.Room]))
+		new(mocks.ICRUDAddOnRepository[model.Room]), new(mocks.IReferenceRepository))
 	floorRepoMock.
 		On("All", mock.Anything).
 		Once().
@@ -648,12 +689,12 @@ func (suite *storeTestSuite) TestStoreService_FloorsWithTable_ShouldErrorAllWher
 }
 
 func (suite *storeTestSuite) TestStoreService_FloorsWithRoom_ShouldSuccess() {
-	floorRepoMock := new(mocks.ICRUDRepository[model.Floor])
+	floorRepoMock := new(mocks.ISoftDeleteRepository[model.Floor])
 	roomRepoMock := new(mocks.ICRUDAddOnRepository[model.Room])
 	svc := service.NewStoreService(
 		floorRepoMock,
 		new(mocks.ICRUDAddOnRepository[model.Table]),
-		roomRepoMock)
+		roomRepoMock, new(mocks.IReferenceRepository))
 	floorRepoMock.
 		On("All", mock.Anything).
 		Once().
@@ -677,12 +718,12 @@ func (suite *storeTestSuite) TestStoreService_FloorsWithRoom_ShouldSuccess() {
 }
 
 func (suite *storeTestSuite) TestStoreService_FloorsWithRoom_ShouldErrorAllWhere() {
-	floorRepoMock := new(mocks.ICRUDRepository[model.Floor])
+	floorRepoMock := new(mocks.ISoftDeleteRepository[model.Floor])
 	roomRepoMock := new(mocks.ICRUDAddOnRepository[model.Room])
 	svc := service.NewStoreService(
 		floorRepoMock,
 		new(mocks.ICRUDAddOnRepository[model.Table]),
-		roomRepoMock)
+		roomRepoMock, new(mocks.IReferenceRepository))
 	floorRepoMock.
 		On("All", mock.Anything).
 		Once().
@@ -706,11 +747,11 @@ func (suite *storeTestSuite) TestStoreService_FloorsWithRoom_ShouldErrorAllWhere
 }
 
 func (suite *storeTestSuite) TestStoreService_FloorsWith_ShouldErrorFind() {
-	floorRepoMock := new(mocks.ICRUDRepository[model.Floor])
+	floorRepoMock := new(mocks.ISoftDeleteRepository[model.Floor])
 	tableRepoMock := new(mocks.ICRUDAddOnRepository[model.Table])
 	svc := service.NewStoreService(
 		floorRepoMock, tableRepoMock,
-		new(mocks.ICRUDAddOnRepository[model.Room]))
+		new(mocks.ICRUDAddOnRepository[model.Room]), new(mocks.IReferenceRepository))
 	floorRepoMock.
 		On("All", mock.Anything).
 		Once().
@@ -1,6 +1,214 @@
 package http
 
-// type orderHandler struct{}
-// add order items to transaction
-// payment
-// func NewOrderHandler() {}
+import (
+	"net/http"
+
+	"github.com/aasumitro/posbe/pkg/http/middleware"
+	"github.com/aasumitro/posbe/pkg/model"
+	"github.com/aasumitro/posbe/pkg/utils"
+	"github.com/gin-gonic/gin"
+)
+
+type orderHandler struct {
+	svc model.IOrderService
+}
+
+// orders godoc
+// @Schemes
+// @Summary Order Detail
+// @Description Get order with its items, payments and refunds.
+// @Tags Orders
+// @Accept json
+// @Produce json
+// @Param id path string true "order id"
+// @Success 200 {object} utils.SuccessRespond{data=model.Order} "OK RESPOND"
+// @Failure 401 {object} utils.ErrorRespond "UNAUTHORIZED RESPOND"
+// @Failure 403 {object} utils.ErrorRespond "FORBIDDEN RESPOND"
+// @Failure 404 {object} utils.ErrorRespond "NOT FOUND RESPOND"
+// @Failure 500 {object} utils.ErrorRespond "INTERNAL SERVER ERROR RESPOND"
+// @Router /api/v1/orders/{id} [GET]
+func (handler orderHandler) detail(ctx *gin.Context) {
+	data, err := handler.svc.OrderDetail(ctx, ctx.Param("id"))
+	if err != nil {
+		utils.NewHTTPRespond(ctx, err.Code, err.Message)
+		return
+	}
+
+	utils.NewHTTPRespond(ctx, http.StatusOK, data)
+}
+
+// orders godoc
+// @Schemes
+// @Summary Void Order
+// @Description Void an open order, a paid order is refunded instead. Users without order.void
+// @Description permission need a supervisor override in X-Override-Token header.
+// @Tags Orders
+// @Accept json
+// @Produce json
+// @Param X-Override-Token header string false "override token"
+// @Param id path string true "order id"
+// @Param body body model.OrderVoidForm true "reason to void"
+// @Success 200 {object} utils.SuccessRespond{data=model.Order} "OK RESPOND"
+// @Failure 401 {object} utils.ErrorRespond "UNAUTHORIZED RESPOND"
+// @Failure 403 {object} utils.ErrorRespond "FORBIDDEN RESPOND"
+// @Failure 404 {object} utils.ErrorRespond "NOT FOUND RESPOND"
+// @Failure 409 {object} utils.ErrorRespond "CONFLICT RESPOND"
+// @Failure 422 {object} utils.ValidationErrorRespond "UNPROCESSABLE ENTITY RESPOND"
+// @Failure 500 {object} utils.ErrorRespond "INTERNAL SERVER ERROR RESPOND"
+// @Router /api/v1/orders/{id}/void [POST]
+func (handler orderHandler) void(ctx *gin.Context) {
+	var form model.OrderVoidForm
+	if err := ctx.ShouldBindJSON(&form); err != nil {
+		utils.NewHTTPRespond(ctx,
+			http.StatusUnprocessableEntity,
+			err.Error())
+		return
+	}
+	form.VoidedBy = middleware.PayloadUserID(ctx)
+	form.ApprovedBy = middleware.ApproverID(ctx)
+
+	data, err := handler.svc.VoidOrder(ctx, ctx.Param("id"), &form)
+	if err != nil {
+		utils.NewHTTPRespond(ctx, err.Code, err.Message)
+		return
+	}
+
+	utils.NewHTTPRespond(ctx, http.StatusOK, data)
+}
+
+// orders godoc
+// @Schemes
+// @Summary Refund Order
+// @Description Refund a part (or all) of what was paid on a paid order, never more than what is left to refund.
+// @Description Users without order.refund permission need a supervisor override in X-Override-Token header.
+// @Tags Orders
+// @Accept json
+// @Produce json
+// @Param X-Override-Token header string false "override token"
+// @Param id path string true "order id"
+// @Param body body model.OrderRefundForm true "amount and reason to refund"
+// @Success 201 {object} utils.SuccessRespond{data=model.OrderRefund} "CREATED RESPOND"
+// @Failure 401 {object} utils.ErrorRespond "UNAUTHORIZED RESPOND"
+// @Failure 403 {object} utils.ErrorRespond "FORBIDDEN RESPOND"
+// @Failure 404 {object} utils.ErrorRespond "NOT FOUND RESPOND"
+// @Failure 409 {object} utils.ErrorRespond "CONFLICT RESPOND"
+// @Failure 422 {object} utils.ValidationErrorRespond "UNPROCESSABLE ENTITY RESPOND"
+// @Failure 500 {object} utils.ErrorRespond "INTERNAL SERVER ERROR RESPOND"
+// @Router /api/v1/orders/{id}/refund [POST]
+func (handler orderHandler) refund(ctx *gin.Context) {
+	var form model.OrderRefundForm
+	if err := ctx.ShouldBindJSON(&form); err != nil {
+		utils.NewHTTPRespond(ctx,
+			http.StatusUnprocessableEntity,
+			err.Error())
+		return
+	}
+	form.RefundedBy = middleware.PayloadUserID(ctx)
+	form.ApprovedBy = middleware.ApproverID(ctx)
+
+	data, err := handler.svc.RefundOrder(ctx, ctx.Param("id"), &form)
+	if err != nil {
+		utils.NewHTTPRespond(ctx, err.Code, err.Message)
+		return
+	}
+
+	utils.NewHTTPRespond(ctx, http.StatusCreated, data)
+}
+
+// orders godoc
+// @Schemes
+// @Summary Override Item Price
+// @Description Set the price of an item of an open order by hand, the next pushes keep it.
+// @Description Users without order.price_override permission need a supervisor override in X-Override-Token header.
+// @Tags Orders
+// @Accept json
+// @Produce json
+// @Param X-Override-Token header string false "override token"
+// @Param id path string true "order id"
+// @Param item_id path string true "order item id"
+// @Param body body model.OrderPriceForm true "price of the item"
+// @Success 200 {object} utils.SuccessRespond{data=model.Order} "OK RESPOND"
+// @Failure 401 {object} utils.ErrorRespond "UNAUTHORIZED RESPOND"
+// @Failure 403 {object} utils.ErrorRespond "FORBIDDEN RESPOND"
+// @Failure 404 {object} utils.ErrorRespond "NOT FOUND RESPOND"
+// @Failure 409 {object} utils.ErrorRespond "CONFLICT RESPOND"
+// @Failure 422 {object} utils.ValidationErrorRespond "UNPROCESSABLE ENTITY RESPOND"
+// @Failure 500 {object} utils.ErrorRespond "INTERNAL SERVER ERROR RESPOND"
+// @Router /api/v1/orders/{id}/items/{item_id}/price [PUT]
+func (handler orderHandler) price(ctx *gin.Context) {
+	var form model.OrderPriceForm
+	if err := ctx.ShouldBindJSON(&form); err != nil {
+		utils.NewHTTPRespond(ctx,
+			http.StatusUnprocessableEntity,
+			err.Error())
+		return
+	}
+	form.ApprovedBy = middleware.ApproverID(ctx)
+
+	data, err := handler.svc.OverrideItemPrice(ctx,
+		ctx.Param("id"), ctx.Param("item_id"), &form)
+	if err != nil {
+		utils.NewHTTPRespond(ctx, err.Code, err.Message)
+		return
+	}
+
+	utils.NewHTTPRespond(ctx, http.StatusOK, data)
+}
+
+// orders godoc
+// @Schemes
+// @Summary Discount Order
+// @Description Set the discount (taken off before tax), tax exempt and no service of an open order,
+// @Description the next pushes keep them. Users without order.discount permission need a supervisor
+// @Description override in X-Override-Token header.
+// @Tags Orders
+// @Accept json
+// @Produce json
+// @Param X-Override-Token header string false "override token"
+// @Param id path string true "order id"
+// @Param body body model.OrderDiscountForm true "discount, tax exempt and no service"
+// @Success 200 {object} utils.SuccessRespond{data=model.Order} "OK RESPOND"
+// @Failure 401 {object} utils.ErrorRespond "UNAUTHORIZED RESPOND"
+// @Failure 403 {object} utils.ErrorRespond "FORBIDDEN RESPOND"
+// @Failure 404 {object} utils.ErrorRespond "NOT FOUND RESPOND"
+// @Failure 409 {object} utils.ErrorRespond "CONFLICT RESPOND"
+// @Failure 422 {object} utils.ValidationErrorRespond "UNPROCESSABLE ENTITY RESPOND"
+// @Failure 500 {object} utils.ErrorRespond "INTERNAL SERVER ERROR RESPOND"
+// @Router /api/v1/orders/{id}/discount [PUT]
+func (handler orderHandler) discount(ctx *gin.Context) {
+	var form model.OrderDiscountForm
+	if err := ctx.ShouldBindJSON(&form); err != nil {
+		utils.NewHTTPRespond(ctx,
+			http.StatusUnprocessableEntity,
+			err.Error())
+		return
+	}
+	form.ApprovedBy = middleware.ApproverID(ctx)
+
+	data, err := handler.svc.DiscountOrder(ctx, ctx.Param("id"), &form)
+	if err != nil {
+		utils.NewHTTPRespond(ctx, err.Code, err.Message)
+		return
+	}
+
+	utils.NewHTTPRespond(ctx, http.StatusOK, data)
+}
+
+func NewOrderHandler(svc model.IOrderService, router gin.IRoutes) {
+	handler := orderHandler{svc: svc}
+	router.GET("/orders/:id",
+		middleware.Permitted(model.PermissionOrderRead),
+		handler.detail)
+	router.POST("/orders/:id/void",
+		middleware.Approved(model.PermissionOrderVoid),
+		handler.void)
+	router.POST("/orders/:id/refund",
+		middleware.Approved(model.PermissionOrderRefund),
+		handler.refund)
+	router.PUT("/orders/:id/items/:item_id/price",
+		middleware.Approved(model.PermissionOrderPriceOverride),
+		handler.price)
+	router.PUT("/orders/:id/discount",
+		middleware.Approved(model.PermissionOrderDiscount),
+		handler.discount)
+}
@@ -0,0 +1,120 @@
+package http_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	transactionHttp "github.com/aasumitro/posbe/internal/transaction/handler/http"
+	"github.com/aasumitro/posbe/mocks"
+	"github.com/aasumitro/posbe/pkg/http/middleware"
+	"github.com/aasumitro/posbe/pkg/model"
+	"github.com/aasumitro/posbe/pkg/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+const (
+	cashierID    = 2
+	supervisorID = 1
+	overrideKey  = "override-token"
+)
+
+// orderRouter serve the order routes to a logged-in user
+// whose role is granted the permissions
+func orderRouter(t *testing.T, svc model.IOrderService, permissions ...string) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	middleware.SetPermissionResolver(func(_ context.Context, _ int) ([]string, *utils.ServiceError) {
+		return permissions, nil
+	})
+	middleware.SetOverrideConsumer(func(_ context.Context, token, action string, _ int) (*model.Override, *utils.ServiceError) {
+		if token != overrideKey {
+			return nil, &utils.ServiceError{Code: http.StatusForbidden, Message: "OVERRIDE_INVALID"}
+		}
+		return &model.Override{Action: action, ApprovedBy: supervisorID}, nil
+	})
+	t.Cleanup(func() {
+		middleware.SetPermissionResolver(nil)
+		middleware.SetOverrideConsumer(nil)
+	})
+	router := gin.New()
+	group := router.Group("/api/v1", func(ctx *gin.Context) {
+		ctx.Set("payload", map[string]interface{}{
+			"id":   float64(cashierID),
+			"role": map[string]interface{}{"id": float64(2)},
+		})
+	})
+	transactionHttp.NewOrderHandler(svc, group)
+	return router
+}
+
+// orderRequests is a valid request to each route that need an override
+var orderRequests = []struct{ method, path, body string }{
+	{http.MethodPost, "/api/v1/orders/lorem/void", `{"reason":"lorem"}`},
+	{http.MethodPost, "/api/v1/orders/lorem/refund", `{"amount":10,"reason":"lorem"}`},
+	{http.MethodPut, "/api/v1/orders/lorem/items/ipsum/price", `{"price":5}`},
+	{http.MethodPut, "/api/v1/orders/lorem/discount", `{"discount":5,"no_service":true}`},
+}
+
+func serve(router *gin.Engine, method, path, body, token string) *httptest.ResponseRecorder {
+	writer := httptest.NewRecorder()
+	request := httptest.NewRequest(method, path, strings.NewReader(body))
+	request.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		request.Header.Set("X-Override-Token", token)
+	}
+	router.ServeHTTP(writer, request)
+	return writer
+}
+
+func TestOrderHandler_ShouldRequireOverrideWithoutPermission(t *testing.T) {
+	svc := mocks.NewIOrderService(t)
+	router := orderRouter(t, svc, model.PermissionOrderRead,
+		model.PermissionOrderWrite, model.PermissionOrderTender)
+	for _, req := range orderRequests {
+		writer := serve(router, req.method, req.path, req.body, "")
+		assert.Equal(t, http.StatusForbidden, writer.Code, req.path)
+		assert.Contains(t, writer.Body.String(), "OVERRIDE_REQUIRED", req.path)
+
+		writer = serve(router, req.method, req.path, req.body, "garbage")
+		assert.Equal(t, http.StatusForbidden, writer.Code, req.path)
+	}
+	svc.AssertNotCalled(t, "VoidOrder", mock.Anything, mock.Anything, mock.Anything)
+	svc.AssertNotCalled(t, "RefundOrder", mock.Anything, mock.Anything, mock.Anything)
+	svc.AssertNotCalled(t, "OverrideItemPrice", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	svc.AssertNotCalled(t, "DiscountOrder", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestOrderHandler_ShouldPassApproverOfOverride(t *testing.T) {
+	svc := mocks.NewIOrderService(t)
+	router := orderRouter(t, svc, model.PermissionOrderRead)
+	svc.On("VoidOrder", mock.Anything, "lorem", mock.MatchedBy(func(form *model.OrderVoidForm) bool {
+		return form.VoidedBy == cashierID && form.ApprovedBy == supervisorID
+	})).Return(&model.Order{ID: "lorem"}, nil).Once()
+	svc.On("RefundOrder", mock.Anything, "lorem", mock.MatchedBy(func(form *model.OrderRefundForm) bool {
+		return form.RefundedBy == cashierID && form.ApprovedBy == supervisorID
+	})).Return(&model.OrderRefund{ID: 1}, nil).Once()
+	svc.On("OverrideItemPrice", mock.Anything, "lorem", "ipsum", mock.MatchedBy(func(form *model.OrderPriceForm) bool {
+		return form.ApprovedBy == supervisorID
+	})).Return(&model.Order{ID: "lorem"}, nil).Once()
+	svc.On("DiscountOrder", mock.Anything, "lorem", mock.MatchedBy(func(form *model.OrderDiscountForm) bool {
+		return form.NoService && form.ApprovedBy == supervisorID
+	})).Return(&model.Order{ID: "lorem"}, nil).Once()
+	for _, req := range orderRequests {
+		writer := serve(router, req.method, req.path, req.body, overrideKey)
+		assert.Contains(t, []int{http.StatusOK, http.StatusCreated}, writer.Code, req.path)
+	}
+}
+
+func TestOrderHandler_ShouldApproveByUserWithPermission(t *testing.T) {
+	svc := mocks.NewIOrderService(t)
+	router := orderRouter(t, svc, model.PermissionOrderVoid)
+	svc.On("VoidOrder", mock.Anything, "lorem", mock.MatchedBy(func(form *model.OrderVoidForm) bool {
+		return form.VoidedBy == cashierID && form.ApprovedBy == cashierID
+	})).Return(&model.Order{ID: "lorem"}, nil).Once()
+	writer := serve(router, orderRequests[0].method, orderRequests[0].path, orderRequests[0].body, "")
+	assert.Equal(t, http.StatusOK, writer.Code)
+}
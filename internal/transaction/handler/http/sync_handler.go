@@ -0,0 +1,99 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/aasumitro/posbe/pkg/http/middleware"
+	"github.com/aasumitro/posbe/pkg/model"
+	"github.com/aasumitro/posbe/pkg/utils"
+	"github.com/gin-gonic/gin"
+)
+
+type syncHandler struct {
+	svc model.ISyncService
+}
+
+// sync godoc
+// @Schemes
+// @Summary Pull Changes
+// @Description Get the changes of catalog, store layout and prefs after the version since, oldest first.
+// @Description Keep the cursor and pull again from it while there is more, since 0 pull everything.
+// @Tags Sync
+// @Accept json
+// @Produce json
+// @Param since query int false "last version the terminal has, default 0"
+// @Param limit query int false "max changes, default 500 (max 1000)"
+// @Success 200 {object} utils.SuccessRespond{data=model.SyncPull} "OK RESPOND"
+// @Failure 401 {object} utils.ErrorRespond "UNAUTHORIZED RESPOND"
+// @Failure 403 {object} utils.ErrorRespond "FORBIDDEN RESPOND"
+// @Failure 422 {object} utils.ValidationErrorRespond "UNPROCESSABLE ENTITY RESPOND"
+// @Failure 500 {object} utils.ErrorRespond "INTERNAL SERVER ERROR RESPOND"
+// @Router /api/v1/sync/pull [GET]
+func (handler syncHandler) pull(ctx *gin.Context) {
+	var query model.SyncPullQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		utils.NewHTTPRespond(ctx,
+			http.StatusUnprocessableEntity,
+			err.Error())
+		return
+	}
+
+	data, err := handler.svc.Pull(ctx, &query)
+	if err != nil {
+		utils.NewHTTPRespond(ctx, err.Code, err.Message)
+		return
+	}
+
+	utils.NewHTTPRespond(ctx, http.StatusOK, data)
+}
+
+// sync godoc
+// @Schemes
+// @Summary Push Orders
+// @Description Push the orders taken on the terminal (e.g: while offline) with their payments,
+// @Description ids are uuids made by the terminal so the same push can be sent again safely.
+// @Description Every order is reported back with the conflicts found and how the server resolved them,
+// @Description the terminal replace its copy with the order of the result. A push take an order as far as
+// @Description paid (with order.tender permission and payments that cover the total), void, tax exempt and
+// @Description no service are done with the /orders endpoints.
+// @Tags Sync
+// @Accept json
+// @Produce json
+// @Param body body model.SyncPushForm true "orders taken on the terminal"
+// @Success 200 {object} utils.SuccessRespond{data=model.SyncPushResult} "OK RESPOND"
+// @Failure 401 {object} utils.ErrorRespond "UNAUTHORIZED RESPOND"
+// @Failure 403 {object} utils.ErrorRespond "FORBIDDEN RESPOND"
+// @Failure 422 {object} utils.ValidationErrorRespond "UNPROCESSABLE ENTITY RESPOND"
+// @Failure 500 {object} utils.ErrorRespond "INTERNAL SERVER ERROR RESPOND"
+// @Router /api/v1/sync/push [POST]
+func (handler syncHandler) push(ctx *gin.Context) {
+	var form model.SyncPushForm
+	if err := ctx.ShouldBindJSON(&form); err != nil {
+		utils.NewHTTPRespond(ctx,
+			http.StatusUnprocessableEntity,
+			err.Error())
+		return
+	}
+	form.CreatedBy = middleware.PayloadUserID(ctx)
+	form.TerminalID = middleware.PayloadTerminalID(ctx)
+	form.CanTender = middleware.Granted(ctx, model.PermissionOrderTender)
+
+	data, err := handler.svc.Push(ctx, &form)
+	if err != nil {
+		utils.NewHTTPRespond(ctx, err.Code, err.Message)
+		return
+	}
+
+	utils.NewHTTPRespond(ctx, http.StatusOK, data)
+}
+
+func NewSyncHandler(svc model.ISyncService, router gin.IRoutes) {
+	handler := syncHandler{svc: svc}
+	router.GET("/sync/pull",
+		middleware.Permitted(model.PermissionCatalogRead),
+		middleware.Permitted(model.PermissionStoreRead),
+		handler.pull)
+	router.POST("/sync/push",
+		middleware.Permitted(model.PermissionOrderWrite),
+		handler.push)
+}
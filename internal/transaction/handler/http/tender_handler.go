@@ -0,0 +1,127 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/aasumitro/posbe/pkg/http/middleware"
+	"github.com/aasumitro/posbe/pkg/model"
+	"github.com/aasumitro/posbe/pkg/utils"
+	"github.com/gin-gonic/gin"
+)
+
+type tenderHandler struct {
+	svc model.ITenderService
+}
+
+// tenders godoc
+// @Schemes
+// @Summary Cash Tender
+// @Description Accept cash in the store currency or a foreign currency,
+// @Description foreign cash is converted with the rate in effect and the change is given in the store currency.
+// @Tags Tenders
+// @Accept json
+// @Produce json
+// @Param body body model.TenderForm true "tendered cash and amount due"
+// @Success 201 {object} utils.SuccessRespond{data=model.CashTender} "CREATED RESPOND"
+// @Failure 401 {object} utils.ErrorRespond "UNAUTHORIZED RESPOND"
+// @Failure 422 {object} utils.ValidationErrorRespond "UNPROCESSABLE ENTITY RESPOND"
+// @Failure 500 {object} utils.ErrorRespond "INTERNAL SERVER ERROR RESPOND"
+// @Router /api/v1/tenders/cash [POST]
+func (handler tenderHandler) cash(ctx *gin.Context) {
+	var form model.TenderForm
+	if err := ctx.ShouldBindJSON(&form); err != nil {
+		utils.NewHTTPRespond(ctx,
+			http.StatusUnprocessableEntity,
+			err.Error())
+		return
+	}
+	form.CreatedBy = middleware.PayloadUserID(ctx)
+
+	data, err := handler.svc.CashTender(ctx, &form)
+	if err != nil {
+		utils.NewHTTPRespond(ctx, err.Code, err.Message)
+		return
+	}
+
+	utils.NewHTTPRespond(ctx, http.StatusCreated, data)
+}
+
+// tenders godoc
+// @Schemes
+// @Summary Tender Detail
+// @Description Get tender with the rate it was converted with.
+// @Tags Tenders
+// @Accept json
+// @Produce json
+// @Param id path int true "tender id"
+// @Success 200 {object} utils.SuccessRespond{data=model.CashTender} "OK RESPOND"
+// @Failure 400 {object} utils.ErrorRespond "BAD REQUEST RESPOND"
+// @Failure 401 {object} utils.ErrorRespond "UNAUTHORIZED RESPOND"
+// @Failure 404 {object} utils.ErrorRespond "NOT FOUND RESPOND"
+// @Failure 500 {object} utils.ErrorRespond "INTERNAL SERVER ERROR RESPOND"
+// @Router /api/v1/tenders/{id} [GET]
+func (handler tenderHandler) detail(ctx *gin.Context) {
+	id, errParse := strconv.Atoi(ctx.Param("id"))
+	if errParse != nil {
+		utils.NewHTTPRespond(ctx,
+			http.StatusBadRequest,
+			errParse.Error())
+		return
+	}
+
+	data, err := handler.svc.TenderDetail(ctx, id)
+	if err != nil {
+		utils.NewHTTPRespond(ctx, err.Code, err.Message)
+		return
+	}
+
+	utils.NewHTTPRespond(ctx, http.StatusOK, data)
+}
+
+// cash drawer godoc
+// @Schemes
+// @Summary Open Cash Drawer
+// @Description Open the cash drawer without a sale, users without cash_drawer.open
+// @Description permission need a supervisor override in X-Override-Token header.
+// @Tags Tenders
+// @Accept json
+// @Produce json
+// @Param X-Override-Token header string false "override token"
+// @Param body body model.CashDrawerForm true "reason to open"
+// @Success 201 {object} utils.SuccessRespond{data=model.CashDrawerOpen} "CREATED RESPOND"
+// @Failure 401 {object} utils.ErrorRespond "UNAUTHORIZED RESPOND"
+// @Failure 403 {object} utils.ErrorRespond "FORBIDDEN RESPOND"
+// @Failure 422 {object} utils.ValidationErrorRespond "UNPROCESSABLE ENTITY RESPOND"
+// @Failure 500 {object} utils.ErrorRespond "INTERNAL SERVER ERROR RESPOND"
+// @Router /api/v1/cash-drawer/open [POST]
+func (handler tenderHandler) openDrawer(ctx *gin.Context) {
+	var form model.CashDrawerForm
+	if err := ctx.ShouldBindJSON(&form); err != nil {
+		utils.NewHTTPRespond(ctx,
+			http.StatusUnprocessableEntity,
+			err.Error())
+		return
+	}
+	form.OpenedBy = middleware.PayloadUserID(ctx)
+	form.ApprovedBy = middleware.ApproverID(ctx)
+
+	data, err := handler.svc.OpenCashDrawer(ctx, &form)
+	if err != nil {
+		utils.NewHTTPRespond(ctx, err.Code, err.Message)
+		return
+	}
+
+	utils.NewHTTPRespond(ctx, http.StatusCreated, data)
+}
+
+func NewTenderHandler(svc model.ITenderService, router gin.IRoutes) {
+	handler := tenderHandler{svc: svc}
+	tender := middleware.Permitted(model.PermissionOrderTender)
+	read := middleware.Permitted(model.PermissionOrderRead)
+	router.POST("/tenders/cash", tender, handler.cash)
+	router.GET("/tenders/:id", read, handler.detail)
+	router.POST("/cash-drawer/open",
+		middleware.Approved(model.PermissionCashDrawerOpen),
+		handler.openDrawer)
+}
@@ -1,12 +1,39 @@
 package transaction
 
 import (
+	"github.com/aasumitro/posbe/common"
+	catalogRepository "github.com/aasumitro/posbe/internal/catalog/repository/sql"
+	catalogService "github.com/aasumitro/posbe/internal/catalog/service"
+	storeRepository "github.com/aasumitro/posbe/internal/store/repository/sql"
+	"github.com/aasumitro/posbe/internal/transaction/handler/http"
+	repository "github.com/aasumitro/posbe/internal/transaction/repository/sql"
+	"github.com/aasumitro/posbe/internal/transaction/service"
+	"github.com/aasumitro/posbe/pkg/http/middleware"
 	"github.com/gin-gonic/gin"
 )
 
-func NewTransactionModuleProvider(_ *gin.RouterGroup) {
-	// Order
-	// OrderItem
-	// OrderBill
-	// Order...
+func NewTransactionModuleProvider(router *gin.RouterGroup) {
+	cashTenderRepository := repository.NewCashTenderSQLRepository()
+	currencyRateRepository := storeRepository.NewCurrencyRateSQLRepository()
+	storePrefRepository := storeRepository.NewStorePrefSQLRepository()
+	orderRepository := repository.NewOrderSQLRepository()
+	taxService := catalogService.NewCatalogTaxService(
+		catalogRepository.NewTaxClassSQLRepository(), storePrefRepository)
+	tenderService := service.NewTenderService(
+		cashTenderRepository, currencyRateRepository)
+	syncService := service.NewSyncService(
+		repository.NewSyncSQLRepository(),
+		orderRepository,
+		catalogRepository.NewAvailabilitySQLRepository(),
+		catalogService.NewCatalogPriceService(
+			catalogRepository.NewPriceListSQLRepository(), storePrefRepository),
+		taxService)
+	orderService := service.NewOrderService(orderRepository, taxService)
+	// use sub group, so the middlewares not leaking to other modules
+	protectedRouter := router.Group(common.EmptyPath).
+		Use(middleware.Auth()).
+		Use(middleware.ActivityObserver())
+	http.NewTenderHandler(tenderService, protectedRouter)
+	http.NewSyncHandler(syncService, protectedRouter)
+	http.NewOrderHandler(orderService, protectedRouter)
 }
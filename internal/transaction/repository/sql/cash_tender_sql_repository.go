@@ -0,0 +1,87 @@
+package sql
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/aasumitro/posbe/config"
+	"github.com/aasumitro/posbe/pkg/model"
+	"github.com/aasumitro/posbe/pkg/money"
+)
+
+type CashTenderSQLRepository struct {
+	Db *sql.DB
+}
+
+func (repo CashTenderSQLRepository) Find(ctx context.Context, id int) (data *model.CashTender, err error) {
+	q := "SELECT id, COALESCE(store_shift_id, 0), COALESCE(currency_rate_id, 0), rate, "
+	q += "currency, amount, base_currency, base_amount, due, change, "
+	q += "COALESCE(created_by, 0), created_at FROM cash_tenders WHERE id = $1"
+	data = &model.CashTender{}
+	var currency, baseCurrency string
+	if err := repo.Db.QueryRowContext(ctx, q, id).Scan(
+		&data.ID, &data.StoreShiftID, &data.CurrencyRateID, &data.Rate,
+		&currency, &data.Tendered.Amount, &baseCurrency, &data.Converted.Amount,
+		&data.Due.Amount, &data.Change.Amount, &data.CreatedBy, &data.CreatedAt,
+	); err != nil {
+		return nil, err
+	}
+	// both code were validated when the tender was taken
+	data.Tendered.Currency, _ = money.Lookup(currency)
+	base, _ := money.Lookup(baseCurrency)
+	data.Converted.Currency, data.Due.Currency, data.Change.Currency = base, base, base
+
+	return data, nil
+}
+
+// Create record the tender on the open store shift
+func (repo CashTenderSQLRepository) Create(ctx context.Context, tender *model.CashTender) (data *model.CashTender, err error) {
+	q := "INSERT INTO cash_tenders (store_shift_id, currency_rate_id, rate, currency, amount, "
+	q += "base_currency, base_amount, due, change, created_by, created_at) "
+	q += "VALUES ((SELECT id FROM store_shifts WHERE close_at IS NULL ORDER BY open_at DESC LIMIT 1), "
+	q += "$1, $2, $3, $4, $5, $6, $7, $8, $9, $10) RETURNING id, COALESCE(store_shift_id, 0)"
+	created := *tender
+	data = &created
+	data.CreatedAt = time.Now().Unix()
+	if err := repo.Db.QueryRowContext(ctx, q,
+		nullID(data.CurrencyRateID), data.Rate,
+		data.Tendered.Currency.Code, data.Tendered.Amount,
+		data.Converted.Currency.Code, data.Converted.Amount,
+		data.Due.Amount, data.Change.Amount,
+		nullID(data.CreatedBy), data.CreatedAt,
+	).Scan(&data.ID, &data.StoreShiftID); err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// OpenDrawer record the open on the open store shift
+func (repo CashTenderSQLRepository) OpenDrawer(
+	ctx context.Context,
+	drawer *model.CashDrawerOpen,
+) (data *model.CashDrawerOpen, err error) {
+	q := "INSERT INTO cash_drawer_opens (store_shift_id, reason, opened_by, approved_by, created_at) "
+	q += "SELECT id, $1, $2, $3, $4 FROM store_shifts WHERE close_at IS NULL "
+	q += "ORDER BY open_at DESC LIMIT 1 RETURNING id, store_shift_id"
+	created := *drawer
+	data = &created
+	data.CreatedAt = time.Now().Unix()
+	if err := repo.Db.QueryRowContext(ctx, q,
+		data.Reason, nullID(data.OpenedBy), nullID(data.ApprovedBy), data.CreatedAt,
+	).Scan(&data.ID, &data.StoreShiftID); err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// nullID store zero id as NULL for optional foreign key
+func nullID(id int) sql.NullInt64 {
+	return sql.NullInt64{Int64: int64(id), Valid: id > 0}
+}
+
+func NewCashTenderSQLRepository() model.ICashTenderRepository {
+	return &CashTenderSQLRepository{Db: config.DbPool}
+}
@@ -0,0 +1,137 @@
+package sql_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/aasumitro/posbe/config"
+	storeSql "github.com/aasumitro/posbe/internal/store/repository/sql"
+	repoSql "github.com/aasumitro/posbe/internal/transaction/repository/sql"
+	"github.com/aasumitro/posbe/pkg/dbtest"
+	"github.com/aasumitro/posbe/pkg/dialect"
+	"github.com/aasumitro/posbe/pkg/model"
+	"github.com/aasumitro/posbe/pkg/money"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type cashTenderRepositoryTestSuite struct {
+	suite.Suite
+	mock sqlmock.Sqlmock
+	repo model.ICashTenderRepository
+}
+
+func (suite *cashTenderRepositoryTestSuite) SetupSuite() {
+	var err error
+	config.DbPool, suite.mock, err = sqlmock.New(
+		sqlmock.QueryMatcherOption(sqlmock.QueryMatcherRegexp))
+	require.NoError(suite.T(), err)
+	suite.repo = repoSql.NewCashTenderSQLRepository()
+}
+
+func (suite *cashTenderRepositoryTestSuite) AfterTest(_, _ string) {
+	require.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+func (suite *cashTenderRepositoryTestSuite) TestRepository_Find_ExpectReturnRow() {
+	rows := suite.mock.NewRows([]string{"id", "store_shift_id", "currency_rate_id", "rate",
+		"currency", "amount", "base_currency", "base_amount", "due", "change",
+		"created_by", "created_at"}).
+		AddRow(1, 3, 2, []byte("16200.00000000"), "USD", 2000, "IDR", 32400000, 30000000, 2400000, 1, 123)
+	suite.mock.ExpectQuery("SELECT (.+) FROM cash_tenders WHERE id = (.+)").
+		WithArgs(1).WillReturnRows(rows)
+	res, err := suite.repo.Find(context.TODO(), 1)
+	require.NoError(suite.T(), err)
+	require.Equal(suite.T(), "USD", res.Tendered.Currency.Code)
+	require.Equal(suite.T(), "IDR", res.Change.Currency.Code)
+	require.Equal(suite.T(), money.Amount(2400000), res.Change.Amount)
+	require.Equal(suite.T(), "16200", res.Rate.String())
+}
+
+func (suite *cashTenderRepositoryTestSuite) TestRepository_Find_ExpectReturnErrorNoRows() {
+	suite.mock.ExpectQuery("SELECT (.+) FROM cash_tenders WHERE id = (.+)").
+		WithArgs(9).WillReturnError(sql.ErrNoRows)
+	res, err := suite.repo.Find(context.TODO(), 9)
+	require.Nil(suite.T(), res)
+	require.ErrorIs(suite.T(), err, sql.ErrNoRows)
+}
+
+func (suite *cashTenderRepositoryTestSuite) TestRepository_Create_ExpectReturnRow() {
+	usd, _ := money.Lookup("USD")
+	idr, _ := money.Lookup("IDR")
+	rate, _ := money.ParseRate("16200")
+	suite.mock.ExpectQuery("INSERT INTO cash_tenders (.+) RETURNING id").
+		WithArgs(sql.NullInt64{Int64: 2, Valid: true}, "16200", "USD", int64(2000),
+			"IDR", int64(32400000), int64(30000000), int64(2400000),
+			sql.NullInt64{}, sqlmock.AnyArg()).
+		WillReturnRows(suite.mock.NewRows([]string{"id", "store_shift_id"}).AddRow(1, 3))
+	res, err := suite.repo.Create(context.TODO(), &model.CashTender{
+		CurrencyRateID: 2, Rate: rate,
+		Tendered:  money.Money{Amount: 2000, Currency: usd},
+		Converted: money.Money{Amount: 32400000, Currency: idr},
+		Due:       money.Money{Amount: 30000000, Currency: idr},
+		Change:    money.Money{Amount: 2400000, Currency: idr},
+	})
+	require.NoError(suite.T(), err)
+	require.Equal(suite.T(), 1, res.ID)
+	require.Equal(suite.T(), 3, res.StoreShiftID)
+}
+
+func (suite *cashTenderRepositoryTestSuite) TestRepository_OpenDrawer_ExpectReturnRow() {
+	suite.mock.ExpectQuery("INSERT INTO cash_drawer_opens (.+) SELECT (.+) FROM store_shifts (.+) RETURNING id, store_shift_id").
+		WithArgs("change for float", sql.NullInt64{Int64: 2, Valid: true},
+			sql.NullInt64{Int64: 1, Valid: true}, sqlmock.AnyArg()).
+		WillReturnRows(suite.mock.NewRows([]string{"id", "store_shift_id"}).AddRow(1, 3))
+	res, err := suite.repo.OpenDrawer(context.TODO(), &model.CashDrawerOpen{
+		Reason: "change for float", OpenedBy: 2, ApprovedBy: 1})
+	require.NoError(suite.T(), err)
+	require.Equal(suite.T(), 3, res.StoreShiftID)
+}
+
+func (suite *cashTenderRepositoryTestSuite) TestRepository_OpenDrawer_ExpectReturnErrorNoShift() {
+	suite.mock.ExpectQuery("INSERT INTO cash_drawer_opens (.+)").
+		WillReturnError(sql.ErrNoRows)
+	res, err := suite.repo.OpenDrawer(context.TODO(), &model.CashDrawerOpen{Reason: "lorem"})
+	require.Nil(suite.T(), res)
+	require.ErrorIs(suite.T(), err, sql.ErrNoRows)
+}
+
+func (suite *cashTenderRepositoryTestSuite) TestCashTenderRepository_OpenDrawer() {
+	dbtest.Migrated(suite.T(), config.Dialect.Name())
+	ctx := context.TODO()
+	repo := repoSql.NewCashTenderSQLRepository()
+	_, err := repo.OpenDrawer(ctx, &model.CashDrawerOpen{Reason: "lorem", OpenedBy: 2, ApprovedBy: 1})
+	require.ErrorIs(suite.T(), err, sql.ErrNoRows)
+
+	require.NoError(suite.T(), storeSql.NewStoreShiftSQLRepository().OpenShift(ctx,
+		&model.StoreShiftForm{UserID: 1, ShiftID: 1, Cash: 100000}))
+	drawer, err := repo.OpenDrawer(ctx, &model.CashDrawerOpen{Reason: "lorem", OpenedBy: 2, ApprovedBy: 1})
+	require.NoError(suite.T(), err)
+	require.NotZero(suite.T(), drawer.StoreShiftID)
+
+	idr, err := money.Lookup(money.DefaultCurrency)
+	require.NoError(suite.T(), err)
+	rate, err := money.ParseRate("1")
+	require.NoError(suite.T(), err)
+	tender, err := repo.Create(ctx, &model.CashTender{Rate: rate,
+		Tendered: money.Money{Currency: idr, Amount: 5000}, Converted: money.Money{Currency: idr, Amount: 5000},
+		Due: money.Money{Currency: idr, Amount: 3000}, Change: money.Money{Currency: idr, Amount: 2000},
+		CreatedBy: 2})
+	require.NoError(suite.T(), err)
+	require.Equal(suite.T(), drawer.StoreShiftID, tender.StoreShiftID)
+	found, err := repo.Find(ctx, tender.ID)
+	require.NoError(suite.T(), err)
+	require.Equal(suite.T(), money.Amount(2000), found.Change.Amount)
+	require.Equal(suite.T(), idr, found.Change.Currency)
+}
+
+func TestCashTenderRepository(t *testing.T) {
+	for _, d := range dialect.All() {
+		t.Run(d.Name(), func(t *testing.T) {
+			config.Dialect = d
+			suite.Run(t, new(cashTenderRepositoryTestSuite))
+		})
+	}
+}
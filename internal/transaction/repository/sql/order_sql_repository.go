@@ -0,0 +1,294 @@
+package sql
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/aasumitro/posbe/config"
+	"github.com/aasumitro/posbe/pkg/model"
+)
+
+const orderColumns = "id, COALESCE(terminal_id, 0), COALESCE(table_id, 0), COALESCE(room_id, 0), " +
+	"status, currency, total, service_charge, tax, tax_summary, discount, tax_exempt, no_service, " +
+	"COALESCE(discount_approved_by, 0), COALESCE(void_reason, ''), COALESCE(voided_by, 0), " +
+	"COALESCE(void_approved_by, 0), COALESCE(voided_at, 0), version, checksum, " +
+	"COALESCE(created_by, 0), created_at, COALESCE(updated_at, 0), synced_at"
+
+type OrderSQLRepository struct {
+	Db *sql.DB
+}
+
+func (repo OrderSQLRepository) Find(ctx context.Context, id string) (data *model.Order, err error) {
+	q := "SELECT " + orderColumns + " FROM orders WHERE id = $1"
+	data = &model.Order{}
+	// orders saved before the tax was calculated have no summary
+	var summary sql.Null[model.TaxSummary]
+	if err := repo.Db.QueryRowContext(ctx, q, id).Scan(
+		&data.ID, &data.TerminalID, &data.TableID, &data.RoomID,
+		&data.Status, &data.Currency, &data.Total, &data.ServiceCharge, &data.Tax,
+		&summary, &data.Discount, &data.TaxExempt, &data.NoService,
+		&data.DiscountApprovedBy, &data.VoidReason, &data.VoidedBy,
+		&data.VoidApprovedBy, &data.VoidedAt, &data.Version, &data.Checksum,
+		&data.CreatedBy, &data.CreatedAt, &data.UpdatedAt, &data.SyncedAt,
+	); err != nil {
+		return nil, err
+	}
+	if summary.Valid {
+		data.TaxSummary = &summary.V
+	}
+	if data.Items, err = repo.items(ctx, id); err != nil {
+		return nil, err
+	}
+	if data.Payments, err = repo.payments(ctx, id); err != nil {
+		return nil, err
+	}
+	if data.Refunds, err = repo.refunds(ctx, id); err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+func (repo OrderSQLRepository) items(ctx context.Context, orderID string) (data []*model.OrderItem, err error) {
+	q := "SELECT id, product_id, COALESCE(variant_id, 0), name, quantity, price, "
+	q += "COALESCE(price_list_id, 0), COALESCE(note, ''), COALESCE(void_reason, ''), "
+	q += "COALESCE(price_approved_by, 0) FROM order_items "
+	q += "WHERE order_id = $1 ORDER BY position"
+	rows, err := repo.Db.QueryContext(ctx, q, orderID)
+	if err != nil {
+		return nil, err
+	}
+	defer func(rows *sql.Rows) { _ = rows.Close() }(rows)
+
+	data = []*model.OrderItem{}
+	for rows.Next() {
+		var item model.OrderItem
+		if err := rows.Scan(&item.ID, &item.ProductID, &item.VariantID, &item.Name,
+			&item.Quantity, &item.Price, &item.PriceListID, &item.Note, &item.VoidReason,
+			&item.PriceApprovedBy); err != nil {
+			return nil, err
+		}
+		data = append(data, &item)
+	}
+
+	return data, rows.Err()
+}
+
+func (repo OrderSQLRepository) payments(ctx context.Context, orderID string) (data []*model.OrderPayment, err error) {
+	q := "SELECT id, method, amount, COALESCE(reference, ''), COALESCE(created_by, 0), created_at "
+	q += "FROM order_payments WHERE order_id = $1 ORDER BY created_at, id"
+	rows, err := repo.Db.QueryContext(ctx, q, orderID)
+	if err != nil {
+		return nil, err
+	}
+	defer func(rows *sql.Rows) { _ = rows.Close() }(rows)
+
+	data = []*model.OrderPayment{}
+	for rows.Next() {
+		var payment model.OrderPayment
+		if err := rows.Scan(&payment.ID, &payment.Method, &payment.Amount,
+			&payment.Reference, &payment.CreatedBy, &payment.CreatedAt); err != nil {
+			return nil, err
+		}
+		data = append(data, &payment)
+	}
+
+	return data, rows.Err()
+}
+
+func (repo OrderSQLRepository) refunds(ctx context.Context, orderID string) (data []*model.OrderRefund, err error) {
+	q := "SELECT id, order_id, amount, reason, COALESCE(refunded_by, 0), COALESCE(approved_by, 0), "
+	q += "created_at FROM order_refunds WHERE order_id = $1 ORDER BY created_at, id"
+	rows, err := repo.Db.QueryContext(ctx, q, orderID)
+	if err != nil {
+		return nil, err
+	}
+	defer func(rows *sql.Rows) { _ = rows.Close() }(rows)
+
+	data = []*model.OrderRefund{}
+	for rows.Next() {
+		var refund model.OrderRefund
+		if err := rows.Scan(&refund.ID, &refund.OrderID, &refund.Amount, &refund.Reason,
+			&refund.RefundedBy, &refund.ApprovedBy, &refund.CreatedAt); err != nil {
+			return nil, err
+		}
+		data = append(data, &refund)
+	}
+
+	return data, rows.Err()
+}
+
+// Save write the order and replace its items in one transaction, the
+// version guard keep two pushes of the same order from both being applied
+func (repo OrderSQLRepository) Save(ctx context.Context, order *model.Order) (data *model.Order, err error) {
+	tx, err := repo.Db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	saved := *order
+	data = &saved
+	data.SyncedAt = time.Now().Unix()
+	var result sql.Result
+	if data.Version == 1 {
+		q := "INSERT INTO orders (id, terminal_id, table_id, room_id, status, currency, total, "
+		q += "service_charge, tax, tax_summary, discount, tax_exempt, no_service, "
+		q += "discount_approved_by, void_reason, voided_by, void_approved_by, voided_at, "
+		q += "version, checksum, created_by, created_at, updated_at, synced_at) "
+		q += "VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, "
+		q += "$17, $18, $19, $20, $21, $22, $23, $24) ON CONFLICT (id) DO NOTHING"
+		result, err = tx.ExecContext(ctx, q, data.ID, nullID(data.TerminalID),
+			nullID(data.TableID), nullID(data.RoomID), data.Status, data.Currency,
+			data.Total, data.ServiceCharge, data.Tax, data.TaxSummary, data.Discount,
+			data.TaxExempt, data.NoService, nullID(data.DiscountApprovedBy),
+			nullString(data.VoidReason), nullID(data.VoidedBy), nullID(data.VoidApprovedBy),
+			nullInt(data.VoidedAt), data.Version, data.Checksum, nullID(data.CreatedBy),
+			data.CreatedAt, nullInt(data.UpdatedAt), data.SyncedAt)
+	} else {
+		q := "UPDATE orders SET table_id = $1, room_id = $2, status = $3, total = $4, "
+		q += "service_charge = $5, tax = $6, tax_summary = $7, discount = $8, tax_exempt = $9, "
+		q += "no_service = $10, discount_approved_by = $11, void_reason = $12, voided_by = $13, "
+		q += "void_approved_by = $14, voided_at = $15, version = $16, checksum = $17, "
+		q += "updated_at = $18, synced_at = $19 WHERE id = $20 AND version = $21"
+		result, err = tx.ExecContext(ctx, q, nullID(data.TableID), nullID(data.RoomID),
+			data.Status, data.Total, data.ServiceCharge, data.Tax, data.TaxSummary,
+			data.Discount, data.TaxExempt, data.NoService, nullID(data.DiscountApprovedBy),
+			nullString(data.VoidReason), nullID(data.VoidedBy), nullID(data.VoidApprovedBy),
+			nullInt(data.VoidedAt), data.Version, data.Checksum, nullInt(data.UpdatedAt),
+			data.SyncedAt, data.ID, data.Version-1)
+	}
+	if err != nil {
+		return nil, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return nil, err
+	}
+	if affected == 0 {
+		return nil, sql.ErrNoRows
+	}
+
+	if _, err := tx.ExecContext(ctx, "DELETE FROM order_items WHERE order_id = $1", data.ID); err != nil {
+		return nil, err
+	}
+	q := "INSERT INTO order_items (id, order_id, position, product_id, variant_id, "
+	q += "name, quantity, price, price_list_id, note, void_reason, price_approved_by) "
+	q += "VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)"
+	for i, item := range data.Items {
+		if _, err := tx.ExecContext(ctx, q, item.ID, data.ID, i+1, item.ProductID,
+			nullID(item.VariantID), item.Name, item.Quantity, item.Price,
+			nullID(item.PriceListID), nullString(item.Note), nullString(item.VoidReason),
+			nullID(item.PriceApprovedBy),
+		); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// AddPayments ignore the payments kept by an earlier push
+func (repo OrderSQLRepository) AddPayments(
+	ctx context.Context,
+	orderID string,
+	payments []*model.OrderPayment,
+) (added []string, err error) {
+	tx, err := repo.Db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	q := "INSERT INTO order_payments (id, order_id, method, amount, reference, created_by, created_at, synced_at) "
+	q += "VALUES ($1, $2, $3, $4, $5, $6, $7, $8) ON CONFLICT (id) DO NOTHING"
+	syncedAt := time.Now().Unix()
+	for _, payment := range payments {
+		result, err := tx.ExecContext(ctx, q, payment.ID, orderID, payment.Method,
+			payment.Amount, nullString(payment.Reference), nullID(payment.CreatedBy),
+			payment.CreatedAt, syncedAt)
+		if err != nil {
+			return nil, err
+		}
+		affected, err := result.RowsAffected()
+		if err != nil {
+			return nil, err
+		}
+		if affected > 0 {
+			added = append(added, payment.ID)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return added, nil
+}
+
+// AddRefund raise the version of the paid order first, it lock the order
+// so two refunds of the same order are checked against the payments one
+// after the other
+func (repo OrderSQLRepository) AddRefund(ctx context.Context, refund *model.OrderRefund) (data *model.OrderRefund, err error) {
+	tx, err := repo.Db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	saved := *refund
+	data = &saved
+	data.CreatedAt = time.Now().Unix()
+	q := "UPDATE orders SET version = version + 1, synced_at = $1 WHERE id = $2 AND status = $3"
+	result, err := tx.ExecContext(ctx, q, data.CreatedAt, data.OrderID, model.OrderStatusPaid)
+	if err != nil {
+		return nil, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return nil, err
+	}
+	if affected == 0 {
+		return nil, sql.ErrNoRows
+	}
+
+	q = "SELECT (SELECT COALESCE(SUM(amount), 0) FROM order_payments WHERE order_id = $1) - "
+	q += "(SELECT COALESCE(SUM(amount), 0) FROM order_refunds WHERE order_id = $1)"
+	var refundable int64
+	if err := tx.QueryRowContext(ctx, q, data.OrderID).Scan(&refundable); err != nil {
+		return nil, err
+	}
+	if int64(data.Amount) > refundable {
+		return nil, sql.ErrNoRows
+	}
+
+	q = "INSERT INTO order_refunds (order_id, amount, reason, refunded_by, approved_by, created_at) "
+	q += "VALUES ($1, $2, $3, $4, $5, $6) RETURNING id"
+	if err := tx.QueryRowContext(ctx, q, data.OrderID, data.Amount, data.Reason,
+		nullID(data.RefundedBy), nullID(data.ApprovedBy), data.CreatedAt,
+	).Scan(&data.ID); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// nullString store empty string as NULL for optional column
+func nullString(value string) sql.NullString {
+	return sql.NullString{String: value, Valid: value != ""}
+}
+
+// nullInt store zero (e.g: unset time) as NULL
+func nullInt(value int64) sql.NullInt64 {
+	return sql.NullInt64{Int64: value, Valid: value > 0}
+}
+
+func NewOrderSQLRepository() model.IOrderRepository {
+	return &OrderSQLRepository{Db: config.DbPool}
+}
@@ -0,0 +1,296 @@
+package sql_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/aasumitro/posbe/config"
+	repoSql "github.com/aasumitro/posbe/internal/transaction/repository/sql"
+	"github.com/aasumitro/posbe/pkg/dbtest"
+	"github.com/aasumitro/posbe/pkg/dialect"
+	"github.com/aasumitro/posbe/pkg/model"
+	"github.com/aasumitro/posbe/pkg/money"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+const orderID = "6f1c2d3e-4b5a-4c6d-8e7f-901234567890"
+
+// the ids of the order saved to the migrated database
+const (
+	savedOrderID   = "6f1c2d3e-4b5a-4c6d-8e7f-901234567890"
+	savedItemID    = "0a0a0a0a-0000-4000-8000-00000000000a"
+	savedPaymentID = "0c0c0c0c-0000-4000-8000-00000000000c"
+)
+
+type orderRepositoryTestSuite struct {
+	suite.Suite
+	mock sqlmock.Sqlmock
+	repo model.IOrderRepository
+}
+
+func (suite *orderRepositoryTestSuite) SetupSuite() {
+	var err error
+	config.DbPool, suite.mock, err = sqlmock.New(
+		sqlmock.QueryMatcherOption(sqlmock.QueryMatcherRegexp))
+	require.NoError(suite.T(), err)
+	suite.repo = repoSql.NewOrderSQLRepository()
+}
+
+func (suite *orderRepositoryTestSuite) AfterTest(_, _ string) {
+	require.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+func (suite *orderRepositoryTestSuite) order(version int) *model.Order {
+	return &model.Order{
+		ID: orderID, TerminalID: 2, TableID: 3, Status: model.OrderStatusOpen,
+		Currency: "IDR", Total: 3000, Tax: 273, Version: version, Checksum: "lorem",
+		TaxSummary: &model.TaxSummary{Subtotal: 3000, Net: 2727, Tax: 273, Total: 3000},
+		CreatedBy:  1, CreatedAt: 100,
+		Items: []*model.OrderItem{{ID: "item-a", ProductID: 1, Name: "lorem", Quantity: 2, Price: 1500}},
+	}
+}
+
+func (suite *orderRepositoryTestSuite) TestRepository_Find_ExpectReturnRow() {
+	suite.mock.ExpectQuery("SELECT (.+) FROM orders WHERE id = (.+)").
+		WithArgs(orderID).
+		WillReturnRows(suite.mock.NewRows([]string{"id", "terminal_id", "table_id", "room_id",
+			"status", "currency", "total", "service_charge", "tax", "tax_summary", "discount",
+			"tax_exempt", "no_service", "discount_approved_by", "void_reason", "voided_by",
+			"void_approved_by", "voided_at", "version", "checksum", "created_by", "created_at",
+			"updated_at", "synced_at"}).
+			AddRow(orderID, 2, 3, 0, "paid", "IDR", 3000, 0, 273,
+				`{"subtotal":30.00,"net":27.27,"tax":2.73,"total":30.00}`, 500, false, true, 5,
+				"", 0, 0, 0, 1, "lorem", 1, 100, 0, 120))
+	suite.mock.ExpectQuery("SELECT (.+) FROM order_items WHERE order_id = (.+) ORDER BY position").
+		WithArgs(orderID).
+		WillReturnRows(suite.mock.NewRows([]string{"id", "product_id", "variant_id", "name",
+			"quantity", "price", "price_list_id", "note", "void_reason", "price_approved_by"}).
+			AddRow("item-a", 1, 0, "lorem", 2, 1500, 4, "", "", 0).
+			AddRow("item-b", 2, 0, "ipsum", 1, 2000, 0, "", "sold_out", 5))
+	suite.mock.ExpectQuery("SELECT (.+) FROM order_payments WHERE order_id = (.+)").
+		WithArgs(orderID).
+		WillReturnRows(suite.mock.NewRows([]string{"id", "method", "amount", "reference",
+			"created_by", "created_at"}).
+			AddRow("payment-a", "cash", 3000, "", 1, 110))
+	suite.mock.ExpectQuery("SELECT (.+) FROM order_refunds WHERE order_id = (.+)").
+		WithArgs(orderID).
+		WillReturnRows(suite.mock.NewRows([]string{"id", "order_id", "amount", "reason",
+			"refunded_by", "approved_by", "created_at"}).
+			AddRow(1, orderID, 1000, "lorem", 2, 5, 130))
+	res, err := suite.repo.Find(context.TODO(), orderID)
+	require.NoError(suite.T(), err)
+	require.Equal(suite.T(), money.Amount(3000), res.Total)
+	require.Equal(suite.T(), money.Amount(2727), res.TaxSummary.Net)
+	require.Equal(suite.T(), money.Amount(500), res.Discount)
+	require.True(suite.T(), res.NoService)
+	require.Equal(suite.T(), 5, res.DiscountApprovedBy)
+	require.Len(suite.T(), res.Items, 2)
+	require.Equal(suite.T(), 4, res.Items[0].PriceListID)
+	require.Equal(suite.T(), "sold_out", res.Items[1].VoidReason)
+	require.Equal(suite.T(), 5, res.Items[1].PriceApprovedBy)
+	require.Equal(suite.T(), "payment-a", res.Payments[0].ID)
+	require.Equal(suite.T(), 5, res.Refunds[0].ApprovedBy)
+}
+
+func (suite *orderRepositoryTestSuite) TestRepository_Find_ExpectReturnErrorNoRows() {
+	suite.mock.ExpectQuery("SELECT (.+) FROM orders WHERE id = (.+)").
+		WithArgs(orderID).WillReturnError(sql.ErrNoRows)
+	res, err := suite.repo.Find(context.TODO(), orderID)
+	require.Nil(suite.T(), res)
+	require.ErrorIs(suite.T(), err, sql.ErrNoRows)
+}
+
+func (suite *orderRepositoryTestSuite) TestRepository_Save_ExpectInsertNewOrder() {
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectExec("INSERT INTO orders (.+) ON CONFLICT \\(id\\) DO NOTHING").
+		WithArgs(orderID, sql.NullInt64{Int64: 2, Valid: true}, sql.NullInt64{Int64: 3, Valid: true},
+			sql.NullInt64{}, "open", "IDR", money.Amount(3000), money.Amount(0), money.Amount(273),
+			`{"subtotal":30.00,"net":27.27,"service_rate":0,"service_charge":0.00,"tax":2.73,`+
+				`"total":30.00,"exempt":false,"lines":null}`, money.Amount(0), false, false,
+			sql.NullInt64{}, sql.NullString{}, sql.NullInt64{}, sql.NullInt64{}, sql.NullInt64{},
+			1, "lorem", sql.NullInt64{Int64: 1, Valid: true}, int64(100), sql.NullInt64{}, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	suite.mock.ExpectExec("DELETE FROM order_items WHERE order_id = (.+)").
+		WithArgs(orderID).WillReturnResult(sqlmock.NewResult(0, 0))
+	suite.mock.ExpectExec("INSERT INTO order_items (.+)").
+		WithArgs("item-a", orderID, 1, 1, sql.NullInt64{}, "lorem", 2, money.Amount(1500),
+			sql.NullInt64{}, sql.NullString{}, sql.NullString{}, sql.NullInt64{}).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	suite.mock.ExpectCommit()
+	res, err := suite.repo.Save(context.TODO(), suite.order(1))
+	require.NoError(suite.T(), err)
+	require.NotZero(suite.T(), res.SyncedAt)
+}
+
+func (suite *orderRepositoryTestSuite) TestRepository_Save_ExpectUpdateFromPreviousVersion() {
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectExec("UPDATE orders SET (.+) WHERE id = (.+) AND version = (.+)").
+		WithArgs(sql.NullInt64{Int64: 3, Valid: true}, sql.NullInt64{}, "void",
+			money.Amount(3000), money.Amount(0), money.Amount(273), sqlmock.AnyArg(),
+			money.Amount(0), false, false, sql.NullInt64{}, sql.NullString{String: "lorem", Valid: true},
+			sql.NullInt64{Int64: 1, Valid: true}, sql.NullInt64{Int64: 5, Valid: true},
+			sql.NullInt64{Int64: 130, Valid: true}, 2, "lorem", sql.NullInt64{}, sqlmock.AnyArg(),
+			orderID, 1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	suite.mock.ExpectExec("DELETE FROM order_items WHERE order_id = (.+)").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	suite.mock.ExpectExec("INSERT INTO order_items (.+)").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	suite.mock.ExpectCommit()
+	order := suite.order(2)
+	order.Status, order.VoidReason, order.VoidedBy, order.VoidApprovedBy, order.VoidedAt =
+		model.OrderStatusVoid, "lorem", 1, 5, 130
+	res, err := suite.repo.Save(context.TODO(), order)
+	require.NoError(suite.T(), err)
+	require.Equal(suite.T(), 2, res.Version)
+}
+
+func (suite *orderRepositoryTestSuite) TestRepository_Save_ExpectReturnErrorNoRowsWhenStale() {
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectExec("UPDATE orders SET (.+)").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	suite.mock.ExpectRollback()
+	res, err := suite.repo.Save(context.TODO(), suite.order(2))
+	require.Nil(suite.T(), res)
+	require.ErrorIs(suite.T(), err, sql.ErrNoRows)
+}
+
+func (suite *orderRepositoryTestSuite) TestRepository_AddPayments_ExpectSkipKeptPayments() {
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectExec("INSERT INTO order_payments (.+) ON CONFLICT \\(id\\) DO NOTHING").
+		WithArgs("payment-a", orderID, "cash", money.Amount(3000), sql.NullString{},
+			sql.NullInt64{Int64: 1, Valid: true}, int64(110), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	suite.mock.ExpectExec("INSERT INTO order_payments (.+)").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	suite.mock.ExpectCommit()
+	added, err := suite.repo.AddPayments(context.TODO(), orderID, []*model.OrderPayment{
+		{ID: "payment-a", Method: "cash", Amount: 3000, CreatedBy: 1, CreatedAt: 110},
+		{ID: "payment-b", Method: "card", Amount: 2000, CreatedBy: 1, CreatedAt: 120},
+	})
+	require.NoError(suite.T(), err)
+	require.Equal(suite.T(), []string{"payment-b"}, added)
+}
+
+func (suite *orderRepositoryTestSuite) TestRepository_AddRefund_ExpectInsertWhenRefundable() {
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectExec("UPDATE orders SET version = version \\+ 1, (.+) WHERE id = (.+) AND status = (.+)").
+		WithArgs(sqlmock.AnyArg(), orderID, model.OrderStatusPaid).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	suite.mock.ExpectQuery("SELECT (.+) FROM order_payments (.+) FROM order_refunds").
+		WithArgs(orderID).
+		WillReturnRows(suite.mock.NewRows([]string{"refundable"}).AddRow(3000))
+	suite.mock.ExpectQuery("INSERT INTO order_refunds (.+) RETURNING id").
+		WithArgs(orderID, money.Amount(1000), "lorem", sql.NullInt64{Int64: 2, Valid: true},
+			sql.NullInt64{Int64: 5, Valid: true}, sqlmock.AnyArg()).
+		WillReturnRows(suite.mock.NewRows([]string{"id"}).AddRow(1))
+	suite.mock.ExpectCommit()
+	res, err := suite.repo.AddRefund(context.TODO(), &model.OrderRefund{OrderID: orderID,
+		Amount: 1000, Reason: "lorem", RefundedBy: 2, ApprovedBy: 5})
+	require.NoError(suite.T(), err)
+	require.Equal(suite.T(), 1, res.ID)
+	require.NotZero(suite.T(), res.CreatedAt)
+}
+
+func (suite *orderRepositoryTestSuite) TestRepository_AddRefund_ExpectReturnErrorNoRowsWhenNotPaid() {
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectExec("UPDATE orders SET version = version \\+ 1, (.+)").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	suite.mock.ExpectRollback()
+	res, err := suite.repo.AddRefund(context.TODO(), &model.OrderRefund{OrderID: orderID, Amount: 1000})
+	require.Nil(suite.T(), res)
+	require.ErrorIs(suite.T(), err, sql.ErrNoRows)
+}
+
+func (suite *orderRepositoryTestSuite) TestRepository_AddRefund_ExpectReturnErrorNoRowsWhenMoreThanPaid() {
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectExec("UPDATE orders SET version = version \\+ 1, (.+)").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	suite.mock.ExpectQuery("SELECT (.+) FROM order_payments (.+) FROM order_refunds").
+		WillReturnRows(suite.mock.NewRows([]string{"refundable"}).AddRow(500))
+	suite.mock.ExpectRollback()
+	res, err := suite.repo.AddRefund(context.TODO(), &model.OrderRefund{OrderID: orderID, Amount: 1000})
+	require.Nil(suite.T(), res)
+	require.ErrorIs(suite.T(), err, sql.ErrNoRows)
+}
+
+func (suite *orderRepositoryTestSuite) TestOrderRepository_Save() {
+	dbtest.Migrated(suite.T(), config.Dialect.Name())
+	ctx := context.TODO()
+	repo := repoSql.NewOrderSQLRepository()
+	order := &model.Order{ID: savedOrderID, TerminalID: 0, TableID: 1, Status: model.OrderStatusOpen,
+		Currency: money.DefaultCurrency, Total: 3000, Version: 1, Checksum: "first", CreatedBy: 1,
+		CreatedAt: time.Now().Unix(), Items: []*model.OrderItem{{ID: savedItemID, ProductID: 1,
+			Name: "lorem", Quantity: 2, Price: 1500, PriceListID: 3}}}
+	_, err := repo.Save(ctx, order)
+	require.NoError(suite.T(), err)
+	found, err := repo.Find(ctx, savedOrderID)
+	require.NoError(suite.T(), err)
+	require.Nil(suite.T(), found.TaxSummary)
+	// the same version is saved only once
+	_, err = repo.Save(ctx, order)
+	require.ErrorIs(suite.T(), err, sql.ErrNoRows)
+
+	order.Version, order.Checksum, order.Status = 2, "second", model.OrderStatusPaid
+	order.Discount, order.NoService, order.DiscountApprovedBy = 500, true, 1
+	order.Items[0].VoidReason, order.Items[0].PriceApprovedBy = model.SyncConflictSoldOut, 1
+	order.Total, order.ServiceCharge, order.Tax = 3450, 150, 300
+	order.TaxSummary = &model.TaxSummary{Subtotal: 3000, Net: 3000, ServiceCharge: 150, Tax: 300,
+		Total: 3450, Lines: []*model.TaxLine{{TaxClassID: 1, Name: "VAT", Base: 3000, Amount: 300}}}
+	_, err = repo.Save(ctx, order)
+	require.NoError(suite.T(), err)
+	found, err = repo.Find(ctx, savedOrderID)
+	require.NoError(suite.T(), err)
+	require.Equal(suite.T(), 2, found.Version)
+	require.Equal(suite.T(), model.OrderStatusPaid, found.Status)
+	require.Equal(suite.T(), money.Amount(300), found.Tax)
+	require.Equal(suite.T(), order.TaxSummary, found.TaxSummary)
+	require.Len(suite.T(), found.Items, 1)
+	require.Equal(suite.T(), model.SyncConflictSoldOut, found.Items[0].VoidReason)
+	require.Equal(suite.T(), 3, found.Items[0].PriceListID)
+	require.Equal(suite.T(), 1, found.Items[0].PriceApprovedBy)
+	require.Equal(suite.T(), money.Amount(500), found.Discount)
+	require.True(suite.T(), found.NoService)
+	require.Equal(suite.T(), 1, found.DiscountApprovedBy)
+	require.Empty(suite.T(), found.Payments)
+
+	payments := []*model.OrderPayment{{ID: savedPaymentID, Method: "cash",
+		Amount: 3000, CreatedBy: 2, CreatedAt: time.Now().Unix()}}
+	added, err := repo.AddPayments(ctx, savedOrderID, payments)
+	require.NoError(suite.T(), err)
+	require.Equal(suite.T(), []string{savedPaymentID}, added)
+	added, err = repo.AddPayments(ctx, savedOrderID, payments)
+	require.NoError(suite.T(), err)
+	require.Empty(suite.T(), added)
+	found, err = repo.Find(ctx, savedOrderID)
+	require.NoError(suite.T(), err)
+	require.Len(suite.T(), found.Payments, 1)
+	require.Equal(suite.T(), 2, found.Payments[0].CreatedBy)
+
+	// refunds never exceed the payments
+	refund, err := repo.AddRefund(ctx, &model.OrderRefund{OrderID: savedOrderID, Amount: 2000,
+		Reason: "lorem", RefundedBy: 2, ApprovedBy: 1})
+	require.NoError(suite.T(), err)
+	require.NotZero(suite.T(), refund.ID)
+	_, err = repo.AddRefund(ctx, &model.OrderRefund{OrderID: savedOrderID, Amount: 1001,
+		Reason: "lorem", RefundedBy: 2, ApprovedBy: 1})
+	require.ErrorIs(suite.T(), err, sql.ErrNoRows)
+	found, err = repo.Find(ctx, savedOrderID)
+	require.NoError(suite.T(), err)
+	require.Equal(suite.T(), 3, found.Version)
+	require.Len(suite.T(), found.Refunds, 1)
+	require.Equal(suite.T(), money.Amount(2000), found.Refunds[0].Amount)
+}
+
+func TestOrderRepository(t *testing.T) {
+	for _, d := range dialect.All() {
+		t.Run(d.Name(), func(t *testing.T) {
+			config.Dialect = d
+			suite.Run(t, new(orderRepositoryTestSuite))
+		})
+	}
+}
@@ -0,0 +1,86 @@
+package sql
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/aasumitro/posbe/config"
+	"github.com/aasumitro/posbe/pkg/dialect"
+	"github.com/aasumitro/posbe/pkg/model"
+)
+
+type SyncSQLRepository struct {
+	Db      *sql.DB
+	Dialect dialect.Dialect
+}
+
+// Changes read the change log kept by the triggers of the synced tables,
+// a soft deleted row is a deleted change that still carry its data
+func (repo SyncSQLRepository) Changes(ctx context.Context, since int64, limit int) (data []*model.SyncChange, err error) {
+	q := "SELECT entity_type, entity_id, version, data, "
+	q += "data IS NULL OR data ->> 'deleted_at' IS NOT NULL, changed_at "
+	q += "FROM sync_changes WHERE version > $1 ORDER BY version LIMIT $2"
+	rows, err := repo.Db.QueryContext(ctx, q, since, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer func(rows *sql.Rows) { _ = rows.Close() }(rows)
+
+	data = []*model.SyncChange{}
+	for rows.Next() {
+		var change model.SyncChange
+		var row []byte
+		if err := rows.Scan(&change.EntityType, &change.EntityID, &change.Version,
+			&row, &change.Deleted, &change.ChangedAt); err != nil {
+			return nil, err
+		}
+		if row != nil {
+			change.Data = row
+		}
+		data = append(data, &change)
+	}
+
+	return data, rows.Err()
+}
+
+func (repo SyncSQLRepository) Products(ctx context.Context, ids []int) (active map[int]bool, err error) {
+	q := "SELECT id, deleted_at IS NULL FROM products WHERE " + repo.Dialect.Any("id", "$1")
+	return repo.exists(ctx, q, ids)
+}
+
+func (repo SyncSQLRepository) Variants(ctx context.Context, ids []int) (active map[int]bool, err error) {
+	q := "SELECT v.id, v.deleted_at IS NULL AND p.deleted_at IS NULL FROM product_variants AS v "
+	q += "JOIN products AS p ON p.id = v.product_id WHERE " + repo.Dialect.Any("v.id", "$1")
+	return repo.exists(ctx, q, ids)
+}
+
+func (repo SyncSQLRepository) ShiftOpenAt(ctx context.Context) (openAt int64, err error) {
+	q := "SELECT COALESCE(MAX(open_at), 0) FROM store_shifts WHERE close_at IS NULL"
+	err = repo.Db.QueryRowContext(ctx, q).Scan(&openAt)
+	return openAt, err
+}
+
+// exists scan the id and whether it is active of every row found
+func (repo SyncSQLRepository) exists(ctx context.Context, q string, ids []int) (active map[int]bool, err error) {
+	rows, err := repo.Db.QueryContext(ctx, q, repo.Dialect.Array(ids))
+	if err != nil {
+		return nil, err
+	}
+	defer func(rows *sql.Rows) { _ = rows.Close() }(rows)
+
+	active = make(map[int]bool)
+	for rows.Next() {
+		var id int
+		var isActive bool
+		if err := rows.Scan(&id, &isActive); err != nil {
+			return nil, err
+		}
+		active[id] = isActive
+	}
+
+	return active, rows.Err()
+}
+
+func NewSyncSQLRepository() model.ISyncRepository {
+	return &SyncSQLRepository{Db: config.DbPool, Dialect: config.Dialect}
+}
@@ -0,0 +1,135 @@
+package sql_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/aasumitro/posbe/config"
+	catalogSql "github.com/aasumitro/posbe/internal/catalog/repository/sql"
+	repoSql "github.com/aasumitro/posbe/internal/transaction/repository/sql"
+	"github.com/aasumitro/posbe/pkg/dbtest"
+	"github.com/aasumitro/posbe/pkg/dialect"
+	"github.com/aasumitro/posbe/pkg/model"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type syncRepositoryTestSuite struct {
+	suite.Suite
+	mock sqlmock.Sqlmock
+	repo model.ISyncRepository
+}
+
+func (suite *syncRepositoryTestSuite) SetupSuite() {
+	var err error
+	config.DbPool, suite.mock, err = sqlmock.New(
+		sqlmock.QueryMatcherOption(sqlmock.QueryMatcherRegexp))
+	require.NoError(suite.T(), err)
+	suite.repo = repoSql.NewSyncSQLRepository()
+}
+
+func (suite *syncRepositoryTestSuite) AfterTest(_, _ string) {
+	require.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+func (suite *syncRepositoryTestSuite) TestRepository_Changes_ExpectReturnRows() {
+	suite.mock.ExpectQuery("SELECT (.+) FROM sync_changes WHERE version > (.+) ORDER BY version LIMIT (.+)").
+		WithArgs(int64(4), 2).
+		WillReturnRows(suite.mock.NewRows([]string{"entity_type", "entity_id", "version",
+			"data", "deleted", "changed_at"}).
+			AddRow("products", "1", 5, []byte(`{"id":1,"name":"lorem"}`), false, 100).
+			AddRow("tables", "3", 7, nil, true, 110))
+	res, err := suite.repo.Changes(context.TODO(), 4, 2)
+	require.NoError(suite.T(), err)
+	require.Len(suite.T(), res, 2)
+	require.JSONEq(suite.T(), `{"id":1,"name":"lorem"}`, string(res[0].Data))
+	require.Nil(suite.T(), res[1].Data)
+	require.True(suite.T(), res[1].Deleted)
+}
+
+func (suite *syncRepositoryTestSuite) TestRepository_Changes_ExpectReturnError() {
+	suite.mock.ExpectQuery("SELECT (.+) FROM sync_changes (.+)").
+		WillReturnError(errors.New("lorem"))
+	res, err := suite.repo.Changes(context.TODO(), 0, 10)
+	require.Nil(suite.T(), res)
+	require.Error(suite.T(), err)
+}
+
+func (suite *syncRepositoryTestSuite) TestRepository_Products_ExpectReturnRows() {
+	suite.mock.ExpectQuery("SELECT id, deleted_at IS NULL FROM products WHERE (.+)").
+		WithArgs(sqlmock.AnyArg()).
+		WillReturnRows(suite.mock.NewRows([]string{"id", "active"}).AddRow(1, true).AddRow(2, false))
+	res, err := suite.repo.Products(context.TODO(), []int{1, 2, 5})
+	require.NoError(suite.T(), err)
+	require.Equal(suite.T(), map[int]bool{1: true, 2: false}, res)
+}
+
+func (suite *syncRepositoryTestSuite) TestRepository_Variants_ExpectReturnError() {
+	suite.mock.ExpectQuery("SELECT v.id, (.+) FROM product_variants AS v JOIN products AS p (.+)").
+		WithArgs(sqlmock.AnyArg()).
+		WillReturnError(errors.New("UNEXPECTED"))
+	res, err := suite.repo.Variants(context.TODO(), []int{1})
+	require.Nil(suite.T(), res)
+	require.Error(suite.T(), err)
+}
+
+func (suite *syncRepositoryTestSuite) TestRepository_ShiftOpenAt_ExpectReturnOpenAt() {
+	suite.mock.ExpectQuery("SELECT COALESCE\\(MAX\\(open_at\\), 0\\) FROM store_shifts WHERE close_at IS NULL").
+		WillReturnRows(suite.mock.NewRows([]string{"open_at"}).AddRow(100))
+	res, err := suite.repo.ShiftOpenAt(context.TODO())
+	require.NoError(suite.T(), err)
+	require.Equal(suite.T(), int64(100), res)
+}
+
+func (suite *syncRepositoryTestSuite) TestSyncRepository_Changes() {
+	db := dbtest.Migrated(suite.T(), config.Dialect.Name())
+	ctx := context.TODO()
+	repo := repoSql.NewSyncSQLRepository()
+	changes, err := repo.Changes(ctx, 0, 1000)
+	require.NoError(suite.T(), err)
+	require.NotEmpty(suite.T(), changes)
+	cursor := changes[len(changes)-1].Version
+
+	units := catalogSql.NewUnitSQLRepository()
+	require.NoError(suite.T(), units.Delete(ctx, &model.Unit{ID: 5}))
+	changes, err = repo.Changes(ctx, cursor, 10)
+	require.NoError(suite.T(), err)
+	require.Len(suite.T(), changes, 1)
+	require.Equal(suite.T(), "units", changes[0].EntityType)
+	require.Equal(suite.T(), "5", changes[0].EntityID)
+	require.True(suite.T(), changes[0].Deleted)
+	require.NotEmpty(suite.T(), changes[0].Data)
+
+	_, err = db.ExecContext(ctx, "UPDATE products SET deleted_at = 1 WHERE id = 2")
+	require.NoError(suite.T(), err)
+	products, err := repo.Products(ctx, []int{1, 2, 99})
+	require.NoError(suite.T(), err)
+	require.Equal(suite.T(), map[int]bool{1: true, 2: false}, products)
+	// variant 5 is of the deleted product 2
+	variants, err := repo.Variants(ctx, []int{1, 5, 99})
+	require.NoError(suite.T(), err)
+	require.Equal(suite.T(), map[int]bool{1: true, 5: false}, variants)
+
+	openAt, err := repo.ShiftOpenAt(ctx)
+	require.NoError(suite.T(), err)
+	require.Zero(suite.T(), openAt)
+	_, err = db.ExecContext(ctx, "INSERT INTO store_shifts (shift_id, open_at, open_by, open_cash) "+
+		"VALUES (1, 100, 1, 0), (1, 200, 1, 0)")
+	require.NoError(suite.T(), err)
+	_, err = db.ExecContext(ctx, "UPDATE store_shifts SET close_at = 300 WHERE open_at = 200")
+	require.NoError(suite.T(), err)
+	openAt, err = repo.ShiftOpenAt(ctx)
+	require.NoError(suite.T(), err)
+	require.Equal(suite.T(), int64(100), openAt)
+}
+
+func TestSyncRepository(t *testing.T) {
+	for _, d := range dialect.All() {
+		t.Run(d.Name(), func(t *testing.T) {
+			config.Dialect = d
+			suite.Run(t, new(syncRepositoryTestSuite))
+		})
+	}
+}
@@ -0,0 +1,235 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/aasumitro/posbe/pkg/model"
+	"github.com/aasumitro/posbe/pkg/money"
+	"github.com/aasumitro/posbe/pkg/utils"
+)
+
+type orderService struct {
+	orderRepo  model.IOrderRepository
+	taxService model.ICatalogTaxService
+}
+
+func (service orderService) OrderDetail(
+	ctx context.Context,
+	id string,
+) (data *model.Order, errData *utils.ServiceError) {
+	data, err := service.orderRepo.Find(ctx, id)
+	return utils.ValidateDataRow[model.Order](data, err)
+}
+
+// VoidOrder void an open order, a paid order is refunded instead
+func (service orderService) VoidOrder(
+	ctx context.Context,
+	id string,
+	form *model.OrderVoidForm,
+) (data *model.Order, errData *utils.ServiceError) {
+	order, errData := service.openOrder(ctx, id)
+	if errData != nil {
+		return nil, errData
+	}
+	order.Status, order.VoidReason = model.OrderStatusVoid, form.Reason
+	order.VoidedBy, order.VoidApprovedBy = form.VoidedBy, form.ApprovedBy
+	order.VoidedAt = time.Now().Unix()
+	return service.save(ctx, order)
+}
+
+// RefundOrder give back a part (or all) of what was paid on a paid order
+func (service orderService) RefundOrder(
+	ctx context.Context,
+	id string,
+	form *model.OrderRefundForm,
+) (data *model.OrderRefund, errData *utils.ServiceError) {
+	order, err := service.orderRepo.Find(ctx, id)
+	if err != nil {
+		return utils.ValidateDataRow[model.OrderRefund](nil, err)
+	}
+	if order.Status != model.OrderStatusPaid {
+		return nil, &utils.ServiceError{
+			Code:    http.StatusUnprocessableEntity,
+			Message: fmt.Sprintf("order is %s, only a paid order can be refunded", order.Status),
+		}
+	}
+	var refundable money.Amount
+	for _, payment := range order.Payments {
+		refundable += payment.Amount
+	}
+	for _, refund := range order.Refunds {
+		refundable -= refund.Amount
+	}
+	if form.Amount > refundable {
+		return nil, &utils.ServiceError{
+			Code:    http.StatusUnprocessableEntity,
+			Message: fmt.Sprintf("refund %s is more than the %s left to refund", form.Amount, refundable),
+		}
+	}
+
+	data, err = service.orderRepo.AddRefund(ctx, &model.OrderRefund{
+		OrderID: id, Amount: form.Amount, Reason: form.Reason,
+		RefundedBy: form.RefundedBy, ApprovedBy: form.ApprovedBy,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, orderChanged()
+	}
+	return utils.ValidateDataRow[model.OrderRefund](data, err)
+}
+
+// OverrideItemPrice set the price of an item by hand, the next pushes of
+// the order keep it instead of pricing the item with the price lists
+func (service orderService) OverrideItemPrice(
+	ctx context.Context,
+	id, itemID string,
+	form *model.OrderPriceForm,
+) (data *model.Order, errData *utils.ServiceError) {
+	order, errData := service.openOrder(ctx, id)
+	if errData != nil {
+		return nil, errData
+	}
+	var item *model.OrderItem
+	for _, orderItem := range order.Items {
+		if orderItem.ID == itemID {
+			item = orderItem
+		}
+	}
+	if item == nil {
+		return nil, &utils.ServiceError{
+			Code:    http.StatusNotFound,
+			Message: fmt.Sprintf("item %s not found", itemID),
+		}
+	}
+	if item.VoidReason != "" {
+		return nil, &utils.ServiceError{
+			Code:    http.StatusUnprocessableEntity,
+			Message: fmt.Sprintf("item %s is voided", itemID),
+		}
+	}
+	item.Price, item.PriceListID, item.PriceApprovedBy = form.Price, 0, form.ApprovedBy
+	return service.save(ctx, order)
+}
+
+// DiscountOrder set the discount, tax exempt and no service of an open order
+func (service orderService) DiscountOrder(
+	ctx context.Context,
+	id string,
+	form *model.OrderDiscountForm,
+) (data *model.Order, errData *utils.ServiceError) {
+	order, errData := service.openOrder(ctx, id)
+	if errData != nil {
+		return nil, errData
+	}
+	if subtotal := orderSubtotal(order); form.Discount > subtotal {
+		return nil, &utils.ServiceError{
+			Code:    http.StatusUnprocessableEntity,
+			Message: fmt.Sprintf("discount %s is more than the order %s", form.Discount, subtotal),
+		}
+	}
+	order.Discount, order.TaxExempt, order.NoService = form.Discount, form.TaxExempt, form.NoService
+	order.DiscountApprovedBy = form.ApprovedBy
+	return service.save(ctx, order)
+}
+
+// openOrder find the order to change, it must still be open
+func (service orderService) openOrder(ctx context.Context, id string) (*model.Order, *utils.ServiceError) {
+	order, err := service.orderRepo.Find(ctx, id)
+	if err != nil {
+		return utils.ValidateDataRow[model.Order](nil, err)
+	}
+	if order.Status != model.OrderStatusOpen {
+		return nil, &utils.ServiceError{
+			Code:    http.StatusUnprocessableEntity,
+			Message: fmt.Sprintf("order is already %s", order.Status),
+		}
+	}
+	return order, nil
+}
+
+// save calculate the total again and save the order at the next version,
+// the checksum is cleared so a push from the previous version is stale
+func (service orderService) save(ctx context.Context, order *model.Order) (*model.Order, *utils.ServiceError) {
+	if err := calculateOrder(ctx, service.taxService, order); err != nil {
+		return utils.ValidateDataRow[model.Order](nil, err)
+	}
+	order.Version, order.Checksum = order.Version+1, ""
+	if _, err := service.orderRepo.Save(ctx, order); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, orderChanged()
+		}
+		return utils.ValidateDataRow[model.Order](nil, err)
+	}
+	data, err := service.orderRepo.Find(ctx, order.ID)
+	return utils.ValidateDataRow[model.Order](data, err)
+}
+
+// orderChanged is the error of a change that lost the race
+// with another change (or push) of the same order
+func orderChanged() *utils.ServiceError {
+	return &utils.ServiceError{
+		Code:    http.StatusConflict,
+		Message: "order was changed in the meantime, try again",
+	}
+}
+
+// orderSubtotal is the amount of the items that are not voided
+func orderSubtotal(order *model.Order) (subtotal money.Amount) {
+	for _, item := range order.Items {
+		if item.VoidReason == "" {
+			subtotal += item.Price.Mul(item.Quantity)
+		}
+	}
+	return subtotal
+}
+
+// calculateOrder set the total of the order from the items that are not
+// voided, the discount is split over the items by their amount and taken
+// off before the tax, tax exempt and no service are passed on to the tax
+func calculateOrder(ctx context.Context, taxService model.ICatalogTaxService, order *model.Order) error {
+	order.Total, order.ServiceCharge, order.Tax, order.TaxSummary = 0, 0, 0, nil
+	taxQuery := &model.TaxQuery{NoService: order.NoService, Exempt: order.TaxExempt}
+	weights := make([]int64, 0, len(order.Items))
+	for _, item := range order.Items {
+		if item.VoidReason != "" {
+			continue
+		}
+		amount := item.Price.Mul(item.Quantity)
+		taxQuery.Items = append(taxQuery.Items, &model.TaxQueryItem{ProductID: item.ProductID, Amount: amount})
+		weights = append(weights, int64(amount))
+	}
+	if len(taxQuery.Items) == 0 {
+		return nil
+	}
+	// the items may have been voided since the discount was given
+	if discount := min(order.Discount, orderSubtotal(order)); discount > 0 {
+		shares, err := discount.Allocate(weights...)
+		if err != nil {
+			return err
+		}
+		for i, item := range taxQuery.Items {
+			item.Amount -= shares[i]
+		}
+	}
+	summary, errData := taxService.CalculateTax(ctx, taxQuery)
+	if errData != nil {
+		return fmt.Errorf("unable to calculate tax of order %s: %v", order.ID, errData.Message)
+	}
+	order.Total, order.ServiceCharge, order.Tax, order.TaxSummary =
+		summary.Total, summary.ServiceCharge, summary.Tax, summary
+	return nil
+}
+
+func NewOrderService(
+	orderRepo model.IOrderRepository,
+	taxService model.ICatalogTaxService,
+) model.IOrderService {
+	return &orderService{
+		orderRepo:  orderRepo,
+		taxService: taxService,
+	}
+}
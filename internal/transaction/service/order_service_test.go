@@ -0,0 +1,204 @@
+package service_test
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"testing"
+
+	catalogService "github.com/aasumitro/posbe/internal/catalog/service"
+	"github.com/aasumitro/posbe/internal/transaction/service"
+	"github.com/aasumitro/posbe/mocks"
+	"github.com/aasumitro/posbe/pkg/model"
+	"github.com/aasumitro/posbe/pkg/money"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type orderTestSuite struct {
+	suite.Suite
+	orderRepo *mocks.IOrderRepository
+	prefRepo  *mocks.IStorePrefRepository
+	taxRepo   *mocks.ITaxClassRepository
+	svc       model.IOrderService
+	saved     *model.Order
+}
+
+func (suite *orderTestSuite) SetupTest() {
+	suite.orderRepo = new(mocks.IOrderRepository)
+	suite.prefRepo = new(mocks.IStorePrefRepository)
+	suite.taxRepo = new(mocks.ITaxClassRepository)
+	suite.svc = service.NewOrderService(suite.orderRepo,
+		catalogService.NewCatalogTaxService(suite.taxRepo, suite.prefRepo))
+	suite.saved = nil
+	// every product is in an inclusive class, so the total is the subtotal
+	suite.taxRepo.On("ProductClasses", mock.Anything, mock.Anything).
+		Return(func(_ context.Context, productIDs []int) map[int]int {
+			classes := make(map[int]int)
+			for _, productID := range productIDs {
+				classes[productID] = 1
+			}
+			return classes
+		}, nil)
+	suite.taxRepo.On("All", mock.Anything).Return([]*model.TaxClass{
+		{ID: 1, Name: "VAT", Rate: 100000, Inclusive: true, IsDefault: true}}, nil)
+	suite.prefRepo.On("Find", mock.Anything, "service_rate").
+		Return(&model.StoreSetting{"service_rate": ""}, nil)
+}
+
+// an open order of two items of 3000 each
+func (suite *orderTestSuite) order(status string) *model.Order {
+	order := &model.Order{
+		ID: syncOrderID, Status: status, Version: 2, Checksum: "lorem", Total: 6000,
+		Items: []*model.OrderItem{
+			{ID: syncItemA, ProductID: 1, Name: "lorem", Quantity: 2, Price: 1500},
+			{ID: syncItemB, ProductID: 2, Name: "ipsum", Quantity: 2, Price: 1500, PriceListID: 4},
+		},
+	}
+	if status == model.OrderStatusPaid {
+		order.Payments = []*model.OrderPayment{{ID: syncPayment, Method: "cash", Amount: 6000}}
+	}
+	return order
+}
+
+// find the order once, keep the saved order so the following Find return it
+func (suite *orderTestSuite) onFind(order *model.Order) {
+	suite.orderRepo.On("Find", mock.Anything, syncOrderID).Once().Return(order, nil)
+	suite.orderRepo.On("Save", mock.Anything, mock.Anything).Once().
+		Return(func(_ context.Context, order *model.Order) *model.Order {
+			suite.saved = order
+			return order
+		}, nil)
+	suite.orderRepo.On("Find", mock.Anything, syncOrderID).
+		Return(func(context.Context, string) *model.Order { return suite.saved }, nil)
+}
+
+func (suite *orderTestSuite) TestOrderService_OrderDetail_ShouldReturnNotFound() {
+	suite.orderRepo.On("Find", mock.Anything, syncOrderID).Once().Return(nil, sql.ErrNoRows)
+	data, err := suite.svc.OrderDetail(context.TODO(), syncOrderID)
+	require.Nil(suite.T(), data)
+	require.Equal(suite.T(), http.StatusNotFound, err.Code)
+}
+
+func (suite *orderTestSuite) TestOrderService_VoidOrder_ShouldVoidOpenOrder() {
+	suite.onFind(suite.order(model.OrderStatusOpen))
+	data, err := suite.svc.VoidOrder(context.TODO(), syncOrderID,
+		&model.OrderVoidForm{Reason: "lorem", VoidedBy: 2, ApprovedBy: 1})
+	require.Nil(suite.T(), err)
+	require.Equal(suite.T(), model.OrderStatusVoid, data.Status)
+	require.Equal(suite.T(), "lorem", data.VoidReason)
+	require.Equal(suite.T(), 2, data.VoidedBy)
+	require.Equal(suite.T(), 1, data.VoidApprovedBy)
+	require.NotZero(suite.T(), data.VoidedAt)
+	// the push of the previous version is stale
+	require.Equal(suite.T(), 3, data.Version)
+	require.Empty(suite.T(), data.Checksum)
+}
+
+func (suite *orderTestSuite) TestOrderService_VoidOrder_ShouldRejectPaidOrder() {
+	suite.orderRepo.On("Find", mock.Anything, syncOrderID).Once().
+		Return(suite.order(model.OrderStatusPaid), nil)
+	data, err := suite.svc.VoidOrder(context.TODO(), syncOrderID, &model.OrderVoidForm{Reason: "lorem"})
+	require.Nil(suite.T(), data)
+	require.Equal(suite.T(), http.StatusUnprocessableEntity, err.Code)
+	suite.orderRepo.AssertNotCalled(suite.T(), "Save", mock.Anything, mock.Anything)
+}
+
+func (suite *orderTestSuite) TestOrderService_VoidOrder_ShouldReturnConflictWhenChanged() {
+	suite.orderRepo.On("Find", mock.Anything, syncOrderID).Once().
+		Return(suite.order(model.OrderStatusOpen), nil)
+	suite.orderRepo.On("Save", mock.Anything, mock.Anything).Once().Return(nil, sql.ErrNoRows)
+	data, err := suite.svc.VoidOrder(context.TODO(), syncOrderID, &model.OrderVoidForm{Reason: "lorem"})
+	require.Nil(suite.T(), data)
+	require.Equal(suite.T(), http.StatusConflict, err.Code)
+}
+
+func (suite *orderTestSuite) TestOrderService_RefundOrder_ShouldAddRefund() {
+	order := suite.order(model.OrderStatusPaid)
+	order.Refunds = []*model.OrderRefund{{ID: 1, Amount: 1000}}
+	suite.orderRepo.On("Find", mock.Anything, syncOrderID).Once().Return(order, nil)
+	suite.orderRepo.On("AddRefund", mock.Anything, &model.OrderRefund{OrderID: syncOrderID,
+		Amount: 5000, Reason: "lorem", RefundedBy: 2, ApprovedBy: 1}).Once().
+		Return(&model.OrderRefund{ID: 2, Amount: 5000}, nil)
+	data, err := suite.svc.RefundOrder(context.TODO(), syncOrderID,
+		&model.OrderRefundForm{Amount: 5000, Reason: "lorem", RefundedBy: 2, ApprovedBy: 1})
+	require.Nil(suite.T(), err)
+	require.Equal(suite.T(), 2, data.ID)
+}
+
+func (suite *orderTestSuite) TestOrderService_RefundOrder_ShouldRejectMoreThanPaid() {
+	order := suite.order(model.OrderStatusPaid)
+	order.Refunds = []*model.OrderRefund{{ID: 1, Amount: 1000}}
+	suite.orderRepo.On("Find", mock.Anything, syncOrderID).Once().Return(order, nil)
+	data, err := suite.svc.RefundOrder(context.TODO(), syncOrderID,
+		&model.OrderRefundForm{Amount: 5001, Reason: "lorem"})
+	require.Nil(suite.T(), data)
+	require.Equal(suite.T(), http.StatusUnprocessableEntity, err.Code)
+	suite.orderRepo.AssertNotCalled(suite.T(), "AddRefund", mock.Anything, mock.Anything)
+}
+
+func (suite *orderTestSuite) TestOrderService_RefundOrder_ShouldRejectOpenOrder() {
+	suite.orderRepo.On("Find", mock.Anything, syncOrderID).Once().
+		Return(suite.order(model.OrderStatusOpen), nil)
+	data, err := suite.svc.RefundOrder(context.TODO(), syncOrderID,
+		&model.OrderRefundForm{Amount: 1000, Reason: "lorem"})
+	require.Nil(suite.T(), data)
+	require.Equal(suite.T(), http.StatusUnprocessableEntity, err.Code)
+}
+
+func (suite *orderTestSuite) TestOrderService_RefundOrder_ShouldReturnConflictWhenRefundedMeanwhile() {
+	suite.orderRepo.On("Find", mock.Anything, syncOrderID).Once().
+		Return(suite.order(model.OrderStatusPaid), nil)
+	suite.orderRepo.On("AddRefund", mock.Anything, mock.Anything).Once().Return(nil, sql.ErrNoRows)
+	data, err := suite.svc.RefundOrder(context.TODO(), syncOrderID,
+		&model.OrderRefundForm{Amount: 6000, Reason: "lorem"})
+	require.Nil(suite.T(), data)
+	require.Equal(suite.T(), http.StatusConflict, err.Code)
+}
+
+func (suite *orderTestSuite) TestOrderService_OverrideItemPrice_ShouldSetPrice() {
+	suite.onFind(suite.order(model.OrderStatusOpen))
+	data, err := suite.svc.OverrideItemPrice(context.TODO(), syncOrderID, syncItemB,
+		&model.OrderPriceForm{Price: 1000, ApprovedBy: 1})
+	require.Nil(suite.T(), err)
+	item := data.Items[1]
+	require.Equal(suite.T(), money.Amount(1000), item.Price)
+	require.Zero(suite.T(), item.PriceListID)
+	require.Equal(suite.T(), 1, item.PriceApprovedBy)
+	require.Equal(suite.T(), money.Amount(5000), data.Total)
+}
+
+func (suite *orderTestSuite) TestOrderService_OverrideItemPrice_ShouldReturnNotFound() {
+	suite.orderRepo.On("Find", mock.Anything, syncOrderID).Once().
+		Return(suite.order(model.OrderStatusOpen), nil)
+	data, err := suite.svc.OverrideItemPrice(context.TODO(), syncOrderID, "lorem",
+		&model.OrderPriceForm{Price: 1000, ApprovedBy: 1})
+	require.Nil(suite.T(), data)
+	require.Equal(suite.T(), http.StatusNotFound, err.Code)
+}
+
+func (suite *orderTestSuite) TestOrderService_DiscountOrder_ShouldTakeDiscountOffBeforeTax() {
+	suite.onFind(suite.order(model.OrderStatusOpen))
+	data, err := suite.svc.DiscountOrder(context.TODO(), syncOrderID,
+		&model.OrderDiscountForm{Discount: 1000, NoService: true, ApprovedBy: 1})
+	require.Nil(suite.T(), err)
+	require.Equal(suite.T(), money.Amount(1000), data.Discount)
+	require.True(suite.T(), data.NoService)
+	require.Equal(suite.T(), 1, data.DiscountApprovedBy)
+	require.Equal(suite.T(), money.Amount(5000), data.Total)
+	require.Equal(suite.T(), money.Amount(5000), data.TaxSummary.Subtotal)
+}
+
+func (suite *orderTestSuite) TestOrderService_DiscountOrder_ShouldRejectMoreThanSubtotal() {
+	suite.orderRepo.On("Find", mock.Anything, syncOrderID).Once().
+		Return(suite.order(model.OrderStatusOpen), nil)
+	data, err := suite.svc.DiscountOrder(context.TODO(), syncOrderID,
+		&model.OrderDiscountForm{Discount: 6001, ApprovedBy: 1})
+	require.Nil(suite.T(), data)
+	require.Equal(suite.T(), http.StatusUnprocessableEntity, err.Code)
+}
+
+func TestOrderService(t *testing.T) {
+	suite.Run(t, new(orderTestSuite))
+}
@@ -0,0 +1,470 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/aasumitro/posbe/common"
+	"github.com/aasumitro/posbe/pkg/model"
+	"github.com/aasumitro/posbe/pkg/money"
+	"github.com/aasumitro/posbe/pkg/utils"
+)
+
+type syncService struct {
+	syncRepo         model.ISyncRepository
+	orderRepo        model.IOrderRepository
+	availabilityRepo model.IAvailabilityRepository
+	priceService     model.ICatalogPriceService
+	taxService       model.ICatalogTaxService
+}
+
+// soldOutKey is an 86'd product (variant 0) or variant
+type soldOutKey struct {
+	productID, variantID int
+}
+
+// pushIndex is what the conflicts of a push are checked against,
+// products and variants hold the ones that exist, true when not deleted,
+// the orders are taken between shiftOpenAt (0 when no shift is open) and
+// receivedAt, the server clock when the push is received
+type pushIndex struct {
+	products    map[int]bool
+	variants    map[int]bool
+	soldOut     map[soldOutKey]int64
+	shiftOpenAt int64
+	receivedAt  int64
+}
+
+func (service syncService) Pull(
+	ctx context.Context,
+	query *model.SyncPullQuery,
+) (data *model.SyncPull, errData *utils.ServiceError) {
+	limit := query.Limit
+	if limit == 0 {
+		limit = common.SyncDefaultLimit
+	}
+	// one more change tell whether there is a next page
+	changes, err := service.syncRepo.Changes(ctx, query.Since, limit+1)
+	if err != nil {
+		_, errData := utils.ValidateDataRows[model.SyncChange](nil, err)
+		return nil, errData
+	}
+
+	data = &model.SyncPull{Cursor: query.Since, Changes: changes}
+	if len(changes) > limit {
+		data.More, data.Changes = true, changes[:limit]
+	}
+	if len(data.Changes) > 0 {
+		data.Cursor = data.Changes[len(data.Changes)-1].Version
+	}
+	return data, nil
+}
+
+// Push apply the orders one by one, an order already applied is left
+// as is so a push that failed half way can be sent again
+func (service syncService) Push(
+	ctx context.Context,
+	form *model.SyncPushForm,
+) (data *model.SyncPushResult, errData *utils.ServiceError) {
+	index, err := service.index(ctx, form)
+	if err != nil {
+		_, errData := utils.ValidateDataRow[model.SyncPushResult](nil, err)
+		return nil, errData
+	}
+
+	data = &model.SyncPushResult{Orders: make([]*model.SyncOrderResult, 0, len(form.Orders))}
+	for _, pushed := range form.Orders {
+		result, err := service.pushOrder(ctx, form, pushed, index)
+		if err != nil {
+			_, errData := utils.ValidateDataRow[model.SyncOrderResult](nil, err)
+			return nil, errData
+		}
+		data.Orders = append(data.Orders, result)
+	}
+	return data, nil
+}
+
+func (service syncService) index(ctx context.Context, form *model.SyncPushForm) (*pushIndex, error) {
+	var productIDs, variantIDs []int
+	for _, order := range form.Orders {
+		for _, item := range order.Items {
+			productIDs = append(productIDs, item.ProductID)
+			if item.VariantID > 0 {
+				variantIDs = append(variantIDs, item.VariantID)
+			}
+		}
+	}
+	index := &pushIndex{products: map[int]bool{}, variants: map[int]bool{}, soldOut: map[soldOutKey]int64{},
+		receivedAt: time.Now().Unix()}
+	var err error
+	if index.shiftOpenAt, err = service.syncRepo.ShiftOpenAt(ctx); err != nil {
+		return nil, err
+	}
+	if len(productIDs) == 0 {
+		return index, nil
+	}
+	if index.products, err = service.syncRepo.Products(ctx, productIDs); err != nil {
+		return nil, err
+	}
+	if len(variantIDs) > 0 {
+		if index.variants, err = service.syncRepo.Variants(ctx, variantIDs); err != nil {
+			return nil, err
+		}
+	}
+	items, err := service.availabilityRepo.SoldOut(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range items {
+		index.soldOut[soldOutKey{item.ProductID, item.VariantID}] = item.CreatedAt
+	}
+	return index, nil
+}
+
+// pushOrder resolve the pushed order against the server copy:
+// the same order pushed again change nothing, an order paid or void on
+// the server or changed since the version pushed from keep the server
+// copy, otherwise the pushed order replace it. Payments are money already
+// taken so they are kept whatever happened to the order
+func (service syncService) pushOrder(
+	ctx context.Context,
+	form *model.SyncPushForm,
+	pushed *model.SyncOrderForm,
+	index *pushIndex,
+) (result *model.SyncOrderResult, err error) {
+	id := pushed.ID
+	result = &model.SyncOrderResult{ID: id, Conflicts: []*model.SyncConflict{}}
+	checksum, err := orderChecksum(pushed)
+	if err != nil {
+		return nil, err
+	}
+	current, err := service.orderRepo.Find(ctx, id)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, err
+	}
+
+	stale := false
+	switch {
+	case current != nil && current.Checksum == checksum:
+		result.Status = model.SyncOrderUnchanged
+	case current != nil && current.Status != model.OrderStatusOpen:
+		result.Status = model.SyncOrderRejected
+		result.Conflicts = append(result.Conflicts, &model.SyncConflict{
+			Rule: model.SyncConflictOrderClosed, Resolution: model.SyncResolutionServerWins,
+			Message: fmt.Sprintf("order is already %s", current.Status),
+		})
+	case current != nil && current.Version != pushed.Version:
+		result.Status, stale = model.SyncOrderRejected, true
+	default:
+		order, err := service.resolve(ctx, form, pushed, current, index, result)
+		if err != nil {
+			return nil, err
+		}
+		order.ID, order.Checksum = id, checksum
+		_, err = service.orderRepo.Save(ctx, order)
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			// saved by another push in between
+			result.Status, result.Conflicts, stale = model.SyncOrderRejected, []*model.SyncConflict{}, true
+		case err != nil:
+			return nil, err
+		default:
+			result.Status = model.SyncOrderApplied
+		}
+	}
+
+	if len(pushed.Payments) > 0 {
+		added, err := service.pushPayments(ctx, form, id, pushed.Payments, result)
+		if err != nil {
+			return nil, err
+		}
+		if added && result.Status == model.SyncOrderUnchanged {
+			result.Status = model.SyncOrderApplied
+		}
+	}
+
+	if result.Order, err = service.orderRepo.Find(ctx, id); err != nil {
+		return nil, err
+	}
+	result.Version = result.Order.Version
+	if stale {
+		result.Conflicts = append([]*model.SyncConflict{{
+			Rule: model.SyncConflictStaleVersion, Resolution: model.SyncResolutionServerWins,
+			Message: fmt.Sprintf("order was pushed from version %d, the server has version %d",
+				pushed.Version, result.Version),
+		}}, result.Conflicts...)
+	}
+	return result, nil
+}
+
+func (service syncService) pushPayments(
+	ctx context.Context,
+	form *model.SyncPushForm,
+	orderID string,
+	pushed []*model.OrderPayment,
+	result *model.SyncOrderResult,
+) (added bool, err error) {
+	if !form.CanTender {
+		for _, payment := range pushed {
+			result.Conflicts = append(result.Conflicts, &model.SyncConflict{
+				Rule: model.SyncConflictNotPermitted, Resolution: model.SyncResolutionRejected,
+				PaymentID: payment.ID,
+				Message:   "user is not permitted to take payments",
+			})
+		}
+		return false, nil
+	}
+	payments := make([]*model.OrderPayment, 0, len(pushed))
+	for _, payment := range pushed {
+		kept := *payment
+		kept.CreatedBy = form.CreatedBy
+		payments = append(payments, &kept)
+	}
+	ids, err := service.orderRepo.AddPayments(ctx, orderID, payments)
+	return len(ids) > 0, err
+}
+
+// resolve build and price the order to save. A push take the order as far
+// as paid: a void push is kept open (it is voided with POST /orders/{id}/void)
+// and so is a paid push of a user who can not take payments or whose payments
+// do not cover the total, tax exempt and no service are kept as on the server
+// (set with PUT /orders/{id}/discount)
+func (service syncService) resolve(
+	ctx context.Context,
+	form *model.SyncPushForm,
+	pushed *model.SyncOrderForm,
+	current *model.Order,
+	index *pushIndex,
+	result *model.SyncOrderResult,
+) (*model.Order, error) {
+	status := pushed.Status
+	switch {
+	case status == model.OrderStatusVoid:
+		status = model.OrderStatusOpen
+		result.Conflicts = append(result.Conflicts, &model.SyncConflict{
+			Rule: model.SyncConflictNotPermitted, Resolution: model.SyncResolutionRejected,
+			Message: "order is kept open, void it with POST /orders/{id}/void",
+		})
+	case status == model.OrderStatusPaid && !form.CanTender:
+		status = model.OrderStatusOpen
+		result.Conflicts = append(result.Conflicts, &model.SyncConflict{
+			Rule: model.SyncConflictNotPermitted, Resolution: model.SyncResolutionRejected,
+			Message: "order is kept open, user is not permitted to take payments",
+		})
+	}
+	var taxExempt, noService bool
+	if current != nil {
+		taxExempt, noService = current.TaxExempt, current.NoService
+	}
+	if pushed.TaxExempt != taxExempt || pushed.NoService != noService {
+		result.Conflicts = append(result.Conflicts, &model.SyncConflict{
+			Rule: model.SyncConflictNotPermitted, Resolution: model.SyncResolutionServerWins,
+			Message: "tax exempt and no service are set with PUT /orders/{id}/discount",
+		})
+	}
+
+	order, conflicts := resolveOrder(form, pushed, status, current, index)
+	if err := service.priceOrder(ctx, pushed, order); err != nil {
+		return nil, err
+	}
+	if paid := paidAmount(current, pushed); status == model.OrderStatusPaid && paid < order.Total {
+		// resolved again as open, so the 86'd items are voided
+		result.Conflicts = append(result.Conflicts, &model.SyncConflict{
+			Rule: model.SyncConflictUnpaid, Resolution: model.SyncResolutionRejected,
+			Message: fmt.Sprintf("order is kept open, the payments %s do not cover the total %s",
+				paid, order.Total),
+		})
+		order, conflicts = resolveOrder(form, pushed, model.OrderStatusOpen, current, index)
+		if err := service.priceOrder(ctx, pushed, order); err != nil {
+			return nil, err
+		}
+	}
+	result.Conflicts = append(result.Conflicts, conflicts...)
+	return order, nil
+}
+
+// resolveOrder build the order to save with the status, an item of a deleted
+// or 86'd (before the order was taken) product is voided while the order is
+// open and kept once it is paid, an item of a product or variant that never
+// existed is always voided, a voided item stay voided. The price override of
+// an item, the approved discount, tax exempt and no service are kept
+func resolveOrder(
+	form *model.SyncPushForm,
+	pushed *model.SyncOrderForm,
+	status string,
+	current *model.Order,
+	index *pushIndex,
+) (order *model.Order, conflicts []*model.SyncConflict) {
+	order = &model.Order{
+		TerminalID: form.TerminalID, TableID: pushed.TableID, RoomID: pushed.RoomID,
+		Status: status, Currency: money.StoreCurrency().Code, Version: 1,
+		Items: make([]*model.OrderItem, 0, len(pushed.Items)), CreatedBy: form.CreatedBy,
+		CreatedAt: index.takenAt(pushed, current), UpdatedAt: pushed.UpdatedAt,
+	}
+	voided := make(map[string]string)
+	overridden := make(map[string]*model.OrderItem)
+	if current != nil {
+		order.Version = current.Version + 1
+		order.Discount, order.TaxExempt, order.NoService, order.DiscountApprovedBy =
+			current.Discount, current.TaxExempt, current.NoService, current.DiscountApprovedBy
+		for _, item := range current.Items {
+			if item.VoidReason != "" {
+				voided[item.ID] = item.VoidReason
+			}
+			if item.PriceApprovedBy > 0 {
+				overridden[item.ID] = item
+			}
+		}
+	}
+
+	for _, pushedItem := range pushed.Items {
+		item := *pushedItem
+		item.VoidReason, item.PriceApprovedBy = voided[item.ID], 0
+		if kept, ok := overridden[item.ID]; ok {
+			item.Price, item.PriceApprovedBy = kept.Price, kept.PriceApprovedBy
+		}
+		if item.VoidReason == "" {
+			if rule, message, missing := index.unavailable(&item, order.CreatedAt); rule != "" {
+				conflict := &model.SyncConflict{
+					Rule: rule, Resolution: model.SyncResolutionKept,
+					ItemID: item.ID, Message: message,
+				}
+				if missing || order.Status == model.OrderStatusOpen {
+					conflict.Resolution, item.VoidReason = model.SyncResolutionVoided, rule
+				}
+				conflicts = append(conflicts, conflict)
+			}
+		}
+		order.Items = append(order.Items, &item)
+	}
+	return order, conflicts
+}
+
+// paidAmount is what is paid on the order once the payments
+// of the push are added, a payment is only counted once
+func paidAmount(current *model.Order, pushed *model.SyncOrderForm) (paid money.Amount) {
+	kept := make(map[string]bool)
+	if current != nil {
+		for _, payment := range current.Payments {
+			paid += payment.Amount
+			kept[payment.ID] = true
+		}
+	}
+	for _, payment := range pushed.Payments {
+		if !kept[payment.ID] {
+			paid += payment.Amount
+		}
+	}
+	return paid
+}
+
+// priceOrder price the items that are not voided with the price lists in
+// effect when the order was taken, the price pushed by the terminal is only
+// the one it showed, voided items keep it and are left out of the total,
+// so are the items priced by hand, the total is then calculated again
+func (service syncService) priceOrder(ctx context.Context, pushed *model.SyncOrderForm, order *model.Order) error {
+	query := &model.PriceQuery{Channel: pushed.Channel, CustomerTier: pushed.CustomerTier, At: order.CreatedAt}
+	priced := make([]*model.OrderItem, 0, len(order.Items))
+	for _, item := range order.Items {
+		item.PriceListID = 0
+		if item.VoidReason != "" || item.PriceApprovedBy > 0 {
+			continue
+		}
+		queryItem := &model.PriceQueryItem{ItemType: model.PriceItemProduct, ItemID: item.ProductID}
+		if item.VariantID > 0 {
+			queryItem = &model.PriceQueryItem{ItemType: model.PriceItemVariant, ItemID: item.VariantID}
+		}
+		query.Items = append(query.Items, queryItem)
+		priced = append(priced, item)
+	}
+	if len(priced) > 0 {
+		snapshots, errData := service.priceService.ResolvePrices(ctx, query)
+		if errData != nil {
+			return fmt.Errorf("unable to price order %s: %v", order.ID, errData.Message)
+		}
+		for i, item := range priced {
+			item.Price, item.PriceListID = snapshots[i].Price, snapshots[i].PriceListID
+		}
+	}
+	return calculateOrder(ctx, service.taxService, order)
+}
+
+// takenAt is when the order was taken as far as the server trust the clock
+// of the terminal, the created_at of the first push clamped between the open
+// of the store shift and the time the push is received
+func (index *pushIndex) takenAt(pushed *model.SyncOrderForm, current *model.Order) int64 {
+	if current != nil {
+		return current.CreatedAt
+	}
+	return min(max(pushed.CreatedAt, index.shiftOpenAt), index.receivedAt)
+}
+
+// unavailable return the conflict rule of the item, empty when it could be
+// sold, missing is set when the product or variant never existed
+func (index *pushIndex) unavailable(item *model.OrderItem, takenAt int64) (rule, message string, missing bool) {
+	if active, ok := index.products[item.ProductID]; !active {
+		return model.SyncConflictNotFound, fmt.Sprintf("product %d not found", item.ProductID), !ok
+	}
+	if item.VariantID > 0 {
+		if active, ok := index.variants[item.VariantID]; !active {
+			return model.SyncConflictNotFound, fmt.Sprintf("variant %d not found", item.VariantID), !ok
+		}
+	}
+	keys := []soldOutKey{{productID: item.ProductID}}
+	if item.VariantID > 0 {
+		keys = append(keys, soldOutKey{item.ProductID, item.VariantID})
+	}
+	for _, key := range keys {
+		if at, ok := index.soldOut[key]; ok && at <= takenAt {
+			return model.SyncConflictSoldOut, fmt.Sprintf("%s was sold out before the order was taken", item.Name), false
+		}
+	}
+	return "", "", false
+}
+
+// orderChecksum identify the pushed order without its version and
+// payments, the same order pushed again has the same checksum
+func orderChecksum(pushed *model.SyncOrderForm) (string, error) {
+	items := make([]model.OrderItem, 0, len(pushed.Items))
+	for _, item := range pushed.Items {
+		canonical := *item
+		canonical.PriceListID, canonical.VoidReason, canonical.PriceApprovedBy = 0, "", 0
+		items = append(items, canonical)
+	}
+	data, err := json.Marshal(struct {
+		TableID, RoomID       int
+		Status                string
+		Channel, CustomerTier string
+		TaxExempt, NoService  bool
+		Items                 []model.OrderItem
+		CreatedAt, UpdatedAt  int64
+	}{pushed.TableID, pushed.RoomID, pushed.Status, pushed.Channel, pushed.CustomerTier,
+		pushed.TaxExempt, pushed.NoService, items, pushed.CreatedAt, pushed.UpdatedAt})
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+func NewSyncService(
+	syncRepo model.ISyncRepository,
+	orderRepo model.IOrderRepository,
+	availabilityRepo model.IAvailabilityRepository,
+	priceService model.ICatalogPriceService,
+	taxService model.ICatalogTaxService,
+) model.ISyncService {
+	return &syncService{
+		syncRepo:         syncRepo,
+		orderRepo:        orderRepo,
+		availabilityRepo: availabilityRepo,
+		priceService:     priceService,
+		taxService:       taxService,
+	}
+}
@@ -0,0 +1,484 @@
+package service_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	catalogService "github.com/aasumitro/posbe/internal/catalog/service"
+	"github.com/aasumitro/posbe/internal/transaction/service"
+	"github.com/aasumitro/posbe/mocks"
+	"github.com/aasumitro/posbe/pkg/model"
+	"github.com/aasumitro/posbe/pkg/money"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+const (
+	syncOrderID = "6f1c2d3e-4b5a-4c6d-8e7f-901234567890"
+	syncItemA   = "0a0a0a0a-0000-4000-8000-00000000000a"
+	syncItemB   = "0b0b0b0b-0000-4000-8000-00000000000b"
+	syncPayment = "0c0c0c0c-0000-4000-8000-00000000000c"
+)
+
+type syncTestSuite struct {
+	suite.Suite
+	syncRepo         *mocks.ISyncRepository
+	orderRepo        *mocks.IOrderRepository
+	availabilityRepo *mocks.IAvailabilityRepository
+	priceListRepo    *mocks.IPriceListRepository
+	prefRepo         *mocks.IStorePrefRepository
+	taxRepo          *mocks.ITaxClassRepository
+	svc              model.ISyncService
+	saved            *model.Order
+	candidates       []*model.PriceCandidate
+	taxClasses       []*model.TaxClass
+	serviceRate      string
+	shiftOpenAt      int64
+}
+
+func (suite *syncTestSuite) SetupTest() {
+	suite.syncRepo = new(mocks.ISyncRepository)
+	suite.orderRepo = new(mocks.IOrderRepository)
+	suite.availabilityRepo = new(mocks.IAvailabilityRepository)
+	suite.priceListRepo = new(mocks.IPriceListRepository)
+	suite.prefRepo = new(mocks.IStorePrefRepository)
+	suite.taxRepo = new(mocks.ITaxClassRepository)
+	suite.svc = service.NewSyncService(suite.syncRepo, suite.orderRepo, suite.availabilityRepo,
+		catalogService.NewCatalogPriceService(suite.priceListRepo, suite.prefRepo),
+		catalogService.NewCatalogTaxService(suite.taxRepo, suite.prefRepo))
+	suite.saved, suite.candidates, suite.serviceRate, suite.shiftOpenAt = nil, nil, "", 0
+	// every product is in the default class, inclusive so the total is the subtotal
+	suite.taxClasses = []*model.TaxClass{{ID: 1, Name: "VAT", Rate: 100000, Inclusive: true, IsDefault: true}}
+	// product 5 is deleted, the others never existed
+	suite.syncRepo.On("Products", mock.Anything, mock.Anything).
+		Return(map[int]bool{1: true, 2: true, 5: false}, nil)
+	suite.syncRepo.On("Variants", mock.Anything, mock.Anything).
+		Return(map[int]bool{7: true}, nil)
+	suite.syncRepo.On("ShiftOpenAt", mock.Anything).
+		Return(func(context.Context) int64 { return suite.shiftOpenAt }, nil)
+	// the catalog price of every item is 1500
+	suite.priceListRepo.On("BasePrices", mock.Anything, mock.Anything).
+		Return(func(_ context.Context, items []*model.PriceQueryItem) map[string]money.Amount {
+			prices := make(map[string]money.Amount)
+			for _, item := range items {
+				prices[model.PriceItemKey(item.ItemType, item.ItemID)] = 1500
+			}
+			return prices
+		}, nil)
+	suite.priceListRepo.On("Candidates", mock.Anything, mock.Anything).
+		Return(func(context.Context, []*model.PriceQueryItem) []*model.PriceCandidate {
+			return suite.candidates
+		}, nil)
+	suite.prefRepo.On("Find", mock.Anything, "fe_locale").Return(nil, sql.ErrNoRows)
+	suite.taxRepo.On("ProductClasses", mock.Anything, mock.Anything).
+		Return(func(_ context.Context, productIDs []int) map[int]int {
+			classes := make(map[int]int)
+			for _, productID := range productIDs {
+				classes[productID] = suite.taxClasses[0].ID
+			}
+			return classes
+		}, nil)
+	suite.taxRepo.On("All", mock.Anything).
+		Return(func(context.Context) []*model.TaxClass { return suite.taxClasses }, nil)
+	suite.prefRepo.On("Find", mock.Anything, "service_rate").
+		Return(func(context.Context, string) *model.StoreSetting {
+			return &model.StoreSetting{"service_rate": suite.serviceRate}
+		}, nil)
+	// product 2 was 86'd at 150
+	suite.availabilityRepo.On("SoldOut", mock.Anything).
+		Return([]*model.SoldOutItem{{ProductID: 2, CreatedAt: 150}}, nil)
+}
+
+// keep the saved order so the following Find return it
+func (suite *syncTestSuite) onSave() {
+	suite.orderRepo.On("Save", mock.Anything, mock.Anything).Once().
+		Return(func(_ context.Context, order *model.Order) *model.Order {
+			suite.saved = order
+			return order
+		}, nil)
+	suite.orderRepo.On("Find", mock.Anything, syncOrderID).Once().Return(nil, sql.ErrNoRows)
+	suite.orderRepo.On("Find", mock.Anything, syncOrderID).
+		Return(func(context.Context, string) *model.Order { return suite.saved }, nil)
+}
+
+// accept the payments pushed with the order
+func (suite *syncTestSuite) onPayments() {
+	suite.orderRepo.On("AddPayments", mock.Anything, syncOrderID, mock.Anything).
+		Return(func(_ context.Context, _ string, payments []*model.OrderPayment) []string {
+			ids := make([]string, 0, len(payments))
+			for _, payment := range payments {
+				ids = append(ids, payment.ID)
+			}
+			return ids
+		}, nil)
+}
+
+func syncOrder(createdAt int64, productIDs ...int) *model.SyncOrderForm {
+	order := &model.SyncOrderForm{ID: syncOrderID, Status: model.OrderStatusOpen, TableID: 3, CreatedAt: createdAt}
+	for i, productID := range productIDs {
+		order.Items = append(order.Items, &model.OrderItem{
+			ID: []string{syncItemA, syncItemB}[i], ProductID: productID,
+			Name: "lorem", Quantity: 2, Price: 1500,
+		})
+	}
+	return order
+}
+
+func (suite *syncTestSuite) TestSyncService_Pull_ShouldPageChanges() {
+	suite.syncRepo.On("Changes", mock.Anything, int64(4), 3).Once().
+		Return([]*model.SyncChange{{Version: 5}, {Version: 7}, {Version: 8}}, nil)
+	data, err := suite.svc.Pull(context.TODO(), &model.SyncPullQuery{Since: 4, Limit: 2})
+	require.Nil(suite.T(), err)
+	require.True(suite.T(), data.More)
+	require.Len(suite.T(), data.Changes, 2)
+	require.Equal(suite.T(), int64(7), data.Cursor)
+}
+
+func (suite *syncTestSuite) TestSyncService_Pull_ShouldKeepCursorWhenNoChanges() {
+	suite.syncRepo.On("Changes", mock.Anything, int64(9), 501).Once().
+		Return([]*model.SyncChange{}, nil)
+	data, err := suite.svc.Pull(context.TODO(), &model.SyncPullQuery{Since: 9})
+	require.Nil(suite.T(), err)
+	require.False(suite.T(), data.More)
+	require.Equal(suite.T(), int64(9), data.Cursor)
+}
+
+func (suite *syncTestSuite) TestSyncService_Pull_ShouldReturnError() {
+	suite.syncRepo.On("Changes", mock.Anything, int64(0), 501).Once().
+		Return(nil, errors.New("lorem"))
+	data, err := suite.svc.Pull(context.TODO(), &model.SyncPullQuery{})
+	require.Nil(suite.T(), data)
+	require.Equal(suite.T(), http.StatusInternalServerError, err.Code)
+}
+
+func (suite *syncTestSuite) TestSyncService_Push_ShouldApplyNewOrder() {
+	suite.onSave()
+	data, err := suite.svc.Push(context.TODO(), &model.SyncPushForm{
+		Orders: []*model.SyncOrderForm{syncOrder(100, 1, 2)}, CreatedBy: 4, TerminalID: 2})
+	require.Nil(suite.T(), err)
+	result := data.Orders[0]
+	require.Equal(suite.T(), model.SyncOrderApplied, result.Status)
+	require.Empty(suite.T(), result.Conflicts)
+	require.Equal(suite.T(), 1, result.Version)
+	require.Equal(suite.T(), money.Amount(6000), result.Order.Total)
+	require.Equal(suite.T(), 2, result.Order.TerminalID)
+	require.Equal(suite.T(), 4, result.Order.CreatedBy)
+}
+
+func (suite *syncTestSuite) TestSyncService_Push_ShouldPriceItemsWithPriceList() {
+	suite.onSave()
+	suite.candidates = []*model.PriceCandidate{
+		{List: &model.PriceList{ID: 4, Name: "takeaway", Channel: model.PriceChannelTakeaway},
+			Item: &model.PriceListItem{ItemType: model.PriceItemProduct, ItemID: 1, Price: 1000}},
+		{List: &model.PriceList{ID: 5, Name: "delivery", Channel: model.PriceChannelDelivery},
+			Item: &model.PriceListItem{ItemType: model.PriceItemVariant, ItemID: 7, Price: 900}},
+	}
+	order := syncOrder(100, 1, 2)
+	order.Channel = model.PriceChannelTakeaway
+	order.Items[1].VariantID, order.Items[1].PriceListID = 7, 5
+	data, err := suite.svc.Push(context.TODO(), &model.SyncPushForm{Orders: []*model.SyncOrderForm{order}})
+	require.Nil(suite.T(), err)
+	items := data.Orders[0].Order.Items
+	// the price pushed by the terminal is replaced
+	require.Equal(suite.T(), money.Amount(1000), items[0].Price)
+	require.Equal(suite.T(), 4, items[0].PriceListID)
+	require.Equal(suite.T(), money.Amount(1500), items[1].Price)
+	require.Zero(suite.T(), items[1].PriceListID)
+	require.Equal(suite.T(), money.Amount(5000), data.Orders[0].Order.Total)
+	// priced as at the time the order was taken
+	suite.priceListRepo.AssertCalled(suite.T(), "BasePrices", mock.Anything,
+		[]*model.PriceQueryItem{{ItemType: model.PriceItemProduct, ItemID: 1},
+			{ItemType: model.PriceItemVariant, ItemID: 7}})
+}
+
+func (suite *syncTestSuite) TestSyncService_Push_ShouldKeepTaxSummary() {
+	suite.onSave()
+	suite.serviceRate = "5"
+	suite.taxClasses = []*model.TaxClass{{ID: 2, Name: "VAT", Rate: 100000, IsDefault: true}}
+	data, err := suite.svc.Push(context.TODO(), &model.SyncPushForm{
+		Orders: []*model.SyncOrderForm{syncOrder(100, 1)}})
+	require.Nil(suite.T(), err)
+	order := data.Orders[0].Order
+	// 3000 net, 5% service and 10% exclusive tax on the net
+	require.Equal(suite.T(), money.Amount(150), order.ServiceCharge)
+	require.Equal(suite.T(), money.Amount(300), order.Tax)
+	require.Equal(suite.T(), money.Amount(3450), order.Total)
+	require.Len(suite.T(), order.TaxSummary.Lines, 1)
+	require.Equal(suite.T(), 2, order.TaxSummary.Lines[0].TaxClassID)
+}
+
+func (suite *syncTestSuite) TestSyncService_Push_ShouldIgnoreTaxExemptOfPush() {
+	suite.onSave()
+	suite.serviceRate = "5"
+	suite.taxClasses = []*model.TaxClass{{ID: 2, Name: "VAT", Rate: 100000, IsDefault: true}}
+	order := syncOrder(100, 1)
+	order.TaxExempt, order.NoService = true, true
+	data, err := suite.svc.Push(context.TODO(), &model.SyncPushForm{Orders: []*model.SyncOrderForm{order}})
+	require.Nil(suite.T(), err)
+	result := data.Orders[0]
+	require.Equal(suite.T(), model.SyncConflictNotPermitted, result.Conflicts[0].Rule)
+	require.Equal(suite.T(), model.SyncResolutionServerWins, result.Conflicts[0].Resolution)
+	require.False(suite.T(), result.Order.TaxExempt)
+	require.Equal(suite.T(), money.Amount(150), result.Order.ServiceCharge)
+	require.Equal(suite.T(), money.Amount(3450), result.Order.Total)
+}
+
+func (suite *syncTestSuite) TestSyncService_Push_ShouldVoidItemNeverExisted() {
+	suite.onSave()
+	suite.onPayments()
+	order := syncOrder(100, 1, 9)
+	order.Status = model.OrderStatusPaid
+	order.Payments = []*model.OrderPayment{{ID: syncPayment, Method: "cash", Amount: 3000, CreatedAt: 110}}
+	data, err := suite.svc.Push(context.TODO(), &model.SyncPushForm{
+		Orders: []*model.SyncOrderForm{order}, CanTender: true})
+	require.Nil(suite.T(), err)
+	result := data.Orders[0]
+	require.Len(suite.T(), result.Conflicts, 1)
+	require.Equal(suite.T(), model.SyncConflictNotFound, result.Conflicts[0].Rule)
+	require.Equal(suite.T(), model.SyncResolutionVoided, result.Conflicts[0].Resolution)
+	require.Equal(suite.T(), money.Amount(3000), result.Order.Total)
+}
+
+func (suite *syncTestSuite) TestSyncService_Push_ShouldLeaveSameOrderUnchanged() {
+	suite.onSave()
+	form := &model.SyncPushForm{Orders: []*model.SyncOrderForm{syncOrder(100, 1)}}
+	_, err := suite.svc.Push(context.TODO(), form)
+	require.Nil(suite.T(), err)
+	// the respond was lost, the terminal push the same order again
+	data, err := suite.svc.Push(context.TODO(), form)
+	require.Nil(suite.T(), err)
+	require.Equal(suite.T(), model.SyncOrderUnchanged, data.Orders[0].Status)
+	suite.orderRepo.AssertNumberOfCalls(suite.T(), "Save", 1)
+}
+
+func (suite *syncTestSuite) TestSyncService_Push_ShouldVoidSoldOutItemOfOpenOrder() {
+	suite.onSave()
+	data, err := suite.svc.Push(context.TODO(), &model.SyncPushForm{
+		Orders: []*model.SyncOrderForm{syncOrder(200, 1, 2)}})
+	require.Nil(suite.T(), err)
+	result := data.Orders[0]
+	require.Equal(suite.T(), model.SyncOrderApplied, result.Status)
+	require.Len(suite.T(), result.Conflicts, 1)
+	require.Equal(suite.T(), model.SyncConflictSoldOut, result.Conflicts[0].Rule)
+	require.Equal(suite.T(), model.SyncResolutionVoided, result.Conflicts[0].Resolution)
+	require.Equal(suite.T(), syncItemB, result.Conflicts[0].ItemID)
+	require.Equal(suite.T(), model.SyncConflictSoldOut, result.Order.Items[1].VoidReason)
+	require.Equal(suite.T(), money.Amount(3000), result.Order.Total)
+}
+
+func (suite *syncTestSuite) TestSyncService_Push_ShouldKeepSoldOutItemOfPaidOrder() {
+	suite.onSave()
+	suite.onPayments()
+	order := syncOrder(200, 2, 5)
+	order.Status = model.OrderStatusPaid
+	order.Payments = []*model.OrderPayment{{ID: syncPayment, Method: "cash", Amount: 6000, CreatedAt: 210}}
+	data, err := suite.svc.Push(context.TODO(), &model.SyncPushForm{
+		Orders: []*model.SyncOrderForm{order}, CanTender: true})
+	require.Nil(suite.T(), err)
+	result := data.Orders[0]
+	require.Len(suite.T(), result.Conflicts, 2)
+	require.Equal(suite.T(), model.SyncConflictSoldOut, result.Conflicts[0].Rule)
+	require.Equal(suite.T(), model.SyncConflictNotFound, result.Conflicts[1].Rule)
+	for _, conflict := range result.Conflicts {
+		require.Equal(suite.T(), model.SyncResolutionKept, conflict.Resolution)
+	}
+	require.Equal(suite.T(), money.Amount(6000), result.Order.Total)
+	require.Equal(suite.T(), model.OrderStatusPaid, result.Order.Status)
+}
+
+func (suite *syncTestSuite) TestSyncService_Push_ShouldKeepVoidOrderOpen() {
+	suite.onSave()
+	order := syncOrder(100, 1)
+	order.Status = model.OrderStatusVoid
+	data, err := suite.svc.Push(context.TODO(), &model.SyncPushForm{
+		Orders: []*model.SyncOrderForm{order}, CanTender: true})
+	require.Nil(suite.T(), err)
+	result := data.Orders[0]
+	require.Equal(suite.T(), model.SyncOrderApplied, result.Status)
+	require.Equal(suite.T(), model.OrderStatusOpen, result.Order.Status)
+	require.Len(suite.T(), result.Conflicts, 1)
+	require.Equal(suite.T(), model.SyncConflictNotPermitted, result.Conflicts[0].Rule)
+	require.Contains(suite.T(), result.Conflicts[0].Message, "/orders/{id}/void")
+}
+
+func (suite *syncTestSuite) TestSyncService_Push_ShouldKeepPaidOrderOpenWhenNotPermitted() {
+	suite.onSave()
+	order := syncOrder(100, 1)
+	order.Status = model.OrderStatusPaid
+	data, err := suite.svc.Push(context.TODO(), &model.SyncPushForm{Orders: []*model.SyncOrderForm{order}})
+	require.Nil(suite.T(), err)
+	result := data.Orders[0]
+	require.Equal(suite.T(), model.OrderStatusOpen, result.Order.Status)
+	require.Equal(suite.T(), model.SyncConflictNotPermitted, result.Conflicts[0].Rule)
+	require.Equal(suite.T(), model.SyncResolutionRejected, result.Conflicts[0].Resolution)
+}
+
+func (suite *syncTestSuite) TestSyncService_Push_ShouldKeepUnpaidOrderOpen() {
+	suite.onSave()
+	suite.onPayments()
+	order := syncOrder(200, 1, 2)
+	order.Status = model.OrderStatusPaid
+	order.Payments = []*model.OrderPayment{{ID: syncPayment, Method: "cash", Amount: 1000, CreatedAt: 210}}
+	data, err := suite.svc.Push(context.TODO(), &model.SyncPushForm{
+		Orders: []*model.SyncOrderForm{order}, CanTender: true})
+	require.Nil(suite.T(), err)
+	result := data.Orders[0]
+	require.Equal(suite.T(), model.OrderStatusOpen, result.Order.Status)
+	require.Len(suite.T(), result.Conflicts, 2)
+	require.Equal(suite.T(), model.SyncConflictUnpaid, result.Conflicts[0].Rule)
+	// resolved as open, the 86'd item is voided
+	require.Equal(suite.T(), model.SyncConflictSoldOut, result.Conflicts[1].Rule)
+	require.Equal(suite.T(), model.SyncResolutionVoided, result.Conflicts[1].Resolution)
+	require.Equal(suite.T(), money.Amount(3000), result.Order.Total)
+	suite.orderRepo.AssertCalled(suite.T(), "AddPayments", mock.Anything, syncOrderID, mock.Anything)
+}
+
+func (suite *syncTestSuite) TestSyncService_Push_ShouldClampCreatedAtToOpenShift() {
+	suite.onSave()
+	suite.shiftOpenAt = 180
+	// taken "before" product 2 was 86'd at 150
+	data, err := suite.svc.Push(context.TODO(), &model.SyncPushForm{
+		Orders: []*model.SyncOrderForm{syncOrder(100, 1, 2)}})
+	require.Nil(suite.T(), err)
+	result := data.Orders[0]
+	require.Equal(suite.T(), int64(180), result.Order.CreatedAt)
+	require.Equal(suite.T(), model.SyncConflictSoldOut, result.Conflicts[0].Rule)
+	require.Equal(suite.T(), model.SyncResolutionVoided, result.Conflicts[0].Resolution)
+}
+
+func (suite *syncTestSuite) TestSyncService_Push_ShouldClampCreatedAtToReceivedAt() {
+	suite.onSave()
+	future := time.Now().Add(24 * time.Hour).Unix()
+	data, err := suite.svc.Push(context.TODO(), &model.SyncPushForm{
+		Orders: []*model.SyncOrderForm{syncOrder(future, 1)}})
+	require.Nil(suite.T(), err)
+	createdAt := data.Orders[0].Order.CreatedAt
+	require.Less(suite.T(), createdAt, future)
+	require.LessOrEqual(suite.T(), createdAt, time.Now().Unix())
+}
+
+func (suite *syncTestSuite) TestSyncService_Push_ShouldKeepCreatedAtOfFirstPush() {
+	suite.orderRepo.On("Find", mock.Anything, syncOrderID).Once().Return(&model.Order{
+		ID: syncOrderID, Status: model.OrderStatusOpen, Version: 1, CreatedAt: 100,
+	}, nil)
+	suite.orderRepo.On("Save", mock.Anything, mock.Anything).Once().
+		Return(func(_ context.Context, order *model.Order) *model.Order {
+			suite.saved = order
+			return order
+		}, nil)
+	suite.orderRepo.On("Find", mock.Anything, syncOrderID).
+		Return(func(context.Context, string) *model.Order { return suite.saved }, nil)
+	order := syncOrder(300, 2)
+	order.Version = 1
+	data, err := suite.svc.Push(context.TODO(), &model.SyncPushForm{Orders: []*model.SyncOrderForm{order}})
+	require.Nil(suite.T(), err)
+	result := data.Orders[0]
+	require.Equal(suite.T(), int64(100), result.Order.CreatedAt)
+	// taken before product 2 was 86'd at 150
+	require.Empty(suite.T(), result.Conflicts)
+}
+
+func (suite *syncTestSuite) TestSyncService_Push_ShouldRejectClosedOrder() {
+	suite.orderRepo.On("Find", mock.Anything, syncOrderID).
+		Return(&model.Order{ID: syncOrderID, Status: model.OrderStatusVoid, Version: 2}, nil)
+	order := syncOrder(100, 1)
+	order.Version = 2
+	data, err := suite.svc.Push(context.TODO(), &model.SyncPushForm{Orders: []*model.SyncOrderForm{order}})
+	require.Nil(suite.T(), err)
+	result := data.Orders[0]
+	require.Equal(suite.T(), model.SyncOrderRejected, result.Status)
+	require.Equal(suite.T(), model.SyncConflictOrderClosed, result.Conflicts[0].Rule)
+	require.Equal(suite.T(), model.SyncResolutionServerWins, result.Conflicts[0].Resolution)
+	suite.orderRepo.AssertNotCalled(suite.T(), "Save", mock.Anything, mock.Anything)
+}
+
+func (suite *syncTestSuite) TestSyncService_Push_ShouldRejectStaleVersion() {
+	suite.orderRepo.On("Find", mock.Anything, syncOrderID).
+		Return(&model.Order{ID: syncOrderID, Status: model.OrderStatusOpen, Version: 3}, nil)
+	order := syncOrder(100, 1)
+	order.Version = 2
+	data, err := suite.svc.Push(context.TODO(), &model.SyncPushForm{Orders: []*model.SyncOrderForm{order}})
+	require.Nil(suite.T(), err)
+	result := data.Orders[0]
+	require.Equal(suite.T(), model.SyncOrderRejected, result.Status)
+	require.Equal(suite.T(), 3, result.Version)
+	require.Equal(suite.T(), model.SyncConflictStaleVersion, result.Conflicts[0].Rule)
+	suite.orderRepo.AssertNotCalled(suite.T(), "Save", mock.Anything, mock.Anything)
+}
+
+func (suite *syncTestSuite) TestSyncService_Push_ShouldKeepApprovedOverrides() {
+	suite.orderRepo.On("Find", mock.Anything, syncOrderID).Once().Return(&model.Order{
+		ID: syncOrderID, Status: model.OrderStatusOpen, Version: 2,
+		Discount: 1000, NoService: true, DiscountApprovedBy: 1,
+		Items: []*model.OrderItem{{ID: syncItemA, ProductID: 1, Quantity: 2, Price: 500, PriceApprovedBy: 1}},
+	}, nil)
+	suite.orderRepo.On("Save", mock.Anything, mock.Anything).Once().
+		Return(func(_ context.Context, order *model.Order) *model.Order {
+			suite.saved = order
+			return order
+		}, nil)
+	suite.orderRepo.On("Find", mock.Anything, syncOrderID).
+		Return(func(context.Context, string) *model.Order { return suite.saved }, nil)
+	order := syncOrder(100, 1, 2)
+	order.Version, order.NoService = 2, true
+	// the terminal try to set its own price and to approve it
+	order.Items[0].PriceApprovedBy = 4
+	order.Items[1].PriceApprovedBy = 4
+	data, err := suite.svc.Push(context.TODO(), &model.SyncPushForm{Orders: []*model.SyncOrderForm{order}})
+	require.Nil(suite.T(), err)
+	saved := data.Orders[0].Order
+	require.Equal(suite.T(), model.SyncOrderApplied, data.Orders[0].Status)
+	require.Empty(suite.T(), data.Orders[0].Conflicts)
+	require.Equal(suite.T(), money.Amount(500), saved.Items[0].Price)
+	require.Equal(suite.T(), 1, saved.Items[0].PriceApprovedBy)
+	require.Equal(suite.T(), money.Amount(1500), saved.Items[1].Price)
+	require.Zero(suite.T(), saved.Items[1].PriceApprovedBy)
+	require.Equal(suite.T(), 1, saved.DiscountApprovedBy)
+	require.True(suite.T(), saved.NoService)
+	// 1000 + 3000 less the discount
+	require.Equal(suite.T(), money.Amount(3000), saved.Total)
+}
+
+func (suite *syncTestSuite) TestSyncService_Push_ShouldRejectPaymentsNotPermitted() {
+	suite.onSave()
+	order := syncOrder(100, 1)
+	order.Payments = []*model.OrderPayment{{ID: syncPayment, Method: "cash", Amount: 3000, CreatedAt: 110}}
+	data, err := suite.svc.Push(context.TODO(), &model.SyncPushForm{Orders: []*model.SyncOrderForm{order}})
+	require.Nil(suite.T(), err)
+	result := data.Orders[0]
+	require.Equal(suite.T(), model.SyncOrderApplied, result.Status)
+	require.Equal(suite.T(), model.SyncConflictNotPermitted, result.Conflicts[0].Rule)
+	require.Equal(suite.T(), syncPayment, result.Conflicts[0].PaymentID)
+	suite.orderRepo.AssertNotCalled(suite.T(), "AddPayments", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *syncTestSuite) TestSyncService_Push_ShouldAddPayments() {
+	suite.onSave()
+	suite.orderRepo.On("AddPayments", mock.Anything, syncOrderID,
+		mock.MatchedBy(func(payments []*model.OrderPayment) bool {
+			return payments[0].CreatedBy == 4
+		})).Once().Return([]string{syncPayment}, nil)
+	order := syncOrder(100, 1)
+	order.Payments = []*model.OrderPayment{{ID: syncPayment, Method: "cash", Amount: 3000, CreatedAt: 110}}
+	data, err := suite.svc.Push(context.TODO(), &model.SyncPushForm{
+		Orders: []*model.SyncOrderForm{order}, CreatedBy: 4, CanTender: true})
+	require.Nil(suite.T(), err)
+	require.Empty(suite.T(), data.Orders[0].Conflicts)
+}
+
+func (suite *syncTestSuite) TestSyncService_Push_ShouldReturnError() {
+	suite.orderRepo.On("Find", mock.Anything, syncOrderID).Return(nil, errors.New("lorem"))
+	data, err := suite.svc.Push(context.TODO(), &model.SyncPushForm{
+		Orders: []*model.SyncOrderForm{syncOrder(100, 1)}})
+	require.Nil(suite.T(), data)
+	require.Equal(suite.T(), http.StatusInternalServerError, err.Code)
+}
+
+func TestSyncService(t *testing.T) {
+	suite.Run(t, new(syncTestSuite))
+}
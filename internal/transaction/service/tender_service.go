@@ -0,0 +1,117 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/aasumitro/posbe/pkg/model"
+	"github.com/aasumitro/posbe/pkg/money"
+	"github.com/aasumitro/posbe/pkg/utils"
+)
+
+type tenderService struct {
+	tenderRepo model.ICashTenderRepository
+	rateRepo   model.ICurrencyRateRepository
+}
+
+// CashTender convert the tendered cash with the rate in effect, change is
+// given in the store currency and the rate used is kept with the tender
+func (service tenderService) CashTender(
+	ctx context.Context,
+	form *model.TenderForm,
+) (data *model.CashTender, errData *utils.ServiceError) {
+	base := money.StoreCurrency()
+	currency, err := money.Lookup(form.Currency)
+	if err != nil {
+		return nil, &utils.ServiceError{
+			Code:    http.StatusUnprocessableEntity,
+			Message: err.Error(),
+		}
+	}
+	amount, err := currency.Parse(form.Amount.String())
+	if err == nil && (amount <= 0 || form.Due < 0) {
+		err = money.ErrorInvalidAmount
+	}
+	if err != nil {
+		return nil, &utils.ServiceError{
+			Code:    http.StatusUnprocessableEntity,
+			Message: err.Error(),
+		}
+	}
+
+	tender := &model.CashTender{
+		Tendered:  money.Money{Amount: amount, Currency: currency},
+		Converted: money.Money{Amount: amount, Currency: base},
+		Due:       money.Money{Amount: form.Due, Currency: base},
+		CreatedBy: form.CreatedBy,
+	}
+	tender.Rate, _ = money.ParseRate("1")
+	if currency.Code != base.Code {
+		rate, err := service.rateRepo.Find(ctx, currency.Code)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return nil, &utils.ServiceError{
+					Code:    http.StatusUnprocessableEntity,
+					Message: fmt.Sprintf("no exchange rate for %s", currency.Code),
+				}
+			}
+			return nil, &utils.ServiceError{
+				Code:    http.StatusInternalServerError,
+				Message: err.Error(),
+			}
+		}
+		tender.CurrencyRateID, tender.Rate = rate.ID, rate.Rate
+		tender.Converted.Amount = rate.Rate.Convert(amount, currency, base)
+	}
+
+	if tender.Converted.Amount < tender.Due.Amount {
+		return nil, &utils.ServiceError{
+			Code: http.StatusUnprocessableEntity,
+			Message: fmt.Sprintf("tendered %s (%s) does not cover %s",
+				currency.Display(amount), base.Display(tender.Converted.Amount),
+				base.Display(tender.Due.Amount)),
+		}
+	}
+	tender.Change = money.Money{Amount: tender.Converted.Amount - tender.Due.Amount, Currency: base}
+
+	data, err = service.tenderRepo.Create(ctx, tender)
+	return utils.ValidateDataRow[model.CashTender](data, err)
+}
+
+func (service tenderService) TenderDetail(
+	ctx context.Context,
+	id int,
+) (data *model.CashTender, errData *utils.ServiceError) {
+	data, err := service.tenderRepo.Find(ctx, id)
+	return utils.ValidateDataRow[model.CashTender](data, err)
+}
+
+// OpenCashDrawer record a no-sale open with the user who approved it
+func (service tenderService) OpenCashDrawer(
+	ctx context.Context,
+	form *model.CashDrawerForm,
+) (data *model.CashDrawerOpen, errData *utils.ServiceError) {
+	data, err := service.tenderRepo.OpenDrawer(ctx, &model.CashDrawerOpen{
+		Reason: form.Reason, OpenedBy: form.OpenedBy, ApprovedBy: form.ApprovedBy,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, &utils.ServiceError{
+			Code:    http.StatusUnprocessableEntity,
+			Message: "no store shift is open",
+		}
+	}
+	return utils.ValidateDataRow[model.CashDrawerOpen](data, err)
+}
+
+func NewTenderService(
+	tenderRepo model.ICashTenderRepository,
+	rateRepo model.ICurrencyRateRepository,
+) model.ITenderService {
+	return &tenderService{
+		tenderRepo: tenderRepo,
+		rateRepo:   rateRepo,
+	}
+}
@@ -0,0 +1,106 @@
+package service_test
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"testing"
+
+	"github.com/aasumitro/posbe/internal/transaction/service"
+	"github.com/aasumitro/posbe/mocks"
+	"github.com/aasumitro/posbe/pkg/model"
+	"github.com/aasumitro/posbe/pkg/money"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type tenderTestSuite struct {
+	suite.Suite
+	tenderRepo *mocks.ICashTenderRepository
+	rateRepo   *mocks.ICurrencyRateRepository
+	svc        model.ITenderService
+}
+
+func (suite *tenderTestSuite) SetupTest() {
+	suite.tenderRepo = new(mocks.ICashTenderRepository)
+	suite.rateRepo = new(mocks.ICurrencyRateRepository)
+	suite.svc = service.NewTenderService(suite.tenderRepo, suite.rateRepo)
+	rate, err := money.ParseRate("16200.5")
+	require.NoError(suite.T(), err)
+	suite.rateRepo.On("Find", mock.Anything, "USD").
+		Return(&model.CurrencyRate{ID: 7, Currency: "USD", Rate: rate}, nil)
+	suite.rateRepo.On("Find", mock.Anything, "SGD").Return(nil, sql.ErrNoRows)
+	suite.tenderRepo.On("Create", mock.Anything, mock.Anything).
+		Return(func(_ context.Context, tender *model.CashTender) *model.CashTender {
+			tender.ID = 1
+			return tender
+		}, nil)
+}
+
+func (suite *tenderTestSuite) TestTenderService_CashTender_ShouldConvertForeignCash() {
+	// USD 20.01 at 16,200.5 = IDR 324,172.00 (truncated), due IDR 300,000.00
+	data, err := suite.svc.CashTender(context.TODO(), &model.TenderForm{
+		Currency: "usd", Amount: "20.01", Due: 30000000})
+	require.Nil(suite.T(), err)
+	require.Equal(suite.T(), 7, data.CurrencyRateID)
+	require.Equal(suite.T(), money.Amount(2001), data.Tendered.Amount)
+	require.Equal(suite.T(), money.Amount(32417200), data.Converted.Amount)
+	require.Equal(suite.T(), money.Amount(2417200), data.Change.Amount)
+	require.Equal(suite.T(), "IDR", data.Change.Currency.Code)
+	require.Equal(suite.T(), "Rp24.172,00", data.Change.Currency.Display(data.Change.Amount))
+}
+
+func (suite *tenderTestSuite) TestTenderService_CashTender_ShouldAcceptStoreCurrency() {
+	data, err := suite.svc.CashTender(context.TODO(), &model.TenderForm{
+		Currency: "IDR", Amount: "50000", Due: 4500000})
+	require.Nil(suite.T(), err)
+	require.Zero(suite.T(), data.CurrencyRateID)
+	require.Equal(suite.T(), "1", data.Rate.String())
+	require.Equal(suite.T(), money.Amount(500000), data.Change.Amount)
+	suite.rateRepo.AssertNotCalled(suite.T(), "Find", mock.Anything, "IDR")
+}
+
+func (suite *tenderTestSuite) TestTenderService_CashTender_ShouldReject() {
+	for _, form := range []*model.TenderForm{
+		{Currency: "XXX", Amount: "10"},
+		{Currency: "USD", Amount: "10.001"},
+		{Currency: "USD", Amount: "0"},
+		{Currency: "SGD", Amount: "10"},
+		{Currency: "USD", Amount: "10", Due: 30000000},
+	} {
+		data, err := suite.svc.CashTender(context.TODO(), form)
+		require.Nil(suite.T(), data)
+		require.Equal(suite.T(), http.StatusUnprocessableEntity, err.Code, form)
+	}
+	suite.tenderRepo.AssertNotCalled(suite.T(), "Create", mock.Anything, mock.Anything)
+}
+
+func (suite *tenderTestSuite) TestTenderService_TenderDetail_ShouldErrorNotFound() {
+	suite.tenderRepo.On("Find", mock.Anything, 9).Once().Return(nil, sql.ErrNoRows)
+	data, err := suite.svc.TenderDetail(context.TODO(), 9)
+	require.Nil(suite.T(), data)
+	require.Equal(suite.T(), http.StatusNotFound, err.Code)
+}
+
+func (suite *tenderTestSuite) TestTenderService_OpenCashDrawer_ShouldRecordApprover() {
+	suite.tenderRepo.On("OpenDrawer", mock.Anything, &model.CashDrawerOpen{
+		Reason: "change for float", OpenedBy: 2, ApprovedBy: 1}).
+		Once().Return(&model.CashDrawerOpen{ID: 1, StoreShiftID: 3, OpenedBy: 2, ApprovedBy: 1}, nil)
+	data, err := suite.svc.OpenCashDrawer(context.TODO(), &model.CashDrawerForm{
+		Reason: "change for float", OpenedBy: 2, ApprovedBy: 1})
+	require.Nil(suite.T(), err)
+	require.Equal(suite.T(), 1, data.ApprovedBy)
+}
+
+func (suite *tenderTestSuite) TestTenderService_OpenCashDrawer_ShouldErrorNoShift() {
+	suite.tenderRepo.On("OpenDrawer", mock.Anything, mock.Anything).
+		Once().Return(nil, sql.ErrNoRows)
+	data, err := suite.svc.OpenCashDrawer(context.TODO(), &model.CashDrawerForm{Reason: "lorem"})
+	require.Nil(suite.T(), data)
+	require.Equal(suite.T(), http.StatusUnprocessableEntity, err.Code)
+}
+
+func TestTenderService(t *testing.T) {
+	suite.Run(t, new(tenderTestSuite))
+}
@@ -120,6 +120,29 @@ func (_m *ICRUDAddOnRepository[T]) Find(ctx context.Context, key domain.FindWith
 	return r0, r1
 }
 
+// Restore provides a mock function with given fields: ctx, params
+func (_m *ICRUDAddOnRepository[T]) Restore(ctx context.Context, params *T) (*T, error) {
+	ret := _m.Called(ctx, params)
+
+	var r0 *T
+	if rf, ok := ret.Get(0).(func(context.Context, *T) *T); ok {
+		r0 = rf(ctx, params)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*T)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *T) error); ok {
+		r1 = rf(ctx, params)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Update provides a mock function with given fields: ctx, params
 func (_m *ICRUDAddOnRepository[T]) Update(ctx context.Context, params *T) (*T, error) {
 	ret := _m.Called(ctx, params)
@@ -97,6 +97,29 @@ func (_m *ICRUDWithSearchRepository[T]) Find(ctx context.Context, key domain.Fin
 	return r0, r1
 }
 
+// Restore provides a mock function with given fields: ctx, params
+func (_m *ICRUDWithSearchRepository[T]) Restore(ctx context.Context, params *T) (*T, error) {
+	ret := _m.Called(ctx, params)
+
+	var r0 *T
+	if rf, ok := ret.Get(0).(func(context.Context, *T) *T); ok {
+		r0 = rf(ctx, params)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*T)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *T) error); ok {
+		r1 = rf(ctx, params)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Search provides a mock function with given fields: ctx, keys, values
 func (_m *ICRUDWithSearchRepository[T]) Search(ctx context.Context, keys []domain.FindWith, values []interface{}) ([]*T, error) {
 	ret := _m.Called(ctx, keys, values)
@@ -0,0 +1,67 @@
+package audit
+
+import (
+	"context"
+	"database/sql"
+	"strconv"
+
+	"github.com/aasumitro/posbe/config"
+	"github.com/aasumitro/posbe/pkg/model"
+)
+
+// ActorID read the user of the audited request, 0 when not audited
+// or the request is made with an api key
+func ActorID(ctx context.Context) int {
+	entry, ok := ctx.Value(model.AuditEntryKey).(*model.AuditEntry)
+	if !ok {
+		return 0
+	}
+	return entry.ActorID
+}
+
+// NullActor is ActorID as nullable column (e.g. deleted_by)
+func NullActor(ctx context.Context) sql.NullInt64 {
+	id := ActorID(ctx)
+	return sql.NullInt64{Int64: int64(id), Valid: id > 0}
+}
+
+// SetActor keep the actor on the transaction (posbe.actor_id of postgres,
+// audit_actor of sqlite), it is read by the triggers that record entity versions
+func SetActor(ctx context.Context, tx *sql.Tx) error {
+	var actor string
+	if id := ActorID(ctx); id > 0 {
+		actor = strconv.Itoa(id)
+	}
+	_, err := tx.ExecContext(ctx, config.Dialect.SetActor(), actor)
+	return err
+}
+
+// ResetActor clear the actor before commit when it outlive the transaction
+func ResetActor(ctx context.Context, tx *sql.Tx) error {
+	q := config.Dialect.ResetActor()
+	if q == "" {
+		return nil
+	}
+	_, err := tx.ExecContext(ctx, q)
+	return err
+}
+
+// Versioned run the write of a versioned table in a transaction with the actor set
+func Versioned(ctx context.Context, db *sql.DB, write func(tx *sql.Tx) error) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	// rollback is no-op after commit
+	defer func() { _ = tx.Rollback() }()
+	if err := SetActor(ctx, tx); err != nil {
+		return err
+	}
+	if err := write(tx); err != nil {
+		return err
+	}
+	if err := ResetActor(ctx, tx); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,30 @@
+package audit
+
+import (
+	"encoding/json"
+	"reflect"
+
+	"github.com/aasumitro/posbe/pkg/model"
+)
+
+// Diff compare the top level fields of two json objects,
+// nil when one of them is missing (e.g. create and delete)
+func Diff(before, after json.RawMessage) map[string]*model.AuditChange {
+	var old, current map[string]any
+	if json.Unmarshal(before, &old) != nil || json.Unmarshal(after, &current) != nil ||
+		old == nil || current == nil {
+		return nil
+	}
+	diff := make(map[string]*model.AuditChange)
+	for field, value := range current {
+		if !reflect.DeepEqual(old[field], value) {
+			diff[field] = &model.AuditChange{Before: old[field], After: value}
+		}
+	}
+	for field, value := range old {
+		if _, ok := current[field]; !ok {
+			diff[field] = &model.AuditChange{Before: value}
+		}
+	}
+	return diff
+}
@@ -0,0 +1,107 @@
+package audit
+
+import (
+	"context"
+	"reflect"
+
+	"github.com/aasumitro/posbe/pkg/model"
+)
+
+type (
+	crudRepository[T any] struct {
+		model.ISoftDeleteRepository[T]
+	}
+
+	crudAddOnRepository[T any] struct {
+		model.ICRUDAddOnRepository[T]
+	}
+
+	crudWithSearchRepository[T any] struct {
+		model.ICRUDWithSearchRepository[T]
+	}
+)
+
+// Track load the row before Update, Delete and Restore of an audited request,
+// so the audit entry keep the before state of the change
+func Track[T any](repo model.ISoftDeleteRepository[T]) model.ISoftDeleteRepository[T] {
+	return &crudRepository[T]{repo}
+}
+
+// TrackAddOn is Track for model.ICRUDAddOnRepository
+func TrackAddOn[T any](repo model.ICRUDAddOnRepository[T]) model.ICRUDAddOnRepository[T] {
+	return &crudAddOnRepository[T]{repo}
+}
+
+// TrackWithSearch is Track for model.ICRUDWithSearchRepository
+func TrackWithSearch[T any](repo model.ICRUDWithSearchRepository[T]) model.ICRUDWithSearchRepository[T] {
+	return &crudWithSearchRepository[T]{repo}
+}
+
+func (repo crudRepository[T]) Update(ctx context.Context, params *T) (*T, error) {
+	before(ctx, repo.ISoftDeleteRepository, params)
+	return repo.ISoftDeleteRepository.Update(ctx, params)
+}
+
+func (repo crudRepository[T]) Delete(ctx context.Context, params *T) error {
+	before(ctx, repo.ISoftDeleteRepository, params)
+	return repo.ISoftDeleteRepository.Delete(ctx, params)
+}
+
+func (repo crudRepository[T]) Restore(ctx context.Context, params *T) (*T, error) {
+	before(deleted(ctx), repo.ISoftDeleteRepository, params)
+	return repo.ISoftDeleteRepository.Restore(ctx, params)
+}
+
+func (repo crudAddOnRepository[T]) Update(ctx context.Context, params *T) (*T, error) {
+	before(ctx, repo.ICRUDAddOnRepository, params)
+	return repo.ICRUDAddOnRepository.Update(ctx, params)
+}
+
+func (repo crudAddOnRepository[T]) Delete(ctx context.Context, params *T) error {
+	before(ctx, repo.ICRUDAddOnRepository, params)
+	return repo.ICRUDAddOnRepository.Delete(ctx, params)
+}
+
+func (repo crudAddOnRepository[T]) Restore(ctx context.Context, params *T) (*T, error) {
+	before(deleted(ctx), repo.ICRUDAddOnRepository, params)
+	return repo.ICRUDAddOnRepository.Restore(ctx, params)
+}
+
+func (repo crudWithSearchRepository[T]) Update(ctx context.Context, params *T) (*T, error) {
+	before(ctx, repo.ICRUDWithSearchRepository, params)
+	return repo.ICRUDWithSearchRepository.Update(ctx, params)
+}
+
+func (repo crudWithSearchRepository[T]) Delete(ctx context.Context, params *T) error {
+	before(ctx, repo.ICRUDWithSearchRepository, params)
+	return repo.ICRUDWithSearchRepository.Delete(ctx, params)
+}
+
+func (repo crudWithSearchRepository[T]) Restore(ctx context.Context, params *T) (*T, error) {
+	before(deleted(ctx), repo.ICRUDWithSearchRepository, params)
+	return repo.ICRUDWithSearchRepository.Restore(ctx, params)
+}
+
+// deleted let before find the soft deleted row that is restored
+func deleted(ctx context.Context) context.Context {
+	return context.WithValue(ctx, model.IncludeDeletedKey, true) //nolint:staticcheck // gin context keys are strings
+}
+
+// before find the row with the ID field of params, nothing is loaded
+// when the request is not audited
+func before[T any](ctx context.Context, repo model.ICRUDRepository[T], params *T) {
+	if _, ok := ctx.Value(model.AuditEntryKey).(*model.AuditEntry); !ok || params == nil {
+		return
+	}
+	value := reflect.ValueOf(params).Elem()
+	if value.Kind() != reflect.Struct {
+		return
+	}
+	id := value.FieldByName("ID")
+	if !id.IsValid() || !id.CanInt() || id.Int() == 0 {
+		return
+	}
+	if data, err := repo.Find(ctx, model.FindWithID, int(id.Int())); err == nil {
+		model.AuditBefore(ctx, data)
+	}
+}
@@ -0,0 +1,67 @@
+package audit_test
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/aasumitro/posbe/mocks"
+	"github.com/aasumitro/posbe/pkg/audit"
+	"github.com/aasumitro/posbe/pkg/model"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func auditedContext() (*gin.Context, *model.AuditEntry) {
+	ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+	entry := &model.AuditEntry{}
+	ctx.Set(model.AuditEntryKey, entry)
+	return ctx, entry
+}
+
+func TestTrack_Update_ShouldKeepBefore(t *testing.T) {
+	repo := new(mocks.ISoftDeleteRepository[model.Unit])
+	repo.On("Find", mock.Anything, model.FindWithID, 1).
+		Return(&model.Unit{ID: 1, Magnitude: "mass", Name: "gram", Symbol: "g"}, nil)
+	repo.On("Update", mock.Anything, mock.Anything).
+		Return(&model.Unit{ID: 1, Magnitude: "mass", Name: "kilogram", Symbol: "kg"}, nil)
+	ctx, entry := auditedContext()
+	_, err := audit.Track[model.Unit](repo).Update(ctx, &model.Unit{ID: 1, Name: "kilogram"})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"id":1,"magnitude":"mass","name":"gram","symbol":"g"}`, string(entry.Before))
+}
+
+func TestTrack_Delete_ShouldKeepFirstBefore(t *testing.T) {
+	repo := new(mocks.ICRUDAddOnRepository[model.Room])
+	repo.On("Find", mock.Anything, model.FindWithID, 1).
+		Return(&model.Room{ID: 1, Name: "vip"}, nil)
+	repo.On("Delete", mock.Anything, mock.Anything).Return(nil)
+	ctx, entry := auditedContext()
+	entry.Before = []byte(`{"id":1,"name":"first"}`)
+	require.NoError(t, audit.TrackAddOn[model.Room](repo).Delete(ctx, &model.Room{ID: 1}))
+	assert.JSONEq(t, `{"id":1,"name":"first"}`, string(entry.Before))
+}
+
+func TestTrack_ShouldNotFindWhenNotAudited(t *testing.T) {
+	repo := new(mocks.ICRUDWithSearchRepository[model.Product])
+	repo.On("Delete", mock.Anything, mock.Anything).Return(nil)
+	require.NoError(t, audit.TrackWithSearch[model.Product](repo).
+		Delete(context.TODO(), &model.Product{ID: 1}))
+	repo.AssertNotCalled(t, "Find", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestTrack_Restore_ShouldFindDeleted(t *testing.T) {
+	repo := new(mocks.ISoftDeleteRepository[model.Unit])
+	deletedAt := int64(123)
+	repo.On("Find", mock.MatchedBy(model.IncludeDeleted), model.FindWithID, 1).
+		Return(&model.Unit{ID: 1, Name: "gram", SoftDelete: model.SoftDelete{DeletedAt: &deletedAt}}, nil)
+	repo.On("Restore", mock.Anything, mock.Anything).
+		Return(&model.Unit{ID: 1, Name: "gram"}, nil)
+	ctx, entry := auditedContext()
+	_, err := audit.Track[model.Unit](repo).Restore(ctx, &model.Unit{ID: 1})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"id":1,"magnitude":"","name":"gram","symbol":"","deleted_at":123}`, string(entry.Before))
+	assert.False(t, model.IncludeDeleted(ctx))
+}
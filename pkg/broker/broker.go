@@ -0,0 +1,54 @@
+package broker
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/redis/go-redis/v9"
+)
+
+type (
+	// Broker fan out messages to every subscriber of the topic,
+	// used to push changes to the terminals
+	Broker interface {
+		Publish(ctx context.Context, topic string, payload any) error
+		// Subscribe deliver raw json messages until closeFn is called
+		// or the context is done
+		Subscribe(ctx context.Context, topic string) (messages <-chan []byte, closeFn func() error)
+	}
+
+	// RedisBroker use redis pub/sub, so every api instance
+	// behind the load balancer receive the message
+	RedisBroker struct {
+		Client *redis.Client
+	}
+)
+
+func (b *RedisBroker) Publish(ctx context.Context, topic string, payload any) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	return b.Client.Publish(ctx, topic, data).Err()
+}
+
+func (b *RedisBroker) Subscribe(ctx context.Context, topic string) (<-chan []byte, func() error) {
+	pubsub := b.Client.Subscribe(ctx, topic)
+	messages := make(chan []byte)
+	go func() {
+		defer close(messages)
+		for message := range pubsub.Channel() {
+			select {
+			case messages <- []byte(message.Payload):
+			case <-ctx.Done():
+				_ = pubsub.Close()
+				return
+			}
+		}
+	}()
+	return messages, pubsub.Close
+}
+
+func NewRedisBroker(client *redis.Client) Broker {
+	return &RedisBroker{Client: client}
+}
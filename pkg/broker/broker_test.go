@@ -0,0 +1,71 @@
+package broker_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aasumitro/posbe/pkg/broker"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRedisBroker_PublishSubscribe(t *testing.T) {
+	client := redis.NewClient(&redis.Options{Addr: miniredis.RunT(t).Addr()})
+	b := broker.NewRedisBroker(client)
+	messages, closeFn := b.Subscribe(context.TODO(), "topic")
+	// wait until the subscription is registered
+	require.Eventually(t, func() bool {
+		channels, _ := client.PubSubNumSub(context.TODO(), "topic").Result()
+		return channels["topic"] == 1
+	}, time.Second, 10*time.Millisecond)
+
+	require.NoError(t, b.Publish(context.TODO(), "topic", map[string]int{"id": 1}))
+	select {
+	case message := <-messages:
+		assert.JSONEq(t, `{"id":1}`, string(message))
+	case <-time.After(time.Second):
+		t.Fatal("message not received")
+	}
+
+	require.NoError(t, closeFn())
+	require.Eventually(t, func() bool {
+		_, ok := <-messages
+		return !ok
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestRedisBroker_PublishInvalidPayload(t *testing.T) {
+	client := redis.NewClient(&redis.Options{Addr: miniredis.RunT(t).Addr()})
+	b := broker.NewRedisBroker(client)
+	require.Error(t, b.Publish(context.TODO(), "topic", make(chan int)))
+}
+
+func TestMemoryBroker_PublishSubscribe(t *testing.T) {
+	b := broker.NewMemoryBroker()
+	ctx, cancel := context.WithCancel(context.TODO())
+	messages, closeFn := b.Subscribe(ctx, "topic")
+	other, closeOther := b.Subscribe(context.TODO(), "other")
+	defer func() { _ = closeOther() }()
+
+	require.NoError(t, b.Publish(context.TODO(), "topic", map[string]int{"id": 1}))
+	select {
+	case message := <-messages:
+		assert.JSONEq(t, `{"id":1}`, string(message))
+	case <-time.After(time.Second):
+		t.Fatal("message not received")
+	}
+	assert.Empty(t, other)
+
+	// cancel the context close the subscription, closeFn is still safe
+	cancel()
+	require.Eventually(t, func() bool {
+		_, ok := <-messages
+		return !ok
+	}, time.Second, 10*time.Millisecond)
+	require.NoError(t, closeFn())
+	require.NoError(t, b.Publish(context.TODO(), "topic", 1))
+	require.Error(t, b.Publish(context.TODO(), "topic", make(chan int)))
+}
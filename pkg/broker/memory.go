@@ -0,0 +1,80 @@
+package broker
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+)
+
+// subscriberBuffer is how many messages a slow subscriber may fall behind,
+// the next messages are dropped (same as a full redis pub/sub channel)
+const subscriberBuffer = 100
+
+type (
+	// MemoryBroker fan out the messages in the process,
+	// it only reach the terminals connected to this api instance
+	MemoryBroker struct {
+		mu          sync.RWMutex
+		subscribers map[string]map[*subscriber]struct{}
+	}
+
+	subscriber struct {
+		messages chan []byte
+		done     chan struct{}
+		once     sync.Once
+	}
+)
+
+func (b *MemoryBroker) Publish(_ context.Context, topic string, payload any) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	for sub := range b.subscribers[topic] {
+		select {
+		case sub.messages <- data:
+		default:
+		}
+	}
+	return nil
+}
+
+func (b *MemoryBroker) Subscribe(ctx context.Context, topic string) (<-chan []byte, func() error) {
+	sub := &subscriber{
+		messages: make(chan []byte, subscriberBuffer),
+		done:     make(chan struct{}),
+	}
+	b.mu.Lock()
+	if b.subscribers[topic] == nil {
+		b.subscribers[topic] = map[*subscriber]struct{}{}
+	}
+	b.subscribers[topic][sub] = struct{}{}
+	b.mu.Unlock()
+	closeFn := func() error {
+		sub.once.Do(func() {
+			b.mu.Lock()
+			delete(b.subscribers[topic], sub)
+			if len(b.subscribers[topic]) == 0 {
+				delete(b.subscribers, topic)
+			}
+			b.mu.Unlock()
+			close(sub.done)
+			close(sub.messages)
+		})
+		return nil
+	}
+	go func() {
+		select {
+		case <-ctx.Done():
+			_ = closeFn()
+		case <-sub.done:
+		}
+	}()
+	return sub.messages, closeFn
+}
+
+func NewMemoryBroker() Broker {
+	return &MemoryBroker{subscribers: map[string]map[*subscriber]struct{}{}}
+}
@@ -0,0 +1,106 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync/atomic"
+	"time"
+
+	"github.com/aasumitro/posbe/config"
+	"github.com/aasumitro/posbe/pkg/kv"
+	"golang.org/x/sync/singleflight"
+)
+
+const (
+	RolesKey      = "roles"
+	StorePrefsKey = "store_prefs"
+)
+
+// Policy is the ttl of each key, 0 keep the data until it is invalidated
+// by a write (the data is only changed through the services)
+var Policy = map[string]time.Duration{
+	RolesKey:      time.Hour * 1,
+	StorePrefsKey: 0,
+}
+
+type (
+	// Loader read the data from the repository on a cache miss
+	Loader[T any] func(ctx context.Context) (T, error)
+
+	// Cache keep a JSON value of T under a single key of the store,
+	// the data is always decoded to T whether it comes from the store
+	// or from the loader, the concurrent misses of an instance share
+	// a single load (stampede protection)
+	Cache[T any] struct {
+		key        string
+		ttl        time.Duration
+		group      singleflight.Group
+		generation atomic.Uint64
+	}
+)
+
+// New create a cache of the key with the ttl of its Policy
+func New[T any](key string) *Cache[T] {
+	return &Cache[T]{key: key, ttl: Policy[key]}
+}
+
+func (cache *Cache[T]) Key() string {
+	return cache.key
+}
+
+// Get return the cached data or load it, a store failure fall back to
+// the loader so the cache is never a reason for a request to fail
+func (cache *Cache[T]) Get(ctx context.Context, load Loader[T]) (data T, err error) {
+	store := config.KV
+	if store == nil {
+		return load(ctx)
+	}
+	value, err := store.Get(ctx, cache.key)
+	switch {
+	case err == nil:
+		if json.Unmarshal([]byte(value), &data) == nil {
+			return data, nil
+		}
+		// stale format (e.g. the type changed), it is replaced by the load
+	case !errors.Is(err, kv.ErrNil):
+		return load(ctx)
+	}
+	generation := cache.generation.Load()
+	result, err, _ := cache.group.Do(cache.key, func() (any, error) {
+		data, err := load(ctx)
+		// a write during the load make the data old, it is not kept
+		if err == nil && generation == cache.generation.Load() {
+			_ = cache.Set(ctx, data)
+		}
+		return data, err
+	})
+	data, _ = result.(T)
+	return data, err
+}
+
+// Set write the data through to the store
+func (cache *Cache[T]) Set(ctx context.Context, data T) error {
+	store := config.KV
+	if store == nil {
+		return nil
+	}
+	jsonData, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	return store.Set(ctx, cache.key, jsonData, cache.ttl)
+}
+
+// Invalidate delete the data after a write, the next Get load it again,
+// a load in flight is forgotten so it does not keep the old data
+func (cache *Cache[T]) Invalidate(ctx context.Context) error {
+	cache.generation.Add(1)
+	cache.group.Forget(cache.key)
+	store := config.KV
+	if store == nil {
+		return nil
+	}
+	_, err := store.Del(ctx, cache.key)
+	return err
+}
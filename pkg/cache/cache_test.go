@@ -0,0 +1,148 @@
+package cache_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/aasumitro/posbe/config"
+	"github.com/aasumitro/posbe/pkg/cache"
+	"github.com/aasumitro/posbe/pkg/kv"
+	"github.com/aasumitro/posbe/pkg/model"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testRoles = []*model.Role{{ID: 1, Name: "admin"}, {ID: 2, Name: "cashier"}}
+
+func newRedis(t *testing.T) *miniredis.Miniredis {
+	server := miniredis.RunT(t)
+	config.KV = kv.NewRedisStore(redis.NewClient(&redis.Options{Addr: server.Addr()}))
+	t.Cleanup(func() { config.KV = nil })
+	return server
+}
+
+func loadRoles(calls *atomic.Int32) cache.Loader[[]*model.Role] {
+	return func(_ context.Context) ([]*model.Role, error) {
+		calls.Add(1)
+		return testRoles, nil
+	}
+}
+
+func TestCache_Get_ShouldLoadOnMissAndDecodeOnHit(t *testing.T) {
+	server := newRedis(t)
+	var calls atomic.Int32
+	roleCache := cache.New[[]*model.Role](cache.RolesKey)
+	data, err := roleCache.Get(context.TODO(), loadRoles(&calls))
+	require.NoError(t, err)
+	assert.Equal(t, testRoles, data)
+	assert.Equal(t, cache.Policy[cache.RolesKey], server.TTL(cache.RolesKey))
+	// the hit return the same type as the miss
+	data, err = roleCache.Get(context.TODO(), loadRoles(&calls))
+	require.NoError(t, err)
+	assert.Equal(t, testRoles, data)
+	assert.Equal(t, int32(1), calls.Load())
+}
+
+func TestCache_Get_ShouldReloadStaleFormat(t *testing.T) {
+	server := newRedis(t)
+	require.NoError(t, server.Set(cache.RolesKey, `{"id":1}`))
+	var calls atomic.Int32
+	data, err := cache.New[[]*model.Role](cache.RolesKey).
+		Get(context.TODO(), loadRoles(&calls))
+	require.NoError(t, err)
+	assert.Equal(t, testRoles, data)
+	assert.Equal(t, int32(1), calls.Load())
+	value, _ := server.Get(cache.RolesKey)
+	assert.Contains(t, value, "cashier")
+}
+
+func TestCache_Get_ShouldNotKeepLoadError(t *testing.T) {
+	server := newRedis(t)
+	_, err := cache.New[[]*model.Role](cache.RolesKey).Get(context.TODO(),
+		func(_ context.Context) ([]*model.Role, error) {
+			return nil, errors.New("UNEXPECTED")
+		})
+	assert.EqualError(t, err, "UNEXPECTED")
+	assert.False(t, server.Exists(cache.RolesKey))
+}
+
+func TestCache_Get_ShouldFallbackToLoader(t *testing.T) {
+	var calls atomic.Int32
+	roleCache := cache.New[[]*model.Role](cache.RolesKey)
+	// no redis
+	data, err := roleCache.Get(context.TODO(), loadRoles(&calls))
+	require.NoError(t, err)
+	assert.Equal(t, testRoles, data)
+	// redis is down
+	server := newRedis(t)
+	server.Close()
+	data, err = roleCache.Get(context.TODO(), loadRoles(&calls))
+	require.NoError(t, err)
+	assert.Equal(t, testRoles, data)
+	assert.Equal(t, int32(2), calls.Load())
+}
+
+func TestCache_Get_ShouldLoadOnceOnConcurrentMiss(t *testing.T) {
+	newRedis(t)
+	var calls atomic.Int32
+	release := make(chan struct{})
+	roleCache := cache.New[[]*model.Role](cache.RolesKey)
+	load := func(_ context.Context) ([]*model.Role, error) {
+		calls.Add(1)
+		<-release
+		return testRoles, nil
+	}
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			data, err := roleCache.Get(context.TODO(), load)
+			assert.NoError(t, err)
+			assert.Equal(t, testRoles, data)
+		}()
+	}
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+	assert.Equal(t, int32(1), calls.Load())
+}
+
+func TestCache_Invalidate_ShouldDropDataOfLoadInFlight(t *testing.T) {
+	server := newRedis(t)
+	ctx := context.TODO()
+	roleCache := cache.New[[]*model.Role](cache.RolesKey)
+	loading, release := make(chan struct{}), make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_, _ = roleCache.Get(ctx, func(_ context.Context) ([]*model.Role, error) {
+			close(loading)
+			<-release
+			return testRoles, nil
+		})
+	}()
+	<-loading
+	require.NoError(t, roleCache.Invalidate(ctx))
+	close(release)
+	<-done
+	assert.False(t, server.Exists(cache.RolesKey))
+}
+
+func TestCache_Set_ShouldWriteThrough(t *testing.T) {
+	server := newRedis(t)
+	prefCache := cache.New[*model.StoreSetting](cache.StorePrefsKey)
+	require.NoError(t, prefCache.Set(context.TODO(), &model.StoreSetting{"currency": "IDR"}))
+	assert.Equal(t, time.Duration(0), server.TTL(cache.StorePrefsKey))
+	data, err := prefCache.Get(context.TODO(), func(_ context.Context) (*model.StoreSetting, error) {
+		return nil, errors.New("NOT_CALLED")
+	})
+	require.NoError(t, err)
+	assert.Equal(t, &model.StoreSetting{"currency": "IDR"}, data)
+}
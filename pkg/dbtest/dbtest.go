@@ -0,0 +1,114 @@
+// Package dbtest open a real database for the repository tests, the
+// repository suites run once per dialect and the tests that need the
+// database open it with Migrated(suite.T(), config.Dialect.Name())
+package dbtest
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/aasumitro/posbe/config"
+	"github.com/aasumitro/posbe/db"
+	"github.com/aasumitro/posbe/pkg/dialect"
+	"github.com/aasumitro/posbe/pkg/migrate"
+	"github.com/stretchr/testify/require"
+)
+
+// PostgresEnv is the dsn of a postgres the tests may use, every test get
+// its own schema that is dropped once it is done, the postgres tests are
+// skipped when it is empty
+const PostgresEnv = "POSBE_TEST_POSTGRES_DSN"
+
+// Open open an empty database of the dialect, a sqlite file in the temp dir
+// of the test or a new schema of the PostgresEnv database, with the same
+// pragmas as the server. It is set as config.DbPool and config.Dialect so
+// the repositories constructed after it use it, until the test end
+func Open(t testing.TB, name string) *sql.DB {
+	t.Helper()
+	d, err := dialect.New(name)
+	require.NoError(t, err)
+	var dsn string
+	switch name {
+	case dialect.SQLite:
+		dsn = config.SQLiteDsn(filepath.Join(t.TempDir(), "posbe.db"))
+	case dialect.Postgres:
+		dsn = postgresSchema(t)
+	}
+	conn, err := sql.Open(d.Name(), dsn)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+	require.NoError(t, conn.Ping())
+
+	pool, previous := config.DbPool, config.Dialect
+	t.Cleanup(func() { config.DbPool, config.Dialect = pool, previous })
+	config.DbPool, config.Dialect = conn, d
+	return conn
+}
+
+// Migrated is Open with the migrations and the seeds applied
+func Migrated(t testing.TB, name string) *sql.DB {
+	t.Helper()
+	conn := Open(t, name)
+	ctx := context.Background()
+	migrator, err := migrate.New(conn, config.Dialect, db.Migrations, db.MigrationsDir(config.Dialect))
+	require.NoError(t, err)
+	_, err = migrator.Up(ctx)
+	require.NoError(t, err)
+	_, err = migrate.Seed(ctx, conn, db.Seeds, db.SeedsDir)
+	require.NoError(t, err)
+	return conn
+}
+
+// SQLite open a migrated and seeded sqlite database
+func SQLite(t testing.TB) *sql.DB {
+	t.Helper()
+	return Migrated(t, dialect.SQLite)
+}
+
+// Postgres open a migrated and seeded postgres schema,
+// the test is skipped when PostgresEnv is empty
+func Postgres(t testing.TB) *sql.DB {
+	t.Helper()
+	return Migrated(t, dialect.Postgres)
+}
+
+// postgresSchema create the schema of the test and return
+// the dsn that use it, it is dropped on cleanup
+func postgresSchema(t testing.TB) string {
+	dsn := os.Getenv(PostgresEnv)
+	if dsn == "" {
+		t.Skipf("%s is empty", PostgresEnv)
+	}
+	suffix := make([]byte, 6)
+	_, err := rand.Read(suffix)
+	require.NoError(t, err)
+	schema := "posbe_test_" + hex.EncodeToString(suffix)
+
+	admin, err := sql.Open(dialect.Postgres, dsn)
+	require.NoError(t, err)
+	_, err = admin.Exec("CREATE SCHEMA " + schema)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_, _ = admin.Exec("DROP SCHEMA " + schema + " CASCADE")
+		_ = admin.Close()
+	})
+
+	// lib/pq send the unknown parameters of the dsn as run-time parameters
+	if strings.HasPrefix(dsn, "postgres://") || strings.HasPrefix(dsn, "postgresql://") {
+		u, err := url.Parse(dsn)
+		require.NoError(t, err)
+		query := u.Query()
+		query.Set("search_path", schema)
+		u.RawQuery = query.Encode()
+		return u.String()
+	}
+	return fmt.Sprintf("%s search_path=%s", dsn, schema)
+}
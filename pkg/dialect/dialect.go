@@ -0,0 +1,55 @@
+// Package dialect hold what differ between the supported databases,
+// the queries are otherwise written in the sql both of them understand
+// ($n placeholders, RETURNING and ON CONFLICT)
+package dialect
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+)
+
+const (
+	Postgres = "postgres"
+	SQLite   = "sqlite"
+)
+
+var ErrUnknown = errors.New("unknown database driver")
+
+// Dialect write the statements that are not portable,
+// Name is also the database/sql driver and the migrations dir
+type Dialect interface {
+	Name() string
+	// Array bind a slice (e.g: []string or []int) as one argument
+	Array(values any) driver.Valuer
+	// ScanArray read an Array or ArrayAgg column into dest (e.g: *[]string)
+	ScanArray(dest any) sql.Scanner
+	// Any match expr against the values of param bound with Array
+	Any(expr, param string) string
+	// ArrayAgg select column of the rows of query (FROM ... WHERE ...) as one sorted Array
+	ArrayAgg(column, query string) string
+	// SetActor keep the actor ($1, empty when none) read by the
+	// triggers that record entity versions, ResetActor clear it before commit
+	// and is empty when the actor end with the transaction
+	SetActor() string
+	ResetActor() string
+	// Lock hold conn until unlock so a single instance migrate at once
+	Lock(ctx context.Context, conn *sql.Conn, key int64) (unlock func(), err error)
+}
+
+// New return the dialect of the driver name (DB_DRIVER)
+func New(name string) (Dialect, error) {
+	switch name {
+	case Postgres:
+		return postgresDialect{}, nil
+	case SQLite:
+		return sqliteDialect{}, nil
+	}
+	return nil, ErrUnknown
+}
+
+// All is every supported dialect, the repository tests run against each of them
+func All() []Dialect {
+	return []Dialect{postgresDialect{}, sqliteDialect{}}
+}
@@ -0,0 +1,98 @@
+package dialect_test
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"testing"
+
+	"github.com/aasumitro/posbe/pkg/dialect"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	_ "modernc.org/sqlite"
+)
+
+func TestNew(t *testing.T) {
+	for _, name := range []string{dialect.Postgres, dialect.SQLite} {
+		d, err := dialect.New(name)
+		require.NoError(t, err)
+		assert.Equal(t, name, d.Name())
+	}
+	_, err := dialect.New("mysql")
+	require.ErrorIs(t, err, dialect.ErrUnknown)
+}
+
+func TestPostgres_Statements(t *testing.T) {
+	d, _ := dialect.New(dialect.Postgres)
+	assert.Equal(t, "p.id = ANY($1)", d.Any("p.id", "$1"))
+	assert.Equal(t, "ARRAY(SELECT name FROM permissions ORDER BY name)",
+		d.ArrayAgg("name", "FROM permissions"))
+	assert.Empty(t, d.ResetActor())
+	value, err := d.Array([]string{"a", "b"}).Value()
+	require.NoError(t, err)
+	assert.Equal(t, `{"a","b"}`, value)
+}
+
+func TestSQLite_Array(t *testing.T) {
+	d, _ := dialect.New(dialect.SQLite)
+	value, err := d.Array([]int{3, 1}).Value()
+	require.NoError(t, err)
+	assert.Equal(t, "[3,1]", value)
+	value, err = d.Array([]string(nil)).Value()
+	require.NoError(t, err)
+	assert.Equal(t, "[]", value)
+
+	var names []string
+	require.NoError(t, d.ScanArray(&names).Scan([]byte(`["a","b"]`)))
+	assert.Equal(t, []string{"a", "b"}, names)
+	require.NoError(t, d.ScanArray(&names).Scan(nil))
+	assert.Empty(t, names)
+	require.Error(t, d.ScanArray(&names).Scan(1))
+}
+
+func TestSQLite_Statements(t *testing.T) {
+	d, _ := dialect.New(dialect.SQLite)
+	db, err := sql.Open(d.Name(), ":memory:")
+	require.NoError(t, err)
+	defer func() { _ = db.Close() }()
+	ctx := context.Background()
+	_, err = db.ExecContext(ctx, `
+		CREATE TABLE items (id INTEGER PRIMARY KEY, name TEXT NOT NULL);
+		INSERT INTO items (id, name) VALUES (1, 'tea'), (2, 'coffee'), (3, 'juice');
+		CREATE TABLE audit_actor (id INTEGER PRIMARY KEY, actor_id INTEGER);`)
+	require.NoError(t, err)
+
+	q := fmt.Sprintf("SELECT id FROM items WHERE %s ORDER BY id", d.Any("id", "$1"))
+	rows, err := db.QueryContext(ctx, q, d.Array([]int{3, 1}))
+	require.NoError(t, err)
+	var ids []int
+	for rows.Next() {
+		var id int
+		require.NoError(t, rows.Scan(&id))
+		ids = append(ids, id)
+	}
+	require.NoError(t, rows.Err())
+	assert.Equal(t, []int{1, 3}, ids)
+
+	var names []string
+	q = "SELECT " + d.ArrayAgg("name", "FROM items WHERE id > 1")
+	require.NoError(t, db.QueryRowContext(ctx, q).Scan(d.ScanArray(&names)))
+	assert.Equal(t, []string{"coffee", "juice"}, names)
+
+	var actor sql.NullInt64
+	_, err = db.ExecContext(ctx, d.SetActor(), "7")
+	require.NoError(t, err)
+	require.NoError(t, db.QueryRowContext(ctx, "SELECT actor_id FROM audit_actor").Scan(&actor))
+	assert.Equal(t, sql.NullInt64{Int64: 7, Valid: true}, actor)
+	_, err = db.ExecContext(ctx, d.ResetActor())
+	require.NoError(t, err)
+	require.NoError(t, db.QueryRowContext(ctx, "SELECT actor_id FROM audit_actor").Scan(&actor))
+	assert.False(t, actor.Valid)
+
+	conn, err := db.Conn(ctx)
+	require.NoError(t, err)
+	defer func() { _ = conn.Close() }()
+	unlock, err := d.Lock(ctx, conn, 1)
+	require.NoError(t, err)
+	unlock()
+}
@@ -0,0 +1,51 @@
+package dialect
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+
+	"github.com/lib/pq"
+)
+
+type postgresDialect struct{}
+
+func (postgresDialect) Name() string {
+	return Postgres
+}
+
+func (postgresDialect) Array(values any) driver.Valuer {
+	return pq.Array(values).(driver.Valuer)
+}
+
+func (postgresDialect) ScanArray(dest any) sql.Scanner {
+	return pq.Array(dest).(sql.Scanner)
+}
+
+func (postgresDialect) Any(expr, param string) string {
+	return fmt.Sprintf("%s = ANY(%s)", expr, param)
+}
+
+func (postgresDialect) ArrayAgg(column, query string) string {
+	return fmt.Sprintf("ARRAY(SELECT %s %s ORDER BY %s)", column, query, column)
+}
+
+// SetActor is local to the transaction, there is nothing to reset
+func (postgresDialect) SetActor() string {
+	return "SELECT set_config('posbe.actor_id', $1, true)"
+}
+
+func (postgresDialect) ResetActor() string {
+	return ""
+}
+
+// Lock take the advisory lock, the other instances wait until unlock
+func (postgresDialect) Lock(ctx context.Context, conn *sql.Conn, key int64) (unlock func(), err error) {
+	if _, err := conn.ExecContext(ctx, "SELECT pg_advisory_lock($1)", key); err != nil {
+		return nil, err
+	}
+	return func() {
+		_, _ = conn.ExecContext(context.Background(), "SELECT pg_advisory_unlock($1)", key)
+	}, nil
+}
@@ -0,0 +1,76 @@
+package dialect
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+)
+
+type sqliteDialect struct{}
+
+// jsonArray is an array kept as json text, sqlite has no array type
+type jsonArray struct {
+	value any
+}
+
+func (array jsonArray) Value() (driver.Value, error) {
+	data, err := json.Marshal(array.value)
+	if err != nil {
+		return nil, err
+	}
+	if string(data) == "null" {
+		return "[]", nil
+	}
+	return string(data), nil
+}
+
+func (array jsonArray) Scan(src any) error {
+	switch v := src.(type) {
+	case nil:
+		// null reset the slice like pq.Array does
+		return json.Unmarshal([]byte("null"), array.value)
+	case []byte:
+		return json.Unmarshal(v, array.value)
+	case string:
+		return json.Unmarshal([]byte(v), array.value)
+	}
+	return fmt.Errorf("dialect: cannot scan %T into json array", src)
+}
+
+func (sqliteDialect) Name() string {
+	return SQLite
+}
+
+func (sqliteDialect) Array(values any) driver.Valuer {
+	return jsonArray{value: values}
+}
+
+func (sqliteDialect) ScanArray(dest any) sql.Scanner {
+	return jsonArray{value: dest}
+}
+
+func (sqliteDialect) Any(expr, param string) string {
+	return fmt.Sprintf("%s IN (SELECT value FROM json_each(%s))", expr, param)
+}
+
+func (sqliteDialect) ArrayAgg(column, query string) string {
+	return fmt.Sprintf("(SELECT json_group_array(%s ORDER BY %s) %s)", column, column, query)
+}
+
+// SetActor write the single row of audit_actor, the writes are serialized
+// (_txlock=immediate) so the row belong to the running transaction
+func (sqliteDialect) SetActor() string {
+	return "INSERT INTO audit_actor (id, actor_id) VALUES (1, NULLIF($1, '')) " +
+		"ON CONFLICT (id) DO UPDATE SET actor_id = EXCLUDED.actor_id"
+}
+
+func (sqliteDialect) ResetActor() string {
+	return "UPDATE audit_actor SET actor_id = NULL WHERE id = 1"
+}
+
+// Lock is a no-op, the database file belong to a single instance
+func (sqliteDialect) Lock(context.Context, *sql.Conn, int64) (unlock func(), err error) {
+	return func() {}, nil
+}
@@ -1,38 +1,162 @@
 package middleware
 
 import (
+	"context"
 	"net/http"
+	"strings"
 
 	"github.com/aasumitro/posbe/config"
+	"github.com/aasumitro/posbe/pkg/model"
 	"github.com/aasumitro/posbe/pkg/utils"
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v5"
 )
 
-// Auth expected tobe logged in
+// SessionValidator reject the token of a revoked session, and of a terminal
+// session that is idle or replaced by another user, terminalID is 0 when
+// the user did not log in on a terminal
+type SessionValidator func(ctx context.Context, terminalID int, sessionID string) *utils.ServiceError
+
+var validateSession SessionValidator
+
+// SetSessionValidator is called once by the account module at boot
+func SetSessionValidator(validator SessionValidator) {
+	validateSession = validator
+}
+
+// APIKeyResolver resolve the active api key of the X-API-Key header
+type APIKeyResolver func(ctx context.Context, key string) (apiKey *model.APIKey, errData *utils.ServiceError)
+
+var resolveAPIKey APIKeyResolver
+
+// SetAPIKeyResolver is called once by the account module at boot
+func SetAPIKeyResolver(resolver APIKeyResolver) {
+	resolveAPIKey = resolver
+}
+
+// Auth expected tobe logged in, with the access token as bearer
+// Authorization header or jwt cookie, or with an api key as X-API-Key header
 func Auth() gin.HandlerFunc {
 	return func(context *gin.Context) {
-		tokenCookie, err := context.Request.Cookie("jwt")
+		if key := context.GetHeader("X-API-Key"); key != "" {
+			authenticateAPIKey(context, key)
+			return
+		}
+		accessToken, err := bearerToken(context)
 		if err != nil {
 			context.AbortWithStatusJSON(http.StatusUnauthorized, err.Error())
 			return
 		}
 		token, err := jwt.ParseWithClaims(
-			tokenCookie.Value,
+			accessToken,
 			&utils.JWTClaim{},
 			func(_ *jwt.Token) (interface{}, error) {
 				return []byte(config.Instance.JWTSecretKey), nil
 			})
-		if err != nil && !token.Valid {
-			context.AbortWithStatusJSON(http.StatusUnauthorized, err.Error())
+		// token is nil when the access token is malformed
+		if err != nil || token == nil || !token.Valid {
+			message := "INVALID_TOKEN"
+			if err != nil {
+				message = err.Error()
+			}
+			context.AbortWithStatusJSON(http.StatusUnauthorized, message)
 			return
 		}
 		claims, ok := token.Claims.(*utils.JWTClaim)
 		if !ok {
-			context.AbortWithStatusJSON(http.StatusUnauthorized, err.Error())
+			context.AbortWithStatusJSON(http.StatusUnauthorized, "INVALID_TOKEN")
 			return
 		}
 		context.Set("payload", claims.Payload)
+		if validateSession != nil {
+			if errData := validateSession(context,
+				PayloadTerminalID(context), PayloadSessionID(context),
+			); errData != nil {
+				context.AbortWithStatusJSON(errData.Code, errData.Message)
+				return
+			}
+		}
 		context.Next()
 	}
 }
+
+// bearerToken read the Authorization header, fallback to the jwt cookie
+func bearerToken(context *gin.Context) (string, error) {
+	if scheme, token, ok := strings.Cut(context.GetHeader("Authorization"), " "); ok &&
+		strings.EqualFold(scheme, "Bearer") && token != "" {
+		return token, nil
+	}
+	cookie, err := context.Request.Cookie("jwt")
+	if err != nil {
+		return "", err
+	}
+	return cookie.Value, nil
+}
+
+// authenticateAPIKey log the integration in, it is only granted the key scopes
+func authenticateAPIKey(context *gin.Context, key string) {
+	if resolveAPIKey == nil {
+		context.AbortWithStatusJSON(http.StatusUnauthorized, "INVALID_API_KEY")
+		return
+	}
+	apiKey, errData := resolveAPIKey(context, key)
+	if errData != nil {
+		context.AbortWithStatusJSON(errData.Code, errData.Message)
+		return
+	}
+	// numbers are float64 like the payload decoded from jwt
+	context.Set("payload", map[string]interface{}{
+		"api_key_id": float64(apiKey.ID),
+		"name":       apiKey.Name,
+	})
+	context.Set("scopes", apiKey.Scopes)
+	context.Next()
+}
+
+// PayloadUserID read the logged in user id from the jwt payload, 0 when missing
+func PayloadUserID(context *gin.Context) int {
+	return payloadInt(context, "id")
+}
+
+// PayloadTerminalID read the terminal the user logged in on, 0 when missing
+func PayloadTerminalID(context *gin.Context) int {
+	return payloadInt(context, "terminal_id")
+}
+
+// PayloadAPIKeyID read the api key the integration logged in with, 0 when missing
+func PayloadAPIKeyID(context *gin.Context) int {
+	return payloadInt(context, "api_key_id")
+}
+
+// PayloadSessionID read the session of the access token, empty when missing
+func PayloadSessionID(context *gin.Context) string {
+	sessionID, _ := payloadValue(context, "session_id").(string)
+	return sessionID
+}
+
+func payloadInt(context *gin.Context, key string) int {
+	value, _ := payloadValue(context, key).(float64)
+	return int(value)
+}
+
+func payloadValue(context *gin.Context, key string) interface{} {
+	payload, ok := context.Get("payload")
+	if !ok {
+		return nil
+	}
+	user, ok := payload.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	return user[key]
+}
+
+// PayloadRoleID read the logged in user role id from the jwt payload, 0 when missing
+func PayloadRoleID(context *gin.Context) int {
+	role, ok := payloadValue(context, "role").(map[string]interface{})
+	if !ok {
+		return 0
+	}
+	id, _ := role["id"].(float64)
+	return int(id)
+}
@@ -0,0 +1,60 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/aasumitro/posbe/config"
+	"github.com/aasumitro/posbe/pkg/http/middleware"
+	"github.com/aasumitro/posbe/pkg/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func authRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	config.Instance = &config.Config{JWTSecretKey: "secret"}
+	router := gin.New()
+	router.GET("/me", middleware.Auth(), func(ctx *gin.Context) {
+		ctx.JSON(http.StatusOK, middleware.PayloadUserID(ctx))
+	})
+	return router
+}
+
+func TestAuth_ShouldRejectInvalidToken(t *testing.T) {
+	router := authRouter()
+	expired, _ := (&utils.JSONWebToken{
+		Issuer: "POSBE", SecretKey: []byte("secret"), Lifetime: time.Hour,
+		Now: func() time.Time { return time.Now().Add(-2 * time.Hour) },
+	}).ClaimJWTToken(map[string]interface{}{"id": 1})
+	for _, header := range []string{
+		"",
+		"Bearer garbage",
+		"Bearer a.b",
+		"Bearer a.b.c",
+		"Bearer " + expired,
+	} {
+		writer := httptest.NewRecorder()
+		request := httptest.NewRequest(http.MethodGet, "/me", nil)
+		request.Header.Set("Authorization", header)
+		router.ServeHTTP(writer, request)
+		assert.Equal(t, http.StatusUnauthorized, writer.Code, header)
+	}
+}
+
+func TestAuth_ShouldAcceptBearerToken(t *testing.T) {
+	router := authRouter()
+	token, err := (&utils.JSONWebToken{
+		Issuer: "POSBE", SecretKey: []byte("secret"), Lifetime: time.Hour,
+		Now: time.Now,
+	}).ClaimJWTToken(map[string]interface{}{"id": 1})
+	assert.NoError(t, err)
+	writer := httptest.NewRecorder()
+	request := httptest.NewRequest(http.MethodGet, "/me", nil)
+	request.Header.Set("Authorization", "Bearer "+token)
+	router.ServeHTTP(writer, request)
+	assert.Equal(t, http.StatusOK, writer.Code)
+	assert.Equal(t, "1", writer.Body.String())
+}
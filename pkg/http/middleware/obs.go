@@ -1,24 +1,134 @@
 package middleware
 
 import (
-	"fmt"
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"strings"
 	"time"
 
-	"github.com/aasumitro/posbe/config"
+	"github.com/aasumitro/posbe/common"
+	"github.com/aasumitro/posbe/pkg/model"
 	"github.com/gin-gonic/gin"
 )
 
-// ActivityObserver TODO OBSERVE USER ACTIVITY
+const auditBasePath = "/api/v1/"
+
+var auditActions = map[string]string{
+	http.MethodPost:   model.AuditActionCreate,
+	http.MethodPut:    model.AuditActionUpdate,
+	http.MethodPatch:  model.AuditActionUpdate,
+	http.MethodDelete: model.AuditActionDelete,
+}
+
+// AuditRecorder queue the audit entry of the request
+type AuditRecorder func(entry *model.AuditEntry)
+
+var recordAudit AuditRecorder
+
+// SetAuditRecorder is called once by the audit module at boot
+func SetAuditRecorder(recorder AuditRecorder) {
+	recordAudit = recorder
+}
+
+// auditWriter keep the head of the respond, the data is the after state
+type auditWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (writer *auditWriter) Write(data []byte) (int, error) {
+	if room := common.AuditBodyLimit - writer.body.Len(); room > 0 {
+		writer.body.Write(data[:min(room, len(data))])
+	}
+	return writer.ResponseWriter.Write(data)
+}
+
+func (writer *auditWriter) WriteString(data string) (int, error) {
+	return writer.Write([]byte(data))
+}
+
+// ActivityObserver record every mutating request (POST, PUT, PATCH and DELETE)
+// to the audit log, it does not block the request, must be used after Auth
 func ActivityObserver() gin.HandlerFunc {
 	return func(context *gin.Context) {
-		payload := context.MustGet("payload")
-		user := payload.(map[string]interface{})
-		_, _ = config.PostgresPool.QueryContext(context,
-			"INSERT INTO activity_logs (user_id, description) values ($1, $2)",
-			user["id"], fmt.Sprintf("[%s] | [%s]::%s | [%.0f]%s",
-				time.Now().Format("2006-01-02 15:04:05"),
-				context.Request.Method, context.Request.URL,
-				user["id"], user["username"],
-			))
+		action, ok := auditActions[context.Request.Method]
+		if !ok || recordAudit == nil {
+			context.Next()
+			return
+		}
+		route := context.FullPath()
+		if strings.HasSuffix(route, "/restore") {
+			action = model.AuditActionRestore
+		}
+		entry := &model.AuditEntry{
+			ActorID:    PayloadUserID(context),
+			APIKeyID:   PayloadAPIKeyID(context),
+			TerminalID: PayloadTerminalID(context),
+			SessionID:  PayloadSessionID(context),
+			Action:     action,
+			Method:     context.Request.Method,
+			Route:      route,
+			EntityType: auditEntityType(route),
+			EntityID:   context.Param("id"),
+			IP:         context.ClientIP(),
+			CreatedAt:  time.Now().Unix(),
+		}
+		context.Set(model.AuditEntryKey, entry)
+		writer := &auditWriter{ResponseWriter: context.Writer}
+		context.Writer = writer
+		context.Next()
+		if context.GetBool("audit_skip") {
+			return
+		}
+		entry.Status = writer.Status()
+		if entry.Status < http.StatusMultipleChoices {
+			entry.After = respondData(writer.body.Bytes())
+		}
+		if entry.EntityID == "" {
+			entry.EntityID = dataID(entry.After)
+		}
+		recordAudit(entry)
+	}
+}
+
+// SkipAudit mark POST route that change nothing (e.g. calculation),
+// so it is not recorded by ActivityObserver
+func SkipAudit() gin.HandlerFunc {
+	return func(context *gin.Context) {
+		context.Set("audit_skip", true)
+		context.Next()
+	}
+}
+
+// auditEntityType is the route before the first param,
+// e.g. /api/v1/products/:id/images/:image_id is products
+func auditEntityType(route string) string {
+	route = strings.TrimPrefix(route, auditBasePath)
+	if i := strings.Index(route, "/:"); i >= 0 {
+		route = route[:i]
+	}
+	return strings.Trim(route, "/")
+}
+
+func respondData(body []byte) json.RawMessage {
+	var respond struct {
+		Data json.RawMessage `json:"data"`
+	}
+	if err := json.Unmarshal(body, &respond); err != nil ||
+		len(respond.Data) == 0 || string(respond.Data) == "null" {
+		return nil
+	}
+	return respond.Data
+}
+
+// dataID read the id of the created row
+func dataID(data json.RawMessage) string {
+	var row struct {
+		ID json.Number `json:"id"`
+	}
+	if len(data) == 0 || json.Unmarshal(data, &row) != nil {
+		return ""
 	}
+	return row.ID.String()
 }
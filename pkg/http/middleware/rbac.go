@@ -1,25 +1,139 @@
 package middleware
 
 import (
+	"context"
 	"net/http"
 	"slices"
+	"strconv"
 
+	"github.com/aasumitro/posbe/pkg/model"
+	"github.com/aasumitro/posbe/pkg/utils"
 	"github.com/gin-gonic/gin"
 )
 
-// AcceptedRoles expected to be needed role
-func AcceptedRoles(accepted []string) gin.HandlerFunc {
+// PermissionResolver return the permissions granted to a role
+type PermissionResolver func(ctx context.Context, roleID int) (permissions []string, errData *utils.ServiceError)
+
+var rolePermissions PermissionResolver
+
+// SetPermissionResolver is called once by the account module at boot
+func SetPermissionResolver(resolver PermissionResolver) {
+	rolePermissions = resolver
+}
+
+// grantedPermissions return the scopes of the api key,
+// or the permissions of the logged-in user role
+func grantedPermissions(context *gin.Context) ([]string, *utils.ServiceError) {
+	if scopes, ok := context.Get("scopes"); ok {
+		granted, _ := scopes.([]string)
+		return granted, nil
+	}
+	if rolePermissions == nil {
+		return nil, nil
+	}
+	return rolePermissions(context, PayloadRoleID(context))
+}
+
+// Permitted expected the logged-in user role (or api key) to be granted
+// the permission, must be used after Auth
+func Permitted(permission string) gin.HandlerFunc {
+	return func(context *gin.Context) {
+		permissions, errData := grantedPermissions(context)
+		if errData != nil {
+			context.AbortWithStatusJSON(errData.Code, errData.Message)
+			return
+		}
+		if !slices.Contains(permissions, permission) {
+			context.AbortWithStatusJSON(http.StatusForbidden,
+				"PERMISSION_DENIED")
+			return
+		}
+		context.Next()
+	}
+}
+
+// Granted tell whether the logged-in user role (or api key) is granted the
+// permission, for the handlers that check only a part of the request
+func Granted(context *gin.Context, permission string) bool {
+	permissions, errData := grantedPermissions(context)
+	return errData == nil && slices.Contains(permissions, permission)
+}
+
+// GrantedPermissions return the permissions of the logged-in user role (or
+// the scopes of the api key), for the handlers that hand them to a service
+func GrantedPermissions(context *gin.Context) ([]string, *utils.ServiceError) {
+	return grantedPermissions(context)
+}
+
+// IncludeDeleted let the list and find of the request read deleted rows when
+// it ask for ?include_deleted=true, the user role (or api key) must be granted
+// PermissionTrashRestore, must be used after Auth
+func IncludeDeleted() gin.HandlerFunc {
+	return func(context *gin.Context) {
+		include, _ := strconv.ParseBool(context.Query(model.IncludeDeletedKey))
+		if !include {
+			context.Next()
+			return
+		}
+		permissions, errData := grantedPermissions(context)
+		if errData != nil {
+			context.AbortWithStatusJSON(errData.Code, errData.Message)
+			return
+		}
+		if !slices.Contains(permissions, model.PermissionTrashRestore) {
+			context.AbortWithStatusJSON(http.StatusForbidden,
+				"PERMISSION_DENIED")
+			return
+		}
+		context.Set(model.IncludeDeletedKey, true)
+		context.Next()
+	}
+}
+
+// OverrideConsumer mark the override token of the action as used
+type OverrideConsumer func(ctx context.Context, token, action string, usedBy int) (override *model.Override, errData *utils.ServiceError)
+
+var consumeOverride OverrideConsumer
+
+// SetOverrideConsumer is called once by the account module at boot
+func SetOverrideConsumer(consumer OverrideConsumer) {
+	consumeOverride = consumer
+}
+
+// Approved let the user through when its role is granted the action, otherwise
+// the X-Override-Token header must carry an override approved for the action.
+// The approver (the user itself when granted) is read with ApproverID,
+// must be used after Auth
+func Approved(action string) gin.HandlerFunc {
 	return func(context *gin.Context) {
-		if len(accepted) > 0 && accepted[0] != "*" {
-			payload := context.MustGet("payload")
-			role := payload.(map[string]interface{})["role"]
-			name := role.(map[string]interface{})["name"]
-			if !slices.Contains(accepted, name.(string)) {
-				context.AbortWithStatusJSON(http.StatusUnauthorized,
-					"USER_NOT_AUTHORIZED")
-				return
-			}
+		userID := PayloadUserID(context)
+		permissions, errData := grantedPermissions(context)
+		if errData != nil {
+			context.AbortWithStatusJSON(errData.Code, errData.Message)
+			return
+		}
+		if slices.Contains(permissions, action) {
+			context.Set("approver_id", userID)
+			context.Next()
+			return
 		}
+		token := context.GetHeader("X-Override-Token")
+		if token == "" || consumeOverride == nil {
+			context.AbortWithStatusJSON(http.StatusForbidden,
+				"OVERRIDE_REQUIRED")
+			return
+		}
+		override, errData := consumeOverride(context, token, action, userID)
+		if errData != nil {
+			context.AbortWithStatusJSON(errData.Code, errData.Message)
+			return
+		}
+		context.Set("approver_id", override.ApprovedBy)
 		context.Next()
 	}
 }
+
+// ApproverID read the user who approved the action, 0 when not approved
+func ApproverID(context *gin.Context) int {
+	return context.GetInt("approver_id")
+}
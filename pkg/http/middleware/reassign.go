@@ -0,0 +1,29 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/aasumitro/posbe/pkg/model"
+	"github.com/gin-gonic/gin"
+)
+
+// ReassignTo keep the ?reassign_to=<id> of a delete request, the references of
+// the deleted row are moved to that row before the delete (see reference.Delete)
+func ReassignTo() gin.HandlerFunc {
+	return func(context *gin.Context) {
+		value := context.Query(model.ReassignToKey)
+		if value == "" || context.Request.Method != http.MethodDelete {
+			context.Next()
+			return
+		}
+		id, err := strconv.Atoi(value)
+		if err != nil || id < 1 {
+			context.AbortWithStatusJSON(http.StatusBadRequest,
+				"INVALID_REASSIGN_TO")
+			return
+		}
+		context.Set(model.ReassignToKey, id)
+		context.Next()
+	}
+}
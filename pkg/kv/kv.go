@@ -0,0 +1,51 @@
+package kv
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// Store is the key value backend of the sessions, terminals and cached data,
+// redis share it between the api instances while memory keep it in the
+// process (a single till shop), a ttl of 0 keep the key until it is deleted
+type Store interface {
+	// Get return ErrNil when the key does not exist
+	Get(ctx context.Context, key string) (value string, err error)
+	Set(ctx context.Context, key string, value any, ttl time.Duration) error
+	// SetNX set the value only when the key does not exist
+	SetNX(ctx context.Context, key string, value any, ttl time.Duration) (ok bool, err error)
+	// Incr add one to the counter, the ttl is only set when the counter is
+	// created so it count the hits of a fixed window (e.g: failed logins)
+	Incr(ctx context.Context, key string, ttl time.Duration) (count int64, err error)
+	Del(ctx context.Context, keys ...string) (deleted int64, err error)
+	Exists(ctx context.Context, key string) (ok bool, err error)
+	Expire(ctx context.Context, key string, ttl time.Duration) error
+	// Keys return the keys that match the pattern (e.g. table_*_status)
+	Keys(ctx context.Context, pattern string) (keys []string, err error)
+	// HSet set the fields (name and value pairs) and the ttl of the hash at once
+	HSet(ctx context.Context, key string, ttl time.Duration, fields ...any) error
+	// HGetAll return an empty map when the key does not exist
+	HGetAll(ctx context.Context, key string) (fields map[string]string, err error)
+	// HCompareAndSet set the fields and the ttl of the hash only when its field
+	// still hold the expected value, checked and set at once (e.g: rotate a
+	// token that two requests try to use), ok is false when the key does not exist
+	HCompareAndSet(ctx context.Context, key string, ttl time.Duration,
+		field, expected string, fields ...any) (ok bool, err error)
+	// SAdd add the members and set the ttl of the set at once
+	SAdd(ctx context.Context, key string, ttl time.Duration, members ...any) error
+	SMembers(ctx context.Context, key string) (members []string, err error)
+	SRem(ctx context.Context, key string, members ...any) error
+	Ping(ctx context.Context) error
+	Close() error
+}
+
+const (
+	DriverRedis  = "redis"
+	DriverMemory = "memory"
+)
+
+var (
+	ErrNil       = errors.New("kv: key does not exist")
+	ErrWrongType = errors.New("kv: operation against a key holding the wrong kind of value")
+)
@@ -0,0 +1,192 @@
+package kv_test
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/aasumitro/posbe/pkg/kv"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stores run the same tests on every driver, so memory behave as redis
+func stores(t *testing.T) map[string]kv.Store {
+	memory := kv.NewMemoryStore()
+	t.Cleanup(func() { _ = memory.Close() })
+	client := redis.NewClient(&redis.Options{Addr: miniredis.RunT(t).Addr()})
+	return map[string]kv.Store{
+		kv.DriverMemory: memory,
+		kv.DriverRedis:  kv.NewRedisStore(client),
+	}
+}
+
+func TestStore_String(t *testing.T) {
+	ctx := context.TODO()
+	for driver, store := range stores(t) {
+		t.Run(driver, func(t *testing.T) {
+			_, err := store.Get(ctx, "lorem")
+			assert.ErrorIs(t, err, kv.ErrNil)
+			require.NoError(t, store.Set(ctx, "lorem", []byte(`{"id":1}`), 0))
+			value, err := store.Get(ctx, "lorem")
+			require.NoError(t, err)
+			assert.Equal(t, `{"id":1}`, value)
+
+			ok, err := store.SetNX(ctx, "lorem", "ipsum", 0)
+			require.NoError(t, err)
+			assert.False(t, ok)
+			ok, err = store.SetNX(ctx, "table_1_status", 0, 0)
+			require.NoError(t, err)
+			assert.True(t, ok)
+			value, _ = store.Get(ctx, "table_1_status")
+			assert.Equal(t, "0", value)
+
+			exists, err := store.Exists(ctx, "lorem")
+			require.NoError(t, err)
+			assert.True(t, exists)
+			deleted, err := store.Del(ctx, "lorem", "missing")
+			require.NoError(t, err)
+			assert.Equal(t, int64(1), deleted)
+			exists, _ = store.Exists(ctx, "lorem")
+			assert.False(t, exists)
+		})
+	}
+}
+
+func TestStore_Incr(t *testing.T) {
+	ctx := context.TODO()
+	for driver, store := range stores(t) {
+		t.Run(driver, func(t *testing.T) {
+			count, err := store.Incr(ctx, "badge_failures", time.Hour)
+			require.NoError(t, err)
+			assert.Equal(t, int64(1), count)
+			count, err = store.Incr(ctx, "badge_failures", 0)
+			require.NoError(t, err)
+			assert.Equal(t, int64(2), count)
+			value, _ := store.Get(ctx, "badge_failures")
+			assert.Equal(t, "2", value)
+
+			require.NoError(t, store.Set(ctx, "lorem", "ipsum", 0))
+			_, err = store.Incr(ctx, "lorem", 0)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestStore_Keys(t *testing.T) {
+	ctx := context.TODO()
+	for driver, store := range stores(t) {
+		t.Run(driver, func(t *testing.T) {
+			for _, key := range []string{"roles", "table_1_status", "table_2_status", "room_1_status"} {
+				require.NoError(t, store.Set(ctx, key, 0, 0))
+			}
+			keys, err := store.Keys(ctx, "table_*_status")
+			require.NoError(t, err)
+			sort.Strings(keys)
+			assert.Equal(t, []string{"table_1_status", "table_2_status"}, keys)
+		})
+	}
+}
+
+func TestStore_Hash(t *testing.T) {
+	ctx := context.TODO()
+	for driver, store := range stores(t) {
+		t.Run(driver, func(t *testing.T) {
+			fields, err := store.HGetAll(ctx, "session")
+			require.NoError(t, err)
+			assert.Empty(t, fields)
+			require.NoError(t, store.HSet(ctx, "session", time.Hour,
+				"user_id", 1, "device", "pos"))
+			require.NoError(t, store.HSet(ctx, "session", 0, "device", "tablet"))
+			fields, err = store.HGetAll(ctx, "session")
+			require.NoError(t, err)
+			assert.Equal(t, map[string]string{"user_id": "1", "device": "tablet"}, fields)
+
+			require.NoError(t, store.Set(ctx, "lorem", "ipsum", 0))
+			_, err = store.HGetAll(ctx, "lorem")
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestStore_HCompareAndSet(t *testing.T) {
+	ctx := context.TODO()
+	for driver, store := range stores(t) {
+		t.Run(driver, func(t *testing.T) {
+			ok, err := store.HCompareAndSet(ctx, "session", time.Hour, "refresh", "a", "refresh", "b")
+			require.NoError(t, err)
+			assert.False(t, ok)
+			require.NoError(t, store.HSet(ctx, "session", time.Hour, "user_id", 1, "refresh", "a"))
+			ok, err = store.HCompareAndSet(ctx, "session", time.Hour, "refresh", "b", "refresh", "c")
+			require.NoError(t, err)
+			assert.False(t, ok)
+
+			// only one of the requests that saw the same value set it
+			var wg sync.WaitGroup
+			var set atomic.Int32
+			for i := 0; i < 10; i++ {
+				wg.Add(1)
+				go func(i int) {
+					defer wg.Done()
+					ok, err := store.HCompareAndSet(ctx, "session", time.Hour,
+						"refresh", "a", "refresh", fmt.Sprintf("b%d", i), "device", "pos")
+					assert.NoError(t, err)
+					if ok {
+						set.Add(1)
+					}
+				}(i)
+			}
+			wg.Wait()
+			assert.Equal(t, int32(1), set.Load())
+			fields, err := store.HGetAll(ctx, "session")
+			require.NoError(t, err)
+			assert.Equal(t, "1", fields["user_id"])
+			assert.Equal(t, "pos", fields["device"])
+			assert.NotEqual(t, "a", fields["refresh"])
+
+			require.NoError(t, store.Set(ctx, "lorem", "ipsum", 0))
+			_, err = store.HCompareAndSet(ctx, "lorem", 0, "refresh", "a", "refresh", "b")
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestStore_Set(t *testing.T) {
+	ctx := context.TODO()
+	for driver, store := range stores(t) {
+		t.Run(driver, func(t *testing.T) {
+			members, err := store.SMembers(ctx, "user_1")
+			require.NoError(t, err)
+			assert.Empty(t, members)
+			require.NoError(t, store.SAdd(ctx, "user_1", time.Hour, "a", "b"))
+			require.NoError(t, store.SRem(ctx, "user_1", "a"))
+			members, err = store.SMembers(ctx, "user_1")
+			require.NoError(t, err)
+			assert.Equal(t, []string{"b"}, members)
+			// an empty set does not exist
+			require.NoError(t, store.SRem(ctx, "user_1", "b"))
+			exists, _ := store.Exists(ctx, "user_1")
+			assert.False(t, exists)
+		})
+	}
+}
+
+func TestStore_Expire(t *testing.T) {
+	ctx := context.TODO()
+	for driver, store := range stores(t) {
+		t.Run(driver, func(t *testing.T) {
+			require.NoError(t, store.Set(ctx, "lorem", "ipsum", 0))
+			require.NoError(t, store.Expire(ctx, "lorem", 0))
+			exists, _ := store.Exists(ctx, "lorem")
+			assert.False(t, exists)
+			require.NoError(t, store.Expire(ctx, "missing", time.Hour))
+			assert.NoError(t, store.Ping(ctx))
+		})
+	}
+}
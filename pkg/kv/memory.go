@@ -0,0 +1,342 @@
+package kv
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// sweepInterval is how often the expired keys are removed,
+// an expired key is never returned even before it is swept
+const sweepInterval = time.Minute
+
+type (
+	// MemoryStore keep the data in the process, it is lost on restart
+	// and is not shared, so it only fit a single api instance
+	MemoryStore struct {
+		mu      sync.Mutex
+		entries map[string]*entry
+		stop    chan struct{}
+		once    sync.Once
+	}
+
+	// entry hold one kind of value: a string, a hash or a set
+	entry struct {
+		value     string
+		hash      map[string]string
+		set       map[string]struct{}
+		expiresAt time.Time
+	}
+)
+
+func (s *MemoryStore) Get(_ context.Context, key string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	item := s.live(key)
+	if item == nil {
+		return "", ErrNil
+	}
+	if item.hash != nil || item.set != nil {
+		return "", ErrWrongType
+	}
+	return item.value, nil
+}
+
+func (s *MemoryStore) Set(_ context.Context, key string, value any, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entries[key] = &entry{value: format(value), expiresAt: expiresAt(ttl)}
+	return nil
+}
+
+func (s *MemoryStore) SetNX(_ context.Context, key string, value any, ttl time.Duration) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.live(key) != nil {
+		return false, nil
+	}
+	s.entries[key] = &entry{value: format(value), expiresAt: expiresAt(ttl)}
+	return true, nil
+}
+
+func (s *MemoryStore) Incr(_ context.Context, key string, ttl time.Duration) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	item := s.live(key)
+	if item == nil {
+		item = &entry{value: "0", expiresAt: expiresAt(ttl)}
+		s.entries[key] = item
+	}
+	if item.hash != nil || item.set != nil {
+		return 0, ErrWrongType
+	}
+	count, err := strconv.ParseInt(item.value, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("kv: value of %s is not an integer", key)
+	}
+	count++
+	item.value = strconv.FormatInt(count, 10)
+	return count, nil
+}
+
+func (s *MemoryStore) Del(_ context.Context, keys ...string) (deleted int64, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, key := range keys {
+		if s.live(key) != nil {
+			delete(s.entries, key)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+func (s *MemoryStore) Exists(_ context.Context, key string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.live(key) != nil, nil
+}
+
+func (s *MemoryStore) Expire(_ context.Context, key string, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	item := s.live(key)
+	if item == nil {
+		return nil
+	}
+	// same as redis, a ttl that is not positive delete the key
+	if ttl <= 0 {
+		delete(s.entries, key)
+		return nil
+	}
+	item.expiresAt = expiresAt(ttl)
+	return nil
+}
+
+func (s *MemoryStore) Keys(_ context.Context, pattern string) (keys []string, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for key := range s.entries {
+		if s.live(key) == nil {
+			continue
+		}
+		matched, err := path.Match(pattern, key)
+		if err != nil {
+			return nil, err
+		}
+		if matched {
+			keys = append(keys, key)
+		}
+	}
+	return keys, nil
+}
+
+func (s *MemoryStore) HSet(_ context.Context, key string, ttl time.Duration, fields ...any) error {
+	if len(fields)%2 != 0 {
+		return fmt.Errorf("kv: HSet of %s expect name and value pairs", key)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	item := s.live(key)
+	if item == nil {
+		item = &entry{hash: map[string]string{}}
+		s.entries[key] = item
+	}
+	if item.hash == nil {
+		return ErrWrongType
+	}
+	for i := 0; i < len(fields); i += 2 {
+		item.hash[format(fields[i])] = format(fields[i+1])
+	}
+	if ttl > 0 {
+		item.expiresAt = expiresAt(ttl)
+	}
+	return nil
+}
+
+func (s *MemoryStore) HGetAll(_ context.Context, key string) (map[string]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	fields := map[string]string{}
+	item := s.live(key)
+	if item == nil {
+		return fields, nil
+	}
+	if item.hash == nil {
+		return nil, ErrWrongType
+	}
+	for name, value := range item.hash {
+		fields[name] = value
+	}
+	return fields, nil
+}
+
+func (s *MemoryStore) HCompareAndSet(
+	_ context.Context,
+	key string,
+	ttl time.Duration,
+	field, expected string,
+	fields ...any,
+) (bool, error) {
+	if len(fields) == 0 || len(fields)%2 != 0 {
+		return false, fmt.Errorf("kv: HCompareAndSet of %s expect name and value pairs", key)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	item := s.live(key)
+	if item == nil {
+		return false, nil
+	}
+	if item.hash == nil {
+		return false, ErrWrongType
+	}
+	if value, ok := item.hash[field]; !ok || value != expected {
+		return false, nil
+	}
+	for i := 0; i < len(fields); i += 2 {
+		item.hash[format(fields[i])] = format(fields[i+1])
+	}
+	if ttl > 0 {
+		item.expiresAt = expiresAt(ttl)
+	}
+	return true, nil
+}
+
+func (s *MemoryStore) SAdd(_ context.Context, key string, ttl time.Duration, members ...any) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	item := s.live(key)
+	if item == nil {
+		item = &entry{set: map[string]struct{}{}}
+		s.entries[key] = item
+	}
+	if item.set == nil {
+		return ErrWrongType
+	}
+	for _, member := range members {
+		item.set[format(member)] = struct{}{}
+	}
+	if ttl > 0 {
+		item.expiresAt = expiresAt(ttl)
+	}
+	return nil
+}
+
+func (s *MemoryStore) SMembers(_ context.Context, key string) ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	members := []string{}
+	item := s.live(key)
+	if item == nil {
+		return members, nil
+	}
+	if item.set == nil {
+		return nil, ErrWrongType
+	}
+	for member := range item.set {
+		members = append(members, member)
+	}
+	return members, nil
+}
+
+func (s *MemoryStore) SRem(_ context.Context, key string, members ...any) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	item := s.live(key)
+	if item == nil {
+		return nil
+	}
+	if item.set == nil {
+		return ErrWrongType
+	}
+	for _, member := range members {
+		delete(item.set, format(member))
+	}
+	// same as redis, an empty set does not exist
+	if len(item.set) == 0 {
+		delete(s.entries, key)
+	}
+	return nil
+}
+
+func (s *MemoryStore) Ping(_ context.Context) error {
+	return nil
+}
+
+func (s *MemoryStore) Close() error {
+	s.once.Do(func() { close(s.stop) })
+	return nil
+}
+
+// live return the entry of the key, an expired entry is deleted,
+// the caller must hold the lock
+func (s *MemoryStore) live(key string) *entry {
+	item, ok := s.entries[key]
+	if !ok {
+		return nil
+	}
+	if !item.expiresAt.IsZero() && !time.Now().Before(item.expiresAt) {
+		delete(s.entries, key)
+		return nil
+	}
+	return item
+}
+
+func (s *MemoryStore) sweep() {
+	ticker := time.NewTicker(sweepInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			s.mu.Lock()
+			for key := range s.entries {
+				s.live(key)
+			}
+			s.mu.Unlock()
+		case <-s.stop:
+			return
+		}
+	}
+}
+
+func expiresAt(ttl time.Duration) time.Time {
+	if ttl <= 0 {
+		return time.Time{}
+	}
+	return time.Now().Add(ttl)
+}
+
+// format the value as redis store it
+func format(value any) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case []byte:
+		return string(v)
+	case bool:
+		if v {
+			return "1"
+		}
+		return "0"
+	case int:
+		return strconv.Itoa(v)
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case fmt.Stringer:
+		return v.String()
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+func NewMemoryStore() Store {
+	store := &MemoryStore{
+		entries: map[string]*entry{},
+		stop:    make(chan struct{}),
+	}
+	go store.sweep()
+	return store
+}
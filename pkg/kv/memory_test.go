@@ -0,0 +1,47 @@
+package kv_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aasumitro/posbe/pkg/kv"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryStore_ShouldExpireKeys(t *testing.T) {
+	ctx := context.TODO()
+	store := kv.NewMemoryStore()
+	defer func() { _ = store.Close() }()
+	require.NoError(t, store.Set(ctx, "lorem", "ipsum", 20*time.Millisecond))
+	require.NoError(t, store.HSet(ctx, "session", 20*time.Millisecond, "user_id", 1))
+	require.NoError(t, store.SAdd(ctx, "user_1", time.Hour, "a"))
+	require.NoError(t, store.Expire(ctx, "user_1", 20*time.Millisecond))
+	require.NoError(t, store.Set(ctx, "roles", "[]", 0))
+	time.Sleep(30 * time.Millisecond)
+
+	_, err := store.Get(ctx, "lorem")
+	assert.ErrorIs(t, err, kv.ErrNil)
+	fields, _ := store.HGetAll(ctx, "session")
+	assert.Empty(t, fields)
+	members, _ := store.SMembers(ctx, "user_1")
+	assert.Empty(t, members)
+	keys, _ := store.Keys(ctx, "*")
+	assert.Equal(t, []string{"roles"}, keys)
+	// an expired key can be set again
+	ok, _ := store.SetNX(ctx, "lorem", "dolor", 0)
+	assert.True(t, ok)
+}
+
+func TestMemoryStore_ShouldRejectWrongType(t *testing.T) {
+	ctx := context.TODO()
+	store := kv.NewMemoryStore()
+	defer func() { _ = store.Close() }()
+	require.NoError(t, store.SAdd(ctx, "user_1", 0, "a"))
+	_, err := store.Get(ctx, "user_1")
+	assert.ErrorIs(t, err, kv.ErrWrongType)
+	assert.ErrorIs(t, store.HSet(ctx, "user_1", 0, "a", "b"), kv.ErrWrongType)
+	assert.Error(t, store.HSet(ctx, "session", 0, "a"))
+	assert.NoError(t, store.Close())
+}
@@ -0,0 +1,140 @@
+package kv
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// RedisStore share the data between every api instance
+type RedisStore struct {
+	Client *redis.Client
+}
+
+// compareAndSetScript run on the redis server so no other command get in
+// between the check and the set, ARGV: field, expected, ttl (ms), fields
+var compareAndSetScript = redis.NewScript(`
+if redis.call('HGET', KEYS[1], ARGV[1]) ~= ARGV[2] then
+	return 0
+end
+redis.call('HSET', KEYS[1], unpack(ARGV, 4))
+if tonumber(ARGV[3]) > 0 then
+	redis.call('PEXPIRE', KEYS[1], ARGV[3])
+end
+return 1
+`)
+
+func (s *RedisStore) Get(ctx context.Context, key string) (string, error) {
+	value, err := s.Client.Get(ctx, key).Result()
+	if errors.Is(err, redis.Nil) {
+		return "", ErrNil
+	}
+	return value, err
+}
+
+func (s *RedisStore) Set(ctx context.Context, key string, value any, ttl time.Duration) error {
+	return s.Client.Set(ctx, key, value, ttl).Err()
+}
+
+func (s *RedisStore) SetNX(ctx context.Context, key string, value any, ttl time.Duration) (bool, error) {
+	return s.Client.SetNX(ctx, key, value, ttl).Result()
+}
+
+func (s *RedisStore) Incr(ctx context.Context, key string, ttl time.Duration) (int64, error) {
+	count, err := s.Client.Incr(ctx, key).Result()
+	if err != nil {
+		return 0, err
+	}
+	if count == 1 && ttl > 0 {
+		err = s.Client.Expire(ctx, key, ttl).Err()
+	}
+	return count, err
+}
+
+func (s *RedisStore) Del(ctx context.Context, keys ...string) (int64, error) {
+	if len(keys) == 0 {
+		return 0, nil
+	}
+	return s.Client.Del(ctx, keys...).Result()
+}
+
+func (s *RedisStore) Exists(ctx context.Context, key string) (bool, error) {
+	count, err := s.Client.Exists(ctx, key).Result()
+	return count > 0, err
+}
+
+func (s *RedisStore) Expire(ctx context.Context, key string, ttl time.Duration) error {
+	return s.Client.Expire(ctx, key, ttl).Err()
+}
+
+func (s *RedisStore) Keys(ctx context.Context, pattern string) (keys []string, err error) {
+	iter := s.Client.Scan(ctx, 0, pattern, 0).Iterator()
+	for iter.Next(ctx) {
+		keys = append(keys, iter.Val())
+	}
+	return keys, iter.Err()
+}
+
+func (s *RedisStore) HSet(ctx context.Context, key string, ttl time.Duration, fields ...any) error {
+	_, err := s.Client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HSet(ctx, key, fields...)
+		if ttl > 0 {
+			pipe.Expire(ctx, key, ttl)
+		}
+		return nil
+	})
+	return err
+}
+
+func (s *RedisStore) HGetAll(ctx context.Context, key string) (map[string]string, error) {
+	return s.Client.HGetAll(ctx, key).Result()
+}
+
+func (s *RedisStore) HCompareAndSet(
+	ctx context.Context,
+	key string,
+	ttl time.Duration,
+	field, expected string,
+	fields ...any,
+) (bool, error) {
+	if len(fields) == 0 || len(fields)%2 != 0 {
+		return false, fmt.Errorf("kv: HCompareAndSet of %s expect name and value pairs", key)
+	}
+	args := append([]any{field, expected, ttl.Milliseconds()}, fields...)
+	set, err := compareAndSetScript.Run(ctx, s.Client, []string{key}, args...).Int()
+	return set == 1, err
+}
+
+func (s *RedisStore) SAdd(ctx context.Context, key string, ttl time.Duration, members ...any) error {
+	_, err := s.Client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.SAdd(ctx, key, members...)
+		if ttl > 0 {
+			pipe.Expire(ctx, key, ttl)
+		}
+		return nil
+	})
+	return err
+}
+
+func (s *RedisStore) SMembers(ctx context.Context, key string) ([]string, error) {
+	return s.Client.SMembers(ctx, key).Result()
+}
+
+func (s *RedisStore) SRem(ctx context.Context, key string, members ...any) error {
+	return s.Client.SRem(ctx, key, members...).Err()
+}
+
+func (s *RedisStore) Ping(ctx context.Context) error {
+	return s.Client.Ping(ctx).Err()
+}
+
+func (s *RedisStore) Close() error {
+	return s.Client.Close()
+}
+
+func NewRedisStore(client *redis.Client) Store {
+	return &RedisStore{Client: client}
+}
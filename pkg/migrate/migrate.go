@@ -0,0 +1,282 @@
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+
+	"github.com/aasumitro/posbe/pkg/dialect"
+)
+
+// NilVersion is the version of a database without migration
+const NilVersion int64 = -1
+
+// lockID is the key of the lock held while migrating,
+// so only one instance migrate when many start at once
+const lockID int64 = 7251846103
+
+// the table is the one of golang-migrate, so a database migrated
+// by its cli keep its version
+const schemaTable = "CREATE TABLE IF NOT EXISTS schema_migrations " +
+	"(version BIGINT NOT NULL PRIMARY KEY, dirty BOOLEAN NOT NULL)"
+
+var (
+	ErrDirty      = errors.New("database is dirty, fix the failed migration and force its version")
+	ErrNoChange   = errors.New("no change")
+	ErrNoMigrated = errors.New("version is not a migration")
+
+	fileName = regexp.MustCompile(`^(\d+)_(.+)\.(up|down)\.sql$`)
+)
+
+type (
+	// Migration is a pair of <version>_<name>.up.sql and .down.sql files,
+	// the down file is optional (e.g. default data)
+	Migration struct {
+		Version int64  `json:"version"`
+		Name    string `json:"name"`
+		Applied bool   `json:"applied"`
+		up      string
+		down    string
+	}
+
+	// Status is the version of the database and the migrations
+	Status struct {
+		Version    int64        `json:"version"`
+		Dirty      bool         `json:"dirty"`
+		Migrations []*Migration `json:"migrations"`
+	}
+
+	Migrator struct {
+		db         *sql.DB
+		dialect    dialect.Dialect
+		migrations []*Migration
+	}
+)
+
+// New read the migrations of dir in source (e.g. the embedded db.Migrations),
+// d is the dialect of db
+func New(db *sql.DB, d dialect.Dialect, source fs.FS, dir string) (*Migrator, error) {
+	entries, err := fs.ReadDir(source, dir)
+	if err != nil {
+		return nil, err
+	}
+	versions := make(map[int64]*Migration)
+	for _, entry := range entries {
+		match := fileName.FindStringSubmatch(entry.Name())
+		if entry.IsDir() || match == nil {
+			continue
+		}
+		version, err := strconv.ParseInt(match[1], 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		migration, ok := versions[version]
+		if !ok {
+			migration = &Migration{Version: version, Name: match[2]}
+			versions[version] = migration
+		}
+		body, err := fs.ReadFile(source, path.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		if match[3] == "up" {
+			migration.up = string(body)
+		} else {
+			migration.down = string(body)
+		}
+	}
+	migrator := &Migrator{db: db, dialect: d}
+	for _, migration := range versions {
+		migrator.migrations = append(migrator.migrations, migration)
+	}
+	sort.Slice(migrator.migrations, func(i, j int) bool {
+		return migrator.migrations[i].Version < migrator.migrations[j].Version
+	})
+	return migrator, nil
+}
+
+// Up apply every pending migration, it return the applied ones
+func (m *Migrator) Up(ctx context.Context) (applied []*Migration, err error) {
+	err = m.locked(ctx, func(conn *sql.Conn) error {
+		version, dirty, err := m.version(ctx, conn)
+		if err != nil {
+			return err
+		}
+		if dirty {
+			return ErrDirty
+		}
+		for _, migration := range m.migrations {
+			if migration.Version <= version {
+				continue
+			}
+			if err := m.run(ctx, conn, migration.up, migration.Version); err != nil {
+				return fmt.Errorf("%d_%s: %w", migration.Version, migration.Name, err)
+			}
+			applied = append(applied, migration)
+		}
+		if len(applied) == 0 {
+			return ErrNoChange
+		}
+		return nil
+	})
+	return applied, err
+}
+
+// Down roll back the last steps migrations, it return the rolled back ones
+func (m *Migrator) Down(ctx context.Context, steps int) (reverted []*Migration, err error) {
+	err = m.locked(ctx, func(conn *sql.Conn) error {
+		version, dirty, err := m.version(ctx, conn)
+		if err != nil {
+			return err
+		}
+		if dirty {
+			return ErrDirty
+		}
+		for i := len(m.migrations) - 1; i >= 0 && len(reverted) < steps; i-- {
+			migration := m.migrations[i]
+			if migration.Version > version {
+				continue
+			}
+			previous := NilVersion
+			if i > 0 {
+				previous = m.migrations[i-1].Version
+			}
+			if err := m.run(ctx, conn, migration.down, previous); err != nil {
+				return fmt.Errorf("%d_%s: %w", migration.Version, migration.Name, err)
+			}
+			reverted = append(reverted, migration)
+			version = previous
+		}
+		if len(reverted) == 0 {
+			return ErrNoChange
+		}
+		return nil
+	})
+	return reverted, err
+}
+
+// Status read the version of the database without the lock
+func (m *Migrator) Status(ctx context.Context) (status *Status, err error) {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer func(conn *sql.Conn) { _ = conn.Close() }(conn)
+
+	if _, err := conn.ExecContext(ctx, schemaTable); err != nil {
+		return nil, err
+	}
+	status = &Status{}
+	if status.Version, status.Dirty, err = m.version(ctx, conn); err != nil {
+		return nil, err
+	}
+	for _, migration := range m.migrations {
+		item := *migration
+		item.Applied = migration.Version <= status.Version
+		status.Migrations = append(status.Migrations, &item)
+	}
+	return status, nil
+}
+
+// Force set the version and clear the dirty flag without running
+// anything, NilVersion remove the version
+func (m *Migrator) Force(ctx context.Context, version int64) error {
+	if version != NilVersion && !m.exists(version) {
+		return ErrNoMigrated
+	}
+	return m.locked(ctx, func(conn *sql.Conn) error {
+		return m.setVersion(ctx, conn, version, false)
+	})
+}
+
+func (m *Migrator) exists(version int64) bool {
+	for _, migration := range m.migrations {
+		if migration.Version == version {
+			return true
+		}
+	}
+	return false
+}
+
+// locked run fn on a single connection that hold the lock of the dialect,
+// the lock wait for the other instances to finish
+func (m *Migrator) locked(ctx context.Context, fn func(conn *sql.Conn) error) error {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer func(conn *sql.Conn) { _ = conn.Close() }(conn)
+
+	unlock, err := m.dialect.Lock(ctx, conn, lockID)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	if _, err := conn.ExecContext(ctx, schemaTable); err != nil {
+		return err
+	}
+	return fn(conn)
+}
+
+func (m *Migrator) version(ctx context.Context, conn *sql.Conn) (version int64, dirty bool, err error) {
+	q := "SELECT version, dirty FROM schema_migrations LIMIT 1"
+	err = conn.QueryRowContext(ctx, q).Scan(&version, &dirty)
+	if errors.Is(err, sql.ErrNoRows) {
+		return NilVersion, false, nil
+	}
+	return version, dirty, err
+}
+
+// run mark the version dirty first, so a migration that fail halfway
+// is left dirty, the body and the clean version are then committed together
+func (m *Migrator) run(ctx context.Context, conn *sql.Conn, body string, version int64) error {
+	if err := m.setVersion(ctx, conn, version, true); err != nil {
+		return err
+	}
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	// rollback is no-op after commit
+	defer func() { _ = tx.Rollback() }()
+	if body != "" {
+		if _, err := tx.ExecContext(ctx, body); err != nil {
+			return err
+		}
+	}
+	if err := writeVersion(ctx, tx, version, false); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (m *Migrator) setVersion(ctx context.Context, conn *sql.Conn, version int64, dirty bool) error {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+	if err := writeVersion(ctx, tx, version, dirty); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func writeVersion(ctx context.Context, tx *sql.Tx, version int64, dirty bool) error {
+	if _, err := tx.ExecContext(ctx, "DELETE FROM schema_migrations"); err != nil {
+		return err
+	}
+	if version == NilVersion {
+		return nil
+	}
+	_, err := tx.ExecContext(ctx,
+		"INSERT INTO schema_migrations (version, dirty) VALUES ($1, $2)",
+		version, dirty)
+	return err
+}
@@ -0,0 +1,316 @@
+package migrate_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"io/fs"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	posbedb "github.com/aasumitro/posbe/db"
+	"github.com/aasumitro/posbe/pkg/dbtest"
+	"github.com/aasumitro/posbe/pkg/dialect"
+	"github.com/aasumitro/posbe/pkg/migrate"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	_ "modernc.org/sqlite"
+)
+
+type migrateTestSuite struct {
+	suite.Suite
+	dialect  dialect.Dialect
+	mock     sqlmock.Sqlmock
+	migrator *migrate.Migrator
+}
+
+func (suite *migrateTestSuite) SetupTest() {
+	db, mock, err := sqlmock.New(
+		sqlmock.QueryMatcherOption(
+			sqlmock.QueryMatcherRegexp))
+	require.NoError(suite.T(), err)
+	suite.mock = mock
+	suite.migrator, err = migrate.New(db, suite.dialect, fstest.MapFS{
+		"migrations/1_create_table_units.up.sql":   {Data: []byte("CREATE TABLE units (id INT)")},
+		"migrations/1_create_table_units.down.sql": {Data: []byte("DROP TABLE units")},
+		"migrations/2_add_unit_data.up.sql":        {Data: []byte("INSERT INTO units VALUES (1)")},
+		"migrations/README.md":                     {Data: []byte("not a migration")},
+	}, "migrations")
+	require.NoError(suite.T(), err)
+}
+
+func (suite *migrateTestSuite) AfterTest(_, _ string) {
+	require.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+// expectLock expect the advisory lock of postgres, sqlite has none
+func (suite *migrateTestSuite) expectLock() {
+	if suite.dialect.Name() == dialect.Postgres {
+		suite.mock.ExpectExec("SELECT pg_advisory_lock\\(\\$1\\)").
+			WillReturnResult(sqlmock.NewResult(0, 0))
+	}
+	suite.mock.ExpectExec("CREATE TABLE IF NOT EXISTS schema_migrations").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+}
+
+func (suite *migrateTestSuite) expectUnlock() {
+	if suite.dialect.Name() == dialect.Postgres {
+		suite.mock.ExpectExec("SELECT pg_advisory_unlock\\(\\$1\\)").
+			WillReturnResult(sqlmock.NewResult(0, 0))
+	}
+}
+
+func (suite *migrateTestSuite) expectVersion(version int64, dirty bool) {
+	rows := suite.mock.NewRows([]string{"version", "dirty"})
+	if version != migrate.NilVersion {
+		rows.AddRow(version, dirty)
+	}
+	suite.mock.ExpectQuery("SELECT version, dirty FROM schema_migrations LIMIT 1").
+		WillReturnRows(rows)
+}
+
+func (suite *migrateTestSuite) expectWriteVersion(version int64, dirty bool) {
+	suite.mock.ExpectExec("DELETE FROM schema_migrations").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	if version != migrate.NilVersion {
+		suite.mock.ExpectExec("INSERT INTO schema_migrations \\(version, dirty\\) VALUES \\(\\$1, \\$2\\)").
+			WithArgs(version, dirty).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+	}
+}
+
+func (suite *migrateTestSuite) expectRun(body string, version int64) {
+	suite.mock.ExpectBegin()
+	suite.expectWriteVersion(version, true)
+	suite.mock.ExpectCommit()
+	suite.mock.ExpectBegin()
+	if body != "" {
+		suite.mock.ExpectExec(body).WillReturnResult(sqlmock.NewResult(0, 0))
+	}
+	suite.expectWriteVersion(version, false)
+	suite.mock.ExpectCommit()
+}
+
+func (suite *migrateTestSuite) TestMigrator_Up_ExpectApplyPending() {
+	suite.expectLock()
+	suite.expectVersion(migrate.NilVersion, false)
+	suite.expectRun("CREATE TABLE units", 1)
+	suite.expectRun("INSERT INTO units", 2)
+	suite.expectUnlock()
+	applied, err := suite.migrator.Up(context.TODO())
+	require.NoError(suite.T(), err)
+	require.Len(suite.T(), applied, 2)
+	require.Equal(suite.T(), "add_unit_data", applied[1].Name)
+}
+
+func (suite *migrateTestSuite) TestMigrator_Up_ExpectNoChange() {
+	suite.expectLock()
+	suite.expectVersion(2, false)
+	suite.expectUnlock()
+	applied, err := suite.migrator.Up(context.TODO())
+	require.ErrorIs(suite.T(), err, migrate.ErrNoChange)
+	require.Empty(suite.T(), applied)
+}
+
+func (suite *migrateTestSuite) TestMigrator_Up_ExpectErrorDirty() {
+	suite.expectLock()
+	suite.expectVersion(1, true)
+	suite.expectUnlock()
+	_, err := suite.migrator.Up(context.TODO())
+	require.ErrorIs(suite.T(), err, migrate.ErrDirty)
+}
+
+func (suite *migrateTestSuite) TestMigrator_Up_ExpectLeftDirtyWhenFail() {
+	suite.expectLock()
+	suite.expectVersion(1, false)
+	suite.mock.ExpectBegin()
+	suite.expectWriteVersion(2, true)
+	suite.mock.ExpectCommit()
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectExec("INSERT INTO units").WillReturnError(errors.New("UNEXPECTED"))
+	suite.mock.ExpectRollback()
+	suite.expectUnlock()
+	_, err := suite.migrator.Up(context.TODO())
+	require.EqualError(suite.T(), err, "2_add_unit_data: UNEXPECTED")
+}
+
+func (suite *migrateTestSuite) TestMigrator_Down_ExpectRevertSteps() {
+	suite.expectLock()
+	suite.expectVersion(2, false)
+	// the data migration has no down file
+	suite.expectRun("", 1)
+	suite.expectRun("DROP TABLE units", migrate.NilVersion)
+	suite.expectUnlock()
+	reverted, err := suite.migrator.Down(context.TODO(), 5)
+	require.NoError(suite.T(), err)
+	require.Len(suite.T(), reverted, 2)
+}
+
+func (suite *migrateTestSuite) TestMigrator_Status_ExpectApplied() {
+	suite.mock.ExpectExec("CREATE TABLE IF NOT EXISTS schema_migrations").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	suite.expectVersion(1, false)
+	status, err := suite.migrator.Status(context.TODO())
+	require.NoError(suite.T(), err)
+	require.Equal(suite.T(), int64(1), status.Version)
+	require.True(suite.T(), status.Migrations[0].Applied)
+	require.False(suite.T(), status.Migrations[1].Applied)
+}
+
+func (suite *migrateTestSuite) TestMigrator_Force_ExpectCleanVersion() {
+	suite.expectLock()
+	suite.mock.ExpectBegin()
+	suite.expectWriteVersion(1, false)
+	suite.mock.ExpectCommit()
+	suite.expectUnlock()
+	require.NoError(suite.T(), suite.migrator.Force(context.TODO(), 1))
+}
+
+func (suite *migrateTestSuite) TestMigrator_Force_ExpectErrorUnknownVersion() {
+	err := suite.migrator.Force(context.TODO(), 3)
+	require.ErrorIs(suite.T(), err, migrate.ErrNoMigrated)
+}
+
+func TestMigrate(t *testing.T) {
+	for _, d := range dialect.All() {
+		t.Run(d.Name(), func(t *testing.T) {
+			suite.Run(t, &migrateTestSuite{dialect: d})
+		})
+	}
+}
+
+func TestNew_ShouldReadEmbeddedMigrations(t *testing.T) {
+	// every dialect end at the same version, new migrations are added to both
+	heads := make(map[int64]string)
+	for _, d := range dialect.All() {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		migrator, err := migrate.New(db, d, posbedb.Migrations, posbedb.MigrationsDir(d))
+		require.NoError(t, err)
+		mock.ExpectExec("CREATE TABLE IF NOT EXISTS schema_migrations").
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectQuery("SELECT version, dirty FROM schema_migrations").
+			WillReturnRows(mock.NewRows([]string{"version", "dirty"}))
+		status, err := migrator.Status(context.TODO())
+		require.NoError(t, err)
+		require.NotEmpty(t, status.Migrations)
+		for i := 1; i < len(status.Migrations); i++ {
+			require.Less(t, status.Migrations[i-1].Version, status.Migrations[i].Version)
+		}
+		heads[status.Migrations[len(status.Migrations)-1].Version] = d.Name()
+	}
+	require.Len(t, heads, 1)
+}
+
+func TestMigrator_SQLite_ShouldApplyEmbeddedMigrations(t *testing.T) {
+	// sqlite need no server, the embedded migrations and seeds run for real
+	d, _ := dialect.New(dialect.SQLite)
+	db, err := sql.Open(d.Name(), "file:"+filepath.Join(t.TempDir(), "posbe.db")+"?_pragma=foreign_keys(1)")
+	require.NoError(t, err)
+	defer func() { _ = db.Close() }()
+	migrator, err := migrate.New(db, d, posbedb.Migrations, posbedb.MigrationsDir(d))
+	require.NoError(t, err)
+	ctx := context.TODO()
+
+	applied, err := migrator.Up(ctx)
+	require.NoError(t, err)
+	require.NotEmpty(t, applied)
+	for i := 0; i < 2; i++ {
+		_, err = migrate.Seed(ctx, db, posbedb.Seeds, posbedb.SeedsDir)
+		require.NoError(t, err)
+	}
+	var users int
+	require.NoError(t, db.QueryRowContext(ctx, "SELECT count(*) FROM users").Scan(&users))
+	require.Greater(t, users, 1)
+
+	_, err = db.ExecContext(ctx, "UPDATE products SET name = name || ' v2' WHERE id = 1")
+	require.NoError(t, err)
+	var versions int
+	require.NoError(t, db.QueryRowContext(ctx,
+		"SELECT count(*) FROM entity_versions WHERE entity_type = 'products' AND entity_id = '1'",
+	).Scan(&versions))
+	require.Equal(t, 2, versions)
+
+	reverted, err := migrator.Down(ctx, len(applied))
+	require.NoError(t, err)
+	require.Len(t, reverted, len(applied))
+	var tables int
+	require.NoError(t, db.QueryRowContext(ctx,
+		"SELECT count(*) FROM sqlite_master WHERE type = 'table' AND name NOT IN ('schema_migrations', 'sqlite_sequence')",
+	).Scan(&tables))
+	require.Zero(t, tables)
+}
+
+// moneyMinorUnits is the version that turn the FLOAT prices of postgres
+// into BIGINT minor units
+const moneyMinorUnits int64 = 20261019140000
+
+// migrationsBefore copy the embedded migrations of the dialect
+// older than the version, to stop the schema right before it
+func migrationsBefore(t *testing.T, d dialect.Dialect, version int64) fstest.MapFS {
+	t.Helper()
+	dir := posbedb.MigrationsDir(d)
+	entries, err := fs.ReadDir(posbedb.Migrations, dir)
+	require.NoError(t, err)
+	files := fstest.MapFS{}
+	for _, entry := range entries {
+		v, err := strconv.ParseInt(strings.SplitN(entry.Name(), "_", 2)[0], 10, 64)
+		require.NoError(t, err)
+		if v >= version {
+			continue
+		}
+		data, err := fs.ReadFile(posbedb.Migrations, path.Join(dir, entry.Name()))
+		require.NoError(t, err)
+		files[path.Join(dir, entry.Name())] = &fstest.MapFile{Data: data}
+	}
+	return files
+}
+
+func TestMigrator_Postgres_ShouldConvertFloatPricesToMinorUnits(t *testing.T) {
+	// need a real postgres, skipped unless dbtest.PostgresEnv is set
+	d, _ := dialect.New(dialect.Postgres)
+	db := dbtest.Open(t, d.Name())
+	ctx := context.TODO()
+	before, err := migrate.New(db, d, migrationsBefore(t, d, moneyMinorUnits), posbedb.MigrationsDir(d))
+	require.NoError(t, err)
+	_, err = before.Up(ctx)
+	require.NoError(t, err)
+
+	// 1234.56 saved by a float32 client is 1234.56005859375 once widened
+	_, err = db.ExecContext(ctx, "UPDATE products SET price = $1 WHERE id = 1", float64(float32(1234.56)))
+	require.NoError(t, err)
+	_, err = db.ExecContext(ctx, "UPDATE products SET price = $1 WHERE id = 2", 19.99)
+	require.NoError(t, err)
+
+	migrator, err := migrate.New(db, d, posbedb.Migrations, posbedb.MigrationsDir(d))
+	require.NoError(t, err)
+	_, err = migrator.Up(ctx)
+	require.NoError(t, err)
+	var first, second int64
+	require.NoError(t, db.QueryRowContext(ctx, "SELECT price FROM products WHERE id = 1").Scan(&first))
+	require.NoError(t, db.QueryRowContext(ctx, "SELECT price FROM products WHERE id = 2").Scan(&second))
+	require.Equal(t, int64(123456), first)
+	require.Equal(t, int64(1999), second)
+}
+
+func TestMigrator_Postgres_ShouldRefuseLossyPrices(t *testing.T) {
+	d, _ := dialect.New(dialect.Postgres)
+	db := dbtest.Open(t, d.Name())
+	ctx := context.TODO()
+	before, err := migrate.New(db, d, migrationsBefore(t, d, moneyMinorUnits), posbedb.MigrationsDir(d))
+	require.NoError(t, err)
+	_, err = before.Up(ctx)
+	require.NoError(t, err)
+	_, err = db.ExecContext(ctx, "UPDATE products SET price = 1.005 WHERE id = 1")
+	require.NoError(t, err)
+
+	migrator, err := migrate.New(db, d, posbedb.Migrations, posbedb.MigrationsDir(d))
+	require.NoError(t, err)
+	_, err = migrator.Up(ctx)
+	require.ErrorContains(t, err, "products.price has 1 value(s) with more decimals")
+}
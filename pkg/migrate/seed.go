@@ -0,0 +1,42 @@
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+)
+
+// Seed run every .sql file of dir in source by name order in one transaction,
+// the seeds must be safe to run again (e.g. ON CONFLICT DO NOTHING)
+func Seed(ctx context.Context, db *sql.DB, source fs.FS, dir string) (files []string, err error) {
+	entries, err := fs.ReadDir(source, dir)
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range entries {
+		if !entry.IsDir() && strings.HasSuffix(entry.Name(), ".sql") {
+			files = append(files, entry.Name())
+		}
+	}
+	sort.Strings(files)
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	// rollback is no-op after commit
+	defer func() { _ = tx.Rollback() }()
+	for _, file := range files {
+		body, err := fs.ReadFile(source, path.Join(dir, file))
+		if err != nil {
+			return nil, err
+		}
+		if _, err := tx.ExecContext(ctx, string(body)); err != nil {
+			return nil, fmt.Errorf("%s: %w", file, err)
+		}
+	}
+	return files, tx.Commit()
+}
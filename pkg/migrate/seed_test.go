@@ -0,0 +1,42 @@
+package migrate_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"testing/fstest"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/aasumitro/posbe/pkg/migrate"
+	"github.com/stretchr/testify/require"
+)
+
+var seeds = fstest.MapFS{
+	"seeds/002_demo_tables.sql": {Data: []byte("INSERT INTO tables VALUES (1)")},
+	"seeds/001_demo_users.sql":  {Data: []byte("INSERT INTO users VALUES (1)")},
+}
+
+func TestSeed_ShouldRunInOrder(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherRegexp))
+	require.NoError(t, err)
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO users").WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec("INSERT INTO tables").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	files, err := migrate.Seed(context.TODO(), db, seeds, "seeds")
+	require.NoError(t, err)
+	require.Equal(t, []string{"001_demo_users.sql", "002_demo_tables.sql"}, files)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSeed_ShouldRollbackWhenError(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherRegexp))
+	require.NoError(t, err)
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO users").WillReturnError(errors.New("UNEXPECTED"))
+	mock.ExpectRollback()
+	files, err := migrate.Seed(context.TODO(), db, seeds, "seeds")
+	require.Nil(t, files)
+	require.EqualError(t, err, "001_demo_users.sql: UNEXPECTED")
+	require.NoError(t, mock.ExpectationsWereMet())
+}
@@ -6,6 +6,79 @@ import (
 	"github.com/aasumitro/posbe/pkg/utils"
 )
 
+const (
+	PermissionAccountUserRead  = "account.user.read"
+	PermissionAccountUserWrite = "account.user.write"
+	PermissionAccountRoleWrite = "account.role.write"
+	// PermissionAccountTerminalWrite register shared terminals
+	PermissionAccountTerminalWrite = "account.terminal.write"
+	// PermissionAccountAPIKeyWrite issue, rotate and revoke api keys
+	PermissionAccountAPIKeyWrite = "account.api_key.write"
+
+	PermissionCatalogRead              = "catalog.read"
+	PermissionCatalogProductWrite      = "catalog.product.write"
+	PermissionCatalogPriceWrite        = "catalog.price.write"
+	PermissionCatalogAvailabilityWrite = "catalog.availability.write"
+
+	PermissionStoreRead  = "store.read"
+	PermissionStoreWrite = "store.write"
+
+	PermissionOrderRead   = "order.read"
+	PermissionOrderWrite  = "order.write"
+	PermissionOrderTender = "order.tender"
+
+	// sensitive actions, a user without the permission
+	// need an override approved by a user who has it
+	PermissionOrderVoid          = "order.void"
+	PermissionOrderRefund        = "order.refund"
+	PermissionOrderPriceOverride = "order.price_override"
+	PermissionOrderDiscount      = "order.discount"
+	PermissionCashDrawerOpen     = "cash_drawer.open"
+
+	PermissionReportExport = "report.export"
+
+	PermissionAuditRead = "audit.read"
+	// PermissionTrashRestore list deleted rows with ?include_deleted=true
+	// and restore them
+	PermissionTrashRestore = "trash.restore"
+)
+
+// Permissions is every permission a route can require,
+// roles are granted a subset of them
+var Permissions = []*Permission{
+	{Name: PermissionAccountUserRead, Description: "view users, roles and permissions"},
+	{Name: PermissionAccountUserWrite, Description: "create, update and delete users"},
+	{Name: PermissionAccountRoleWrite, Description: "create, update and delete roles and their permissions"},
+	{Name: PermissionAccountTerminalWrite, Description: "register, update and delete shared terminals"},
+	{Name: PermissionAccountAPIKeyWrite, Description: "issue, rotate and revoke api keys of machine integrations"},
+	{Name: PermissionCatalogRead, Description: "view catalog, prices, taxes and availability"},
+	{Name: PermissionCatalogProductWrite, Description: "manage units, categories, addons, products, bundles and schedules"},
+	{Name: PermissionCatalogPriceWrite, Description: "manage price lists, addon prices and tax classes"},
+	{Name: PermissionCatalogAvailabilityWrite, Description: "mark products sold out (86) and restock them"},
+	{Name: PermissionStoreRead, Description: "view floors, tables, rooms, prefs and currency"},
+	{Name: PermissionStoreWrite, Description: "manage floors, tables, rooms, prefs and currency rates"},
+	{Name: PermissionOrderRead, Description: "view orders and tenders"},
+	{Name: PermissionOrderWrite, Description: "take orders and push the orders taken offline"},
+	{Name: PermissionOrderTender, Description: "take payment of orders"},
+	{Name: PermissionOrderVoid, Description: "void orders and order lines, or approve it"},
+	{Name: PermissionOrderRefund, Description: "refund paid orders, or approve it"},
+	{Name: PermissionOrderPriceOverride, Description: "change the price of order lines, or approve it"},
+	{Name: PermissionOrderDiscount, Description: "give large discounts, or approve it"},
+	{Name: PermissionCashDrawerOpen, Description: "open the cash drawer without a sale, or approve it"},
+	{Name: PermissionReportExport, Description: "export reports"},
+	{Name: PermissionAuditRead, Description: "view the audit log of every change"},
+	{Name: PermissionTrashRestore, Description: "view deleted rows and restore them"},
+}
+
+// OverrideActions can be approved with a manager override
+var OverrideActions = []string{
+	PermissionOrderVoid,
+	PermissionOrderRefund,
+	PermissionOrderPriceOverride,
+	PermissionOrderDiscount,
+	PermissionCashDrawerOpen,
+}
+
 type (
 	LoginForm struct {
 		Username string `json:"username" form:"username" binding:"required"`
@@ -21,13 +94,223 @@ type (
 		Phone    string `json:"phone" form:"phone"`
 		Password string `json:"password,omitempty" form:"password" binding:"required"`
 		Role     Role   `json:"role" binding:"-"`
+		SoftDelete
 	}
 
 	Role struct {
+		ID          int      `json:"id"`
+		Name        string   `json:"name" form:"name" binding:"required"`
+		Description string   `json:"description" form:"description"  binding:"required"`
+		Usage       int      `json:"usage,omitempty"`
+		Permissions []string `json:"permissions" form:"permissions"`
+		SoftDelete
+	}
+
+	Permission struct {
+		Name        string `json:"name"`
+		Description string `json:"description"`
+	}
+
+	// UserPIN is the hashed pin of the user, empty when unset,
+	// the pin can not be used until LockedUntil after repeated failures
+	UserPIN struct {
+		UserID      int
+		RoleID      int
+		PIN         string
+		LockedUntil int64
+	}
+
+	// TerminalUser is the user to pick from on a terminal, without
+	// the contact and role of the user that the terminal does not need
+	TerminalUser struct {
+		ID     int    `json:"id"`
+		Name   string `json:"name"`
+		HasPIN bool   `json:"has_pin"`
+	}
+
+	PINForm struct {
+		PIN string `json:"pin" form:"pin" binding:"required,numeric,min=4,max=6"`
+	}
+
+	BadgeForm struct {
+		Badge string `json:"badge" form:"badge" binding:"required,min=4,max=64"`
+	}
+
+	// Terminal is a shared device (e.g. waiter tablet), the key is only
+	// returned when the terminal is registered and stored hashed
+	Terminal struct {
 		ID          int    `json:"id"`
 		Name        string `json:"name" form:"name" binding:"required"`
-		Description string `json:"description" form:"description"  binding:"required"`
-		Usage       int    `json:"usage,omitempty"`
+		Key         string `json:"key,omitempty" form:"-"`
+		KeyHash     string `json:"-" form:"-"`
+		IdleTimeout int    `json:"idle_timeout" form:"idle_timeout" binding:"omitempty,min=30"`
+		Disabled    bool   `json:"disabled" form:"disabled"`
+		CreatedAt   int64  `json:"created_at"`
+		UpdatedAt   int64  `json:"updated_at"`
+		SoftDelete
+	}
+
+	// QuickLoginForm log in on a terminal with user and pin, or badge only
+	QuickLoginForm struct {
+		UserID int    `json:"user_id" form:"user_id" binding:"required_without=Badge"`
+		PIN    string `json:"pin" form:"pin" binding:"required_with=UserID,omitempty,numeric,min=4,max=6"`
+		Badge  string `json:"badge" form:"badge" binding:"required_without=UserID"`
+		Device string `json:"-" form:"-"`
+		IP     string `json:"-" form:"-"`
+	}
+
+	// AuthSession is the jwt payload of a login, the user is embedded so the
+	// payload keep the user shape, the tokens are only set on login and refresh.
+	// Terminal login end when idle or when another user log in on the terminal
+	AuthSession struct {
+		*User
+		SessionID    string `json:"session_id"`
+		TerminalID   int    `json:"terminal_id,omitempty"`
+		IdleTimeout  int    `json:"idle_timeout,omitempty"`
+		AccessToken  string `json:"access_token,omitempty"`
+		RefreshToken string `json:"refresh_token,omitempty"`
+		ExpiresIn    int    `json:"expires_in,omitempty"`
+	}
+
+	// Session is a logged in device of the user
+	Session struct {
+		ID         string `json:"id"`
+		UserID     int    `json:"user_id"`
+		TerminalID int    `json:"terminal_id,omitempty"`
+		Device     string `json:"device"`
+		IP         string `json:"ip"`
+		CreatedAt  int64  `json:"created_at"`
+		LastUsedAt int64  `json:"last_used_at"`
+		Current    bool   `json:"current"`
+	}
+
+	RefreshForm struct {
+		RefreshToken string `json:"refresh_token" form:"refresh_token"`
+	}
+
+	// APIKey authenticate a machine integration with X-API-Key header, it is
+	// only granted its scopes (permission names). The key is only returned when
+	// it is issued or rotated and stored hashed, the rotated key keep working
+	// until PreviousValidUntil so the integration can switch without downtime
+	APIKey struct {
+		ID                 int      `json:"id"`
+		Name               string   `json:"name" form:"name" binding:"required"`
+		Prefix             string   `json:"prefix"`
+		Key                string   `json:"key,omitempty"`
+		KeyHash            string   `json:"-"`
+		Scopes             []string `json:"scopes" form:"scopes" binding:"required,min=1"`
+		ExpiresAt          int64    `json:"expires_at,omitempty" form:"expires_at"`
+		PreviousValidUntil int64    `json:"previous_valid_until,omitempty"`
+		LastUsedAt         int64    `json:"last_used_at,omitempty"`
+		RevokedAt          int64    `json:"revoked_at,omitempty"`
+		CreatedBy          int      `json:"created_by"`
+		CreatedAt          int64    `json:"created_at"`
+		// CreatorPermissions is set by the handler, the scopes must be a
+		// subset of it so a key never get more than its creator
+		CreatorPermissions []string `json:"-" form:"-"`
+	}
+
+	// OverrideForm is filled on the requester terminal by the approver
+	OverrideForm struct {
+		ApproverID  int    `json:"approver_id" form:"approver_id" binding:"required"`
+		PIN         string `json:"pin" form:"pin" binding:"required"`
+		Action      string `json:"action" form:"action" binding:"required"`
+		Reason      string `json:"reason" form:"reason"`
+		RequestedBy int    `json:"-" form:"-"`
+	}
+
+	// Override is a short-lived single-use approval of one action, the
+	// token is only returned when it is approved and stored hashed
+	Override struct {
+		ID          int    `json:"id"`
+		Token       string `json:"token,omitempty"`
+		Action      string `json:"action"`
+		Reason      string `json:"reason"`
+		ApprovedBy  int    `json:"approved_by"`
+		RequestedBy int    `json:"requested_by"`
+		ExpiresAt   int64  `json:"expires_at"`
+		UsedBy      int    `json:"used_by,omitempty"`
+		UsedAt      int64  `json:"used_at,omitempty"`
+		CreatedAt   int64  `json:"created_at"`
+	}
+
+	IOverrideRepository interface {
+		// UserPIN return sql.ErrNoRows when the user does not exist
+		UserPIN(ctx context.Context, userID int) (pin *UserPIN, err error)
+		// UpdateUserPIN return sql.ErrNoRows when the user does not exist
+		UpdateUserPIN(ctx context.Context, userID int, pin string) error
+		// TerminalUsers list the users that are not deleted by name
+		TerminalUsers(ctx context.Context) (users []*TerminalUser, err error)
+		// UserBadge return sql.ErrNoRows when no user has the badge
+		UserBadge(ctx context.Context, badgeHash string) (pin *UserPIN, err error)
+		// UpdateUserBadge return sql.ErrNoRows when the user does not exist
+		UpdateUserBadge(ctx context.Context, userID int, badgeHash string) error
+		// PINFailed count the failure, the pin is locked until lockedUntil
+		// once the failures reach maxAttempts
+		PINFailed(ctx context.Context, userID, maxAttempts int, lockedUntil int64) error
+		PINSucceeded(ctx context.Context, userID int) error
+		Create(ctx context.Context, override *Override, tokenHash string) (data *Override, err error)
+		// Consume mark the unused and unexpired token of the action as used,
+		// sql.ErrNoRows when there is no such token
+		Consume(ctx context.Context, tokenHash, action string, usedBy int) (data *Override, err error)
+	}
+
+	IOverrideService interface {
+		SetPIN(ctx context.Context, userID int, pin string) *utils.ServiceError
+		Approve(ctx context.Context, form *OverrideForm) (override *Override, errData *utils.ServiceError)
+		Consume(ctx context.Context, token, action string, usedBy int) (override *Override, errData *utils.ServiceError)
+	}
+
+	IAPIKeyRepository interface {
+		All(ctx context.Context) (keys []*APIKey, err error)
+		Find(ctx context.Context, id int) (key *APIKey, err error)
+		// FindByHash return the active key of the current or the rotated
+		// (still in grace) key hash, sql.ErrNoRows when there is none
+		FindByHash(ctx context.Context, keyHash string, now int64) (key *APIKey, err error)
+		Create(ctx context.Context, key *APIKey) (data *APIKey, err error)
+		// Rotate replace the key hash, the current one is kept until previousValidUntil
+		Rotate(ctx context.Context, id int, keyHash, prefix string, previousValidUntil int64) (data *APIKey, err error)
+		Revoke(ctx context.Context, id int, revokedAt int64) error
+		Touch(ctx context.Context, id int, usedAt int64) error
+	}
+
+	IAPIKeyService interface {
+		APIKeyList(ctx context.Context) (keys []*APIKey, errData *utils.ServiceError)
+		AddAPIKey(ctx context.Context, data *APIKey) (key *APIKey, errData *utils.ServiceError)
+		RotateAPIKey(ctx context.Context, id int) (key *APIKey, errData *utils.ServiceError)
+		RevokeAPIKey(ctx context.Context, id int) *utils.ServiceError
+		// Authenticate resolve the active api key of the plain key
+		Authenticate(ctx context.Context, key string) (data *APIKey, errData *utils.ServiceError)
+	}
+
+	ITerminalService interface {
+		TerminalList(ctx context.Context) (terminals []*Terminal, errData *utils.ServiceError)
+		AddTerminal(ctx context.Context, data *Terminal) (terminal *Terminal, errData *utils.ServiceError)
+		EditTerminal(ctx context.Context, data *Terminal) (terminal *Terminal, errData *utils.ServiceError)
+		DeleteTerminal(ctx context.Context, data *Terminal) *utils.ServiceError
+		RestoreTerminal(ctx context.Context, data *Terminal) (terminal *Terminal, errData *utils.ServiceError)
+		SetBadge(ctx context.Context, userID int, badge string) *utils.ServiceError
+		// TerminalUsers list the users to pick from on the terminal
+		TerminalUsers(ctx context.Context, key string) (users []*TerminalUser, errData *utils.ServiceError)
+		// QuickLogin start a session on the terminal, it replace
+		// the session of the previous user (quick switch), badge
+		// failures are counted per terminal and per ip
+		QuickLogin(ctx context.Context, key string, form *QuickLoginForm) (session *AuthSession, errData *utils.ServiceError)
+		// TouchSession check the session is still the active one of
+		// the terminal and extend its idle timeout
+		TouchSession(ctx context.Context, terminalID int, sessionID string) *utils.ServiceError
+	}
+
+	ISessionService interface {
+		// Start the session of the user on the device and issue its tokens
+		Start(ctx context.Context, session *AuthSession, device, ip string) (data *AuthSession, errData *utils.ServiceError)
+		// Refresh rotate the refresh token, reusing a rotated token revoke the session
+		Refresh(ctx context.Context, refreshToken, device, ip string) (data *AuthSession, errData *utils.ServiceError)
+		SessionList(ctx context.Context, userID int, currentID string) (sessions []*Session, errData *utils.ServiceError)
+		Revoke(ctx context.Context, userID int, sessionID string) *utils.ServiceError
+		RevokeAll(ctx context.Context, userID int) *utils.ServiceError
+		// Validate reject the access token of a revoked session
+		Validate(ctx context.Context, sessionID string) *utils.ServiceError
 	}
 
 	// IAccountService contract
@@ -36,12 +319,17 @@ type (
 		AddRole(ctx context.Context, data *Role) (role *Role, errData *utils.ServiceError)
 		EditRole(ctx context.Context, data *Role) (role *Role, errData *utils.ServiceError)
 		DeleteRole(ctx context.Context, data *Role) *utils.ServiceError
+		RestoreRole(ctx context.Context, data *Role) (role *Role, errData *utils.ServiceError)
+		PermissionList(ctx context.Context) (permissions []*Permission, errData *utils.ServiceError)
+		// RolePermissions resolve the permissions granted to the role
+		RolePermissions(ctx context.Context, roleID int) (permissions []string, errData *utils.ServiceError)
 
 		UserList(ctx context.Context) (users []*User, errData *utils.ServiceError)
 		ShowUser(ctx context.Context, id int) (user *User, errData *utils.ServiceError)
 		AddUser(ctx context.Context, data *User) (user *User, errData *utils.ServiceError)
 		EditUser(ctx context.Context, data *User) (user *User, errData *utils.ServiceError)
 		DeleteUser(ctx context.Context, data *User) *utils.ServiceError
+		RestoreUser(ctx context.Context, data *User) (user *User, errData *utils.ServiceError)
 
 		VerifyUserCredentials(ctx context.Context, username, password string) (data any, errData *utils.ServiceError)
 	}
@@ -0,0 +1,87 @@
+package model
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/aasumitro/posbe/pkg/utils"
+)
+
+const (
+	// AuditEntryKey hold the *AuditEntry of the request in the gin context
+	AuditEntryKey = "audit_entry"
+
+	AuditActionCreate = "create"
+	AuditActionUpdate = "update"
+	AuditActionDelete = "delete"
+	// AuditActionRestore is a PATCH on a /restore route
+	AuditActionRestore = "restore"
+)
+
+type (
+	// AuditEntry is a mutating request of a user or an api key, Before is
+	// the row loaded before the change and After the respond data
+	AuditEntry struct {
+		ID         int64                   `json:"id"`
+		ActorID    int                     `json:"actor_id,omitempty"`
+		APIKeyID   int                     `json:"api_key_id,omitempty"`
+		TerminalID int                     `json:"terminal_id,omitempty"`
+		SessionID  string                  `json:"session_id,omitempty"`
+		Action     string                  `json:"action"`
+		Method     string                  `json:"method"`
+		Route      string                  `json:"route"`
+		EntityType string                  `json:"entity_type"`
+		EntityID   string                  `json:"entity_id,omitempty"`
+		Status     int                     `json:"status"`
+		Before     json.RawMessage         `json:"before,omitempty" swaggertype:"object"`
+		After      json.RawMessage         `json:"after,omitempty" swaggertype:"object"`
+		Changes    map[string]*AuditChange `json:"changes,omitempty"`
+		IP         string                  `json:"ip"`
+		CreatedAt  int64                   `json:"created_at"`
+	}
+
+	// AuditChange is a top level field changed between Before and After
+	AuditChange struct {
+		Before any `json:"before"`
+		After  any `json:"after"`
+	}
+
+	AuditFilter struct {
+		ActorID    int    `form:"actor_id"`
+		APIKeyID   int    `form:"api_key_id"`
+		TerminalID int    `form:"terminal_id"`
+		Action     string `form:"action"`
+		EntityType string `form:"entity_type"`
+		EntityID   string `form:"entity_id"`
+		From       int64  `form:"from"`
+		To         int64  `form:"to"`
+		Limit      int    `form:"limit" binding:"omitempty,min=1,max=500"`
+		Offset     int    `form:"offset" binding:"omitempty,min=0"`
+	}
+
+	IAuditRepository interface {
+		Insert(ctx context.Context, entries []*AuditEntry) error
+		// Search return the newest entries first
+		Search(ctx context.Context, filter *AuditFilter) (entries []*AuditEntry, err error)
+	}
+
+	IAuditService interface {
+		// Record queue the entry without blocking the request,
+		// queued entries are written in batches by Run
+		Record(entry *AuditEntry)
+		// Run write the queued entries until the context is done,
+		// what is left in the queue is written before it return
+		Run(ctx context.Context)
+		AuditList(ctx context.Context, filter *AuditFilter) (entries []*AuditEntry, errData *utils.ServiceError)
+	}
+)
+
+// AuditBefore keep the row before the change on the audit entry of the request,
+// the first call win so the row is the one before any change
+func AuditBefore(ctx context.Context, data any) {
+	entry, ok := ctx.Value(AuditEntryKey).(*AuditEntry)
+	if !ok || entry.Before != nil {
+		return
+	}
+	entry.Before, _ = json.Marshal(data)
+}
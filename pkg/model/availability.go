@@ -0,0 +1,112 @@
+package model
+
+import (
+	"context"
+
+	"github.com/aasumitro/posbe/pkg/utils"
+)
+
+const (
+	// AvailabilityTopic is the broker topic terminals subscribe to
+	AvailabilityTopic = "catalog:availability"
+
+	AvailabilityEventSoldOut   = "sold_out"
+	AvailabilityEventRestocked = "restocked"
+	AvailabilityEventSchedule  = "schedule_updated"
+
+	UnavailableSchedule = "schedule"
+	UnavailableSoldOut  = "sold_out"
+	UnavailableNotFound = "not_found"
+)
+
+type (
+	// AvailabilityRule is a selling window of a product or of every product
+	// in a category, product rules replace the category rules. Days use
+	// time.Weekday (0 sunday, empty mean every day), time window is HH:MM
+	// in store timezone and may pass midnight, empty mean all day
+	AvailabilityRule struct {
+		ID         int    `json:"id"`
+		ProductID  int    `json:"product_id,omitempty"`
+		CategoryID int    `json:"category_id,omitempty"`
+		Days       []int  `json:"days" binding:"dive,min=0,max=6"`
+		StartTime  string `json:"start_time" binding:"omitempty,datetime=15:04"`
+		EndTime    string `json:"end_time" binding:"omitempty,datetime=15:04"`
+	}
+
+	// AvailabilityRuleForm replace every rule of the product or the category,
+	// empty rules make it always available again
+	AvailabilityRuleForm struct {
+		ProductID  int                 `json:"product_id"`
+		CategoryID int                 `json:"category_id"`
+		Rules      []*AvailabilityRule `json:"rules" binding:"dive"`
+	}
+
+	// SoldOutItem is an 86'd product or variant, it expire when the store
+	// shift that was open at the time is closed, or stay until cleared
+	// when there was no open shift
+	SoldOutItem struct {
+		ID           int   `json:"id"`
+		ProductID    int   `json:"product_id" binding:"required"`
+		VariantID    int   `json:"variant_id,omitempty"`
+		StoreShiftID int   `json:"store_shift_id,omitempty"`
+		CreatedBy    int   `json:"created_by,omitempty"`
+		CreatedAt    int64 `json:"created_at"`
+	}
+
+	ProductAvailability struct {
+		ProductID         int    `json:"product_id"`
+		Available         bool   `json:"available"`
+		Reason            string `json:"reason,omitempty"`
+		SoldOutVariantIDs []int  `json:"sold_out_variant_ids"`
+	}
+
+	AvailabilityCheckItem struct {
+		ProductID int `json:"product_id" binding:"required"`
+		VariantID int `json:"variant_id"`
+	}
+
+	// AvailabilityCheckForm is validated on order entry, At default to now
+	AvailabilityCheckForm struct {
+		At    int64                    `json:"at" binding:"min=0"`
+		Items []*AvailabilityCheckItem `json:"items" binding:"required,min=1,dive"`
+	}
+
+	AvailabilityError struct {
+		ProductID int    `json:"product_id"`
+		VariantID int    `json:"variant_id,omitempty"`
+		Reason    string `json:"reason"`
+		Message   string `json:"message"`
+	}
+
+	// AvailabilityEvent is broadcast to terminals on every change
+	AvailabilityEvent struct {
+		Type       string `json:"type"`
+		ProductID  int    `json:"product_id,omitempty"`
+		VariantID  int    `json:"variant_id,omitempty"`
+		CategoryID int    `json:"category_id,omitempty"`
+		At         int64  `json:"at"`
+	}
+
+	IAvailabilityRepository interface {
+		Rules(ctx context.Context) (data []*AvailabilityRule, err error)
+		ReplaceRules(ctx context.Context, form *AvailabilityRuleForm) error
+		// SoldOut return only the items that are still in effect
+		SoldOut(ctx context.Context) (data []*SoldOutItem, err error)
+		MarkSoldOut(ctx context.Context, item *SoldOutItem) (data *SoldOutItem, err error)
+		// ClearSoldOut return sql.ErrNoRows when the item is not 86'd
+		ClearSoldOut(ctx context.Context, item *SoldOutItem) error
+		// ProductCategories map every product id to its category id
+		ProductCategories(ctx context.Context) (categories map[int]int, err error)
+	}
+
+	ICatalogAvailabilityService interface {
+		AvailabilityRuleList(ctx context.Context) (rules []*AvailabilityRule, errData *utils.ServiceError)
+		SaveAvailabilityRules(ctx context.Context, form *AvailabilityRuleForm) *utils.ServiceError
+		SoldOutList(ctx context.Context) (items []*SoldOutItem, errData *utils.ServiceError)
+		MarkSoldOut(ctx context.Context, data *SoldOutItem) (item *SoldOutItem, errData *utils.ServiceError)
+		ClearSoldOut(ctx context.Context, data *SoldOutItem) *utils.ServiceError
+		AvailabilityBoard(ctx context.Context) (board []*ProductAvailability, errData *utils.ServiceError)
+		CheckAvailability(ctx context.Context, form *AvailabilityCheckForm) *utils.ServiceError
+		Subscribe(ctx context.Context) (events <-chan []byte, closeFn func() error)
+	}
+)
@@ -3,7 +3,12 @@ package model
 import (
 	"context"
 	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"strings"
 
+	"github.com/aasumitro/posbe/pkg/money"
 	"github.com/aasumitro/posbe/pkg/utils"
 )
 
@@ -12,12 +17,14 @@ type (
 		ID          int            `json:"id"`
 		Name        string         `json:"name" form:"name" binding:"required"`
 		Subcategory []*Subcategory `json:"subcategories,omitempty" binding:"-"`
+		SoftDelete
 	}
 
 	Subcategory struct {
 		ID         int    `json:"id"`
 		CategoryID int    `json:"category_id" form:"category_id" binding:"required"`
 		Name       string `json:"name" form:"name" binding:"required"`
+		SoftDelete
 	}
 
 	Unit struct {
@@ -25,30 +32,226 @@ type (
 		Magnitude string `json:"magnitude" form:"magnitude" binding:"required"` // e.g: mass [length, time]
 		Name      string `json:"name" form:"name" binding:"required"`           // e.g: kilogram [metre, second]
 		Symbol    string `json:"symbol" form:"symbol" binding:"required"`       // e.g: kg [m, s]
+		SoftDelete
 	}
 
 	Addon struct {
-		ID          int     `json:"id"`
-		Name        string  `json:"name" form:"name" binding:"required"`
-		Description string  `json:"description" form:"description"  binding:"required"`
-		Price       float32 `json:"price" form:"price" binding:"required"`
+		ID          int          `json:"id"`
+		Name        string       `json:"name" form:"name" binding:"required"`
+		Description string       `json:"description" form:"description"  binding:"required"`
+		Price       money.Amount `json:"price" form:"price" binding:"required"`
+		SoftDelete
 	}
 
 	Product struct {
-		ID              int               `json:"id"`
-		CategoryID      int               `json:"category_id" form:"category_id" binding:"required"`
-		SubcategoryID   int               `json:"subcategory_id" form:"subcategory_id" binding:"required"`
-		Sku             string            `json:"sku" form:"sku" binding:"required"`
-		Image           sql.NullString    `json:"image" form:"image"`
-		Gallery         sql.NullString    `json:"gallery" form:"gallery"`
-		Name            string            `json:"name" form:"name" binding:"required"`
-		Price           float32           `json:"price" form:"price" binding:"required"`
-		Description     sql.NullString    `json:"description" form:"description"`
-		Category        *Category         `json:"category,omitempty" binding:"-"`
-		Subcategory     *Subcategory      `json:"subcategory,omitempty" binding:"-"`
-		ProductVariants []*ProductVariant `json:"variants,omitempty" form:"variants" binding:"required"`
+		ID              int                  `json:"id"`
+		CategoryID      int                  `json:"category_id" form:"category_id" binding:"required"`
+		SubcategoryID   int                  `json:"subcategory_id" form:"subcategory_id" binding:"required"`
+		Sku             string               `json:"sku" form:"sku" binding:"required"`
+		Image           sql.NullString       `json:"image" form:"image"`
+		Gallery         ProductGallery       `json:"gallery" form:"-"`
+		Name            string               `json:"name" form:"name" binding:"required"`
+		Price           money.Amount         `json:"price" form:"price" binding:"required"`
+		Description     sql.NullString       `json:"description" form:"description"`
+		Category        *Category            `json:"category,omitempty" binding:"-"`
+		Subcategory     *Subcategory         `json:"subcategory,omitempty" binding:"-"`
+		ProductVariants []*ProductVariant    `json:"variants,omitempty" form:"variants" binding:"required"`
+		Availability    *ProductAvailability `json:"availability,omitempty" binding:"-"`
+		SoftDelete
 	}
 
+	// AddonGroup is a modifier group, e.g: "Milk choice" (min 1, max 1)
+	// or "Extra toppings" (min 0, max 3), MaxSelect 0 mean unlimited
+	AddonGroup struct {
+		ID          int      `json:"id"`
+		Name        string   `json:"name" form:"name" binding:"required"`
+		Description string   `json:"description" form:"description"`
+		MinSelect   int      `json:"min_select" form:"min_select" binding:"min=0"`
+		MaxSelect   int      `json:"max_select" form:"max_select" binding:"min=0"`
+		AddonIDs    []int    `json:"addon_ids,omitempty" form:"addon_ids" binding:"required,min=1"`
+		Addons      []*Addon `json:"addons,omitempty" binding:"-"`
+	}
+
+	// AddonGroupAssignment link the group to a product or to every product in a category
+	AddonGroupAssignment struct {
+		AddonGroupID int `json:"addon_group_id"`
+		ProductID    int `json:"product_id" form:"product_id"`
+		CategoryID   int `json:"category_id" form:"category_id"`
+	}
+
+	// ProductAddonPrice override addon price for a single product
+	ProductAddonPrice struct {
+		ProductID int          `json:"product_id"`
+		AddonID   int          `json:"addon_id" form:"addon_id" binding:"required"`
+		Price     money.Amount `json:"price" form:"price" binding:"min=0"`
+	}
+
+	AddonSelection struct {
+		AddonGroupID int `json:"addon_group_id" binding:"required"`
+		AddonID      int `json:"addon_id" binding:"required"`
+		Qty          int `json:"qty" binding:"min=0"`
+	}
+
+	AddonSelectionForm struct {
+		Addons []*AddonSelection `json:"addons" binding:"dive"`
+	}
+
+	// AddonSelectionLine is a validated selection with the resolved price
+	AddonSelectionLine struct {
+		AddonGroupID int          `json:"addon_group_id"`
+		AddonID      int          `json:"addon_id"`
+		Name         string       `json:"name"`
+		Qty          int          `json:"qty"`
+		Price        money.Amount `json:"price"`
+		Subtotal     money.Amount `json:"subtotal"`
+	}
+
+	AddonSelectionResult struct {
+		Lines []*AddonSelectionLine `json:"lines"`
+		Total money.Amount          `json:"total"`
+	}
+
+	AddonSelectionError struct {
+		AddonGroupID int    `json:"addon_group_id"`
+		AddonID      int    `json:"addon_id,omitempty"`
+		Message      string `json:"message"`
+	}
+
+	IAddonGroupRepository interface {
+		ICRUDRepository[AddonGroup]
+		Assign(ctx context.Context, assignment *AddonGroupAssignment) error
+		Unassign(ctx context.Context, assignment *AddonGroupAssignment) error
+		// ProductGroups return groups assigned to the product or to its category,
+		// addon price already use the product override when exists
+		ProductGroups(ctx context.Context, productID int) (data []*AddonGroup, err error)
+		SetProductPrice(ctx context.Context, price *ProductAddonPrice) error
+		DeleteProductPrice(ctx context.Context, price *ProductAddonPrice) error
+	}
+
+	ICatalogModifierService interface {
+		AddonGroupList(ctx context.Context) (groups []*AddonGroup, errData *utils.ServiceError)
+		AddAddonGroup(ctx context.Context, data *AddonGroup) (group *AddonGroup, errData *utils.ServiceError)
+		EditAddonGroup(ctx context.Context, data *AddonGroup) (group *AddonGroup, errData *utils.ServiceError)
+		DeleteAddonGroup(ctx context.Context, data *AddonGroup) *utils.ServiceError
+		AssignAddonGroup(ctx context.Context, data *AddonGroupAssignment) *utils.ServiceError
+		UnassignAddonGroup(ctx context.Context, data *AddonGroupAssignment) *utils.ServiceError
+
+		ProductAddonGroups(ctx context.Context, productID int) (groups []*AddonGroup, errData *utils.ServiceError)
+		SetProductAddonPrice(ctx context.Context, data *ProductAddonPrice) *utils.ServiceError
+		DeleteProductAddonPrice(ctx context.Context, data *ProductAddonPrice) *utils.ServiceError
+		// ValidateAddonSelection check the selected addons against the product group rules,
+		// used by order entry before the line is accepted
+		ValidateAddonSelection(ctx context.Context, productID int, selections []*AddonSelection) (result *AddonSelectionResult, errData *utils.ServiceError)
+	}
+
+	// Bundle is a product sold as a set menu, it is made of fixed items
+	// and choice slots, the bundle price is the product price
+	Bundle struct {
+		ProductID int           `json:"product_id"`
+		Items     []*BundleItem `json:"items" binding:"dive"`
+		Slots     []*BundleSlot `json:"slots" binding:"dive"`
+	}
+
+	BundleItem struct {
+		ID        int    `json:"id"`
+		ProductID int    `json:"product_id" binding:"required"`
+		VariantID int    `json:"variant_id"`
+		Qty       int    `json:"qty" binding:"required,min=1"`
+		Name      string `json:"name,omitempty" binding:"-"`
+	}
+
+	// BundleSlot let customer pick Qty products, from the listed options
+	// or from every product of the subcategory when SubcategoryID is set
+	BundleSlot struct {
+		ID            int                 `json:"id"`
+		Name          string              `json:"name" binding:"required"`
+		SubcategoryID int                 `json:"subcategory_id"`
+		Qty           int                 `json:"qty" binding:"required,min=1"`
+		Options       []*BundleSlotOption `json:"options" binding:"dive"`
+	}
+
+	// BundleSlotOption with VariantID 0 accept every variant of the product
+	BundleSlotOption struct {
+		ProductID int          `json:"product_id" binding:"required"`
+		VariantID int          `json:"variant_id"`
+		Upcharge  money.Amount `json:"upcharge" binding:"min=0"`
+		Name      string       `json:"name,omitempty" binding:"-"`
+	}
+
+	BundleChoice struct {
+		SlotID    int `json:"slot_id" binding:"required"`
+		ProductID int `json:"product_id" binding:"required"`
+		VariantID int `json:"variant_id"`
+	}
+
+	BundleExpandForm struct {
+		Qty     int             `json:"qty" binding:"min=0"`
+		Choices []*BundleChoice `json:"choices" binding:"dive"`
+	}
+
+	// BundleComponent is a single product of expanded bundle, Revenue is the
+	// share of bundle price (plus its own upcharge) allocated for reporting
+	BundleComponent struct {
+		ProductID     int          `json:"product_id"`
+		VariantID     int          `json:"variant_id,omitempty"`
+		CategoryID    int          `json:"category_id"`
+		SubcategoryID int          `json:"subcategory_id"`
+		SlotID        int          `json:"slot_id,omitempty"`
+		Name          string       `json:"name"`
+		Qty           int          `json:"qty"`
+		ListPrice     money.Amount `json:"list_price"`
+		Upcharge      money.Amount `json:"upcharge"`
+		Revenue       money.Amount `json:"revenue"`
+	}
+
+	BundleExpansion struct {
+		ProductID  int                `json:"product_id"`
+		Name       string             `json:"name"`
+		Qty        int                `json:"qty"`
+		UnitPrice  money.Amount       `json:"unit_price"`
+		Total      money.Amount       `json:"total"`
+		Components []*BundleComponent `json:"components"`
+	}
+
+	BundleChoiceError struct {
+		SlotID    int    `json:"slot_id"`
+		ProductID int    `json:"product_id,omitempty"`
+		Message   string `json:"message"`
+	}
+
+	IBundleRepository interface {
+		// Find return sql.ErrNoRows when the product is not a bundle
+		Find(ctx context.Context, productID int) (data *Bundle, err error)
+		// Save replace every item and slot of the bundle
+		Save(ctx context.Context, bundle *Bundle) (data *Bundle, err error)
+		Delete(ctx context.Context, productID int) error
+	}
+
+	ICatalogBundleService interface {
+		BundleDetail(ctx context.Context, productID int) (bundle *Bundle, errData *utils.ServiceError)
+		SaveBundle(ctx context.Context, data *Bundle) (bundle *Bundle, errData *utils.ServiceError)
+		DeleteBundle(ctx context.Context, productID int) *utils.ServiceError
+		// ExpandBundle validate the slot choices and break the bundle down into
+		// components for kitchen routing, stock depletion and revenue reporting
+		ExpandBundle(ctx context.Context, productID int, form *BundleExpandForm) (expansion *BundleExpansion, errData *utils.ServiceError)
+	}
+
+	// ProductImage is a single uploaded image of product gallery,
+	// keys are the storage keys used to remove the files
+	ProductImage struct {
+		ID           string `json:"id"`
+		URL          string `json:"url"`
+		ThumbnailURL string `json:"thumbnail_url"`
+		Key          string `json:"key"`
+		ThumbnailKey string `json:"thumbnail_key"`
+		Width        int    `json:"width"`
+		Height       int    `json:"height"`
+		Cover        bool   `json:"cover"`
+	}
+
+	// ProductGallery is stored as JSON array
+	ProductGallery []*ProductImage
+
 	ProductVariant struct {
 		ID          int            `json:"id"`
 		ProductID   int            `json:"product_id" form:"product_id" binding:"required"`
@@ -57,8 +260,88 @@ type (
 		Type        string         `json:"type" form:"type" binding:"required"`
 		Name        string         `json:"name" form:"name" binding:"required"`
 		Description sql.NullString `json:"description" form:"description"`
-		Price       float32        `json:"price" form:"price" binding:"required"`
+		Price       money.Amount   `json:"price" form:"price" binding:"required"`
 		Unit        *Unit          `json:"unit,omitempty" binding:"-"`
+		SoftDelete
+	}
+
+	// CatalogImportSnapshot hold the lookup keys of existing catalog data,
+	// used to resolve references and to decide create or update on import
+	CatalogImportSnapshot struct {
+		Categories    map[string]int // key: category name
+		Subcategories map[string]int // key: category name, subcategory name
+		Units         map[string]int // key: unit symbol
+		Addons        map[string]int // key: addon name
+		Products      map[string]int // key: product sku
+		Variants      map[string]int // key: product sku, variant name
+	}
+
+	// CatalogImportPlan is validated import data, references are
+	// still kept as name/sku and resolved when applied
+	CatalogImportPlan struct {
+		Categories    []*Category
+		Subcategories []*CatalogImportSubcategory
+		Units         []*Unit
+		Addons        []*Addon
+		Products      []*CatalogImportProduct
+		Variants      []*CatalogImportVariant
+	}
+
+	CatalogImportSubcategory struct {
+		Subcategory
+		Category string
+	}
+
+	CatalogImportProduct struct {
+		Product
+		Category    string
+		Subcategory string
+	}
+
+	CatalogImportVariant struct {
+		ProductVariant
+		ProductSku string
+		UnitSymbol string
+	}
+
+	CatalogImportForm struct {
+		Entity string `form:"entity"`
+		DryRun bool   `form:"dry_run"`
+	}
+
+	CatalogImportCount struct {
+		Created int `json:"created"`
+		Updated int `json:"updated"`
+		Skipped int `json:"skipped"`
+	}
+
+	CatalogImportError struct {
+		Entity  string `json:"entity"`
+		Line    int    `json:"line"`
+		Field   string `json:"field,omitempty"`
+		Message string `json:"message"`
+	}
+
+	CatalogImportReport struct {
+		DryRun  bool                           `json:"dry_run"`
+		Applied bool                           `json:"applied"`
+		Summary map[string]*CatalogImportCount `json:"summary"`
+		Errors  []*CatalogImportError          `json:"errors"`
+	}
+
+	ICatalogImportRepository interface {
+		Snapshot(ctx context.Context) (snapshot *CatalogImportSnapshot, err error)
+		// Apply upsert the whole plan in a single transaction
+		Apply(ctx context.Context, plan *CatalogImportPlan) error
+	}
+
+	ICatalogImportService interface {
+		Import(ctx context.Context, sheets []*utils.ImportSheet, dryRun bool) (report *CatalogImportReport, errData *utils.ServiceError)
+	}
+
+	ICatalogMediaService interface {
+		AddProductImage(ctx context.Context, productID int, upload *utils.UploadImage, cover bool) (product *Product, errData *utils.ServiceError)
+		DeleteProductImage(ctx context.Context, productID int, imageID string) (product *Product, errData *utils.ServiceError)
 	}
 
 	ICatalogCommonService interface {
@@ -66,27 +349,32 @@ type (
 		AddUnit(ctx context.Context, data *Unit) (units *Unit, errData *utils.ServiceError)
 		EditUnit(ctx context.Context, data *Unit) (units *Unit, errData *utils.ServiceError)
 		DeleteUnit(ctx context.Context, data *Unit) *utils.ServiceError
+		RestoreUnit(ctx context.Context, data *Unit) (units *Unit, errData *utils.ServiceError)
 
 		CategoryList(ctx context.Context) (units []*Category, errData *utils.ServiceError)
 		AddCategory(ctx context.Context, data *Category) (units *Category, errData *utils.ServiceError)
 		EditCategory(ctx context.Context, data *Category) (units *Category, errData *utils.ServiceError)
 		DeleteCategory(ctx context.Context, data *Category) *utils.ServiceError
+		RestoreCategory(ctx context.Context, data *Category) (units *Category, errData *utils.ServiceError)
 
 		SubcategoryList(ctx context.Context) (units []*Subcategory, errData *utils.ServiceError)
 		AddSubcategory(ctx context.Context, data *Subcategory) (units *Subcategory, errData *utils.ServiceError)
 		EditSubcategory(ctx context.Context, data *Subcategory) (units *Subcategory, errData *utils.ServiceError)
 		DeleteSubcategory(ctx context.Context, data *Subcategory) *utils.ServiceError
+		RestoreSubcategory(ctx context.Context, data *Subcategory) (units *Subcategory, errData *utils.ServiceError)
 
 		AddonList(ctx context.Context) (units []*Addon, errData *utils.ServiceError)
 		AddAddon(ctx context.Context, data *Addon) (units *Addon, errData *utils.ServiceError)
 		EditAddon(ctx context.Context, data *Addon) (units *Addon, errData *utils.ServiceError)
 		DeleteAddon(ctx context.Context, data *Addon) *utils.ServiceError
+		RestoreAddon(ctx context.Context, data *Addon) (units *Addon, errData *utils.ServiceError)
 	}
 
 	ICatalogProductService interface {
 		AddProductVariant(ctx context.Context, data *ProductVariant) (variant *ProductVariant, errData *utils.ServiceError)
 		EditProductVariant(ctx context.Context, data *ProductVariant) (variant *ProductVariant, errData *utils.ServiceError)
 		DeleteProductVariant(ctx context.Context, data *ProductVariant) *utils.ServiceError
+		RestoreProductVariant(ctx context.Context, data *ProductVariant) (variant *ProductVariant, errData *utils.ServiceError)
 
 		ProductSearch(ctx context.Context, keys []FindWith, values []any) (products []*Product, errData *utils.ServiceError)
 		ProductList(ctx context.Context) (products []*Product, errData *utils.ServiceError)
@@ -94,5 +382,45 @@ type (
 		AddProduct(ctx context.Context, data *Product) (product *Product, errData *utils.ServiceError)
 		EditProduct(ctx context.Context, data *Product) (product *Product, errData *utils.ServiceError)
 		DeleteProduct(ctx context.Context, data *Product) *utils.ServiceError
+		RestoreProduct(ctx context.Context, data *Product) (product *Product, errData *utils.ServiceError)
 	}
 )
+
+// CatalogImportKey build case-insensitive lookup key from names
+func CatalogImportKey(parts ...string) string {
+	for i := range parts {
+		parts[i] = strings.ToLower(strings.TrimSpace(parts[i]))
+	}
+	return strings.Join(parts, "/")
+}
+
+// Scan implements the sql.Scanner interface
+func (gallery *ProductGallery) Scan(src any) error {
+	var data []byte
+	switch v := src.(type) {
+	case nil:
+		*gallery = ProductGallery{}
+		return nil
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	default:
+		return errors.New("unsupported product gallery value")
+	}
+	images := ProductGallery{}
+	if err := json.Unmarshal(data, &images); err != nil {
+		return err
+	}
+	*gallery = images
+	return nil
+}
+
+// Value implements the driver.Valuer interface
+func (gallery ProductGallery) Value() (driver.Value, error) {
+	if gallery == nil {
+		return "[]", nil
+	}
+	data, err := json.Marshal(gallery)
+	return string(data), err
+}
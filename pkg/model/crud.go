@@ -2,6 +2,14 @@ package model
 
 import "context"
 
+// IncludeDeletedKey hold true in the gin context when the request
+// read soft deleted rows too (include_deleted=true)
+const IncludeDeletedKey = "include_deleted"
+
+// ReassignToKey hold the id of the row that take over the references
+// of the deleted row (reassign_to)
+const ReassignToKey = "reassign_to"
+
 type FindWith int64
 
 const (
@@ -17,6 +25,8 @@ const (
 	FindWithCategoryID
 	FindWithSubcategoryID
 	FindWithPriceInRange
+
+	FindWithKey
 )
 
 type ICRUDRepository[T any] interface {
@@ -27,13 +37,68 @@ type ICRUDRepository[T any] interface {
 	Delete(ctx context.Context, params *T) error
 }
 
+// ISoftDeleteRepository is ICRUDRepository with soft delete, Delete set
+// deleted_at and deleted_by and the deleted rows are left out of the reads
+// unless IncludeDeleted, Restore bring back a deleted row
+type ISoftDeleteRepository[T any] interface {
+	ICRUDRepository[T]
+	Restore(ctx context.Context, params *T) (data *T, err error)
+}
+
 // ICRUDAddOnRepository TODO: rename
 type ICRUDAddOnRepository[T any] interface {
 	AllWhere(ctx context.Context, key FindWith, val any) (data []*T, err error)
-	ICRUDRepository[T]
+	ISoftDeleteRepository[T]
 }
 
 type ICRUDWithSearchRepository[T any] interface {
 	Search(ctx context.Context, keys []FindWith, values []any) (data []*T, err error)
-	ICRUDRepository[T]
+	ISoftDeleteRepository[T]
+}
+
+// SoftDelete is embedded by the models of soft deleted rows,
+// both are nil until the row is deleted
+type SoftDelete struct {
+	DeletedAt *int64 `json:"deleted_at,omitempty"`
+	DeletedBy *int   `json:"deleted_by,omitempty"`
+}
+
+// IncludeDeleted tell the repository to read soft deleted rows too
+func IncludeDeleted(ctx context.Context) bool {
+	include, _ := ctx.Value(IncludeDeletedKey).(bool)
+	return include
+}
+
+// ReassignTo is the row that take over the references of the deleted row,
+// 0 when the request don't ask for it
+func ReassignTo(ctx context.Context) int {
+	id, _ := ctx.Value(ReassignToKey).(int)
+	return id
 }
+
+type (
+	// Reference is the rows of a table that still point to a row
+	Reference struct {
+		Table  string `json:"table"`
+		Column string `json:"column"`
+		Total  int    `json:"total"`
+		IDs    []int  `json:"ids"`
+	}
+
+	// DeleteConflict is the 409 respond of a delete blocked by references
+	DeleteConflict struct {
+		Message    string       `json:"message"`
+		References []*Reference `json:"references"`
+	}
+
+	IReferenceRepository interface {
+		// References list the rows that point to the row of the table,
+		// deleted rows are left out
+		References(ctx context.Context, table string, id int) (refs []*Reference, err error)
+		// Delete soft delete the row of the table once its references are
+		// moved to the row of to (0 to move none), at once, nothing is written
+		// when references are left and they are returned instead, sql.ErrNoRows
+		// when to is missing or deleted
+		Delete(ctx context.Context, table string, id, to int) (refs []*Reference, err error)
+	}
+)
@@ -0,0 +1,42 @@
+package model
+
+import (
+	"context"
+
+	"github.com/aasumitro/posbe/pkg/money"
+	"github.com/aasumitro/posbe/pkg/utils"
+)
+
+type (
+	// CurrencyRate is one entry of the rate history, the latest entry of a
+	// currency is in effect and older entries stay for past conversions
+	CurrencyRate struct {
+		ID        int        `json:"id"`
+		Currency  string     `json:"currency" binding:"required,len=3"`
+		Rate      money.Rate `json:"rate"`
+		CreatedBy int        `json:"created_by"`
+		CreatedAt int64      `json:"created_at"`
+	}
+
+	// StoreCurrency describe the store currency and the rate in effect
+	// of every foreign currency accepted as tender
+	StoreCurrency struct {
+		Base  money.Currency  `json:"base"`
+		Rates []*CurrencyRate `json:"rates"`
+	}
+
+	ICurrencyRateRepository interface {
+		// Latest return the rate in effect of every currency
+		Latest(ctx context.Context) (data []*CurrencyRate, err error)
+		History(ctx context.Context, currency string) (data []*CurrencyRate, err error)
+		// Find return the rate in effect of the currency
+		Find(ctx context.Context, currency string) (data *CurrencyRate, err error)
+		Create(ctx context.Context, rate *CurrencyRate) (data *CurrencyRate, err error)
+	}
+
+	IStoreCurrencyService interface {
+		Currency(ctx context.Context) (data *StoreCurrency, errData *utils.ServiceError)
+		RateHistory(ctx context.Context, currency string) (data []*CurrencyRate, errData *utils.ServiceError)
+		AddRate(ctx context.Context, rate *CurrencyRate) (data *CurrencyRate, errData *utils.ServiceError)
+	}
+)
@@ -0,0 +1,46 @@
+package model
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/aasumitro/posbe/pkg/utils"
+)
+
+// VersionedEntities are the tables with recorded versions,
+// they are used as entity type of the history
+var VersionedEntities = []string{"products", "product_variants", "addons", "store_prefs"}
+
+type (
+	// EntityVersion is a row as it was from valid_from until valid_to
+	// (null while current), data is null when the row is deleted
+	EntityVersion struct {
+		ID         int64                   `json:"id"`
+		EntityType string                  `json:"entity_type"`
+		EntityID   string                  `json:"entity_id"`
+		Version    int                     `json:"version"`
+		Data       json.RawMessage         `json:"data" swaggertype:"object"`
+		Deleted    bool                    `json:"deleted"`
+		Changes    map[string]*AuditChange `json:"changes,omitempty"`
+		ChangedBy  int                     `json:"changed_by,omitempty"`
+		ValidFrom  int64                   `json:"valid_from"`
+		ValidTo    *int64                  `json:"valid_to"`
+	}
+
+	// EntityVersionQuery read the rows in force at the given time,
+	// ids is a comma separated entity ids, empty for every row
+	EntityVersionQuery struct {
+		At  int64  `json:"at" form:"at" binding:"required"`
+		IDs string `json:"ids" form:"ids"`
+	}
+
+	IEntityVersionRepository interface {
+		Versions(ctx context.Context, entityType, entityID string) (versions []*EntityVersion, err error)
+		AsOf(ctx context.Context, entityType string, ids []string, at int64) (versions []*EntityVersion, err error)
+	}
+
+	ICatalogHistoryService interface {
+		History(ctx context.Context, entityType, entityID string) (versions []*EntityVersion, errData *utils.ServiceError)
+		Snapshot(ctx context.Context, entityType string, query *EntityVersionQuery) (versions []*EntityVersion, errData *utils.ServiceError)
+	}
+)
@@ -0,0 +1,126 @@
+package model
+
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/aasumitro/posbe/pkg/money"
+	"github.com/aasumitro/posbe/pkg/utils"
+)
+
+const (
+	PriceChannelDineIn   = "dine_in"
+	PriceChannelTakeaway = "takeaway"
+	PriceChannelDelivery = "delivery"
+
+	PriceItemProduct = "product"
+	PriceItemVariant = "variant"
+	PriceItemAddon   = "addon"
+)
+
+type (
+	// PriceList override product, variant and addon prices when every
+	// condition match, empty condition match anything. Days use time.Weekday
+	// (0 sunday), time window is HH:MM in store timezone and may pass midnight,
+	// effective dates are unix time with 0 as no limit
+	PriceList struct {
+		ID            int              `json:"id"`
+		Name          string           `json:"name" binding:"required"`
+		Channel       string           `json:"channel" binding:"omitempty,oneof=dine_in takeaway delivery"`
+		CustomerTier  string           `json:"customer_tier"`
+		Days          []int            `json:"days" binding:"dive,min=0,max=6"`
+		StartTime     string           `json:"start_time" binding:"omitempty,datetime=15:04"`
+		EndTime       string           `json:"end_time" binding:"omitempty,datetime=15:04"`
+		EffectiveFrom int64            `json:"effective_from" binding:"min=0"`
+		EffectiveTo   int64            `json:"effective_to" binding:"min=0"`
+		Priority      int              `json:"priority"`
+		Disabled      bool             `json:"disabled"`
+		Items         []*PriceListItem `json:"items" binding:"dive"`
+	}
+
+	PriceListItem struct {
+		ItemType string       `json:"item_type" binding:"required,oneof=product variant addon"`
+		ItemID   int          `json:"item_id" binding:"required"`
+		Price    money.Amount `json:"price" binding:"min=0"`
+	}
+
+	// PriceCandidate is a price list item joined with its list
+	PriceCandidate struct {
+		List *PriceList
+		Item *PriceListItem
+	}
+
+	PriceQueryItem struct {
+		ItemType string `json:"item_type" binding:"required,oneof=product variant addon"`
+		ItemID   int    `json:"item_id" binding:"required"`
+	}
+
+	// PriceQuery describe the order context, At default to now
+	PriceQuery struct {
+		Channel      string            `json:"channel" binding:"omitempty,oneof=dine_in takeaway delivery"`
+		CustomerTier string            `json:"customer_tier"`
+		At           int64             `json:"at" binding:"min=0"`
+		Items        []*PriceQueryItem `json:"items" binding:"required,min=1,dive"`
+	}
+
+	// PriceSnapshot is the resolved price kept on the order line,
+	// so later price changes never rewrite a past order
+	PriceSnapshot struct {
+		ItemType      string       `json:"item_type"`
+		ItemID        int          `json:"item_id"`
+		BasePrice     money.Amount `json:"base_price"`
+		Price         money.Amount `json:"price"`
+		PriceListID   int          `json:"price_list_id,omitempty"`
+		PriceListName string       `json:"price_list_name,omitempty"`
+		Channel       string       `json:"channel,omitempty"`
+		CustomerTier  string       `json:"customer_tier,omitempty"`
+		ResolvedAt    int64        `json:"resolved_at"`
+	}
+
+	IPriceListRepository interface {
+		ICRUDRepository[PriceList]
+		// Candidates return items of enabled lists for the requested items
+		Candidates(ctx context.Context, items []*PriceQueryItem) (data []*PriceCandidate, err error)
+		// BasePrices return catalog prices keyed by PriceItemKey
+		BasePrices(ctx context.Context, items []*PriceQueryItem) (prices map[string]money.Amount, err error)
+	}
+
+	ICatalogPriceService interface {
+		PriceListList(ctx context.Context) (lists []*PriceList, errData *utils.ServiceError)
+		AddPriceList(ctx context.Context, data *PriceList) (list *PriceList, errData *utils.ServiceError)
+		EditPriceList(ctx context.Context, data *PriceList) (list *PriceList, errData *utils.ServiceError)
+		DeletePriceList(ctx context.Context, data *PriceList) *utils.ServiceError
+		ResolvePrices(ctx context.Context, query *PriceQuery) (snapshots []*PriceSnapshot, errData *utils.ServiceError)
+	}
+)
+
+// PriceItemKey identify an item across products, variants and addons
+func PriceItemKey(itemType string, itemID int) string {
+	return fmt.Sprintf("%s:%d", itemType, itemID)
+}
+
+// Scan implements the sql.Scanner interface
+func (snapshot *PriceSnapshot) Scan(src any) error {
+	var data []byte
+	switch v := src.(type) {
+	case nil:
+		*snapshot = PriceSnapshot{}
+		return nil
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	default:
+		return errors.New("unsupported price snapshot value")
+	}
+	return json.Unmarshal(data, snapshot)
+}
+
+// Value implements the driver.Valuer interface
+func (snapshot PriceSnapshot) Value() (driver.Value, error) {
+	data, err := json.Marshal(snapshot)
+	return string(data), err
+}
@@ -0,0 +1,52 @@
+package model
+
+import (
+	"context"
+	"io"
+
+	"github.com/aasumitro/posbe/pkg/utils"
+)
+
+type (
+	// ExportDataset describe exportable data and the available columns
+	ExportDataset struct {
+		Name    string               `json:"name"`
+		Columns []utils.ExportColumn `json:"columns"`
+		// Ranged mean dataset can be filtered with from & to (unix time)
+		Ranged bool `json:"ranged"`
+	}
+
+	ExportQuery struct {
+		Dataset string
+		Columns []string
+		From    int64
+		To      int64
+	}
+
+	ExportForm struct {
+		Format   string `form:"format"`
+		Columns  string `form:"columns"`
+		Lang     string `form:"lang"`
+		Timezone string `form:"tz"`
+		From     int64  `form:"from"`
+		To       int64  `form:"to"`
+	}
+
+	// Export is a prepared export,
+	// data will be streamed when WriteTo called
+	Export struct {
+		Filename    string
+		ContentType string
+		WriteTo     func(ctx context.Context, out io.Writer) error
+	}
+
+	IExportRepository interface {
+		Datasets() []*ExportDataset
+		Stream(ctx context.Context, query *ExportQuery, fn func(values []any) error) error
+	}
+
+	IExportService interface {
+		DatasetList(ctx context.Context) (datasets []*ExportDataset, errData *utils.ServiceError)
+		PrepareExport(ctx context.Context, dataset string, form *ExportForm) (export *Export, errData *utils.ServiceError)
+	}
+)
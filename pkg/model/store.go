@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 
+	"github.com/aasumitro/posbe/pkg/money"
 	"github.com/aasumitro/posbe/pkg/utils"
 )
 
@@ -17,6 +18,7 @@ type (
 		UpdatedAt   sql.NullInt64 `json:"updated_at"`
 		Tables      []*Table      `json:"tables,omitempty" binding:"-"`
 		Rooms       []*Room       `json:"rooms,omitempty" binding:"-"`
+		SoftDelete
 	}
 
 	// Table Case Study Restaurant Dine in
@@ -32,6 +34,7 @@ type (
 		Type      string        `json:"type" form:"type"`
 		CreatedAt sql.NullInt64 `json:"created_at"`
 		UpdatedAt sql.NullInt64 `json:"updated_at,omitempty"`
+		SoftDelete
 	}
 
 	// Room Case Study Karaoke
@@ -44,9 +47,10 @@ type (
 		WSize     float32       `json:"w_size" form:"w_size" binding:"required"`
 		HSize     float32       `json:"h_size" form:"h_size" binding:"required"`
 		Capacity  int           `json:"capacity" form:"capacity" binding:"required"`
-		Price     float32       `json:"price" form:"price" binding:"required"`
+		Price     money.Amount  `json:"price" form:"price" binding:"required"`
 		CreatedAt sql.NullInt64 `json:"created_at"`
 		UpdatedAt sql.NullInt64 `json:"updated_at,omitempty"`
+		SoftDelete
 	}
 
 	// Shift is reference section data for store
@@ -73,10 +77,10 @@ type (
 		ShiftID   int           `json:"shift_id"`
 		OpenAt    int64         `json:"open_at"`
 		OpenBy    sql.NullInt64 `json:"open_by"`
-		OpenCash  sql.NullInt64 `json:"open_cash"`
+		OpenCash  money.Amount  `json:"open_cash"`
 		CloseAt   sql.NullInt64 `json:"close_at"`
 		CloseBy   sql.NullInt64 `json:"close_by"`
-		CloseCash sql.NullInt64 `json:"close_cash"`
+		CloseCash money.Amount  `json:"close_cash"`
 		CreatedAt sql.NullInt64 `json:"created_at"`
 		UpdatedAt sql.NullInt64 `json:"updated_at,omitempty"`
 		Shift     *Shift        `json:"shift,omitempty" binding:"-"`
@@ -88,10 +92,10 @@ type (
 	}
 
 	StoreShiftForm struct {
-		ID      int   `json:"-" form:"-"`
-		UserID  int   `json:"-" form:"-"`
-		ShiftID int   `json:"shift_id" form:"shift_id" binding:"required"`
-		Cash    int64 `json:"cash" form:"cash" binding:"required"`
+		ID      int          `json:"-" form:"-"`
+		UserID  int          `json:"-" form:"-"`
+		ShiftID int          `json:"shift_id" form:"shift_id" binding:"required"`
+		Cash    money.Amount `json:"cash" form:"cash" binding:"required"`
 	}
 
 	StoreSetting map[string]interface{}
@@ -101,16 +105,19 @@ type (
 		AddFloor(ctx context.Context, data *Floor) (floor *Floor, errData *utils.ServiceError)
 		EditFloor(ctx context.Context, data *Floor) (floor *Floor, errData *utils.ServiceError)
 		DeleteFloor(ctx context.Context, data *Floor) *utils.ServiceError
+		RestoreFloor(ctx context.Context, data *Floor) (floor *Floor, errData *utils.ServiceError)
 
 		TableList(ctx context.Context) (table []*Table, errData *utils.ServiceError)
 		AddTable(ctx context.Context, data *Table) (table *Table, errData *utils.ServiceError)
 		EditTable(ctx context.Context, data *Table) (table *Table, errData *utils.ServiceError)
 		DeleteTable(ctx context.Context, data *Table) *utils.ServiceError
+		RestoreTable(ctx context.Context, data *Table) (table *Table, errData *utils.ServiceError)
 
 		RoomList(ctx context.Context) (rooms []*Room, errData *utils.ServiceError)
 		AddRoom(ctx context.Context, data *Room) (room *Room, errData *utils.ServiceError)
 		EditRoom(ctx context.Context, data *Room) (room *Room, errData *utils.ServiceError)
 		DeleteRoom(ctx context.Context, data *Room) *utils.ServiceError
+		RestoreRoom(ctx context.Context, data *Room) (room *Room, errData *utils.ServiceError)
 
 		FloorsWith(ctx context.Context, s any) (floors []*Floor, errData *utils.ServiceError)
 	}
@@ -124,6 +131,7 @@ type (
 	IStorePrefService interface {
 		AllPrefs(ctx context.Context) (prefs *StoreSetting, errData *utils.ServiceError)
 		UpdatePrefs(ctx context.Context, key, value string) (prefs *StoreSetting, errData *utils.ServiceError)
+		UploadLogo(ctx context.Context, upload *utils.UploadImage) (prefs *StoreSetting, errData *utils.ServiceError)
 	}
 
 	IStoreShiftRepository interface {
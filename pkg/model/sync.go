@@ -0,0 +1,137 @@
+package model
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/aasumitro/posbe/pkg/utils"
+)
+
+const (
+	SyncOrderApplied   = "applied"
+	SyncOrderUnchanged = "unchanged"
+	SyncOrderRejected  = "rejected"
+
+	// SyncConflictStaleVersion the order was changed on the server since the
+	// version the terminal pushed from, the server order is kept
+	SyncConflictStaleVersion = "stale_version"
+	// SyncConflictOrderClosed the order is paid or void on the server, the server order is kept
+	SyncConflictOrderClosed = "order_closed"
+	// SyncConflictSoldOut the item was 86'd before the order was taken,
+	// it is voided while the order is open and kept once it is paid or void
+	SyncConflictSoldOut = UnavailableSoldOut
+	// SyncConflictNotFound the product or variant of the item is deleted, resolved as
+	// SyncConflictSoldOut, one that never existed can not be priced and is always voided
+	SyncConflictNotFound = UnavailableNotFound
+	// SyncConflictNotPermitted the push tried what the user can not do with it: take
+	// payments without order.tender, void the order or set tax exempt and no service
+	SyncConflictNotPermitted = "not_permitted"
+	// SyncConflictUnpaid the order was pushed paid but its payments do not cover
+	// the total, it is kept open
+	SyncConflictUnpaid = "unpaid"
+
+	SyncResolutionServerWins = "server_wins"
+	SyncResolutionVoided     = "voided"
+	SyncResolutionKept       = "kept"
+	SyncResolutionRejected   = "rejected"
+)
+
+type (
+	// SyncChange is the latest change of a synced row, data is the whole row,
+	// null when the row is deleted, deleted is also set for soft deleted rows
+	SyncChange struct {
+		EntityType string          `json:"entity_type"`
+		EntityID   string          `json:"entity_id"`
+		Version    int64           `json:"version"`
+		Data       json.RawMessage `json:"data" swaggertype:"object"`
+		Deleted    bool            `json:"deleted"`
+		ChangedAt  int64           `json:"changed_at"`
+	}
+
+	// SyncPullQuery ask for the changes after the version since (0 for everything)
+	SyncPullQuery struct {
+		Since int64 `json:"since" form:"since" binding:"min=0"`
+		Limit int   `json:"limit" form:"limit" binding:"omitempty,min=1,max=1000"`
+	}
+
+	// SyncPull is a page of changes, the terminal keep cursor
+	// and pull again from it while there is more
+	SyncPull struct {
+		Cursor  int64         `json:"cursor"`
+		More    bool          `json:"more"`
+		Changes []*SyncChange `json:"changes"`
+	}
+
+	// SyncOrderForm is an order as kept on the terminal, version is the one
+	// the terminal got back from its last push of the order (0 when new),
+	// channel and customer tier pick the price lists the items are priced with.
+	// The push take an order as far as paid, void is done with POST
+	// /orders/{id}/void and tax exempt and no service with PUT
+	// /orders/{id}/discount, the server values are kept when they differ.
+	// created_at is clamped between the open of the store shift and the time
+	// the push is received, it is never changed after the first push
+	SyncOrderForm struct {
+		ID           string          `json:"id" binding:"required,uuid"`
+		Version      int             `json:"version" binding:"min=0"`
+		TableID      int             `json:"table_id" binding:"min=0"`
+		RoomID       int             `json:"room_id" binding:"min=0"`
+		Status       string          `json:"status" binding:"required,oneof=open paid void"`
+		Channel      string          `json:"channel" binding:"omitempty,oneof=dine_in takeaway delivery"`
+		CustomerTier string          `json:"customer_tier"`
+		TaxExempt    bool            `json:"tax_exempt"`
+		NoService    bool            `json:"no_service"`
+		Items        []*OrderItem    `json:"items" binding:"dive"`
+		Payments     []*OrderPayment `json:"payments" binding:"dive"`
+		CreatedAt    int64           `json:"created_at" binding:"required,min=1"`
+		UpdatedAt    int64           `json:"updated_at" binding:"min=0"`
+	}
+
+	// SyncPushForm carry the orders taken on the terminal, a push can be
+	// sent again as is (e.g: the respond was lost) without changing anything
+	SyncPushForm struct {
+		Orders     []*SyncOrderForm `json:"orders" binding:"required,min=1,max=100,dive"`
+		CreatedBy  int              `json:"-"`
+		TerminalID int              `json:"-"`
+		// CanTender is set when the user who push has PermissionOrderTender
+		CanTender bool `json:"-"`
+	}
+
+	SyncConflict struct {
+		Rule       string `json:"rule"`
+		Resolution string `json:"resolution"`
+		ItemID     string `json:"item_id,omitempty"`
+		PaymentID  string `json:"payment_id,omitempty"`
+		Message    string `json:"message"`
+	}
+
+	// SyncOrderResult is the outcome of a pushed order, order is
+	// the order as kept by the server, the terminal replace its copy
+	SyncOrderResult struct {
+		ID        string          `json:"id"`
+		Status    string          `json:"status"`
+		Version   int             `json:"version"`
+		Conflicts []*SyncConflict `json:"conflicts"`
+		Order     *Order          `json:"order"`
+	}
+
+	SyncPushResult struct {
+		Orders []*SyncOrderResult `json:"orders"`
+	}
+
+	ISyncRepository interface {
+		// Changes return the changes after the version since, oldest first
+		Changes(ctx context.Context, since int64, limit int) (data []*SyncChange, err error)
+		// Products return the products that exist, true when it is not deleted
+		Products(ctx context.Context, ids []int) (active map[int]bool, err error)
+		// Variants return the variants that exist, true when neither
+		// the variant nor its product is deleted
+		Variants(ctx context.Context, ids []int) (active map[int]bool, err error)
+		// ShiftOpenAt return when the open store shift was opened, 0 when none is open
+		ShiftOpenAt(ctx context.Context) (openAt int64, err error)
+	}
+
+	ISyncService interface {
+		Pull(ctx context.Context, query *SyncPullQuery) (data *SyncPull, errData *utils.ServiceError)
+		Push(ctx context.Context, form *SyncPushForm) (data *SyncPushResult, errData *utils.ServiceError)
+	}
+)
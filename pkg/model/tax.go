@@ -0,0 +1,103 @@
+package model
+
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+
+	"github.com/aasumitro/posbe/pkg/money"
+	"github.com/aasumitro/posbe/pkg/utils"
+)
+
+type (
+	// TaxClass is a tax rate assigned to products or categories, product
+	// assignment win over category assignment and the rest use the default
+	// class. Inclusive class price already contain the tax, AfterService
+	// class also tax the service charge (compounding)
+	TaxClass struct {
+		ID           int           `json:"id"`
+		Name         string        `json:"name" binding:"required"`
+		Rate         money.Percent `json:"rate"`
+		Inclusive    bool          `json:"inclusive"`
+		AfterService bool          `json:"after_service"`
+		IsDefault    bool          `json:"is_default"`
+		ProductIDs   []int         `json:"product_ids"`
+		CategoryIDs  []int         `json:"category_ids"`
+	}
+
+	TaxQueryItem struct {
+		ProductID int `json:"product_id" binding:"required"`
+		// Amount is the line total as priced (price x qty)
+		Amount money.Amount `json:"amount" binding:"min=0"`
+	}
+
+	// TaxQuery describe the order to calculate, Exempt remove every tax
+	// (e.g: diplomatic purchase) and drop inclusive price to its net
+	TaxQuery struct {
+		Items     []*TaxQueryItem `json:"items" binding:"required,min=1,dive"`
+		NoService bool            `json:"no_service"`
+		Exempt    bool            `json:"exempt"`
+	}
+
+	// TaxLine is the tax of one class, Base is the taxed amount
+	TaxLine struct {
+		TaxClassID int           `json:"tax_class_id"`
+		Name       string        `json:"name"`
+		Rate       money.Percent `json:"rate"`
+		Inclusive  bool          `json:"inclusive"`
+		Base       money.Amount  `json:"base"`
+		Amount     money.Amount  `json:"amount"`
+	}
+
+	// TaxSummary is the per-rate breakdown kept on the order for the invoice,
+	// Total = Net + ServiceCharge + Tax
+	TaxSummary struct {
+		Subtotal      money.Amount  `json:"subtotal"`
+		Net           money.Amount  `json:"net"`
+		ServiceRate   money.Percent `json:"service_rate"`
+		ServiceCharge money.Amount  `json:"service_charge"`
+		Tax           money.Amount  `json:"tax"`
+		Total         money.Amount  `json:"total"`
+		Exempt        bool          `json:"exempt"`
+		Lines         []*TaxLine    `json:"lines"`
+	}
+
+	ITaxClassRepository interface {
+		ICRUDRepository[TaxClass]
+		// ProductClasses return tax class id keyed by product id, 0 when the
+		// product has no class (no default class), unknown product is left out
+		ProductClasses(ctx context.Context, productIDs []int) (classes map[int]int, err error)
+	}
+
+	ICatalogTaxService interface {
+		TaxClassList(ctx context.Context) (classes []*TaxClass, errData *utils.ServiceError)
+		AddTaxClass(ctx context.Context, data *TaxClass) (class *TaxClass, errData *utils.ServiceError)
+		EditTaxClass(ctx context.Context, data *TaxClass) (class *TaxClass, errData *utils.ServiceError)
+		DeleteTaxClass(ctx context.Context, data *TaxClass) *utils.ServiceError
+		CalculateTax(ctx context.Context, query *TaxQuery) (summary *TaxSummary, errData *utils.ServiceError)
+	}
+)
+
+// Scan implements the sql.Scanner interface
+func (summary *TaxSummary) Scan(src any) error {
+	var data []byte
+	switch v := src.(type) {
+	case nil:
+		*summary = TaxSummary{}
+		return nil
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	default:
+		return errors.New("unsupported tax summary value")
+	}
+	return json.Unmarshal(data, summary)
+}
+
+// Value implements the driver.Valuer interface
+func (summary TaxSummary) Value() (driver.Value, error) {
+	data, err := json.Marshal(summary)
+	return string(data), err
+}
@@ -0,0 +1,203 @@
+package model
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/aasumitro/posbe/pkg/money"
+	"github.com/aasumitro/posbe/pkg/utils"
+)
+
+const (
+	OrderStatusOpen = "open"
+	OrderStatusPaid = "paid"
+	OrderStatusVoid = "void"
+)
+
+type (
+	// Order is taken on a terminal, online or offline, the ids of the order,
+	// its items and payments are uuids made by the terminal, amounts are in
+	// the store currency, total (service charge and tax included) leave out
+	// the voided items and TaxSummary is its breakdown for the invoice.
+	// Discount, TaxExempt and NoService are set with an approved override
+	// (DiscountApprovedBy), as is the void of the order
+	Order struct {
+		ID                 string          `json:"id"`
+		TerminalID         int             `json:"terminal_id,omitempty"`
+		TableID            int             `json:"table_id,omitempty"`
+		RoomID             int             `json:"room_id,omitempty"`
+		Status             string          `json:"status"`
+		Currency           string          `json:"currency"`
+		Total              money.Amount    `json:"total"`
+		ServiceCharge      money.Amount    `json:"service_charge"`
+		Tax                money.Amount    `json:"tax"`
+		TaxSummary         *TaxSummary     `json:"tax_summary,omitempty"`
+		Discount           money.Amount    `json:"discount"`
+		TaxExempt          bool            `json:"tax_exempt"`
+		NoService          bool            `json:"no_service"`
+		DiscountApprovedBy int             `json:"discount_approved_by,omitempty"`
+		VoidReason         string          `json:"void_reason,omitempty"`
+		VoidedBy           int             `json:"voided_by,omitempty"`
+		VoidApprovedBy     int             `json:"void_approved_by,omitempty"`
+		VoidedAt           int64           `json:"voided_at,omitempty"`
+		Version            int             `json:"version"`
+		Checksum           string          `json:"-"`
+		Items              []*OrderItem    `json:"items"`
+		Payments           []*OrderPayment `json:"payments"`
+		Refunds            []*OrderRefund  `json:"refunds"`
+		CreatedBy          int             `json:"created_by,omitempty"`
+		CreatedAt          int64           `json:"created_at"`
+		UpdatedAt          int64           `json:"updated_at,omitempty"`
+		SyncedAt           int64           `json:"synced_at"`
+	}
+
+	// OrderItem keep the product as sold, Price and PriceListID are set by
+	// the server from the active price lists when the order is pushed and
+	// VoidReason when the item is voided by a sync conflict. A price set with
+	// an override keep PriceApprovedBy (set by the server only, the pushed
+	// one is ignored) and is not priced again by the pushes
+	OrderItem struct {
+		ID              string       `json:"id" binding:"required,uuid"`
+		ProductID       int          `json:"product_id" binding:"required"`
+		VariantID       int          `json:"variant_id,omitempty"`
+		Name            string       `json:"name" binding:"required,max=255"`
+		Quantity        int          `json:"quantity" binding:"required,min=1"`
+		Price           money.Amount `json:"price" binding:"min=0"`
+		PriceListID     int          `json:"price_list_id,omitempty"`
+		Note            string       `json:"note,omitempty" binding:"max=255"`
+		VoidReason      string       `json:"void_reason,omitempty"`
+		PriceApprovedBy int          `json:"price_approved_by,omitempty"`
+	}
+
+	// OrderPayment is never changed once kept, CreatedBy is the user who pushed it
+	OrderPayment struct {
+		ID        string       `json:"id" binding:"required,uuid"`
+		Method    string       `json:"method" binding:"required,oneof=cash card transfer other"`
+		Amount    money.Amount `json:"amount" binding:"required,min=1"`
+		Reference string       `json:"reference,omitempty" binding:"max=255"`
+		CreatedBy int          `json:"created_by,omitempty"`
+		CreatedAt int64        `json:"created_at" binding:"required,min=1"`
+	}
+
+	// OrderRefund is money given back on a paid order,
+	// the refunds of an order never exceed its payments
+	OrderRefund struct {
+		ID         int          `json:"id"`
+		OrderID    string       `json:"order_id"`
+		Amount     money.Amount `json:"amount"`
+		Reason     string       `json:"reason"`
+		RefundedBy int          `json:"refunded_by"`
+		ApprovedBy int          `json:"approved_by"`
+		CreatedAt  int64        `json:"created_at"`
+	}
+
+	// OrderVoidForm void an open order, VoidedBy and ApprovedBy are set by the handler
+	OrderVoidForm struct {
+		Reason     string `json:"reason" binding:"required,max=255"`
+		VoidedBy   int    `json:"-"`
+		ApprovedBy int    `json:"-"`
+	}
+
+	// OrderRefundForm refund a part (or all) of what was paid on a paid order
+	OrderRefundForm struct {
+		Amount     money.Amount `json:"amount" binding:"required,min=1"`
+		Reason     string       `json:"reason" binding:"required,max=255"`
+		RefundedBy int          `json:"-"`
+		ApprovedBy int          `json:"-"`
+	}
+
+	// OrderPriceForm set the price of an item of an open order by hand
+	OrderPriceForm struct {
+		Price      money.Amount `json:"price" binding:"min=0"`
+		ApprovedBy int          `json:"-"`
+	}
+
+	// OrderDiscountForm set the discount (taken off before tax), tax exempt
+	// and no service of an open order, sent again with 0 and false to remove them
+	OrderDiscountForm struct {
+		Discount   money.Amount `json:"discount" binding:"min=0"`
+		TaxExempt  bool         `json:"tax_exempt"`
+		NoService  bool         `json:"no_service"`
+		ApprovedBy int          `json:"-"`
+	}
+
+	// TenderForm is cash handed over by the customer, amount is in major unit
+	// of the tendered currency and due is in the store currency
+	TenderForm struct {
+		Currency  string       `json:"currency" binding:"required,len=3"`
+		Amount    json.Number  `json:"amount" binding:"required"`
+		Due       money.Amount `json:"due"`
+		CreatedBy int          `json:"-"`
+	}
+
+	// CashTender is an accepted cash tender, foreign cash is converted with
+	// the rate in effect and the change is always given in the store currency
+	CashTender struct {
+		ID             int         `json:"id"`
+		StoreShiftID   int         `json:"store_shift_id"`
+		CurrencyRateID int         `json:"currency_rate_id"`
+		Rate           money.Rate  `json:"rate"`
+		Tendered       money.Money `json:"tendered"`
+		Converted      money.Money `json:"converted"`
+		Due            money.Money `json:"due"`
+		Change         money.Money `json:"change"`
+		CreatedBy      int         `json:"created_by"`
+		CreatedAt      int64       `json:"created_at"`
+	}
+
+	CashDrawerForm struct {
+		Reason     string `json:"reason" binding:"required"`
+		OpenedBy   int    `json:"-"`
+		ApprovedBy int    `json:"-"`
+	}
+
+	// CashDrawerOpen is a no-sale drawer open, kept with its approver
+	CashDrawerOpen struct {
+		ID           int    `json:"id"`
+		StoreShiftID int    `json:"store_shift_id"`
+		Reason       string `json:"reason"`
+		OpenedBy     int    `json:"opened_by"`
+		ApprovedBy   int    `json:"approved_by"`
+		CreatedAt    int64  `json:"created_at"`
+	}
+
+	ICashTenderRepository interface {
+		Find(ctx context.Context, id int) (data *CashTender, err error)
+		Create(ctx context.Context, tender *CashTender) (data *CashTender, err error)
+		// OpenDrawer record the open on the open store shift,
+		// sql.ErrNoRows when no store shift is open
+		OpenDrawer(ctx context.Context, drawer *CashDrawerOpen) (data *CashDrawerOpen, err error)
+	}
+
+	IOrderRepository interface {
+		// Find return the order with its items and payments
+		Find(ctx context.Context, id string) (data *Order, err error)
+		// Save insert the order at version 1, or replace it and its items when
+		// it is still at the previous version, sql.ErrNoRows when it is not
+		// (the order was saved by another push in between)
+		Save(ctx context.Context, order *Order) (data *Order, err error)
+		// AddPayments keep the payments that are not kept yet (by id),
+		// return the ids of the ones added
+		AddPayments(ctx context.Context, orderID string, payments []*OrderPayment) (added []string, err error)
+		// AddRefund keep the refund of a paid order and raise its version,
+		// sql.ErrNoRows when the order is not paid or the refunds would
+		// exceed the payments
+		AddRefund(ctx context.Context, refund *OrderRefund) (data *OrderRefund, err error)
+	}
+
+	// IOrderService change the orders on the server, every change is
+	// approved with an override (or the permission of the action)
+	IOrderService interface {
+		OrderDetail(ctx context.Context, id string) (data *Order, errData *utils.ServiceError)
+		VoidOrder(ctx context.Context, id string, form *OrderVoidForm) (data *Order, errData *utils.ServiceError)
+		RefundOrder(ctx context.Context, id string, form *OrderRefundForm) (data *OrderRefund, errData *utils.ServiceError)
+		OverrideItemPrice(ctx context.Context, id, itemID string, form *OrderPriceForm) (data *Order, errData *utils.ServiceError)
+		DiscountOrder(ctx context.Context, id string, form *OrderDiscountForm) (data *Order, errData *utils.ServiceError)
+	}
+
+	ITenderService interface {
+		CashTender(ctx context.Context, form *TenderForm) (data *CashTender, errData *utils.ServiceError)
+		TenderDetail(ctx context.Context, id int) (data *CashTender, errData *utils.ServiceError)
+		OpenCashDrawer(ctx context.Context, form *CashDrawerForm) (data *CashDrawerOpen, errData *utils.ServiceError)
+	}
+)
@@ -0,0 +1,504 @@
+package money
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+	"sync/atomic"
+)
+
+type (
+	// Amount is money in minor unit of the store currency (e.g: cent),
+	// kept as integer so totals never drift. It is stored as BIGINT and
+	// encoded as decimal number in json and form (e.g: 12.50)
+	Amount int64
+
+	// Currency is ISO 4217 code with its minor unit exponent
+	// and the separators used to display it
+	Currency struct {
+		Code     string `json:"code"`
+		Exponent int    `json:"exponent"`
+		Symbol   string `json:"symbol"`
+		Decimal  string `json:"decimal"`
+		Thousand string `json:"thousand"`
+	}
+
+	// Money is amount in minor unit of the given currency, used where the
+	// currency is not (only) the store currency (e.g: foreign tender)
+	Money struct {
+		Amount   Amount
+		Currency Currency
+	}
+
+	// Percent is percentage with PercentDecimals decimals kept as integer
+	// (e.g: 11.5% is 115000), encoded as decimal number in json (e.g: 11.5)
+	Percent int64
+
+	// Rate is how many major unit of the store currency one major unit
+	// of the foreign currency buy (e.g: 16200 IDR for 1 USD), kept exact
+	Rate struct {
+		value *big.Rat
+	}
+)
+
+const (
+	DefaultCurrency = "IDR"
+	// RateDecimals is the precision kept for exchange rate
+	RateDecimals = 8
+	// PercentDecimals is the precision kept for percentage (e.g: tax rate)
+	PercentDecimals = 4
+	groupDigits     = 3
+)
+
+var (
+	ErrorInvalidAmount     = errors.New("invalid money amount")
+	ErrorTooManyDecimals   = errors.New("money amount has more decimals than the currency allow")
+	ErrorUnknownCurrency   = errors.New("unknown currency")
+	ErrorAmountOutOfRange  = errors.New("money amount out of range")
+	ErrorAllocationWeights = errors.New("allocation weights must not be negative")
+	ErrorInvalidRate       = errors.New("exchange rate must be a positive decimal")
+	ErrorInvalidPercent    = errors.New("percentage must be a decimal between 0 and 100")
+
+	// currencies lists the supported codes, exponent follow ISO 4217
+	currencies = map[string]Currency{
+		"IDR": {Code: "IDR", Exponent: 2, Symbol: "Rp", Decimal: ",", Thousand: "."},
+		"USD": {Code: "USD", Exponent: 2, Symbol: "$", Decimal: ".", Thousand: ","},
+		"EUR": {Code: "EUR", Exponent: 2, Symbol: "€", Decimal: ",", Thousand: "."},
+		"SGD": {Code: "SGD", Exponent: 2, Symbol: "S$", Decimal: ".", Thousand: ","},
+		"MYR": {Code: "MYR", Exponent: 2, Symbol: "RM", Decimal: ".", Thousand: ","},
+		"AUD": {Code: "AUD", Exponent: 2, Symbol: "A$", Decimal: ".", Thousand: ","},
+		"JPY": {Code: "JPY", Exponent: 0, Symbol: "¥", Decimal: ".", Thousand: ","},
+		"KRW": {Code: "KRW", Exponent: 0, Symbol: "₩", Decimal: ".", Thousand: ","},
+		"VND": {Code: "VND", Exponent: 0, Symbol: "₫", Decimal: ",", Thousand: "."},
+	}
+
+	// percentUnit parse and format Percent like a currency with 4 decimals
+	percentUnit = Currency{Exponent: PercentDecimals}
+
+	current atomic.Pointer[Currency]
+)
+
+func init() {
+	c := currencies[DefaultCurrency]
+	current.Store(&c)
+}
+
+// Lookup return the currency of the given code (case-insensitive)
+func Lookup(code string) (Currency, error) {
+	c, ok := currencies[strings.ToUpper(strings.TrimSpace(code))]
+	if !ok {
+		return Currency{}, fmt.Errorf("%w: %s", ErrorUnknownCurrency, code)
+	}
+	return c, nil
+}
+
+// SetCurrency switch the store currency used to encode and parse amounts,
+// it follow the currency store pref
+func SetCurrency(code string) error {
+	c, err := Lookup(code)
+	if err != nil {
+		return err
+	}
+	current.Store(&c)
+	return nil
+}
+
+// StoreCurrency return the active store currency
+func StoreCurrency() Currency {
+	return *current.Load()
+}
+
+// Parse read decimal string in major unit (e.g: "12.5") into minor unit
+// without going through float, more decimals than the currency allow is an error
+func Parse(value string) (Amount, error) {
+	return StoreCurrency().Parse(value)
+}
+
+func (c Currency) Parse(value string) (Amount, error) {
+	value = strings.TrimSpace(value)
+	negative := strings.HasPrefix(value, "-")
+	value = strings.TrimPrefix(strings.TrimPrefix(value, "-"), "+")
+	intPart, fracPart, _ := strings.Cut(value, ".")
+	if intPart == "" && fracPart == "" {
+		return 0, ErrorInvalidAmount
+	}
+	// trailing zeros never lose precision (e.g: 12.500 for 2 decimals)
+	fracPart = strings.TrimRight(fracPart, "0")
+	if len(fracPart) > c.Exponent {
+		return 0, ErrorTooManyDecimals
+	}
+	digits := intPart + fracPart + strings.Repeat("0", c.Exponent-len(fracPart))
+	if digits == "" || strings.Trim(digits, "0123456789") != "" {
+		return 0, ErrorInvalidAmount
+	}
+	minor, err := strconv.ParseInt(digits, 10, 64)
+	if err != nil {
+		return 0, ErrorAmountOutOfRange
+	}
+	if negative {
+		minor = -minor
+	}
+	return Amount(minor), nil
+}
+
+// Format write the amount as decimal string in major unit (e.g: 12.50)
+func (c Currency) Format(a Amount) string {
+	minor := int64(a)
+	sign := ""
+	if minor < 0 {
+		sign, minor = "-", -minor
+	}
+	digits := strconv.FormatInt(minor, 10)
+	if c.Exponent == 0 {
+		return sign + digits
+	}
+	if len(digits) <= c.Exponent {
+		digits = strings.Repeat("0", c.Exponent-len(digits)+1) + digits
+	}
+	point := len(digits) - c.Exponent
+	return sign + digits[:point] + "." + digits[point:]
+}
+
+// Display write the amount with the currency symbol and separators (e.g: Rp15.000,00)
+func (c Currency) Display(a Amount) string {
+	formatted := c.Format(a)
+	sign := ""
+	if strings.HasPrefix(formatted, "-") {
+		sign, formatted = "-", formatted[1:]
+	}
+	intPart, fracPart, _ := strings.Cut(formatted, ".")
+	var grouped strings.Builder
+	for i, digit := range intPart {
+		if i > 0 && (len(intPart)-i)%groupDigits == 0 {
+			grouped.WriteString(c.Thousand)
+		}
+		grouped.WriteRune(digit)
+	}
+	if fracPart != "" {
+		grouped.WriteString(c.Decimal + fracPart)
+	}
+	return sign + c.Symbol + grouped.String()
+}
+
+// Major return the amount in major unit, only for display and spreadsheet
+func (c Currency) Major(a Amount) float64 {
+	value, _ := strconv.ParseFloat(c.Format(a), 64)
+	return value
+}
+
+func (a Amount) String() string {
+	return StoreCurrency().Format(a)
+}
+
+// Mul multiply the amount by quantity
+func (a Amount) Mul(qty int) Amount {
+	return a * Amount(qty)
+}
+
+// Allocate split the amount proportionally to the weights, the rounding
+// remainder goes to the largest fractions so the parts always sum up to the amount
+func (a Amount) Allocate(weights ...int64) ([]Amount, error) {
+	parts := make([]Amount, len(weights))
+	weights = append([]int64{}, weights...)
+	var total int64
+	for _, weight := range weights {
+		if weight < 0 {
+			return nil, ErrorAllocationWeights
+		}
+		total += weight
+	}
+	if len(weights) == 0 {
+		return parts, nil
+	}
+	if total == 0 {
+		// nothing to weight with, split evenly
+		for i := range weights {
+			weights[i] = 1
+		}
+		total = int64(len(weights))
+	}
+	remainders := make([]int64, len(weights))
+	allocated := Amount(0)
+	for i, weight := range weights {
+		share := int64(a) * weight
+		parts[i] = Amount(share / total)
+		remainders[i] = share % total
+		allocated += parts[i]
+	}
+	for left := a - allocated; left != 0; {
+		best := 0
+		for i := range remainders {
+			if abs(remainders[i]) > abs(remainders[best]) {
+				best = i
+			}
+		}
+		step := Amount(1)
+		if left < 0 {
+			step = -1
+		}
+		parts[best] += step
+		left -= step
+		remainders[best] = 0
+	}
+	return parts, nil
+}
+
+// MarshalJSON encode as json number in major unit
+func (a Amount) MarshalJSON() ([]byte, error) {
+	return []byte(a.String()), nil
+}
+
+// UnmarshalJSON accept json number or numeric string in major unit
+func (a *Amount) UnmarshalJSON(data []byte) error {
+	value := strings.Trim(string(data), `"`)
+	if value == "null" || value == "" {
+		*a = 0
+		return nil
+	}
+	parsed, err := Parse(value)
+	if err != nil {
+		return err
+	}
+	*a = parsed
+	return nil
+}
+
+// UnmarshalParam implements gin binding.BindUnmarshaler for form and query
+func (a *Amount) UnmarshalParam(param string) error {
+	if strings.TrimSpace(param) == "" {
+		*a = 0
+		return nil
+	}
+	parsed, err := Parse(param)
+	if err != nil {
+		return err
+	}
+	*a = parsed
+	return nil
+}
+
+// Scan implements the sql.Scanner interface, NULL is read as zero
+func (a *Amount) Scan(src any) error {
+	switch v := src.(type) {
+	case nil:
+		*a = 0
+	case int64:
+		*a = Amount(v)
+	case []byte:
+		return a.scanString(string(v))
+	case string:
+		return a.scanString(v)
+	default:
+		return fmt.Errorf("%w: unsupported type %T", ErrorInvalidAmount, src)
+	}
+	return nil
+}
+
+func (a *Amount) scanString(value string) error {
+	minor, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrorInvalidAmount, value)
+	}
+	*a = Amount(minor)
+	return nil
+}
+
+// Value implements the driver.Valuer interface
+func (a Amount) Value() (driver.Value, error) {
+	return int64(a), nil
+}
+
+// MarshalJSON encode the amount in its own currency with display text
+// (e.g: {"currency":"USD","amount":12.50,"display":"$12.50"})
+func (m Money) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Currency string          `json:"currency"`
+		Amount   json.RawMessage `json:"amount"`
+		Display  string          `json:"display"`
+	}{m.Currency.Code, json.RawMessage(m.Currency.Format(m.Amount)), m.Currency.Display(m.Amount)})
+}
+
+// ParseRate read positive decimal string with at most RateDecimals decimals
+func ParseRate(value string) (Rate, error) {
+	value = strings.TrimSpace(value)
+	intPart, fracPart, _ := strings.Cut(value, ".")
+	fracPart = strings.TrimRight(fracPart, "0")
+	digits := intPart + fracPart
+	if digits == "" || strings.Trim(digits, "0123456789") != "" || len(fracPart) > RateDecimals {
+		return Rate{}, ErrorInvalidRate
+	}
+	rate, ok := new(big.Rat).SetString(intPart + "." + fracPart + "0")
+	if !ok || rate.Sign() <= 0 {
+		return Rate{}, ErrorInvalidRate
+	}
+	return Rate{value: rate}, nil
+}
+
+// IsZero report whether the rate is unset
+func (r Rate) IsZero() bool {
+	return r.value == nil || r.value.Sign() == 0
+}
+
+// String write the rate as the shortest decimal (e.g: 16200 or 0.5)
+func (r Rate) String() string {
+	if r.value == nil {
+		return "0"
+	}
+	value := r.value.FloatString(RateDecimals)
+	value = strings.TrimRight(strings.TrimRight(value, "0"), ".")
+	return value
+}
+
+// Convert turn amount of the from currency into the to currency, the
+// result is truncated to the minor unit so a tender is never overvalued
+func (r Rate) Convert(a Amount, from, to Currency) Amount {
+	if r.value == nil {
+		return 0
+	}
+	value := new(big.Rat).SetInt64(int64(a))
+	value.Mul(value, r.value)
+	value.Mul(value, new(big.Rat).SetFrac(pow10(to.Exponent), pow10(from.Exponent)))
+	return Amount(new(big.Int).Quo(value.Num(), value.Denom()).Int64())
+}
+
+// MarshalJSON encode as json number
+func (r Rate) MarshalJSON() ([]byte, error) {
+	return []byte(r.String()), nil
+}
+
+// UnmarshalJSON accept json number or numeric string
+func (r *Rate) UnmarshalJSON(data []byte) error {
+	rate, err := ParseRate(strings.Trim(string(data), `"`))
+	if err != nil {
+		return err
+	}
+	*r = rate
+	return nil
+}
+
+// Scan implements the sql.Scanner interface for NUMERIC column
+func (r *Rate) Scan(src any) error {
+	switch v := src.(type) {
+	case []byte:
+		return r.scanString(string(v))
+	case string:
+		return r.scanString(v)
+	}
+	return fmt.Errorf("%w: unsupported type %T", ErrorInvalidRate, src)
+}
+
+func (r *Rate) scanString(value string) error {
+	rate, err := ParseRate(value)
+	if err != nil {
+		return err
+	}
+	*r = rate
+	return nil
+}
+
+// Value implements the driver.Valuer interface
+func (r Rate) Value() (driver.Value, error) {
+	return r.String(), nil
+}
+
+// ParsePercent read decimal string from 0 to 100 (e.g: "11" or "7.5")
+func ParsePercent(value string) (Percent, error) {
+	parsed, err := percentUnit.Parse(value)
+	if err != nil || parsed < 0 || parsed > Amount(100*pow10(PercentDecimals).Int64()) {
+		return 0, ErrorInvalidPercent
+	}
+	return Percent(parsed), nil
+}
+
+// String write the percentage as the shortest decimal (e.g: 11 or 7.5)
+func (p Percent) String() string {
+	value := percentUnit.Format(Amount(p))
+	if strings.Contains(value, ".") {
+		value = strings.TrimRight(strings.TrimRight(value, "0"), ".")
+	}
+	return value
+}
+
+// Of return the percentage of the amount, rounded half away from zero
+func (p Percent) Of(a Amount) Amount {
+	num := new(big.Int).Mul(big.NewInt(int64(a)), big.NewInt(int64(p)))
+	return Amount(roundQuo(num, percentBase()))
+}
+
+// Included return the part of the amount that is the percentage added on
+// top of a base (e.g: tax included in price), so the base is amount - part
+func (p Percent) Included(a Amount) Amount {
+	num := new(big.Int).Mul(big.NewInt(int64(a)), percentBase())
+	den := new(big.Int).Add(percentBase(), big.NewInt(int64(p)))
+	return a - Amount(roundQuo(num, den))
+}
+
+// MarshalJSON encode as json number
+func (p Percent) MarshalJSON() ([]byte, error) {
+	return []byte(p.String()), nil
+}
+
+// UnmarshalJSON accept json number or numeric string
+func (p *Percent) UnmarshalJSON(data []byte) error {
+	parsed, err := ParsePercent(strings.Trim(string(data), `"`))
+	if err != nil {
+		return err
+	}
+	*p = parsed
+	return nil
+}
+
+// Scan implements the sql.Scanner interface for NUMERIC column
+func (p *Percent) Scan(src any) error {
+	var value string
+	switch v := src.(type) {
+	case []byte:
+		value = string(v)
+	case string:
+		value = v
+	case int64:
+		value = strconv.FormatInt(v, 10)
+	default:
+		return fmt.Errorf("%w: unsupported type %T", ErrorInvalidPercent, src)
+	}
+	parsed, err := ParsePercent(value)
+	if err != nil {
+		return err
+	}
+	*p = parsed
+	return nil
+}
+
+// Value implements the driver.Valuer interface
+func (p Percent) Value() (driver.Value, error) {
+	return p.String(), nil
+}
+
+// percentBase is 100% in Percent unit
+func percentBase() *big.Int {
+	return new(big.Int).Mul(big.NewInt(100), pow10(PercentDecimals))
+}
+
+// roundQuo divide rounding half away from zero
+func roundQuo(num, den *big.Int) int64 {
+	quo, rem := new(big.Int).QuoRem(num, den, new(big.Int))
+	if new(big.Int).Mul(new(big.Int).Abs(rem), big.NewInt(2)).Cmp(den) >= 0 {
+		quo.Add(quo, big.NewInt(int64(num.Sign())))
+	}
+	return quo.Int64()
+}
+
+func pow10(exponent int) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(exponent)), nil)
+}
+
+func abs(value int64) int64 {
+	if value < 0 {
+		return -value
+	}
+	return value
+}
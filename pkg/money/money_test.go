@@ -0,0 +1,199 @@
+package money_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/aasumitro/posbe/pkg/money"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCurrency_Parse(t *testing.T) {
+	idr, err := money.Lookup("idr")
+	require.NoError(t, err)
+	for value, expected := range map[string]money.Amount{
+		"15000": 1500000, "12.5": 1250, "12.50": 1250, "12.500": 1250,
+		"0.01": 1, ".5": 50, "12.": 1200, "-3.25": -325, "+1": 100,
+	} {
+		amount, err := idr.Parse(value)
+		require.NoError(t, err, value)
+		assert.Equal(t, expected, amount, value)
+	}
+	for value, expected := range map[string]error{
+		"": money.ErrorInvalidAmount, ".": money.ErrorInvalidAmount,
+		"1e5": money.ErrorInvalidAmount, "1,5": money.ErrorInvalidAmount,
+		"0.001":                money.ErrorTooManyDecimals,
+		"99999999999999999999": money.ErrorAmountOutOfRange,
+	} {
+		_, err := idr.Parse(value)
+		require.ErrorIs(t, err, expected, value)
+	}
+
+	jpy, err := money.Lookup("JPY")
+	require.NoError(t, err)
+	amount, err := jpy.Parse("1500")
+	require.NoError(t, err)
+	assert.Equal(t, money.Amount(1500), amount)
+	_, err = jpy.Parse("1.5")
+	require.ErrorIs(t, err, money.ErrorTooManyDecimals)
+
+	_, err = money.Lookup("XXX")
+	require.ErrorIs(t, err, money.ErrorUnknownCurrency)
+}
+
+func TestCurrency_Format(t *testing.T) {
+	usd, _ := money.Lookup("USD")
+	jpy, _ := money.Lookup("JPY")
+	assert.Equal(t, "12.50", usd.Format(1250))
+	assert.Equal(t, "0.05", usd.Format(5))
+	assert.Equal(t, "-0.05", usd.Format(-5))
+	assert.Equal(t, "1500", jpy.Format(1500))
+	assert.InDelta(t, 12.5, usd.Major(1250), 0)
+}
+
+func TestAmount_Allocate(t *testing.T) {
+	parts, err := money.Amount(1000).Allocate(1, 1, 1)
+	require.NoError(t, err)
+	assert.Equal(t, []money.Amount{334, 333, 333}, parts)
+
+	parts, err = money.Amount(5000).Allocate(3000, 2000, 0)
+	require.NoError(t, err)
+	assert.Equal(t, []money.Amount{3000, 2000, 0}, parts)
+
+	parts, err = money.Amount(100).Allocate(0, 0)
+	require.NoError(t, err)
+	assert.Equal(t, []money.Amount{50, 50}, parts)
+
+	parts, err = money.Amount(-100).Allocate(1, 2)
+	require.NoError(t, err)
+	assert.Equal(t, money.Amount(-100), parts[0]+parts[1])
+
+	_, err = money.Amount(100).Allocate(-1)
+	require.ErrorIs(t, err, money.ErrorAllocationWeights)
+}
+
+func TestAmount_JSON(t *testing.T) {
+	require.NoError(t, money.SetCurrency("USD"))
+	t.Cleanup(func() { _ = money.SetCurrency(money.DefaultCurrency) })
+
+	var item struct {
+		Price money.Amount `json:"price"`
+		Tip   money.Amount `json:"tip"`
+		Fee   money.Amount `json:"fee"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(`{"price": 12.5, "tip": "0.10", "fee": null}`), &item))
+	assert.Equal(t, money.Amount(1250), item.Price)
+	assert.Equal(t, money.Amount(10), item.Tip)
+	data, err := json.Marshal(item)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"price": 12.50, "tip": 0.10, "fee": 0.00}`, string(data))
+
+	require.Error(t, json.Unmarshal([]byte(`{"price": 0.125}`), &item))
+	require.ErrorIs(t, money.SetCurrency("XXX"), money.ErrorUnknownCurrency)
+}
+
+func TestAmount_SQL(t *testing.T) {
+	var amount money.Amount
+	require.NoError(t, amount.Scan(int64(1250)))
+	assert.Equal(t, money.Amount(1250), amount)
+	require.NoError(t, amount.Scan([]byte("99")))
+	assert.Equal(t, money.Amount(99), amount)
+	require.NoError(t, amount.Scan(nil))
+	assert.Equal(t, money.Amount(0), amount)
+	require.Error(t, amount.Scan("12.5"))
+	require.Error(t, amount.Scan(12.5))
+
+	value, err := money.Amount(1250).Value()
+	require.NoError(t, err)
+	assert.Equal(t, int64(1250), value)
+
+	require.NoError(t, amount.UnmarshalParam("7.25"))
+	assert.Equal(t, money.Amount(725), amount)
+	require.NoError(t, amount.UnmarshalParam(""))
+	assert.Equal(t, money.Amount(0), amount)
+	assert.Equal(t, money.Amount(3000), money.Amount(1000).Mul(3))
+}
+
+func TestCurrency_Display(t *testing.T) {
+	idr, _ := money.Lookup("IDR")
+	usd, _ := money.Lookup("USD")
+	jpy, _ := money.Lookup("JPY")
+	assert.Equal(t, "Rp1.500.000,00", idr.Display(150000000))
+	assert.Equal(t, "-$1,234.05", usd.Display(-123405))
+	assert.Equal(t, "$0.50", usd.Display(50))
+	assert.Equal(t, "¥1,500", jpy.Display(1500))
+
+	data, err := json.Marshal(money.Money{Amount: 2050, Currency: usd})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"currency": "USD", "amount": 20.50, "display": "$20.50"}`, string(data))
+}
+
+func TestRate_Convert(t *testing.T) {
+	idr, _ := money.Lookup("IDR")
+	usd, _ := money.Lookup("USD")
+	jpy, _ := money.Lookup("JPY")
+
+	rate, err := money.ParseRate("16200.50000000")
+	require.NoError(t, err)
+	assert.Equal(t, "16200.5", rate.String())
+	// USD 20.01 = IDR 324,172.005 truncated to the cent
+	assert.Equal(t, money.Amount(32417200), rate.Convert(2001, usd, idr))
+
+	rate, err = money.ParseRate("105.25")
+	require.NoError(t, err)
+	// JPY 1,000 = IDR 105,250.00
+	assert.Equal(t, money.Amount(10525000), rate.Convert(1000, jpy, idr))
+
+	for _, value := range []string{"", "0", "-1", "1e3", "0.000000001", "abc"} {
+		_, err := money.ParseRate(value)
+		require.ErrorIs(t, err, money.ErrorInvalidRate, value)
+	}
+
+	var scanned money.Rate
+	require.NoError(t, scanned.Scan([]byte("0.00006200")))
+	assert.Equal(t, "0.000062", scanned.String())
+	value, err := scanned.Value()
+	require.NoError(t, err)
+	assert.Equal(t, "0.000062", value)
+	require.Error(t, scanned.Scan(nil))
+
+	var form struct {
+		Rate money.Rate `json:"rate"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(`{"rate": 16200}`), &form))
+	assert.Equal(t, "16200", form.Rate.String())
+	assert.True(t, money.Rate{}.IsZero())
+}
+
+func TestPercent(t *testing.T) {
+	tax, err := money.ParsePercent("11")
+	require.NoError(t, err)
+	assert.Equal(t, money.Percent(110000), tax)
+	assert.Equal(t, "11", tax.String())
+	// 11% of 10,000.05 = 1,100.0055
+	assert.Equal(t, money.Amount(110001), tax.Of(1000005))
+	assert.Equal(t, money.Amount(-110001), tax.Of(-1000005))
+	// Rp111.000,00 include Rp11.000,00 of 11% tax
+	assert.Equal(t, money.Amount(1100000), tax.Included(11100000))
+
+	half, err := money.ParsePercent("7.5")
+	require.NoError(t, err)
+	assert.Equal(t, "7.5", half.String())
+	data, err := json.Marshal(half)
+	require.NoError(t, err)
+	assert.Equal(t, "7.5", string(data))
+
+	for _, value := range []string{"-1", "100.01", "1.00001", "x"} {
+		_, err := money.ParsePercent(value)
+		require.ErrorIs(t, err, money.ErrorInvalidPercent, value)
+	}
+
+	var scanned money.Percent
+	require.NoError(t, scanned.Scan([]byte("10.0000")))
+	assert.Equal(t, "10", scanned.String())
+	value, err := scanned.Value()
+	require.NoError(t, err)
+	assert.Equal(t, "10", value)
+	assert.Equal(t, "0", money.Percent(0).String())
+}
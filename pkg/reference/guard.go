@@ -0,0 +1,53 @@
+package reference
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+
+	"github.com/aasumitro/posbe/common"
+	"github.com/aasumitro/posbe/pkg/model"
+	"github.com/aasumitro/posbe/pkg/utils"
+)
+
+// Delete soft delete the row of the guarded table, the rows that point to it
+// are first moved to the reassign_to row of the request in the same
+// transaction, it return 409 with the references that are left
+func Delete(
+	ctx context.Context,
+	repo model.IReferenceRepository,
+	table string,
+	id int,
+) *utils.ServiceError {
+	to := model.ReassignTo(ctx)
+	if to > 0 && to == id {
+		return &utils.ServiceError{
+			Code:    http.StatusUnprocessableEntity,
+			Message: common.ErrorReassignTarget.Error(),
+		}
+	}
+	refs, err := repo.Delete(ctx, table, id, to)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return &utils.ServiceError{
+				Code:    http.StatusUnprocessableEntity,
+				Message: common.ErrorReassignTarget.Error(),
+			}
+		}
+		return &utils.ServiceError{
+			Code:    http.StatusInternalServerError,
+			Message: err.Error(),
+		}
+	}
+	if len(refs) > 0 {
+		return &utils.ServiceError{
+			Code: http.StatusConflict,
+			Message: &model.DeleteConflict{
+				Message:    common.ErrorUnableToDelete.Error(),
+				References: refs,
+			},
+		}
+	}
+	return nil
+}
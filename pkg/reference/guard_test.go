@@ -0,0 +1,76 @@
+package reference_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/aasumitro/posbe/common"
+	"github.com/aasumitro/posbe/mocks"
+	"github.com/aasumitro/posbe/pkg/model"
+	"github.com/aasumitro/posbe/pkg/reference"
+	"github.com/aasumitro/posbe/pkg/utils"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func reassignContext(to int) context.Context {
+	return context.WithValue(context.TODO(), model.ReassignToKey, to) //nolint:staticcheck // gin context keys are strings
+}
+
+func TestDelete_ShouldPassWithoutReferences(t *testing.T) {
+	repo := new(mocks.IReferenceRepository)
+	repo.On("Delete", mock.Anything, "units", 1, 0).Return(nil, nil).Once()
+	require.Nil(t, reference.Delete(context.TODO(), repo, "units", 1))
+	repo.AssertExpectations(t)
+}
+
+func TestDelete_ShouldConflictWithReferences(t *testing.T) {
+	refs := []*model.Reference{{Table: "product_variants", Column: "unit_id", Total: 2, IDs: []int{1, 2}}}
+	repo := new(mocks.IReferenceRepository)
+	repo.On("Delete", mock.Anything, "units", 1, 0).Return(refs, nil).Once()
+	errData := reference.Delete(context.TODO(), repo, "units", 1)
+	require.Equal(t, &utils.ServiceError{
+		Code: http.StatusConflict,
+		Message: &model.DeleteConflict{
+			Message:    common.ErrorUnableToDelete.Error(),
+			References: refs,
+		},
+	}, errData)
+}
+
+func TestDelete_ShouldReassignTo(t *testing.T) {
+	repo := new(mocks.IReferenceRepository)
+	repo.On("Delete", mock.Anything, "categories", 1, 2).Return(nil, nil).Once()
+	require.Nil(t, reference.Delete(reassignContext(2), repo, "categories", 1))
+	repo.AssertExpectations(t)
+}
+
+func TestDelete_ShouldRejectReassignToItself(t *testing.T) {
+	repo := new(mocks.IReferenceRepository)
+	errData := reference.Delete(reassignContext(1), repo, "categories", 1)
+	require.Equal(t, http.StatusUnprocessableEntity, errData.Code)
+	repo.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestDelete_ShouldRejectMissingTarget(t *testing.T) {
+	repo := new(mocks.IReferenceRepository)
+	repo.On("Delete", mock.Anything, "floors", 1, 9).Return(nil, sql.ErrNoRows).Once()
+	errData := reference.Delete(reassignContext(9), repo, "floors", 1)
+	require.Equal(t, &utils.ServiceError{
+		Code:    http.StatusUnprocessableEntity,
+		Message: common.ErrorReassignTarget.Error(),
+	}, errData)
+}
+
+func TestDelete_ShouldErrorWhenDelete(t *testing.T) {
+	repo := new(mocks.IReferenceRepository)
+	repo.On("Delete", mock.Anything, "roles", 1, 0).Return(nil, errors.New("UNEXPECTED")).Once()
+	errData := reference.Delete(context.TODO(), repo, "roles", 1)
+	require.Equal(t, &utils.ServiceError{
+		Code:    http.StatusInternalServerError,
+		Message: "UNEXPECTED",
+	}, errData)
+}
@@ -0,0 +1,197 @@
+package reference
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/aasumitro/posbe/config"
+	"github.com/aasumitro/posbe/pkg/audit"
+	"github.com/aasumitro/posbe/pkg/model"
+)
+
+// dependent is a column that point to the rows of a table
+type dependent struct {
+	table  string
+	column string
+	// key is listed as the ids of the blocking rows
+	key string
+	// soft tell the table is soft deleted, its deleted rows don't block
+	soft bool
+	// unique is the other column of the primary key of a join table,
+	// the rows a reassign would duplicate are dropped instead
+	unique string
+	// set is more assignments of the reassign, $1 is the new row
+	set string
+}
+
+// dependents of the tables that are guarded on delete
+var dependents = map[string][]dependent{
+	"units": {
+		{table: "product_variants", column: "unit_id", key: "id", soft: true},
+	},
+	"categories": {
+		{table: "subcategories", column: "category_id", key: "id", soft: true},
+		{table: "products", column: "category_id", key: "id", soft: true},
+	},
+	"subcategories": {
+		{table: "products", column: "subcategory_id", key: "id", soft: true,
+			set: "category_id = (SELECT category_id FROM subcategories WHERE id = $1)"},
+		{table: "bundle_slots", column: "subcategory_id", key: "id"},
+	},
+	"addons": {
+		{table: "addon_group_items", column: "addon_id", key: "addon_group_id", unique: "addon_group_id"},
+		{table: "product_addon_prices", column: "addon_id", key: "product_id", unique: "product_id"},
+	},
+	"floors": {
+		{table: "tables", column: "floor_id", key: "id", soft: true},
+		{table: "rooms", column: "floor_id", key: "id", soft: true},
+	},
+	"roles": {
+		{table: "users", column: "role_id", key: "id", soft: true},
+	},
+}
+
+// errReferenced roll back the delete of a row that is still referenced
+var errReferenced = errors.New("reference: the row is still referenced")
+
+type (
+	ReferenceSQLRepository struct {
+		Db *sql.DB
+	}
+
+	// querier is the database or the transaction of the delete
+	querier interface {
+		QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+		QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+	}
+)
+
+func (repo ReferenceSQLRepository) References(
+	ctx context.Context,
+	table string,
+	id int,
+) (refs []*model.Reference, err error) {
+	return references(ctx, repo.Db, table, id)
+}
+
+// Delete run the reassign, the check of the references that are left and
+// the soft delete in one transaction, so a failure never leave the
+// references moved to the other row while the row is kept
+func (repo ReferenceSQLRepository) Delete(
+	ctx context.Context,
+	table string,
+	id, to int,
+) (refs []*model.Reference, err error) {
+	if _, ok := dependents[table]; !ok {
+		return nil, fmt.Errorf("reference: %s is not guarded", table)
+	}
+	// products are versioned, the move is kept with the actor
+	err = audit.Versioned(ctx, repo.Db, func(tx *sql.Tx) error {
+		if to > 0 {
+			if err := reassign(ctx, tx, table, id, to); err != nil {
+				return err
+			}
+		}
+		if refs, err = references(ctx, tx, table, id); err != nil {
+			return err
+		}
+		if len(refs) > 0 {
+			return errReferenced
+		}
+		q := fmt.Sprintf("UPDATE %s SET deleted_at = $1, deleted_by = $2 ", table)
+		q += "WHERE id = $3 AND deleted_at IS NULL"
+		_, err := tx.ExecContext(ctx, q, time.Now().Unix(), audit.NullActor(ctx), id)
+		return err
+	})
+	if errors.Is(err, errReferenced) {
+		return refs, nil
+	}
+	return nil, err
+}
+
+func references(ctx context.Context, db querier, table string, id int) (refs []*model.Reference, err error) {
+	for _, dep := range dependents[table] {
+		q := fmt.Sprintf("SELECT %s FROM %s WHERE %s = $1", dep.key, dep.table, dep.column)
+		if dep.soft {
+			q += " AND deleted_at IS NULL"
+		}
+		q += fmt.Sprintf(" ORDER BY %s ASC", dep.key)
+		ref, err := reference(ctx, db, q, id)
+		if err != nil {
+			return nil, err
+		}
+		if ref.Total > 0 {
+			ref.Table, ref.Column = dep.table, dep.column
+			refs = append(refs, ref)
+		}
+	}
+
+	return refs, nil
+}
+
+func reference(ctx context.Context, db querier, q string, id int) (*model.Reference, error) {
+	rows, err := db.QueryContext(ctx, q, id)
+	if err != nil {
+		return nil, err
+	}
+	defer func(rows *sql.Rows) { _ = rows.Close() }(rows)
+
+	ref := &model.Reference{IDs: []int{}}
+	for rows.Next() {
+		var key int
+		if err := rows.Scan(&key); err != nil {
+			return nil, err
+		}
+		ref.IDs = append(ref.IDs, key)
+	}
+	ref.Total = len(ref.IDs)
+
+	return ref, rows.Err()
+}
+
+// reassign move the deleted rows too, so they still point
+// to an existing row once restored
+func reassign(ctx context.Context, tx *sql.Tx, table string, id, to int) error {
+	var exists bool
+	q := fmt.Sprintf("SELECT EXISTS (SELECT 1 FROM %s WHERE id = $1 AND deleted_at IS NULL)", table)
+	if err := tx.QueryRowContext(ctx, q, to).Scan(&exists); err != nil {
+		return err
+	}
+	if !exists {
+		return sql.ErrNoRows
+	}
+	for _, dep := range dependents[table] {
+		if _, err := tx.ExecContext(ctx, reassignQuery(dep), to, id); err != nil {
+			return err
+		}
+		if dep.unique == "" {
+			continue
+		}
+		// the rows that are left would be duplicated
+		q := fmt.Sprintf("DELETE FROM %s WHERE %s = $1", dep.table, dep.column)
+		if _, err := tx.ExecContext(ctx, q, id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func reassignQuery(dep dependent) string {
+	q := fmt.Sprintf("UPDATE %s SET %s = $1", dep.table, dep.column)
+	if dep.set != "" {
+		q += ", " + dep.set
+	}
+	q += fmt.Sprintf(" WHERE %s = $2", dep.column)
+	if dep.unique != "" {
+		q += fmt.Sprintf(" AND %s NOT IN (SELECT %s FROM %s WHERE %s = $1)",
+			dep.unique, dep.unique, dep.table, dep.column)
+	}
+	return q
+}
+
+func NewReferenceSQLRepository() model.IReferenceRepository {
+	return &ReferenceSQLRepository{Db: config.DbPool}
+}
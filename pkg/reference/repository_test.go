@@ -0,0 +1,244 @@
+package reference_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/aasumitro/posbe/config"
+	"github.com/aasumitro/posbe/pkg/dbtest"
+	"github.com/aasumitro/posbe/pkg/dialect"
+	"github.com/aasumitro/posbe/pkg/model"
+	"github.com/aasumitro/posbe/pkg/reference"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type referenceRepositoryTestSuite struct {
+	suite.Suite
+	mock sqlmock.Sqlmock
+	repo model.IReferenceRepository
+}
+
+func (suite *referenceRepositoryTestSuite) SetupSuite() {
+	var err error
+
+	config.DbPool, suite.mock, err = sqlmock.New(
+		sqlmock.QueryMatcherOption(
+			sqlmock.QueryMatcherRegexp))
+	require.NoError(suite.T(), err)
+
+	suite.repo = reference.NewReferenceSQLRepository()
+}
+
+func (suite *referenceRepositoryTestSuite) AfterTest(_, _ string) {
+	require.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
+func (suite *referenceRepositoryTestSuite) expectVersioned() {
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectExec(regexp.QuoteMeta(config.Dialect.SetActor())).
+		WithArgs("").WillReturnResult(sqlmock.NewResult(0, 0))
+}
+
+func (suite *referenceRepositoryTestSuite) expectVersionedCommit() {
+	if q := config.Dialect.ResetActor(); q != "" {
+		suite.mock.ExpectExec(regexp.QuoteMeta(q)).WillReturnResult(sqlmock.NewResult(0, 1))
+	}
+	suite.mock.ExpectCommit()
+}
+
+func (suite *referenceRepositoryTestSuite) TestRepository_References_ExpectReturnRows() {
+	suite.mock.ExpectQuery("SELECT id FROM subcategories WHERE category_id = \\$1 AND deleted_at IS NULL ORDER BY id ASC").
+		WithArgs(1).
+		WillReturnRows(suite.mock.NewRows([]string{"id"}))
+	suite.mock.ExpectQuery("SELECT id FROM products WHERE category_id = \\$1 AND deleted_at IS NULL ORDER BY id ASC").
+		WithArgs(1).
+		WillReturnRows(suite.mock.NewRows([]string{"id"}).AddRow(3).AddRow(5))
+	refs, err := suite.repo.References(context.TODO(), "categories", 1)
+	require.NoError(suite.T(), err)
+	require.Equal(suite.T(), []*model.Reference{
+		{Table: "products", Column: "category_id", Total: 2, IDs: []int{3, 5}},
+	}, refs)
+}
+
+func (suite *referenceRepositoryTestSuite) TestRepository_References_ExpectReturnError() {
+	suite.mock.ExpectQuery("SELECT id FROM product_variants WHERE unit_id = \\$1").
+		WithArgs(1).
+		WillReturnError(errors.New("UNEXPECTED"))
+	refs, err := suite.repo.References(context.TODO(), "units", 1)
+	require.Nil(suite.T(), refs)
+	require.EqualError(suite.T(), err, "UNEXPECTED")
+}
+
+func (suite *referenceRepositoryTestSuite) TestRepository_References_ExpectEmptyWhenNotGuarded() {
+	refs, err := suite.repo.References(context.TODO(), "terminals", 1)
+	require.NoError(suite.T(), err)
+	require.Nil(suite.T(), refs)
+}
+
+func (suite *referenceRepositoryTestSuite) expectSoftDelete(table string, id int) {
+	suite.mock.ExpectExec("UPDATE "+table+" SET deleted_at = \\$1, deleted_by = \\$2 "+
+		"WHERE id = \\$3 AND deleted_at IS NULL").
+		WithArgs(sqlmock.AnyArg(), nil, id).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+}
+
+func (suite *referenceRepositoryTestSuite) TestRepository_Delete_ExpectSuccess() {
+	suite.expectVersioned()
+	suite.mock.ExpectQuery("SELECT id FROM product_variants WHERE unit_id = \\$1").
+		WithArgs(1).
+		WillReturnRows(suite.mock.NewRows([]string{"id"}))
+	suite.expectSoftDelete("units", 1)
+	suite.expectVersionedCommit()
+	refs, err := suite.repo.Delete(context.TODO(), "units", 1, 0)
+	require.NoError(suite.T(), err)
+	require.Nil(suite.T(), refs)
+}
+
+func (suite *referenceRepositoryTestSuite) TestRepository_Delete_ExpectReassignInTheSameTx() {
+	suite.expectVersioned()
+	suite.mock.ExpectQuery("SELECT EXISTS \\(SELECT 1 FROM subcategories WHERE id = \\$1 AND deleted_at IS NULL\\)").
+		WithArgs(2).
+		WillReturnRows(suite.mock.NewRows([]string{"exists"}).AddRow(true))
+	suite.mock.ExpectExec("UPDATE products SET subcategory_id = \\$1, "+
+		"category_id = \\(SELECT category_id FROM subcategories WHERE id = \\$1\\) WHERE subcategory_id = \\$2").
+		WithArgs(2, 1).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+	suite.mock.ExpectExec("UPDATE bundle_slots SET subcategory_id = \\$1 WHERE subcategory_id = \\$2").
+		WithArgs(2, 1).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	suite.mock.ExpectQuery("SELECT id FROM products WHERE subcategory_id = \\$1").
+		WithArgs(1).
+		WillReturnRows(suite.mock.NewRows([]string{"id"}))
+	suite.mock.ExpectQuery("SELECT id FROM bundle_slots WHERE subcategory_id = \\$1").
+		WithArgs(1).
+		WillReturnRows(suite.mock.NewRows([]string{"id"}))
+	suite.expectSoftDelete("subcategories", 1)
+	suite.expectVersionedCommit()
+	refs, err := suite.repo.Delete(context.TODO(), "subcategories", 1, 2)
+	require.NoError(suite.T(), err)
+	require.Nil(suite.T(), refs)
+}
+
+func (suite *referenceRepositoryTestSuite) TestRepository_Delete_ExpectMergeJoinRows() {
+	suite.expectVersioned()
+	suite.mock.ExpectQuery("SELECT EXISTS (.+) FROM addons").
+		WithArgs(2).
+		WillReturnRows(suite.mock.NewRows([]string{"exists"}).AddRow(true))
+	suite.mock.ExpectExec("UPDATE addon_group_items SET addon_id = \\$1 WHERE addon_id = \\$2 "+
+		"AND addon_group_id NOT IN \\(SELECT addon_group_id FROM addon_group_items WHERE addon_id = \\$1\\)").
+		WithArgs(2, 1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	suite.mock.ExpectExec("DELETE FROM addon_group_items WHERE addon_id = \\$1").
+		WithArgs(1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	suite.mock.ExpectExec("UPDATE product_addon_prices SET addon_id = \\$1 WHERE addon_id = \\$2 "+
+		"AND product_id NOT IN \\(SELECT product_id FROM product_addon_prices WHERE addon_id = \\$1\\)").
+		WithArgs(2, 1).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	suite.mock.ExpectExec("DELETE FROM product_addon_prices WHERE addon_id = \\$1").
+		WithArgs(1).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	suite.mock.ExpectQuery("SELECT addon_group_id FROM addon_group_items WHERE addon_id = \\$1").
+		WithArgs(1).
+		WillReturnRows(suite.mock.NewRows([]string{"addon_group_id"}))
+	suite.mock.ExpectQuery("SELECT product_id FROM product_addon_prices WHERE addon_id = \\$1").
+		WithArgs(1).
+		WillReturnRows(suite.mock.NewRows([]string{"product_id"}))
+	suite.expectSoftDelete("addons", 1)
+	suite.expectVersionedCommit()
+	_, err := suite.repo.Delete(context.TODO(), "addons", 1, 2)
+	require.NoError(suite.T(), err)
+}
+
+func (suite *referenceRepositoryTestSuite) TestRepository_Delete_ExpectRollbackWhenReferenced() {
+	suite.expectVersioned()
+	suite.mock.ExpectQuery("SELECT id FROM users WHERE role_id = \\$1").
+		WithArgs(1).
+		WillReturnRows(suite.mock.NewRows([]string{"id"}).AddRow(4))
+	suite.mock.ExpectRollback()
+	refs, err := suite.repo.Delete(context.TODO(), "roles", 1, 0)
+	require.NoError(suite.T(), err)
+	require.Equal(suite.T(), []*model.Reference{
+		{Table: "users", Column: "role_id", Total: 1, IDs: []int{4}},
+	}, refs)
+}
+
+func (suite *referenceRepositoryTestSuite) TestRepository_Delete_ExpectErrorNoTarget() {
+	suite.expectVersioned()
+	suite.mock.ExpectQuery("SELECT EXISTS (.+) FROM units").
+		WithArgs(2).
+		WillReturnRows(suite.mock.NewRows([]string{"exists"}).AddRow(false))
+	suite.mock.ExpectRollback()
+	refs, err := suite.repo.Delete(context.TODO(), "units", 1, 2)
+	require.Nil(suite.T(), refs)
+	require.ErrorIs(suite.T(), err, sql.ErrNoRows)
+}
+
+func (suite *referenceRepositoryTestSuite) TestRepository_Delete_ExpectRollback() {
+	suite.expectVersioned()
+	suite.mock.ExpectQuery("SELECT EXISTS (.+) FROM roles").
+		WithArgs(2).
+		WillReturnRows(suite.mock.NewRows([]string{"exists"}).AddRow(true))
+	suite.mock.ExpectExec("UPDATE users SET role_id = \\$1 WHERE role_id = \\$2").
+		WithArgs(2, 1).
+		WillReturnError(errors.New("UNEXPECTED"))
+	suite.mock.ExpectRollback()
+	_, err := suite.repo.Delete(context.TODO(), "roles", 1, 2)
+	require.EqualError(suite.T(), err, "UNEXPECTED")
+}
+
+func (suite *referenceRepositoryTestSuite) TestRepository_Delete_ExpectErrorNotGuarded() {
+	_, err := suite.repo.Delete(context.TODO(), "terminals", 1, 0)
+	require.Error(suite.T(), err)
+}
+
+func (suite *referenceRepositoryTestSuite) TestRepository_Delete() {
+	db := dbtest.Migrated(suite.T(), config.Dialect.Name())
+	ctx := context.TODO()
+	repo := reference.NewReferenceSQLRepository()
+	refs, err := repo.References(ctx, "subcategories", 4)
+	require.NoError(suite.T(), err)
+	require.Len(suite.T(), refs, 1)
+	require.Equal(suite.T(), "products", refs[0].Table)
+	require.Equal(suite.T(), []int{1}, refs[0].IDs)
+
+	// the referenced row is not deleted
+	refs, err = repo.Delete(ctx, "units", 4, 0)
+	require.NoError(suite.T(), err)
+	require.Len(suite.T(), refs, 1)
+	var deletedAt sql.NullInt64
+	require.NoError(suite.T(), db.QueryRowContext(ctx,
+		"SELECT deleted_at FROM units WHERE id = 4").Scan(&deletedAt))
+	require.False(suite.T(), deletedAt.Valid)
+
+	// the product follow the subcategory to its category
+	refs, err = repo.Delete(ctx, "subcategories", 4, 1)
+	require.NoError(suite.T(), err)
+	require.Empty(suite.T(), refs)
+	var categoryID, subcategoryID int
+	require.NoError(suite.T(), db.QueryRowContext(ctx,
+		"SELECT category_id, subcategory_id FROM products WHERE id = 1",
+	).Scan(&categoryID, &subcategoryID))
+	require.Equal(suite.T(), 1, categoryID)
+	require.Equal(suite.T(), 1, subcategoryID)
+	require.NoError(suite.T(), db.QueryRowContext(ctx,
+		"SELECT deleted_at FROM subcategories WHERE id = 4").Scan(&deletedAt))
+	require.True(suite.T(), deletedAt.Valid)
+
+	_, err = repo.Delete(ctx, "subcategories", 1, 99)
+	require.ErrorIs(suite.T(), err, sql.ErrNoRows)
+}
+
+func TestReferenceRepository(t *testing.T) {
+	for _, d := range dialect.All() {
+		t.Run(d.Name(), func(t *testing.T) {
+			config.Dialect = d
+			suite.Run(t, new(referenceRepositoryTestSuite))
+		})
+	}
+}
@@ -0,0 +1,74 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// LocalStorage keep the files on the local filesystem,
+// PublicURL is where the Root directory is served (e.g: /uploads)
+type LocalStorage struct {
+	Root      string
+	PublicURL string
+}
+
+func (s *LocalStorage) Put(
+	_ context.Context,
+	key string,
+	body io.Reader,
+	_ int64,
+	contentType string,
+) (object *Object, err error) {
+	key, err = CleanKey(key)
+	if err != nil {
+		return nil, err
+	}
+	target := filepath.Join(s.Root, filepath.FromSlash(key))
+	if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		return nil, err
+	}
+	// write to temp file first, so reader never see half written file
+	tmp, err := os.CreateTemp(filepath.Dir(target), ".upload-*")
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+	size, err := io.Copy(tmp, body)
+	if err != nil {
+		_ = tmp.Close()
+		return nil, err
+	}
+	if err := tmp.Close(); err != nil {
+		return nil, err
+	}
+	if err := os.Rename(tmp.Name(), target); err != nil {
+		return nil, err
+	}
+	return &Object{
+		Key: key, URL: s.URL(key),
+		ContentType: contentType, Size: size,
+	}, nil
+}
+
+func (s *LocalStorage) Delete(_ context.Context, key string) error {
+	key, err := CleanKey(key)
+	if err != nil {
+		return err
+	}
+	err = os.Remove(filepath.Join(s.Root, filepath.FromSlash(key)))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+func (s *LocalStorage) URL(key string) string {
+	return joinURL(s.PublicURL, key)
+}
+
+func NewLocalStorage(root, publicURL string) Storage {
+	return &LocalStorage{Root: root, PublicURL: publicURL}
+}
@@ -0,0 +1,42 @@
+package storage_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/aasumitro/posbe/pkg/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocalStorage_PutAndDelete(t *testing.T) {
+	root := t.TempDir()
+	s := storage.NewLocalStorage(root, "/uploads/")
+	object, err := s.Put(context.TODO(), "/products/1/cover.jpg",
+		strings.NewReader("image"), 5, "image/jpeg")
+	require.NoError(t, err)
+	assert.Equal(t, &storage.Object{
+		Key: "products/1/cover.jpg", URL: "/uploads/products/1/cover.jpg",
+		ContentType: "image/jpeg", Size: 5,
+	}, object)
+	data, err := os.ReadFile(filepath.Join(root, "products", "1", "cover.jpg"))
+	require.NoError(t, err)
+	assert.Equal(t, "image", string(data))
+
+	require.NoError(t, s.Delete(context.TODO(), "products/1/cover.jpg"))
+	_, err = os.Stat(filepath.Join(root, "products", "1", "cover.jpg"))
+	assert.True(t, os.IsNotExist(err))
+	// deleting missing file is not an error
+	require.NoError(t, s.Delete(context.TODO(), "products/1/cover.jpg"))
+}
+
+func TestLocalStorage_InvalidKey(t *testing.T) {
+	s := storage.NewLocalStorage(t.TempDir(), "/uploads")
+	for _, key := range []string{"", "../etc/passwd", "products/../../x"} {
+		_, err := s.Put(context.TODO(), key, strings.NewReader("x"), 1, "text/plain")
+		require.ErrorIs(t, err, storage.ErrorInvalidKey, key)
+	}
+}
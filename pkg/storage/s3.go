@@ -0,0 +1,209 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+// S3Storage keep the files on any S3 compatible object storage
+// (AWS S3, MinIO, DigitalOcean Spaces, etc.), requests are signed with SigV4
+type S3Storage struct {
+	Endpoint  string
+	Region    string
+	Bucket    string
+	AccessKey string
+	SecretKey string
+	// PathStyle use endpoint/bucket/key instead of bucket.endpoint/key,
+	// required by most self-hosted storage
+	PathStyle bool
+	// PublicURL is optional, e.g: CDN in front of the bucket
+	PublicURL string
+	Client    *http.Client
+
+	now func() time.Time
+}
+
+const (
+	s3Algorithm = "AWS4-HMAC-SHA256"
+	s3Service   = "s3"
+	s3DateTime  = "20060102T150405Z"
+	s3Date      = "20060102"
+)
+
+func (s *S3Storage) Put(
+	ctx context.Context,
+	key string,
+	body io.Reader,
+	size int64,
+	contentType string,
+) (object *Object, err error) {
+	key, err = CleanKey(key)
+	if err != nil {
+		return nil, err
+	}
+	// payload is hashed for the signature, uploads are small enough to buffer
+	payload, err := io.ReadAll(body)
+	if err != nil {
+		return nil, err
+	}
+	if size > 0 && int64(len(payload)) != size {
+		return nil, fmt.Errorf("storage: expected %d bytes, got %d", size, len(payload))
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut,
+		s.objectURL(key), bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+	req.ContentLength = int64(len(payload))
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	if err := s.do(req, payload); err != nil {
+		return nil, err
+	}
+	return &Object{
+		Key: key, URL: s.URL(key),
+		ContentType: contentType, Size: int64(len(payload)),
+	}, nil
+}
+
+func (s *S3Storage) Delete(ctx context.Context, key string) error {
+	key, err := CleanKey(key)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, s.objectURL(key), nil)
+	if err != nil {
+		return err
+	}
+	return s.do(req, nil)
+}
+
+func (s *S3Storage) URL(key string) string {
+	if s.PublicURL != "" {
+		return joinURL(s.PublicURL, key)
+	}
+	return s.objectURL(key)
+}
+
+func (s *S3Storage) objectURL(key string) string {
+	endpoint, err := url.Parse(s.Endpoint)
+	if err != nil || s.PathStyle {
+		return joinURL(s.Endpoint, s.Bucket+"/"+escapePath(key))
+	}
+	return fmt.Sprintf("%s://%s.%s/%s", endpoint.Scheme,
+		s.Bucket, endpoint.Host, escapePath(key))
+}
+
+func (s *S3Storage) do(req *http.Request, payload []byte) error {
+	s.sign(req, payload)
+	client := s.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode >= http.StatusMultipleChoices {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("storage: %s %s: %s", req.Method, resp.Status,
+			strings.TrimSpace(string(message)))
+	}
+	return nil
+}
+
+// sign add SigV4 headers to the request, every header already set
+// on the request (plus host) is part of the signature
+func (s *S3Storage) sign(req *http.Request, payload []byte) {
+	now := time.Now
+	if s.now != nil {
+		now = s.now
+	}
+	t := now().UTC()
+	payloadHash := sha256Hex(payload)
+	req.Header.Set("X-Amz-Date", t.Format(s3DateTime))
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	headers := map[string]string{"host": req.URL.Host}
+	for name, values := range req.Header {
+		headers[strings.ToLower(name)] = strings.TrimSpace(strings.Join(values, ","))
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.Query().Encode(),
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+	scope := strings.Join([]string{t.Format(s3Date), s.Region, s3Service, "aws4_request"}, "/")
+	stringToSign := strings.Join([]string{
+		s3Algorithm, t.Format(s3DateTime), scope,
+		sha256Hex([]byte(canonicalRequest)),
+	}, "\n")
+
+	signingKey := hmacSHA256([]byte("AWS4"+s.SecretKey), t.Format(s3Date))
+	for _, part := range []string{s.Region, s3Service, "aws4_request"} {
+		signingKey = hmacSHA256(signingKey, part)
+	}
+	signature := hex.EncodeToString(hmacSHA256(signingKey, stringToSign))
+	req.Header.Set("Authorization", fmt.Sprintf(
+		"%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s3Algorithm, s.AccessKey, scope, signedHeaders, signature))
+}
+
+func escapePath(key string) string {
+	segments := strings.Split(key, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	return strings.Join(segments, "/")
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+func NewS3Storage(
+	endpoint, region, bucket,
+	accessKey, secretKey string,
+	pathStyle bool,
+	publicURL string,
+) Storage {
+	return &S3Storage{
+		Endpoint: endpoint, Region: region, Bucket: bucket,
+		AccessKey: accessKey, SecretKey: secretKey,
+		PathStyle: pathStyle, PublicURL: publicURL,
+		Client: &http.Client{Timeout: 30 * time.Second},
+	}
+}
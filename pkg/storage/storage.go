@@ -0,0 +1,48 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"path"
+	"strings"
+)
+
+type (
+	// Object describe the stored file
+	Object struct {
+		Key         string `json:"key"`
+		URL         string `json:"url"`
+		ContentType string `json:"content_type"`
+		Size        int64  `json:"size"`
+	}
+
+	// Storage is the file backend used by uploads,
+	// keys are always slash separated (e.g: products/1/abc.jpg)
+	Storage interface {
+		Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) (object *Object, err error)
+		Delete(ctx context.Context, key string) error
+		URL(key string) string
+	}
+)
+
+const (
+	DriverLocal = "local"
+	DriverS3    = "s3"
+)
+
+var ErrorInvalidKey = errors.New("invalid storage key")
+
+// CleanKey normalize the given key and reject path traversal
+func CleanKey(key string) (string, error) {
+	cleaned := path.Clean("/" + strings.ReplaceAll(key, "\\", "/"))
+	cleaned = strings.TrimPrefix(cleaned, "/")
+	if cleaned == "" || cleaned == "." || strings.Contains(key, "..") {
+		return "", ErrorInvalidKey
+	}
+	return cleaned, nil
+}
+
+func joinURL(base, key string) string {
+	return strings.TrimRight(base, "/") + "/" + key
+}
@@ -0,0 +1,338 @@
+package utils
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aasumitro/posbe/pkg/money"
+	"github.com/xuri/excelize/v2"
+)
+
+type (
+	ExportFormat string
+
+	ExportColumnKind int
+
+	// ExportColumn describe a single column of exported data,
+	// Key is used for column selection and Title as the header
+	ExportColumn struct {
+		Key   string           `json:"key"`
+		Title string           `json:"title"`
+		Kind  ExportColumnKind `json:"kind"`
+	}
+
+	// ExportLocale hold number & date format rules
+	ExportLocale struct {
+		Decimal    string
+		Thousand   string
+		DateLayout string
+		XLSXDate   string
+		Location   *time.Location
+	}
+
+	// ExportWriter write rows one by one to the underlying writer,
+	// so the caller never needs to hold the whole data set in memory
+	ExportWriter interface {
+		ContentType() string
+		WriteHeader(columns []ExportColumn) error
+		WriteRow(values []any) error
+		Close() error
+	}
+
+	csvExportWriter struct {
+		writer  *csv.Writer
+		locale  *ExportLocale
+		columns []ExportColumn
+		rows    int
+	}
+
+	xlsxExportWriter struct {
+		out     io.Writer
+		file    *excelize.File
+		stream  *excelize.StreamWriter
+		locale  *ExportLocale
+		columns []ExportColumn
+		styles  map[ExportColumnKind]int
+		rows    int
+	}
+)
+
+const (
+	ExportFormatCSV  ExportFormat = "csv"
+	ExportFormatXLSX ExportFormat = "xlsx"
+)
+
+const (
+	ExportKindText ExportColumnKind = iota
+	ExportKindInteger
+	ExportKindDecimal
+	ExportKindDate
+	// ExportKindMoney is minor unit of the store currency (e.g: cent)
+	ExportKindMoney
+)
+
+const (
+	csvFlushEvery  = 500
+	xlsxSheetName  = "Sheet1"
+	decimalPlaces  = 2
+	thousandsGroup = 3
+)
+
+var ErrorExportUnsupportedFormat = errors.New("unsupported export format")
+
+// ContentType return mime type of the given format
+func (format ExportFormat) ContentType() string {
+	if format == ExportFormatXLSX {
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	}
+	return "text/csv"
+}
+
+// NewExportLocale build locale rules from store lang pref (e.g: en_US, id_ID)
+// and timezone pref (e.g: Asia/Makassar), unknown value will fall back to en_US/UTC
+func NewExportLocale(lang, timezone string) *ExportLocale {
+	loc, err := time.LoadLocation(timezone)
+	if err != nil || timezone == "" {
+		loc = time.UTC
+	}
+	if strings.EqualFold(lang, "id_ID") {
+		return &ExportLocale{
+			Decimal: ",", Thousand: ".",
+			DateLayout: "02/01/2006 15:04",
+			XLSXDate:   "dd/mm/yyyy hh:mm",
+			Location:   loc,
+		}
+	}
+	return &ExportLocale{
+		Decimal: ".", Thousand: ",",
+		DateLayout: "01/02/2006 15:04",
+		XLSXDate:   "mm/dd/yyyy hh:mm",
+		Location:   loc,
+	}
+}
+
+// FormatNumber format given number with locale separators
+func (locale *ExportLocale) FormatNumber(value float64, places int) string {
+	str := strconv.FormatFloat(math.Abs(value), 'f', places, 64)
+	intPart, fracPart, _ := strings.Cut(str, ".")
+	var grouped strings.Builder
+	for i, digit := range intPart {
+		if i > 0 && (len(intPart)-i)%thousandsGroup == 0 {
+			grouped.WriteString(locale.Thousand)
+		}
+		grouped.WriteRune(digit)
+	}
+	result := grouped.String()
+	if fracPart != "" {
+		result += locale.Decimal + fracPart
+	}
+	if value < 0 {
+		result = "-" + result
+	}
+	return result
+}
+
+// FormatDate format unix timestamp with locale layout & timezone
+func (locale *ExportLocale) FormatDate(unix int64) string {
+	return time.Unix(unix, 0).In(locale.Location).Format(locale.DateLayout)
+}
+
+func NewExportWriter(
+	format ExportFormat,
+	out io.Writer,
+	locale *ExportLocale,
+) (ExportWriter, error) {
+	switch format {
+	case ExportFormatCSV:
+		return &csvExportWriter{writer: csv.NewWriter(out), locale: locale}, nil
+	case ExportFormatXLSX:
+		file := excelize.NewFile()
+		stream, err := file.NewStreamWriter(xlsxSheetName)
+		if err != nil {
+			return nil, err
+		}
+		return &xlsxExportWriter{
+			out: out, file: file, stream: stream, locale: locale,
+			styles: make(map[ExportColumnKind]int),
+		}, nil
+	}
+	return nil, ErrorExportUnsupportedFormat
+}
+
+func (w *csvExportWriter) ContentType() string {
+	return ExportFormatCSV.ContentType()
+}
+
+func (w *csvExportWriter) WriteHeader(columns []ExportColumn) error {
+	w.columns = columns
+	titles := make([]string, len(columns))
+	for i, column := range columns {
+		titles[i] = column.Title
+	}
+	return w.writer.Write(titles)
+}
+
+func (w *csvExportWriter) WriteRow(values []any) error {
+	record := make([]string, len(values))
+	for i, value := range values {
+		record[i] = w.format(w.columns[i].Kind, value)
+	}
+	if err := w.writer.Write(record); err != nil {
+		return err
+	}
+	w.rows++
+	if w.rows%csvFlushEvery == 0 {
+		w.writer.Flush()
+	}
+	return w.writer.Error()
+}
+
+func (w *csvExportWriter) Close() error {
+	w.writer.Flush()
+	return w.writer.Error()
+}
+
+func (w *csvExportWriter) format(kind ExportColumnKind, value any) string {
+	if value == nil {
+		return ""
+	}
+	switch kind {
+	case ExportKindInteger:
+		if number, ok := exportNumber(value); ok {
+			return w.locale.FormatNumber(number, 0)
+		}
+	case ExportKindDecimal:
+		if number, ok := exportNumber(value); ok {
+			return w.locale.FormatNumber(number, decimalPlaces)
+		}
+	case ExportKindDate:
+		if number, ok := exportNumber(value); ok {
+			return w.locale.FormatDate(int64(number))
+		}
+	case ExportKindMoney:
+		if number, ok := exportNumber(value); ok {
+			currency := money.StoreCurrency()
+			return w.locale.FormatNumber(
+				currency.Major(money.Amount(number)), currency.Exponent)
+		}
+	}
+	return exportText(value)
+}
+
+func (w *xlsxExportWriter) ContentType() string {
+	return ExportFormatXLSX.ContentType()
+}
+
+func (w *xlsxExportWriter) WriteHeader(columns []ExportColumn) error {
+	w.columns = columns
+	formats := map[ExportColumnKind]string{
+		ExportKindInteger: "#,##0",
+		ExportKindDecimal: "#,##0.00",
+		ExportKindDate:    w.locale.XLSXDate,
+		ExportKindMoney:   "#,##0",
+	}
+	if exponent := money.StoreCurrency().Exponent; exponent > 0 {
+		formats[ExportKindMoney] += "." + strings.Repeat("0", exponent)
+	}
+	for kind, format := range formats {
+		numFmt := format
+		styleID, err := w.file.NewStyle(&excelize.Style{CustomNumFmt: &numFmt})
+		if err != nil {
+			return err
+		}
+		w.styles[kind] = styleID
+	}
+	titles := make([]any, len(columns))
+	for i, column := range columns {
+		titles[i] = column.Title
+	}
+	return w.writeRow(titles)
+}
+
+func (w *xlsxExportWriter) WriteRow(values []any) error {
+	cells := make([]any, len(values))
+	for i, value := range values {
+		cells[i] = w.cell(w.columns[i].Kind, value)
+	}
+	return w.writeRow(cells)
+}
+
+func (w *xlsxExportWriter) Close() error {
+	defer func() { _ = w.file.Close() }()
+	if err := w.stream.Flush(); err != nil {
+		return err
+	}
+	return w.file.Write(w.out)
+}
+
+func (w *xlsxExportWriter) writeRow(cells []any) error {
+	w.rows++
+	axis, err := excelize.CoordinatesToCellName(1, w.rows)
+	if err != nil {
+		return err
+	}
+	return w.stream.SetRow(axis, cells)
+}
+
+func (w *xlsxExportWriter) cell(kind ExportColumnKind, value any) any {
+	if value == nil {
+		return nil
+	}
+	number, ok := exportNumber(value)
+	if !ok || kind == ExportKindText {
+		return exportText(value)
+	}
+	if kind == ExportKindDate {
+		// spreadsheet has no timezone, keep the store wall clock
+		local := time.Unix(int64(number), 0).In(w.locale.Location)
+		return excelize.Cell{
+			StyleID: w.styles[kind],
+			Value: time.Date(local.Year(), local.Month(), local.Day(),
+				local.Hour(), local.Minute(), local.Second(), 0, time.UTC),
+		}
+	}
+	if kind == ExportKindMoney {
+		number = money.StoreCurrency().Major(money.Amount(number))
+	}
+	return excelize.Cell{StyleID: w.styles[kind], Value: number}
+}
+
+func exportNumber(value any) (float64, bool) {
+	switch v := value.(type) {
+	case int:
+		return float64(v), true
+	case int32:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case float32:
+		return float64(v), true
+	case float64:
+		return v, true
+	case []byte:
+		number, err := strconv.ParseFloat(string(v), 64)
+		return number, err == nil
+	case string:
+		number, err := strconv.ParseFloat(v, 64)
+		return number, err == nil
+	}
+	return 0, false
+}
+
+func exportText(value any) string {
+	switch v := value.(type) {
+	case []byte:
+		return string(v)
+	case string:
+		return v
+	case time.Time:
+		return v.Format(time.RFC3339)
+	}
+	return fmt.Sprint(value)
+}
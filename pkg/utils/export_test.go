@@ -0,0 +1,118 @@
+package utils_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/aasumitro/posbe/pkg/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xuri/excelize/v2"
+)
+
+var exportColumns = []utils.ExportColumn{
+	{Key: "name", Title: "Name", Kind: utils.ExportKindText},
+	{Key: "qty", Title: "Qty", Kind: utils.ExportKindInteger},
+	{Key: "price", Title: "Price", Kind: utils.ExportKindDecimal},
+	{Key: "date", Title: "Date", Kind: utils.ExportKindDate},
+}
+
+func TestExportLocale_FormatNumber(t *testing.T) {
+	tests := []struct {
+		name   string
+		lang   string
+		value  float64
+		places int
+		want   string
+	}{
+		{name: "en_US decimal", lang: "en_US", value: 1234567.891, places: 2, want: "1,234,567.89"},
+		{name: "id_ID decimal", lang: "id_ID", value: 1234567.891, places: 2, want: "1.234.567,89"},
+		{name: "id_ID integer", lang: "id_ID", value: 16200, places: 0, want: "16.200"},
+		{name: "negative", lang: "en_US", value: -1000, places: 0, want: "-1,000"},
+		{name: "small", lang: "en_US", value: 12, places: 0, want: "12"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			locale := utils.NewExportLocale(tt.lang, "")
+			assert.Equal(t, tt.want, locale.FormatNumber(tt.value, tt.places))
+		})
+	}
+}
+
+func TestExportLocale_FormatDate(t *testing.T) {
+	var unix int64 = 1714377600 // 2024-04-29 08:00:00 UTC
+	assert.Equal(t, "04/29/2024 08:00",
+		utils.NewExportLocale("en_US", "UTC").FormatDate(unix))
+	assert.Equal(t, "29/04/2024 16:00",
+		utils.NewExportLocale("id_ID", "Asia/Makassar").FormatDate(unix))
+	assert.Equal(t, "04/29/2024 08:00",
+		utils.NewExportLocale("xx", "Unknown/Zone").FormatDate(unix))
+}
+
+func TestNewExportWriter_UnsupportedFormat(t *testing.T) {
+	w, err := utils.NewExportWriter("pdf", &bytes.Buffer{}, utils.NewExportLocale("", ""))
+	assert.Nil(t, w)
+	assert.ErrorIs(t, err, utils.ErrorExportUnsupportedFormat)
+}
+
+func TestExportWriter_CSV(t *testing.T) {
+	var buf bytes.Buffer
+	w, err := utils.NewExportWriter(utils.ExportFormatCSV, &buf,
+		utils.NewExportLocale("id_ID", "UTC"))
+	require.NoError(t, err)
+	assert.Equal(t, "text/csv", w.ContentType())
+	require.NoError(t, w.WriteHeader(exportColumns))
+	require.NoError(t, w.WriteRow([]any{"kopi, susu", int64(1200), []byte("25000.5"), int64(1714377600)}))
+	require.NoError(t, w.WriteRow([]any{"teh", nil, 10.0, nil}))
+	require.NoError(t, w.Close())
+	assert.Equal(t, "Name,Qty,Price,Date\n"+
+		"\"kopi, susu\",1.200,\"25.000,50\",29/04/2024 08:00\n"+
+		"teh,,\"10,00\",\n", buf.String())
+}
+
+func TestExportWriter_XLSX(t *testing.T) {
+	var buf bytes.Buffer
+	w, err := utils.NewExportWriter(utils.ExportFormatXLSX, &buf,
+		utils.NewExportLocale("en_US", "UTC"))
+	require.NoError(t, err)
+	assert.Contains(t, w.ContentType(), "spreadsheetml")
+	require.NoError(t, w.WriteHeader(exportColumns))
+	require.NoError(t, w.WriteRow([]any{"kopi", int64(2), 25000.5, int64(1714377600)}))
+	require.NoError(t, w.Close())
+	file, err := excelize.OpenReader(&buf)
+	require.NoError(t, err)
+	defer func() { _ = file.Close() }()
+	rows, err := file.GetRows("Sheet1", excelize.Options{RawCellValue: true})
+	require.NoError(t, err)
+	require.Len(t, rows, 2)
+	assert.Equal(t, []string{"Name", "Qty", "Price", "Date"}, rows[0])
+	assert.Equal(t, "kopi", rows[1][0])
+	assert.Equal(t, "2", rows[1][1])
+	assert.Equal(t, "25000.5", rows[1][2])
+}
+
+func TestExportWriter_Money(t *testing.T) {
+	columns := []utils.ExportColumn{{Key: "price", Title: "Price", Kind: utils.ExportKindMoney}}
+	var buf bytes.Buffer
+	w, err := utils.NewExportWriter(utils.ExportFormatCSV, &buf,
+		utils.NewExportLocale("id_ID", "UTC"))
+	require.NoError(t, err)
+	require.NoError(t, w.WriteHeader(columns))
+	require.NoError(t, w.WriteRow([]any{int64(2500050)}))
+	require.NoError(t, w.Close())
+	assert.Equal(t, "Price\n\"25.000,50\"\n", buf.String())
+
+	buf.Reset()
+	w, err = utils.NewExportWriter(utils.ExportFormatXLSX, &buf,
+		utils.NewExportLocale("en_US", "UTC"))
+	require.NoError(t, err)
+	require.NoError(t, w.WriteHeader(columns))
+	require.NoError(t, w.WriteRow([]any{int64(2500050)}))
+	require.NoError(t, w.Close())
+	file, err := excelize.OpenReader(&buf)
+	require.NoError(t, err)
+	defer func() { _ = file.Close() }()
+	rows, err := file.GetRows("Sheet1", excelize.Options{RawCellValue: true})
+	require.NoError(t, err)
+	assert.Equal(t, "25000.5", rows[1][0])
+}
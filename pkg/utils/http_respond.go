@@ -37,7 +37,8 @@ func NewHTTPRespond(context *gin.Context, code int, data interface{}) {
 		return
 	}
 
-	if code == http.StatusUnprocessableEntity {
+	// a conflict carry the data that block the request (e.g. references)
+	if code == http.StatusUnprocessableEntity || code == http.StatusConflict {
 		context.JSON(code, ValidationErrorRespond{
 			Code:   code,
 			Status: http.StatusText(code),
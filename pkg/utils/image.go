@@ -0,0 +1,145 @@
+package utils
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	// register gif decoder, gif is accepted and stored as is
+	_ "image/gif"
+
+	"golang.org/x/image/draw"
+	// register webp decoder
+	_ "golang.org/x/image/webp"
+)
+
+// UploadImage is a validated image with the generated thumbnail
+type UploadImage struct {
+	Data          []byte
+	ContentType   string
+	Ext           string
+	Width         int
+	Height        int
+	Thumbnail     []byte
+	ThumbnailType string
+	ThumbnailExt  string
+}
+
+const (
+	MaxUploadImageSize = 5 << 20
+	ThumbnailSize      = 320
+	// maxImagePixels guard against decompression bomb
+	maxImagePixels   = 40_000_000
+	thumbnailQuality = 85
+)
+
+var (
+	ErrorImageTooLarge        = errors.New("image is larger than the allowed size")
+	ErrorImageUnsupportedType = errors.New("unsupported image type, use jpeg, png, gif or webp")
+	ErrorImageInvalid         = errors.New("unable to read image")
+)
+
+var imageExtensions = map[string]string{
+	"image/jpeg": "jpg",
+	"image/png":  "png",
+	"image/gif":  "gif",
+	"image/webp": "webp",
+}
+
+// ProcessImage read at most maxSize bytes, validate the real content type
+// (not the client provided one) and fit a thumbnail into thumbSize box
+func ProcessImage(in io.Reader, maxSize int64, thumbSize int) (*UploadImage, error) {
+	data, err := io.ReadAll(io.LimitReader(in, maxSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > maxSize {
+		return nil, ErrorImageTooLarge
+	}
+	contentType := http.DetectContentType(data)
+	ext, ok := imageExtensions[contentType]
+	if !ok {
+		return nil, ErrorImageUnsupportedType
+	}
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil || cfg.Width == 0 || cfg.Height == 0 {
+		return nil, ErrorImageInvalid
+	}
+	if cfg.Width*cfg.Height > maxImagePixels {
+		return nil, ErrorImageTooLarge
+	}
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrorImageInvalid
+	}
+	upload := &UploadImage{
+		Data: data, ContentType: contentType, Ext: ext,
+		Width: cfg.Width, Height: cfg.Height,
+	}
+	if err := upload.thumbnail(src, thumbSize); err != nil {
+		return nil, err
+	}
+	return upload, nil
+}
+
+func (upload *UploadImage) thumbnail(src image.Image, size int) error {
+	bounds := src.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	// keep aspect ratio, never upscale
+	if width > size || height > size {
+		if width >= height {
+			width, height = size, max(1, height*size/width)
+		} else {
+			width, height = max(1, width*size/height), size
+		}
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), src, bounds, draw.Over, nil)
+	var buf bytes.Buffer
+	// png keep the transparency, everything else become jpeg
+	if upload.ContentType == "image/png" {
+		if err := png.Encode(&buf, dst); err != nil {
+			return err
+		}
+		upload.ThumbnailType, upload.ThumbnailExt = "image/png", "png"
+	} else {
+		if err := jpeg.Encode(&buf, dst, &jpeg.Options{Quality: thumbnailQuality}); err != nil {
+			return err
+		}
+		upload.ThumbnailType, upload.ThumbnailExt = "image/jpeg", "jpg"
+	}
+	upload.Thumbnail = buf.Bytes()
+	return nil
+}
+
+// ReadUploadImage read and process image from multipart form field,
+// the returned error is ready to be used as http respond
+func ReadUploadImage(ctx *gin.Context, field string) (*UploadImage, *ServiceError) {
+	header, err := ctx.FormFile(field)
+	if err != nil {
+		return nil, &ServiceError{Code: http.StatusBadRequest, Message: err.Error()}
+	}
+	if header.Size > MaxUploadImageSize {
+		return nil, &ServiceError{Code: http.StatusRequestEntityTooLarge, Message: ErrorImageTooLarge.Error()}
+	}
+	file, err := header.Open()
+	if err != nil {
+		return nil, &ServiceError{Code: http.StatusBadRequest, Message: err.Error()}
+	}
+	defer func() { _ = file.Close() }()
+	upload, err := ProcessImage(file, MaxUploadImageSize, ThumbnailSize)
+	switch {
+	case errors.Is(err, ErrorImageTooLarge):
+		return nil, &ServiceError{Code: http.StatusRequestEntityTooLarge, Message: err.Error()}
+	case errors.Is(err, ErrorImageUnsupportedType):
+		return nil, &ServiceError{Code: http.StatusUnsupportedMediaType, Message: err.Error()}
+	case err != nil:
+		return nil, &ServiceError{Code: http.StatusUnprocessableEntity, Message: err.Error()}
+	}
+	return upload, nil
+}
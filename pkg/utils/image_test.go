@@ -0,0 +1,71 @@
+package utils_test
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"strings"
+	"testing"
+
+	"github.com/aasumitro/posbe/pkg/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func encodedImage(t *testing.T, width, height int, asPNG bool) []byte {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for x := 0; x < width; x++ {
+		img.Set(x, 0, color.RGBA{R: 255, A: 255})
+	}
+	var buf bytes.Buffer
+	if asPNG {
+		require.NoError(t, png.Encode(&buf, img))
+	} else {
+		require.NoError(t, jpeg.Encode(&buf, img, nil))
+	}
+	return buf.Bytes()
+}
+
+func TestProcessImage_Thumbnail(t *testing.T) {
+	upload, err := utils.ProcessImage(bytes.NewReader(
+		encodedImage(t, 800, 400, false)), utils.MaxUploadImageSize, 320)
+	require.NoError(t, err)
+	assert.Equal(t, "image/jpeg", upload.ContentType)
+	assert.Equal(t, "jpg", upload.Ext)
+	assert.Equal(t, 800, upload.Width)
+	assert.Equal(t, 400, upload.Height)
+	assert.Equal(t, "image/jpeg", upload.ThumbnailType)
+	thumb, _, err := image.DecodeConfig(bytes.NewReader(upload.Thumbnail))
+	require.NoError(t, err)
+	assert.Equal(t, 320, thumb.Width)
+	assert.Equal(t, 160, thumb.Height)
+}
+
+func TestProcessImage_SmallPNG(t *testing.T) {
+	upload, err := utils.ProcessImage(bytes.NewReader(
+		encodedImage(t, 100, 200, true)), utils.MaxUploadImageSize, 320)
+	require.NoError(t, err)
+	assert.Equal(t, "png", upload.ThumbnailExt)
+	thumb, err := png.DecodeConfig(bytes.NewReader(upload.Thumbnail))
+	require.NoError(t, err)
+	// never upscale
+	assert.Equal(t, 100, thumb.Width)
+	assert.Equal(t, 200, thumb.Height)
+}
+
+func TestProcessImage_Invalid(t *testing.T) {
+	_, err := utils.ProcessImage(bytes.NewReader(
+		encodedImage(t, 100, 100, true)), 10, 320)
+	require.ErrorIs(t, err, utils.ErrorImageTooLarge)
+
+	_, err = utils.ProcessImage(strings.NewReader("<svg></svg>"),
+		utils.MaxUploadImageSize, 320)
+	require.ErrorIs(t, err, utils.ErrorImageUnsupportedType)
+
+	data := encodedImage(t, 100, 100, true)
+	_, err = utils.ProcessImage(bytes.NewReader(data[:64]),
+		utils.MaxUploadImageSize, 320)
+	require.ErrorIs(t, err, utils.ErrorImageInvalid)
+}
@@ -0,0 +1,114 @@
+package utils
+
+import (
+	"encoding/csv"
+	"errors"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+type (
+	// ImportRow hold a single data row keyed by lowercase header,
+	// Line is the row number as seen by the user (header is line 1)
+	ImportRow struct {
+		Line   int
+		Values map[string]string
+	}
+
+	// ImportSheet is a named table of rows, for csv file
+	// the name is taken from the requested entity
+	ImportSheet struct {
+		Name    string
+		Headers []string
+		Rows    []*ImportRow
+	}
+)
+
+var (
+	ErrorImportUnsupportedFormat = errors.New("unsupported import format, use csv or xlsx")
+	ErrorImportSheetRequired     = errors.New("entity is required for csv import")
+)
+
+// Get return trimmed value of the given column
+func (row *ImportRow) Get(key string) string {
+	return strings.TrimSpace(row.Values[key])
+}
+
+// HasHeader check the sheet contain the given column
+func (sheet *ImportSheet) HasHeader(key string) bool {
+	for _, header := range sheet.Headers {
+		if header == key {
+			return true
+		}
+	}
+	return false
+}
+
+// ReadImportSheets read csv or xlsx file (decided by the filename extension),
+// for xlsx every worksheet is read unless sheet is provided
+func ReadImportSheets(in io.Reader, filename, sheet string) ([]*ImportSheet, error) {
+	sheet = strings.ToLower(strings.TrimSpace(sheet))
+	switch ExportFormat(strings.TrimPrefix(strings.ToLower(filepath.Ext(filename)), ".")) {
+	case ExportFormatCSV:
+		if sheet == "" {
+			return nil, ErrorImportSheetRequired
+		}
+		records, err := csv.NewReader(in).ReadAll()
+		if err != nil {
+			return nil, err
+		}
+		return []*ImportSheet{newImportSheet(sheet, records)}, nil
+	case ExportFormatXLSX:
+		file, err := excelize.OpenReader(in)
+		if err != nil {
+			return nil, err
+		}
+		defer func() { _ = file.Close() }()
+		var sheets []*ImportSheet
+		for _, name := range file.GetSheetList() {
+			if sheet != "" && !strings.EqualFold(name, sheet) {
+				continue
+			}
+			records, err := file.GetRows(name, excelize.Options{RawCellValue: true})
+			if err != nil {
+				return nil, err
+			}
+			sheets = append(sheets, newImportSheet(strings.ToLower(name), records))
+		}
+		return sheets, nil
+	}
+	return nil, ErrorImportUnsupportedFormat
+}
+
+func newImportSheet(name string, records [][]string) *ImportSheet {
+	sheet := &ImportSheet{Name: name}
+	if len(records) == 0 {
+		return sheet
+	}
+	for _, header := range records[0] {
+		sheet.Headers = append(sheet.Headers,
+			strings.ToLower(strings.TrimSpace(header)))
+	}
+	for i, record := range records[1:] {
+		row := &ImportRow{Line: i + 2, Values: make(map[string]string)}
+		empty := true
+		for j, value := range record {
+			if j >= len(sheet.Headers) {
+				break
+			}
+			if strings.TrimSpace(value) != "" {
+				empty = false
+			}
+			row.Values[sheet.Headers[j]] = value
+		}
+		// skip blank lines, spreadsheet tend to keep them
+		if empty {
+			continue
+		}
+		sheet.Rows = append(sheet.Rows, row)
+	}
+	return sheet
+}
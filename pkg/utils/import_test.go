@@ -0,0 +1,69 @@
+package utils_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/aasumitro/posbe/pkg/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xuri/excelize/v2"
+)
+
+func TestReadImportSheets_CSV(t *testing.T) {
+	in := strings.NewReader("SKU, Name ,price\nA-1,Coffee,15000\n,,\nA-2,Tea,\n")
+	sheets, err := utils.ReadImportSheets(in, "products.CSV", "Products")
+	require.NoError(t, err)
+	require.Len(t, sheets, 1)
+	sheet := sheets[0]
+	assert.Equal(t, "products", sheet.Name)
+	assert.Equal(t, []string{"sku", "name", "price"}, sheet.Headers)
+	assert.True(t, sheet.HasHeader("price"))
+	assert.False(t, sheet.HasHeader("image"))
+	require.Len(t, sheet.Rows, 2)
+	assert.Equal(t, 2, sheet.Rows[0].Line)
+	assert.Equal(t, "Coffee", sheet.Rows[0].Get("name"))
+	// blank line is skipped but still counted
+	assert.Equal(t, 4, sheet.Rows[1].Line)
+	assert.Equal(t, "", sheet.Rows[1].Get("price"))
+}
+
+func TestReadImportSheets_CSVWithoutEntity(t *testing.T) {
+	_, err := utils.ReadImportSheets(strings.NewReader("name\n"), "data.csv", "")
+	require.ErrorIs(t, err, utils.ErrorImportSheetRequired)
+}
+
+func TestReadImportSheets_UnsupportedFormat(t *testing.T) {
+	_, err := utils.ReadImportSheets(strings.NewReader(""), "data.pdf", "units")
+	require.ErrorIs(t, err, utils.ErrorImportUnsupportedFormat)
+}
+
+func TestReadImportSheets_XLSX(t *testing.T) {
+	file := excelize.NewFile()
+	require.NoError(t, file.SetSheetName("Sheet1", "Units"))
+	require.NoError(t, file.SetSheetRow("Units", "A1", &[]any{"symbol", "name", "magnitude"}))
+	require.NoError(t, file.SetSheetRow("Units", "A2", &[]any{"kg", "kilogram", "mass"}))
+	_, err := file.NewSheet("Addons")
+	require.NoError(t, err)
+	require.NoError(t, file.SetSheetRow("Addons", "A1", &[]any{"name", "price"}))
+	require.NoError(t, file.SetSheetRow("Addons", "A2", &[]any{"Cheese", 2500.5}))
+	styleID, err := file.NewStyle(&excelize.Style{NumFmt: 4})
+	require.NoError(t, err)
+	require.NoError(t, file.SetCellStyle("Addons", "B2", "B2", styleID))
+	var buf bytes.Buffer
+	require.NoError(t, file.Write(&buf))
+
+	sheets, err := utils.ReadImportSheets(bytes.NewReader(buf.Bytes()), "catalog.xlsx", "")
+	require.NoError(t, err)
+	require.Len(t, sheets, 2)
+	assert.Equal(t, "units", sheets[0].Name)
+	assert.Equal(t, "kilogram", sheets[0].Rows[0].Get("name"))
+	// formatted cell is read as raw value
+	assert.Equal(t, "2500.5", sheets[1].Rows[0].Get("price"))
+
+	sheets, err = utils.ReadImportSheets(bytes.NewReader(buf.Bytes()), "catalog.xlsx", "addons")
+	require.NoError(t, err)
+	require.Len(t, sheets, 1)
+	assert.Equal(t, "addons", sheets[0].Name)
+}
@@ -0,0 +1,17 @@
+package utils_test
+
+import (
+	"testing"
+
+	"github.com/aasumitro/posbe/pkg/utils"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRandomHex(t *testing.T) {
+	first, err := utils.RandomHex(8)
+	require.NoError(t, err)
+	require.Len(t, first, 16)
+	second, err := utils.RandomHex(8)
+	require.NoError(t, err)
+	require.NotEqual(t, first, second)
+}
@@ -18,18 +18,24 @@ type JWTClaim struct {
 type JSONWebToken struct {
 	Issuer    string
 	SecretKey []byte
-	IssuedAt  time.Time
-	ExpiredAt time.Time
+	// Lifetime of every claimed token, counted from Now
+	Lifetime time.Duration
+	// Now default to time.Now, replaced in test
+	Now func() time.Time
 }
 
 // ClaimJWTToken
-// args app name, expiration time, secret key, payload
+// args app name, lifetime, secret key, payload
 func (j *JSONWebToken) ClaimJWTToken(payload interface{}) (string, error) {
+	now := time.Now()
+	if j.Now != nil {
+		now = j.Now()
+	}
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, JWTClaim{
 		RegisteredClaims: jwt.RegisteredClaims{
 			Issuer:    j.Issuer,
-			IssuedAt:  &jwt.NumericDate{Time: j.IssuedAt},
-			ExpiresAt: &jwt.NumericDate{Time: j.ExpiredAt},
+			IssuedAt:  &jwt.NumericDate{Time: now},
+			ExpiresAt: &jwt.NumericDate{Time: now.Add(j.Lifetime)},
 		},
 		Payload: payload,
 	})